@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
@@ -40,9 +41,13 @@ var (
 	// MethodSetEpoch is the method name for setting epochs.
 	MethodSetEpoch = transaction.NewMethodName(ModuleName, "SetEpoch", EpochTime(0))
 
+	// MethodSetEpochSchedule is the method name for setting the mock epoch transition schedule.
+	MethodSetEpochSchedule = transaction.NewMethodName(ModuleName, "SetEpochSchedule", EpochSchedule{})
+
 	// Methods is a list of all methods supported by the beacon backend.
 	Methods = []transaction.MethodName{
 		MethodSetEpoch,
+		MethodSetEpochSchedule,
 		MethodVRFProve,
 	}
 )
@@ -141,9 +146,16 @@ type ConsensusParameters struct {
 
 	// VRFParameters are the beacon parameters for the VRF backend.
 	VRFParameters *VRFParameters `json:"vrf_parameters,omitempty"`
+
+	// IntervalChanges are the epoch interval changes ordered by epoch. The interval configured
+	// in the backend parameters is in effect until the first change.
+	IntervalChanges []EpochIntervalChange `json:"interval_changes,omitempty"`
 }
 
-// Interval returns the epoch interval (in blocks).
+// Interval returns the base epoch interval (in blocks) that is in effect until the first epoch
+// interval change.
+//
+// Use IntervalAt or EpochBlocks for any epoch time math.
 func (cp *ConsensusParameters) Interval() int64 {
 	switch cp.Backend {
 	case BackendInsecure:
@@ -155,12 +167,110 @@ func (cp *ConsensusParameters) Interval() int64 {
 	}
 }
 
+// IntervalAt returns the length (in blocks) of the given epoch.
+func (cp *ConsensusParameters) IntervalAt(epoch EpochTime) int64 {
+	interval := cp.Interval()
+	for _, change := range cp.IntervalChanges {
+		if change.Epoch > epoch {
+			break
+		}
+		interval = change.Interval
+	}
+	return interval
+}
+
+// EpochBlocks returns the number of blocks between the starts of the given epochs, taking any
+// epoch interval changes into account. The result is negative in case to is before from.
+func (cp *ConsensusParameters) EpochBlocks(from, to EpochTime) int64 {
+	if to < from {
+		return -cp.EpochBlocks(to, from)
+	}
+
+	var blocks int64
+	epoch, interval := from, cp.IntervalAt(from)
+	for _, change := range cp.IntervalChanges {
+		if change.Epoch <= epoch {
+			continue
+		}
+		if change.Epoch >= to {
+			break
+		}
+		blocks += int64(change.Epoch-epoch) * interval
+		epoch, interval = change.Epoch, change.Interval
+	}
+	return blocks + int64(to-epoch)*interval
+}
+
+// EpochIntervalChange is a change of the epoch interval.
+type EpochIntervalChange struct {
+	// Epoch is the first epoch with the new interval.
+	Epoch EpochTime `json:"epoch"`
+
+	// Interval is the new epoch interval (in blocks).
+	Interval int64 `json:"interval"`
+}
+
+// ConsensusParameterChanges are allowed beacon consensus parameter changes.
+type ConsensusParameterChanges struct {
+	// IntervalChange is the epoch interval change to schedule.
+	IntervalChange *EpochIntervalChange `json:"interval_change,omitempty"`
+}
+
+// Apply applies changes to the given consensus parameters.
+func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
+	if c.IntervalChange != nil {
+		if n := len(params.IntervalChanges); n > 0 && params.IntervalChanges[n-1].Epoch >= c.IntervalChange.Epoch {
+			return fmt.Errorf("%w: epoch interval change must be after all scheduled changes", ErrInvalidArgument)
+		}
+		params.IntervalChanges = append(params.IntervalChanges, *c.IntervalChange)
+	}
+	return nil
+}
+
 // InsecureParameters are the beacon parameters for the insecure backend.
 type InsecureParameters struct {
 	// Interval is the epoch interval (in blocks).
 	Interval int64 `json:"interval,omitempty"`
 }
 
+// EpochSchedule is a schedule of explicit epoch transitions for the mock beacon backend.
+//
+// When installed, an epoch transition is requested at each consensus height that is a multiple of
+// the interval after the start height, so all nodes transition deterministically without anyone
+// having to explicitly set the epoch. A schedule with zero interval clears the schedule.
+type EpochSchedule struct {
+	// Start is the first consensus height at which an epoch transition is requested.
+	Start int64 `json:"start,omitempty"`
+
+	// Interval is the number of consensus heights between requested epoch transitions.
+	Interval int64 `json:"interval,omitempty"`
+}
+
+// IsEnabled returns true iff the schedule requests any epoch transitions.
+func (s *EpochSchedule) IsEnabled() bool {
+	return s.Interval > 0
+}
+
+// IsTransitionHeight returns true iff an epoch transition is requested at the given height.
+func (s *EpochSchedule) IsTransitionHeight(height int64) bool {
+	if !s.IsEnabled() || height < s.Start {
+		return false
+	}
+	return (height-s.Start)%s.Interval == 0
+}
+
+// ValidateBasic performs basic epoch schedule validity checks.
+func (s *EpochSchedule) ValidateBasic() error {
+	switch {
+	case s.Interval < 0:
+		return fmt.Errorf("%w: negative epoch schedule interval", ErrInvalidArgument)
+	case s.IsEnabled() && s.Start < 1:
+		return fmt.Errorf("%w: invalid epoch schedule start height", ErrInvalidArgument)
+	default:
+		return nil
+	}
+}
+
 // EpochEvent is the epoch event.
 type EpochEvent struct {
 	// Epoch is the new epoch.
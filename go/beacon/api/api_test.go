@@ -33,3 +33,98 @@ func TestDiff(t *testing.T) {
 		require.Equal(tc.e1.AbsDiff(tc.e2), tc.diff)
 	}
 }
+
+func TestEpochSchedule(t *testing.T) {
+	require := require.New(t)
+
+	var s EpochSchedule
+	require.False(s.IsEnabled())
+	require.False(s.IsTransitionHeight(10))
+	require.NoError(s.ValidateBasic())
+
+	s = EpochSchedule{Start: 10, Interval: 5}
+	require.True(s.IsEnabled())
+	require.NoError(s.ValidateBasic())
+	for height, expected := range map[int64]bool{
+		1:  false,
+		5:  false,
+		10: true,
+		11: false,
+		14: false,
+		15: true,
+		20: true,
+	} {
+		require.Equal(expected, s.IsTransitionHeight(height), "IsTransitionHeight(%d)", height)
+	}
+
+	require.Error((&EpochSchedule{Start: 10, Interval: -1}).ValidateBasic())
+	require.Error((&EpochSchedule{Start: 0, Interval: 5}).ValidateBasic())
+}
+
+func TestEpochIntervalChanges(t *testing.T) {
+	require := require.New(t)
+
+	params := ConsensusParameters{
+		Backend: BackendInsecure,
+		InsecureParameters: &InsecureParameters{
+			Interval: 10,
+		},
+	}
+	require.EqualValues(10, params.IntervalAt(5))
+	require.EqualValues(50, params.EpochBlocks(0, 5))
+	require.EqualValues(-50, params.EpochBlocks(5, 0))
+
+	// Schedule interval changes.
+	changes := ConsensusParameterChanges{
+		IntervalChange: &EpochIntervalChange{Epoch: 5, Interval: 20},
+	}
+	require.NoError(changes.SanityCheck(), "SanityCheck")
+	require.NoError(changes.Apply(&params), "Apply")
+	changes.IntervalChange = &EpochIntervalChange{Epoch: 8, Interval: 5}
+	require.NoError(changes.Apply(&params), "Apply")
+	require.NoError(params.SanityCheck(), "SanityCheck")
+
+	// Changes must be scheduled in order.
+	changes.IntervalChange = &EpochIntervalChange{Epoch: 8, Interval: 15}
+	require.ErrorIs(changes.Apply(&params), ErrInvalidArgument, "Apply")
+	require.Len(params.IntervalChanges, 2)
+
+	for _, tc := range []struct {
+		epoch    EpochTime
+		interval int64
+	}{
+		{0, 10},
+		{4, 10},
+		{5, 20},
+		{7, 20},
+		{8, 5},
+		{100, 5},
+	} {
+		require.EqualValues(tc.interval, params.IntervalAt(tc.epoch), "IntervalAt(%d)", tc.epoch)
+	}
+
+	// Epoch boundaries before the first change must remain stable.
+	for epoch := range EpochTime(6) {
+		require.EqualValues(int64(epoch)*10, params.EpochBlocks(0, epoch), "EpochBlocks(0, %d)", epoch)
+	}
+	// Epoch boundaries after a change are computed piecewise.
+	require.EqualValues(50+20, params.EpochBlocks(0, 6))
+	require.EqualValues(50+3*20, params.EpochBlocks(0, 8))
+	require.EqualValues(50+3*20+2*5, params.EpochBlocks(0, 10))
+	require.EqualValues(3*20+2*5, params.EpochBlocks(5, 10))
+	require.EqualValues(2*20+5, params.EpochBlocks(6, 9))
+	require.EqualValues(-(2*20 + 5), params.EpochBlocks(9, 6))
+	require.EqualValues(0, params.EpochBlocks(7, 7))
+	for from := range EpochTime(12) {
+		for to := from; to < 12; to++ {
+			require.Equal(params.EpochBlocks(0, to)-params.EpochBlocks(0, from), params.EpochBlocks(from, to), "EpochBlocks(%d, %d)", from, to)
+		}
+	}
+
+	// Invalid changes should be rejected.
+	require.Error((&ConsensusParameterChanges{}).SanityCheck(), "SanityCheck")
+	params.IntervalChanges = append(params.IntervalChanges, EpochIntervalChange{Epoch: 10, Interval: 0})
+	require.Error(params.SanityCheck(), "SanityCheck")
+	params.IntervalChanges[2] = EpochIntervalChange{Epoch: 7, Interval: 10}
+	require.Error(params.SanityCheck(), "SanityCheck")
+}
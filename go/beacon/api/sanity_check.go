@@ -53,6 +53,18 @@ func (p *ConsensusParameters) SanityCheck() error {
 		return fmt.Errorf("unknown backend: '%s'", p.Backend)
 	}
 
+	for i, change := range p.IntervalChanges {
+		if i > 0 && change.Epoch <= p.IntervalChanges[i-1].Epoch {
+			return fmt.Errorf("epoch interval changes must be ordered by epoch")
+		}
+		if change.Interval <= 0 {
+			return fmt.Errorf("epoch interval must be > 0")
+		}
+		if p.Backend == BackendVRF && p.VRFParameters.ProofSubmissionDelay >= change.Interval {
+			return fmt.Errorf("submission delay must be < epoch interval")
+		}
+	}
+
 	unsafeFlags := p.DebugMockBackend
 	if unsafeFlags && !flags.DebugDontBlameOasis() {
 		return fmt.Errorf("one or more unsafe debug flags set")
@@ -60,3 +72,11 @@ func (p *ConsensusParameters) SanityCheck() error {
 
 	return nil
 }
+
+// SanityCheck performs a sanity check on the consensus parameter changes.
+func (c *ConsensusParameterChanges) SanityCheck() error {
+	if c.IntervalChange == nil {
+		return fmt.Errorf("consensus parameter changes should not be empty")
+	}
+	return nil
+}
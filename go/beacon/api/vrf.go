@@ -65,6 +65,12 @@ type VRFState struct {
 	PrevState *PrevVRFState `json:"prev_state,omitempty"`
 }
 
+// HasSubmitted returns true iff the given node has submitted a VRF proof
+// for the current epoch.
+func (s *VRFState) HasSubmitted(nodeID signature.PublicKey) bool {
+	return s.Pi[nodeID] != nil
+}
+
 // PrevVRFState is the previous epoch's VRF state that is to be used for
 // elections.
 type PrevVRFState struct {
@@ -108,6 +114,28 @@ func (ev *VRFEvent) EventKind() string {
 	return "vrf"
 }
 
+// VRFParticipationEvent is a VRF proof participation event for a watched
+// node.
+type VRFParticipationEvent struct {
+	// Epoch is the epoch the VRF proof is for.
+	Epoch EpochTime `json:"epoch"`
+
+	// Height is the block height at which the event was generated.
+	Height int64 `json:"height"`
+
+	// NodeID is the identifier of the watched node.
+	NodeID signature.PublicKey `json:"node_id"`
+
+	// Warning is true iff the node has not submitted its VRF proof
+	// by the configured fraction of the proof submission window.  If
+	// false, a previously raised warning has been cleared, either because
+	// the proof was submitted or because the submission window closed.
+	Warning bool `json:"warning"`
+
+	// Submitted is true iff the node has submitted its VRF proof.
+	Submitted bool `json:"submitted"`
+}
+
 // VRFBackend is a Backend that is backed by VRFs.
 type VRFBackend interface {
 	Backend
@@ -122,4 +150,11 @@ type VRFBackend interface {
 	//
 	// Upon subscription the current epoch event is sent immediately.
 	WatchLatestVRFEvent(ctx context.Context) (<-chan *VRFEvent, *pubsub.Subscription, error)
+
+	// WatchParticipation returns a channel that produces a stream of
+	// VRF proof participation events for the given nodes.  A warning
+	// is raised for any node that has not submitted its proof once the
+	// given fraction of the proof submission window has elapsed, and
+	// cleared once the proof is submitted or the window closes.
+	WatchParticipation(ctx context.Context, nodes []signature.PublicKey, warnFraction float64) (<-chan *VRFParticipationEvent, pubsub.ClosableSubscription, error)
 }
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// VRFParticipationTracker tracks the VRF proof submissions of a set of
+// watched nodes during the proof submission window of each epoch.
+type VRFParticipationTracker struct {
+	nodes        []signature.PublicKey
+	warnFraction float64
+
+	epoch  EpochTime
+	warned map[signature.PublicKey]bool
+}
+
+// NewVRFParticipationTracker creates a new VRF participation tracker that
+// warns about watched nodes that have not submitted their proofs once the
+// given fraction of the proof submission window has elapsed.
+func NewVRFParticipationTracker(nodes []signature.PublicKey, warnFraction float64) (*VRFParticipationTracker, error) {
+	if warnFraction <= 0 || warnFraction > 1 {
+		return nil, fmt.Errorf("beacon: invalid VRF participation warning fraction: %v", warnFraction)
+	}
+
+	return &VRFParticipationTracker{
+		nodes:        append([]signature.PublicKey{}, nodes...),
+		warnFraction: warnFraction,
+		epoch:        EpochInvalid,
+		warned:       make(map[signature.PublicKey]bool),
+	}, nil
+}
+
+// Update updates the tracker with the VRF state at the given height, where
+// windowEnd is the height of the next epoch transition that closes the
+// proof submission window.  If the next epoch transition is not known, the
+// windowEnd should be zero and no new warnings will be raised.
+//
+// It returns the events for watched nodes whose warning status changed.
+func (t *VRFParticipationTracker) Update(state *VRFState, height, windowEnd int64) []*VRFParticipationEvent {
+	if state == nil {
+		return nil
+	}
+
+	var evs []*VRFParticipationEvent
+	if state.Epoch != t.epoch {
+		// The submission window of the previous epoch has closed.
+		for _, id := range t.nodes {
+			if !t.warned[id] {
+				continue
+			}
+			evs = append(evs, &VRFParticipationEvent{
+				Epoch:  t.epoch,
+				Height: height,
+				NodeID: id,
+			})
+		}
+		t.epoch = state.Epoch
+		clear(t.warned)
+	}
+
+	canWarn := windowEnd > state.SubmitAfter && height >= t.warnHeight(state.SubmitAfter, windowEnd)
+	for _, id := range t.nodes {
+		submitted := state.HasSubmitted(id)
+		switch {
+		case t.warned[id] && submitted:
+			delete(t.warned, id)
+		case !t.warned[id] && !submitted && canWarn:
+			t.warned[id] = true
+		default:
+			continue
+		}
+		evs = append(evs, &VRFParticipationEvent{
+			Epoch:     state.Epoch,
+			Height:    height,
+			NodeID:    id,
+			Warning:   t.warned[id],
+			Submitted: submitted,
+		})
+	}
+	return evs
+}
+
+func (t *VRFParticipationTracker) warnHeight(submitAfter, windowEnd int64) int64 {
+	window := float64(windowEnd - submitAfter)
+	return submitAfter + int64(math.Ceil(t.warnFraction*window))
+}
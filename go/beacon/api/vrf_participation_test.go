@@ -0,0 +1,85 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+func TestVRFParticipationTracker(t *testing.T) {
+	require := require.New(t)
+
+	var late, punctual signature.PublicKey
+	late[0] = 0x01
+	punctual[0] = 0x02
+
+	_, err := NewVRFParticipationTracker([]signature.PublicKey{late}, 0)
+	require.Error(err, "zero warning fraction should be rejected")
+	_, err = NewVRFParticipationTracker([]signature.PublicKey{late}, 1.5)
+	require.Error(err, "warning fraction above one should be rejected")
+
+	tracker, err := NewVRFParticipationTracker([]signature.PublicKey{late, punctual}, 0.5)
+	require.NoError(err, "NewVRFParticipationTracker")
+
+	// The submission window of epoch 1 spans heights 10 to 30, so warnings are raised at 20.
+	state := &VRFState{
+		Epoch:       1,
+		SubmitAfter: 10,
+		Pi:          make(map[signature.PublicKey]*signature.Proof),
+	}
+	const windowEnd = 30
+
+	evs := tracker.Update(state, 11, windowEnd)
+	require.Empty(evs, "no warnings before the warning height")
+
+	state.Pi[punctual] = &signature.Proof{}
+	evs = tracker.Update(state, 19, windowEnd)
+	require.Empty(evs, "no warnings before the warning height")
+
+	evs = tracker.Update(state, 20, windowEnd)
+	require.Len(evs, 1, "warning should fire for the late node")
+	require.Equal(&VRFParticipationEvent{Epoch: 1, Height: 20, NodeID: late, Warning: true}, evs[0])
+
+	evs = tracker.Update(state, 21, windowEnd)
+	require.Empty(evs, "warning should only fire once")
+
+	// Late submission clears the warning.
+	state.Pi[late] = &signature.Proof{}
+	evs = tracker.Update(state, 22, windowEnd)
+	require.Len(evs, 1, "warning should clear after late submission")
+	require.Equal(&VRFParticipationEvent{Epoch: 1, Height: 22, NodeID: late, Submitted: true}, evs[0])
+
+	evs = tracker.Update(state, 23, windowEnd)
+	require.Empty(evs, "no further events after submission")
+
+	// Missing the submission entirely clears the warning on epoch transition.
+	state = &VRFState{
+		Epoch:       2,
+		SubmitAfter: 40,
+		Pi:          make(map[signature.PublicKey]*signature.Proof),
+	}
+	evs = tracker.Update(state, 55, 60)
+	require.Len(evs, 2, "warnings should fire for both nodes")
+	for _, ev := range evs {
+		require.True(ev.Warning, "event should be a warning")
+		require.EqualValues(2, ev.Epoch, "event epoch")
+	}
+
+	state = &VRFState{
+		Epoch:       3,
+		SubmitAfter: 70,
+	}
+	evs = tracker.Update(state, 61, 90)
+	require.Len(evs, 2, "warnings should clear on epoch transition")
+	for _, ev := range evs {
+		require.False(ev.Warning, "event should clear the warning")
+		require.False(ev.Submitted, "proof should not be submitted")
+		require.EqualValues(2, ev.Epoch, "event epoch")
+	}
+
+	// Unknown submission window end never raises warnings.
+	evs = tracker.Update(state, 89, 0)
+	require.Empty(evs, "no warnings without a known submission window end")
+}
@@ -220,3 +220,13 @@ func SetEpoch(ctx context.Context, epoch api.EpochTime, consensus consensusAPI.S
 		}
 	}
 }
+
+// SetEpochSchedule installs the given mock epoch schedule. A disabled schedule clears any
+// installed schedule.
+func SetEpochSchedule(ctx context.Context, schedule *api.EpochSchedule, consensus consensusAPI.Service) error {
+	tx := transaction.NewTransaction(0, nil, api.MethodSetEpochSchedule, schedule)
+	if err := consensusAPI.SignAndSubmitTx(ctx, consensus, TestSigner, tx); err != nil {
+		return fmt.Errorf("set epoch schedule failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+// Package crashdump implements structured crash dumps that are written to the
+// node's data directory when a panic occurs in a critical code path.
+//
+// Crash dumps are opt-in. A dump only ever contains data that is explicitly
+// passed to the writer (the panic value, state fingerprints and the in-flight
+// request) together with goroutine stack traces and recent log output. The
+// writer never has access to any signers or other key material.
+package crashdump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+const (
+	// ModuleName is the module name used for crash dump errors.
+	ModuleName = "common/crashdump"
+
+	// DirName is the name of the crash dump directory within the node's data directory.
+	DirName = "crash-dumps"
+
+	filePrefix = "crash-"
+	fileSuffix = ".json"
+	timeFormat = "20060102T150405.000000000Z"
+
+	// maxStackSize is the maximum size of the goroutine stack traces included in a dump.
+	maxStackSize = 64 * 1024 * 1024
+)
+
+var (
+	// ErrDisabled is the error returned when crash dumps are not enabled.
+	ErrDisabled = errors.New(ModuleName, 1, "crashdump: crash dumps are disabled")
+	// ErrNotFound is the error returned when the requested crash dump does not exist.
+	ErrNotFound = errors.New(ModuleName, 2, "crashdump: crash dump not found")
+
+	logger = logging.GetLogger("common/crashdump")
+
+	global struct {
+		sync.Mutex
+
+		w *writer
+	}
+)
+
+// Config is the crash dump writer configuration.
+type Config struct {
+	// MaxRequestSize is the maximum size of the in-flight request body included in a dump.
+	MaxRequestSize uint64
+	// LogLines is the number of recent log lines included in a dump.
+	LogLines uint64
+}
+
+// Request is the in-flight request that was being processed when the panic occurred.
+type Request struct {
+	// Method is the name of the method being processed.
+	Method string `json:"method"`
+	// Body is the CBOR-encoded request body, truncated to the configured maximum size.
+	Body []byte `json:"body,omitempty"`
+	// Size is the size of the full request body.
+	Size int `json:"size"`
+	// Truncated is true iff the request body has been truncated.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// State is the state captured at the time of the panic.
+type State struct {
+	// Height is the current consensus height.
+	Height int64
+	// Round is the current runtime round, if any.
+	Round uint64
+	// StateRoots are the current state root hashes keyed by name.
+	StateRoots map[string]hash.Hash
+	// Request is the in-flight request, if any.
+	Request *Request
+}
+
+// Dump is a structured crash dump.
+type Dump struct {
+	// Time is the time when the dump was written.
+	Time time.Time `json:"time"`
+	// Source is the code path that panicked.
+	Source string `json:"source"`
+	// Panic is the panic value.
+	Panic string `json:"panic"`
+	// Height is the consensus height at the time of the panic.
+	Height int64 `json:"height,omitempty"`
+	// Round is the runtime round at the time of the panic.
+	Round uint64 `json:"round,omitempty"`
+	// StateRoots are the state root hashes at the time of the panic keyed by name.
+	StateRoots map[string]hash.Hash `json:"state_roots,omitempty"`
+	// Request is the in-flight request at the time of the panic.
+	Request *Request `json:"request,omitempty"`
+	// Goroutines are the stack traces of all goroutines.
+	Goroutines string `json:"goroutines"`
+	// Logs are the most recent log lines.
+	Logs []string `json:"logs,omitempty"`
+}
+
+// Info is the crash dump metadata.
+type Info struct {
+	// Name is the name of the crash dump.
+	Name string `json:"name"`
+	// Size is the size of the crash dump in bytes.
+	Size int64 `json:"size"`
+}
+
+type writer struct {
+	sync.Mutex
+
+	dir            string
+	maxRequestSize uint64
+	logs           *logBuffer
+}
+
+// Init enables crash dumps which are written to the given directory.
+func Init(dir string, cfg *Config) error {
+	if err := common.Mkdir(dir); err != nil {
+		return fmt.Errorf("crashdump: failed to create directory: %w", err)
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	global.w = &writer{
+		dir:            dir,
+		maxRequestSize: cfg.MaxRequestSize,
+		logs:           newLogBuffer(int(cfg.LogLines)),
+	}
+	return nil
+}
+
+// Enabled returns true iff crash dumps are enabled.
+func Enabled() bool {
+	return getWriter() != nil
+}
+
+// WrapLogWriter returns a writer that additionally records recent log output for inclusion in
+// crash dumps. In case crash dumps are disabled, the passed writer is returned unchanged.
+func WrapLogWriter(w io.Writer) io.Writer {
+	cw := getWriter()
+	if cw == nil || cw.logs == nil {
+		return w
+	}
+	return io.MultiWriter(cw.logs, w)
+}
+
+// RecentLogs returns the most recent log lines, oldest first. In case crash dumps are disabled,
+// no log lines are recorded and nil is returned.
+func RecentLogs() []string {
+	w := getWriter()
+	if w == nil {
+		return nil
+	}
+	return w.logs.lines()
+}
+
+// Recover writes a crash dump for the given recovered panic value and then re-panics with it.
+// It does nothing in case the panic value is nil. The state function is only invoked when a dump
+// is actually written and may be nil.
+//
+// It must be called from a deferred function with the result of recover:
+//
+//	defer func() { crashdump.Recover(recover(), "source", stateFn) }()
+func Recover(p any, source string, stateFn func() *State) {
+	if p == nil {
+		return
+	}
+
+	if Enabled() {
+		path, err := Write(p, source, captureState(stateFn))
+		switch err {
+		case nil:
+			logger.Error("crash dump written",
+				"source", source,
+				"path", path,
+			)
+		default:
+			logger.Error("failed to write crash dump",
+				"source", source,
+				"err", err,
+			)
+		}
+	}
+
+	panic(p)
+}
+
+// Write writes a crash dump for the given panic value and returns the path of the dump.
+func Write(p any, source string, state *State) (string, error) {
+	w := getWriter()
+	if w == nil {
+		return "", ErrDisabled
+	}
+
+	// Capture stack traces before doing anything else.
+	stack := goroutineStacks()
+
+	w.Lock()
+	defer w.Unlock()
+
+	dump := Dump{
+		Time:       time.Now().UTC(),
+		Source:     source,
+		Panic:      fmt.Sprintf("%v", p),
+		Goroutines: string(stack),
+		Logs:       w.logs.lines(),
+	}
+	if state != nil {
+		dump.Height = state.Height
+		dump.Round = state.Round
+		dump.StateRoots = state.StateRoots
+		dump.Request = w.capRequest(state.Request)
+	}
+
+	data, err := json.MarshalIndent(&dump, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("crashdump: failed to marshal dump: %w", err)
+	}
+
+	path := filepath.Join(w.dir, filePrefix+dump.Time.Format(timeFormat)+fileSuffix)
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("crashdump: failed to write dump: %w", err)
+	}
+	return path, nil
+}
+
+// List returns the metadata of all available crash dumps, oldest first.
+func List() ([]*Info, error) {
+	w := getWriter()
+	if w == nil {
+		return nil, ErrDisabled
+	}
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("crashdump: failed to read directory: %w", err)
+	}
+
+	dumps := []*Info{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isValidName(entry.Name()) {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		dumps = append(dumps, &Info{
+			Name: entry.Name(),
+			Size: fi.Size(),
+		})
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].Name < dumps[j].Name
+	})
+	return dumps, nil
+}
+
+// Get returns the crash dump with the given name.
+func Get(name string) (*Dump, error) {
+	w := getWriter()
+	if w == nil {
+		return nil, ErrDisabled
+	}
+	if !isValidName(name) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(w.dir, name))
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("crashdump: failed to read dump: %w", err)
+	}
+
+	var dump Dump
+	if err = json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("crashdump: malformed dump: %w", err)
+	}
+	return &dump, nil
+}
+
+func (w *writer) capRequest(req *Request) *Request {
+	if req == nil {
+		return nil
+	}
+
+	capped := Request{
+		Method: req.Method,
+		Body:   req.Body,
+		Size:   len(req.Body),
+	}
+	if uint64(len(capped.Body)) > w.maxRequestSize {
+		capped.Body = capped.Body[:w.maxRequestSize]
+		capped.Truncated = true
+	}
+	return &capped
+}
+
+func getWriter() *writer {
+	global.Lock()
+	defer global.Unlock()
+
+	return global.w
+}
+
+func captureState(stateFn func() *State) (state *State) {
+	if stateFn == nil {
+		return nil
+	}
+
+	// Make sure that a failure to capture the state does not prevent the dump from being
+	// written or mask the original panic.
+	defer func() {
+		if p := recover(); p != nil {
+			logger.Error("failed to capture crash dump state",
+				"err", p,
+			)
+			state = nil
+		}
+	}()
+	return stateFn()
+}
+
+func goroutineStacks() []byte {
+	buf := make([]byte, 1024*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackSize {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+func isValidName(name string) bool {
+	return filepath.Base(name) == name &&
+		strings.HasPrefix(name, filePrefix) &&
+		strings.HasSuffix(name, fileSuffix)
+}
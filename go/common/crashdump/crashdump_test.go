@@ -0,0 +1,207 @@
+package crashdump
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+)
+
+func initTestWriter(t *testing.T, cfg *Config) string {
+	dir := filepath.Join(t.TempDir(), DirName)
+	require.NoError(t, Init(dir, cfg), "Init")
+	t.Cleanup(func() {
+		global.Lock()
+		defer global.Unlock()
+		global.w = nil
+	})
+	return dir
+}
+
+func TestLogBuffer(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(newLogBuffer(0), "zero sized buffer should be disabled")
+
+	b := newLogBuffer(3)
+	require.Empty(b.lines())
+
+	_, _ = b.Write([]byte("a\n"))
+	_, _ = b.Write([]byte("b\n"))
+	require.Equal([]string{"a", "b"}, b.lines())
+
+	_, _ = b.Write([]byte("c\nd\n"))
+	require.Equal([]string{"b", "c", "d"}, b.lines())
+
+	_, _ = b.Write([]byte("e\n"))
+	require.Equal([]string{"c", "d", "e"}, b.lines())
+}
+
+func TestCrashDump(t *testing.T) {
+	require := require.New(t)
+
+	_, err := List()
+	require.ErrorIs(err, ErrDisabled, "List should fail when disabled")
+	require.Nil(RecentLogs(), "RecentLogs should return nothing when disabled")
+	require.NotPanics(func() {
+		defer func() { Recover(recover(), "test", nil) }()
+	}, "Recover should do nothing without a panic")
+
+	initTestWriter(t, &Config{
+		MaxRequestSize: 4,
+		LogLines:       2,
+	})
+	require.True(Enabled())
+
+	var logs bytes.Buffer
+	lw := WrapLogWriter(&logs)
+	_, _ = lw.Write([]byte("level=info msg=first\n"))
+	_, _ = lw.Write([]byte("level=info msg=second\n"))
+	_, _ = lw.Write([]byte("level=info msg=third\n"))
+	require.Equal("level=info msg=first\nlevel=info msg=second\nlevel=info msg=third\n", logs.String())
+
+	root := hash.NewFromBytes([]byte("state root"))
+	require.PanicsWithValue("boom", func() {
+		defer func() {
+			Recover(recover(), "test/source", func() *State {
+				return &State{
+					Height: 42,
+					Round:  7,
+					StateRoots: map[string]hash.Hash{
+						"state": root,
+					},
+					Request: &Request{
+						Method: "test.Method",
+						Body:   []byte("request body"),
+					},
+				}
+			})
+		}()
+		panic("boom")
+	}, "Recover should re-panic with the original value")
+
+	dumps, err := List()
+	require.NoError(err, "List")
+	require.Len(dumps, 1)
+	require.True(strings.HasPrefix(dumps[0].Name, filePrefix))
+	require.NotZero(dumps[0].Size)
+
+	dump, err := Get(dumps[0].Name)
+	require.NoError(err, "Get")
+	require.Equal("test/source", dump.Source)
+	require.Equal("boom", dump.Panic)
+	require.EqualValues(42, dump.Height)
+	require.EqualValues(7, dump.Round)
+	require.Equal(map[string]hash.Hash{"state": root}, dump.StateRoots)
+	require.Equal(&Request{
+		Method:    "test.Method",
+		Body:      []byte("requ"),
+		Size:      12,
+		Truncated: true,
+	}, dump.Request)
+	require.Equal([]string{"level=info msg=second", "level=info msg=third"}, dump.Logs)
+	require.Equal(dump.Logs, RecentLogs(), "recent logs should be available")
+	require.Contains(dump.Goroutines, "TestCrashDump", "dump should contain stack traces")
+
+	// A failure to capture the state should still result in a dump.
+	require.PanicsWithValue("boom again", func() {
+		defer func() {
+			Recover(recover(), "test/source", func() *State {
+				panic("state unavailable")
+			})
+		}()
+		panic("boom again")
+	})
+	dumps, err = List()
+	require.NoError(err, "List")
+	require.Len(dumps, 2)
+
+	for _, name := range []string{"", "nonexistent", "../" + dumps[0].Name, "crash-missing.json"} {
+		_, err = Get(name)
+		require.ErrorIs(err, ErrNotFound, "Get(%s)", name)
+	}
+}
+
+func TestCrashDumpNoKeyMaterial(t *testing.T) {
+	require := require.New(t)
+
+	dir := initTestWriter(t, &Config{
+		MaxRequestSize: 1024,
+		LogLines:       10,
+	})
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	privateKey := append([]byte{}, signer.(*memorySigner.Signer).UnsafeBytes()...)
+
+	// Make sure a signer is live while the dump is being written and that the in-flight request
+	// has been produced by it.
+	sig, err := signature.Sign(signer, signature.NewContext("crashdump test"), []byte("message"))
+	require.NoError(err, "Sign")
+	body := cbor.Marshal(sig)
+
+	path, err := Write("boom", "test/signer", &State{
+		Height:  1,
+		Request: &Request{Method: "test.Signed", Body: body},
+	})
+	require.NoError(err, "Write")
+	require.True(strings.HasPrefix(path, dir))
+
+	data, err := os.ReadFile(path)
+	require.NoError(err, "ReadFile")
+	for _, encoded := range [][]byte{
+		privateKey,
+		privateKey[:32],
+		[]byte(hex.EncodeToString(privateKey[:32])),
+		[]byte(base64.StdEncoding.EncodeToString(privateKey[:32])),
+	} {
+		require.False(bytes.Contains(data, encoded), "dump must not contain private key material")
+	}
+	signer.Reset()
+
+	// The writer must not be able to reference any signers.
+	signerType := reflect.TypeOf((*signature.Signer)(nil)).Elem()
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(writer{}),
+		reflect.TypeOf(State{}),
+		reflect.TypeOf(Dump{}),
+	} {
+		requireNoSignerReference(t, typ, signerType, map[reflect.Type]bool{})
+	}
+}
+
+func requireNoSignerReference(t *testing.T, typ, signerType reflect.Type, seen map[reflect.Type]bool) {
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	require.False(t, typ.Implements(signerType), "type %s must not be a signer", typ)
+	require.False(t, reflect.PointerTo(typ).Implements(signerType), "type %s must not be a signer", typ)
+	require.NotEqual(t, reflect.Interface, typ.Kind(), "type %s must not be an interface", typ)
+	require.NotEqual(t, reflect.Func, typ.Kind(), "type %s must not be a function", typ)
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			requireNoSignerReference(t, typ.Field(i).Type, signerType, seen)
+		}
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		requireNoSignerReference(t, typ.Elem(), signerType, seen)
+	case reflect.Map:
+		requireNoSignerReference(t, typ.Key(), signerType, seen)
+		requireNoSignerReference(t, typ.Elem(), signerType, seen)
+	}
+}
@@ -0,0 +1,54 @@
+package crashdump
+
+import (
+	"strings"
+	"sync"
+)
+
+// logBuffer is a ring buffer holding the most recent log lines.
+type logBuffer struct {
+	sync.Mutex
+
+	buf  []string
+	next int
+	full bool
+}
+
+func newLogBuffer(size int) *logBuffer {
+	if size <= 0 {
+		return nil
+	}
+	return &logBuffer{
+		buf: make([]string, size),
+	}
+}
+
+// Write implements io.Writer.
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.buf[b.next] = line
+		b.next = (b.next + 1) % len(b.buf)
+		if b.next == 0 {
+			b.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// lines returns the buffered log lines, oldest first.
+func (b *logBuffer) lines() []string {
+	if b == nil {
+		return nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.full {
+		return append([]string{}, b.buf[:b.next]...)
+	}
+	return append(append([]string{}, b.buf[b.next:]...), b.buf[:b.next]...)
+}
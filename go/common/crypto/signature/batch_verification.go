@@ -1,9 +1,78 @@
 package signature
 
 import (
+	"fmt"
+
 	"github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
 )
 
+// BatchEntry is a single (public key, context, message, signature) quad to
+// be verified with BatchVerify.
+type BatchEntry struct {
+	// PublicKey is the public key of the signer.
+	PublicKey PublicKey
+	// Context is the signature domain separation context.
+	Context Context
+	// Message is the signed message.
+	Message []byte
+	// Signature is the raw signature.
+	Signature []byte
+}
+
+// BatchVerifyError is the error returned by BatchVerify in case one or more
+// of the signatures in the batch are invalid.
+type BatchVerifyError struct {
+	// Errors contains the verification error for each batch entry, or nil
+	// in case the signature of the corresponding entry is valid.
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *BatchVerifyError) Error() string {
+	return fmt.Sprintf("signature: batch verification failed for entries %v", e.Invalid())
+}
+
+// Invalid returns the indices of the batch entries with invalid signatures.
+func (e *BatchVerifyError) Invalid() []int {
+	var invalid []int
+	for i, err := range e.Errors {
+		if err != nil {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid
+}
+
+// BatchVerify verifies all of the given entries at once, returning nil iff
+// every signature is valid.
+//
+// If any of the signatures is invalid, the batch falls back to individual
+// verification and a *BatchVerifyError identifying the invalid entries is
+// returned.
+func BatchVerify(entries []BatchEntry) error {
+	switch len(entries) {
+	case 0:
+		return nil
+	case 1:
+		// Batch verification of a single signature is slower than verifying
+		// it individually.
+		e := entries[0]
+		if err := verifySingle(e.PublicKey, e.Context, e.Message, e.Signature); err != nil {
+			return &BatchVerifyError{Errors: []error{err}}
+		}
+		return nil
+	}
+
+	verifier := NewBatchVerifierWithCapacity(len(entries))
+	for _, e := range entries {
+		verifier.Add(e.PublicKey, e.Context, e.Message, e.Signature)
+	}
+	if ok, errs := verifier.Verify(); !ok {
+		return &BatchVerifyError{Errors: errs}
+	}
+	return nil
+}
+
 // BatchVerifier accumulates batch entries with Add, before performing
 // batch verification with Verify.
 type BatchVerifier struct {
@@ -147,3 +216,22 @@ func NewBatchVerifierWithCapacity(n int) *BatchVerifier {
 		resultsMap: make(map[int]int),
 	}
 }
+
+// verifySingle verifies a single signature, returning the same errors as the
+// batch verifier would.
+func verifySingle(publicKey PublicKey, context Context, message, sig []byte) error {
+	if len(sig) != SignatureSize {
+		return ErrMalformedSignature
+	}
+	if publicKey.IsBlacklisted() {
+		return ErrForbiddenPublicKey
+	}
+	data, err := PrepareSignerMessage(context, message)
+	if err != nil {
+		return err
+	}
+	if !cachingVerifier.VerifyWithOptions(publicKey[:], data, sig, defaultOptions) {
+		return ErrVerifyFailed
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package signature
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
@@ -75,3 +76,139 @@ func genTestKeypair(t *testing.T) (PublicKey, ed25519.PrivateKey) {
 
 	return pubKey, privKey
 }
+
+func TestBatchVerify(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewContext("batch verify test context")
+	msg := []byte("test message")
+	data, err := PrepareSignerMessage(ctx, msg)
+	require.NoError(err, "PrepareSignerMessage")
+
+	pubKey, privKey := genTestKeypair(t)
+	sig := ed25519.Sign(privKey, data)
+
+	err = BatchVerify(nil)
+	require.NoError(err, "BatchVerify(empty)")
+
+	entries := []BatchEntry{
+		{PublicKey: pubKey, Context: ctx, Message: msg, Signature: sig},
+		{PublicKey: pubKey, Context: ctx, Message: msg, Signature: sig},
+	}
+	err = BatchVerify(entries)
+	require.NoError(err, "BatchVerify(good)")
+
+	entries = append(entries,
+		BatchEntry{PublicKey: pubKey, Context: ctx, Message: []byte("other message"), Signature: sig},
+		BatchEntry{PublicKey: pubKey, Context: ctx, Message: msg, Signature: sig},
+		BatchEntry{PublicKey: pubKey, Context: ctx, Message: msg, Signature: sig[:SignatureSize-1]},
+	)
+	err = BatchVerify(entries)
+	require.Error(err, "BatchVerify(bad)")
+
+	var bvErr *BatchVerifyError
+	require.ErrorAs(err, &bvErr)
+	require.Equal([]int{2, 4}, bvErr.Invalid())
+	require.Equal(ErrVerifyFailed, bvErr.Errors[2])
+	require.Equal(ErrMalformedSignature, bvErr.Errors[4])
+}
+
+func FuzzBatchVerify(f *testing.F) {
+	ctx := NewContext("batch verify fuzz context")
+
+	// Use a fixed set of keys so that the fuzzer can produce valid signatures.
+	const numKeys = 4
+	pubKeys := make([]PublicKey, numKeys)
+	privKeys := make([]ed25519.PrivateKey, numKeys)
+	for i := range privKeys {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i)
+		privKeys[i] = ed25519.NewKeyFromSeed(seed)
+		copy(pubKeys[i][:], privKeys[i].Public().(ed25519.PublicKey))
+	}
+
+	f.Add([]byte("message"), uint8(3), uint8(0))
+	f.Add([]byte("message"), uint8(5), uint8(0xff))
+	f.Add([]byte{}, uint8(1), uint8(0x01))
+
+	f.Fuzz(func(t *testing.T, msg []byte, n uint8, corrupt uint8) {
+		data, err := PrepareSignerMessage(ctx, msg)
+		require.NoError(t, err, "PrepareSignerMessage")
+
+		n %= 16
+		entries := make([]BatchEntry, 0, n)
+		for i := range int(n) {
+			k := i % numKeys
+			sig := ed25519.Sign(privKeys[k], data)
+			if corrupt&(1<<(i%8)) != 0 {
+				sig[i%SignatureSize] ^= 0x01
+			}
+			entries = append(entries, BatchEntry{
+				PublicKey: pubKeys[k],
+				Context:   ctx,
+				Message:   msg,
+				Signature: sig,
+			})
+		}
+
+		// Batch verification must be equivalent to individual verification.
+		var invalid []int
+		for i, e := range entries {
+			if !e.PublicKey.Verify(e.Context, e.Message, e.Signature) {
+				invalid = append(invalid, i)
+			}
+		}
+
+		err = BatchVerify(entries)
+		switch len(invalid) {
+		case 0:
+			require.NoError(t, err, "BatchVerify should succeed")
+		default:
+			var bvErr *BatchVerifyError
+			require.ErrorAs(t, err, &bvErr, "BatchVerify should fail")
+			require.Equal(t, invalid, bvErr.Invalid(), "BatchVerify should identify invalid signatures")
+		}
+	})
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	ctx := NewContext("batch verify benchmark context")
+	msg := []byte("benchmark message")
+	data, err := PrepareSignerMessage(ctx, msg)
+	require.NoError(b, err, "PrepareSignerMessage")
+
+	for _, n := range []int{1, 8, 64, 256} {
+		entries := make([]BatchEntry, 0, n)
+		for range n {
+			rawPubKey, privKey, err := ed25519.GenerateKey(nil)
+			require.NoError(b, err, "GenerateKey")
+
+			var pubKey PublicKey
+			copy(pubKey[:], rawPubKey)
+
+			entries = append(entries, BatchEntry{
+				PublicKey: pubKey,
+				Context:   ctx,
+				Message:   msg,
+				Signature: ed25519.Sign(privKey, data),
+			})
+		}
+
+		b.Run(fmt.Sprintf("Individual/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for _, e := range entries {
+					if !e.PublicKey.Verify(e.Context, e.Message, e.Signature) {
+						b.Fatal("verification failed")
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Batch/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if err := BatchVerify(entries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
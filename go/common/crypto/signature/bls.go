@@ -0,0 +1,191 @@
+package signature
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/sign/bls"
+)
+
+const (
+	// BLSPublicKeySize is the size of a compressed BLS12-381 public key (in G2) in bytes.
+	BLSPublicKeySize = 96
+
+	// BLSSignatureSize is the size of a compressed BLS12-381 signature (in G1) in bytes.
+	BLSSignatureSize = 48
+
+	// BLSPrivateKeySize is the size of a BLS12-381 private key in bytes.
+	BLSPrivateKeySize = 32
+
+	blsSeedSize = 32
+)
+
+var _ SchemeSigner = (*BLSSigner)(nil)
+
+// BLSSigner is a signer producing BLS signatures over the BLS12-381 curve.
+//
+// Signatures are computed over the signer's public key followed by the same prehashed context
+// and message as Ed25519 signatures (message augmentation). This makes it safe to aggregate
+// signatures of different signers over the same message without requiring proofs of possession.
+type BLSSigner struct {
+	privateKey *bls.PrivateKey[bls.KeyG2SigG1]
+	publicKey  []byte
+}
+
+// NewBLSSigner generates a new BLS signer using the given entropy source.
+func NewBLSSigner(rng io.Reader) (*BLSSigner, error) {
+	var seed [blsSeedSize]byte
+	if _, err := io.ReadFull(rng, seed[:]); err != nil {
+		return nil, fmt.Errorf("signature: failed to read BLS key seed: %w", err)
+	}
+	privateKey, err := bls.KeyGen[bls.KeyG2SigG1](seed[:], nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("signature: failed to generate BLS private key: %w", err)
+	}
+	return newBLSSigner(privateKey)
+}
+
+// NewBLSSignerFromBytes creates a new BLS signer from the given private key bytes.
+func NewBLSSignerFromBytes(data []byte) (*BLSSigner, error) {
+	if len(data) != BLSPrivateKeySize {
+		return nil, ErrMalformedPrivateKey
+	}
+
+	var privateKey bls.PrivateKey[bls.KeyG2SigG1]
+	if err := privateKey.UnmarshalBinary(data); err != nil || !privateKey.Validate() {
+		return nil, ErrMalformedPrivateKey
+	}
+	return newBLSSigner(&privateKey)
+}
+
+func newBLSSigner(privateKey *bls.PrivateKey[bls.KeyG2SigG1]) (*BLSSigner, error) {
+	publicKey, err := privateKey.PublicKey().MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("signature: failed to serialize BLS public key: %w", err)
+	}
+	return &BLSSigner{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+// Public returns the scheme-tagged public key corresponding to the signer.
+func (s *BLSSigner) Public() SchemePublicKey {
+	return SchemePublicKey{
+		Scheme: SchemeBLS12381,
+		Key:    append([]byte{}, s.publicKey...),
+	}
+}
+
+// ContextSign generates a signature with the private key over the context and message.
+func (s *BLSSigner) ContextSign(context Context, message []byte) ([]byte, error) {
+	if !IsSchemeAllowed(context, SchemeBLS12381) {
+		return nil, ErrSchemeNotAllowed
+	}
+
+	data, err := prepareBLSMessage(s.publicKey, context, message)
+	if err != nil {
+		return nil, err
+	}
+	return bls.Sign(s.privateKey, data), nil
+}
+
+// String returns a string representation of the signer.
+func (s *BLSSigner) String() string {
+	return "[redacted BLS private key]"
+}
+
+// Reset tears down the signer and obliterates any sensitive state if any.
+func (s *BLSSigner) Reset() {
+	s.privateKey = nil
+	s.publicKey = nil
+}
+
+// UnsafeBytes returns the byte representation of the private key.
+func (s *BLSSigner) UnsafeBytes() []byte {
+	data, _ := s.privateKey.MarshalBinary()
+	return data
+}
+
+// AggregateBLSSignatures aggregates the given BLS signatures into a single signature.
+func AggregateBLSSignatures(sigs [][]byte) ([]byte, error) {
+	for _, sig := range sigs {
+		if len(sig) != BLSSignatureSize {
+			return nil, ErrMalformedSignature
+		}
+	}
+	aggSig, err := bls.Aggregate(bls.KeyG2SigG1{}, sigs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedSignature, err)
+	}
+	return aggSig, nil
+}
+
+// VerifyBLSAggregate returns true iff the aggregate signature is valid for the given distinct
+// BLS public keys over the context and message, and the BLS scheme is allowed for the context.
+func VerifyBLSAggregate(context Context, message []byte, publicKeys []SchemePublicKey, aggSig []byte) bool {
+	if len(publicKeys) == 0 || len(aggSig) != BLSSignatureSize {
+		return false
+	}
+	if !IsSchemeAllowed(context, SchemeBLS12381) {
+		return false
+	}
+
+	seen := make(map[string]bool, len(publicKeys))
+	pks := make([]*bls.PublicKey[bls.KeyG2SigG1], 0, len(publicKeys))
+	msgs := make([][]byte, 0, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		if publicKey.Scheme != SchemeBLS12381 || seen[string(publicKey.Key)] {
+			return false
+		}
+		seen[string(publicKey.Key)] = true
+
+		pk, err := parseBLSPublicKey(publicKey.Key)
+		if err != nil {
+			return false
+		}
+		data, err := prepareBLSMessage(publicKey.Key, context, message)
+		if err != nil {
+			return false
+		}
+		pks = append(pks, pk)
+		msgs = append(msgs, data)
+	}
+
+	return bls.VerifyAggregate(pks, msgs, aggSig)
+}
+
+func verifyBLS(key []byte, context Context, message, sig []byte) bool {
+	if len(sig) != BLSSignatureSize {
+		return false
+	}
+	pk, err := parseBLSPublicKey(key)
+	if err != nil {
+		return false
+	}
+	data, err := prepareBLSMessage(key, context, message)
+	if err != nil {
+		return false
+	}
+	return bls.Verify(pk, data, sig)
+}
+
+func parseBLSPublicKey(key []byte) (*bls.PublicKey[bls.KeyG2SigG1], error) {
+	if len(key) != BLSPublicKeySize {
+		return nil, ErrMalformedPublicKey
+	}
+
+	var pk bls.PublicKey[bls.KeyG2SigG1]
+	if err := pk.UnmarshalBinary(key); err != nil || !pk.Validate() {
+		return nil, ErrMalformedPublicKey
+	}
+	return &pk, nil
+}
+
+func prepareBLSMessage(publicKey []byte, context Context, message []byte) ([]byte, error) {
+	data, err := PrepareSignerMessage(context, message)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, publicKey...), data...), nil
+}
@@ -0,0 +1,300 @@
+package signature
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupportedScheme is the error returned when a signature scheme is not supported.
+	ErrUnsupportedScheme = errors.New("signature: unsupported signature scheme")
+
+	// ErrSchemeNotAllowed is the error returned when a signature scheme is not allowed to be
+	// used with the given context.
+	ErrSchemeNotAllowed = errors.New("signature: signature scheme not allowed for context")
+
+	_ encoding.BinaryMarshaler   = SchemePublicKey{}
+	_ encoding.BinaryUnmarshaler = (*SchemePublicKey)(nil)
+	_ encoding.TextMarshaler     = SchemePublicKey{}
+	_ encoding.TextUnmarshaler   = (*SchemePublicKey)(nil)
+	_ encoding.TextMarshaler     = Scheme(0)
+	_ encoding.TextUnmarshaler   = (*Scheme)(nil)
+)
+
+// Scheme is a signature scheme.
+type Scheme uint8
+
+const (
+	// SchemeEd25519 is the Ed25519 signature scheme used by all consensus-critical roles.
+	SchemeEd25519 Scheme = 0
+	// SchemeSecp256k1 is the ECDSA signature scheme over the secp256k1 curve.
+	SchemeSecp256k1 Scheme = 1
+	// SchemeBLS12381 is the BLS signature scheme over the BLS12-381 curve with public keys in G2
+	// and signatures in G1.
+	SchemeBLS12381 Scheme = 2
+
+	SchemeEd25519Name   = "ed25519"
+	SchemeSecp256k1Name = "secp256k1"
+	SchemeBLS12381Name  = "bls12381"
+)
+
+// String returns a string representation of a signature scheme.
+func (s Scheme) String() string {
+	name, err := s.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("[unknown signature scheme: %d]", s)
+	}
+	return string(name)
+}
+
+// MarshalText encodes a signature scheme into text form.
+func (s Scheme) MarshalText() ([]byte, error) {
+	switch s {
+	case SchemeEd25519:
+		return []byte(SchemeEd25519Name), nil
+	case SchemeSecp256k1:
+		return []byte(SchemeSecp256k1Name), nil
+	case SchemeBLS12381:
+		return []byte(SchemeBLS12381Name), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedScheme, s)
+	}
+}
+
+// UnmarshalText decodes a text slice into a signature scheme.
+func (s *Scheme) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case SchemeEd25519Name:
+		*s = SchemeEd25519
+	case SchemeSecp256k1Name:
+		*s = SchemeSecp256k1
+	case SchemeBLS12381Name:
+		*s = SchemeBLS12381
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedScheme, string(text))
+	}
+	return nil
+}
+
+// publicKeySize returns the size of a public key of the given scheme in bytes.
+func (s Scheme) publicKeySize() (int, error) {
+	switch s {
+	case SchemeEd25519:
+		return PublicKeySize, nil
+	case SchemeSecp256k1:
+		return Secp256k1PublicKeySize, nil
+	case SchemeBLS12381:
+		return BLSPublicKeySize, nil
+	default:
+		return 0, fmt.Errorf("%w: %d", ErrUnsupportedScheme, s)
+	}
+}
+
+// SchemePublicKey is a public key tagged with its signature scheme.
+//
+// Ed25519 public keys use the same binary encoding as PublicKey, so that existing keys decode
+// as Ed25519 keys. Public keys of other schemes are encoded as the scheme identifier followed
+// by the scheme-specific encoding of the key.
+type SchemePublicKey struct {
+	// Scheme is the signature scheme.
+	Scheme Scheme
+	// Key is the scheme-specific encoding of the public key.
+	Key []byte
+}
+
+// NewEd25519SchemePublicKey creates a new scheme-tagged public key from an Ed25519 public key.
+func NewEd25519SchemePublicKey(pk PublicKey) SchemePublicKey {
+	return SchemePublicKey{
+		Scheme: SchemeEd25519,
+		Key:    append([]byte{}, pk[:]...),
+	}
+}
+
+// Ed25519 returns the Ed25519 public key iff the public key uses the Ed25519 scheme.
+func (k SchemePublicKey) Ed25519() (PublicKey, bool) {
+	var pk PublicKey
+	if k.Scheme != SchemeEd25519 || len(k.Key) != PublicKeySize {
+		return pk, false
+	}
+	copy(pk[:], k.Key)
+	return pk, true
+}
+
+// Verify returns true iff the signature is valid for the public key over the context and
+// message, and the public key's signature scheme is allowed for the context.
+func (k SchemePublicKey) Verify(context Context, message, sig []byte) bool {
+	if !IsSchemeAllowed(context, k.Scheme) {
+		return false
+	}
+
+	switch k.Scheme {
+	case SchemeEd25519:
+		pk, ok := k.Ed25519()
+		if !ok {
+			return false
+		}
+		return pk.Verify(context, message, sig)
+	case SchemeSecp256k1:
+		return verifySecp256k1(k.Key, context, message, sig)
+	case SchemeBLS12381:
+		return verifyBLS(k.Key, context, message, sig)
+	default:
+		return false
+	}
+}
+
+// ValidateBasic performs basic public key validity checks.
+func (k SchemePublicKey) ValidateBasic() error {
+	size, err := k.Scheme.publicKeySize()
+	if err != nil {
+		return err
+	}
+	if len(k.Key) != size {
+		return ErrMalformedPublicKey
+	}
+	return nil
+}
+
+// Equal compares vs another public key for equality.
+func (k SchemePublicKey) Equal(cmp SchemePublicKey) bool {
+	return k.Scheme == cmp.Scheme && bytes.Equal(k.Key, cmp.Key)
+}
+
+// String returns a string representation of the public key.
+func (k SchemePublicKey) String() string {
+	return k.Scheme.String() + ":" + base64.StdEncoding.EncodeToString(k.Key)
+}
+
+// MarshalBinary encodes a public key into binary form.
+func (k SchemePublicKey) MarshalBinary() ([]byte, error) {
+	if err := k.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if k.Scheme == SchemeEd25519 {
+		return append([]byte{}, k.Key...), nil
+	}
+	return append([]byte{byte(k.Scheme)}, k.Key...), nil
+}
+
+// UnmarshalBinary decodes a binary marshaled public key.
+func (k *SchemePublicKey) UnmarshalBinary(data []byte) error {
+	var pk SchemePublicKey
+	switch len(data) {
+	case 0:
+		return ErrMalformedPublicKey
+	case PublicKeySize:
+		// Untagged Ed25519 public key.
+		pk.Scheme = SchemeEd25519
+		pk.Key = append([]byte{}, data...)
+	default:
+		pk.Scheme = Scheme(data[0])
+		pk.Key = append([]byte{}, data[1:]...)
+		if pk.Scheme == SchemeEd25519 {
+			// Ed25519 public keys must always use the untagged encoding.
+			return ErrMalformedPublicKey
+		}
+	}
+	if err := pk.ValidateBasic(); err != nil {
+		return err
+	}
+
+	*k = pk
+	return nil
+}
+
+// MarshalText encodes a public key into text form.
+func (k SchemePublicKey) MarshalText() ([]byte, error) {
+	data, err := k.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// UnmarshalText decodes a text marshaled public key.
+func (k *SchemePublicKey) UnmarshalText(text []byte) error {
+	data, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	return k.UnmarshalBinary(data)
+}
+
+// SchemeSigner is a signer that is capable of producing signatures using a given signature
+// scheme.
+type SchemeSigner interface {
+	// Public returns the scheme-tagged public key corresponding to the signer.
+	Public() SchemePublicKey
+
+	// ContextSign generates a signature with the private key over the context and message.
+	//
+	// Signing fails in case the signer's signature scheme is not allowed for the context.
+	ContextSign(context Context, message []byte) ([]byte, error)
+
+	// String returns the string representation of the signer, which MUST not include any
+	// sensitive information.
+	String() string
+
+	// Reset tears down the signer and obliterates any sensitive state if any.
+	Reset()
+}
+
+// SchemeSignature is a signature, bundled with the scheme-tagged signing public key.
+//
+// Its encoding is compatible with Signature, so that existing signatures decode as Ed25519
+// signatures.
+type SchemeSignature struct {
+	// PublicKey is the public key that produced the signature.
+	PublicKey SchemePublicKey `json:"public_key"`
+
+	// Signature is the actual raw signature.
+	Signature []byte `json:"signature"`
+}
+
+// SignScheme generates a signature with the scheme signer over the context and message.
+func SignScheme(signer SchemeSigner, context Context, message []byte) (*SchemeSignature, error) {
+	sig, err := signer.ContextSign(context, message)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemeSignature{
+		PublicKey: signer.Public(),
+		Signature: sig,
+	}, nil
+}
+
+// Verify returns true iff the signature is valid over the given context and message.
+func (s *SchemeSignature) Verify(context Context, message []byte) bool {
+	return s.PublicKey.Verify(context, message, s.Signature)
+}
+
+// Equal compares vs another signature for equality.
+func (s *SchemeSignature) Equal(cmp *SchemeSignature) bool {
+	return s.PublicKey.Equal(cmp.PublicKey) && bytes.Equal(s.Signature, cmp.Signature)
+}
+
+// IsSchemeAllowed returns true iff the given signature scheme is allowed to be used with the
+// given context.
+//
+// Ed25519 is allowed for all contexts, while other schemes are only allowed for contexts that
+// explicitly opted in via the WithSchemes option.
+func IsSchemeAllowed(context Context, scheme Scheme) bool {
+	if scheme == SchemeEd25519 {
+		return true
+	}
+
+	rawOpts, isRegistered := registeredContexts.Load(context)
+	if !isRegistered {
+		return false
+	}
+	opts := rawOpts.(*contextOptions)
+	for _, s := range opts.schemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package signature
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/oasisprotocol/curve25519-voi/primitives/ed25519"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+func TestSchemeSignatures(t *testing.T) {
+	require := require.New(t)
+
+	consensusCtx := NewContext("test: scheme consensus context")
+	secp256k1Ctx := NewContext("test: scheme secp256k1 context", WithSchemes(SchemeSecp256k1))
+	externalCtx := NewContext("test: scheme external context", WithSchemes(SchemeSecp256k1, SchemeBLS12381))
+	suffixCtx := NewContext("test: scheme suffix context", WithSchemes(SchemeBLS12381), WithDynamicSuffix(" for ", 8))
+	suffixedCtx, err := suffixCtx.WithSuffix("test")
+	require.NoError(err, "WithSuffix")
+
+	require.Panics(func() { NewContext("test: scheme invalid context", WithSchemes(Scheme(42))) }, "unsupported schemes should be rejected")
+
+	msg := []byte("scheme test message")
+
+	pk, privKey := genTestKeypair(t)
+	data, err := PrepareSignerMessage(externalCtx, msg)
+	require.NoError(err, "PrepareSignerMessage")
+	ed25519Sig := &SchemeSignature{
+		PublicKey: NewEd25519SchemePublicKey(pk),
+		Signature: ed25519.Sign(privKey, data),
+	}
+
+	secp256k1Signer, err := NewSecp256k1Signer(rand.Reader)
+	require.NoError(err, "NewSecp256k1Signer")
+	blsSigner, err := NewBLSSigner(rand.Reader)
+	require.NoError(err, "NewBLSSigner")
+
+	// Ed25519 is allowed for all contexts, other schemes only when enabled.
+	for _, tc := range []struct {
+		ctx     Context
+		scheme  Scheme
+		allowed bool
+	}{
+		{consensusCtx, SchemeEd25519, true},
+		{consensusCtx, SchemeSecp256k1, false},
+		{consensusCtx, SchemeBLS12381, false},
+		{secp256k1Ctx, SchemeSecp256k1, true},
+		{secp256k1Ctx, SchemeBLS12381, false},
+		{externalCtx, SchemeSecp256k1, true},
+		{externalCtx, SchemeBLS12381, true},
+		{suffixedCtx, SchemeBLS12381, true},
+		{suffixedCtx, SchemeSecp256k1, false},
+		{Context("test: unregistered scheme context"), SchemeSecp256k1, false},
+	} {
+		require.Equal(tc.allowed, IsSchemeAllowed(tc.ctx, tc.scheme), "IsSchemeAllowed(%s, %s)", tc.ctx, tc.scheme)
+	}
+
+	// Signing with schemes that are not allowed for the context should fail.
+	_, err = SignScheme(secp256k1Signer, consensusCtx, msg)
+	require.ErrorIs(err, ErrSchemeNotAllowed, "secp256k1 signing should fail for consensus context")
+	_, err = SignScheme(blsSigner, consensusCtx, msg)
+	require.ErrorIs(err, ErrSchemeNotAllowed, "BLS signing should fail for consensus context")
+	_, err = SignScheme(blsSigner, secp256k1Ctx, msg)
+	require.ErrorIs(err, ErrSchemeNotAllowed, "BLS signing should fail for secp256k1 context")
+
+	secp256k1Sig, err := SignScheme(secp256k1Signer, externalCtx, msg)
+	require.NoError(err, "secp256k1 SignScheme")
+	require.Len(secp256k1Sig.Signature, Secp256k1SignatureSize)
+	blsSig, err := SignScheme(blsSigner, externalCtx, msg)
+	require.NoError(err, "BLS SignScheme")
+	require.Len(blsSig.Signature, BLSSignatureSize)
+
+	sigs := []*SchemeSignature{ed25519Sig, secp256k1Sig, blsSig}
+	for _, sig := range sigs {
+		scheme := sig.PublicKey.Scheme
+		require.True(sig.Verify(externalCtx, msg), "%s signature should verify", scheme)
+		require.False(sig.Verify(externalCtx, []byte("other message")), "%s signature should not verify for other messages", scheme)
+		require.False(sig.Verify(secp256k1Ctx, msg), "%s signature should not verify for other contexts", scheme)
+		require.False(sig.Verify(consensusCtx, msg), "%s signature should not verify for consensus context", scheme)
+
+		tampered := *sig
+		tampered.Signature = append([]byte{}, sig.Signature...)
+		tampered.Signature[len(tampered.Signature)/2] ^= 0x01
+		require.False(tampered.Verify(externalCtx, msg), "tampered %s signature should not verify", scheme)
+
+		tampered.Signature = sig.Signature[:len(sig.Signature)-1]
+		require.False(tampered.Verify(externalCtx, msg), "truncated %s signature should not verify", scheme)
+	}
+
+	// Signatures must not verify under a public key of another scheme, nor when relabeled.
+	for _, sig := range sigs {
+		for _, other := range sigs {
+			if sig == other {
+				continue
+			}
+			require.False(other.PublicKey.Verify(externalCtx, msg, sig.Signature),
+				"%s signature should not verify with %s public key", sig.PublicKey.Scheme, other.PublicKey.Scheme)
+
+			relabeled := SchemePublicKey{Scheme: other.PublicKey.Scheme, Key: sig.PublicKey.Key}
+			require.False(relabeled.Verify(externalCtx, msg, sig.Signature),
+				"%s signature should not verify with public key relabeled as %s", sig.PublicKey.Scheme, other.PublicKey.Scheme)
+		}
+	}
+
+	// Malleated (high-S) secp256k1 signatures should be rejected.
+	var s secp256k1.ModNScalar
+	s.SetByteSlice(secp256k1Sig.Signature[32:])
+	highS := s.Negate().Bytes()
+	malleated := append([]byte{}, secp256k1Sig.Signature[:32]...)
+	malleated = append(malleated, highS[:]...)
+	require.False(secp256k1Sig.PublicKey.Verify(externalCtx, msg, malleated), "high-S secp256k1 signature should not verify")
+
+	// Signers restored from their private keys should produce the same public keys.
+	restoredSecp256k1, err := NewSecp256k1SignerFromBytes(secp256k1Signer.UnsafeBytes())
+	require.NoError(err, "NewSecp256k1SignerFromBytes")
+	require.True(restoredSecp256k1.Public().Equal(secp256k1Signer.Public()))
+	restoredBLS, err := NewBLSSignerFromBytes(blsSigner.UnsafeBytes())
+	require.NoError(err, "NewBLSSignerFromBytes")
+	require.True(restoredBLS.Public().Equal(blsSigner.Public()))
+	_, err = NewSecp256k1SignerFromBytes(make([]byte, Secp256k1PrivateKeySize))
+	require.ErrorIs(err, ErrMalformedPrivateKey, "zero secp256k1 private key should be rejected")
+	_, err = NewBLSSignerFromBytes([]byte("too short"))
+	require.ErrorIs(err, ErrMalformedPrivateKey, "short BLS private key should be rejected")
+}
+
+func TestSchemeSerialization(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewContext("test: scheme serialization context", WithSchemes(SchemeSecp256k1, SchemeBLS12381))
+	msg := []byte("scheme serialization test message")
+
+	// Existing Ed25519 public keys and signatures should decode as Ed25519.
+	pk, privKey := genTestKeypair(t)
+	data, err := PrepareSignerMessage(ctx, msg)
+	require.NoError(err, "PrepareSignerMessage")
+	var legacySig Signature
+	legacySig.PublicKey = pk
+	copy(legacySig.Signature[:], ed25519.Sign(privKey, data))
+
+	var schemePk SchemePublicKey
+	require.NoError(cbor.Unmarshal(cbor.Marshal(pk), &schemePk), "legacy public key should decode")
+	require.Equal(SchemeEd25519, schemePk.Scheme)
+	decodedPk, ok := schemePk.Ed25519()
+	require.True(ok, "Ed25519")
+	require.Equal(pk, decodedPk)
+	require.Equal(cbor.Marshal(pk), cbor.Marshal(schemePk), "Ed25519 encoding should match the legacy encoding")
+
+	var schemeSig SchemeSignature
+	require.NoError(cbor.Unmarshal(cbor.Marshal(legacySig), &schemeSig), "legacy signature should decode")
+	require.True(schemeSig.Verify(ctx, msg), "decoded legacy signature should verify")
+	require.Equal(cbor.Marshal(legacySig), cbor.Marshal(schemeSig), "Ed25519 encoding should match the legacy encoding")
+
+	legacyJSON, err := json.Marshal(legacySig)
+	require.NoError(err, "json.Marshal")
+	schemeSig = SchemeSignature{}
+	require.NoError(json.Unmarshal(legacyJSON, &schemeSig), "legacy JSON signature should decode")
+	require.True(schemeSig.Verify(ctx, msg), "decoded legacy JSON signature should verify")
+
+	// Other schemes should round-trip.
+	secp256k1Signer, err := NewSecp256k1Signer(rand.Reader)
+	require.NoError(err, "NewSecp256k1Signer")
+	blsSigner, err := NewBLSSigner(rand.Reader)
+	require.NoError(err, "NewBLSSigner")
+	for _, signer := range []SchemeSigner{secp256k1Signer, blsSigner} {
+		sig, err := SignScheme(signer, ctx, msg)
+		require.NoError(err, "SignScheme")
+
+		var decoded SchemeSignature
+		require.NoError(cbor.Unmarshal(cbor.Marshal(sig), &decoded), "CBOR round-trip")
+		require.True(decoded.Equal(sig))
+		require.True(decoded.Verify(ctx, msg))
+
+		raw, err := json.Marshal(sig)
+		require.NoError(err, "json.Marshal")
+		decoded = SchemeSignature{}
+		require.NoError(json.Unmarshal(raw, &decoded), "JSON round-trip")
+		require.True(decoded.Equal(sig))
+
+		// Non-Ed25519 keys must not decode as legacy public keys.
+		var legacyPk PublicKey
+		require.Error(cbor.Unmarshal(cbor.Marshal(sig.PublicKey), &legacyPk), "%s public key should not decode as Ed25519", signer.Public().Scheme)
+	}
+
+	// Malformed encodings should be rejected.
+	secp256k1Key := secp256k1Signer.Public().Key
+	for _, tc := range []struct {
+		data []byte
+		msg  string
+	}{
+		{nil, "empty public key"},
+		{append([]byte{byte(SchemeEd25519)}, pk[:]...), "tagged Ed25519 public key"},
+		{append([]byte{byte(SchemeSecp256k1)}, secp256k1Key[1:]...), "truncated secp256k1 public key"},
+		{append([]byte{byte(SchemeBLS12381)}, secp256k1Key...), "secp256k1 public key tagged as BLS"},
+	} {
+		var k SchemePublicKey
+		require.ErrorIs(k.UnmarshalBinary(tc.data), ErrMalformedPublicKey, tc.msg)
+	}
+	var k SchemePublicKey
+	require.ErrorIs(k.UnmarshalBinary([]byte{42, 1, 2, 3}), ErrUnsupportedScheme, "unknown scheme")
+	_, err = SchemePublicKey{Scheme: SchemeSecp256k1, Key: pk[:]}.MarshalBinary()
+	require.ErrorIs(err, ErrMalformedPublicKey, "mislabeled public key should not encode")
+
+	var scheme Scheme
+	require.NoError(scheme.UnmarshalText([]byte(SchemeBLS12381Name)))
+	require.Equal(SchemeBLS12381, scheme)
+	require.ErrorIs(scheme.UnmarshalText([]byte("rsa")), ErrUnsupportedScheme)
+}
+
+func TestBLSAggregate(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewContext("test: BLS aggregate context", WithSchemes(SchemeBLS12381))
+	otherCtx := NewContext("test: BLS aggregate other context", WithSchemes(SchemeBLS12381))
+	consensusCtx := NewContext("test: BLS aggregate consensus context")
+	msg := []byte("BLS aggregate test message")
+
+	var (
+		pks  []SchemePublicKey
+		sigs [][]byte
+	)
+	for range 4 {
+		signer, err := NewBLSSigner(rand.Reader)
+		require.NoError(err, "NewBLSSigner")
+		sig, err := signer.ContextSign(ctx, msg)
+		require.NoError(err, "ContextSign")
+
+		pks = append(pks, signer.Public())
+		sigs = append(sigs, sig)
+	}
+
+	aggSig, err := AggregateBLSSignatures(sigs)
+	require.NoError(err, "AggregateBLSSignatures")
+	require.Len(aggSig, BLSSignatureSize)
+	require.True(VerifyBLSAggregate(ctx, msg, pks, aggSig), "aggregate signature should verify")
+	require.True(VerifyBLSAggregate(ctx, msg, []SchemePublicKey{pks[3], pks[1], pks[2], pks[0]}, aggSig), "public key order should not matter")
+
+	require.False(VerifyBLSAggregate(ctx, []byte("other message"), pks, aggSig), "aggregate signature should not verify for other messages")
+	require.False(VerifyBLSAggregate(otherCtx, msg, pks, aggSig), "aggregate signature should not verify for other contexts")
+	require.False(VerifyBLSAggregate(consensusCtx, msg, pks, aggSig), "aggregate signature should not verify for consensus context")
+	require.False(VerifyBLSAggregate(ctx, msg, pks[:3], aggSig), "aggregate signature should not verify with missing signers")
+	require.False(VerifyBLSAggregate(ctx, msg, append(pks[:3:3], pks[0]), aggSig), "aggregate signature should not verify with duplicate signers")
+	require.False(VerifyBLSAggregate(ctx, msg, nil, aggSig), "aggregate signature should not verify without signers")
+	require.False(VerifyBLSAggregate(ctx, msg, pks, sigs[0]), "single signature should not verify as aggregate")
+
+	partialSig, err := AggregateBLSSignatures(sigs[:3])
+	require.NoError(err, "AggregateBLSSignatures")
+	require.False(VerifyBLSAggregate(ctx, msg, pks, partialSig), "partial aggregate signature should not verify for all signers")
+	require.True(VerifyBLSAggregate(ctx, msg, pks[:3], partialSig), "partial aggregate signature should verify for its signers")
+
+	// Public keys of other schemes should be rejected.
+	secp256k1Signer, err := NewSecp256k1Signer(rand.Reader)
+	require.NoError(err, "NewSecp256k1Signer")
+	mixed := append([]SchemePublicKey{secp256k1Signer.Public()}, pks[1:]...)
+	require.False(VerifyBLSAggregate(ctx, msg, mixed, aggSig), "aggregate signature should not verify with secp256k1 keys")
+	relabeled := append([]SchemePublicKey{{Scheme: SchemeSecp256k1, Key: pks[0].Key}}, pks[1:]...)
+	require.False(VerifyBLSAggregate(ctx, msg, relabeled, aggSig), "aggregate signature should not verify with relabeled keys")
+
+	_, err = AggregateBLSSignatures(nil)
+	require.ErrorIs(err, ErrMalformedSignature, "aggregating no signatures should fail")
+	_, err = AggregateBLSSignatures([][]byte{sigs[0], make([]byte, Secp256k1SignatureSize)})
+	require.ErrorIs(err, ErrMalformedSignature, "aggregating non-BLS signatures should fail")
+}
@@ -0,0 +1,121 @@
+package signature
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+const (
+	// Secp256k1PublicKeySize is the size of a compressed secp256k1 public key in bytes.
+	Secp256k1PublicKeySize = secp256k1.PubKeyBytesLenCompressed
+
+	// Secp256k1SignatureSize is the size of a secp256k1 signature in bytes.
+	Secp256k1SignatureSize = 64
+
+	// Secp256k1PrivateKeySize is the size of a secp256k1 private key in bytes.
+	Secp256k1PrivateKeySize = secp256k1.PrivKeyBytesLen
+)
+
+var _ SchemeSigner = (*Secp256k1Signer)(nil)
+
+// Secp256k1Signer is a signer producing ECDSA signatures over the secp256k1 curve.
+//
+// Signatures are computed over the same prehashed context and message as Ed25519 signatures
+// and are encoded as the 32-byte big-endian R and S values, with S always in the lower half of
+// the curve order.
+type Secp256k1Signer struct {
+	privateKey *secp256k1.PrivateKey
+}
+
+// NewSecp256k1Signer generates a new secp256k1 signer using the given entropy source.
+func NewSecp256k1Signer(rng io.Reader) (*Secp256k1Signer, error) {
+	privateKey, err := secp256k1.GeneratePrivateKeyFromRand(rng)
+	if err != nil {
+		return nil, fmt.Errorf("signature: failed to generate secp256k1 private key: %w", err)
+	}
+	return &Secp256k1Signer{privateKey}, nil
+}
+
+// NewSecp256k1SignerFromBytes creates a new secp256k1 signer from the given private key bytes.
+func NewSecp256k1SignerFromBytes(data []byte) (*Secp256k1Signer, error) {
+	if len(data) != Secp256k1PrivateKeySize {
+		return nil, ErrMalformedPrivateKey
+	}
+
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(data); overflow || scalar.IsZero() {
+		return nil, ErrMalformedPrivateKey
+	}
+	return &Secp256k1Signer{secp256k1.NewPrivateKey(&scalar)}, nil
+}
+
+// Public returns the scheme-tagged public key corresponding to the signer.
+func (s *Secp256k1Signer) Public() SchemePublicKey {
+	return SchemePublicKey{
+		Scheme: SchemeSecp256k1,
+		Key:    s.privateKey.PubKey().SerializeCompressed(),
+	}
+}
+
+// ContextSign generates a signature with the private key over the context and message.
+func (s *Secp256k1Signer) ContextSign(context Context, message []byte) ([]byte, error) {
+	if !IsSchemeAllowed(context, SchemeSecp256k1) {
+		return nil, ErrSchemeNotAllowed
+	}
+
+	data, err := PrepareSignerMessage(context, message)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := ecdsa.Sign(s.privateKey, data)
+	r, ss := sig.R(), sig.S()
+
+	var raw [Secp256k1SignatureSize]byte
+	r.PutBytesUnchecked(raw[:32])
+	ss.PutBytesUnchecked(raw[32:])
+	return raw[:], nil
+}
+
+// String returns a string representation of the signer.
+func (s *Secp256k1Signer) String() string {
+	return "[redacted secp256k1 private key]"
+}
+
+// Reset tears down the signer and obliterates any sensitive state if any.
+func (s *Secp256k1Signer) Reset() {
+	s.privateKey.Zero()
+}
+
+// UnsafeBytes returns the byte representation of the private key.
+func (s *Secp256k1Signer) UnsafeBytes() []byte {
+	return s.privateKey.Serialize()
+}
+
+func verifySecp256k1(key []byte, context Context, message, sig []byte) bool {
+	if len(key) != Secp256k1PublicKeySize || len(sig) != Secp256k1SignatureSize {
+		return false
+	}
+	pk, err := secp256k1.ParsePubKey(key)
+	if err != nil {
+		return false
+	}
+
+	// Reject zero, out of range and high-S values to prevent signature malleability.
+	var r, s secp256k1.ModNScalar
+	if overflow := r.SetByteSlice(sig[:32]); overflow || r.IsZero() {
+		return false
+	}
+	if overflow := s.SetByteSlice(sig[32:]); overflow || s.IsZero() || s.IsOverHalfOrder() {
+		return false
+	}
+
+	data, err := PrepareSignerMessage(context, message)
+	if err != nil {
+		return false
+	}
+	return ecdsa.NewSignature(&r, &s).Verify(data, pk)
+}
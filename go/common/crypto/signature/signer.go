@@ -41,6 +41,11 @@ var (
 	// of generating VRF proofs.
 	ErrVRFNotSupported = errors.New("signature: VRF proofs not supported")
 
+	// ErrPassphraseRequired is the error returned when a private key is
+	// encrypted, but no passphrase is available to decrypt it (e.g., when
+	// running in a non-interactive context).
+	ErrPassphraseRequired = errors.New("signature: passphrase required")
+
 	errMalformedContext    = errors.New("signature: malformed context")
 	errUnregisteredContext = errors.New("signature: unregistered context")
 	errNoChainContext      = errors.New("signature: chain domain separation context not set")
@@ -71,6 +76,8 @@ type contextOptions struct {
 
 	dynamicSuffix       string
 	dynamicSuffixMaxLen int
+
+	schemes []Scheme
 }
 
 // ContextOption is a context configuration option.
@@ -93,6 +100,16 @@ func WithDynamicSuffix(str string, maxLen int) ContextOption {
 	}
 }
 
+// WithSchemes is a context option that allows the given signature schemes to
+// be used with the context in addition to Ed25519.
+//
+// Contexts used by consensus-critical roles must not use this option.
+func WithSchemes(schemes ...Scheme) ContextOption {
+	return func(o *contextOptions) {
+		o.schemes = append(o.schemes, schemes...)
+	}
+}
+
 // Context is a domain separation context.
 type Context string
 
@@ -116,6 +133,7 @@ func (c Context) WithSuffix(str string) (Context, error) {
 	// No dynamic suffix for the new context.
 	newOpts := contextOptions{
 		chainSeparation: opts.chainSeparation,
+		schemes:         opts.schemes,
 	}
 	// Register the context so it can be looked up (same suffix can be used multiple times).
 	_, _ = registeredContexts.LoadOrStore(newCtx, &newOpts)
@@ -148,6 +166,12 @@ func NewContext(rawContext string, opts ...ContextOption) Context {
 		panic(errMalformedContext)
 	}
 
+	for _, scheme := range opt.schemes {
+		if _, err := scheme.publicKeySize(); err != nil {
+			panic(err)
+		}
+	}
+
 	// Disallow contexts including the chain context separator as a simple
 	// way to avoid conflicts with chain-separated contexts.
 	if strings.Contains(rawContext, chainContextSeparator) {
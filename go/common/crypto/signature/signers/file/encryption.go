@@ -0,0 +1,141 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/deoxysii"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+const (
+	encryptedPemTypePrefix = "ENCRYPTED "
+
+	// Default scrypt parameters used to derive the key file encryption key from the passphrase.
+	scryptN        = 1 << 15
+	scryptR        = 8
+	scryptP        = 1
+	scryptSaltSize = 32
+
+	// Upper bound on the scrypt cost parameter accepted when decrypting key files.
+	scryptMaxN = 1 << 20
+)
+
+// ErrInvalidPassphrase is the error returned when an encrypted key file cannot be decrypted with
+// the provided passphrase.
+var ErrInvalidPassphrase = errors.New("signature/signer/file: invalid passphrase")
+
+// PassphraseFunc returns the passphrase used to encrypt and decrypt key files.
+//
+// In case no passphrase is available (e.g., when running in a non-interactive context), the
+// function should return an error wrapping signature.ErrPassphraseRequired.
+type PassphraseFunc func() ([]byte, error)
+
+// encryptedKeyFile is the envelope of an encrypted key file.
+type encryptedKeyFile struct {
+	// Salt is the scrypt salt.
+	Salt []byte `json:"salt"`
+	// N is the scrypt CPU/memory cost parameter.
+	N uint64 `json:"n"`
+	// R is the scrypt block size parameter.
+	R uint64 `json:"r"`
+	// P is the scrypt parallelization parameter.
+	P uint64 `json:"p"`
+	// Nonce is the Deoxys-II nonce.
+	Nonce []byte `json:"nonce"`
+	// Ciphertext is the Deoxys-II encrypted plaintext PEM file.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (ek *encryptedKeyFile) deriveKey(passphrase []byte) ([]byte, error) {
+	if ek.N > scryptMaxN || ek.R == 0 || ek.P == 0 || ek.R*ek.P >= 1<<30 {
+		return nil, fmt.Errorf("signature/signer/file: invalid key derivation parameters")
+	}
+	return scrypt.Key(passphrase, ek.Salt, int(ek.N), int(ek.R), int(ek.P), deoxysii.KeySize)
+}
+
+func isEncryptedPEM(data []byte) bool {
+	blk, _ := pem.Decode(data)
+	return blk != nil && strings.HasPrefix(blk.Type, encryptedPemTypePrefix)
+}
+
+// encryptPEM encrypts the given plaintext PEM file with a key derived from the passphrase.
+func encryptPEM(data []byte, passphrase []byte) ([]byte, error) {
+	blk, _ := pem.Decode(data)
+	if blk == nil {
+		return nil, fmt.Errorf("signature/signer/file: malformed PEM file")
+	}
+	pemType := encryptedPemTypePrefix + blk.Type
+
+	ek := encryptedKeyFile{
+		Salt:  make([]byte, scryptSaltSize),
+		N:     scryptN,
+		R:     scryptR,
+		P:     scryptP,
+		Nonce: make([]byte, deoxysii.NonceSize),
+	}
+	if _, err := rand.Read(ek.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(ek.Nonce); err != nil {
+		return nil, err
+	}
+
+	key, err := ek.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return nil, err
+	}
+	ek.Ciphertext = aead.Seal(nil, ek.Nonce, data, []byte(pemType))
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemType,
+		Bytes: cbor.Marshal(&ek),
+	}), nil
+}
+
+// decryptPEM decrypts the given encrypted PEM file and returns the plaintext PEM file.
+func decryptPEM(data []byte, passphrase []byte) ([]byte, error) {
+	blk, rest := pem.Decode(data)
+	if blk == nil || len(rest) != 0 || !strings.HasPrefix(blk.Type, encryptedPemTypePrefix) {
+		return nil, signature.ErrMalformedPrivateKey
+	}
+
+	var ek encryptedKeyFile
+	if err := cbor.Unmarshal(blk.Bytes, &ek); err != nil {
+		return nil, signature.ErrMalformedPrivateKey
+	}
+	if len(ek.Nonce) != deoxysii.NonceSize {
+		return nil, signature.ErrMalformedPrivateKey
+	}
+
+	key, err := ek.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := deoxysii.New(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, ek.Nonce, ek.Ciphertext, []byte(blk.Type))
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	// Ensure the encrypted PEM type matches the plaintext PEM type.
+	inner, _ := pem.Decode(plaintext)
+	if inner == nil || encryptedPemTypePrefix+inner.Type != blk.Type {
+		return nil, signature.ErrMalformedPrivateKey
+	}
+
+	return plaintext, nil
+}
@@ -58,17 +58,40 @@ var (
 	}
 )
 
+// FactoryConfig is the file backed SignerFactory configuration.
+type FactoryConfig struct {
+	// DataDir is the directory containing the key files.
+	DataDir string
+
+	// Passphrase is an optional function returning the passphrase used to encrypt and decrypt
+	// key files. It is only invoked once a key file needs to be encrypted or decrypted.
+	Passphrase PassphraseFunc
+
+	// Encrypt specifies whether newly generated key files should be encrypted.
+	Encrypt bool
+}
+
 // NewFactory creates a new factory with the specified roles, with the
-// specified dataDir.
+// specified configuration which is either a dataDir or a FactoryConfig.
 func NewFactory(config any, roles ...signature.SignerRole) (signature.SignerFactory, error) {
-	dataDir, ok := config.(string)
-	if !ok {
+	var cfg FactoryConfig
+	switch c := config.(type) {
+	case string:
+		cfg.DataDir = c
+	case *FactoryConfig:
+		cfg = *c
+	default:
 		return nil, errors.New("signature/signer/file: invalid file signer configuration provided")
 	}
+	if cfg.Encrypt && cfg.Passphrase == nil {
+		return nil, errors.New("signature/signer/file: encryption requires a passphrase")
+	}
 
 	return &Factory{
-		roles:   append([]signature.SignerRole{}, roles...),
-		dataDir: dataDir,
+		roles:        append([]signature.SignerRole{}, roles...),
+		dataDir:      cfg.DataDir,
+		passphraseFn: cfg.Passphrase,
+		encrypt:      cfg.Encrypt,
 	}, nil
 }
 
@@ -76,6 +99,10 @@ func NewFactory(config any, roles ...signature.SignerRole) (signature.SignerFact
 type Factory struct {
 	roles   []signature.SignerRole
 	dataDir string
+
+	passphraseFn PassphraseFunc
+	passphrase   []byte
+	encrypt      bool
 }
 
 // EnsureRole ensures that the SignerFactory is configured for the given
@@ -122,7 +149,7 @@ func (fac *Factory) Generate(role signature.SignerRole, rng io.Reader) (signatur
 	if err != nil {
 		return nil, err
 	}
-	if err = os.WriteFile(fn, buf, filePerm); err != nil {
+	if err = fac.writePEM(fn, buf); err != nil {
 		return nil, err
 	}
 
@@ -148,7 +175,7 @@ func (fac *Factory) generateStaticEntropy(fn string, signer *Signer, rng io.Read
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(fac.dataDir, fn), buf, filePerm)
+	return fac.writePEM(filepath.Join(fac.dataDir, fn), buf)
 }
 
 // Load will load the private key corresponding to the role, and return a Signer
@@ -186,7 +213,144 @@ func (fac *Factory) loadPEM(fn string) ([]byte, error) {
 		return nil, fmt.Errorf("signature/signer/file: invalid PEM file permissions %o on %s", fi.Mode(), fn)
 	}
 
-	return io.ReadAll(f)
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if !isEncryptedPEM(buf) {
+		// Unencrypted key files are always supported.
+		return buf, nil
+	}
+
+	passphrase, err := fac.getPassphrase()
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/file: failed to decrypt %s: %w", fn, err)
+	}
+	buf, err = decryptPEM(buf, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("signature/signer/file: failed to decrypt %s: %w", fn, err)
+	}
+	return buf, nil
+}
+
+func (fac *Factory) writePEM(fn string, buf []byte) error {
+	if fac.encrypt {
+		passphrase, err := fac.getPassphrase()
+		if err != nil {
+			return fmt.Errorf("signature/signer/file: failed to encrypt %s: %w", fn, err)
+		}
+		if buf, err = encryptPEM(buf, passphrase); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(fn, buf, filePerm)
+}
+
+func (fac *Factory) getPassphrase() ([]byte, error) {
+	if fac.passphrase != nil {
+		return fac.passphrase, nil
+	}
+	if fac.passphraseFn == nil {
+		return nil, signature.ErrPassphraseRequired
+	}
+
+	passphrase, err := fac.passphraseFn()
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("signature/signer/file: empty passphrase")
+	}
+	fac.passphrase = passphrase
+
+	return passphrase, nil
+}
+
+// RotatePassphrase re-encrypts all existing key files of the factory's roles with the given
+// passphrase. Unencrypted key files get encrypted and newly generated key files will also be
+// encrypted with the new passphrase.
+//
+// All key files are decrypted and re-encrypted into temporary files before any original key file
+// is atomically replaced, so that a failure (e.g., due to a wrong current passphrase) while
+// preparing the key files leaves them intact.
+func (fac *Factory) RotatePassphrase(passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return fmt.Errorf("signature/signer/file: empty passphrase")
+	}
+
+	fns := make(map[string]struct{})
+	for _, role := range fac.roles {
+		fn, ok := rolePEMFiles[role]
+		if !ok {
+			continue
+		}
+		fns[filepath.Join(fac.dataDir, fn)] = struct{}{}
+		if role == signature.SignerP2P {
+			fns[filepath.Join(fac.dataDir, FileP2PStaticEntropy)] = struct{}{}
+		}
+	}
+
+	// Prepare re-encrypted key files.
+	tmpFns := make(map[string]string)
+	cleanup := func() {
+		for _, tmpFn := range tmpFns {
+			_ = os.Remove(tmpFn)
+		}
+	}
+	for fn := range fns {
+		buf, err := fac.loadPEM(fn)
+		switch err {
+		case nil:
+		case signature.ErrNotExist:
+			continue
+		default:
+			cleanup()
+			return err
+		}
+		if buf, err = encryptPEM(buf, passphrase); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Remove any leftover temporary file from an interrupted rotation.
+		tmpFn := fn + ".tmp"
+		_ = os.Remove(tmpFn)
+		tmpFns[fn] = tmpFn
+		if err = writeFileSync(tmpFn, buf); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	// Replace the original key files.
+	for fn, tmpFn := range tmpFns {
+		if err := os.Rename(tmpFn, fn); err != nil {
+			cleanup()
+			return fmt.Errorf("signature/signer/file: failed to replace %s: %w", fn, err)
+		}
+		delete(tmpFns, fn)
+	}
+
+	fac.passphrase = passphrase
+	fac.encrypt = true
+
+	return nil
+}
+
+func writeFileSync(fn string, buf []byte) error {
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_CREATE|os.O_EXCL, filePerm)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
 func (fac *Factory) doLoad(fn string, role signature.SignerRole) (signature.Signer, error) {
@@ -105,3 +105,134 @@ func TestStaticEntropy(t *testing.T) {
 	require.NoError(err, "StaticEntropy()")
 	require.NotEqual(se, se2, "static entropy is regenerated")
 }
+
+func TestEncryptedFileSigner(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "oasis-signature-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(tmpDir)
+
+	passphrase := func(p string) PassphraseFunc {
+		return func() ([]byte, error) {
+			return []byte(p), nil
+		}
+	}
+
+	// Encryption requires a passphrase.
+	_, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Encrypt: true}, signature.SignerP2P)
+	require.Error(err, "NewFactory() without passphrase")
+
+	// Generate encrypted keys.
+	factory, err := NewFactory(&FactoryConfig{
+		DataDir:    tmpDir,
+		Passphrase: passphrase("correct horse"),
+		Encrypt:    true,
+	}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+
+	signer, err := factory.Generate(signature.SignerP2P, rand.Reader)
+	require.NoError(err, "Generate(SignerP2P, rand.Reader)")
+	se, err := signer.(signature.StaticEntropyProvider).StaticEntropy()
+	require.NoError(err, "StaticEntropy()")
+
+	for _, fn := range []string{FileP2PKey, FileP2PStaticEntropy} {
+		buf, rerr := os.ReadFile(filepath.Join(tmpDir, fn))
+		require.NoError(rerr, "ReadFile(%s)", fn)
+		require.True(isEncryptedPEM(buf), "%s should be encrypted", fn)
+	}
+
+	// Load with the correct passphrase.
+	factory, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Passphrase: passphrase("correct horse")}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	signer2, err := factory.Load(signature.SignerP2P)
+	require.NoError(err, "Load(SignerP2P)")
+	require.Equal(signer, signer2, "Generated = Loaded")
+	se2, err := signer2.(signature.StaticEntropyProvider).StaticEntropy()
+	require.NoError(err, "StaticEntropy()")
+	require.EqualValues(se, se2, "static entropy round trips")
+
+	// Load without a passphrase.
+	factory, err = NewFactory(tmpDir, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	_, err = factory.Load(signature.SignerP2P)
+	require.ErrorIs(err, signature.ErrPassphraseRequired, "Load(SignerP2P) without passphrase")
+
+	// Load with a wrong passphrase.
+	factory, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Passphrase: passphrase("battery staple")}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	_, err = factory.Load(signature.SignerP2P)
+	require.ErrorIs(err, ErrInvalidPassphrase, "Load(SignerP2P) with wrong passphrase")
+
+	// Rotating the passphrase requires the correct current passphrase and leaves the key files
+	// intact otherwise.
+	err = factory.(*Factory).RotatePassphrase([]byte("battery staple"))
+	require.ErrorIs(err, ErrInvalidPassphrase, "RotatePassphrase() with wrong passphrase")
+
+	factory, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Passphrase: passphrase("correct horse")}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	err = factory.(*Factory).RotatePassphrase([]byte("battery staple"))
+	require.NoError(err, "RotatePassphrase()")
+
+	factory, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Passphrase: passphrase("correct horse")}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	_, err = factory.Load(signature.SignerP2P)
+	require.ErrorIs(err, ErrInvalidPassphrase, "Load(SignerP2P) with old passphrase")
+
+	factory, err = NewFactory(&FactoryConfig{DataDir: tmpDir, Passphrase: passphrase("battery staple")}, signature.SignerP2P)
+	require.NoError(err, "NewFactory()")
+	signer2, err = factory.Load(signature.SignerP2P)
+	require.NoError(err, "Load(SignerP2P) with new passphrase")
+	require.Equal(signer, signer2, "Generated = Loaded")
+}
+
+func TestEncryptLegacyFileSigner(t *testing.T) {
+	require := require.New(t)
+
+	tmpDir, err := os.MkdirTemp("", "oasis-signature-test")
+	require.NoError(err, "TempDir()")
+	defer os.RemoveAll(tmpDir)
+
+	// Generate unencrypted keys.
+	factory, err := NewFactory(tmpDir, signature.SignerNode)
+	require.NoError(err, "NewFactory()")
+	signer, err := factory.Generate(signature.SignerNode, rand.Reader)
+	require.NoError(err, "Generate(SignerNode, rand.Reader)")
+
+	// Unencrypted keys load even when a passphrase is configured.
+	var prompted bool
+	factory, err = NewFactory(&FactoryConfig{
+		DataDir: tmpDir,
+		Passphrase: func() ([]byte, error) {
+			prompted = true
+			return []byte("correct horse"), nil
+		},
+	}, signature.SignerNode)
+	require.NoError(err, "NewFactory()")
+	signer2, err := factory.Load(signature.SignerNode)
+	require.NoError(err, "Load(SignerNode)")
+	require.Equal(signer, signer2, "Generated = Loaded")
+	require.False(prompted, "passphrase should not be requested for unencrypted keys")
+
+	// Encrypt existing keys.
+	err = factory.(*Factory).RotatePassphrase([]byte("battery staple"))
+	require.NoError(err, "RotatePassphrase()")
+
+	buf, err := os.ReadFile(filepath.Join(tmpDir, FileIdentityKey))
+	require.NoError(err, "ReadFile()")
+	require.True(isEncryptedPEM(buf), "key file should be encrypted")
+	info, err := os.Stat(filepath.Join(tmpDir, FileIdentityKey))
+	require.NoError(err, "Stat()")
+	require.EqualValues(filePerm, info.Mode().Perm(), "key file permissions should be preserved")
+
+	factory, err = NewFactory(&FactoryConfig{
+		DataDir: tmpDir,
+		Passphrase: func() ([]byte, error) {
+			return []byte("battery staple"), nil
+		},
+	}, signature.SignerNode)
+	require.NoError(err, "NewFactory()")
+	signer2, err = factory.Load(signature.SignerNode)
+	require.NoError(err, "Load(SignerNode)")
+	require.Equal(signer, signer2, "Generated = Loaded")
+}
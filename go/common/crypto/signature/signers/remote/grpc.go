@@ -3,28 +3,55 @@ package remote
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
-// SignerName is the name used to identify the remote signer.
-const SignerName = "remote"
+const (
+	// SignerName is the name used to identify the remote signer.
+	SignerName = "remote"
+
+	// attestationNonceSize is the size of the attestation nonce in bytes.
+	attestationNonceSize = 32
+
+	// defaultHealthCheckInterval is the default interval between signer health checks.
+	defaultHealthCheckInterval = 5 * time.Second
+	// defaultRecoveryThreshold is the default number of consecutive successful health checks
+	// required before signing is resumed after the signer has been unreachable.
+	defaultRecoveryThreshold = 3
+)
 
 var (
+	// ErrSignerUnavailable is the error returned when the remote signer is unreachable
+	// or has not yet recovered from being unreachable.
+	ErrSignerUnavailable = errors.New("signature/signer/remote: signer unavailable")
+
+	// AttestationSignatureContext is the context used for signer identity attestations.
+	AttestationSignatureContext = signature.NewContext("oasis-core/remote-signer: attestation")
+
 	serviceName = cmnGrpc.NewServiceName("RemoteSigner")
 
 	methodPublicKeys = serviceName.NewMethod("PublicKeys", nil)
 	methodSign       = serviceName.NewMethod("Sign", SignRequest{})
 	methodProve      = serviceName.NewMethod("Prove", ProveRequest{})
+	methodAttest     = serviceName.NewMethod("Attest", AttestRequest{})
 
 	serviceDesc = grpc.ServiceDesc{
 		ServiceName: string(serviceName),
@@ -42,6 +69,10 @@ var (
 				MethodName: methodProve.ShortName(),
 				Handler:    handlerProve,
 			},
+			{
+				MethodName: methodAttest.ShortName(),
+				Handler:    handlerAttest,
+			},
 		},
 	}
 )
@@ -65,11 +96,45 @@ type ProveRequest struct {
 	Alpha []byte               `json:"alpha"`
 }
 
+// AttestRequest is a signer identity attestation request.
+type AttestRequest struct {
+	Nonce []byte `json:"nonce"`
+}
+
+// Attestation is an attestation that the signer holds the private key
+// corresponding to the given role's public key.
+type Attestation struct {
+	Role      signature.SignerRole `json:"role"`
+	PublicKey signature.PublicKey  `json:"public_key"`
+	Signature []byte               `json:"signature"`
+}
+
+// attestationBody is the message signed by an attestation.
+type attestationBody struct {
+	Nonce     []byte               `json:"nonce"`
+	Role      signature.SignerRole `json:"role"`
+	PublicKey signature.PublicKey  `json:"public_key"`
+}
+
+// Verify verifies the attestation against the given nonce.
+func (a *Attestation) Verify(nonce []byte) error {
+	body := attestationBody{
+		Nonce:     nonce,
+		Role:      a.Role,
+		PublicKey: a.PublicKey,
+	}
+	if !a.PublicKey.Verify(AttestationSignatureContext, cbor.Marshal(body), a.Signature) {
+		return fmt.Errorf("signature/signer/remote: invalid attestation for role %v", a.Role)
+	}
+	return nil
+}
+
 // Backend is the remote signer backend interface.
 type Backend interface {
 	PublicKeys() ([]PublicKey, error)
 	Sign(*SignRequest) ([]byte, error)
 	Prove(*ProveRequest) ([]byte, error)
+	Attest(*AttestRequest) ([]Attestation, error)
 }
 
 type wrapper struct {
@@ -94,6 +159,10 @@ func (w *wrapper) Sign(req *SignRequest) ([]byte, error) {
 	if !ok {
 		return nil, signature.ErrNotExist
 	}
+	// Prevent the signing of messages that could be confused with attestations.
+	if req.Context == string(AttestationSignatureContext) {
+		return nil, fmt.Errorf("signature/signer/remote: reserved signature context")
+	}
 	return signer.ContextSign(signature.Context(req.Context), req.Message)
 }
 
@@ -107,11 +176,40 @@ func (w *wrapper) Prove(req *ProveRequest) ([]byte, error) {
 		return nil, fmt.Errorf("signature/signer/remote: signer does not support VRF prove")
 	}
 	if req.Role != signature.SignerVRF {
-		return nil, signature.ErrInvalidRole
+		return nil, signature.ErrRoleAction
 	}
 	return vrfSigner.Prove(req.Alpha)
 }
 
+func (w *wrapper) Attest(req *AttestRequest) ([]Attestation, error) {
+	if len(req.Nonce) != attestationNonceSize {
+		return nil, fmt.Errorf("signature/signer/remote: invalid attestation nonce")
+	}
+
+	var resp []Attestation
+	for _, v := range signature.SignerRoles { // Return in consistent order.
+		signer := w.signers[v]
+		if signer == nil {
+			continue
+		}
+		body := attestationBody{
+			Nonce:     req.Nonce,
+			Role:      v,
+			PublicKey: signer.Public(),
+		}
+		sig, err := signer.ContextSign(AttestationSignatureContext, cbor.Marshal(body))
+		if err != nil {
+			return nil, fmt.Errorf("signature/signer/remote: failed to attest role %v: %w", v, err)
+		}
+		resp = append(resp, Attestation{
+			Role:      v,
+			PublicKey: body.PublicKey,
+			Signature: sig,
+		})
+	}
+	return resp, nil
+}
+
 func handlerPublicKeys(
 	srv any,
 	ctx context.Context,
@@ -177,6 +275,29 @@ func handlerProve(
 	return interceptor(ctx, &req, info, handler)
 }
 
+func handlerAttest(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req AttestRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).Attest(&req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodAttest.FullName(),
+	}
+	handler := func(_ context.Context, req any) (any, error) {
+		return srv.(Backend).Attest(req.(*AttestRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 // RegisterService registers a new remote signer backend service with the given
 // gRPC server.
 func RegisterService(server *grpc.Server, signerFactory signature.SignerFactory) {
@@ -201,8 +322,15 @@ func RegisterService(server *grpc.Server, signerFactory signature.SignerFactory)
 type remoteFactory struct {
 	conn   *grpc.ClientConn
 	reqCtx context.Context
+	cancel context.CancelFunc
 
 	signers map[signature.SignerRole]*remoteSigner
+
+	// attestation is true iff the signer supports identity attestations.
+	attestation bool
+	healthy     atomic.Bool
+
+	logger *logging.Logger
 }
 
 func (rf *remoteFactory) EnsureRole(role signature.SignerRole) error {
@@ -221,9 +349,130 @@ func (rf *remoteFactory) Load(role signature.SignerRole) (signature.Signer, erro
 	if signer == nil {
 		return nil, signature.ErrNotExist
 	}
+	if role == signature.SignerVRF {
+		return &remoteVRFSigner{signer}, nil
+	}
 	return signer, nil
 }
 
+// Close stops the signer health watchdog. Signing requests fail once the factory is closed.
+func (rf *remoteFactory) Close() error {
+	rf.cancel()
+	return nil
+}
+
+// invoke invokes the given method on the remote signer, unless the signer is
+// considered unavailable.
+func (rf *remoteFactory) invoke(method string, req, rsp any) error {
+	if !rf.healthy.Load() {
+		return ErrSignerUnavailable
+	}
+	if err := rf.conn.Invoke(rf.reqCtx, method, req, rsp); err != nil {
+		if status.Code(err) == codes.Unavailable {
+			rf.markUnhealthy(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// attest requests a fresh signer identity attestation and verifies that it
+// covers exactly the given set of public keys.
+func (rf *remoteFactory) attest(ctx context.Context, expected []PublicKey) error {
+	nonce := make([]byte, attestationNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("signature/signer/remote: failed to generate nonce: %w", err)
+	}
+
+	var rsp []Attestation
+	if err := rf.conn.Invoke(ctx, methodAttest.FullName(), &AttestRequest{Nonce: nonce}, &rsp); err != nil {
+		return err
+	}
+	if len(rsp) != len(expected) {
+		return fmt.Errorf("signature/signer/remote: attestation does not cover all keys")
+	}
+	for i, att := range rsp {
+		if att.Role != expected[i].Role || !att.PublicKey.Equal(expected[i].PublicKey) {
+			return fmt.Errorf("signature/signer/remote: signer identity changed for role %v", att.Role)
+		}
+		if err := att.Verify(nonce); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIdentity verifies that the signer still holds the given set of public keys. In case the
+// signer doesn't support attestations, only the advertised public keys are compared.
+func (rf *remoteFactory) checkIdentity(ctx context.Context, expected []PublicKey) error {
+	if rf.attestation {
+		return rf.attest(ctx, expected)
+	}
+
+	var rsp []PublicKey
+	if err := rf.conn.Invoke(ctx, methodPublicKeys.FullName(), nil, &rsp); err != nil {
+		return err
+	}
+	if len(rsp) != len(expected) {
+		return fmt.Errorf("signature/signer/remote: signer keys changed")
+	}
+	for i, pk := range rsp {
+		if pk.Role != expected[i].Role || !pk.PublicKey.Equal(expected[i].PublicKey) {
+			return fmt.Errorf("signature/signer/remote: signer identity changed for role %v", pk.Role)
+		}
+	}
+	return nil
+}
+
+func (rf *remoteFactory) markUnhealthy(err error) {
+	if rf.healthy.Swap(false) {
+		rf.logger.Error("remote signer unreachable, suspending signing",
+			"err", err,
+		)
+	}
+}
+
+// watchdog periodically checks the health of the remote signer and suspends
+// signing while the signer is unreachable, so that the node stops submitting
+// signed messages instead of risking double signing after a signer failover.
+//
+// Signing is only resumed after the signer has passed the configured number of
+// consecutive health checks, each of which includes a fresh attestation of the
+// signer identity, if supported by the signer.
+func (rf *remoteFactory) watchdog(interval time.Duration, recoveryThreshold int, expected []PublicKey) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var successes int
+	for {
+		select {
+		case <-rf.reqCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(rf.reqCtx, interval)
+		err := rf.checkIdentity(ctx, expected)
+		cancel()
+
+		if err != nil {
+			successes = 0
+			rf.markUnhealthy(err)
+			continue
+		}
+		if rf.healthy.Load() {
+			continue
+		}
+
+		successes++
+		if successes < recoveryThreshold {
+			continue
+		}
+		rf.healthy.Store(true)
+		rf.logger.Info("remote signer reachable again, resuming signing")
+	}
+}
+
 type remoteSigner struct {
 	factory *remoteFactory
 
@@ -249,7 +498,7 @@ func (rs *remoteSigner) ContextSign(context signature.Context, message []byte) (
 	}
 
 	var rsp []byte
-	if err := rs.factory.conn.Invoke(rs.factory.reqCtx, methodSign.FullName(), req, &rsp); err != nil {
+	if err := rs.factory.invoke(methodSign.FullName(), req, &rsp); err != nil {
 		return nil, err
 	}
 
@@ -264,6 +513,24 @@ func (rs *remoteSigner) Reset() {
 	// Nothing to do.
 }
 
+type remoteVRFSigner struct {
+	*remoteSigner
+}
+
+func (rs *remoteVRFSigner) Prove(alphaString []byte) ([]byte, error) {
+	req := &ProveRequest{
+		Role:  rs.role,
+		Alpha: alphaString,
+	}
+
+	var rsp []byte
+	if err := rs.factory.invoke(methodProve.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
 // FactoryConfig is the remote factory configuration.
 type FactoryConfig struct {
 	// Address is the remote factory gRPC address.
@@ -272,6 +539,14 @@ type FactoryConfig struct {
 	ServerCertificate *tls.Certificate
 	// ClientCertificate is the client certificate.
 	ClientCertificate *tls.Certificate
+
+	// HealthCheckInterval is the interval between signer health checks.
+	// If zero, a default interval is used.
+	HealthCheckInterval time.Duration
+	// RecoveryThreshold is the number of consecutive successful health checks
+	// required to resume signing after the signer has been unreachable.
+	// If zero, a default threshold is used.
+	RecoveryThreshold int
 }
 
 // IsLocal returns true iff the configured endpoint is over AF_LOCAL.
@@ -318,12 +593,24 @@ func NewFactory(config any) (signature.SignerFactory, error) {
 		return nil, fmt.Errorf("signature/signer/remote: failed to dial server: %w", err)
 	}
 
-	return NewRemoteFactory(context.Background(), conn)
+	return NewRemoteFactoryWithConfig(context.Background(), conn, cfg)
 }
 
 // NewRemoteFactory creates a new gRPC remote signer client service given an
 // existing grpc connection.
 func NewRemoteFactory(ctx context.Context, conn *grpc.ClientConn) (signature.SignerFactory, error) {
+	return NewRemoteFactoryWithConfig(ctx, conn, &FactoryConfig{})
+}
+
+// NewRemoteFactoryWithConfig creates a new gRPC remote signer client service given an
+// existing grpc connection and the health check parameters from the given configuration.
+//
+// The identity of the signer is attested before the factory is returned, and is then
+// periodically re-attested by the signer health watchdog. Signers that don't support
+// attestations are only checked for changes of the advertised public keys.
+//
+// The returned factory implements io.Closer, and closing it stops the health watchdog.
+func NewRemoteFactoryWithConfig(ctx context.Context, conn *grpc.ClientConn, cfg *FactoryConfig) (signature.SignerFactory, error) {
 	// Enumerate the keys available, and cache them.
 	var rsp []PublicKey
 	if err := conn.Invoke(ctx, methodPublicKeys.FullName(), nil, &rsp); err != nil {
@@ -332,8 +619,8 @@ func NewRemoteFactory(ctx context.Context, conn *grpc.ClientConn) (signature.Sig
 
 	rf := &remoteFactory{
 		conn:    conn,
-		reqCtx:  ctx,
 		signers: make(map[signature.SignerRole]*remoteSigner),
+		logger:  logging.GetLogger("signature/signer/remote"),
 	}
 	for _, v := range rsp {
 		rf.signers[v.Role] = &remoteSigner{
@@ -343,5 +630,28 @@ func NewRemoteFactory(ctx context.Context, conn *grpc.ClientConn) (signature.Sig
 		}
 	}
 
+	// Ensure that the signer actually holds the advertised keys.
+	err := rf.attest(ctx, rsp)
+	switch {
+	case err == nil:
+		rf.attestation = true
+	case status.Code(err) == codes.Unimplemented:
+		rf.logger.Warn("remote signer does not support identity attestation, consider upgrading it")
+	default:
+		return nil, fmt.Errorf("signature/signer/remote: signer identity attestation failed: %w", err)
+	}
+	rf.healthy.Store(true)
+	rf.reqCtx, rf.cancel = context.WithCancel(ctx)
+
+	interval := cfg.HealthCheckInterval
+	if interval == 0 {
+		interval = defaultHealthCheckInterval
+	}
+	recoveryThreshold := cfg.RecoveryThreshold
+	if recoveryThreshold == 0 {
+		recoveryThreshold = defaultRecoveryThreshold
+	}
+	go rf.watchdog(interval, recoveryThreshold, rsp)
+
 	return rf, nil
 }
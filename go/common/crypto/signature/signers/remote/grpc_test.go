@@ -0,0 +1,153 @@
+package remote
+
+import (
+	"crypto/rand"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+)
+
+var testSignatureContext = signature.NewContext("oasis-core/remote-signer: test")
+
+func startTestServer(t *testing.T, sf signature.SignerFactory, address string) *grpc.Server {
+	listener, err := net.Listen("unix", address)
+	require.NoError(t, err, "net.Listen")
+
+	server := grpc.NewServer(grpc.ForceServerCodec(&cmnGrpc.CBORCodec{}))
+	RegisterService(server, sf)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return server
+}
+
+// startLegacyTestServer starts a signer server that doesn't support attestations.
+func startLegacyTestServer(t *testing.T, sf signature.SignerFactory, address string) *grpc.Server {
+	listener, err := net.Listen("unix", address)
+	require.NoError(t, err, "net.Listen")
+
+	desc := serviceDesc
+	desc.Methods = nil
+	for _, m := range serviceDesc.Methods {
+		if m.MethodName != methodAttest.ShortName() {
+			desc.Methods = append(desc.Methods, m)
+		}
+	}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(&cmnGrpc.CBORCodec{}))
+	w := &wrapper{
+		signers: make(map[signature.SignerRole]signature.Signer),
+	}
+	for _, v := range signature.SignerRoles {
+		if signer, err := sf.Load(v); err == nil {
+			w.signers[v] = signer
+		}
+	}
+	server.RegisterService(&desc, w)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return server
+}
+
+func TestRemoteSigner(t *testing.T) {
+	require := require.New(t)
+
+	signature.UnsafeAllowUnregisteredContexts()
+
+	dataDir := t.TempDir()
+	roles := []signature.SignerRole{signature.SignerNode, signature.SignerP2P, signature.SignerVRF}
+	sf, err := fileSigner.NewFactory(dataDir, roles...)
+	require.NoError(err, "fileSigner.NewFactory")
+	for _, role := range roles {
+		_, err = sf.Generate(role, rand.Reader)
+		require.NoError(err, "Generate(%v)", role)
+	}
+
+	address := filepath.Join(dataDir, "remote-signer.sock")
+	server := startTestServer(t, sf, address)
+
+	rf, err := NewFactory(&FactoryConfig{
+		Address:             "unix:" + address,
+		HealthCheckInterval: 50 * time.Millisecond,
+		RecoveryThreshold:   2,
+	})
+	require.NoError(err, "NewFactory")
+
+	// Signing.
+	msg := []byte("remote signer test message")
+	signer, err := rf.Load(signature.SignerNode)
+	require.NoError(err, "Load(SignerNode)")
+	sig, err := signer.ContextSign(testSignatureContext, msg)
+	require.NoError(err, "ContextSign")
+	require.True(signer.Public().Verify(testSignatureContext, msg, sig), "signature should verify")
+
+	// Attestation context must not be usable for regular signing.
+	_, err = signer.ContextSign(AttestationSignatureContext, msg)
+	require.Error(err, "ContextSign with the attestation context should fail")
+
+	// VRF proofs.
+	vrfSigner, err := rf.Load(signature.SignerVRF)
+	require.NoError(err, "Load(SignerVRF)")
+	_, ok := vrfSigner.(signature.VRFSigner)
+	require.True(ok, "VRF signer should support proofs")
+	_, err = signature.Prove(vrfSigner, []byte("alpha"))
+	require.NoError(err, "Prove")
+
+	// Unavailable roles.
+	_, err = rf.Load(signature.SignerEntity)
+	require.ErrorIs(err, signature.ErrNotExist, "Load(SignerEntity)")
+
+	// Signing must be suspended while the signer is unreachable.
+	server.Stop()
+	require.Eventually(func() bool {
+		_, err = signer.ContextSign(testSignatureContext, msg)
+		return err == ErrSignerUnavailable
+	}, 5*time.Second, 10*time.Millisecond, "signing should be suspended")
+
+	// And resumed once the signer is reachable again.
+	server = startTestServer(t, sf, address)
+	defer server.Stop()
+	require.Eventually(func() bool {
+		_, err = signer.ContextSign(testSignatureContext, msg)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond, "signing should be resumed")
+
+	// Closing the factory stops the watchdog and signing.
+	closer, ok := rf.(io.Closer)
+	require.True(ok, "factory should be closable")
+	require.NoError(closer.Close(), "Close")
+	_, err = signer.ContextSign(testSignatureContext, msg)
+	require.Error(err, "signing should fail once the factory is closed")
+	server.Stop()
+
+	// Signers without attestation support are still usable.
+	server = startLegacyTestServer(t, sf, address)
+	defer server.Stop()
+	rf, err = NewFactory(&FactoryConfig{
+		Address:             "unix:" + address,
+		HealthCheckInterval: 50 * time.Millisecond,
+		RecoveryThreshold:   2,
+	})
+	require.NoError(err, "NewFactory with a signer without attestation support")
+	defer rf.(io.Closer).Close()
+	signer, err = rf.Load(signature.SignerNode)
+	require.NoError(err, "Load(SignerNode)")
+	sig, err = signer.ContextSign(testSignatureContext, msg)
+	require.NoError(err, "ContextSign")
+	require.True(signer.Public().Verify(testSignatureContext, msg, sig), "signature should verify")
+
+	// Health checks must not suspend signing for signers without attestation support.
+	time.Sleep(200 * time.Millisecond)
+	_, err = signer.ContextSign(testSignatureContext, msg)
+	require.NoError(err, "ContextSign after health checks")
+}
@@ -0,0 +1,336 @@
+// Package diagnostics implements support diagnostics bundles that are assembled on demand in the
+// node's data directory.
+//
+// A bundle is a gzip-compressed tar archive containing the passed entries together with a
+// manifest. Entries whose names suggest key material are never included and all included entries
+// are redacted before being written, so that private keys, passwords, tokens and other secrets do
+// not end up in bundles that are shared with third parties.
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+)
+
+const (
+	// ModuleName is the module name used for diagnostics errors.
+	ModuleName = "common/diagnostics"
+
+	// DirName is the name of the diagnostics directory within the node's data directory.
+	DirName = "diagnostics"
+
+	// ManifestName is the name of the manifest entry in a bundle.
+	ManifestName = "manifest.json"
+
+	// DefaultMaxEntrySize is the default maximum size of a single bundle entry in bytes.
+	DefaultMaxEntrySize = 16 * 1024 * 1024
+	// DefaultMaxBundleSize is the default maximum uncompressed size of all bundle entries in bytes.
+	DefaultMaxBundleSize = 64 * 1024 * 1024
+	// DefaultMaxBundles is the default number of bundles retained in the diagnostics directory.
+	DefaultMaxBundles = 4
+
+	filePrefix = "diagnostics-"
+	fileSuffix = ".tar.gz"
+	timeFormat = "20060102T150405.000000000Z"
+)
+
+var (
+	// ErrNotFound is the error returned when the requested bundle does not exist.
+	ErrNotFound = errors.New(ModuleName, 1, "diagnostics: bundle not found")
+	// ErrInvalidEntry is the error returned when a bundle entry is malformed.
+	ErrInvalidEntry = errors.New(ModuleName, 2, "diagnostics: invalid entry")
+)
+
+// Config is the diagnostics bundle configuration.
+type Config struct {
+	// MaxEntrySize is the maximum size of a single entry in bytes. Larger entries are truncated.
+	MaxEntrySize uint64
+	// MaxBundleSize is the maximum uncompressed size of all entries in bytes. Entries that do not
+	// fit are truncated or omitted.
+	MaxBundleSize uint64
+	// MaxBundles is the number of bundles retained in the diagnostics directory, including the
+	// newly created one. Zero means that old bundles are never removed.
+	MaxBundles int
+}
+
+// DefaultConfig returns the default diagnostics bundle configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		MaxEntrySize:  DefaultMaxEntrySize,
+		MaxBundleSize: DefaultMaxBundleSize,
+		MaxBundles:    DefaultMaxBundles,
+	}
+}
+
+// Entry is a single bundle entry.
+type Entry struct {
+	// Name is the name of the entry within the bundle.
+	Name string
+	// Data is the entry content.
+	Data []byte
+	// Err is the error encountered while collecting the entry, if any. Failed entries are only
+	// recorded in the manifest.
+	Err error
+}
+
+// Info is the diagnostics bundle metadata.
+type Info struct {
+	// Name is the name of the bundle.
+	Name string `json:"name"`
+	// Path is the path of the bundle on the node.
+	Path string `json:"path"`
+	// Size is the size of the bundle in bytes.
+	Size int64 `json:"size"`
+	// Checksum is the hex-encoded SHA-256 checksum of the bundle.
+	Checksum string `json:"checksum"`
+}
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	// Time is the time when the bundle was created.
+	Time time.Time `json:"time"`
+	// Entries are the included entries.
+	Entries []*ManifestEntry `json:"entries"`
+	// Omitted are the names of entries that have been omitted together with the reason.
+	Omitted map[string]string `json:"omitted,omitempty"`
+	// Errors are the names of entries that could not be collected together with the error.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ManifestEntry describes an included entry.
+type ManifestEntry struct {
+	// Name is the name of the entry.
+	Name string `json:"name"`
+	// Size is the size of the included entry in bytes.
+	Size int `json:"size"`
+	// Truncated is true iff the entry has been truncated due to size limits.
+	Truncated bool `json:"truncated,omitempty"`
+	// Redactions is the number of redacted values.
+	Redactions int `json:"redactions,omitempty"`
+}
+
+// Create assembles a bundle with the given entries in the given directory and returns its
+// metadata.
+func Create(dir string, cfg *Config, entries []Entry) (*Info, error) {
+	if err := common.Mkdir(dir); err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to create directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	manifest, files, err := prepare(cfg, entries)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Time = now
+
+	name := filePrefix + now.Format(timeFormat) + fileSuffix
+	path := filepath.Join(dir, name)
+
+	f, err := os.CreateTemp(dir, ".tmp-"+filePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to create bundle: %w", err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	if err = f.Chmod(0o600); err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to set bundle permissions: %w", err)
+	}
+
+	h := sha256.New()
+	if err = write(io.MultiWriter(f, h), now, manifest, files); err != nil {
+		return nil, err
+	}
+	if err = f.Sync(); err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to sync bundle: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to stat bundle: %w", err)
+	}
+	if err = os.Rename(f.Name(), path); err != nil {
+		return nil, fmt.Errorf("diagnostics: failed to rename bundle: %w", err)
+	}
+
+	prune(dir, cfg.MaxBundles)
+
+	return &Info{
+		Name:     name,
+		Path:     path,
+		Size:     fi.Size(),
+		Checksum: checksum(h),
+	}, nil
+}
+
+// Get returns the content of the bundle with the given name.
+func Get(dir, name string) ([]byte, error) {
+	if !isValidName(name) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("diagnostics: failed to read bundle: %w", err)
+	}
+	return data, nil
+}
+
+// Checksum returns the hex-encoded SHA-256 checksum of the given bundle content.
+func Checksum(data []byte) string {
+	h := sha256.New()
+	_, _ = h.Write(data)
+	return checksum(h)
+}
+
+func checksum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func prepare(cfg *Config, entries []Entry) (*Manifest, []*Entry, error) {
+	manifest := Manifest{
+		Entries: []*ManifestEntry{},
+		Omitted: make(map[string]string),
+		Errors:  make(map[string]string),
+	}
+
+	var (
+		files []*Entry
+		total uint64
+	)
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		if !isValidEntryName(entry.Name) {
+			return nil, nil, fmt.Errorf("%w: malformed name '%s'", ErrInvalidEntry, entry.Name)
+		}
+		if _, ok := seen[entry.Name]; ok {
+			return nil, nil, fmt.Errorf("%w: duplicate name '%s'", ErrInvalidEntry, entry.Name)
+		}
+		seen[entry.Name] = struct{}{}
+
+		if entry.Err != nil {
+			manifest.Errors[entry.Name] = entry.Err.Error()
+			continue
+		}
+		if isExcluded(entry.Name) {
+			manifest.Omitted[entry.Name] = "may contain secrets"
+			continue
+		}
+
+		data, redactions := Redact(entry.Data)
+		me := &ManifestEntry{
+			Name:       entry.Name,
+			Redactions: redactions,
+		}
+		if limit := cfg.MaxEntrySize; uint64(len(data)) > limit {
+			data = data[:limit]
+			me.Truncated = true
+		}
+		if remaining := cfg.MaxBundleSize - total; uint64(len(data)) > remaining {
+			if remaining == 0 {
+				manifest.Omitted[entry.Name] = "bundle size limit exceeded"
+				continue
+			}
+			data = data[:remaining]
+			me.Truncated = true
+		}
+		total += uint64(len(data))
+		me.Size = len(data)
+
+		manifest.Entries = append(manifest.Entries, me)
+		files = append(files, &Entry{
+			Name: entry.Name,
+			Data: data,
+		})
+	}
+	return &manifest, files, nil
+}
+
+func write(w io.Writer, now time.Time, manifest *Manifest, files []*Entry) error {
+	rawManifest, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("diagnostics: failed to marshal manifest: %w", err)
+	}
+	files = append([]*Entry{{Name: ManifestName, Data: rawManifest}}, files...)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, file := range files {
+		hdr := tar.Header{
+			Name:    file.Name,
+			Mode:    0o600,
+			Size:    int64(len(file.Data)),
+			ModTime: now,
+		}
+		if err = tw.WriteHeader(&hdr); err != nil {
+			return fmt.Errorf("diagnostics: failed to write entry header: %w", err)
+		}
+		if _, err = tw.Write(file.Data); err != nil {
+			return fmt.Errorf("diagnostics: failed to write entry: %w", err)
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("diagnostics: failed to finalize archive: %w", err)
+	}
+	if err = gw.Close(); err != nil {
+		return fmt.Errorf("diagnostics: failed to finalize compression: %w", err)
+	}
+	return nil
+}
+
+// prune removes the oldest bundles so that at most the given number of bundles remain.
+func prune(dir string, keep int) {
+	if keep <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isValidName(entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	if len(names) <= keep {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+func isValidName(name string) bool {
+	return filepath.Base(name) == name &&
+		strings.HasPrefix(name, filePrefix) &&
+		strings.HasSuffix(name, fileSuffix)
+}
+
+func isValidEntryName(name string) bool {
+	return name != "" &&
+		name != ManifestName &&
+		filepath.Base(name) == name &&
+		name != "." && name != ".."
+}
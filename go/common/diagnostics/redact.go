@@ -0,0 +1,55 @@
+package diagnostics
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Redacted is the placeholder that replaces redacted values.
+const Redacted = "[REDACTED]"
+
+var (
+	// excludedPatterns are the entry name patterns of entries that are never included.
+	excludedPatterns = []string{"*.pem", "*.key", "*private*", "*secret*", "*mnemonic*"}
+
+	// pemPrivateKeyRe matches PEM-encoded private key blocks.
+	pemPrivateKeyRe = regexp.MustCompile(`(?s)-----BEGIN ([A-Z0-9 ]*PRIVATE KEY)-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+	// sensitiveValueRe matches key/value pairs with sensitive keys in JSON, YAML and logfmt
+	// encodings. Structured values (objects and lists) are left intact as their nested values
+	// are matched separately.
+	sensitiveValueRe = regexp.MustCompile(`(?i)("?[\w.-]*(?:secret|password|passphrase|private_?key|mnemonic|token|credential)[\w.-]*"?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|[^\s,{}\[\]"]+)`)
+	// urlCredentialsRe matches user credentials embedded in URLs.
+	urlCredentialsRe = regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`)
+)
+
+// Redact replaces private keys, secrets and credentials in the given data and returns the
+// redacted data together with the number of redacted values.
+func Redact(data []byte) ([]byte, int) {
+	var count int
+	data = pemPrivateKeyRe.ReplaceAllFunc(data, func([]byte) []byte {
+		count++
+		return []byte(Redacted)
+	})
+	data = sensitiveValueRe.ReplaceAllFunc(data, func(match []byte) []byte {
+		count++
+		sub := sensitiveValueRe.FindSubmatch(match)
+		return append(append([]byte{}, sub[1]...), `"`+Redacted+`"`...)
+	})
+	data = urlCredentialsRe.ReplaceAllFunc(data, func(match []byte) []byte {
+		count++
+		return []byte("://" + Redacted + "@")
+	})
+	return data, count
+}
+
+// isExcluded returns true iff an entry with the given name must never be included in a bundle.
+func isExcluded(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range excludedPatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+// Package fault provides a framework for deterministic failure injection used to test how the
+// network and operators cope with a misbehaving node. The package provides a global singleton
+// that can only be configured when the node runs in debug mode. Otherwise, all injection points
+// are no-ops.
+package fault
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+)
+
+// ModuleName is the module name used for fault injection errors.
+const ModuleName = "common/fault"
+
+var (
+	// ErrDisabled is the error returned when fault injection is attempted outside debug mode.
+	ErrDisabled = errors.New(ModuleName, 1, "fault: fault injection is only available in debug mode")
+	// ErrInvalidArgument is the error returned when a fault is configured with an invalid value.
+	ErrInvalidArgument = errors.New(ModuleName, 2, "fault: invalid argument")
+	// ErrInjected is the error returned by operations that fail due to an injected fault.
+	ErrInjected = errors.New(ModuleName, 3, "fault: injected failure")
+)
+
+var testForceEnable bool
+
+var (
+	logger = logging.GetLogger("common/fault")
+
+	global struct {
+		sync.Mutex
+
+		faults Faults
+
+		// committeeMessages is the number of committee messages seen since the drop percentage
+		// was last configured.
+		committeeMessages uint64
+	}
+)
+
+// Faults is the set of injected faults.
+type Faults struct {
+	// RegistrationStopped is true iff the node stopped submitting registrations.
+	RegistrationStopped bool `json:"registration_stopped,omitempty"`
+
+	// ExecutorCommitDelay is the delay applied before submitting executor commitments.
+	ExecutorCommitDelay time.Duration `json:"executor_commit_delay,omitempty"`
+
+	// CommitteeMessageDropPercent is the percentage of received p2p committee messages that are
+	// dropped.
+	CommitteeMessageDropPercent uint8 `json:"committee_message_drop_percent,omitempty"`
+
+	// CheckpointServingPaused is true iff the node stopped serving storage checkpoints to peers.
+	CheckpointServingPaused bool `json:"checkpoint_serving_paused,omitempty"`
+}
+
+// IsActive returns true iff any of the faults is active.
+func (f *Faults) IsActive() bool {
+	return f.RegistrationStopped ||
+		f.ExecutorCommitDelay > 0 ||
+		f.CommitteeMessageDropPercent > 0 ||
+		f.CheckpointServingPaused
+}
+
+func enabled() bool {
+	return cmdFlags.DebugDontBlameOasis() || testForceEnable
+}
+
+func update(fn func(f *Faults)) error {
+	if !enabled() {
+		return ErrDisabled
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	fn(&global.faults)
+
+	logger.Warn("injected faults updated",
+		"faults", global.faults,
+	)
+	return nil
+}
+
+// SetRegistrationStopped configures whether the node stops submitting registrations.
+func SetRegistrationStopped(stopped bool) error {
+	return update(func(f *Faults) {
+		f.RegistrationStopped = stopped
+	})
+}
+
+// SetExecutorCommitDelay configures the delay applied before submitting executor commitments.
+// A zero delay disables the fault.
+func SetExecutorCommitDelay(delay time.Duration) error {
+	if delay < 0 {
+		return ErrInvalidArgument
+	}
+	return update(func(f *Faults) {
+		f.ExecutorCommitDelay = delay
+	})
+}
+
+// SetCommitteeMessageDropPercent configures the percentage of received p2p committee messages
+// that are dropped. A zero percentage disables the fault.
+//
+// Messages are dropped deterministically, evenly spread over the received messages.
+func SetCommitteeMessageDropPercent(percent uint8) error {
+	if percent > 100 {
+		return ErrInvalidArgument
+	}
+	return update(func(f *Faults) {
+		f.CommitteeMessageDropPercent = percent
+		global.committeeMessages = 0
+	})
+}
+
+// SetCheckpointServingPaused configures whether the node stops serving storage checkpoints.
+func SetCheckpointServingPaused(paused bool) error {
+	return update(func(f *Faults) {
+		f.CheckpointServingPaused = paused
+	})
+}
+
+// Active returns the currently active faults or nil if there are none.
+func Active() *Faults {
+	if !enabled() {
+		return nil
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	if !global.faults.IsActive() {
+		return nil
+	}
+	f := global.faults
+	return &f
+}
+
+// RegistrationStopped returns true iff the node should not submit registrations.
+func RegistrationStopped() bool {
+	if !enabled() {
+		return false
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	return global.faults.RegistrationStopped
+}
+
+// ExecutorCommitDelay returns the delay that should be applied before submitting executor
+// commitments.
+func ExecutorCommitDelay() time.Duration {
+	if !enabled() {
+		return 0
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	return global.faults.ExecutorCommitDelay
+}
+
+// DropCommitteeMessage returns true iff a received p2p committee message should be dropped.
+func DropCommitteeMessage() bool {
+	if !enabled() {
+		return false
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	percent := uint64(global.faults.CommitteeMessageDropPercent)
+	if percent == 0 {
+		return false
+	}
+
+	// Drop a message whenever the number of messages that should have been dropped so far
+	// increases, which spreads the drops evenly.
+	n := global.committeeMessages
+	global.committeeMessages++
+	return (n+1)*percent/100 > n*percent/100
+}
+
+// CheckpointServingPaused returns true iff the node should not serve storage checkpoints.
+func CheckpointServingPaused() bool {
+	if !enabled() {
+		return false
+	}
+
+	global.Lock()
+	defer global.Unlock()
+
+	return global.faults.CheckpointServingPaused
+}
@@ -0,0 +1,80 @@
+package fault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultsDisabled(t *testing.T) {
+	require := require.New(t)
+
+	require.ErrorIs(SetRegistrationStopped(true), ErrDisabled)
+	require.ErrorIs(SetExecutorCommitDelay(time.Second), ErrDisabled)
+	require.ErrorIs(SetCommitteeMessageDropPercent(50), ErrDisabled)
+	require.ErrorIs(SetCheckpointServingPaused(true), ErrDisabled)
+
+	require.Nil(Active())
+	require.False(RegistrationStopped())
+	require.Zero(ExecutorCommitDelay())
+	require.False(DropCommitteeMessage())
+	require.False(CheckpointServingPaused())
+}
+
+func TestFaults(t *testing.T) {
+	require := require.New(t)
+
+	testForceEnable = true
+	defer func() {
+		testForceEnable = false
+		global.faults = Faults{}
+	}()
+
+	require.Nil(Active(), "no faults should be active by default")
+
+	require.NoError(SetRegistrationStopped(true))
+	require.True(RegistrationStopped())
+	require.NoError(SetExecutorCommitDelay(5 * time.Second))
+	require.Equal(5*time.Second, ExecutorCommitDelay())
+	require.NoError(SetCheckpointServingPaused(true))
+	require.True(CheckpointServingPaused())
+
+	require.ErrorIs(SetExecutorCommitDelay(-time.Second), ErrInvalidArgument)
+	require.ErrorIs(SetCommitteeMessageDropPercent(101), ErrInvalidArgument)
+
+	// Dropped messages are spread evenly.
+	for _, percent := range []uint8{0, 1, 25, 50, 100} {
+		require.NoError(SetCommitteeMessageDropPercent(percent))
+
+		var dropped int
+		for range 200 {
+			if DropCommitteeMessage() {
+				dropped++
+			}
+		}
+		require.Equal(2*int(percent), dropped, "percent %d", percent)
+	}
+	require.NoError(SetCommitteeMessageDropPercent(50))
+	require.Equal([]bool{false, true, false, true}, []bool{
+		DropCommitteeMessage(),
+		DropCommitteeMessage(),
+		DropCommitteeMessage(),
+		DropCommitteeMessage(),
+	})
+
+	require.Equal(&Faults{
+		RegistrationStopped:         true,
+		ExecutorCommitDelay:         5 * time.Second,
+		CommitteeMessageDropPercent: 50,
+		CheckpointServingPaused:     true,
+	}, Active())
+
+	// Faults can be toggled individually.
+	require.NoError(SetRegistrationStopped(false))
+	require.NoError(SetExecutorCommitDelay(0))
+	require.NoError(SetCommitteeMessageDropPercent(0))
+	require.Equal(&Faults{CheckpointServingPaused: true}, Active())
+	require.NoError(SetCheckpointServingPaused(false))
+	require.Nil(Active())
+}
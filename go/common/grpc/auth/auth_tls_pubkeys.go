@@ -58,6 +58,21 @@ func (auth *PeerPubkeyAuthenticator) AllowPeerPublicKey(key signature.PublicKey)
 	auth.whitelist[key] = true
 }
 
+// SetAllowedPeerPublicKeys replaces the set of peer public keys that are allowed access.
+//
+// This can be used to revoke access of keys that are no longer valid, e.g., a node's previous TLS
+// key after its TLS key rotation overlap window has passed.
+func (auth *PeerPubkeyAuthenticator) SetAllowedPeerPublicKeys(keys []signature.PublicKey) {
+	whitelist := make(map[signature.PublicKey]bool, len(keys))
+	for _, key := range keys {
+		whitelist[key] = true
+	}
+
+	auth.Lock()
+	defer auth.Unlock()
+	auth.whitelist = whitelist
+}
+
 // NewPeerPubkeyAuthenticator creates a new (empty) PeerPubkeyAuthenticator.
 func NewPeerPubkeyAuthenticator() *PeerPubkeyAuthenticator {
 	return &PeerPubkeyAuthenticator{
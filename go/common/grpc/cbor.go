@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 )
@@ -10,13 +11,22 @@ import (
 const cborCodecName = "cbor"
 
 // CBORCodec implements gRPC's encoding.Codec interface.
+//
+// Protocol buffer messages (e.g., of the standard gRPC health service) are encoded using
+// protocol buffers so that standard tooling can talk to such services.
 type CBORCodec struct{}
 
 func (c *CBORCodec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
 	return cbor.Marshal(v), nil
 }
 
 func (c *CBORCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
 	return cbor.UnmarshalRPC(data, v)
 }
 
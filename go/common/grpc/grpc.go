@@ -67,6 +67,13 @@ var (
 		},
 		[]string{"call"},
 	)
+	grpcServerAccessDenied = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_grpc_server_access_denied",
+			Help: "Number of gRPC calls rejected by the access policy.",
+		},
+		[]string{"call"},
+	)
 	grpcClientCalls = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "oasis_grpc_client_calls",
@@ -93,9 +100,11 @@ var (
 		grpcClientCalls,
 		grpcClientLatency,
 		grpcClientStreamWrites,
+		grpcClientRetries,
 		grpcServerCalls,
 		grpcServerLatency,
 		grpcServerStreamWrites,
+		grpcServerAccessDenied,
 	}
 
 	serverKeepAliveParams = keepalive.ServerParameters{
@@ -438,6 +447,8 @@ type ServerConfig struct { // nolint: maligned
 	InstallWrapper bool
 	// AuthFunc is the authentication function for access control.
 	AuthFunc auth.AuthenticationFunction
+	// AccessPolicy is the optional per-method access policy.
+	AccessPolicy *AccessPolicy
 	// ClientCommonName is the expected common name on client TLS certificates. If not specified,
 	// the default identity.CommonName will be used.
 	ClientCommonName string
@@ -628,6 +639,10 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		serverStreamErrorMapper,
 		auth.StreamServerInterceptor(config.AuthFunc),
 	}
+	if config.AccessPolicy != nil {
+		unaryInterceptors = append(unaryInterceptors, config.AccessPolicy.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, config.AccessPolicy.streamInterceptor)
+	}
 	if config.InstallWrapper {
 		wrapper = newWrapper()
 		unaryInterceptors = append(unaryInterceptors, wrapper.unaryInterceptor)
@@ -652,7 +667,8 @@ func NewServer(config *ServerConfig) (*Server, error) {
 				})
 			},
 			GetCertificate: func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
-				return config.Identity.TLSCertificate, nil
+				// Always use the current certificate as it can change on TLS key rotation.
+				return config.Identity.GetTLSCertificate(), nil
 			},
 		}
 
@@ -672,6 +688,9 @@ func NewServer(config *ServerConfig) (*Server, error) {
 }
 
 // Dial creates a client connection to the given target.
+//
+// Calls of idempotent methods are retried according to DefaultRetryPolicy, which can be changed
+// for the connection via WithRetryPolicy or for a single call via WithCallRetryPolicy.
 func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	// If debug gRPC logs are enabled, setup the global gRPC logger.
 	if viper.GetBool(CfgLogDebug) {
@@ -691,14 +710,15 @@ func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 
 	logger := logging.GetLogger("grpc/client")
 	logAdapter := newGrpcLogAdapter(logger)
+	retry := newRetryInterceptor(opts)
 	dialOpts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(
 			grpc.ForceCodec(&CBORCodec{}),
 			grpc.MaxCallSendMsgSize(maxSendMsgSize),
 			grpc.MaxCallRecvMsgSize(maxRecvMsgSize),
 		),
-		grpc.WithChainUnaryInterceptor(logAdapter.unaryClientLogger, clientUnaryErrorMapper),
-		grpc.WithChainStreamInterceptor(logAdapter.streamClientLogger, clientStreamErrorMapper),
+		grpc.WithChainUnaryInterceptor(logAdapter.unaryClientLogger, clientUnaryErrorMapper, retry.unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(logAdapter.streamClientLogger, clientStreamErrorMapper, retry.streamClientInterceptor),
 	}
 	dialOpts = append(dialOpts, opts...)
 	return grpc.NewClient(target, dialOpts...)
@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// errAccessDenied is the error returned when the access policy rejects a call. It intentionally
+// does not include any details about the reason for the rejection.
+var errAccessDenied = status.Error(codes.PermissionDenied, "permission denied")
+
+// MethodPolicy is the access policy of a gRPC method.
+type MethodPolicy string
+
+const (
+	// MethodPolicyAllowlist allows only callers presenting a TLS client certificate whose public
+	// key is on the allowlist.
+	MethodPolicyAllowlist MethodPolicy = "allowlist"
+	// MethodPolicyRegistered allows callers presenting a TLS client certificate whose public key
+	// is either on the allowlist or registered on-chain.
+	MethodPolicyRegistered MethodPolicy = "registered"
+)
+
+// Validate validates the method policy.
+func (p MethodPolicy) Validate() error {
+	switch p {
+	case MethodPolicyAllowlist, MethodPolicyRegistered:
+		return nil
+	default:
+		return fmt.Errorf("unknown method policy: %s", p)
+	}
+}
+
+// RegisteredKeyFunc returns true iff the given public key is registered on-chain.
+type RegisteredKeyFunc func(ctx context.Context, key signature.PublicKey) (bool, error)
+
+// AccessPolicy is a per-method access policy for gRPC servers.
+//
+// Methods without a policy are open to all callers.
+type AccessPolicy struct {
+	sync.RWMutex
+
+	methods    map[string]MethodPolicy
+	allowlist  map[signature.PublicKey]bool
+	registered RegisteredKeyFunc
+
+	logger *logging.Logger
+}
+
+// SetAllowlist replaces the set of public keys that are allowed to call restricted methods.
+func (p *AccessPolicy) SetAllowlist(keys []signature.PublicKey) {
+	allowlist := make(map[signature.PublicKey]bool, len(keys))
+	for _, key := range keys {
+		allowlist[key] = true
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.allowlist = allowlist
+}
+
+// Allowlist returns the set of public keys that are allowed to call restricted methods.
+func (p *AccessPolicy) Allowlist() []signature.PublicKey {
+	p.RLock()
+	defer p.RUnlock()
+
+	keys := make([]signature.PublicKey, 0, len(p.allowlist))
+	for key := range p.allowlist {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// authorize checks whether the caller is allowed to call the given method.
+func (p *AccessPolicy) authorize(ctx context.Context, method string) error {
+	policy, ok := p.methods[method]
+	if !ok {
+		return nil
+	}
+
+	key, ok := peerPublicKey(ctx)
+	if !ok {
+		return p.deny(method, "missing client certificate")
+	}
+
+	p.RLock()
+	allowed := p.allowlist[key]
+	p.RUnlock()
+	if allowed {
+		return nil
+	}
+
+	if policy == MethodPolicyRegistered && p.registered != nil {
+		registered, err := p.registered(ctx, key)
+		if err != nil {
+			p.logger.Warn("failed to look up registered key",
+				"err", err,
+				"key", key,
+			)
+		}
+		if registered {
+			return nil
+		}
+	}
+
+	return p.deny(method, "unknown key", "key", key)
+}
+
+func (p *AccessPolicy) deny(method string, reason string, keyvals ...any) error {
+	p.logger.Debug("access denied",
+		append([]any{"method", method, "reason", reason}, keyvals...)...,
+	)
+	grpcServerAccessDenied.With(prometheus.Labels{"call": method}).Inc()
+
+	return errAccessDenied
+}
+
+func (p *AccessPolicy) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := p.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (p *AccessPolicy) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := p.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// peerPublicKey returns the public key of the TLS client certificate presented by the peer.
+func peerPublicKey(ctx context.Context) (signature.PublicKey, bool) {
+	var key signature.PublicKey
+
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return key, false
+	}
+	tlsAuth, ok := peer.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsAuth.State.PeerCertificates) != 1 {
+		return key, false
+	}
+	pk, ok := tlsAuth.State.PeerCertificates[0].PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return key, false
+	}
+	if err := key.UnmarshalBinary(pk); err != nil {
+		return key, false
+	}
+	return key, true
+}
+
+// NewAccessPolicy creates a new access policy with the given per-method policies keyed by full
+// method names, the initial allowlist and an optional function for looking up registered keys.
+func NewAccessPolicy(methods map[string]MethodPolicy, allowlist []signature.PublicKey, registered RegisteredKeyFunc) (*AccessPolicy, error) {
+	for method, policy := range methods {
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("method %s: %w", method, err)
+		}
+	}
+
+	p := &AccessPolicy{
+		methods:    methods,
+		registered: registered,
+		logger:     logging.GetLogger("grpc/policy"),
+	}
+	p.SetAllowlist(allowlist)
+
+	return p, nil
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	cmnTLS "github.com/oasisprotocol/oasis-core/go/common/crypto/tls"
+)
+
+func TestAccessPolicy(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		methodOpen       = "/test/Open"
+		methodAllowlist  = "/test/Allowlist"
+		methodRegistered = "/test/Registered"
+	)
+
+	newPeerContext := func() (context.Context, signature.PublicKey) {
+		cert, err := cmnTLS.Generate("test")
+		require.NoError(err, "Generate")
+		x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(err, "ParseCertificate")
+
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			}},
+		})
+		pk, ok := peerPublicKey(ctx)
+		require.True(ok, "peerPublicKey")
+
+		return ctx, pk
+	}
+	ctxAllowed, pkAllowed := newPeerContext()
+	ctxRegistered, pkRegistered := newPeerContext()
+	ctxUnknown, _ := newPeerContext()
+	ctxAnonymous := context.Background()
+
+	registered := func(_ context.Context, key signature.PublicKey) (bool, error) {
+		return key.Equal(pkRegistered), nil
+	}
+
+	_, err := NewAccessPolicy(map[string]MethodPolicy{methodOpen: "invalid"}, nil, registered)
+	require.Error(err, "NewAccessPolicy should fail with an invalid method policy")
+
+	p, err := NewAccessPolicy(map[string]MethodPolicy{
+		methodAllowlist:  MethodPolicyAllowlist,
+		methodRegistered: MethodPolicyRegistered,
+	}, []signature.PublicKey{pkAllowed}, registered)
+	require.NoError(err, "NewAccessPolicy")
+
+	for _, tc := range []struct {
+		ctx     context.Context
+		method  string
+		allowed bool
+	}{
+		{ctxAnonymous, methodOpen, true},
+		{ctxUnknown, methodOpen, true},
+
+		{ctxAnonymous, methodAllowlist, false},
+		{ctxUnknown, methodAllowlist, false},
+		{ctxRegistered, methodAllowlist, false},
+		{ctxAllowed, methodAllowlist, true},
+
+		{ctxAnonymous, methodRegistered, false},
+		{ctxUnknown, methodRegistered, false},
+		{ctxRegistered, methodRegistered, true},
+		{ctxAllowed, methodRegistered, true},
+	} {
+		denied := testutil.ToFloat64(grpcServerAccessDenied.WithLabelValues(tc.method))
+
+		err = p.authorize(tc.ctx, tc.method)
+		switch tc.allowed {
+		case true:
+			require.NoError(err, "authorize %s", tc.method)
+			require.Equal(denied, testutil.ToFloat64(grpcServerAccessDenied.WithLabelValues(tc.method)))
+		case false:
+			require.Error(err, "authorize %s", tc.method)
+			require.Equal(codes.PermissionDenied, status.Code(err))
+			require.Equal("permission denied", status.Convert(err).Message(), "rejections should not include details")
+			require.Equal(denied+1, testutil.ToFloat64(grpcServerAccessDenied.WithLabelValues(tc.method)))
+		}
+	}
+
+	// Registered key lookup failures should be treated as rejections.
+	p.registered = func(context.Context, signature.PublicKey) (bool, error) {
+		return false, fmt.Errorf("registry unavailable")
+	}
+	err = p.authorize(ctxRegistered, methodRegistered)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,255 @@
+package grpc
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// backoffJitter is the maximum relative amount by which backoff delays are randomized.
+const backoffJitter = 0.2
+
+var grpcClientRetries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "oasis_grpc_client_retries",
+		Help: "Number of retried gRPC calls.",
+	},
+	[]string{"call"},
+)
+
+// DefaultRetryPolicy is the retry policy installed by Dial unless overridden.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       4,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        2 * time.Second,
+	BackoffMultiplier: 2,
+}
+
+// RetryPolicy is a client-side retry policy.
+//
+// Only methods marked as idempotent (see MethodDesc.WithIdempotent) are retried and only in case
+// the attempt failed with a transient error (the server was unavailable or an attempt timed out
+// while the call deadline has not yet passed). For streaming methods, only establishing the
+// stream is retried as none of the streaming methods support resumption.
+type RetryPolicy struct {
+	// MaxAttempts is the overall attempt budget, including the first attempt. A value of one or
+	// less disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound on the delay between retries.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is the factor by which the delay grows after each retry.
+	BackoffMultiplier float64
+
+	// PerAttemptTimeout is the optional timeout of each attempt. The call deadline always takes
+	// precedence, so each attempt ends at the earlier of the two.
+	PerAttemptTimeout time.Duration
+}
+
+// Enabled returns true iff the policy allows retries.
+func (p *RetryPolicy) Enabled() bool {
+	return p != nil && p.MaxAttempts > 1
+}
+
+// backoff returns the delay before the given retry (starting with one).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry && (p.MaxBackoff == 0 || delay < float64(p.MaxBackoff)); i++ {
+		delay *= p.BackoffMultiplier
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	delay *= 1 + backoffJitter*(2*rand.Float64()-1) // #nosec G404
+	return time.Duration(delay)
+}
+
+type retryDialOption struct {
+	grpc.EmptyDialOption
+
+	policy *RetryPolicy
+}
+
+// WithRetryPolicy returns a dial option that configures the retry policy used by all calls on
+// the connection. Passing nil disables retries.
+func WithRetryPolicy(policy *RetryPolicy) grpc.DialOption {
+	return retryDialOption{policy: policy}
+}
+
+type retryCallOption struct {
+	grpc.EmptyCallOption
+
+	policy *RetryPolicy
+}
+
+// WithCallRetryPolicy returns a call option that overrides the retry policy of the connection for
+// a single call. Passing nil disables retries for the call.
+func WithCallRetryPolicy(policy *RetryPolicy) grpc.CallOption {
+	return retryCallOption{policy: policy}
+}
+
+// WithoutRetry returns a call option that disables retries for a single call.
+func WithoutRetry() grpc.CallOption {
+	return WithCallRetryPolicy(nil)
+}
+
+type retryInterceptor struct {
+	logger *logging.Logger
+	policy *RetryPolicy
+}
+
+func newRetryInterceptor(opts []grpc.DialOption) *retryInterceptor {
+	policy := &DefaultRetryPolicy
+	for _, opt := range opts {
+		if o, ok := opt.(retryDialOption); ok {
+			policy = o.policy
+		}
+	}
+
+	return &retryInterceptor{
+		logger: logging.GetLogger("grpc/client/retry"),
+		policy: policy,
+	}
+}
+
+// policyFor returns the retry policy for the given call or nil in case the call should not be
+// retried.
+func (ri *retryInterceptor) policyFor(method string, opts []grpc.CallOption) *RetryPolicy {
+	policy := ri.policy
+	for _, opt := range opts {
+		if o, ok := opt.(retryCallOption); ok {
+			policy = o.policy
+		}
+	}
+	if !policy.Enabled() {
+		return nil
+	}
+
+	md, err := GetRegisteredMethod(method)
+	if err != nil || !md.IsIdempotent() {
+		return nil
+	}
+	return policy
+}
+
+// attempt runs a single attempt, applying the per-attempt timeout, and returns whether the call
+// should be retried.
+func (ri *retryInterceptor) attempt(
+	ctx context.Context,
+	policy *RetryPolicy,
+	fn func(ctx context.Context) error,
+) (bool, error) {
+	attemptCtx := ctx
+	if policy.PerAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, policy.PerAttemptTimeout)
+		defer cancel()
+	}
+
+	err := fn(attemptCtx)
+	if err == nil || ctx.Err() != nil {
+		return false, err
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true, err
+	case codes.DeadlineExceeded:
+		// Only the attempt timed out, the call deadline has not yet passed.
+		return policy.PerAttemptTimeout > 0, err
+	default:
+		return false, err
+	}
+}
+
+// retry invokes fn until it succeeds, fails with a non-transient error or the attempt budget or
+// the call deadline is exhausted.
+func (ri *retryInterceptor) retry(
+	ctx context.Context,
+	method string,
+	policy *RetryPolicy,
+	fn func(ctx context.Context) error,
+) error {
+	for attempt := 1; ; attempt++ {
+		retryable, err := ri.attempt(ctx, policy, fn)
+		if !retryable || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		// Do not wait in case the call deadline would pass before the next attempt.
+		delay := policy.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return err
+		}
+
+		ri.logger.Debug("retrying call",
+			"method", method,
+			"attempt", attempt,
+			"backoff", delay,
+			"err", err,
+		)
+		grpcClientRetries.With(prometheus.Labels{"call": method}).Inc()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (ri *retryInterceptor) unaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, rsp any,
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	policy := ri.policyFor(method, opts)
+	if policy == nil {
+		return invoker(ctx, method, req, rsp, cc, opts...)
+	}
+
+	return ri.retry(ctx, method, policy, func(ctx context.Context) error {
+		return invoker(ctx, method, req, rsp, cc, opts...)
+	})
+}
+
+func (ri *retryInterceptor) streamClientInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	policy := ri.policyFor(method, opts)
+	if policy == nil {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	// The per-attempt timeout would also bound the lifetime of the established stream, so it is
+	// not applied to streams.
+	streamPolicy := *policy
+	streamPolicy.PerAttemptTimeout = 0
+
+	var cs grpc.ClientStream
+	err := ri.retry(ctx, method, &streamPolicy, func(ctx context.Context) error {
+		var err error
+		cs, err = streamer(ctx, desc, cc, method, opts...)
+		return err
+	})
+	return cs, err
+}
@@ -0,0 +1,273 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	retryTestServiceName = NewServiceName("RetryTest")
+
+	retryTestMethodIdempotent    = retryTestServiceName.NewMethod("Idempotent", int64(0)).WithIdempotent()
+	retryTestMethodNotIdempotent = retryTestServiceName.NewMethod("NotIdempotent", int64(0))
+	retryTestMethodWatch         = retryTestServiceName.NewMethod("Watch", int64(0)).WithIdempotent()
+
+	retryTestServiceDesc = grpc.ServiceDesc{
+		ServiceName: string(retryTestServiceName),
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: retryTestMethodIdempotent.ShortName(),
+				Handler:    retryTestHandler,
+			},
+			{
+				MethodName: retryTestMethodNotIdempotent.ShortName(),
+				Handler:    retryTestHandler,
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    retryTestMethodWatch.ShortName(),
+				Handler:       retryTestWatchHandler,
+				ServerStreams: true,
+			},
+		},
+	}
+)
+
+// flakyServer fails the first few calls with the configured error.
+type flakyServer struct {
+	calls    atomic.Int64
+	failures int64
+	code     codes.Code
+	delay    time.Duration
+}
+
+func (s *flakyServer) call(ctx context.Context) error {
+	n := s.calls.Add(1)
+	if n > s.failures {
+		return nil
+	}
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+		}
+	}
+	return status.Error(s.code, "flaky server failure")
+}
+
+func retryTestHandler(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+	var req int64
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if err := srv.(*flakyServer).call(ctx); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func retryTestWatchHandler(_ any, stream grpc.ServerStream) error {
+	var req int64
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	return stream.SendMsg(req)
+}
+
+func startRetryTestServer(t *testing.T, srv *flakyServer, addr string) string {
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err, "Listen")
+
+	server := grpc.NewServer(grpc.ForceServerCodec(&CBORCodec{}))
+	server.RegisterService(&retryTestServiceDesc, srv)
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(server.Stop)
+
+	return ln.Addr().String()
+}
+
+func dialRetryTest(t *testing.T, addr string, policy *RetryPolicy) *grpc.ClientConn {
+	conn, err := Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		WithRetryPolicy(policy),
+	)
+	require.NoError(t, err, "Dial")
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	require := require.New(t)
+
+	policy := RetryPolicy{
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 2,
+	}
+	for _, tc := range []struct {
+		retry    int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	} {
+		delay := policy.backoff(tc.retry)
+		require.InDelta(float64(tc.expected), float64(delay), backoffJitter*float64(tc.expected), "retry %d", tc.retry)
+	}
+
+	require.False((*RetryPolicy)(nil).Enabled())
+	require.False((&RetryPolicy{MaxAttempts: 1}).Enabled())
+	require.True(DefaultRetryPolicy.Enabled())
+}
+
+func TestRetryUnary(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:       4,
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        50 * time.Millisecond,
+		BackoffMultiplier: 2,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		method   *MethodDesc
+		failures int64
+		code     codes.Code
+		opts     []grpc.CallOption
+		calls    int64
+		err      codes.Code
+	}{
+		{"Transient failures", retryTestMethodIdempotent, 2, codes.Unavailable, nil, 3, codes.OK},
+		{"Attempt budget exhausted", retryTestMethodIdempotent, 10, codes.Unavailable, nil, 4, codes.Unavailable},
+		{"Non-transient failure", retryTestMethodIdempotent, 10, codes.InvalidArgument, nil, 1, codes.InvalidArgument},
+		{"Not idempotent", retryTestMethodNotIdempotent, 2, codes.Unavailable, nil, 1, codes.Unavailable},
+		{"Call without retry", retryTestMethodIdempotent, 2, codes.Unavailable, []grpc.CallOption{WithoutRetry()}, 1, codes.Unavailable},
+		{"Call policy override", retryTestMethodIdempotent, 10, codes.Unavailable, []grpc.CallOption{
+			WithCallRetryPolicy(&RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+		}, 2, codes.Unavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			srv := &flakyServer{failures: tc.failures, code: tc.code}
+			conn := dialRetryTest(t, startRetryTestServer(t, srv, "127.0.0.1:0"), policy)
+
+			retries := testutil.ToFloat64(grpcClientRetries.WithLabelValues(tc.method.FullName()))
+
+			var rsp int64
+			err := conn.Invoke(context.Background(), tc.method.FullName(), int64(42), &rsp, tc.opts...)
+			require.Equal(tc.err, status.Code(err), "call should fail with the expected code")
+			if err == nil {
+				require.EqualValues(42, rsp)
+			}
+			require.Equal(tc.calls, srv.calls.Load(), "server should receive the expected number of attempts")
+			require.Equal(float64(tc.calls-1), testutil.ToFloat64(grpcClientRetries.WithLabelValues(tc.method.FullName()))-retries,
+				"retries should be reported",
+			)
+		})
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	t.Run("Call deadline", func(t *testing.T) {
+		require := require.New(t)
+
+		srv := &flakyServer{failures: 10, code: codes.Unavailable}
+		conn := dialRetryTest(t, startRetryTestServer(t, srv, "127.0.0.1:0"), &RetryPolicy{
+			MaxAttempts:       10,
+			InitialBackoff:    time.Second,
+			BackoffMultiplier: 2,
+		})
+
+		// The call should not wait for a retry that would happen after its deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		var rsp int64
+		err := conn.Invoke(ctx, retryTestMethodIdempotent.FullName(), int64(42), &rsp)
+		require.Equal(codes.Unavailable, status.Code(err))
+		require.Less(time.Since(start), 500*time.Millisecond, "call should fail before its deadline")
+		require.EqualValues(1, srv.calls.Load())
+	})
+
+	t.Run("Per-attempt timeout", func(t *testing.T) {
+		require := require.New(t)
+
+		srv := &flakyServer{failures: 1, code: codes.Unavailable, delay: 5 * time.Second}
+		conn := dialRetryTest(t, startRetryTestServer(t, srv, "127.0.0.1:0"), &RetryPolicy{
+			MaxAttempts:       2,
+			InitialBackoff:    10 * time.Millisecond,
+			BackoffMultiplier: 2,
+			PerAttemptTimeout: 200 * time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		var rsp int64
+		err := conn.Invoke(ctx, retryTestMethodIdempotent.FullName(), int64(42), &rsp)
+		require.NoError(err, "timed out attempt should be retried")
+		require.EqualValues(2, srv.calls.Load())
+	})
+}
+
+func TestRetryServerRestart(t *testing.T) {
+	require := require.New(t)
+
+	// Reserve an address and start the server only after the client made its first attempt.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	addr := ln.Addr().String()
+	require.NoError(ln.Close(), "Close")
+
+	conn := dialRetryTest(t, addr, &RetryPolicy{
+		MaxAttempts:       20,
+		InitialBackoff:    50 * time.Millisecond,
+		MaxBackoff:        100 * time.Millisecond,
+		BackoffMultiplier: 2,
+	})
+
+	server := grpc.NewServer(grpc.ForceServerCodec(&CBORCodec{}))
+	server.RegisterService(&retryTestServiceDesc, &flakyServer{})
+	defer server.Stop()
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if ln, err := net.Listen("tcp", addr); err == nil {
+			_ = server.Serve(ln)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Establishing streams is retried.
+	stream, err := conn.NewStream(ctx, &retryTestServiceDesc.Streams[0], retryTestMethodWatch.FullName())
+	require.NoError(err, "NewStream")
+	require.NoError(stream.SendMsg(int64(42)), "SendMsg")
+	require.NoError(stream.CloseSend(), "CloseSend")
+	var rsp int64
+	require.NoError(stream.RecvMsg(&rsp), "RecvMsg")
+	require.EqualValues(42, rsp)
+
+	// Unary calls work on the established connection.
+	err = conn.Invoke(ctx, retryTestMethodIdempotent.FullName(), int64(43), &rsp)
+	require.NoError(err, "Invoke")
+	require.EqualValues(43, rsp)
+}
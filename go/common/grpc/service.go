@@ -90,11 +90,18 @@ func (m *MethodDesc) WithAccessControl(f AccessControlFunc) *MethodDesc {
 	return m
 }
 
+// WithIdempotent marks the endpoint as idempotent, which makes it safe for clients to retry.
+func (m *MethodDesc) WithIdempotent() *MethodDesc {
+	m.idempotent = true
+	return m
+}
+
 // MethodDesc is a gRPC method descriptor.
 type MethodDesc struct {
 	short       string
 	full        string
 	requestType any
+	idempotent  bool
 
 	accessControl      AccessControlFunc
 	namespaceExtractor NamespaceExtractorFunc
@@ -118,6 +125,11 @@ func (m *MethodDesc) IsAccessControlled(req any) (bool, error) {
 	return m.accessControl(req)
 }
 
+// IsIdempotent returns true iff the method is idempotent.
+func (m *MethodDesc) IsIdempotent() bool {
+	return m.idempotent
+}
+
 // UnmarshalRawMessage unmarshals `cbor.RawMessage` request.
 func (m *MethodDesc) UnmarshalRawMessage(req *cbor.RawMessage) (any, error) {
 	v := reflect.New(reflect.TypeOf(m.requestType)).Interface()
@@ -0,0 +1,189 @@
+// Package heartbeat implements liveness tracking of long-running service loops.
+//
+// Service loops register a heartbeat and beat it at least every Interval while they are
+// responsive. A heartbeat whose last beat is older than its staleness threshold indicates
+// that the corresponding loop is stuck.
+package heartbeat
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Interval is the interval at which service loops should beat their heartbeats.
+	Interval = 5 * time.Second
+
+	// DefaultThreshold is the default heartbeat staleness threshold.
+	DefaultThreshold = time.Minute
+)
+
+// Heartbeat is a heartbeat of a single service loop.
+type Heartbeat struct {
+	name    string
+	last    atomic.Int64
+	monitor *Monitor
+}
+
+// Name returns the name of the service loop.
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+// Beat records that the service loop is responsive.
+func (h *Heartbeat) Beat() {
+	h.last.Store(h.monitor.now().UnixNano())
+}
+
+// Stop stops tracking the heartbeat. It should be called when the service loop terminates.
+func (h *Heartbeat) Stop() {
+	h.monitor.unregister(h)
+}
+
+func (h *Heartbeat) lastBeat() time.Time {
+	return time.Unix(0, h.last.Load())
+}
+
+// Status is the status of a heartbeat.
+type Status struct {
+	// Name is the name of the service loop.
+	Name string `json:"name"`
+	// LastBeat is the time of the last beat.
+	LastBeat time.Time `json:"last_beat"`
+	// Threshold is the staleness threshold of the heartbeat.
+	Threshold time.Duration `json:"threshold"`
+	// Stale is true iff the last beat is older than the staleness threshold.
+	Stale bool `json:"stale"`
+}
+
+// Monitor tracks the heartbeats of service loops.
+type Monitor struct {
+	sync.RWMutex
+
+	heartbeats       map[string]*Heartbeat
+	defaultThreshold time.Duration
+	thresholds       map[string]time.Duration
+
+	now func() time.Time
+}
+
+// SetThresholds configures the heartbeat staleness thresholds.
+//
+// Per-service thresholds are keyed by a service name prefix which matches whole components of
+// slash-separated heartbeat names (e.g., "worker" matches "worker/common/<runtime-id>"). The
+// longest matching prefix applies. Heartbeats without a matching prefix use the default
+// threshold.
+func (m *Monitor) SetThresholds(defaultThreshold time.Duration, thresholds map[string]time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.defaultThreshold = defaultThreshold
+	m.thresholds = make(map[string]time.Duration, len(thresholds))
+	for prefix, threshold := range thresholds {
+		m.thresholds[prefix] = threshold
+	}
+}
+
+// Register registers a new heartbeat of the named service loop. The heartbeat is considered
+// to have beaten upon registration.
+//
+// Registering a heartbeat under the name of an existing heartbeat replaces the latter.
+func (m *Monitor) Register(name string) *Heartbeat {
+	h := &Heartbeat{
+		name:    name,
+		monitor: m,
+	}
+	h.Beat()
+
+	m.Lock()
+	defer m.Unlock()
+	m.heartbeats[name] = h
+
+	return h
+}
+
+func (m *Monitor) unregister(h *Heartbeat) {
+	m.Lock()
+	defer m.Unlock()
+
+	// Do not remove a heartbeat which replaced the given one.
+	if m.heartbeats[h.name] == h {
+		delete(m.heartbeats, h.name)
+	}
+}
+
+// Status returns the status of all tracked heartbeats, sorted by name.
+func (m *Monitor) Status() []Status {
+	now := m.now()
+
+	m.RLock()
+	defer m.RUnlock()
+
+	statuses := make([]Status, 0, len(m.heartbeats))
+	for name, h := range m.heartbeats {
+		last := h.lastBeat()
+		threshold := m.thresholdLocked(name)
+		statuses = append(statuses, Status{
+			Name:      name,
+			LastBeat:  last,
+			Threshold: threshold,
+			Stale:     now.Sub(last) > threshold,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+// IsLive returns true iff none of the tracked heartbeats are stale.
+func (m *Monitor) IsLive() bool {
+	for _, status := range m.Status() {
+		if status.Stale {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Monitor) thresholdLocked(name string) time.Duration {
+	threshold := m.defaultThreshold
+	matched := -1
+	for prefix, t := range m.thresholds {
+		if len(prefix) <= matched {
+			continue
+		}
+		if name != prefix && !strings.HasPrefix(name, prefix+"/") {
+			continue
+		}
+		threshold = t
+		matched = len(prefix)
+	}
+	return threshold
+}
+
+// NewMonitor creates a new heartbeat monitor.
+func NewMonitor() *Monitor {
+	return &Monitor{
+		heartbeats:       make(map[string]*Heartbeat),
+		defaultThreshold: DefaultThreshold,
+		thresholds:       make(map[string]time.Duration),
+		now:              time.Now,
+	}
+}
+
+var defaultMonitor = NewMonitor()
+
+// DefaultMonitor returns the process-wide heartbeat monitor.
+func DefaultMonitor() *Monitor {
+	return defaultMonitor
+}
+
+// Register registers a new heartbeat of the named service loop with the process-wide monitor.
+func Register(name string) *Heartbeat {
+	return defaultMonitor.Register(name)
+}
@@ -0,0 +1,63 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_000_000, 0)
+	m := NewMonitor()
+	m.now = func() time.Time { return now }
+	m.SetThresholds(time.Minute, map[string]time.Duration{
+		"worker":        10 * time.Second,
+		"worker/common": 20 * time.Second,
+	})
+
+	require.True(m.IsLive(), "monitor without heartbeats should be live")
+
+	consensus := m.Register("consensus/services/registry")
+	executor := m.Register("worker/executor/rt")
+	common := m.Register("worker/common/rt")
+	_ = m.Register("workers")
+
+	status := m.Status()
+	require.Len(status, 4)
+	require.Equal("consensus/services/registry", status[0].Name)
+	require.Equal(time.Minute, status[0].Threshold, "default threshold should apply")
+	require.Equal(20*time.Second, status[1].Threshold, "longest prefix should apply")
+	require.Equal(10*time.Second, status[2].Threshold, "prefix should apply")
+	require.Equal(time.Minute, status[3].Threshold, "prefixes should match whole components")
+	require.True(m.IsLive())
+
+	// A stuck worker must flip liveness once its threshold is exceeded.
+	now = now.Add(15 * time.Second)
+	consensus.Beat()
+	common.Beat()
+	require.False(m.IsLive(), "stale heartbeat should flip liveness")
+	for _, s := range m.Status() {
+		require.Equal(s.Name == executor.Name(), s.Stale, "stale %s", s.Name)
+	}
+
+	// Recovered loops restore liveness.
+	executor.Beat()
+	require.True(m.IsLive())
+
+	// Stopped heartbeats are no longer tracked.
+	now = now.Add(2 * time.Minute)
+	for _, s := range m.Status() {
+		m.heartbeats[s.Name].Stop()
+	}
+	require.Empty(m.Status())
+	require.True(m.IsLive())
+
+	// Stopping a replaced heartbeat must not remove its replacement.
+	old := m.Register("p2p")
+	_ = m.Register("p2p")
+	old.Stop()
+	require.Len(m.Status(), 1)
+}
@@ -5,9 +5,11 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
@@ -34,6 +36,10 @@ const (
 	tlsKeyFilename  = "tls_identity.pem"
 	tlsCertFilename = "tls_identity_cert.pem"
 
+	// These are used for the TLS key generated during a pending TLS key rotation.
+	tlsNextKeyFilename  = "tls_identity_next.pem"
+	tlsNextCertFilename = "tls_identity_next_cert.pem"
+
 	tlsEphemeralKeyBaseFilename = "tls_ephemeral"
 
 	tlsEphemeralGenCurrent     = ""
@@ -45,6 +51,9 @@ const (
 	tlsSentryClientCertFilename = "sentry_client_tls_identity_cert.pem"
 )
 
+// ErrNoTLSRotation is the error returned when there is no pending TLS key rotation.
+var ErrNoTLSRotation = errors.New("identity: no pending TLS key rotation")
+
 // RequiredSignerRoles is the required signer roles needed to load or
 // provision a node identity.
 var RequiredSignerRoles = []signature.SignerRole{
@@ -70,9 +79,116 @@ type Identity struct {
 	TLSSentryClientCertificate *tls.Certificate
 
 	// TLSSigner is a node TLS certificate signer.
+	//
+	// As the TLS key can be rotated while the node is running, GetTLSSigner should be used instead
+	// once the identity is in use.
 	TLSSigner signature.Signer
 	// TLSCertificate is a certificate that can be used for TLS.
+	//
+	// As the TLS key can be rotated while the node is running, GetTLSCertificate should be used
+	// instead once the identity is in use.
 	TLSCertificate *tls.Certificate
+
+	tlsLock sync.RWMutex
+
+	// nextTLSSigner is the TLS certificate signer generated for a pending TLS key rotation.
+	nextTLSSigner signature.Signer
+	// nextTLSCertificate is the TLS certificate generated for a pending TLS key rotation.
+	nextTLSCertificate *tls.Certificate
+
+	// dataDir is the directory the TLS keys are persisted in (if any).
+	dataDir string
+}
+
+// GetTLSSigner returns the current TLS certificate signer.
+func (i *Identity) GetTLSSigner() signature.Signer {
+	i.tlsLock.RLock()
+	defer i.tlsLock.RUnlock()
+
+	return i.TLSSigner
+}
+
+// GetTLSCertificate returns the current TLS certificate.
+func (i *Identity) GetTLSCertificate() *tls.Certificate {
+	i.tlsLock.RLock()
+	defer i.tlsLock.RUnlock()
+
+	return i.TLSCertificate
+}
+
+// GetNextTLSSigner returns the TLS certificate signer the node will switch to once the pending
+// TLS key rotation completes, or nil if there is no pending rotation.
+func (i *Identity) GetNextTLSSigner() signature.Signer {
+	i.tlsLock.RLock()
+	defer i.tlsLock.RUnlock()
+
+	return i.nextTLSSigner
+}
+
+// GetNextTLSCertificate returns the TLS certificate the node will switch to once the pending TLS
+// key rotation completes, or nil if there is no pending rotation.
+func (i *Identity) GetNextTLSCertificate() *tls.Certificate {
+	i.tlsLock.RLock()
+	defer i.tlsLock.RUnlock()
+
+	return i.nextTLSCertificate
+}
+
+// BeginTLSRotation starts a TLS key rotation by generating the next TLS key and returns its public
+// key. The current TLS key remains in use until CompleteTLSRotation is called.
+//
+// In case a rotation is already pending, the already generated next TLS key is returned.
+func (i *Identity) BeginTLSRotation() (signature.PublicKey, error) {
+	i.tlsLock.Lock()
+	defer i.tlsLock.Unlock()
+
+	if i.nextTLSSigner != nil {
+		return i.nextTLSSigner.Public(), nil
+	}
+
+	cert, err := tlsCert.Generate(CommonName)
+	if err != nil {
+		return signature.PublicKey{}, err
+	}
+	if i.dataDir != "" {
+		tlsNextCertPath, tlsNextKeyPath := tlsNextCertPaths(i.dataDir)
+		if err = tlsCert.Save(tlsNextCertPath, tlsNextKeyPath, cert); err != nil {
+			return signature.PublicKey{}, err
+		}
+	}
+
+	i.nextTLSSigner = memory.NewFromRuntime(cert.PrivateKey.(ed25519.PrivateKey))
+	i.nextTLSCertificate = cert
+
+	return i.nextTLSSigner.Public(), nil
+}
+
+// CompleteTLSRotation completes a pending TLS key rotation by switching to the next TLS key.
+func (i *Identity) CompleteTLSRotation() error {
+	i.tlsLock.Lock()
+	defer i.tlsLock.Unlock()
+
+	if i.nextTLSSigner == nil {
+		return ErrNoTLSRotation
+	}
+
+	if i.dataDir != "" {
+		tlsCertPath, tlsKeyPath := TLSCertPaths(i.dataDir)
+		if err := tlsCert.Save(tlsCertPath, tlsKeyPath, i.nextTLSCertificate); err != nil {
+			return err
+		}
+		tlsNextCertPath, tlsNextKeyPath := tlsNextCertPaths(i.dataDir)
+		for _, path := range []string{tlsNextCertPath, tlsNextKeyPath} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("identity: failed to remove next TLS key: %w", err)
+			}
+		}
+	}
+
+	i.TLSSigner, i.TLSCertificate = i.nextTLSSigner, i.nextTLSCertificate
+	i.nextTLSSigner, i.nextTLSCertificate = nil, nil
+
+	return nil
 }
 
 // WithTLSCertificate creates a new identity with the specified TLS certificate,
@@ -160,6 +276,26 @@ func doLoadOrGenerate(dataDir string, signerFactory signature.SignerFactory, sho
 		return nil, err
 	}
 
+	// Load and re-generate the TLS certificate of a pending TLS key rotation
+	// (if it exists).
+	var (
+		nextTLSSigner signature.Signer
+		nextCert      *tls.Certificate
+	)
+	tlsNextCertPath, tlsNextKeyPath := tlsNextCertPaths(dataDir)
+	nextCert, err = tlsCert.LoadFromKey(tlsNextKeyPath, CommonName)
+	switch {
+	case err == nil:
+		if err = tlsCert.Save(tlsNextCertPath, tlsNextKeyPath, nextCert); err != nil {
+			return nil, err
+		}
+		nextTLSSigner = memory.NewFromRuntime(nextCert.PrivateKey.(ed25519.PrivateKey))
+	case os.IsNotExist(err):
+		nextCert = nil
+	default:
+		return nil, fmt.Errorf("identity: unable to read next TLS key from file: %w", err)
+	}
+
 	// Load and re-generate the sentry client TLS certificate for this node (if
 	// it exists).
 	// NOTE: This will reuse the sentry client's private key (if it exists)
@@ -193,6 +329,9 @@ func doLoadOrGenerate(dataDir string, signerFactory signature.SignerFactory, sho
 		TLSSigner:                  memory.NewFromRuntime(cert.PrivateKey.(ed25519.PrivateKey)),
 		TLSCertificate:             cert,
 		TLSSentryClientCertificate: sentryClientCert,
+		nextTLSSigner:              nextTLSSigner,
+		nextTLSCertificate:         nextCert,
+		dataDir:                    dataDir,
 	}, nil
 }
 
@@ -221,3 +360,12 @@ func TLSSentryClientCertPaths(dataDir string) (string, string) {
 
 	return tlsCertPath, tlsKeyPath
 }
+
+func tlsNextCertPaths(dataDir string) (string, string) {
+	var (
+		tlsKeyPath  = filepath.Join(dataDir, tlsNextKeyFilename)
+		tlsCertPath = filepath.Join(dataDir, tlsNextCertFilename)
+	)
+
+	return tlsCertPath, tlsKeyPath
+}
@@ -81,3 +81,53 @@ func TestLoadOrGenerate(t *testing.T) {
 	require.NotEqual(t, identity3.TLSSentryClientCertificate, identity4.TLSSentryClientCertificate)
 	require.EqualValues(t, identity4.TLSSentryClientCertificate.PrivateKey, identity4.TLSSentryClientCertificate.PrivateKey)
 }
+
+func TestTLSRotation(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := os.MkdirTemp("", "oasis-identity-test_")
+	require.NoError(err, "create data dir")
+	defer os.RemoveAll(dataDir)
+
+	factory, err := fileSigner.NewFactory(dataDir, RequiredSignerRoles...)
+	require.NoError(err, "NewFactory")
+
+	identity, err := LoadOrGenerate(dataDir, factory)
+	require.NoError(err, "LoadOrGenerate")
+	currentPubKey := identity.GetTLSSigner().Public()
+	require.Nil(identity.GetNextTLSSigner(), "there should be no pending rotation")
+	require.ErrorIs(identity.CompleteTLSRotation(), ErrNoTLSRotation)
+
+	// Begin a rotation.
+	nextPubKey, err := identity.BeginTLSRotation()
+	require.NoError(err, "BeginTLSRotation")
+	require.NotEqual(currentPubKey, nextPubKey, "next TLS key should differ")
+	require.Equal(currentPubKey, identity.GetTLSSigner().Public(), "current TLS key should still be used")
+	require.Equal(nextPubKey, identity.GetNextTLSSigner().Public())
+	require.NotNil(identity.GetNextTLSCertificate())
+
+	// Beginning a rotation again should return the same key.
+	nextPubKey2, err := identity.BeginTLSRotation()
+	require.NoError(err, "BeginTLSRotation (2)")
+	require.Equal(nextPubKey, nextPubKey2)
+
+	// The pending rotation should survive a restart.
+	identity2, err := Load(dataDir, factory)
+	require.NoError(err, "Load")
+	require.Equal(currentPubKey, identity2.GetTLSSigner().Public())
+	require.NotNil(identity2.GetNextTLSSigner(), "pending rotation should be loaded")
+	require.Equal(nextPubKey, identity2.GetNextTLSSigner().Public())
+
+	// Complete the rotation.
+	err = identity2.CompleteTLSRotation()
+	require.NoError(err, "CompleteTLSRotation")
+	require.Equal(nextPubKey, identity2.GetTLSSigner().Public())
+	require.Equal(identity2.GetTLSCertificate(), identity2.TLSCertificate)
+	require.Nil(identity2.GetNextTLSSigner(), "there should be no pending rotation")
+
+	// The rotated key should be used after a restart.
+	identity3, err := Load(dataDir, factory)
+	require.NoError(err, "Load (2)")
+	require.Equal(nextPubKey, identity3.GetTLSSigner().Public())
+	require.Nil(identity3.GetNextTLSSigner(), "there should be no pending rotation")
+}
@@ -0,0 +1,287 @@
+// Package journal implements a persistent journal of significant node lifecycle events.
+//
+// The journal is a bounded ring of events kept in the common node store so that it survives
+// restarts and log rotation. It is meant to aid post-incident analysis and only records events
+// that are rare and relevant to the operator (e.g., registrations, committee elections, upgrades
+// and restarts after a crash).
+package journal
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+)
+
+const (
+	// ModuleName is the module name used for event journal errors.
+	ModuleName = "common/journal"
+
+	// storeName is the name of the persistent store namespace used for storing the journal.
+	storeName = "common/journal"
+)
+
+var (
+	// ErrDisabled is the error returned when the event journal is not enabled.
+	ErrDisabled = errors.New(ModuleName, 1, "journal: event journal is disabled")
+
+	// eventKeyFmt is the key format used for journal events.
+	//
+	// Key format is: 0x01 <index (uint64)>
+	// Value is the CBOR-serialized event.
+	eventKeyFmt = keyformat.New(0x01, uint64(0))
+	// stateKeyFmt is the key format used for the journal state.
+	//
+	// Value is the CBOR-serialized journal state.
+	stateKeyFmt = keyformat.New(0x02)
+
+	logger = logging.GetLogger("common/journal")
+
+	global struct {
+		sync.Mutex
+
+		j *journal
+	}
+)
+
+// Kind is the kind of a journal event.
+type Kind string
+
+const (
+	// KindRegistrationSubmitted is the kind of events recorded when a node registration
+	// transaction is submitted.
+	KindRegistrationSubmitted Kind = "registration_submitted"
+	// KindRegistrationAccepted is the kind of events recorded when a node registration
+	// transaction is accepted by the registry.
+	KindRegistrationAccepted Kind = "registration_accepted"
+	// KindCommitteeElected is the kind of events recorded when the node is elected into a
+	// runtime committee.
+	KindCommitteeElected Kind = "committee_elected"
+	// KindRuntimeSuspended is the kind of events recorded when a runtime is suspended.
+	KindRuntimeSuspended Kind = "runtime_suspended"
+	// KindAttestationRenewed is the kind of events recorded when a runtime TEE attestation
+	// is renewed.
+	KindAttestationRenewed Kind = "attestation_renewed"
+	// KindUpgradeApplied is the kind of events recorded when an upgrade stage is applied.
+	KindUpgradeApplied Kind = "upgrade_applied"
+	// KindCrashRestart is the kind of events recorded when the node starts after it did not
+	// shut down cleanly.
+	KindCrashRestart Kind = "crash_restart"
+	// KindConsensusSync is the kind of events recorded on consensus sync state transitions.
+	KindConsensusSync Kind = "consensus_sync"
+)
+
+// Event is a journal event.
+type Event struct {
+	// Index is the monotonically increasing index of the event in the journal.
+	Index uint64 `json:"index"`
+
+	// Kind is the kind of the event.
+	Kind Kind `json:"kind"`
+
+	// Height is the consensus height at which the event occurred. Zero if not known.
+	Height int64 `json:"height,omitempty"`
+
+	// Time is the local time at which the event was recorded.
+	Time time.Time `json:"time"`
+
+	// RuntimeID is the identifier of the runtime the event relates to, if any.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+
+	// Message is a human readable description of the event.
+	Message string `json:"message"`
+}
+
+// state is the persisted journal state.
+type state struct {
+	// FirstIndex is the index of the oldest retained event.
+	FirstIndex uint64 `json:"first_index"`
+	// NextIndex is the index of the next event.
+	NextIndex uint64 `json:"next_index"`
+
+	// Running is true while the node is running and is cleared on clean shutdown.
+	Running bool `json:"running"`
+}
+
+type journal struct {
+	mu sync.RWMutex
+
+	store     *persistent.ServiceStore
+	maxEvents uint64
+	state     state
+}
+
+func (j *journal) record(ev *Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ev.Index = j.state.NextIndex
+	ev.Time = time.Now()
+
+	st := j.state
+	st.NextIndex++
+	if err := j.store.PutManyCBOR(
+		[][]byte{eventKeyFmt.Encode(ev.Index), stateKeyFmt.Encode()},
+		[]any{ev, &st},
+	); err != nil {
+		return fmt.Errorf("failed to persist event: %w", err)
+	}
+	j.state = st
+
+	if err := j.pruneLocked(); err != nil {
+		logger.Warn("failed to prune event journal",
+			"err", err,
+		)
+	}
+	return nil
+}
+
+func (j *journal) pruneLocked() error {
+	if j.state.NextIndex-j.state.FirstIndex <= j.maxEvents {
+		return nil
+	}
+
+	st := j.state
+	st.FirstIndex = st.NextIndex - j.maxEvents
+
+	keys := make([][]byte, 0, st.FirstIndex-j.state.FirstIndex)
+	for idx := j.state.FirstIndex; idx < st.FirstIndex; idx++ {
+		keys = append(keys, eventKeyFmt.Encode(idx))
+	}
+	// Update the state first so that a failure to delete events can only leave unreachable
+	// events behind.
+	if err := j.store.PutCBOR(stateKeyFmt.Encode(), &st); err != nil {
+		return err
+	}
+	j.state = st
+
+	return j.store.DeleteMany(keys)
+}
+
+func (j *journal) setRunning(running bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	st := j.state
+	st.Running = running
+	if err := j.store.PutCBOR(stateKeyFmt.Encode(), &st); err != nil {
+		return err
+	}
+	j.state = st
+	return nil
+}
+
+func (j *journal) events(fromIndex uint64, limit uint64) ([]*Event, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	fromIndex = max(fromIndex, j.state.FirstIndex)
+	toIndex := j.state.NextIndex
+	if limit > 0 && fromIndex < toIndex && toIndex-fromIndex > limit {
+		toIndex = fromIndex + limit
+	}
+
+	events := make([]*Event, 0)
+	for idx := fromIndex; idx < toIndex; idx++ {
+		var ev Event
+		if err := j.store.GetCBOR(eventKeyFmt.Encode(idx), &ev); err != nil {
+			return nil, fmt.Errorf("failed to load event %d: %w", idx, err)
+		}
+		events = append(events, &ev)
+	}
+	return events, nil
+}
+
+// Init initializes the global event journal, backed by the given common node store and retaining
+// at most the given number of events.
+//
+// In case the node did not shut down cleanly since the journal was last initialized, a crash
+// restart event is recorded.
+func Init(store *persistent.CommonStore, maxEvents uint64) error {
+	if maxEvents == 0 {
+		return nil
+	}
+
+	j := &journal{
+		store:     store.GetServiceStore(storeName),
+		maxEvents: maxEvents,
+	}
+	switch err := j.store.GetCBOR(stateKeyFmt.Encode(), &j.state); err {
+	case nil, persistent.ErrNotFound:
+	default:
+		return fmt.Errorf("journal: failed to load state: %w", err)
+	}
+
+	if j.state.Running {
+		if err := j.record(&Event{
+			Kind:    KindCrashRestart,
+			Message: "node did not shut down cleanly",
+		}); err != nil {
+			return fmt.Errorf("journal: %w", err)
+		}
+	}
+	if err := j.setRunning(true); err != nil {
+		return fmt.Errorf("journal: failed to persist state: %w", err)
+	}
+
+	global.Lock()
+	defer global.Unlock()
+	global.j = j
+
+	return nil
+}
+
+// Close marks the node as cleanly shut down and disables the global event journal.
+func Close() {
+	global.Lock()
+	defer global.Unlock()
+
+	if global.j == nil {
+		return
+	}
+	if err := global.j.setRunning(false); err != nil {
+		logger.Error("failed to persist clean shutdown",
+			"err", err,
+		)
+	}
+	global.j = nil
+}
+
+// Record records the given event in the global event journal. The index and time of the event
+// are assigned by the journal.
+//
+// If the journal is not enabled, this method does nothing. Failures to persist the event are
+// logged and otherwise ignored as the journal is best-effort.
+func Record(ev *Event) {
+	global.Lock()
+	j := global.j
+	global.Unlock()
+
+	if j == nil {
+		return
+	}
+	if err := j.record(ev); err != nil {
+		logger.Error("failed to record event",
+			"err", err,
+			"kind", ev.Kind,
+		)
+	}
+}
+
+// Events returns the retained events of the global event journal, starting at the given index
+// and returning at most limit events. A zero limit means no limit.
+func Events(fromIndex uint64, limit uint64) ([]*Event, error) {
+	global.Lock()
+	j := global.j
+	global.Unlock()
+
+	if j == nil {
+		return nil, ErrDisabled
+	}
+	return j.events(fromIndex, limit)
+}
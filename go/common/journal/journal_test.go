@@ -0,0 +1,78 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+)
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+
+	store, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	// Journal is disabled before initialization.
+	Record(&Event{Kind: KindConsensusSync, Message: "ignored"})
+	_, err = Events(0, 0)
+	require.ErrorIs(err, ErrDisabled, "Events should fail when the journal is disabled")
+
+	err = Init(store, 3)
+	require.NoError(err, "Init")
+	defer Close()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("journal test runtime"), 0)
+	for i := range 5 {
+		Record(&Event{
+			Kind:      KindRegistrationAccepted,
+			Height:    int64(10 + i),
+			RuntimeID: &runtimeID,
+			Message:   "node registered",
+		})
+	}
+
+	// Only the most recent events are retained.
+	events, err := Events(0, 0)
+	require.NoError(err, "Events")
+	require.Len(events, 3)
+	for i, ev := range events {
+		require.EqualValues(2+i, ev.Index)
+		require.EqualValues(12+i, ev.Height)
+		require.Equal(KindRegistrationAccepted, ev.Kind)
+		require.Equal(&runtimeID, ev.RuntimeID)
+		require.False(ev.Time.IsZero(), "event time should be set")
+	}
+
+	// Pagination.
+	events, err = Events(3, 1)
+	require.NoError(err, "Events")
+	require.Len(events, 1)
+	require.EqualValues(3, events[0].Index)
+
+	events, err = Events(5, 0)
+	require.NoError(err, "Events")
+	require.Empty(events)
+
+	// Clean shutdown does not result in a crash restart event.
+	Close()
+	err = Init(store, 3)
+	require.NoError(err, "Init")
+	events, err = Events(0, 0)
+	require.NoError(err, "Events")
+	require.Len(events, 3)
+	require.EqualValues(4, events[2].Index)
+
+	// Simulate a crash by not closing the journal.
+	global.j = nil
+	err = Init(store, 3)
+	require.NoError(err, "Init")
+	events, err = Events(0, 0)
+	require.NoError(err, "Events")
+	require.Len(events, 3)
+	require.EqualValues(5, events[2].Index)
+	require.Equal(KindCrashRestart, events[2].Kind)
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -18,11 +19,11 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// DefaultModule is the module name that refers to the default log level.
+const DefaultModule = "*"
+
 var (
-	backend = logBackend{
-		baseLogger:   log.NewNopLogger(),
-		defaultLevel: LevelError,
-	}
+	backend = newLogBackend()
 
 	_ pflag.Value = (*Level)(nil)
 	_ pflag.Value = (*Format)(nil)
@@ -83,21 +84,6 @@ const (
 	LevelError
 )
 
-func (l Level) toOption() level.Option {
-	switch l {
-	case LevelDebug:
-		return level.AllowDebug()
-	case LevelInfo:
-		return level.AllowInfo()
-	case LevelWarn:
-		return level.AllowWarn()
-	case LevelError:
-		return level.AllowError()
-	default:
-		panic("logging: unsupported log level")
-	}
-}
-
 // String returns the string representation of a Level.
 func (l *Level) String() string {
 	switch *l {
@@ -140,13 +126,30 @@ func (l *Level) Type() string {
 // Logger is a logger instance.
 type Logger struct {
 	logger log.Logger
-	level  Level
 	module string
+
+	// level is the cached effective log level of the logger, tagged with
+	// the generation of the level registry it was derived from.
+	level atomic.Uint64
+}
+
+// getLevel returns the effective log level of the logger, re-evaluating
+// it in case the level registry has been updated since the last call.
+func (l *Logger) getLevel() Level {
+	levels := backend.levels.Load()
+	cached := l.level.Load()
+	if cached>>32 == levels.generation {
+		return Level(uint32(cached))
+	}
+
+	lvl := levels.levelFor(l.module)
+	l.level.Store(levels.generation<<32 | uint64(lvl))
+	return lvl
 }
 
 // Debug logs the message and key value pairs at the Debug log level.
 func (l *Logger) Debug(msg string, keyvals ...any) {
-	if l.level > LevelDebug {
+	if l.getLevel() > LevelDebug {
 		return
 	}
 	keyvals = append([]any{"msg", msg}, keyvals...)
@@ -155,7 +158,7 @@ func (l *Logger) Debug(msg string, keyvals ...any) {
 
 // Info logs the message and key value pairs at the Info log level.
 func (l *Logger) Info(msg string, keyvals ...any) {
-	if l.level > LevelInfo {
+	if l.getLevel() > LevelInfo {
 		return
 	}
 	keyvals = append([]any{"msg", msg}, keyvals...)
@@ -164,7 +167,7 @@ func (l *Logger) Info(msg string, keyvals ...any) {
 
 // Warn logs the message and key value pairs at the Warn log level.
 func (l *Logger) Warn(msg string, keyvals ...any) {
-	if l.level > LevelWarn {
+	if l.getLevel() > LevelWarn {
 		return
 	}
 	keyvals = append([]any{"msg", msg}, keyvals...)
@@ -173,7 +176,7 @@ func (l *Logger) Warn(msg string, keyvals ...any) {
 
 // Error logs the message and key value pairs at the Error log level.
 func (l *Logger) Error(msg string, keyvals ...any) {
-	if l.level > LevelError {
+	if l.getLevel() > LevelError {
 		return
 	}
 	keyvals = append([]any{"msg", msg}, keyvals...)
@@ -185,7 +188,7 @@ func (l *Logger) Error(msg string, keyvals ...any) {
 func (l *Logger) With(keyvals ...any) *Logger {
 	return &Logger{
 		logger: log.With(l.logger, keyvals...),
-		level:  l.level,
+		module: l.module,
 	}
 }
 
@@ -198,7 +201,70 @@ func NewNopLogger() *Logger {
 
 // GetLevel returns the current global log level.
 func GetLevel() Level {
-	return backend.defaultLevel
+	return backend.levels.Load().defaultLevel
+}
+
+// GetLevels returns the current default log level and all of the per-module
+// log level overrides.
+func GetLevels() (Level, map[string]Level) {
+	levels := backend.levels.Load()
+
+	moduleLevels := make(map[string]Level, len(levels.moduleLevels))
+	for k, v := range levels.moduleLevels {
+		moduleLevels[k] = v
+	}
+	return levels.defaultLevel, moduleLevels
+}
+
+// SetLevel changes the log level of all modules with the given prefix at
+// runtime. In case the module is DefaultModule, the default log level is
+// changed instead.
+//
+// The change takes effect for all existing and future loggers.
+func SetLevel(module string, lvl Level) error {
+	if lvl > LevelError {
+		return fmt.Errorf("logging: invalid log level: %d", lvl)
+	}
+
+	backend.Lock()
+	defer backend.Unlock()
+
+	old := backend.levels.Load()
+	defaultLvl := old.defaultLevel
+	moduleLevels := make(map[string]Level, len(old.moduleLevels)+1)
+	for k, v := range old.moduleLevels {
+		moduleLevels[k] = v
+	}
+	switch module {
+	case DefaultModule:
+		defaultLvl = lvl
+	default:
+		moduleLevels[module] = lvl
+	}
+
+	backend.setLevelsLocked(defaultLvl, moduleLevels)
+
+	return nil
+}
+
+// ResetLevel removes the log level override for the given module, so that
+// the module falls back to the next matching prefix or the default level.
+func ResetLevel(module string) {
+	backend.Lock()
+	defer backend.Unlock()
+
+	old := backend.levels.Load()
+	if _, ok := old.moduleLevels[module]; !ok {
+		return
+	}
+	moduleLevels := make(map[string]Level, len(old.moduleLevels))
+	for k, v := range old.moduleLevels {
+		if k != module {
+			moduleLevels[k] = v
+		}
+	}
+
+	backend.setLevelsLocked(old.defaultLevel, moduleLevels)
 }
 
 // GetLogger creates a new logger instance with the specified module.
@@ -258,29 +324,22 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 		}
 	}
 
-	logger = level.NewFilter(logger, defaultLvl.toOption())
-
+	// NOTE: Filtering is performed by the loggers themselves, based on the
+	//       level registry, so that levels can be changed at runtime.
 	backend.baseLogger = logger
-	backend.moduleLevels = moduleLvls
-	backend.defaultLevel = defaultLvl
+	backend.setLevelsLocked(defaultLvl, moduleLvls)
 	backend.initialized = true
 
-	// Swap all the early loggers to the initialized backend.
+	// Swap all the early loggers to the initialized backend. Their log levels
+	// get re-evaluated automatically as the level registry has changed.
 	for _, l := range backend.earlyLoggers {
-		l.swapLogger.Swap(backend.baseLogger)
-
-		// Re-evaluate log level.
-		// NOTE: This introduces a potential race condition if loggers are used
-		//       before the logging system is initialized. Protecting the log
-		//       level with a mutex just for this one case seems overkill.
-		backend.setupLogLevelLocked(l.logger)
+		l.Swap(backend.baseLogger)
 	}
 	backend.earlyLoggers = nil
 
 	// libp2p/IPFS uses yet another logging library, that appears to be a
 	// wrapper around zap.
 	ipfsLogger := newZapCore(log.With(logger, "ts", log.DefaultTimestampUTC), "libp2p", 7)
-	backend.setupLogLevelLocked(ipfsLogger.logger)
 
 	// Update the ipfs core logger.
 	ipfsLog.SetPrimaryCore(ipfsLogger)
@@ -291,41 +350,63 @@ func Initialize(w io.Writer, format Format, defaultLvl Level, moduleLvls map[str
 	return nil
 }
 
-type earlyLogger struct {
-	swapLogger *log.SwapLogger
-	logger     *Logger
+// levelRegistry is an immutable snapshot of the configured log levels.
+type levelRegistry struct {
+	generation uint64
+
+	defaultLevel   Level
+	moduleLevels   map[string]Level
+	modulePrefixes []string
+}
+
+func (r *levelRegistry) levelFor(module string) Level {
+	// Check, whether there is a specific logging level set for the module.
+	// The longest prefix match of the module name provided in the config file will be taken.
+	// Otherwise, fallback to level defined by "default" key.
+	for _, k := range r.modulePrefixes {
+		if strings.HasPrefix(module, k) {
+			return r.moduleLevels[k]
+		}
+	}
+	return r.defaultLevel
 }
 
 type logBackend struct {
 	sync.Mutex
 
 	baseLogger   log.Logger
-	earlyLoggers []*earlyLogger
-	defaultLevel Level
-	moduleLevels map[string]Level
+	earlyLoggers []*log.SwapLogger
+	levels       atomic.Pointer[levelRegistry]
 
 	initialized bool
 }
 
-func (b *logBackend) setupLogLevelLocked(l *Logger) {
-	// Check, whether there is a specific logging level set for the module.
-	// The longest prefix match of the module name provided in the config file will be taken.
-	// Otherwise, fallback to level defined by "default" key.
-	modulePrefixes := make([]string, 0, len(b.moduleLevels))
-	for k := range b.moduleLevels {
-		modulePrefixes = append(modulePrefixes, k)
+func newLogBackend() *logBackend {
+	b := &logBackend{
+		baseLogger: log.NewNopLogger(),
 	}
-	sort.Sort(sort.Reverse(sort.StringSlice(modulePrefixes)))
+	b.levels.Store(&levelRegistry{
+		// Start with a non-zero generation so that zero-valued loggers
+		// always evaluate their level on first use.
+		generation:   1,
+		defaultLevel: LevelError,
+	})
+	return b
+}
 
-	lvl := b.defaultLevel
-	for _, k := range modulePrefixes {
-		if strings.HasPrefix(l.module, k) {
-			lvl = b.moduleLevels[k]
-			break
-		}
+func (b *logBackend) setLevelsLocked(defaultLvl Level, moduleLvls map[string]Level) {
+	modulePrefixes := make([]string, 0, len(moduleLvls))
+	for k := range moduleLvls {
+		modulePrefixes = append(modulePrefixes, k)
 	}
+	sort.Sort(sort.Reverse(sort.StringSlice(modulePrefixes)))
 
-	l.level = lvl
+	b.levels.Store(&levelRegistry{
+		generation:     b.levels.Load().generation + 1,
+		defaultLevel:   defaultLvl,
+		moduleLevels:   moduleLvls,
+		modulePrefixes: modulePrefixes,
+	})
 }
 
 func (b *logBackend) getLogger(module string, extraUnwind int) *Logger {
@@ -351,13 +432,11 @@ func (b *logBackend) getLogger(module string, extraUnwind int) *Logger {
 		logger: log.WithPrefix(logger, prefixes...),
 		module: module,
 	}
-	b.setupLogLevelLocked(l)
 
 	if !b.initialized {
 		// Stash the logger so that it can be instantiated once logging
 		// is actually initialized.
-		sLog := logger.(*log.SwapLogger)
-		b.earlyLoggers = append(b.earlyLoggers, &earlyLogger{swapLogger: sLog, logger: l})
+		b.earlyLoggers = append(b.earlyLoggers, logger.(*log.SwapLogger))
 	}
 
 	return l
@@ -376,13 +455,11 @@ func (b *logBackend) getBaseLogger(module string) *Logger {
 		logger: log.WithPrefix(logger, "module", module),
 		module: module,
 	}
-	b.setupLogLevelLocked(l)
 
 	if !b.initialized {
 		// Stash the logger so that it can be instantiated once logging
 		// is actually initialized.
-		sLog := logger.(*log.SwapLogger)
-		b.earlyLoggers = append(b.earlyLoggers, &earlyLogger{swapLogger: sLog, logger: l})
+		b.earlyLoggers = append(b.earlyLoggers, logger.(*log.SwapLogger))
 	}
 
 	return l
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevel(t *testing.T) {
+	require := require.New(t)
+
+	defaultLvl, moduleLvls := GetLevels()
+	t.Cleanup(func() {
+		backend.Lock()
+		defer backend.Unlock()
+		backend.setLevelsLocked(defaultLvl, moduleLvls)
+	})
+
+	l1 := GetLogger("test/module/a")
+	l2 := GetLogger("test/module/b")
+	l3 := GetLogger("test/other").With("key", "value")
+
+	err := SetLevel(DefaultModule, LevelWarn)
+	require.NoError(err, "SetLevel")
+	require.Equal(LevelWarn, GetLevel())
+	require.Equal(LevelWarn, l1.getLevel())
+	require.Equal(LevelWarn, l2.getLevel())
+	require.Equal(LevelWarn, l3.getLevel())
+
+	err = SetLevel("test/module", LevelInfo)
+	require.NoError(err, "SetLevel")
+	require.Equal(LevelInfo, l1.getLevel())
+	require.Equal(LevelInfo, l2.getLevel())
+	require.Equal(LevelWarn, l3.getLevel())
+
+	err = SetLevel("test/module/b", LevelDebug)
+	require.NoError(err, "SetLevel")
+	require.Equal(LevelInfo, l1.getLevel())
+	require.Equal(LevelDebug, l2.getLevel())
+
+	_, levels := GetLevels()
+	require.Equal(LevelInfo, levels["test/module"])
+	require.Equal(LevelDebug, levels["test/module/b"])
+
+	ResetLevel("test/module/b")
+	require.Equal(LevelInfo, l2.getLevel())
+
+	err = SetLevel("test/module", Level(42))
+	require.Error(err, "SetLevel should fail with an invalid level")
+}
@@ -218,18 +218,19 @@ func newZapCore(logger log.Logger, module string, unwind int) *zapCore {
 
 // Implements zapcore.LevelEnabler.
 func (l *zapCore) Enabled(level zapcore.Level) bool {
+	lvl := l.logger.getLevel()
 	switch level {
 	case zapcore.DebugLevel:
-		return l.logger.level <= LevelDebug
+		return lvl <= LevelDebug
 	case zapcore.InfoLevel:
-		return l.logger.level <= LevelInfo
+		return lvl <= LevelInfo
 	case zapcore.WarnLevel:
-		return l.logger.level <= LevelWarn
+		return lvl <= LevelWarn
 	case zapcore.ErrorLevel:
-		return l.logger.level <= LevelError
+		return lvl <= LevelError
 	default:
 		// DPanic, Panic, Fatal levels..
-		return l.logger.level <= LevelError
+		return lvl <= LevelError
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 
 	"github.com/multiformats/go-multiaddr"
@@ -131,6 +132,13 @@ type ConsensusAddress struct {
 	ID signature.PublicKey `json:"id"`
 	// Address is the address at which the node can be reached.
 	Address Address `json:"address"`
+	// Priority is the priority of the address, where addresses with lower values are preferred.
+	Priority uint8 `json:"priority,omitempty"`
+}
+
+// Equal compares vs another consensus address for equality, ignoring the priority.
+func (ca *ConsensusAddress) Equal(other *ConsensusAddress) bool {
+	return ca.ID.Equal(other.ID) && ca.Address.Equal(&other.Address)
 }
 
 // MarshalText implements the encoding.TextMarshaler interface.
@@ -163,6 +171,16 @@ func (ca *ConsensusAddress) String() string {
 	return fmt.Sprintf("%s@%s", ca.ID, ca.Address)
 }
 
+// SortConsensusAddresses returns a copy of the given consensus addresses ordered by priority,
+// most preferred first. Addresses with equal priority keep their relative order.
+func SortConsensusAddresses(addrs []ConsensusAddress) []ConsensusAddress {
+	sorted := slices.Clone(addrs)
+	slices.SortStableFunc(sorted, func(a, b ConsensusAddress) int {
+		return int(a.Priority) - int(b.Priority)
+	})
+	return sorted
+}
+
 // TLSAddress represents an Oasis committee address that includes a TLS public key and a TCP
 // address.
 //
@@ -70,6 +70,53 @@ func TestConsensusAddress(t *testing.T) {
 	}
 }
 
+func TestSortConsensusAddresses(t *testing.T) {
+	require := require.New(t)
+
+	var addrs []ConsensusAddress
+	for i, v := range []struct {
+		addr     string
+		priority uint8
+	}{
+		{"35.100.2.11:8000", 1},
+		{"35.100.2.12:8000", 0},
+		{"[2001:db8::1]:8000", 1},
+		{"35.100.2.13:8000", 0},
+	} {
+		var ca ConsensusAddress
+		require.NoError(ca.UnmarshalText([]byte("D2hqhJcmZnBmhw9TodOdoFPAjmRkpRatANCNHxIDHgA=@"+v.addr)), "UnmarshalText %d", i)
+		ca.Priority = v.priority
+		addrs = append(addrs, ca)
+	}
+
+	sorted := SortConsensusAddresses(addrs)
+	require.Equal([]ConsensusAddress{addrs[1], addrs[3], addrs[0], addrs[2]}, sorted, "addresses should be sorted by priority")
+	require.Equal(uint8(1), addrs[0].Priority, "input should not be modified")
+
+	// Equality should ignore the priority.
+	other := addrs[0]
+	other.Priority = 5
+	require.True(addrs[0].Equal(&other))
+	require.False(addrs[0].Equal(&addrs[1]))
+}
+
+func TestP2PSortedAddresses(t *testing.T) {
+	require := require.New(t)
+
+	var info P2PInfo
+	for i, v := range []string{"35.100.2.11:8000", "35.100.2.12:8000", "35.100.2.13:8000"} {
+		var addr Address
+		require.NoError(addr.UnmarshalText([]byte(v)), "UnmarshalText %d", i)
+		info.Addresses = append(info.Addresses, addr)
+	}
+	require.Equal(info.Addresses, info.SortedAddresses(), "addresses without priorities should keep their order")
+
+	info.AddressPriorities = []uint8{2, 0, 1}
+	require.Equal([]Address{info.Addresses[1], info.Addresses[2], info.Addresses[0]}, info.SortedAddresses(), "addresses should be sorted by priority")
+	require.Equal(uint8(2), info.AddressPriority(0))
+	require.Equal(uint8(0), info.AddressPriority(3), "missing priorities should default to zero")
+}
+
 func TestTLSAddress(t *testing.T) {
 	type testCase struct {
 		tlsAddress string
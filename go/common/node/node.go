@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,8 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 )
 
@@ -173,6 +176,18 @@ func (sw SoftwareVersion) ValidateBasic() error {
 	return nil
 }
 
+// Version parses the oasis-node version from the software version.
+//
+// Any additional information following the version (e.g., the list of supported upgrade
+// handlers) is ignored.
+func (sw SoftwareVersion) Version() (version.Version, error) {
+	fields := strings.Fields(string(sw))
+	if len(fields) == 0 {
+		return version.Version{}, fmt.Errorf("missing node software version")
+	}
+	return version.FromString(fields[0])
+}
+
 // RolesMask is Oasis node roles bitmask.
 type RolesMask uint32
 
@@ -452,6 +467,11 @@ type Runtime struct {
 type TLSInfo struct {
 	// PubKey is the public key used for establishing TLS connections.
 	PubKey signature.PublicKey `json:"pub_key"`
+
+	// NextPubKey is the public key that will be used for establishing TLS connections once the
+	// node's pending TLS key rotation completes. It is only set during the rotation overlap
+	// window, in which connections authenticated with either key should be accepted.
+	NextPubKey *signature.PublicKey `json:"next_pub_key,omitempty"`
 }
 
 // nodeV2TLSInfo is TLSInfo used in version 2 node descriptors.
@@ -470,7 +490,21 @@ type nodeV2TLSInfo struct {
 
 // Equal compares vs another TLSInfo for equality.
 func (t *TLSInfo) Equal(other *TLSInfo) bool {
-	return t.PubKey.Equal(other.PubKey)
+	if !t.PubKey.Equal(other.PubKey) {
+		return false
+	}
+	if t.NextPubKey == nil || other.NextPubKey == nil {
+		return t.NextPubKey == other.NextPubKey
+	}
+	return t.NextPubKey.Equal(*other.NextPubKey)
+}
+
+// PubKeys returns all public keys that can be used for establishing TLS connections.
+func (t *TLSInfo) PubKeys() []signature.PublicKey {
+	if t.NextPubKey == nil {
+		return []signature.PublicKey{t.PubKey}
+	}
+	return []signature.PublicKey{t.PubKey, *t.NextPubKey}
 }
 
 // P2PInfo contains information for connecting to this node via P2P transport.
@@ -480,6 +514,36 @@ type P2PInfo struct {
 
 	// Addresses is the list of addresses at which the node can be reached.
 	Addresses []Address `json:"addresses"`
+
+	// AddressPriorities are the priorities of the addresses at the same index, where addresses
+	// with lower values are preferred. If empty, all addresses have the same priority.
+	AddressPriorities []uint8 `json:"address_priorities,omitempty"`
+}
+
+// AddressPriority returns the priority of the address at the given index.
+func (p *P2PInfo) AddressPriority(i int) uint8 {
+	if i >= len(p.AddressPriorities) {
+		return 0
+	}
+	return p.AddressPriorities[i]
+}
+
+// SortedAddresses returns a copy of the addresses ordered by priority, most preferred first.
+// Addresses with equal priority keep their relative order.
+func (p *P2PInfo) SortedAddresses() []Address {
+	indices := make([]int, len(p.Addresses))
+	for i := range indices {
+		indices[i] = i
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		return int(p.AddressPriority(a)) - int(p.AddressPriority(b))
+	})
+
+	sorted := make([]Address, 0, len(p.Addresses))
+	for _, i := range indices {
+		sorted = append(sorted, p.Addresses[i])
+	}
+	return sorted
 }
 
 // ConsensusInfo contains information for connecting to this node as a
@@ -601,6 +665,46 @@ func (c *CapabilityTEE) Verify(teeCfg *TEEFeatures, ts time.Time, height uint64,
 	}
 }
 
+// ExplainVerificationFailure explains which quote policy rule the node's TEE capabilities fail
+// at the provided timestamp. In case no quote policy rule fails, nil is returned.
+//
+// Note that this does not check the parts of the attestation that are not covered by the quote
+// policy (e.g., the RAK binding and the attestation signature).
+func (c *CapabilityTEE) ExplainVerificationFailure(teeCfg *TEEFeatures, ts time.Time, constraints []byte) *pcs.QuotePolicyFailure {
+	if teeCfg == nil {
+		teeCfg = &emptyFeatures
+	}
+
+	switch c.Hardware {
+	case TEEHardwareIntelSGX:
+		var sa SGXAttestation
+		if err := cbor.Unmarshal(c.Attestation, &sa); err != nil {
+			return &pcs.QuotePolicyFailure{
+				Rule:   pcs.RuleMalformedQuote,
+				Reason: "malformed SGX attestation",
+				Err:    err,
+			}
+		}
+		var sc SGXConstraints
+		if err := cbor.Unmarshal(constraints, &sc); err != nil {
+			return &pcs.QuotePolicyFailure{
+				Rule:   pcs.RuleMalformedQuote,
+				Reason: "malformed SGX constraints",
+				Err:    err,
+			}
+		}
+		teeCfg.SGX.ApplyDefaultConstraints(&sc)
+
+		enclaves := sc.Enclaves
+		if enclaves == nil {
+			enclaves = []sgx.EnclaveIdentity{}
+		}
+		return sa.Quote.ExplainPolicyFailure(sc.Policy, ts, enclaves)
+	default:
+		return nil
+	}
+}
+
 // EndorseCapabilityTEESignatureContext is the signature context used for TEE capability endorsement.
 var EndorseCapabilityTEESignatureContext = signature.NewContext("oasis-core/node: endorse TEE capability")
 
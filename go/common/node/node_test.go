@@ -479,4 +479,15 @@ func TestNodeSoftwareVersion(t *testing.T) {
 
 	sw = SoftwareVersion(strings.Repeat("a", 1000))
 	require.Error(sw.ValidateBasic(), "invalid software version")
+
+	_, err := SoftwareVersion("").Version()
+	require.Error(err, "Version should fail for empty software version")
+
+	v, err := SoftwareVersion("24.3.1-git1234+dirty (upgrade handlers: consensus243)").Version()
+	require.NoError(err, "Version")
+	require.Equal(version.Version{Major: 24, Minor: 3, Patch: 1}, v)
+
+	v, err = SoftwareVersion("24.3").Version()
+	require.NoError(err, "Version")
+	require.Equal(version.Version{Major: 24, Minor: 3}, v)
 }
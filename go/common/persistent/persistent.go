@@ -4,22 +4,46 @@ package persistent
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
+	"sort"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/dgraph-io/badger/v4/options"
 
 	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
-const dbName = "persistent-store.badger.db"
+const (
+	dbName = "persistent-store.badger.db"
 
-// ErrNotFound is returned when the requested key could not be found in the database.
-var ErrNotFound = errors.New("persistent: key not found in database")
+	// metaNamespace is the name of the namespace holding store metadata.
+	metaNamespace = "persistent/meta"
+
+	// SnapshotVersion is the current version of the store snapshot format.
+	SnapshotVersion = 1
+)
+
+var (
+	// ErrNotFound is returned when the requested key could not be found in the database.
+	ErrNotFound = errors.New("persistent: key not found in database")
+
+	// ErrIdentityMismatch is returned when importing a snapshot belonging to a different
+	// node identity than the store.
+	ErrIdentityMismatch = errors.New("persistent: snapshot belongs to a different node identity")
+
+	// namespaceSeparator separates the namespace from the key in database keys.
+	namespaceSeparator = []byte{'.'}
+
+	// metaKeyNodeID is the metadata key under which the node identity is stored.
+	metaKeyNodeID = []byte("node_id")
+)
 
 // GetPersistentStoreDBDir returns the database directory path for the node with
 // the given data directory.
@@ -47,6 +71,170 @@ func (cs *CommonStore) GetServiceStore(name string) *ServiceStore {
 	}
 }
 
+// SetNodeID records the identity of the node that owns the store.
+func (cs *CommonStore) SetNodeID(id signature.PublicKey) error {
+	return cs.GetServiceStore(metaNamespace).PutCBOR(metaKeyNodeID, id)
+}
+
+// NodeID returns the identity of the node that owns the store.
+//
+// In case the identity has not yet been recorded, ErrNotFound is returned.
+func (cs *CommonStore) NodeID() (signature.PublicKey, error) {
+	var id signature.PublicKey
+	if err := cs.GetServiceStore(metaNamespace).GetCBOR(metaKeyNodeID, &id); err != nil {
+		return signature.PublicKey{}, err
+	}
+	return id, nil
+}
+
+// Namespaces returns the sorted list of all namespaces that contain at least one key.
+func (cs *CommonStore) Namespaces() ([]string, error) {
+	namespaces := make(map[string]struct{})
+	err := cs.iterate(nil, func(namespace string, _, _ []byte) error {
+		namespaces[namespace] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Snapshot is a versioned snapshot of the contents of the common store.
+type Snapshot struct {
+	cbor.Versioned
+
+	// NodeID is the identity of the node that owns the store, if known.
+	NodeID *signature.PublicKey `json:"node_id,omitempty"`
+
+	// Entries are the exported store entries, ordered by namespace and key.
+	Entries []SnapshotEntry `json:"entries"`
+}
+
+// SnapshotEntry is a single entry of the common store snapshot.
+type SnapshotEntry struct {
+	// Namespace is the namespace of the entry.
+	Namespace string `json:"namespace"`
+	// Key is the key of the entry within the namespace.
+	Key []byte `json:"key"`
+	// Value is the CBOR-serialized value of the entry.
+	Value []byte `json:"value"`
+}
+
+// Export writes a versioned CBOR snapshot of the entire store into the given writer.
+func (cs *CommonStore) Export(ctx context.Context, w io.Writer) error {
+	snapshot := Snapshot{
+		Versioned: cbor.NewVersioned(SnapshotVersion),
+	}
+	switch id, err := cs.NodeID(); err {
+	case nil:
+		snapshot.NodeID = &id
+	case ErrNotFound:
+	default:
+		return fmt.Errorf("persistent: failed to get node identity: %w", err)
+	}
+
+	err := cs.iterate(nil, func(namespace string, key, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if namespace == metaNamespace {
+			return nil
+		}
+		snapshot.Entries = append(snapshot.Entries, SnapshotEntry{
+			Namespace: namespace,
+			Key:       key,
+			Value:     value,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("persistent: failed to export store: %w", err)
+	}
+
+	return cbor.NewEncoder(w).Encode(&snapshot)
+}
+
+// Import reads a versioned CBOR snapshot from the given reader and writes all of
+// its entries into the store, overwriting any existing entries with the same keys.
+//
+// Unless force is set, importing a snapshot that belongs to a different node identity
+// than the one recorded in the store fails with ErrIdentityMismatch.
+func (cs *CommonStore) Import(ctx context.Context, r io.Reader, force bool) error {
+	var snapshot Snapshot
+	if err := cbor.NewDecoder(r).Decode(&snapshot); err != nil {
+		return fmt.Errorf("persistent: failed to decode snapshot: %w", err)
+	}
+	if snapshot.V != SnapshotVersion {
+		return fmt.Errorf("persistent: unsupported snapshot version: %d", snapshot.V)
+	}
+
+	switch id, err := cs.NodeID(); err {
+	case nil:
+		if !force && (snapshot.NodeID == nil || !snapshot.NodeID.Equal(id)) {
+			return ErrIdentityMismatch
+		}
+	case ErrNotFound:
+	default:
+		return fmt.Errorf("persistent: failed to get node identity: %w", err)
+	}
+
+	wb := cs.db.NewWriteBatch()
+	defer wb.Cancel()
+
+	for _, entry := range snapshot.Entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.Namespace == metaNamespace {
+			return fmt.Errorf("persistent: snapshot must not contain metadata entries")
+		}
+		if err := wb.Set(dbKey([]byte(entry.Namespace), entry.Key), entry.Value); err != nil {
+			return fmt.Errorf("persistent: failed to import entry: %w", err)
+		}
+	}
+	if snapshot.NodeID != nil {
+		if err := wb.Set(dbKey([]byte(metaNamespace), metaKeyNodeID), cbor.Marshal(snapshot.NodeID)); err != nil {
+			return fmt.Errorf("persistent: failed to import node identity: %w", err)
+		}
+	}
+
+	if err := wb.Flush(); err != nil {
+		return fmt.Errorf("persistent: failed to import snapshot: %w", err)
+	}
+	return nil
+}
+
+// iterate calls the given function for each key with the given prefix, in key order.
+func (cs *CommonStore) iterate(prefix []byte, fn func(namespace string, key, value []byte) error) error {
+	return cs.db.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			namespace, key, ok := bytes.Cut(item.KeyCopy(nil), namespaceSeparator)
+			if !ok {
+				continue
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err = fn(string(namespace), key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // NewCommonStore opens the default common node storage and returns a handle.
 func NewCommonStore(dataDir string) (*CommonStore, error) {
 	logger := logging.GetLogger("common/persistent")
@@ -111,6 +299,37 @@ func (ss *ServiceStore) PutCBOR(key []byte, value any) error {
 	})
 }
 
+// PutManyCBOR is a helper for storing multiple CBOR-serialized values at once. The i-th key is
+// set to the i-th value.
+//
+// Writes are batched into as few database transactions as possible.
+func (ss *ServiceStore) PutManyCBOR(keys [][]byte, values []any) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("persistent: number of keys and values differ")
+	}
+	return ss.updateMany(len(keys), func(tx *badger.Txn, i int) error {
+		return tx.Set(ss.dbKey(keys[i]), cbor.Marshal(values[i]))
+	})
+}
+
+// Iterate calls the given function for each key in the service store, in key order,
+// passing the key and its raw CBOR-serialized value.
+//
+// Iteration stops at the first error returned by the function.
+func (ss *ServiceStore) Iterate(fn func(key, value []byte) error) error {
+	return ss.IteratePrefix(nil, fn)
+}
+
+// IteratePrefix calls the given function for each key in the service store that starts with
+// the given prefix, in key order, passing the key and its raw CBOR-serialized value.
+//
+// Iteration stops at the first error returned by the function.
+func (ss *ServiceStore) IteratePrefix(prefix []byte, fn func(key, value []byte) error) error {
+	return ss.store.iterate(ss.dbKey(prefix), func(_ string, key, value []byte) error {
+		return fn(key, value)
+	})
+}
+
 // Delete removes the specified key from the service store.
 func (ss *ServiceStore) Delete(key []byte) error {
 	return ss.store.db.Update(func(tx *badger.Txn) error {
@@ -123,6 +342,102 @@ func (ss *ServiceStore) Delete(key []byte) error {
 	})
 }
 
+// DeleteMany removes the specified keys from the service store. Keys that do not exist are
+// ignored.
+//
+// Deletions are batched into as few database transactions as possible.
+func (ss *ServiceStore) DeleteMany(keys [][]byte) error {
+	return ss.updateMany(len(keys), func(tx *badger.Txn, i int) error {
+		return tx.Delete(ss.dbKey(keys[i]))
+	})
+}
+
+// updateMany performs n updates, committing the current database transaction and starting
+// a new one whenever the transaction grows too big.
+func (ss *ServiceStore) updateMany(n int, fn func(tx *badger.Txn, i int) error) error {
+	tx := ss.store.db.NewTransaction(true)
+	defer func() {
+		tx.Discard()
+	}()
+
+	for i := 0; i < n; i++ {
+		err := fn(tx, i)
+		if err == badger.ErrTxnTooBig {
+			if err = tx.Commit(); err != nil {
+				return err
+			}
+			tx = ss.store.db.NewTransaction(true)
+			err = fn(tx, i)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (ss *ServiceStore) dbKey(key []byte) []byte {
-	return bytes.Join([][]byte{ss.name, key}, []byte{'.'})
+	return dbKey(ss.name, key)
+}
+
+func dbKey(namespace, key []byte) []byte {
+	return bytes.Join([][]byte{namespace, key}, namespaceSeparator)
+}
+
+// TypedStore is a namespace bucket holding values of a single type.
+type TypedStore[V any] struct {
+	ss *ServiceStore
+}
+
+// NewTypedStore creates a new typed namespace bucket for the given service.
+func NewTypedStore[V any](cs *CommonStore, name string) *TypedStore[V] {
+	return &TypedStore[V]{
+		ss: cs.GetServiceStore(name),
+	}
+}
+
+// Get retrieves the value stored under the given key.
+func (ts *TypedStore[V]) Get(key []byte) (*V, error) {
+	var value V
+	if err := ts.ss.GetCBOR(key, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// Put stores the value under the given key.
+func (ts *TypedStore[V]) Put(key []byte, value *V) error {
+	return ts.ss.PutCBOR(key, value)
+}
+
+// PutMany stores the i-th value under the i-th key.
+func (ts *TypedStore[V]) PutMany(keys [][]byte, values []*V) error {
+	vs := make([]any, 0, len(values))
+	for _, v := range values {
+		vs = append(vs, v)
+	}
+	return ts.ss.PutManyCBOR(keys, vs)
+}
+
+// Delete removes the given key.
+func (ts *TypedStore[V]) Delete(key []byte) error {
+	return ts.ss.Delete(key)
+}
+
+// DeleteMany removes the given keys, ignoring the ones that do not exist.
+func (ts *TypedStore[V]) DeleteMany(keys [][]byte) error {
+	return ts.ss.DeleteMany(keys)
+}
+
+// Iterate calls the given function for each key and its decoded value, in key order.
+//
+// Iteration stops at the first error returned by the function.
+func (ts *TypedStore[V]) Iterate(fn func(key []byte, value *V) error) error {
+	return ts.ss.Iterate(func(key, raw []byte) error {
+		var value V
+		if err := cbor.Unmarshal(raw, &value); err != nil {
+			return fmt.Errorf("persistent: failed to decode value: %w", err)
+		}
+		return fn(key, &value)
+	})
 }
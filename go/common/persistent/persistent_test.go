@@ -1,10 +1,15 @@
 package persistent
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 )
 
 func TestPersistent(t *testing.T) {
@@ -31,3 +36,130 @@ func TestPersistent(t *testing.T) {
 	err = svc.GetCBOR(nonexistentKey, &valOut)
 	assert.Equal(t, ErrNotFound, err, "GetCBOR(nonexistent)")
 }
+
+func TestPersistentIterate(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	common, err := NewCommonStore(dir)
+	require.NoError(err, "NewCommonStore")
+	defer common.Close()
+
+	svc := NewTypedStore[uint64](common, "persistent_test/a")
+	for i, key := range []string{"c", "a", "b"} {
+		v := uint64(i)
+		err = svc.Put([]byte(key), &v)
+		require.NoError(err, "Put")
+	}
+	other := common.GetServiceStore("persistent_test/b")
+	err = other.PutCBOR([]byte("x"), "y")
+	require.NoError(err, "PutCBOR")
+
+	var keys []string
+	var values []uint64
+	err = svc.Iterate(func(key []byte, value *uint64) error {
+		keys = append(keys, string(key))
+		values = append(values, *value)
+		return nil
+	})
+	require.NoError(err, "Iterate")
+	require.Equal([]string{"a", "b", "c"}, keys)
+	require.Equal([]uint64{1, 2, 0}, values)
+
+	err = svc.Put([]byte("bb"), new(uint64))
+	require.NoError(err, "Put")
+	keys = nil
+	err = common.GetServiceStore("persistent_test/a").IteratePrefix([]byte("b"), func(key, _ []byte) error {
+		keys = append(keys, string(key))
+		return nil
+	})
+	require.NoError(err, "IteratePrefix")
+	require.Equal([]string{"b", "bb"}, keys)
+
+	namespaces, err := common.Namespaces()
+	require.NoError(err, "Namespaces")
+	require.Equal([]string{"persistent_test/a", "persistent_test/b"}, namespaces)
+}
+
+func TestPersistentBatch(t *testing.T) {
+	require := require.New(t)
+
+	common, err := NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer common.Close()
+
+	svc := NewTypedStore[uint64](common, "persistent_test")
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	values := []*uint64{new(uint64), new(uint64), new(uint64)}
+	for i, v := range values {
+		*v = uint64(i)
+	}
+	err = svc.PutMany(keys, values)
+	require.NoError(err, "PutMany")
+
+	err = svc.PutMany(keys, values[:1])
+	require.Error(err, "PutMany should fail with mismatched keys and values")
+
+	for i, key := range keys {
+		v, err := svc.Get(key)
+		require.NoError(err, "Get")
+		require.EqualValues(i, *v)
+	}
+
+	err = svc.DeleteMany([][]byte{[]byte("a"), []byte("c"), []byte("nonexistent")})
+	require.NoError(err, "DeleteMany")
+
+	_, err = svc.Get([]byte("a"))
+	require.Equal(ErrNotFound, err)
+	_, err = svc.Get([]byte("c"))
+	require.Equal(ErrNotFound, err)
+	v, err := svc.Get([]byte("b"))
+	require.NoError(err, "Get")
+	require.EqualValues(1, *v)
+}
+
+func TestPersistentExportImport(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	src, err := NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer src.Close()
+
+	nodeID := signature.NewPublicKey("4242424242424242424242424242424242424242424242424242424242424242")
+	otherID := signature.NewPublicKey("4343434343434343434343434343434343434343434343434343434343434343")
+
+	err = src.SetNodeID(nodeID)
+	require.NoError(err, "SetNodeID")
+	err = src.GetServiceStore("persistent_test").PutCBOR([]byte("foo"), "bar")
+	require.NoError(err, "PutCBOR")
+
+	var buf bytes.Buffer
+	err = src.Export(ctx, &buf)
+	require.NoError(err, "Export")
+	snapshot := buf.Bytes()
+
+	// Import into an empty store.
+	dst, err := NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer dst.Close()
+
+	err = dst.Import(ctx, bytes.NewReader(snapshot), false)
+	require.NoError(err, "Import")
+
+	var val string
+	err = dst.GetServiceStore("persistent_test").GetCBOR([]byte("foo"), &val)
+	require.NoError(err, "GetCBOR")
+	require.Equal("bar", val)
+	id, err := dst.NodeID()
+	require.NoError(err, "NodeID")
+	require.EqualValues(nodeID, id)
+
+	// Import into a store belonging to a different node.
+	err = dst.SetNodeID(otherID)
+	require.NoError(err, "SetNodeID")
+	err = dst.Import(ctx, bytes.NewReader(snapshot), false)
+	require.ErrorIs(err, ErrIdentityMismatch, "Import should fail for a different identity")
+	err = dst.Import(ctx, bytes.NewReader(snapshot), true)
+	require.NoError(err, "Import(force)")
+}
@@ -0,0 +1,266 @@
+package pcs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+)
+
+// QuotePolicyRule is a quote policy rule that a quote can fail.
+type QuotePolicyRule string
+
+const (
+	// RuleMalformedQuote is the rule that the quote must be well-formed.
+	RuleMalformedQuote QuotePolicyRule = "malformed_quote"
+	// RulePolicyDisabled is the rule that PCS quotes must be enabled by the policy.
+	RulePolicyDisabled QuotePolicyRule = "policy_disabled"
+	// RuleTEEType is the rule that the TEE type must be allowed by the policy.
+	RuleTEEType QuotePolicyRule = "tee_type"
+	// RuleDebugEnclave is the rule that the debug flag must match the node mode.
+	RuleDebugEnclave QuotePolicyRule = "debug_enclave"
+	// RuleTDXModule is the rule that the TDX module must be allowed by the policy.
+	RuleTDXModule QuotePolicyRule = "tdx_module"
+	// RulePCKCertificate is the rule that the PCK certificate chain must be valid.
+	RulePCKCertificate QuotePolicyRule = "pck_certificate"
+	// RuleFMSPC is the rule that the FMSPC must match the collateral and must not be blacklisted.
+	RuleFMSPC QuotePolicyRule = "fmspc"
+	// RuleCollateral is the rule that the TCB collateral must be valid under the policy.
+	RuleCollateral QuotePolicyRule = "collateral"
+	// RuleTCBStatus is the rule that the platform TCB status must be allowed.
+	RuleTCBStatus QuotePolicyRule = "tcb_status"
+	// RuleQuoteSignature is the rule that the quote and QE signatures and identity must be valid.
+	RuleQuoteSignature QuotePolicyRule = "quote_signature"
+	// RuleEnclaveIdentity is the rule that the enclave identity (MRENCLAVE/MRSIGNER) must be allowed.
+	RuleEnclaveIdentity QuotePolicyRule = "enclave_identity"
+)
+
+// QuotePolicyFailure is an explanation of why a quote does not satisfy a quote policy.
+type QuotePolicyFailure struct {
+	// Rule is the rule that failed.
+	Rule QuotePolicyRule `json:"rule"`
+	// Reason is a human readable description of the failure.
+	Reason string `json:"reason"`
+
+	// TeeType is the TEE type of the quote, if known.
+	TeeType TeeType `json:"tee_type,omitempty"`
+	// Identity is the enclave identity of the quote, if known.
+	Identity *sgx.EnclaveIdentity `json:"identity,omitempty"`
+	// FMSPC is the FMSPC of the platform, if known.
+	FMSPC []byte `json:"fmspc,omitempty"`
+	// TCBStatus is the TCB status of the platform, if known.
+	TCBStatus *TCBStatus `json:"tcb_status,omitempty"`
+	// AdvisoryIDs are the advisory identifiers of the platform TCB level, if known.
+	AdvisoryIDs []string `json:"advisory_ids,omitempty"`
+
+	// Err is the underlying verification error, if any.
+	Err error `json:"-"`
+}
+
+// Error returns the explanation as an error message.
+func (f *QuotePolicyFailure) Error() string {
+	return fmt.Sprintf("pcs/quote: policy rule '%s' failed: %s", f.Rule, f.Reason)
+}
+
+// Unwrap returns the underlying verification error.
+func (f *QuotePolicyFailure) Unwrap() error {
+	return f.Err
+}
+
+// String returns a multi-line human readable explanation.
+func (f *QuotePolicyFailure) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Failed rule: %s\n", f.Rule)
+	fmt.Fprintf(&b, "Reason:      %s\n", f.Reason)
+	if f.TeeType != 0 {
+		fmt.Fprintf(&b, "TEE type:    %s\n", f.TeeType)
+	}
+	if f.Identity != nil {
+		fmt.Fprintf(&b, "MRENCLAVE:   %s\n", f.Identity.MrEnclave)
+		fmt.Fprintf(&b, "MRSIGNER:    %s\n", f.Identity.MrSigner)
+	}
+	if f.FMSPC != nil {
+		fmt.Fprintf(&b, "FMSPC:       %X\n", f.FMSPC)
+	}
+	if f.TCBStatus != nil {
+		fmt.Fprintf(&b, "TCB status:  %s\n", f.TCBStatus)
+	}
+	if len(f.AdvisoryIDs) > 0 {
+		fmt.Fprintf(&b, "Advisories:  %s\n", strings.Join(f.AdvisoryIDs, ", "))
+	}
+	if f.Err != nil {
+		fmt.Fprintf(&b, "Error:       %s\n", f.Err)
+	}
+	return b.String()
+}
+
+// ExplainQuotePolicyFailure verifies the quote bundle against the given policy and allowed
+// enclave identities at the given timestamp and explains which policy rule failed.
+//
+// In case allowed enclaves are nil, the enclave identity is not checked. In case the quote
+// satisfies the policy, nil is returned.
+func ExplainQuotePolicyFailure(
+	bundle *QuoteBundle,
+	policy *QuotePolicy,
+	ts time.Time,
+	enclaves []sgx.EnclaveIdentity,
+) *QuotePolicyFailure {
+	var quote Quote
+	if err := quote.UnmarshalBinary(bundle.Quote); err != nil {
+		return &QuotePolicyFailure{
+			Rule:   RuleMalformedQuote,
+			Reason: "quote could not be parsed",
+			Err:    err,
+		}
+	}
+	if policy == nil {
+		policy = defaultQuotePolicy()
+	}
+
+	teeType := quote.header.TeeType()
+	identity := quote.reportBody.AsEnclaveIdentity()
+	failure := func(rule QuotePolicyRule, reason string, err error) *QuotePolicyFailure {
+		return &QuotePolicyFailure{
+			Rule:     rule,
+			Reason:   reason,
+			TeeType:  teeType,
+			Identity: &identity,
+			Err:      err,
+		}
+	}
+
+	if policy.Disabled {
+		return failure(RulePolicyDisabled, "PCS quotes are disabled by the policy", nil)
+	}
+
+	// TEE type, debug flag and TDX module.
+	var tdxCompSvn *[16]byte
+	switch report := quote.reportBody.(type) {
+	case *SgxReport:
+		if teeType != TeeTypeSGX {
+			return failure(RuleMalformedQuote, "mismatched report body and TEE type", nil)
+		}
+		if mrSignerBlacklist[report.mrSigner] {
+			return failure(RuleEnclaveIdentity, "enclave MRSIGNER is blacklisted", nil)
+		}
+		if isDebug := report.attributes.Flags.Contains(sgx.AttributeDebug); isDebug != unsafeAllowDebugEnclaves {
+			return failure(RuleDebugEnclave, debugMismatchReason(isDebug), nil)
+		}
+	case *TdReport:
+		if teeType != TeeTypeTDX {
+			return failure(RuleMalformedQuote, "mismatched report body and TEE type", nil)
+		}
+		if isDebug := report.tdAttributes.Contains(TdAttributeDebug); isDebug != unsafeAllowDebugEnclaves {
+			return failure(RuleDebugEnclave, debugMismatchReason(isDebug), nil)
+		}
+		if policy.TDX == nil {
+			return failure(RuleTEEType, "TDX quotes are not allowed by the policy", nil)
+		}
+		if err := policy.TDX.Verify(report); err != nil {
+			return failure(RuleTDXModule, fmt.Sprintf("TDX module (MRSIGNER_SEAM %X) is not allowed by the policy", report.mrSignerSeam), err)
+		}
+		tdxCompSvn = &report.teeTcbSvn
+	default:
+		return failure(RuleTEEType, fmt.Sprintf("unsupported TEE type: %s", teeType), nil)
+	}
+
+	// PCK certificate.
+	sig, ok := quote.signature.(*QuoteSignatureECDSA_P256)
+	if !ok {
+		return failure(RuleQuoteSignature, "unsupported attestation key type", nil)
+	}
+	pckInfo, err := sig.VerifyPCK(ts)
+	if err != nil {
+		return failure(RulePCKCertificate, "PCK certificate chain is invalid", err)
+	}
+
+	// FMSPC.
+	var tcbInfo TCBInfo
+	if err = json.Unmarshal(bundle.TCB.TCBInfo.TCBInfo, &tcbInfo); err != nil {
+		f := failure(RuleCollateral, "TCB info is malformed", err)
+		f.FMSPC = pckInfo.FMSPC
+		return f
+	}
+	if expected, _ := hex.DecodeString(tcbInfo.FMSPC); !bytes.Equal(expected, pckInfo.FMSPC) {
+		f := failure(RuleFMSPC, fmt.Sprintf("platform FMSPC does not match the TCB info FMSPC (%s)", tcbInfo.FMSPC), nil)
+		f.FMSPC = pckInfo.FMSPC
+		return f
+	}
+	for _, blocked := range policy.FMSPCBlacklist {
+		if strings.EqualFold(blocked, hex.EncodeToString(pckInfo.FMSPC)) {
+			f := failure(RuleFMSPC, "platform FMSPC is blacklisted by the policy", nil)
+			f.FMSPC = pckInfo.FMSPC
+			return f
+		}
+	}
+
+	// Collateral.
+	pk, err := bundle.TCB.getPublicKey(ts)
+	if err != nil {
+		f := failure(RuleCollateral, "TCB info certificate chain is invalid", err)
+		f.FMSPC = pckInfo.FMSPC
+		return f
+	}
+	opened, err := bundle.TCB.TCBInfo.open(teeType, ts, policy, pk)
+	if err != nil {
+		f := failure(RuleCollateral, fmt.Sprintf("TCB info is not valid under the policy (issued: %s, evaluation data number: %d, min: %d, validity: %d days)",
+			tcbInfo.IssueDate, tcbInfo.TCBEvaluationDataNumber, policy.MinTCBEvaluationDataNumber, policy.TCBValidityPeriod), err)
+		f.FMSPC = pckInfo.FMSPC
+		return f
+	}
+
+	// TCB status.
+	if err = opened.validateTCBLevel(pckInfo.TCBCompSVN, tdxCompSvn, pckInfo.PCESVN); err != nil {
+		f := failure(RuleTCBStatus, "platform TCB level is not supported", err)
+		f.FMSPC = pckInfo.FMSPC
+
+		var tcbErr *TCBOutOfDateError
+		if errors.As(err, &tcbErr) {
+			f.TCBStatus = &tcbErr.Status
+			f.AdvisoryIDs = tcbErr.AdvisoryIDs
+			f.Reason = fmt.Sprintf("%s TCB status %s is not allowed", tcbErr.Kind, tcbErr.Status)
+			if len(tcbErr.AdvisoryIDs) > 0 {
+				f.Reason += " and the policy does not exempt the listed advisories"
+			}
+		}
+		return f
+	}
+
+	// Everything else (QE identity, QE report and quote signatures).
+	if _, err = quote.Verify(policy, ts, &bundle.TCB); err != nil {
+		f := failure(RuleQuoteSignature, "quote verification failed", err)
+		f.FMSPC = pckInfo.FMSPC
+		return f
+	}
+
+	// Enclave identity.
+	if enclaves != nil {
+		var signerKnown bool
+		for _, allowed := range enclaves {
+			if allowed == identity {
+				return nil
+			}
+			signerKnown = signerKnown || allowed.MrSigner == identity.MrSigner
+		}
+
+		reason := "enclave MRSIGNER is not among the allowed enclaves"
+		if signerKnown {
+			reason = "enclave MRENCLAVE is not among the allowed enclaves for its MRSIGNER"
+		}
+		return failure(RuleEnclaveIdentity, reason, nil)
+	}
+
+	return nil
+}
+
+func debugMismatchReason(isDebug bool) string {
+	if isDebug {
+		return "debug enclave is not allowed in production mode"
+	}
+	return "production enclave is not allowed in debug mode"
+}
@@ -0,0 +1,137 @@
+package pcs
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+)
+
+func TestExplainQuotePolicyFailure(t *testing.T) {
+	require := require.New(t)
+
+	verificationCache.clear()
+	t.Cleanup(verificationCache.clear)
+
+	rawQuote, err := os.ReadFile("testdata/quote_v3_ecdsa_p256_pck_chain.bin")
+	require.NoError(err, "Read test vector")
+	quote, tcbBundle := loadTestQuoteAndBundle(t)
+	bundle := &QuoteBundle{
+		Quote: rawQuote,
+		TCB:   *tcbBundle,
+	}
+	policy := &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: DefaultMinTCBEvaluationDataNumber,
+	}
+	now := time.Unix(1671497404, 0)
+	identity := quote.reportBody.AsEnclaveIdentity()
+
+	// Valid quote.
+	require.Nil(ExplainQuotePolicyFailure(bundle, policy, now, nil))
+	require.Nil(ExplainQuotePolicyFailure(bundle, policy, now, []sgx.EnclaveIdentity{identity}))
+
+	// Malformed quote.
+	f := ExplainQuotePolicyFailure(&QuoteBundle{Quote: rawQuote[:16]}, policy, now, nil)
+	require.NotNil(f)
+	require.Equal(RuleMalformedQuote, f.Rule)
+
+	// Disabled policy.
+	f = ExplainQuotePolicyFailure(bundle, &QuotePolicy{Disabled: true}, now, nil)
+	require.NotNil(f)
+	require.Equal(RulePolicyDisabled, f.Rule)
+
+	// Blacklisted FMSPC.
+	f = ExplainQuotePolicyFailure(bundle, &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: DefaultMinTCBEvaluationDataNumber,
+		FMSPCBlacklist:             []string{"00606A000000"},
+	}, now, nil)
+	require.NotNil(f)
+	require.Equal(RuleFMSPC, f.Rule)
+	require.EqualValues([]byte{0x00, 0x60, 0x6a, 0x00, 0x00, 0x00}, f.FMSPC)
+
+	// Collateral not valid under the policy.
+	f = ExplainQuotePolicyFailure(bundle, &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: 14,
+	}, now, nil)
+	require.NotNil(f)
+	require.Equal(RuleCollateral, f.Rule)
+	require.ErrorContains(f, "evaluation data number: 13, min: 14")
+
+	// Expired collateral.
+	f = ExplainQuotePolicyFailure(bundle, policy, now.Add(60*24*time.Hour), nil)
+	require.NotNil(f)
+	require.Equal(RuleCollateral, f.Rule)
+
+	// Enclave identity not allowed.
+	other := identity
+	other.MrEnclave[0] ^= 0xff
+	f = ExplainQuotePolicyFailure(bundle, policy, now, []sgx.EnclaveIdentity{other})
+	require.NotNil(f)
+	require.Equal(RuleEnclaveIdentity, f.Rule)
+	require.Contains(f.Reason, "MRENCLAVE")
+	require.Equal(identity, *f.Identity)
+
+	other = identity
+	other.MrSigner[0] ^= 0xff
+	f = ExplainQuotePolicyFailure(bundle, policy, now, []sgx.EnclaveIdentity{other})
+	require.NotNil(f)
+	require.Equal(RuleEnclaveIdentity, f.Rule)
+	require.Contains(f.Reason, "MRSIGNER")
+}
+
+func TestExplainQuotePolicyFailureTDX(t *testing.T) {
+	require := require.New(t)
+
+	rawQuote, err := os.ReadFile("testdata/quote_v4_tdx_ecdsa_p256_out_of_date.bin")
+	require.NoError(err, "Read test vector")
+	rawTCBInfo, err := os.ReadFile("testdata/tcb_info_v3_tdx_fmspc_50806F000000.json")
+	require.NoError(err, "Read test vector")
+	rawCerts, err := os.ReadFile("testdata/tcb_info_v3_fmspc_00606A000000_certs.pem")
+	require.NoError(err, "Read test vector")
+	rawQEIdentity, err := os.ReadFile("testdata/qe_identity_v2_tdx.json")
+	require.NoError(err, "Read test vector")
+
+	var tcbInfo SignedTCBInfo
+	err = json.Unmarshal(rawTCBInfo, &tcbInfo)
+	require.NoError(err, "Parse TCB info")
+	var qeIdentity SignedQEIdentity
+	err = json.Unmarshal(rawQEIdentity, &qeIdentity)
+	require.NoError(err, "Parse QE identity")
+
+	bundle := &QuoteBundle{
+		Quote: rawQuote,
+		TCB: TCBBundle{
+			TCBInfo:      tcbInfo,
+			QEIdentity:   qeIdentity,
+			Certificates: rawCerts,
+		},
+	}
+	now := time.Unix(1687091776, 0)
+
+	// TDX not allowed.
+	f := ExplainQuotePolicyFailure(bundle, &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: 12,
+	}, now, nil)
+	require.NotNil(f)
+	require.Equal(RuleTEEType, f.Rule)
+	require.Equal(TeeTypeTDX, f.TeeType)
+
+	// Out of date TCB.
+	f = ExplainQuotePolicyFailure(bundle, &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: 12,
+		TDX:                        &TdxQuotePolicy{},
+	}, now, nil)
+	require.NotNil(f)
+	require.Equal(RuleTCBStatus, f.Rule)
+	require.ErrorContains(f.Err, "TCB level not supported")
+	require.NotEmpty(f.String())
+}
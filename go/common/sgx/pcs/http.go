@@ -19,11 +19,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
+// DefaultBaseURL is the base URL of the Intel SGX PCS API.
+const DefaultBaseURL = "https://api.trustedservices.intel.com"
+
 //nolint:deadcode,varcheck
 const (
 	pcsAPISubscriptionKeyHeader = "Ocp-Apim-Subscription-Key"
 	pcsAPITimeout               = 10 * time.Second
-	pcsAPIBaseURL               = "https://api.trustedservices.intel.com"
 	pcsAPIGetPCKCertificatePath = "/sgx/certification/v4/pckcert"
 	pcsAPIGetRevocationListPath = "/sgx/certification/v4/pckcrl"
 	pcsAPIGetSgxTCBInfoPath     = "/sgx/certification/v4/tcb"
@@ -39,6 +41,10 @@ type HTTPClientConfig struct {
 	// SubscriptionKey is the Intel PCS API key used for client authentication (needed for PCK
 	// certificate retrieval).
 	SubscriptionKey string
+
+	// BaseURL is the optional base URL of the PCS API (e.g., a caching PCS proxy). If empty,
+	// DefaultBaseURL is used.
+	BaseURL string
 }
 
 type httpClient struct {
@@ -239,7 +245,15 @@ func NewHTTPClient(cfg *HTTPClientConfig) (Client, error) {
 		trustRoots:      IntelTrustRoots,
 		logger:          logging.GetLogger("common/sgx/pcs/http"),
 	}
-	hc.baseURL, _ = url.Parse(pcsAPIBaseURL)
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	var err error
+	if hc.baseURL, err = url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("pcs: malformed base URL: %w", err)
+	}
 
 	return hc, nil
 }
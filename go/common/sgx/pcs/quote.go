@@ -141,11 +141,7 @@ func (q *Quote) UnmarshalBinaryWithTrailing(data []byte, allowTrailing bool) (in
 // In case of successful verification it returns the TCB level.
 func (q *Quote) Verify(policy *QuotePolicy, ts time.Time, tcb *TCBBundle) (*sgx.VerifiedQuote, error) {
 	if policy == nil {
-		policy = &QuotePolicy{
-			TCBValidityPeriod:          30,
-			MinTCBEvaluationDataNumber: DefaultMinTCBEvaluationDataNumber,
-			FMSPCBlacklist:             []string{},
-		}
+		policy = defaultQuotePolicy()
 	}
 
 	if policy.Disabled {
@@ -205,6 +201,15 @@ func (q *Quote) Verify(policy *QuotePolicy, ts time.Time, tcb *TCBBundle) (*sgx.
 	}, nil
 }
 
+// defaultQuotePolicy returns the quote policy used when no policy is configured.
+func defaultQuotePolicy() *QuotePolicy {
+	return &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: DefaultMinTCBEvaluationDataNumber,
+		FMSPCBlacklist:             []string{},
+	}
+}
+
 // Header returns the quote header.
 func (q *Quote) Header() QuoteHeader {
 	return q.header
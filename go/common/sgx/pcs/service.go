@@ -21,12 +21,17 @@ type QuoteService interface {
 }
 
 type cachingQuoteService struct {
+	*tcbStatusWatcher
+
 	client Client
 	cache  *tcbCache
 	logger *logging.Logger
 }
 
 // NewCachingQuoteService creates a new caching quote service.
+//
+// The returned service also implements TCBStatusWatcher, watching the TCB status of the platform
+// of the last resolved quote.
 func NewCachingQuoteService(
 	client Client,
 	store *persistent.CommonStore,
@@ -35,9 +40,10 @@ func NewCachingQuoteService(
 	logger := logging.GetLogger("common/sgx/pcs/cqs")
 
 	return &cachingQuoteService{
-		client: client,
-		cache:  newTcbCache(serviceStore, logger),
-		logger: logger,
+		tcbStatusWatcher: newTCBStatusWatcher(client, logger),
+		client:           client,
+		cache:            newTcbCache(serviceStore, logger),
+		logger:           logger,
 	}
 }
 
@@ -160,6 +166,18 @@ func (qs *cachingQuoteService) ResolveQuote(ctx context.Context, rawQuote []byte
 		return nil, err
 	}
 
+	// Watch the platform TCB status so that TCB status changes get noticed early.
+	platform := PlatformInfo{
+		TeeType:    quote.Header().TeeType(),
+		FMSPC:      pckInfo.FMSPC,
+		TCBCompSVN: pckInfo.TCBCompSVN,
+		PCESVN:     pckInfo.PCESVN,
+	}
+	if report, ok := quote.reportBody.(*TdReport); ok {
+		platform.TDXCompSVN = &report.teeTcbSvn
+	}
+	qs.setPlatform(&platform, quotePolicy)
+
 	// Prepare quote structure.
 	return &QuoteBundle{
 		Quote: rawQuote[:size], // Trim quote as it may contain extra data.
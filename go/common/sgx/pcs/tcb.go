@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -56,6 +57,13 @@ func (bnd *TCBBundle) Verify(
 	pcesvn uint16,
 	qe *SgxReport,
 ) error {
+	// Skip verification in case the same platform TCB has already been successfully verified
+	// against the same collateral and the collateral is still valid.
+	key := bnd.verificationKey(teeType, policy, fmspc, sgxCompSvn, tdxCompSvn, pcesvn, qe)
+	if verificationCache.lookup(key, ts) {
+		return nil
+	}
+
 	pk, err := bnd.getPublicKey(ts)
 	if err != nil {
 		return err
@@ -68,9 +76,54 @@ func (bnd *TCBBundle) Verify(
 	if err != nil {
 		return fmt.Errorf("pcs/tcb: failed to verify TCB info: %w", err)
 	}
+
+	if window, err := bnd.verificationWindow(policy); err == nil {
+		verificationCache.insert(key, *window, ts)
+	}
 	return nil
 }
 
+// PlatformStatus evaluates the TCB status of the given platform against the TCB bundle.
+//
+// In contrast to Verify, a platform with a TCB status that is not up to date is not treated as
+// an error, so that the caller can observe TCB status changes.
+func (bnd *TCBBundle) PlatformStatus(ts time.Time, policy *QuotePolicy, platform *PlatformInfo) (*PlatformTCBStatus, error) {
+	pk, err := bnd.getPublicKey(ts)
+	if err != nil {
+		return nil, err
+	}
+	tcbInfo, err := bnd.TCBInfo.open(platform.TeeType, ts, policy, pk)
+	if err != nil {
+		return nil, fmt.Errorf("pcs/tcb: invalid TCB info: %w", err)
+	}
+	if err = tcbInfo.validateFMSPC(platform.FMSPC); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: failed to validate FMSPC: %w", err)
+	}
+
+	status := PlatformTCBStatus{
+		TeeType:                 platform.TeeType,
+		FMSPC:                   platform.FMSPC,
+		TCBEvaluationDataNumber: tcbInfo.TCBEvaluationDataNumber,
+		Timestamp:               ts,
+	}
+
+	tcbLevel, err := tcbInfo.getTCBLevel(platform.TCBCompSVN, platform.TDXCompSVN, platform.PCESVN)
+	var tcbErr *TCBOutOfDateError
+	switch {
+	case err == nil:
+		status.Kind = TCBKindPlatform
+		status.Status = tcbLevel.Status
+		status.AdvisoryIDs = tcbLevel.AdvisoryIDs
+	case errors.As(err, &tcbErr):
+		status.Kind = tcbErr.Kind
+		status.Status = tcbErr.Status
+		status.AdvisoryIDs = tcbErr.AdvisoryIDs
+	default:
+		return nil, fmt.Errorf("pcs/tcb: failed to get TCB level: %w", err)
+	}
+	return &status, nil
+}
+
 // verifyQEIdentity verifies the QE identity.
 func (bnd *TCBBundle) verifyQEIdentity(
 	teeType TeeType,
@@ -0,0 +1,217 @@
+package pcs
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// maxVerificationCacheEntries is the maximum number of entries in the TCB verification cache.
+const maxVerificationCacheEntries = 256
+
+// verificationCache is the process-wide TCB bundle verification cache.
+var verificationCache = newTCBVerificationCache(maxVerificationCacheEntries)
+
+// tcbVerificationKey is the key under which a successful TCB bundle verification is cached.
+//
+// The key covers everything that the verification outcome depends on, apart from the
+// verification timestamp which is handled by the validity window of the cached entry.
+type tcbVerificationKey struct {
+	TeeType    TeeType   `json:"tee_type"`
+	FMSPC      []byte    `json:"fmspc"`
+	SGXCompSVN [16]int32 `json:"sgx_comp_svn"`
+	TDXCompSVN *[16]byte `json:"tdx_comp_svn,omitempty"`
+	PCESVN     uint16    `json:"pcesvn"`
+
+	// QEReport is the hash of the raw QE report.
+	QEReport hash.Hash `json:"qe_report"`
+	// Collateral is the hash of the TCB bundle.
+	Collateral hash.Hash `json:"collateral"`
+
+	TCBValidityPeriod          uint16   `json:"tcb_validity_period"`
+	MinTCBEvaluationDataNumber uint32   `json:"min_tcb_evaluation_data_number"`
+	FMSPCBlacklist             []string `json:"fmspc_blacklist,omitempty"`
+	LaxVerify                  bool     `json:"lax_verify,omitempty"`
+}
+
+// tcbVerificationWindow is the time window in which a cached verification is valid.
+type tcbVerificationWindow struct {
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// contains returns true iff the given timestamp is within the window (inclusive).
+func (w *tcbVerificationWindow) contains(ts time.Time) bool {
+	return !ts.Before(w.notBefore) && !ts.After(w.notAfter)
+}
+
+// tcbVerificationCache is a cache of successful TCB bundle verifications.
+//
+// Verifying the TCB bundle requires verifying the TCB info certificate chain and the ECDSA
+// signatures over both the TCB info and the QE identity, which is repeated for every quote
+// coming from the same platform. Since the outcome only depends on the platform TCB level
+// and the collateral, successful verifications can be cached for as long as the collateral
+// remains valid.
+type tcbVerificationCache struct {
+	sync.Mutex
+
+	entries    map[hash.Hash]tcbVerificationWindow
+	maxEntries int
+}
+
+func newTCBVerificationCache(maxEntries int) *tcbVerificationCache {
+	return &tcbVerificationCache{
+		entries:    make(map[hash.Hash]tcbVerificationWindow),
+		maxEntries: maxEntries,
+	}
+}
+
+// lookup returns true iff there is a cached successful verification for the given key that
+// is valid at the given timestamp.
+func (c *tcbVerificationCache) lookup(key hash.Hash, ts time.Time) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	window, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if !window.contains(ts) {
+		// Collateral has expired (or is not yet valid) at the given time, so the bundle must
+		// be verified again. Verification with an older timestamp can re-insert the entry.
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// insert caches a successful verification for the given key.
+func (c *tcbVerificationCache) insert(key hash.Hash, window tcbVerificationWindow, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		// Prune expired entries first and if that is not enough, evict the entry that
+		// expires first.
+		var (
+			evictKey   hash.Hash
+			evictAfter time.Time
+		)
+		for k, w := range c.entries {
+			if now.After(w.notAfter) {
+				delete(c.entries, k)
+				continue
+			}
+			if evictAfter.IsZero() || w.notAfter.Before(evictAfter) {
+				evictKey, evictAfter = k, w.notAfter
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, evictKey)
+		}
+	}
+	c.entries[key] = window
+}
+
+// clear removes all entries from the cache.
+func (c *tcbVerificationCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.entries = make(map[hash.Hash]tcbVerificationWindow)
+}
+
+// len returns the number of entries in the cache.
+func (c *tcbVerificationCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.entries)
+}
+
+// verificationKey derives the verification cache key for the given verification inputs.
+func (bnd *TCBBundle) verificationKey(
+	teeType TeeType,
+	policy *QuotePolicy,
+	fmspc []byte,
+	sgxCompSvn [16]int32,
+	tdxCompSvn *[16]byte,
+	pcesvn uint16,
+	qe *SgxReport,
+) hash.Hash {
+	key := tcbVerificationKey{
+		TeeType:                    teeType,
+		FMSPC:                      fmspc,
+		SGXCompSVN:                 sgxCompSvn,
+		TDXCompSVN:                 tdxCompSvn,
+		PCESVN:                     pcesvn,
+		QEReport:                   hash.NewFromBytes(qe.raw),
+		Collateral:                 hash.NewFromBytes(cbor.Marshal(bnd)),
+		TCBValidityPeriod:          policy.TCBValidityPeriod,
+		MinTCBEvaluationDataNumber: policy.MinTCBEvaluationDataNumber,
+		FMSPCBlacklist:             policy.FMSPCBlacklist,
+		LaxVerify:                  unsafeLaxVerify,
+	}
+	return hash.NewFrom(key)
+}
+
+// verificationWindow computes the time window in which the TCB bundle collateral is valid
+// under the given policy.
+func (bnd *TCBBundle) verificationWindow(policy *QuotePolicy) (*tcbVerificationWindow, error) {
+	var window tcbVerificationWindow
+	narrow := func(notBefore, notAfter time.Time) {
+		if window.notBefore.IsZero() || notBefore.After(window.notBefore) {
+			window.notBefore = notBefore
+		}
+		if window.notAfter.IsZero() || notAfter.Before(window.notAfter) {
+			window.notAfter = notAfter
+		}
+	}
+
+	// Certificate chain validity.
+	data := bnd.Certificates
+	for len(data) > 0 {
+		var (
+			cert *x509.Certificate
+			err  error
+		)
+		if cert, data, err = CertFromPEM(data); err != nil {
+			return nil, fmt.Errorf("pcs/tcb: bad X509 certificate in TCB bundle: %w", err)
+		}
+		if cert == nil {
+			break
+		}
+		narrow(cert.NotBefore, cert.NotAfter)
+	}
+
+	// TCB info and QE identity validity.
+	validity := time.Duration(policy.TCBValidityPeriod) * 24 * time.Hour
+	var tcbInfo TCBInfo
+	if err := json.Unmarshal(bnd.TCBInfo.TCBInfo, &tcbInfo); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: malformed TCB info body: %w", err)
+	}
+	issueDate, err := time.Parse(TimestampFormat, tcbInfo.IssueDate)
+	if err != nil {
+		return nil, fmt.Errorf("pcs/tcb: invalid issue date: %w", err)
+	}
+	narrow(issueDate, issueDate.Add(validity))
+
+	var qeIdentity QEIdentity
+	if err = json.Unmarshal(bnd.QEIdentity.EnclaveIdentity, &qeIdentity); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: malformed QE identity body: %w", err)
+	}
+	if issueDate, err = time.Parse(TimestampFormat, qeIdentity.IssueDate); err != nil {
+		return nil, fmt.Errorf("pcs/tcb: invalid issue date: %w", err)
+	}
+	narrow(issueDate, issueDate.Add(validity))
+
+	if window.notAfter.Before(window.notBefore) {
+		return nil, fmt.Errorf("pcs/tcb: empty collateral validity window")
+	}
+	return &window, nil
+}
@@ -0,0 +1,139 @@
+package pcs
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+func loadTestQuoteAndBundle(t *testing.T) (*Quote, *TCBBundle) {
+	require := require.New(t)
+
+	rawQuote, err := os.ReadFile("testdata/quote_v3_ecdsa_p256_pck_chain.bin")
+	require.NoError(err, "Read test vector")
+	var quote Quote
+	err = quote.UnmarshalBinary(rawQuote)
+	require.NoError(err, "Parse quote")
+
+	rawTCBInfo, err := os.ReadFile("testdata/tcb_info_v3_fmspc_00606A000000.json")
+	require.NoError(err, "Read test vector")
+	rawCerts, err := os.ReadFile("testdata/tcb_info_v3_fmspc_00606A000000_certs.pem")
+	require.NoError(err, "Read test vector")
+	rawQEIdentity, err := os.ReadFile("testdata/qe_identity_v2.json")
+	require.NoError(err, "Read test vector")
+
+	var tcbInfo SignedTCBInfo
+	err = json.Unmarshal(rawTCBInfo, &tcbInfo)
+	require.NoError(err, "Parse TCB info")
+	var qeIdentity SignedQEIdentity
+	err = json.Unmarshal(rawQEIdentity, &qeIdentity)
+	require.NoError(err, "Parse QE identity")
+
+	return &quote, &TCBBundle{
+		TCBInfo:      tcbInfo,
+		QEIdentity:   qeIdentity,
+		Certificates: rawCerts,
+	}
+}
+
+func TestTCBVerificationCache(t *testing.T) {
+	require := require.New(t)
+
+	verificationCache.clear()
+	t.Cleanup(verificationCache.clear)
+
+	quote, bundle := loadTestQuoteAndBundle(t)
+	policy := &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: DefaultMinTCBEvaluationDataNumber,
+	}
+	now := time.Unix(1671497404, 0)
+
+	// Successful verification should be cached.
+	_, err := quote.Verify(policy, now, bundle)
+	require.NoError(err, "Verify")
+	require.Equal(1, verificationCache.len(), "successful verification should be cached")
+
+	sig := quote.signature.(*QuoteSignatureECDSA_P256)
+	pckInfo, err := sig.VerifyPCK(now)
+	require.NoError(err, "VerifyPCK")
+	key := bundle.verificationKey(TeeTypeSGX, policy, pckInfo.FMSPC, pckInfo.TCBCompSVN, nil, pckInfo.PCESVN, &sig.qe.QEReport)
+	require.True(verificationCache.lookup(key, now), "cached verification should be found")
+
+	// The validity window should be bounded by the validity periods of both the TCB info and the QE identity.
+	window, err := bundle.verificationWindow(policy)
+	require.NoError(err, "verificationWindow")
+	require.Equal(time.Date(2022, 12, 19, 9, 40, 10, 0, time.UTC), window.notBefore)
+	require.Equal(time.Date(2023, 1, 15, 12, 45, 36, 0, time.UTC), window.notAfter) // QE identity issued earlier.
+	require.True(verificationCache.lookup(key, window.notAfter), "cached verification should be valid at the end of the window")
+
+	// Cached verification should be keyed by the policy.
+	strictPolicy := &QuotePolicy{
+		TCBValidityPeriod:          30,
+		MinTCBEvaluationDataNumber: 14,
+	}
+	strictKey := bundle.verificationKey(TeeTypeSGX, strictPolicy, pckInfo.FMSPC, pckInfo.TCBCompSVN, nil, pckInfo.PCESVN, &sig.qe.QEReport)
+	require.False(verificationCache.lookup(strictKey, now), "verification under a different policy should not be cached")
+	_, err = quote.Verify(strictPolicy, now, bundle)
+	require.ErrorContains(err, "invalid QE evaluation data number")
+
+	// Cached verification should be keyed by the platform TCB components.
+	compSvn := pckInfo.TCBCompSVN
+	compSvn[0]--
+	otherKey := bundle.verificationKey(TeeTypeSGX, policy, pckInfo.FMSPC, compSvn, nil, pckInfo.PCESVN, &sig.qe.QEReport)
+	require.False(verificationCache.lookup(otherKey, now), "verification of a different TCB level should not be cached")
+
+	// Once the collateral expires, the entry should be invalidated and verification should fail.
+	expired := window.notAfter.Add(time.Second)
+	require.False(verificationCache.lookup(key, expired), "expired verification should not be found")
+	require.Equal(0, verificationCache.len(), "expired verification should be removed")
+	_, err = quote.Verify(policy, expired, bundle)
+	require.ErrorContains(err, "expired")
+	require.Equal(0, verificationCache.len(), "failed verification should not be cached")
+
+	// Verification before the collateral is valid should not be served from the cache.
+	_, err = quote.Verify(policy, now, bundle)
+	require.NoError(err, "Verify")
+	require.False(verificationCache.lookup(key, window.notBefore.Add(-time.Second)), "verification before the window should not be found")
+}
+
+func TestTCBVerificationCacheEviction(t *testing.T) {
+	require := require.New(t)
+
+	cache := newTCBVerificationCache(2)
+	now := time.Unix(1671497404, 0)
+	window := func(d time.Duration) tcbVerificationWindow {
+		return tcbVerificationWindow{
+			notBefore: now.Add(-time.Hour),
+			notAfter:  now.Add(d),
+		}
+	}
+
+	key1 := hash.NewFromBytes([]byte("key1"))
+	key2 := hash.NewFromBytes([]byte("key2"))
+	key3 := hash.NewFromBytes([]byte("key3"))
+	key4 := hash.NewFromBytes([]byte("key4"))
+
+	cache.insert(key1, window(2*time.Hour), now)
+	cache.insert(key2, window(time.Hour), now)
+	require.Equal(2, cache.len())
+
+	// The entry which expires first should be evicted.
+	cache.insert(key3, window(3*time.Hour), now)
+	require.Equal(2, cache.len())
+	require.True(cache.lookup(key1, now))
+	require.False(cache.lookup(key2, now))
+	require.True(cache.lookup(key3, now))
+
+	// Expired entries should be pruned first.
+	later := now.Add(150 * time.Minute)
+	cache.insert(key4, window(4*time.Hour), later)
+	require.Equal(2, cache.len())
+	require.True(cache.lookup(key3, later))
+	require.True(cache.lookup(key4, later))
+}
@@ -0,0 +1,211 @@
+package pcs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+)
+
+// tcbStatusCheckInterval is the interval between platform TCB status checks.
+const tcbStatusCheckInterval = 6 * time.Hour
+
+// PlatformInfo is the information about the platform needed to evaluate its TCB status.
+type PlatformInfo struct {
+	// TeeType is the TEE type of the platform.
+	TeeType TeeType
+	// FMSPC is the FMSPC of the platform.
+	FMSPC []byte
+	// TCBCompSVN are the SGX TCB component SVNs of the platform.
+	TCBCompSVN [16]int32
+	// TDXCompSVN are the TEE TCB SVNs of the platform in case of TDX.
+	TDXCompSVN *[16]byte
+	// PCESVN is the PCE SVN of the platform.
+	PCESVN uint16
+}
+
+// PlatformTCBStatus is the TCB status of a platform.
+type PlatformTCBStatus struct {
+	// TeeType is the TEE type of the platform.
+	TeeType TeeType `json:"tee_type"`
+	// FMSPC is the FMSPC of the platform.
+	FMSPC []byte `json:"fmspc"`
+	// Kind is the kind of the TCB that determined the status.
+	Kind TCBKind `json:"kind"`
+	// Status is the TCB status.
+	Status TCBStatus `json:"status"`
+	// AdvisoryIDs are the advisory identifiers for the TCB level.
+	AdvisoryIDs []string `json:"advisory_ids,omitempty"`
+	// TCBEvaluationDataNumber is the TCB evaluation data number of the TCB info.
+	TCBEvaluationDataNumber uint32 `json:"tcb_evaluation_data_number"`
+	// Timestamp is the time of the evaluation.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IsUpToDate returns true iff the TCB status does not require any action from the operator.
+func (s *PlatformTCBStatus) IsUpToDate() bool {
+	switch s.Status {
+	case StatusUpToDate, StatusSWHardeningNeeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// TCBStatusWatcher is an interface for watching the TCB status of the local platform.
+type TCBStatusWatcher interface {
+	// TCBStatus returns the last known TCB status of the local platform.
+	//
+	// In case the status is not yet known, nil is returned.
+	TCBStatus() *PlatformTCBStatus
+
+	// WatchTCBStatus returns a channel that produces a stream of platform TCB status changes.
+	WatchTCBStatus() (<-chan *PlatformTCBStatus, pubsub.ClosableSubscription)
+
+	// Stop stops watching the TCB status.
+	Stop()
+}
+
+// tcbStatusWatcher periodically re-evaluates the TCB status of the local platform against fresh
+// TCB info so that operators notice TCB status changes before registrations start failing.
+type tcbStatusWatcher struct {
+	sync.Mutex
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	client   Client
+	interval time.Duration
+	now      func() time.Time
+
+	startOnce sync.Once
+	platform  *PlatformInfo
+	policy    *QuotePolicy
+	status    *PlatformTCBStatus
+
+	notifier *pubsub.Broker
+	logger   *logging.Logger
+}
+
+// setPlatform configures the platform and policy under which the TCB status is evaluated and
+// starts the watcher in case it is not yet running.
+func (w *tcbStatusWatcher) setPlatform(platform *PlatformInfo, policy *QuotePolicy) {
+	w.Lock()
+	w.platform = platform
+	w.policy = policy
+	w.Unlock()
+
+	w.startOnce.Do(func() {
+		go w.worker(w.ctx)
+	})
+}
+
+// Stop implements TCBStatusWatcher.
+func (w *tcbStatusWatcher) Stop() {
+	w.cancel()
+}
+
+// TCBStatus implements TCBStatusWatcher.
+func (w *tcbStatusWatcher) TCBStatus() *PlatformTCBStatus {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.status
+}
+
+// WatchTCBStatus implements TCBStatusWatcher.
+func (w *tcbStatusWatcher) WatchTCBStatus() (<-chan *PlatformTCBStatus, pubsub.ClosableSubscription) {
+	typedCh := make(chan *PlatformTCBStatus)
+	sub := w.notifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
+
+func (w *tcbStatusWatcher) worker(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check fetches fresh TCB info and re-evaluates the platform TCB status.
+func (w *tcbStatusWatcher) check(ctx context.Context) {
+	w.Lock()
+	platform, policy := w.platform, w.policy
+	w.Unlock()
+
+	if policy == nil {
+		policy = defaultQuotePolicy()
+	}
+
+	bundle, err := w.client.GetTCBBundle(ctx, platform.TeeType, platform.FMSPC, UpdateStandard)
+	if err != nil {
+		w.logger.Warn("failed to fetch TCB bundle for TCB status check",
+			"err", err,
+		)
+		return
+	}
+	status, err := bundle.PlatformStatus(w.now(), policy, platform)
+	if err != nil {
+		w.logger.Warn("failed to evaluate platform TCB status",
+			"err", err,
+		)
+		return
+	}
+
+	w.updateStatus(status)
+}
+
+// updateStatus updates the platform TCB status and notifies watchers on changes.
+func (w *tcbStatusWatcher) updateStatus(status *PlatformTCBStatus) {
+	w.Lock()
+	prev := w.status
+	w.status = status
+	w.Unlock()
+
+	if prev != nil && prev.Status == status.Status && prev.Kind == status.Kind {
+		return
+	}
+
+	if status.IsUpToDate() {
+		w.logger.Info("platform TCB status changed",
+			"fmspc", status.FMSPC,
+			"kind", status.Kind,
+			"tcb_status", status.Status.String(),
+		)
+	} else {
+		w.logger.Warn("platform TCB is not up to date, registrations may start failing",
+			"fmspc", status.FMSPC,
+			"kind", status.Kind,
+			"tcb_status", status.Status.String(),
+			"advisory_ids", status.AdvisoryIDs,
+			"tcb_evaluation_data_number", status.TCBEvaluationDataNumber,
+		)
+	}
+
+	w.notifier.Broadcast(status)
+}
+
+func newTCBStatusWatcher(client Client, logger *logging.Logger) *tcbStatusWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &tcbStatusWatcher{
+		ctx:      ctx,
+		cancel:   cancel,
+		client:   client,
+		interval: tcbStatusCheckInterval,
+		now:      time.Now,
+		notifier: pubsub.NewBroker(true),
+		logger:   logger,
+	}
+}
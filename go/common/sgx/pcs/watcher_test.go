@@ -0,0 +1,91 @@
+package pcs
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+type testTCBClient struct {
+	bundle *TCBBundle
+}
+
+func (c *testTCBClient) GetTCBBundle(context.Context, TeeType, []byte, UpdateType) (*TCBBundle, error) {
+	return c.bundle, nil
+}
+
+func (c *testTCBClient) GetPCKCertificateChain(context.Context, []byte, [384]byte, [16]byte, uint16, uint16) ([]*x509.Certificate, error) {
+	return nil, nil
+}
+
+func TestTCBStatusWatcher(t *testing.T) {
+	require := require.New(t)
+
+	quote, bundle := loadTestQuoteAndBundle(t)
+	now := time.Unix(1671497404, 0)
+
+	sig := quote.signature.(*QuoteSignatureECDSA_P256)
+	pckInfo, err := sig.VerifyPCK(now)
+	require.NoError(err, "VerifyPCK")
+
+	w := newTCBStatusWatcher(&testTCBClient{bundle}, logging.GetLogger(loggerModule))
+	w.now = func() time.Time { return now }
+	ch, sub := w.WatchTCBStatus()
+	defer sub.Close()
+
+	// Status is unknown before the first check.
+	require.Nil(w.TCBStatus())
+
+	// Platform at the latest TCB level.
+	platform := &PlatformInfo{
+		TeeType:    TeeTypeSGX,
+		FMSPC:      pckInfo.FMSPC,
+		TCBCompSVN: pckInfo.TCBCompSVN,
+		PCESVN:     pckInfo.PCESVN,
+	}
+	w.platform = platform
+	w.check(context.Background())
+
+	status := w.TCBStatus()
+	require.NotNil(status)
+	require.Equal(StatusSWHardeningNeeded, status.Status)
+	require.True(status.IsUpToDate())
+	require.EqualValues(13, status.TCBEvaluationDataNumber)
+	require.Equal(status, <-ch, "status change should be broadcast")
+
+	// Unchanged status should not be broadcast.
+	w.check(context.Background())
+	select {
+	case <-ch:
+		t.Fatalf("unchanged status should not be broadcast")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Platform at an older TCB level.
+	w.platform = &PlatformInfo{
+		TeeType:    TeeTypeSGX,
+		FMSPC:      pckInfo.FMSPC,
+		TCBCompSVN: [16]int32{4, 4, 3, 3, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		PCESVN:     11,
+	}
+	w.check(context.Background())
+
+	status = w.TCBStatus()
+	require.Equal(StatusOutOfDate, status.Status)
+	require.False(status.IsUpToDate())
+	require.Equal(status, <-ch, "status change should be broadcast")
+
+	// Failed evaluation (e.g. expired TCB info) should keep the last known status.
+	w.now = func() time.Time { return now.Add(60 * 24 * time.Hour) }
+	w.check(context.Background())
+	require.Equal(status, w.TCBStatus())
+
+	// Stopping the watcher should stop the worker.
+	w.Stop()
+	require.ErrorIs(w.ctx.Err(), context.Canceled)
+}
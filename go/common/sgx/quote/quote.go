@@ -2,6 +2,7 @@ package quote
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
@@ -59,6 +60,38 @@ func (q *Quote) Verify(policy *Policy, ts time.Time) (*sgx.VerifiedQuote, error)
 	}
 }
 
+// ExplainPolicyFailure verifies the SGX remote attestation quote against the given policy and
+// allowed enclave identities and explains which policy rule failed.
+//
+// In case the quote satisfies the policy, nil is returned.
+func (q *Quote) ExplainPolicyFailure(policy *Policy, ts time.Time, enclaves []sgx.EnclaveIdentity) *pcs.QuotePolicyFailure {
+	if policy == nil {
+		policy = &Policy{}
+	}
+
+	if q.PCS != nil && q.IAS == nil {
+		return pcs.ExplainQuotePolicyFailure(q.PCS, policy.PCS, ts, enclaves)
+	}
+
+	// IAS quotes do not support detailed explanations.
+	verifiedQuote, err := q.Verify(policy, ts)
+	if err != nil {
+		return &pcs.QuotePolicyFailure{
+			Rule:   pcs.RuleQuoteSignature,
+			Reason: "quote verification failed",
+			Err:    err,
+		}
+	}
+	if enclaves != nil && !slices.Contains(enclaves, verifiedQuote.Identity) {
+		return &pcs.QuotePolicyFailure{
+			Rule:     pcs.RuleEnclaveIdentity,
+			Reason:   "enclave identity is not among the allowed enclaves",
+			Identity: &verifiedQuote.Identity,
+		}
+	}
+	return nil
+}
+
 // Policy is the quote validity policy.
 type Policy struct {
 	IAS *ias.QuotePolicy `json:"ias,omitempty" yaml:"ias,omitempty"`
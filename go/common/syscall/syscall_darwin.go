@@ -6,6 +6,9 @@ import "syscall"
 // IoctlTermiosGetAttr is the ioctl that implements termios tcgetattr.
 const IoctlTermiosGetAttr = syscall.TIOCGETA
 
+// IoctlTermiosSetAttr is the ioctl that implements termios tcsetattr.
+const IoctlTermiosSetAttr = syscall.TIOCSETA
+
 // CmdAttrs is the SysProcAttr used for spawning child processes. It is empty
 // for Darwin as PR_SET_PDEATH_SIG is not implemented. As a consequence, child
 // processes may not be cleaned up.
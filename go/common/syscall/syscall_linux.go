@@ -6,6 +6,9 @@ import "syscall"
 // IoctlTermiosGetAttr is the ioctl that implements termios tcgetattr.
 const IoctlTermiosGetAttr = syscall.TCGETS
 
+// IoctlTermiosSetAttr is the ioctl that implements termios tcsetattr.
+const IoctlTermiosSetAttr = syscall.TCSETS
+
 // CmdAttrs is the SysProcAttr that will ensure graceful cleanup (on Linux).
 var CmdAttrs = &syscall.SysProcAttr{
 	Pdeathsig: syscall.SIGKILL,
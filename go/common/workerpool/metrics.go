@@ -0,0 +1,45 @@
+package workerpool
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolQueueTime = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_workerpool_queue_time",
+			Help: "Time jobs spent queued before being started (seconds).",
+		},
+		[]string{"pool", "class"},
+	)
+	poolQueuedJobs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_workerpool_queued_jobs",
+			Help: "Number of jobs waiting to be started.",
+		},
+		[]string{"pool", "class"},
+	)
+	poolRunningJobs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_workerpool_running_jobs",
+			Help: "Number of running jobs.",
+		},
+		[]string{"pool", "class"},
+	)
+
+	poolCollectors = []prometheus.Collector{
+		poolQueueTime,
+		poolQueuedJobs,
+		poolRunningJobs,
+	}
+
+	metricsOnce sync.Once
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(poolCollectors...)
+	})
+}
@@ -0,0 +1,371 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// ErrPoolStopped is the error returned when submitting work to a stopped pool.
+var ErrPoolStopped = errors.New("workerpool: pool stopped")
+
+// Class is the priority class of a job submitted to a priority pool.
+//
+// Classes with lower values have higher priority.
+type Class uint8
+
+const (
+	// ClassCritical is the class of latency-sensitive work required for timely participation in
+	// the protocol (e.g., commitment verification).
+	ClassCritical Class = iota
+	// ClassNormal is the class of regular work (e.g., storage finalization).
+	ClassNormal
+	// ClassBackground is the class of work that can be arbitrarily delayed (e.g., checkpoint
+	// creation).
+	ClassBackground
+
+	numClasses
+)
+
+// Classes returns all priority classes ordered by decreasing priority.
+func Classes() []Class {
+	return []Class{ClassCritical, ClassNormal, ClassBackground}
+}
+
+// String returns a string representation of the priority class.
+func (c Class) String() string {
+	switch c {
+	case ClassCritical:
+		return "critical"
+	case ClassNormal:
+		return "normal"
+	case ClassBackground:
+		return "background"
+	default:
+		return fmt.Sprintf("[unknown class: %d]", uint8(c))
+	}
+}
+
+// MarshalText encodes a priority class into text form.
+func (c Class) MarshalText() ([]byte, error) {
+	if c >= numClasses {
+		return nil, fmt.Errorf("workerpool: unknown class: %d", uint8(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a text slice into a priority class.
+func (c *Class) UnmarshalText(text []byte) error {
+	for _, class := range Classes() {
+		if string(text) == class.String() {
+			*c = class
+			return nil
+		}
+	}
+	return fmt.Errorf("workerpool: unknown class: %s", string(text))
+}
+
+// Limits are the concurrency limits of a priority pool.
+type Limits struct {
+	// Global is the maximum number of jobs running concurrently across all classes.
+	Global uint `json:"global"`
+	// Classes are the maximum numbers of concurrently running jobs of individual classes. Classes
+	// without a limit are only bounded by the global limit.
+	Classes map[Class]uint `json:"classes,omitempty"`
+}
+
+// Validate validates the concurrency limits.
+func (l *Limits) Validate() error {
+	if l.Global == 0 {
+		return fmt.Errorf("workerpool: global limit must be greater than zero")
+	}
+	for class, limit := range l.Classes {
+		if class >= numClasses {
+			return fmt.Errorf("workerpool: unknown class: %d", uint8(class))
+		}
+		if limit == 0 {
+			return fmt.Errorf("workerpool: limit of class %s must be greater than zero", class)
+		}
+	}
+	return nil
+}
+
+func (l *Limits) classLimit(class Class) uint {
+	if limit, ok := l.Classes[class]; ok {
+		return min(limit, l.Global)
+	}
+	return l.Global
+}
+
+func (l *Limits) clone() Limits {
+	classes := make(map[Class]uint, len(l.Classes))
+	for class, limit := range l.Classes {
+		classes[class] = limit
+	}
+	return Limits{
+		Global:  l.Global,
+		Classes: classes,
+	}
+}
+
+// DefaultLimits returns the default concurrency limits based on the number of available CPUs.
+//
+// Normal and background work is capped so that some capacity always remains available for
+// critical work.
+func DefaultLimits() Limits {
+	numCPU := uint(runtime.NumCPU())
+	normal := max(numCPU/2, 1)
+	background := max(numCPU/4, 1)
+	return Limits{
+		Global: max(numCPU, normal+background+1),
+		Classes: map[Class]uint{
+			ClassNormal:     normal,
+			ClassBackground: background,
+		},
+	}
+}
+
+type priorityJob struct {
+	class       Class
+	job         func()
+	submittedAt time.Time
+	completeCh  chan struct{}
+	cancelled   bool
+}
+
+// PriorityPool is a pool of goroutine workers executing jobs of different priority classes.
+//
+// Queued jobs of higher priority classes are always started before jobs of lower priority
+// classes, subject to the per-class and global concurrency limits. Jobs of the same class are
+// started in submission order.
+type PriorityPool struct {
+	mu sync.Mutex
+
+	name   string
+	limits Limits
+
+	queues   [numClasses][]*priorityJob
+	running  [numClasses]uint
+	total    uint
+	stopped  bool
+	stopCh   chan struct{}
+	jobGroup sync.WaitGroup
+
+	logger *logging.Logger
+}
+
+// NewPriorityPool creates a new priority pool with the given concurrency limits.
+func NewPriorityPool(name string, limits Limits) (*PriorityPool, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	initMetrics()
+
+	return &PriorityPool{
+		name:   name,
+		limits: limits.clone(),
+		stopCh: make(chan struct{}),
+		logger: logging.GetLogger(fmt.Sprintf("workerpool/%s", name)),
+	}, nil
+}
+
+// Limits returns the current concurrency limits.
+func (p *PriorityPool) Limits() Limits {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.limits.clone()
+}
+
+// SetLimits changes the concurrency limits.
+//
+// Jobs that are already running are not affected, but no new jobs are started until the number
+// of running jobs drops below the new limits.
+func (p *PriorityPool) SetLimits(limits Limits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.limits = limits.clone()
+	p.logger.Info("concurrency limits changed",
+		"global", limits.Global,
+		"classes", limits.Classes,
+	)
+	p.dispatchLocked()
+
+	return nil
+}
+
+// Submit adds a job of the given class to the pool's queue and returns a channel that will be
+// closed once the job is complete.
+//
+// In case the pool is stopped, nil is returned.
+func (p *PriorityPool) Submit(class Class, job func()) <-chan struct{} {
+	pj := p.submit(class, job)
+	if pj == nil {
+		return nil
+	}
+	return pj.completeCh
+}
+
+// Run submits a job of the given class and waits for it to complete.
+//
+// In case the context is canceled before the job is started, the job is not executed at all.
+func (p *PriorityPool) Run(ctx context.Context, class Class, job func()) error {
+	pj := p.submit(class, job)
+	if pj == nil {
+		return ErrPoolStopped
+	}
+
+	select {
+	case <-pj.completeCh:
+		return nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		pj.cancelled = true
+		p.mu.Unlock()
+		return ctx.Err()
+	case <-p.stopCh:
+		return ErrPoolStopped
+	}
+}
+
+func (p *PriorityPool) submit(class Class, job func()) *priorityJob {
+	if class >= numClasses {
+		panic(fmt.Sprintf("workerpool/%s: unknown class: %d", p.name, uint8(class)))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return nil
+	}
+
+	pj := &priorityJob{
+		class:       class,
+		job:         job,
+		submittedAt: time.Now(),
+		completeCh:  make(chan struct{}),
+	}
+	p.queues[class] = append(p.queues[class], pj)
+	poolQueuedJobs.With(p.metricLabels(class)).Inc()
+
+	p.dispatchLocked()
+
+	return pj
+}
+
+// Stop discards all queued jobs and waits for the running jobs to complete.
+//
+// The pool must not be used for any further jobs after calling this method.
+func (p *PriorityPool) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.stopCh)
+
+		for class := range p.queues {
+			poolQueuedJobs.With(p.metricLabels(Class(class))).Sub(float64(len(p.queues[class])))
+			p.queues[class] = nil
+		}
+	}
+	p.mu.Unlock()
+
+	p.jobGroup.Wait()
+}
+
+func (p *PriorityPool) dispatchLocked() {
+	for p.total < p.limits.Global {
+		pj := p.nextLocked()
+		if pj == nil {
+			return
+		}
+		p.startLocked(pj)
+	}
+}
+
+func (p *PriorityPool) nextLocked() *priorityJob {
+	for _, class := range Classes() {
+		if p.running[class] >= p.limits.classLimit(class) {
+			continue
+		}
+
+		for len(p.queues[class]) > 0 {
+			pj := p.queues[class][0]
+			p.queues[class][0] = nil
+			p.queues[class] = p.queues[class][1:]
+			poolQueuedJobs.With(p.metricLabels(class)).Dec()
+
+			if pj.cancelled {
+				continue
+			}
+			return pj
+		}
+	}
+	return nil
+}
+
+func (p *PriorityPool) startLocked(pj *priorityJob) {
+	labels := p.metricLabels(pj.class)
+	poolQueueTime.With(labels).Observe(time.Since(pj.submittedAt).Seconds())
+	poolRunningJobs.With(labels).Inc()
+
+	p.running[pj.class]++
+	p.total++
+	p.jobGroup.Add(1)
+
+	go func() {
+		defer p.jobGroup.Done()
+
+		pj.job()
+
+		p.mu.Lock()
+		p.running[pj.class]--
+		p.total--
+		poolRunningJobs.With(labels).Dec()
+		close(pj.completeCh)
+		if !p.stopped {
+			p.dispatchLocked()
+		}
+		p.mu.Unlock()
+	}()
+}
+
+func (p *PriorityPool) metricLabels(class Class) prometheus.Labels {
+	return prometheus.Labels{
+		"pool":  p.name,
+		"class": class.String(),
+	}
+}
+
+var (
+	sharedPool     *PriorityPool
+	sharedPoolOnce sync.Once
+)
+
+// Shared returns the process-wide priority pool used to coordinate resource-intensive work of
+// different subsystems.
+//
+// The shared pool is created with the default limits and is never stopped.
+func Shared() *PriorityPool {
+	sharedPoolOnce.Do(func() {
+		var err error
+		sharedPool, err = NewPriorityPool("shared", DefaultLimits())
+		if err != nil {
+			panic(err)
+		}
+	})
+	return sharedPool
+}
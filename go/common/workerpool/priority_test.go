@@ -0,0 +1,248 @@
+package workerpool
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (p *PriorityPool) numRunning(class Class) uint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running[class]
+}
+
+func TestLimits(t *testing.T) {
+	require := require.New(t)
+
+	limits := DefaultLimits()
+	require.NoError(limits.Validate())
+	require.Less(limits.Classes[ClassNormal]+limits.Classes[ClassBackground], limits.Global,
+		"some capacity should always remain available for critical work",
+	)
+
+	require.Error((&Limits{}).Validate(), "zero global limit should be rejected")
+	require.Error((&Limits{Global: 1, Classes: map[Class]uint{ClassNormal: 0}}).Validate())
+	require.Error((&Limits{Global: 1, Classes: map[Class]uint{numClasses: 1}}).Validate())
+
+	_, err := NewPriorityPool("invalid", Limits{})
+	require.Error(err, "NewPriorityPool should reject invalid limits")
+
+	raw, err := json.Marshal(limits)
+	require.NoError(err, "json.Marshal")
+	require.Contains(string(raw), `"background"`)
+	var decoded Limits
+	err = json.Unmarshal(raw, &decoded)
+	require.NoError(err, "json.Unmarshal")
+	require.Equal(limits, decoded)
+
+	var class Class
+	require.Error(class.UnmarshalText([]byte("unknown")))
+}
+
+func TestPriorityPoolOrder(t *testing.T) {
+	require := require.New(t)
+
+	p, err := NewPriorityPool("order", Limits{Global: 1})
+	require.NoError(err, "NewPriorityPool")
+	defer p.Stop()
+
+	// Block the only worker so that all other jobs get queued.
+	blockCh := make(chan struct{})
+	blocked := p.Submit(ClassBackground, func() { <-blockCh })
+
+	var (
+		mu    sync.Mutex
+		order []Class
+	)
+	var done []<-chan struct{}
+	for _, class := range []Class{ClassBackground, ClassNormal, ClassCritical, ClassNormal, ClassBackground, ClassCritical} {
+		done = append(done, p.Submit(class, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, class)
+		}))
+	}
+
+	close(blockCh)
+	<-blocked
+	for _, ch := range done {
+		<-ch
+	}
+
+	require.Equal([]Class{
+		ClassCritical, ClassCritical,
+		ClassNormal, ClassNormal,
+		ClassBackground, ClassBackground,
+	}, order, "jobs should be started in priority order")
+}
+
+func TestPriorityPoolClassLimits(t *testing.T) {
+	require := require.New(t)
+
+	p, err := NewPriorityPool("class limits", Limits{
+		Global: 3,
+		Classes: map[Class]uint{
+			ClassBackground: 1,
+		},
+	})
+	require.NoError(err, "NewPriorityPool")
+	defer p.Stop()
+
+	// Saturate the background class.
+	blockCh := make(chan struct{})
+	var background []<-chan struct{}
+	for range 5 {
+		background = append(background, p.Submit(ClassBackground, func() { <-blockCh }))
+	}
+	require.EqualValues(1, p.numRunning(ClassBackground), "background work should be capped")
+
+	// Critical work should not be blocked by queued background work.
+	err = p.Run(context.Background(), ClassCritical, func() {})
+	require.NoError(err, "Run(ClassCritical)")
+
+	// Raising the limits should start more queued jobs.
+	err = p.SetLimits(Limits{Global: 3, Classes: map[Class]uint{ClassBackground: 3}})
+	require.NoError(err, "SetLimits")
+	require.EqualValues(3, p.numRunning(ClassBackground))
+
+	// The global limit should apply even when classes are not capped.
+	err = p.SetLimits(Limits{Global: 4})
+	require.NoError(err, "SetLimits")
+	require.EqualValues(4, p.numRunning(ClassBackground))
+	require.Equal(Limits{Global: 4, Classes: map[Class]uint{}}, p.Limits())
+
+	err = p.SetLimits(Limits{})
+	require.Error(err, "SetLimits should reject invalid limits")
+
+	close(blockCh)
+	for _, ch := range background {
+		<-ch
+	}
+	require.Zero(p.numRunning(ClassBackground))
+}
+
+func TestPriorityPoolRunCancel(t *testing.T) {
+	require := require.New(t)
+
+	p, err := NewPriorityPool("run cancel", Limits{Global: 1})
+	require.NoError(err, "NewPriorityPool")
+	defer p.Stop()
+
+	blockCh := make(chan struct{})
+	blocked := p.Submit(ClassNormal, func() { <-blockCh })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var executed bool
+	err = p.Run(ctx, ClassCritical, func() { executed = true })
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	close(blockCh)
+	<-blocked
+	err = p.Run(context.Background(), ClassNormal, func() {})
+	require.NoError(err, "Run")
+	require.False(executed, "canceled job should not be executed")
+}
+
+func TestPriorityPoolStop(t *testing.T) {
+	require := require.New(t)
+
+	p, err := NewPriorityPool("stop", Limits{Global: 1})
+	require.NoError(err, "NewPriorityPool")
+
+	blockCh := make(chan struct{})
+	p.Submit(ClassNormal, func() { <-blockCh })
+
+	runErrCh := make(chan error)
+	go func() {
+		runErrCh <- p.Run(context.Background(), ClassCritical, func() {})
+	}()
+	require.Eventually(func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.queues[ClassCritical]) == 1
+	}, time.Second, time.Millisecond)
+
+	stopCh := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(stopCh)
+	}()
+	require.ErrorIs(<-runErrCh, ErrPoolStopped, "queued jobs should be discarded on stop")
+
+	select {
+	case <-stopCh:
+		require.Fail("Stop should wait for running jobs")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(blockCh)
+	<-stopCh
+
+	require.Nil(p.Submit(ClassNormal, func() {}), "stopped pool should not accept jobs")
+	require.ErrorIs(p.Run(context.Background(), ClassNormal, func() {}), ErrPoolStopped)
+}
+
+// BenchmarkCriticalLatencyDuringCheckpoint measures the latency of critical work (commitment
+// signature verification) while the pool is saturated with background work (checkpoint
+// creation).
+func BenchmarkCriticalLatencyDuringCheckpoint(b *testing.B) {
+	p, err := NewPriorityPool("benchmark", DefaultLimits())
+	require.NoError(b, err, "NewPriorityPool")
+	defer p.Stop()
+	limits := p.Limits()
+
+	// Simulate an in-progress checkpoint creation by keeping the background class saturated
+	// with chunk hashing jobs, with plenty of work queued.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chunk := make([]byte, 1<<20)
+	var wg sync.WaitGroup
+	for range 2 * limits.Global {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				_ = p.Run(ctx, ClassBackground, func() {
+					sha256.Sum256(chunk)
+				})
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer cancel()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(b, err, "GenerateKey")
+	msg := []byte("executor commitment")
+	sig := ed25519.Sign(priv, msg)
+
+	latencies := make([]time.Duration, 0, b.N)
+	b.ResetTimer()
+	for range b.N {
+		start := time.Now()
+		err = p.Run(context.Background(), ClassCritical, func() {
+			if !ed25519.Verify(pub, msg, sig) {
+				panic("signature verification failed")
+			}
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	b.StopTimer()
+
+	slices.Sort(latencies)
+	b.ReportMetric(float64(latencies[len(latencies)/2].Microseconds()), "p50-µs")
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Microseconds()), "p99-µs")
+}
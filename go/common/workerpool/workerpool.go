@@ -1,4 +1,7 @@
-// Package workerpool implements a simple goroutine-based workerpool with a configurable number of workers.
+// Package workerpool implements goroutine-based workerpools.
+//
+// A Pool is a simple workerpool with a configurable number of workers, while a PriorityPool
+// executes jobs of different priority classes subject to per-class and global concurrency limits.
 package workerpool
 
 import (
@@ -13,10 +13,12 @@ import (
 	genesis "github.com/oasisprotocol/oasis-core/go/genesis/config"
 	ias "github.com/oasisprotocol/oasis-core/go/ias/config"
 	common "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/config"
+	health "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/health/config"
 	metrics "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics/config"
 	pprof "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/pprof/config"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/config"
 	runtime "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	workerBeacon "github.com/oasisprotocol/oasis-core/go/worker/beacon/config"
 	workerKM "github.com/oasisprotocol/oasis-core/go/worker/keymanager/config"
 	workerRegistration "github.com/oasisprotocol/oasis-core/go/worker/registration/config"
 	workerSentry "github.com/oasisprotocol/oasis-core/go/worker/sentry/config"
@@ -83,11 +85,13 @@ type Config struct {
 	IAS       ias.Config     `yaml:"ias,omitempty"`
 	Pprof     pprof.Config   `yaml:"pprof,omitempty"`
 	Metrics   metrics.Config `yaml:"metrics,omitempty"`
+	Health    health.Config  `yaml:"health,omitempty"`
 
 	Registration workerRegistration.Config `yaml:"registration,omitempty"`
 	Keymanager   workerKM.Config           `yaml:"keymanager,omitempty"`
 	Storage      workerStorage.Config      `yaml:"storage,omitempty"`
 	Sentry       workerSentry.Config       `yaml:"sentry,omitempty"`
+	Beacon       workerBeacon.Config       `yaml:"beacon,omitempty"`
 }
 
 // Validate validates the configuration settings.
@@ -118,6 +122,12 @@ func (c *Config) Validate() error {
 	if err = c.Runtime.Validate(); err != nil {
 		return fmt.Errorf("runtime: %w", err)
 	}
+	for _, rt := range c.Runtime.Runtimes {
+		// Read-only runtimes need to follow the runtime state locally.
+		if rt.Mode == runtime.RuntimeModeReadOnly && (!c.Mode.HasLocalStorage() || c.Mode.IsArchive()) {
+			return fmt.Errorf("runtime: runtime %s: read-only mode is not supported in %s node mode", rt.ID, c.Mode)
+		}
+	}
 	if err = c.P2P.Validate(); err != nil {
 		return fmt.Errorf("p2p: %w", err)
 	}
@@ -133,6 +143,9 @@ func (c *Config) Validate() error {
 	if err = c.Sentry.Validate(); err != nil {
 		return fmt.Errorf("sentry: %w", err)
 	}
+	if err = c.Beacon.Validate(); err != nil {
+		return fmt.Errorf("beacon: %w", err)
+	}
 	if err = c.IAS.Validate(); err != nil {
 		return fmt.Errorf("ias: %w", err)
 	}
@@ -142,6 +155,9 @@ func (c *Config) Validate() error {
 	if err = c.Metrics.Validate(); err != nil {
 		return fmt.Errorf("metrics: %w", err)
 	}
+	if err = c.Health.Validate(); err != nil {
+		return fmt.Errorf("health: %w", err)
+	}
 
 	return nil
 }
@@ -159,9 +175,11 @@ func DefaultConfig() Config {
 		Keymanager:   workerKM.DefaultConfig(),
 		Storage:      workerStorage.DefaultConfig(),
 		Sentry:       workerSentry.DefaultConfig(),
+		Beacon:       workerBeacon.DefaultConfig(),
 		IAS:          ias.DefaultConfig(),
 		Pprof:        pprof.DefaultConfig(),
 		Metrics:      metrics.DefaultConfig(),
+		Health:       health.DefaultConfig(),
 	}
 }
 
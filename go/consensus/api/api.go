@@ -126,12 +126,23 @@ type Backend interface {
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error)
 
+	// StateToGenesisFiltered returns the genesis state at the specified block height with the
+	// given dump filter applied.
+	StateToGenesisFiltered(ctx context.Context, req *StateToGenesisRequest) (*genesis.Document, error)
+
 	// EstimateGas calculates the amount of gas required to execute the given transaction.
 	EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error)
 
 	// MinGasPrice returns the minimum gas price.
 	MinGasPrice(ctx context.Context) (*quantity.Quantity, error)
 
+	// GetSuggestedGasPrice returns the gas price suggested for transactions that should be
+	// included in a timely manner, computed as the given percentile (0-100) of gas prices paid by
+	// transactions in recent blocks, weighted by block fullness.
+	//
+	// In case recent blocks are empty, the minimum gas price is returned.
+	GetSuggestedGasPrice(ctx context.Context, percentile uint8) (*quantity.Quantity, error)
+
 	// GetBlock returns a consensus block at a specific height.
 	GetBlock(ctx context.Context, height int64) (*Block, error)
 
@@ -155,9 +166,24 @@ type Backend interface {
 	// GetParameters returns the consensus parameters for a specific height.
 	GetParameters(ctx context.Context, height int64) (*Parameters, error)
 
+	// GetSigningContextBundle returns a snapshot of everything needed to construct and sign
+	// consensus transactions offline, taken at a specific height.
+	GetSigningContextBundle(ctx context.Context, height int64) (*SigningContextBundle, error)
+
+	// GetParametersDiff returns the consensus parameters of all services that changed between
+	// the two given heights.
+	GetParametersDiff(ctx context.Context, request *GetParametersDiffRequest) (*ParametersDiff, error)
+
 	// SubmitEvidence submits evidence of misbehavior.
 	SubmitEvidence(ctx context.Context, evidence *Evidence) error
 
+	// GetEvidence returns the records of validator misbehavior evidence committed in the given
+	// range of block heights, ordered by height.
+	//
+	// Evidence records are only available if they are recorded (see the staking consensus
+	// parameter EvidenceRecordsRetention).
+	GetEvidence(ctx context.Context, request *GetEvidenceRequest) ([]*EvidenceRecord, error)
+
 	// GetSignerNonce returns the nonce that should be used by the given
 	// signer for transmitting the next transaction.
 	GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error)
@@ -374,6 +400,30 @@ type Status struct { // nolint: maligned
 
 	// P2P is the P2P status of the node.
 	P2P *P2PStatus `json:"p2p,omitempty"`
+
+	// Sync is the synchronization status of the node relative to the network.
+	//
+	// It is not set in case the node has no peer data yet.
+	Sync *SyncStatus `json:"sync,omitempty"`
+
+	// NextEpochTime is the estimated time of the next epoch transition, computed from the epoch
+	// interval and recent block intervals.
+	//
+	// It is not set in case there is not enough data for an estimate.
+	NextEpochTime *time.Time `json:"next_epoch_time,omitempty"`
+}
+
+// SyncStatus is the synchronization status of a node relative to the network.
+type SyncStatus struct {
+	// NetworkHeight is the best-known height of the network as reported by peers.
+	NetworkHeight int64 `json:"network_height"`
+
+	// LagBlocks is the number of blocks the node is behind the network.
+	LagBlocks int64 `json:"lag_blocks"`
+
+	// Lag is the estimated time the node is behind the network, computed from recent block
+	// intervals.
+	Lag time.Duration `json:"lag"`
 }
 
 // P2PStatus is the P2P status of a node.
@@ -441,6 +491,12 @@ type StatePruneHandler interface {
 	Prune(height int64) error
 }
 
+// StateToGenesisRequest is a StateToGenesisFiltered request.
+type StateToGenesisRequest struct {
+	Height int64               `json:"height"`
+	Filter *genesis.DumpFilter `json:"filter,omitempty"`
+}
+
 // EstimateGasRequest is a EstimateGas request.
 type EstimateGasRequest struct {
 	Signer      signature.PublicKey      `json:"signer"`
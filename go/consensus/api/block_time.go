@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+// MethodSetBlockTime is the method name for the debug-only block time control transaction.
+var MethodSetBlockTime = transaction.NewMethodName(ModuleName, "SetBlockTime", BlockTimeSchedule{})
+
+// BlockTimeSchedule is a schedule of block timestamps observed by consensus applications which
+// replaces the timestamps proposed by the consensus backend.
+//
+// This is only available when the debug block time control is enabled in the consensus
+// parameters and should only be used in tests.
+type BlockTimeSchedule struct {
+	// Base is the timestamp of the first block that follows the schedule. A zero base timestamp
+	// clears any installed schedule.
+	Base time.Time `json:"base"`
+	// Step is the amount by which the timestamp is advanced on each subsequent block. A zero step
+	// freezes the timestamp.
+	Step time.Duration `json:"step,omitempty"`
+
+	// Height is the height of the first block that follows the schedule. It is set by the
+	// consensus backend when the schedule is installed.
+	Height int64 `json:"height,omitempty"`
+}
+
+// IsEnabled returns true iff the schedule overrides block timestamps.
+func (s *BlockTimeSchedule) IsEnabled() bool {
+	return !s.Base.IsZero()
+}
+
+// ValidateBasic performs basic block time schedule validation.
+func (s *BlockTimeSchedule) ValidateBasic() error {
+	if s.Step < 0 {
+		return fmt.Errorf("block time step must not be negative")
+	}
+	if !s.IsEnabled() && s.Step != 0 {
+		return fmt.Errorf("block time step must be zero when clearing the schedule")
+	}
+	return nil
+}
+
+// TimeAt returns the timestamp of the block at the given height.
+//
+// Heights before the start of the schedule get the base timestamp.
+func (s *BlockTimeSchedule) TimeAt(height int64) time.Time {
+	if height <= s.Height {
+		return s.Base
+	}
+	return s.Base.Add(time.Duration(height-s.Height) * s.Step)
+}
+
+// NewSetBlockTimeTx creates a new debug block time control transaction.
+func NewSetBlockTimeTx(nonce uint64, fee *transaction.Fee, schedule *BlockTimeSchedule) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSetBlockTime, schedule)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// GetEvidenceRequest is a GetEvidence request.
+type GetEvidenceRequest struct {
+	// Height is the consensus block height at which the evidence records are queried.
+	Height int64 `json:"height"`
+
+	// FromHeight is the (inclusive) height of the first block in which the evidence was committed.
+	FromHeight int64 `json:"from_height,omitempty"`
+	// ToHeight is the (inclusive) height of the last block in which the evidence was committed.
+	// Zero means that there is no upper bound.
+	ToHeight int64 `json:"to_height,omitempty"`
+
+	// Offset is the number of matching evidence records to skip.
+	Offset uint64 `json:"offset,omitempty"`
+	// Limit is the maximum number of evidence records to return. Zero means no limit.
+	Limit uint32 `json:"limit,omitempty"`
+}
+
+// EvidenceRecord is a record of validator misbehavior evidence processed by the consensus layer.
+type EvidenceRecord struct {
+	// Height is the height of the block in which the evidence was committed.
+	Height int64 `json:"height"`
+	// Index is the index of the evidence among all evidence committed in the same block.
+	Index uint32 `json:"index"`
+
+	// Reason is the kind of the misbehavior.
+	Reason staking.SlashReason `json:"reason"`
+	// MisbehaviorHeight is the height at which the misbehavior occurred.
+	MisbehaviorHeight int64 `json:"misbehavior_height"`
+
+	// ValidatorAddress is the consensus address of the offending validator.
+	ValidatorAddress []byte `json:"validator_address"`
+	// NodeID is the identifier of the offending validator node in case it could be resolved.
+	NodeID *signature.PublicKey `json:"node_id,omitempty"`
+	// EntityID is the identifier of the entity controlling the offending validator node in case
+	// it could be resolved.
+	EntityID *signature.PublicKey `json:"entity_id,omitempty"`
+
+	// Slashed is the amount slashed from the entity's escrow as a result of the evidence. It is
+	// not set in case the offending validator was not slashed (e.g., because it was frozen).
+	Slashed *quantity.Quantity `json:"slashed,omitempty"`
+}
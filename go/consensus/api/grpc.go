@@ -33,10 +33,14 @@ var (
 	methodSubmitTxWithProof = serviceName.NewMethod("SubmitTxWithProof", transaction.SignedTransaction{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
+	// methodStateToGenesisFiltered is the StateToGenesisFiltered method.
+	methodStateToGenesisFiltered = serviceName.NewMethod("StateToGenesisFiltered", &StateToGenesisRequest{})
 	// methodEstimateGas is the EstimateGas method.
 	methodEstimateGas = serviceName.NewMethod("EstimateGas", &EstimateGasRequest{})
 	// methodMinGasPrice is the MinGasPrice method.
 	methodMinGasPrice = serviceName.NewMethod("MinGasPrice", nil)
+	// methodGetSuggestedGasPrice is the GetSuggestedGasPrice method.
+	methodGetSuggestedGasPrice = serviceName.NewMethod("GetSuggestedGasPrice", uint8(0))
 	// methodGetSignerNonce is a GetSignerNonce method.
 	methodGetSignerNonce = serviceName.NewMethod("GetSignerNonce", &GetSignerNonceRequest{})
 	// methodGetBlock is the GetBlock method.
@@ -71,8 +75,14 @@ var (
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 	// methodGetNextBlockState is the GetNextBlockState method.
 	methodGetNextBlockState = serviceName.NewMethod("GetNextBlockState", nil)
+	// methodGetEvidence is the GetEvidence method.
+	methodGetEvidence = serviceName.NewMethod("GetEvidence", GetEvidenceRequest{})
 	// methodGetParameters is the GetParameters method.
 	methodGetParameters = serviceName.NewMethod("GetParameters", int64(0))
+	// methodGetSigningContextBundle is the GetSigningContextBundle method.
+	methodGetSigningContextBundle = serviceName.NewMethod("GetSigningContextBundle", int64(0))
+	// methodGetParametersDiff is the GetParametersDiff method.
+	methodGetParametersDiff = serviceName.NewMethod("GetParametersDiff", GetParametersDiffRequest{})
 	// methodSubmitEvidence is the SubmitEvidence method.
 	methodSubmitEvidence = serviceName.NewMethod("SubmitEvidence", &Evidence{})
 
@@ -100,6 +110,10 @@ var (
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
 			},
+			{
+				MethodName: methodStateToGenesisFiltered.ShortName(),
+				Handler:    handlerStateToGenesisFiltered,
+			},
 			{
 				MethodName: methodEstimateGas.ShortName(),
 				Handler:    handlerEstimateGas,
@@ -108,6 +122,10 @@ var (
 				MethodName: methodMinGasPrice.ShortName(),
 				Handler:    handlerMinGasPrice,
 			},
+			{
+				MethodName: methodGetSuggestedGasPrice.ShortName(),
+				Handler:    handlerGetSuggestedGasPrice,
+			},
 			{
 				MethodName: methodGetSignerNonce.ShortName(),
 				Handler:    handlerGetSignerNonce,
@@ -176,10 +194,22 @@ var (
 				MethodName: methodGetNextBlockState.ShortName(),
 				Handler:    handlerGetNextBlockState,
 			},
+			{
+				MethodName: methodGetEvidence.ShortName(),
+				Handler:    handlerGetEvidence,
+			},
 			{
 				MethodName: methodGetParameters.ShortName(),
 				Handler:    handlerGetParameters,
 			},
+			{
+				MethodName: methodGetSigningContextBundle.ShortName(),
+				Handler:    handlerGetSigningContextBundle,
+			},
+			{
+				MethodName: methodGetParametersDiff.ShortName(),
+				Handler:    handlerGetParametersDiff,
+			},
 			{
 				MethodName: methodSubmitEvidence.ShortName(),
 				Handler:    handlerSubmitEvidence,
@@ -287,6 +317,29 @@ func handlerStateToGenesis(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerStateToGenesisFiltered(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(StateToGenesisRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().StateToGenesisFiltered(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodStateToGenesisFiltered.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().StateToGenesisFiltered(ctx, req.(*StateToGenesisRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 func handlerEstimateGas(
 	srv any,
 	ctx context.Context,
@@ -329,6 +382,29 @@ func handlerMinGasPrice(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetSuggestedGasPrice(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var percentile uint8
+	if err := dec(&percentile); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().GetSuggestedGasPrice(ctx, percentile)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetSuggestedGasPrice.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().GetSuggestedGasPrice(ctx, req.(uint8))
+	}
+	return interceptor(ctx, percentile, info, handler)
+}
+
 func handlerGetSignerNonce(
 	srv any,
 	ctx context.Context,
@@ -692,6 +768,29 @@ func handlerGetNextBlockState(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetEvidence(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq GetEvidenceRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().GetEvidence(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEvidence.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().GetEvidence(ctx, req.(*GetEvidenceRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerGetParameters(
 	srv any,
 	ctx context.Context,
@@ -715,6 +814,52 @@ func handlerGetParameters(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetSigningContextBundle(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().GetSigningContextBundle(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetSigningContextBundle.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().GetSigningContextBundle(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetParametersDiff(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq GetParametersDiffRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Services).Core().GetParametersDiff(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetParametersDiff.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Services).Core().GetParametersDiff(ctx, req.(*GetParametersDiffRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerSubmitEvidence(
 	srv any,
 	ctx context.Context,
@@ -807,6 +952,14 @@ func (c *Client) StateToGenesis(ctx context.Context, height int64) (*genesis.Doc
 	return &rsp, nil
 }
 
+func (c *Client) StateToGenesisFiltered(ctx context.Context, req *StateToGenesisRequest) (*genesis.Document, error) {
+	var rsp genesis.Document
+	if err := c.conn.Invoke(ctx, methodStateToGenesisFiltered.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) EstimateGas(ctx context.Context, req *EstimateGasRequest) (transaction.Gas, error) {
 	var gas transaction.Gas
 	if err := c.conn.Invoke(ctx, methodEstimateGas.FullName(), req, &gas); err != nil {
@@ -823,6 +976,14 @@ func (c *Client) MinGasPrice(ctx context.Context) (*quantity.Quantity, error) {
 	return &rsp, nil
 }
 
+func (c *Client) GetSuggestedGasPrice(ctx context.Context, percentile uint8) (*quantity.Quantity, error) {
+	var rsp quantity.Quantity
+	if err := c.conn.Invoke(ctx, methodGetSuggestedGasPrice.FullName(), percentile, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
 	var nonce uint64
 	if err := c.conn.Invoke(ctx, methodGetSignerNonce.FullName(), req, &nonce); err != nil {
@@ -970,6 +1131,14 @@ func (c *Client) GetNextBlockState(ctx context.Context) (*NextBlockState, error)
 	return &rsp, nil
 }
 
+func (c *Client) GetEvidence(ctx context.Context, request *GetEvidenceRequest) ([]*EvidenceRecord, error) {
+	var rsp []*EvidenceRecord
+	if err := c.conn.Invoke(ctx, methodGetEvidence.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *Client) GetParameters(ctx context.Context, height int64) (*Parameters, error) {
 	var rsp Parameters
 	if err := c.conn.Invoke(ctx, methodGetParameters.FullName(), height, &rsp); err != nil {
@@ -978,6 +1147,22 @@ func (c *Client) GetParameters(ctx context.Context, height int64) (*Parameters,
 	return &rsp, nil
 }
 
+func (c *Client) GetSigningContextBundle(ctx context.Context, height int64) (*SigningContextBundle, error) {
+	var rsp SigningContextBundle
+	if err := c.conn.Invoke(ctx, methodGetSigningContextBundle.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *Client) GetParametersDiff(ctx context.Context, request *GetParametersDiffRequest) (*ParametersDiff, error) {
+	var rsp ParametersDiff
+	if err := c.conn.Invoke(ctx, methodGetParametersDiff.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) SubmitEvidence(ctx context.Context, evidence *Evidence) error {
 	return c.conn.Invoke(ctx, methodSubmitEvidence.FullName(), evidence, nil)
 }
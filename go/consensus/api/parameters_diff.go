@@ -0,0 +1,275 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+var (
+	_ prettyprint.PrettyPrinter = (*ParametersDiff)(nil)
+
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// GetParametersDiffRequest is a GetParametersDiff request.
+type GetParametersDiffRequest struct {
+	// FromHeight is the height of the parameters to compare against.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the height of the compared parameters.
+	ToHeight int64 `json:"to_height"`
+}
+
+// ParametersDiff is a list of consensus parameter changes between two heights.
+type ParametersDiff struct {
+	// FromHeight is the height of the parameters that were compared against.
+	FromHeight int64 `json:"from_height"`
+	// ToHeight is the height of the compared parameters.
+	ToHeight int64 `json:"to_height"`
+
+	// Changes are the changed parameters, ordered by module and field.
+	Changes []*ParameterChange `json:"changes,omitempty"`
+}
+
+// PrettyPrint writes a pretty-printed representation of ParametersDiff to the given writer.
+func (d ParametersDiff) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sFrom Height: %d\n", prefix, d.FromHeight)
+	fmt.Fprintf(w, "%sTo Height:   %d\n", prefix, d.ToHeight)
+
+	if len(d.Changes) == 0 {
+		fmt.Fprintf(w, "%sNo Parameter Changes\n", prefix)
+		return
+	}
+
+	fmt.Fprintf(w, "%sChanges:\n", prefix)
+	for _, c := range d.Changes {
+		fmt.Fprintf(w, "%s  %s.%s:\n", prefix, c.Module, c.Field)
+		fmt.Fprintf(w, "%s    Old: %s\n", prefix, prettyValue(c.Old))
+		fmt.Fprintf(w, "%s    New: %s\n", prefix, prettyValue(c.New))
+		if c.ProposalID != nil {
+			fmt.Fprintf(w, "%s    Proposal: %d\n", prefix, *c.ProposalID)
+		}
+		if c.UpgradeHandler != "" {
+			fmt.Fprintf(w, "%s    Upgrade Handler: %s\n", prefix, c.UpgradeHandler)
+		}
+	}
+}
+
+// PrettyType returns a representation of ParametersDiff that can be used for pretty printing.
+func (d ParametersDiff) PrettyType() (any, error) {
+	return d, nil
+}
+
+func prettyValue(v json.RawMessage) string {
+	if v == nil {
+		return "(none)"
+	}
+	return string(v)
+}
+
+// ParameterChange is a change of a single consensus parameter.
+type ParameterChange struct {
+	// Module is the name of the module that the parameter belongs to.
+	Module string `json:"module"`
+	// Field is the path to the changed parameter, consisting of dot-separated CBOR field names
+	// and map keys.
+	Field string `json:"field"`
+
+	// Old is the JSON-encoded old value of the parameter, if any.
+	Old json.RawMessage `json:"old,omitempty"`
+	// New is the JSON-encoded new value of the parameter, if any.
+	New json.RawMessage `json:"new,omitempty"`
+
+	// ProposalID is the identifier of the governance proposal that changed the parameter, if
+	// it could be determined.
+	ProposalID *uint64 `json:"proposal_id,omitempty"`
+	// UpgradeHandler is the name of the upgrade handler that changed the parameter, if it could
+	// be determined.
+	UpgradeHandler upgrade.HandlerName `json:"upgrade_handler,omitempty"`
+}
+
+// DiffParameters compares the given consensus parameters of a module and returns the list of
+// changed parameters.
+//
+// Parameters are compared field by field using reflection and their CBOR encoding, so that any
+// new parameters are picked up automatically.
+func DiffParameters(module string, oldParams, newParams any) ([]*ParameterChange, error) {
+	oldValue, newValue := reflect.ValueOf(oldParams), reflect.ValueOf(newParams)
+	if oldValue.Type() != newValue.Type() {
+		return nil, fmt.Errorf("consensus: mismatched parameter types: %s and %s", oldValue.Type(), newValue.Type())
+	}
+
+	var changes []*ParameterChange
+	if err := diffValues(module, nil, oldValue, newValue, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func diffValues(module string, path []string, oldValue, newValue reflect.Value, changes *[]*ParameterChange) error {
+	// Dereference pointers, treating nil pointers as leaves.
+	for oldValue.Kind() == reflect.Pointer && !oldValue.IsNil() && !newValue.IsNil() {
+		oldValue, newValue = oldValue.Elem(), newValue.Elem()
+	}
+
+	switch {
+	case isLeaf(oldValue.Type()):
+		return diffLeaves(module, path, &oldValue, &newValue, changes)
+	case oldValue.Kind() == reflect.Struct:
+		return diffStructs(module, path, oldValue, newValue, changes)
+	default:
+		return diffMaps(module, path, oldValue, newValue, changes)
+	}
+}
+
+func isLeaf(t reflect.Type) bool {
+	switch {
+	case t.Implements(binaryMarshalerType), reflect.PointerTo(t).Implements(binaryMarshalerType):
+		// Types with custom encodings are compared as a whole.
+		return true
+	case t.Implements(textMarshalerType), reflect.PointerTo(t).Implements(textMarshalerType):
+		return true
+	case t.Kind() == reflect.Struct, t.Kind() == reflect.Map:
+		return false
+	default:
+		return true
+	}
+}
+
+func diffStructs(module string, path []string, oldValue, newValue reflect.Value, changes *[]*ParameterChange) error {
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, ok := cborFieldName(field)
+		if !ok {
+			continue
+		}
+
+		fieldPath := path
+		if !field.Anonymous || name != field.Name {
+			fieldPath = append(append([]string{}, path...), name)
+		}
+		if err := diffValues(module, fieldPath, oldValue.Field(i), newValue.Field(i), changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cborFieldName returns the name under which the given struct field is serialized and whether it
+// is serialized at all.
+func cborFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("cbor")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
+func diffMaps(module string, path []string, oldValue, newValue reflect.Value, changes *[]*ParameterChange) error {
+	keys := make(map[string]reflect.Value)
+	for _, v := range []reflect.Value{oldValue, newValue} {
+		for _, key := range v.MapKeys() {
+			name, err := mapKeyName(key)
+			if err != nil {
+				return err
+			}
+			keys[name] = key
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := keys[name]
+		keyPath := append(append([]string{}, path...), name)
+
+		oldElem, newElem := oldValue.MapIndex(key), newValue.MapIndex(key)
+		if !oldElem.IsValid() || !newElem.IsValid() {
+			if err := diffLeaves(module, keyPath, optionalValue(oldElem), optionalValue(newElem), changes); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := diffValues(module, keyPath, oldElem, newElem, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapKeyName(key reflect.Value) (string, error) {
+	if m, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("consensus: failed to marshal parameter map key: %w", err)
+		}
+		return string(text), nil
+	}
+	return fmt.Sprint(key.Interface()), nil
+}
+
+func optionalValue(v reflect.Value) *reflect.Value {
+	if !v.IsValid() {
+		return nil
+	}
+	return &v
+}
+
+func diffLeaves(module string, path []string, oldValue, newValue *reflect.Value, changes *[]*ParameterChange) error {
+	var oldRaw, newRaw []byte
+	if oldValue != nil {
+		oldRaw = cbor.Marshal(oldValue.Interface())
+	}
+	if newValue != nil {
+		newRaw = cbor.Marshal(newValue.Interface())
+	}
+	if oldValue != nil && newValue != nil && bytes.Equal(oldRaw, newRaw) {
+		return nil
+	}
+
+	change := ParameterChange{
+		Module: module,
+		Field:  strings.Join(path, "."),
+	}
+	var err error
+	if oldValue != nil {
+		if change.Old, err = json.Marshal(oldValue.Interface()); err != nil {
+			return fmt.Errorf("consensus: failed to marshal old parameter value: %w", err)
+		}
+	}
+	if newValue != nil {
+		if change.New, err = json.Marshal(newValue.Interface()); err != nil {
+			return fmt.Errorf("consensus: failed to marshal new parameter value: %w", err)
+		}
+	}
+	*changes = append(*changes, &change)
+	return nil
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+)
+
+type diffTestEmbedded struct {
+	Flag bool `json:"flag"`
+}
+
+type diffTestParameters struct {
+	diffTestEmbedded
+
+	Amount   quantity.Quantity  `json:"amount"`
+	Limit    *uint64            `json:"limit,omitempty"`
+	Items    []string           `json:"items,omitempty"`
+	Limits   map[string]*uint64 `json:"limits,omitempty"`
+	Internal uint64             `json:"-"`
+}
+
+func TestDiffParameters(t *testing.T) {
+	require := require.New(t)
+
+	one, two := uint64(1), uint64(2)
+	oldParams := diffTestParameters{
+		Amount:   *quantity.NewFromUint64(100),
+		Limit:    &one,
+		Items:    []string{"a"},
+		Limits:   map[string]*uint64{"a": &one, "b": &one},
+		Internal: 1,
+	}
+	changes, err := DiffParameters("test", &oldParams, &oldParams)
+	require.NoError(err, "DiffParameters")
+	require.Empty(changes, "identical parameters should have no changes")
+
+	newParams := diffTestParameters{
+		diffTestEmbedded: diffTestEmbedded{Flag: true},
+		Amount:           *quantity.NewFromUint64(200),
+		Limit:            nil,
+		Items:            []string{"a", "b"},
+		Limits:           map[string]*uint64{"a": &one, "b": &two, "c": &two},
+		Internal:         2,
+	}
+	changes, err = DiffParameters("test", &oldParams, &newParams)
+	require.NoError(err, "DiffParameters")
+
+	type change struct {
+		field    string
+		old, new string
+	}
+	var got []change
+	for _, c := range changes {
+		require.Equal("test", c.Module)
+		got = append(got, change{c.Field, string(c.Old), string(c.New)})
+	}
+	require.Equal([]change{
+		{"flag", "false", "true"},
+		{"amount", `"100"`, `"200"`},
+		{"limit", "1", "null"},
+		{"items", `["a"]`, `["a","b"]`},
+		{"limits.b", "1", "2"},
+		{"limits.c", "", "2"},
+	}, got)
+
+	_, err = DiffParameters("test", &oldParams, &genesis.Parameters{})
+	require.Error(err, "DiffParameters should fail on mismatched types")
+}
+
+func TestDiffParametersConsensus(t *testing.T) {
+	require := require.New(t)
+
+	oldParams := genesis.Parameters{
+		MaxTxSize: 1024,
+		GasCosts: transaction.Costs{
+			"tx_a": 10,
+		},
+	}
+	newParams := oldParams
+	newParams.MaxTxSize = 2048
+	newParams.GasCosts = transaction.Costs{
+		"tx_b": 20,
+	}
+
+	changes, err := DiffParameters(ModuleName, &oldParams, &newParams)
+	require.NoError(err, "DiffParameters")
+	require.Len(changes, 3)
+
+	require.Equal("max_tx_size", changes[0].Field)
+	require.Equal(json.RawMessage("1024"), changes[0].Old)
+	require.Equal(json.RawMessage("2048"), changes[0].New)
+	require.Equal("gas_costs.tx_a", changes[1].Field)
+	require.Nil(changes[1].New, "removed entries should have no new value")
+	require.Equal("gas_costs.tx_b", changes[2].Field)
+	require.Nil(changes[2].Old, "added entries should have no old value")
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// LatestSigningContextBundleVersion is the latest signing context bundle version.
+const LatestSigningContextBundleVersion = 1
+
+// SigningContextBundle is a snapshot of everything needed to construct and sign consensus
+// transactions offline.
+type SigningContextBundle struct {
+	cbor.Versioned
+
+	// Height is the consensus height at which the snapshot was taken.
+	Height int64 `json:"height"`
+	// Hash is the hash of the consensus block at which the snapshot was taken.
+	Hash hash.Hash `json:"hash"`
+	// Time is the consensus time of the block at which the snapshot was taken.
+	//
+	// Offline tooling should use it to determine whether the snapshot is still fresh.
+	Time time.Time `json:"time"`
+
+	// ChainContext is the chain domain separation context.
+	ChainContext string `json:"chain_context"`
+
+	// TokenSymbol is the token's ticker symbol.
+	TokenSymbol string `json:"token_symbol"`
+	// TokenValueExponent is the token's value base-10 exponent.
+	TokenValueExponent uint8 `json:"token_value_exponent"`
+
+	// Consensus are the core consensus parameters, including the minimum gas price and the base
+	// transaction gas costs.
+	Consensus genesis.Parameters `json:"consensus"`
+	// Staking are the staking consensus parameters, including staking thresholds.
+	Staking staking.ConsensusParameters `json:"staking"`
+	// Registry are the registry consensus parameters.
+	Registry registry.ConsensusParameters `json:"registry"`
+	// RootHash are the roothash consensus parameters.
+	RootHash roothash.ConsensusParameters `json:"roothash"`
+	// Governance are the governance consensus parameters.
+	Governance governance.ConsensusParameters `json:"governance"`
+	// KeyManagerChurp are the key manager CHURP consensus parameters.
+	KeyManagerChurp churp.ConsensusParameters `json:"keymanager_churp"`
+	// Vault are the vault consensus parameters.
+	Vault vault.ConsensusParameters `json:"vault"`
+}
+
+// ValidateBasic performs basic signing context bundle validity checks.
+func (b *SigningContextBundle) ValidateBasic() error {
+	if b.V != LatestSigningContextBundleVersion {
+		return fmt.Errorf("unsupported signing context bundle version: %d", b.V)
+	}
+	if b.ChainContext == "" {
+		return fmt.Errorf("signing context bundle is missing the chain context")
+	}
+	return nil
+}
+
+// Age returns the age of the snapshot at the given time.
+func (b *SigningContextBundle) Age(now time.Time) time.Duration {
+	return now.Sub(b.Time)
+}
+
+// IsStale returns true iff the snapshot is older than the given maximum age at the given time.
+//
+// A zero maximum age means that the snapshot never becomes stale.
+func (b *SigningContextBundle) IsStale(now time.Time, maxAge time.Duration) bool {
+	return maxAge > 0 && b.Age(now) > maxAge
+}
@@ -1,19 +1,24 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
 
 	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/metrics"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -26,6 +31,10 @@ const (
 type PriceDiscovery interface {
 	// GasPrice returns the current consensus gas price.
 	GasPrice() (*quantity.Quantity, error)
+
+	// SuggestedGasPrice returns the gas price at the given percentile (0-100) of gas prices paid
+	// by transactions in recent blocks, weighted by block fullness.
+	SuggestedGasPrice(percentile uint8) (*quantity.Quantity, error)
 }
 
 // SubmissionManager is a transaction submission manager interface.
@@ -39,14 +48,17 @@ type SubmissionManager interface {
 	// SignAndSubmitTx populates the nonce and fee fields in the transaction, signs the transaction
 	// with the passed signer and submits it to consensus backend.
 	//
-	// It also automatically handles retries in case the nonce was incorrectly estimated.
+	// Submissions with the same signer are serialized. Retries are handled automatically in case
+	// the nonce was incorrectly estimated (e.g., because it has been used by another process
+	// sharing the signer key), making sure that a transaction which has already been included is
+	// not resubmitted.
 	SignAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) error
 
 	// SignAndSubmitTxWithProof populates the nonce and fee fields in the transaction, signs
 	// the transaction with the passed signer, submits it to consensus backend and creates
 	// a proof of inclusion.
 	//
-	// It also automatically handles retries in case the nonce was incorrectly estimated.
+	// Submissions and retries are handled the same way as in SignAndSubmitTx.
 	SignAndSubmitTxWithProof(ctx context.Context, signer signature.Signer, tx *transaction.Transaction) (*transaction.SignedTransaction, *transaction.Proof, error)
 }
 
@@ -55,8 +67,8 @@ type submissionManager struct {
 	priceDiscovery PriceDiscovery
 	maxFee         quantity.Quantity
 
-	noncesLock sync.Mutex
-	nonces     map[staking.Address]uint64
+	queuesLock sync.Mutex
+	queues     map[staking.Address]*signerQueue
 
 	logger *logging.Logger
 }
@@ -111,45 +123,44 @@ func (m *submissionManager) EstimateGasAndSetFee(ctx context.Context, signer sig
 	return nil
 }
 
-func (m *submissionManager) getSignerNonce(ctx context.Context, signerAddr staking.Address) (uint64, error) {
-	m.noncesLock.Lock()
-	defer m.noncesLock.Unlock()
+func (m *submissionManager) signerQueue(signerAddr staking.Address) *signerQueue {
+	m.queuesLock.Lock()
+	defer m.queuesLock.Unlock()
 
-	nonce, ok := m.nonces[signerAddr]
+	q, ok := m.queues[signerAddr]
 	if !ok {
-		// Query latest nonce when one is not available.
-		var err error
-		nonce, err = m.consensus.GetSignerNonce(ctx, &GetSignerNonceRequest{
-			AccountAddress: signerAddr,
-			Height:         HeightLatest,
-		})
-		if err != nil {
-			return 0, err
-		}
+		q = newSignerQueue()
+		m.queues[signerAddr] = q
 	}
-
-	m.nonces[signerAddr] = nonce + 1
-
-	return nonce, nil
+	return q
 }
 
-func (m *submissionManager) clearSignerNonce(signerAddr staking.Address) {
-	m.noncesLock.Lock()
-	defer m.noncesLock.Unlock()
+func (m *submissionManager) signAndSubmitTx(ctx context.Context, q *signerQueue, signer signature.Signer, tx *transaction.Transaction, withProof bool) (*transaction.SignedTransaction, *transaction.Proof, error) {
+	signerAddr := staking.NewAddress(signer.Public())
 
-	delete(m.nonces, signerAddr)
-}
+	// Make sure that a previous attempt which may have been included is not executed twice.
+	if q.pending != nil {
+		sigTx, proof, included, err := m.checkPendingIncluded(ctx, q, withProof)
+		if err != nil {
+			return nil, nil, err
+		}
+		if included {
+			m.logger.Debug("previous transaction submission has been included",
+				"account_address", signerAddr,
+				"nonce", tx.Nonce,
+			)
+			return sigTx, proof, nil
+		}
+	}
 
-func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signature.Signer, tx *transaction.Transaction, withProof bool) (*transaction.SignedTransaction, *transaction.Proof, error) {
 	// Update transaction nonce.
 	var err error
-	signerAddr := staking.NewAddress(signer.Public())
-
-	tx.Nonce, err = m.getSignerNonce(ctx, signerAddr)
+	tx.Nonce, err = q.nextNonce(ctx, m.consensus, signerAddr)
 	if err != nil {
 		if errors.Is(err, ErrNoCommittedBlocks) {
 			// No committed blocks available, retry submission.
 			m.logger.Debug("retrying transaction submission due to no committed blocks")
+			metrics.SubmissionRetries.With(prometheus.Labels{"reason": "no_committed_blocks"}).Inc()
 			return nil, nil, err
 		}
 		return nil, nil, backoff.Permanent(err)
@@ -169,6 +180,12 @@ func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signatur
 		return nil, nil, backoff.Permanent(err)
 	}
 
+	// Remember the height before submission so that inclusion can be checked later.
+	height, err := m.consensus.GetLatestHeight(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query latest height: %w", err)
+	}
+
 	var proof *transaction.Proof
 	if withProof {
 		proof, err = m.consensus.SubmitTxWithProof(ctx, sigTx)
@@ -177,31 +194,125 @@ func (m *submissionManager) signAndSubmitTx(ctx context.Context, signer signatur
 	}
 	if err != nil {
 		// If the transaction check fails (which cannot be determined from
-		// the error), the nonce in the cache should be either decremented
-		// or cleared to ensure consistency.
-		m.clearSignerNonce(signerAddr)
+		// the error), the expected nonce should be refreshed from chain state
+		// to ensure consistency.
+		q.clearNonce()
 
 		switch {
 		case errors.Is(err, transaction.ErrUpgradePending):
 			// Pending upgrade, retry submission.
 			m.logger.Debug("retrying transaction submission due to pending upgrade")
+			metrics.SubmissionRetries.With(prometheus.Labels{"reason": "upgrade_pending"}).Inc()
 			return nil, nil, err
-		case errors.Is(err, transaction.ErrInvalidNonce):
-			// Invalid nonce, retry submission.
+		case errors.Is(err, transaction.ErrInvalidNonce), errors.Is(err, ErrDuplicateTx):
+			// The nonce has been used by a concurrent submission sharing the signer key or the
+			// transaction has been invalidated after entering the mempool. In the latter case
+			// the transaction may still have been included by another validator, so check that
+			// before resubmitting.
 			m.logger.Debug("retrying transaction submission due to invalid nonce",
 				"account_address", signerAddr,
 				"nonce", tx.Nonce,
+				"err", err,
 			)
+			metrics.SubmissionRetries.With(prometheus.Labels{"reason": "invalid_nonce"}).Inc()
+			q.pending = &pendingTx{
+				sigTx:  sigTx,
+				height: height,
+			}
 			return nil, nil, err
 		default:
 			return nil, nil, backoff.Permanent(err)
 		}
 	}
 
+	q.setNonce(tx.Nonce + 1)
+	q.pending = nil
+
 	return sigTx, proof, nil
 }
 
+// checkPendingIncluded checks whether the pending transaction of the given signer queue has been
+// included in a block since it was submitted and clears it if it has not been and can no longer
+// be included.
+func (m *submissionManager) checkPendingIncluded(ctx context.Context, q *signerQueue, withProof bool) (*transaction.SignedTransaction, *transaction.Proof, bool, error) {
+	pending := q.pending
+	data := cbor.Marshal(pending.sigTx)
+
+	latestHeight, err := m.consensus.GetLatestHeight(ctx)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to query latest height: %w", err)
+	}
+	for height := pending.height + 1; height <= latestHeight; height++ {
+		txs, err := m.consensus.GetTransactionsWithResults(ctx, height)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to query transactions: %w", err)
+		}
+
+		index := slices.IndexFunc(txs.Transactions, func(tx []byte) bool {
+			return bytes.Equal(tx, data)
+		})
+		if index < 0 {
+			continue
+		}
+		q.pending = nil
+
+		if result := txs.Results[index]; !result.IsSuccess() {
+			return nil, nil, false, backoff.Permanent(errors.FromCode(result.Error.Module, result.Error.Code, result.Error.Message))
+		}
+		if !withProof {
+			return pending.sigTx, nil, true, nil
+		}
+
+		tps, err := m.consensus.GetTransactionsWithProofs(ctx, height)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to query transaction proofs: %w", err)
+		}
+		if index >= len(tps.Proofs) {
+			return nil, nil, false, fmt.Errorf("invalid transaction index")
+		}
+		return pending.sigTx, &transaction.Proof{
+			Height:   height,
+			RawProof: tps.Proofs[index],
+		}, true, nil
+	}
+
+	// The pending transaction can only be included if its nonce is still valid.
+	nonce, err := m.consensus.GetSignerNonce(ctx, &GetSignerNonceRequest{
+		AccountAddress: staking.NewAddress(pending.sigTx.Signature.PublicKey),
+		Height:         latestHeight,
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var tx transaction.Transaction
+	if err = cbor.Unmarshal(pending.sigTx.Blob, &tx); err != nil {
+		return nil, nil, false, backoff.Permanent(err)
+	}
+	if tx.Nonce < nonce {
+		// Nonce has been consumed by another transaction, so it is safe to resubmit.
+		q.pending = nil
+		metrics.SubmissionRetries.With(prometheus.Labels{"reason": "nonce_gap"}).Inc()
+		m.logger.Info("transaction nonce has been used by a concurrent submission, resubmitting",
+			"account_address", staking.NewAddress(pending.sigTx.Signature.PublicKey),
+			"nonce", tx.Nonce,
+			"next_nonce", nonce,
+		)
+	}
+	return nil, nil, false, nil
+}
+
 func (m *submissionManager) signAndSubmitTxWithRetry(ctx context.Context, signer signature.Signer, tx *transaction.Transaction, withProof bool) (*transaction.SignedTransaction, *transaction.Proof, error) {
+	// Serialize submissions of the same signer so that nonces are assigned in order.
+	q := m.signerQueue(staking.NewAddress(signer.Public()))
+
+	metrics.SubmissionQueueDepth.Inc()
+	defer metrics.SubmissionQueueDepth.Dec()
+
+	if err := q.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer q.unlock()
+
 	sched := cmnBackoff.NewExponentialBackOff()
 	sched.MaxInterval = maxSubmissionRetryInterval
 	sched.MaxElapsedTime = maxSubmissionRetryElapsedTime
@@ -213,7 +324,7 @@ func (m *submissionManager) signAndSubmitTxWithRetry(ctx context.Context, signer
 
 	f := func() error {
 		var err error
-		sigTx, proof, err = m.signAndSubmitTx(ctx, signer, tx, withProof)
+		sigTx, proof, err = m.signAndSubmitTx(ctx, q, signer, tx, withProof)
 		return err
 	}
 
@@ -240,7 +351,7 @@ func NewSubmissionManager(consensus Backend, priceDiscovery PriceDiscovery, maxF
 	sm := &submissionManager{
 		consensus:      consensus,
 		priceDiscovery: priceDiscovery,
-		nonces:         make(map[staking.Address]uint64),
+		queues:         make(map[staking.Address]*signerQueue),
 		logger:         logging.GetLogger("consensus/submission"),
 	}
 	_ = sm.maxFee.FromUint64(maxFee)
@@ -278,6 +389,10 @@ func (pd *noOpPriceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return nil, transaction.ErrMethodNotSupported
 }
 
+func (pd *noOpPriceDiscovery) SuggestedGasPrice(uint8) (*quantity.Quantity, error) {
+	return nil, transaction.ErrMethodNotSupported
+}
+
 // NoOpSubmissionManager implements a submission manager that doesn't support submitting transactions.
 type NoOpSubmissionManager struct{}
 
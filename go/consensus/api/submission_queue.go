@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// pendingTx is a submitted transaction whose inclusion is uncertain.
+type pendingTx struct {
+	// sigTx is the submitted signed transaction.
+	sigTx *transaction.SignedTransaction
+	// height is the latest consensus height before the transaction was submitted.
+	height int64
+}
+
+// signerQueue serializes transaction submissions of a single signer and tracks the nonce that
+// the signer is expected to use next.
+//
+// All fields may only be accessed while holding the queue lock.
+type signerQueue struct {
+	sem chan struct{}
+
+	nonce      uint64
+	nonceValid bool
+
+	// pending is the last transaction submitted by the current submission whose inclusion is
+	// uncertain, if any.
+	pending *pendingTx
+}
+
+func newSignerQueue() *signerQueue {
+	return &signerQueue{
+		sem: make(chan struct{}, 1),
+	}
+}
+
+// lock waits until all previously queued submissions are done.
+func (q *signerQueue) lock(ctx context.Context) error {
+	select {
+	case q.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock allows the next queued submission to proceed.
+func (q *signerQueue) unlock() {
+	q.pending = nil
+	<-q.sem
+}
+
+// nextNonce returns the nonce that should be used for the next transaction, querying the chain
+// state in case the expected nonce is not known.
+func (q *signerQueue) nextNonce(ctx context.Context, consensus Backend, signerAddr staking.Address) (uint64, error) {
+	if q.nonceValid {
+		return q.nonce, nil
+	}
+
+	nonce, err := consensus.GetSignerNonce(ctx, &GetSignerNonceRequest{
+		AccountAddress: signerAddr,
+		Height:         HeightLatest,
+	})
+	if err != nil {
+		return 0, err
+	}
+	q.setNonce(nonce)
+	return nonce, nil
+}
+
+// setNonce sets the nonce that the signer is expected to use next.
+func (q *signerQueue) setNonce(nonce uint64) {
+	q.nonce = nonce
+	q.nonceValid = true
+}
+
+// clearNonce forces the expected nonce to be refreshed from chain state.
+func (q *signerQueue) clearNonce() {
+	q.nonce = 0
+	q.nonceValid = false
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+)
+
+// submissionTestBackend is a fake consensus backend that includes each submitted transaction in
+// its own block.
+type submissionTestBackend struct {
+	Backend
+
+	sync.Mutex
+
+	nonce  uint64
+	blocks [][]byte
+
+	// invalidateIncluded makes the next submission fail with an invalid nonce error even though
+	// the transaction has been included.
+	invalidateIncluded bool
+	numSubmitted       int
+}
+
+func (b *submissionTestBackend) GetSignerNonce(context.Context, *GetSignerNonceRequest) (uint64, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.nonce, nil
+}
+
+func (b *submissionTestBackend) GetLatestHeight(context.Context) (int64, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	return int64(len(b.blocks)), nil
+}
+
+func (b *submissionTestBackend) GetTransactionsWithResults(_ context.Context, height int64) (*TransactionsWithResults, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	return &TransactionsWithResults{
+		Transactions: [][]byte{b.blocks[height-1]},
+		Results:      []*results.Result{{}},
+	}, nil
+}
+
+func (b *submissionTestBackend) SubmitTx(_ context.Context, sigTx *transaction.SignedTransaction) error {
+	b.Lock()
+	defer b.Unlock()
+
+	b.numSubmitted++
+
+	var tx transaction.Transaction
+	if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+		return err
+	}
+	if tx.Nonce != b.nonce {
+		return transaction.ErrInvalidNonce
+	}
+	b.include(cbor.Marshal(sigTx))
+
+	if b.invalidateIncluded {
+		b.invalidateIncluded = false
+		return transaction.ErrInvalidNonce
+	}
+	return nil
+}
+
+// submitConcurrent simulates a transaction being submitted by another process sharing the
+// signer key.
+func (b *submissionTestBackend) submitConcurrent() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.include([]byte("concurrent"))
+}
+
+func (b *submissionTestBackend) include(tx []byte) {
+	b.blocks = append(b.blocks, tx)
+	b.nonce++
+}
+
+func TestSubmissionManager(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-core consensus submission")
+
+	backend := &submissionTestBackend{}
+	sm := NewSubmissionManager(backend, nil, 0)
+	signer := memorySigner.NewTestSigner("consensus submission test signer")
+	ctx := context.Background()
+
+	newTx := func() *transaction.Transaction {
+		return transaction.NewTransaction(0, &transaction.Fee{Amount: *quantity.NewFromUint64(0)}, "test.Method", nil)
+	}
+
+	// Regular submissions should use consecutive nonces.
+	err := sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx")
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx")
+	require.EqualValues(2, backend.nonce)
+	require.Equal(2, backend.numSubmitted)
+
+	// A transaction submitted by another process should cause a nonce gap, which should be
+	// repaired by refreshing the nonce.
+	backend.submitConcurrent()
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx should repair nonce gaps")
+	require.EqualValues(4, backend.nonce)
+	require.Equal(4, backend.numSubmitted, "transaction should be resubmitted once")
+
+	// A transaction that has been included even though the submission failed should not be
+	// resubmitted.
+	backend.invalidateIncluded = true
+	err = sm.SignAndSubmitTx(ctx, signer, newTx())
+	require.NoError(err, "SignAndSubmitTx should detect included transactions")
+	require.EqualValues(5, backend.nonce, "transaction should only be executed once")
+	require.Equal(5, backend.numSubmitted, "included transaction should not be resubmitted")
+
+	// Concurrent submissions from the same signer should be serialized.
+	var wg sync.WaitGroup
+	errCh := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errCh <- sm.SignAndSubmitTx(ctx, signer, newTx())
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		require.NoError(err, "SignAndSubmitTx")
+	}
+	require.EqualValues(15, backend.nonce)
+	require.Equal(15, backend.numSubmitted, "concurrent submissions should not cause nonce conflicts")
+}
@@ -0,0 +1,52 @@
+package abci
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	abciState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+)
+
+// processSetBlockTimeTx processes a debug block time control transaction.
+func (mux *abciMux) processSetBlockTimeTx(ctx *api.Context, tx *transaction.Transaction) error {
+	if !mux.state.ConsensusParameters().DebugBlockTimeControl {
+		return fmt.Errorf("mux: method '%s' is disabled via consensus", tx.Method)
+	}
+
+	var schedule consensus.BlockTimeSchedule
+	if err := cbor.Unmarshal(tx.Body, &schedule); err != nil {
+		return fmt.Errorf("%w: malformed block time schedule: %w", consensus.ErrInvalidArgument, err)
+	}
+	if err := schedule.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %w", consensus.ErrInvalidArgument, err)
+	}
+
+	if ctx.IsCheckOnly() || ctx.IsSimulation() {
+		return nil
+	}
+
+	state := abciState.NewMutableState(ctx.State())
+
+	if !schedule.IsEnabled() {
+		ctx.Logger().Info("clearing block time schedule")
+		return state.ClearBlockTimeSchedule(ctx)
+	}
+
+	// Make sure that block time does not go backwards.
+	if schedule.Base.Before(ctx.Now()) {
+		return fmt.Errorf("%w: block time schedule base is before current block time", consensus.ErrInvalidArgument)
+	}
+
+	// The schedule takes effect in the next block.
+	schedule.Height = ctx.BlockHeight() + 2
+
+	ctx.Logger().Info("setting block time schedule",
+		"base", schedule.Base,
+		"step", schedule.Step,
+		"height", schedule.Height,
+	)
+	return state.SetBlockTimeSchedule(ctx, &schedule)
+}
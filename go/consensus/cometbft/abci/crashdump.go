@@ -0,0 +1,59 @@
+package abci
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+// handlePanic writes a crash dump for the given recovered panic value (if any) that occurred
+// while processing the given ABCI method and re-panics.
+//
+// It must be called from a deferred function with the result of recover.
+func (mux *abciMux) handlePanic(p any, method string, rawTx []byte) {
+	if p == nil {
+		return
+	}
+
+	crashdump.Recover(p, "abci/"+method, func() *crashdump.State {
+		state := mux.state.crashDumpState()
+		if rawTx != nil {
+			state.Request = &crashdump.Request{
+				Method: string(txMethod(rawTx)),
+				Body:   rawTx,
+			}
+		}
+		return state
+	})
+}
+
+// crashDumpState returns the state fingerprint included in crash dumps.
+//
+// As this may be called while the panicking goroutine holds the block lock, it must not block.
+func (s *applicationState) crashDumpState() *crashdump.State {
+	if !s.blockLock.TryRLock() {
+		return &crashdump.State{}
+	}
+	defer s.blockLock.RUnlock()
+
+	return &crashdump.State{
+		Height: int64(s.stateRoot.Version),
+		StateRoots: map[string]hash.Hash{
+			"consensus_state": s.stateRoot.Hash,
+		},
+	}
+}
+
+// txMethod returns the method of the given raw transaction without verifying it.
+func txMethod(rawTx []byte) transaction.MethodName {
+	var sigTx transaction.SignedTransaction
+	if err := cbor.Unmarshal(rawTx, &sigTx); err != nil {
+		return ""
+	}
+	var tx transaction.Transaction
+	if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+		return ""
+	}
+	return tx.Method
+}
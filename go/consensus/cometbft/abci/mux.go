@@ -178,6 +178,12 @@ func (a *ApplicationServer) EstimateGas(caller signature.PublicKey, tx *transact
 	return a.mux.EstimateGas(caller, tx)
 }
 
+// SimulateConsensusUpgrade simulates the consensus portion of an upgrade on top of a throwaway
+// copy of the latest state.
+func (a *ApplicationServer) SimulateConsensusUpgrade(ctx context.Context, fn func(privateCtx any) error) (int64, error) {
+	return a.mux.SimulateConsensusUpgrade(ctx, fn)
+}
+
 // State returns the application state.
 func (a *ApplicationServer) State() api.ApplicationState {
 	return a.mux.state
@@ -278,6 +284,8 @@ func (mux *abciMux) Info(types.RequestInfo) types.ResponseInfo {
 }
 
 func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChain {
+	defer func() { mux.handlePanic(recover(), "InitChain", nil) }()
+
 	mux.logger.Debug("InitChain")
 
 	// Sanity-check the genesis application state.
@@ -529,6 +537,7 @@ func (mux *abciMux) executeProposal(
 	// Reset proposal state.
 	mux.state.resetProposal()
 	mux.state.proposal.hash = hash
+	mux.state.proposal.verifiedTxs = batchVerifyTxSignatures(txs)
 
 	resultsBeginBlock := mux.BeginBlock(types.RequestBeginBlock{
 		Hash:                hash,
@@ -564,6 +573,8 @@ func (mux *abciMux) processProvableEvents(ctx *api.Context) {
 }
 
 func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock {
+	defer func() { mux.handlePanic(recover(), "BeginBlock", nil) }()
+
 	// Use cached results when available, otherwise reset proposal.
 	if !mux.state.resetProposalIfChanged(req.Hash) && !mux.state.proposal.needsExecution() {
 		return *mux.state.proposal.resultsBeginBlock
@@ -581,7 +592,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 
 	// Reset block context for the new block.
 	blockCtx := api.NewBlockContext(api.BlockInfo{
-		Time:                 req.Header.Time,
+		Time:                 mux.state.blockTimeAt(req.Header.Height, req.Header.Time),
 		ProposerAddress:      req.Header.ProposerAddress,
 		LastCommitInfo:       req.LastCommitInfo,
 		ValidatorMisbehavior: req.ByzantineValidators,
@@ -664,6 +675,8 @@ func (mux *abciMux) notifyInvalidatedCheckTx(txHash hash.Hash, err error) {
 }
 
 func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	defer func() { mux.handlePanic(recover(), "CheckTx", req.Tx) }()
+
 	ctx := mux.state.NewContext(api.ContextCheckTx)
 	defer ctx.Close()
 
@@ -700,6 +713,8 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 }
 
 func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+	defer func() { mux.handlePanic(recover(), "DeliverTx", req.Tx) }()
+
 	// Use cached results when available.
 	if !mux.state.proposal.needsExecution() {
 		if len(mux.state.proposal.resultsDeliverTx) == 0 {
@@ -749,6 +764,8 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 }
 
 func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
+	defer func() { mux.handlePanic(recover(), "EndBlock", nil) }()
+
 	// Use cached results when available.
 	if !mux.state.proposal.needsExecution() {
 		if len(mux.state.proposal.resultsDeliverTx) != 0 {
@@ -816,6 +833,8 @@ func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 }
 
 func (mux *abciMux) Commit() types.ResponseCommit {
+	defer func() { mux.handlePanic(recover(), "Commit", nil) }()
+
 	lastRetainedVersion, err := mux.state.doCommit()
 	if err != nil {
 		mux.logger.Error("Commit failed",
@@ -28,6 +28,10 @@ func (mux *abciMux) ListSnapshots(types.RequestListSnapshots) types.ResponseList
 
 	var rsp types.ResponseListSnapshots
 	for _, cp := range cps {
+		// Do not advertise checkpoints that are missing chunks.
+		if !cp.IsComplete() {
+			continue
+		}
 		cpHash := cp.EncodedHash()
 
 		rsp.Snapshots = append(rsp.Snapshots, &types.Snapshot{
@@ -35,7 +39,7 @@ func (mux *abciMux) ListSnapshots(types.RequestListSnapshots) types.ResponseList
 			Format:   uint32(cp.Version),
 			Chunks:   uint32(len(cp.Chunks)),
 			Hash:     cpHash[:],
-			Metadata: cbor.Marshal(cp),
+			Metadata: cbor.Marshal(cp.WithoutAvailability()),
 		})
 	}
 
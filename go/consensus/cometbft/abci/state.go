@@ -3,7 +3,6 @@ package abci
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +21,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	abciState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
@@ -59,6 +59,10 @@ type proposalState struct {
 	resultsDeliverTx []*types.ResponseDeliverTx
 	// resultsEndBlock are the results of running the EndBlock hook.
 	resultsEndBlock *types.ResponseEndBlock
+
+	// verifiedTxs is the set of hashes of proposed transactions whose signatures have already
+	// been verified in a batch before execution.
+	verifiedTxs map[hash.Hash]struct{}
 }
 
 // isEqual returns true if the proposal is equal to the passed proposal.
@@ -105,6 +109,17 @@ func (ps *proposalState) reset() {
 	ps.resultsBeginBlock = nil
 	ps.resultsDeliverTx = nil
 	ps.resultsEndBlock = nil
+	ps.verifiedTxs = nil
+}
+
+// isVerifiedTx returns true iff the signature of the given raw transaction has already been
+// verified as part of the proposal.
+func (ps *proposalState) isVerifiedTx(rawTx []byte) bool {
+	if len(ps.verifiedTxs) == 0 {
+		return false
+	}
+	_, ok := ps.verifiedTxs[hash.NewFromBytes(rawTx)]
+	return ok
 }
 
 // needsExecution returns true iff the proposal has not yet been executed.
@@ -155,10 +170,11 @@ type applicationState struct { // nolint: maligned
 	checkpointer checkpoint.Checkpointer
 	upgrader     upgrade.Backend
 
-	blockLock   sync.RWMutex
-	blockTime   time.Time
-	blockCtx    *api.BlockContext
-	blockParams *consensusGenesis.Parameters
+	blockLock         sync.RWMutex
+	blockTime         time.Time
+	blockCtx          *api.BlockContext
+	blockParams       *consensusGenesis.Parameters
+	blockTimeSchedule *consensus.BlockTimeSchedule
 
 	txAuthHandler api.TransactionAuthHandler
 
@@ -221,6 +237,18 @@ func (s *applicationState) NewContext(mode api.ContextMode) *api.Context {
 	)
 }
 
+// newUpgradeSimulationState creates a throwaway in-memory copy of the latest state and returns
+// it together with the corresponding block height and time.
+func (s *applicationState) newUpgradeSimulationState() (mkvs.OverlayTree, int64, time.Time) {
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	// Since simulation is running in parallel to any changes to the database, we make sure
+	// to create a separate in-memory tree at the given block height.
+	tree := mkvs.NewOverlayWrapper(mkvs.NewWithRoot(nil, s.storage.NodeDB(), s.stateRoot, mkvs.WithoutWriteLog()))
+	return tree, int64(s.stateRoot.Version), s.blockTime
+}
+
 func (s *applicationState) LastRetainedVersion() (int64, error) {
 	return int64(s.statePruner.GetLastRetainedVersion()), nil
 }
@@ -266,6 +294,18 @@ func (s *applicationState) ConsensusParameters() *consensusGenesis.Parameters {
 	return s.blockParams
 }
 
+// blockTimeAt returns the timestamp observed by applications for the block at the given height,
+// which is the given proposed timestamp unless a debug block time schedule is installed.
+func (s *applicationState) blockTimeAt(height int64, proposed time.Time) time.Time {
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	if s.blockTimeSchedule == nil {
+		return proposed
+	}
+	return s.blockTimeSchedule.TimeAt(height)
+}
+
 func (s *applicationState) BlockContext() *api.BlockContext {
 	return s.blockCtx
 }
@@ -491,6 +531,17 @@ func (s *applicationState) doCommitOrInitChainLocked() error {
 	}
 	s.blockParams = params
 
+	// Update cache of the debug block time schedule (the schedule can only be changed by
+	// transactions, so we can safely update the cache here).
+	s.blockTimeSchedule = nil
+	if params.DebugBlockTimeControl {
+		schedule, err := state.BlockTimeSchedule(s.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load block time schedule: %w", err)
+		}
+		s.blockTimeSchedule = schedule
+	}
+
 	return nil
 }
 
@@ -790,10 +841,15 @@ func newApplicationState(ctx context.Context, upgrader upgrade.Backend, cfg *App
 }
 
 func parseGenesisAppState(req types.RequestInitChain) (*genesis.Document, error) {
-	var st genesis.Document
-	if err := json.Unmarshal(req.AppStateBytes, &st); err != nil {
+	ds, err := genesis.NewDocumentStream(bytes.NewReader(req.AppStateBytes), int64(len(req.AppStateBytes)))
+	if err != nil {
 		return nil, err
 	}
 
-	return &st, nil
+	// Compute the document hash (needed for the chain context) in the streamed form so that
+	// the whole document never needs to be re-encoded.
+	if _, err = ds.Hash(); err != nil {
+		return nil, err
+	}
+	return ds.Document()
 }
@@ -20,6 +20,10 @@ var (
 	//
 	// Value is CBOR-serialized consensusGenesis.Parameters.
 	parametersKeyFmt = consensus.KeyFormat.New(0xF1)
+	// blockTimeScheduleKeyFmt is the key format used for the debug block time schedule.
+	//
+	// Value is CBOR-serialized consensus.BlockTimeSchedule.
+	blockTimeScheduleKeyFmt = consensus.KeyFormat.New(0xF2)
 )
 
 // ImmutableState is an immutable consensus backend state wrapper.
@@ -60,6 +64,23 @@ func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*consensusGen
 	return &params, nil
 }
 
+// BlockTimeSchedule returns the debug block time schedule, if any.
+func (s *ImmutableState) BlockTimeSchedule(ctx context.Context) (*consensus.BlockTimeSchedule, error) {
+	raw, err := s.state.Get(ctx, blockTimeScheduleKeyFmt.Encode())
+	if err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var schedule consensus.BlockTimeSchedule
+	if err := cbor.Unmarshal(raw, &schedule); err != nil {
+		return nil, api.UnavailableStateError(err)
+	}
+	return &schedule, nil
+}
+
 // MutableState is a mutable consensus backend state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -96,3 +117,15 @@ func (s *MutableState) SetConsensusParameters(ctx context.Context, params *conse
 	err := s.ms.Insert(ctx, parametersKeyFmt.Encode(), cbor.Marshal(params))
 	return api.UnavailableStateError(err)
 }
+
+// SetBlockTimeSchedule sets the debug block time schedule.
+func (s *MutableState) SetBlockTimeSchedule(ctx context.Context, schedule *consensus.BlockTimeSchedule) error {
+	err := s.ms.Insert(ctx, blockTimeScheduleKeyFmt.Encode(), cbor.Marshal(schedule))
+	return api.UnavailableStateError(err)
+}
+
+// ClearBlockTimeSchedule clears the debug block time schedule.
+func (s *MutableState) ClearBlockTimeSchedule(ctx context.Context) error {
+	err := s.ms.Remove(ctx, blockTimeScheduleKeyFmt.Encode())
+	return api.UnavailableStateError(err)
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -39,11 +40,22 @@ func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Trans
 		return nil, nil, err
 	}
 	var tx transaction.Transaction
-	if err := sigTx.Open(&tx); err != nil {
-		ctx.Logger().Debug("failed to verify transaction signature",
-			"tx", base64.StdEncoding.EncodeToString(rawTx),
-		)
-		return nil, nil, err
+	switch ctx.Mode() == api.ContextDeliverTx && mux.state.proposal.isVerifiedTx(rawTx) {
+	case true:
+		// Signature has already been verified in a batch, only unmarshal the transaction.
+		if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+			ctx.Logger().Debug("failed to unmarshal transaction",
+				"tx", base64.StdEncoding.EncodeToString(rawTx),
+			)
+			return nil, nil, err
+		}
+	case false:
+		if err := sigTx.Open(&tx); err != nil {
+			ctx.Logger().Debug("failed to verify transaction signature",
+				"tx", base64.StdEncoding.EncodeToString(rawTx),
+			)
+			return nil, nil, err
+		}
 	}
 	if err := tx.SanityCheck(); err != nil {
 		ctx.Logger().Debug("bad transaction",
@@ -55,6 +67,41 @@ func (mux *abciMux) decodeTx(ctx *api.Context, rawTx []byte) (*transaction.Trans
 	return &tx, &sigTx, nil
 }
 
+// batchVerifyTxSignatures batch verifies the signatures of the given raw transactions and
+// returns the set of hashes of the transactions with valid signatures.
+func batchVerifyTxSignatures(rawTxs [][]byte) map[hash.Hash]struct{} {
+	hashes := make([]hash.Hash, 0, len(rawTxs))
+	entries := make([]signature.BatchEntry, 0, len(rawTxs))
+	for _, rawTx := range rawTxs {
+		var sigTx transaction.SignedTransaction
+		if err := cbor.Unmarshal(rawTx, &sigTx); err != nil {
+			// Malformed transactions will be rejected during execution.
+			continue
+		}
+		hashes = append(hashes, hash.NewFromBytes(rawTx))
+		entries = append(entries, signature.BatchEntry{
+			PublicKey: sigTx.Signature.PublicKey,
+			Context:   transaction.SignatureContext,
+			Message:   sigTx.Blob,
+			Signature: sigTx.Signature.Signature[:],
+		})
+	}
+
+	var sigErrs []error
+	if bvErr, ok := signature.BatchVerify(entries).(*signature.BatchVerifyError); ok {
+		sigErrs = bvErr.Errors
+	}
+
+	verified := make(map[hash.Hash]struct{}, len(hashes))
+	for i, h := range hashes {
+		if sigErrs != nil && sigErrs[i] != nil {
+			continue
+		}
+		verified[h] = struct{}{}
+	}
+	return verified
+}
+
 func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txSize int) error {
 	// Handle special methods.
 	if _, isSystem := consensus.SystemMethods[tx.Method]; isSystem {
@@ -62,9 +109,21 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 	}
 
 	// Lookup method handler.
-	app, err := mux.resolveAppForMethod(ctx, tx.Method)
-	if err != nil {
-		return err
+	var (
+		handlerName string
+		execute     func(*api.Context, *transaction.Transaction) error
+	)
+	switch tx.Method {
+	case consensus.MethodSetBlockTime:
+		handlerName = "mux"
+		execute = mux.processSetBlockTimeTx
+	default:
+		app, err := mux.resolveAppForMethod(ctx, tx.Method)
+		if err != nil {
+			return err
+		}
+		handlerName = app.Name()
+		execute = app.ExecuteTx
 	}
 
 	// Pass the transaction through the fee handler if configured.
@@ -101,11 +160,11 @@ func (mux *abciMux) processTx(ctx *api.Context, tx *transaction.Transaction, txS
 
 	// Route to correct handler.
 	ctx.Logger().Debug("dispatching",
-		"app", app.Name(),
+		"app", handlerName,
 		"tx", tx,
 	)
 
-	if err := app.ExecuteTx(ctx, tx); err != nil {
+	if err := execute(ctx, tx); err != nil {
 		return err
 	}
 
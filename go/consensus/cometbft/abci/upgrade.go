@@ -4,9 +4,13 @@ import (
 	"context"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
+var _ upgrade.ConsensusSimulator = (*abciMux)(nil)
+
 // maybeHaltForUpgrade will check whether the node needs to stop due to an upgrade being scheduled
 // in the next consensus block and gracefully (e.g. without panicking) start the node halt process
 // if this is indeed the case.
@@ -71,3 +75,39 @@ func (s *applicationState) shouldLocalHalt(blockHeight int64, currentEpoch beaco
 	}
 	return false
 }
+
+// SimulateConsensusUpgrade implements upgrade.ConsensusSimulator.
+func (mux *abciMux) SimulateConsensusUpgrade(ctx context.Context, fn func(privateCtx any) error) (int64, error) {
+	// Certain modules, in particular the beacon require InitChain or BeginBlock
+	// to have completed before initialization is complete.
+	if mux.state.BlockHeight() == 0 {
+		return 0, consensus.ErrNoCommittedBlocks
+	}
+
+	tree, height, now := mux.state.newUpgradeSimulationState()
+	defer tree.Close()
+
+	blockCtx := api.NewBlockContext(api.BlockInfo{
+		Time:          now,
+		GasAccountant: api.NewNopGasAccountant(),
+	})
+	for _, mode := range []api.ContextMode{api.ContextBeginBlock, api.ContextEndBlock} {
+		simCtx := api.NewContext(
+			ctx,
+			mode,
+			now,
+			api.NewNopGasAccountant(),
+			mux.state,
+			tree,
+			height,
+			blockCtx,
+			int64(mux.state.initialHeight),
+		)
+		err := fn(simCtx.WithUpgradeSimulation())
+		simCtx.Close()
+		if err != nil {
+			return height, err
+		}
+	}
+	return height, nil
+}
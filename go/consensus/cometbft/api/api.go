@@ -70,8 +70,8 @@ func NodeToP2PAddr(n *node.Node) (*cmtp2p.NetAddress, error) {
 		return nil, fmt.Errorf("cometbft/api: node has no consensus addresses")
 	}
 
-	// TODO: Should we extend the function to return more P2P addresses?
-	consensusAddr := n.Consensus.Addresses[0]
+	// The address book only keeps a single address per peer, so use the most preferred one.
+	consensusAddr := node.SortConsensusAddresses(n.Consensus.Addresses)[0]
 
 	pubKey := crypto.PublicKeyToCometBFT(&consensusAddr.ID)
 	pubKeyAddrHex := strings.ToLower(pubKey.Address().String())
@@ -65,8 +65,9 @@ type Context struct { // nolint: maligned
 	mode        ContextMode
 	currentTime time.Time
 
-	isMessageExecution bool
-	isTransaction      bool
+	isMessageExecution  bool
+	isTransaction       bool
+	isUpgradeSimulation bool
 
 	data           any
 	events         []types.Event
@@ -220,19 +221,20 @@ func (c *Context) CallDepth() int {
 // If you want isolated state and events use NewTransaction instad.
 func (c *Context) NewChild() *Context {
 	cc := &Context{
-		parent:             c,
-		mode:               c.mode,
-		currentTime:        c.currentTime,
-		isMessageExecution: c.isMessageExecution,
-		gasAccountant:      c.gasAccountant,
-		txSigner:           c.txSigner,
-		callerAddress:      c.callerAddress,
-		appState:           c.appState,
-		state:              c.state,
-		blockHeight:        c.blockHeight,
-		blockCtx:           c.blockCtx,
-		initialHeight:      c.initialHeight,
-		logger:             c.logger,
+		parent:              c,
+		mode:                c.mode,
+		currentTime:         c.currentTime,
+		isMessageExecution:  c.isMessageExecution,
+		isUpgradeSimulation: c.isUpgradeSimulation,
+		gasAccountant:       c.gasAccountant,
+		txSigner:            c.txSigner,
+		callerAddress:       c.callerAddress,
+		appState:            c.appState,
+		state:               c.state,
+		blockHeight:         c.blockHeight,
+		blockCtx:            c.blockCtx,
+		initialHeight:       c.initialHeight,
+		logger:              c.logger,
 	}
 	cc.Context = context.WithValue(c.Context, contextKey{}, cc)
 	return cc
@@ -302,6 +304,16 @@ func (c *Context) WithMessageExecution() *Context {
 	return child
 }
 
+// WithUpgradeSimulation creates a child context and sets the upgrade simulation flag.
+//
+// Note that state is unchanged -- the caller is responsible for providing throwaway state.
+func (c *Context) WithUpgradeSimulation() *Context {
+	child := c.NewChild()
+	child.isUpgradeSimulation = true
+	child.logger = child.logger.With("upgrade_simulation", true)
+	return child
+}
+
 // IsInitChain returns true if this ia an init chain context.
 func (c *Context) IsInitChain() bool {
 	return c.mode == ContextInitChain
@@ -317,6 +329,12 @@ func (c *Context) IsSimulation() bool {
 	return c.mode == ContextSimulateTx
 }
 
+// IsUpgradeSimulation returns true if this is an upgrade simulation context in which all state
+// updates are discarded.
+func (c *Context) IsUpgradeSimulation() bool {
+	return c.isUpgradeSimulation
+}
+
 // IsMessageExecution returns true if this is a message execution context.
 func (c *Context) IsMessageExecution() bool {
 	return c.isMessageExecution
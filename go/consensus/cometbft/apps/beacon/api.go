@@ -33,4 +33,10 @@ type internalBackend interface {
 	OnInitChain(*api.Context, *beaconState.MutableState, *beacon.ConsensusParameters, *genesis.Document) error
 	OnBeginBlock(*api.Context, *beaconState.MutableState, *beacon.ConsensusParameters) error
 	ExecuteTx(*api.Context, *beaconState.MutableState, *beacon.ConsensusParameters, *transaction.Transaction) error
+
+	// SetMockEpoch requests an explicit transition to the given epoch.
+	SetMockEpoch(*api.Context, *beaconState.MutableState, beacon.EpochTime) error
+	// ClearMockEpoch clears any requested explicit epoch transition that has not yet been
+	// scheduled.
+	ClearMockEpoch(*api.Context, *beaconState.MutableState) error
 }
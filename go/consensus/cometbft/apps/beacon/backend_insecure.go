@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
-	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
@@ -37,7 +36,7 @@ func (impl *backendInsecure) OnInitChain(
 	impl.app.doEmitEpochEvent(ctx, baseEpoch)
 
 	// Arm the initial epoch transition.
-	return impl.scheduleEpochTransitionBlock(ctx, state, params.InsecureParameters, doc.Beacon.Base+1)
+	return impl.scheduleEpochTransitionBlock(ctx, state, params, doc.Beacon.Base+1)
 }
 
 func (impl *backendInsecure) OnBeginBlock(
@@ -83,7 +82,7 @@ func (impl *backendInsecure) OnBeginBlock(
 		return fmt.Errorf("beacon: failed to clear future epoch: %w", err)
 	}
 	if !params.DebugMockBackend {
-		if err = impl.scheduleEpochTransitionBlock(ctx, state, params.InsecureParameters, future.Epoch+1); err != nil {
+		if err = impl.scheduleEpochTransitionBlock(ctx, state, params, future.Epoch+1); err != nil {
 			return err
 		}
 	}
@@ -96,11 +95,12 @@ func (impl *backendInsecure) OnBeginBlock(
 func (impl *backendInsecure) scheduleEpochTransitionBlock(
 	ctx *api.Context,
 	state *beaconState.MutableState,
-	params *beacon.InsecureParameters,
+	params *beacon.ConsensusParameters,
 	nextEpoch beacon.EpochTime,
 ) error {
-	// Schedule the epoch transition based on block height.
-	nextHeight := int64(nextEpoch) * params.Interval
+	// Schedule the epoch transition based on block height. Epochs start at fixed heights which are
+	// computed piecewise so that epoch interval changes only affect later epochs.
+	nextHeight := params.EpochBlocks(0, nextEpoch)
 	return impl.app.scheduleEpochTransitionBlock(ctx, state, nextEpoch, nextHeight)
 }
 
@@ -129,37 +129,24 @@ func (impl *backendInsecure) onEpochChangeBeacon(
 }
 
 func (impl *backendInsecure) ExecuteTx(
-	ctx *api.Context,
-	state *beaconState.MutableState,
-	params *beacon.ConsensusParameters,
+	_ *api.Context,
+	_ *beaconState.MutableState,
+	_ *beacon.ConsensusParameters,
 	tx *transaction.Transaction,
 ) error {
-	switch tx.Method {
-	case beacon.MethodSetEpoch:
-		if !params.DebugMockBackend {
-			return fmt.Errorf("beacon: method '%s' is disabled via consensus", beacon.MethodSetEpoch)
-		}
-		return impl.doTxSetEpoch(ctx, state, tx.Body)
-	default:
-		return fmt.Errorf("beacon: invalid method: %s", tx.Method)
-	}
+	return fmt.Errorf("beacon: invalid method: %s", tx.Method)
 }
 
-func (impl *backendInsecure) doTxSetEpoch(
+func (impl *backendInsecure) SetMockEpoch(
 	ctx *api.Context,
 	state *beaconState.MutableState,
-	txBody []byte,
+	epoch beacon.EpochTime,
 ) error {
 	now, _, err := state.GetEpoch(ctx)
 	if err != nil {
 		return err
 	}
 
-	var epoch beacon.EpochTime
-	if err := cbor.Unmarshal(txBody, &epoch); err != nil {
-		return err
-	}
-
 	if epoch <= now {
 		ctx.Logger().Error("explicit epoch transition does not advance time",
 			"epoch", now,
@@ -168,6 +155,14 @@ func (impl *backendInsecure) doTxSetEpoch(
 		return fmt.Errorf("beacon: explicit epoch does not advance time")
 	}
 
+	future, err := state.GetFutureEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to get future epoch: %w", err)
+	}
+	if future != nil {
+		return errMockEpochPending
+	}
+
 	height := ctx.BlockHeight() + 1 // Current height is ctx.BlockHeight() + 1
 
 	ctx.Logger().Info("scheduling explicit epoch transition",
@@ -178,3 +173,10 @@ func (impl *backendInsecure) doTxSetEpoch(
 
 	return state.SetFutureEpoch(ctx, epoch, height+1)
 }
+
+func (impl *backendInsecure) ClearMockEpoch(
+	ctx *api.Context,
+	state *beaconState.MutableState,
+) error {
+	return state.ClearFutureEpoch(ctx)
+}
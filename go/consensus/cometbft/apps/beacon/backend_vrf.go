@@ -46,7 +46,7 @@ func (impl *backendVRF) OnInitChain(
 	impl.app.doEmitEpochEvent(ctx, baseEpoch)
 
 	// Arm the initial epoch transition.
-	return impl.scheduleEpochTransitionBlock(ctx, state, params.VRFParameters, doc.Beacon.Base+1)
+	return impl.scheduleEpochTransitionBlock(ctx, state, params, doc.Beacon.Base+1)
 }
 
 func (impl *backendVRF) OnBeginBlock(
@@ -186,7 +186,7 @@ func (impl *backendVRF) OnBeginBlock(
 		return fmt.Errorf("beacon: failed to clear future epoch: %w", err)
 	}
 	if !params.DebugMockBackend {
-		if err = impl.scheduleEpochTransitionBlock(ctx, state, params.VRFParameters, future.Epoch+1); err != nil {
+		if err = impl.scheduleEpochTransitionBlock(ctx, state, params, future.Epoch+1); err != nil {
 			return err
 		}
 	}
@@ -243,11 +243,6 @@ func (impl *backendVRF) ExecuteTx(
 	switch tx.Method {
 	case beacon.MethodVRFProve:
 		return impl.doProveTx(ctx, state, params, tx)
-	case beacon.MethodSetEpoch:
-		if !params.DebugMockBackend {
-			return fmt.Errorf("beacon: method '%s' is disabled via consensus", beacon.MethodSetEpoch)
-		}
-		return impl.doSetEpochTx(ctx, state, tx.Body)
 	default:
 		return fmt.Errorf("beacon: invalid method: %s", tx.Method)
 	}
@@ -340,21 +335,16 @@ func (impl *backendVRF) doProveTx(
 	return nil
 }
 
-func (impl *backendVRF) doSetEpochTx(
+func (impl *backendVRF) SetMockEpoch(
 	ctx *api.Context,
 	state *beaconState.MutableState,
-	txBody []byte,
+	epoch beacon.EpochTime,
 ) error {
 	now, _, err := state.GetEpoch(ctx)
 	if err != nil {
 		return err
 	}
 
-	var epoch beacon.EpochTime
-	if err = cbor.Unmarshal(txBody, &epoch); err != nil {
-		return err
-	}
-
 	// Ensure there is no SetEpoch call in progress.
 	pendingMockEpoch, err := state.PendingMockEpoch(ctx)
 	if err != nil {
@@ -365,7 +355,7 @@ func (impl *backendVRF) doSetEpochTx(
 		if *pendingMockEpoch == epoch {
 			return nil
 		}
-		return fmt.Errorf("beacon: explicit epoch transition already pending")
+		return errMockEpochPending
 	}
 
 	if epoch <= now {
@@ -387,6 +377,13 @@ func (impl *backendVRF) doSetEpochTx(
 	return nil
 }
 
+func (impl *backendVRF) ClearMockEpoch(
+	ctx *api.Context,
+	state *beaconState.MutableState,
+) error {
+	return state.ClearPendingMockEpoch(ctx)
+}
+
 func (impl *backendVRF) updateNodeStatus(
 	ctx *api.Context,
 	_ *beaconState.MutableState,
@@ -420,11 +417,11 @@ func (impl *backendVRF) updateNodeStatus(
 func (impl *backendVRF) scheduleEpochTransitionBlock(
 	ctx *api.Context,
 	state *beaconState.MutableState,
-	params *beacon.VRFParameters,
+	params *beacon.ConsensusParameters,
 	nextEpoch beacon.EpochTime,
 ) error {
 	// Schedule the epoch transition based on block height.
-	nextHeight := (ctx.BlockHeight() + 1) + params.Interval
+	nextHeight := (ctx.BlockHeight() + 1) + params.IntervalAt(nextEpoch-1)
 	return impl.app.scheduleEpochTransitionBlock(ctx, state, nextEpoch, nextHeight)
 }
 
@@ -14,18 +14,25 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance/api"
 )
 
 var prodEntropyCtx = []byte("EkB-tmnt")
 
 // Application is a beacon application.
 type Application struct {
+	state api.ApplicationState
+	md    api.MessageDispatcher
+
 	backend internalBackend
 }
 
 // New constructs a new beacon application.
-func New() *Application {
-	return &Application{}
+func New(state api.ApplicationState, md api.MessageDispatcher) *Application {
+	return &Application{
+		state: state,
+		md:    md,
+	}
 }
 
 // Name implements api.Application.
@@ -55,6 +62,9 @@ func (app *Application) Dependencies() []string {
 
 // Subscribe implements api.Application.
 func (app *Application) Subscribe() {
+	// Subscribe to messages emitted by other apps.
+	app.md.Subscribe(governanceApi.MessageChangeParameters, app)
+	app.md.Subscribe(governanceApi.MessageValidateParameterChanges, app)
 }
 
 // OnCleanup implements api.Application.
@@ -74,9 +84,30 @@ func (app *Application) BeginBlock(ctx *api.Context) error {
 		return fmt.Errorf("beacon: failed to (re-)initialize backend: %w", err)
 	}
 
+	if params.DebugMockBackend {
+		if err := app.onEpochSchedule(ctx, state); err != nil {
+			return err
+		}
+	}
+
 	return app.backend.OnBeginBlock(ctx, state, params)
 }
 
+// ExecuteMessage implements api.MessageSubscriber.
+func (app *Application) ExecuteMessage(ctx *api.Context, kind, msg any) (any, error) {
+	switch kind {
+	case governanceApi.MessageValidateParameterChanges:
+		// A change parameters proposal is about to be submitted. Validate changes.
+		return app.changeParameters(ctx, msg, false)
+	case governanceApi.MessageChangeParameters:
+		// A change parameters proposal has just been accepted and closed. Validate and apply
+		// changes.
+		return app.changeParameters(ctx, msg, true)
+	default:
+		return nil, fmt.Errorf("beacon: unexpected message")
+	}
+}
+
 // ExecuteTx implements api.Application.
 func (app *Application) ExecuteTx(ctx *api.Context, tx *transaction.Transaction) error {
 	if app.backend == nil {
@@ -94,7 +125,15 @@ func (app *Application) ExecuteTx(ctx *api.Context, tx *transaction.Transaction)
 
 	ctx.SetPriority(AppPriority)
 
-	return app.backend.ExecuteTx(ctx, state, params, tx)
+	switch tx.Method {
+	case beacon.MethodSetEpoch, beacon.MethodSetEpochSchedule:
+		if !params.DebugMockBackend {
+			return fmt.Errorf("beacon: method '%s' is disabled via consensus", tx.Method)
+		}
+		return app.executeMockTx(ctx, state, tx)
+	default:
+		return app.backend.ExecuteTx(ctx, state, params, tx)
+	}
 }
 
 // EndBlock implements api.Application.
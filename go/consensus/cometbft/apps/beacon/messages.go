@@ -0,0 +1,82 @@
+package beacon
+
+import (
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool) (any, error) {
+	// Unmarshal changes and check if they should be applied to this module.
+	proposal, ok := msg.(*governance.ChangeParametersProposal)
+	if !ok {
+		return nil, fmt.Errorf("beacon: failed to type assert change parameters proposal")
+	}
+
+	if proposal.Module != beacon.ModuleName {
+		return nil, nil
+	}
+
+	// Allow changing beacon parameters with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, fmt.Errorf("%w: epoch interval changes not enabled", beacon.ErrInvalidArgument)
+	}
+
+	var changes beacon.ConsensusParameterChanges
+	if err = cbor.Unmarshal(proposal.Changes, &changes); err != nil {
+		return nil, fmt.Errorf("beacon: failed to unmarshal consensus parameter changes: %w", err)
+	}
+
+	// Validate changes against current parameters.
+	state := beaconState.NewMutableState(ctx.State())
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beacon: failed to load consensus parameters: %w", err)
+	}
+	if err = changes.SanityCheck(); err != nil {
+		return nil, fmt.Errorf("beacon: failed to validate consensus parameter changes: %w", err)
+	}
+
+	// Epoch interval changes may only affect future epochs so that the boundaries of past epochs
+	// and of the current epoch remain stable.
+	if changes.IntervalChange != nil {
+		if params.DebugMockBackend {
+			return nil, fmt.Errorf("%w: epoch interval changes not supported with mock backend", beacon.ErrInvalidArgument)
+		}
+
+		var epoch beacon.EpochTime
+		if epoch, _, err = state.GetEpoch(ctx); err != nil {
+			return nil, fmt.Errorf("beacon: failed to get current epoch: %w", err)
+		}
+		if changes.IntervalChange.Epoch <= epoch {
+			return nil, fmt.Errorf("%w: epoch interval change must be for a future epoch", beacon.ErrInvalidArgument)
+		}
+	}
+
+	if err = changes.Apply(params); err != nil {
+		return nil, fmt.Errorf("beacon: failed to apply consensus parameter changes: %w", err)
+	}
+	if err = params.SanityCheck(); err != nil {
+		return nil, fmt.Errorf("beacon: failed to validate consensus parameters: %w", err)
+	}
+
+	// Apply changes.
+	if apply {
+		if err = state.SetConsensusParameters(ctx, params); err != nil {
+			return nil, fmt.Errorf("beacon: failed to update consensus parameters: %w", err)
+		}
+	}
+
+	// Non-nil response signals that changes are valid and were successfully applied (if required).
+	return struct{}{}, nil
+}
@@ -0,0 +1,195 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+func TestChangeParameters(t *testing.T) {
+	// Prepare context.
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	// Setup state.
+	consState := consensusState.NewMutableState(ctx.State())
+	err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(t, err, "SetConsensusParameters")
+	state := beaconState.NewMutableState(ctx.State())
+	err = state.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend:            beacon.BackendInsecure,
+		InsecureParameters: &beacon.InsecureParameters{Interval: 10},
+	})
+	require.NoError(t, err, "setting consensus parameters should succeed")
+	err = state.SetEpoch(ctx, 3, 30)
+	require.NoError(t, err, "SetEpoch")
+	app := New(appState, nil)
+
+	// Prepare proposals.
+	intervalChange := func(epoch beacon.EpochTime, interval int64) *governance.ChangeParametersProposal {
+		return &governance.ChangeParametersProposal{
+			Module: beacon.ModuleName,
+			Changes: cbor.Marshal(beacon.ConsensusParameterChanges{
+				IntervalChange: &beacon.EpochIntervalChange{
+					Epoch:    epoch,
+					Interval: interval,
+				},
+			}),
+		}
+	}
+
+	// Run sub-tests.
+	t.Run("happy path - validate only", func(t *testing.T) {
+		require := require.New(t)
+
+		res, err := app.changeParameters(ctx, intervalChange(5, 20), false)
+		require.NoError(err, "validation of consensus parameter changes should succeed")
+		require.Equal(struct{}{}, res)
+
+		params, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Empty(params.IntervalChanges, "consensus parameters shouldn't change")
+	})
+	t.Run("happy path - apply changes", func(t *testing.T) {
+		require := require.New(t)
+
+		res, err := app.changeParameters(ctx, intervalChange(5, 20), true)
+		require.NoError(err, "changing consensus parameters should succeed")
+		require.Equal(struct{}{}, res)
+
+		params, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Equal([]beacon.EpochIntervalChange{{Epoch: 5, Interval: 20}}, params.IntervalChanges, "consensus parameters should change")
+	})
+	t.Run("invalid proposal", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := app.changeParameters(ctx, "proposal", true)
+		require.EqualError(err, "beacon: failed to type assert change parameters proposal")
+	})
+	t.Run("different module", func(t *testing.T) {
+		require := require.New(t)
+
+		proposal := governance.ChangeParametersProposal{
+			Module: "module",
+		}
+		res, err := app.changeParameters(ctx, &proposal, true)
+		require.Nil(res, "changes for other modules should be ignored")
+		require.NoError(err, "changes for other modules should be ignored without error")
+	})
+	t.Run("empty changes", func(t *testing.T) {
+		require := require.New(t)
+
+		proposal := governance.ChangeParametersProposal{
+			Module:  beacon.ModuleName,
+			Changes: cbor.Marshal(beacon.ConsensusParameterChanges{}),
+		}
+		_, err := app.changeParameters(ctx, &proposal, true)
+		require.EqualError(err, "beacon: failed to validate consensus parameter changes: consensus parameter changes should not be empty")
+	})
+	t.Run("past epoch", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := app.changeParameters(ctx, intervalChange(3, 20), true)
+		require.ErrorIs(err, beacon.ErrInvalidArgument, "changes for the current epoch should be rejected")
+	})
+	t.Run("out of order", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := app.changeParameters(ctx, intervalChange(4, 20), true)
+		require.ErrorIs(err, beacon.ErrInvalidArgument, "changes before scheduled changes should be rejected")
+	})
+	t.Run("invalid interval", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := app.changeParameters(ctx, intervalChange(10, 0), true)
+		require.Error(err, "changes with an invalid interval should be rejected")
+	})
+	t.Run("feature disabled", func(t *testing.T) {
+		require := require.New(t)
+
+		err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+		require.NoError(err, "SetConsensusParameters")
+
+		_, err = app.changeParameters(ctx, intervalChange(10, 20), false)
+		require.ErrorIs(err, beacon.ErrInvalidArgument, "changes should be rejected before the feature is enabled")
+	})
+}
+
+func TestEpochIntervalChangeTransition(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextInitChain)
+	defer ctx.Close()
+
+	consState := consensusState.NewMutableState(ctx.State())
+	err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+	state := beaconState.NewMutableState(ctx.State())
+	err = state.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend:            beacon.BackendInsecure,
+		InsecureParameters: &beacon.InsecureParameters{Interval: 10},
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = state.SetEpoch(ctx, 3, 30)
+	require.NoError(err, "SetEpoch")
+	err = state.SetFutureEpoch(ctx, 4, 40)
+	require.NoError(err, "SetFutureEpoch")
+
+	app := New(appState, nil)
+	proposal := governance.ChangeParametersProposal{
+		Module: beacon.ModuleName,
+		Changes: cbor.Marshal(beacon.ConsensusParameterChanges{
+			IntervalChange: &beacon.EpochIntervalChange{
+				Epoch:    5,
+				Interval: 20,
+			},
+		}),
+	}
+	_, err = app.changeParameters(ctx, &proposal, true)
+	require.NoError(err, "changing the epoch interval should succeed")
+
+	// Epoch transitions before the change epoch use the old interval and transitions after use
+	// the new one.
+	for _, tc := range []struct {
+		height     int64
+		epoch      beacon.EpochTime
+		nextHeight int64
+	}{
+		{40, 4, 50},
+		{50, 5, 70},
+		{70, 6, 90},
+	} {
+		appState.UpdateMockApplicationStateConfig(&abciAPI.MockApplicationStateConfig{
+			BlockHeight: tc.height - 1,
+		})
+		err = app.BeginBlock(appState.NewContext(abciAPI.ContextBeginBlock))
+		require.NoError(err, "BeginBlock")
+
+		epoch, height, err := state.GetEpoch(ctx)
+		require.NoError(err, "GetEpoch")
+		require.Equal(tc.epoch, epoch, "epoch at height %d", tc.height)
+		require.Equal(tc.height, height, "epoch transition height")
+
+		future, err := state.GetFutureEpoch(ctx)
+		require.NoError(err, "GetFutureEpoch")
+		require.NotNil(future, "next epoch transition should be scheduled")
+		require.Equal(tc.epoch+1, future.Epoch)
+		require.Equal(tc.nextHeight, future.Height, "next epoch transition height")
+	}
+}
@@ -0,0 +1,132 @@
+package beacon
+
+import (
+	"errors"
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+)
+
+// errMockEpochPending is the error returned when an explicit epoch transition is already pending.
+var errMockEpochPending = errors.New("beacon: explicit epoch transition already pending")
+
+func (app *Application) executeMockTx(
+	ctx *api.Context,
+	state *beaconState.MutableState,
+	tx *transaction.Transaction,
+) error {
+	switch tx.Method {
+	case beacon.MethodSetEpoch:
+		var epoch beacon.EpochTime
+		if err := cbor.Unmarshal(tx.Body, &epoch); err != nil {
+			return err
+		}
+		return app.doTxSetEpoch(ctx, state, epoch)
+	case beacon.MethodSetEpochSchedule:
+		var schedule beacon.EpochSchedule
+		if err := cbor.Unmarshal(tx.Body, &schedule); err != nil {
+			return err
+		}
+		return app.doTxSetEpochSchedule(ctx, state, &schedule)
+	default:
+		return fmt.Errorf("beacon: invalid method: %s", tx.Method)
+	}
+}
+
+func (app *Application) doTxSetEpoch(
+	ctx *api.Context,
+	state *beaconState.MutableState,
+	epoch beacon.EpochTime,
+) error {
+	// Explicitly setting the epoch overrides the epoch schedule, including any transition that
+	// has been requested by the schedule but not yet performed.
+	schedule, err := state.EpochSchedule(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to query epoch schedule: %w", err)
+	}
+	if schedule != nil {
+		ctx.Logger().Info("clearing epoch schedule due to explicit epoch transition",
+			"epoch", epoch,
+		)
+
+		if err = state.ClearEpochSchedule(ctx); err != nil {
+			return fmt.Errorf("beacon: failed to clear epoch schedule: %w", err)
+		}
+		if err = app.backend.ClearMockEpoch(ctx, state); err != nil {
+			return fmt.Errorf("beacon: failed to clear mock epoch state: %w", err)
+		}
+	}
+
+	return app.backend.SetMockEpoch(ctx, state, epoch)
+}
+
+func (app *Application) doTxSetEpochSchedule(
+	ctx *api.Context,
+	state *beaconState.MutableState,
+	schedule *beacon.EpochSchedule,
+) error {
+	if err := schedule.ValidateBasic(); err != nil {
+		return err
+	}
+
+	if !schedule.IsEnabled() {
+		ctx.Logger().Info("clearing epoch schedule")
+		return state.ClearEpochSchedule(ctx)
+	}
+
+	ctx.Logger().Info("setting epoch schedule",
+		"start", schedule.Start,
+		"interval", schedule.Interval,
+	)
+	return state.SetEpochSchedule(ctx, schedule)
+}
+
+// onEpochSchedule requests an explicit epoch transition in case the current height is one of the
+// heights in the epoch schedule.
+func (app *Application) onEpochSchedule(ctx *api.Context, state *beaconState.MutableState) error {
+	schedule, err := state.EpochSchedule(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to query epoch schedule: %w", err)
+	}
+	if schedule == nil {
+		return nil
+	}
+
+	height := ctx.BlockHeight() + 1 // Current height is ctx.BlockHeight() + 1
+	if !schedule.IsTransitionHeight(height) {
+		return nil
+	}
+
+	// Skip in case an epoch transition is already in progress.
+	future, err := state.GetFutureEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to get future epoch: %w", err)
+	}
+	if future != nil {
+		ctx.Logger().Warn("skipping scheduled epoch transition, transition already in progress",
+			"height", height,
+			"future_epoch", future.Epoch,
+		)
+		return nil
+	}
+
+	epoch, _, err := state.GetEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to get current epoch: %w", err)
+	}
+
+	switch err = app.backend.SetMockEpoch(ctx, state, epoch+1); {
+	case err == nil:
+	case errors.Is(err, errMockEpochPending):
+		ctx.Logger().Warn("skipping scheduled epoch transition, transition already pending",
+			"height", height,
+		)
+	default:
+		return fmt.Errorf("beacon: failed to request scheduled epoch transition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package beacon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+)
+
+func TestEpochSchedule(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+		BlockHeight: 9,
+	})
+	ctx := appState.NewContext(abciAPI.ContextInitChain)
+	defer ctx.Close()
+
+	state := beaconState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend:            beacon.BackendInsecure,
+		DebugMockBackend:   true,
+		InsecureParameters: &beacon.InsecureParameters{},
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = state.SetEpoch(ctx, 1, 1)
+	require.NoError(err, "SetEpoch")
+
+	app := New(appState, nil)
+	err = app.BeginBlock(appState.NewContext(abciAPI.ContextBeginBlock))
+	require.NoError(err, "BeginBlock")
+
+	// Invalid schedules should be rejected.
+	tx := transaction.NewTransaction(0, nil, beacon.MethodSetEpochSchedule, &beacon.EpochSchedule{Start: 10, Interval: -1})
+	err = app.ExecuteTx(appState.NewContext(abciAPI.ContextDeliverTx), tx)
+	require.Error(err, "SetEpochSchedule should fail with an invalid schedule")
+
+	// Install a schedule starting at the current height.
+	schedule := beacon.EpochSchedule{Start: 10, Interval: 5}
+	tx = transaction.NewTransaction(0, nil, beacon.MethodSetEpochSchedule, &schedule)
+	err = app.ExecuteTx(appState.NewContext(abciAPI.ContextDeliverTx), tx)
+	require.NoError(err, "SetEpochSchedule")
+
+	installed, err := state.EpochSchedule(ctx)
+	require.NoError(err, "EpochSchedule")
+	require.Equal(&schedule, installed)
+
+	// A transition should be requested at a scheduled height.
+	err = app.BeginBlock(appState.NewContext(abciAPI.ContextBeginBlock))
+	require.NoError(err, "BeginBlock")
+	future, err := state.GetFutureEpoch(ctx)
+	require.NoError(err, "GetFutureEpoch")
+	require.NotNil(future, "scheduled epoch transition should be pending")
+	require.EqualValues(2, future.Epoch)
+	require.EqualValues(11, future.Height)
+
+	// Explicitly setting the epoch should override the schedule.
+	tx = transaction.NewTransaction(0, nil, beacon.MethodSetEpoch, beacon.EpochTime(5))
+	err = app.ExecuteTx(appState.NewContext(abciAPI.ContextDeliverTx), tx)
+	require.NoError(err, "SetEpoch")
+
+	installed, err = state.EpochSchedule(ctx)
+	require.NoError(err, "EpochSchedule")
+	require.Nil(installed, "epoch schedule should be cleared")
+	future, err = state.GetFutureEpoch(ctx)
+	require.NoError(err, "GetFutureEpoch")
+	require.NotNil(future, "explicit epoch transition should be pending")
+	require.EqualValues(5, future.Epoch)
+
+	// Clearing the schedule when none is installed should succeed.
+	tx = transaction.NewTransaction(0, nil, beacon.MethodSetEpochSchedule, &beacon.EpochSchedule{})
+	err = app.ExecuteTx(appState.NewContext(abciAPI.ContextDeliverTx), tx)
+	require.NoError(err, "SetEpochSchedule")
+}
+
+func TestEpochScheduleDisabled(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextInitChain)
+	defer ctx.Close()
+
+	state := beaconState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend:            beacon.BackendInsecure,
+		InsecureParameters: &beacon.InsecureParameters{Interval: 10},
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	app := New(appState, nil)
+	err = app.BeginBlock(appState.NewContext(abciAPI.ContextBeginBlock))
+	require.NoError(err, "BeginBlock")
+
+	tx := transaction.NewTransaction(0, nil, beacon.MethodSetEpochSchedule, &beacon.EpochSchedule{Start: 10, Interval: 5})
+	err = app.ExecuteTx(appState.NewContext(abciAPI.ContextDeliverTx), tx)
+	require.Error(err, "SetEpochSchedule should fail without a mock backend")
+}
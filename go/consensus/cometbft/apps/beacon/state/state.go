@@ -25,6 +25,10 @@ var (
 	//
 	// Value is CBOR-serialized epoch time.
 	epochPendingMockKeyFmt = consensus.KeyFormat.New(0x45)
+	// epochScheduleKeyFmt is the mock epoch transition schedule key format.
+	//
+	// Value is CBOR-serialized beacon.EpochSchedule.
+	epochScheduleKeyFmt = consensus.KeyFormat.New(0x47)
 
 	// beaconKeyFmt is the random beacon key format.
 	//
@@ -124,6 +128,35 @@ func (s *ImmutableState) PendingMockEpoch(ctx context.Context) (*beacon.EpochTim
 	return &pendingEpoch, nil
 }
 
+// EpochSchedule returns the mock epoch transition schedule (if any).
+func (s *ImmutableState) EpochSchedule(ctx context.Context) (*beacon.EpochSchedule, error) {
+	data, err := s.state.Get(ctx, epochScheduleKeyFmt.Encode())
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var schedule beacon.EpochSchedule
+	if err = cbor.Unmarshal(data, &schedule); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &schedule, nil
+}
+
+// SetEpochSchedule sets the mock epoch transition schedule.
+func (s *MutableState) SetEpochSchedule(ctx context.Context, schedule *beacon.EpochSchedule) error {
+	err := s.ms.Insert(ctx, epochScheduleKeyFmt.Encode(), cbor.Marshal(schedule))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// ClearEpochSchedule clears the mock epoch transition schedule.
+func (s *MutableState) ClearEpochSchedule(ctx context.Context) error {
+	err := s.ms.Remove(ctx, epochScheduleKeyFmt.Encode())
+	return abciAPI.UnavailableStateError(err)
+}
+
 func (s *MutableState) SetPendingMockEpoch(ctx context.Context, epoch beacon.EpochTime) error {
 	err := s.ms.Insert(ctx, epochPendingMockKeyFmt.Encode(), cbor.Marshal(epoch))
 	return abciAPI.UnavailableStateError(err)
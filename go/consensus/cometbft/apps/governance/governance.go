@@ -254,7 +254,7 @@ func (app *Application) executeProposal(ctx *api.Context, state *governanceState
 
 		// Locally apply the upgrade proposal.
 		if upgrader := ctx.AppState().Upgrader(); upgrader != nil {
-			if err = upgrader.SubmitDescriptor(&proposal.Content.Upgrade.Descriptor); err != nil {
+			if err = upgrader.SubmitDescriptor(upgrade.UpgradeSourceGovernance, &proposal.Content.Upgrade.Descriptor); err != nil {
 				ctx.Logger().Error("failed to locally apply the upgrade descriptor",
 					"err", err,
 					"descriptor", proposal.Content.Upgrade.Descriptor,
@@ -366,6 +366,121 @@ func validatorsEscrow(
 	return totalVotingStake, validatorEntitiesEscrow, nil
 }
 
+// voteShares attributes shares in the escrow share pools of the validator entities to voters.
+//
+// A validator entity that voted is attributed all shares in its own pool, except for the shares
+// of delegators that voted themselves. A voter delegating to a validator entity is attributed its
+// delegated shares in that validator's pool, overriding the validator's vote. Shares of delegators
+// that did not vote are only counted through the vote of the validator entity.
+//
+// The returned map is indexed by voter and then by validator entity. Additionally the number of
+// votes from voters without any delegations to validator entities is returned.
+func voteShares(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+) (map[stakingAPI.Address]map[stakingAPI.Address]quantity.Quantity, uint64, error) {
+	voterShares := make(map[stakingAPI.Address]map[stakingAPI.Address]quantity.Quantity)
+
+	// Validator entities that voted are attributed all shares in their pool.
+	for _, vote := range votes {
+		pool, ok := validatorEntitiesPool[vote.Voter]
+		if !ok {
+			continue
+		}
+		voterShares[vote.Voter] = map[stakingAPI.Address]quantity.Quantity{
+			vote.Voter: *pool.TotalShares.Clone(),
+		}
+	}
+
+	// Delegators that voted override the votes of the validator entities they delegate to.
+	var invalidVotes uint64
+	for _, vote := range votes {
+		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to fetch delegations: %w", err)
+		}
+
+		var delegationToValidator bool
+		for to, delegation := range delegations {
+			if _, ok := validatorEntitiesPool[to]; !ok {
+				continue
+			}
+			delegationToValidator = true
+
+			// Shares delegated to self are already attributed to the validator entity.
+			if to == vote.Voter {
+				continue
+			}
+
+			// Deduct shares from the validator entity if it voted.
+			if validatorShares, ok := voterShares[to]; ok {
+				shares := validatorShares[to]
+				if err = shares.Sub(&delegation.Shares); err != nil {
+					return nil, 0, fmt.Errorf("failed to sub shares: %w", err)
+				}
+				validatorShares[to] = shares
+			}
+
+			// Attribute shares to the delegator.
+			if voterShares[vote.Voter] == nil {
+				voterShares[vote.Voter] = make(map[stakingAPI.Address]quantity.Quantity)
+			}
+			shares := voterShares[vote.Voter][to]
+			if err = shares.Add(&delegation.Shares); err != nil {
+				return nil, 0, fmt.Errorf("failed to add shares: %w", err)
+			}
+			voterShares[vote.Voter][to] = shares
+		}
+		if !delegationToValidator {
+			invalidVotes++
+		}
+	}
+
+	return voterShares, invalidVotes, nil
+}
+
+// voteWeight computes the stake that the vote of the given voter carries towards the results.
+//
+// In case the voter did not vote yet, the weight is computed as if it did.
+func voteWeight(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[stakingAPI.Address]*stakingAPI.SharePool,
+	votes []*governance.VoteEntry,
+	voter stakingAPI.Address,
+) (*quantity.Quantity, error) {
+	var voted bool
+	for _, vote := range votes {
+		if vote.Voter == voter {
+			voted = true
+			break
+		}
+	}
+	if !voted {
+		// The vote itself is not relevant for the weight.
+		votes = append(append([]*governance.VoteEntry{}, votes...), &governance.VoteEntry{Voter: voter})
+	}
+
+	voterShares, _, err := voteShares(ctx, stakingState, validatorEntitiesPool, votes)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := quantity.NewQuantity()
+	for validator, shares := range voterShares[voter] {
+		stake, err := validatorEntitiesPool[validator].StakeForShares(shares.Clone())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute stake from shares: %w", err)
+		}
+		if err = weight.Add(stake); err != nil {
+			return nil, fmt.Errorf("failed to add stake: %w", err)
+		}
+	}
+	return weight, nil
+}
+
 // closeProposal closes an active proposal.
 //
 // This method modifies the passed proposal.
@@ -393,64 +508,24 @@ func (app *Application) closeProposal(
 		"validator_entities_pool", validatorEntitiesPool,
 		"votes", votes,
 	)
-	validatorVotes := make(map[stakingAPI.Address]*governance.Vote)
+
+	voterShares, invalidVotes, err := voteShares(ctx, stakingState, validatorEntitiesPool, votes)
+	if err != nil {
+		return err
+	}
+	proposal.InvalidVotes += invalidVotes
+
+	// Tally the votes.
 	validatorVoteShares := make(map[stakingAPI.Address]map[governance.Vote]quantity.Quantity)
 	for validator := range validatorEntitiesPool {
 		validatorVoteShares[validator] = make(map[governance.Vote]quantity.Quantity)
 	}
-
-	// Tally the validator votes.
-	for _, vote := range votes {
-		escrow, ok := validatorEntitiesPool[vote.Voter]
-		if !ok {
-			// Skip non-validator votes.
-			continue
-		}
-		validatorVotes[vote.Voter] = &vote.Vote //nolint:gosec
-		if err = addShares(validatorVoteShares[vote.Voter], vote.Vote, escrow.TotalShares); err != nil {
-			return fmt.Errorf("failed to add shares: %w", err)
-		}
-	}
-
-	// Tally delegator votes.
 	for _, vote := range votes {
-		// Fetch outgoing delegations.
-		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
-		if err != nil {
-			ctx.Logger().Error("failed to fetch delegations for",
-				"delegator", vote.Voter,
-				"err", err,
-			)
-			return fmt.Errorf("failed to fetch delegations: %w", err)
-		}
-		var delegationToValidator bool
-		for to, delegation := range delegations {
-			if _, ok := validatorEntitiesPool[to]; !ok {
-				continue
-			}
-			delegationToValidator = true
-			validatorVote := validatorVotes[to]
-
-			// Skip if vote matches the delegated validator vote.
-			if validatorVote != nil && *validatorVote == vote.Vote {
-				continue
-			}
-
-			// Deduct shares from the validators shares.
-			if validatorVote != nil {
-				if err := subShares(validatorVoteShares[to], *validatorVote, delegation.Shares); err != nil {
-					return fmt.Errorf("failed to sub votes: %w", err)
-				}
-			}
-
-			// Add shares to the voters vote.
-			if err := addShares(validatorVoteShares[to], vote.Vote, delegation.Shares); err != nil {
-				return fmt.Errorf("failed to add votes: %w", err)
+		for validator, shares := range voterShares[vote.Voter] {
+			if err = addShares(validatorVoteShares[validator], vote.Vote, shares); err != nil {
+				return fmt.Errorf("failed to add shares: %w", err)
 			}
 		}
-		if !delegationToValidator {
-			proposal.InvalidVotes++
-		}
 	}
 
 	// Finalize the voting results - convert votes in shares into results in stake.
@@ -502,16 +577,6 @@ func addShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote g
 	return nil
 }
 
-func subShares(validatorVoteShares map[governance.Vote]quantity.Quantity, vote governance.Vote, amount quantity.Quantity) error {
-	amt := amount.Clone()
-	currShares := validatorVoteShares[vote]
-	if err := currShares.Sub(amt); err != nil {
-		return fmt.Errorf("failed to sub votes: %w", err)
-	}
-	validatorVoteShares[vote] = currShares
-	return nil
-}
-
 // EndBlock implements api.Application.
 func (app *Application) EndBlock(ctx *api.Context) (types.ResponseEndBlock, error) {
 	// Check if epoch has changed.
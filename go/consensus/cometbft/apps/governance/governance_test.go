@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -448,6 +449,287 @@ func TestCloseProposal(t *testing.T) {
 	}
 }
 
+func TestVoteWeight(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakingState := stakingState.NewMutableState(ctx.State())
+	addr1 := staking.NewAddress(signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr2 := staking.NewAddress(signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr3 := staking.NewAddress(signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr4 := staking.NewAddress(signature.NewPublicKey("dddfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+	addr5 := staking.NewAddress(signature.NewPublicKey("eeefffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	// Validators.
+	require.NoError(stakingState.SetDelegation(ctx, addr1, addr1, &staking.Delegation{Shares: *quantity.NewFromUint64(40)}))
+	require.NoError(stakingState.SetDelegation(ctx, addr2, addr2, &staking.Delegation{Shares: *quantity.NewFromUint64(70)}))
+	// Delegators.
+	require.NoError(stakingState.SetDelegation(ctx, addr3, addr1, &staking.Delegation{Shares: *quantity.NewFromUint64(40)}))
+	require.NoError(stakingState.SetDelegation(ctx, addr3, addr2, &staking.Delegation{Shares: *quantity.NewFromUint64(30)}))
+	require.NoError(stakingState.SetDelegation(ctx, addr4, addr1, &staking.Delegation{Shares: *quantity.NewFromUint64(20)}))
+
+	validatorEntitiesPool := map[staking.Address]*staking.SharePool{
+		addr1: {
+			Balance: *quantity.NewFromUint64(200),
+			// Shares:
+			// - addr1: 40
+			// - addr3: 40
+			// - addr4: 20
+			TotalShares: *quantity.NewFromUint64(100),
+		},
+		addr2: {
+			Balance: *quantity.NewFromUint64(100),
+			// Shares:
+			// - addr2: 70
+			// - addr3: 30
+			TotalShares: *quantity.NewFromUint64(100),
+		},
+	}
+
+	for _, tc := range []struct {
+		msg            string
+		votes          []*governance.VoteEntry
+		voter          staking.Address
+		expectedWeight uint64
+	}{
+		{
+			"validator without votes",
+			nil,
+			addr1,
+			200,
+		},
+		{
+			"delegator without votes",
+			nil,
+			addr3,
+			80 + 30,
+		},
+		{
+			"account without delegations",
+			nil,
+			addr5,
+			0,
+		},
+		{
+			"validator with delegator override",
+			[]*governance.VoteEntry{
+				{Voter: addr3, Vote: governance.VoteYes},
+			},
+			addr1,
+			120,
+		},
+		{
+			"validator with all delegators voting",
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteNo},
+				{Voter: addr3, Vote: governance.VoteYes},
+				{Voter: addr4, Vote: governance.VoteNo},
+			},
+			addr1,
+			80,
+		},
+		{
+			"delegator with validators voting",
+			[]*governance.VoteEntry{
+				{Voter: addr1, Vote: governance.VoteNo},
+				{Voter: addr2, Vote: governance.VoteNo},
+				{Voter: addr3, Vote: governance.VoteYes},
+			},
+			addr3,
+			80 + 30,
+		},
+	} {
+		weight, err := voteWeight(ctx, stakingState.ImmutableState, validatorEntitiesPool, tc.votes, tc.voter)
+		require.NoError(err, tc.msg)
+		require.EqualValues(*quantity.NewFromUint64(tc.expectedWeight), *weight, tc.msg)
+	}
+}
+
+// referenceVoteShares is the reference implementation of the vote tally in terms of shares that
+// the shared implementation must match.
+func referenceVoteShares(
+	ctx context.Context,
+	stakingState *stakingState.ImmutableState,
+	validatorEntitiesPool map[staking.Address]*staking.SharePool,
+	votes []*governance.VoteEntry,
+) (map[staking.Address]map[governance.Vote]quantity.Quantity, uint64, error) {
+	validatorVotes := make(map[staking.Address]*governance.Vote)
+	validatorVoteShares := make(map[staking.Address]map[governance.Vote]quantity.Quantity)
+	for validator := range validatorEntitiesPool {
+		validatorVoteShares[validator] = make(map[governance.Vote]quantity.Quantity)
+	}
+
+	for _, vote := range votes {
+		escrow, ok := validatorEntitiesPool[vote.Voter]
+		if !ok {
+			continue
+		}
+		validatorVotes[vote.Voter] = &vote.Vote
+		if err := addShares(validatorVoteShares[vote.Voter], vote.Vote, escrow.TotalShares); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var invalidVotes uint64
+	for _, vote := range votes {
+		delegations, err := stakingState.DelegationsFor(ctx, vote.Voter)
+		if err != nil {
+			return nil, 0, err
+		}
+		var delegationToValidator bool
+		for to, delegation := range delegations {
+			if _, ok := validatorEntitiesPool[to]; !ok {
+				continue
+			}
+			delegationToValidator = true
+			validatorVote := validatorVotes[to]
+			if validatorVote != nil && *validatorVote == vote.Vote {
+				continue
+			}
+			if validatorVote != nil {
+				shares := validatorVoteShares[to][*validatorVote]
+				if err = shares.Sub(&delegation.Shares); err != nil {
+					return nil, 0, err
+				}
+				validatorVoteShares[to][*validatorVote] = shares
+			}
+			if err = addShares(validatorVoteShares[to], vote.Vote, delegation.Shares); err != nil {
+				return nil, 0, err
+			}
+		}
+		if !delegationToValidator {
+			invalidVotes++
+		}
+	}
+
+	return validatorVoteShares, invalidVotes, nil
+}
+
+func TestVoteSharesProperties(t *testing.T) {
+	require := require.New(t)
+
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec
+	voteKinds := []governance.Vote{governance.VoteYes, governance.VoteNo, governance.VoteAbstain}
+
+	for iteration := 0; iteration < 200; iteration++ {
+		appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+		ctx := appState.NewContext(abciAPI.ContextEndBlock)
+		stakingState := stakingState.NewMutableState(ctx.State())
+
+		// Generate random accounts where the first few are validator entities.
+		addrs := make([]staking.Address, 2+rng.Intn(10))
+		for i := range addrs {
+			addrs[i] = staking.NewAddress(signature.NewPublicKey(fmt.Sprintf("%064x", iteration*100+i+1)))
+		}
+		validatorEntitiesPool := make(map[staking.Address]*staking.SharePool)
+		for _, addr := range addrs[:1+rng.Intn(len(addrs)-1)] {
+			validatorEntitiesPool[addr] = &staking.SharePool{
+				Balance: *quantity.NewFromUint64(uint64(rng.Intn(1_000_000))),
+			}
+		}
+
+		// Generate random delegations, including ones to non-validator accounts.
+		for _, from := range addrs {
+			for _, to := range addrs {
+				if rng.Intn(3) != 0 {
+					continue
+				}
+				shares := quantity.NewFromUint64(uint64(1 + rng.Intn(1000)))
+				require.NoError(stakingState.SetDelegation(ctx, from, to, &staking.Delegation{Shares: *shares}))
+				if pool, ok := validatorEntitiesPool[to]; ok {
+					require.NoError(pool.TotalShares.Add(shares))
+				}
+			}
+		}
+
+		// Generate random votes.
+		var votes []*governance.VoteEntry
+		for _, addr := range addrs {
+			if rng.Intn(2) == 0 {
+				continue
+			}
+			votes = append(votes, &governance.VoteEntry{Voter: addr, Vote: voteKinds[rng.Intn(len(voteKinds))]})
+		}
+
+		// Attributed shares must match the reference tally exactly.
+		expectedShares, expectedInvalidVotes, err := referenceVoteShares(ctx, stakingState.ImmutableState, validatorEntitiesPool, votes)
+		require.NoError(err, "referenceVoteShares")
+		voterShares, invalidVotes, err := voteShares(ctx, stakingState.ImmutableState, validatorEntitiesPool, votes)
+		require.NoError(err, "voteShares")
+		require.EqualValues(expectedInvalidVotes, invalidVotes, "invalid votes should match")
+
+		validatorVoteShares := make(map[staking.Address]map[governance.Vote]quantity.Quantity)
+		for validator := range validatorEntitiesPool {
+			validatorVoteShares[validator] = make(map[governance.Vote]quantity.Quantity)
+		}
+		for _, vote := range votes {
+			for validator, shares := range voterShares[vote.Voter] {
+				require.NoError(addShares(validatorVoteShares[validator], vote.Vote, shares))
+			}
+		}
+		require.Len(validatorVoteShares, len(expectedShares), "tallied shares should match")
+		for validator, expectedVoteShares := range expectedShares {
+			require.Len(validatorVoteShares[validator], len(expectedVoteShares), "tallied shares should match")
+			for vote, expected := range expectedVoteShares {
+				shares, ok := validatorVoteShares[validator][vote]
+				require.True(ok, "tallied shares should match")
+				require.Zero(expected.Cmp(&shares), "tallied shares should match")
+			}
+		}
+
+		// Vote weights must add up to the results, up to rounding of each term.
+		results := make(map[governance.Vote]*quantity.Quantity)
+		for validator, voteShares := range expectedShares {
+			for vote, shares := range voteShares {
+				stake, err := validatorEntitiesPool[validator].StakeForShares(shares.Clone())
+				require.NoError(err, "StakeForShares")
+				if results[vote] == nil {
+					results[vote] = quantity.NewQuantity()
+				}
+				require.NoError(results[vote].Add(stake))
+			}
+		}
+		weights := make(map[governance.Vote]*quantity.Quantity)
+		terms := make(map[governance.Vote]uint64)
+		for _, vote := range votes {
+			weight, err := voteWeight(ctx, stakingState.ImmutableState, validatorEntitiesPool, votes, vote.Voter)
+			require.NoError(err, "voteWeight")
+			if weights[vote.Vote] == nil {
+				weights[vote.Vote] = quantity.NewQuantity()
+			}
+			require.NoError(weights[vote.Vote].Add(weight))
+			terms[vote.Vote] += uint64(len(voterShares[vote.Voter]))
+
+			// The weight must not depend on whether the voter already voted.
+			var otherVotes []*governance.VoteEntry
+			for _, other := range votes {
+				if other.Voter != vote.Voter {
+					otherVotes = append(otherVotes, other)
+				}
+			}
+			preview, err := voteWeight(ctx, stakingState.ImmutableState, validatorEntitiesPool, otherVotes, vote.Voter)
+			require.NoError(err, "voteWeight")
+			require.Zero(weight.Cmp(preview), "weight preview should match weight after voting")
+		}
+		for vote, weight := range weights {
+			result := results[vote]
+			if result == nil {
+				result = quantity.NewQuantity()
+			}
+			require.LessOrEqual(weight.Cmp(result), 0, "weights should not exceed results")
+
+			diff := result.Clone()
+			require.NoError(diff.Sub(weight))
+			require.LessOrEqual(diff.Cmp(quantity.NewFromUint64(terms[vote])), 0, "weights should only differ from results by rounding")
+		}
+
+		ctx.Close()
+	}
+}
+
 func TestExecuteProposal(t *testing.T) {
 	require := require.New(t)
 	var err error
@@ -22,7 +22,7 @@ func (app *Application) completeStateSync(ctx *api.Context) (any, error) {
 	// Apply all pending upgrades locally.
 	if upgrader := ctx.AppState().Upgrader(); upgrader != nil {
 		for _, pu := range pendingUpgrades {
-			switch err = upgrader.SubmitDescriptor(pu); err {
+			switch err = upgrader.SubmitDescriptor(upgrade.UpgradeSourceGovernance, pu); err {
 			case nil, upgrade.ErrAlreadyPending:
 			default:
 				ctx.Logger().Error("failed to locally apply the upgrade descriptor",
@@ -3,9 +3,13 @@ package governance
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance/state"
+	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -15,6 +19,7 @@ type Query interface {
 	Proposals(context.Context) ([]*governance.Proposal, error)
 	Proposal(context.Context, uint64) (*governance.Proposal, error)
 	Votes(context.Context, uint64) ([]*governance.VoteEntry, error)
+	VoteWeight(context.Context, uint64, staking.Address) (*quantity.Quantity, error)
 	PendingUpgrades(context.Context) ([]*upgrade.Descriptor, error)
 	Genesis(context.Context) (*governance.Genesis, error)
 	ConsensusParameters(context.Context) (*governance.ConsensusParameters, error)
@@ -32,12 +37,16 @@ func (f *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error)
 		return nil, err
 	}
 	return &governanceQuerier{
-		state: governanceState.NewImmutableState(state),
+		state:          governanceState.NewImmutableState(state),
+		stakingState:   stakingState.NewImmutableState(state),
+		schedulerState: schedulerState.NewImmutableState(state),
 	}, nil
 }
 
 type governanceQuerier struct {
-	state *governanceState.ImmutableState
+	state          *governanceState.ImmutableState
+	stakingState   *stakingState.ImmutableState
+	schedulerState *schedulerState.ImmutableState
 }
 
 func (q *governanceQuerier) ActiveProposals(ctx context.Context) ([]*governance.Proposal, error) {
@@ -56,6 +65,27 @@ func (q *governanceQuerier) Votes(ctx context.Context, id uint64) ([]*governance
 	return q.state.Votes(ctx, id)
 }
 
+func (q *governanceQuerier) VoteWeight(ctx context.Context, id uint64, voter staking.Address) (*quantity.Quantity, error) {
+	proposal, err := q.state.Proposal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.State != governance.StateActive {
+		return nil, governance.ErrVotingIsClosed
+	}
+
+	votes, err := q.state.Votes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	_, validatorEntitiesPool, err := validatorsEscrow(ctx, q.stakingState, q.schedulerState)
+	if err != nil {
+		return nil, err
+	}
+
+	return voteWeight(ctx, q.stakingState, validatorEntitiesPool, votes, voter)
+}
+
 func (q *governanceQuerier) PendingUpgrades(ctx context.Context) ([]*upgrade.Descriptor, error) {
 	return q.state.PendingUpgrades(ctx)
 }
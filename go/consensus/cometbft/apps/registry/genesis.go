@@ -49,6 +49,25 @@ func (app *Application) InitChain(ctx *abciAPI.Context, _ types.RequestInitChain
 			return fmt.Errorf("registry: genesis entity registration failure: %w", err)
 		}
 	}
+	// Node authorizations need to be set before registering nodes as they are checked during
+	// node registration.
+	for i, auth := range st.NodeAuthorizations {
+		if auth == nil {
+			return fmt.Errorf("registry: genesis node authorization index %d is nil", i)
+		}
+		if err := auth.ValidateBasic(); err != nil {
+			return fmt.Errorf("registry: genesis node authorization index %d is invalid: %w", i, err)
+		}
+		if _, err := state.Entity(ctx, auth.EntityID); err != nil {
+			return fmt.Errorf("registry: genesis node authorization for node %s references a missing entity: %w", auth.NodeID, err)
+		}
+		if err := state.SetNodeAuthorization(ctx, auth); err != nil {
+			ctx.Logger().Error("InitChain: failed to set node authorization",
+				"err", err,
+			)
+			return fmt.Errorf("registry: genesis node authorization set failure: %w", err)
+		}
+	}
 	// Register runtimes. First key manager and then compute runtime(s).
 	for _, k := range []registry.RuntimeKind{registry.KindKeyManager, registry.KindCompute} {
 		for i, rt := range st.Runtimes {
@@ -170,18 +189,24 @@ func (rq *registryQuerier) Genesis(ctx context.Context) (*registry.Genesis, erro
 		nodeStatuses[n.ID] = status
 	}
 
+	nodeAuthorizations, err := rq.state.NodeAuthorizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	params, err := rq.state.ConsensusParameters(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	gen := registry.Genesis{
-		Parameters:        *params,
-		Entities:          signedEntities,
-		Runtimes:          runtimes,
-		SuspendedRuntimes: suspendedRuntimes,
-		Nodes:             validatorNodes,
-		NodeStatuses:      nodeStatuses,
+		Parameters:         *params,
+		Entities:           signedEntities,
+		Runtimes:           runtimes,
+		SuspendedRuntimes:  suspendedRuntimes,
+		Nodes:              validatorNodes,
+		NodeStatuses:       nodeStatuses,
+		NodeAuthorizations: nodeAuthorizations,
 	}
 	return &gen, nil
 }
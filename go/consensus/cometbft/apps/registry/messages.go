@@ -6,8 +6,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool) (any, error) {
@@ -26,6 +28,17 @@ func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool)
 		return nil, fmt.Errorf("registry: failed to unmarshal consensus parameter changes: %w", err)
 	}
 
+	// Allow enabling election policies with the 24.3 release.
+	if changes.EnableElectionPolicies != nil {
+		enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("%w: election policies not enabled", registry.ErrInvalidArgument)
+		}
+	}
+
 	// Validate changes against current parameters.
 	state := registryState.NewMutableState(ctx.State())
 	params, err := state.ConsensusParameters(ctx)
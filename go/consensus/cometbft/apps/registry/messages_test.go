@@ -6,10 +6,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestChangeParameters(t *testing.T) {
@@ -101,4 +105,35 @@ func TestChangeParameters(t *testing.T) {
 		_, err := app.changeParameters(ctx, &proposal, true)
 		require.EqualError(err, "registry: failed to validate consensus parameters: maximum node expiration not specified")
 	})
+	t.Run("election policies", func(t *testing.T) {
+		require := require.New(t)
+
+		changes := registry.ConsensusParameterChanges{
+			EnableElectionPolicies: map[scheduler.ElectionPolicy]bool{
+				scheduler.ElectionPolicyStakeWeighted: true,
+			},
+		}
+		proposal := governance.ChangeParametersProposal{
+			Module:  registry.ModuleName,
+			Changes: cbor.Marshal(changes),
+		}
+
+		// Election policies can only be enabled with the 24.3 feature version.
+		consState := consensusState.NewMutableState(ctx.State())
+		err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.ErrorIs(err, registry.ErrInvalidArgument)
+
+		err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+			FeatureVersion: &migrations.Version243,
+		})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.NoError(err, "enabling election policies should succeed")
+
+		state, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Equal(changes.EnableElectionPolicies, state.EnableElectionPolicies, "consensus parameters should change")
+	})
 }
@@ -7,10 +7,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 // Query is the registry query interface.
@@ -21,10 +25,12 @@ type Query interface {
 	NodeByConsensusAddress(context.Context, []byte) (*node.Node, error)
 	NodeStatus(context.Context, signature.PublicKey) (*registry.NodeStatus, error)
 	Nodes(context.Context) ([]*node.Node, error)
+	NodeAuthorizations(context.Context, signature.PublicKey) ([]*registry.NodeAuthorization, error)
 	Runtime(ctx context.Context, id common.Namespace, includeSuspended bool) (*registry.Runtime, error)
 	Runtimes(ctx context.Context, includeSuspended bool) ([]*registry.Runtime, error)
 	Genesis(context.Context) (*registry.Genesis, error)
 	ConsensusParameters(context.Context) (*registry.ConsensusParameters, error)
+	ValidateRuntimeUpdate(context.Context, *registry.Runtime) error
 }
 
 // QueryFactory is the registry query factory.
@@ -41,6 +47,7 @@ func (f *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error)
 	return &registryQuerier{
 		queryState: f.state,
 		state:      registryState.NewImmutableState(state),
+		consState:  consensusState.NewImmutableState(state),
 		height:     height,
 	}, nil
 }
@@ -48,6 +55,7 @@ func (f *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error)
 type registryQuerier struct {
 	queryState abciAPI.ApplicationQueryState
 	state      *registryState.ImmutableState
+	consState  *consensusState.ImmutableState
 	height     int64
 }
 
@@ -107,6 +115,10 @@ func (q *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return filteredNodes, nil
 }
 
+func (q *registryQuerier) NodeAuthorizations(ctx context.Context, entityID signature.PublicKey) ([]*registry.NodeAuthorization, error) {
+	return q.state.EntityNodeAuthorizations(ctx, entityID)
+}
+
 func (q *registryQuerier) Runtime(ctx context.Context, id common.Namespace, includeSuspended bool) (*registry.Runtime, error) {
 	if includeSuspended {
 		return q.state.AnyRuntime(ctx, id)
@@ -125,6 +137,40 @@ func (q *registryQuerier) ConsensusParameters(ctx context.Context) (*registry.Co
 	return q.state.ConsensusParameters(ctx)
 }
 
+func (q *registryQuerier) ValidateRuntimeUpdate(ctx context.Context, rt *registry.Runtime) error {
+	params, err := q.state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get consensus parameters: %w", err)
+	}
+	if params.DisableRuntimeRegistration {
+		return registry.ErrForbidden
+	}
+
+	height := q.height
+	if height == consensus.HeightLatest {
+		height = q.queryState.BlockHeight()
+	}
+	epoch, err := q.queryState.GetEpoch(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	consParams, err := q.consState.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get consensus parameters: %w", err)
+	}
+	features243 := consParams.IsFeatureVersion(migrations.Version243)
+
+	// Use the same verification as when registering the runtime, except for the checks that
+	// depend on the transaction signer.
+	logger := logging.GetLogger("cometbft/registry/query")
+	if err = verifyRuntime(ctx, logger, q.state, params, rt, epoch, false, features243); err != nil {
+		return err
+	}
+	_, _, err = verifyRuntimeChanges(ctx, logger, q.state, params, rt, epoch, false)
+	return err
+}
+
 // NewQueryFactory returns a new QueryFactory backed by the given state
 // instance.
 func NewQueryFactory(state abciAPI.ApplicationQueryState) *QueryFactory {
@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+func TestValidateRuntimeUpdate(t *testing.T) {
+	require := require.New(t)
+
+	cfg := abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 10,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	state := registryState.NewMutableState(ctx.State())
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		DebugAllowTestRuntimes: true,
+		MaxRuntimeDeployments:  20,
+		EnableRuntimeGovernanceModels: map[registry.RuntimeGovernanceModel]bool{
+			registry.GovernanceEntity: true,
+		},
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	consState := consensusState.NewMutableState(ctx.State())
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	newRuntime := func() *registry.Runtime {
+		return &registry.Runtime{
+			Versioned:       cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+			ID:              common.NewTestNamespaceFromSeed([]byte("consensus/cometbft/apps/registry: validate runtime update"), 0),
+			EntityID:        memorySigner.NewTestSigner("consensus/cometbft/apps/registry: validate runtime update").Public(),
+			Kind:            registry.KindCompute,
+			GovernanceModel: registry.GovernanceEntity,
+			Executor: registry.ExecutorParameters{
+				GroupSize:    1,
+				RoundTimeout: 5,
+			},
+			TxnScheduler: registry.TxnSchedulerParameters{
+				BatchFlushTimeout: time.Second,
+				MaxBatchSize:      100,
+				MaxBatchSizeBytes: 100_000_000,
+				ProposerTimeout:   2 * time.Second,
+			},
+			Deployments: []*registry.VersionInfo{
+				{
+					ValidFrom: 5,
+				},
+			},
+			AdmissionPolicy: registry.RuntimeAdmissionPolicy{
+				AnyNode: &registry.AnyNodeRuntimeAdmissionPolicy{},
+			},
+		}
+	}
+
+	q := &registryQuerier{
+		queryState: appState,
+		state:      state.ImmutableState,
+		consState:  consState.ImmutableState,
+		height:     consensus.HeightLatest,
+	}
+
+	// New runtimes must not be deployed immediately.
+	err = q.ValidateRuntimeUpdate(ctx, newRuntime())
+	require.ErrorIs(err, registry.ErrRuntimeUpdateNotAllowed)
+
+	err = state.SetRuntime(ctx, newRuntime(), false)
+	require.NoError(err, "SetRuntime")
+
+	// Adding a future deployment is valid.
+	rt := newRuntime()
+	rt.Deployments = append(rt.Deployments, &registry.VersionInfo{
+		Version:   version.Version{Major: 1},
+		ValidFrom: 20,
+	})
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.NoError(err, "adding a future deployment should be valid")
+
+	// Adding a retroactive deployment is invalid.
+	rt.Deployments[1].ValidFrom = 10
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.ErrorIs(err, registry.ErrRuntimeUpdateNotAllowed)
+
+	// Malformed TEE identities are invalid.
+	rt = newRuntime()
+	rt.Deployments = append(rt.Deployments, &registry.VersionInfo{
+		Version:   version.Version{Major: 1},
+		ValidFrom: 20,
+		TEE:       []byte("malformed"),
+	})
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.Error(err, "malformed TEE identity should be invalid")
+
+	// Malformed bundle checksums are invalid.
+	rt.Deployments[1].TEE = nil
+	rt.Deployments[1].BundleChecksum = []byte("malformed")
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.Error(err, "malformed bundle checksum should be invalid")
+
+	// Changing the runtime kind is invalid.
+	rt = newRuntime()
+	rt.Kind = registry.KindKeyManager
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.Error(err, "changing the runtime kind should be invalid")
+
+	// Features introduced in 24.3 are invalid before the feature version is enabled.
+	rt = newRuntime()
+	rt.Executor.FinalizationQuorumPercent = 60
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.ErrorIs(err, registry.ErrInvalidArgument)
+
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+	err = q.ValidateRuntimeUpdate(ctx, rt)
+	require.NoError(err, "finalization quorum should be valid once enabled")
+
+	// Validation must not modify the registered runtime.
+	registered, err := state.Runtime(ctx, rt.ID)
+	require.NoError(err, "Runtime")
+	require.Len(registered.Deployments, 1)
+}
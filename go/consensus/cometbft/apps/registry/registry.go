@@ -161,6 +161,20 @@ func (app *Application) ExecuteTx(ctx *api.Context, tx *transaction.Transaction)
 		}
 		return nil
 
+	case registry.MethodAuthorizeNode:
+		var authorize registry.AuthorizeNode
+		if err := cbor.Unmarshal(tx.Body, &authorize); err != nil {
+			return registry.ErrInvalidArgument
+		}
+		return app.authorizeNode(ctx, state, &authorize)
+
+	case registry.MethodRevokeNodeAuthorization:
+		var revoke registry.RevokeNodeAuthorization
+		if err := cbor.Unmarshal(tx.Body, &revoke); err != nil {
+			return registry.ErrInvalidArgument
+		}
+		return app.revokeNodeAuthorization(ctx, state, &revoke)
+
 	default:
 		return registry.ErrInvalidArgument
 	}
@@ -262,10 +276,35 @@ func (app *Application) onRegistryEpochChanged(ctx *api.Context, registryEpoch b
 		}
 	}
 
+	// Remove expired node authorizations.
+	auths, err := regState.NodeAuthorizations(ctx)
+	if err != nil {
+		return fmt.Errorf("registry: onRegistryEpochChanged: failed to get node authorizations: %w", err)
+	}
+	var expiredAuths []*registry.NodeAuthorization
+	for _, auth := range auths {
+		if !auth.IsExpired(registryEpoch) {
+			continue
+		}
+
+		ctx.Logger().Debug("removing expired node authorization",
+			"entity_id", auth.EntityID,
+			"node_id", auth.NodeID,
+		)
+		if err = regState.RemoveNodeAuthorization(ctx, auth.EntityID, auth.NodeID); err != nil {
+			return fmt.Errorf("registry: onRegistryEpochChanged: couldn't remove node authorization: %w", err)
+		}
+		expiredAuths = append(expiredAuths, auth)
+	}
+
 	// Emit the expired node event for all expired nodes.
 	for _, expiredNode := range expiredNodes {
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeEvent{Node: expiredNode, IsRegistration: false}))
 	}
+	// Emit the expired node authorization event for all expired authorizations.
+	for _, auth := range expiredAuths {
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeAuthorizationEvent{Authorization: auth, IsExpired: true}))
+	}
 	// Emit the node list epoch event.
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeListEpochEvent{}))
 
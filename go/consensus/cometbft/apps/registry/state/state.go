@@ -76,6 +76,11 @@ var (
 	//
 	// Value is empty.
 	runtimeByEntityKeyFmt = consensus.KeyFormat.New(0x19, keyformat.H(&signature.PublicKey{}), keyformat.H(&common.Namespace{}))
+	// nodeAuthorizationKeyFmt is the key format used for node authorizations
+	// (entity ID, node ID).
+	//
+	// Value is CBOR-serialized node authorization.
+	nodeAuthorizationKeyFmt = consensus.KeyFormat.New(0x1a, keyformat.H(&signature.PublicKey{}), keyformat.H(&signature.PublicKey{}))
 )
 
 // ImmutableState is an immutable registry state wrapper.
@@ -506,6 +511,85 @@ func (s *ImmutableState) NodeBySubKey(ctx context.Context, key signature.PublicK
 	return s.Node(ctx, id)
 }
 
+// NodeAuthorization looks up the given entity's authorization for the given node.
+func (s *ImmutableState) NodeAuthorization(ctx context.Context, entityID, nodeID signature.PublicKey) (*registry.NodeAuthorization, error) {
+	raw, err := s.state.Get(ctx, nodeAuthorizationKeyFmt.Encode(&entityID, &nodeID))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if raw == nil {
+		return nil, registry.ErrNoSuchNodeAuthorization
+	}
+
+	var auth registry.NodeAuthorization
+	if err := cbor.Unmarshal(raw, &auth); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &auth, nil
+}
+
+func (s *ImmutableState) iterateNodeAuthorizations(
+	ctx context.Context,
+	entityID *signature.PublicKey,
+	cb func(*registry.NodeAuthorization) error,
+) error {
+	it := s.state.NewIterator(ctx)
+	defer it.Close()
+
+	var hID keyformat.PreHashed
+	switch entityID {
+	case nil:
+		it.Seek(nodeAuthorizationKeyFmt.Encode())
+	default:
+		hID = keyformat.PreHashed(entityID.Hash())
+		it.Seek(nodeAuthorizationKeyFmt.Encode(entityID))
+	}
+	for ; it.Valid(); it.Next() {
+		var hEntityID keyformat.PreHashed
+		if !nodeAuthorizationKeyFmt.Decode(it.Key(), &hEntityID) {
+			break
+		}
+		if entityID != nil && !hEntityID.Equal(&hID) {
+			break
+		}
+
+		var auth registry.NodeAuthorization
+		if err := cbor.Unmarshal(it.Value(), &auth); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+		if err := cb(&auth); err != nil {
+			return err
+		}
+	}
+	return abciAPI.UnavailableStateError(it.Err())
+}
+
+// NodeAuthorizations returns a list of all node authorizations.
+func (s *ImmutableState) NodeAuthorizations(ctx context.Context) ([]*registry.NodeAuthorization, error) {
+	var auths []*registry.NodeAuthorization
+	err := s.iterateNodeAuthorizations(ctx, nil, func(auth *registry.NodeAuthorization) error {
+		auths = append(auths, auth)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auths, nil
+}
+
+// EntityNodeAuthorizations returns the node authorizations of the given entity.
+func (s *ImmutableState) EntityNodeAuthorizations(ctx context.Context, id signature.PublicKey) ([]*registry.NodeAuthorization, error) {
+	var auths []*registry.NodeAuthorization
+	err := s.iterateNodeAuthorizations(ctx, &id, func(auth *registry.NodeAuthorization) error {
+		auths = append(auths, auth)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auths, nil
+}
+
 // MutableState is a mutable registry state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -702,6 +786,18 @@ func (s *MutableState) SetNodeStatus(ctx context.Context, id signature.PublicKey
 	return abciAPI.UnavailableStateError(err)
 }
 
+// SetNodeAuthorization sets a node authorization.
+func (s *MutableState) SetNodeAuthorization(ctx context.Context, auth *registry.NodeAuthorization) error {
+	err := s.ms.Insert(ctx, nodeAuthorizationKeyFmt.Encode(&auth.EntityID, &auth.NodeID), cbor.Marshal(auth))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// RemoveNodeAuthorization removes a node authorization.
+func (s *MutableState) RemoveNodeAuthorization(ctx context.Context, entityID, nodeID signature.PublicKey) error {
+	err := s.ms.Remove(ctx, nodeAuthorizationKeyFmt.Encode(&entityID, &nodeID))
+	return abciAPI.UnavailableStateError(err)
+}
+
 // SetConsensusParameters sets registry consensus parameters.
 //
 // NOTE: This method must only be called from InitChain/EndBlock contexts.
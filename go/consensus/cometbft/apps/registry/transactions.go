@@ -1,19 +1,24 @@
 package registry
 
 import (
+	"context"
 	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
 	registryApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func (app *Application) registerEntity(
@@ -158,6 +163,21 @@ func (app *Application) deregisterEntity(ctx *api.Context, state *registryState.
 		return fmt.Errorf("DeregisterEntity: failed to remove entity: %w", err)
 	}
 
+	// Remove any node authorizations as they are meaningless without the entity.
+	auths, err := state.EntityNodeAuthorizations(ctx, id)
+	if err != nil {
+		ctx.Logger().Error("DeregisterEntity: failed to fetch node authorizations",
+			"err", err,
+		)
+		return err
+	}
+	for _, auth := range auths {
+		if err = state.RemoveNodeAuthorization(ctx, auth.EntityID, auth.NodeID); err != nil {
+			return fmt.Errorf("DeregisterEntity: failed to remove node authorization: %w", err)
+		}
+		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeAuthorizationEvent{Authorization: auth}))
+	}
+
 	stakeState := stakingState.NewMutableState(ctx.State())
 	stakeParams, err := stakeState.ConsensusParameters(ctx)
 	if err != nil {
@@ -244,6 +264,35 @@ func (app *Application) registerNode( // nolint: gocyclo
 		return err
 	}
 
+	// Allow the next TLS public key with the 24.3 release.
+	if newNode.TLS.NextPubKey != nil && !ctx.IsInitChain() {
+		var enabled bool
+		if enabled, err = features.IsFeatureVersion(ctx, migrations.Version243); err != nil {
+			return err
+		}
+		if !enabled {
+			return fmt.Errorf("%w: next TLS public key not enabled", registry.ErrInvalidArgument)
+		}
+	}
+
+	// Allow address priorities and enforce address limits with the 24.3 release.
+	addressLimitsEnabled := ctx.IsInitChain()
+	if !addressLimitsEnabled {
+		if addressLimitsEnabled, err = features.IsFeatureVersion(ctx, migrations.Version243); err != nil {
+			return err
+		}
+	}
+	switch addressLimitsEnabled {
+	case true:
+		if err = registry.VerifyNodeAddressLimits(newNode); err != nil {
+			return err
+		}
+	case false:
+		if hasAddressPriorities(newNode) {
+			return fmt.Errorf("%w: address priorities not enabled", registry.ErrInvalidArgument)
+		}
+	}
+
 	// Make sure the signer of the transaction is the node identity key.
 	// NOTE: If this is invoked during InitChain then there is no actual transaction
 	//       and thus no transaction signer so we must skip this check.
@@ -557,56 +606,187 @@ func (app *Application) unfreezeNode(
 	return nil
 }
 
-func (app *Application) registerRuntime( // nolint: gocyclo
+func (app *Application) authorizeNode(
 	ctx *api.Context,
 	state *registryState.MutableState,
-	rt *registry.Runtime,
-) (*registry.Runtime, error) {
+	authorize *registry.AuthorizeNode,
+) error {
+	// Allow node authorizations with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf("%w: node authorizations not enabled", registry.ErrInvalidArgument)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
 	params, err := state.ConsensusParameters(ctx)
 	if err != nil {
-		ctx.Logger().Error("RegisterRuntime: failed to fetch registry consensus parameters",
+		ctx.Logger().Error("AuthorizeNode: failed to fetch registry consensus parameters",
 			"err", err,
 		)
-		return nil, err
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, registry.GasOpAuthorizeNode, params.GasCosts); err != nil {
+		return err
 	}
 
-	if params.DisableRuntimeRegistration {
-		return nil, registry.ErrForbidden
+	// Return early if simulating since this is just estimating gas.
+	if ctx.IsSimulation() {
+		return nil
+	}
+
+	auth := &registry.NodeAuthorization{
+		EntityID:   ctx.TxSigner(),
+		NodeID:     authorize.NodeID,
+		Expiration: authorize.Expiration,
+	}
+	if err = auth.ValidateBasic(); err != nil {
+		return err
+	}
+
+	// Make sure that the authorizing entity exists.
+	if _, err = state.Entity(ctx, auth.EntityID); err != nil {
+		ctx.Logger().Debug("AuthorizeNode: failed to fetch entity",
+			"err", err,
+			"entity_id", auth.EntityID,
+		)
+		return err
 	}
 
+	// Make sure that the authorization is not already expired.
 	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	if err = registry.VerifyRuntime(params, ctx.Logger(), rt, ctx.IsInitChain(), false, epoch); err != nil {
-		return nil, err
+	if auth.IsExpired(epoch) {
+		return fmt.Errorf("%w: authorization expiration in the past", registry.ErrInvalidArgument)
 	}
 
-	if rt.Kind == registry.KindKeyManager && params.DisableKeyManagerRuntimeRegistration {
-		return nil, registry.ErrForbidden
+	if err = state.SetNodeAuthorization(ctx, auth); err != nil {
+		return fmt.Errorf("AuthorizeNode: failed to set node authorization: %w", err)
 	}
 
-	if rt.Kind == registry.KindCompute {
-		if err = registry.VerifyRegisterComputeRuntimeArgs(ctx, ctx.Logger(), rt, state); err != nil {
-			return nil, err
-		}
+	ctx.Logger().Debug("AuthorizeNode: authorized",
+		"entity_id", auth.EntityID,
+		"node_id", auth.NodeID,
+		"expiration", auth.Expiration,
+	)
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeAuthorizationEvent{Authorization: auth, IsGrant: true}))
+
+	return nil
+}
+
+func (app *Application) revokeNodeAuthorization(
+	ctx *api.Context,
+	state *registryState.MutableState,
+	revoke *registry.RevokeNodeAuthorization,
+) error {
+	// Allow revoking node authorizations with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf("%w: node authorizations not enabled", registry.ErrInvalidArgument)
 	}
 
 	if ctx.IsCheckOnly() {
-		return nil, nil
+		return nil
 	}
 
 	// Charge gas for this transaction.
-	if err = ctx.Gas().UseGas(1, registry.GasOpRegisterRuntime, params.GasCosts); err != nil {
-		return nil, err
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("RevokeNodeAuthorization: failed to fetch registry consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, registry.GasOpRevokeNodeAuthorization, params.GasCosts); err != nil {
+		return err
 	}
 
 	// Return early if simulating since this is just estimating gas.
 	if ctx.IsSimulation() {
-		return nil, nil
+		return nil
+	}
+
+	auth, err := state.NodeAuthorization(ctx, ctx.TxSigner(), revoke.NodeID)
+	if err != nil {
+		ctx.Logger().Debug("RevokeNodeAuthorization: failed to fetch node authorization",
+			"err", err,
+			"entity_id", ctx.TxSigner(),
+			"node_id", revoke.NodeID,
+		)
+		return err
 	}
 
+	if err = state.RemoveNodeAuthorization(ctx, auth.EntityID, auth.NodeID); err != nil {
+		return fmt.Errorf("RevokeNodeAuthorization: failed to remove node authorization: %w", err)
+	}
+
+	ctx.Logger().Debug("RevokeNodeAuthorization: revoked",
+		"entity_id", auth.EntityID,
+		"node_id", auth.NodeID,
+	)
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&registry.NodeAuthorizationEvent{Authorization: auth}))
+
+	return nil
+}
+
+// verifyRuntime verifies the given runtime descriptor as part of runtime registration.
+func verifyRuntime(
+	ctx context.Context,
+	logger *logging.Logger,
+	state *registryState.ImmutableState,
+	params *registry.ConsensusParameters,
+	rt *registry.Runtime,
+	epoch beacon.EpochTime,
+	isGenesis bool,
+	features243 bool,
+) error {
+	if err := registry.VerifyRuntime(params, logger, rt, isGenesis, false, epoch); err != nil {
+		return err
+	}
+
+	if err := verifyRuntimeFeatures(rt, features243); err != nil {
+		return err
+	}
+
+	if rt.Kind == registry.KindKeyManager && params.DisableKeyManagerRuntimeRegistration {
+		return registry.ErrForbidden
+	}
+
+	if rt.Kind == registry.KindCompute {
+		if err := registry.VerifyRegisterComputeRuntimeArgs(ctx, logger, rt, state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyRuntimeChanges verifies the given runtime descriptor against the currently registered
+// descriptor of the same runtime, if any.
+//
+// It returns the currently registered descriptor and whether the runtime is suspended.
+func verifyRuntimeChanges(
+	ctx context.Context,
+	logger *logging.Logger,
+	state *registryState.ImmutableState,
+	params *registry.ConsensusParameters,
+	rt *registry.Runtime,
+	epoch beacon.EpochTime,
+	isGenesis bool,
+) (*registry.Runtime, bool, error) {
 	// Make sure the runtime doesn't exist yet.
 	var suspended bool
 	existingRt, err := state.Runtime(ctx, rt.ID)
@@ -620,20 +800,109 @@ func (app *Application) registerRuntime( // nolint: gocyclo
 			suspended = true
 		case registry.ErrNoSuchRuntime:
 		default:
-			return nil, fmt.Errorf("failed to fetch suspended runtime: %w", err)
+			return nil, false, fmt.Errorf("failed to fetch suspended runtime: %w", err)
 		}
 	default:
-		return nil, fmt.Errorf("failed to fetch runtime: %w", err)
+		return nil, false, fmt.Errorf("failed to fetch runtime: %w", err)
 	}
 	// Invoke the right verification logic.
 	switch {
 	case existingRt != nil:
 		// Existing runtime, verify update.
-		err = registry.VerifyRuntimeUpdate(ctx.Logger(), existingRt, rt, epoch, params)
+		err = registry.VerifyRuntimeUpdate(logger, existingRt, rt, epoch, params)
 	default:
 		// New runtime, verify new descriptor.
-		err = registry.VerifyRuntimeNew(ctx.Logger(), rt, epoch, params, ctx.IsInitChain())
+		err = registry.VerifyRuntimeNew(logger, rt, epoch, params, isGenesis)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return existingRt, suspended, nil
+}
+
+// verifyRuntimeFeatures verifies that the runtime descriptor only uses the features that are
+// enabled by the consensus feature version.
+func verifyRuntimeFeatures(rt *registry.Runtime, features243 bool) error {
+	if features243 {
+		return nil
+	}
+
+	// Reject descriptor fields introduced in the 24.3 release.
+	switch {
+	case rt.MinNodeSoftwareVersion != nil:
+		return fmt.Errorf("%w: min node software version not enabled", registry.ErrInvalidArgument)
+	case rt.Executor.FinalizationQuorumPercent != 0:
+		return fmt.Errorf("%w: finalization quorum not enabled", registry.ErrInvalidArgument)
+	case rt.Executor.MaxInFlightRounds != 0:
+		return fmt.Errorf("%w: max in-flight rounds not enabled", registry.ErrInvalidArgument)
+	case hasDeploymentFeatures(rt):
+		return fmt.Errorf("%w: deployment features not enabled", registry.ErrInvalidArgument)
+	case rt.Executor.ElectionPolicy != scheduler.ElectionPolicyUniform, rt.Executor.ElectionMaxWeightPercent != 0:
+		return fmt.Errorf("%w: election policies not enabled", registry.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// hasDeploymentFeatures returns true iff any of the runtime deployments has feature flags set.
+func hasDeploymentFeatures(rt *registry.Runtime) bool {
+	for _, d := range rt.Deployments {
+		if d != nil && len(d.Features) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (app *Application) registerRuntime( // nolint: gocyclo
+	ctx *api.Context,
+	state *registryState.MutableState,
+	rt *registry.Runtime,
+) (*registry.Runtime, error) {
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		ctx.Logger().Error("RegisterRuntime: failed to fetch registry consensus parameters",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	if params.DisableRuntimeRegistration {
+		return nil, registry.ErrForbidden
 	}
+
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return nil, err
+	}
+
+	features243 := ctx.IsInitChain()
+	if !features243 {
+		if features243, err = features.IsFeatureVersion(ctx, migrations.Version243); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = verifyRuntime(ctx, ctx.Logger(), state.ImmutableState, params, rt, epoch, ctx.IsInitChain(), features243); err != nil {
+		return nil, err
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil, nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, registry.GasOpRegisterRuntime, params.GasCosts); err != nil {
+		return nil, err
+	}
+
+	// Return early if simulating since this is just estimating gas.
+	if ctx.IsSimulation() {
+		return nil, nil
+	}
+
+	existingRt, suspended, err := verifyRuntimeChanges(ctx, ctx.Logger(), state.ImmutableState, params, rt, epoch, ctx.IsInitChain())
 	if err != nil {
 		return nil, err
 	}
@@ -788,3 +1057,17 @@ func (app *Application) proveFreshness(
 
 	return nil
 }
+
+// hasAddressPriorities returns true iff the node descriptor assigns priorities to any of its
+// consensus or P2P addresses.
+func hasAddressPriorities(n *node.Node) bool {
+	if len(n.P2P.AddressPriorities) > 0 {
+		return true
+	}
+	for _, addr := range n.Consensus.Addresses {
+		if addr.Priority != 0 {
+			return true
+		}
+	}
+	return false
+}
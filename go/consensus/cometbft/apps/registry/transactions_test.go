@@ -15,12 +15,15 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestRegisterNode(t *testing.T) {
@@ -36,6 +39,7 @@ func TestRegisterNode(t *testing.T) {
 	state := registryState.NewMutableState(ctx.State())
 	stakeState := stakingState.NewMutableState(ctx.State())
 	beaconState := beaconState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
 
 	// Set up default staking consensus parameters.
 	defaultStakeParameters := staking.ConsensusParameters{
@@ -55,6 +59,8 @@ func TestRegisterNode(t *testing.T) {
 		MaxNodeExpiration: 5,
 	})
 	require.NoError(err, "registry.SetConsensusParameters")
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "consensus.SetConsensusParameters")
 
 	// Setup beacon consensus parameters.
 	err = beaconState.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
@@ -682,6 +688,11 @@ func TestRegisterRuntime(t *testing.T) {
 	state := registryState.NewMutableState(ctx.State())
 	stakeState := stakingState.NewMutableState(ctx.State())
 	beaconState := beaconState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+
+	// Set up default consensus parameters.
+	err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "consensus.SetConsensusParameters")
 
 	// Set up default staking consensus parameters.
 	defaultStakeParameters := staking.ConsensusParameters{
@@ -693,7 +704,7 @@ func TestRegisterRuntime(t *testing.T) {
 	}
 
 	// Set up registry consensus parameters.
-	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+	err = state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
 		DebugAllowTestRuntimes: true,
 		MaxRuntimeDeployments:  20,
 		EnableRuntimeGovernanceModels: map[registry.RuntimeGovernanceModel]bool{
@@ -757,6 +768,140 @@ func TestRegisterRuntime(t *testing.T) {
 			nil,
 			true,
 		},
+		// Test minimum node software version.
+		{
+			"Compute Runtime Min Node Software Version Not Enabled",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.MinNodeSoftwareVersion = &version.Version{Major: 24, Minor: 3}
+			},
+			nil,
+			false,
+		},
+		// Test finalization quorum.
+		{
+			"Compute Runtime Finalization Quorum Not Enabled",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.MinNodeSoftwareVersion = nil
+				tcd.runtime.Executor.FinalizationQuorumPercent = 67
+			},
+			nil,
+			false,
+		},
+		// Test round pipelining.
+		{
+			"Compute Runtime Max In-Flight Rounds Not Enabled",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Executor.FinalizationQuorumPercent = 0
+				tcd.runtime.Executor.MaxInFlightRounds = 2
+			},
+			nil,
+			false,
+		},
+		// Test deployment features.
+		{
+			"Compute Runtime Deployment Features Not Enabled",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Executor.FinalizationQuorumPercent = 0
+				tcd.runtime.Executor.MaxInFlightRounds = 0
+				tcd.runtime.Deployments = []*registry.VersionInfo{
+					{
+						ValidFrom: 100,
+						Features: map[string]string{
+							registry.FeatureStorageFetcherCount: "8",
+						},
+					},
+				}
+			},
+			nil,
+			false,
+		},
+		{
+			"Compute Runtime Min Node Software Version",
+			func(tcd *testCaseData) {
+				err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+					FeatureVersion: &migrations.Version243,
+				})
+				require.NoError(err, "consensus.SetConsensusParameters")
+
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.MinNodeSoftwareVersion = &version.Version{Major: 24, Minor: 3}
+				tcd.runtime.Executor.FinalizationQuorumPercent = 0
+			},
+			nil,
+			true,
+		},
+		{
+			"Compute Runtime Finalization Quorum",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Executor.FinalizationQuorumPercent = 67
+			},
+			nil,
+			true,
+		},
+		{
+			"Compute Runtime Max In-Flight Rounds",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Executor.MaxInFlightRounds = 2
+			},
+			nil,
+			true,
+		},
+		{
+			"Compute Runtime Deployment Features Unknown",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Deployments = []*registry.VersionInfo{
+					{
+						ValidFrom: 100,
+						Features: map[string]string{
+							"executor.unknown": "1",
+						},
+					},
+				}
+			},
+			nil,
+			false,
+		},
+		{
+			"Compute Runtime Deployment Features",
+			func(tcd *testCaseData) {
+				// Update previously registered runtime, which is now controlled by the new entity.
+				tcd.runtime = tcData["Compute Runtime Update Entity"].runtime
+				tcd.entitySigner = memorySigner.NewTestSigner("consensus/cometbft/apps/registry: runtime entity signer: Compute Runtime Update Entity")
+				tcd.runtime.Deployments = []*registry.VersionInfo{
+					{
+						ValidFrom: 100,
+						Features: map[string]string{
+							registry.FeatureStorageFetcherCount: "8",
+						},
+					},
+				}
+			},
+			nil,
+			true,
+		},
 		// TODO: add more tests in future.
 	}
 
@@ -893,3 +1038,406 @@ func TestProofFreshness(t *testing.T) {
 		require.Equal(registry.ErrInvalidArgument, err)
 	})
 }
+
+func TestNodeAuthorization(t *testing.T) {
+	require := requirePkg.New(t)
+
+	cfg := abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	var md abciAPI.NoopMessageDispatcher
+	app := Application{appState, &md}
+	state := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration: 5,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		DebugBypassStake: true,
+	})
+	require.NoError(err, "staking.SetConsensusParameters")
+
+	entitySigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization entity signer")
+	nodeSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization node signer")
+	consensusSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization consensus signer")
+	p2pSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization p2p signer")
+	tlsSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization tls signer")
+	vrfSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: node authorization vrf signer").(signature.VRFSigner)
+
+	authorize := func(expiration beacon.EpochTime) error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(entitySigner.Public())
+		return app.authorizeNode(txCtx, state, &registry.AuthorizeNode{
+			NodeID:     nodeSigner.Public(),
+			Expiration: expiration,
+		})
+	}
+	revoke := func() error {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(entitySigner.Public())
+		return app.revokeNodeAuthorization(txCtx, state, &registry.RevokeNodeAuthorization{
+			NodeID: nodeSigner.Public(),
+		})
+	}
+	registerNode := func() error {
+		var address node.Address
+		err = address.UnmarshalText([]byte("8.8.8.8:1234"))
+		require.NoError(err, "address.UnmarshalText")
+
+		n := node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entitySigner.Public(),
+			Expiration: uint64(cfg.CurrentEpoch) + 2,
+			P2P: node.P2PInfo{
+				ID:        p2pSigner.Public(),
+				Addresses: []node.Address{address},
+			},
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+				Addresses: []node.ConsensusAddress{
+					{ID: consensusSigner.Public(), Address: address},
+				},
+			},
+			TLS: node.TLSInfo{
+				PubKey: tlsSigner.Public(),
+			},
+			VRF: node.VRFInfo{
+				ID: vrfSigner.Public(),
+			},
+			Roles: node.RoleValidator,
+		}
+		signers := []signature.Signer{nodeSigner, p2pSigner, consensusSigner, tlsSigner, vrfSigner}
+		sigNode, err := node.MultiSignNode(signers, registry.RegisterNodeSignatureContext, &n)
+		require.NoError(err, "MultiSignNode")
+
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(nodeSigner.Public())
+		return app.registerNode(txCtx, state, sigNode)
+	}
+
+	// Node authorizations should not be accepted before the feature is enabled.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "consensus.SetConsensusParameters")
+	err = authorize(0)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "node authorizations should not be enabled")
+
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "consensus.SetConsensusParameters")
+
+	// The authorizing entity must exist.
+	err = authorize(0)
+	require.ErrorIs(err, registry.ErrNoSuchEntity, "authorization by a missing entity should fail")
+
+	// Register an entity that doesn't list the node in its descriptor.
+	ent := entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        entitySigner.Public(),
+	}
+	sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+	require.NoError(err, "SignEntity")
+	err = state.SetEntity(ctx, &ent, sigEnt)
+	require.NoError(err, "SetEntity")
+
+	err = registerNode()
+	require.ErrorIs(err, registry.ErrInvalidArgument, "registration of an unauthorized node should fail")
+
+	// Authorizations that are already expired should be rejected.
+	err = authorize(cfg.CurrentEpoch)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "expired authorization should be rejected")
+
+	err = authorize(cfg.CurrentEpoch + 2)
+	require.NoError(err, "AuthorizeNode")
+	auths, err := state.EntityNodeAuthorizations(ctx, ent.ID)
+	require.NoError(err, "EntityNodeAuthorizations")
+	require.Len(auths, 1)
+	require.EqualValues(cfg.CurrentEpoch+2, auths[0].Expiration)
+
+	err = registerNode()
+	require.NoError(err, "registration of an authorized node should succeed")
+
+	// Re-registration should fail with a clear error once the authorization expires (before the
+	// expiration is processed at the epoch transition).
+	cfg.CurrentEpoch += 2
+	err = registerNode()
+	require.ErrorIs(err, registry.ErrNodeAuthorizationExpired, "registration with an expired authorization should fail")
+
+	// Expired authorizations should be removed at the epoch transition.
+	err = app.onRegistryEpochChanged(ctx, cfg.CurrentEpoch)
+	require.NoError(err, "onRegistryEpochChanged")
+	_, err = state.NodeAuthorization(ctx, ent.ID, nodeSigner.Public())
+	require.ErrorIs(err, registry.ErrNoSuchNodeAuthorization, "expired authorization should be removed")
+
+	// Authorizations without expiration remain valid until revoked.
+	err = authorize(0)
+	require.NoError(err, "AuthorizeNode")
+	err = app.onRegistryEpochChanged(ctx, cfg.CurrentEpoch+100)
+	require.NoError(err, "onRegistryEpochChanged")
+	err = registerNode()
+	require.NoError(err, "registration of an authorized node should succeed")
+
+	err = revoke()
+	require.NoError(err, "RevokeNodeAuthorization")
+	err = revoke()
+	require.ErrorIs(err, registry.ErrNoSuchNodeAuthorization, "revoking a missing authorization should fail")
+	err = registerNode()
+	require.ErrorIs(err, registry.ErrInvalidArgument, "registration after revocation should fail")
+}
+
+func TestRegisterNodeNextTLSKey(t *testing.T) {
+	require := requirePkg.New(t)
+
+	cfg := abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	var md abciAPI.NoopMessageDispatcher
+	app := Application{appState, &md}
+	state := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+	beaconState := beaconState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration: 5,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		DebugBypassStake: true,
+	})
+	require.NoError(err, "staking.SetConsensusParameters")
+	err = beaconState.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend: beacon.BackendInsecure,
+	})
+	require.NoError(err, "beacon.SetConsensusParameters")
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "consensus.SetConsensusParameters")
+
+	entitySigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key entity signer")
+	nodeSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key node signer")
+	consensusSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key consensus signer")
+	p2pSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key p2p signer")
+	tlsSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key tls signer")
+	nextTLSSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key next tls signer")
+	vrfSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: next tls key vrf signer").(signature.VRFSigner)
+
+	// Register an entity that lists the node in its descriptor.
+	ent := entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        entitySigner.Public(),
+		Nodes:     []signature.PublicKey{nodeSigner.Public()},
+	}
+	sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+	require.NoError(err, "SignEntity")
+	err = state.SetEntity(ctx, &ent, sigEnt)
+	require.NoError(err, "SetEntity")
+
+	registerNode := func(withNextKey bool) error {
+		var address node.Address
+		err = address.UnmarshalText([]byte("8.8.8.8:1234"))
+		require.NoError(err, "address.UnmarshalText")
+
+		n := node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entitySigner.Public(),
+			Expiration: uint64(cfg.CurrentEpoch) + 2,
+			P2P: node.P2PInfo{
+				ID:        p2pSigner.Public(),
+				Addresses: []node.Address{address},
+			},
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+				Addresses: []node.ConsensusAddress{
+					{ID: consensusSigner.Public(), Address: address},
+				},
+			},
+			TLS: node.TLSInfo{
+				PubKey: tlsSigner.Public(),
+			},
+			VRF: node.VRFInfo{
+				ID: vrfSigner.Public(),
+			},
+			Roles: node.RoleValidator,
+		}
+		signers := []signature.Signer{nodeSigner, p2pSigner, consensusSigner, tlsSigner, vrfSigner}
+		if withNextKey {
+			nextPubKey := nextTLSSigner.Public()
+			n.TLS.NextPubKey = &nextPubKey
+			signers = append(signers, nextTLSSigner)
+		}
+		sigNode, err := node.MultiSignNode(signers, registry.RegisterNodeSignatureContext, &n)
+		require.NoError(err, "MultiSignNode")
+
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(nodeSigner.Public())
+		return app.registerNode(txCtx, state, sigNode)
+	}
+
+	// The next TLS public key should not be accepted before the feature is enabled.
+	err = registerNode(true)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "next TLS public key should not be enabled")
+	err = registerNode(false)
+	require.NoError(err, "registration without the next TLS public key should succeed")
+
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "consensus.SetConsensusParameters")
+	err = state.SetNodeAuthorization(ctx, &registry.NodeAuthorization{
+		EntityID: ent.ID,
+		NodeID:   nodeSigner.Public(),
+	})
+	require.NoError(err, "SetNodeAuthorization")
+
+	err = registerNode(true)
+	require.NoError(err, "registration with the next TLS public key should succeed")
+}
+
+func TestRegisterNodeAddressLimits(t *testing.T) {
+	require := requirePkg.New(t)
+
+	cfg := abciAPI.MockApplicationStateConfig{
+		CurrentEpoch: 5,
+	}
+	appState := abciAPI.NewMockApplicationState(&cfg)
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	var md abciAPI.NoopMessageDispatcher
+	app := Application{appState, &md}
+	state := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+	beaconState := beaconState.NewMutableState(ctx.State())
+
+	err := state.SetConsensusParameters(ctx, &registry.ConsensusParameters{
+		MaxNodeExpiration: 5,
+	})
+	require.NoError(err, "registry.SetConsensusParameters")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		DebugBypassStake: true,
+	})
+	require.NoError(err, "staking.SetConsensusParameters")
+	err = beaconState.SetConsensusParameters(ctx, &beacon.ConsensusParameters{
+		Backend: beacon.BackendInsecure,
+	})
+	require.NoError(err, "beacon.SetConsensusParameters")
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "consensus.SetConsensusParameters")
+
+	entitySigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits entity signer")
+	nodeSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits node signer")
+	consensusSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits consensus signer")
+	p2pSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits p2p signer")
+	tlsSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits tls signer")
+	vrfSigner := memorySigner.NewTestSigner("consensus/cometbft/apps/registry: address limits vrf signer").(signature.VRFSigner)
+
+	// Register an entity that lists the node in its descriptor.
+	ent := entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        entitySigner.Public(),
+		Nodes:     []signature.PublicKey{nodeSigner.Public()},
+	}
+	sigEnt, err := entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, &ent)
+	require.NoError(err, "SignEntity")
+	err = state.SetEntity(ctx, &ent, sigEnt)
+	require.NoError(err, "SetEntity")
+
+	var addresses []node.Address
+	for _, addr := range []string{"8.8.8.8:1234", "8.8.4.4:1234"} {
+		var address node.Address
+		err = address.UnmarshalText([]byte(addr))
+		require.NoError(err, "address.UnmarshalText")
+		addresses = append(addresses, address)
+	}
+
+	registerNode := func(fn func(n *node.Node)) error {
+		n := node.Node{
+			Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:         nodeSigner.Public(),
+			EntityID:   entitySigner.Public(),
+			Expiration: uint64(cfg.CurrentEpoch) + 2,
+			P2P: node.P2PInfo{
+				ID:        p2pSigner.Public(),
+				Addresses: addresses,
+			},
+			Consensus: node.ConsensusInfo{
+				ID: consensusSigner.Public(),
+				Addresses: []node.ConsensusAddress{
+					{ID: consensusSigner.Public(), Address: addresses[0]},
+					{ID: consensusSigner.Public(), Address: addresses[1]},
+				},
+			},
+			TLS: node.TLSInfo{
+				PubKey: tlsSigner.Public(),
+			},
+			VRF: node.VRFInfo{
+				ID: vrfSigner.Public(),
+			},
+			Roles: node.RoleValidator,
+		}
+		fn(&n)
+		signers := []signature.Signer{nodeSigner, p2pSigner, consensusSigner, tlsSigner, vrfSigner}
+		sigNode, err := node.MultiSignNode(signers, registry.RegisterNodeSignatureContext, &n)
+		require.NoError(err, "MultiSignNode")
+
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(nodeSigner.Public())
+		return app.registerNode(txCtx, state, sigNode)
+	}
+
+	withConsensusPriorities := func(n *node.Node) {
+		n.Consensus.Addresses[1].Priority = 1
+	}
+	withP2PPriorities := func(n *node.Node) {
+		n.P2P.AddressPriorities = []uint8{0, 1}
+	}
+	withDuplicates := func(n *node.Node) {
+		n.P2P.Addresses = append(n.P2P.Addresses, n.P2P.Addresses[0])
+		n.Consensus.Addresses = append(n.Consensus.Addresses, n.Consensus.Addresses[0])
+	}
+
+	// Address priorities should not be accepted before the feature is enabled.
+	err = registerNode(withConsensusPriorities)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "consensus address priorities should not be enabled")
+	err = registerNode(withP2PPriorities)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "P2P address priorities should not be enabled")
+	// Address limits should not be enforced before the feature is enabled.
+	err = registerNode(withDuplicates)
+	require.NoError(err, "registration with duplicate addresses should succeed before the feature is enabled")
+
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "consensus.SetConsensusParameters")
+
+	err = registerNode(withDuplicates)
+	require.ErrorIs(err, registry.ErrInvalidArgument, "registration with duplicate addresses should fail")
+	err = registerNode(func(n *node.Node) {
+		n.P2P.AddressPriorities = []uint8{0}
+	})
+	require.ErrorIs(err, registry.ErrInvalidArgument, "mismatched P2P address priorities should be rejected")
+	err = registerNode(func(n *node.Node) {
+		withConsensusPriorities(n)
+		withP2PPriorities(n)
+	})
+	require.NoError(err, "registration with address priorities should succeed")
+}
@@ -11,11 +11,14 @@ import (
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func (app *Application) tryFinalizeRounds(
@@ -78,7 +81,19 @@ func (app *Application) tryFinalizeRoundInsideTx( //nolint: gocyclo
 	}
 	livenessStats := rtState.LivenessStatistics
 
-	sc, err := pool.ProcessCommitments(rtState.Committee, rtState.Runtime.Executor.AllowedStragglers, timeout)
+	// Detect discrepancies before the scheduler commits with the 24.3 release.
+	earlyDiscrepancy, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return err
+	}
+
+	sc, err := pool.ProcessCommitmentsWithQuorum(
+		rtState.Committee,
+		rtState.Runtime.Executor.AllowedStragglers,
+		rtState.Runtime.Executor.FinalizationQuorumPercent,
+		earlyDiscrepancy,
+		timeout,
+	)
 	switch err {
 	case commitment.ErrDiscrepancyDetected:
 		ctx.Logger().Warn("executor discrepancy detected",
@@ -111,7 +126,13 @@ func (app *Application) tryFinalizeRoundInsideTx( //nolint: gocyclo
 		timeout = rtState.NextTimeout == ctx.BlockHeight()+1 // Current height is ctx.BlockHeight() + 1
 
 		// Retry as we may be able to already perform discrepancy resolution.
-		sc, err = pool.ProcessCommitments(rtState.Committee, rtState.Runtime.Executor.AllowedStragglers, timeout)
+		sc, err = pool.ProcessCommitmentsWithQuorum(
+			rtState.Committee,
+			rtState.Runtime.Executor.AllowedStragglers,
+			rtState.Runtime.Executor.FinalizationQuorumPercent,
+			earlyDiscrepancy,
+			timeout,
+		)
 	}
 
 	switch err {
@@ -270,13 +291,80 @@ func (app *Application) tryFinalizeRoundInsideTx( //nolint: gocyclo
 		Messages:            msgEvents,
 		GoodComputeEntities: goodComputeEntities,
 		BadComputeEntities:  badComputeEntities,
+		BatchSummary:        sc.Commitment.Header.Header.BatchSummary,
 	}
 	if err = state.SetLastRoundResults(ctx, rtState.Runtime.ID, &results); err != nil {
 		return fmt.Errorf("failed to set last round results: %w", err)
 	}
 
 	// Generate the final block.
-	return app.finalizeBlock(ctx, rtState, block.Normal, &sc.Commitment.Header.Header)
+	parentBlk := rtState.LastBlock
+	if err = app.finalizeBlock(ctx, rtState, block.Normal, &sc.Commitment.Header.Header); err != nil {
+		return err
+	}
+
+	// In case the round has been finalized early or the late commitment window is configured,
+	// keep accepting commitments from the remaining workers so that they can be accounted for
+	// liveness and slashing.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get consensus parameters: %w", err)
+	}
+	rtState.LateCommitments = lateCommitments(ctx, rtState, params, pool, sc, parentBlk)
+
+	return nil
+}
+
+// lateCommitments returns the late commitments tracker for a round that has been finalized or nil
+// in case late commitments are not accepted or all workers have already submitted their
+// commitments.
+func lateCommitments(
+	ctx *tmapi.Context,
+	rtState *roothash.RuntimeState,
+	params *roothash.ConsensusParameters,
+	pool *commitment.Pool,
+	sc *commitment.SchedulerCommitment,
+	parentBlk *block.Block,
+) *roothash.LateCommitments {
+	var deadline int64
+	switch {
+	case pool.Discrepancy:
+		return nil
+	case rtState.Runtime.Executor.FinalizationQuorumPercent != 0:
+		// Commitments are accepted until the next round is finalized.
+	case params.MaxLateCommitmentBlocks != 0:
+		deadline = ctx.BlockHeight() + 1 + int64(params.MaxLateCommitmentBlocks) // Current height is ctx.BlockHeight() + 1
+	default:
+		return nil
+	}
+
+	var pending []signature.PublicKey
+	seen := make(map[signature.PublicKey]struct{})
+	for _, n := range rtState.Committee.Members {
+		if n.Role != scheduler.RoleWorker {
+			continue
+		}
+		if _, ok := sc.Votes[n.PublicKey]; ok {
+			continue
+		}
+		if _, ok := seen[n.PublicKey]; ok {
+			continue
+		}
+		seen[n.PublicKey] = struct{}{}
+
+		pending = append(pending, n.PublicKey)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	return &roothash.LateCommitments{
+		Block:       parentBlk,
+		SchedulerID: sc.Commitment.Header.SchedulerID,
+		Vote:        sc.Commitment.ToVote(),
+		Pending:     pending,
+		Deadline:    deadline,
+	}
 }
 
 func (app *Application) finalizeBlock(ctx *tmapi.Context, rtState *roothash.RuntimeState, hdrType block.HeaderType, hdr *commitment.ComputeResultsHeader) error {
@@ -317,6 +405,7 @@ func (app *Application) finalizeBlock(ctx *tmapi.Context, rtState *roothash.Runt
 	)
 
 	// Reset scheduler commitments.
+	rtState.LateCommitments = nil
 	switch hdrType {
 	case block.Suspended:
 		rtState.CommitmentPool = nil
@@ -58,7 +58,9 @@ func processLivenessStatistics(ctx *tmapi.Context, epoch beacon.EpochTime, rtSta
 			continue
 		}
 
-		liveRounds := rtState.LivenessStatistics.LiveRounds[i]
+		// Rounds with correct commitments within the late commitment window also count towards
+		// liveness.
+		liveRounds := rtState.LivenessStatistics.LiveRounds[i] + rtState.LivenessStatistics.LateRoundsAt(i)
 		finalizedProposals := rtState.LivenessStatistics.FinalizedProposals[i]
 		missedProposals := rtState.LivenessStatistics.MissedProposals[i]
 
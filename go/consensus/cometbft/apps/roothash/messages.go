@@ -12,10 +12,12 @@ import (
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func fetchRuntimeMessages(
@@ -211,6 +213,17 @@ func (app *Application) changeParameters(ctx *tmapi.Context, msg any, apply bool
 		return nil, fmt.Errorf("roothash: failed to unmarshal consensus parameter changes: %w", err)
 	}
 
+	// Allow changing the late commitment window with the 24.3 release.
+	if changes.MaxLateCommitmentBlocks != nil {
+		enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("%w: late commitment window not enabled", roothash.ErrInvalidArgument)
+		}
+	}
+
 	// Validate changes against current parameters.
 	state := roothashState.NewMutableState(ctx.State())
 	params, err := state.ConsensusParameters(ctx)
@@ -7,12 +7,15 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestChangeParameters(t *testing.T) {
@@ -92,6 +95,52 @@ func TestChangeParameters(t *testing.T) {
 	})
 }
 
+func TestChangeMaxLateCommitmentBlocks(t *testing.T) {
+	require := require.New(t)
+
+	// Prepare context.
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	// Setup state.
+	consState := consensusState.NewMutableState(ctx.State())
+	err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+	state := roothashState.NewMutableState(ctx.State())
+	err = state.SetConsensusParameters(ctx, &roothash.ConsensusParameters{})
+	require.NoError(err, "setting consensus parameters should succeed")
+	app := &Application{
+		state: appState,
+	}
+
+	// Prepare proposal.
+	maxLateCommitmentBlocks := uint64(10)
+	proposal := governance.ChangeParametersProposal{
+		Module: roothash.ModuleName,
+		Changes: cbor.Marshal(roothash.ConsensusParameterChanges{
+			MaxLateCommitmentBlocks: &maxLateCommitmentBlocks,
+		}),
+	}
+
+	// Changes should be rejected before the feature is enabled.
+	_, err = app.changeParameters(ctx, &proposal, false)
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "changing the late commitment window should fail")
+
+	// With the feature enabled, changes should be applied.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	_, err = app.changeParameters(ctx, &proposal, true)
+	require.NoError(err, "changing the late commitment window should succeed")
+
+	params, err := state.ConsensusParameters(ctx)
+	require.NoError(err, "fetching consensus parameters should succeed")
+	require.Equal(maxLateCommitmentBlocks, params.MaxLateCommitmentBlocks, "consensus parameters should change")
+}
+
 func initRuntimeGenesisBlock(require *require.Assertions, ctx *abciAPI.Context, id int) (*registry.Runtime, *block.Block) {
 	var runtime registry.Runtime
 	err := runtime.ID.UnmarshalHex(fmt.Sprintf("8%0*d", 63, id))
@@ -21,10 +21,12 @@ import (
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
 	stakingapp "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 // Application is a roothash application.
@@ -193,9 +195,17 @@ func (app *Application) onCommitteeChanged(ctx *tmapi.Context, state *roothashSt
 
 		switch suspend {
 		case true:
+			reason := roothash.SuspensionReasonInsufficientStake
+			if committee == nil {
+				if reason, err = suspensionReasonNoCommittee(ctx, regState, rt); err != nil {
+					return err
+				}
+			}
+
 			ctx.Logger().Debug("suspending runtime, maintenance fees not paid or owner debonded",
 				"runtime_id", rt.ID,
 				"epoch", epoch,
+				"reason", reason,
 			)
 
 			if err = regState.SuspendRuntime(ctx, rt.ID); err != nil {
@@ -207,7 +217,9 @@ func (app *Application) onCommitteeChanged(ctx *tmapi.Context, state *roothashSt
 				return fmt.Errorf("failed to emit empty block: %w", err)
 			}
 
-			rtState.Suspended = true
+			if err = app.setSuspended(ctx, rtState, reason); err != nil {
+				return err
+			}
 			rtState.Committee = nil
 		case false:
 			ctx.Logger().Debug("updating committee for runtime",
@@ -223,7 +235,9 @@ func (app *Application) onCommitteeChanged(ctx *tmapi.Context, state *roothashSt
 			}
 
 			// Warning: Non-suspended runtimes can still have a nil committee.
-			rtState.Suspended = false
+			if rtState.Suspended {
+				app.setResumed(ctx, rtState)
+			}
 			rtState.Committee = committee
 		}
 
@@ -240,6 +254,56 @@ func (app *Application) onCommitteeChanged(ctx *tmapi.Context, state *roothashSt
 	return nil
 }
 
+// suspensionReasonNoCommittee determines the reason for suspending a runtime for which no executor
+// committee has been elected.
+func suspensionReasonNoCommittee(ctx *tmapi.Context, regState *registryState.MutableState, rt *registry.Runtime) (roothash.SuspensionReason, error) {
+	if rt.KeyManager != nil {
+		_, err := regState.Runtime(ctx, *rt.KeyManager)
+		switch err {
+		case nil:
+		case registry.ErrNoSuchRuntime:
+			return roothash.SuspensionReasonMissingKeyManager, nil
+		default:
+			return roothash.SuspensionReasonNone, fmt.Errorf("failed to fetch key manager runtime: %w", err)
+		}
+	}
+	return roothash.SuspensionReasonInsufficientCommittee, nil
+}
+
+// setSuspended marks the runtime as suspended for the given reason and emits a runtime suspended
+// event.
+func (app *Application) setSuspended(ctx *tmapi.Context, rtState *roothash.RuntimeState, reason roothash.SuspensionReason) error {
+	rtState.Suspended = true
+
+	// Record suspension reasons in state with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		rtState.SuspensionReason = reason
+	}
+
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			TypedAttribute(&roothash.RuntimeSuspendedEvent{Reason: reason}).
+			TypedAttribute(&roothash.RuntimeIDAttribute{ID: rtState.Runtime.ID}),
+	)
+	return nil
+}
+
+// setResumed marks the runtime as no longer suspended and emits a runtime resumed event.
+func (app *Application) setResumed(ctx *tmapi.Context, rtState *roothash.RuntimeState) {
+	rtState.Suspended = false
+	rtState.SuspensionReason = roothash.SuspensionReasonNone
+
+	ctx.EmitEvent(
+		tmapi.NewEventBuilder(app.Name()).
+			TypedAttribute(&roothash.RuntimeResumedEvent{}).
+			TypedAttribute(&roothash.RuntimeIDAttribute{ID: rtState.Runtime.ID}),
+	)
+}
+
 // ExecuteMessage implements api.MessageSubscriber.
 func (app *Application) ExecuteMessage(ctx *tmapi.Context, kind, msg any) (any, error) {
 	switch kind {
@@ -376,18 +440,21 @@ func (app *Application) onNewRuntime(ctx *tmapi.Context, runtime *registry.Runti
 		}
 	}
 
-	// Create new state containing the genesis block.
-	err = state.SetRuntimeState(ctx, &roothash.RuntimeState{
+	// Create new state containing the genesis block. The runtime remains suspended until an
+	// executor committee is elected for it.
+	rtState := &roothash.RuntimeState{
 		Runtime:          runtime,
-		Suspended:        true,
 		LastBlock:        genesisBlock,
 		LastBlockHeight:  ctx.BlockHeight() + 1, // Current height is ctx.BlockHeight() + 1
 		LastNormalRound:  genesisBlock.Header.Round,
 		LastNormalHeight: ctx.BlockHeight() + 1, // Current height is ctx.BlockHeight() + 1
 		GenesisBlock:     genesisBlock,
 		NextTimeout:      roothash.TimeoutNever,
-	})
-	if err != nil {
+	}
+	if err = app.setSuspended(ctx, rtState, roothash.SuspensionReasonInsufficientCommittee); err != nil {
+		return err
+	}
+	if err = state.SetRuntimeState(ctx, rtState); err != nil {
 		return fmt.Errorf("failed to set runtime state: %w", err)
 	}
 
@@ -0,0 +1,110 @@
+package roothash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
+)
+
+func TestSuspension(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	app := &Application{
+		state: appState,
+	}
+
+	consState := consensusState.NewMutableState(ctx.State())
+	err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	rtState := &roothash.RuntimeState{
+		Runtime: &registry.Runtime{
+			ID: common.NewTestNamespaceFromSeed([]byte("roothash/suspension: runtime"), 0),
+		},
+	}
+
+	// Without the feature enabled, the reason should only be emitted in the event.
+	err = app.setSuspended(ctx, rtState, roothash.SuspensionReasonInsufficientStake)
+	require.NoError(err, "setSuspended")
+	require.True(rtState.Suspended, "runtime should be suspended")
+	require.Equal(roothash.SuspensionReasonNone, rtState.SuspensionReason, "reason should not be recorded")
+	require.True(ctx.HasEvent(app.Name(), &roothash.RuntimeSuspendedEvent{}), "suspended event should be emitted")
+
+	var ev roothash.RuntimeSuspendedEvent
+	err = ctx.DecodeEvent(0, &ev)
+	require.NoError(err, "DecodeEvent")
+	require.Equal(roothash.SuspensionReasonInsufficientStake, ev.Reason, "event should contain the reason")
+
+	// With the feature enabled, the reason should also be recorded in state.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	err = app.setSuspended(ctx, rtState, roothash.SuspensionReasonInsufficientCommittee)
+	require.NoError(err, "setSuspended")
+	require.True(rtState.Suspended, "runtime should be suspended")
+	require.Equal(roothash.SuspensionReasonInsufficientCommittee, rtState.SuspensionReason, "reason should be recorded")
+
+	app.setResumed(ctx, rtState)
+	require.False(rtState.Suspended, "runtime should be resumed")
+	require.Equal(roothash.SuspensionReasonNone, rtState.SuspensionReason, "reason should be cleared")
+	require.True(ctx.HasEvent(app.Name(), &roothash.RuntimeResumedEvent{}), "resumed event should be emitted")
+}
+
+func TestSuspensionReasonNoCommittee(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	regState := registryState.NewMutableState(ctx.State())
+
+	kmRuntime := &registry.Runtime{
+		ID:   common.NewTestNamespaceFromSeed([]byte("roothash/suspension: key manager"), common.NamespaceKeyManager),
+		Kind: registry.KindKeyManager,
+	}
+	runtime := &registry.Runtime{
+		ID:         common.NewTestNamespaceFromSeed([]byte("roothash/suspension: runtime"), 0),
+		Kind:       registry.KindCompute,
+		KeyManager: &kmRuntime.ID,
+	}
+
+	// Runtime without a key manager.
+	reason, err := suspensionReasonNoCommittee(ctx, regState, &registry.Runtime{ID: runtime.ID})
+	require.NoError(err, "suspensionReasonNoCommittee")
+	require.Equal(roothash.SuspensionReasonInsufficientCommittee, reason)
+
+	// Runtime with a key manager that is not registered.
+	reason, err = suspensionReasonNoCommittee(ctx, regState, runtime)
+	require.NoError(err, "suspensionReasonNoCommittee")
+	require.Equal(roothash.SuspensionReasonMissingKeyManager, reason)
+
+	// Runtime with a key manager that is suspended.
+	err = regState.SetRuntime(ctx, kmRuntime, true)
+	require.NoError(err, "SetRuntime")
+	reason, err = suspensionReasonNoCommittee(ctx, regState, runtime)
+	require.NoError(err, "suspensionReasonNoCommittee")
+	require.Equal(roothash.SuspensionReasonMissingKeyManager, reason)
+
+	// Runtime with an available key manager.
+	err = regState.SetRuntime(ctx, kmRuntime, false)
+	require.NoError(err, "SetRuntime")
+	reason, err = suspensionReasonNoCommittee(ctx, regState, runtime)
+	require.NoError(err, "suspensionReasonNoCommittee")
+	require.Equal(roothash.SuspensionReasonInsufficientCommittee, reason)
+}
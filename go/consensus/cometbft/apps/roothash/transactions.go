@@ -10,10 +10,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
+	p2pError "github.com/oasisprotocol/oasis-core/go/p2p/error"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 // getRuntimeState fetches the current runtime state and performs common
@@ -91,9 +95,60 @@ func (app *Application) executorCommit(
 		return msgErr
 	}
 
+	// Batch verify all commitment signatures upfront.
+	sigErrs := commitment.VerifyExecutorCommitmentSignatures(cc.ID, cc.Commits)
+
+	// Entities which submitted late commitments that conflict with the finalized round.
+	var incorrectEntities []signature.PublicKey
+	// Nodes which submitted correct commitments within the late commitment window.
+	var lateCommitted []signature.PublicKey
+
 	// Verify and add commitments to the pool.
-	for _, commit := range cc.Commits {
-		if err = commitment.VerifyExecutorCommitment(ctx, rtState.LastBlock, rtState.Runtime, rtState.Committee.ValidFor, &commit, msgGasAccountant, nl); err != nil { // nolint: gosec
+	for i, commit := range cc.Commits {
+		if err = sigErrs[i]; err != nil {
+			ctx.Logger().Debug("failed to verify executor commitment signature",
+				"err", err,
+				"runtime_id", cc.ID,
+				"round", commit.Header.Header.Round,
+			)
+			return p2pError.Permanent(err)
+		}
+
+		// Allow batch summaries in executor commitments with the 24.3 release.
+		if commit.Header.Header.BatchSummary != nil {
+			var enabled bool
+			if enabled, err = features.IsFeatureVersion(ctx, migrations.Version243); err != nil {
+				return err
+			}
+			if !enabled {
+				ctx.Logger().Debug("executor commitment includes batch summary before it is enabled",
+					"runtime_id", cc.ID,
+					"round", commit.Header.Header.Round,
+				)
+				return fmt.Errorf("%w: batch summary not enabled", roothash.ErrInvalidArgument)
+			}
+		}
+
+		// Commitments for a round that has already been finalized are only used for liveness
+		// accounting and slashing as they can no longer affect the finalized block.
+		if lc := rtState.LateCommitments; lc != nil && commit.Header.Header.Round == rtState.LastBlock.Header.Round && lc.Accepts(ctx.BlockHeight()+1) { // Current height is ctx.BlockHeight() + 1
+			var (
+				entityID *signature.PublicKey
+				late     bool
+			)
+			if entityID, late, err = app.processLateCommitment(ctx, rtState, &commit, nl); err != nil { // nolint: gosec
+				return err
+			}
+			if entityID != nil {
+				incorrectEntities = append(incorrectEntities, *entityID)
+			}
+			if late {
+				lateCommitted = append(lateCommitted, commit.NodeID)
+			}
+			continue
+		}
+
+		if err = commitment.VerifyExecutorCommitmentContent(ctx, rtState.LastBlock, rtState.Runtime, rtState.Committee.ValidFor, &commit, msgGasAccountant, nl); err != nil { // nolint: gosec
 			ctx.Logger().Debug("failed to verify executor commitment",
 				"err", err,
 				"runtime_id", cc.ID,
@@ -131,6 +186,13 @@ func (app *Application) executorCommit(
 
 	state = roothashState.NewMutableState(ctx.State())
 
+	// Slash entities for late commitments with incorrect results if configured.
+	if penalty, ok := rtState.Runtime.Staking.Slashing[staking.SlashRuntimeIncorrectResults]; ok && len(incorrectEntities) > 0 {
+		if err = onRuntimeIncorrectResults(ctx, incorrectEntities, nil, rtState.Runtime, &penalty.Amount); err != nil {
+			return fmt.Errorf("failed to slash for incorrect results: %w", err)
+		}
+	}
+
 	// Check if higher-ranked scheduler submitted a commitment.
 	if prevRank != rtState.CommitmentPool.HighestRank {
 		round := rtState.LastBlock.Header.Round + 1
@@ -164,6 +226,16 @@ func (app *Application) executorCommit(
 				TypedAttribute(&roothash.RuntimeIDAttribute{ID: cc.ID}),
 		)
 	}
+	for _, nodeID := range lateCommitted {
+		ctx.EmitEvent(
+			abciAPI.NewEventBuilder(app.Name()).
+				TypedAttribute(&roothash.LateExecutorCommittedEvent{
+					Round:  rtState.LastBlock.Header.Round,
+					NodeID: nodeID,
+				}).
+				TypedAttribute(&roothash.RuntimeIDAttribute{ID: cc.ID}),
+		)
+	}
 
 	ctx.Commit()
 
@@ -173,6 +245,92 @@ func (app *Application) executorCommit(
 	return nil
 }
 
+// processLateCommitment verifies an executor commitment submitted for a round that has already
+// been finalized and updates the liveness statistics accordingly.
+//
+// In case the commitment conflicts with a round that has been finalized early, the public key of
+// the entity owning the node is returned so that it can be slashed. In case a correct commitment
+// has been accepted within the late commitment window, true is returned.
+func (app *Application) processLateCommitment(
+	ctx *abciAPI.Context,
+	rtState *roothash.RuntimeState,
+	commit *commitment.ExecutorCommitment,
+	nl *registryState.MutableState,
+) (*signature.PublicKey, bool, error) {
+	lc := rtState.LateCommitments
+
+	memberIdx := -1
+	for i, n := range rtState.Committee.Members {
+		if n.Role == scheduler.RoleWorker && n.PublicKey.Equal(commit.NodeID) {
+			memberIdx = i
+			break
+		}
+	}
+	switch {
+	case memberIdx < 0:
+		return nil, false, commitment.ErrNotInCommittee
+	case !lc.IsPending(commit.NodeID):
+		return nil, false, commitment.ErrAlreadyCommitted
+	case !commit.Header.SchedulerID.Equal(lc.SchedulerID):
+		ctx.Logger().Debug("late executor commitment not for the finalized scheduler",
+			"runtime_id", rtState.Runtime.ID,
+			"round", commit.Header.Header.Round,
+			"node_id", commit.NodeID,
+			"scheduler_id", commit.Header.SchedulerID,
+		)
+		return nil, false, commitment.ErrBadExecutorCommitment
+	}
+
+	if err := commitment.VerifyExecutorCommitmentContent(ctx, lc.Block, rtState.Runtime, rtState.Committee.ValidFor, commit, nil, nl); err != nil {
+		ctx.Logger().Debug("failed to verify late executor commitment",
+			"err", err,
+			"runtime_id", rtState.Runtime.ID,
+			"round", commit.Header.Header.Round,
+		)
+		return nil, false, err
+	}
+
+	lc.Remove(commit.NodeID)
+	if len(lc.Pending) == 0 {
+		rtState.LateCommitments = nil
+	}
+
+	ctx.Logger().Debug("late executor commitment processed",
+		"runtime_id", rtState.Runtime.ID,
+		"round", commit.Header.Header.Round,
+		"node_id", commit.NodeID,
+		"failure", commit.IsIndicatingFailure(),
+	)
+
+	if commit.IsIndicatingFailure() {
+		// Failures are not counted as live rounds.
+		return nil, false, nil
+	}
+
+	vote := commit.ToVote()
+	switch {
+	case lc.IsWindow():
+		// Commitments within the late commitment window are only credited in case they match
+		// the finalized round. As the workers were expected to commit in time, they are neither
+		// counted as live rounds nor slashed.
+		if !vote.Equal(&lc.Vote) {
+			return nil, false, nil
+		}
+		rtState.LivenessStatistics.RecordLateRound(memberIdx)
+		return nil, true, nil
+	case vote.Equal(&lc.Vote):
+		rtState.LivenessStatistics.LiveRounds[memberIdx]++
+		return nil, false, nil
+	}
+
+	// Resolve the entity owning the node.
+	n, err := nl.Node(ctx, commit.NodeID)
+	if err != nil {
+		return nil, false, fmt.Errorf("cometbft/roothash: getting node %s: %w", commit.NodeID, err)
+	}
+	return &n.EntityID, false, nil
+}
+
 func (app *Application) submitEvidence(
 	ctx *abciAPI.Context,
 	state *roothashState.MutableState,
@@ -2,7 +2,9 @@ package roothash
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,15 +14,19 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	eventsAPI "github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	roothashApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/api"
 	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -30,6 +36,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 type testMsgDispatcher struct{}
@@ -232,6 +239,654 @@ func TestMessagesGasEstimation(t *testing.T) {
 	require.EqualValues(15000, ctx.Gas().GasUsed(), "gas amount should be correct")
 }
 
+func TestExecutorCommitBatchSummary(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	genesisTestHelpers.SetTestChainContext()
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	app := Application{appState, &testMsgDispatcher{}, nil}
+
+	sk, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+
+	runtime := registry.Runtime{
+		Executor: registry.ExecutorParameters{
+			MaxMessages: 32,
+		},
+	}
+
+	// Initialize scheduler state.
+	schedulerState := schedulerState.NewMutableState(ctx.State())
+	executorCommittee := scheduler.Committee{
+		RuntimeID: runtime.ID,
+		Kind:      scheduler.KindComputeExecutor,
+		Members: []*scheduler.CommitteeNode{
+			{
+				Role:      scheduler.RoleWorker,
+				PublicKey: sk.Public(),
+			},
+		},
+	}
+	err = schedulerState.PutCommittee(ctx, &executorCommittee)
+	require.NoError(err, "PutCommittee")
+
+	// Initialize consensus state without the batch summary feature.
+	consState := consensusState.NewMutableState(ctx.State())
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	// Initialize roothash state.
+	roothashState := roothashState.NewMutableState(ctx.State())
+	err = roothashState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+		MaxRuntimeMessages: 32,
+	})
+	require.NoError(err, "SetConsensusParameters")
+	blk := block.NewGenesisBlock(runtime.ID, 0)
+	err = roothashState.SetRuntimeState(ctx, &roothash.RuntimeState{
+		Runtime:          &runtime,
+		GenesisBlock:     blk,
+		LastBlock:        blk,
+		LastBlockHeight:  1,
+		LastNormalRound:  0,
+		LastNormalHeight: 1,
+		Committee:        &executorCommittee,
+		CommitmentPool:   commitment.NewPool(),
+	})
+	require.NoError(err, "SetRuntimeState")
+
+	// Generate executor commitment with a batch summary for a new block.
+	newBlk := block.NewEmptyBlock(blk, 1, block.Normal)
+
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+
+	summary := &commitment.BatchSummary{
+		GasUsed:   1000,
+		BatchSize: 256,
+		TxCount:   2,
+	}
+	ec := commitment.ExecutorCommitment{
+		NodeID: sk.Public(),
+		Header: commitment.ExecutorCommitmentHeader{
+			SchedulerID: sk.Public(),
+			Header: commitment.ComputeResultsHeader{
+				Round:          newBlk.Header.Round,
+				PreviousHash:   newBlk.Header.PreviousHash,
+				IORoot:         &newBlk.Header.IORoot,
+				StateRoot:      &newBlk.Header.StateRoot,
+				MessagesHash:   &emptyHash,
+				InMessagesHash: &emptyHash,
+				BatchSummary:   summary,
+			},
+		},
+	}
+	err = ec.Sign(sk, runtime.ID)
+	require.NoError(err, "ec.Sign")
+
+	cc := &roothash.ExecutorCommit{
+		ID:      runtime.ID,
+		Commits: []commitment.ExecutorCommitment{ec},
+	}
+
+	// Batch summaries should be rejected before the feature is enabled.
+	err = app.executorCommit(ctx, roothashState, cc)
+	require.ErrorIs(err, roothash.ErrInvalidArgument, "ExecutorCommit should fail")
+
+	// With the feature enabled, batch summaries should be recorded in round results.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	err = app.executorCommit(ctx, roothashState, cc)
+	require.NoError(err, "ExecutorCommit")
+
+	err = app.tryFinalizeRounds(ctx)
+	require.NoError(err, "tryFinalizeRounds")
+
+	results, err := roothashState.LastRoundResults(ctx, runtime.ID)
+	require.NoError(err, "LastRoundResults")
+	require.Equal(summary, results.BatchSummary, "batch summary should be recorded")
+}
+
+func TestExecutorCommitFinalizationQuorum(t *testing.T) {
+	require := require.New(t)
+	var err error
+
+	genesisTestHelpers.SetTestChainContext()
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	app := Application{appState, &testMsgDispatcher{}, nil}
+
+	penalty := quantity.NewFromUint64(100)
+	runtime := registry.Runtime{
+		Executor: registry.ExecutorParameters{
+			MaxMessages:               32,
+			FinalizationQuorumPercent: 60,
+		},
+		Staking: registry.RuntimeStakingParameters{
+			Slashing: map[staking.SlashReason]staking.Slash{
+				staking.SlashRuntimeIncorrectResults: {Amount: *penalty},
+			},
+		},
+	}
+
+	regState := registryState.NewMutableState(ctx.State())
+	stakeState := stakingState.NewMutableState(ctx.State())
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{})
+	require.NoError(err, "staking.SetConsensusParameters")
+	consState := consensusState.NewMutableState(ctx.State())
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	// Register 5 workers, each owned by a separate entity with some stake.
+	const numWorkers = 5
+	initialEscrow := quantity.NewFromUint64(200)
+	executorCommittee := scheduler.Committee{
+		RuntimeID: runtime.ID,
+		Kind:      scheduler.KindComputeExecutor,
+	}
+	signers := make(map[signature.PublicKey]signature.Signer)
+	entities := make(map[signature.PublicKey]signature.PublicKey)
+	for i := 0; i < numWorkers; i++ {
+		entitySigner := memorySigner.NewTestSigner(fmt.Sprintf("TestExecutorCommitFinalizationQuorum entity signer: %d", i))
+		ent := &entity.Entity{
+			ID: entitySigner.Public(),
+		}
+		var sigEntity *entity.SignedEntity
+		sigEntity, err = entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, ent)
+		require.NoError(err, "SignEntity")
+		err = regState.SetEntity(ctx, ent, sigEntity)
+		require.NoError(err, "SetEntity")
+
+		nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("TestExecutorCommitFinalizationQuorum node signer: %d", i))
+		nod := &node.Node{
+			Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+			ID:        nodeSigner.Public(),
+			EntityID:  ent.ID,
+		}
+		var sigNode *node.MultiSignedNode
+		sigNode, err = node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, nod)
+		require.NoError(err, "MultiSignNode")
+		err = regState.SetNode(ctx, nil, nod, sigNode)
+		require.NoError(err, "SetNode")
+
+		var totalShares quantity.Quantity
+		_ = totalShares.FromUint64(200)
+		err = stakeState.SetAccount(ctx, staking.NewAddress(ent.ID), &staking.Account{
+			Escrow: staking.EscrowAccount{
+				Active: staking.SharePool{
+					Balance:     *initialEscrow,
+					TotalShares: totalShares,
+				},
+			},
+		})
+		require.NoError(err, "SetAccount")
+
+		executorCommittee.Members = append(executorCommittee.Members, &scheduler.CommitteeNode{
+			Role:      scheduler.RoleWorker,
+			PublicKey: nod.ID,
+		})
+		signers[nod.ID] = nodeSigner
+		entities[nod.ID] = ent.ID
+	}
+
+	// Initialize roothash state.
+	roothashState := roothashState.NewMutableState(ctx.State())
+	err = roothashState.SetConsensusParameters(ctx, &roothash.ConsensusParameters{
+		MaxRuntimeMessages: 32,
+	})
+	require.NoError(err, "SetConsensusParameters")
+	blk := block.NewGenesisBlock(runtime.ID, 0)
+	err = roothashState.SetRuntimeState(ctx, &roothash.RuntimeState{
+		Runtime:          &runtime,
+		GenesisBlock:     blk,
+		LastBlock:        blk,
+		LastBlockHeight:  1,
+		LastNormalRound:  0,
+		LastNormalHeight: 1,
+		Committee:        &executorCommittee,
+		CommitmentPool:   commitment.NewPool(),
+	})
+	require.NoError(err, "SetRuntimeState")
+
+	// Order workers so that the primary scheduler comes first.
+	newBlk := block.NewEmptyBlock(blk, 1, block.Normal)
+	schedulerIdx, ok := executorCommittee.SchedulerIdx(newBlk.Header.Round, 0)
+	require.True(ok, "SchedulerIdx")
+	schedulerID := executorCommittee.Members[schedulerIdx].PublicKey
+	workers := []int{schedulerIdx}
+	for i := range executorCommittee.Members {
+		if i != schedulerIdx {
+			workers = append(workers, i)
+		}
+	}
+
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+
+	newCommit := func(idx int, stateRoot hash.Hash) roothash.ExecutorCommit {
+		nodeID := executorCommittee.Members[idx].PublicKey
+		ec := commitment.ExecutorCommitment{
+			NodeID: nodeID,
+			Header: commitment.ExecutorCommitmentHeader{
+				SchedulerID: schedulerID,
+				Header: commitment.ComputeResultsHeader{
+					Round:          newBlk.Header.Round,
+					PreviousHash:   newBlk.Header.PreviousHash,
+					IORoot:         &newBlk.Header.IORoot,
+					StateRoot:      &stateRoot,
+					MessagesHash:   &emptyHash,
+					InMessagesHash: &emptyHash,
+				},
+			},
+		}
+		err = ec.Sign(signers[nodeID], runtime.ID)
+		require.NoError(err, "ec.Sign")
+
+		return roothash.ExecutorCommit{
+			ID:      runtime.ID,
+			Commits: []commitment.ExecutorCommitment{ec},
+		}
+	}
+
+	// Commitments from 3/5 workers reach the quorum and finalize the round early.
+	for _, idx := range workers[:3] {
+		cc := newCommit(idx, newBlk.Header.StateRoot)
+		err = app.executorCommit(ctx, roothashState, &cc)
+		require.NoError(err, "ExecutorCommit")
+	}
+
+	err = app.tryFinalizeRounds(ctx)
+	require.NoError(err, "tryFinalizeRounds")
+
+	rtState, err := roothashState.RuntimeState(ctx, runtime.ID)
+	require.NoError(err, "RuntimeState")
+	require.EqualValues(1, rtState.LastBlock.Header.Round, "round should be finalized early")
+	require.NotNil(rtState.LateCommitments, "late commitments should be tracked")
+	require.Len(rtState.LateCommitments.Pending, 2)
+	finalizedBlk := rtState.LastBlock
+
+	// A late matching commitment only counts towards liveness.
+	lateIdx := workers[3]
+	cc := newCommit(lateIdx, newBlk.Header.StateRoot)
+	err = app.executorCommit(ctx, roothashState, &cc)
+	require.NoError(err, "ExecutorCommit (late, matching)")
+
+	rtState, err = roothashState.RuntimeState(ctx, runtime.ID)
+	require.NoError(err, "RuntimeState")
+	require.EqualValues(1, rtState.LivenessStatistics.LiveRounds[lateIdx], "late matching commitment should count as live")
+	require.Len(rtState.LateCommitments.Pending, 1)
+	require.Empty(rtState.CommitmentPool.SchedulerCommitments, "late commitments should not be added to the pool")
+
+	// Workers cannot commit twice.
+	err = app.executorCommit(ctx, roothashState, &cc)
+	require.ErrorIs(err, commitment.ErrAlreadyCommitted, "ExecutorCommit (late, duplicate)")
+
+	// A late conflicting commitment results in slashing but does not affect the finalized block.
+	conflictIdx := workers[4]
+	var badRoot hash.Hash
+	badRoot.FromBytes([]byte("bad state root"))
+	cc = newCommit(conflictIdx, badRoot)
+	err = app.executorCommit(ctx, roothashState, &cc)
+	require.NoError(err, "ExecutorCommit (late, conflicting)")
+
+	err = app.tryFinalizeRounds(ctx)
+	require.NoError(err, "tryFinalizeRounds")
+
+	rtState, err = roothashState.RuntimeState(ctx, runtime.ID)
+	require.NoError(err, "RuntimeState")
+	require.Equal(finalizedBlk, rtState.LastBlock, "finalized block should not change")
+	require.EqualValues(0, rtState.LivenessStatistics.LiveRounds[conflictIdx], "late conflicting commitment should not count as live")
+	require.Nil(rtState.LateCommitments, "all late commitments should be processed")
+
+	for idx, n := range executorCommittee.Members {
+		expectedEscrow := initialEscrow.Clone()
+		if idx == conflictIdx {
+			require.NoError(expectedEscrow.Sub(penalty))
+		}
+
+		acc, err := stakeState.Account(ctx, staking.NewAddress(entities[n.PublicKey]))
+		require.NoError(err, "Account")
+		require.Equal(*expectedEscrow, acc.Escrow.Active.Balance, "only the conflicting worker should be slashed")
+	}
+}
+
+func TestExecutorCommitLateCommitmentWindow(t *testing.T) {
+	genesisTestHelpers.SetTestChainContext()
+
+	const (
+		numWorkers = 5
+		window     = 10
+		height     = 100
+	)
+
+	type testCase struct {
+		ctx         *abciAPI.Context
+		app         Application
+		state       *roothashState.MutableState
+		runtime     registry.Runtime
+		committee   scheduler.Committee
+		workers     []int
+		newCommit   func(idx int, stateRoot hash.Hash) roothash.ExecutorCommit
+		newBlk      *block.Block
+		initEscrow  *quantity.Quantity
+		entities    map[signature.PublicKey]signature.PublicKey
+		stakeState  *stakingState.MutableState
+		lateIdx     int
+		finalizeAll func(order []int)
+	}
+
+	setup := func(t *testing.T, maxLateCommitmentBlocks uint64) *testCase {
+		require := require.New(t)
+		var err error
+
+		tc := &testCase{
+			entities:   make(map[signature.PublicKey]signature.PublicKey),
+			initEscrow: quantity.NewFromUint64(200),
+		}
+		appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{
+			BlockHeight: height,
+		})
+		tc.ctx = appState.NewContext(abciAPI.ContextEndBlock)
+		t.Cleanup(tc.ctx.Close)
+		tc.app = Application{appState, &testMsgDispatcher{}, nil}
+
+		penalty := quantity.NewFromUint64(100)
+		tc.runtime = registry.Runtime{
+			Executor: registry.ExecutorParameters{
+				MaxMessages:       32,
+				AllowedStragglers: 1,
+			},
+			Staking: registry.RuntimeStakingParameters{
+				Slashing: map[staking.SlashReason]staking.Slash{
+					staking.SlashRuntimeIncorrectResults: {Amount: *penalty},
+				},
+			},
+		}
+
+		regState := registryState.NewMutableState(tc.ctx.State())
+		tc.stakeState = stakingState.NewMutableState(tc.ctx.State())
+		err = tc.stakeState.SetConsensusParameters(tc.ctx, &staking.ConsensusParameters{})
+		require.NoError(err, "staking.SetConsensusParameters")
+		consState := consensusState.NewMutableState(tc.ctx.State())
+		err = consState.SetConsensusParameters(tc.ctx, &consensusGenesis.Parameters{
+			FeatureVersion: &migrations.Version243,
+		})
+		require.NoError(err, "SetConsensusParameters")
+
+		tc.committee = scheduler.Committee{
+			RuntimeID: tc.runtime.ID,
+			Kind:      scheduler.KindComputeExecutor,
+		}
+		signers := make(map[signature.PublicKey]signature.Signer)
+		for i := 0; i < numWorkers; i++ {
+			entitySigner := memorySigner.NewTestSigner(fmt.Sprintf("TestExecutorCommitLateCommitmentWindow entity signer: %d", i))
+			ent := &entity.Entity{
+				ID: entitySigner.Public(),
+			}
+			var sigEntity *entity.SignedEntity
+			sigEntity, err = entity.SignEntity(entitySigner, registry.RegisterEntitySignatureContext, ent)
+			require.NoError(err, "SignEntity")
+			err = regState.SetEntity(tc.ctx, ent, sigEntity)
+			require.NoError(err, "SetEntity")
+
+			nodeSigner := memorySigner.NewTestSigner(fmt.Sprintf("TestExecutorCommitLateCommitmentWindow node signer: %d", i))
+			nod := &node.Node{
+				Versioned: cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+				ID:        nodeSigner.Public(),
+				EntityID:  ent.ID,
+			}
+			var sigNode *node.MultiSignedNode
+			sigNode, err = node.MultiSignNode([]signature.Signer{nodeSigner}, registry.RegisterNodeSignatureContext, nod)
+			require.NoError(err, "MultiSignNode")
+			err = regState.SetNode(tc.ctx, nil, nod, sigNode)
+			require.NoError(err, "SetNode")
+
+			var totalShares quantity.Quantity
+			_ = totalShares.FromUint64(200)
+			err = tc.stakeState.SetAccount(tc.ctx, staking.NewAddress(ent.ID), &staking.Account{
+				Escrow: staking.EscrowAccount{
+					Active: staking.SharePool{
+						Balance:     *tc.initEscrow,
+						TotalShares: totalShares,
+					},
+				},
+			})
+			require.NoError(err, "SetAccount")
+
+			tc.committee.Members = append(tc.committee.Members, &scheduler.CommitteeNode{
+				Role:      scheduler.RoleWorker,
+				PublicKey: nod.ID,
+			})
+			signers[nod.ID] = nodeSigner
+			tc.entities[nod.ID] = ent.ID
+		}
+
+		// Initialize roothash state.
+		tc.state = roothashState.NewMutableState(tc.ctx.State())
+		err = tc.state.SetConsensusParameters(tc.ctx, &roothash.ConsensusParameters{
+			MaxRuntimeMessages:      32,
+			MaxLateCommitmentBlocks: maxLateCommitmentBlocks,
+		})
+		require.NoError(err, "SetConsensusParameters")
+		blk := block.NewGenesisBlock(tc.runtime.ID, 0)
+		err = tc.state.SetRuntimeState(tc.ctx, &roothash.RuntimeState{
+			Runtime:          &tc.runtime,
+			GenesisBlock:     blk,
+			LastBlock:        blk,
+			LastBlockHeight:  1,
+			LastNormalRound:  0,
+			LastNormalHeight: 1,
+			Committee:        &tc.committee,
+			CommitmentPool:   commitment.NewPool(),
+		})
+		require.NoError(err, "SetRuntimeState")
+
+		// Order workers so that the primary scheduler comes first.
+		tc.newBlk = block.NewEmptyBlock(blk, 1, block.Normal)
+		schedulerIdx, ok := tc.committee.SchedulerIdx(tc.newBlk.Header.Round, 0)
+		require.True(ok, "SchedulerIdx")
+		schedulerID := tc.committee.Members[schedulerIdx].PublicKey
+		tc.workers = []int{schedulerIdx}
+		for i := range tc.committee.Members {
+			if i != schedulerIdx {
+				tc.workers = append(tc.workers, i)
+			}
+		}
+		tc.lateIdx = tc.workers[numWorkers-1]
+
+		var emptyHash hash.Hash
+		emptyHash.Empty()
+
+		tc.newCommit = func(idx int, stateRoot hash.Hash) roothash.ExecutorCommit {
+			nodeID := tc.committee.Members[idx].PublicKey
+			ec := commitment.ExecutorCommitment{
+				NodeID: nodeID,
+				Header: commitment.ExecutorCommitmentHeader{
+					SchedulerID: schedulerID,
+					Header: commitment.ComputeResultsHeader{
+						Round:          tc.newBlk.Header.Round,
+						PreviousHash:   tc.newBlk.Header.PreviousHash,
+						IORoot:         &tc.newBlk.Header.IORoot,
+						StateRoot:      &stateRoot,
+						MessagesHash:   &emptyHash,
+						InMessagesHash: &emptyHash,
+					},
+				},
+			}
+			err := ec.Sign(signers[nodeID], tc.runtime.ID)
+			require.NoError(err, "ec.Sign")
+
+			return roothash.ExecutorCommit{
+				ID:      tc.runtime.ID,
+				Commits: []commitment.ExecutorCommitment{ec},
+			}
+		}
+
+		// Commitments from all but one straggler finalize the round.
+		tc.finalizeAll = func(order []int) {
+			for _, idx := range order {
+				cc := tc.newCommit(idx, tc.newBlk.Header.StateRoot)
+				err := tc.app.executorCommit(tc.ctx, tc.state, &cc)
+				require.NoError(err, "ExecutorCommit")
+			}
+
+			err := tc.app.tryFinalizeRounds(tc.ctx)
+			require.NoError(err, "tryFinalizeRounds")
+
+			rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+			require.NoError(err, "RuntimeState")
+			require.EqualValues(1, rtState.LastBlock.Header.Round, "round should be finalized without the straggler")
+		}
+
+		return tc
+	}
+
+	lateEvents := func(ctx *abciAPI.Context) []*roothash.LateExecutorCommittedEvent {
+		var evs []*roothash.LateExecutorCommittedEvent
+		for _, ev := range ctx.GetEvents() {
+			for _, attr := range ev.Attributes {
+				if !eventsAPI.IsAttributeKind(attr.Key, &roothash.LateExecutorCommittedEvent{}) {
+					continue
+				}
+				var e roothash.LateExecutorCommittedEvent
+				require.NoError(t, eventsAPI.DecodeValue(attr.Value, &e), "DecodeValue")
+				evs = append(evs, &e)
+			}
+		}
+		return evs
+	}
+
+	t.Run("Matching", func(t *testing.T) {
+		require := require.New(t)
+
+		tc := setup(t, window)
+		tc.finalizeAll(tc.workers[:numWorkers-1])
+
+		rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		require.NotNil(rtState.LateCommitments, "late commitments should be tracked")
+		require.EqualValues(height+1+window, rtState.LateCommitments.Deadline)
+		require.Len(rtState.LateCommitments.Pending, 1)
+		finalizedBlk := rtState.LastBlock
+
+		cc := tc.newCommit(tc.lateIdx, tc.newBlk.Header.StateRoot)
+		err = tc.app.executorCommit(tc.ctx, tc.state, &cc)
+		require.NoError(err, "ExecutorCommit (late, matching)")
+
+		rtState, err = tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		require.Equal(finalizedBlk, rtState.LastBlock, "finalized block should not change")
+		require.EqualValues(0, rtState.LivenessStatistics.LiveRounds[tc.lateIdx], "late commitment should not count as live")
+		require.EqualValues(1, rtState.LivenessStatistics.LateRoundsAt(tc.lateIdx), "late commitment should be recorded")
+		require.EqualValues(0, rtState.LivenessStatistics.MissedRoundsAt(tc.lateIdx))
+		require.Nil(rtState.LateCommitments, "all late commitments should be processed")
+		require.Empty(rtState.CommitmentPool.SchedulerCommitments, "late commitments should not be added to the pool")
+
+		evs := lateEvents(tc.ctx)
+		require.Len(evs, 1, "late executor committed event should be emitted")
+		require.EqualValues(1, evs[0].Round)
+		require.Equal(tc.committee.Members[tc.lateIdx].PublicKey, evs[0].NodeID)
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		require := require.New(t)
+
+		// The resulting state should not depend on the order in which commitments arrive.
+		var states [][]byte
+		for _, reverse := range []bool{false, true} {
+			tc := setup(t, window)
+			order := slices.Clone(tc.workers[:numWorkers-1])
+			if reverse {
+				slices.Reverse(order[1:])
+			}
+			tc.finalizeAll(order)
+
+			cc := tc.newCommit(tc.lateIdx, tc.newBlk.Header.StateRoot)
+			err := tc.app.executorCommit(tc.ctx, tc.state, &cc)
+			require.NoError(err, "ExecutorCommit (late, matching)")
+
+			rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+			require.NoError(err, "RuntimeState")
+			states = append(states, cbor.Marshal(rtState))
+		}
+		require.Equal(states[0], states[1], "runtime state should be deterministic")
+	})
+
+	t.Run("Conflicting", func(t *testing.T) {
+		require := require.New(t)
+
+		tc := setup(t, window)
+		tc.finalizeAll(tc.workers[:numWorkers-1])
+
+		var badRoot hash.Hash
+		badRoot.FromBytes([]byte("bad state root"))
+		cc := tc.newCommit(tc.lateIdx, badRoot)
+		err := tc.app.executorCommit(tc.ctx, tc.state, &cc)
+		require.NoError(err, "ExecutorCommit (late, conflicting)")
+
+		rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		require.EqualValues(0, rtState.LivenessStatistics.LateRoundsAt(tc.lateIdx), "conflicting commitment should not be credited")
+		require.EqualValues(1, rtState.LivenessStatistics.MissedRoundsAt(tc.lateIdx))
+		require.Empty(lateEvents(tc.ctx))
+
+		acc, err := tc.stakeState.Account(tc.ctx, staking.NewAddress(tc.entities[tc.committee.Members[tc.lateIdx].PublicKey]))
+		require.NoError(err, "Account")
+		require.Equal(*tc.initEscrow, acc.Escrow.Active.Balance, "workers should not be slashed within the window")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		require := require.New(t)
+
+		tc := setup(t, 0)
+		tc.finalizeAll(tc.workers[:numWorkers-1])
+
+		rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		require.Nil(rtState.LateCommitments, "late commitments should not be tracked")
+
+		cc := tc.newCommit(tc.lateIdx, tc.newBlk.Header.StateRoot)
+		err = tc.app.executorCommit(tc.ctx, tc.state, &cc)
+		require.Error(err, "ExecutorCommit (late) should fail")
+		require.Empty(lateEvents(tc.ctx))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		require := require.New(t)
+
+		tc := setup(t, window)
+		tc.finalizeAll(tc.workers[:numWorkers-1])
+
+		// Simulate the window passing.
+		rtState, err := tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		rtState.LateCommitments.Deadline = height
+		err = tc.state.SetRuntimeState(tc.ctx, rtState)
+		require.NoError(err, "SetRuntimeState")
+
+		cc := tc.newCommit(tc.lateIdx, tc.newBlk.Header.StateRoot)
+		err = tc.app.executorCommit(tc.ctx, tc.state, &cc)
+		require.Error(err, "ExecutorCommit (late, expired) should fail")
+
+		rtState, err = tc.state.RuntimeState(tc.ctx, tc.runtime.ID)
+		require.NoError(err, "RuntimeState")
+		require.EqualValues(0, rtState.LivenessStatistics.LateRoundsAt(tc.lateIdx))
+		require.Empty(lateEvents(tc.ctx))
+	})
+}
+
 func TestEvidence(t *testing.T) {
 	require := require.New(t)
 	var err error
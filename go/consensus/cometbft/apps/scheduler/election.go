@@ -0,0 +1,215 @@
+package scheduler
+
+import (
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+func newElectionInputs(
+	ctx *api.Context,
+	beaconState *beaconState.MutableState,
+	stakeAcc *stakingState.StakeAccumulatorCache,
+	prevState *beacon.PrevVRFState,
+	rt *registry.Runtime,
+	kind scheduler.CommitteeKind,
+	epoch beacon.EpochTime,
+	nodeList []*nodeWithStatus,
+	nodeLists map[scheduler.Role][]*node.Node,
+	committeeRoles []scheduler.Role,
+	groupSizes map[scheduler.Role]int,
+	cs map[scheduler.Role]registry.SchedulingConstraints,
+) (*scheduler.ElectionInputs, error) {
+	inputs := &scheduler.ElectionInputs{
+		Epoch:     epoch,
+		RuntimeID: rt.ID,
+		Kind:      kind,
+		VRF:       prevState != nil,
+
+		Policy:           rt.Executor.ElectionPolicy,
+		MaxWeightPercent: rt.Executor.ElectionMaxWeightPercent,
+	}
+	if !inputs.VRF {
+		entropy, err := beaconState.Beacon(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cometbft/scheduler: couldn't get beacon: %w", err)
+		}
+		inputs.Entropy = entropy
+	}
+
+	for _, n := range nodeList {
+		if n.status.IsSuspended(rt.ID, epoch) {
+			inputs.SuspendedNodes = append(inputs.SuspendedNodes, n.node.ID)
+		}
+	}
+
+	for _, role := range committeeRoles {
+		if groupSizes[role] == 0 {
+			continue
+		}
+
+		ri := &scheduler.RoleElectionInputs{
+			Role:       role,
+			GroupSize:  uint16(groupSizes[role]),
+			Candidates: make([]*scheduler.ElectionCandidate, 0, len(nodeLists[role])),
+		}
+		if mn := cs[role].MaxNodes; mn != nil {
+			ri.MaxNodesPerEntity = mn.Limit
+		}
+		if mps := cs[role].MinPoolSize; mps != nil {
+			ri.MinPoolSize = mps.Limit
+		}
+
+		for _, n := range nodeLists[role] {
+			candidate := &scheduler.ElectionCandidate{
+				ID:       n.ID,
+				EntityID: n.EntityID,
+			}
+			if stakeAcc != nil {
+				stake, err := stakeAcc.GetEscrowBalance(staking.NewAddress(n.EntityID))
+				if err != nil {
+					return nil, fmt.Errorf("cometbft/scheduler: failed to query entity stake: %w", err)
+				}
+				candidate.Stake = *stake
+			}
+			if inputs.VRF {
+				if pi := prevState.Pi[n.ID]; pi != nil {
+					candidate.Beta = pi.UnsafeToHash()
+				}
+			}
+			ri.Candidates = append(ri.Candidates, candidate)
+		}
+
+		inputs.Roles = append(inputs.Roles, ri)
+	}
+
+	return inputs, nil
+}
+
+// ReproduceElection independently re-runs a committee election from the recorded election
+// inputs and returns the elected committee members.
+//
+// Elections rigged via the DebugForceElect consensus parameter cannot be reproduced.
+func ReproduceElection(chainContext []byte, inputs *scheduler.ElectionInputs) ([]*scheduler.CommitteeNode, error) {
+	betas := make(map[signature.PublicKey][]byte)
+	betaOf := func(id signature.PublicKey) []byte {
+		return betas[id]
+	}
+	stakes := make(map[signature.PublicKey]*quantity.Quantity)
+	stakeOf := func(n *node.Node) (*quantity.Quantity, error) {
+		return stakes[n.ID], nil
+	}
+
+	var members []*scheduler.CommitteeNode
+	for _, ri := range inputs.Roles {
+		nodeList := make([]*node.Node, 0, len(ri.Candidates))
+		for _, c := range ri.Candidates {
+			nodeList = append(nodeList, &node.Node{
+				ID:       c.ID,
+				EntityID: c.EntityID,
+			})
+			stakes[c.ID] = &c.Stake
+			if inputs.VRF && c.Beta != nil {
+				betas[c.ID] = c.Beta
+			}
+		}
+
+		if ri.MaxNodesPerEntity > 0 {
+			switch inputs.VRF {
+			case false:
+				nodeList = dedupEntityNodesTrivial(nodeList, ri.MaxNodesPerEntity)
+			case true:
+				nodeList = dedupEntityNodesByHashedBeta(
+					betaOf,
+					chainContext,
+					inputs.Epoch,
+					inputs.RuntimeID,
+					inputs.Kind,
+					ri.Role,
+					nodeList,
+					ri.MaxNodesPerEntity,
+				)
+			}
+		}
+		nrNodes := len(nodeList)
+
+		if nrNodes < int(ri.MinPoolSize) {
+			return nil, fmt.Errorf("cometbft/scheduler: not enough eligible nodes for role %s (%d < %d)", ri.Role, nrNodes, ri.MinPoolSize)
+		}
+		wantedNodes := int(ri.GroupSize)
+		if wantedNodes > nrNodes {
+			return nil, fmt.Errorf("cometbft/scheduler: committee size exceeds available nodes for role %s (%d > %d)", ri.Role, wantedNodes, nrNodes)
+		}
+
+		var idxs []int
+		switch inputs.VRF {
+		case false:
+			rngCtx, err := committeeRNGContext(inputs.Kind, ri.Role)
+			if err != nil {
+				return nil, err
+			}
+			if idxs, err = GetPerm(inputs.Entropy, inputs.RuntimeID, rngCtx, nrNodes); err != nil {
+				return nil, fmt.Errorf("failed to derive permutation: %w", err)
+			}
+		case true:
+			baseHasher := newCommitteeBetaHasher(
+				chainContext,
+				inputs.Epoch,
+				inputs.RuntimeID,
+				inputs.Kind,
+				ri.Role,
+			)
+			idxs = committeeVRFBetaIndexes(betaOf, baseHasher, nodeList)
+		}
+
+		idxs, err := electionPolicyIndexes(
+			inputs.Policy,
+			inputs.MaxWeightPercent,
+			inputs.RuntimeID,
+			inputs.Kind,
+			ri.Role,
+			nodeList,
+			idxs,
+			stakeOf,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var elected int
+		nodesPerEntity := make(map[signature.PublicKey]int)
+		for _, idx := range idxs {
+			if elected >= wantedNodes {
+				break
+			}
+
+			n := nodeList[idx]
+			if ri.MaxNodesPerEntity > 0 {
+				if nodesPerEntity[n.EntityID] >= int(ri.MaxNodesPerEntity) {
+					return nil, fmt.Errorf("cometbft/scheduler: max nodes per committee exceeded for role %s", ri.Role)
+				}
+				nodesPerEntity[n.EntityID]++
+			}
+
+			members = append(members, &scheduler.CommitteeNode{
+				Role:      ri.Role,
+				PublicKey: n.ID,
+			})
+			elected++
+		}
+		if elected != wantedNodes {
+			return nil, fmt.Errorf("cometbft/scheduler: insufficient nodes that satisfy constraints for role %s", ri.Role)
+		}
+	}
+
+	return members, nil
+}
@@ -0,0 +1,321 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	abciState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+func TestReproduceElection(t *testing.T) {
+	for _, useVRF := range []bool{false, true} {
+		t.Run(fmt.Sprintf("VRF=%v", useVRF), func(t *testing.T) {
+			testReproduceElection(t, useVRF)
+		})
+	}
+}
+
+func testReproduceElection(t *testing.T, useVRF bool) {
+	require := require.New(t)
+
+	appState := api.NewMockApplicationState(&api.MockApplicationStateConfig{})
+	initCtx := appState.NewContext(api.ContextInitChain)
+	err := abciState.NewMutableState(initCtx.State()).SetChainContext(initCtx, "test chain context")
+	require.NoError(err, "SetChainContext")
+	initCtx.Close()
+
+	ctx := appState.NewContext(api.ContextBeginBlock)
+	defer ctx.Close()
+
+	app := &Application{
+		state: appState,
+	}
+
+	const epoch = beacon.EpochTime(42)
+
+	beaconState := beaconState.NewMutableState(ctx.State())
+	err = beaconState.DebugForceSetBeacon(ctx, []byte("mock random beacon mock random beacon mock random beacon!!"))
+	require.NoError(err, "DebugForceSetBeacon")
+	err = beaconState.SetEpoch(ctx, epoch, 100)
+	require.NoError(err, "SetEpoch")
+
+	beaconParameters := &beacon.ConsensusParameters{
+		Backend: beacon.BackendInsecure,
+	}
+	if useVRF {
+		beaconParameters.Backend = beacon.BackendVRF
+	}
+
+	rtID := common.NewTestNamespaceFromSeed([]byte("runtime"), 0)
+	rt := &registry.Runtime{
+		ID:   rtID,
+		Kind: registry.KindCompute,
+		Executor: registry.ExecutorParameters{
+			GroupSize:       3,
+			GroupBackupSize: 2,
+		},
+		Constraints: map[scheduler.CommitteeKind]map[scheduler.Role]registry.SchedulingConstraints{
+			scheduler.KindComputeExecutor: {
+				scheduler.RoleWorker: {
+					MaxNodes: &registry.MaxNodesConstraint{
+						Limit: 1,
+					},
+					MinPoolSize: &registry.MinPoolSizeConstraint{
+						Limit: 3,
+					},
+				},
+			},
+		},
+		Deployments: []*registry.VersionInfo{
+			{},
+		},
+	}
+
+	// Generate a set of nodes controlled by a few entities, with one suspended node.
+	var nodes []*nodeWithStatus
+	prevState := &beacon.PrevVRFState{
+		Pi:                 make(map[signature.PublicKey]*signature.Proof),
+		CanElectCommittees: true,
+	}
+	for i := 0; i < 10; i++ {
+		signer := memorySigner.NewTestSigner(fmt.Sprintf("election inputs test node %d", i))
+		entitySigner := memorySigner.NewTestSigner(fmt.Sprintf("election inputs test entity %d", i%4))
+
+		n := &node.Node{
+			ID:       signer.Public(),
+			EntityID: entitySigner.Public(),
+			Runtimes: []*node.Runtime{
+				{ID: rtID},
+			},
+			Roles: node.RoleComputeWorker,
+		}
+		status := &registry.NodeStatus{}
+		if i == 0 {
+			status.Faults = map[common.Namespace]*registry.Fault{
+				rtID: {SuspendedUntil: epoch + 1},
+			}
+		}
+		nodes = append(nodes, &nodeWithStatus{n, status})
+
+		vrfSigner := memorySigner.NewTestSigner(fmt.Sprintf("election inputs test node vrf %d", i))
+		vrfSigner.(*memorySigner.Signer).UnsafeSetRole(signature.SignerVRF)
+		pi, err := signature.Prove(vrfSigner, []byte("alpha"))
+		require.NoError(err, "Prove")
+		prevState.Pi[n.ID] = pi
+	}
+	err = beaconState.SetVRFState(ctx, &beacon.VRFState{
+		Epoch:     epoch,
+		PrevState: prevState,
+	})
+	require.NoError(err, "SetVRFState")
+
+	elect := func(retention uint64) {
+		err := app.electCommittee(
+			ctx,
+			&scheduler.ConsensusParameters{ElectionInputsRetention: retention},
+			beaconState,
+			beaconParameters,
+			&registry.ConsensusParameters{},
+			nil,
+			nil,
+			nil,
+			rt,
+			nodes,
+			scheduler.KindComputeExecutor,
+		)
+		require.NoError(err, "electCommittee")
+	}
+
+	schedulerState := schedulerState.NewMutableState(ctx.State())
+
+	// Election inputs should not be recorded by default.
+	elect(0)
+	_, err = schedulerState.ElectionInputs(ctx, epoch, scheduler.KindComputeExecutor, rtID)
+	require.ErrorIs(err, scheduler.ErrNoElectionInputs, "election inputs should not be recorded by default")
+
+	elect(1)
+	committee, err := schedulerState.Committee(ctx, scheduler.KindComputeExecutor, rtID)
+	require.NoError(err, "Committee")
+	require.NotNil(committee, "committee should be elected")
+	require.Len(committee.Members, 5)
+
+	inputs, err := schedulerState.ElectionInputs(ctx, epoch, scheduler.KindComputeExecutor, rtID)
+	require.NoError(err, "ElectionInputs")
+	require.Equal(epoch, inputs.Epoch)
+	require.Equal(useVRF, inputs.VRF)
+	require.Equal(!useVRF, len(inputs.Entropy) > 0, "entropy should only be recorded without VRF")
+	require.Equal([]signature.PublicKey{nodes[0].node.ID}, inputs.SuspendedNodes)
+	require.Len(inputs.Roles, 2)
+	require.Equal(scheduler.RoleWorker, inputs.Roles[0].Role)
+	require.EqualValues(3, inputs.Roles[0].GroupSize)
+	require.EqualValues(1, inputs.Roles[0].MaxNodesPerEntity)
+	require.EqualValues(3, inputs.Roles[0].MinPoolSize)
+	require.Len(inputs.Roles[0].Candidates, 9, "suspended nodes should not be candidates")
+	require.Equal(scheduler.RoleBackupWorker, inputs.Roles[1].Role)
+	for _, c := range inputs.Roles[0].Candidates {
+		require.Equal(useVRF, c.Beta != nil, "beta should only be recorded with VRF")
+	}
+
+	// Reproducing the election from the recorded inputs should yield the on-chain committee.
+	members, err := ReproduceElection([]byte("test chain context"), inputs)
+	require.NoError(err, "ReproduceElection")
+	require.Equal(committee.Members, members, "reproduced committee should match")
+
+	// Reproducing the election should fail when there are not enough distinct entities.
+	inputs.Roles[0].GroupSize = 5
+	_, err = ReproduceElection([]byte("test chain context"), inputs)
+	require.Error(err, "ReproduceElection should fail with unsatisfiable inputs")
+
+	// Pruning should remove the election inputs.
+	err = schedulerState.PruneElectionInputs(ctx, epoch)
+	require.NoError(err, "PruneElectionInputs")
+	_, err = schedulerState.ElectionInputs(ctx, epoch, scheduler.KindComputeExecutor, rtID)
+	require.NoError(err, "election inputs of the current epoch should be retained")
+
+	err = schedulerState.PruneElectionInputs(ctx, epoch+1)
+	require.NoError(err, "PruneElectionInputs")
+	_, err = schedulerState.ElectionInputs(ctx, epoch, scheduler.KindComputeExecutor, rtID)
+	require.ErrorIs(err, scheduler.ErrNoElectionInputs, "election inputs should be pruned")
+}
+
+func TestElectionPolicies(t *testing.T) {
+	require := require.New(t)
+
+	var rtID common.Namespace
+	require.NoError(rtID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"), "runtime id")
+
+	// One entity controls the majority of the stake, the remaining entities have equal stake.
+	const nrCandidates = 10
+	candidates := make([]*scheduler.ElectionCandidate, 0, nrCandidates)
+	for i := range nrCandidates {
+		stake := quantity.NewFromUint64(100)
+		if i == 0 {
+			stake = quantity.NewFromUint64(9000)
+		}
+		candidates = append(candidates, &scheduler.ElectionCandidate{
+			ID:       memorySigner.NewTestSigner(fmt.Sprintf("election policy test node %d", i)).Public(),
+			EntityID: memorySigner.NewTestSigner(fmt.Sprintf("election policy test entity %d", i)).Public(),
+			Stake:    *stake,
+		})
+	}
+	whale := candidates[0].ID
+
+	newInputs := func(policy scheduler.ElectionPolicy, maxWeightPercent uint8, entropy []byte) *scheduler.ElectionInputs {
+		return &scheduler.ElectionInputs{
+			RuntimeID:        rtID,
+			Kind:             scheduler.KindComputeExecutor,
+			Entropy:          entropy,
+			Policy:           policy,
+			MaxWeightPercent: maxWeightPercent,
+			Roles: []*scheduler.RoleElectionInputs{
+				{
+					Role:       scheduler.RoleWorker,
+					GroupSize:  1,
+					Candidates: candidates,
+				},
+			},
+		}
+	}
+
+	// Count how often the majority entity is elected with the same entropy under each policy.
+	const nrElections = 200
+	counts := make(map[scheduler.ElectionPolicy]int)
+	for i := range nrElections {
+		entropy := hash.NewFromBytes([]byte(fmt.Sprintf("election policy test entropy %d", i)))
+		for _, tc := range []struct {
+			policy           scheduler.ElectionPolicy
+			maxWeightPercent uint8
+		}{
+			{scheduler.ElectionPolicyUniform, 0},
+			{scheduler.ElectionPolicyStakeWeighted, 0},
+			{scheduler.ElectionPolicyCappedWeight, 50},
+		} {
+			members, err := ReproduceElection([]byte("test chain context"), newInputs(tc.policy, tc.maxWeightPercent, entropy[:]))
+			require.NoError(err, "ReproduceElection")
+			require.Len(members, 1)
+
+			again, err := ReproduceElection([]byte("test chain context"), newInputs(tc.policy, tc.maxWeightPercent, entropy[:]))
+			require.NoError(err, "ReproduceElection")
+			require.Equal(members, again, "elections should be deterministic")
+
+			if members[0].PublicKey.Equal(whale) {
+				counts[tc.policy]++
+			}
+		}
+	}
+
+	// Expected frequencies are 10% (uniform), 90% (stake-weighted) and 50% (capped-weight).
+	require.Less(counts[scheduler.ElectionPolicyUniform], nrElections/4, "uniform election should ignore stake")
+	require.Greater(counts[scheduler.ElectionPolicyStakeWeighted], nrElections*3/4, "stake-weighted election should favor stake")
+	require.Greater(counts[scheduler.ElectionPolicyCappedWeight], counts[scheduler.ElectionPolicyUniform], "capped-weight election should favor stake")
+	require.Less(counts[scheduler.ElectionPolicyCappedWeight], counts[scheduler.ElectionPolicyStakeWeighted], "capped-weight election should limit stake")
+
+	// Candidates without stake should only be elected after all staked candidates.
+	for i := 5; i < nrCandidates; i++ {
+		candidates[i].Stake = *quantity.NewQuantity()
+	}
+	entropy := hash.NewFromBytes([]byte("election policy test entropy for zero stake"))
+	inputs := newInputs(scheduler.ElectionPolicyStakeWeighted, 0, entropy[:])
+	inputs.Roles[0].GroupSize = 5
+	members, err := ReproduceElection([]byte("test chain context"), inputs)
+	require.NoError(err, "ReproduceElection")
+	elected := make(map[signature.PublicKey]bool)
+	for _, m := range members {
+		elected[m.PublicKey] = true
+	}
+	for i, c := range candidates {
+		require.Equal(i < 5, elected[c.ID], "only staked candidates should be elected")
+	}
+}
+
+func TestCappedWeights(t *testing.T) {
+	for _, tc := range []struct {
+		weights          []int64
+		maxWeightPercent uint8
+		expectedShares   []float64
+	}{
+		{[]int64{90, 10}, 50, []float64{0.5, 0.5}},
+		{[]int64{90, 10}, 40, []float64{0.5, 0.5}},
+		{[]int64{10, 90}, 100, []float64{0.1, 0.9}},
+		{[]int64{60, 30, 10}, 50, []float64{0.5, 0.375, 0.125}},
+		{[]int64{70, 20, 10}, 40, []float64{0.4, 0.4, 0.2}},
+		{[]int64{9000, 100, 100, 100, 100, 100, 100, 100, 100, 100}, 50, []float64{0.5, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9, 0.5 / 9}},
+		{[]int64{100, 0, 0}, 50, []float64{1, 0, 0}},
+		{[]int64{0, 0}, 50, []float64{0, 0}},
+	} {
+		weights := make([]*big.Int, 0, len(tc.weights))
+		for _, w := range tc.weights {
+			weights = append(weights, big.NewInt(w))
+		}
+		capped := cappedWeights(weights, tc.maxWeightPercent)
+		require.Len(t, capped, len(weights))
+
+		total := new(big.Int)
+		for _, w := range capped {
+			total.Add(total, w)
+		}
+		for i, w := range capped {
+			var share float64
+			if total.Sign() > 0 {
+				share, _ = new(big.Rat).SetFrac(w, total).Float64()
+			}
+			require.InDelta(t, tc.expectedShares[i], share, 1e-9, "effective share of weight %d (weights: %v, max: %d%%)", i, tc.weights, tc.maxWeightPercent)
+		}
+		require.Equal(t, tc.weights[0], weights[0].Int64(), "input weights should not be modified")
+	}
+}
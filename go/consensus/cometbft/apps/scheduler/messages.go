@@ -6,8 +6,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool) (any, error) {
@@ -26,6 +28,17 @@ func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool)
 		return nil, fmt.Errorf("cometbft/scheduler: failed to unmarshal consensus parameter changes: %w", err)
 	}
 
+	// Allow recording election inputs with the 24.3 release.
+	if changes.ElectionInputsRetention != nil {
+		enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("cometbft/scheduler: election inputs retention not enabled")
+		}
+	}
+
 	// Validate changes against current parameters.
 	state := schedulerState.NewMutableState(ctx.State())
 	params, err := state.ConsensusParameters(ctx)
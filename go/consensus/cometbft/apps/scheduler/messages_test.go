@@ -6,10 +6,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestChangeParameters(t *testing.T) {
@@ -87,4 +90,34 @@ func TestChangeParameters(t *testing.T) {
 		_, err := app.changeParameters(ctx, &proposal, true)
 		require.EqualError(err, "cometbft/scheduler: failed to validate consensus parameter changes: consensus parameter changes should not be empty")
 	})
+	t.Run("election inputs retention", func(t *testing.T) {
+		require := require.New(t)
+
+		retention := uint64(5)
+		changes := scheduler.ConsensusParameterChanges{
+			ElectionInputsRetention: &retention,
+		}
+		proposal := governance.ChangeParametersProposal{
+			Module:  scheduler.ModuleName,
+			Changes: cbor.Marshal(changes),
+		}
+
+		// Election inputs retention can only be changed with the 24.3 feature version.
+		consState := consensusState.NewMutableState(ctx.State())
+		err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.EqualError(err, "cometbft/scheduler: election inputs retention not enabled")
+
+		err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+			FeatureVersion: &migrations.Version243,
+		})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.NoError(err, "changing election inputs retention should succeed")
+
+		state, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Equal(retention, state.ElectionInputsRetention, "consensus parameters should change")
+	})
 }
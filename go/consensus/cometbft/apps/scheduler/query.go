@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
@@ -13,6 +14,7 @@ type Query interface {
 	Validators(context.Context) ([]*scheduler.Validator, error)
 	AllCommittees(context.Context) ([]*scheduler.Committee, error)
 	KindsCommittees(context.Context, []scheduler.CommitteeKind) ([]*scheduler.Committee, error)
+	ElectionInputs(context.Context, scheduler.CommitteeKind, common.Namespace) (*scheduler.ElectionInputs, error)
 	Genesis(context.Context) (*scheduler.Genesis, error)
 	ConsensusParameters(context.Context) (*scheduler.ConsensusParameters, error)
 }
@@ -59,6 +61,17 @@ func (q *schedulerQuerier) KindsCommittees(ctx context.Context, kinds []schedule
 	return q.state.KindsCommittees(ctx, kinds)
 }
 
+func (q *schedulerQuerier) ElectionInputs(ctx context.Context, kind scheduler.CommitteeKind, runtimeID common.Namespace) (*scheduler.ElectionInputs, error) {
+	committee, err := q.state.Committee(ctx, kind, runtimeID)
+	if err != nil {
+		return nil, err
+	}
+	if committee == nil {
+		return nil, scheduler.ErrNoElectionInputs
+	}
+	return q.state.ElectionInputs(ctx, committee.ValidFor, kind, runtimeID)
+}
+
 func (q *schedulerQuerier) ConsensusParameters(ctx context.Context) (*scheduler.ConsensusParameters, error) {
 	return q.state.ConsensusParameters(ctx)
 }
@@ -38,6 +38,8 @@ var (
 	RNGContextValidators = []byte("EkS-ABCI-Validators")
 	RNGContextEntities   = []byte("EkS-ABCI-Entities")
 
+	RNGContextElectionWeights = []byte("EkS-ABCI-Election-Weights")
+
 	RNGContextRoleWorker       = []byte("Worker")
 	RNGContextRoleBackupWorker = []byte("Backup-Worker")
 )
@@ -239,6 +241,16 @@ func (app *Application) BeginBlock(ctx *api.Context) error {
 		}
 		ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&scheduler.ElectedEvent{Kinds: kinds}))
 
+		// Prune election inputs outside the retention window. In case recording is disabled,
+		// this removes any previously recorded inputs.
+		var pruneBefore beacon.EpochTime
+		if retention := beacon.EpochTime(params.ElectionInputsRetention); epoch >= retention {
+			pruneBefore = epoch - retention + 1
+		}
+		if err = state.PruneElectionInputs(ctx, pruneBefore); err != nil {
+			return fmt.Errorf("cometbft/scheduler: failed to prune election inputs: %w", err)
+		}
+
 		var kindNames []string
 		for _, kind := range kinds {
 			kindNames = append(kindNames, kind.String())
@@ -369,6 +381,16 @@ func (app *Application) isSuitableExecutorWorker(
 		return false
 	}
 
+	// Nodes may have registered before the runtime raised its minimum node software version.
+	if err := rt.VerifyNodeSoftwareVersion(n.node.SoftwareVersion); err != nil {
+		ctx.Logger().Debug("node software version too old",
+			"err", err,
+			"node_id", n.node.ID,
+			"runtime", rt.ID,
+		)
+		return false
+	}
+
 	for _, nrt := range n.node.Runtimes {
 		if !nrt.ID.Equal(&rt.ID) {
 			continue
@@ -175,6 +175,60 @@ func TestElectCommittee(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"executor: should not elect node below min software version",
+			scheduler.KindComputeExecutor,
+			[]*node.Node{
+				{
+					ID:              nodeID1,
+					Runtimes:        []*node.Runtime{{ID: rtID1}},
+					Roles:           node.RoleComputeWorker,
+					SoftwareVersion: "24.2.1",
+				},
+			},
+			map[signature.PublicKey]*registry.NodeStatus{},
+			map[staking.Address]bool{},
+			registry.Runtime{
+				ID:   rtID1,
+				Kind: registry.KindCompute,
+				Executor: registry.ExecutorParameters{
+					GroupSize:       1,
+					GroupBackupSize: 0,
+				},
+				Deployments: []*registry.VersionInfo{
+					{},
+				},
+				MinNodeSoftwareVersion: &version.Version{Major: 24, Minor: 3},
+			},
+			false,
+		},
+		{
+			"executor: should elect node satisfying min software version",
+			scheduler.KindComputeExecutor,
+			[]*node.Node{
+				{
+					ID:              nodeID1,
+					Runtimes:        []*node.Runtime{{ID: rtID1}},
+					Roles:           node.RoleComputeWorker,
+					SoftwareVersion: "24.3.0 (upgrade handlers: consensus243)",
+				},
+			},
+			map[signature.PublicKey]*registry.NodeStatus{},
+			map[staking.Address]bool{},
+			registry.Runtime{
+				ID:   rtID1,
+				Kind: registry.KindCompute,
+				Executor: registry.ExecutorParameters{
+					GroupSize:       1,
+					GroupBackupSize: 0,
+				},
+				Deployments: []*registry.VersionInfo{
+					{},
+				},
+				MinNodeSoftwareVersion: &version.Version{Major: 24, Minor: 3},
+			},
+			true,
+		},
 		{
 			"executor: only node not for the correct runtime",
 			scheduler.KindComputeExecutor,
@@ -5,16 +5,20 @@ import (
 	"crypto"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"math/big"
 	"math/rand"
 	"sort"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/drbg"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/mathrand"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/tuplehash"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	tmBeacon "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon"
 	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
@@ -31,6 +35,32 @@ type nodeWithStatus struct {
 	status *registry.NodeStatus
 }
 
+// betaFn returns the VRF output (beta) of the given node, or nil if the node did not submit
+// a VRF proof.
+type betaFn func(id signature.PublicKey) []byte
+
+// prevStateBetas returns a betaFn backed by the VRF proofs accumulated in the previous epoch.
+func prevStateBetas(prevState *beacon.PrevVRFState) betaFn {
+	return func(id signature.PublicKey) []byte {
+		pi := prevState.Pi[id]
+		if pi == nil {
+			return nil
+		}
+		return pi.UnsafeToHash()
+	}
+}
+
+// accumulatorStakes returns a stakeFn backed by the escrow balances of the entities controlling
+// the candidates. In case the stake accumulator is not available, all candidates have no stake.
+func accumulatorStakes(stakeAcc *stakingState.StakeAccumulatorCache) stakeFn {
+	return func(n *node.Node) (*quantity.Quantity, error) {
+		if stakeAcc == nil {
+			return quantity.NewQuantity(), nil
+		}
+		return stakeAcc.GetEscrowBalance(staking.NewAddress(n.EntityID))
+	}
+}
+
 func getPrevVRFState(
 	ctx *api.Context,
 	beaconState *beaconState.MutableState,
@@ -101,7 +131,7 @@ func shuffleValidators(
 
 		// Do the cryptographic sortition.
 		ret := sortNodesByHashedBeta(
-			prevState,
+			prevStateBetas(prevState),
 			baseHasher,
 			nodeList,
 		)
@@ -297,6 +327,27 @@ func (app *Application) electCommittee( //nolint: gocyclo
 		}
 	}
 
+	// Record the election inputs so that the election can be independently reproduced.
+	var inputs *scheduler.ElectionInputs
+	if schedulerParameters.ElectionInputsRetention > 0 {
+		if inputs, err = newElectionInputs(
+			ctx,
+			beaconState,
+			stakeAcc,
+			prevState,
+			rt,
+			kind,
+			epoch,
+			nodeList,
+			nodeLists,
+			committeeRoles,
+			groupSizes,
+			cs,
+		); err != nil {
+			return err
+		}
+	}
+
 	// Perform election.
 	var members []*scheduler.CommitteeNode
 	for _, role := range committeeRoles {
@@ -333,7 +384,7 @@ func (app *Application) electCommittee( //nolint: gocyclo
 				)
 			case true:
 				nodeList = dedupEntityNodesByHashedBeta(
-					prevState,
+					prevStateBetas(prevState),
 					tmBeacon.MustGetChainContext(ctx),
 					epoch,
 					rt.ID,
@@ -386,17 +437,8 @@ func (app *Application) electCommittee( //nolint: gocyclo
 		case false:
 			// Use the per-epoch entropy to do the elections.
 			var rngCtx []byte
-			switch kind {
-			case scheduler.KindComputeExecutor:
-				rngCtx = RNGContextExecutor
-			}
-			switch role {
-			case scheduler.RoleWorker:
-				rngCtx = append(rngCtx, RNGContextRoleWorker...)
-			case scheduler.RoleBackupWorker:
-				rngCtx = append(rngCtx, RNGContextRoleBackupWorker...)
-			default:
-				return fmt.Errorf("cometbft/scheduler: unsupported role: %v", role)
+			if rngCtx, err = committeeRNGContext(kind, role); err != nil {
+				return err
 			}
 
 			var entropy []byte
@@ -419,12 +461,26 @@ func (app *Application) electCommittee( //nolint: gocyclo
 			)
 
 			idxs = committeeVRFBetaIndexes(
-				prevState,
+				prevStateBetas(prevState),
 				baseHasher,
 				nodeList,
 			)
 		}
 
+		// Apply the runtime's election policy.
+		if idxs, err = electionPolicyIndexes(
+			rt.Executor.ElectionPolicy,
+			rt.Executor.ElectionMaxWeightPercent,
+			rt.ID,
+			kind,
+			role,
+			nodeList,
+			idxs,
+			accumulatorStakes(stakeAcc),
+		); err != nil {
+			return err
+		}
+
 		// If the election is rigged for testing purposes, force-elect the
 		// nodes if possible.
 		ok, elected, forceState := app.debugForceElect(
@@ -521,11 +577,198 @@ func (app *Application) electCommittee( //nolint: gocyclo
 	if err = schedulerState.NewMutableState(ctx.State()).PutCommittee(ctx, committee); err != nil {
 		return fmt.Errorf("cometbft/scheduler: failed to save committee: %w", err)
 	}
+	if inputs != nil {
+		if err = schedulerState.NewMutableState(ctx.State()).PutElectionInputs(ctx, inputs); err != nil {
+			return fmt.Errorf("cometbft/scheduler: failed to save election inputs: %w", err)
+		}
+	}
 	return nil
 }
 
+func committeeRNGContext(kind scheduler.CommitteeKind, role scheduler.Role) ([]byte, error) {
+	var rngCtx []byte
+	switch kind {
+	case scheduler.KindComputeExecutor:
+		rngCtx = RNGContextExecutor
+	}
+	switch role {
+	case scheduler.RoleWorker:
+		rngCtx = append(rngCtx, RNGContextRoleWorker...)
+	case scheduler.RoleBackupWorker:
+		rngCtx = append(rngCtx, RNGContextRoleBackupWorker...)
+	default:
+		return nil, fmt.Errorf("cometbft/scheduler: unsupported role: %v", role)
+	}
+	return rngCtx, nil
+}
+
+// stakeFn returns the stake backing the given candidate node.
+type stakeFn func(n *node.Node) (*quantity.Quantity, error)
+
+// electionPolicyIndexes reorders the uniformly shuffled candidate indexes according to the
+// given election policy.
+//
+// The uniform policy keeps the order as is. Weighted policies sequentially sample candidates
+// without replacement with probability proportional to their weight, using randomness derived
+// from the uniformly shuffled order so that the result is fully determined by the election
+// inputs. Candidates without any weight are appended in the uniformly shuffled order.
+func electionPolicyIndexes(
+	policy scheduler.ElectionPolicy,
+	maxWeightPercent uint8,
+	runtimeID common.Namespace,
+	kind scheduler.CommitteeKind,
+	role scheduler.Role,
+	nodeList []*node.Node,
+	idxs []int,
+	stakeOf stakeFn,
+) ([]int, error) {
+	switch policy {
+	case scheduler.ElectionPolicyUniform:
+		return idxs, nil
+	case scheduler.ElectionPolicyStakeWeighted, scheduler.ElectionPolicyCappedWeight:
+	default:
+		return nil, fmt.Errorf("cometbft/scheduler: unsupported election policy: %s", policy)
+	}
+
+	weights := make([]*big.Int, 0, len(idxs))
+	for _, idx := range idxs {
+		stake, err := stakeOf(nodeList[idx])
+		if err != nil {
+			return nil, fmt.Errorf("cometbft/scheduler: failed to query candidate stake: %w", err)
+		}
+		weights = append(weights, stake.ToBigInt())
+	}
+	if policy == scheduler.ElectionPolicyCappedWeight {
+		weights = cappedWeights(weights, maxWeightPercent)
+	}
+	total := new(big.Int)
+	for _, w := range weights {
+		total.Add(total, w)
+	}
+
+	var seed []byte
+	for _, idx := range idxs {
+		seed = append(seed, nodeList[idx].ID[:]...)
+	}
+	seedHash := hash.NewFromBytes(seed)
+	rngCtx, err := committeeRNGContext(kind, role)
+	if err != nil {
+		return nil, err
+	}
+	rng, err := drbg.New(crypto.SHA512, seedHash[:], runtimeID[:], append(append([]byte{}, RNGContextElectionWeights...), rngCtx...))
+	if err != nil {
+		return nil, fmt.Errorf("cometbft/scheduler: couldn't instantiate DRBG: %w", err)
+	}
+
+	ret := make([]int, 0, len(idxs))
+	picked := make([]bool, len(idxs))
+	for total.Sign() > 0 {
+		target, err := uniformBigInt(rng, total)
+		if err != nil {
+			return nil, err
+		}
+
+		acc := new(big.Int)
+		for i, w := range weights {
+			if picked[i] || w.Sign() == 0 {
+				continue
+			}
+			if acc.Add(acc, w).Cmp(target) > 0 {
+				ret = append(ret, idxs[i])
+				picked[i] = true
+				total.Sub(total, w)
+				break
+			}
+		}
+	}
+	for i, idx := range idxs {
+		if !picked[i] {
+			ret = append(ret, idx)
+		}
+	}
+
+	return ret, nil
+}
+
+// cappedWeights returns the given weights capped so that no weight exceeds the given percentage
+// of the total capped weight.
+//
+// The largest weights are lowered to a common cap and the remaining weights are scaled so that
+// the result is exact in integer arithmetic. In case there are too few non-zero weights for the
+// cap to be satisfiable, all non-zero weights are made equal.
+func cappedWeights(weights []*big.Int, maxWeightPercent uint8) []*big.Int {
+	// Sort the non-zero weights in descending order.
+	order := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w.Sign() > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return weights[order[a]].Cmp(weights[order[b]]) > 0
+	})
+
+	// Remaining is the sum of the weights that are not capped.
+	remaining := new(big.Int)
+	for _, i := range order {
+		remaining.Add(remaining, weights[i])
+	}
+
+	// Find the smallest number of capped weights k such that the largest uncapped weight w does
+	// not exceed the cap c, where c satisfies c = p * (k * c + remaining) / 100, i.e. it is
+	// exactly p percent of the new total. To avoid fractions, the cap is p * remaining and the
+	// uncapped weights are scaled by 100 - k * p.
+	p := big.NewInt(int64(maxWeightPercent))
+	for k, i := range order {
+		scale := big.NewInt(100 - int64(k)*int64(maxWeightPercent))
+		if scale.Sign() <= 0 {
+			break
+		}
+		capWeight := new(big.Int).Mul(p, remaining)
+		if new(big.Int).Mul(weights[i], scale).Cmp(capWeight) > 0 {
+			remaining.Sub(remaining, weights[i])
+			continue
+		}
+
+		capped := make([]*big.Int, len(weights))
+		for j, w := range weights {
+			capped[j] = new(big.Int).Mul(w, scale)
+		}
+		for _, j := range order[:k] {
+			capped[j] = capWeight
+		}
+		return capped
+	}
+
+	// The cap cannot be satisfied, so use the closest approximation.
+	capped := make([]*big.Int, len(weights))
+	for j, w := range weights {
+		capped[j] = big.NewInt(int64(w.Sign()))
+	}
+	return capped
+}
+
+// uniformBigInt returns a uniformly distributed integer in [0, maxValue) read from the given
+// source of randomness.
+func uniformBigInt(rng io.Reader, maxValue *big.Int) (*big.Int, error) {
+	bitLen := maxValue.BitLen()
+	buf := make([]byte, (bitLen+7)/8)
+	mask := byte(0xff >> (8*len(buf) - bitLen))
+
+	v := new(big.Int)
+	for {
+		if _, err := io.ReadFull(rng, buf); err != nil {
+			return nil, fmt.Errorf("cometbft/scheduler: failed to read randomness: %w", err)
+		}
+		buf[0] &= mask
+		if v.SetBytes(buf).Cmp(maxValue) < 0 {
+			return v, nil
+		}
+	}
+}
+
 func committeeVRFBetaIndexes(
-	prevState *beacon.PrevVRFState,
+	betas betaFn,
 	baseHasher *tuplehash.Hasher,
 	nodeList []*node.Node,
 ) []int {
@@ -535,7 +778,7 @@ func committeeVRFBetaIndexes(
 	}
 
 	sorted := sortNodesByHashedBeta(
-		prevState,
+		betas,
 		baseHasher,
 		nodeList,
 	)
@@ -549,7 +792,7 @@ func committeeVRFBetaIndexes(
 }
 
 func sortNodesByHashedBeta(
-	prevState *beacon.PrevVRFState,
+	betaOf betaFn,
 	baseHasher *tuplehash.Hasher,
 	nodeList []*node.Node,
 ) []*node.Node {
@@ -558,12 +801,12 @@ func sortNodesByHashedBeta(
 	betas := make([]hashedBeta, 0, len(nodeList))
 	for i := range nodeList {
 		n := nodeList[i]
-		pi := prevState.Pi[n.ID]
-		if pi == nil {
+		rawBeta := betaOf(n.ID)
+		if rawBeta == nil {
 			continue
 		}
 
-		beta := hashBeta(baseHasher, pi.UnsafeToHash())
+		beta := hashBeta(baseHasher, rawBeta)
 		if nodeByHashedBeta[beta] == nil {
 			// These should never collide in practice, but on the off-chance
 			// that they do, the first one wins.
@@ -649,7 +892,7 @@ func newBetaHasher(
 }
 
 func dedupEntityNodesByHashedBeta(
-	prevState *beacon.PrevVRFState,
+	betas betaFn,
 	chainContext []byte,
 	epoch beacon.EpochTime,
 	runtimeID common.Namespace,
@@ -673,7 +916,7 @@ func dedupEntityNodesByHashedBeta(
 
 	// Do the cryptographic sortition.
 	shuffledNodeList := sortNodesByHashedBeta(
-		prevState,
+		betas,
 		baseHasher,
 		nodeList,
 	)
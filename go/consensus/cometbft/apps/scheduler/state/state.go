@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -33,6 +34,10 @@ var (
 	//
 	// Value is CBOR-serialized api.ConsensusParameters.
 	parametersKeyFmt = consensus.KeyFormat.New(0x63)
+	// electionInputsKeyFmt is the key format used for committee election inputs.
+	//
+	// Value is CBOR-serialized api.ElectionInputs.
+	electionInputsKeyFmt = consensus.KeyFormat.New(0x64, uint64(0), uint8(0), keyformat.H(&common.Namespace{}))
 )
 
 // ImmutableState is an immutable scheduler state wrapper.
@@ -171,6 +176,28 @@ func (s *ImmutableState) ConsensusParameters(ctx context.Context) (*api.Consensu
 	return &params, nil
 }
 
+// ElectionInputs returns the inputs used to elect a specific committee in the given epoch.
+func (s *ImmutableState) ElectionInputs(
+	ctx context.Context,
+	epoch beacon.EpochTime,
+	kind api.CommitteeKind,
+	runtimeID common.Namespace,
+) (*api.ElectionInputs, error) {
+	raw, err := s.state.Get(ctx, electionInputsKeyFmt.Encode(uint64(epoch), uint8(kind), &runtimeID))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if raw == nil {
+		return nil, api.ErrNoElectionInputs
+	}
+
+	var inputs api.ElectionInputs
+	if err = cbor.Unmarshal(raw, &inputs); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &inputs, nil
+}
+
 // MutableState is a mutable scheduler state wrapper.
 type MutableState struct {
 	*ImmutableState
@@ -198,6 +225,45 @@ func (s *MutableState) DropCommittee(ctx context.Context, kind api.CommitteeKind
 	return abciAPI.UnavailableStateError(err)
 }
 
+// PutElectionInputs stores the inputs used to elect a committee.
+func (s *MutableState) PutElectionInputs(ctx context.Context, inputs *api.ElectionInputs) error {
+	err := s.ms.Insert(ctx, electionInputsKeyFmt.Encode(uint64(inputs.Epoch), uint8(inputs.Kind), &inputs.RuntimeID), cbor.Marshal(inputs))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// PruneElectionInputs removes the election inputs of all committees elected before the given
+// epoch.
+func (s *MutableState) PruneElectionInputs(ctx context.Context, before beacon.EpochTime) error {
+	it := s.ms.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Seek(electionInputsKeyFmt.Encode()); it.Valid(); it.Next() {
+		var (
+			epoch      uint64
+			kind       uint8
+			hRuntimeID keyformat.PreHashed
+		)
+		if !electionInputsKeyFmt.Decode(it.Key(), &epoch, &kind, &hRuntimeID) {
+			break
+		}
+		if beacon.EpochTime(epoch) >= before {
+			break
+		}
+		toDelete = append(toDelete, it.Key())
+	}
+	if it.Err() != nil {
+		return abciAPI.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
 // PutCurrentValidators stores the current set of validators.
 func (s *MutableState) PutCurrentValidators(ctx context.Context, validators map[signature.PublicKey]*api.Validator) error {
 	err := s.ms.Insert(ctx, validatorsCurrentKeyFmt.Encode(), cbor.Marshal(validators))
@@ -7,8 +7,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool) (any, error) {
@@ -26,6 +28,28 @@ func (app *Application) changeParameters(ctx *api.Context, msg any, apply bool)
 		return nil, fmt.Errorf("staking: failed to unmarshal consensus parameter changes: %w", err)
 	}
 
+	// Allow recording evidence with the 24.3 release.
+	if changes.EvidenceRecordsRetention != nil {
+		enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("%w: evidence records retention not enabled", staking.ErrInvalidArgument)
+		}
+	}
+
+	// Allow changing the allowance history limit with the 24.3 release.
+	if changes.MaxAllowanceHistory != nil {
+		enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("%w: allowance history not enabled", staking.ErrInvalidArgument)
+		}
+	}
+
 	// Validate and apply changes to the parameters.
 	state := stakingState.NewMutableState(ctx.State())
 	params, err := state.ConsensusParameters(ctx)
@@ -7,10 +7,13 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestChangeParameters(t *testing.T) {
@@ -112,4 +115,64 @@ func TestChangeParameters(t *testing.T) {
 		_, err := app.changeParameters(ctx, &proposal, true)
 		require.EqualError(err, "staking: failed to validate consensus parameters: fee split proportions are all zero")
 	})
+	t.Run("evidence records retention", func(t *testing.T) {
+		require := require.New(t)
+
+		retention := uint64(100)
+		changes := staking.ConsensusParameterChanges{
+			EvidenceRecordsRetention: &retention,
+		}
+		proposal := governance.ChangeParametersProposal{
+			Module:  staking.ModuleName,
+			Changes: cbor.Marshal(changes),
+		}
+
+		// Evidence records retention can only be changed with the 24.3 feature version.
+		consState := consensusState.NewMutableState(ctx.State())
+		err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.ErrorIs(err, staking.ErrInvalidArgument)
+
+		err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+			FeatureVersion: &migrations.Version243,
+		})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.NoError(err, "changing evidence records retention should succeed")
+
+		state, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Equal(retention, state.EvidenceRecordsRetention, "consensus parameters should change")
+	})
+	t.Run("allowance history", func(t *testing.T) {
+		require := require.New(t)
+
+		maxAllowanceHistory := uint32(8)
+		changes := staking.ConsensusParameterChanges{
+			MaxAllowanceHistory: &maxAllowanceHistory,
+		}
+		proposal := governance.ChangeParametersProposal{
+			Module:  staking.ModuleName,
+			Changes: cbor.Marshal(changes),
+		}
+
+		// The allowance history limit can only be changed with the 24.3 feature version.
+		consState := consensusState.NewMutableState(ctx.State())
+		err := consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.ErrorIs(err, staking.ErrInvalidArgument)
+
+		err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+			FeatureVersion: &migrations.Version243,
+		})
+		require.NoError(err, "SetConsensusParameters")
+		_, err = app.changeParameters(ctx, &proposal, true)
+		require.NoError(err, "changing the allowance history limit should succeed")
+
+		state, err := state.ConsensusParameters(ctx)
+		require.NoError(err, "fetching consensus parameters should succeed")
+		require.Equal(maxAllowanceHistory, state.MaxAllowanceHistory, "consensus parameters should change")
+	})
 }
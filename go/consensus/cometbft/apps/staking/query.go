@@ -2,9 +2,11 @@ package staking
 
 import (
 	"context"
+	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -27,6 +29,9 @@ type Query interface {
 	DebondingDelegationsFor(context.Context, staking.Address) (map[staking.Address][]*staking.DebondingDelegation, error)
 	DebondingDelegationInfosFor(context.Context, staking.Address) (map[staking.Address][]*staking.DebondingDelegationInfo, error)
 	DebondingDelegationsTo(context.Context, staking.Address) (map[staking.Address][]*staking.DebondingDelegation, error)
+	ValidateScheduleUpdate(context.Context, staking.Address, *staking.CommissionSchedule) (*staking.CommissionSchedule, error)
+	EvidenceRecords(context.Context, *consensus.GetEvidenceRequest) ([]*consensus.EvidenceRecord, error)
+	AllowanceHistory(context.Context, *staking.AllowanceHistoryQuery) ([]*staking.AllowanceHistoryEntry, error)
 	Genesis(context.Context) (*staking.Genesis, error)
 	ConsensusParameters(context.Context) (*staking.ConsensusParameters, error)
 }
@@ -43,12 +48,16 @@ func (f *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error)
 		return nil, err
 	}
 	return &stakingQuerier{
-		state: stakingState.NewImmutableState(state),
+		queryState: f.state,
+		state:      stakingState.NewImmutableState(state),
+		height:     height,
 	}, nil
 }
 
 type stakingQuerier struct {
-	state *stakingState.ImmutableState
+	queryState abciAPI.ApplicationQueryState
+	state      *stakingState.ImmutableState
+	height     int64
 }
 
 func (q *stakingQuerier) TotalSupply(ctx context.Context) (*quantity.Quantity, error) {
@@ -188,6 +197,31 @@ func (q *stakingQuerier) DebondingDelegationsTo(ctx context.Context, addr stakin
 	return q.state.DebondingDelegationsTo(ctx, addr)
 }
 
+func (q *stakingQuerier) ValidateScheduleUpdate(ctx context.Context, addr staking.Address, amendment *staking.CommissionSchedule) (*staking.CommissionSchedule, error) {
+	epoch, err := q.queryState.GetEpoch(ctx, q.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+	params, err := q.state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acct, err := q.state.Account(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return staking.ValidateScheduleUpdate(&acct.Escrow.CommissionSchedule, amendment, &params.CommissionScheduleRules, epoch)
+}
+
+func (q *stakingQuerier) EvidenceRecords(ctx context.Context, request *consensus.GetEvidenceRequest) ([]*consensus.EvidenceRecord, error) {
+	return q.state.EvidenceRecords(ctx, request.FromHeight, request.ToHeight, request.Offset, request.Limit)
+}
+
+func (q *stakingQuerier) AllowanceHistory(ctx context.Context, query *staking.AllowanceHistoryQuery) ([]*staking.AllowanceHistoryEntry, error) {
+	return q.state.AllowanceHistory(ctx, query.Owner, query.Beneficiary, query.Offset, query.Limit)
+}
+
 func (q *stakingQuerier) ConsensusParameters(ctx context.Context) (*staking.ConsensusParameters, error) {
 	return q.state.ConsensusParameters(ctx)
 }
@@ -5,9 +5,8 @@ import (
 	"encoding/hex"
 	"math"
 
-	cmtcrypto "github.com/cometbft/cometbft/crypto"
-
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
@@ -15,11 +14,12 @@ import (
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-func onEvidenceByzantineConsensus(
-	ctx *abciAPI.Context,
-	reason staking.SlashReason,
-	addr cmtcrypto.Address,
-) error {
+// onEvidenceByzantineConsensus processes evidence of validator misbehavior, slashing and freezing
+// the offending validator as configured. The resolved offender and the slashed amount are filled
+// in the given evidence record.
+func onEvidenceByzantineConsensus(ctx *abciAPI.Context, record *consensus.EvidenceRecord) error {
+	reason, addr := record.Reason, record.ValidatorAddress
+
 	regState := registryState.NewMutableState(ctx.State())
 	stakeState := stakingState.NewMutableState(ctx.State())
 
@@ -34,6 +34,8 @@ func onEvidenceByzantineConsensus(
 		)
 		return nil
 	}
+	record.NodeID = &node.ID
+	record.EntityID = &node.EntityID
 
 	nodeStatus, err := regState.NodeStatus(ctx, node.ID)
 	if err != nil {
@@ -86,7 +88,7 @@ func onEvidenceByzantineConsensus(
 
 	// Slash validator.
 	entityAddr := staking.NewAddress(node.EntityID)
-	_, err = stakeState.SlashEscrow(ctx, entityAddr, &penalty.Amount)
+	slashed, err := stakeState.SlashEscrow(ctx, entityAddr, &penalty.Amount)
 	if err != nil {
 		ctx.Logger().Error("failed to slash validator entity",
 			"err", err,
@@ -95,6 +97,9 @@ func onEvidenceByzantineConsensus(
 		)
 		return err
 	}
+	if !slashed.IsZero() {
+		record.Slashed = slashed
+	}
 
 	if err = regState.SetNodeStatus(ctx, node.ID, nodeStatus); err != nil {
 		ctx.Logger().Error("failed to set validator node status",
@@ -11,6 +11,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
@@ -32,12 +33,18 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	consensusSigner := memorySigner.NewTestSigner("consensus test signer")
 	consensusID := consensusSigner.Public()
 	validatorAddress := tmcrypto.PublicKeyToCometBFT(&consensusID).Address()
+	newRecord := func(reason staking.SlashReason) *consensus.EvidenceRecord {
+		return &consensus.EvidenceRecord{
+			Reason:           reason,
+			ValidatorAddress: validatorAddress,
+		}
+	}
 
 	regState := registryState.NewMutableState(ctx.State())
 	stakeState := stakingState.NewMutableState(ctx.State())
 
 	// Validator address is not known as there are no nodes.
-	err := onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	err := onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusEquivocation))
 	require.NoError(err, "should not fail when validator address is not known")
 
 	// Add entity.
@@ -62,7 +69,7 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	require.NoError(err, "SetNode")
 
 	// Should not fail if node status is not available.
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	err = onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusEquivocation))
 	require.NoError(err, "should not fail when node status is not available")
 
 	// Add node status.
@@ -70,7 +77,7 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	require.NoError(err, "SetNodeStatus")
 
 	// Should fail if unable to get the slashing procedure.
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	err = onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusEquivocation))
 	require.Error(err, "should fail when unable to get the slashing procedure")
 
 	// Add slashing procedure.
@@ -88,7 +95,7 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 
 	// Should not fail if the validator has no stake (which is in any case an
 	// invariant violation as a validator needs to have some stake).
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	err = onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusEquivocation))
 	require.NoError(err, "should not fail when validator has no stake")
 	// Node should be frozen.
 	status, err := regState.NodeStatus(ctx, nod.ID)
@@ -97,7 +104,7 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	require.EqualValues(registry.FreezeForever, status.FreezeEndTime, "node should be frozen forever")
 
 	// Should not fail slashing a frozen node.
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	err = onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusEquivocation))
 	require.NoError(err, "should not fail when validator is frozen")
 	// Unfreeze the node.
 	err = regState.SetNodeStatus(ctx, nod.ID, &registry.NodeStatus{FreezeEndTime: 0})
@@ -122,8 +129,12 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	require.NoError(err, "SetAccount")
 
 	// Should slash.
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusEquivocation, validatorAddress)
+	record := newRecord(staking.SlashConsensusEquivocation)
+	err = onEvidenceByzantineConsensus(ctx, record)
 	require.NoError(err, "slashing should succeed")
+	require.EqualValues(&nod.ID, record.NodeID, "evidence record should contain the node ID")
+	require.EqualValues(&ent.ID, record.EntityID, "evidence record should contain the entity ID")
+	require.EqualValues(&slashAmount, record.Slashed, "evidence record should contain the slashed amount")
 
 	// Entity stake should be slashed.
 	acct, err := stakeState.Account(ctx, addr)
@@ -138,6 +149,6 @@ func TestOnEvidenceByzantineConsensus(t *testing.T) {
 	require.EqualValues(registry.FreezeForever, status.FreezeEndTime, "node should be frozen forever")
 
 	// Should not fail in case the slashing penalty is not configured.
-	err = onEvidenceByzantineConsensus(ctx, staking.SlashConsensusLightClientAttack, validatorAddress)
+	err = onEvidenceByzantineConsensus(ctx, newRecord(staking.SlashConsensusLightClientAttack))
 	require.NoError(err, "slashing should not fail")
 }
@@ -9,6 +9,7 @@ import (
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	governanceApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance/api"
@@ -111,6 +112,11 @@ func (app *Application) BeginBlock(ctx *api.Context) error {
 
 	// Iterate over any submitted evidence of a validator misbehaving. Note that
 	// the actual evidence has already been verified by CometBFT to be valid.
+	params, err := stakeState.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("staking: failed to load consensus parameters: %w", err)
+	}
+	var index uint32
 	for _, evidence := range ctx.BlockContext().ValidatorMisbehavior {
 		var reason staking.SlashReason
 		switch evidence.Type {
@@ -125,9 +131,35 @@ func (app *Application) BeginBlock(ctx *api.Context) error {
 			continue
 		}
 
-		if err = onEvidenceByzantineConsensus(ctx, reason, evidence.Validator.Address); err != nil {
+		record := &consensus.EvidenceRecord{
+			Height:            ctx.BlockHeight(),
+			Index:             index,
+			Reason:            reason,
+			MisbehaviorHeight: evidence.Height,
+			ValidatorAddress:  evidence.Validator.Address,
+		}
+		index++
+
+		if err = onEvidenceByzantineConsensus(ctx, record); err != nil {
 			return err
 		}
+
+		if params.EvidenceRecordsRetention > 0 {
+			if err = stakeState.PutEvidenceRecord(ctx, record); err != nil {
+				return fmt.Errorf("staking: failed to store evidence record: %w", err)
+			}
+		}
+	}
+
+	// Prune old evidence records. In case recording has been disabled, remove all of them.
+	pruneBefore := ctx.BlockHeight() + 1
+	if retention := int64(params.EvidenceRecordsRetention); retention > 0 {
+		pruneBefore -= retention
+	}
+	if pruneBefore > 0 {
+		if err = stakeState.PruneEvidenceRecords(ctx, pruneBefore); err != nil {
+			return fmt.Errorf("staking: failed to prune evidence records: %w", err)
+		}
 	}
 
 	return nil
@@ -223,6 +255,22 @@ func (app *Application) ExecuteTx(ctx *api.Context, tx *transaction.Transaction)
 
 		_, err := app.withdraw(ctx, state, &withdraw)
 		return err
+	case staking.MethodDelegateFromAllowance:
+		var delegate staking.DelegateFromAllowance
+		if err := cbor.Unmarshal(tx.Body, &delegate); err != nil {
+			return staking.ErrInvalidArgument
+		}
+
+		_, err := app.delegateFromAllowance(ctx, state, &delegate)
+		return err
+	case staking.MethodReclaimEscrowFromAllowance:
+		var reclaim staking.ReclaimEscrowFromAllowance
+		if err := cbor.Unmarshal(tx.Body, &reclaim); err != nil {
+			return staking.ErrInvalidArgument
+		}
+
+		_, err := app.reclaimEscrowFromAllowance(ctx, state, &reclaim)
+		return err
 	default:
 		return staking.ErrInvalidArgument
 	}
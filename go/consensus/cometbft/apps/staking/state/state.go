@@ -81,6 +81,24 @@ var (
 	// Value is empty.
 	commissionScheduleAddressesKeyFmt = consensus.KeyFormat.New(0x5B, &staking.Address{})
 
+	// evidenceRecordsKeyFmt is the key format used for records of processed validator
+	// misbehavior evidence (height, index).
+	//
+	// Value is CBOR-serialized consensus.EvidenceRecord.
+	evidenceRecordsKeyFmt = consensus.KeyFormat.New(0x5C, uint64(0), uint32(0))
+
+	// allowanceHistoryKeyFmt is the key format used for recorded allowance changes
+	// (owner address, beneficiary address, sequence number).
+	//
+	// Value is CBOR-serialized staking.AllowanceHistoryEntry.
+	allowanceHistoryKeyFmt = consensus.KeyFormat.New(0x5D, &staking.Address{}, &staking.Address{}, uint64(0))
+
+	// allowanceDelegationKeyFmt is the key format used for delegation shares escrowed by a
+	// beneficiary from an owner's allowance (owner address, escrow address, beneficiary address).
+	//
+	// Value is a CBOR-serialized quantity.
+	allowanceDelegationKeyFmt = consensus.KeyFormat.New(0x5E, &staking.Address{}, &staking.Address{}, &staking.Address{})
+
 	logger = logging.GetLogger("cometbft/staking")
 )
 
@@ -530,6 +548,128 @@ func (s *ImmutableState) GovernanceDeposits(ctx context.Context) (*quantity.Quan
 	return s.loadStoredBalance(ctx, governanceDepositsKeyFmt)
 }
 
+// EvidenceRecords returns the records of processed validator misbehavior evidence committed in
+// blocks with heights in the given (inclusive) range. A zero toHeight means that there is no
+// upper bound. The first offset matching records are skipped and at most limit records are
+// returned, unless limit is zero.
+func (s *ImmutableState) EvidenceRecords(
+	ctx context.Context,
+	fromHeight int64,
+	toHeight int64,
+	offset uint64,
+	limit uint32,
+) ([]*consensus.EvidenceRecord, error) {
+	it := s.state.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		records []*consensus.EvidenceRecord
+		skipped uint64
+	)
+	for it.Seek(evidenceRecordsKeyFmt.Encode(uint64(max(fromHeight, 0)))); it.Valid(); it.Next() {
+		var (
+			height uint64
+			index  uint32
+		)
+		if !evidenceRecordsKeyFmt.Decode(it.Key(), &height, &index) {
+			break
+		}
+		if toHeight > 0 && height > uint64(toHeight) {
+			break
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		var record consensus.EvidenceRecord
+		if err := cbor.Unmarshal(it.Value(), &record); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		records = append(records, &record)
+
+		if limit > 0 && len(records) >= int(limit) {
+			break
+		}
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return records, nil
+}
+
+// AllowanceHistory returns the recorded allowance changes for the given owner/beneficiary
+// combination, ordered from oldest to newest. The first offset entries are skipped and at most
+// limit entries are returned, unless limit is zero.
+func (s *ImmutableState) AllowanceHistory(
+	ctx context.Context,
+	owner staking.Address,
+	beneficiary staking.Address,
+	offset uint64,
+	limit uint32,
+) ([]*staking.AllowanceHistoryEntry, error) {
+	it := s.state.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		entries []*staking.AllowanceHistoryEntry
+		skipped uint64
+	)
+	for it.Seek(allowanceHistoryKeyFmt.Encode(&owner, &beneficiary)); it.Valid(); it.Next() {
+		var (
+			decOwner       staking.Address
+			decBeneficiary staking.Address
+		)
+		if !allowanceHistoryKeyFmt.Decode(it.Key(), &decOwner, &decBeneficiary) {
+			break
+		}
+		if !decOwner.Equal(owner) || !decBeneficiary.Equal(beneficiary) {
+			break
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		var entry staking.AllowanceHistoryEntry
+		if err := cbor.Unmarshal(it.Value(), &entry); err != nil {
+			return nil, abciAPI.UnavailableStateError(err)
+		}
+		entries = append(entries, &entry)
+
+		if limit > 0 && len(entries) >= int(limit) {
+			break
+		}
+	}
+	if it.Err() != nil {
+		return nil, abciAPI.UnavailableStateError(it.Err())
+	}
+	return entries, nil
+}
+
+// AllowanceDelegationShares returns the number of delegation shares that the given beneficiary
+// escrowed from the owner's allowance into the given escrow account and did not reclaim yet.
+func (s *ImmutableState) AllowanceDelegationShares(
+	ctx context.Context,
+	owner staking.Address,
+	escrowAddr staking.Address,
+	beneficiary staking.Address,
+) (*quantity.Quantity, error) {
+	value, err := s.state.Get(ctx, allowanceDelegationKeyFmt.Encode(&owner, &escrowAddr, &beneficiary))
+	if err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	if value == nil {
+		return &quantity.Quantity{}, nil
+	}
+
+	var shares quantity.Quantity
+	if err = cbor.Unmarshal(value, &shares); err != nil {
+		return nil, abciAPI.UnavailableStateError(err)
+	}
+	return &shares, nil
+}
+
 type EpochSigning struct {
 	Total    uint64
 	ByEntity map[signature.PublicKey]uint64
@@ -658,6 +798,117 @@ func (s *MutableState) SetConsensusParameters(ctx context.Context, params *staki
 	return abciAPI.UnavailableStateError(err)
 }
 
+// PutEvidenceRecord stores a record of processed validator misbehavior evidence.
+func (s *MutableState) PutEvidenceRecord(ctx context.Context, record *consensus.EvidenceRecord) error {
+	err := s.ms.Insert(ctx, evidenceRecordsKeyFmt.Encode(uint64(record.Height), record.Index), cbor.Marshal(record))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// PruneEvidenceRecords removes the records of all evidence committed in blocks before the given
+// height.
+func (s *MutableState) PruneEvidenceRecords(ctx context.Context, before int64) error {
+	it := s.ms.NewIterator(ctx)
+	defer it.Close()
+
+	var toDelete [][]byte
+	for it.Seek(evidenceRecordsKeyFmt.Encode()); it.Valid(); it.Next() {
+		var (
+			height uint64
+			index  uint32
+		)
+		if !evidenceRecordsKeyFmt.Decode(it.Key(), &height, &index) {
+			break
+		}
+		if int64(height) >= before {
+			break
+		}
+		toDelete = append(toDelete, it.Key())
+	}
+	if it.Err() != nil {
+		return abciAPI.UnavailableStateError(it.Err())
+	}
+
+	for _, key := range toDelete {
+		if err := s.ms.Remove(ctx, key); err != nil {
+			return abciAPI.UnavailableStateError(err)
+		}
+	}
+	return nil
+}
+
+// AppendAllowanceHistory records an allowance change for the given owner/beneficiary combination.
+// The oldest recorded changes are removed so that at most maxEntries changes are retained.
+func (s *MutableState) AppendAllowanceHistory(
+	ctx context.Context,
+	owner staking.Address,
+	beneficiary staking.Address,
+	entry *staking.AllowanceHistoryEntry,
+	maxEntries uint32,
+) error {
+	if maxEntries == 0 {
+		return nil
+	}
+
+	it := s.ms.NewIterator(ctx)
+	defer it.Close()
+
+	var (
+		keys    [][]byte
+		nextSeq uint64
+	)
+	for it.Seek(allowanceHistoryKeyFmt.Encode(&owner, &beneficiary)); it.Valid(); it.Next() {
+		var (
+			decOwner       staking.Address
+			decBeneficiary staking.Address
+			seq            uint64
+		)
+		if !allowanceHistoryKeyFmt.Decode(it.Key(), &decOwner, &decBeneficiary, &seq) {
+			break
+		}
+		if !decOwner.Equal(owner) || !decBeneficiary.Equal(beneficiary) {
+			break
+		}
+		keys = append(keys, it.Key())
+		nextSeq = seq + 1
+	}
+	if it.Err() != nil {
+		return abciAPI.UnavailableStateError(it.Err())
+	}
+
+	// Prune the oldest entries to make room for the new one.
+	if excess := len(keys) + 1 - int(maxEntries); excess > 0 {
+		for _, key := range keys[:excess] {
+			if err := s.ms.Remove(ctx, key); err != nil {
+				return abciAPI.UnavailableStateError(err)
+			}
+		}
+	}
+
+	err := s.ms.Insert(ctx, allowanceHistoryKeyFmt.Encode(&owner, &beneficiary, nextSeq), cbor.Marshal(entry))
+	return abciAPI.UnavailableStateError(err)
+}
+
+// SetAllowanceDelegationShares sets the number of delegation shares that the given beneficiary
+// escrowed from the owner's allowance into the given escrow account.
+func (s *MutableState) SetAllowanceDelegationShares(
+	ctx context.Context,
+	owner staking.Address,
+	escrowAddr staking.Address,
+	beneficiary staking.Address,
+	shares *quantity.Quantity,
+) error {
+	key := allowanceDelegationKeyFmt.Encode(&owner, &escrowAddr, &beneficiary)
+
+	// Remove the record if there are no more shares in it.
+	if shares.IsZero() {
+		err := s.ms.Remove(ctx, key)
+		return abciAPI.UnavailableStateError(err)
+	}
+
+	err := s.ms.Insert(ctx, key, cbor.Marshal(shares))
+	return abciAPI.UnavailableStateError(err)
+}
+
 func (s *MutableState) SetDelegation(
 	ctx context.Context,
 	delegatorAddr, escrowAddr staking.Address,
@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
@@ -582,6 +583,60 @@ func TestEpochSigning(t *testing.T) {
 	require.Empty(esClear.ByEntity, "cleared epoch signing info by entity")
 }
 
+func TestEvidenceRecords(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextBeginBlock)
+	defer ctx.Close()
+
+	s := NewMutableState(ctx.State())
+
+	records, err := s.EvidenceRecords(ctx, 0, 0, 0, 0)
+	require.NoError(err, "EvidenceRecords")
+	require.Empty(records, "there should be no evidence records")
+
+	for _, height := range []int64{10, 20, 30} {
+		for index := range uint32(2) {
+			err = s.PutEvidenceRecord(ctx, &consensus.EvidenceRecord{
+				Height:            height,
+				Index:             index,
+				Reason:            staking.SlashConsensusEquivocation,
+				MisbehaviorHeight: height - 1,
+				ValidatorAddress:  []byte{byte(height), byte(index)},
+			})
+			require.NoError(err, "PutEvidenceRecord")
+		}
+	}
+
+	records, err = s.EvidenceRecords(ctx, 0, 0, 0, 0)
+	require.NoError(err, "EvidenceRecords")
+	require.Len(records, 6, "all evidence records should be returned")
+	require.EqualValues(10, records[0].Height, "evidence records should be ordered by height")
+	require.EqualValues(1, records[1].Index, "evidence records should be ordered by index")
+
+	records, err = s.EvidenceRecords(ctx, 20, 20, 0, 0)
+	require.NoError(err, "EvidenceRecords")
+	require.Len(records, 2, "only evidence records in the height range should be returned")
+	require.EqualValues(20, records[0].Height)
+	require.EqualValues(20, records[1].Height)
+
+	records, err = s.EvidenceRecords(ctx, 11, 0, 1, 2)
+	require.NoError(err, "EvidenceRecords")
+	require.Len(records, 2, "the number of evidence records should be limited")
+	require.EqualValues(20, records[0].Height)
+	require.EqualValues(1, records[0].Index)
+	require.EqualValues(30, records[1].Height)
+	require.EqualValues(0, records[1].Index)
+
+	err = s.PruneEvidenceRecords(ctx, 30)
+	require.NoError(err, "PruneEvidenceRecords")
+	records, err = s.EvidenceRecords(ctx, 0, 0, 0, 0)
+	require.NoError(err, "EvidenceRecords")
+	require.Len(records, 2, "old evidence records should be pruned")
+	require.EqualValues(30, records[0].Height)
+}
+
 func TestProposalDeposits(t *testing.T) {
 	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
 	ctx := appState.NewContext(abciAPI.ContextDeliverTx)
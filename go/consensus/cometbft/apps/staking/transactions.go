@@ -8,7 +8,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingApi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/features"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func isTransferPermitted(params *staking.ConsensusParameters, fromAddr staking.Address) (permitted bool) {
@@ -647,6 +649,7 @@ func (app *Application) allow(
 		acct.General.Allowances = make(map[staking.Address]quantity.Quantity)
 	}
 	allowance := acct.General.Allowances[allow.Beneficiary]
+	oldAllowance := allowance.Clone()
 	var amountChange *quantity.Quantity
 	switch allow.Negative {
 	case false:
@@ -688,12 +691,17 @@ func (app *Application) allow(
 		return fmt.Errorf("failed to set account: %w", err)
 	}
 
+	if oldAllowance, err = recordAllowanceChange(ctx, state, params, addr, allow.Beneficiary, oldAllowance, &allowance); err != nil {
+		return err
+	}
+
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.AllowanceChangeEvent{
 		Owner:        addr,
 		Beneficiary:  allow.Beneficiary,
 		Allowance:    allowance,
 		Negative:     allow.Negative,
 		AmountChange: *amountChange,
+		OldAllowance: oldAllowance,
 	}))
 
 	return nil
@@ -752,7 +760,10 @@ func (app *Application) withdraw(
 
 	// Check if a withdrawal hook is configured for the source account. In this case the hook may
 	// override configured allowances.
-	var allowance quantity.Quantity
+	var (
+		allowance    quantity.Quantity
+		oldAllowance *quantity.Quantity
+	)
 	switch dst, ok := from.General.Hooks[staking.HookKindWithdraw]; ok {
 	case true:
 		// Withdraw hook configured for the source account, use custom authorization logic.
@@ -774,6 +785,7 @@ func (app *Application) withdraw(
 			// Fail early in case there is no allowance configured.
 			return nil, staking.ErrForbidden
 		}
+		oldAllowance = allowance.Clone()
 		if err = allowance.Sub(&withdraw.Amount); err != nil {
 			return nil, staking.ErrForbidden
 		}
@@ -821,6 +833,12 @@ func (app *Application) withdraw(
 		return nil, fmt.Errorf("failed to set account: %w", err)
 	}
 
+	if oldAllowance != nil {
+		if oldAllowance, err = recordAllowanceChange(ctx, state, params, withdraw.From, toAddr, oldAllowance, &allowance); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.TransferEvent{
 		From:   withdraw.From,
 		To:     toAddr,
@@ -833,6 +851,7 @@ func (app *Application) withdraw(
 		Allowance:    allowance,
 		Negative:     true,
 		AmountChange: withdraw.Amount,
+		OldAllowance: oldAllowance,
 	}))
 
 	ctx.Commit()
@@ -844,3 +863,421 @@ func (app *Application) withdraw(
 		AmountChange: withdraw.Amount,
 	}, nil
 }
+
+func (app *Application) delegateFromAllowance(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	delegate *staking.DelegateFromAllowance,
+) (*staking.AddEscrowResult, error) {
+	// Allow delegating from allowances with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, fmt.Errorf("%w: delegate from allowance not enabled", staking.ErrInvalidArgument)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil, nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpDelegateFromAllowance, params.GasCosts); err != nil {
+		return nil, err
+	}
+
+	// Return early for simulation as we only need gas accounting.
+	if ctx.IsSimulation() {
+		return nil, nil
+	}
+
+	// Check if sender provided at least a minimum amount of stake.
+	if delegate.Amount.Cmp(&params.MinDelegationAmount) < 0 {
+		return nil, staking.ErrUnderMinDelegationAmount
+	}
+
+	// Allowances are disabled in case either max allowances is zero or if transfers are disabled.
+	if params.DisableTransfers || params.MaxAllowances == 0 {
+		return nil, staking.ErrForbidden
+	}
+
+	// Validate addresses -- if either is reserved or both are equal, the method should fail.
+	beneficiaryAddr := ctx.CallerAddress()
+	if beneficiaryAddr.IsReserved() || delegate.From.IsReserved() {
+		return nil, staking.ErrForbidden
+	}
+	if beneficiaryAddr.Equal(delegate.From) {
+		return nil, staking.ErrInvalidArgument
+	}
+	if !delegate.From.Equal(delegate.Account) && params.DisableDelegation {
+		return nil, staking.ErrForbidden
+	}
+
+	// Start a new transaction and rollback in case we fail.
+	ctx = ctx.NewTransaction()
+	defer ctx.Close()
+
+	from, err := state.Account(ctx, delegate.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	// Debit the allowance. Withdrawal hooks are not consulted as the escrowed stake remains owned
+	// by the source account.
+	allowance, ok := from.General.Allowances[beneficiaryAddr]
+	if !ok {
+		// Fail early in case there is no allowance configured.
+		return nil, staking.ErrForbidden
+	}
+	oldAllowance := allowance.Clone()
+	if err = allowance.Sub(&delegate.Amount); err != nil {
+		return nil, staking.ErrForbidden
+	}
+	if allowance.IsZero() {
+		// In case the new allowance is equal to zero, remove it.
+		delete(from.General.Allowances, beneficiaryAddr)
+	} else {
+		// Otherwise update the allowance.
+		from.General.Allowances[beneficiaryAddr] = allowance
+	}
+
+	// Fetch escrow account.
+	//
+	// NOTE: Could be the same account, so make sure to not have two duplicate
+	//       copies of it and overwrite it later.
+	var to *staking.Account
+	if delegate.From.Equal(delegate.Account) {
+		to = from
+	} else {
+		to, err = state.Account(ctx, delegate.Account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account: %w", err)
+		}
+	}
+
+	// Fetch delegation, owned by the source account.
+	delegation, err := state.Delegation(ctx, delegate.From, delegate.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delegation: %w", err)
+	}
+
+	obtainedShares, err := to.Escrow.Active.Deposit(&delegation.Shares, &from.General.Balance, &delegate.Amount)
+	if err != nil {
+		ctx.Logger().Debug("DelegateFromAllowance: failed to escrow stake",
+			"err", err,
+			"from", delegate.From,
+			"to", delegate.Account,
+			"beneficiary", beneficiaryAddr,
+			"amount", delegate.Amount,
+		)
+		return nil, staking.ErrInsufficientBalance
+	}
+
+	// Check against minimum balance.
+	if from.General.Balance.Cmp(&params.MinTransactBalance) < 0 {
+		ctx.Logger().Debug("after delegate from allowance account balance too low",
+			"account_addr", delegate.From,
+			"account_balance", from.General.Balance,
+			"min_transact_balance", params.MinTransactBalance,
+		)
+		return nil, staking.ErrBalanceTooLow
+	}
+
+	// Track the shares escrowed by the beneficiary so that it can later reclaim them.
+	allowanceShares, err := state.AllowanceDelegationShares(ctx, delegate.From, delegate.Account, beneficiaryAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch allowance delegation shares: %w", err)
+	}
+	if err = allowanceShares.Add(obtainedShares); err != nil {
+		return nil, fmt.Errorf("failed to add allowance delegation shares: %w", err)
+	}
+
+	// Commit accounts.
+	if err = state.SetAccount(ctx, delegate.From, from); err != nil {
+		return nil, fmt.Errorf("failed to set account: %w", err)
+	}
+	if !delegate.From.Equal(delegate.Account) {
+		if err = state.SetAccount(ctx, delegate.Account, to); err != nil {
+			return nil, fmt.Errorf("failed to set account: %w", err)
+		}
+	}
+	// Commit delegation descriptor.
+	if err = state.SetDelegation(ctx, delegate.From, delegate.Account, delegation); err != nil {
+		return nil, fmt.Errorf("failed to set delegation: %w", err)
+	}
+	if err = state.SetAllowanceDelegationShares(ctx, delegate.From, delegate.Account, beneficiaryAddr, allowanceShares); err != nil {
+		return nil, fmt.Errorf("failed to set allowance delegation shares: %w", err)
+	}
+
+	if oldAllowance, err = recordAllowanceChange(ctx, state, params, delegate.From, beneficiaryAddr, oldAllowance, &allowance); err != nil {
+		return nil, err
+	}
+
+	ctx.Logger().Debug("DelegateFromAllowance: escrowed stake",
+		"from", delegate.From,
+		"to", delegate.Account,
+		"beneficiary", beneficiaryAddr,
+		"amount", delegate.Amount,
+		"obtained_shares", obtainedShares,
+	)
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.AddEscrowEvent{
+		Owner:       delegate.From,
+		Escrow:      delegate.Account,
+		Amount:      delegate.Amount,
+		NewShares:   *obtainedShares,
+		Beneficiary: &beneficiaryAddr,
+	}))
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.AllowanceChangeEvent{
+		Owner:        delegate.From,
+		Beneficiary:  beneficiaryAddr,
+		Allowance:    allowance,
+		Negative:     true,
+		AmountChange: delegate.Amount,
+		OldAllowance: oldAllowance,
+	}))
+
+	ctx.Commit()
+
+	return &staking.AddEscrowResult{
+		Owner:     delegate.From,
+		Escrow:    delegate.Account,
+		Amount:    delegate.Amount,
+		NewShares: *obtainedShares,
+	}, nil
+}
+
+func (app *Application) reclaimEscrowFromAllowance(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	reclaim *staking.ReclaimEscrowFromAllowance,
+) (*staking.ReclaimEscrowResult, error) {
+	// No sense if there is nothing to reclaim.
+	if reclaim.Shares.IsZero() {
+		return nil, staking.ErrInvalidArgument
+	}
+
+	// Allow reclaiming escrow via allowances with the 24.3 release.
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, fmt.Errorf("%w: delegate from allowance not enabled", staking.ErrInvalidArgument)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil, nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch consensus parameters: %w", err)
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpReclaimEscrowFromAllowance, params.GasCosts); err != nil {
+		return nil, err
+	}
+
+	// Return early for simulation as we only need gas accounting.
+	if ctx.IsSimulation() {
+		return nil, nil
+	}
+
+	// Allowances are disabled in case either max allowances is zero or if transfers are disabled.
+	if params.DisableTransfers || params.MaxAllowances == 0 {
+		return nil, staking.ErrForbidden
+	}
+
+	// Validate addresses -- if either is reserved or both are equal, the method should fail.
+	beneficiaryAddr := ctx.CallerAddress()
+	if beneficiaryAddr.IsReserved() || reclaim.From.IsReserved() {
+		return nil, staking.ErrForbidden
+	}
+	if beneficiaryAddr.Equal(reclaim.From) {
+		return nil, staking.ErrInvalidArgument
+	}
+
+	// The beneficiary may only reclaim shares it escrowed from the owner's allowance.
+	allowanceShares, err := state.AllowanceDelegationShares(ctx, reclaim.From, reclaim.Account, beneficiaryAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch allowance delegation shares: %w", err)
+	}
+	if err = allowanceShares.Sub(&reclaim.Shares); err != nil {
+		return nil, staking.ErrForbidden
+	}
+
+	toAddr := reclaim.From
+	to, err := state.Account(ctx, toAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account: %w", err)
+	}
+
+	// Fetch escrow account.
+	//
+	// NOTE: Could be the same account, so make sure to not have two duplicate
+	//       copies of it and overwrite it later.
+	var from *staking.Account
+	if toAddr.Equal(reclaim.Account) {
+		from = to
+	} else {
+		if params.DisableDelegation {
+			return nil, staking.ErrForbidden
+		}
+		from, err = state.Account(ctx, reclaim.Account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account: %w", err)
+		}
+	}
+
+	// Fetch delegation, owned by the original owner.
+	delegation, err := state.Delegation(ctx, toAddr, reclaim.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delegation: %w", err)
+	}
+
+	// Fetch debonding interval and current epoch.
+	debondingInterval, err := state.DebondingInterval(ctx)
+	if err != nil {
+		ctx.Logger().Error("ReclaimEscrowFromAllowance: failed to query debonding interval",
+			"err", err,
+		)
+		return nil, err
+	}
+	epoch, err := app.state.GetEpoch(ctx, ctx.BlockHeight()+1)
+	if err != nil {
+		return nil, err
+	}
+
+	deb := staking.DebondingDelegation{
+		DebondEndTime: epoch + debondingInterval,
+	}
+
+	var baseUnits quantity.Quantity
+
+	if err = from.Escrow.Active.Withdraw(&baseUnits, &delegation.Shares, &reclaim.Shares); err != nil {
+		ctx.Logger().Debug("ReclaimEscrowFromAllowance: failed to redeem escrow shares",
+			"err", err,
+			"to", toAddr,
+			"from", reclaim.Account,
+			"beneficiary", beneficiaryAddr,
+			"shares", reclaim.Shares,
+		)
+		return nil, err
+	}
+	stakeAmount := baseUnits.Clone()
+
+	var debondingShares *quantity.Quantity
+	if debondingShares, err = from.Escrow.Debonding.Deposit(&deb.Shares, &baseUnits, stakeAmount); err != nil {
+		ctx.Logger().Debug("ReclaimEscrowFromAllowance: failed to debond shares",
+			"err", err,
+			"to", toAddr,
+			"from", reclaim.Account,
+			"beneficiary", beneficiaryAddr,
+			"shares", reclaim.Shares,
+			"base_units", stakeAmount,
+		)
+		return nil, err
+	}
+
+	if !baseUnits.IsZero() {
+		ctx.Logger().Debug("ReclaimEscrowFromAllowance: inconsistency in transferring stake from active escrow to debonding",
+			"remaining_base_units", baseUnits,
+		)
+		return nil, staking.ErrInvalidArgument
+	}
+
+	// The owner may have reclaimed some of the shares directly, make sure that the beneficiary
+	// is never tracked as having more shares than the delegation.
+	if allowanceShares.Cmp(&delegation.Shares) > 0 {
+		allowanceShares = delegation.Shares.Clone()
+	}
+
+	// Include the end time epoch as the disambiguator. If a debonding delegation for the same account
+	// and end time already exists, the delegations will be merged.
+	if err = state.SetDebondingDelegation(ctx, toAddr, reclaim.Account, deb.DebondEndTime, &deb); err != nil {
+		return nil, fmt.Errorf("failed to set debonding delegation: %w", err)
+	}
+
+	if err = state.SetDelegation(ctx, toAddr, reclaim.Account, delegation); err != nil {
+		return nil, fmt.Errorf("failed to set delegation: %w", err)
+	}
+	if err = state.SetAllowanceDelegationShares(ctx, toAddr, reclaim.Account, beneficiaryAddr, allowanceShares); err != nil {
+		return nil, fmt.Errorf("failed to set allowance delegation shares: %w", err)
+	}
+	if err = state.SetAccount(ctx, toAddr, to); err != nil {
+		return nil, fmt.Errorf("failed to set account: %w", err)
+	}
+	if !toAddr.Equal(reclaim.Account) {
+		if err = state.SetAccount(ctx, reclaim.Account, from); err != nil {
+			return nil, fmt.Errorf("failed to set account: %w", err)
+		}
+	}
+
+	ctx.Logger().Debug("ReclaimEscrowFromAllowance: started debonding stake",
+		"from", reclaim.Account,
+		"to", toAddr,
+		"beneficiary", beneficiaryAddr,
+		"base_units", stakeAmount,
+		"active_shares", reclaim.Shares,
+		"debonding_shares", debondingShares,
+		"debond_end_time", deb.DebondEndTime,
+	)
+
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).TypedAttribute(&staking.DebondingStartEscrowEvent{
+		Owner:           toAddr,
+		Escrow:          reclaim.Account,
+		Amount:          *stakeAmount,
+		ActiveShares:    reclaim.Shares,
+		DebondingShares: *debondingShares,
+		DebondEndTime:   deb.DebondEndTime,
+		Beneficiary:     &beneficiaryAddr,
+	}))
+
+	return &staking.ReclaimEscrowResult{
+		Owner:           toAddr,
+		Escrow:          reclaim.Account,
+		Amount:          *stakeAmount,
+		DebondingShares: *debondingShares,
+		RemainingShares: delegation.Shares,
+		DebondEndTime:   deb.DebondEndTime,
+	}, nil
+}
+
+// recordAllowanceChange records a change of the allowance for the given beneficiary in the
+// allowance history and returns the old allowance to be included in the emitted event.
+//
+// Nothing is recorded and nil is returned before the 24.3 release.
+func recordAllowanceChange(
+	ctx *api.Context,
+	state *stakingState.MutableState,
+	params *staking.ConsensusParameters,
+	owner staking.Address,
+	beneficiary staking.Address,
+	oldAllowance *quantity.Quantity,
+	allowance *quantity.Quantity,
+) (*quantity.Quantity, error) {
+	enabled, err := features.IsFeatureVersion(ctx, migrations.Version243)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	entry := &staking.AllowanceHistoryEntry{
+		Height:       ctx.BlockHeight() + 1,
+		OldAllowance: *oldAllowance,
+		Allowance:    *allowance,
+	}
+	if err = state.AppendAllowanceHistory(ctx, owner, beneficiary, entry, params.MaxAllowanceHistory); err != nil {
+		return nil, fmt.Errorf("failed to record allowance change: %w", err)
+	}
+	return oldAllowance, nil
+}
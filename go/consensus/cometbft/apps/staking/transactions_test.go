@@ -1,16 +1,21 @@
 package staking
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
 	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	consensusGenesis "github.com/oasisprotocol/oasis-core/go/consensus/genesis"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
 
 func TestIsTransferPermitted(t *testing.T) {
@@ -129,6 +134,9 @@ func TestAllow(t *testing.T) {
 	defer ctx.Close()
 
 	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
 
 	app := &Application{
 		state: appState,
@@ -321,6 +329,9 @@ func TestWithdraw(t *testing.T) {
 	defer ctx.Close()
 
 	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
 
 	app := &Application{
 		state: appState,
@@ -628,6 +639,317 @@ func TestWithdraw(t *testing.T) {
 	}
 }
 
+func TestAllowanceHistory(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+
+	app := &Application{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr1 := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr2 := staking.NewAddress(pk2)
+
+	err := stakeState.SetTotalSupply(ctx, quantity.NewFromUint64(1_000))
+	require.NoError(err, "SetTotalSupply")
+	err = stakeState.SetAccount(ctx, addr1, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(500),
+		},
+	})
+	require.NoError(err, "SetAccount")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		MaxAllowances:       1,
+		MaxAllowanceHistory: 2,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	allow := func(amount uint64, negative bool) *staking.AllowanceChangeEvent {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		defer txCtx.Close()
+		txCtx.SetTxSigner(pk1)
+
+		err = app.allow(txCtx, stakeState, &staking.Allow{
+			Beneficiary:  addr2,
+			Negative:     negative,
+			AmountChange: *quantity.NewFromUint64(amount),
+		})
+		require.NoError(err, "allow")
+
+		var ev staking.AllowanceChangeEvent
+		require.NoError(txCtx.DecodeEvent(0, &ev), "DecodeEvent")
+		return &ev
+	}
+	history := func(offset uint64, limit uint32) []*staking.AllowanceHistoryEntry {
+		entries, err := stakeState.AllowanceHistory(ctx, addr1, addr2, offset, limit)
+		require.NoError(err, "AllowanceHistory")
+		return entries
+	}
+
+	// Before the upgrade, the old allowance is neither emitted nor recorded.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	ev := allow(100, false)
+	require.Nil(ev.OldAllowance, "old allowance should not be emitted")
+	require.Empty(history(0, 0), "allowance change should not be recorded")
+
+	// After the upgrade, allowance changes are emitted and recorded.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	ev = allow(50, false)
+	require.Equal(quantity.NewFromUint64(100), ev.OldAllowance, "old allowance should be emitted")
+	require.Equal(*quantity.NewFromUint64(150), ev.Allowance, "new allowance should be emitted")
+
+	ev = allow(30, true)
+	require.Equal(quantity.NewFromUint64(150), ev.OldAllowance, "old allowance should be emitted")
+
+	// Withdrawals also change the allowance.
+	txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+	defer txCtx.Close()
+	txCtx.SetTxSigner(pk2)
+	_, err = app.withdraw(txCtx, stakeState, &staking.Withdraw{
+		From:   addr1,
+		Amount: *quantity.NewFromUint64(20),
+	})
+	require.NoError(err, "withdraw")
+
+	// Only the most recent changes are retained.
+	entries := history(0, 0)
+	require.Len(entries, 2, "history should be bounded")
+	require.Equal(*quantity.NewFromUint64(150), entries[0].OldAllowance)
+	require.Equal(*quantity.NewFromUint64(120), entries[0].Allowance)
+	require.Equal(*quantity.NewFromUint64(120), entries[1].OldAllowance)
+	require.Equal(*quantity.NewFromUint64(100), entries[1].Allowance)
+
+	// Pagination.
+	require.Equal(entries[1:], history(1, 0), "offset should skip the oldest entries")
+	require.Equal(entries[:1], history(0, 1), "limit should bound the number of entries")
+	require.Empty(history(2, 0), "offset past the end should return nothing")
+
+	// Other pairs are not affected.
+	other, err := stakeState.AllowanceHistory(ctx, addr2, addr1, 0, 0)
+	require.NoError(err, "AllowanceHistory")
+	require.Empty(other, "history of other pairs should be empty")
+}
+
+func TestDelegateFromAllowance(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+	consState := consensusState.NewMutableState(ctx.State())
+
+	app := &Application{
+		state: appState,
+	}
+
+	pk1 := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	owner := staking.NewAddress(pk1)
+	pk2 := signature.NewPublicKey("bbbfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	beneficiary := staking.NewAddress(pk2)
+	pk3 := signature.NewPublicKey("cccfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	other := staking.NewAddress(pk3)
+	escrowAddr := staking.NewAddress(signature.NewPublicKey("dddfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"))
+
+	err := stakeState.SetAccount(ctx, owner, &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: *quantity.NewFromUint64(1_000),
+			Allowances: map[staking.Address]quantity.Quantity{
+				beneficiary: *quantity.NewFromUint64(300),
+				other:       *quantity.NewFromUint64(100),
+			},
+		},
+	})
+	require.NoError(err, "SetAccount")
+	err = stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		MaxAllowances:       2,
+		MaxAllowanceHistory: 4,
+		MinDelegationAmount: *quantity.NewFromUint64(10),
+		MinTransactBalance:  *quantity.NewFromUint64(500),
+		DebondingInterval:   2,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	delegate := func(signer signature.PublicKey, amount uint64) (*abciAPI.Context, *staking.AddEscrowResult, error) {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		t.Cleanup(txCtx.Close)
+		txCtx.SetTxSigner(signer)
+
+		result, err := app.delegateFromAllowance(txCtx, stakeState, &staking.DelegateFromAllowance{
+			From:    owner,
+			Account: escrowAddr,
+			Amount:  *quantity.NewFromUint64(amount),
+		})
+		return txCtx, result, err
+	}
+	reclaim := func(signer signature.PublicKey, shares uint64) (*abciAPI.Context, *staking.ReclaimEscrowResult, error) {
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		t.Cleanup(txCtx.Close)
+		txCtx.SetTxSigner(signer)
+
+		result, err := app.reclaimEscrowFromAllowance(txCtx, stakeState, &staking.ReclaimEscrowFromAllowance{
+			From:    owner,
+			Account: escrowAddr,
+			Shares:  *quantity.NewFromUint64(shares),
+		})
+		return txCtx, result, err
+	}
+	account := func() *staking.Account {
+		acct, err := stakeState.Account(ctx, owner)
+		require.NoError(err, "Account")
+		return acct
+	}
+	allowanceShares := func(beneficiary staking.Address) *quantity.Quantity {
+		shares, err := stakeState.AllowanceDelegationShares(ctx, owner, escrowAddr, beneficiary)
+		require.NoError(err, "AllowanceDelegationShares")
+		return shares
+	}
+
+	// Before the upgrade, the methods are not available.
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{})
+	require.NoError(err, "SetConsensusParameters")
+
+	_, _, err = delegate(pk2, 100)
+	require.ErrorIs(err, staking.ErrInvalidArgument, "delegate from allowance should not be enabled")
+	_, _, err = reclaim(pk2, 100)
+	require.ErrorIs(err, staking.ErrInvalidArgument, "reclaim escrow from allowance should not be enabled")
+
+	err = consState.SetConsensusParameters(ctx, &consensusGenesis.Parameters{
+		FeatureVersion: &migrations.Version243,
+	})
+	require.NoError(err, "SetConsensusParameters")
+
+	// Failures should leave the account untouched.
+	for _, tc := range []struct {
+		msg     string
+		signer  signature.PublicKey
+		balance uint64
+		amount  uint64
+		err     error
+	}{
+		{"should fail under min delegation amount", pk2, 1_000, 9, staking.ErrUnderMinDelegationAmount},
+		{"should fail with equal addresses", pk1, 1_000, 100, staking.ErrInvalidArgument},
+		{"should fail without allowance", signature.NewPublicKey("eeefffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"), 1_000, 100, staking.ErrForbidden},
+		{"should fail on allowance underflow", pk2, 1_000, 301, staking.ErrForbidden},
+		{"should fail on insufficient balance", pk2, 250, 300, staking.ErrInsufficientBalance},
+		{"should fail under min transact balance", pk2, 799, 300, staking.ErrBalanceTooLow},
+	} {
+		acct := account()
+		acct.General.Balance = *quantity.NewFromUint64(tc.balance)
+		require.NoError(stakeState.SetAccount(ctx, owner, acct), "SetAccount")
+		before := account()
+
+		_, result, err := delegate(tc.signer, tc.amount)
+		require.ErrorIs(err, tc.err, tc.msg)
+		require.Nil(result, tc.msg)
+		require.Equal(before, account(), tc.msg)
+	}
+
+	acct := account()
+	acct.General.Balance = *quantity.NewFromUint64(1_000)
+	require.NoError(stakeState.SetAccount(ctx, owner, acct), "SetAccount")
+
+	// Delegating debits the allowance and creates a delegation owned by the owner.
+	txCtx, result, err := delegate(pk2, 200)
+	require.NoError(err, "delegate from allowance")
+	require.Equal(&staking.AddEscrowResult{
+		Owner:     owner,
+		Escrow:    escrowAddr,
+		Amount:    *quantity.NewFromUint64(200),
+		NewShares: *quantity.NewFromUint64(200),
+	}, result)
+
+	var addEv staking.AddEscrowEvent
+	require.NoError(txCtx.DecodeEvent(0, &addEv), "DecodeEvent")
+	require.Equal(owner, addEv.Owner, "event should identify the owner")
+	require.Equal(escrowAddr, addEv.Escrow, "event should identify the escrow account")
+	require.Equal(&beneficiary, addEv.Beneficiary, "event should identify the beneficiary")
+	var allowanceEv staking.AllowanceChangeEvent
+	require.NoError(txCtx.DecodeEvent(1, &allowanceEv), "DecodeEvent")
+	require.Equal(*quantity.NewFromUint64(100), allowanceEv.Allowance)
+	require.Equal(quantity.NewFromUint64(300), allowanceEv.OldAllowance)
+	require.True(allowanceEv.Negative)
+
+	acct = account()
+	require.Equal(*quantity.NewFromUint64(800), acct.General.Balance)
+	require.Equal(*quantity.NewFromUint64(100), acct.General.Allowances[beneficiary])
+	delegation, err := stakeState.Delegation(ctx, owner, escrowAddr)
+	require.NoError(err, "Delegation")
+	require.Equal(*quantity.NewFromUint64(200), delegation.Shares, "delegation should be owned by the owner")
+	benDelegation, err := stakeState.Delegation(ctx, beneficiary, escrowAddr)
+	require.NoError(err, "Delegation")
+	require.True(benDelegation.Shares.IsZero(), "beneficiary should not own a delegation")
+	require.Equal(quantity.NewFromUint64(200), allowanceShares(beneficiary))
+
+	// Exhausting the allowance removes it.
+	_, _, err = delegate(pk2, 100)
+	require.NoError(err, "delegate from allowance")
+	_, ok := account().General.Allowances[beneficiary]
+	require.False(ok, "exhausted allowance should be removed")
+	_, _, err = delegate(pk2, 10)
+	require.ErrorIs(err, staking.ErrForbidden, "delegating past the allowance should fail")
+	require.Equal(quantity.NewFromUint64(300), allowanceShares(beneficiary))
+
+	// Beneficiaries can only reclaim shares they escrowed.
+	_, _, err = reclaim(pk3, 1)
+	require.ErrorIs(err, staking.ErrForbidden, "other beneficiary should not be able to reclaim")
+	_, _, err = reclaim(pk2, 301)
+	require.ErrorIs(err, staking.ErrForbidden, "reclaiming more than escrowed should fail")
+	_, _, err = reclaim(pk2, 0)
+	require.ErrorIs(err, staking.ErrInvalidArgument, "reclaiming nothing should fail")
+
+	txCtx, reclaimResult, err := reclaim(pk2, 100)
+	require.NoError(err, "reclaim escrow from allowance")
+	require.Equal(owner, reclaimResult.Owner)
+	require.Equal(*quantity.NewFromUint64(100), reclaimResult.Amount)
+	require.Equal(*quantity.NewFromUint64(200), reclaimResult.RemainingShares)
+
+	var debEv staking.DebondingStartEscrowEvent
+	require.NoError(txCtx.DecodeEvent(0, &debEv), "DecodeEvent")
+	require.Equal(owner, debEv.Owner, "event should identify the owner")
+	require.Equal(&beneficiary, debEv.Beneficiary, "event should identify the beneficiary")
+
+	deb, err := stakeState.DebondingDelegation(ctx, owner, escrowAddr, reclaimResult.DebondEndTime)
+	require.NoError(err, "DebondingDelegation")
+	require.Equal(*quantity.NewFromUint64(100), deb.Shares, "debonding delegation should be owned by the owner")
+	require.Equal(quantity.NewFromUint64(200), allowanceShares(beneficiary))
+	_, ok = account().General.Allowances[beneficiary]
+	require.False(ok, "allowance should not be restored")
+
+	// When the owner reclaims directly, the beneficiary may only reclaim what is left.
+	ownerCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+	defer ownerCtx.Close()
+	ownerCtx.SetTxSigner(pk1)
+	_, err = app.reclaimEscrow(ownerCtx, stakeState, &staking.ReclaimEscrow{
+		Account: escrowAddr,
+		Shares:  *quantity.NewFromUint64(150),
+	})
+	require.NoError(err, "reclaim escrow")
+
+	_, _, err = reclaim(pk2, 51)
+	require.Error(err, "reclaiming more than the delegation should fail")
+	_, _, err = reclaim(pk2, 50)
+	require.NoError(err, "reclaim escrow from allowance")
+	require.True(allowanceShares(beneficiary).IsZero(), "no shares should be left to reclaim")
+	_, _, err = reclaim(pk2, 1)
+	require.ErrorIs(err, staking.ErrForbidden, "nothing should be left to reclaim")
+}
+
 func TestAddEscrow(t *testing.T) {
 	require := require.New(t)
 	var err error
@@ -1114,3 +1436,114 @@ func TestAmendCommissionSchedule(t *testing.T) {
 	}}))
 	require.NoError(app.amendCommissionSchedule(txCtx, stakeState, amendment), "amending commission schedule for address with enough stake should work")
 }
+
+func randomCommissionSchedule(rng *rand.Rand, rules *staking.CommissionScheduleRules, from beacon.EpochTime) staking.CommissionSchedule {
+	// Start both schedules at the same aligned epoch, but occasionally generate misaligned or
+	// too many steps and rates out of bounds to also exercise the failure paths.
+	first := (from/rules.RateChangeInterval + beacon.EpochTime(rng.Intn(6))) * rules.RateChangeInterval
+	randomStart := func(prev beacon.EpochTime, i int) beacon.EpochTime {
+		start := prev + beacon.EpochTime(1+rng.Intn(3))*rules.RateChangeInterval
+		if i == 0 {
+			start = first
+		}
+		if rng.Intn(20) == 0 {
+			start++
+		}
+		return start
+	}
+
+	var (
+		cs    staking.CommissionSchedule
+		start beacon.EpochTime
+	)
+	for i := 0; i < 1+rng.Intn(int(rules.MaxRateSteps)+1); i++ {
+		start = randomStart(start, i)
+		cs.Rates = append(cs.Rates, staking.CommissionRateStep{
+			Start: start,
+			Rate:  *quantity.NewFromUint64(uint64(10_000 + rng.Intn(90_000))),
+		})
+	}
+	for i := 0; i < 1+rng.Intn(int(rules.MaxBoundSteps)+1); i++ {
+		start = randomStart(start, i)
+		cs.Bounds = append(cs.Bounds, staking.CommissionRateBoundStep{
+			Start:   start,
+			RateMin: *quantity.NewFromUint64(uint64(rng.Intn(20_000))),
+			RateMax: *quantity.NewFromUint64(uint64(80_000 + rng.Intn(30_000))),
+		})
+	}
+	return cs
+}
+
+func TestAmendCommissionScheduleValidateScheduleUpdate(t *testing.T) {
+	require := require.New(t)
+
+	appState := abciAPI.NewMockApplicationState(&abciAPI.MockApplicationStateConfig{})
+	ctx := appState.NewContext(abciAPI.ContextEndBlock)
+	defer ctx.Close()
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	rules := staking.CommissionScheduleRules{
+		RateChangeInterval: 10,
+		RateBoundLead:      30,
+		MaxRateSteps:       4,
+		MaxBoundSteps:      4,
+		MinCommissionRate:  *quantity.NewFromUint64(1_000),
+	}
+	err := stakeState.SetConsensusParameters(ctx, &staking.ConsensusParameters{
+		CommissionScheduleRules: rules,
+	})
+	require.NoError(err, "setting staking consensus parameters should not error")
+
+	app := &Application{
+		state: appState,
+	}
+
+	pk := signature.NewPublicKey("aaafffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	addr := staking.NewAddress(pk)
+
+	// The ABCI application and the helper must agree on all random amendments.
+	rng := rand.New(rand.NewSource(42)) // nolint: gosec
+	var accepted, rejected int
+	for i := 0; i < 2000; i++ {
+		now := beacon.EpochTime(rng.Intn(60))
+		current := randomCommissionSchedule(rng, &rules, 0)
+		amendment := randomCommissionSchedule(rng, &rules, now)
+		if rng.Intn(2) == 0 {
+			// Only amend the rates.
+			amendment.Bounds = nil
+		}
+
+		expected, expectedErr := staking.ValidateScheduleUpdate(&current, &amendment, &rules, now)
+
+		appState.UpdateMockApplicationStateConfig(&abciAPI.MockApplicationStateConfig{
+			CurrentEpoch: now,
+		})
+		require.NoError(stakeState.SetAccount(ctx, addr, &staking.Account{Escrow: staking.EscrowAccount{
+			CommissionSchedule: current,
+		}}))
+
+		txCtx := appState.NewContext(abciAPI.ContextDeliverTx)
+		txCtx.SetTxSigner(pk)
+		err = app.amendCommissionSchedule(txCtx, stakeState, &staking.AmendCommissionSchedule{
+			Amendment: amendment,
+		})
+		txCtx.Close()
+
+		if expectedErr != nil {
+			require.Error(err, "ABCI application should reject the amendment (iteration %d): %s", i, expectedErr)
+			rejected++
+			continue
+		}
+		require.NoError(err, "ABCI application should accept the amendment (iteration %d)", i)
+		accepted++
+
+		acct, err := stakeState.Account(ctx, addr)
+		require.NoError(err, "Account")
+		require.Equal(cbor.Marshal(expected), cbor.Marshal(acct.Escrow.CommissionSchedule), "amended schedules should match (iteration %d)", i)
+	}
+
+	// Make sure that both outcomes have been exercised.
+	require.NotZero(accepted, "some amendments should be accepted")
+	require.NotZero(rejected, "some amendments should be rejected")
+}
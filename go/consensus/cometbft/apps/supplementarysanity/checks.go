@@ -69,7 +69,11 @@ func checkRegistry(ctx *abciAPI.Context, now beacon.EpochTime) error {
 	if err != nil {
 		return fmt.Errorf("SignedNodes: %w", err)
 	}
-	_, err = registry.SanityCheckNodes(logger, params, signedNodes, seenEntities, runtimeLookup, false, now, ctx.Now(), uint64(ctx.BlockHeight()))
+	authorizations, err := st.NodeAuthorizations(ctx)
+	if err != nil {
+		return fmt.Errorf("NodeAuthorizations: %w", err)
+	}
+	_, err = registry.SanityCheckNodes(logger, params, signedNodes, seenEntities, authorizations, runtimeLookup, false, now, ctx.Now(), uint64(ctx.BlockHeight()))
 	if err != nil {
 		return fmt.Errorf("SanityCheckNodes: %w", err)
 	}
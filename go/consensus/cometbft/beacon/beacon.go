@@ -16,6 +16,7 @@ import (
 	beaconAPI "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -47,6 +48,8 @@ type ServiceClient struct {
 	vrfLastNotified hash.Hash
 	vrfEvent        *beaconAPI.VRFEvent
 
+	participationWatchers map[*participationWatcher]struct{}
+
 	initialNotify bool
 
 	baseEpoch beaconAPI.EpochTime
@@ -56,15 +59,16 @@ type ServiceClient struct {
 // New constructs a new CometBFT backed beacon service client.
 func New(baseEpoch beaconAPI.EpochTime, baseBlock int64, consensus consensus.Backend, querier *app.QueryFactory) *ServiceClient {
 	return &ServiceClient{
-		logger:            logging.GetLogger("cometbft/beacon"),
-		consensus:         consensus,
-		querier:           querier,
-		epochNotifier:     pubsub.NewBroker(false),
-		epochLastNotified: beaconAPI.EpochInvalid,
-		epochCache:        lru.New(lru.Capacity(epochCacheCapacity, false)),
-		vrfNotifier:       pubsub.NewBroker(false),
-		baseEpoch:         baseEpoch,
-		baseBlock:         baseBlock,
+		logger:                logging.GetLogger("cometbft/beacon"),
+		consensus:             consensus,
+		querier:               querier,
+		epochNotifier:         pubsub.NewBroker(false),
+		epochLastNotified:     beaconAPI.EpochInvalid,
+		epochCache:            lru.New(lru.Capacity(epochCacheCapacity, false)),
+		vrfNotifier:           pubsub.NewBroker(false),
+		participationWatchers: make(map[*participationWatcher]struct{}),
+		baseEpoch:             baseEpoch,
+		baseBlock:             baseBlock,
 	}
 }
 
@@ -243,11 +247,40 @@ func (sc *ServiceClient) WatchLatestVRFEvent(context.Context) (<-chan *beaconAPI
 	return ch, sub, nil
 }
 
+func (sc *ServiceClient) WatchParticipation(
+	_ context.Context,
+	nodes []signature.PublicKey,
+	warnFraction float64,
+) (<-chan *beaconAPI.VRFParticipationEvent, pubsub.ClosableSubscription, error) {
+	tracker, err := beaconAPI.NewVRFParticipationTracker(nodes, warnFraction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := &participationWatcher{
+		sc:      sc,
+		tracker: tracker,
+		ch:      channels.NewInfiniteChannel(),
+	}
+	ch := make(chan *beaconAPI.VRFParticipationEvent)
+	channels.Unwrap(w.ch, ch)
+
+	sc.Lock()
+	sc.participationWatchers[w] = struct{}{}
+	sc.Unlock()
+
+	return ch, w, nil
+}
+
 func (sc *ServiceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor("beacon", app.EventType, []cmtpubsub.Query{app.QueryApp})
 }
 
 func (sc *ServiceClient) DeliverHeight(ctx context.Context, height int64) error {
+	if err := sc.updateParticipation(ctx, height); err != nil {
+		return err
+	}
+
 	if sc.initialNotify {
 		return nil
 	}
@@ -358,6 +391,56 @@ func (sc *ServiceClient) updateCachedVRFEvent(event *beaconAPI.VRFEvent) bool {
 	return false
 }
 
+func (sc *ServiceClient) updateParticipation(ctx context.Context, height int64) error {
+	sc.RLock()
+	numWatchers := len(sc.participationWatchers)
+	sc.RUnlock()
+	if numWatchers == 0 {
+		return nil
+	}
+
+	q, err := sc.querier.QueryAt(ctx, height)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to query state: %w", err)
+	}
+	vrfState, err := q.VRFState(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to query VRF state: %w", err)
+	}
+	if vrfState == nil {
+		return nil
+	}
+	// The proof submission window closes at the next epoch transition, which is
+	// not known in advance when the mock backend is in use.
+	future, err := q.FutureEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("beacon: failed to query future epoch: %w", err)
+	}
+	var windowEnd int64
+	if future != nil {
+		windowEnd = future.Height
+	}
+
+	sc.Lock()
+	defer sc.Unlock()
+
+	for w := range sc.participationWatchers {
+		for _, ev := range w.tracker.Update(vrfState, height, windowEnd) {
+			if ev.Warning {
+				sc.logger.Warn("node has not submitted VRF proof",
+					"epoch", ev.Epoch,
+					"node_id", ev.NodeID,
+					"height", ev.Height,
+					"submit_after", vrfState.SubmitAfter,
+					"window_end", windowEnd,
+				)
+			}
+			w.ch.In() <- ev
+		}
+	}
+	return nil
+}
+
 func (sc *ServiceClient) currentEpochBlock() (beaconAPI.EpochTime, int64) {
 	sc.RLock()
 	defer sc.RUnlock()
@@ -386,3 +469,21 @@ func (sc *ServiceClient) vrfNotifierHook() pubsub.OnSubscribeHook {
 		}
 	}
 }
+
+type participationWatcher struct {
+	sc      *ServiceClient
+	tracker *beaconAPI.VRFParticipationTracker
+	ch      channels.Channel
+}
+
+// Close implements pubsub.ClosableSubscription.
+func (w *participationWatcher) Close() {
+	w.sc.Lock()
+	defer w.sc.Unlock()
+
+	if _, ok := w.sc.participationWatchers[w]; !ok {
+		return
+	}
+	delete(w.sc.participationWatchers, w)
+	w.ch.Close()
+}
@@ -2,11 +2,14 @@ package common
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/url"
 	"path/filepath"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/config"
 )
 
@@ -29,6 +32,43 @@ func GetExternalAddress() (*url.URL, error) {
 		return nil, fmt.Errorf("cometbft: no external address configured")
 	}
 
+	return parseExternalAddress(addrURI)
+}
+
+// GetConsensusAddresses returns the configured CometBFT external addresses with the given ID,
+// prioritized in the order in which they are configured.
+func GetConsensusAddresses(id signature.PublicKey) ([]node.ConsensusAddress, error) {
+	u, err := GetExternalAddress()
+	if err != nil {
+		return nil, err
+	}
+	urls := []*url.URL{u}
+	for _, addrURI := range config.GlobalConfig.Consensus.AdditionalExternalAddresses {
+		if u, err = parseExternalAddress(addrURI); err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) > math.MaxUint8+1 {
+		return nil, fmt.Errorf("cometbft: too many external addresses configured")
+	}
+
+	addrs := make([]node.ConsensusAddress, 0, len(urls))
+	for i, u := range urls {
+		addr := node.ConsensusAddress{
+			ID:       id,
+			Priority: uint8(i),
+		}
+		if err = addr.Address.UnmarshalText([]byte(u.Host)); err != nil {
+			return nil, fmt.Errorf("cometbft: failed to parse external address host: %w", err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+func parseExternalAddress(addrURI string) (*url.URL, error) {
 	u, err := url.Parse(addrURI)
 	if err != nil {
 		return nil, fmt.Errorf("cometbft: failed to parse external address URL: %w", err)
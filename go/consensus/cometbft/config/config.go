@@ -17,6 +17,9 @@ type Config struct {
 	ListenAddress string `yaml:"listen_address"`
 	// CometBFT address advertised to other nodes.
 	ExternalAddress string `yaml:"external_address,omitempty"`
+	// Additional CometBFT addresses advertised to other nodes, in decreasing order of priority.
+	// These are only used when the addresses preceding them are unreachable.
+	AdditionalExternalAddresses []string `yaml:"additional_external_addresses,omitempty"`
 
 	// CometBFT P2P configuration.
 	P2P P2PConfig `yaml:"p2p,omitempty"`
@@ -88,6 +91,11 @@ type SubmissionConfig struct {
 	GasPrice uint64 `yaml:"gas_price"`
 	// Max transaction fee when submitting consensus transactions.
 	MaxFee uint64 `yaml:"max_fee"`
+	// Max gas price when submitting consensus transactions (zero means no limit).
+	MaxGasPrice uint64 `yaml:"max_gas_price"`
+	// Percentile of gas prices paid in recent blocks used when submitting consensus
+	// transactions.
+	GasPricePercentile uint8 `yaml:"gas_price_percentile"`
 }
 
 const (
@@ -209,6 +217,10 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Submission.GasPricePercentile > 100 {
+		return fmt.Errorf("submission.gas_price_percentile must be <= 100")
+	}
+
 	if c.SupplementarySanity.Enabled && c.SupplementarySanity.Interval < 1 {
 		return fmt.Errorf("supplementary_sanity.interval must be >= 1")
 	}
@@ -234,8 +246,10 @@ func DefaultConfig() Config {
 		SentryUpstreamAddresses: []string{},
 		MinGasPrice:             0,
 		Submission: SubmissionConfig{
-			GasPrice: 0,
-			MaxFee:   10_000_000_000,
+			GasPrice:           0,
+			MaxFee:             10_000_000_000,
+			MaxGasPrice:        0,
+			GasPricePercentile: 50,
 		},
 		HaltEpoch:        0,
 		HaltHeight:       0,
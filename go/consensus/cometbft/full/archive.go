@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
@@ -142,6 +143,11 @@ func (srv *archiveService) EstimateGas(context.Context, *consensusAPI.EstimateGa
 	return 0, consensusAPI.ErrUnsupported
 }
 
+// Implements consensusAPI.Backend.
+func (srv *archiveService) GetSuggestedGasPrice(context.Context, uint8) (*quantity.Quantity, error) {
+	return nil, consensusAPI.ErrUnsupported
+}
+
 // Implements consensusAPI.Backend.
 func (srv *archiveService) GetSignerNonce(context.Context, *consensusAPI.GetSignerNonceRequest) (uint64, error) {
 	return 0, consensusAPI.ErrUnsupported
@@ -0,0 +1,73 @@
+package full
+
+import (
+	"sync"
+	"time"
+)
+
+// blockIntervalWindow is the number of most recent block intervals used to estimate the block
+// interval.
+const blockIntervalWindow = 100
+
+// blockIntervalEstimator keeps a moving average of recent block intervals.
+type blockIntervalEstimator struct {
+	sync.Mutex
+
+	lastHeight int64
+	lastTime   time.Time
+
+	intervals []time.Duration
+	next      int
+	sum       time.Duration
+}
+
+func newBlockIntervalEstimator() *blockIntervalEstimator {
+	return &blockIntervalEstimator{
+		intervals: make([]time.Duration, 0, blockIntervalWindow),
+	}
+}
+
+// observe records the timestamp of the block at the given height.
+//
+// Only intervals between consecutive blocks are taken into account.
+func (e *blockIntervalEstimator) observe(height int64, timestamp time.Time) {
+	e.Lock()
+	defer e.Unlock()
+
+	defer func() {
+		e.lastHeight = height
+		e.lastTime = timestamp
+	}()
+
+	if e.lastHeight == 0 || height != e.lastHeight+1 || timestamp.Before(e.lastTime) {
+		return
+	}
+	interval := timestamp.Sub(e.lastTime)
+
+	if len(e.intervals) < cap(e.intervals) {
+		e.intervals = append(e.intervals, interval)
+		e.sum += interval
+		return
+	}
+	e.sum += interval - e.intervals[e.next]
+	e.intervals[e.next] = interval
+	e.next = (e.next + 1) % len(e.intervals)
+}
+
+// average returns the average of recent block intervals and true, or false in case no intervals
+// have been observed yet.
+func (e *blockIntervalEstimator) average() (time.Duration, bool) {
+	e.Lock()
+	defer e.Unlock()
+
+	if len(e.intervals) == 0 {
+		return 0, false
+	}
+	return e.sum / time.Duration(len(e.intervals)), true
+}
+
+// estimateTime estimates the time at which the given height will be (or was) reached based on
+// the given reference block and the average block interval.
+func estimateTime(height int64, refHeight int64, refTime time.Time, interval time.Duration) time.Time {
+	return refTime.Add(time.Duration(height-refHeight) * interval)
+}
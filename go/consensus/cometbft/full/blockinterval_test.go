@@ -0,0 +1,44 @@
+package full
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockIntervalEstimator(t *testing.T) {
+	require := require.New(t)
+
+	e := newBlockIntervalEstimator()
+	_, ok := e.average()
+	require.False(ok, "no estimate without observed blocks")
+
+	now := time.Now()
+	e.observe(10, now)
+	_, ok = e.average()
+	require.False(ok, "no estimate from a single block")
+
+	e.observe(11, now.Add(6*time.Second))
+	e.observe(12, now.Add(10*time.Second))
+	avg, ok := e.average()
+	require.True(ok)
+	require.Equal(5*time.Second, avg)
+
+	// Non-consecutive blocks are ignored.
+	e.observe(20, now.Add(time.Hour))
+	avg, _ = e.average()
+	require.Equal(5*time.Second, avg)
+
+	// Older samples are evicted once the window is full.
+	ts := now.Add(time.Hour)
+	for h := int64(21); h <= 20+blockIntervalWindow; h++ {
+		ts = ts.Add(2 * time.Second)
+		e.observe(h, ts)
+	}
+	avg, _ = e.average()
+	require.Equal(2*time.Second, avg)
+
+	// Estimates are based on the reference block.
+	require.Equal(ts.Add(20*time.Second), estimateTime(130, 120, ts, avg))
+}
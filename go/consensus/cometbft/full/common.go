@@ -258,7 +258,7 @@ func (n *commonNode) initialize() error {
 	}
 
 	// Register CometBFT applications.
-	beaconApp := beaconApp.New()
+	beaconApp := beaconApp.New(state, md)
 	governanceApp := governanceApp.New(state, md)
 	keymanagerApp := keymanagerApp.New(state)
 	registryApp := registryApp.New(state, md)
@@ -327,18 +327,7 @@ func (n *commonNode) Cleanup() {
 
 // Implements consensusAPI.Backend.
 func (n *commonNode) GetAddresses() ([]node.ConsensusAddress, error) {
-	u, err := common.GetExternalAddress()
-	if err != nil {
-		return nil, err
-	}
-
-	var addr node.ConsensusAddress
-	if err = addr.Address.UnmarshalText([]byte(u.Host)); err != nil {
-		return nil, fmt.Errorf("cometbft: failed to parse external address host: %w", err)
-	}
-	addr.ID = n.identity.P2PSigner.Public()
-
-	return []node.ConsensusAddress{addr}, nil
+	return common.GetConsensusAddresses(n.identity.P2PSigner.Public())
 }
 
 // Implements consensusAPI.Backend.
@@ -408,7 +397,7 @@ func (n *commonNode) StateToGenesis(ctx context.Context, height int64) (*genesis
 		return nil, err
 	}
 
-	return &genesisAPI.Document{
+	doc := &genesisAPI.Document{
 		Height:     height,
 		ChainID:    n.chainID,
 		Time:       blk.Header.Time,
@@ -424,7 +413,35 @@ func (n *commonNode) StateToGenesis(ctx context.Context, height int64) (*genesis
 			Backend:    api.BackendName,
 			Parameters: *cp,
 		},
-	}, nil
+	}
+	doc.Canonicalize()
+
+	return doc, nil
+}
+
+// Implements consensusAPI.Backend.
+func (n *commonNode) StateToGenesisFiltered(ctx context.Context, req *consensusAPI.StateToGenesisRequest) (*genesisAPI.Document, error) {
+	if req.Filter != nil {
+		if err := req.Filter.ValidateBasic(); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := n.StateToGenesis(ctx, req.Height)
+	if err != nil {
+		return nil, err
+	}
+	if req.Filter.IsEmpty() {
+		return doc, nil
+	}
+
+	if err = req.Filter.Apply(doc); err != nil {
+		return nil, err
+	}
+	if err = doc.SanityCheck(); err != nil {
+		return nil, fmt.Errorf("cometbft: filtered genesis document is invalid: %w", err)
+	}
+	return doc, nil
 }
 
 // Implements consensusAPI.Backend.
@@ -826,6 +843,76 @@ func (n *commonNode) GetParameters(ctx context.Context, height int64) (*consensu
 	}, nil
 }
 
+// Implements consensusAPI.Backend.
+func (n *commonNode) GetSigningContextBundle(ctx context.Context, height int64) (*consensusAPI.SigningContextBundle, error) {
+	if err := n.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	// Ensure all queries use the same height if the specified height is the latest.
+	tmHeight, err := n.heightToCometBFTHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := n.GetBlock(ctx, tmHeight)
+	if err != nil {
+		return nil, err
+	}
+	params, err := n.GetParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := consensusAPI.SigningContextBundle{
+		Versioned:    cbor.NewVersioned(consensusAPI.LatestSigningContextBundleVersion),
+		Height:       blk.Height,
+		Hash:         blk.Hash,
+		Time:         blk.Time,
+		ChainContext: n.chainContext,
+		Consensus:    params.Parameters,
+	}
+	if bundle.TokenSymbol, err = n.staking.TokenSymbol(ctx, tmHeight); err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch token symbol: %w", err)
+	}
+	if bundle.TokenValueExponent, err = n.staking.TokenValueExponent(ctx, tmHeight); err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch token value exponent: %w", err)
+	}
+
+	stakingParams, err := n.staking.ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch staking consensus parameters: %w", err)
+	}
+	bundle.Staking = *stakingParams
+	registryParams, err := n.registry.ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch registry consensus parameters: %w", err)
+	}
+	bundle.Registry = *registryParams
+	roothashParams, err := n.roothash.ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch roothash consensus parameters: %w", err)
+	}
+	bundle.RootHash = *roothashParams
+	governanceParams, err := n.governance.ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch governance consensus parameters: %w", err)
+	}
+	bundle.Governance = *governanceParams
+	churpParams, err := n.keymanager.Churp().ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch key manager CHURP consensus parameters: %w", err)
+	}
+	bundle.KeyManagerChurp = *churpParams
+	vaultParams, err := n.vault.ConsensusParameters(ctx, tmHeight)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch vault consensus parameters: %w", err)
+	}
+	bundle.Vault = *vaultParams
+
+	return &bundle, nil
+}
+
 // Implements consensusAPI.Backend.
 func (n *commonNode) GetStatus(ctx context.Context) (*consensusAPI.Status, error) {
 	status := &consensusAPI.Status{
@@ -917,6 +1004,15 @@ func (n *commonNode) SubmitEvidence(context.Context, *consensusAPI.Evidence) err
 	return consensusAPI.ErrUnsupported
 }
 
+// Implements consensusAPI.Backend.
+func (n *commonNode) GetEvidence(ctx context.Context, request *consensusAPI.GetEvidenceRequest) ([]*consensusAPI.EvidenceRecord, error) {
+	if err := n.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	return n.staking.EvidenceRecords(ctx, request)
+}
+
 // Implements consensusAPI.Backend.
 func (n *commonNode) SubmitTx(context.Context, *transaction.SignedTransaction) error {
 	return consensusAPI.ErrUnsupported
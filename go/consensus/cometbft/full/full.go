@@ -15,6 +15,7 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 	cmtabcitypes "github.com/cometbft/cometbft/abci/types"
 	cmtconfig "github.com/cometbft/cometbft/config"
+	cmtconsensus "github.com/cometbft/cometbft/consensus"
 	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	cmtlight "github.com/cometbft/cometbft/light"
 	cmtmempool "github.com/cometbft/cometbft/mempool"
@@ -34,8 +35,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	"github.com/oasisprotocol/oasis-core/go/common/random"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -108,6 +111,8 @@ type fullService struct { // nolint: maligned
 	blockNotifier *pubsub.Broker
 	failMonitor   *failMonitor
 
+	blockIntervals *blockIntervalEstimator
+
 	submissionMgr consensusAPI.SubmissionManager
 
 	timeoutCommit      time.Duration
@@ -397,6 +402,11 @@ func (t *fullService) SubmissionManager() consensusAPI.SubmissionManager {
 	return t.submissionMgr
 }
 
+// Implements consensusAPI.Backend.
+func (t *fullService) GetSuggestedGasPrice(_ context.Context, percentile uint8) (*quantity.Quantity, error) {
+	return t.submissionMgr.PriceDiscovery().SuggestedGasPrice(percentile)
+}
+
 // Implements consensusAPI.Backend.
 func (t *fullService) GetUnconfirmedTransactions(context.Context) ([][]byte, error) {
 	mempoolTxs := t.node.Mempool().ReapMaxTxs(-1)
@@ -442,11 +452,90 @@ func (t *fullService) GetStatus(ctx context.Context) (*consensusAPI.Status, erro
 
 		status.P2P.Peers = peers
 		status.P2P.PeerID = string(t.node.NodeInfo().ID())
+
+		status.Sync = t.syncStatus(tmpeers, status.LatestHeight)
+		status.NextEpochTime = t.estimateNextEpochTime(ctx, status)
 	}
 
 	return status, nil
 }
 
+// syncStatus returns the synchronization status of the node based on the heights reported by
+// the given peers or nil in case no peer has reported its height yet.
+func (t *fullService) syncStatus(peers []cmtp2p.Peer, latestHeight int64) *consensusAPI.SyncStatus {
+	var networkHeight int64
+	for _, peer := range peers {
+		ps, ok := peer.Get(cmttypes.PeerStateKey).(*cmtconsensus.PeerState)
+		if !ok {
+			continue
+		}
+		// Peers report the height they are working on, which is one above their latest block.
+		networkHeight = max(networkHeight, ps.GetHeight()-1)
+	}
+	if networkHeight <= 0 {
+		return nil
+	}
+
+	status := &consensusAPI.SyncStatus{
+		NetworkHeight: max(networkHeight, latestHeight),
+		LagBlocks:     max(networkHeight-latestHeight, 0),
+	}
+	if interval, ok := t.blockIntervals.average(); ok {
+		status.Lag = time.Duration(status.LagBlocks) * interval
+	}
+	return status
+}
+
+// estimateNextEpochTime estimates the time of the next epoch transition based on the given status
+// or returns nil in case there is not enough data for an estimate.
+func (t *fullService) estimateNextEpochTime(ctx context.Context, status *consensusAPI.Status) *time.Time {
+	if status.LatestHeight == 0 {
+		return nil
+	}
+	interval, ok := t.blockIntervals.average()
+	if !ok {
+		return nil
+	}
+
+	height, err := t.nextEpochHeight(ctx, status.LatestHeight, status.LatestEpoch)
+	if err != nil {
+		t.Logger.Debug("failed to determine next epoch transition height",
+			"err", err,
+		)
+		return nil
+	}
+	// The transition may be overdue, in which case it is expected in the next block.
+	height = max(height, status.LatestHeight+1)
+
+	estimate := estimateTime(height, status.LatestHeight, status.LatestTime, interval)
+	return &estimate
+}
+
+// nextEpochHeight returns the height of the next epoch transition following the given epoch.
+func (t *fullService) nextEpochHeight(ctx context.Context, height int64, epoch beaconAPI.EpochTime) (int64, error) {
+	// Prefer epoch transitions that are already scheduled.
+	future, err := t.beacon.GetFutureEpoch(ctx, height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query future epoch: %w", err)
+	}
+	if future != nil {
+		return future.Height, nil
+	}
+
+	params, err := t.beacon.ConsensusParameters(ctx, height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query beacon consensus parameters: %w", err)
+	}
+	if params.DebugMockBackend {
+		return 0, fmt.Errorf("epoch transitions are not periodic")
+	}
+	epochHeight, err := t.beacon.GetEpochBlock(ctx, epoch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query epoch block: %w", err)
+	}
+	return epochHeight + params.IntervalAt(epoch), nil
+}
+
 // Implements consensusAPI.Backend.
 func (t *fullService) GetNextBlockState(ctx context.Context) (*consensusAPI.NextBlockState, error) {
 	if !t.started() {
@@ -574,6 +663,9 @@ func (t *fullService) lazyInit() error { // nolint: gocyclo
 	if err != nil {
 		return err
 	}
+	if t.upgrader != nil {
+		t.upgrader.SetConsensusSimulator(t.mux)
+	}
 
 	// CometBFT needs the on-disk directories to be present when
 	// launched like this, so create the relevant sub-directories
@@ -797,6 +889,14 @@ func (t *fullService) syncWorker() {
 		return t.node.ConsensusReactor().WaitSync(), nil
 	}
 
+	// The height is best-effort as it only serves as a reference point.
+	height, _ := t.GetLatestHeight(t.ctx)
+	journal.Record(&journal.Event{
+		Kind:    journal.KindConsensusSync,
+		Height:  height,
+		Message: "consensus initial sync started",
+	})
+
 	for {
 		select {
 		case <-t.node.Quit():
@@ -827,6 +927,11 @@ func (t *fullService) syncWorker() {
 				// Latest block within threshold.
 				if now.Sub(tmBlock.Header.Time) < syncWorkerLastBlockTimeDiffThreshold {
 					t.Logger.Info("CometBFT Node finished initial sync")
+					journal.Record(&journal.Event{
+						Kind:    journal.KindConsensusSync,
+						Height:  tmBlock.Height,
+						Message: "consensus initial sync finished",
+					})
 					close(t.syncedCh)
 					return
 				}
@@ -846,6 +951,7 @@ func (t *fullService) blockNotifierWorker() {
 	handle := func(_ context.Context, msg cmtpubsub.Message) {
 		switch ev := msg.Data().(type) {
 		case cmttypes.EventDataNewBlock:
+			t.blockIntervals.observe(ev.Block.Height, ev.Block.Time)
 			t.blockNotifier.Broadcast(ev.Block)
 		default:
 		}
@@ -982,6 +1088,7 @@ func New(ctx context.Context, cfg Config) (consensusAPI.Service, error) {
 		commonNode:         commonNode,
 		upgrader:           cfg.Upgrader,
 		blockNotifier:      pubsub.NewBroker(false),
+		blockIntervals:     newBlockIntervalEstimator(),
 		timeoutCommit:      cfg.TimeoutCommit,
 		skipTimeoutCommit:  cfg.SkipTimeoutCommit,
 		emptyBlockInterval: cfg.EmptyBlockInterval,
@@ -994,7 +1101,11 @@ func New(ctx context.Context, cfg Config) (consensusAPI.Service, error) {
 	t.Logger.Info("starting a full consensus node")
 
 	// Create price discovery mechanism and the submission manager.
-	pd, err := pricediscovery.New(ctx, t, config.GlobalConfig.Consensus.Submission.GasPrice)
+	pd, err := pricediscovery.New(ctx, t, pricediscovery.Config{
+		FallbackGasPrice: config.GlobalConfig.Consensus.Submission.GasPrice,
+		MaxGasPrice:      config.GlobalConfig.Consensus.Submission.MaxGasPrice,
+		Percentile:       config.GlobalConfig.Consensus.Submission.GasPricePercentile,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create price discovery: %w", err)
 	}
@@ -0,0 +1,183 @@
+package full
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	beaconAPI "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	governanceAPI "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	schedulerAPI "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	stakingAPI "github.com/oasisprotocol/oasis-core/go/staking/api"
+	upgradeAPI "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	vaultAPI "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// moduleParameters are the consensus parameters of a single module.
+type moduleParameters struct {
+	module string
+	params any
+}
+
+// Implements consensusAPI.Backend.
+func (n *commonNode) GetParametersDiff(ctx context.Context, request *consensusAPI.GetParametersDiffRequest) (*consensusAPI.ParametersDiff, error) {
+	if err := n.ensureStarted(ctx); err != nil {
+		return nil, err
+	}
+
+	fromHeight, err := n.heightToCometBFTHeight(request.FromHeight)
+	if err != nil {
+		return nil, err
+	}
+	toHeight, err := n.heightToCometBFTHeight(request.ToHeight)
+	if err != nil {
+		return nil, err
+	}
+	if fromHeight > toHeight {
+		return nil, fmt.Errorf("cometbft: from height (%d) is after to height (%d)", fromHeight, toHeight)
+	}
+
+	fromParams, err := n.loadModuleParameters(ctx, fromHeight)
+	if err != nil {
+		return nil, err
+	}
+	toParams, err := n.loadModuleParameters(ctx, toHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := consensusAPI.ParametersDiff{
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	}
+	for i := range fromParams {
+		changes, err := consensusAPI.DiffParameters(fromParams[i].module, fromParams[i].params, toParams[i].params)
+		if err != nil {
+			return nil, err
+		}
+		diff.Changes = append(diff.Changes, changes...)
+	}
+	if len(diff.Changes) == 0 {
+		return &diff, nil
+	}
+
+	if err = n.attributeParameterChanges(ctx, fromHeight, toHeight, diff.Changes); err != nil {
+		return nil, err
+	}
+
+	return &diff, nil
+}
+
+// loadModuleParameters loads the consensus parameters of all modules at the given height.
+//
+// The modules are always returned in the same order.
+func (n *commonNode) loadModuleParameters(ctx context.Context, height int64) ([]moduleParameters, error) {
+	params, err := n.GetParameters(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	beaconParams, err := n.beacon.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch beacon consensus parameters: %w", err)
+	}
+	registryParams, err := n.registry.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch registry consensus parameters: %w", err)
+	}
+	roothashParams, err := n.roothash.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch roothash consensus parameters: %w", err)
+	}
+	schedulerParams, err := n.scheduler.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch scheduler consensus parameters: %w", err)
+	}
+	stakingParams, err := n.staking.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch staking consensus parameters: %w", err)
+	}
+	governanceParams, err := n.governance.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch governance consensus parameters: %w", err)
+	}
+	churpParams, err := n.keymanager.Churp().ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch key manager CHURP consensus parameters: %w", err)
+	}
+	vaultParams, err := n.vault.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("cometbft: failed to fetch vault consensus parameters: %w", err)
+	}
+
+	return []moduleParameters{
+		{consensusAPI.ModuleName, &params.Parameters},
+		{beaconAPI.ModuleName, beaconParams},
+		{registryAPI.ModuleName, registryParams},
+		{roothashAPI.ModuleName, roothashParams},
+		{schedulerAPI.ModuleName, schedulerParams},
+		{stakingAPI.ModuleName, stakingParams},
+		{governanceAPI.ModuleName, governanceParams},
+		{churp.ModuleName, churpParams},
+		{vaultAPI.ModuleName, vaultParams},
+	}, nil
+}
+
+// attributeParameterChanges attributes the given parameter changes to the governance proposals
+// or upgrades that took effect between the given heights, where this can be determined
+// unambiguously.
+func (n *commonNode) attributeParameterChanges(ctx context.Context, fromHeight, toHeight int64, changes []*consensusAPI.ParameterChange) error {
+	fromEpoch, err := n.beacon.GetEpoch(ctx, fromHeight)
+	if err != nil {
+		return fmt.Errorf("cometbft: failed to fetch epoch: %w", err)
+	}
+	toEpoch, err := n.beacon.GetEpoch(ctx, toHeight)
+	if err != nil {
+		return fmt.Errorf("cometbft: failed to fetch epoch: %w", err)
+	}
+	proposals, err := n.governance.Proposals(ctx, toHeight)
+	if err != nil {
+		return fmt.Errorf("cometbft: failed to fetch governance proposals: %w", err)
+	}
+
+	inRange := func(epoch beaconAPI.EpochTime) bool {
+		return epoch > fromEpoch && epoch <= toEpoch
+	}
+
+	paramProposals := make(map[string][]uint64)
+	var upgrades []upgradeAPI.HandlerName
+	for _, p := range proposals {
+		if p.State != governanceAPI.StatePassed {
+			continue
+		}
+		switch {
+		case p.Content.ChangeParameters != nil:
+			if inRange(p.ClosesAt) {
+				module := p.Content.ChangeParameters.Module
+				paramProposals[module] = append(paramProposals[module], p.ID)
+			}
+		case p.Content.Upgrade != nil:
+			if inRange(p.Content.Upgrade.Epoch) {
+				upgrades = append(upgrades, p.Content.Upgrade.Handler)
+			}
+		}
+	}
+
+	for _, c := range changes {
+		// Parameters of module extensions (e.g. keymanager/churp) are changed via proposals
+		// targeting the parent module.
+		module, _, _ := strings.Cut(c.Module, "/")
+		switch ids := paramProposals[module]; {
+		case len(ids) == 1:
+			id := ids[0]
+			c.ProposalID = &id
+		case len(ids) == 0 && len(upgrades) == 1:
+			c.UpgradeHandler = upgrades[0]
+		}
+	}
+
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	cmtabcitypes "github.com/cometbft/cometbft/abci/types"
 	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	cmttypes "github.com/cometbft/cometbft/types"
 
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 )
 
@@ -35,12 +37,19 @@ func (t *fullService) serviceClientWorker(ctx context.Context, svc api.ServiceCl
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
+	hb := heartbeat.Register("consensus/services/" + svd.Name())
+	defer hb.Stop()
+	hbTicker := time.NewTicker(heartbeat.Interval)
+	defer hbTicker.Stop()
+
 	// Service client event loop.
 	evCh := make(chan annotatedEvent, 1)
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-hbTicker.C:
+			hb.Beat()
 		case query := <-svd.Queries():
 			subscriber := newEventSubscriber(query, t.node.EventBus())
 			handler := newEventHandler(query, svd.EventType(), evCh)
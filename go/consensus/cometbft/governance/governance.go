@@ -11,6 +11,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	tmapi "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	app "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance"
@@ -76,6 +77,15 @@ func (sc *ServiceClient) Votes(ctx context.Context, query *api.ProposalQuery) ([
 	return q.Votes(ctx, query.ProposalID)
 }
 
+func (sc *ServiceClient) GetVoteWeight(ctx context.Context, query *api.VoteWeightQuery) (*quantity.Quantity, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.VoteWeight(ctx, query.ProposalID, query.Voter)
+}
+
 func (sc *ServiceClient) PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -142,6 +152,8 @@ func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 	}
 	events = append(events, blockEvs...)
 
+	sc.setVoteWeights(ctx, results.Height, events)
+
 	return events, nil
 }
 
@@ -162,17 +174,43 @@ func (sc *ServiceClient) ConsensusParameters(ctx context.Context, height int64)
 	return q.ConsensusParameters(ctx)
 }
 
+// setVoteWeights populates the weights of vote events based on the state at the given height.
+func (sc *ServiceClient) setVoteWeights(ctx context.Context, height int64, events []*api.Event) {
+	for _, ev := range events {
+		if ev.Vote == nil {
+			continue
+		}
+
+		weight, err := sc.GetVoteWeight(ctx, &api.VoteWeightQuery{
+			Height:     height,
+			ProposalID: ev.Vote.ID,
+			Voter:      ev.Vote.Submitter,
+		})
+		if err != nil {
+			sc.logger.Warn("failed to compute vote weight",
+				"err", err,
+				"height", height,
+				"proposal_id", ev.Vote.ID,
+				"voter", ev.Vote.Submitter,
+			)
+			continue
+		}
+		ev.Vote.Weight = weight
+	}
+}
+
 // ServiceDescriptor implements api.ServiceClient.
 func (sc *ServiceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []cmtpubsub.Query{app.QueryApp})
 }
 
 // DeliverEvent implements api.ServiceClient.
-func (sc *ServiceClient) DeliverEvent(_ context.Context, height int64, tx cmttypes.Tx, ev *cmtabcitypes.Event) error {
+func (sc *ServiceClient) DeliverEvent(ctx context.Context, height int64, tx cmttypes.Tx, ev *cmtabcitypes.Event) error {
 	events, err := EventsFromCometBFT(tx, height, []cmtabcitypes.Event{*ev})
 	if err != nil {
 		return fmt.Errorf("governance: failed to process cometbft events: %w", err)
 	}
+	sc.setVoteWeights(ctx, height, events)
 
 	// Notify subscribers of events.
 	for _, ev := range events {
@@ -88,6 +88,14 @@ func EventsFromCometBFT(
 					continue
 				}
 				events = append(events, &api.Event{Height: height, TxHash: txHash, NodeUnfrozenEvent: &e})
+			case eventsAPI.IsAttributeKind(key, &api.NodeAuthorizationEvent{}):
+				// Node authorization event.
+				var e api.NodeAuthorizationEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("registry: corrupt NodeAuthorization event: %w", err))
+					continue
+				}
+				events = append(events, &api.Event{Height: height, TxHash: txHash, NodeAuthorizationEvent: &e})
 			}
 		}
 	}
@@ -99,6 +99,15 @@ func (sc *ServiceClient) GetNodeStatus(ctx context.Context, query *api.IDQuery)
 	return q.NodeStatus(ctx, query.ID)
 }
 
+func (sc *ServiceClient) GetNodeAuthorizations(ctx context.Context, query *api.IDQuery) ([]*api.NodeAuthorization, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodeAuthorizations(ctx, query.ID)
+}
+
 func (sc *ServiceClient) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -237,6 +246,14 @@ func (sc *ServiceClient) ConsensusParameters(ctx context.Context, height int64)
 	return q.ConsensusParameters(ctx)
 }
 
+func (sc *ServiceClient) ValidateRuntimeUpdate(ctx context.Context, rt *api.Runtime) error {
+	q, err := sc.querier.QueryAt(ctx, consensus.HeightLatest)
+	if err != nil {
+		return err
+	}
+	return q.ValidateRuntimeUpdate(ctx, rt)
+}
+
 // ServiceDescriptor implements api.ServiceClient.
 func (sc *ServiceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []cmtpubsub.Query{app.QueryApp})
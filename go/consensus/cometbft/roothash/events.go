@@ -73,6 +73,15 @@ EventLoop:
 				}
 
 				ev = &roothash.Event{ExecutorCommitted: &e}
+			case eventsAPI.IsAttributeKind(key, &roothash.LateExecutorCommittedEvent{}):
+				// A late executor commit has been accepted.
+				var e roothash.LateExecutorCommittedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("roothash: corrupt LateExecutorCommitted event: %w", err))
+					continue EventLoop
+				}
+
+				ev = &roothash.Event{LateExecutorCommitted: &e}
 			case eventsAPI.IsAttributeKind(key, &roothash.InMsgProcessedEvent{}):
 				// Incoming message processed event.
 				var e roothash.InMsgProcessedEvent
@@ -82,6 +91,24 @@ EventLoop:
 				}
 
 				ev = &roothash.Event{InMsgProcessed: &e}
+			case eventsAPI.IsAttributeKind(key, &roothash.RuntimeSuspendedEvent{}):
+				// Runtime suspended event.
+				var e roothash.RuntimeSuspendedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("roothash: corrupt RuntimeSuspended event: %w", err))
+					continue EventLoop
+				}
+
+				ev = &roothash.Event{RuntimeSuspended: &e}
+			case eventsAPI.IsAttributeKind(key, &roothash.RuntimeResumedEvent{}):
+				// Runtime resumed event.
+				var e roothash.RuntimeResumedEvent
+				if err := eventsAPI.DecodeValue(val, &e); err != nil {
+					errs = errors.Join(errs, fmt.Errorf("roothash: corrupt RuntimeResumed event: %w", err))
+					continue EventLoop
+				}
+
+				ev = &roothash.Event{RuntimeResumed: &e}
 			case eventsAPI.IsAttributeKind(key, &roothash.RuntimeIDAttribute{}):
 				if runtimeID != nil {
 					errs = errors.Join(errs, fmt.Errorf("roothash: duplicate runtime ID attribute"))
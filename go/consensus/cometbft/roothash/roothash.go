@@ -358,9 +358,14 @@ func (sc *ServiceClient) DeliverHeight(ctx context.Context, height int64) error
 			)
 			return fmt.Errorf("roothash: failed to get runtime state: %w", err)
 		}
-		blk := &api.AnnotatedBlock{
-			Height: rs.LastBlockHeight,
-			Block:  rs.LastBlock,
+		blk, err := sc.annotateBlock(ctx, tr.runtimeID, rs.LastBlockHeight, rs.LastBlock)
+		if err != nil {
+			sc.logger.Warn("failed to annotate latest block",
+				"err", err,
+				"runtime_id", tr.runtimeID,
+				"height", rs.LastBlockHeight,
+			)
+			return fmt.Errorf("roothash: failed to annotate latest block: %w", err)
 		}
 		if err := sc.emitBlock(tr, blk); err != nil {
 			sc.logger.Warn("failed to emit latest block",
@@ -491,10 +496,30 @@ func (sc *ServiceClient) fetchBlock(ctx context.Context, runtimeID common.Namesp
 	if err != nil {
 		return nil, err
 	}
-	return &api.AnnotatedBlock{
+	return sc.annotateBlock(ctx, runtimeID, height, blk)
+}
+
+// annotateBlock annotates a block finalized at the given height with the results of its round.
+func (sc *ServiceClient) annotateBlock(ctx context.Context, runtimeID common.Namespace, height int64, blk *block.Block) (*api.AnnotatedBlock, error) {
+	annBlk := &api.AnnotatedBlock{
 		Height: height,
 		Block:  blk,
-	}, nil
+	}
+	if blk.Header.HeaderType != block.Normal {
+		return annBlk, nil
+	}
+
+	// Round results at the height where a normal block was finalized are the results of its round.
+	results, err := sc.GetLastRoundResults(ctx, &api.RuntimeRequest{
+		RuntimeID: runtimeID,
+		Height:    height,
+	})
+	if err != nil {
+		return nil, err
+	}
+	annBlk.BatchSummary = results.BatchSummary
+
+	return annBlk, nil
 }
 
 // DeliverExecutorCommitment implements api.ExecutorCommitmentNotifier.
@@ -97,6 +97,15 @@ func (sc *ServiceClient) GetCommittees(ctx context.Context, request *api.GetComm
 	return runtimeCommittees, nil
 }
 
+func (sc *ServiceClient) GetElectionInputs(ctx context.Context, request *api.GetElectionInputsRequest) (*api.ElectionInputs, error) {
+	q, err := sc.querier.QueryAt(ctx, request.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ElectionInputs(ctx, request.Kind, request.RuntimeID)
+}
+
 func (sc *ServiceClient) WatchCommittees(_ context.Context) (<-chan *api.Committee, pubsub.ClosableSubscription, error) {
 	ch := make(chan *api.Committee)
 	sub := sc.notifier.Subscribe()
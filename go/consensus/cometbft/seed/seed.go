@@ -111,18 +111,7 @@ func (s *Service) GetPeers() []string {
 
 // GetAddresses returns a list of configured external addresses.
 func (s *Service) GetAddresses() ([]node.ConsensusAddress, error) {
-	u, err := tmcommon.GetExternalAddress()
-	if err != nil {
-		return nil, err
-	}
-
-	var addr node.ConsensusAddress
-	if err = addr.Address.UnmarshalText([]byte(u.Host)); err != nil {
-		return nil, fmt.Errorf("cometbft: failed to parse external address host: %w", err)
-	}
-	addr.ID = s.identity.P2PSigner.Public()
-
-	return []node.ConsensusAddress{addr}, nil
+	return tmcommon.GetConsensusAddresses(s.identity.P2PSigner.Public())
 }
 
 func initSeedDataDir(dataDir string) (string, error) {
@@ -3,12 +3,15 @@ package staking
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 
 	cmtabcitypes "github.com/cometbft/cometbft/abci/types"
+	cmtmerkle "github.com/cometbft/cometbft/crypto/merkle"
 	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	cmttypes "github.com/cometbft/cometbft/types"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -78,6 +81,22 @@ func (sc *ServiceClient) TokenValueExponent(ctx context.Context, height int64) (
 	return genesis.Staking.TokenValueExponent, nil
 }
 
+func (sc *ServiceClient) GetTokenInfo(ctx context.Context, height int64) (*api.TokenInfo, error) {
+	symbol, err := sc.TokenSymbol(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := sc.TokenValueExponent(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.TokenInfo{
+		Symbol:        symbol,
+		ValueExponent: exp,
+	}, nil
+}
+
 func (sc *ServiceClient) TotalSupply(ctx context.Context, height int64) (*quantity.Quantity, error) {
 	q, err := sc.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -217,6 +236,36 @@ func (sc *ServiceClient) Allowance(ctx context.Context, query *api.AllowanceQuer
 	return &allowance, nil
 }
 
+func (sc *ServiceClient) GetAllowanceHistory(ctx context.Context, query *api.AllowanceHistoryQuery) ([]*api.AllowanceHistoryEntry, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.AllowanceHistory(ctx, query)
+}
+
+func (sc *ServiceClient) CommissionAt(ctx context.Context, query *api.CommissionAtQuery) (*quantity.Quantity, error) {
+	acct, err := sc.Account(ctx, &api.OwnerQuery{
+		Height: query.Height,
+		Owner:  query.Owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.CommissionAt(&acct.Escrow.CommissionSchedule, query.Epoch), nil
+}
+
+func (sc *ServiceClient) ValidateScheduleUpdate(ctx context.Context, query *api.ScheduleUpdateQuery) (*api.CommissionSchedule, error) {
+	q, err := sc.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ValidateScheduleUpdate(ctx, query.Owner, &query.Amendment)
+}
+
 func (sc *ServiceClient) StateToGenesis(ctx context.Context, height int64) (*api.Genesis, error) {
 	// Query the staking genesis state.
 	q, err := sc.querier.QueryAt(ctx, height)
@@ -242,6 +291,73 @@ func (sc *ServiceClient) StateToGenesis(ctx context.Context, height int64) (*api
 }
 
 func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Event, error) {
+	events, _, err := sc.getEvents(ctx, height)
+	return events, err
+}
+
+func (sc *ServiceClient) GetEventProof(ctx context.Context, query *api.EventProofQuery) (*api.EventProof, error) {
+	blk, err := sc.consensus.GetBlock(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+	var meta tmapi.BlockMeta
+	if err = cbor.Unmarshal(blk.Meta, &meta); err != nil {
+		return nil, fmt.Errorf("staking: malformed block meta: %w", err)
+	}
+
+	events, txns, err := sc.getEvents(ctx, blk.Height)
+	if err != nil {
+		return nil, err
+	}
+	if query.Index >= uint64(len(events)) {
+		return nil, fmt.Errorf("%w: event index out of range", api.ErrInvalidArgument)
+	}
+	// The block metadata system transaction is always the last transaction in the block.
+	if len(txns) == 0 {
+		return nil, fmt.Errorf("staking: missing block metadata transaction")
+	}
+
+	// The provable events are in the same order as the events returned by GetEvents.
+	leaves := make([][]byte, 0, len(events))
+	for _, ev := range events {
+		pv, pvErr := ev.ProvableRepresentation()
+		if pvErr != nil {
+			return nil, pvErr
+		}
+		leaves = append(leaves, cbor.Marshal(pv))
+	}
+	_, eventProofs := cmtmerkle.ProofsFromByteSlices(leaves)
+
+	// CometBFT Merkle tree is computed over hashes and not over transactions.
+	hashes := make([][]byte, 0, len(txns))
+	for _, tx := range txns {
+		h := sha256.Sum256(tx)
+		hashes = append(hashes, h[:])
+	}
+	_, txProofs := cmtmerkle.ProofsFromByteSlices(hashes)
+
+	proof := &api.EventProof{
+		Event:       events[query.Index],
+		Header:      cbor.Marshal(meta.Header),
+		EventProof:  eventProofs[query.Index],
+		MetaTx:      txns[len(txns)-1],
+		MetaTxProof: txProofs[len(txns)-1],
+	}
+
+	// Make sure that the proof is consistent with the committed provable events root.
+	if err = api.VerifyEventProof(meta.Header.DataHash, proof); err != nil {
+		sc.logger.Error("failed to verify generated event proof",
+			"err", err,
+			"height", blk.Height,
+			"index", query.Index,
+		)
+		return nil, fmt.Errorf("staking: failed to generate event proof: %w", err)
+	}
+
+	return proof, nil
+}
+
+func (sc *ServiceClient) getEvents(ctx context.Context, height int64) ([]*api.Event, [][]byte, error) {
 	// Get block results at given height.
 	results, err := tmapi.GetBlockResults(ctx, height, sc.consensus)
 	if err != nil {
@@ -249,7 +365,7 @@ func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 			"err", err,
 			"height", height,
 		)
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get transactions at given height.
@@ -259,14 +375,14 @@ func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 			"err", err,
 			"height", results.Height,
 		)
-		return nil, err
+		return nil, nil, err
 	}
 
 	var events []*api.Event
 	// Decode events from block results (at the beginning of the block).
 	blockEvs, err := EventsFromCometBFT(nil, results.Height, results.Meta.BeginBlockEvents)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	events = append(events, blockEvs...)
 
@@ -277,7 +393,7 @@ func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 		// same transaction.
 		evs, txErr := EventsFromCometBFT(txns[txIdx], results.Height, txResult.Events)
 		if txErr != nil {
-			return nil, txErr
+			return nil, nil, txErr
 		}
 		events = append(events, evs...)
 	}
@@ -285,11 +401,11 @@ func (sc *ServiceClient) GetEvents(ctx context.Context, height int64) ([]*api.Ev
 	// Decode events from block results (at the end of the block).
 	blockEvs, err = EventsFromCometBFT(nil, results.Height, results.Meta.EndBlockEvents)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	events = append(events, blockEvs...)
 
-	return events, nil
+	return events, txns, nil
 }
 
 func (sc *ServiceClient) WatchEvents(context.Context) (<-chan *api.Event, pubsub.ClosableSubscription, error) {
@@ -309,6 +425,16 @@ func (sc *ServiceClient) ConsensusParameters(ctx context.Context, height int64)
 	return q.ConsensusParameters(ctx)
 }
 
+// EvidenceRecords returns the records of processed validator misbehavior evidence.
+func (sc *ServiceClient) EvidenceRecords(ctx context.Context, request *consensus.GetEvidenceRequest) ([]*consensus.EvidenceRecord, error) {
+	q, err := sc.querier.QueryAt(ctx, request.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.EvidenceRecords(ctx, request)
+}
+
 // ServiceDescriptor implements api.ServiceClient.
 func (sc *ServiceClient) ServiceDescriptor() tmapi.ServiceDescriptor {
 	return tmapi.NewStaticServiceDescriptor(api.ModuleName, app.EventType, []cmtpubsub.Query{app.QueryApp})
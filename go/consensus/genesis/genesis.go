@@ -52,6 +52,10 @@ type Parameters struct { // nolint: maligned
 	// FeatureVersion represents the latest consensus-breaking software version
 	// that follows calendar versioning (yy.minor[.micro]).
 	FeatureVersion *version.Version `json:"feature_version,omitempty"`
+
+	// DebugBlockTimeControl enables the debug-only block time control which allows the block
+	// timestamps observed by consensus applications to be frozen and stepped (UNSAFE).
+	DebugBlockTimeControl bool `json:"debug_block_time_control,omitempty"`
 }
 
 // IsFeatureVersion returns true iff the consensus feature version is high
@@ -91,6 +95,10 @@ func (g *Genesis) SanityCheck() error {
 		}
 	}
 
+	if params.DebugBlockTimeControl && !flags.DebugDontBlameOasis() {
+		return fmt.Errorf("consensus: sanity check failed: debug block time control is not allowed")
+	}
+
 	// Check for duplicate entries in the pk blacklist.
 	m := make(map[signature.PublicKey]bool)
 	for _, v := range params.PublicKeyBlacklist {
@@ -21,10 +21,33 @@ var (
 		},
 		[]string{"backend"},
 	)
+	SubmissionQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_consensus_submission_queue_depth",
+			Help: "Number of transactions waiting for or undergoing submission by the submission manager.",
+		},
+	)
+	SubmissionRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_consensus_submission_retries",
+			Help: "Number of transaction submission retries.",
+		},
+		[]string{"reason"},
+	)
+	SuggestedGasPrice = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_consensus_suggested_gas_price",
+			Help: "Gas price suggested for submitting transactions based on recent blocks.",
+		},
+		[]string{"percentile"},
+	)
 
 	consensusCollectors = []prometheus.Collector{
 		SignedBlocks,
 		ProposedBlocks,
+		SubmissionQueueDepth,
+		SubmissionRetries,
+		SuggestedGasPrice,
 	}
 
 	metricsOnce sync.Once
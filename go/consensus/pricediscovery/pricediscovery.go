@@ -4,36 +4,69 @@ package pricediscovery
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/metrics"
 )
 
 const (
-	// defaultWindowSize is the default number of recent blocks to use for calculating min gas
-	// price.
-	//
-	// NOTE: Code assumes that this is relatively small.
-	windowSize int = 6
+	// windowSize is the number of recent blocks to use for calculating the suggested gas price.
+	windowSize int = 20
+
+	// blockWeight is the total weight of gas price samples of a block.
+	blockWeight uint64 = 1_000_000
+
+	// MaxPercentile is the maximum gas price percentile.
+	MaxPercentile = 100
 )
 
+// Config is the dynamic price discovery configuration.
+type Config struct {
+	// FallbackGasPrice is the gas price used in case it is higher than the suggested gas price.
+	FallbackGasPrice uint64
+	// MaxGasPrice is the maximum gas price used for submitting transactions. Zero means that
+	// there is no maximum.
+	MaxGasPrice uint64
+	// Percentile is the percentile of gas prices paid in recent blocks used for submitting
+	// transactions.
+	Percentile uint8
+}
+
+// priceSample is a gas price paid for a fraction of a block.
+type priceSample struct {
+	// price is the gas price or nil for the minimum gas price.
+	price *quantity.Quantity
+	// weight is the fraction of the block in units of 1/blockWeight.
+	weight uint64
+}
+
 type priceDiscovery struct {
 	mu sync.RWMutex
 
 	// finalGasPrice is protected by the mutex.
 	finalGasPrice *quantity.Quantity
+	// minGasPrice is protected by the mutex.
+	minGasPrice *quantity.Quantity
+	// blockSamples is a rolling-array containing gas price samples for last up to `windowSize`
+	// blocks and is protected by the mutex.
+	blockSamples [][]priceSample
+	// tracks the current index of the blockSamples rolling array.
+	blockSamplesCurrentIdx int
 
+	cfg              Config
 	fallbackGasPrice *quantity.Quantity
-	minGasPrice      *quantity.Quantity
-	computedGasPrice *quantity.Quantity
-	// blockPrices is a rolling-array containing minimum transaction prices for last up to
-	// `windowSize` blocks.
-	blockPrices []*quantity.Quantity
-	// tracks the current index of the blockPrices rolling array.
-	blockPricesCurrentIdx int
+	maxGasPrice      *quantity.Quantity
 
 	consensus consensus.Backend
 
@@ -48,6 +81,61 @@ func (pd *priceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return pd.finalGasPrice.Clone(), nil
 }
 
+// SuggestedGasPrice implements consensus.PriceDiscovery.
+func (pd *priceDiscovery) SuggestedGasPrice(percentile uint8) (*quantity.Quantity, error) {
+	if percentile > MaxPercentile {
+		return nil, errors.WithContext(consensus.ErrInvalidArgument,
+			fmt.Sprintf("percentile must be at most %d", MaxPercentile),
+		)
+	}
+
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	return pd.suggestedGasPriceLocked(percentile), nil
+}
+
+func (pd *priceDiscovery) suggestedGasPriceLocked(percentile uint8) *quantity.Quantity {
+	// Unused block capacity is priced at the minimum gas price, so all samples of that price are
+	// merged together.
+	var (
+		samples   []priceSample
+		minWeight uint64
+	)
+	for _, blockSamples := range pd.blockSamples {
+		for _, sample := range blockSamples {
+			if sample.price == nil || sample.price.Cmp(pd.minGasPrice) <= 0 {
+				minWeight += sample.weight
+				continue
+			}
+			samples = append(samples, sample)
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].price.Cmp(samples[j].price) < 0
+	})
+
+	totalWeight := minWeight
+	for _, sample := range samples {
+		totalWeight += sample.weight
+	}
+	target := totalWeight * uint64(percentile)
+
+	// Find the lowest price such that the given percentile of block capacity was paid at most
+	// that price.
+	weight := minWeight
+	if totalWeight == 0 || weight*MaxPercentile >= target {
+		return pd.minGasPrice.Clone()
+	}
+	for _, sample := range samples {
+		weight += sample.weight
+		if weight*MaxPercentile >= target {
+			return sample.price.Clone()
+		}
+	}
+	return samples[len(samples)-1].price.Clone()
+}
+
 // refreshMinGasPrice refreshes minimum gas price reported by the consensus layer.
 func (pd *priceDiscovery) refreshMinGasPrice(ctx context.Context) {
 	mgp, err := pd.consensus.MinGasPrice(ctx)
@@ -58,38 +146,57 @@ func (pd *priceDiscovery) refreshMinGasPrice(ctx context.Context) {
 		return
 	}
 
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
 	pd.minGasPrice = mgp
 }
 
-// processBlock computes the gas price based on transactions in a block.
-func (pd *priceDiscovery) processBlock(_ context.Context, _ *consensus.Block) {
-	// Currently transactions are not ordered by price, so track price as zero. After the mempool
-	// is refactored, change this to properly compute the median gas price. Note that simply sorting
-	// transactions here wouldn't work as it wouldn't reflect the actual queuing process until the
-	// mempool is updated.
-	//
-	// We should also make sure to add some margin over the median in case of full blocks.
-	pd.trackPrice(quantity.NewQuantity())
+// processBlock records gas prices paid by transactions in a block.
+func (pd *priceDiscovery) processBlock(ctx context.Context, blk *consensus.Block) error {
+	params, err := pd.consensus.GetParameters(ctx, blk.Height)
+	if err != nil {
+		return fmt.Errorf("failed to query consensus parameters: %w", err)
+	}
+	txs, err := pd.consensus.GetTransactionsWithResults(ctx, blk.Height)
+	if err != nil {
+		return fmt.Errorf("failed to query transactions: %w", err)
+	}
+
+	pd.trackSamples(blockSamples(params.Parameters.MaxBlockGas, params.Parameters.MaxBlockSize, txs))
+	return nil
+}
+
+// trackSamples records the gas price samples of a block.
+func (pd *priceDiscovery) trackSamples(samples []priceSample) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pd.blockSamples[pd.blockSamplesCurrentIdx] = samples
+	pd.blockSamplesCurrentIdx = (pd.blockSamplesCurrentIdx + 1) % windowSize
 }
 
-// trackPrice records the price for a block.
-func (pd *priceDiscovery) trackPrice(price *quantity.Quantity) {
-	pd.blockPrices[pd.blockPricesCurrentIdx] = price
-	pd.blockPricesCurrentIdx = (pd.blockPricesCurrentIdx + 1) % windowSize
+// updateGasPrice recomputes the gas price used for submitting transactions.
+func (pd *priceDiscovery) updateGasPrice() {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
 
-	// Find maximum gas price.
-	maxPrice := quantity.NewFromUint64(0)
-	for _, price := range pd.blockPrices {
-		if price.Cmp(maxPrice) > 0 {
-			maxPrice = price
-		}
-	}
+	suggested := pd.suggestedGasPriceLocked(pd.cfg.Percentile)
+	price, _ := suggested.ToBigInt().Float64()
+	metrics.SuggestedGasPrice.With(prometheus.Labels{
+		"percentile": strconv.FormatUint(uint64(pd.cfg.Percentile), 10),
+	}).Set(price)
 
-	// No full blocks among last `windowSize` blocks.
-	if maxPrice.IsZero() {
-		maxPrice = nil
+	// Choose the maximum of (fallback, suggested) gas prices, bounded by the maximum gas price.
+	gasPrice := suggested
+	if pd.fallbackGasPrice.Cmp(gasPrice) > 0 {
+		gasPrice = pd.fallbackGasPrice
 	}
-	pd.computedGasPrice = maxPrice
+	if !pd.maxGasPrice.IsZero() && gasPrice.Cmp(pd.maxGasPrice) > 0 {
+		gasPrice = pd.maxGasPrice
+	}
+
+	pd.finalGasPrice = gasPrice.Clone()
 }
 
 func (pd *priceDiscovery) worker(ctx context.Context, ch <-chan *consensus.Block, sub pubsub.ClosableSubscription) {
@@ -101,40 +208,87 @@ func (pd *priceDiscovery) worker(ctx context.Context, ch <-chan *consensus.Block
 			return
 		case blk := <-ch:
 			pd.refreshMinGasPrice(ctx)
-			pd.processBlock(ctx, blk)
-
-			// Choose the maximum of (fallback, min, computed) gas prices.
-			gasPrice := pd.fallbackGasPrice
-			if pd.computedGasPrice != nil && pd.computedGasPrice.Cmp(gasPrice) > 0 {
-				gasPrice = pd.computedGasPrice
-			}
-			if pd.minGasPrice.Cmp(gasPrice) > 0 {
-				gasPrice = pd.minGasPrice
+			if err := pd.processBlock(ctx, blk); err != nil {
+				pd.logger.Warn("failed to process block",
+					"err", err,
+					"height", blk.Height,
+				)
 			}
+			pd.updateGasPrice()
+		}
+	}
+}
 
-			pd.mu.Lock()
-			pd.finalGasPrice = gasPrice.Clone()
-			pd.mu.Unlock()
+// blockSamples computes the gas price samples of a block with the given limits.
+//
+// Each block contributes samples with a total weight of one. Transactions are weighted by the
+// fraction of block capacity they used while unused capacity is weighted at the minimum gas price.
+func blockSamples(maxBlockGas transaction.Gas, maxBlockSize uint64, txs *consensus.TransactionsWithResults) []priceSample {
+	var (
+		samples   []priceSample
+		gasUsed   []uint64
+		totalGas  uint64
+		totalSize uint64
+	)
+	for i, rawTx := range txs.Transactions {
+		totalSize += uint64(len(rawTx))
+
+		var sigTx transaction.SignedTransaction
+		if err := cbor.Unmarshal(rawTx, &sigTx); err != nil {
+			continue
+		}
+		var tx transaction.Transaction
+		if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+			continue
 		}
+		if tx.Fee == nil || tx.Fee.Gas == 0 {
+			continue
+		}
+
+		gas := uint64(tx.Fee.Gas)
+		if i < len(txs.Results) && txs.Results[i].GasUsed > 0 {
+			gas = txs.Results[i].GasUsed
+		}
+		samples = append(samples, priceSample{price: tx.Fee.GasPrice()})
+		gasUsed = append(gasUsed, gas)
+		totalGas += gas
+	}
+
+	// Block fullness is determined by the most constrained resource.
+	var fullness uint64
+	if maxBlockGas > 0 {
+		fullness = max(fullness, totalGas*blockWeight/uint64(maxBlockGas))
 	}
+	if maxBlockSize > 0 {
+		fullness = max(fullness, totalSize*blockWeight/maxBlockSize)
+	}
+	fullness = min(fullness, blockWeight)
+
+	unused := blockWeight
+	for i := range samples {
+		samples[i].weight = fullness * gasUsed[i] / totalGas
+		unused -= samples[i].weight
+	}
+	return append(samples, priceSample{weight: unused})
 }
 
 // New creates a new dynamic price discovery implementation.
-func New(ctx context.Context, consensus consensus.Backend, fallbackGasPrice uint64) (consensus.PriceDiscovery, error) {
+func New(ctx context.Context, consensus consensus.Backend, cfg Config) (consensus.PriceDiscovery, error) {
+	if cfg.Percentile > MaxPercentile {
+		return nil, fmt.Errorf("percentile must be at most %d", MaxPercentile)
+	}
+
 	pd := &priceDiscovery{
-		finalGasPrice:    quantity.NewFromUint64(fallbackGasPrice),
-		fallbackGasPrice: quantity.NewFromUint64(fallbackGasPrice),
+		finalGasPrice:    quantity.NewFromUint64(cfg.FallbackGasPrice),
 		minGasPrice:      quantity.NewQuantity(),
-		computedGasPrice: quantity.NewQuantity(),
+		blockSamples:     make([][]priceSample, windowSize),
+		cfg:              cfg,
+		fallbackGasPrice: quantity.NewFromUint64(cfg.FallbackGasPrice),
+		maxGasPrice:      quantity.NewFromUint64(cfg.MaxGasPrice),
 		consensus:        consensus,
 		logger:           logging.GetLogger("consensus/pricediscovery"),
 	}
 
-	pd.blockPrices = make([]*quantity.Quantity, windowSize)
-	for i := range windowSize {
-		pd.blockPrices[i] = quantity.NewQuantity()
-	}
-
 	// Subscribe to consensus layer blocks and start watching.
 	ch, sub, err := pd.consensus.WatchBlocks(ctx)
 	if err != nil {
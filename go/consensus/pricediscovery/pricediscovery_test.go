@@ -0,0 +1,180 @@
+package pricediscovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction/results"
+)
+
+const (
+	testMaxBlockGas  = transaction.Gas(10_000)
+	testMaxBlockSize = 1024 * 1024
+	testMinGasPrice  = 10
+)
+
+type testTx struct {
+	gas   uint64
+	price uint64
+}
+
+func (pd *priceDiscovery) addTestBlocks(n int, block *consensus.TransactionsWithResults) {
+	for range n {
+		pd.trackSamples(blockSamples(testMaxBlockGas, testMaxBlockSize, block))
+	}
+	pd.updateGasPrice()
+}
+
+func requireSuggestedGasPrice(t *testing.T, pd *priceDiscovery, percentile uint8, expected uint64) {
+	price, err := pd.SuggestedGasPrice(percentile)
+	require.NoError(t, err, "SuggestedGasPrice")
+	require.EqualValues(t, quantity.NewFromUint64(expected), price, "suggested gas price (percentile: %d)", percentile)
+}
+
+func TestPriceDiscovery(t *testing.T) {
+	newPriceDiscovery := func(cfg Config) *priceDiscovery {
+		return &priceDiscovery{
+			finalGasPrice:    quantity.NewFromUint64(cfg.FallbackGasPrice),
+			minGasPrice:      quantity.NewFromUint64(testMinGasPrice),
+			blockSamples:     make([][]priceSample, windowSize),
+			cfg:              cfg,
+			fallbackGasPrice: quantity.NewFromUint64(cfg.FallbackGasPrice),
+			maxGasPrice:      quantity.NewFromUint64(cfg.MaxGasPrice),
+			logger:           logging.GetLogger("consensus/pricediscovery/test"),
+		}
+	}
+
+	newBlock := func(txs ...testTx) *consensus.TransactionsWithResults {
+		var block consensus.TransactionsWithResults
+		for i, tx := range txs {
+			var fee transaction.Fee
+			fee.Gas = transaction.Gas(tx.gas)
+			err := fee.Amount.FromUint64(tx.gas * tx.price)
+			require.NoError(t, err, "FromUint64")
+
+			// Signatures are not verified, so there is no need to sign transactions.
+			var sigTx transaction.SignedTransaction
+			sigTx.Blob = cbor.Marshal(&transaction.Transaction{
+				Nonce:  uint64(i),
+				Fee:    &fee,
+				Method: "test.Method",
+			})
+
+			block.Transactions = append(block.Transactions, cbor.Marshal(sigTx))
+			block.Results = append(block.Results, &results.Result{GasUsed: tx.gas})
+		}
+		return &block
+	}
+
+	t.Run("SuggestedGasPriceEmpty", func(t *testing.T) {
+		pd := newPriceDiscovery(Config{Percentile: 50})
+
+		// Without any blocks, the minimum gas price should be suggested.
+		for _, p := range []uint8{0, 50, 100} {
+			requireSuggestedGasPrice(t, pd, p, testMinGasPrice)
+		}
+
+		// Empty blocks should also result in the minimum gas price.
+		pd.addTestBlocks(windowSize, newBlock())
+		for _, p := range []uint8{0, 50, 100} {
+			requireSuggestedGasPrice(t, pd, p, testMinGasPrice)
+		}
+
+		// Transactions in almost empty blocks should not affect the suggestion much, even if they
+		// pay high fees.
+		pd.addTestBlocks(windowSize, newBlock(testTx{gas: 100, price: 1000}))
+		requireSuggestedGasPrice(t, pd, 50, testMinGasPrice)
+		requireSuggestedGasPrice(t, pd, 98, testMinGasPrice)
+		requireSuggestedGasPrice(t, pd, 100, 1000)
+
+		_, err := pd.SuggestedGasPrice(MaxPercentile + 1)
+		require.ErrorIs(t, err, consensus.ErrInvalidArgument)
+	})
+
+	t.Run("SuggestedGasPricePercentile", func(t *testing.T) {
+		pd := newPriceDiscovery(Config{Percentile: 50})
+
+		// Full blocks with gas prices uniformly distributed over 101-200.
+		var txs []testTx
+		for price := uint64(101); price <= 200; price++ {
+			txs = append(txs, testTx{gas: uint64(testMaxBlockGas) / 100, price: price})
+		}
+		pd.addTestBlocks(windowSize, newBlock(txs...))
+		for _, p := range []uint8{1, 10, 25, 50, 75, 90, 100} {
+			requireSuggestedGasPrice(t, pd, p, 100+uint64(p))
+		}
+
+		// Half-full blocks should only affect the upper half of the distribution.
+		pd.addTestBlocks(windowSize, newBlock(testTx{gas: uint64(testMaxBlockGas) / 2, price: 500}))
+		requireSuggestedGasPrice(t, pd, 25, testMinGasPrice)
+		requireSuggestedGasPrice(t, pd, 50, testMinGasPrice)
+		requireSuggestedGasPrice(t, pd, 51, 500)
+		requireSuggestedGasPrice(t, pd, 100, 500)
+	})
+
+	t.Run("SuggestedGasPriceFeeSpike", func(t *testing.T) {
+		const (
+			normalPrice = 100
+			spikePrice  = 5000
+		)
+		normalBlock := newBlock(testTx{gas: uint64(testMaxBlockGas), price: normalPrice})
+		spikeBlock := newBlock(testTx{gas: uint64(testMaxBlockGas), price: spikePrice})
+
+		pd := newPriceDiscovery(Config{Percentile: 75})
+		pd.addTestBlocks(windowSize, normalBlock)
+		requireSuggestedGasPrice(t, pd, 75, normalPrice)
+
+		// The suggestion for a percentile should follow the spike once more than the remaining
+		// fraction of recent blocks is affected.
+		for i := 1; i <= windowSize; i++ {
+			pd.addTestBlocks(1, spikeBlock)
+
+			for _, p := range []uint8{10, 50, 75, 90} {
+				expected := uint64(normalPrice)
+				if i*MaxPercentile > windowSize*(MaxPercentile-int(p)) {
+					expected = spikePrice
+				}
+				requireSuggestedGasPrice(t, pd, p, expected)
+			}
+		}
+
+		// Once the spike is over, the suggestion should return to normal.
+		pd.addTestBlocks(windowSize, normalBlock)
+		requireSuggestedGasPrice(t, pd, 90, normalPrice)
+		price, err := pd.GasPrice()
+		require.NoError(t, err, "GasPrice")
+		require.EqualValues(t, quantity.NewFromUint64(normalPrice), price)
+	})
+
+	t.Run("GasPriceBounds", func(t *testing.T) {
+		fullBlock := newBlock(testTx{gas: uint64(testMaxBlockGas), price: 5000})
+
+		for _, tc := range []struct {
+			cfg      Config
+			block    *consensus.TransactionsWithResults
+			expected uint64
+		}{
+			// Empty network uses the minimum gas price.
+			{Config{Percentile: 50}, newBlock(), testMinGasPrice},
+			// Fallback gas price is used when higher than the suggested gas price.
+			{Config{Percentile: 50, FallbackGasPrice: 42}, newBlock(), 42},
+			// Suggested gas price is used when higher than the fallback.
+			{Config{Percentile: 50, FallbackGasPrice: 42}, fullBlock, 5000},
+			// Suggested gas price is bounded by the maximum gas price.
+			{Config{Percentile: 50, FallbackGasPrice: 42, MaxGasPrice: 1000}, fullBlock, 1000},
+		} {
+			pd := newPriceDiscovery(tc.cfg)
+			pd.addTestBlocks(windowSize, tc.block)
+
+			price, err := pd.GasPrice()
+			require.NoError(t, err, "GasPrice")
+			require.EqualValues(t, quantity.NewFromUint64(tc.expected), price, "gas price (config: %+v)", tc.cfg)
+		}
+	})
+}
@@ -24,3 +24,7 @@ func NewStatic(price uint64) (consensus.PriceDiscovery, error) {
 func (pd *staticPriceDiscovery) GasPrice() (*quantity.Quantity, error) {
 	return pd.price.Clone(), nil
 }
+
+func (pd *staticPriceDiscovery) SuggestedGasPrice(uint8) (*quantity.Quantity, error) {
+	return pd.price.Clone(), nil
+}
@@ -131,6 +131,14 @@ func ConsensusImplementationTests(t *testing.T, consensus api.Backend) {
 	})
 	require.NoError(err, "EstimateGas")
 
+	minGasPrice, err := consensus.MinGasPrice(ctx)
+	require.NoError(err, "MinGasPrice")
+	gasPrice, err := consensus.GetSuggestedGasPrice(ctx, 50)
+	require.NoError(err, "GetSuggestedGasPrice")
+	require.True(gasPrice.Cmp(minGasPrice) >= 0, "suggested gas price should not be below minimum gas price")
+	_, err = consensus.GetSuggestedGasPrice(ctx, 101)
+	require.ErrorIs(err, api.ErrInvalidArgument, "GetSuggestedGasPrice with invalid percentile should fail")
+
 	nonce, err := consensus.GetSignerNonce(ctx, &api.GetSignerNonceRequest{
 		AccountAddress: staking.NewAddress(
 			signature.NewPublicKey("badfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
@@ -162,6 +170,15 @@ func ConsensusImplementationTests(t *testing.T, consensus api.Backend) {
 	require.NoError(err, "GetParameters(HeightLatest)")
 	require.NotEqual(0, lparams.Parameters.StateCheckpointInterval, "returned parameters should contain parameters")
 
+	bundle, err := consensus.GetSigningContextBundle(ctx, blk.Height)
+	require.NoError(err, "GetSigningContextBundle")
+	require.NoError(bundle.ValidateBasic(), "returned signing context bundle should be valid")
+	require.Equal(blk.Height, bundle.Height, "returned signing context bundle height should be correct")
+	require.Equal(blk.Hash, bundle.Hash, "returned signing context bundle hash should be correct")
+	require.True(blk.Time.Equal(bundle.Time), "returned signing context bundle time should be correct")
+	require.Equal(chainCtx, bundle.ChainContext, "returned signing context bundle chain context should be correct")
+	require.Equal(params.Parameters, bundle.Consensus, "returned signing context bundle should contain parameters")
+
 	err = consensus.SubmitTxNoWait(ctx, &transaction.SignedTransaction{})
 	require.Error(err, "SubmitTxNoWait should fail with invalid transaction")
 
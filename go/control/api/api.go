@@ -7,19 +7,31 @@ import (
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/diagnostics"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	block "github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	txpoolConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+	sentry "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common/api"
 	executorWorker "github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
@@ -33,6 +45,21 @@ const ModuleName = "control"
 // ErrNotImplemented is the error raised when the node does not support the required functionality.
 var ErrNotImplemented = errors.New(ModuleName, 1, "control: not implemented")
 
+// ErrRuntimeNotHosted is the error raised when the requested runtime is not hosted by the node.
+var ErrRuntimeNotHosted = errors.New(ModuleName, 2, "control: runtime not hosted")
+
+// ErrUnsupportedRoleChange is the error raised when the requested role change is not supported.
+var ErrUnsupportedRoleChange = errors.New(ModuleName, 3, "control: unsupported role change")
+
+const (
+	// HealthServiceLiveness is the name of the service reported by the standard gRPC health
+	// service which is serving iff the node is live.
+	HealthServiceLiveness = "liveness"
+	// HealthServiceReadiness is the name of the service reported by the standard gRPC health
+	// service which is serving iff the node is ready.
+	HealthServiceReadiness = "readiness"
+)
+
 // NodeController is a node controller interface.
 type NodeController interface {
 	// RequestShutdown requests the node to shut down gracefully.
@@ -51,8 +78,17 @@ type NodeController interface {
 	WaitReady(ctx context.Context) error
 
 	// IsReady checks whether the node is ready to accept runtime work.
+	//
+	// A node is ready once it is synced, registered (if it registers) and all of the runtimes
+	// required by its roles are initialized.
 	IsReady(ctx context.Context) (bool, error)
 
+	// IsLive checks whether the node's internal services are responsive.
+	//
+	// A node is live as long as none of the heartbeats of its internal service loops (e.g.,
+	// consensus, P2P and runtime workers) are older than their staleness thresholds.
+	IsLive(ctx context.Context) (bool, error)
+
 	// UpgradeBinary submits an upgrade descriptor to a running node.
 	// The node will wait for the appropriate epoch, then update its binaries
 	// and shut down.
@@ -61,6 +97,16 @@ type NodeController interface {
 	// CancelUpgrade cancels the specific pending upgrade, unless it is already in progress.
 	CancelUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) error
 
+	// ListPendingUpgrades returns the upgrades scheduled on this node together with their
+	// sources (governance or local submission).
+	ListPendingUpgrades(ctx context.Context) ([]*upgrade.PendingUpgrade, error)
+
+	// CancelLocalUpgrade cancels the locally submitted pending upgrades with the given handler
+	// name, unless they are already in progress.
+	//
+	// Upgrades scheduled by governance can not be cancelled.
+	CancelLocalUpgrade(ctx context.Context, name upgrade.HandlerName) error
+
 	// GetStatus returns the current status overview of the node.
 	GetStatus(ctx context.Context) (*Status, error)
 
@@ -69,6 +115,109 @@ type NodeController interface {
 	// If the bundle upgrades an existing ROFL component, the latter will
 	// be upgraded to the new version.
 	AddBundle(ctx context.Context, path string) error
+
+	// SetLogLevel changes the log level of all modules with the given prefix
+	// at runtime. The "*" module changes the default log level. An empty level
+	// removes the log level override of the given prefix.
+	SetLogLevel(ctx context.Context, module string, level string) error
+
+	// GetLogLevels returns the current log levels keyed by module prefix.
+	// The default log level is stored under the "*" key.
+	GetLogLevels(ctx context.Context) (map[string]string, error)
+
+	// SetTxPoolLanes reconfigures the transaction pool priority lanes of the given runtime.
+	//
+	// The change is not persisted and the configured lanes are restored on restart.
+	SetTxPoolLanes(ctx context.Context, req *SetTxPoolLanesRequest) error
+
+	// PauseRuntime stops the given hosted runtime and omits it from subsequent node
+	// registrations until it is resumed.
+	//
+	// The paused state is persisted and respected across node restarts.
+	PauseRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// ResumeRuntime restarts the given previously paused hosted runtime.
+	ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error
+
+	// SetGrpcAccessAllowlist replaces the set of TLS client certificate public keys that are
+	// allowed to call the restricted methods of the external gRPC server.
+	//
+	// The change is not persisted and the configured allowlist is restored on restart.
+	SetGrpcAccessAllowlist(ctx context.Context, keys []signature.PublicKey) error
+
+	// AddIndexerToken adds an access token for the indexer gRPC server of the storage worker.
+	//
+	// The change is not persisted and only the configured tokens are restored on restart.
+	AddIndexerToken(ctx context.Context, token *storageWorker.IndexerToken) error
+
+	// RevokeIndexerToken revokes the indexer access token with the given name.
+	//
+	// The change is not persisted and only the configured tokens are restored on restart.
+	RevokeIndexerToken(ctx context.Context, name string) error
+
+	// ListIndexerTokens returns information about the indexer access tokens, sorted by name.
+	ListIndexerTokens(ctx context.Context) ([]*storageWorker.IndexerTokenInfo, error)
+
+	// GetWorkerPoolLimits returns the concurrency limits of the shared priority worker pool.
+	GetWorkerPoolLimits(ctx context.Context) (*workerpool.Limits, error)
+
+	// SetWorkerPoolLimits changes the concurrency limits of the shared priority worker pool.
+	//
+	// The change is not persisted and the default limits are restored on restart.
+	SetWorkerPoolLimits(ctx context.Context, limits *workerpool.Limits) error
+
+	// UpdateRoles downgrades the node to the given roles without a restart.
+	//
+	// Workers of the removed roles stop participating in committees once their current round
+	// completes, after which the node registration is updated to omit the removed roles. The
+	// change is persisted and respected across node restarts. Only the compute and storage RPC
+	// roles can be removed and adding roles is not supported.
+	UpdateRoles(ctx context.Context, roles node.RolesMask) error
+
+	// ListCrashDumps returns the metadata of the crash dumps available on the node, oldest first.
+	ListCrashDumps(ctx context.Context) ([]*crashdump.Info, error)
+
+	// GetCrashDump returns the crash dump with the given name.
+	GetCrashDump(ctx context.Context, name string) (*crashdump.Dump, error)
+
+	// GetEventJournal returns the retained significant node lifecycle events, oldest first,
+	// starting at the given index.
+	GetEventJournal(ctx context.Context, request *GetEventJournalRequest) ([]*journal.Event, error)
+
+	// CreateDiagnosticsBundle assembles a support diagnostics bundle in the node's data directory
+	// and returns its metadata.
+	//
+	// The bundle contains the node status, the event journal, recent logs, version information,
+	// consensus parameters at the latest height, runtime statuses and a metrics snapshot. Private
+	// keys and other secrets are never included.
+	CreateDiagnosticsBundle(ctx context.Context) (*diagnostics.Info, error)
+
+	// GetDiagnosticsBundle returns the content of the diagnostics bundle with the given name.
+	GetDiagnosticsBundle(ctx context.Context, name string) ([]byte, error)
+}
+
+// GetEventJournalRequest is a GetEventJournal request.
+type GetEventJournalRequest struct {
+	// FromIndex is the index of the first returned event.
+	FromIndex uint64 `json:"from_index,omitempty"`
+	// Limit is the maximum number of returned events. Zero means no limit.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// SetLogLevelRequest is a SetLogLevel request.
+type SetLogLevelRequest struct {
+	// Module is the module prefix or "*" for the default log level.
+	Module string `json:"module"`
+	// Level is the new log level (debug, info, warn, error) or empty to reset the override.
+	Level string `json:"level"`
+}
+
+// SetTxPoolLanesRequest is a SetTxPoolLanes request.
+type SetTxPoolLanesRequest struct {
+	// RuntimeID is the identifier of the runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Lanes are the new priority lanes. An empty list disables lanes.
+	Lanes []txpoolConfig.LaneConfig `json:"lanes,omitempty"`
 }
 
 // Status is the current status overview.
@@ -88,26 +237,48 @@ type Status struct {
 	// Consensus is the status overview of the consensus layer.
 	Consensus *consensus.Status `json:"consensus,omitempty"`
 
+	// ValidatorEvidence are the retained records of misbehavior evidence committed against this
+	// node's validator, if any.
+	ValidatorEvidence []*consensus.EvidenceRecord `json:"validator_evidence,omitempty"`
+
 	// LightClient is the status overview of the light client service.
 	LightClient *consensus.LightClientStatus `json:"light_client,omitempty"`
 
+	// TCBStatus is the TCB status of the local platform in case it is not up to date.
+	TCBStatus *pcs.PlatformTCBStatus `json:"tcb_status,omitempty"`
+
 	// Runtimes is the status overview for each runtime supported by the node.
 	Runtimes map[common.Namespace]RuntimeStatus `json:"runtimes,omitempty"`
 
 	// Registration is the node's registration status.
 	Registration *RegistrationStatus `json:"registration,omitempty"`
 
+	// Beacon is the node's beacon worker status.
+	Beacon *BeaconStatus `json:"beacon,omitempty"`
+
+	// RoleTransition is the status of the last role downgrade requested via the control API.
+	RoleTransition *RoleTransitionStatus `json:"role_transition,omitempty"`
+
 	// Keymanager is the node's key manager worker status if the node is a key manager node.
 	Keymanager *keymanagerWorker.Status `json:"keymanager,omitempty"`
 
 	// PendingUpgrades are the node's pending upgrades.
 	PendingUpgrades []*upgrade.PendingUpgrade `json:"pending_upgrades,omitempty"`
 
+	// UpgradeReadiness is the node's readiness for each of the pending upgrades.
+	UpgradeReadiness []*upgrade.PendingUpgradeReadiness `json:"upgrade_readiness,omitempty"`
+
 	// P2P is the P2P status of the node.
 	P2P *p2p.Status `json:"p2p,omitempty"`
 
 	// Seed is the seed node status if the node is a seed node.
 	Seed *SeedStatus `json:"seed,omitempty"`
+
+	// Sentry is the sentry node status if the node is a sentry node.
+	Sentry *sentry.Status `json:"sentry,omitempty"`
+
+	// Sentries are the statuses of the node's configured sentry nodes.
+	Sentries []SentryStatus `json:"sentries,omitempty"`
 }
 
 // DebugStatus is the current node debug status, listing the various node
@@ -120,6 +291,9 @@ type DebugStatus struct {
 	// AllowRoot is true iff the node is running with DebugAllowRoot
 	// set.
 	AllowRoot bool `json:"allow_root"`
+
+	// Faults are the currently injected faults, if any.
+	Faults *fault.Faults `json:"faults,omitempty"`
 }
 
 // IdentityStatus is the current node identity status, listing all the public keys that identify
@@ -133,6 +307,46 @@ type IdentityStatus struct {
 
 	// TLS is the public key used for TLS connections.
 	TLS signature.PublicKey `json:"tls"`
+
+	// NextTLS is the public key that will be used for TLS connections once the pending TLS key
+	// rotation completes (if any).
+	NextTLS *signature.PublicKey `json:"next_tls,omitempty"`
+}
+
+// BeaconStatus is the beacon worker status.
+type BeaconStatus struct {
+	// VRFProofWarning is true iff the node has not submitted its VRF proof for the current epoch
+	// by the configured fraction of the proof submission window.
+	VRFProofWarning bool `json:"vrf_proof_warning"`
+
+	// VRFProofWarningEpoch is the epoch for which the VRF proof warning was raised.
+	VRFProofWarningEpoch beacon.EpochTime `json:"vrf_proof_warning_epoch,omitempty"`
+}
+
+// RoleTransitionState is the state of a role downgrade.
+type RoleTransitionState string
+
+const (
+	// RoleTransitionStateStoppingWorkers is the state in which the workers of the removed roles
+	// are waiting for their current rounds to complete.
+	RoleTransitionStateStoppingWorkers RoleTransitionState = "stopping_workers"
+	// RoleTransitionStateUpdatingRegistration is the state in which the node is waiting for its
+	// registration without the removed roles to be accepted.
+	RoleTransitionStateUpdatingRegistration RoleTransitionState = "updating_registration"
+	// RoleTransitionStateCompleted is the state in which the role downgrade has completed.
+	RoleTransitionStateCompleted RoleTransitionState = "completed"
+)
+
+// RoleTransitionStatus is the status of a role downgrade.
+type RoleTransitionStatus struct {
+	// State is the state of the role downgrade.
+	State RoleTransitionState `json:"state"`
+
+	// Roles are the roles the node is being downgraded to.
+	Roles node.RolesMask `json:"roles"`
+
+	// RemovedRoles are the roles being removed.
+	RemovedRoles node.RolesMask `json:"removed_roles"`
 }
 
 // RegistrationStatus is the node registration status.
@@ -159,6 +373,28 @@ type RegistrationStatus struct {
 
 	// NodeStatus is the registry live status of the node.
 	NodeStatus *registry.NodeStatus `json:"node_status,omitempty"`
+
+	// TLSRotation is the status of the automatic TLS key rotation, if enabled.
+	TLSRotation *TLSRotationStatus `json:"tls_rotation,omitempty"`
+}
+
+// TLSRotationStatus is the automatic TLS key rotation status.
+type TLSRotationStatus struct {
+	// Pending is true iff a TLS key rotation is in its overlap window.
+	Pending bool `json:"pending"`
+
+	// NextPubKey is the TLS public key the node will switch to once the pending rotation completes.
+	NextPubKey *signature.PublicKey `json:"next_pub_key,omitempty"`
+
+	// StartedEpoch is the epoch at which the pending rotation started.
+	StartedEpoch beacon.EpochTime `json:"started_epoch,omitempty"`
+
+	// LastRotationEpoch is the epoch at which the last rotation completed.
+	LastRotationEpoch beacon.EpochTime `json:"last_rotation_epoch,omitempty"`
+
+	// LastRotation is the time at which the last rotation completed. In case the node did not
+	// rotate its TLS key yet, it will be the zero timestamp.
+	LastRotation time.Time `json:"last_rotation"`
 }
 
 // RuntimeStatus is the per-runtime status overview.
@@ -194,11 +430,47 @@ type RuntimeStatus struct {
 	// Indexer contains the runtime history indexer status in case this runtime has a block indexer.
 	Indexer *history.IndexerStatus `json:"indexer,omitempty"`
 
+	// TagIndex contains the transaction tag index status in case the index is enabled.
+	TagIndex *runtimeClient.TagIndexStatus `json:"tag_index,omitempty"`
+	// ReadOnly contains the read-only mode status in case this runtime is operated in read-only
+	// mode.
+	ReadOnly *ReadOnlyRuntimeStatus `json:"read_only,omitempty"`
+	// Paused is true iff the hosted runtime has been paused via the control API.
+	Paused bool `json:"paused,omitempty"`
+
 	// Provisioner is the name of the runtime provisioner.
 	Provisioner string `json:"provisioner,omitempty"`
 
+	// Replicas contains statuses of the runtime instances in case the active runtime version is
+	// backed by multiple instances.
+	Replicas []host.ReplicaStatus `json:"replicas,omitempty"`
+
 	// Components contains statuses of the runtime components.
 	Components []ComponentStatus `json:"components,omitempty"`
+
+	// PendingBundles contains statuses of the runtime bundles pending download.
+	PendingBundles []bundle.DownloadStatus `json:"pending_bundles,omitempty"`
+}
+
+// ReadOnlyRuntimeStatus is the status of a runtime that only follows the runtime state.
+type ReadOnlyRuntimeStatus struct {
+	// LastSyncedRound is the last round for which the runtime state has been synced.
+	LastSyncedRound uint64 `json:"last_synced_round"`
+	// StateLag is the number of rounds the synced runtime state is behind the latest round.
+	StateLag uint64 `json:"state_lag"`
+}
+
+// NewReadOnlyRuntimeStatus creates a new read-only runtime status given the round of the latest
+// runtime block and the last synced round.
+func NewReadOnlyRuntimeStatus(latestRound, lastSyncedRound uint64) *ReadOnlyRuntimeStatus {
+	var lag uint64
+	if latestRound > lastSyncedRound {
+		lag = latestRound - lastSyncedRound
+	}
+	return &ReadOnlyRuntimeStatus{
+		LastSyncedRound: lastSyncedRound,
+		StateLag:        lag,
+	}
 }
 
 // ComponentStatus is the runtime component status overview.
@@ -232,6 +504,18 @@ type SeedStatus struct {
 	NodePeers []string `json:"node_peers"`
 }
 
+// SentryStatus is the status of a sentry node as reported by the node using it.
+type SentryStatus struct {
+	// Address is the TLS address of the sentry node.
+	Address node.TLSAddress `json:"address"`
+
+	// Status is the status reported by the sentry node.
+	Status *sentry.Status `json:"status,omitempty"`
+
+	// Error is the error encountered while querying the sentry node (if any).
+	Error string `json:"error,omitempty"`
+}
+
 // DebugModuleName is the module name for the debug controller service.
 const DebugModuleName = "control/debug"
 
@@ -243,6 +527,59 @@ type DebugController interface {
 	//       return an error.
 	SetEpoch(ctx context.Context, epoch beacon.EpochTime) error
 
+	// SetEpochSchedule installs a schedule of automatic epoch transitions at fixed block
+	// heights. A schedule with a zero interval clears any installed schedule. Manually setting
+	// the epoch via SetEpoch clears the schedule.
+	//
+	// NOTE: This only works with a mock beacon backend and will otherwise
+	//       return an error.
+	SetEpochSchedule(ctx context.Context, schedule *beacon.EpochSchedule) error
+
+	// SetBlockTime freezes the block timestamps observed by consensus applications at the given
+	// base time and advances them by the given step on each subsequent block. A zero step keeps
+	// the timestamps frozen, while a zero base time restores the timestamps proposed by the
+	// consensus backend, which may cause block time to go backwards.
+	//
+	// NOTE: This only works when debug block time control is enabled in the consensus
+	//       parameters and will otherwise return an error.
+	SetBlockTime(ctx context.Context, base time.Time, step time.Duration) error
+
 	// WaitNodesRegistered waits for the given number of nodes to register.
 	WaitNodesRegistered(ctx context.Context, count int) error
+
+	// DryRunUpgrade executes the consensus portion of the given upgrade against a throwaway copy
+	// of the current consensus state without committing anything.
+	DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error)
+
+	// RepairStorage walks all storage roots of the given runtime round and restores any nodes
+	// that are missing from local storage by fetching them from peers.
+	RepairStorage(ctx context.Context, request *storageWorker.RepairRequest) (*storageWorker.RepairResult, error)
+
+	// ExportRuntimeState writes a self-contained archive of the state of the given runtime at
+	// the given round to a file on the node's filesystem. The archive can be imported into a
+	// fresh node to reproduce runtime bugs at exactly that state.
+	//
+	// In case the round has already been pruned, an error containing the nearest available round
+	// is returned.
+	ExportRuntimeState(ctx context.Context, request *storageWorker.ExportStateRequest) (*storageWorker.ExportStateResult, error)
+
+	// GetRuntimeStateStats walks the state of the given runtime at the given round and returns
+	// aggregate node counts, key counts and sizes grouped by key prefixes. Results are cached
+	// per finalized root.
+	GetRuntimeStateStats(ctx context.Context, request *storageWorker.StateStatsRequest) (*mkvs.TreeStats, error)
+
+	// SetRegistrationFault configures whether the node stops submitting node registrations.
+	SetRegistrationFault(ctx context.Context, stopped bool) error
+
+	// SetExecutorCommitDelayFault configures the delay applied before the node submits executor
+	// commitments. A zero delay disables the fault.
+	SetExecutorCommitDelayFault(ctx context.Context, delay time.Duration) error
+
+	// SetCommitteeMessageDropFault configures the percentage of received p2p committee messages
+	// that the node drops. A zero percentage disables the fault.
+	SetCommitteeMessageDropFault(ctx context.Context, percent uint8) error
+
+	// SetCheckpointServingFault configures whether the node stops serving storage checkpoints to
+	// its peers.
+	SetCheckpointServingFault(ctx context.Context, paused bool) error
 }
@@ -5,8 +5,16 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/diagnostics"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
 var (
@@ -23,14 +31,54 @@ var (
 	methodWaitReady = serviceName.NewMethod("WaitReady", nil)
 	// methodIsReady is the IsReady method.
 	methodIsReady = serviceName.NewMethod("IsReady", nil)
+	// methodIsLive is the IsLive method.
+	methodIsLive = serviceName.NewMethod("IsLive", nil)
 	// methodUpgradeBinary is the UpgradeBinary method.
 	methodUpgradeBinary = serviceName.NewMethod("UpgradeBinary", upgradeApi.Descriptor{})
 	// methodCancelUpgrade is the CancelUpgrade method.
 	methodCancelUpgrade = serviceName.NewMethod("CancelUpgrade", nil)
+	// methodListPendingUpgrades is the ListPendingUpgrades method.
+	methodListPendingUpgrades = serviceName.NewMethod("ListPendingUpgrades", nil)
+	// methodCancelLocalUpgrade is the CancelLocalUpgrade method.
+	methodCancelLocalUpgrade = serviceName.NewMethod("CancelLocalUpgrade", upgradeApi.HandlerName(""))
 	// methodGetStatus is the GetStatus method.
 	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 	// methodAddBundle is the AddBundle method.
 	methodAddBundle = serviceName.NewMethod("AddBundle", nil)
+	// methodSetLogLevel is the SetLogLevel method.
+	methodSetLogLevel = serviceName.NewMethod("SetLogLevel", SetLogLevelRequest{})
+	// methodGetLogLevels is the GetLogLevels method.
+	methodGetLogLevels = serviceName.NewMethod("GetLogLevels", nil)
+	// methodSetTxPoolLanes is the SetTxPoolLanes method.
+	methodSetTxPoolLanes = serviceName.NewMethod("SetTxPoolLanes", SetTxPoolLanesRequest{})
+	// methodPauseRuntime is the PauseRuntime method.
+	methodPauseRuntime = serviceName.NewMethod("PauseRuntime", common.Namespace{})
+	// methodResumeRuntime is the ResumeRuntime method.
+	methodResumeRuntime = serviceName.NewMethod("ResumeRuntime", common.Namespace{})
+	// methodSetGrpcAccessAllowlist is the SetGrpcAccessAllowlist method.
+	methodSetGrpcAccessAllowlist = serviceName.NewMethod("SetGrpcAccessAllowlist", []signature.PublicKey{})
+	// methodAddIndexerToken is the AddIndexerToken method.
+	methodAddIndexerToken = serviceName.NewMethod("AddIndexerToken", storageWorker.IndexerToken{})
+	// methodRevokeIndexerToken is the RevokeIndexerToken method.
+	methodRevokeIndexerToken = serviceName.NewMethod("RevokeIndexerToken", "")
+	// methodListIndexerTokens is the ListIndexerTokens method.
+	methodListIndexerTokens = serviceName.NewMethod("ListIndexerTokens", nil)
+	// methodGetWorkerPoolLimits is the GetWorkerPoolLimits method.
+	methodGetWorkerPoolLimits = serviceName.NewMethod("GetWorkerPoolLimits", nil)
+	// methodSetWorkerPoolLimits is the SetWorkerPoolLimits method.
+	methodSetWorkerPoolLimits = serviceName.NewMethod("SetWorkerPoolLimits", workerpool.Limits{})
+	// methodUpdateRoles is the UpdateRoles method.
+	methodUpdateRoles = serviceName.NewMethod("UpdateRoles", node.RolesMask(0))
+	// methodListCrashDumps is the ListCrashDumps method.
+	methodListCrashDumps = serviceName.NewMethod("ListCrashDumps", nil)
+	// methodGetCrashDump is the GetCrashDump method.
+	methodGetCrashDump = serviceName.NewMethod("GetCrashDump", "")
+	// methodGetEventJournal is the GetEventJournal method.
+	methodGetEventJournal = serviceName.NewMethod("GetEventJournal", GetEventJournalRequest{})
+	// methodCreateDiagnosticsBundle is the CreateDiagnosticsBundle method.
+	methodCreateDiagnosticsBundle = serviceName.NewMethod("CreateDiagnosticsBundle", nil)
+	// methodGetDiagnosticsBundle is the GetDiagnosticsBundle method.
+	methodGetDiagnosticsBundle = serviceName.NewMethod("GetDiagnosticsBundle", "")
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -57,6 +105,10 @@ var (
 				MethodName: methodIsReady.ShortName(),
 				Handler:    handlerIsReady,
 			},
+			{
+				MethodName: methodIsLive.ShortName(),
+				Handler:    handlerIsLive,
+			},
 			{
 				MethodName: methodUpgradeBinary.ShortName(),
 				Handler:    handlerUpgradeBinary,
@@ -65,6 +117,14 @@ var (
 				MethodName: methodCancelUpgrade.ShortName(),
 				Handler:    handlerCancelUpgrade,
 			},
+			{
+				MethodName: methodListPendingUpgrades.ShortName(),
+				Handler:    handlerListPendingUpgrades,
+			},
+			{
+				MethodName: methodCancelLocalUpgrade.ShortName(),
+				Handler:    handlerCancelLocalUpgrade,
+			},
 			{
 				MethodName: methodGetStatus.ShortName(),
 				Handler:    handlerGetStatus,
@@ -73,6 +133,74 @@ var (
 				MethodName: methodAddBundle.ShortName(),
 				Handler:    handlerAddBundle,
 			},
+			{
+				MethodName: methodSetLogLevel.ShortName(),
+				Handler:    handlerSetLogLevel,
+			},
+			{
+				MethodName: methodGetLogLevels.ShortName(),
+				Handler:    handlerGetLogLevels,
+			},
+			{
+				MethodName: methodSetTxPoolLanes.ShortName(),
+				Handler:    handlerSetTxPoolLanes,
+			},
+			{
+				MethodName: methodPauseRuntime.ShortName(),
+				Handler:    handlerPauseRuntime,
+			},
+			{
+				MethodName: methodResumeRuntime.ShortName(),
+				Handler:    handlerResumeRuntime,
+			},
+			{
+				MethodName: methodSetGrpcAccessAllowlist.ShortName(),
+				Handler:    handlerSetGrpcAccessAllowlist,
+			},
+			{
+				MethodName: methodAddIndexerToken.ShortName(),
+				Handler:    handlerAddIndexerToken,
+			},
+			{
+				MethodName: methodRevokeIndexerToken.ShortName(),
+				Handler:    handlerRevokeIndexerToken,
+			},
+			{
+				MethodName: methodListIndexerTokens.ShortName(),
+				Handler:    handlerListIndexerTokens,
+			},
+			{
+				MethodName: methodGetWorkerPoolLimits.ShortName(),
+				Handler:    handlerGetWorkerPoolLimits,
+			},
+			{
+				MethodName: methodSetWorkerPoolLimits.ShortName(),
+				Handler:    handlerSetWorkerPoolLimits,
+			},
+			{
+				MethodName: methodUpdateRoles.ShortName(),
+				Handler:    handlerUpdateRoles,
+			},
+			{
+				MethodName: methodListCrashDumps.ShortName(),
+				Handler:    handlerListCrashDumps,
+			},
+			{
+				MethodName: methodGetCrashDump.ShortName(),
+				Handler:    handlerGetCrashDump,
+			},
+			{
+				MethodName: methodGetEventJournal.ShortName(),
+				Handler:    handlerGetEventJournal,
+			},
+			{
+				MethodName: methodCreateDiagnosticsBundle.ShortName(),
+				Handler:    handlerCreateDiagnosticsBundle,
+			},
+			{
+				MethodName: methodGetDiagnosticsBundle.ShortName(),
+				Handler:    handlerGetDiagnosticsBundle,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -169,7 +297,7 @@ func handlerIsReady(
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: methodIsSynced.FullName(),
+		FullMethod: methodIsReady.FullName(),
 	}
 	handler := func(ctx context.Context, _ any) (any, error) {
 		return srv.(NodeController).IsReady(ctx)
@@ -177,6 +305,25 @@ func handlerIsReady(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerIsLive(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).IsLive(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodIsLive.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).IsLive(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerUpgradeBinary(
 	srv any,
 	ctx context.Context,
@@ -223,6 +370,48 @@ func handlerCancelUpgrade(
 	return interceptor(ctx, &descriptor, info, handler)
 }
 
+func handlerListPendingUpgrades(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).ListPendingUpgrades(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodListPendingUpgrades.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).ListPendingUpgrades(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerCancelLocalUpgrade(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var name upgradeApi.HandlerName
+	if err := dec(&name); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).CancelLocalUpgrade(ctx, name)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCancelLocalUpgrade.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).CancelLocalUpgrade(ctx, *req.(*upgradeApi.HandlerName))
+	}
+	return interceptor(ctx, &name, info, handler)
+}
+
 func handlerGetStatus(
 	srv any,
 	ctx context.Context,
@@ -265,6 +454,378 @@ func handlerAddBundle(
 	return interceptor(ctx, &path, info, handler)
 }
 
+func handlerSetLogLevel(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req SetLogLevelRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetLogLevel(ctx, req.Module, req.Level)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetLogLevel.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		r := req.(*SetLogLevelRequest)
+		return nil, srv.(NodeController).SetLogLevel(ctx, r.Module, r.Level)
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerSetTxPoolLanes(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req SetTxPoolLanesRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetTxPoolLanes(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetTxPoolLanes.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).SetTxPoolLanes(ctx, req.(*SetTxPoolLanesRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
+func handlerPauseRuntime(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).PauseRuntime(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodPauseRuntime.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).PauseRuntime(ctx, *req.(*common.Namespace))
+	}
+	return interceptor(ctx, &runtimeID, info, handler)
+}
+
+func handlerResumeRuntime(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var runtimeID common.Namespace
+	if err := dec(&runtimeID); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).ResumeRuntime(ctx, runtimeID)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodResumeRuntime.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).ResumeRuntime(ctx, *req.(*common.Namespace))
+	}
+	return interceptor(ctx, &runtimeID, info, handler)
+}
+
+func handlerSetGrpcAccessAllowlist(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var keys []signature.PublicKey
+	if err := dec(&keys); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetGrpcAccessAllowlist(ctx, keys)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetGrpcAccessAllowlist.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).SetGrpcAccessAllowlist(ctx, *req.(*[]signature.PublicKey))
+	}
+	return interceptor(ctx, &keys, info, handler)
+}
+
+func handlerAddIndexerToken(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var token storageWorker.IndexerToken
+	if err := dec(&token); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).AddIndexerToken(ctx, &token)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodAddIndexerToken.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).AddIndexerToken(ctx, req.(*storageWorker.IndexerToken))
+	}
+	return interceptor(ctx, &token, info, handler)
+}
+
+func handlerRevokeIndexerToken(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var name string
+	if err := dec(&name); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).RevokeIndexerToken(ctx, name)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRevokeIndexerToken.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).RevokeIndexerToken(ctx, *req.(*string))
+	}
+	return interceptor(ctx, &name, info, handler)
+}
+
+func handlerListIndexerTokens(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).ListIndexerTokens(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodListIndexerTokens.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).ListIndexerTokens(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetLogLevels(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetLogLevels(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetLogLevels.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).GetLogLevels(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetWorkerPoolLimits(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetWorkerPoolLimits(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetWorkerPoolLimits.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).GetWorkerPoolLimits(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerSetWorkerPoolLimits(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var limits workerpool.Limits
+	if err := dec(&limits); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).SetWorkerPoolLimits(ctx, &limits)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetWorkerPoolLimits.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).SetWorkerPoolLimits(ctx, req.(*workerpool.Limits))
+	}
+	return interceptor(ctx, &limits, info, handler)
+}
+
+func handlerUpdateRoles(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var roles node.RolesMask
+	if err := dec(&roles); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(NodeController).UpdateRoles(ctx, roles)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodUpdateRoles.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(NodeController).UpdateRoles(ctx, *req.(*node.RolesMask))
+	}
+	return interceptor(ctx, &roles, info, handler)
+}
+
+func handlerListCrashDumps(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).ListCrashDumps(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodListCrashDumps.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).ListCrashDumps(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetCrashDump(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var name string
+	if err := dec(&name); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetCrashDump(ctx, name)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetCrashDump.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(NodeController).GetCrashDump(ctx, *req.(*string))
+	}
+	return interceptor(ctx, &name, info, handler)
+}
+
+func handlerGetEventJournal(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq GetEventJournalRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetEventJournal(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEventJournal.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(NodeController).GetEventJournal(ctx, req.(*GetEventJournalRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerCreateDiagnosticsBundle(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(NodeController).CreateDiagnosticsBundle(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCreateDiagnosticsBundle.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(NodeController).CreateDiagnosticsBundle(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetDiagnosticsBundle(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var name string
+	if err := dec(&name); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeController).GetDiagnosticsBundle(ctx, name)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetDiagnosticsBundle.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(NodeController).GetDiagnosticsBundle(ctx, *req.(*string))
+	}
+	return interceptor(ctx, &name, info, handler)
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -310,6 +871,14 @@ func (c *NodeControllerClient) IsReady(ctx context.Context) (bool, error) {
 	return rsp, nil
 }
 
+func (c *NodeControllerClient) IsLive(ctx context.Context) (bool, error) {
+	var rsp bool
+	if err := c.conn.Invoke(ctx, methodIsLive.FullName(), nil, &rsp); err != nil {
+		return false, err
+	}
+	return rsp, nil
+}
+
 func (c *NodeControllerClient) UpgradeBinary(ctx context.Context, descriptor *upgradeApi.Descriptor) error {
 	return c.conn.Invoke(ctx, methodUpgradeBinary.FullName(), descriptor, nil)
 }
@@ -318,6 +887,18 @@ func (c *NodeControllerClient) CancelUpgrade(ctx context.Context, descriptor *up
 	return c.conn.Invoke(ctx, methodCancelUpgrade.FullName(), descriptor, nil)
 }
 
+func (c *NodeControllerClient) ListPendingUpgrades(ctx context.Context) ([]*upgradeApi.PendingUpgrade, error) {
+	var rsp []*upgradeApi.PendingUpgrade
+	if err := c.conn.Invoke(ctx, methodListPendingUpgrades.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) CancelLocalUpgrade(ctx context.Context, name upgradeApi.HandlerName) error {
+	return c.conn.Invoke(ctx, methodCancelLocalUpgrade.FullName(), name, nil)
+}
+
 func (c *NodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
 	var rsp Status
 	if err := c.conn.Invoke(ctx, methodGetStatus.FullName(), nil, &rsp); err != nil {
@@ -333,3 +914,107 @@ func (c *NodeControllerClient) AddBundle(ctx context.Context, path string) error
 	}
 	return nil
 }
+
+func (c *NodeControllerClient) SetLogLevel(ctx context.Context, module string, level string) error {
+	req := SetLogLevelRequest{
+		Module: module,
+		Level:  level,
+	}
+	return c.conn.Invoke(ctx, methodSetLogLevel.FullName(), req, nil)
+}
+
+func (c *NodeControllerClient) GetLogLevels(ctx context.Context) (map[string]string, error) {
+	var rsp map[string]string
+	if err := c.conn.Invoke(ctx, methodGetLogLevels.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) SetTxPoolLanes(ctx context.Context, req *SetTxPoolLanesRequest) error {
+	return c.conn.Invoke(ctx, methodSetTxPoolLanes.FullName(), req, nil)
+}
+
+func (c *NodeControllerClient) PauseRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodPauseRuntime.FullName(), runtimeID, nil)
+}
+
+func (c *NodeControllerClient) ResumeRuntime(ctx context.Context, runtimeID common.Namespace) error {
+	return c.conn.Invoke(ctx, methodResumeRuntime.FullName(), runtimeID, nil)
+}
+
+func (c *NodeControllerClient) SetGrpcAccessAllowlist(ctx context.Context, keys []signature.PublicKey) error {
+	return c.conn.Invoke(ctx, methodSetGrpcAccessAllowlist.FullName(), keys, nil)
+}
+
+func (c *NodeControllerClient) AddIndexerToken(ctx context.Context, token *storageWorker.IndexerToken) error {
+	return c.conn.Invoke(ctx, methodAddIndexerToken.FullName(), token, nil)
+}
+
+func (c *NodeControllerClient) RevokeIndexerToken(ctx context.Context, name string) error {
+	return c.conn.Invoke(ctx, methodRevokeIndexerToken.FullName(), name, nil)
+}
+
+func (c *NodeControllerClient) ListIndexerTokens(ctx context.Context) ([]*storageWorker.IndexerTokenInfo, error) {
+	var rsp []*storageWorker.IndexerTokenInfo
+	if err := c.conn.Invoke(ctx, methodListIndexerTokens.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) GetWorkerPoolLimits(ctx context.Context) (*workerpool.Limits, error) {
+	var rsp workerpool.Limits
+	if err := c.conn.Invoke(ctx, methodGetWorkerPoolLimits.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *NodeControllerClient) SetWorkerPoolLimits(ctx context.Context, limits *workerpool.Limits) error {
+	return c.conn.Invoke(ctx, methodSetWorkerPoolLimits.FullName(), limits, nil)
+}
+
+func (c *NodeControllerClient) UpdateRoles(ctx context.Context, roles node.RolesMask) error {
+	return c.conn.Invoke(ctx, methodUpdateRoles.FullName(), roles, nil)
+}
+
+func (c *NodeControllerClient) ListCrashDumps(ctx context.Context) ([]*crashdump.Info, error) {
+	var rsp []*crashdump.Info
+	if err := c.conn.Invoke(ctx, methodListCrashDumps.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) GetCrashDump(ctx context.Context, name string) (*crashdump.Dump, error) {
+	var rsp crashdump.Dump
+	if err := c.conn.Invoke(ctx, methodGetCrashDump.FullName(), name, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *NodeControllerClient) GetEventJournal(ctx context.Context, request *GetEventJournalRequest) ([]*journal.Event, error) {
+	var rsp []*journal.Event
+	if err := c.conn.Invoke(ctx, methodGetEventJournal.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *NodeControllerClient) CreateDiagnosticsBundle(ctx context.Context) (*diagnostics.Info, error) {
+	var rsp diagnostics.Info
+	if err := c.conn.Invoke(ctx, methodCreateDiagnosticsBundle.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *NodeControllerClient) GetDiagnosticsBundle(ctx context.Context, name string) ([]byte, error) {
+	var rsp []byte
+	if err := c.conn.Invoke(ctx, methodGetDiagnosticsBundle.FullName(), name, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
@@ -2,11 +2,16 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
 var (
@@ -15,8 +20,28 @@ var (
 
 	// methodSetEpoch is the SetEpoch method.
 	methodSetEpoch = debugServiceName.NewMethod("SetEpoch", beacon.EpochTime(0))
+	// methodSetEpochSchedule is the SetEpochSchedule method.
+	methodSetEpochSchedule = debugServiceName.NewMethod("SetEpochSchedule", beacon.EpochSchedule{})
+	// methodSetBlockTime is the SetBlockTime method.
+	methodSetBlockTime = debugServiceName.NewMethod("SetBlockTime", consensus.BlockTimeSchedule{})
 	// methodWaitNodesRegistered is the WaitNodesRegistered method.
 	methodWaitNodesRegistered = debugServiceName.NewMethod("WaitNodesRegistered", int(0))
+	// methodDryRunUpgrade is the DryRunUpgrade method.
+	methodDryRunUpgrade = debugServiceName.NewMethod("DryRunUpgrade", &upgrade.Descriptor{})
+	// methodRepairStorage is the RepairStorage method.
+	methodRepairStorage = debugServiceName.NewMethod("RepairStorage", &storageWorker.RepairRequest{})
+	// methodExportRuntimeState is the ExportRuntimeState method.
+	methodExportRuntimeState = debugServiceName.NewMethod("ExportRuntimeState", &storageWorker.ExportStateRequest{})
+	// methodGetRuntimeStateStats is the GetRuntimeStateStats method.
+	methodGetRuntimeStateStats = debugServiceName.NewMethod("GetRuntimeStateStats", &storageWorker.StateStatsRequest{})
+	// methodSetRegistrationFault is the SetRegistrationFault method.
+	methodSetRegistrationFault = debugServiceName.NewMethod("SetRegistrationFault", false)
+	// methodSetExecutorCommitDelayFault is the SetExecutorCommitDelayFault method.
+	methodSetExecutorCommitDelayFault = debugServiceName.NewMethod("SetExecutorCommitDelayFault", time.Duration(0))
+	// methodSetCommitteeMessageDropFault is the SetCommitteeMessageDropFault method.
+	methodSetCommitteeMessageDropFault = debugServiceName.NewMethod("SetCommitteeMessageDropFault", uint8(0))
+	// methodSetCheckpointServingFault is the SetCheckpointServingFault method.
+	methodSetCheckpointServingFault = debugServiceName.NewMethod("SetCheckpointServingFault", false)
 
 	// debugServiceDesc is the gRPC service descriptor.
 	debugServiceDesc = grpc.ServiceDesc{
@@ -27,10 +52,50 @@ var (
 				MethodName: methodSetEpoch.ShortName(),
 				Handler:    handlerSetEpoch,
 			},
+			{
+				MethodName: methodSetEpochSchedule.ShortName(),
+				Handler:    handlerSetEpochSchedule,
+			},
+			{
+				MethodName: methodSetBlockTime.ShortName(),
+				Handler:    handlerSetBlockTime,
+			},
 			{
 				MethodName: methodWaitNodesRegistered.ShortName(),
 				Handler:    handlerWaitNodesRegistered,
 			},
+			{
+				MethodName: methodDryRunUpgrade.ShortName(),
+				Handler:    handlerDryRunUpgrade,
+			},
+			{
+				MethodName: methodRepairStorage.ShortName(),
+				Handler:    handlerRepairStorage,
+			},
+			{
+				MethodName: methodExportRuntimeState.ShortName(),
+				Handler:    handlerExportRuntimeState,
+			},
+			{
+				MethodName: methodGetRuntimeStateStats.ShortName(),
+				Handler:    handlerGetRuntimeStateStats,
+			},
+			{
+				MethodName: methodSetRegistrationFault.ShortName(),
+				Handler:    handlerSetRegistrationFault,
+			},
+			{
+				MethodName: methodSetExecutorCommitDelayFault.ShortName(),
+				Handler:    handlerSetExecutorCommitDelayFault,
+			},
+			{
+				MethodName: methodSetCommitteeMessageDropFault.ShortName(),
+				Handler:    handlerSetCommitteeMessageDropFault,
+			},
+			{
+				MethodName: methodSetCheckpointServingFault.ShortName(),
+				Handler:    handlerSetCheckpointServingFault,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -59,6 +124,53 @@ func handlerSetEpoch(
 	return interceptor(ctx, epoch, info, handler)
 }
 
+func handlerSetEpochSchedule(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var schedule beacon.EpochSchedule
+	if err := dec(&schedule); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetEpochSchedule(ctx, &schedule)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetEpochSchedule.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(DebugController).SetEpochSchedule(ctx, req.(*beacon.EpochSchedule))
+	}
+	return interceptor(ctx, &schedule, info, handler)
+}
+
+func handlerSetBlockTime(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var schedule consensus.BlockTimeSchedule
+	if err := dec(&schedule); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetBlockTime(ctx, schedule.Base, schedule.Step)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetBlockTime.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		rq := req.(*consensus.BlockTimeSchedule)
+		return nil, srv.(DebugController).SetBlockTime(ctx, rq.Base, rq.Step)
+	}
+	return interceptor(ctx, &schedule, info, handler)
+}
+
 func handlerWaitNodesRegistered(
 	srv any,
 	ctx context.Context,
@@ -82,6 +194,190 @@ func handlerWaitNodesRegistered(
 	return interceptor(ctx, count, info, handler)
 }
 
+func handlerDryRunUpgrade(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var descriptor upgrade.Descriptor
+	if err := dec(&descriptor); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugController).DryRunUpgrade(ctx, &descriptor)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDryRunUpgrade.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugController).DryRunUpgrade(ctx, req.(*upgrade.Descriptor))
+	}
+	return interceptor(ctx, &descriptor, info, handler)
+}
+
+func handlerRepairStorage(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq storageWorker.RepairRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugController).RepairStorage(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodRepairStorage.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugController).RepairStorage(ctx, req.(*storageWorker.RepairRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerExportRuntimeState(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq storageWorker.ExportStateRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugController).ExportRuntimeState(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodExportRuntimeState.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugController).ExportRuntimeState(ctx, req.(*storageWorker.ExportStateRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerGetRuntimeStateStats(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq storageWorker.StateStatsRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugController).GetRuntimeStateStats(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRuntimeStateStats.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(DebugController).GetRuntimeStateStats(ctx, req.(*storageWorker.StateStatsRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
+func handlerSetRegistrationFault(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var stopped bool
+	if err := dec(&stopped); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetRegistrationFault(ctx, stopped)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetRegistrationFault.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(DebugController).SetRegistrationFault(ctx, req.(bool))
+	}
+	return interceptor(ctx, stopped, info, handler)
+}
+
+func handlerSetExecutorCommitDelayFault(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var delay time.Duration
+	if err := dec(&delay); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetExecutorCommitDelayFault(ctx, delay)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetExecutorCommitDelayFault.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(DebugController).SetExecutorCommitDelayFault(ctx, req.(time.Duration))
+	}
+	return interceptor(ctx, delay, info, handler)
+}
+
+func handlerSetCommitteeMessageDropFault(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var percent uint8
+	if err := dec(&percent); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetCommitteeMessageDropFault(ctx, percent)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetCommitteeMessageDropFault.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(DebugController).SetCommitteeMessageDropFault(ctx, req.(uint8))
+	}
+	return interceptor(ctx, percent, info, handler)
+}
+
+func handlerSetCheckpointServingFault(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var paused bool
+	if err := dec(&paused); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetCheckpointServingFault(ctx, paused)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetCheckpointServingFault.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(DebugController).SetCheckpointServingFault(ctx, req.(bool))
+	}
+	return interceptor(ctx, paused, info, handler)
+}
+
 // RegisterDebugService registers a new debug controller service with the given gRPC server.
 func RegisterDebugService(server *grpc.Server, service DebugController) {
 	server.RegisterService(&debugServiceDesc, service)
@@ -103,6 +399,66 @@ func (c *DebugControllerClient) SetEpoch(ctx context.Context, epoch beacon.Epoch
 	return c.conn.Invoke(ctx, methodSetEpoch.FullName(), epoch, nil)
 }
 
+func (c *DebugControllerClient) SetEpochSchedule(ctx context.Context, schedule *beacon.EpochSchedule) error {
+	return c.conn.Invoke(ctx, methodSetEpochSchedule.FullName(), schedule, nil)
+}
+
+func (c *DebugControllerClient) SetBlockTime(ctx context.Context, base time.Time, step time.Duration) error {
+	schedule := consensus.BlockTimeSchedule{
+		Base: base,
+		Step: step,
+	}
+	return c.conn.Invoke(ctx, methodSetBlockTime.FullName(), &schedule, nil)
+}
+
 func (c *DebugControllerClient) WaitNodesRegistered(ctx context.Context, count int) error {
 	return c.conn.Invoke(ctx, methodWaitNodesRegistered.FullName(), count, nil)
 }
+
+func (c *DebugControllerClient) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error) {
+	var rsp upgrade.DryRunResult
+	if err := c.conn.Invoke(ctx, methodDryRunUpgrade.FullName(), descriptor, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *DebugControllerClient) RepairStorage(ctx context.Context, request *storageWorker.RepairRequest) (*storageWorker.RepairResult, error) {
+	var rsp storageWorker.RepairResult
+	if err := c.conn.Invoke(ctx, methodRepairStorage.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *DebugControllerClient) ExportRuntimeState(ctx context.Context, request *storageWorker.ExportStateRequest) (*storageWorker.ExportStateResult, error) {
+	var rsp storageWorker.ExportStateResult
+	if err := c.conn.Invoke(ctx, methodExportRuntimeState.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *DebugControllerClient) GetRuntimeStateStats(ctx context.Context, request *storageWorker.StateStatsRequest) (*mkvs.TreeStats, error) {
+	var rsp mkvs.TreeStats
+	if err := c.conn.Invoke(ctx, methodGetRuntimeStateStats.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *DebugControllerClient) SetRegistrationFault(ctx context.Context, stopped bool) error {
+	return c.conn.Invoke(ctx, methodSetRegistrationFault.FullName(), stopped, nil)
+}
+
+func (c *DebugControllerClient) SetExecutorCommitDelayFault(ctx context.Context, delay time.Duration) error {
+	return c.conn.Invoke(ctx, methodSetExecutorCommitDelayFault.FullName(), delay, nil)
+}
+
+func (c *DebugControllerClient) SetCommitteeMessageDropFault(ctx context.Context, percent uint8) error {
+	return c.conn.Invoke(ctx, methodSetCommitteeMessageDropFault.FullName(), percent, nil)
+}
+
+func (c *DebugControllerClient) SetCheckpointServingFault(ctx context.Context, paused bool) error {
+	return c.conn.Invoke(ctx, methodSetCheckpointServingFault.FullName(), paused, nil)
+}
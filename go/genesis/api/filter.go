@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// FilterableServices are the services whose state can be omitted from a genesis document dump.
+//
+// The remaining sections (beacon, scheduler, consensus) only contain parameters and are always
+// included.
+var FilterableServices = []string{
+	registry.ModuleName,
+	roothash.ModuleName,
+	staking.ModuleName,
+	keymanager.ModuleName,
+	governance.ModuleName,
+	vault.ModuleName,
+}
+
+// filterDependencies are the services whose state must be included in case the state of the
+// given service is included.
+var filterDependencies = map[string][]string{
+	// Entity and node stake claims can only be satisfied by the staking ledger.
+	registry.ModuleName: {staking.ModuleName},
+}
+
+// DumpFilter is a filter applied to the genesis document when dumping state.
+type DumpFilter struct {
+	// Services is the list of services whose state should be included. In case the list is
+	// empty, the state of all services is included.
+	//
+	// For the omitted services only the consensus parameters are retained.
+	Services []string `json:"services,omitempty"`
+
+	// DropRuntimeStates specifies whether the roothash runtime states should be omitted.
+	DropRuntimeStates bool `json:"drop_runtime_states,omitempty"`
+
+	// AddressRemap is a map of account addresses that should be replaced in the staking ledger,
+	// delegations, allowances and governance state.
+	AddressRemap map[staking.Address]staking.Address `json:"address_remap,omitempty"`
+}
+
+// IsEmpty returns true iff the filter does not modify the genesis document.
+func (f *DumpFilter) IsEmpty() bool {
+	return f == nil || (len(f.Services) == 0 && !f.DropRuntimeStates && len(f.AddressRemap) == 0)
+}
+
+// ValidateBasic performs basic filter validity checks.
+func (f *DumpFilter) ValidateBasic() error {
+	for _, svc := range f.Services {
+		if !slices.Contains(FilterableServices, svc) {
+			return fmt.Errorf("genesis: invalid dump filter: unknown service: %s", svc)
+		}
+		for _, dep := range filterDependencies[svc] {
+			if !slices.Contains(f.Services, dep) {
+				return fmt.Errorf("genesis: invalid dump filter: service %s requires service %s", svc, dep)
+			}
+		}
+	}
+
+	targets := make(map[staking.Address]bool, len(f.AddressRemap))
+	for from, to := range f.AddressRemap {
+		if !from.IsValid() || !to.IsValid() {
+			return fmt.Errorf("genesis: invalid dump filter: invalid address remap: %s -> %s", from, to)
+		}
+		if from.IsReserved() || to.IsReserved() {
+			return fmt.Errorf("genesis: invalid dump filter: reserved address remap: %s -> %s", from, to)
+		}
+		if targets[to] {
+			return fmt.Errorf("genesis: invalid dump filter: duplicate address remap target: %s", to)
+		}
+		targets[to] = true
+	}
+	return nil
+}
+
+// includes returns true iff the state of the given service should be included.
+func (f *DumpFilter) includes(svc string) bool {
+	return len(f.Services) == 0 || slices.Contains(f.Services, svc)
+}
+
+// Apply applies the filter to the given genesis document.
+//
+// The omitted sections are reset to empty defaults and the token accounting is adjusted so that
+// the resulting document still passes the sanity checks.
+func (f *DumpFilter) Apply(d *Document) error {
+	if err := f.ValidateBasic(); err != nil {
+		return err
+	}
+	d.cachedHash = nil
+
+	if !f.includes(registry.ModuleName) {
+		d.Registry = registry.Genesis{
+			Parameters: d.Registry.Parameters,
+		}
+	}
+	if !f.includes(roothash.ModuleName) || f.DropRuntimeStates {
+		d.RootHash.RuntimeStates = nil
+	}
+	if !f.includes(keymanager.ModuleName) {
+		d.KeyManager.Statuses = nil
+		if d.KeyManager.Churp != nil {
+			d.KeyManager.Churp.Statuses = nil
+		}
+	}
+	if !f.includes(governance.ModuleName) {
+		d.Governance = governance.Genesis{
+			Parameters: d.Governance.Parameters,
+		}
+
+		// Return the deposits of the omitted proposals to the common pool.
+		if err := d.Staking.CommonPool.Add(&d.Staking.GovernanceDeposits); err != nil {
+			return fmt.Errorf("genesis: failed to return governance deposits: %w", err)
+		}
+		d.Staking.GovernanceDeposits = quantity.Quantity{}
+	}
+	if !f.includes(vault.ModuleName) && d.Vault != nil {
+		d.Vault = &vault.Genesis{
+			Parameters: d.Vault.Parameters,
+		}
+	}
+	if !f.includes(staking.ModuleName) {
+		d.Staking.Ledger = nil
+		d.Staking.Delegations = nil
+		d.Staking.DebondingDelegations = nil
+
+		// The total supply is only what remains outside of the ledger.
+		total := d.Staking.CommonPool.Clone()
+		if err := total.Add(&d.Staking.LastBlockFees); err != nil {
+			return fmt.Errorf("genesis: failed to compute total supply: %w", err)
+		}
+		if err := total.Add(&d.Staking.GovernanceDeposits); err != nil {
+			return fmt.Errorf("genesis: failed to compute total supply: %w", err)
+		}
+		d.Staking.TotalSupply = *total
+	}
+
+	if len(f.AddressRemap) > 0 {
+		if err := f.remapAddresses(d); err != nil {
+			return err
+		}
+	}
+
+	d.Canonicalize()
+
+	return nil
+}
+
+func (f *DumpFilter) remap(addr staking.Address) staking.Address {
+	if to, ok := f.AddressRemap[addr]; ok {
+		return to
+	}
+	return addr
+}
+
+func (f *DumpFilter) remapAddresses(d *Document) error {
+	var err error
+	st := &d.Staking
+	if st.Ledger, err = remapMap(f, st.Ledger); err != nil {
+		return fmt.Errorf("genesis: failed to remap ledger: %w", err)
+	}
+	for _, acct := range st.Ledger {
+		if acct.General.Allowances, err = remapMap(f, acct.General.Allowances); err != nil {
+			return fmt.Errorf("genesis: failed to remap allowances: %w", err)
+		}
+	}
+	if st.Delegations, err = remapMap(f, st.Delegations); err != nil {
+		return fmt.Errorf("genesis: failed to remap delegations: %w", err)
+	}
+	for addr, delegations := range st.Delegations {
+		if st.Delegations[addr], err = remapMap(f, delegations); err != nil {
+			return fmt.Errorf("genesis: failed to remap delegations: %w", err)
+		}
+	}
+	if st.DebondingDelegations, err = remapMap(f, st.DebondingDelegations); err != nil {
+		return fmt.Errorf("genesis: failed to remap debonding delegations: %w", err)
+	}
+	for addr, delegations := range st.DebondingDelegations {
+		if st.DebondingDelegations[addr], err = remapMap(f, delegations); err != nil {
+			return fmt.Errorf("genesis: failed to remap debonding delegations: %w", err)
+		}
+	}
+
+	for _, p := range d.Governance.Proposals {
+		p.Submitter = f.remap(p.Submitter)
+	}
+	for _, entries := range d.Governance.VoteEntries {
+		for _, e := range entries {
+			e.Voter = f.remap(e.Voter)
+		}
+	}
+	return nil
+}
+
+// remapMap returns a copy of the given map with remapped keys.
+func remapMap[V any](f *DumpFilter, m map[staking.Address]V) (map[staking.Address]V, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	remapped := make(map[staking.Address]V, len(m))
+	for addr, v := range m {
+		to := f.remap(addr)
+		if _, ok := remapped[to]; ok {
+			return nil, fmt.Errorf("address remap collision: %s", to)
+		}
+		remapped[to] = v
+	}
+	return remapped, nil
+}
+
+// Canonicalize sorts all lists in the genesis document whose order is not significant so that
+// documents with the same state have the same encoding.
+func (d *Document) Canonicalize() {
+	d.cachedHash = nil
+
+	slices.SortStableFunc(d.Registry.Entities, func(a, b *entity.SignedEntity) int {
+		return bytes.Compare(a.Signature.PublicKey[:], b.Signature.PublicKey[:])
+	})
+	compareRuntimes := func(a, b *registry.Runtime) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	}
+	slices.SortStableFunc(d.Registry.Runtimes, compareRuntimes)
+	slices.SortStableFunc(d.Registry.SuspendedRuntimes, compareRuntimes)
+	nodeIDs := make(map[*node.MultiSignedNode][]byte, len(d.Registry.Nodes))
+	for _, n := range d.Registry.Nodes {
+		nodeIDs[n] = multiSignedNodeID(n)
+	}
+	slices.SortStableFunc(d.Registry.Nodes, func(a, b *node.MultiSignedNode) int {
+		return bytes.Compare(nodeIDs[a], nodeIDs[b])
+	})
+
+	slices.SortStableFunc(d.KeyManager.Statuses, func(a, b *secrets.Status) int {
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	if d.KeyManager.Churp != nil {
+		slices.SortStableFunc(d.KeyManager.Churp.Statuses, func(a, b *churp.Status) int {
+			if c := bytes.Compare(a.RuntimeID[:], b.RuntimeID[:]); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+
+	slices.SortStableFunc(d.Governance.Proposals, func(a, b *governance.Proposal) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, entries := range d.Governance.VoteEntries {
+		slices.SortStableFunc(entries, func(a, b *governance.VoteEntry) int {
+			return bytes.Compare(a.Voter[:], b.Voter[:])
+		})
+	}
+
+	if d.Vault != nil {
+		slices.SortStableFunc(d.Vault.Vaults, func(a, b *vault.Vault) int {
+			if c := bytes.Compare(a.Creator[:], b.Creator[:]); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+
+	// Note: Debonding delegations and vault pending actions are ordered by time and are kept as is.
+}
+
+// multiSignedNodeID returns the raw node identifier of the given signed node descriptor or nil
+// in case the descriptor is malformed.
+func multiSignedNodeID(sn *node.MultiSignedNode) []byte {
+	var n node.Node
+	if err := cbor.Unmarshal(sn.Blob, &n); err != nil {
+		return nil
+	}
+	return n.ID[:]
+}
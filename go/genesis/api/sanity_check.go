@@ -4,22 +4,62 @@ import (
 	"fmt"
 	"strings"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
 // SanityCheck does basic sanity checking on the contents of the genesis document.
 func (d *Document) SanityCheck() error {
+	pkBlacklist, epoch, err := d.sanityCheckBase()
+	if err != nil {
+		return err
+	}
+
+	escrows := make(map[staking.Address]*staking.EscrowAccount)
+
+	if err := d.Registry.SanityCheck(
+		d.Time,
+		uint64(d.Height),
+		epoch,
+		pkBlacklist,
+		escrows,
+	); err != nil {
+		return err
+	}
+	if err := d.RootHash.SanityCheck(); err != nil {
+		return err
+	}
+	if err := d.Staking.SanityCheck(epoch); err != nil {
+		return err
+	}
+	if err := d.sanityCheckOther(epoch); err != nil {
+		return err
+	}
+
+	if d.Staking.Parameters.DebugBypassStake {
+		return nil
+	}
+	return staking.SanityCheckStake(d.Staking.Ledger, escrows, d.Staking.Parameters.Thresholds, true)
+}
+
+// sanityCheckBase checks the top-level fields and the consensus and beacon sections, returning
+// the public key blacklist and the base epoch needed to check the other sections.
+func (d *Document) sanityCheckBase() (map[signature.PublicKey]bool, beacon.EpochTime, error) {
 	if d.Height < 1 {
-		return fmt.Errorf("genesis: sanity check failed: height must be >= 1")
+		return nil, 0, fmt.Errorf("genesis: sanity check failed: height must be >= 1")
 	}
 
 	if strings.TrimSpace(d.ChainID) == "" {
-		return fmt.Errorf("genesis: sanity check failed: chain ID must not be empty")
+		return nil, 0, fmt.Errorf("genesis: sanity check failed: chain ID must not be empty")
 	}
 
 	if err := d.Consensus.SanityCheck(); err != nil {
-		return err
+		return nil, 0, err
 	}
 	pkBlacklist := make(map[signature.PublicKey]bool)
 	for _, v := range d.Consensus.Parameters.PublicKeyBlacklist {
@@ -27,13 +67,52 @@ func (d *Document) SanityCheck() error {
 	}
 
 	if err := d.Beacon.SanityCheck(); err != nil {
-		return err
+		return nil, 0, err
 	}
 	epoch := d.Beacon.Base // Note: d.Height has no easy connection to the epoch.
 
+	return pkBlacklist, epoch, nil
+}
+
+// sanityCheckOther checks the key manager, scheduler, governance and vault sections.
+func (d *Document) sanityCheckOther(epoch beacon.EpochTime) error {
+	if err := d.KeyManager.SanityCheck(); err != nil {
+		return err
+	}
+	if err := d.Scheduler.SanityCheck(&d.Staking.TotalSupply, d.Scheduler.Parameters.VotingPowerDistribution); err != nil {
+		return err
+	}
+	if err := d.Governance.SanityCheck(epoch, &d.Staking.GovernanceDeposits); err != nil {
+		return err
+	}
+	return d.Vault.SanityCheck()
+}
+
+// SanityCheck does basic sanity checking on the contents of the genesis document.
+//
+// The checks are the same as the ones performed by Document.SanityCheck, but the staking ledger,
+// delegations and roothash runtime states are only decoded one entry at a time. Registry nodes
+// are decoded into memory as they need to be cross-checked against each other.
+func (ds *DocumentStream) SanityCheck() error {
+	d := &ds.header
+	pkBlacklist, epoch, err := d.sanityCheckBase()
+	if err != nil {
+		return err
+	}
+
 	escrows := make(map[staking.Address]*staking.EscrowAccount)
 
-	if err := d.Registry.SanityCheck(
+	reg := d.Registry
+	if ds.nodes != nil {
+		reg.Nodes = make([]*node.MultiSignedNode, 0, len(ds.nodes))
+		if err = ds.forEachNode(func(n *node.MultiSignedNode) error {
+			reg.Nodes = append(reg.Nodes, n)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if err = reg.SanityCheck(
 		d.Time,
 		uint64(d.Height),
 		epoch,
@@ -42,27 +121,113 @@ func (d *Document) SanityCheck() error {
 	); err != nil {
 		return err
 	}
-	if err := d.RootHash.SanityCheck(); err != nil {
+	reg.Nodes = nil // Release the nodes as they are no longer needed.
+
+	if err = d.RootHash.SanityCheck(); err != nil {
 		return err
 	}
-	if err := d.Staking.SanityCheck(epoch); err != nil {
+	if err = forEachEntry(ds, ds.runtimeStates, func(_ common.Namespace, rtg *roothash.GenesisRuntimeState) error {
+		return rtg.SanityCheck(true)
+	}); err != nil {
 		return err
 	}
-	if err := d.KeyManager.SanityCheck(); err != nil {
+	if err = ds.sanityCheckStaking(epoch); err != nil {
 		return err
 	}
-	if err := d.Scheduler.SanityCheck(&d.Staking.TotalSupply, d.Scheduler.Parameters.VotingPowerDistribution); err != nil {
+	if err = d.sanityCheckOther(epoch); err != nil {
 		return err
 	}
-	if err := d.Governance.SanityCheck(epoch, &d.Staking.GovernanceDeposits); err != nil {
+
+	if d.Staking.Parameters.DebugBypassStake {
+		return nil
+	}
+
+	// Only the accounts with stake claims are needed to check the stake.
+	accounts := make(map[staking.Address]*staking.Account, len(escrows))
+	for addr := range escrows {
+		acct, err := lookupEntry[staking.Address, *staking.Account](ds, ds.ledger, addr)
+		if err != nil {
+			return err
+		}
+		if acct != nil {
+			accounts[addr] = acct
+		}
+	}
+	return staking.SanityCheckStake(accounts, escrows, d.Staking.Parameters.Thresholds, true)
+}
+
+func (ds *DocumentStream) sanityCheckStaking(now beacon.EpochTime) error {
+	st := &ds.header.Staking
+	if err := st.SanityCheckHeader(); err != nil {
+		return err
+	}
+
+	// Check if the total supply adds up:
+	// common pool + last block fees + all balances in the ledger.
+	// Check all commission schedules.
+	var total quantity.Quantity
+	if err := forEachEntry(ds, ds.ledger, func(addr staking.Address, acct *staking.Account) error {
+		return st.SanityCheckGenesisAccount(&total, now, addr, acct)
+	}); err != nil {
 		return err
 	}
-	if err := d.Vault.SanityCheck(); err != nil {
+	if err := st.SanityCheckTotalSupply(total); err != nil {
 		return err
 	}
 
-	if d.Staking.Parameters.DebugBypassStake {
-		return nil
+	// All shares of all delegations for a given account must add up to account's Escrow.Active.TotalShares.
+	if err := forEachEntry(ds, ds.delegations, func(addr staking.Address, delegations map[staking.Address]*staking.Delegation) error {
+		acct, err := lookupEntry[staking.Address, *staking.Account](ds, ds.ledger, addr)
+		if err != nil {
+			return err
+		}
+		if acct == nil {
+			return fmt.Errorf(
+				"staking: sanity check failed: delegation specified for a nonexisting account: %v",
+				addr,
+			)
+		}
+		return staking.SanityCheckDelegations(addr, acct, delegations)
+	}); err != nil {
+		return err
 	}
-	return staking.SanityCheckStake(d.Staking.Ledger, escrows, d.Staking.Parameters.Thresholds, true)
+
+	// All shares of all debonding delegations for a given account must add up to account's Escrow.Debonding.TotalShares.
+	if err := forEachEntry(ds, ds.debondingDelegations, func(addr staking.Address, delegations map[staking.Address][]*staking.DebondingDelegation) error {
+		acct, err := lookupEntry[staking.Address, *staking.Account](ds, ds.ledger, addr)
+		if err != nil {
+			return err
+		}
+		if acct == nil {
+			return fmt.Errorf(
+				"staking: sanity check failed: debonding delegation specified for a nonexisting account: %v", addr,
+			)
+		}
+		return staking.SanityCheckDebondingDelegations(addr, acct, delegations)
+	}); err != nil {
+		return err
+	}
+
+	ba, err := lookupEntry[staking.Address, *staking.Account](ds, ds.ledger, staking.BurnAddress)
+	if err != nil {
+		return err
+	}
+	if ba != nil {
+		if err = staking.SanityCheckBurnAccount(ba); err != nil {
+			return err
+		}
+	}
+
+	// Check the above two invariants for each account as well.
+	return forEachEntry(ds, ds.ledger, func(addr staking.Address, acct *staking.Account) error {
+		delegations, err := lookupEntry[staking.Address, map[staking.Address]*staking.Delegation](ds, ds.delegations, addr)
+		if err != nil {
+			return err
+		}
+		debondingDelegations, err := lookupEntry[staking.Address, map[staking.Address][]*staking.DebondingDelegation](ds, ds.debondingDelegations, addr)
+		if err != nil {
+			return err
+		}
+		return staking.SanityCheckAccountShares(addr, acct, delegations, debondingDelegations)
+	})
 }
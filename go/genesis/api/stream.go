@@ -0,0 +1,389 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// streamEntry is the location of an encoded entry of a large genesis document section.
+type streamEntry struct {
+	offset int64
+	length int64
+}
+
+// DocumentStream is a JSON-encoded genesis document whose large sections (registry nodes,
+// staking ledger and delegations, roothash runtime states) are not kept in memory. Instead,
+// only the locations of their entries are indexed and the entries are decoded one at a time
+// when needed, so that the document can be validated and hashed with bounded memory.
+type DocumentStream struct {
+	r io.ReaderAt
+
+	// header is the genesis document without the large sections.
+	header Document
+
+	nodes                []streamEntry
+	ledger               map[staking.Address]streamEntry
+	delegations          map[staking.Address]streamEntry
+	debondingDelegations map[staking.Address]streamEntry
+	runtimeStates        map[common.Namespace]streamEntry
+
+	cachedHash *hash.Hash
+}
+
+// NewDocumentStream creates a new genesis document stream from the given JSON-encoded genesis
+// document of the given size.
+//
+// The reader must remain valid and unmodified for the lifetime of the stream.
+func NewDocumentStream(r io.ReaderAt, size int64) (*DocumentStream, error) {
+	ds := &DocumentStream{
+		r: r,
+	}
+
+	dec := json.NewDecoder(io.NewSectionReader(r, 0, size))
+	if err := ds.index(dec); err != nil {
+		return nil, fmt.Errorf("genesis: malformed genesis document: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("genesis: malformed genesis document: trailing data")
+	}
+
+	return ds, nil
+}
+
+// Close closes the underlying reader in case it implements io.Closer.
+func (ds *DocumentStream) Close() error {
+	if c, ok := ds.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Header returns the genesis document without the large sections.
+//
+// The returned document shares the small sections with the stream and must not be modified.
+func (ds *DocumentStream) Header() *Document {
+	header := ds.header
+	return &header
+}
+
+// Document decodes the complete genesis document into memory.
+//
+// The returned document shares the small sections with the stream and must not be modified.
+func (ds *DocumentStream) Document() (*Document, error) {
+	doc := ds.header
+
+	var err error
+	if ds.nodes != nil {
+		doc.Registry.Nodes = make([]*node.MultiSignedNode, 0, len(ds.nodes))
+		if err = ds.forEachNode(func(n *node.MultiSignedNode) error {
+			doc.Registry.Nodes = append(doc.Registry.Nodes, n)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if doc.Staking.Ledger, err = readEntries[staking.Address, *staking.Account](ds, ds.ledger); err != nil {
+		return nil, err
+	}
+	if doc.Staking.Delegations, err = readEntries[staking.Address, map[staking.Address]*staking.Delegation](ds, ds.delegations); err != nil {
+		return nil, err
+	}
+	if doc.Staking.DebondingDelegations, err = readEntries[staking.Address, map[staking.Address][]*staking.DebondingDelegation](ds, ds.debondingDelegations); err != nil {
+		return nil, err
+	}
+	if doc.RootHash.RuntimeStates, err = readEntries[common.Namespace, *roothash.GenesisRuntimeState](ds, ds.runtimeStates); err != nil {
+		return nil, err
+	}
+
+	if ds.cachedHash != nil {
+		h := *ds.cachedHash
+		doc.cachedHash = &h
+	}
+
+	return &doc, nil
+}
+
+// Hash returns the cryptographic hash of the encoded genesis document.
+//
+// The hash is computed incrementally and is the same as the one returned by Document.Hash.
+func (ds *DocumentStream) Hash() (hash.Hash, error) {
+	if ds.cachedHash != nil {
+		return *ds.cachedHash, nil
+	}
+
+	b := hash.NewBuilder()
+	if err := ds.writeCBOR(b); err != nil {
+		return hash.Hash{}, fmt.Errorf("genesis: failed to hash genesis document: %w", err)
+	}
+	h := b.Build()
+	ds.cachedHash = &h
+
+	return h, nil
+}
+
+// ChainContext returns a string that can be used as a chain domain separation context.
+//
+// See Document.ChainContext for details.
+func (ds *DocumentStream) ChainContext() (string, error) {
+	h, err := ds.Hash()
+	if err != nil {
+		return "", err
+	}
+	return h.Hex(), nil
+}
+
+func (ds *DocumentStream) index(dec *json.Decoder) error {
+	_, err := decodeObject(dec, func(key string) error {
+		switch key {
+		case "registry":
+			return decodeSection(dec, &ds.header.Registry, map[string]func() error{
+				"nodes": func() error { return indexArray(dec, &ds.nodes) },
+			})
+		case "staking":
+			return decodeSection(dec, &ds.header.Staking, map[string]func() error{
+				"ledger":                func() error { return indexMap(dec, &ds.ledger) },
+				"delegations":           func() error { return indexMap(dec, &ds.delegations) },
+				"debonding_delegations": func() error { return indexMap(dec, &ds.debondingDelegations) },
+			})
+		case "roothash":
+			return decodeSection(dec, &ds.header.RootHash, map[string]func() error{
+				"runtime_states": func() error { return indexMap(dec, &ds.runtimeStates) },
+			})
+		default:
+			return decodeField(dec, &ds.header, key)
+		}
+	})
+	return err
+}
+
+// readEntry decodes the given entry of a large section.
+func (ds *DocumentStream) readEntry(e streamEntry, v any) error {
+	buf := make([]byte, e.length)
+	if _, err := io.ReadFull(io.NewSectionReader(ds.r, e.offset, e.length), buf); err != nil {
+		return fmt.Errorf("genesis: failed to read genesis document: %w", err)
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return fmt.Errorf("genesis: malformed genesis document: %w", err)
+	}
+	return nil
+}
+
+func (ds *DocumentStream) forEachNode(fn func(*node.MultiSignedNode) error) error {
+	for _, e := range ds.nodes {
+		var n *node.MultiSignedNode
+		if err := ds.readEntry(e, &n); err != nil {
+			return err
+		}
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupEntry decodes the entry with the given key, returning the zero value in case there is
+// no such entry.
+func lookupEntry[K comparable, V any](ds *DocumentStream, entries map[K]streamEntry, key K) (V, error) {
+	var v V
+	e, ok := entries[key]
+	if !ok {
+		return v, nil
+	}
+	err := ds.readEntry(e, &v)
+	return v, err
+}
+
+// forEachEntry decodes the given entries one at a time and calls fn on each of them.
+func forEachEntry[K comparable, V any](ds *DocumentStream, entries map[K]streamEntry, fn func(K, V) error) error {
+	for k, e := range entries {
+		var v V
+		if err := ds.readEntry(e, &v); err != nil {
+			return err
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readEntries decodes all of the given entries into memory.
+func readEntries[K comparable, V any](ds *DocumentStream, entries map[K]streamEntry) (map[K]V, error) {
+	if entries == nil {
+		return nil, nil
+	}
+
+	m := make(map[K]V, len(entries))
+	if err := forEachEntry(ds, entries, func(k K, v V) error {
+		m[k] = v
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeObject decodes a JSON object token by token, calling fn for each key, which must consume
+// the corresponding value. Returns false in case the object is null.
+func decodeObject(dec *json.Decoder, fn func(key string) error) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	switch tok {
+	case nil:
+		return false, nil
+	case json.Delim('{'):
+	default:
+		return false, fmt.Errorf("expected object, got: %v", tok)
+	}
+
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return false, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return false, fmt.Errorf("expected object key, got: %v", tok)
+		}
+		if err = fn(key); err != nil {
+			return false, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	// Consume the closing delimiter.
+	if _, err = dec.Token(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// decodeSection decodes a genesis document section into the given struct, calling the given
+// index functions instead of decoding the corresponding large fields.
+func decodeSection(dec *json.Decoder, section any, large map[string]func() error) error {
+	_, err := decodeObject(dec, func(key string) error {
+		if index, ok := large[key]; ok {
+			return index()
+		}
+		return decodeField(dec, section, key)
+	})
+	return err
+}
+
+// decodeField decodes the next value into the field of the given struct matching the key the
+// same way encoding/json does. Values of unknown fields are skipped.
+func decodeField(dec *json.Decoder, v any, key string) error {
+	s := reflect.ValueOf(v).Elem()
+	t := s.Type()
+
+	var field reflect.Value
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		if name == key {
+			field = s.Field(i)
+			break
+		}
+		if !field.IsValid() && strings.EqualFold(name, key) {
+			field = s.Field(i)
+		}
+	}
+	if !field.IsValid() {
+		var skip json.RawMessage
+		return dec.Decode(&skip)
+	}
+	return dec.Decode(field.Addr().Interface())
+}
+
+// indexEntry records the location of the next value without decoding it.
+func indexEntry(dec *json.Decoder) (streamEntry, error) {
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return streamEntry{}, err
+	}
+	length := int64(len(raw))
+	return streamEntry{
+		offset: dec.InputOffset() - length,
+		length: length,
+	}, nil
+}
+
+// indexArray records the locations of the elements of a JSON array.
+func indexArray(dec *json.Decoder, entries *[]streamEntry) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case nil:
+		*entries = nil
+		return nil
+	case json.Delim('['):
+	default:
+		return fmt.Errorf("expected array, got: %v", tok)
+	}
+
+	*entries = []streamEntry{}
+	for dec.More() {
+		e, err := indexEntry(dec)
+		if err != nil {
+			return err
+		}
+		*entries = append(*entries, e)
+	}
+
+	// Consume the closing delimiter.
+	_, err = dec.Token()
+	return err
+}
+
+// indexMap records the locations of the values of a JSON object.
+func indexMap[K comparable, PK interface {
+	*K
+	encoding.TextUnmarshaler
+}](dec *json.Decoder, entries *map[K]streamEntry) error {
+	ok, err := decodeObject(dec, func(key string) error {
+		var k K
+		if err := PK(&k).UnmarshalText([]byte(key)); err != nil {
+			return err
+		}
+		e, err := indexEntry(dec)
+		if err != nil {
+			return err
+		}
+		if *entries == nil {
+			*entries = make(map[K]streamEntry)
+		}
+		(*entries)[k] = e
+		return nil
+	})
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		*entries = nil
+	case *entries == nil:
+		*entries = make(map[K]streamEntry)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	cborMajorArray = 0x80
+	cborMajorMap   = 0xa0
+)
+
+// cborEntry is an entry of a CBOR map whose value is written incrementally.
+type cborEntry struct {
+	key   []byte
+	value func(w io.Writer) error
+}
+
+// writeCBOR writes the canonical CBOR encoding of the genesis document without ever holding
+// the encoding of a complete large section in memory.
+func (ds *DocumentStream) writeCBOR(w io.Writer) error {
+	entries, err := structCBOREntries(&ds.header)
+	if err != nil {
+		return err
+	}
+	entries = setCBOREntry(entries, "registry", ds.writeRegistryCBOR)
+	entries = setCBOREntry(entries, "staking", ds.writeStakingCBOR)
+	entries = setCBOREntry(entries, "roothash", ds.writeRootHashCBOR)
+
+	return writeCBORMap(w, entries)
+}
+
+func (ds *DocumentStream) writeRegistryCBOR(w io.Writer) error {
+	entries, err := structCBOREntries(&ds.header.Registry)
+	if err != nil {
+		return err
+	}
+	if len(ds.nodes) > 0 {
+		entries = setCBOREntry(entries, "nodes", func(w io.Writer) error {
+			if err := writeCBORHead(w, cborMajorArray, uint64(len(ds.nodes))); err != nil {
+				return err
+			}
+			return ds.forEachNode(func(n *node.MultiSignedNode) error {
+				_, err := w.Write(cbor.Marshal(n))
+				return err
+			})
+		})
+	}
+
+	return writeCBORMap(w, entries)
+}
+
+func (ds *DocumentStream) writeStakingCBOR(w io.Writer) error {
+	entries, err := structCBOREntries(&ds.header.Staking)
+	if err != nil {
+		return err
+	}
+	if len(ds.ledger) > 0 {
+		entries = setCBOREntry(entries, "ledger", cborMapWriter[staking.Address, *staking.Account](ds, ds.ledger))
+	}
+	if len(ds.delegations) > 0 {
+		entries = setCBOREntry(entries, "delegations", cborMapWriter[staking.Address, map[staking.Address]*staking.Delegation](ds, ds.delegations))
+	}
+	if len(ds.debondingDelegations) > 0 {
+		entries = setCBOREntry(entries, "debonding_delegations", cborMapWriter[staking.Address, map[staking.Address][]*staking.DebondingDelegation](ds, ds.debondingDelegations))
+	}
+
+	return writeCBORMap(w, entries)
+}
+
+func (ds *DocumentStream) writeRootHashCBOR(w io.Writer) error {
+	entries, err := structCBOREntries(&ds.header.RootHash)
+	if err != nil {
+		return err
+	}
+	if len(ds.runtimeStates) > 0 {
+		entries = setCBOREntry(entries, "runtime_states", cborMapWriter[common.Namespace, *roothash.GenesisRuntimeState](ds, ds.runtimeStates))
+	}
+
+	return writeCBORMap(w, entries)
+}
+
+// cborMapWriter returns a function that writes the given entries as a CBOR map, decoding and
+// encoding one entry at a time.
+func cborMapWriter[K comparable, V any](ds *DocumentStream, entries map[K]streamEntry) func(io.Writer) error {
+	return func(w io.Writer) error {
+		mapEntries := make([]cborEntry, 0, len(entries))
+		for k, e := range entries {
+			mapEntries = append(mapEntries, cborEntry{
+				key: cbor.Marshal(k),
+				value: func(w io.Writer) error {
+					var v V
+					if err := ds.readEntry(e, &v); err != nil {
+						return err
+					}
+					_, err := w.Write(cbor.Marshal(v))
+					return err
+				},
+			})
+		}
+		return writeCBORMap(w, mapEntries)
+	}
+}
+
+// structCBOREntries returns the CBOR map entries of the canonical encoding of the given struct.
+func structCBOREntries(v any) ([]cborEntry, error) {
+	var fields map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(cbor.Marshal(v), &fields); err != nil {
+		return nil, err
+	}
+
+	entries := make([]cborEntry, 0, len(fields))
+	for name, raw := range fields {
+		entries = append(entries, cborEntry{
+			key: cbor.Marshal(name),
+			value: func(w io.Writer) error {
+				_, err := w.Write(raw)
+				return err
+			},
+		})
+	}
+	return entries, nil
+}
+
+// setCBOREntry replaces the value of the map entry with the given key, adding a new entry in
+// case there is none.
+func setCBOREntry(entries []cborEntry, name string, value func(io.Writer) error) []cborEntry {
+	key := cbor.Marshal(name)
+	for i := range entries {
+		if bytes.Equal(entries[i].key, key) {
+			entries[i].value = value
+			return entries
+		}
+	}
+	return append(entries, cborEntry{
+		key:   key,
+		value: value,
+	})
+}
+
+// writeCBORMap writes a CBOR map with the given entries in canonical order.
+func writeCBORMap(w io.Writer, entries []cborEntry) error {
+	// Canonical CBOR sorts map keys by the length of their encoding first and then bytewise.
+	slices.SortFunc(entries, func(a, b cborEntry) int {
+		if c := cmp.Compare(len(a.key), len(b.key)); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.key, b.key)
+	})
+
+	if err := writeCBORHead(w, cborMajorMap, uint64(len(entries))); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if _, err := w.Write(e.key); err != nil {
+			return err
+		}
+		if err := e.value(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCBORHead writes the head of a CBOR data item of the given major type and argument.
+func writeCBORHead(w io.Writer, major byte, n uint64) error {
+	var head []byte
+	switch {
+	case n < 24:
+		head = []byte{major | byte(n)}
+	case n <= 0xff:
+		head = []byte{major | 24, byte(n)}
+	case n <= 0xffff:
+		head = binary.BigEndian.AppendUint16([]byte{major | 25}, uint16(n))
+	case n <= 0xffffffff:
+		head = binary.BigEndian.AppendUint32([]byte{major | 26}, uint32(n))
+	default:
+		head = binary.BigEndian.AppendUint64([]byte{major | 27}, n)
+	}
+	_, err := w.Write(head)
+	return err
+}
@@ -2,7 +2,6 @@
 package file
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -24,21 +23,48 @@ func NewProvider(filename string) *Provider {
 }
 
 // GetGenesisDocument returns the genesis document.
+//
+// The document is sanity checked and hashed in its streamed form before it is
+// decoded into memory.
 func (p *Provider) GetGenesisDocument() (*api.Document, error) {
-	raw, err := os.ReadFile(p.filename)
+	ds, err := p.GetGenesisDocumentStream()
+	if err != nil {
+		return nil, err
+	}
+	defer ds.Close()
+
+	if _, err = ds.Hash(); err != nil {
+		return nil, err
+	}
+	return ds.Document()
+}
+
+// GetGenesisDocumentStream returns the sanity checked streamed form of the
+// genesis document.
+//
+// The caller is responsible for closing the returned stream.
+func (p *Provider) GetGenesisDocumentStream() (*api.DocumentStream, error) {
+	f, err := os.Open(p.filename)
 	if err != nil {
 		return nil, fmt.Errorf("genesis: failed to open genesis document: %w", err)
 	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("genesis: failed to open genesis document: %w", err)
+	}
 
-	var doc api.Document
-	if err = json.Unmarshal(raw, &doc); err != nil {
+	ds, err := api.NewDocumentStream(f, fi.Size())
+	if err != nil {
+		f.Close()
 		return nil, fmt.Errorf("genesis: malformed genesis file: %w", err)
 	}
-	if err = doc.SanityCheck(); err != nil {
+	if err = ds.SanityCheck(); err != nil {
+		ds.Close()
 		return nil, fmt.Errorf("genesis: bad genesis file: %w", err)
 	}
 
-	return &doc, nil
+	return ds, nil
 }
 
 // DefaultProvider creates a new local file genesis provider for the genesis file path
@@ -1,8 +1,11 @@
 package genesis
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -947,3 +950,218 @@ func TestGenesisSanityCheck(t *testing.T) {
 	d.Registry.SuspendedRuntimes = []*registry.Runtime{testKMRuntime, testRuntime}
 	require.NoError(d.SanityCheck(), "sanity check for entity should pass")
 }
+
+func TestDocumentStream(t *testing.T) {
+	signer := memorySigner.NewTestSigner("genesis document stream signer")
+	invalidPK := memorySigner.NewTestSigner("invalid genesis document stream signer").Public()
+	require.NoError(t, invalidPK.Blacklist(), "blacklist invalid signer")
+	signature.BuildPublicKeyBlacklist(true)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*genesis.Document)
+	}{
+		{"Valid", func(*genesis.Document) {}},
+		{"InvalidHeight", func(d *genesis.Document) { d.Height = 0 }},
+		{"EmptyChainID", func(d *genesis.Document) { d.ChainID = "   \t" }},
+		{"InvalidBaseEpoch", func(d *genesis.Document) { d.Beacon.Base = beacon.EpochInvalid }},
+		{"Entity", func(d *genesis.Document) {
+			d.Registry.Entities = []*entity.SignedEntity{signEntityOrDie(signer, &entity.Entity{
+				Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+				ID:        signer.Public(),
+			})}
+		}},
+		{"InvalidEntity", func(d *genesis.Document) {
+			d.Registry.Entities = []*entity.SignedEntity{signEntityOrDie(signer, &entity.Entity{
+				Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+				ID:        invalidPK,
+			})}
+		}},
+		{"InvalidTotalSupply", func(d *genesis.Document) { d.Staking.TotalSupply = *quantity.NewFromUint64(1) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			d := testDoc()
+			tc.modify(&d)
+
+			raw, err := d.CanonicalJSON()
+			require.NoError(err, "CanonicalJSON")
+			var expected genesis.Document
+			require.NoError(json.Unmarshal(raw, &expected), "Unmarshal")
+
+			ds, err := genesis.NewDocumentStream(bytes.NewReader(raw), int64(len(raw)))
+			require.NoError(err, "NewDocumentStream")
+			streamed, err := ds.Document()
+			require.NoError(err, "Document")
+			require.Equal(&expected, streamed, "streamed document should be the same")
+
+			h, err := ds.Hash()
+			require.NoError(err, "Hash")
+			require.Equal(hash.NewFrom(&expected), h, "streamed document hash should be the same")
+
+			expectedErr := expected.SanityCheck()
+			err = ds.SanityCheck()
+			require.Equal(expectedErr == nil, err == nil, "streamed document sanity check should have the same result (expected: %v got: %v)", expectedErr, err)
+		})
+	}
+}
+
+func TestGenesisDumpFilter(t *testing.T) {
+	require := require.New(t)
+
+	testAcc1Address := stakingTests.Accounts.GetAddress(1)
+	testAcc2Address := stakingTests.Accounts.GetAddress(2)
+	remappedAddress := staking.NewAddress(memorySigner.NewTestSigner("genesis dump filter remap signer").Public())
+
+	newDoc := func() genesis.Document {
+		d := testDoc()
+		d.Beacon.Base = 10
+		d.Beacon.Parameters.DebugMockBackend = false
+		d.Beacon.Parameters.InsecureParameters = &beacon.InsecureParameters{
+			Interval: 100,
+		}
+		d.Governance.Proposals = []*governance.Proposal{
+			{
+				CreatedAt: 1,
+				ClosesAt:  100,
+				Submitter: testAcc1Address,
+				Deposit:   *quantity.NewFromUint64(100),
+				Content: governance.ProposalContent{
+					Upgrade: &governance.UpgradeProposal{
+						Descriptor: upgrade.Descriptor{
+							Versioned: cbor.NewVersioned(upgrade.LatestDescriptorVersion),
+							Handler:   "genesis_tests",
+							Target:    version.Versions,
+							Epoch:     500,
+						},
+					},
+				},
+				State: governance.StateActive,
+				ID:    1,
+			},
+		}
+		d.Governance.VoteEntries = map[uint64][]*governance.VoteEntry{
+			1: {
+				{Voter: testAcc2Address, Vote: governance.VoteNo},
+				{Voter: testAcc1Address, Vote: governance.VoteYes},
+			},
+		}
+		d.Staking.GovernanceDeposits = *quantity.NewFromUint64(100)
+		require.NoError(d.Staking.TotalSupply.Add(&d.Staking.GovernanceDeposits), "TotalSupply.Add")
+		d.Staking.Ledger[testAcc1Address].General.Allowances = map[staking.Address]quantity.Quantity{
+			testAcc2Address: *quantity.NewFromUint64(10),
+		}
+		require.NoError(d.SanityCheck(), "unfiltered document should be valid")
+		return d
+	}
+
+	d := newDoc()
+	require.NoError((&genesis.DumpFilter{}).Apply(&d), "empty filter")
+	require.NoError(d.SanityCheck(), "empty filter should keep the document valid")
+	require.Len(d.Governance.Proposals, 1, "empty filter should keep proposals")
+	require.True(slices.IsSortedFunc(d.Governance.VoteEntries[1], func(a, b *governance.VoteEntry) int {
+		return bytes.Compare(a.Voter[:], b.Voter[:])
+	}), "vote entries should be sorted by voter")
+
+	for _, svc := range []string{"registry", "governance", "staking", "roothash", "keymanager", "vault", "beacon"} {
+		err := (&genesis.DumpFilter{Services: []string{svc}}).ValidateBasic()
+		switch svc {
+		case "registry", "beacon":
+			require.Error(err, "invalid service filter %s should be rejected", svc)
+		default:
+			require.NoError(err, "valid service filter %s", svc)
+		}
+	}
+
+	for _, services := range [][]string{
+		{"registry", "staking"},
+		{"staking"},
+		{"governance"},
+		{"roothash", "keymanager", "vault"},
+	} {
+		d = newDoc()
+		require.NoError((&genesis.DumpFilter{Services: services}).Apply(&d), "Apply(%v)", services)
+		require.NoError(d.SanityCheck(), "filtered document should be valid (%v)", services)
+	}
+
+	d = newDoc()
+	require.NoError((&genesis.DumpFilter{Services: []string{"staking"}}).Apply(&d), "Apply")
+	require.Empty(d.Governance.Proposals, "omitted proposals")
+	require.True(d.Staking.GovernanceDeposits.IsZero(), "omitted proposal deposits")
+	require.NotEmpty(d.Staking.Ledger, "included ledger")
+
+	d = newDoc()
+	require.NoError((&genesis.DumpFilter{Services: []string{"governance"}}).Apply(&d), "Apply")
+	require.Empty(d.Staking.Ledger, "omitted ledger")
+	require.Empty(d.Staking.Delegations, "omitted delegations")
+	require.Len(d.Governance.Proposals, 1, "included proposals")
+
+	d = newDoc()
+	filter := genesis.DumpFilter{
+		AddressRemap: map[staking.Address]staking.Address{
+			testAcc2Address: remappedAddress,
+		},
+	}
+	require.NoError(filter.Apply(&d), "Apply")
+	require.NoError(d.SanityCheck(), "remapped document should be valid")
+	require.Contains(d.Staking.Ledger[testAcc1Address].General.Allowances, remappedAddress, "remapped allowance")
+	require.True(slices.ContainsFunc(d.Governance.VoteEntries[1], func(e *governance.VoteEntry) bool {
+		return e.Voter == remappedAddress
+	}), "remapped voter")
+
+	d = newDoc()
+	filter = genesis.DumpFilter{
+		AddressRemap: map[staking.Address]staking.Address{
+			testAcc1Address: stakingTests.Accounts.GetAddress(3),
+		},
+	}
+	require.Error(filter.Apply(&d), "colliding address remap should be rejected")
+
+	filter = genesis.DumpFilter{
+		AddressRemap: map[staking.Address]staking.Address{
+			testAcc1Address: remappedAddress,
+			testAcc2Address: remappedAddress,
+		},
+	}
+	require.Error(filter.ValidateBasic(), "duplicate address remap target should be rejected")
+
+	filter = genesis.DumpFilter{
+		AddressRemap: map[staking.Address]staking.Address{
+			testAcc1Address: staking.CommonPoolAddress,
+		},
+	}
+	require.Error(filter.ValidateBasic(), "reserved address remap target should be rejected")
+}
+
+func TestGenesisCanonicalize(t *testing.T) {
+	require := require.New(t)
+
+	newDoc := func(reverse bool) genesis.Document {
+		d := testDoc()
+		for i := range 4 {
+			signer := memorySigner.NewTestSigner(fmt.Sprintf("genesis canonicalize signer %d", i))
+			d.Registry.Entities = append(d.Registry.Entities, signEntityOrDie(signer, &entity.Entity{
+				Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+				ID:        signer.Public(),
+			}))
+			d.KeyManager.Statuses = append(d.KeyManager.Statuses, &secrets.Status{
+				ID: hex2ns(fmt.Sprintf("40000000000000000000000000000000000000000000000000000000000000%02x", i), false),
+			})
+		}
+		if reverse {
+			slices.Reverse(d.Registry.Entities)
+			slices.Reverse(d.KeyManager.Statuses)
+		}
+		d.Canonicalize()
+		return d
+	}
+
+	d1, d2 := newDoc(false), newDoc(true)
+	raw1, err := d1.CanonicalJSON()
+	require.NoError(err, "CanonicalJSON")
+	raw2, err := d2.CanonicalJSON()
+	require.NoError(err, "CanonicalJSON")
+	require.Equal(raw1, raw2, "canonicalized documents should have the same encoding")
+	require.Equal(d1.Hash(), d2.Hash(), "canonicalized documents should have the same hash")
+}
@@ -367,6 +367,11 @@ type Backend interface {
 	// Votes looks up votes for a specific proposal.
 	Votes(ctx context.Context, query *ProposalQuery) ([]*VoteEntry, error)
 
+	// GetVoteWeight computes the effective stake that a vote cast by the given voter carries
+	// towards the results of a specific proposal, using the same rules as are used for tallying
+	// the votes when the proposal closes.
+	GetVoteWeight(ctx context.Context, query *VoteWeightQuery) (*quantity.Quantity, error)
+
 	// PendingUpgrades returns a list of all pending upgrades.
 	PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error)
 
@@ -389,6 +394,13 @@ type ProposalQuery struct {
 	ProposalID uint64 `json:"id"`
 }
 
+// VoteWeightQuery is a vote weight query.
+type VoteWeightQuery struct {
+	Height     int64           `json:"height"`
+	ProposalID uint64          `json:"id"`
+	Voter      staking.Address `json:"voter"`
+}
+
 // VoteEntry contains data about a cast vote.
 type VoteEntry struct {
 	Voter staking.Address `json:"voter"`
@@ -554,6 +566,12 @@ type VoteEvent struct {
 	Submitter staking.Address `json:"submitter"`
 	// Vote is the cast vote.
 	Vote Vote `json:"vote"`
+	// Weight is the effective stake that the vote carries as of the height at which the event
+	// was emitted.
+	//
+	// This field is not part of the consensus event. It is computed by the node when serving
+	// events and may be missing in case it could not be computed.
+	Weight *quantity.Quantity `json:"weight,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
@@ -7,6 +7,7 @@ import (
 
 	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
 
@@ -22,6 +23,8 @@ var (
 	methodProposal = serviceName.NewMethod("Proposal", ProposalQuery{})
 	// methodVotes is the Votes method.
 	methodVotes = serviceName.NewMethod("Votes", ProposalQuery{})
+	// methodGetVoteWeight is the GetVoteWeight method.
+	methodGetVoteWeight = serviceName.NewMethod("GetVoteWeight", VoteWeightQuery{})
 	// methodPendingUpgrades is the PendingUpgrades method.
 	methodPendingUpgrades = serviceName.NewMethod("PendingUpgrades", int64(0))
 	// methodStateToGenesis is the StateToGenesis method.
@@ -55,6 +58,10 @@ var (
 				MethodName: methodVotes.ShortName(),
 				Handler:    handlerVotes,
 			},
+			{
+				MethodName: methodGetVoteWeight.ShortName(),
+				Handler:    handlerGetVoteWeight,
+			},
 			{
 				MethodName: methodPendingUpgrades.ShortName(),
 				Handler:    handlerPendingUpgrades,
@@ -151,6 +158,29 @@ func handlerVotes(
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerGetVoteWeight(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query VoteWeightQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetVoteWeight(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetVoteWeight.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetVoteWeight(ctx, req.(*VoteWeightQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerProposal(
 	srv any,
 	ctx context.Context,
@@ -342,6 +372,14 @@ func (c *Client) Votes(ctx context.Context, request *ProposalQuery) ([]*VoteEntr
 	return rsp, nil
 }
 
+func (c *Client) GetVoteWeight(ctx context.Context, request *VoteWeightQuery) (*quantity.Quantity, error) {
+	var rsp quantity.Quantity
+	if err := c.conn.Invoke(ctx, methodGetVoteWeight.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) PendingUpgrades(ctx context.Context, height int64) ([]*upgrade.Descriptor, error) {
 	var rsp []*upgrade.Descriptor
 	if err := c.conn.Invoke(ctx, methodPendingUpgrades.FullName(), height, &rsp); err != nil {
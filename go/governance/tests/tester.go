@@ -374,6 +374,13 @@ WaitForSubmittedVote:
 	require.EqualValues(ev.Vote.Vote, votes[0].Vote, "vote event should be equal to the queried vote")
 	require.EqualValues(ev.Vote.Submitter, votes[0].Voter, "vote event should be equal to the queried vote")
 
+	// Query vote weight.
+	weight, err := governance.GetVoteWeight(ctx, &api.VoteWeightQuery{Height: consensusAPI.HeightLatest, ProposalID: testState.proposal.ID, Voter: entAddr})
+	require.NoError(err, "GetVoteWeight query")
+	require.False(weight.IsZero(), "vote weight should not be zero")
+	require.NotNil(ev.Vote.Weight, "vote event should include the vote weight")
+	require.Zero(ev.Vote.Weight.Cmp(weight), "vote event weight should be equal to the queried vote weight")
+
 	// Transition to the voting close epoch.
 	timeSource := consensus.Beacon()
 	currentEpoch, err := timeSource.GetEpoch(ctx, consensusAPI.HeightLatest)
@@ -0,0 +1,100 @@
+package pcs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// BundleSignatureContext is the signature context used for collateral bundles.
+var BundleSignatureContext = signature.NewContext("oasis-core/ias: pcs collateral bundle")
+
+// ErrUntrustedBundle is the error returned when a collateral bundle is not signed by any of the
+// trusted signers.
+var ErrUntrustedBundle = errors.New("pcs/proxy: collateral bundle signer not trusted")
+
+// Bundle is a collateral bundle used to transfer cached collateral to an offline proxy.
+type Bundle struct {
+	// Created is the UNIX timestamp of when the bundle was created.
+	Created int64 `json:"created"`
+
+	// Entries is the exported collateral.
+	Entries []*Entry `json:"entries"`
+}
+
+// SignedBundle is a signed collateral bundle.
+type SignedBundle struct {
+	signature.Signed
+}
+
+// Open verifies that the bundle is signed by one of the trusted signers and returns it.
+func (sb *SignedBundle) Open(trusted []signature.PublicKey) (*Bundle, error) {
+	if !slices.Contains(trusted, sb.Signature.PublicKey) {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedBundle, sb.Signature.PublicKey)
+	}
+
+	var bundle Bundle
+	if err := sb.Signed.Open(BundleSignatureContext, &bundle); err != nil {
+		return nil, fmt.Errorf("pcs/proxy: failed to open collateral bundle: %w", err)
+	}
+	return &bundle, nil
+}
+
+// Export exports all non-expired cached collateral as a bundle signed by the given signer.
+func (c *Cache) Export(signer signature.Signer) (*SignedBundle, error) {
+	bundle := Bundle{
+		Created: c.now().Unix(),
+		Entries: c.Entries(),
+	}
+	signed, err := signature.SignSigned(signer, BundleSignatureContext, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("pcs/proxy: failed to sign collateral bundle: %w", err)
+	}
+	return &SignedBundle{Signed: *signed}, nil
+}
+
+// ImportResult is the result of a collateral bundle import.
+type ImportResult struct {
+	// Imported is the number of entries that updated the cache.
+	Imported int `json:"imported"`
+	// Expired is the number of entries that were skipped as they have already expired.
+	Expired int `json:"expired"`
+	// Stale is the number of entries that were skipped as the cache already contains collateral
+	// that expires at the same time or later.
+	Stale int `json:"stale"`
+}
+
+// Import imports the collateral from a bundle signed by one of the trusted signers.
+//
+// The expiry of each entry is derived from the collateral itself and expired collateral is
+// never imported.
+func (c *Cache) Import(sb *SignedBundle, trusted []signature.PublicKey) (*ImportResult, error) {
+	bundle, err := sb.Open(trusted)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range bundle.Entries {
+		if kind, ok := routes[pathOf(entry.Path)]; !ok || kind != entry.Kind {
+			return nil, fmt.Errorf("pcs/proxy: unsupported collateral in bundle: %s", entry.Path)
+		}
+	}
+
+	var result ImportResult
+	for _, entry := range bundle.Entries {
+		updated, err := c.Put(entry)
+		switch {
+		case errors.Is(err, ErrExpired):
+			result.Expired++
+		case err != nil:
+			return nil, fmt.Errorf("pcs/proxy: bad collateral in bundle (%s): %w", entry.Path, err)
+		case updated:
+			result.Imported++
+		default:
+			result.Stale++
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,263 @@
+package pcs
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	cmnPCS "github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+)
+
+// serviceStoreName is the name of the common store service used for the collateral cache.
+const serviceStoreName = "ias_pcs_proxy"
+
+var (
+	// ErrNotCached is the error returned when the requested collateral is not cached.
+	ErrNotCached = errors.New("pcs/proxy: collateral not cached")
+
+	// ErrExpired is the error returned when the requested collateral has expired.
+	ErrExpired = errors.New("pcs/proxy: collateral expired")
+)
+
+// Kind is the kind of cached collateral.
+type Kind string
+
+const (
+	// KindTCBInfo is the kind of cached TCB info.
+	KindTCBInfo Kind = "tcb_info"
+	// KindQEIdentity is the kind of cached QE identity.
+	KindQEIdentity Kind = "qe_identity"
+	// KindPCKCRL is the kind of cached PCK certificate revocation lists.
+	KindPCKCRL Kind = "pck_crl"
+)
+
+// Entry is a cached PCS API response.
+type Entry struct {
+	// Kind is the kind of the cached collateral.
+	Kind Kind `json:"kind"`
+
+	// Path is the PCS API path, including the canonical query string, that the response was
+	// served for.
+	Path string `json:"path"`
+
+	// Headers are the relevant response headers (e.g., the issuer certificate chain).
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body is the raw response body.
+	Body []byte `json:"body"`
+}
+
+// NextUpdate parses the collateral and returns the time after which it must no longer be used.
+func (e *Entry) NextUpdate() (time.Time, error) {
+	switch e.Kind {
+	case KindTCBInfo:
+		var signed cmnPCS.SignedTCBInfo
+		if err := json.Unmarshal(e.Body, &signed); err != nil {
+			return time.Time{}, fmt.Errorf("malformed signed TCB info: %w", err)
+		}
+		var info cmnPCS.TCBInfo
+		if err := json.Unmarshal(signed.TCBInfo, &info); err != nil {
+			return time.Time{}, fmt.Errorf("malformed TCB info: %w", err)
+		}
+		return parseTimestamp(info.NextUpdate)
+	case KindQEIdentity:
+		var signed cmnPCS.SignedQEIdentity
+		if err := json.Unmarshal(e.Body, &signed); err != nil {
+			return time.Time{}, fmt.Errorf("malformed signed QE identity: %w", err)
+		}
+		var identity cmnPCS.QEIdentity
+		if err := json.Unmarshal(signed.EnclaveIdentity, &identity); err != nil {
+			return time.Time{}, fmt.Errorf("malformed QE identity: %w", err)
+		}
+		return parseTimestamp(identity.NextUpdate)
+	case KindPCKCRL:
+		// The CRL may be either PEM or DER encoded, depending on the requested encoding.
+		der := e.Body
+		if blk, _ := pem.Decode(e.Body); blk != nil {
+			der = blk.Bytes
+		}
+		crl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("malformed PCK CRL: %w", err)
+		}
+		if crl.NextUpdate.IsZero() {
+			return time.Time{}, fmt.Errorf("PCK CRL is missing the next update timestamp")
+		}
+		return crl.NextUpdate, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported collateral kind: %s", e.Kind)
+	}
+}
+
+func parseTimestamp(ts string) (time.Time, error) {
+	t, err := time.Parse(cmnPCS.TimestampFormat, ts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed next update timestamp: %w", err)
+	}
+	return t, nil
+}
+
+// cacheKey returns the canonical cache key for the given PCS API path and query.
+func cacheKey(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+type cachedEntry struct {
+	entry      *Entry
+	nextUpdate time.Time
+}
+
+// Cache is a persistent PCS collateral cache.
+//
+// Expiry is strict: collateral is only ever returned before its next update timestamp.
+type Cache struct {
+	mu sync.RWMutex
+
+	store   *persistent.TypedStore[Entry]
+	entries map[string]*cachedEntry
+
+	now    func() time.Time
+	logger *logging.Logger
+}
+
+// Get returns the cached collateral for the given key.
+//
+// It returns ErrNotCached in case no collateral is cached and ErrExpired in case the cached
+// collateral has expired.
+func (c *Cache) Get(key string) (*Entry, time.Time, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ce, ok := c.entries[key]
+	if !ok {
+		return nil, time.Time{}, ErrNotCached
+	}
+	if !c.now().Before(ce.nextUpdate) {
+		return nil, ce.nextUpdate, ErrExpired
+	}
+	return ce.entry, ce.nextUpdate, nil
+}
+
+// Put adds the given collateral to the cache.
+//
+// Expired collateral is rejected and collateral is never replaced with collateral that expires
+// earlier. It returns true iff the cache was updated.
+func (c *Cache) Put(entry *Entry) (bool, error) {
+	nextUpdate, err := entry.NextUpdate()
+	if err != nil {
+		return false, err
+	}
+	if !c.now().Before(nextUpdate) {
+		return false, fmt.Errorf("%w: next update was at %s", ErrExpired, nextUpdate)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ce, ok := c.entries[entry.Path]; ok && !nextUpdate.After(ce.nextUpdate) {
+		return false, nil
+	}
+	if c.store != nil {
+		if err = c.store.Put([]byte(entry.Path), entry); err != nil {
+			return false, fmt.Errorf("failed to persist collateral: %w", err)
+		}
+	}
+	c.entries[entry.Path] = &cachedEntry{
+		entry:      entry,
+		nextUpdate: nextUpdate,
+	}
+	return true, nil
+}
+
+// Entries returns all non-expired cached collateral, ordered by path.
+func (c *Cache) Entries() []*Entry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	entries := make([]*Entry, 0, len(c.entries))
+	for _, ce := range c.entries {
+		if !now.Before(ce.nextUpdate) {
+			continue
+		}
+		entries = append(entries, ce.entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// Prune removes all expired collateral from the cache.
+func (c *Cache) Prune() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, ce := range c.entries {
+		if now.Before(ce.nextUpdate) {
+			continue
+		}
+		if c.store != nil {
+			if err := c.store.Delete([]byte(key)); err != nil {
+				return fmt.Errorf("failed to remove expired collateral: %w", err)
+			}
+		}
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *Cache) load() error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Iterate(func(key []byte, entry *Entry) error {
+		nextUpdate, err := entry.NextUpdate()
+		if err != nil {
+			c.logger.Warn("ignoring malformed cached collateral",
+				"path", string(key),
+				"err", err,
+			)
+			return nil
+		}
+		c.entries[string(key)] = &cachedEntry{
+			entry:      entry,
+			nextUpdate: nextUpdate,
+		}
+		return nil
+	})
+}
+
+func newCache(store *persistent.CommonStore, now func() time.Time) (*Cache, error) {
+	c := &Cache{
+		entries: make(map[string]*cachedEntry),
+		now:     now,
+		logger:  logging.GetLogger("ias/pcs/cache"),
+	}
+	if store != nil {
+		c.store = persistent.NewTypedStore[Entry](store, serviceStoreName)
+	}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("pcs/proxy: failed to load cached collateral: %w", err)
+	}
+	return c, nil
+}
+
+// NewCache creates a new collateral cache backed by the given common store.
+//
+// If the store is nil, the cache is kept in memory only.
+func NewCache(store *persistent.CommonStore) (*Cache, error) {
+	return newCache(store, time.Now)
+}
@@ -0,0 +1,218 @@
+package pcs
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+)
+
+const (
+	testTCBInfoPath    = "/sgx/certification/v4/tcb?fmspc=00606a000000&update=early"
+	testQEIdentityPath = "/sgx/certification/v4/qe/identity?update=early"
+	testPCKCRLPath     = "/sgx/certification/v4/pckcrl?ca=processor"
+)
+
+var (
+	// Next update timestamps of the fixture collateral.
+	testTCBInfoNextUpdate    = time.Date(2023, 1, 18, 9, 40, 10, 0, time.UTC)
+	testQEIdentityNextUpdate = time.Date(2023, 1, 15, 12, 45, 36, 0, time.UTC)
+	testPCKCRLNextUpdate     = time.Date(2023, 1, 19, 10, 0, 0, 0, time.UTC)
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) get() time.Time {
+	return fc.now
+}
+
+func loadTestEntry(t *testing.T, kind Kind, path, fn string) *Entry {
+	body, err := os.ReadFile("testdata/" + fn)
+	require.NoError(t, err, "ReadFile")
+	return &Entry{
+		Kind: kind,
+		Path: path,
+		Body: body,
+	}
+}
+
+func loadTestEntries(t *testing.T) []*Entry {
+	return []*Entry{
+		loadTestEntry(t, KindTCBInfo, testTCBInfoPath, "tcb_info_v3_fmspc_00606A000000.json"),
+		loadTestEntry(t, KindQEIdentity, testQEIdentityPath, "qe_identity_v2.json"),
+		loadTestEntry(t, KindPCKCRL, testPCKCRLPath, "pck_crl_processor.pem"),
+	}
+}
+
+func TestEntryNextUpdate(t *testing.T) {
+	require := require.New(t)
+
+	entries := loadTestEntries(t)
+	for i, expected := range []time.Time{testTCBInfoNextUpdate, testQEIdentityNextUpdate, testPCKCRLNextUpdate} {
+		nextUpdate, err := entries[i].NextUpdate()
+		require.NoError(err, "NextUpdate(%s)", entries[i].Kind)
+		require.True(expected.Equal(nextUpdate), "NextUpdate(%s)", entries[i].Kind)
+	}
+
+	_, err := (&Entry{Kind: KindTCBInfo, Body: []byte("{}")}).NextUpdate()
+	require.Error(err, "NextUpdate should fail for malformed collateral")
+	_, err = (&Entry{Kind: "unknown"}).NextUpdate()
+	require.Error(err, "NextUpdate should fail for unknown collateral")
+}
+
+func TestCacheExpiry(t *testing.T) {
+	require := require.New(t)
+
+	clock := &fakeClock{now: testTCBInfoNextUpdate.Add(-time.Second)}
+	cache, err := newCache(nil, clock.get)
+	require.NoError(err, "newCache")
+
+	entry := loadTestEntry(t, KindTCBInfo, testTCBInfoPath, "tcb_info_v3_fmspc_00606A000000.json")
+	updated, err := cache.Put(entry)
+	require.NoError(err, "Put")
+	require.True(updated, "Put should update the cache")
+
+	_, _, err = cache.Get("/sgx/certification/v4/tcb")
+	require.ErrorIs(err, ErrNotCached, "Get should fail for uncached collateral")
+
+	// Collateral is served up until its next update.
+	clock.now = testTCBInfoNextUpdate.Add(-time.Nanosecond)
+	cached, nextUpdate, err := cache.Get(testTCBInfoPath)
+	require.NoError(err, "Get just before expiry")
+	require.Equal(entry, cached)
+	require.True(testTCBInfoNextUpdate.Equal(nextUpdate))
+	require.Len(cache.Entries(), 1)
+
+	// Collateral is never served once it has expired.
+	clock.now = testTCBInfoNextUpdate
+	_, _, err = cache.Get(testTCBInfoPath)
+	require.ErrorIs(err, ErrExpired, "Get at expiry")
+	require.Empty(cache.Entries(), "Entries should not include expired collateral")
+
+	// Expired collateral is never cached.
+	_, err = cache.Put(loadTestEntry(t, KindTCBInfo, testTCBInfoPath, "tcb_info_v3_fmspc_00606A000000.json"))
+	require.ErrorIs(err, ErrExpired, "Put at expiry")
+
+	require.NoError(cache.Prune(), "Prune")
+	_, _, err = cache.Get(testTCBInfoPath)
+	require.ErrorIs(err, ErrNotCached, "Get after prune")
+}
+
+func TestCacheNoDowngrade(t *testing.T) {
+	require := require.New(t)
+
+	clock := &fakeClock{now: testTCBInfoNextUpdate.Add(-24 * time.Hour)}
+	cache, err := newCache(nil, clock.get)
+	require.NoError(err, "newCache")
+
+	entry := loadTestEntry(t, KindTCBInfo, testTCBInfoPath, "tcb_info_v3_fmspc_00606A000000.json")
+	_, err = cache.Put(entry)
+	require.NoError(err, "Put")
+
+	older := *entry
+	older.Body = bytes.Replace(entry.Body, []byte("2023-01-18T09:40:10Z"), []byte("2023-01-18T08:40:10Z"), 1)
+	updated, err := cache.Put(&older)
+	require.NoError(err, "Put older")
+	require.False(updated, "Put should not replace collateral with collateral that expires earlier")
+
+	cached, _, err := cache.Get(testTCBInfoPath)
+	require.NoError(err, "Get")
+	require.Equal(entry, cached)
+}
+
+func TestCachePersistence(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+
+	clock := &fakeClock{now: testQEIdentityNextUpdate.Add(-time.Hour)}
+	cache, err := newCache(store, clock.get)
+	require.NoError(err, "newCache")
+	for _, entry := range loadTestEntries(t) {
+		_, err = cache.Put(entry)
+		require.NoError(err, "Put")
+	}
+	expected := cache.Entries()
+	require.Len(expected, 3)
+	store.Close()
+
+	store, err = persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	cache, err = newCache(store, clock.get)
+	require.NoError(err, "newCache after reopen")
+	require.Equal(expected, cache.Entries(), "cached collateral should be persisted")
+}
+
+func TestBundle(t *testing.T) {
+	require := require.New(t)
+
+	signer := memorySigner.NewTestSigner("ias/pcs: bundle signer")
+	other := memorySigner.NewTestSigner("ias/pcs: other signer")
+	trusted := []signature.PublicKey{signer.Public()}
+
+	// Export on the online proxy.
+	clock := &fakeClock{now: testQEIdentityNextUpdate.Add(-time.Hour)}
+	online, err := newCache(nil, clock.get)
+	require.NoError(err, "newCache")
+	for _, entry := range loadTestEntries(t) {
+		_, err = online.Put(entry)
+		require.NoError(err, "Put")
+	}
+	bundle, err := online.Export(signer)
+	require.NoError(err, "Export")
+
+	t.Run("Untrusted signer", func(t *testing.T) {
+		offline, err := newCache(nil, clock.get)
+		require.NoError(err, "newCache")
+
+		_, err = offline.Import(bundle, []signature.PublicKey{other.Public()})
+		require.ErrorIs(err, ErrUntrustedBundle)
+
+		forged, err := online.Export(other)
+		require.NoError(err, "Export")
+		forged.Signature.PublicKey = signer.Public()
+		_, err = offline.Import(forged, trusted)
+		require.ErrorIs(err, signature.ErrVerifyFailed)
+		require.Empty(offline.Entries())
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		offline, err := newCache(nil, clock.get)
+		require.NoError(err, "newCache")
+
+		result, err := offline.Import(bundle, trusted)
+		require.NoError(err, "Import")
+		require.Equal(&ImportResult{Imported: 3}, result)
+		require.Equal(online.Entries(), offline.Entries())
+
+		// Importing again does not change anything.
+		result, err = offline.Import(bundle, trusted)
+		require.NoError(err, "Import again")
+		require.Equal(&ImportResult{Stale: 3}, result)
+	})
+
+	t.Run("Import at expiry", func(t *testing.T) {
+		late := &fakeClock{now: testQEIdentityNextUpdate}
+		offline, err := newCache(nil, late.get)
+		require.NoError(err, "newCache")
+
+		result, err := offline.Import(bundle, trusted)
+		require.NoError(err, "Import")
+		require.Equal(&ImportResult{Imported: 2, Expired: 1}, result)
+
+		_, _, err = offline.Get(testQEIdentityPath)
+		require.ErrorIs(err, ErrNotCached, "expired collateral should not be imported")
+	})
+}
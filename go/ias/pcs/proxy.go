@@ -0,0 +1,255 @@
+// Package pcs implements the caching Intel SGX PCS proxy.
+//
+// The proxy serves TCB info, QE identity and PCK CRLs under the same paths as the Intel PCS API
+// so nodes can simply point their PCS base URL to it. Collateral is served from a local cache
+// which is refreshed from the upstream PCS API when one is configured. An offline proxy can be
+// populated by importing a signed collateral bundle exported by an online proxy.
+package pcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	cmnPCS "github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+)
+
+const (
+	// DefaultUpstream is the default upstream PCS API.
+	DefaultUpstream = cmnPCS.DefaultBaseURL
+
+	upstreamTimeout = 10 * time.Second
+
+	// maxResponseSize is the maximum size of an upstream PCS API response.
+	maxResponseSize = 16 * 1024 * 1024
+)
+
+// routes are the PCS API paths served by the proxy.
+var routes = map[string]Kind{
+	"/sgx/certification/v4/tcb":         KindTCBInfo,
+	"/tdx/certification/v4/tcb":         KindTCBInfo,
+	"/sgx/certification/v4/qe/identity": KindQEIdentity,
+	"/tdx/certification/v4/qe/identity": KindQEIdentity,
+	"/sgx/certification/v4/pckcrl":      KindPCKCRL,
+}
+
+// forwardedHeaders are the upstream response headers that are cached and served.
+var forwardedHeaders = []string{
+	"Content-Type",
+	"TCB-Info-Issuer-Chain",
+	"SGX-Enclave-Identity-Issuer-Chain",
+	"SGX-PCK-CRL-Issuer-Chain",
+	"TCB-Evaluation-Data-Numbers",
+}
+
+// pathOf returns the path part of a cache key.
+func pathOf(key string) string {
+	p, _, _ := strings.Cut(key, "?")
+	return p
+}
+
+// Config is the caching PCS proxy configuration.
+type Config struct {
+	// Upstream is the base URL of the upstream PCS API. If empty, the proxy operates offline and
+	// only serves imported collateral.
+	Upstream string
+
+	// RefreshThreshold is the time before the next update of cached collateral at which the
+	// proxy starts refreshing it from upstream.
+	RefreshThreshold time.Duration
+}
+
+// Proxy is the caching PCS proxy.
+type Proxy struct {
+	cache *Cache
+	cfg   Config
+
+	upstream   *url.URL
+	httpClient *http.Client
+
+	logger *logging.Logger
+}
+
+// Cache returns the collateral cache used by the proxy.
+func (p *Proxy) Cache() *Cache {
+	return p.cache
+}
+
+// IsOffline returns true iff the proxy does not refresh collateral from upstream.
+func (p *Proxy) IsOffline() bool {
+	return p.upstream == nil
+}
+
+func (p *Proxy) needsRefresh(nextUpdate time.Time) bool {
+	return !p.IsOffline() && nextUpdate.Sub(p.cache.now()) < p.cfg.RefreshThreshold
+}
+
+// fetch retrieves the collateral for the given key from upstream and caches it.
+func (p *Proxy) fetch(ctx context.Context, kind Kind, key string) (*Entry, error) {
+	u := *p.upstream
+	reqPath, query, _ := strings.Cut(key, "?")
+	u.Path = path.Join(u.Path, reqPath)
+	u.RawQuery = query
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := ctxhttp.Do(ctx, p.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream response status error: %s", http.StatusText(rsp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream response body: %w", err)
+	}
+
+	entry := &Entry{
+		Kind:    kind,
+		Path:    key,
+		Headers: make(map[string]string),
+		Body:    body,
+	}
+	for _, h := range forwardedHeaders {
+		if v := rsp.Header.Get(h); v != "" {
+			entry.Headers[h] = v
+		}
+	}
+	if _, err = p.cache.Put(entry); err != nil {
+		return nil, fmt.Errorf("bad upstream collateral: %w", err)
+	}
+
+	// The cache may already contain collateral that expires later.
+	entry, _, err = p.cache.Get(key)
+	return entry, err
+}
+
+// get returns non-expired collateral for the given key, refreshing it from upstream if needed.
+func (p *Proxy) get(ctx context.Context, kind Kind, key string) (*Entry, error) {
+	entry, nextUpdate, err := p.cache.Get(key)
+	switch {
+	case err == nil && !p.needsRefresh(nextUpdate):
+		return entry, nil
+	case err != nil && p.IsOffline():
+		return nil, err
+	}
+
+	fresh, ferr := p.fetch(ctx, kind, key)
+	if ferr == nil {
+		return fresh, nil
+	}
+	p.logger.Warn("failed to refresh collateral from upstream",
+		"path", key,
+		"err", ferr,
+	)
+
+	// Fall back to cached collateral as long as it has not yet expired.
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	kind, ok := routes[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	key := cacheKey(r.URL.Path, r.URL.Query())
+
+	entry, err := p.get(r.Context(), kind, key)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNotCached), errors.Is(err, ErrExpired):
+		p.logger.Warn("collateral not available",
+			"path", key,
+			"err", err,
+		)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	for k, v := range entry.Headers {
+		w.Header().Set(k, v)
+	}
+	_, _ = w.Write(entry.Body)
+}
+
+// Refresh refreshes all cached collateral that is about to expire from upstream and prunes any
+// expired collateral.
+func (p *Proxy) Refresh(ctx context.Context) {
+	if !p.IsOffline() {
+		for _, entry := range p.cache.Entries() {
+			if _, err := p.get(ctx, entry.Kind, entry.Path); err != nil {
+				p.logger.Warn("failed to refresh collateral",
+					"path", entry.Path,
+					"err", err,
+				)
+			}
+		}
+	}
+
+	if err := p.cache.Prune(); err != nil {
+		p.logger.Error("failed to prune expired collateral",
+			"err", err,
+		)
+	}
+}
+
+// Worker periodically refreshes cached collateral until the context is canceled.
+func (p *Proxy) Worker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.Refresh(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// New creates a new caching PCS proxy using the given cache.
+func New(cache *Cache, cfg Config) (*Proxy, error) {
+	p := &Proxy{
+		cache: cache,
+		cfg:   cfg,
+		httpClient: &http.Client{
+			Timeout: upstreamTimeout,
+		},
+		logger: logging.GetLogger("ias/pcs/proxy"),
+	}
+	if cfg.Upstream != "" {
+		var err error
+		if p.upstream, err = url.Parse(cfg.Upstream); err != nil {
+			return nil, fmt.Errorf("pcs/proxy: malformed upstream URL: %w", err)
+		}
+	}
+	return p, nil
+}
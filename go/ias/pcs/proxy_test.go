@@ -0,0 +1,143 @@
+package pcs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cmnPCS "github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+)
+
+// testUpstream is a fake upstream PCS API serving fixture collateral.
+type testUpstream struct {
+	requests atomic.Int64
+	down     atomic.Bool
+
+	entries map[string]*Entry
+}
+
+func (u *testUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.requests.Add(1)
+	if u.down.Load() {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+		return
+	}
+	entry, ok := u.entries[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for k, v := range entry.Headers {
+		w.Header().Set(k, v)
+	}
+	_, _ = w.Write(entry.Body)
+}
+
+func getTestCollateral(t *testing.T, proxyURL, path string) int {
+	rsp, err := http.Get(proxyURL + path)
+	require.NoError(t, err, "http.Get")
+	defer rsp.Body.Close()
+	return rsp.StatusCode
+}
+
+func TestProxyOnline(t *testing.T) {
+	require := require.New(t)
+
+	upstream := &testUpstream{
+		entries: make(map[string]*Entry),
+	}
+	for _, entry := range loadTestEntries(t) {
+		entry.Headers = map[string]string{
+			"TCB-Info-Issuer-Chain": "issuer-chain",
+		}
+		upstream.entries[pathOf(entry.Path)] = entry
+	}
+	upstreamSrv := httptest.NewServer(upstream)
+	defer upstreamSrv.Close()
+
+	clock := &fakeClock{now: testTCBInfoNextUpdate.Add(-48 * time.Hour)}
+	cache, err := newCache(nil, clock.get)
+	require.NoError(err, "newCache")
+	proxy, err := New(cache, Config{
+		Upstream:         upstreamSrv.URL,
+		RefreshThreshold: 24 * time.Hour,
+	})
+	require.NoError(err, "New")
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+	proxyURL := srv.URL
+
+	// Collateral is fetched from upstream on first use and then served from the cache.
+	require.Equal(http.StatusOK, getTestCollateral(t, proxyURL, testTCBInfoPath))
+	require.EqualValues(1, upstream.requests.Load())
+	require.Equal(http.StatusOK, getTestCollateral(t, proxyURL, testTCBInfoPath))
+	require.EqualValues(1, upstream.requests.Load(), "cached collateral should be served")
+
+	// Collateral about to expire is refreshed.
+	clock.now = testTCBInfoNextUpdate.Add(-time.Hour)
+	require.Equal(http.StatusOK, getTestCollateral(t, proxyURL, testTCBInfoPath))
+	require.EqualValues(2, upstream.requests.Load(), "collateral about to expire should be refreshed")
+
+	// Cached collateral is served while upstream is unavailable, but only until it expires.
+	upstream.down.Store(true)
+	clock.now = testTCBInfoNextUpdate.Add(-time.Nanosecond)
+	require.Equal(http.StatusOK, getTestCollateral(t, proxyURL, testTCBInfoPath))
+	clock.now = testTCBInfoNextUpdate
+	require.Equal(http.StatusNotFound, getTestCollateral(t, proxyURL, testTCBInfoPath))
+
+	// Expired collateral from upstream is never served.
+	upstream.down.Store(false)
+	require.Equal(http.StatusNotFound, getTestCollateral(t, proxyURL, testTCBInfoPath))
+
+	// Unsupported paths are not proxied.
+	require.Equal(http.StatusNotFound, getTestCollateral(t, proxyURL, "/sgx/certification/v4/pckcert"))
+}
+
+func TestProxyOffline(t *testing.T) {
+	require := require.New(t)
+
+	clock := &fakeClock{now: testQEIdentityNextUpdate.Add(-time.Hour)}
+	cache, err := newCache(nil, clock.get)
+	require.NoError(err, "newCache")
+	proxy, err := New(cache, Config{
+		RefreshThreshold: 24 * time.Hour,
+	})
+	require.NoError(err, "New")
+	require.True(proxy.IsOffline())
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+	proxyURL := srv.URL
+
+	require.Equal(http.StatusNotFound, getTestCollateral(t, proxyURL, testPCKCRLPath))
+
+	for _, entry := range loadTestEntries(t) {
+		_, err = proxy.Cache().Put(entry)
+		require.NoError(err, "Put")
+	}
+	require.Equal(http.StatusOK, getTestCollateral(t, proxyURL, testPCKCRLPath))
+
+	// Nodes can fetch the TCB bundle through the proxy.
+	client, err := cmnPCS.NewHTTPClient(&cmnPCS.HTTPClientConfig{
+		BaseURL: proxyURL,
+	})
+	require.NoError(err, "NewHTTPClient")
+	fmspc := []byte{0x00, 0x60, 0x6a, 0x00, 0x00, 0x00}
+	bundle, err := client.GetTCBBundle(context.Background(), cmnPCS.TeeTypeSGX, fmspc, cmnPCS.UpdateEarly)
+	require.NoError(err, "GetTCBBundle")
+	require.NotEmpty(bundle.TCBInfo.Signature)
+	require.NotEmpty(bundle.QEIdentity.Signature)
+
+	// Once the QE identity expires, the bundle can no longer be fetched.
+	clock.now = testQEIdentityNextUpdate
+	_, err = client.GetTCBBundle(context.Background(), cmnPCS.TeeTypeSGX, fmspc, cmnPCS.UpdateEarly)
+	require.Error(err, "GetTCBBundle should fail with expired collateral")
+
+	// Refreshing an offline proxy prunes expired collateral.
+	proxy.Refresh(context.Background())
+	require.Len(proxy.Cache().Entries(), 2)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -106,6 +107,7 @@ func EarlyLogAndExit(err error) {
 func Init() error {
 	initFns := []func() error{
 		initDataDir,
+		initCrashDumps,
 		initLogging,
 		initPublicKeyBlacklist,
 		initDebugEnclaves,
@@ -180,6 +182,18 @@ func initDataDir() error {
 	return common.Mkdir(dataDir)
 }
 
+func initCrashDumps() error {
+	cfg := config.GlobalConfig.Common.CrashDumps
+	dataDir := config.GlobalConfig.Common.DataDir
+	if !cfg.Enabled || dataDir == "" {
+		return nil
+	}
+	return crashdump.Init(filepath.Join(dataDir, crashdump.DirName), &crashdump.Config{
+		MaxRequestSize: cfg.MaxRequestSize,
+		LogLines:       cfg.LogLines,
+	})
+}
+
 func normalizePath(f string) string {
 	if !filepath.IsAbs(f) {
 		dataDir := config.GlobalConfig.Common.DataDir
@@ -1,6 +1,12 @@
 // Package config implements global configuration options.
 package config
 
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
 // Config is the common configuration structure.
 type Config struct {
 	// Node's data directory.
@@ -9,10 +15,44 @@ type Config struct {
 	InternalSocketPath string `yaml:"internal_socket_path,omitempty"`
 	// Logging configuration options.
 	Log LogConfig `yaml:"log,omitempty"`
+	// External gRPC server configuration options.
+	Grpc GrpcConfig `yaml:"grpc,omitempty"`
+	// Crash dump configuration options.
+	CrashDumps CrashDumpsConfig `yaml:"crash_dumps,omitempty"`
+	// Event journal configuration options.
+	EventJournal EventJournalConfig `yaml:"event_journal,omitempty"`
 	// Debug configuration options (do not use).
 	Debug DebugConfig `yaml:"debug,omitempty"`
 }
 
+// CrashDumpsConfig is the crash dump configuration structure.
+type CrashDumpsConfig struct {
+	// Write a crash dump to the data directory on panics in the consensus and executor paths.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Maximum size of the in-flight request body included in a crash dump (in bytes).
+	MaxRequestSize uint64 `yaml:"max_request_size,omitempty"`
+	// Number of recent log lines included in a crash dump.
+	LogLines uint64 `yaml:"log_lines,omitempty"`
+}
+
+// EventJournalConfig is the event journal configuration structure.
+type EventJournalConfig struct {
+	// Maximum number of significant node lifecycle events retained in the journal (0 disables
+	// the journal).
+	MaxEvents uint64 `yaml:"max_events,omitempty"`
+}
+
+// GrpcConfig is the external gRPC server configuration structure.
+type GrpcConfig struct {
+	// TCP port of the external gRPC server exposing the runtime client (0 disables the server).
+	Port uint16 `yaml:"port,omitempty"`
+	// Access policies (allowlist, registered) keyed by full method name. Methods that are not
+	// listed are open to all callers.
+	MethodPolicies map[string]string `yaml:"method_policies,omitempty"`
+	// Public keys of TLS client certificates allowed to call restricted methods.
+	Allowlist []string `yaml:"allowlist,omitempty"`
+}
+
 // LogConfig is the common logging configuration structure.
 type LogConfig struct {
 	// Log file.
@@ -21,6 +61,8 @@ type LogConfig struct {
 	Format string `yaml:"format,omitempty"`
 	// Log level (debug, info, warn, error) per module.
 	Level map[string]string `yaml:"level,omitempty"`
+	// Persist log levels changed via the control API across restarts.
+	PersistLevels bool `yaml:"persist_levels,omitempty"`
 }
 
 // DebugConfig is the common debug configuration structure.
@@ -33,6 +75,19 @@ type DebugConfig struct {
 
 // Validate validates the configuration settings.
 func (c *Config) Validate() error {
+	for method, policy := range c.Grpc.MethodPolicies {
+		switch policy {
+		case "allowlist", "registered":
+		default:
+			return fmt.Errorf("grpc.method_policies: unknown policy for method %s: %s", method, policy)
+		}
+	}
+	for _, key := range c.Grpc.Allowlist {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(key)); err != nil {
+			return fmt.Errorf("grpc.allowlist: malformed public key %s: %w", key, err)
+		}
+	}
 	return nil
 }
 
@@ -50,6 +105,14 @@ func DefaultConfig() Config {
 				"mkvs/db":           "info",  // Debug logs are too verbose and not very useful.
 			},
 		},
+		CrashDumps: CrashDumpsConfig{
+			Enabled:        false,
+			MaxRequestSize: 64 * 1024,
+			LogLines:       1000,
+		},
+		EventJournal: EventJournalConfig{
+			MaxEvents: 10_000,
+		},
 		Debug: DebugConfig{
 			AllowRoot: false,
 			Rlimit:    0,
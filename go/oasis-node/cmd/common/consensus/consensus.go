@@ -5,19 +5,25 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	signerFile "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
 	signerPlugin "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/plugin"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
 	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdContext "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/context"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 )
@@ -37,11 +43,20 @@ const (
 
 	// CfgTxUnsigned makes SaveTx save an unsigned transaction.
 	CfgTxUnsigned = "transaction.unsigned"
+
+	// CfgTxSigningBundle configures the path to the signing context bundle which is used instead
+	// of the genesis document when constructing transactions offline.
+	CfgTxSigningBundle = "transaction.signing_bundle"
+
+	// CfgTxSigningBundleMaxAge configures the age after which the signing context bundle is
+	// considered stale.
+	CfgTxSigningBundleMaxAge = "transaction.signing_bundle_max_age"
 )
 
 var (
-	TxFlags     = flag.NewFlagSet("", flag.ContinueOnError)
-	TxFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	TxFlags            = flag.NewFlagSet("", flag.ContinueOnError)
+	TxFileFlags        = flag.NewFlagSet("", flag.ContinueOnError)
+	SigningBundleFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	logger = logging.GetLogger("cmd/common/consensus")
 )
@@ -73,6 +88,85 @@ func InitGenesis() *genesisAPI.Document {
 	return genesisDoc
 }
 
+// LoadSigningContextBundle loads the signing context bundle from the given file.
+func LoadSigningContextBundle(fn string) (*consensus.SigningContextBundle, error) {
+	raw, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing context bundle: %w", err)
+	}
+
+	var bundle consensus.SigningContextBundle
+	if err = cbor.Unmarshal(raw, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse signing context bundle: %w", err)
+	}
+	if err = bundle.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// InitSigningContext configures the chain domain separation context from the configured signing
+// context bundle or, in case no bundle is configured, from the genesis document.
+//
+// It returns a context with additional information used when pretty-printing transactions and
+// the signing context bundle, if any.
+func InitSigningContext() (context.Context, *consensus.SigningContextBundle) {
+	fn := viper.GetString(CfgTxSigningBundle)
+	if fn == "" {
+		return cmdContext.GetCtxWithGenesisInfo(InitGenesis()), nil
+	}
+
+	bundle, err := LoadSigningContextBundle(fn)
+	if err != nil {
+		logger.Error("failed to load signing context bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if maxAge := viper.GetDuration(CfgTxSigningBundleMaxAge); bundle.IsStale(time.Now(), maxAge) {
+		logger.Warn("signing context bundle is stale, consensus parameters may have changed",
+			"height", bundle.Height,
+			"age", bundle.Age(time.Now()),
+			"max_age", maxAge,
+		)
+	}
+
+	var genesisHash hash.Hash
+	if err = genesisHash.UnmarshalHex(bundle.ChainContext); err != nil {
+		logger.Error("malformed chain context in signing context bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	signature.SetChainContext(bundle.ChainContext)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, bundle.TokenSymbol)
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, bundle.TokenValueExponent)
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyGenesisHash, genesisHash)
+	return ctx, bundle
+}
+
+// CheckTxFee warns in case the given transaction fee is below the minimum fee required by
+// the consensus parameters in the given signing context bundle.
+func CheckTxFee(fee *transaction.Fee, bundle *consensus.SigningContextBundle) {
+	if bundle == nil || fee == nil {
+		return
+	}
+
+	minFee := quantity.NewFromUint64(bundle.Consensus.MinGasPrice)
+	if err := minFee.Mul(quantity.NewFromUint64(uint64(fee.Gas))); err != nil {
+		return
+	}
+	if fee.Amount.Cmp(minFee) < 0 {
+		logger.Warn("transaction fee is below the minimum fee, transaction will be rejected",
+			"fee", fee.Amount,
+			"min_fee", minFee,
+			"min_gas_price", bundle.Consensus.MinGasPrice,
+		)
+	}
+}
+
 func GetTxNonceAndFee() (uint64, *transaction.Fee) {
 	var fee transaction.Fee
 	nonce := viper.GetUint64(CfgTxNonce)
@@ -86,7 +180,53 @@ func GetTxNonceAndFee() (uint64, *transaction.Fee) {
 	return nonce, &fee
 }
 
+// SignAndSaveTx signs the transaction and saves it as pretty-printed JSON to the configured
+// transaction file.
+//
+// In case an unsigned transaction was requested, the CBOR-encoded unsigned transaction is saved
+// instead.
 func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction, signer signature.Signer) {
+	sigTx := signTx(ctx, tx, signer)
+	if sigTx == nil {
+		return
+	}
+
+	prettySigTx, err := cmdCommon.PrettyJSONMarshal(sigTx)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(viper.GetString(CfgTxFile), prettySigTx, 0o600); err != nil {
+		logger.Error("failed to save signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// SignAndSaveRawTx signs the transaction and saves it as CBOR to the configured transaction file.
+//
+// In case an unsigned transaction was requested, the CBOR-encoded unsigned transaction is saved
+// instead.
+func SignAndSaveRawTx(ctx context.Context, tx *transaction.Transaction, signer signature.Signer) {
+	sigTx := signTx(ctx, tx, signer)
+	if sigTx == nil {
+		return
+	}
+
+	if err := os.WriteFile(viper.GetString(CfgTxFile), cbor.Marshal(sigTx), 0o600); err != nil {
+		logger.Error("failed to save signed transaction",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// signTx signs the given transaction, returning nil in case an unsigned transaction was
+// requested, in which case the unsigned transaction is saved.
+func signTx(ctx context.Context, tx *transaction.Transaction, signer signature.Signer) *transaction.SignedTransaction {
 	if viper.GetBool(CfgTxUnsigned) {
 		rawUnsignedTx := cbor.Marshal(tx)
 		if err := os.WriteFile(viper.GetString(CfgTxFile), rawUnsignedTx, 0o600); err != nil {
@@ -95,7 +235,7 @@ func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction, signer sign
 			)
 			os.Exit(1)
 		}
-		return
+		return nil
 	}
 
 	if signer == nil {
@@ -133,20 +273,7 @@ func SignAndSaveTx(ctx context.Context, tx *transaction.Transaction, signer sign
 		)
 		os.Exit(1)
 	}
-
-	prettySigTx, err := cmdCommon.PrettyJSONMarshal(sigTx)
-	if err != nil {
-		logger.Error("failed to get pretty JSON of signed transaction",
-			"err", err,
-		)
-		os.Exit(1)
-	}
-	if err = os.WriteFile(viper.GetString(CfgTxFile), prettySigTx, 0o600); err != nil {
-		logger.Error("failed to save signed transaction",
-			"err", err,
-		)
-		os.Exit(1)
-	}
+	return sigTx
 }
 
 func init() {
@@ -163,4 +290,8 @@ func init() {
 	TxFlags.AddFlagSet(cmdSigner.Flags)
 	TxFlags.AddFlagSet(cmdSigner.CLIFlags)
 	TxFlags.AddFlagSet(cmdFlags.GenesisFileFlags)
+
+	SigningBundleFlags.String(CfgTxSigningBundle, "", "path to the signing context bundle (used instead of the genesis document)")
+	SigningBundleFlags.Duration(CfgTxSigningBundleMaxAge, 24*time.Hour, "age after which the signing context bundle is considered stale (0 to disable)")
+	_ = viper.BindPFlags(SigningBundleFlags)
 }
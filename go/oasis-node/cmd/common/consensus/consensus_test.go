@@ -0,0 +1,49 @@
+package consensus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+)
+
+func TestLoadSigningContextBundle(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	bundle := consensus.SigningContextBundle{
+		Versioned:          cbor.NewVersioned(consensus.LatestSigningContextBundleVersion),
+		Height:             42,
+		Hash:               hash.NewFromBytes([]byte("block")),
+		Time:               now.Add(-2 * time.Hour).Round(time.Second),
+		ChainContext:       hash.NewFromBytes([]byte("genesis")).Hex(),
+		TokenSymbol:        "TEST",
+		TokenValueExponent: 9,
+	}
+	bundle.Consensus.MinGasPrice = 10
+
+	fn := filepath.Join(t.TempDir(), "bundle.cbor")
+	require.NoError(os.WriteFile(fn, cbor.Marshal(bundle), 0o600))
+
+	loaded, err := LoadSigningContextBundle(fn)
+	require.NoError(err, "LoadSigningContextBundle")
+	require.EqualValues(bundle.Consensus, loaded.Consensus)
+	require.Equal(bundle.ChainContext, loaded.ChainContext)
+	require.True(bundle.Time.Equal(loaded.Time))
+
+	require.True(loaded.IsStale(now, time.Hour), "bundle older than max age should be stale")
+	require.False(loaded.IsStale(now, 3*time.Hour), "bundle younger than max age should not be stale")
+	require.False(loaded.IsStale(now, 0), "bundle should never be stale without a max age")
+
+	// Bundles with an unsupported version should be rejected.
+	bundle.V = consensus.LatestSigningContextBundleVersion + 1
+	require.NoError(os.WriteFile(fn, cbor.Marshal(bundle), 0o600))
+	_, err = LoadSigningContextBundle(fn)
+	require.Error(err, "LoadSigningContextBundle should fail with an unsupported version")
+}
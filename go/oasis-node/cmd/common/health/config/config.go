@@ -0,0 +1,43 @@
+// Package config implements global configuration options.
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+)
+
+// Config is the health probe configuration structure.
+type Config struct {
+	// Enable the plain HTTP liveness and readiness probe endpoint at given address.
+	BindAddress string `yaml:"bind_address"`
+
+	// StalenessThreshold is the maximum age of the last heartbeat of an internal service loop
+	// after which the node is no longer considered live.
+	StalenessThreshold time.Duration `yaml:"staleness_threshold"`
+	// ServiceStalenessThresholds are per-service staleness thresholds keyed by service name
+	// prefix (e.g., consensus, p2p, worker/executor), overriding the default threshold.
+	ServiceStalenessThresholds map[string]time.Duration `yaml:"service_staleness_thresholds,omitempty"`
+}
+
+// Validate validates the configuration settings.
+func (c *Config) Validate() error {
+	if c.StalenessThreshold <= heartbeat.Interval {
+		return fmt.Errorf("staleness_threshold must be greater than %s", heartbeat.Interval)
+	}
+	for service, threshold := range c.ServiceStalenessThresholds {
+		if threshold <= heartbeat.Interval {
+			return fmt.Errorf("service_staleness_thresholds: threshold for %s must be greater than %s", service, heartbeat.Interval)
+		}
+	}
+	return nil
+}
+
+// DefaultConfig returns the default configuration settings.
+func DefaultConfig() Config {
+	return Config{
+		BindAddress:        "",
+		StalenessThreshold: heartbeat.DefaultThreshold,
+	}
+}
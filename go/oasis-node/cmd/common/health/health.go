@@ -0,0 +1,130 @@
+// Package health implements a plain HTTP liveness and readiness probe service.
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	"github.com/oasisprotocol/oasis-core/go/config"
+)
+
+const (
+	// LivenessPath is the path of the liveness probe endpoint.
+	LivenessPath = "/livez"
+	// ReadinessPath is the path of the readiness probe endpoint.
+	ReadinessPath = "/readyz"
+
+	probeTimeout = 5 * time.Second
+)
+
+// Checker checks the liveness and readiness of a node.
+type Checker interface {
+	// IsLive checks whether the node's internal services are responsive.
+	IsLive(ctx context.Context) (bool, error)
+
+	// IsReady checks whether the node is ready to accept work.
+	IsReady(ctx context.Context) (bool, error)
+}
+
+type healthService struct {
+	service.BaseBackgroundService
+
+	address string
+	checker Checker
+
+	listener net.Listener
+	server   *http.Server
+}
+
+func (h *healthService) Start() error {
+	if h.address == "" {
+		return nil
+	}
+
+	h.Logger.Info("health probe HTTP endpoint is enabled",
+		"address", h.address,
+	)
+
+	listener, err := net.Listen("tcp", h.address)
+	if err != nil {
+		return err
+	}
+
+	h.listener = listener
+	h.server = &http.Server{Handler: h.handler(), ReadTimeout: 5 * time.Second}
+
+	go func() {
+		if err := h.server.Serve(h.listener); err != nil {
+			if err != http.ErrServerClosed {
+				h.Logger.Error("health probe server terminated uncleanly",
+					"err", err,
+				)
+			}
+		}
+		h.BaseBackgroundService.Stop()
+	}()
+
+	return nil
+}
+
+func (h *healthService) handler() http.Handler {
+	probe := func(check func(ctx context.Context) (bool, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+			defer cancel()
+
+			ok, err := check(ctx)
+			switch {
+			case err != nil:
+				h.Logger.Debug("health probe failed",
+					"path", r.URL.Path,
+					"err", err,
+				)
+				http.Error(w, "error", http.StatusServiceUnavailable)
+			case !ok:
+				http.Error(w, "not ok", http.StatusServiceUnavailable)
+			default:
+				_, _ = w.Write([]byte("ok\n"))
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, probe(h.checker.IsLive))
+	mux.HandleFunc(ReadinessPath, probe(h.checker.IsReady))
+	return mux
+}
+
+func (h *healthService) Stop() {
+	// If we never started, make sure that the service doesn't hang forever.
+	if h.address == "" {
+		h.BaseBackgroundService.Stop()
+		return
+	}
+
+	if h.server != nil {
+		_ = h.server.Close()
+		h.server = nil
+	}
+}
+
+func (h *healthService) Cleanup() {
+	if h.listener != nil {
+		_ = h.listener.Close()
+		h.listener = nil
+	}
+}
+
+// New constructs a new health probe service.
+func New(checker Checker) (service.BackgroundService, error) {
+	address := config.GlobalConfig.Health.BindAddress
+
+	return &healthService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("health"),
+		address:               address,
+		checker:               checker,
+	}, nil
+}
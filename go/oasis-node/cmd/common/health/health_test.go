@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+)
+
+type testChecker struct {
+	live, ready bool
+	err         error
+}
+
+func (c *testChecker) IsLive(context.Context) (bool, error) {
+	return c.live, c.err
+}
+
+func (c *testChecker) IsReady(context.Context) (bool, error) {
+	return c.ready, c.err
+}
+
+func TestProbes(t *testing.T) {
+	require := require.New(t)
+
+	checker := &testChecker{}
+	h := &healthService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("health/test"),
+		checker:               checker,
+	}
+	srv := httptest.NewServer(h.handler())
+	defer srv.Close()
+
+	requireStatus := func(path string, expected int) {
+		rsp, err := http.Get(srv.URL + path)
+		require.NoError(err, "Get %s", path)
+		defer rsp.Body.Close()
+		require.Equal(expected, rsp.StatusCode, "status of %s", path)
+	}
+
+	requireStatus(LivenessPath, http.StatusServiceUnavailable)
+	requireStatus(ReadinessPath, http.StatusServiceUnavailable)
+
+	checker.live = true
+	requireStatus(LivenessPath, http.StatusOK)
+	requireStatus(ReadinessPath, http.StatusServiceUnavailable)
+
+	checker.ready = true
+	requireStatus(LivenessPath, http.StatusOK)
+	requireStatus(ReadinessPath, http.StatusOK)
+
+	checker.err = fmt.Errorf("failed")
+	requireStatus(LivenessPath, http.StatusServiceUnavailable)
+	requireStatus(ReadinessPath, http.StatusServiceUnavailable)
+
+	requireStatus("/", http.StatusNotFound)
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/config"
 )
@@ -45,5 +46,8 @@ func initLogging() error {
 		}
 	}
 
+	// Retain recent log output for inclusion in crash dumps, if enabled.
+	w = crashdump.WrapLogWriter(w)
+
 	return logging.Initialize(w, logFmt, logLevel, moduleLevels)
 }
@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/file"
+)
+
+const (
+	// CfgFileEncrypt is the flag used to specify whether newly generated
+	// file signer keys should be encrypted with a passphrase.
+	CfgFileEncrypt = "signer.file.encrypt"
+
+	// CfgFilePassphraseFile is the flag used to specify the path to a file
+	// containing the file signer passphrase.
+	CfgFilePassphraseFile = "signer.file.passphrase_file"
+
+	// EnvFilePassphrase is the environment variable used to specify the
+	// file signer passphrase.
+	EnvFilePassphrase = "OASIS_SIGNER_PASSPHRASE"
+)
+
+var errNotTerminal = errors.New("not a terminal")
+
+// NewFileFactory returns a file backed SignerFactory configured based on
+// flags.
+//
+// Encrypted keys are decrypted with the passphrase obtained by Passphrase,
+// which is only requested once an encrypted key is encountered, so that
+// unencrypted keys keep loading without any passphrase being configured.
+func NewFileFactory(dataDir string, roles ...signature.SignerRole) (signature.SignerFactory, error) {
+	return fileSigner.NewFactory(&fileSigner.FactoryConfig{
+		DataDir:    dataDir,
+		Passphrase: Passphrase,
+		Encrypt:    viper.GetBool(CfgFileEncrypt),
+	}, roles...)
+}
+
+// Passphrase returns the file signer passphrase.
+//
+// See ReadPassphrase for the passphrase sources.
+func Passphrase() ([]byte, error) {
+	return ReadPassphrase(CfgFilePassphraseFile, EnvFilePassphrase, "Enter key passphrase: ", false)
+}
+
+// ReadPassphrase reads a passphrase from the file configured by the given
+// flag, the given environment variable or, when the standard input is a
+// terminal, an interactive prompt (in that order).
+//
+// In case no source is available (e.g., when running as a service), an error
+// wrapping signature.ErrPassphraseRequired is returned instead of blocking.
+func ReadPassphrase(fileFlag, envVar, prompt string, confirm bool) ([]byte, error) {
+	if fn := viper.GetString(fileFlag); fn != "" {
+		passphrase, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase = bytes.TrimRight(passphrase, "\r\n")
+		if len(passphrase) == 0 {
+			return nil, fmt.Errorf("passphrase file %s is empty", fn)
+		}
+		return passphrase, nil
+	}
+
+	if passphrase := os.Getenv(envVar); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	passphrase, err := readPassword(fd, prompt)
+	switch {
+	case errors.Is(err, errNotTerminal):
+		return nil, fmt.Errorf("%w: not running interactively, use --%s or set %s",
+			signature.ErrPassphraseRequired, fileFlag, envVar,
+		)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+
+	confirmation, err := readPassword(fd, "Confirm passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if !bytes.Equal(passphrase, confirmation) {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
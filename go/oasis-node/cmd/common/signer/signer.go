@@ -49,6 +49,9 @@ var (
 	// CLIFlags has the oasis-node specific signer related flags.
 	CLIFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
+	// FileFlags has the file signer related flags.
+	FileFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	testingAllowMemory bool
 )
 
@@ -88,7 +91,7 @@ func NewFactory(signerBackend, signerDir string, roles ...signature.SignerRole)
 func doNewFactory(signerBackend, signerDir string, roles ...signature.SignerRole) (signature.SignerFactory, error) {
 	switch signerBackend {
 	case fileSigner.SignerName:
-		return fileSigner.NewFactory(signerDir, roles...)
+		return NewFileFactory(signerDir, roles...)
 	case memorySigner.SignerName:
 		if !testingAllowMemory {
 			return nil, fmt.Errorf("memory signer backend is only for testing")
@@ -189,6 +192,11 @@ func init() {
 	Flags.String(cfgSignerPluginPath, "", "plugin signer binary path")
 	Flags.String(cfgSignerPluginConfig, "", "plugin signer configuration")
 
+	FileFlags.Bool(CfgFileEncrypt, false, "encrypt newly generated file signer keys with a passphrase")
+	FileFlags.String(CfgFilePassphraseFile, "", "path to file containing the file signer passphrase (alternatively set "+EnvFilePassphrase+")")
+	_ = viper.BindPFlags(FileFlags)
+	Flags.AddFlagSet(FileFlags)
+
 	_ = viper.BindPFlags(Flags)
 
 	CLIFlags.String(CfgCLISignerDir, "", "path to directory containing the entity files. If file signer backend is being used, the directory must also contain the private key. If blank, defaults to the working directory.")
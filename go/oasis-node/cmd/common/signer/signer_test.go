@@ -3,6 +3,7 @@ package signer
 import (
 	"crypto/rand"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -51,3 +52,56 @@ func TestCompositeCtor(t *testing.T) {
 	require.NoError(err, "Generate: memory")
 	require.IsType(&memorySigner.Signer{}, signer2, "Generate: memory")
 }
+
+func TestReadPassphrase(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := os.MkdirTemp("", "oasis-node-test_signer_")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dataDir)
+
+	// Non-interactive context without a passphrase.
+	t.Setenv(EnvFilePassphrase, "")
+	_, err = Passphrase()
+	require.ErrorIs(err, signature.ErrPassphraseRequired, "Passphrase: not available")
+
+	// Environment variable.
+	t.Setenv(EnvFilePassphrase, "from env")
+	passphrase, err := Passphrase()
+	require.NoError(err, "Passphrase: env")
+	require.EqualValues("from env", passphrase)
+
+	// Passphrase file takes precedence, trailing newlines are ignored.
+	fn := filepath.Join(dataDir, "passphrase")
+	err = os.WriteFile(fn, []byte("from file\n"), 0o600)
+	require.NoError(err, "WriteFile")
+	viper.Set(CfgFilePassphraseFile, fn)
+	defer viper.Set(CfgFilePassphraseFile, "")
+
+	passphrase, err = Passphrase()
+	require.NoError(err, "Passphrase: file")
+	require.EqualValues("from file", passphrase)
+
+	// Encrypted keys are generated and loaded using the configured passphrase.
+	viper.Set(CfgFileEncrypt, true)
+	defer viper.Set(CfgFileEncrypt, false)
+
+	sf, err := NewFileFactory(dataDir, signature.SignerNode)
+	require.NoError(err, "NewFileFactory")
+	signer, err := sf.Generate(signature.SignerNode, rand.Reader)
+	require.NoError(err, "Generate")
+
+	viper.Set(CfgFilePassphraseFile, "")
+	t.Setenv(EnvFilePassphrase, "")
+	sf, err = NewFileFactory(dataDir, signature.SignerNode)
+	require.NoError(err, "NewFileFactory")
+	_, err = sf.Load(signature.SignerNode)
+	require.ErrorIs(err, signature.ErrPassphraseRequired, "Load: passphrase not available")
+
+	t.Setenv(EnvFilePassphrase, "from file")
+	sf, err = NewFileFactory(dataDir, signature.SignerNode)
+	require.NoError(err, "NewFileFactory")
+	signer2, err := sf.Load(signature.SignerNode)
+	require.NoError(err, "Load")
+	require.Equal(signer.Public(), signer2.Public(), "Generated = Loaded")
+}
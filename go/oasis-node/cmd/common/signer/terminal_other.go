@@ -0,0 +1,71 @@
+//go:build !windows
+// +build !windows
+
+package signer
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	cmnSyscall "github.com/oasisprotocol/oasis-core/go/common/syscall"
+)
+
+func termiosIoctl(fd int, req uintptr, attrs *syscall.Termios) error {
+	_, _, errno := syscall.Syscall6(
+		syscall.SYS_IOCTL,
+		uintptr(fd),
+		req,
+		uintptr(unsafe.Pointer(attrs)),
+		0,
+		0,
+		0,
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// readPassword prints the prompt and reads a line from the terminal with echo disabled.
+func readPassword(fd int, prompt string) ([]byte, error) {
+	var attrs syscall.Termios
+	if err := termiosIoctl(fd, cmnSyscall.IoctlTermiosGetAttr, &attrs); err != nil {
+		return nil, errNotTerminal
+	}
+
+	noEcho := attrs
+	noEcho.Lflag &^= syscall.ECHO
+	noEcho.Lflag |= syscall.ICANON | syscall.ISIG
+	noEcho.Iflag |= syscall.ICRNL
+	if err := termiosIoctl(fd, cmnSyscall.IoctlTermiosSetAttr, &noEcho); err != nil {
+		return nil, fmt.Errorf("failed to disable terminal echo: %w", err)
+	}
+	defer func() {
+		_ = termiosIoctl(fd, cmnSyscall.IoctlTermiosSetAttr, &attrs)
+	}()
+
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+
+	var (
+		line []byte
+		buf  [1]byte
+	)
+	for {
+		n, err := syscall.Read(fd, buf[:])
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 || buf[0] == '\n' {
+			break
+		}
+		line = append(line, buf[0])
+	}
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+
+	return line, nil
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package signer
+
+// readPassword prints the prompt and reads a line from the terminal with echo disabled.
+func readPassword(int, string) ([]byte, error) {
+	return nil, errNotTerminal
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
@@ -25,11 +26,22 @@ import (
 const (
 	// CfgSignerPub is the public key of the account that will sign an unsigned transaction in estimate gas.
 	CfgSignerPub = "consensus.signer_pub"
+
+	// CfgHeight is the consensus height at which the signing context bundle is taken.
+	CfgHeight = "consensus.height"
+
+	// CfgFromHeight is the consensus height of the parameters to compare against.
+	CfgFromHeight = "consensus.from_height"
+	// CfgToHeight is the consensus height of the compared parameters.
+	CfgToHeight = "consensus.to_height"
 )
 
 var (
 	signerPub string
 
+	signingBundleFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	parametersDiffFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
 	consensusCmd = &cobra.Command{
 		Use:        "consensus",
 		Short:      "consensus backend commands",
@@ -63,6 +75,18 @@ var (
 		Deprecated: "use the `oasis` CLI instead.",
 	}
 
+	signingBundleCmd = &cobra.Command{
+		Use:   "signing_bundle",
+		Short: "fetch a signing context bundle for constructing transactions offline",
+		Run:   doSigningBundle,
+	}
+
+	parametersDiffCmd = &cobra.Command{
+		Use:   "parameters_diff",
+		Short: "show consensus parameters that changed between two heights",
+		Run:   doParametersDiff,
+	}
+
 	logger = logging.GetLogger("cmd/consensus")
 )
 
@@ -88,8 +112,14 @@ func loadTx() *transaction.SignedTransaction {
 		os.Exit(1)
 	}
 
+	// Transactions are either pretty-printed JSON or CBOR (as generated by gen_tx).
+	unmarshal := cbor.Unmarshal
+	if json.Valid(rawTx) {
+		unmarshal = json.Unmarshal
+	}
+
 	var tx transaction.SignedTransaction
-	if err = json.Unmarshal(rawTx, &tx); err != nil {
+	if err = unmarshal(rawTx, &tx); err != nil {
 		logger.Error("failed to parse serialized transaction",
 			"err", err,
 		)
@@ -207,6 +237,56 @@ func doNextBlockState(cmd *cobra.Command, _ []string) {
 	fmt.Println(string(prettyStatus))
 }
 
+func doSigningBundle(cmd *cobra.Command, _ []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	fn := viper.GetString(cmdConsensus.CfgTxSigningBundle)
+	if fn == "" {
+		logger.Error("failed to determine signing context bundle file")
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	bundle, err := client.Core().GetSigningContextBundle(context.Background(), viper.GetInt64(CfgHeight))
+	if err != nil {
+		logger.Error("failed to fetch signing context bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(fn, cbor.Marshal(bundle), 0o600); err != nil {
+		logger.Error("failed to save signing context bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doParametersDiff(cmd *cobra.Command, _ []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	diff, err := client.Core().GetParametersDiff(context.Background(), &consensus.GetParametersDiffRequest{
+		FromHeight: viper.GetInt64(CfgFromHeight),
+		ToHeight:   viper.GetInt64(CfgToHeight),
+	})
+	if err != nil {
+		logger.Error("failed to fetch consensus parameters diff",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	diff.PrettyPrint(context.Background(), "", os.Stdout)
+}
+
 // Register registers the consensus sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	for _, v := range []*cobra.Command{
@@ -214,6 +294,9 @@ func Register(parentCmd *cobra.Command) {
 		showTxCmd,
 		estimateGasCmd,
 		nextBlockStateCmd,
+		signingBundleCmd,
+		parametersDiffCmd,
+		genTxCmd,
 	} {
 		consensusCmd.AddCommand(v)
 	}
@@ -230,5 +313,26 @@ func Register(parentCmd *cobra.Command) {
 
 	nextBlockStateCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	signingBundleCmd.Flags().AddFlagSet(signingBundleFlags)
+	signingBundleCmd.Flags().AddFlagSet(cmdConsensus.SigningBundleFlags)
+	signingBundleCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	parametersDiffCmd.Flags().AddFlagSet(parametersDiffFlags)
+	parametersDiffCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	genTxCmd.Long = genTxLongHelp()
+	genTxCmd.Flags().AddFlagSet(genTxFlags)
+	genTxCmd.Flags().AddFlagSet(cmdConsensus.TxFlags)
+	genTxCmd.Flags().AddFlagSet(cmdConsensus.SigningBundleFlags)
+
 	parentCmd.AddCommand(consensusCmd)
 }
+
+func init() {
+	signingBundleFlags.Int64(CfgHeight, consensus.HeightLatest, "consensus height (0 for the latest height)")
+	_ = viper.BindPFlags(signingBundleFlags)
+
+	parametersDiffFlags.Int64(CfgFromHeight, consensus.HeightLatest, "consensus height of the parameters to compare against (0 for the latest height)")
+	parametersDiffFlags.Int64(CfgToHeight, consensus.HeightLatest, "consensus height of the compared parameters (0 for the latest height)")
+	_ = viper.BindPFlags(parametersDiffFlags)
+}
@@ -0,0 +1,160 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+const (
+	// CfgTxBody configures the JSON-encoded transaction body.
+	CfgTxBody = "transaction.body"
+
+	// CfgTxBodyFile configures the path to the JSON-encoded transaction body.
+	CfgTxBodyFile = "transaction.body_file"
+)
+
+var (
+	genTxFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	genTxCmd = &cobra.Command{
+		Use:   "gen_tx <method>",
+		Short: "Generate a consensus transaction",
+		Args:  cobra.ExactArgs(1),
+		Run:   doGenTx,
+	}
+
+	// genTxMethods are the consensus transaction methods supported by gen_tx.
+	//
+	// Methods that are only ever submitted by nodes themselves (e.g., executor commitments or
+	// key manager secrets) are intentionally not supported.
+	genTxMethods = []transaction.MethodName{
+		staking.MethodTransfer,
+		staking.MethodBurn,
+		staking.MethodAddEscrow,
+		staking.MethodReclaimEscrow,
+		staking.MethodAmendCommissionSchedule,
+		staking.MethodAllow,
+		staking.MethodWithdraw,
+		staking.MethodDelegateFromAllowance,
+		staking.MethodReclaimEscrowFromAllowance,
+		registry.MethodRegisterEntity,
+		registry.MethodDeregisterEntity,
+		registry.MethodRegisterNode,
+		registry.MethodUnfreezeNode,
+		registry.MethodRegisterRuntime,
+		roothash.MethodEvidence,
+		roothash.MethodSubmitMsg,
+		governance.MethodSubmitProposal,
+		governance.MethodCastVote,
+		secrets.MethodUpdatePolicy,
+		churp.MethodCreate,
+		churp.MethodUpdate,
+		vault.MethodCreate,
+		vault.MethodAuthorizeAction,
+		vault.MethodCancelAction,
+	}
+)
+
+func genTxLongHelp() string {
+	var b strings.Builder
+	b.WriteString("Generate a consensus transaction with the given method and JSON-encoded body.\n\n")
+	b.WriteString("The generated transaction is saved in CBOR format and can be submitted via submit_tx.\n\n")
+	b.WriteString("When a signing context bundle (see signing_bundle) is given, it is used instead of the\n")
+	b.WriteString("genesis document and the transaction fee is checked against the minimum gas price.\n\n")
+	b.WriteString("Supported methods:\n")
+	for _, method := range genTxMethods {
+		fmt.Fprintf(&b, "  %s\n", method)
+	}
+	return b.String()
+}
+
+// newTxBody decodes the JSON-encoded body of a transaction with the given method into the body
+// type registered for the method.
+func newTxBody(method transaction.MethodName, rawBody []byte) (any, error) {
+	if !slices.Contains(genTxMethods, method) {
+		return nil, fmt.Errorf("unsupported transaction method: %s", method)
+	}
+
+	body := reflect.New(reflect.TypeOf(method.BodyType())).Interface()
+	dec := json.NewDecoder(bytes.NewReader(rawBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(body); err != nil {
+		return nil, fmt.Errorf("malformed %s transaction body: %w", method, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("malformed %s transaction body: trailing data", method)
+	}
+	return body, nil
+}
+
+func loadTxBody() ([]byte, error) {
+	body, bodyFile := viper.GetString(CfgTxBody), viper.GetString(CfgTxBodyFile)
+	switch {
+	case body != "" && bodyFile != "":
+		return nil, fmt.Errorf("only one of '%s' and '%s' may be set", CfgTxBody, CfgTxBodyFile)
+	case bodyFile != "":
+		return os.ReadFile(bodyFile)
+	case body != "":
+		return []byte(body), nil
+	default:
+		return nil, fmt.Errorf("missing transaction body: either '%s' or '%s' required", CfgTxBody, CfgTxBodyFile)
+	}
+}
+
+func doGenTx(_ *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	ctx, bundle := cmdConsensus.InitSigningContext()
+	cmdConsensus.AssertTxFileOK()
+
+	rawBody, err := loadTxBody()
+	if err != nil {
+		logger.Error("failed to load transaction body",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	method := transaction.MethodName(args[0])
+	body, err := newTxBody(method, rawBody)
+	if err != nil {
+		logger.Error("failed to parse transaction body",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	nonce, fee := cmdConsensus.GetTxNonceAndFee()
+	cmdConsensus.CheckTxFee(fee, bundle)
+	tx := transaction.NewTransaction(nonce, fee, method, body)
+
+	cmdConsensus.SignAndSaveRawTx(ctx, tx, nil)
+}
+
+func init() {
+	genTxFlags.String(CfgTxBody, "", "JSON-encoded transaction body")
+	genTxFlags.String(CfgTxBodyFile, "", "path to the JSON-encoded transaction body")
+	_ = viper.BindPFlags(genTxFlags)
+}
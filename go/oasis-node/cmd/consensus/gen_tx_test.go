@@ -0,0 +1,172 @@
+package consensus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// decodeBody decodes the transaction body the same way as the consensus application that
+// handles the method does.
+func decodeBody[T any](raw []byte) (any, error) {
+	var body T
+	if err := cbor.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	return &body, nil
+}
+
+func TestGenTxRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	signature.SetChainContext("test: oasis-node/cmd/consensus gen_tx")
+	signer := memorySigner.NewTestSigner("oasis-node/cmd/consensus: gen_tx test")
+	addr := staking.NewAddress(signer.Public())
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"))
+
+	sigEnt, err := entity.SignEntity(signer, registry.RegisterEntitySignatureContext, &entity.Entity{
+		Versioned: cbor.NewVersioned(entity.LatestDescriptorVersion),
+		ID:        signer.Public(),
+	})
+	require.NoError(err, "SignEntity")
+	sigNode, err := node.MultiSignNode([]signature.Signer{signer}, registry.RegisterNodeSignatureContext, &node.Node{
+		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
+		ID:         signer.Public(),
+		EntityID:   signer.Public(),
+		Expiration: 42,
+		Roles:      node.RoleValidator,
+	})
+	require.NoError(err, "MultiSignNode")
+
+	policy := secrets.SignedPolicySGX{
+		Policy: secrets.PolicySGX{
+			Serial: 1,
+			ID:     runtimeID,
+		},
+	}
+	extraShares := uint8(2)
+
+	for _, tc := range []struct {
+		method transaction.MethodName
+		body   any
+		decode func([]byte) (any, error)
+	}{
+		{staking.MethodTransfer, &staking.Transfer{To: addr, Amount: *quantity.NewFromUint64(100)}, decodeBody[staking.Transfer]},
+		{staking.MethodBurn, &staking.Burn{Amount: *quantity.NewFromUint64(100)}, decodeBody[staking.Burn]},
+		{staking.MethodAddEscrow, &staking.Escrow{Account: addr, Amount: *quantity.NewFromUint64(100)}, decodeBody[staking.Escrow]},
+		{staking.MethodReclaimEscrow, &staking.ReclaimEscrow{Account: addr, Shares: *quantity.NewFromUint64(100)}, decodeBody[staking.ReclaimEscrow]},
+		{staking.MethodAmendCommissionSchedule, &staking.AmendCommissionSchedule{Amendment: staking.CommissionSchedule{
+			Rates: []staking.CommissionRateStep{{Start: 10, Rate: *quantity.NewFromUint64(5000)}},
+		}}, decodeBody[staking.AmendCommissionSchedule]},
+		{staking.MethodAllow, &staking.Allow{Beneficiary: addr, Negative: true, AmountChange: *quantity.NewFromUint64(100)}, decodeBody[staking.Allow]},
+		{staking.MethodWithdraw, &staking.Withdraw{From: addr, Amount: *quantity.NewFromUint64(100)}, decodeBody[staking.Withdraw]},
+		{staking.MethodDelegateFromAllowance, &staking.DelegateFromAllowance{From: addr, Account: addr, Amount: *quantity.NewFromUint64(100)}, decodeBody[staking.DelegateFromAllowance]},
+		{staking.MethodReclaimEscrowFromAllowance, &staking.ReclaimEscrowFromAllowance{From: addr, Account: addr, Shares: *quantity.NewFromUint64(100)}, decodeBody[staking.ReclaimEscrowFromAllowance]},
+		{registry.MethodRegisterEntity, sigEnt, decodeBody[entity.SignedEntity]},
+		{registry.MethodDeregisterEntity, &registry.DeregisterEntity{}, decodeBody[registry.DeregisterEntity]},
+		{registry.MethodRegisterNode, sigNode, decodeBody[node.MultiSignedNode]},
+		{registry.MethodUnfreezeNode, &registry.UnfreezeNode{NodeID: signer.Public()}, decodeBody[registry.UnfreezeNode]},
+		{registry.MethodRegisterRuntime, &registry.Runtime{
+			Versioned:       cbor.NewVersioned(registry.LatestRuntimeDescriptorVersion),
+			ID:              runtimeID,
+			EntityID:        signer.Public(),
+			Kind:            registry.KindCompute,
+			GovernanceModel: registry.GovernanceEntity,
+		}, decodeBody[registry.Runtime]},
+		{roothash.MethodEvidence, &roothash.Evidence{ID: runtimeID}, decodeBody[roothash.Evidence]},
+		{roothash.MethodSubmitMsg, &roothash.SubmitMsg{ID: runtimeID, Tag: 1, Data: []byte("data")}, decodeBody[roothash.SubmitMsg]},
+		{governance.MethodSubmitProposal, &governance.ProposalContent{
+			CancelUpgrade: &governance.CancelUpgradeProposal{ProposalID: 1},
+		}, decodeBody[governance.ProposalContent]},
+		{governance.MethodCastVote, &governance.ProposalVote{ID: 1, Vote: governance.VoteYes}, decodeBody[governance.ProposalVote]},
+		{secrets.MethodUpdatePolicy, &policy, decodeBody[secrets.SignedPolicySGX]},
+		{churp.MethodCreate, &churp.CreateRequest{
+			Identity:  churp.Identity{ID: 1, RuntimeID: runtimeID},
+			Threshold: 1,
+		}, decodeBody[churp.CreateRequest]},
+		{churp.MethodUpdate, &churp.UpdateRequest{
+			Identity:    churp.Identity{ID: 1, RuntimeID: runtimeID},
+			ExtraShares: &extraShares,
+		}, decodeBody[churp.UpdateRequest]},
+		{vault.MethodCreate, &vault.Create{
+			AdminAuthority:   vault.Authority{Addresses: []staking.Address{addr}, Threshold: 1},
+			SuspendAuthority: vault.Authority{Addresses: []staking.Address{addr}, Threshold: 1},
+		}, decodeBody[vault.Create]},
+		{vault.MethodAuthorizeAction, &vault.AuthorizeAction{
+			Vault:  addr,
+			Nonce:  1,
+			Action: vault.Action{Suspend: &vault.ActionSuspend{}},
+		}, decodeBody[vault.AuthorizeAction]},
+		{vault.MethodCancelAction, &vault.CancelAction{Vault: addr, Nonce: 1}, decodeBody[vault.CancelAction]},
+	} {
+		rawBody, err := json.Marshal(tc.body)
+		require.NoError(err, "json.Marshal(%s)", tc.method)
+
+		body, err := newTxBody(tc.method, rawBody)
+		require.NoError(err, "newTxBody(%s)", tc.method)
+
+		fee := &transaction.Fee{Gas: 1000}
+		tx := transaction.NewTransaction(1, fee, tc.method, body)
+		sigTx, err := transaction.Sign(signer, tx)
+		require.NoError(err, "Sign(%s)", tc.method)
+
+		// Decode the signed transaction from its CBOR encoding, as submit_tx does.
+		var decSigTx transaction.SignedTransaction
+		require.NoError(cbor.Unmarshal(cbor.Marshal(sigTx), &decSigTx), "Unmarshal(%s)", tc.method)
+		var decTx transaction.Transaction
+		require.NoError(decSigTx.Open(&decTx), "Open(%s)", tc.method)
+		require.Equal(tc.method, decTx.Method, "method")
+		require.EqualValues(1, decTx.Nonce, "nonce")
+
+		decBody, err := tc.decode(decTx.Body)
+		require.NoError(err, "decode(%s)", tc.method)
+		require.Equal(cbor.Marshal(tc.body), cbor.Marshal(decBody), "body should round-trip (%s)", tc.method)
+	}
+}
+
+func TestGenTxMethodsCovered(t *testing.T) {
+	require := require.New(t)
+
+	for _, method := range genTxMethods {
+		require.NotNil(method.BodyType(), "method %s should have a registered body type", method)
+	}
+	require.Len(genTxMethods, 24, "all supported methods should be covered by TestGenTxRoundTrip")
+}
+
+func TestGenTxMalformedBody(t *testing.T) {
+	require := require.New(t)
+
+	_, err := newTxBody(staking.MethodTransfer, []byte(`{"to":"oasis1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq","amount":"100"}`))
+	require.Error(err, "invalid address should fail")
+
+	_, err = newTxBody(staking.MethodTransfer, []byte(`{"amount":"100","recipient":"foo"}`))
+	require.Error(err, "unknown fields should fail")
+
+	_, err = newTxBody(staking.MethodBurn, []byte(`{"amount":"100"} {}`))
+	require.Error(err, "trailing data should fail")
+
+	_, err = newTxBody(roothash.MethodExecutorCommit, []byte(`{}`))
+	require.Error(err, "unsupported methods should fail")
+
+	body, err := newTxBody(staking.MethodBurn, []byte(`{"amount":"100"}`))
+	require.NoError(err, "valid body should succeed")
+	require.Equal(&staking.Burn{Amount: *quantity.NewFromUint64(100)}, body)
+}
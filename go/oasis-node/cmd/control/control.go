@@ -3,25 +3,49 @@ package control
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/diagnostics"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	txpoolConfig "github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
+// indexerTokenSecretSize is the size of generated indexer access token secrets in bytes.
+const indexerTokenSecretSize = 32
+
 var (
 	shutdownWait = false
 
+	eventJournalFrom  uint64
+	eventJournalLimit uint64
+
+	indexerTokenRateLimit uint64
+	indexerTokenRateBurst uint64
+	indexerTokenTTL       time.Duration
+
+	diagnosticsOutput string
+
 	controlCmd = &cobra.Command{
 		Use:   "control",
 		Short: "node control interface utilities",
@@ -64,6 +88,19 @@ var (
 		Run:   doCancelUpgrade,
 	}
 
+	controlListUpgradesCmd = &cobra.Command{
+		Use:   "list-upgrades",
+		Short: "list upgrades scheduled on the node together with their source",
+		Run:   doListUpgrades,
+	}
+
+	controlCancelLocalUpgradeCmd = &cobra.Command{
+		Use:   "cancel-local-upgrade <handler>",
+		Short: "cancel a locally submitted pending upgrade unless it is already in progress",
+		Args:  cobra.ExactArgs(1),
+		Run:   doCancelLocalUpgrade,
+	}
+
 	controlStatusCmd = &cobra.Command{
 		Use:   "status",
 		Short: "show node status",
@@ -83,6 +120,110 @@ var (
 		Run:   doAddBundle,
 	}
 
+	controlSetLogLevelCmd = &cobra.Command{
+		Use:   "set-log-level <module> [<level>]",
+		Short: "change the log level of a module (use * for the default level, omit the level to reset it)",
+		Args:  cobra.RangeArgs(1, 2),
+		Run:   doSetLogLevel,
+	}
+
+	controlLogLevelsCmd = &cobra.Command{
+		Use:   "log-levels",
+		Short: "show the current log levels",
+		Run:   doLogLevels,
+	}
+
+	controlCrashDumpsCmd = &cobra.Command{
+		Use:   "crash-dumps",
+		Short: "list the crash dumps available on the node",
+		Run:   doCrashDumps,
+	}
+
+	controlCrashDumpCmd = &cobra.Command{
+		Use:   "crash-dump <name>",
+		Short: "show the given crash dump",
+		Args:  cobra.ExactArgs(1),
+		Run:   doCrashDump,
+	}
+
+	controlEventJournalCmd = &cobra.Command{
+		Use:   "event-journal",
+		Short: "show the journal of significant node lifecycle events",
+		Run:   doEventJournal,
+	}
+
+	controlDiagnosticsBundleCmd = &cobra.Command{
+		Use:   "diagnostics-bundle",
+		Short: "create a support diagnostics bundle on the node and download it",
+		Run:   doDiagnosticsBundle,
+	}
+
+	controlWorkerPoolLimitsCmd = &cobra.Command{
+		Use:   "worker-pool-limits",
+		Short: "show the current concurrency limits of the shared worker pool",
+		Run:   doWorkerPoolLimits,
+	}
+
+	controlSetWorkerPoolLimitsCmd = &cobra.Command{
+		Use:   "set-worker-pool-limits <global> [<class>:<limit>...]",
+		Short: "change the concurrency limits of the shared worker pool (classes: critical, normal, background)",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   doSetWorkerPoolLimits,
+	}
+
+	controlSetTxLanesCmd = &cobra.Command{
+		Use:   "set-tx-lanes <runtime-id> [<min-priority>:<reserved-capacity>...]",
+		Short: "change the transaction pool priority lanes of a runtime (no lanes disables them)",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   doSetTxLanes,
+	}
+
+	controlPauseRuntimeCmd = &cobra.Command{
+		Use:   "pause-runtime <runtime-id>",
+		Short: "stop a hosted runtime and omit it from node registration until resumed",
+		Args:  cobra.ExactArgs(1),
+		Run:   doPauseRuntime,
+	}
+
+	controlResumeRuntimeCmd = &cobra.Command{
+		Use:   "resume-runtime <runtime-id>",
+		Short: "restart a previously paused hosted runtime",
+		Args:  cobra.ExactArgs(1),
+		Run:   doResumeRuntime,
+	}
+
+	controlUpdateRolesCmd = &cobra.Command{
+		Use:   "update-roles [<role>...]",
+		Short: "downgrade the node to the given roles without a restart (no roles removes all of them)",
+		Run:   doUpdateRoles,
+	}
+
+	controlSetGrpcAllowlistCmd = &cobra.Command{
+		Use:   "set-grpc-allowlist [<public-key>...]",
+		Short: "replace the TLS client keys allowed to call restricted external gRPC methods",
+		Run:   doSetGrpcAllowlist,
+	}
+
+	controlAddIndexerTokenCmd = &cobra.Command{
+		Use:   "add-indexer-token <name>",
+		Short: "add a storage indexer access token with a generated secret and print the secret",
+		Args:  cobra.ExactArgs(1),
+		Run:   doAddIndexerToken,
+	}
+
+	controlRevokeIndexerTokenCmd = &cobra.Command{
+		Use:   "revoke-indexer-token <name>",
+		Short: "revoke a storage indexer access token",
+		Args:  cobra.ExactArgs(1),
+		Run:   doRevokeIndexerToken,
+	}
+
+	controlIndexerTokensCmd = &cobra.Command{
+		Use:   "indexer-tokens",
+		Short: "list storage indexer access tokens",
+		Run:   doIndexerTokens,
+	}
+
 	logger = logging.GetLogger("cmd/control")
 )
 
@@ -274,6 +415,48 @@ func doCancelUpgrade(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doListUpgrades(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	upgrades, err := client.ListPendingUpgrades(context.Background())
+	if err != nil {
+		logger.Error("failed to query pending upgrades",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyUpgrades, err := cmdCommon.PrettyJSONMarshal(upgrades)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of pending upgrades",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyUpgrades))
+}
+
+func doCancelLocalUpgrade(cmd *cobra.Command, args []string) {
+	name := upgrade.HandlerName(args[0])
+	if err := name.ValidateBasic(); err != nil {
+		logger.Error("malformed upgrade handler name",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.CancelLocalUpgrade(context.Background(), name); err != nil {
+		logger.Error("failed to cancel local upgrade",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
 // DoFetchStatus connects to the node's gRPC server and fetches its status.
 func DoFetchStatus(cmd *cobra.Command) *control.Status {
 	conn, client := DoConnect(cmd)
@@ -320,11 +503,456 @@ func doAddBundle(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doSetLogLevel(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	var level string
+	if len(args) > 1 {
+		level = args[1]
+	}
+
+	if err := client.SetLogLevel(context.Background(), args[0], level); err != nil {
+		logger.Error("failed to set log level",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doLogLevels(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	levels, err := client.GetLogLevels(context.Background())
+	if err != nil {
+		logger.Error("failed to query log levels",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyLevels, err := cmdCommon.PrettyJSONMarshal(levels)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of log levels",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyLevels))
+}
+
+func doCrashDumps(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	dumps, err := client.ListCrashDumps(context.Background())
+	if err != nil {
+		logger.Error("failed to list crash dumps",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyDumps, err := cmdCommon.PrettyJSONMarshal(dumps)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of crash dumps",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyDumps))
+}
+
+func doCrashDump(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	dump, err := client.GetCrashDump(context.Background(), args[0])
+	if err != nil {
+		logger.Error("failed to fetch crash dump",
+			"err", err,
+			"name", args[0],
+		)
+		os.Exit(1)
+	}
+
+	prettyDump, err := cmdCommon.PrettyJSONMarshal(dump)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of crash dump",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyDump))
+}
+
+func doDiagnosticsBundle(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	info, err := client.CreateDiagnosticsBundle(context.Background())
+	if err != nil {
+		logger.Error("failed to create diagnostics bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	data, err := client.GetDiagnosticsBundle(context.Background(), info.Name)
+	if err != nil {
+		logger.Error("failed to fetch diagnostics bundle",
+			"err", err,
+			"name", info.Name,
+		)
+		os.Exit(1)
+	}
+	if checksum := diagnostics.Checksum(data); checksum != info.Checksum {
+		logger.Error("diagnostics bundle checksum mismatch",
+			"name", info.Name,
+			"expected", info.Checksum,
+			"actual", checksum,
+		)
+		os.Exit(1)
+	}
+
+	path := diagnosticsOutput
+	if path == "" {
+		path = info.Name
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		logger.Error("failed to write diagnostics bundle",
+			"err", err,
+			"path", path,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s  %s\n", info.Checksum, path)
+}
+
+func doEventJournal(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	events, err := client.GetEventJournal(context.Background(), &control.GetEventJournalRequest{
+		FromIndex: eventJournalFrom,
+		Limit:     eventJournalLimit,
+	})
+	if err != nil {
+		logger.Error("failed to fetch event journal",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyEvents, err := cmdCommon.PrettyJSONMarshal(events)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of event journal",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyEvents))
+}
+
+// parseTxLanes parses priority lanes in the <min-priority>:<reserved-capacity> format.
+func parseTxLanes(args []string) ([]txpoolConfig.LaneConfig, error) {
+	var lanes []txpoolConfig.LaneConfig
+	for _, arg := range args {
+		rawPriority, rawCapacity, ok := strings.Cut(arg, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed lane '%s': expected <min-priority>:<reserved-capacity>", arg)
+		}
+		priority, err := strconv.ParseUint(rawPriority, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed lane '%s': %w", arg, err)
+		}
+		capacity, err := strconv.ParseUint(rawCapacity, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("malformed lane '%s': %w", arg, err)
+		}
+		lanes = append(lanes, txpoolConfig.LaneConfig{
+			MinPriority:      priority,
+			ReservedCapacity: uint8(capacity),
+		})
+	}
+	if err := txpoolConfig.ValidateLanes(lanes); err != nil {
+		return nil, err
+	}
+	return lanes, nil
+}
+
+func doSetTxLanes(cmd *cobra.Command, args []string) {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalText([]byte(args[0])); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	lanes, err := parseTxLanes(args[1:])
+	if err != nil {
+		logger.Error("malformed priority lanes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	req := control.SetTxPoolLanesRequest{
+		RuntimeID: runtimeID,
+		Lanes:     lanes,
+	}
+	if err = client.SetTxPoolLanes(context.Background(), &req); err != nil {
+		logger.Error("failed to set transaction pool priority lanes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doWorkerPoolLimits(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	limits, err := client.GetWorkerPoolLimits(context.Background())
+	if err != nil {
+		logger.Error("failed to query worker pool limits",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyLimits, err := cmdCommon.PrettyJSONMarshal(limits)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of worker pool limits",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyLimits))
+}
+
+// parseWorkerPoolLimits parses the global limit followed by class limits in the <class>:<limit>
+// format.
+func parseWorkerPoolLimits(args []string) (*workerpool.Limits, error) {
+	global, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("malformed global limit '%s': %w", args[0], err)
+	}
+	limits := workerpool.Limits{
+		Global:  uint(global),
+		Classes: make(map[workerpool.Class]uint),
+	}
+	for _, arg := range args[1:] {
+		rawClass, rawLimit, ok := strings.Cut(arg, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed class limit '%s': expected <class>:<limit>", arg)
+		}
+		var class workerpool.Class
+		if err = class.UnmarshalText([]byte(rawClass)); err != nil {
+			return nil, fmt.Errorf("malformed class limit '%s': %w", arg, err)
+		}
+		var limit uint64
+		if limit, err = strconv.ParseUint(rawLimit, 10, 32); err != nil {
+			return nil, fmt.Errorf("malformed class limit '%s': %w", arg, err)
+		}
+		limits.Classes[class] = uint(limit)
+	}
+	if err = limits.Validate(); err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+func doSetWorkerPoolLimits(cmd *cobra.Command, args []string) {
+	limits, err := parseWorkerPoolLimits(args)
+	if err != nil {
+		logger.Error("malformed worker pool limits",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err = client.SetWorkerPoolLimits(context.Background(), limits); err != nil {
+		logger.Error("failed to set worker pool limits",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doPauseRuntime(cmd *cobra.Command, args []string) {
+	doSetRuntimePaused(cmd, args[0], true)
+}
+
+func doResumeRuntime(cmd *cobra.Command, args []string) {
+	doSetRuntimePaused(cmd, args[0], false)
+}
+
+func doSetRuntimePaused(cmd *cobra.Command, rawRuntimeID string, paused bool) {
+	var runtimeID common.Namespace
+	if err := runtimeID.UnmarshalText([]byte(rawRuntimeID)); err != nil {
+		logger.Error("malformed runtime identifier",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	var err error
+	switch paused {
+	case true:
+		err = client.PauseRuntime(context.Background(), runtimeID)
+	case false:
+		err = client.ResumeRuntime(context.Background(), runtimeID)
+	}
+	if err != nil {
+		logger.Error("failed to change runtime paused state",
+			"err", err,
+			"runtime_id", runtimeID,
+			"paused", paused,
+		)
+		os.Exit(1)
+	}
+}
+
+func doSetGrpcAllowlist(cmd *cobra.Command, args []string) {
+	keys := make([]signature.PublicKey, 0, len(args))
+	for _, arg := range args {
+		var key signature.PublicKey
+		if err := key.UnmarshalText([]byte(arg)); err != nil {
+			logger.Error("malformed public key",
+				"err", err,
+				"key", arg,
+			)
+			os.Exit(1)
+		}
+		keys = append(keys, key)
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.SetGrpcAccessAllowlist(context.Background(), keys); err != nil {
+		logger.Error("failed to set gRPC access allowlist",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doAddIndexerToken(cmd *cobra.Command, args []string) {
+	var rawSecret [indexerTokenSecretSize]byte
+	if _, err := rand.Read(rawSecret[:]); err != nil {
+		logger.Error("failed to generate indexer access token secret",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	token := storageWorker.IndexerToken{
+		Name:      args[0],
+		Secret:    hex.EncodeToString(rawSecret[:]),
+		RateLimit: indexerTokenRateLimit,
+		RateBurst: indexerTokenRateBurst,
+	}
+	if indexerTokenTTL > 0 {
+		token.Expiration = time.Now().Add(indexerTokenTTL).Unix()
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.AddIndexerToken(context.Background(), &token); err != nil {
+		logger.Error("failed to add indexer access token",
+			"err", err,
+			"name", token.Name,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(token.Secret)
+}
+
+func doRevokeIndexerToken(cmd *cobra.Command, args []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.RevokeIndexerToken(context.Background(), args[0]); err != nil {
+		logger.Error("failed to revoke indexer access token",
+			"err", err,
+			"name", args[0],
+		)
+		os.Exit(1)
+	}
+}
+
+func doIndexerTokens(cmd *cobra.Command, _ []string) {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	tokens, err := client.ListIndexerTokens(context.Background())
+	if err != nil {
+		logger.Error("failed to list indexer access tokens",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	prettyTokens, err := cmdCommon.PrettyJSONMarshal(tokens)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of indexer access tokens",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(prettyTokens))
+}
+
+func doUpdateRoles(cmd *cobra.Command, args []string) {
+	var roles node.RolesMask
+	for _, arg := range args {
+		var role node.RolesMask
+		if err := role.UnmarshalText([]byte(arg)); err != nil {
+			logger.Error("malformed role",
+				"err", err,
+				"role", arg,
+			)
+			os.Exit(1)
+		}
+		roles |= role
+	}
+
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	if err := client.UpdateRoles(context.Background(), roles); err != nil {
+		logger.Error("failed to update roles",
+			"err", err,
+			"roles", roles,
+		)
+		os.Exit(1)
+	}
+}
+
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 
 	controlShutdownCmd.Flags().BoolVarP(&shutdownWait, "wait", "w", false, "wait for the node to finish shutdown")
+	controlEventJournalCmd.Flags().Uint64Var(&eventJournalFrom, "from", 0, "index of the first event to show")
+	controlEventJournalCmd.Flags().Uint64Var(&eventJournalLimit, "limit", 0, "maximum number of events to show (0 means no limit)")
+	controlDiagnosticsBundleCmd.Flags().StringVarP(&diagnosticsOutput, "output", "o", "", "path of the downloaded bundle (defaults to the bundle name in the current directory)")
+	controlAddIndexerTokenCmd.Flags().Uint64Var(&indexerTokenRateLimit, "rate-limit", 0, "maximum number of requests per second (0 means no limit)")
+	controlAddIndexerTokenCmd.Flags().Uint64Var(&indexerTokenRateBurst, "rate-burst", 0, "maximum request burst size (0 means same as rate limit)")
+	controlAddIndexerTokenCmd.Flags().DurationVar(&indexerTokenTTL, "ttl", 0, "token validity period (0 means the token never expires)")
 
 	controlCmd.AddCommand(controlIsSyncedCmd)
 	controlCmd.AddCommand(controlWaitSyncCmd)
@@ -332,8 +960,28 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.AddCommand(controlClearDeregisterCmd)
 	controlCmd.AddCommand(controlUpgradeBinaryCmd)
 	controlCmd.AddCommand(controlCancelUpgradeCmd)
+	controlCmd.AddCommand(controlListUpgradesCmd)
+	controlCmd.AddCommand(controlCancelLocalUpgradeCmd)
 	controlCmd.AddCommand(controlStatusCmd)
 	controlCmd.AddCommand(controlRuntimeStatsCmd)
 	controlCmd.AddCommand(controlAddBundleCmd)
+	controlCmd.AddCommand(controlSetLogLevelCmd)
+	controlCmd.AddCommand(controlLogLevelsCmd)
+	controlCmd.AddCommand(controlCrashDumpsCmd)
+	controlCmd.AddCommand(controlCrashDumpCmd)
+	controlCmd.AddCommand(controlEventJournalCmd)
+	controlCmd.AddCommand(controlDiagnosticsBundleCmd)
+	controlCmd.AddCommand(controlSetTxLanesCmd)
+	controlCmd.AddCommand(controlWorkerPoolLimitsCmd)
+	controlCmd.AddCommand(controlSetWorkerPoolLimitsCmd)
+	controlCmd.AddCommand(controlPauseRuntimeCmd)
+	controlCmd.AddCommand(controlResumeRuntimeCmd)
+	controlCmd.AddCommand(controlSetGrpcAllowlistCmd)
+	controlCmd.AddCommand(controlUpdateRolesCmd)
+	controlCmd.AddCommand(controlAddIndexerTokenCmd)
+	controlCmd.AddCommand(controlRevokeIndexerTokenCmd)
+	controlCmd.AddCommand(controlIndexerTokensCmd)
+	controlDoctorCmd.Flags().AddFlagSet(doctorFlags)
+	controlCmd.AddCommand(controlDoctorCmd)
 	parentCmd.AddCommand(controlCmd)
 }
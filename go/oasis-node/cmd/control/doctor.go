@@ -0,0 +1,621 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+const (
+	cfgDoctorOutput          = "doctor.output"
+	cfgDoctorMaxBlockLag     = "doctor.max_block_lag"
+	cfgDoctorMaxBlockAge     = "doctor.max_block_age"
+	cfgDoctorMaxClockSkew    = "doctor.max_clock_skew"
+	cfgDoctorMaxStorageLag   = "doctor.max_storage_lag"
+	cfgDoctorAttestationWarn = "doctor.attestation_warn_ratio"
+
+	doctorOutputText = "text"
+	doctorOutputJSON = "json"
+)
+
+var (
+	doctorFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	controlDoctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "diagnose common node problems",
+		Run:   doDoctor,
+	}
+)
+
+// doctorCheckStatus is the outcome of a doctor check.
+type doctorCheckStatus string
+
+const (
+	doctorCheckOK      doctorCheckStatus = "ok"
+	doctorCheckWarning doctorCheckStatus = "warning"
+	doctorCheckFailed  doctorCheckStatus = "failed"
+	doctorCheckSkipped doctorCheckStatus = "skipped"
+)
+
+// doctorCheck is the result of a single doctor check.
+type doctorCheck struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+	// Runtime is the runtime the check applies to (if any).
+	Runtime *common.Namespace `json:"runtime,omitempty"`
+	// Status is the outcome of the check.
+	Status doctorCheckStatus `json:"status"`
+	// Message describes the outcome of the check.
+	Message string `json:"message"`
+	// Values are the raw values the outcome is based on.
+	Values map[string]any `json:"values,omitempty"`
+	// Hint is a hint on how to resolve a problem.
+	Hint string `json:"hint,omitempty"`
+}
+
+// doctorConfig is the doctor check configuration.
+type doctorConfig struct {
+	// MaxBlockLag is the maximum number of blocks the node may be behind its peers.
+	MaxBlockLag int64
+	// MaxBlockAge is the maximum age of the latest consensus block.
+	MaxBlockAge time.Duration
+	// MaxClockSkew is the maximum allowed amount the local clock can be behind consensus time.
+	MaxClockSkew time.Duration
+	// MaxStorageLag is the maximum number of rounds storage may be behind the latest round.
+	MaxStorageLag uint64
+	// AttestationWarnRatio is the fraction of the maximum attestation age after which a warning
+	// is emitted.
+	AttestationWarnRatio float64
+}
+
+// doctorInput is everything the doctor checks are based on.
+type doctorInput struct {
+	// Now is the local time.
+	Now time.Time
+	// Status is the node status.
+	Status *control.Status
+
+	// Account is the staking account of the node's entity (if registered).
+	Account *staking.Account
+	// StakingParams are the staking consensus parameters.
+	StakingParams *staking.ConsensusParameters
+	// RegistryParams are the registry consensus parameters.
+	RegistryParams *registry.ConsensusParameters
+	// Validators is the current validator set.
+	Validators []*scheduler.Validator
+}
+
+func (in *doctorInput) descriptor() *node.Node {
+	if in.Status.Registration == nil {
+		return nil
+	}
+	return in.Status.Registration.Descriptor
+}
+
+// runDoctorChecks performs all doctor checks.
+func runDoctorChecks(in *doctorInput, cfg *doctorConfig) []doctorCheck {
+	var checks []doctorCheck
+	checks = append(checks, checkConsensusSynced(in, cfg))
+	checks = append(checks, checkClockSkew(in, cfg))
+	checks = append(checks, checkRegistration(in))
+	checks = append(checks, checkStake(in))
+	checks = append(checks, checkValidator(in))
+	checks = append(checks, checkAttestations(in, cfg)...)
+	checks = append(checks, checkRuntimeVersions(in)...)
+	checks = append(checks, checkStorage(in, cfg)...)
+	return checks
+}
+
+func checkConsensusSynced(in *doctorInput, cfg *doctorConfig) doctorCheck {
+	check := doctorCheck{Name: "consensus_synced"}
+	cs := in.Status.Consensus
+	if cs == nil {
+		check.Status = doctorCheckFailed
+		check.Message = "consensus status is not available"
+		check.Hint = "make sure the node is running in a mode that includes the consensus layer"
+		return check
+	}
+
+	check.Values = map[string]any{
+		"status":        cs.Status.String(),
+		"latest_height": cs.LatestHeight,
+	}
+	if cs.P2P != nil {
+		check.Values["peers"] = len(cs.P2P.Peers)
+	}
+	if lc := in.Status.LightClient; lc != nil {
+		check.Values["peers_latest_height"] = lc.LatestHeight
+	}
+
+	switch {
+	case cs.Status != consensus.StatusStateReady:
+		check.Status = doctorCheckFailed
+		check.Message = "consensus is not synced"
+		check.Hint = "wait for the node to finish syncing; if it does not make progress, check that it has peers"
+	case in.Status.LightClient != nil && in.Status.LightClient.LatestHeight-cs.LatestHeight > cfg.MaxBlockLag:
+		check.Status = doctorCheckFailed
+		check.Message = fmt.Sprintf("consensus is more than %d blocks behind peers", cfg.MaxBlockLag)
+		check.Hint = "check the node's resource usage and network connectivity"
+	case cs.P2P != nil && len(cs.P2P.Peers) == 0:
+		check.Status = doctorCheckWarning
+		check.Message = "consensus is synced but the node has no peers"
+		check.Hint = "check the configured seed nodes and that the P2P port is reachable"
+	default:
+		check.Status = doctorCheckOK
+		check.Message = "consensus is synced"
+	}
+	return check
+}
+
+func checkClockSkew(in *doctorInput, cfg *doctorConfig) doctorCheck {
+	check := doctorCheck{Name: "clock_skew"}
+	cs := in.Status.Consensus
+	if cs == nil || cs.LatestTime.IsZero() {
+		check.Status = doctorCheckSkipped
+		check.Message = "consensus time is not available"
+		return check
+	}
+
+	age := in.Now.Sub(cs.LatestTime)
+	check.Values = map[string]any{
+		"local_time":     in.Now,
+		"consensus_time": cs.LatestTime,
+		"block_age":      age.String(),
+	}
+
+	switch {
+	case -age > cfg.MaxClockSkew:
+		check.Status = doctorCheckFailed
+		check.Message = fmt.Sprintf("local clock is %s behind consensus time", -age)
+		check.Hint = "synchronize the local clock (e.g., via NTP)"
+	case age > cfg.MaxBlockAge:
+		check.Status = doctorCheckWarning
+		check.Message = fmt.Sprintf("latest consensus block is %s old", age)
+		check.Hint = "either the node is behind or the local clock is ahead; check consensus sync and NTP"
+	default:
+		check.Status = doctorCheckOK
+		check.Message = "local clock matches consensus time"
+	}
+	return check
+}
+
+func checkRegistration(in *doctorInput) doctorCheck {
+	check := doctorCheck{Name: "registration"}
+	rs := in.Status.Registration
+	if rs == nil || (rs.Descriptor == nil && rs.LastAttempt.IsZero()) {
+		check.Status = doctorCheckSkipped
+		check.Message = "node does not register"
+		return check
+	}
+
+	check.Values = map[string]any{
+		"last_attempt_successful": rs.LastAttemptSuccessful,
+		"last_attempt":            rs.LastAttempt,
+		"last_registration":       rs.LastRegistration,
+	}
+	if rs.LastAttemptErrorMessage != "" {
+		check.Values["last_attempt_error"] = rs.LastAttemptErrorMessage
+	}
+	var epoch uint64
+	if in.Status.Consensus != nil {
+		epoch = uint64(in.Status.Consensus.LatestEpoch)
+		check.Values["epoch"] = epoch
+	}
+	if rs.Descriptor != nil {
+		check.Values["expiration"] = rs.Descriptor.Expiration
+	}
+	if rs.NodeStatus != nil {
+		check.Values["freeze_end_time"] = rs.NodeStatus.FreezeEndTime
+	}
+
+	switch {
+	case !rs.LastAttemptSuccessful:
+		check.Status = doctorCheckFailed
+		check.Message = "last registration attempt failed"
+		check.Hint = "see the last attempt error and the registration worker logs"
+	case rs.Descriptor == nil:
+		check.Status = doctorCheckFailed
+		check.Message = "node is not registered"
+		check.Hint = "make sure the node's entity is registered and has sufficient stake"
+	case in.Status.Consensus != nil && rs.Descriptor.IsExpired(epoch):
+		check.Status = doctorCheckFailed
+		check.Message = "node registration has expired"
+		check.Hint = "see the registration worker logs for why re-registration fails"
+	case rs.NodeStatus != nil && rs.NodeStatus.IsFrozen():
+		check.Status = doctorCheckFailed
+		check.Message = "node is frozen"
+		check.Hint = "fix the cause of the freeze and submit an unfreeze node transaction after the freeze period"
+	default:
+		check.Status = doctorCheckOK
+		check.Message = "node registration is fresh"
+	}
+	return check
+}
+
+func checkStake(in *doctorInput) doctorCheck {
+	check := doctorCheck{Name: "stake"}
+	desc := in.descriptor()
+	switch {
+	case desc == nil:
+		check.Status = doctorCheckSkipped
+		check.Message = "node is not registered"
+		return check
+	case in.Account == nil || in.StakingParams == nil:
+		check.Status = doctorCheckSkipped
+		check.Message = "staking state is not available"
+		return check
+	}
+
+	escrow := &in.Account.Escrow
+	claims, err := escrow.StakeAccumulator.TotalClaims(in.StakingParams.Thresholds, nil)
+	if err != nil {
+		check.Status = doctorCheckFailed
+		check.Message = fmt.Sprintf("failed to compute stake claims: %s", err)
+		return check
+	}
+	check.Values = map[string]any{
+		"entity":         staking.NewAddress(desc.EntityID),
+		"escrow_balance": escrow.Active.Balance.String(),
+		"total_claims":   claims.String(),
+		"node_roles":     desc.Roles.String(),
+	}
+
+	_, hasNodeClaim := escrow.StakeAccumulator.Claims[registry.StakeClaimForNode(desc.ID)]
+	switch {
+	case in.StakingParams.DebugBypassStake:
+		check.Status = doctorCheckSkipped
+		check.Message = "stake checks are bypassed"
+	case escrow.Active.Balance.Cmp(claims) < 0:
+		check.Status = doctorCheckFailed
+		check.Message = "entity escrow is below the stake claimed by its registrations"
+		check.Hint = "escrow more stake to the entity or reduce the number of nodes and roles"
+	case !hasNodeClaim:
+		check.Status = doctorCheckWarning
+		check.Message = "entity has no stake claim for this node"
+		check.Hint = "the node may have been deregistered; see the registration worker logs"
+	default:
+		check.Status = doctorCheckOK
+		check.Message = "entity escrow satisfies all stake claims"
+	}
+	return check
+}
+
+func checkValidator(in *doctorInput) doctorCheck {
+	check := doctorCheck{Name: "validator"}
+	desc := in.descriptor()
+	if desc == nil || !desc.HasRoles(node.RoleValidator) {
+		check.Status = doctorCheckSkipped
+		check.Message = "node is not registered as a validator"
+		return check
+	}
+	if in.Validators == nil {
+		check.Status = doctorCheckSkipped
+		check.Message = "validator set is not available"
+		return check
+	}
+
+	check.Values = map[string]any{
+		"validators": len(in.Validators),
+	}
+	if slices.ContainsFunc(in.Validators, func(v *scheduler.Validator) bool { return v.ID.Equal(desc.ID) }) {
+		check.Status = doctorCheckOK
+		check.Message = "node is in the validator set"
+		return check
+	}
+	check.Status = doctorCheckWarning
+	check.Message = "node is registered as a validator but is not in the validator set"
+	check.Hint = "the entity may not have enough stake to be elected or the node only registered recently"
+	return check
+}
+
+func checkAttestations(in *doctorInput, cfg *doctorConfig) []doctorCheck {
+	desc := in.descriptor()
+	if desc == nil || in.Status.Consensus == nil {
+		return nil
+	}
+	height := uint64(in.Status.Consensus.LatestHeight)
+
+	var checks []doctorCheck
+	for _, nodeRt := range desc.Runtimes {
+		tee := nodeRt.Capabilities.TEE
+		if tee == nil || tee.Hardware != node.TEEHardwareIntelSGX {
+			continue
+		}
+
+		check := doctorCheck{
+			Name:    "attestation",
+			Runtime: &nodeRt.ID,
+			Values: map[string]any{
+				"version": nodeRt.Version.String(),
+			},
+		}
+
+		var sa node.SGXAttestation
+		if err := cbor.Unmarshal(tee.Attestation, &sa); err != nil {
+			check.Status = doctorCheckFailed
+			check.Message = fmt.Sprintf("malformed attestation: %s", err)
+			check.Hint = "restart the runtime to trigger a new attestation"
+			checks = append(checks, check)
+			continue
+		}
+
+		var sc node.SGXConstraints
+		if rt := in.runtimeDescriptor(nodeRt.ID); rt != nil {
+			if vi := rt.DeploymentForVersion(nodeRt.Version); vi != nil {
+				_ = cbor.Unmarshal(vi.TEE, &sc)
+			}
+		}
+		if in.RegistryParams != nil && in.RegistryParams.TEEFeatures != nil {
+			in.RegistryParams.TEEFeatures.SGX.ApplyDefaultConstraints(&sc)
+		}
+
+		var age uint64
+		if height > sa.Height {
+			age = height - sa.Height
+		}
+		check.Values["attestation_height"] = sa.Height
+		check.Values["attestation_age"] = age
+		check.Values["max_attestation_age"] = sc.MaxAttestationAge
+
+		switch {
+		case sa.V == 0:
+			// Legacy IAS attestations do not include a height.
+			check.Status = doctorCheckOK
+			check.Message = "legacy attestation does not expire"
+		case sc.MaxAttestationAge == 0:
+			check.Status = doctorCheckOK
+			check.Message = "attestation does not expire"
+		case age > sc.MaxAttestationAge:
+			check.Status = doctorCheckFailed
+			check.Message = "attestation has expired"
+			check.Hint = "check the runtime logs for attestation failures (e.g., quote provider issues)"
+		case float64(age) > cfg.AttestationWarnRatio*float64(sc.MaxAttestationAge):
+			check.Status = doctorCheckWarning
+			check.Message = "attestation is close to expiry"
+			check.Hint = "the runtime should re-attest soon; check the runtime logs if it does not"
+		default:
+			check.Status = doctorCheckOK
+			check.Message = "attestation is fresh"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func (in *doctorInput) runtimeDescriptor(id common.Namespace) *registry.Runtime {
+	rs, ok := in.Status.Runtimes[id]
+	if !ok {
+		return nil
+	}
+	return rs.Descriptor
+}
+
+func checkRuntimeVersions(in *doctorInput) []doctorCheck {
+	desc := in.descriptor()
+	if desc == nil || in.Status.Consensus == nil {
+		return nil
+	}
+	epoch := in.Status.Consensus.LatestEpoch
+
+	var checks []doctorCheck
+	for _, id := range sortedRuntimeIDs(in.Status.Runtimes) {
+		rt := in.Status.Runtimes[id].Descriptor
+		if rt == nil {
+			continue
+		}
+		check := doctorCheck{
+			Name:    "runtime_version",
+			Runtime: &id,
+		}
+
+		active := rt.ActiveDeployment(epoch)
+		if active == nil {
+			check.Status = doctorCheckSkipped
+			check.Message = "runtime has no active deployment"
+			checks = append(checks, check)
+			continue
+		}
+
+		var registered []string
+		for _, nodeRt := range desc.Runtimes {
+			if nodeRt.ID.Equal(&id) {
+				registered = append(registered, nodeRt.Version.String())
+			}
+		}
+		check.Values = map[string]any{
+			"active_version":      active.Version.String(),
+			"registered_versions": registered,
+		}
+
+		switch {
+		case len(registered) == 0:
+			check.Status = doctorCheckWarning
+			check.Message = "node is not registered for the runtime"
+			check.Hint = "the runtime may still be provisioning; see the runtime host logs"
+		case !slices.Contains(registered, active.Version.String()):
+			check.Status = doctorCheckFailed
+			check.Message = "node is not registered for the active runtime version"
+			check.Hint = "install the bundle for the active runtime version"
+		default:
+			check.Status = doctorCheckOK
+			check.Message = "node is registered for the active runtime version"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func checkStorage(in *doctorInput, cfg *doctorConfig) []doctorCheck {
+	var checks []doctorCheck
+	for _, id := range sortedRuntimeIDs(in.Status.Runtimes) {
+		rs := in.Status.Runtimes[id]
+		if rs.Storage == nil {
+			continue
+		}
+
+		var lag uint64
+		if rs.LatestRound > rs.Storage.LastFinalizedRound {
+			lag = rs.LatestRound - rs.Storage.LastFinalizedRound
+		}
+		check := doctorCheck{
+			Name:    "storage",
+			Runtime: &id,
+			Values: map[string]any{
+				"status":               rs.Storage.Status,
+				"latest_round":         rs.LatestRound,
+				"last_finalized_round": rs.Storage.LastFinalizedRound,
+				"lag":                  lag,
+			},
+		}
+		if lag > cfg.MaxStorageLag {
+			check.Status = doctorCheckFailed
+			check.Message = fmt.Sprintf("storage is more than %d rounds behind", cfg.MaxStorageLag)
+			check.Hint = "check disk performance and the storage worker logs"
+		} else {
+			check.Status = doctorCheckOK
+			check.Message = "storage is keeping up"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func sortedRuntimeIDs(runtimes map[common.Namespace]control.RuntimeStatus) []common.Namespace {
+	ids := make([]common.Namespace, 0, len(runtimes))
+	for id := range runtimes {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b common.Namespace) int {
+		return strings.Compare(a.Hex(), b.Hex())
+	})
+	return ids
+}
+
+// fetchDoctorInput queries the node for everything needed by the doctor checks.
+//
+// Failures to query the consensus services are logged and the corresponding checks skipped.
+func fetchDoctorInput(ctx context.Context, cmd *cobra.Command) *doctorInput {
+	conn, client := DoConnect(cmd)
+	defer conn.Close()
+
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		logger.Error("failed to query status",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	in := &doctorInput{
+		Now:    time.Now(),
+		Status: status,
+	}
+	if status.Consensus == nil || !status.Consensus.Features.Has(consensus.FeatureServices) {
+		return in
+	}
+
+	services := consensus.NewServicesClient(conn)
+	height := status.Consensus.LatestHeight
+
+	if in.StakingParams, err = services.Staking().ConsensusParameters(ctx, height); err != nil {
+		logger.Warn("failed to query staking parameters", "err", err)
+	}
+	if in.RegistryParams, err = services.Registry().ConsensusParameters(ctx, height); err != nil {
+		logger.Warn("failed to query registry parameters", "err", err)
+	}
+	if in.Validators, err = services.Scheduler().GetValidators(ctx, height); err != nil {
+		logger.Warn("failed to query validators", "err", err)
+	}
+	if desc := in.descriptor(); desc != nil {
+		if in.Account, err = services.Staking().Account(ctx, &staking.OwnerQuery{
+			Height: height,
+			Owner:  staking.NewAddress(desc.EntityID),
+		}); err != nil {
+			logger.Warn("failed to query entity account", "err", err)
+		}
+	}
+	return in
+}
+
+func doDoctor(cmd *cobra.Command, _ []string) {
+	ctx := context.Background()
+	in := fetchDoctorInput(ctx, cmd)
+
+	checks := runDoctorChecks(in, &doctorConfig{
+		MaxBlockLag:          viper.GetInt64(cfgDoctorMaxBlockLag),
+		MaxBlockAge:          viper.GetDuration(cfgDoctorMaxBlockAge),
+		MaxClockSkew:         viper.GetDuration(cfgDoctorMaxClockSkew),
+		MaxStorageLag:        viper.GetUint64(cfgDoctorMaxStorageLag),
+		AttestationWarnRatio: viper.GetFloat64(cfgDoctorAttestationWarn),
+	})
+
+	switch viper.GetString(cfgDoctorOutput) {
+	case doctorOutputJSON:
+		prettyChecks, err := cmdCommon.PrettyJSONMarshal(checks)
+		if err != nil {
+			logger.Error("failed to get pretty JSON of doctor checks",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		fmt.Println(string(prettyChecks))
+	case doctorOutputText:
+		printDoctorChecks(checks)
+	default:
+		logger.Error("unsupported output format",
+			"output", viper.GetString(cfgDoctorOutput),
+		)
+		os.Exit(1)
+	}
+
+	if slices.ContainsFunc(checks, func(c doctorCheck) bool { return c.Status == doctorCheckFailed }) {
+		os.Exit(1)
+	}
+}
+
+func printDoctorChecks(checks []doctorCheck) {
+	for _, c := range checks {
+		name := c.Name
+		if c.Runtime != nil {
+			name = fmt.Sprintf("%s (runtime %s)", c.Name, c.Runtime)
+		}
+		fmt.Printf("[%s] %s: %s\n", strings.ToUpper(string(c.Status)), name, c.Message)
+		if c.Status != doctorCheckWarning && c.Status != doctorCheckFailed {
+			continue
+		}
+		for _, k := range slices.Sorted(maps.Keys(c.Values)) {
+			fmt.Printf("    %s: %v\n", k, c.Values[k])
+		}
+		if c.Hint != "" {
+			fmt.Printf("    hint: %s\n", c.Hint)
+		}
+	}
+}
+
+func init() {
+	doctorFlags.String(cfgDoctorOutput, doctorOutputText, "output format (text, json)")
+	doctorFlags.Int64(cfgDoctorMaxBlockLag, 10, "maximum number of blocks the node may be behind its peers")
+	doctorFlags.Duration(cfgDoctorMaxBlockAge, time.Minute, "maximum age of the latest consensus block")
+	doctorFlags.Duration(cfgDoctorMaxClockSkew, 5*time.Second, "maximum amount the local clock may be behind consensus time")
+	doctorFlags.Uint64(cfgDoctorMaxStorageLag, 10, "maximum number of rounds storage may be behind the latest round")
+	doctorFlags.Float64(cfgDoctorAttestationWarn, 0.9, "fraction of the maximum attestation age after which to warn")
+	_ = viper.BindPFlags(doctorFlags)
+}
@@ -0,0 +1,188 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	storage "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+func doctorCheckStatuses(checks []doctorCheck) map[string]doctorCheckStatus {
+	statuses := make(map[string]doctorCheckStatus)
+	for _, c := range checks {
+		statuses[c.Name] = c.Status
+	}
+	return statuses
+}
+
+func TestDoctorChecks(t *testing.T) {
+	cfg := &doctorConfig{
+		MaxBlockLag:          10,
+		MaxBlockAge:          time.Minute,
+		MaxClockSkew:         5 * time.Second,
+		MaxStorageLag:        10,
+		AttestationWarnRatio: 0.9,
+	}
+
+	// Returns the input of a healthy node.
+	newInput := func() *doctorInput {
+		var runtimeID common.Namespace
+		require.NoError(t, runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"))
+		nodeID := signature.NewPublicKey("0000000000000000000000000000000000000000000000000000000000000001")
+		entityID := signature.NewPublicKey("0000000000000000000000000000000000000000000000000000000000000002")
+		now := time.Now()
+
+		sa := node.SGXAttestation{
+			Versioned: cbor.NewVersioned(node.LatestSGXAttestationVersion),
+			Height:    900,
+		}
+		rtVersion := version.Version{Major: 1}
+
+		account := &staking.Account{}
+		account.Escrow.Active.Balance = *quantity.NewFromUint64(1000)
+		account.Escrow.StakeAccumulator.AddClaimUnchecked(registry.StakeClaimForNode(nodeID), []staking.StakeThreshold{
+			staking.GlobalStakeThreshold(staking.KindNodeValidator),
+		})
+
+		return &doctorInput{
+			Now: now,
+			Status: &control.Status{
+				Consensus: &consensus.Status{
+					Status:       consensus.StatusStateReady,
+					LatestHeight: 1000,
+					LatestTime:   now.Add(-5 * time.Second),
+					LatestEpoch:  10,
+					P2P:          &consensus.P2PStatus{Peers: []string{"peer"}},
+				},
+				LightClient: &consensus.LightClientStatus{LatestHeight: 1001},
+				Registration: &control.RegistrationStatus{
+					LastAttempt:           now,
+					LastAttemptSuccessful: true,
+					Descriptor: &node.Node{
+						ID:         nodeID,
+						EntityID:   entityID,
+						Expiration: 12,
+						Roles:      node.RoleValidator | node.RoleComputeWorker,
+						Runtimes: []*node.Runtime{{
+							ID:      runtimeID,
+							Version: rtVersion,
+							Capabilities: node.Capabilities{TEE: &node.CapabilityTEE{
+								Hardware:    node.TEEHardwareIntelSGX,
+								Attestation: cbor.Marshal(sa),
+							}},
+						}},
+					},
+					NodeStatus: &registry.NodeStatus{},
+				},
+				Runtimes: map[common.Namespace]control.RuntimeStatus{
+					runtimeID: {
+						Descriptor: &registry.Runtime{
+							ID: runtimeID,
+							Deployments: []*registry.VersionInfo{{
+								Version: rtVersion,
+								TEE: cbor.Marshal(node.SGXConstraints{
+									Versioned:         cbor.NewVersioned(node.LatestSGXConstraintsVersion),
+									MaxAttestationAge: 1000,
+								}),
+							}},
+						},
+						LatestRound: 100,
+						Storage:     &storage.Status{LastFinalizedRound: 95},
+					},
+				},
+			},
+			Account: account,
+			StakingParams: &staking.ConsensusParameters{
+				Thresholds: map[staking.ThresholdKind]quantity.Quantity{
+					staking.KindNodeValidator: *quantity.NewFromUint64(500),
+				},
+			},
+			Validators: []*scheduler.Validator{{ID: nodeID, VotingPower: 1}},
+		}
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		require := require.New(t)
+
+		require.Equal(map[string]doctorCheckStatus{
+			"consensus_synced": doctorCheckOK,
+			"clock_skew":       doctorCheckOK,
+			"registration":     doctorCheckOK,
+			"stake":            doctorCheckOK,
+			"validator":        doctorCheckOK,
+			"attestation":      doctorCheckOK,
+			"runtime_version":  doctorCheckOK,
+			"storage":          doctorCheckOK,
+		}, doctorCheckStatuses(runDoctorChecks(newInput(), cfg)))
+	})
+
+	t.Run("Unhealthy", func(t *testing.T) {
+		require := require.New(t)
+
+		in := newInput()
+		in.Status.LightClient.LatestHeight = 2100
+		in.Status.Consensus.LatestTime = in.Now.Add(time.Minute)
+		in.Status.Consensus.LatestEpoch = 13
+		in.Account.Escrow.Active.Balance = *quantity.NewFromUint64(100)
+		in.Validators = []*scheduler.Validator{}
+		for _, rs := range in.Status.Runtimes {
+			rs.Storage.LastFinalizedRound = 50
+			// Activate a new runtime version the node is not registered for.
+			rs.Descriptor.Deployments = append(rs.Descriptor.Deployments, &registry.VersionInfo{
+				Version:   version.Version{Major: 2},
+				ValidFrom: beacon.EpochTime(13),
+			})
+		}
+		in.Status.Consensus.LatestHeight = 2000
+
+		checks := runDoctorChecks(in, cfg)
+		require.Equal(map[string]doctorCheckStatus{
+			"consensus_synced": doctorCheckFailed,
+			"clock_skew":       doctorCheckFailed,
+			"registration":     doctorCheckFailed,
+			"stake":            doctorCheckFailed,
+			"validator":        doctorCheckWarning,
+			"attestation":      doctorCheckFailed,
+			"runtime_version":  doctorCheckFailed,
+			"storage":          doctorCheckFailed,
+		}, doctorCheckStatuses(checks))
+		for _, c := range checks {
+			require.NotEmpty(c.Values, "failed check %s should include raw values", c.Name)
+			require.NotEmpty(c.Hint, "failed check %s should include a hint", c.Name)
+		}
+	})
+
+	t.Run("AttestationExpiring", func(t *testing.T) {
+		require := require.New(t)
+
+		in := newInput()
+		in.Status.Consensus.LatestHeight = 1850
+		require.Equal(doctorCheckWarning, doctorCheckStatuses(runDoctorChecks(in, cfg))["attestation"])
+	})
+
+	t.Run("NotRegistering", func(t *testing.T) {
+		require := require.New(t)
+
+		in := newInput()
+		in.Status.Registration = &control.RegistrationStatus{}
+		statuses := doctorCheckStatuses(runDoctorChecks(in, cfg))
+		require.Equal(doctorCheckSkipped, statuses["registration"])
+		require.Equal(doctorCheckSkipped, statuses["stake"])
+		require.Equal(doctorCheckSkipped, statuses["validator"])
+		require.NotContains(statuses, "attestation")
+	})
+}
@@ -0,0 +1,230 @@
+// Package bundle implements the runtime bundle debug sub-commands.
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+)
+
+const (
+	cfgRuntimeDescriptor = "bundle.runtime_descriptor"
+	cfgSignature         = "bundle.signature"
+	cfgTrustedSigners    = "bundle.trusted_signers"
+)
+
+var (
+	verifyFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	signatureFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	bundleCmd = &cobra.Command{
+		Use:   "bundle",
+		Short: "runtime bundle utilities",
+	}
+
+	verifyCmd = &cobra.Command{
+		Use:   "verify <bundle-file>",
+		Short: "verify a runtime bundle against the runtime descriptor",
+		Long: `Verifies that the runtime bundle is well-formed and that its runtime ID, RONL
+component version and enclave identities match one of the deployments of the
+runtime descriptor, reporting any mismatches field by field.
+
+The runtime descriptor is either read from a JSON file or queried from the node.
+If a detached signature is given, the bundle manifest signature is verified too.`,
+		Args: cobra.ExactArgs(1),
+		Run:  doVerify,
+	}
+
+	signCmd = &cobra.Command{
+		Use:   "sign <bundle-file>",
+		Short: "produce a detached signature over a runtime bundle manifest",
+		Args:  cobra.ExactArgs(1),
+		Run:   doSign,
+	}
+
+	logger = logging.GetLogger("cmd/debug/bundle")
+)
+
+func signatureFile(bundleFn string) string {
+	if fn := viper.GetString(cfgSignature); fn != "" {
+		return fn
+	}
+	return bundleFn + ".sig"
+}
+
+func loadTrustedSigners() ([]signature.PublicKey, error) {
+	var signers []signature.PublicKey
+	for _, raw := range viper.GetStringSlice(cfgTrustedSigners) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(raw)); err != nil {
+			return nil, fmt.Errorf("malformed trusted signer '%s': %w", raw, err)
+		}
+		signers = append(signers, pk)
+	}
+	return signers, nil
+}
+
+func loadRuntimeDescriptor(ctx context.Context, cmd *cobra.Command, bnd *bundle.Bundle) (*registry.Runtime, error) {
+	if fn := viper.GetString(cfgRuntimeDescriptor); fn != "" {
+		data, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read runtime descriptor: %w", err)
+		}
+		var rt registry.Runtime
+		if err = json.Unmarshal(data, &rt); err != nil {
+			return nil, fmt.Errorf("failed to parse runtime descriptor: %w", err)
+		}
+		return &rt, nil
+	}
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish connection with node: %w", err)
+	}
+	defer conn.Close()
+
+	return registry.NewClient(conn).GetRuntime(ctx, &registry.GetRuntimeQuery{
+		Height: consensus.HeightLatest,
+		ID:     bnd.Manifest.ID,
+	})
+}
+
+func doVerify(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+	ctx := context.Background()
+
+	var opts []bundle.OpenOption
+	if viper.IsSet(cfgSignature) || len(viper.GetStringSlice(cfgTrustedSigners)) > 0 {
+		sig, err := bundle.ReadManifestSignature(signatureFile(args[0]))
+		if err != nil {
+			logger.Error("failed to load manifest signature",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		trustedSigners, err := loadTrustedSigners()
+		if err != nil {
+			logger.Error("failed to load trusted signers",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		opts = append(opts, bundle.WithManifestSignature(sig, trustedSigners...))
+	}
+
+	bnd, err := bundle.Open(args[0], opts...)
+	if err != nil {
+		logger.Error("failed to open bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer bnd.Close()
+
+	rt, err := loadRuntimeDescriptor(ctx, cmd, bnd)
+	if err != nil {
+		logger.Error("failed to load runtime descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	err = bundle.Verify(bnd, rt)
+	var verr *bundle.VerificationError
+	switch {
+	case err == nil:
+		fmt.Printf("Bundle %s matches runtime %s.\n", bnd.Manifest.Hash(), rt.ID)
+	case errors.As(err, &verr):
+		fmt.Printf("Bundle %s does not match runtime %s:\n", bnd.Manifest.Hash(), rt.ID)
+		for _, m := range verr.Mismatches {
+			fmt.Printf("  - %s\n", m)
+		}
+		os.Exit(1)
+	default:
+		logger.Error("failed to verify bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doSign(_ *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	bnd, err := bundle.Open(args[0])
+	if err != nil {
+		logger.Error("failed to open bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer bnd.Close()
+
+	_, signer, err := cmdCommon.LoadEntitySigner()
+	if err != nil {
+		logger.Error("failed to load entity signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	sig, err := bundle.SignManifest(signer, bnd.Manifest)
+	if err != nil {
+		logger.Error("failed to sign bundle manifest",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = bundle.WriteManifestSignature(signatureFile(args[0]), sig); err != nil {
+		logger.Error("failed to write bundle manifest signature",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// Register registers the bundle sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	verifyCmd.Flags().AddFlagSet(verifyFlags)
+	verifyCmd.Flags().AddFlagSet(signatureFlags)
+	verifyCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
+
+	signCmd.Flags().AddFlagSet(signatureFlags)
+	signCmd.Flags().AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	signCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	signCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+
+	bundleCmd.AddCommand(verifyCmd)
+	bundleCmd.AddCommand(signCmd)
+	parentCmd.AddCommand(bundleCmd)
+}
+
+func init() {
+	verifyFlags.String(cfgRuntimeDescriptor, "", "path to the JSON-encoded runtime descriptor (queried from the node if not set)")
+	verifyFlags.StringSlice(cfgTrustedSigners, nil, "public keys of trusted bundle signers")
+	_ = viper.BindPFlags(verifyFlags)
+
+	signatureFlags.String(cfgSignature, "", "path to the detached manifest signature (defaults to <bundle-file>.sig)")
+	_ = viper.BindPFlags(signatureFlags)
+}
@@ -147,6 +147,14 @@ func (w *storageWorker) GetCheckpointChunk(ctx context.Context, chunk *checkpoin
 	return w.backend.GetCheckpointChunk(ctx, chunk, wr)
 }
 
+func (w *storageWorker) GetCheckpointChunkInfo(ctx context.Context, chunk *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	if w.failReadRequests {
+		return nil, errByzantine
+	}
+
+	return w.backend.GetCheckpointChunkInfo(ctx, chunk)
+}
+
 func (w *storageWorker) Cleanup() {
 }
 
@@ -3,7 +3,10 @@ package control
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -14,6 +17,8 @@ import (
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
 var (
@@ -45,6 +50,20 @@ var (
 		Run: doWaitReady,
 	}
 
+	controlDryRunUpgradeCmd = &cobra.Command{
+		Use:   "dry-run-upgrade <upgrade-descriptor>",
+		Short: "execute the upgrade handler against a throwaway copy of consensus state",
+		Args:  cobra.ExactArgs(1),
+		Run:   doDryRunUpgrade,
+	}
+
+	controlExportRuntimeStateCmd = &cobra.Command{
+		Use:   "export-runtime-state <runtime-id> <round> <path>",
+		Short: "export the runtime state at the given round into an archive on the node's filesystem",
+		Args:  cobra.ExactArgs(3),
+		Run:   doExportRuntimeState,
+	}
+
 	logger = logging.GetLogger("cmd/debug/control")
 )
 
@@ -115,6 +134,83 @@ func doWaitReady(cmd *cobra.Command, _ []string) {
 	}
 }
 
+func doDryRunUpgrade(cmd *cobra.Command, args []string) {
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	descriptorBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		logger.Error("failed to read upgrade descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var desc upgrade.Descriptor
+	if err = json.Unmarshal(descriptorBytes, &desc); err != nil {
+		logger.Error("can't parse upgrade descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	result, err := client.DryRunUpgrade(ctx, &desc)
+	if err != nil {
+		logger.Error("failed to dry run upgrade",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	result.PrettyPrint(ctx, "", os.Stdout)
+	if !result.Success {
+		os.Exit(1)
+	}
+}
+
+func doExportRuntimeState(cmd *cobra.Command, args []string) {
+	var request storageWorker.ExportStateRequest
+	if err := request.RuntimeID.UnmarshalHex(args[0]); err != nil {
+		logger.Error("malformed runtime id",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	round, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		logger.Error("malformed round",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	request.Round = round
+	if request.Path, err = filepath.Abs(args[2]); err != nil {
+		logger.Error("malformed path",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	result, err := client.ExportRuntimeState(context.Background(), &request)
+	if err != nil {
+		logger.Error("failed to export runtime state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	logger.Info("runtime state exported",
+		"round", result.Round,
+		"path", result.Path,
+		"state_root", result.StateRoot.Hash,
+		"io_root", result.IORoot.Hash,
+	)
+}
+
 // Register registers the dummy sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	controlCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
@@ -124,5 +220,7 @@ func Register(parentCmd *cobra.Command) {
 	controlCmd.AddCommand(controlSetEpochCmd)
 	controlCmd.AddCommand(controlWaitNodesCmd)
 	controlCmd.AddCommand(controlWaitReadyCmd)
+	controlCmd.AddCommand(controlDryRunUpgradeCmd)
+	controlCmd.AddCommand(controlExportRuntimeStateCmd)
 	parentCmd.AddCommand(controlCmd)
 }
@@ -5,9 +5,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/beacon"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/bundle"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/byzantine"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/control"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/dumpdb"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/persistent"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/runtime"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/sgx"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/storage"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/debug/txsource"
 )
@@ -26,6 +30,10 @@ func Register(parentCmd *cobra.Command) {
 	control.Register(debugCmd)
 	dumpdb.Register(debugCmd)
 	beacon.Register(debugCmd)
+	persistent.Register(debugCmd)
+	sgx.Register(debugCmd)
+	runtime.Register(debugCmd)
+	bundle.Register(debugCmd)
 
 	parentCmd.AddCommand(debugCmd)
 }
@@ -242,6 +242,9 @@ func doDumpDB(cmd *cobra.Command, _ []string) {
 	}
 	doc.Vault = vaultSt
 
+	// Use the same ordering as the state export so that the dumps can be compared.
+	doc.Canonicalize()
+
 	logger.Info("writing state dump",
 		"output", viper.GetString(cfgDumpOutput),
 	)
@@ -0,0 +1,238 @@
+// Package persistent implements the persistent store inspection sub-commands.
+package persistent
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+const (
+	cfgNamespace = "persistent.namespace"
+	cfgForce     = "persistent.force"
+)
+
+var (
+	persistentCmd = &cobra.Command{
+		Use:   "persistent",
+		Short: "node persistent store utilities",
+	}
+
+	dumpCmd = &cobra.Command{
+		Use:   "dump",
+		Short: "dump the contents of the persistent store in a readable form",
+		Run:   doDump,
+	}
+
+	exportCmd = &cobra.Command{
+		Use:   "export <output-file>",
+		Short: "export the persistent store into a snapshot file",
+		Args:  cobra.ExactArgs(1),
+		Run:   doExport,
+	}
+
+	importCmd = &cobra.Command{
+		Use:   "import <input-file>",
+		Short: "import a snapshot file into the persistent store",
+		Args:  cobra.ExactArgs(1),
+		Run:   doImport,
+	}
+
+	dumpFlags   = flag.NewFlagSet("", flag.ContinueOnError)
+	importFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/persistent")
+)
+
+func openStore(mustExist bool) *persistent.CommonStore {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	if mustExist {
+		// There does not appear to be a "open, but do not create" badger
+		// option, so check if the persistent store directory exists.
+		fs, err := os.Stat(persistent.GetPersistentStoreDBDir(dataDir))
+		if err != nil {
+			logger.Error("failed to stat persistent store directory",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		if !fs.IsDir() {
+			logger.Error("persistent store directory, is not a directory")
+			os.Exit(1)
+		}
+	}
+
+	store, err := persistent.NewCommonStore(dataDir)
+	if err != nil {
+		logger.Error("failed to open common node store",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return store
+}
+
+func doDump(*cobra.Command, []string) {
+	store := openStore(true)
+	defer store.Close()
+
+	namespaces := viper.GetStringSlice(cfgNamespace)
+	if len(namespaces) == 0 {
+		var err error
+		if namespaces, err = store.Namespaces(); err != nil {
+			logger.Error("failed to list namespaces",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	dump := make(map[string]map[string]any)
+	for _, ns := range namespaces {
+		entries := make(map[string]any)
+		err := store.GetServiceStore(ns).Iterate(func(key, value []byte) error {
+			var v any
+			if err := cbor.Unmarshal(value, &v); err != nil {
+				entries[formatBytes(key)] = hex.EncodeToString(value)
+				return nil
+			}
+			entries[formatBytes(key)] = readableValue(v)
+			return nil
+		})
+		if err != nil {
+			logger.Error("failed to iterate over namespace",
+				"err", err,
+				"namespace", ns,
+			)
+			os.Exit(1)
+		}
+		dump[ns] = entries
+	}
+
+	pretty, err := cmdCommon.PrettyJSONMarshal(dump)
+	if err != nil {
+		logger.Error("failed to get pretty JSON of persistent store",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	fmt.Println(string(pretty))
+}
+
+func doExport(_ *cobra.Command, args []string) {
+	store := openStore(true)
+	defer store.Close()
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		logger.Error("failed to create snapshot file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err = store.Export(context.Background(), f); err != nil {
+		logger.Error("failed to export persistent store",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doImport(_ *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		logger.Error("failed to open snapshot file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	store := openStore(false)
+	defer store.Close()
+
+	if err = store.Import(context.Background(), f, viper.GetBool(cfgForce)); err != nil {
+		logger.Error("failed to import persistent store snapshot",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+// formatBytes returns the given bytes as a string if it is valid UTF-8 and
+// hex-encoded otherwise.
+func formatBytes(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// readableValue converts a generically decoded CBOR value into a form that
+// can be serialized as JSON.
+func readableValue(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			switch k := k.(type) {
+			case []byte:
+				m[formatBytes(k)] = readableValue(val)
+			default:
+				m[fmt.Sprint(k)] = readableValue(val)
+			}
+		}
+		return m
+	case []any:
+		l := make([]any, 0, len(v))
+		for _, val := range v {
+			l = append(l, readableValue(val))
+		}
+		return l
+	case []byte:
+		return formatBytes(v)
+	default:
+		return v
+	}
+}
+
+// Register registers the persistent sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	dumpCmd.Flags().AddFlagSet(dumpFlags)
+	importCmd.Flags().AddFlagSet(importFlags)
+
+	persistentCmd.AddCommand(dumpCmd)
+	persistentCmd.AddCommand(exportCmd)
+	persistentCmd.AddCommand(importCmd)
+	parentCmd.AddCommand(persistentCmd)
+}
+
+func init() {
+	dumpFlags.StringSlice(cfgNamespace, nil, "namespaces to dump (default: all)")
+	_ = viper.BindPFlags(dumpFlags)
+
+	importFlags.Bool(cfgForce, false, "import even if the snapshot belongs to a different node identity")
+	_ = viper.BindPFlags(importFlags)
+}
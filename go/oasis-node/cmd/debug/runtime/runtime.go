@@ -0,0 +1,461 @@
+// Package runtime implements the runtime state inspection debug sub-commands.
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+const (
+	cfgRound  = "round"
+	cfgOutput = "output"
+	cfgLimit  = "limit"
+	cfgStart  = "start"
+	cfgDepth  = "depth"
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	stateFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+	prefixFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	statsFlags  = flag.NewFlagSet("", flag.ContinueOnError)
+
+	runtimeCmd = &cobra.Command{
+		Use:   "runtime",
+		Short: "runtime debug utilities",
+	}
+
+	stateCmd = &cobra.Command{
+		Use:   "state",
+		Short: "inspect verified runtime state",
+	}
+
+	stateGetCmd = &cobra.Command{
+		Use:   "get <runtime-id> <key>",
+		Short: "get the value of the given hex-encoded key from runtime state",
+		Args:  cobra.ExactArgs(2),
+		Run:   doStateGet,
+	}
+
+	statePrefixCmd = &cobra.Command{
+		Use:   "prefix <runtime-id> <prefix>",
+		Short: "list the entries with the given hex-encoded key prefix from runtime state",
+		Args:  cobra.ExactArgs(2),
+		Run:   doStatePrefix,
+	}
+
+	stateStatsCmd = &cobra.Command{
+		Use:   "stats <runtime-id>",
+		Short: "show runtime state node counts, key counts and sizes grouped by key prefix",
+		Long: `Walks the whole runtime state at the given round on the node and shows aggregate
+node counts, key counts and sizes grouped by key prefixes of the given length.
+The node must be running in debug mode. Results are cached by the node, but the
+first walk may take a long time as it is rate limited.`,
+		Args: cobra.ExactArgs(1),
+		Run:  doStateStats,
+	}
+
+	logger = logging.GetLogger("cmd/debug/runtime")
+)
+
+// stateEntry is a runtime state entry.
+type stateEntry struct {
+	Key     hexBytes `json:"key"`
+	Value   hexBytes `json:"value"`
+	Decoded any      `json:"decoded,omitempty"`
+}
+
+// stateGetOutput is the output of the state get command.
+type stateGetOutput struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+	StateRoot hash.Hash        `json:"state_root"`
+
+	stateEntry
+}
+
+// statePrefixOutput is the output of the state prefix command.
+type statePrefixOutput struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+	StateRoot hash.Hash        `json:"state_root"`
+	Entries   []stateEntry     `json:"entries"`
+
+	// Next is the key that should be used as the start of the next page (if any).
+	Next hexBytes `json:"next,omitempty"`
+}
+
+// hexBytes are bytes that are hex-encoded when marshalled to JSON.
+type hexBytes []byte
+
+// MarshalText encodes the bytes into text form.
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+func newStateEntry(key, value []byte) stateEntry {
+	return stateEntry{
+		Key:     key,
+		Value:   value,
+		Decoded: decodeValue(value),
+	}
+}
+
+// decodeValue attempts to decode the given value as CBOR, returning a value that can be
+// marshalled to JSON or nil in case the value is not valid CBOR.
+func decodeValue(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := cbor.Unmarshal(value, &v); err != nil {
+		return nil
+	}
+	return jsonCompatible(v)
+}
+
+// jsonCompatible converts the generic CBOR-decoded value into a value that can be marshalled to
+// JSON, converting map keys to strings and byte strings to hex.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			switch k := k.(type) {
+			case []byte:
+				m[hex.EncodeToString(k)] = jsonCompatible(val)
+			default:
+				m[fmt.Sprint(k)] = jsonCompatible(val)
+			}
+		}
+		return m
+	case []any:
+		s := make([]any, 0, len(v))
+		for _, val := range v {
+			s = append(s, jsonCompatible(val))
+		}
+		return s
+	case []byte:
+		return hex.EncodeToString(v)
+	default:
+		return v
+	}
+}
+
+// scanPrefix returns up to limit entries with the given key prefix, starting at the given key,
+// together with the key at which the next page starts (nil if there are no more entries).
+//
+// A limit of zero means that all entries are returned.
+func scanPrefix(ctx context.Context, tree mkvs.ImmutableKeyValueTree, prefix, start []byte, limit int) ([]stateEntry, []byte, error) {
+	if start != nil && !bytes.HasPrefix(start, prefix) {
+		return nil, nil, fmt.Errorf("start key does not have the given prefix")
+	}
+	if start == nil {
+		start = prefix
+	}
+
+	var options []mkvs.IteratorOption
+	if limit > 0 {
+		// Also prefetch the first entry of the next page.
+		options = append(options, mkvs.IteratorPrefetch(uint16(min(limit+1, 1<<16-1))))
+	}
+	it := tree.NewIterator(ctx, options...)
+	defer it.Close()
+
+	entries := []stateEntry{}
+	for it.Seek(start); it.Valid(); it.Next() {
+		if !bytes.HasPrefix(it.Key(), prefix) {
+			break
+		}
+		if limit > 0 && len(entries) == limit {
+			return entries, bytes.Clone(it.Key()), nil
+		}
+		entries = append(entries, newStateEntry(bytes.Clone(it.Key()), bytes.Clone(it.Value())))
+	}
+	if err := it.Err(); err != nil {
+		return nil, nil, err
+	}
+	return entries, nil, nil
+}
+
+// stateTree connects to the node and returns the runtime state tree at the configured round.
+//
+// All values read from the tree are verified against the state root of the runtime block.
+func stateTree(ctx context.Context, cmd *cobra.Command, rawID string) (mkvs.Tree, node.Root, func()) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var id common.Namespace
+	if err := id.UnmarshalHex(rawID); err != nil {
+		logger.Error("malformed runtime ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	client := runtimeClient.NewClient(conn)
+
+	blk, err := client.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: id,
+		Round:     viper.GetUint64(cfgRound),
+	})
+	if err != nil {
+		conn.Close()
+		logger.Error("failed to get runtime block",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	root := node.Root{
+		Namespace: id,
+		Version:   blk.Header.Round,
+		Type:      node.RootTypeState,
+		Hash:      blk.Header.StateRoot,
+	}
+	tree := mkvs.NewWithRoot(client.State(), nil, root)
+
+	return tree, root, func() {
+		tree.Close()
+		conn.Close()
+	}
+}
+
+func decodeHexArg(name, arg string) []byte {
+	b, err := hex.DecodeString(arg)
+	if err != nil {
+		logger.Error("malformed "+name,
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	return b
+}
+
+func printOutput(output any, printText func()) {
+	switch viper.GetString(cfgOutput) {
+	case outputJSON:
+		prettyJSON, err := cmdCommon.PrettyJSONMarshal(output)
+		if err != nil {
+			logger.Error("failed to get pretty JSON of runtime state",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		fmt.Println(string(prettyJSON))
+	case outputText:
+		printText()
+	default:
+		logger.Error("unsupported output format",
+			"output", viper.GetString(cfgOutput),
+		)
+		os.Exit(1)
+	}
+}
+
+func printEntryText(e *stateEntry) {
+	fmt.Printf("Key:   %s\n", hex.EncodeToString(e.Key))
+	fmt.Printf("Value: %s\n", hex.EncodeToString(e.Value))
+	if e.Decoded != nil {
+		decoded, err := cmdCommon.PrettyJSONMarshal(e.Decoded)
+		if err == nil {
+			fmt.Printf("Decoded (CBOR): %s\n", decoded)
+		}
+	}
+}
+
+func doStateGet(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	key := decodeHexArg("key", args[1])
+
+	tree, root, closeFn := stateTree(ctx, cmd, args[0])
+	defer closeFn()
+
+	value, err := tree.Get(ctx, key)
+	if err != nil {
+		logger.Error("failed to get runtime state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if value == nil {
+		logger.Error("key not found in runtime state",
+			"key", args[1],
+			"round", root.Version,
+		)
+		os.Exit(1)
+	}
+
+	output := stateGetOutput{
+		RuntimeID:  root.Namespace,
+		Round:      root.Version,
+		StateRoot:  root.Hash,
+		stateEntry: newStateEntry(key, value),
+	}
+	printOutput(output, func() {
+		fmt.Printf("Round: %d (state root: %s)\n", output.Round, output.StateRoot)
+		printEntryText(&output.stateEntry)
+	})
+}
+
+func doStatePrefix(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	prefix := decodeHexArg("prefix", args[1])
+	var start []byte
+	if rawStart := viper.GetString(cfgStart); rawStart != "" {
+		start = decodeHexArg("start key", rawStart)
+	}
+
+	tree, root, closeFn := stateTree(ctx, cmd, args[0])
+	defer closeFn()
+
+	entries, next, err := scanPrefix(ctx, tree, prefix, start, viper.GetInt(cfgLimit))
+	if err != nil {
+		logger.Error("failed to scan runtime state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	output := statePrefixOutput{
+		RuntimeID: root.Namespace,
+		Round:     root.Version,
+		StateRoot: root.Hash,
+		Entries:   entries,
+		Next:      next,
+	}
+	printOutput(output, func() {
+		fmt.Printf("Round: %d (state root: %s)\n", output.Round, output.StateRoot)
+		for i := range output.Entries {
+			fmt.Println()
+			printEntryText(&output.Entries[i])
+		}
+		if output.Next != nil {
+			fmt.Printf("\nMore entries available, continue with: --%s %s\n", cfgStart, hex.EncodeToString(output.Next))
+		}
+	})
+}
+
+func formatPrefix(ps *mkvs.PrefixStats) string {
+	prefix := hex.EncodeToString(ps.Prefix)
+	if ps.PrefixBitLength%8 != 0 {
+		prefix = fmt.Sprintf("%s/%d", prefix, ps.PrefixBitLength)
+	}
+	if prefix == "" {
+		prefix = "<empty>"
+	}
+	return prefix
+}
+
+func doStateStats(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var request storageWorker.StateStatsRequest
+	if err := request.RuntimeID.UnmarshalHex(args[0]); err != nil {
+		logger.Error("malformed runtime ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	request.MaxDepth = node.Depth(viper.GetUint(cfgDepth) * 8)
+
+	conn, err := cmdGrpc.NewClient(cmd)
+	if err != nil {
+		logger.Error("failed to establish connection with node",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	blk, err := runtimeClient.NewClient(conn).GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: request.RuntimeID,
+		Round:     viper.GetUint64(cfgRound),
+	})
+	if err != nil {
+		logger.Error("failed to get runtime block",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	request.Round = blk.Header.Round
+
+	stats, err := control.NewDebugControllerClient(conn).GetRuntimeStateStats(ctx, &request)
+	if err != nil {
+		logger.Error("failed to get runtime state statistics",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	printOutput(stats, func() {
+		fmt.Printf("Round: %d (state root: %s)\n", stats.Root.Version, stats.Root.Hash)
+		fmt.Printf("Total: %d keys, %d nodes, %d bytes (%d bytes of values)\n",
+			stats.Total.Keys, stats.Total.Nodes, stats.Total.Size, stats.Total.ValueSize)
+		fmt.Println()
+		fmt.Printf("%-32s %12s %12s %16s %16s\n", "PREFIX", "KEYS", "NODES", "SIZE", "VALUE SIZE")
+		for _, ps := range stats.Prefixes {
+			fmt.Printf("%-32s %12d %12d %16d %16d\n", formatPrefix(ps), ps.Keys, ps.Nodes, ps.Size, ps.ValueSize)
+		}
+	})
+}
+
+// Register registers the runtime sub-command and all of its children.
+func Register(parentCmd *cobra.Command) {
+	stateCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+	stateCmd.PersistentFlags().AddFlagSet(stateFlags)
+	statePrefixCmd.Flags().AddFlagSet(prefixFlags)
+	stateStatsCmd.Flags().AddFlagSet(statsFlags)
+
+	stateCmd.AddCommand(stateGetCmd)
+	stateCmd.AddCommand(statePrefixCmd)
+	stateCmd.AddCommand(stateStatsCmd)
+	runtimeCmd.AddCommand(stateCmd)
+	parentCmd.AddCommand(runtimeCmd)
+}
+
+func init() {
+	stateFlags.Uint64(cfgRound, runtimeClient.RoundLatest, "runtime round (defaults to the latest round)")
+	stateFlags.String(cfgOutput, outputText, "output format (text, json)")
+	_ = viper.BindPFlags(stateFlags)
+
+	prefixFlags.Int(cfgLimit, 100, "maximum number of entries to return (0 for no limit)")
+	prefixFlags.String(cfgStart, "", "hex-encoded key at which to start (for pagination)")
+	_ = viper.BindPFlags(prefixFlags)
+
+	statsFlags.Uint(cfgDepth, 1, "length of the key prefixes (in bytes) by which statistics are grouped")
+	_ = viper.BindPFlags(statsFlags)
+}
@@ -0,0 +1,85 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func TestScanPrefix(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tree := mkvs.New(nil, nil, node.RootTypeState)
+	defer tree.Close()
+	for i := range 10 {
+		require.NoError(tree.Insert(ctx, []byte(fmt.Sprintf("a/%d", i)), cbor.Marshal(i)), "Insert")
+	}
+	require.NoError(tree.Insert(ctx, []byte("b/0"), []byte("b")), "Insert")
+
+	root := node.Root{Type: node.RootTypeState}
+	_, rootHash, err := tree.Commit(ctx, root.Namespace, root.Version)
+	require.NoError(err, "Commit")
+	root.Hash = rootHash
+
+	// Read through a remote tree so that all proofs get verified.
+	remote := mkvs.NewWithRoot(tree, nil, root)
+	defer remote.Close()
+
+	// Paginate over all entries with the given prefix.
+	var (
+		keys  []string
+		start []byte
+		pages int
+	)
+	for {
+		entries, next, err := scanPrefix(ctx, remote, []byte("a/"), start, 4)
+		require.NoError(err, "scanPrefix")
+		require.LessOrEqual(len(entries), 4, "page should not exceed the limit")
+		for _, e := range entries {
+			keys = append(keys, string(e.Key))
+			require.EqualValues(int(e.Key[2]-'0'), e.Decoded, "value should be decoded")
+		}
+		pages++
+		if next == nil {
+			break
+		}
+		start = next
+	}
+	require.Equal(3, pages, "number of pages")
+	require.Len(keys, 10, "all entries should be returned")
+	require.Equal("a/0", keys[0])
+	require.Equal("a/9", keys[9])
+
+	// No limit.
+	entries, next, err := scanPrefix(ctx, remote, []byte("a/"), nil, 0)
+	require.NoError(err, "scanPrefix")
+	require.Len(entries, 10)
+	require.Nil(next)
+
+	// Start key without the prefix.
+	_, _, err = scanPrefix(ctx, remote, []byte("a/"), []byte("b/0"), 0)
+	require.Error(err, "start key without prefix should fail")
+}
+
+func TestDecodeValue(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(decodeValue(nil))
+	require.Nil(decodeValue([]byte{0xff, 0xff}), "invalid CBOR should not be decoded")
+
+	decoded := decodeValue(cbor.Marshal(map[string]any{
+		"bytes": []byte{0xde, 0xad},
+		"list":  []uint64{1, 2},
+	}))
+	require.Equal(map[string]any{
+		"bytes": "dead",
+		"list":  []any{uint64(1), uint64(2)},
+	}, decoded)
+}
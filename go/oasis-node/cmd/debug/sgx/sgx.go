@@ -0,0 +1,182 @@
+// Package sgx implements the SGX debug sub-commands.
+package sgx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+const (
+	cfgEnclaves           = "sgx.enclaves"
+	cfgTimestamp          = "sgx.timestamp"
+	cfgAllowDebugEnclaves = "sgx.allow_debug_enclaves"
+)
+
+var (
+	sgxCmd = &cobra.Command{
+		Use:   "sgx",
+		Short: "SGX utilities",
+	}
+
+	explainQuoteCmd = &cobra.Command{
+		Use:   "explain-quote <quote-file> <policy-file>",
+		Short: "explain why a quote does (not) satisfy a quote policy",
+		Long: `Verifies a PCS quote against a quote policy and reports which policy rule failed.
+
+The quote file is either a JSON-encoded quote bundle or a raw quote, in which case
+the TCB collateral is fetched from Intel PCS. The policy file is a YAML or JSON
+encoded PCS quote policy.`,
+		Args: cobra.ExactArgs(2),
+		Run:  doExplainQuote,
+	}
+
+	explainQuoteFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	logger = logging.GetLogger("cmd/debug/sgx")
+)
+
+func loadQuoteBundle(ctx context.Context, path string) (*pcs.QuoteBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quote file: %w", err)
+	}
+
+	// Quote bundle with collateral.
+	var bundle pcs.QuoteBundle
+	if err = json.Unmarshal(data, &bundle); err == nil {
+		return &bundle, nil
+	}
+
+	// Raw quote, fetch the collateral from PCS.
+	var quote pcs.Quote
+	size, err := quote.UnmarshalBinaryWithTrailing(data, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse quote: %w", err)
+	}
+	sig, ok := quote.Signature().(*pcs.QuoteSignatureECDSA_P256)
+	if !ok {
+		return nil, fmt.Errorf("unsupported attestation key type")
+	}
+	pckInfo, err := sig.VerifyPCK(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("PCK verification failed: %w", err)
+	}
+
+	client, err := pcs.NewHTTPClient(&pcs.HTTPClientConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PCS HTTP client: %w", err)
+	}
+	tcbBundle, err := client.GetTCBBundle(ctx, quote.Header().TeeType(), pckInfo.FMSPC, pcs.UpdateStandard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch TCB bundle: %w", err)
+	}
+
+	return &pcs.QuoteBundle{
+		Quote: data[:size],
+		TCB:   *tcbBundle,
+	}, nil
+}
+
+func loadPolicy(path string) (*pcs.QuotePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	// YAML is a superset of JSON.
+	var policy pcs.QuotePolicy
+	if err = yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func loadEnclaves() ([]sgx.EnclaveIdentity, error) {
+	rawEnclaves := viper.GetStringSlice(cfgEnclaves)
+	if len(rawEnclaves) == 0 {
+		return nil, nil
+	}
+
+	enclaves := make([]sgx.EnclaveIdentity, 0, len(rawEnclaves))
+	for _, raw := range rawEnclaves {
+		var id sgx.EnclaveIdentity
+		if err := id.UnmarshalHex(raw); err != nil {
+			return nil, err
+		}
+		enclaves = append(enclaves, id)
+	}
+	return enclaves, nil
+}
+
+func doExplainQuote(_ *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	if viper.GetBool(cfgAllowDebugEnclaves) {
+		pcs.SetAllowDebugEnclaves()
+	}
+
+	bundle, err := loadQuoteBundle(context.Background(), args[0])
+	if err != nil {
+		logger.Error("failed to load quote",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	policy, err := loadPolicy(args[1])
+	if err != nil {
+		logger.Error("failed to load quote policy",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	enclaves, err := loadEnclaves()
+	if err != nil {
+		logger.Error("failed to parse allowed enclaves",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	ts := time.Now()
+	if unix := viper.GetInt64(cfgTimestamp); unix != 0 {
+		ts = time.Unix(unix, 0)
+	}
+
+	failure := pcs.ExplainQuotePolicyFailure(bundle, policy, ts, enclaves)
+	if failure == nil {
+		fmt.Println("Quote satisfies the policy.")
+		return
+	}
+	fmt.Print(failure.String())
+	os.Exit(1)
+}
+
+// Register registers the sgx sub-command and all of it's children.
+func Register(parentCmd *cobra.Command) {
+	explainQuoteCmd.Flags().AddFlagSet(explainQuoteFlags)
+
+	sgxCmd.AddCommand(explainQuoteCmd)
+	parentCmd.AddCommand(sgxCmd)
+}
+
+func init() {
+	explainQuoteFlags.StringSlice(cfgEnclaves, nil, "allowed hex-encoded enclave identities (MRENCLAVE || MRSIGNER)")
+	explainQuoteFlags.Int64(cfgTimestamp, 0, "verification UNIX timestamp (default: now)")
+	explainQuoteFlags.Bool(cfgAllowDebugEnclaves, false, "allow debug enclaves")
+	_ = viper.BindPFlags(explainQuoteFlags)
+}
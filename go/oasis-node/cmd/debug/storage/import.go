@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/archive"
+)
+
+var storageImportRuntimeStateCmd = &cobra.Command{
+	Use:   "import-runtime-state <archive>",
+	Short: "import a runtime state archive into the local storage of a fresh node",
+	Long: "Import a runtime state archive produced by the export-runtime-state debug control " +
+		"command into the local storage of a fresh node. The command prints the runtime " +
+		"descriptor and the genesis runtime state that must be used in the genesis document " +
+		"for the node to start at exactly the archived state.",
+	Args: cobra.ExactArgs(1),
+	Run:  doImportRuntimeState,
+}
+
+// importedRuntimeState is the output of the import-runtime-state command.
+type importedRuntimeState struct {
+	// Runtime is the runtime descriptor active at the archived round.
+	Runtime *registry.Runtime `json:"runtime"`
+	// Deployment is the runtime deployment that was active at the archived round.
+	Deployment *registry.VersionInfo `json:"deployment,omitempty"`
+	// RuntimeState is the genesis runtime state to use in the genesis document.
+	RuntimeState *roothash.GenesisRuntimeState `json:"runtime_state"`
+}
+
+func doImportRuntimeState(_ *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	if !cmdFlags.DebugDontBlameOasis() {
+		logger.Error("importing runtime state requires debug mode to be enabled")
+		os.Exit(1)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	imported, err := importRuntimeState(context.Background(), dataDir, args[0])
+	if err != nil {
+		logger.Error("failed to import runtime state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(imported); err != nil {
+		logger.Error("failed to encode imported runtime state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func importRuntimeState(ctx context.Context, dataDir, fn string) (*importedRuntimeState, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	rd, err := archive.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	hdr := rd.Header()
+
+	storageBackend, err := newDirectStorageBackend(runtimeConfig.GetRuntimeStateDir(dataDir, hdr.RuntimeID), hdr.RuntimeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct storage backend: %w", err)
+	}
+	<-storageBackend.Initialized()
+	defer storageBackend.Cleanup()
+
+	ndb := storageBackend.(storageAPI.LocalBackend).NodeDB()
+	if version, ok := ndb.GetLatestVersion(); ok {
+		return nil, fmt.Errorf("local storage already initialized (latest version: %d)", version)
+	}
+
+	var roots []node.Root
+	for {
+		root, err := rd.ReadRoot(ctx, func(root node.Root) mkvs.Tree {
+			return mkvs.New(nil, ndb, root.Type, mkvs.WithoutWriteLog())
+		})
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("imported storage root",
+			"root", root,
+		)
+		roots = append(roots, *root)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("%w: no storage roots", archive.ErrInvalidArchive)
+	}
+	if err = ndb.Finalize(roots); err != nil {
+		return nil, fmt.Errorf("failed to finalize imported roots: %w", err)
+	}
+
+	return &importedRuntimeState{
+		Runtime:    hdr.Runtime,
+		Deployment: hdr.Deployment,
+		RuntimeState: &roothash.GenesisRuntimeState{
+			RuntimeGenesis: registry.RuntimeGenesis{
+				StateRoot: hdr.BlockHeader.StateRoot,
+				Round:     hdr.Round,
+			},
+		},
+	}, nil
+}
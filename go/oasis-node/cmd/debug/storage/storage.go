@@ -222,10 +222,13 @@ func Register(parentCmd *cobra.Command) {
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	storageExportCmd.Flags().AddFlagSet(storageExportFlags)
 
+	storageImportRuntimeStateCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
+
 	storageBenchmarkCmd.Flags().AddFlagSet(storageBenchmarkFlags)
 
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageExportCmd)
+	storageCmd.AddCommand(storageImportRuntimeStateCmd)
 	storageCmd.AddCommand(storageBenchmarkCmd)
 	parentCmd.AddCommand(storageCmd)
 }
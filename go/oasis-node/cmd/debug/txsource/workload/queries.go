@@ -77,7 +77,6 @@ type queries struct {
 	logger *logging.Logger
 
 	runtimeID       common.Namespace
-	epochtimeParams *beacon.ConsensusParameters
 	stakingParams   *staking.ConsensusParameters
 	schedulerParams *scheduler.ConsensusParameters
 
@@ -217,14 +216,19 @@ func (q *queries) doConsensusQueries(ctx context.Context, rng *rand.Rand, height
 	if block.Height != height {
 		return fmt.Errorf("block.Height: %d == %d violated", block.Height, height)
 	}
-	if params := q.epochtimeParams.InsecureParameters; params != nil && !q.epochtimeParams.DebugMockBackend {
-		expectedEpoch := beacon.EpochTime(block.Height / params.Interval)
-		if expectedEpoch != epoch {
+	params, err := q.beacon.ConsensusParameters(ctx, height)
+	if err != nil {
+		return fmt.Errorf("beacon.ConsensusParameters at height %d: %w", height, err)
+	}
+	if params.InsecureParameters != nil && !params.DebugMockBackend {
+		// Epochs of the insecure backend start at fixed heights.
+		start, end := params.EpochBlocks(0, epoch), params.EpochBlocks(0, epoch+1)
+		if block.Height < start || block.Height >= end {
 			q.logger.Error("invalid epoch",
-				"expected", expectedEpoch,
 				"epoch", epoch,
+				"epoch_start", start,
+				"epoch_end", end,
 				"height", block.Height,
-				"epoch_interval", params.Interval,
 			)
 			return fmt.Errorf("invalid epoch: %d", epoch)
 		}
@@ -911,10 +915,6 @@ func (q *queries) Run(
 	if err != nil {
 		return fmt.Errorf("failed to query scheduler consensus parameters: %w", err)
 	}
-	q.epochtimeParams, err = q.beacon.ConsensusParameters(ctx, consensusAPI.HeightLatest)
-	if err != nil {
-		return fmt.Errorf("failed to query epochtime consensus parameters: %w", err)
-	}
 
 	// Setup simple-keyvalue runtime info.
 	err = q.runtimeID.UnmarshalHex(viper.GetString(CfgRuntimeID))
@@ -55,12 +55,18 @@ const (
 	CfgChainID       = "chain.id"
 	CfgInitialHeight = "initial_height"
 
+	// Dump config flags.
+	cfgDumpServices          = "dump.services"
+	cfgDumpDropRuntimeStates = "dump.drop_runtime_states"
+	cfgDumpAddressRemap      = "dump.address_remap"
+
 	// Registry config flags.
 	CfgRegistryMaxNodeExpiration                      = "registry.max_node_expiration"
 	CfgRegistryDisableRuntimeRegistration             = "registry.disable_runtime_registration"
 	CfgRegistryDebugAllowUnroutableAddresses          = "registry.debug.allow_unroutable_addresses"
 	CfgRegistryDebugAllowTestRuntimes                 = "registry.debug.allow_test_runtimes"
 	CfgRegistryEnableRuntimeGovernanceModels          = "registry.enable_runtime_governance_models"
+	CfgRegistryEnableElectionPolicies                 = "registry.enable_election_policies"
 	CfgRegistryTEEFeaturesSGXPCS                      = "registry.tee_features.sgx.pcs"
 	CfgRegistryTEEFeaturesSGXSignedAttestations       = "registry.tee_features.sgx.signed_attestations"
 	CfgRegistryTEEFeaturesSGXDefaultMaxAttestationAge = "registry.tee_features.sgx.default_max_attestation_age"
@@ -96,6 +102,7 @@ const (
 	CfgRoothashMaxRuntimeMessages        = "roothash.max_runtime_messages"
 	CfgRoothashMaxInRuntimeMessages      = "roothash.max_in_runtime_messages"
 	CfgRoothashMaxPastRootsStored        = "roothash.max_past_roots_stored"
+	CfgRoothashMaxLateCommitmentBlocks   = "roothash.max_late_commitment_blocks"
 
 	// Staking config flags.
 	CfgStakingTokenSymbol        = "staking.token_symbol"
@@ -116,6 +123,7 @@ const (
 	CfgConsensusGasCostsTxByte           = "consensus.gas_costs.tx_byte"
 	cfgConsensusBlacklistPublicKey       = "consensus.blacklist_public_key"
 	CfgConsensusFeatureVersion           = "consensus.feature_version"
+	CfgConsensusDebugBlockTimeControl    = "consensus.debug.block_time_control"
 
 	// Consensus backend config flag.
 	CfgConsensusBackend = "consensus.backend"
@@ -313,8 +321,9 @@ func doInitGenesis(*cobra.Command, []string) {
 			GasCosts: transaction.Costs{
 				consensusGenesis.GasOpTxByte: transaction.Gas(viper.GetUint64(CfgConsensusGasCostsTxByte)),
 			},
-			PublicKeyBlacklist: pkBlacklist,
-			FeatureVersion:     featureVersion,
+			PublicKeyBlacklist:    pkBlacklist,
+			FeatureVersion:        featureVersion,
+			DebugBlockTimeControl: viper.GetBool(CfgConsensusDebugBlockTimeControl),
 		},
 	}
 
@@ -354,6 +363,7 @@ func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []stri
 			MaxNodeExpiration:             viper.GetUint64(CfgRegistryMaxNodeExpiration),
 			DisableRuntimeRegistration:    viper.GetBool(CfgRegistryDisableRuntimeRegistration),
 			EnableRuntimeGovernanceModels: make(map[registry.RuntimeGovernanceModel]bool),
+			EnableElectionPolicies:        make(map[scheduler.ElectionPolicy]bool),
 		},
 		Entities: make([]*entity.SignedEntity, 0, len(entities)),
 		Runtimes: make([]*registry.Runtime, 0, len(runtimes)),
@@ -390,6 +400,14 @@ func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []stri
 		regSt.Parameters.EnableRuntimeGovernanceModels[gm] = true
 	}
 
+	for _, epStr := range viper.GetStringSlice(CfgRegistryEnableElectionPolicies) {
+		var ep scheduler.ElectionPolicy
+		if err := ep.UnmarshalText([]byte(strings.ToLower(epStr))); err != nil {
+			return fmt.Errorf("%w: '%s'", err, epStr)
+		}
+		regSt.Parameters.EnableElectionPolicies[ep] = true
+	}
+
 	entMap := make(map[signature.PublicKey]bool)
 	appendToEntities := func(signedEntity *entity.SignedEntity, ent *entity.Entity) error {
 		if entMap[ent.ID] {
@@ -526,6 +544,7 @@ func AppendRootHashState(doc *genesis.Document, exports []string, l *logging.Log
 			MaxRuntimeMessages:        viper.GetUint32(CfgRoothashMaxRuntimeMessages),
 			MaxInRuntimeMessages:      viper.GetUint32(CfgRoothashMaxInRuntimeMessages),
 			MaxPastRootsStored:        viper.GetUint64(CfgRoothashMaxPastRootsStored),
+			MaxLateCommitmentBlocks:   viper.GetUint64(CfgRoothashMaxLateCommitmentBlocks),
 			GasCosts:                  roothash.DefaultGasCosts, // TODO: Make these configurable.
 		},
 	}
@@ -666,7 +685,23 @@ func doDumpGenesis(cmd *cobra.Command, _ []string) {
 		)
 		os.Exit(1)
 	}
-	doc, err := client.StateToGenesis(ctx, height)
+	filter, err := loadDumpFilter()
+	if err != nil {
+		logger.Error("failed to load dump filter",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	var doc *genesis.Document
+	if filter.IsEmpty() {
+		doc, err = client.StateToGenesis(ctx, height)
+	} else {
+		doc, err = client.StateToGenesisFiltered(ctx, &consensus.StateToGenesisRequest{
+			Height: height,
+			Filter: filter,
+		})
+	}
 	if err != nil {
 		logger.Error("failed to generate genesis document",
 			"err", err,
@@ -700,6 +735,28 @@ func doDumpGenesis(cmd *cobra.Command, _ []string) {
 	}
 }
 
+func loadDumpFilter() (*genesis.DumpFilter, error) {
+	filter := genesis.DumpFilter{
+		Services:          viper.GetStringSlice(cfgDumpServices),
+		DropRuntimeStates: viper.GetBool(cfgDumpDropRuntimeStates),
+	}
+
+	if path := viper.GetString(cfgDumpAddressRemap); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read address remap file: %w", err)
+		}
+		if err = json.Unmarshal(data, &filter.AddressRemap); err != nil {
+			return nil, fmt.Errorf("failed to parse address remap file: %w", err)
+		}
+	}
+
+	if err := filter.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
 func doCheckGenesis(*cobra.Command, []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -788,6 +845,9 @@ func init() {
 	checkGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 
 	dumpGenesisFlags.Int64(cfgBlockHeight, consensus.HeightLatest, "block height at which to dump state")
+	dumpGenesisFlags.StringSlice(cfgDumpServices, nil, fmt.Sprintf("services whose state to include (default: all, supported: %s)", strings.Join(genesis.FilterableServices, ", ")))
+	dumpGenesisFlags.Bool(cfgDumpDropRuntimeStates, false, "omit the roothash runtime states")
+	dumpGenesisFlags.String(cfgDumpAddressRemap, "", "path to a JSON file mapping account addresses to their replacements")
 	_ = viper.BindPFlags(dumpGenesisFlags)
 	dumpGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 
@@ -805,6 +865,7 @@ func init() {
 	initGenesisFlags.Bool(CfgRegistryDebugAllowUnroutableAddresses, false, "allow unroutable addreses (UNSAFE)")
 	initGenesisFlags.Bool(CfgRegistryDebugAllowTestRuntimes, false, "enable test runtime registration")
 	initGenesisFlags.StringSlice(CfgRegistryEnableRuntimeGovernanceModels, []string{"entity"}, "set of enabled runtime governance models")
+	initGenesisFlags.StringSlice(CfgRegistryEnableElectionPolicies, []string{}, "set of enabled committee election policies in addition to uniform")
 	initGenesisFlags.Bool(CfgRegistryTEEFeaturesSGXPCS, true, "enable PCS support for SGX TEEs")
 	initGenesisFlags.Bool(CfgRegistryTEEFeaturesSGXSignedAttestations, true, "enable SGX RAK-signed attestations")
 	initGenesisFlags.Uint64(CfgRegistryTEEFeaturesSGXDefaultMaxAttestationAge, 1200, "default max attestation age (SGX RAK-signed attestations must be enabled") // ~2 hours at 6 sec per block.
@@ -846,6 +907,7 @@ func init() {
 	initGenesisFlags.Uint32(CfgRoothashMaxRuntimeMessages, 128, "maximum number of runtime messages submitted in a round")
 	initGenesisFlags.Uint32(CfgRoothashMaxInRuntimeMessages, 128, "maximum number of ququed incoming runtime messages")
 	initGenesisFlags.Uint64(CfgRoothashMaxPastRootsStored, 1200, "maximum number of past runtime state and I/O roots stored in consensus state")
+	initGenesisFlags.Uint64(CfgRoothashMaxLateCommitmentBlocks, 0, "number of blocks after round finalization during which late executor commitments are accepted for liveness accounting")
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugDoNotSuspendRuntimes)
 	_ = initGenesisFlags.MarkHidden(cfgRoothashDebugBypassStake)
 
@@ -869,6 +931,8 @@ func init() {
 	initGenesisFlags.Uint64(CfgConsensusGasCostsTxByte, 1, "consensus gas costs: each transaction byte")
 	initGenesisFlags.StringSlice(cfgConsensusBlacklistPublicKey, nil, "blacklist public key")
 	initGenesisFlags.String(CfgConsensusFeatureVersion, "", "latest consensus breaking software feature version")
+	initGenesisFlags.Bool(CfgConsensusDebugBlockTimeControl, false, "enable debug block time control (UNSAFE)")
+	_ = initGenesisFlags.MarkHidden(CfgConsensusDebugBlockTimeControl)
 
 	// Consensus backend flag.
 	initGenesisFlags.String(CfgConsensusBackend, cmt.BackendName, "consensus backend")
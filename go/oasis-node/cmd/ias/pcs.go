@@ -0,0 +1,302 @@
+package ias
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/service"
+	iasPCS "github.com/oasisprotocol/oasis-core/go/ias/pcs"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
+)
+
+const (
+	cfgPCSAddress          = "ias.pcs.address"
+	cfgPCSUpstream         = "ias.pcs.upstream"
+	cfgPCSOffline          = "ias.pcs.offline"
+	cfgPCSRefreshThreshold = "ias.pcs.refresh_threshold"
+	cfgPCSRefreshInterval  = "ias.pcs.refresh_interval"
+
+	cfgPCSBundle         = "ias.pcs.bundle"
+	cfgPCSTrustedSigners = "ias.pcs.trusted_signers"
+)
+
+var (
+	pcsFlags       = flag.NewFlagSet("", flag.ContinueOnError)
+	pcsBundleFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	pcsImportFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	pcsCmd = &cobra.Command{
+		Use:   "pcs",
+		Short: "caching PCS proxy utilities",
+	}
+
+	pcsExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "export the cached PCS collateral as a signed bundle",
+		Long: `Exports all non-expired PCS collateral cached by the proxy in the given data
+directory as a bundle signed by the entity signer. The bundle can be imported
+by an offline proxy.`,
+		Run: doPCSExport,
+	}
+
+	pcsImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "import PCS collateral from a signed bundle",
+		Long: `Imports PCS collateral from a bundle signed by one of the trusted signers into
+the cache of the proxy in the given data directory. Expired collateral and
+collateral older than what is already cached is skipped.`,
+		Run: doPCSImport,
+	}
+)
+
+type pcsService struct {
+	service.BaseBackgroundService
+
+	address         string
+	refreshInterval time.Duration
+
+	store *persistent.CommonStore
+	proxy *iasPCS.Proxy
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	listener net.Listener
+	server   *http.Server
+}
+
+func (s *pcsService) Start() error {
+	s.Logger.Info("caching PCS proxy is enabled",
+		"address", s.address,
+		"offline", s.proxy.IsOffline(),
+	)
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	s.listener = listener
+	s.server = &http.Server{Handler: s.proxy, ReadTimeout: 5 * time.Second}
+
+	go s.proxy.Worker(s.ctx, s.refreshInterval)
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil {
+			if err != http.ErrServerClosed {
+				s.Logger.Error("caching PCS proxy terminated uncleanly",
+					"err", err,
+				)
+			}
+		}
+		s.BaseBackgroundService.Stop()
+	}()
+
+	return nil
+}
+
+func (s *pcsService) Stop() {
+	s.cancel()
+	if s.server != nil {
+		_ = s.server.Close()
+		s.server = nil
+	}
+}
+
+func (s *pcsService) Cleanup() {
+	if s.listener != nil {
+		_ = s.listener.Close()
+		s.listener = nil
+	}
+	s.store.Close()
+}
+
+func newPCSService(dataDir string) (service.BackgroundService, error) {
+	cfg := iasPCS.Config{
+		RefreshThreshold: viper.GetDuration(cfgPCSRefreshThreshold),
+	}
+	if !viper.GetBool(cfgPCSOffline) {
+		cfg.Upstream = viper.GetString(cfgPCSUpstream)
+	}
+
+	store, err := persistent.NewCommonStore(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open common store: %w", err)
+	}
+	cache, err := iasPCS.NewCache(store)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	proxy, err := iasPCS.New(cache, cfg)
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &pcsService{
+		BaseBackgroundService: *service.NewBaseBackgroundService("pcs-proxy"),
+		address:               viper.GetString(cfgPCSAddress),
+		refreshInterval:       viper.GetDuration(cfgPCSRefreshInterval),
+		store:                 store,
+		proxy:                 proxy,
+		ctx:                   ctx,
+		cancel:                cancel,
+	}, nil
+}
+
+func loadPCSCache() (*persistent.CommonStore, *iasPCS.Cache, error) {
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		return nil, nil, fmt.Errorf("data directory not configured")
+	}
+
+	store, err := persistent.NewCommonStore(dataDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open common store: %w", err)
+	}
+	cache, err := iasPCS.NewCache(store)
+	if err != nil {
+		store.Close()
+		return nil, nil, err
+	}
+	return store, cache, nil
+}
+
+func doPCSExport(*cobra.Command, []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	store, cache, err := loadPCSCache()
+	if err != nil {
+		logger.Error("failed to load PCS collateral cache",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	_, signer, err := cmdCommon.LoadEntitySigner()
+	if err != nil {
+		logger.Error("failed to load entity signer",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer signer.Reset()
+
+	bundle, err := cache.Export(signer)
+	if err != nil {
+		logger.Error("failed to export PCS collateral",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(viper.GetString(cfgPCSBundle), cbor.Marshal(bundle), 0o600); err != nil {
+		logger.Error("failed to write PCS collateral bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Exported %d PCS collateral entries signed by %s.\n", len(cache.Entries()), signer.Public())
+}
+
+func doPCSImport(*cobra.Command, []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var trusted []signature.PublicKey
+	for _, raw := range viper.GetStringSlice(cfgPCSTrustedSigners) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(raw)); err != nil {
+			logger.Error("malformed trusted signer",
+				"signer", raw,
+				"err", err,
+			)
+			os.Exit(1)
+		}
+		trusted = append(trusted, pk)
+	}
+	if len(trusted) == 0 {
+		logger.Error("no trusted PCS collateral bundle signers configured")
+		os.Exit(1)
+	}
+
+	raw, err := os.ReadFile(viper.GetString(cfgPCSBundle))
+	if err != nil {
+		logger.Error("failed to read PCS collateral bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	var bundle iasPCS.SignedBundle
+	if err = cbor.Unmarshal(raw, &bundle); err != nil {
+		logger.Error("malformed PCS collateral bundle",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	store, cache, err := loadPCSCache()
+	if err != nil {
+		logger.Error("failed to load PCS collateral cache",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	result, err := cache.Import(&bundle, trusted)
+	if err != nil {
+		logger.Error("failed to import PCS collateral",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d PCS collateral entries (%d expired, %d stale).\n", result.Imported, result.Expired, result.Stale)
+}
+
+func registerPCS(parentCmd *cobra.Command) {
+	pcsExportCmd.Flags().AddFlagSet(pcsBundleFlags)
+	pcsExportCmd.Flags().AddFlagSet(cmdFlags.DebugTestEntityFlags)
+	pcsExportCmd.Flags().AddFlagSet(cmdSigner.Flags)
+	pcsExportCmd.Flags().AddFlagSet(cmdSigner.CLIFlags)
+
+	pcsImportCmd.Flags().AddFlagSet(pcsBundleFlags)
+	pcsImportCmd.Flags().AddFlagSet(pcsImportFlags)
+
+	pcsCmd.AddCommand(pcsExportCmd)
+	pcsCmd.AddCommand(pcsImportCmd)
+	parentCmd.AddCommand(pcsCmd)
+}
+
+func init() {
+	pcsFlags.String(cfgPCSAddress, "", "caching PCS proxy HTTP listen address (disabled if empty)")
+	pcsFlags.String(cfgPCSUpstream, iasPCS.DefaultUpstream, "upstream PCS API base URL")
+	pcsFlags.Bool(cfgPCSOffline, false, "never refresh collateral from upstream, only serve imported collateral")
+	pcsFlags.Duration(cfgPCSRefreshThreshold, 7*24*time.Hour, "time before collateral expiry at which it is refreshed from upstream")
+	pcsFlags.Duration(cfgPCSRefreshInterval, time.Hour, "interval at which cached collateral is checked for refresh")
+	_ = viper.BindPFlags(pcsFlags)
+
+	pcsBundleFlags.String(cfgPCSBundle, "pcs_collateral.cbor", "path to the PCS collateral bundle")
+	_ = viper.BindPFlags(pcsBundleFlags)
+
+	pcsImportFlags.StringSlice(cfgPCSTrustedSigners, nil, "public keys of trusted PCS collateral bundle signers")
+	_ = viper.BindPFlags(pcsImportFlags)
+}
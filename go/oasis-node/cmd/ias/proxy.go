@@ -180,6 +180,25 @@ func doProxy(cmd *cobra.Command, _ []string) {
 		return
 	}
 
+	// Initialize and start the caching PCS proxy.
+	if viper.GetString(cfgPCSAddress) != "" {
+		pcsProxy, err := newPCSService(dataDir)
+		if err != nil {
+			logger.Error("failed to initialize caching PCS proxy",
+				"err", err,
+			)
+			return
+		}
+		env.svcMgr.Register(pcsProxy)
+
+		if err = pcsProxy.Start(); err != nil {
+			logger.Error("failed to start caching PCS proxy",
+				"err", err,
+			)
+			return
+		}
+	}
+
 	// Initialize the IAS proxy authenticator.
 	authenticator, err := grpcAuthenticatorFromFlags(env.svcMgr.Ctx, cmd)
 	if err != nil {
@@ -264,8 +283,10 @@ func grpcAuthenticatorFromFlags(ctx context.Context, cmd *cobra.Command) (iasPro
 // Register registers the ias sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	iasProxyCmd.Flags().AddFlagSet(proxyFlags)
+	iasProxyCmd.Flags().AddFlagSet(pcsFlags)
 
 	iasCmd.AddCommand(iasProxyCmd)
+	registerPCS(iasCmd)
 	parentCmd.AddCommand(iasCmd)
 }
 
@@ -18,15 +18,23 @@ import (
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	cmdGrpc "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
+	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 	cmdControl "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/control"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/identity/cometbft"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
-const CfgDataDir = "datadir"
+const (
+	CfgDataDir = "datadir"
+
+	cfgNewPassphraseFile = "signer.file.new_passphrase_file"
+
+	envNewPassphrase = "OASIS_SIGNER_NEW_PASSPHRASE"
+)
 
 var (
-	datadirFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	datadirFlags          = flag.NewFlagSet("", flag.ContinueOnError)
+	rotatePassphraseFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	identityCmd = &cobra.Command{
 		Use:   "identity",
@@ -51,6 +59,12 @@ var (
 		Run:   doShowTLSPubkey,
 	}
 
+	identityRotatePassphraseCmd = &cobra.Command{
+		Use:   "rotate-passphrase",
+		Short: "re-encrypt node key files with a new passphrase",
+		Run:   doRotatePassphrase,
+	}
+
 	identityShowAddressCmd = &cobra.Command{
 		Use:   "show-address",
 		Short: "outputs node's address",
@@ -75,7 +89,7 @@ func doNodeInit(*cobra.Command, []string) {
 	}
 
 	// Provision the node identity.
-	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	nodeSignerFactory, err := cmdSigner.NewFileFactory(dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to create identity signer factory",
 			"err", err,
@@ -92,6 +106,40 @@ func doNodeInit(*cobra.Command, []string) {
 	fmt.Printf("Generated identity files in: %s\n", dataDir)
 }
 
+func doRotatePassphrase(*cobra.Command, []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	// Workaround for viper bug: https://github.com/spf13/viper/issues/233
+	_ = viper.BindPFlag(CfgDataDir, identityCmd.PersistentFlags().Lookup(CfgDataDir))
+
+	dataDir := viper.GetString(CfgDataDir)
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		os.Exit(1)
+	}
+
+	// Include all roles so that an entity key stored next to the node keys is rotated as well.
+	signerFactory, err := cmdSigner.NewFileFactory(dataDir, signature.SignerRoles...)
+	if err != nil {
+		logger.Error("failed to create identity signer factory",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	passphrase, err := cmdSigner.ReadPassphrase(cfgNewPassphraseFile, envNewPassphrase, "Enter new key passphrase: ", true)
+	if err != nil {
+		cmdCommon.EarlyLogAndExit(fmt.Errorf("failed to read new passphrase: %w", err))
+	}
+	if err = signerFactory.(*fileSigner.Factory).RotatePassphrase(passphrase); err != nil {
+		cmdCommon.EarlyLogAndExit(fmt.Errorf("failed to rotate passphrase: %w", err))
+	}
+
+	fmt.Printf("Rotated passphrase of key files in: %s\n", dataDir)
+}
+
 func doShowPubkey(_ *cobra.Command, _ []string, sentry bool) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -106,7 +154,7 @@ func doShowPubkey(_ *cobra.Command, _ []string, sentry bool) {
 		os.Exit(1)
 	}
 
-	nodeSignerFactory, err := fileSigner.NewFactory(dataDir, identity.RequiredSignerRoles...)
+	nodeSignerFactory, err := cmdSigner.NewFileFactory(dataDir, identity.RequiredSignerRoles...)
 	if err != nil {
 		logger.Error("failed to create node identity signer factory",
 			"err", err,
@@ -162,15 +210,19 @@ func Register(parentCmd *cobra.Command) {
 	cometbft.Register(identityCmd)
 
 	identityCmd.PersistentFlags().AddFlagSet(datadirFlags)
+	identityCmd.PersistentFlags().AddFlagSet(cmdSigner.FileFlags)
 
 	identityInitCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
 
 	identityShowAddressCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
+	identityRotatePassphraseCmd.Flags().AddFlagSet(rotatePassphraseFlags)
+
 	identityCmd.AddCommand(identityInitCmd)
 	identityCmd.AddCommand(identityShowSentryPubkeyCmd)
 	identityCmd.AddCommand(identityShowTLSPubkeyCmd)
 	identityCmd.AddCommand(identityShowAddressCmd)
+	identityCmd.AddCommand(identityRotatePassphraseCmd)
 
 	parentCmd.AddCommand(identityCmd)
 }
@@ -178,4 +230,7 @@ func Register(parentCmd *cobra.Command) {
 func init() {
 	datadirFlags.String(CfgDataDir, "", "data directory")
 	_ = viper.BindPFlags(datadirFlags)
+
+	rotatePassphraseFlags.String(cfgNewPassphraseFile, "", "path to file containing the new file signer passphrase (alternatively set "+envNewPassphrase+")")
+	_ = viper.BindPFlags(rotatePassphraseFlags)
 }
@@ -60,6 +60,14 @@ func (s *debugStorage) GetCheckpointChunk(ctx context.Context, chunk *checkpoint
 	return rt.Storage().GetCheckpointChunk(ctx, chunk, w)
 }
 
+func (s *debugStorage) GetCheckpointChunkInfo(ctx context.Context, chunk *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	rt, err := s.n.RuntimeRegistry.GetRuntime(chunk.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return rt.Storage().GetCheckpointChunkInfo(ctx, chunk)
+}
+
 func (s *debugStorage) Cleanup() {
 }
 
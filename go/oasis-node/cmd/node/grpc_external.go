@@ -0,0 +1,84 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	consensusAPI "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommonConfig "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/config"
+	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+// initExternalGrpc initializes the external gRPC server together with its access policy.
+func (n *Node) initExternalGrpc() error {
+	cfg := config.GlobalConfig.Common.Grpc
+	if cfg.Port == 0 {
+		return nil
+	}
+
+	var err error
+	n.grpcAccessPolicy, err = newGrpcAccessPolicy(&cfg, n.Consensus.Registry())
+	if err != nil {
+		return err
+	}
+
+	n.grpcExternal, err = grpc.NewServer(&grpc.ServerConfig{
+		Name:         "external",
+		Port:         cfg.Port,
+		Identity:     n.Identity,
+		AccessPolicy: n.grpcAccessPolicy,
+	})
+	if err != nil {
+		return err
+	}
+	n.svcMgr.Register(n.grpcExternal)
+
+	return nil
+}
+
+// newGrpcAccessPolicy creates the access policy of the external gRPC server, treating node and
+// entity keys registered in the given registry as registered keys.
+func newGrpcAccessPolicy(cfg *cmdCommonConfig.GrpcConfig, registry registryAPI.Backend) (*grpc.AccessPolicy, error) {
+	methods := make(map[string]grpc.MethodPolicy, len(cfg.MethodPolicies))
+	for method, policy := range cfg.MethodPolicies {
+		methods[method] = grpc.MethodPolicy(policy)
+	}
+
+	allowlist := make([]signature.PublicKey, 0, len(cfg.Allowlist))
+	for _, key := range cfg.Allowlist {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(key)); err != nil {
+			return nil, fmt.Errorf("malformed allowlisted public key %s: %w", key, err)
+		}
+		allowlist = append(allowlist, pk)
+	}
+
+	registered := func(ctx context.Context, key signature.PublicKey) (bool, error) {
+		query := &registryAPI.IDQuery{ID: key, Height: consensusAPI.HeightLatest}
+
+		_, err := registry.GetNode(ctx, query)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, registryAPI.ErrNoSuchNode):
+		default:
+			return false, err
+		}
+
+		_, err = registry.GetEntity(ctx, query)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, registryAPI.ErrNoSuchEntity):
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+
+	return grpc.NewAccessPolicy(methods, allowlist, registered)
+}
@@ -0,0 +1,121 @@
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
+	cmnTesting "github.com/oasisprotocol/oasis-core/go/common/grpc/testing"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+func TestGrpcAccessAllowlistReload(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	policy, err := cmnGrpc.NewAccessPolicy(map[string]cmnGrpc.MethodPolicy{
+		cmnTesting.MethodPing.FullName(): cmnGrpc.MethodPolicyAllowlist,
+	}, nil, nil)
+	require.NoError(err, "NewAccessPolicy")
+
+	// Start the external server with the access policy.
+	serverIdentity, err := identity.LoadOrGenerate(t.TempDir(), memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate (server)")
+	externalCfg := &cmnGrpc.ServerConfig{
+		Name:         "localhost",
+		Port:         52174,
+		Identity:     serverIdentity,
+		AccessPolicy: policy,
+	}
+	external, err := cmnGrpc.NewServer(externalCfg)
+	require.NoError(err, "NewServer (external)")
+	cmnTesting.RegisterService(external.Server(), cmnTesting.NewPingServer(auth.NoAuth))
+	require.NoError(external.Start(), "Start (external)")
+	defer func() {
+		external.Stop()
+		external.Cleanup()
+	}()
+
+	// Start the control server of a node using the access policy.
+	n := &Node{
+		grpcAccessPolicy: policy,
+		logger:           logging.GetLogger("node/test"),
+	}
+	socketPath := filepath.Join(t.TempDir(), "internal.sock")
+	internal, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "internal",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer (internal)")
+	control.RegisterService(internal.Server(), n)
+	require.NoError(internal.Start(), "Start (internal)")
+	defer func() {
+		internal.Stop()
+		internal.Cleanup()
+	}()
+
+	controlConn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(err, "Dial (internal)")
+	defer controlConn.Close()
+	controlClient := control.NewNodeControllerClient(controlConn)
+
+	clientIdentity, err := identity.LoadOrGenerate(t.TempDir(), memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate (client)")
+	clientKey := clientIdentity.GetTLSSigner().Public()
+
+	ping := func() error {
+		creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+			CommonName: identity.CommonName,
+			ServerPubKeys: map[signature.PublicKey]bool{
+				serverIdentity.GetTLSSigner().Public(): true,
+			},
+			Certificates: []tls.Certificate{*clientIdentity.GetTLSCertificate()},
+		})
+		require.NoError(err, "NewClientCreds")
+		conn, err := cmnGrpc.Dial(
+			fmt.Sprintf("%s:%d", externalCfg.Name, externalCfg.Port),
+			grpc.WithTransportCredentials(creds),
+		)
+		require.NoError(err, "Dial (external)")
+		defer conn.Close()
+
+		_, err = cmnTesting.NewPingClient(conn).Ping(ctx, &cmnTesting.PingQuery{})
+		return err
+	}
+
+	err = ping()
+	require.Error(err, "unknown key should be rejected")
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	// Allowlist the client at runtime.
+	err = controlClient.SetGrpcAccessAllowlist(ctx, []signature.PublicKey{clientKey})
+	require.NoError(err, "SetGrpcAccessAllowlist")
+	require.Equal([]signature.PublicKey{clientKey}, policy.Allowlist())
+	require.NoError(ping(), "allowlisted key should be accepted")
+
+	// Remove the client from the allowlist.
+	err = controlClient.SetGrpcAccessAllowlist(ctx, nil)
+	require.NoError(err, "SetGrpcAccessAllowlist")
+	err = ping()
+	require.Error(err, "removed key should be rejected")
+	require.Equal(codes.PermissionDenied, status.Code(err))
+
+	// Nodes without an external gRPC server should refuse to change the allowlist.
+	n.grpcAccessPolicy = nil
+	err = controlClient.SetGrpcAccessAllowlist(ctx, []signature.PublicKey{clientKey})
+	require.ErrorIs(err, control.ErrNotImplemented)
+}
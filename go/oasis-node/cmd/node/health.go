@@ -0,0 +1,92 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/health"
+)
+
+// healthUpdateInterval is the interval at which the gRPC health service status is updated.
+const healthUpdateInterval = heartbeat.Interval
+
+// initHealth configures the heartbeat staleness thresholds, starts the health probe HTTP
+// endpoint (if enabled) and registers the standard gRPC health service with the gRPC servers.
+func (n *Node) initHealth() error {
+	cfg := config.GlobalConfig.Health
+	heartbeat.DefaultMonitor().SetThresholds(cfg.StalenessThreshold, cfg.ServiceStalenessThresholds)
+
+	probes, err := health.New(n)
+	if err != nil {
+		return err
+	}
+	n.svcMgr.Register(probes)
+	if err = probes.Start(); err != nil {
+		return err
+	}
+
+	srv := grpcHealth.NewServer()
+	healthpb.RegisterHealthServer(n.grpcInternal.Server(), srv)
+	if n.grpcExternal != nil {
+		healthpb.RegisterHealthServer(n.grpcExternal.Server(), srv)
+	}
+	go n.healthWorker(n.svcMgr.Ctx, srv)
+
+	return nil
+}
+
+// healthWorker periodically updates the status of the liveness and readiness services reported
+// by the given gRPC health server. The overall server status follows liveness.
+func (n *Node) healthWorker(ctx context.Context, srv *grpcHealth.Server) {
+	defer srv.Shutdown()
+
+	ticker := time.NewTicker(healthUpdateInterval)
+	defer ticker.Stop()
+
+	var wasLive bool
+	for {
+		live := n.updateHealth(ctx, srv)
+		if wasLive && !live {
+			for _, status := range heartbeat.DefaultMonitor().Status() {
+				if !status.Stale {
+					continue
+				}
+				n.logger.Warn("internal service is unresponsive",
+					"service", status.Name,
+					"last_beat", status.LastBeat,
+					"threshold", status.Threshold,
+				)
+			}
+		}
+		wasLive = live
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Node) updateHealth(ctx context.Context, srv *grpcHealth.Server) bool {
+	servingStatus := func(ok bool, err error) healthpb.HealthCheckResponse_ServingStatus {
+		if err != nil || !ok {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	live, err := n.IsLive(ctx)
+	liveness := servingStatus(live, err)
+	srv.SetServingStatus("", liveness)
+	srv.SetServingStatus(control.HealthServiceLiveness, liveness)
+	srv.SetServingStatus(control.HealthServiceReadiness, servingStatus(n.IsReady(ctx)))
+
+	return liveness == healthpb.HealthCheckResponse_SERVING
+}
@@ -0,0 +1,100 @@
+package node
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+func TestHealth(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	n := &Node{
+		readyCh: make(chan struct{}),
+		logger:  logging.GetLogger("node/test"),
+	}
+	healthSrv := grpcHealth.NewServer()
+
+	socketPath := filepath.Join(t.TempDir(), "internal.sock")
+	internal, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "internal",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	control.RegisterService(internal.Server(), n)
+	healthpb.RegisterHealthServer(internal.Server(), healthSrv)
+	require.NoError(internal.Start(), "Start")
+	defer func() {
+		internal.Stop()
+		internal.Cleanup()
+	}()
+
+	// Standard gRPC health clients use protocol buffers.
+	healthConn, err := grpc.NewClient("unix:"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(err, "NewClient")
+	defer healthConn.Close()
+	healthClient := healthpb.NewHealthClient(healthConn)
+
+	controlConn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(err, "Dial")
+	defer controlConn.Close()
+	controlClient := control.NewNodeControllerClient(controlConn)
+
+	requireHealth := func(live, ready bool) {
+		servingStatus := func(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+			if ok {
+				return healthpb.HealthCheckResponse_SERVING
+			}
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		require.Equal(live, n.updateHealth(ctx, healthSrv))
+		for service, expected := range map[string]bool{
+			"":                             live,
+			control.HealthServiceLiveness:  live,
+			control.HealthServiceReadiness: ready,
+		} {
+			rsp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			require.NoError(err, "Check %s", service)
+			require.Equal(servingStatus(expected), rsp.GetStatus(), "status of %s", service)
+		}
+
+		isLive, err := controlClient.IsLive(ctx)
+		require.NoError(err, "IsLive")
+		require.Equal(live, isLive)
+		isReady, err := controlClient.IsReady(ctx)
+		require.NoError(err, "IsReady")
+		require.Equal(ready, isReady)
+	}
+
+	requireHealth(true, false)
+
+	close(n.readyCh)
+	requireHealth(true, true)
+
+	// A stuck service loop should flip liveness.
+	monitor := heartbeat.DefaultMonitor()
+	monitor.SetThresholds(heartbeat.DefaultThreshold, map[string]time.Duration{"test": time.Millisecond})
+	defer monitor.SetThresholds(heartbeat.DefaultThreshold, nil)
+	hb := heartbeat.Register("test/stuck")
+	defer hb.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	requireHealth(false, true)
+
+	hb.Beat()
+	requireHealth(true, true)
+}
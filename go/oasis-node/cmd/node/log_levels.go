@@ -0,0 +1,113 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/config"
+)
+
+// logLevelsBucketName is the name of the database bucket for the log levels
+// changed via the control API.
+const logLevelsBucketName = "node/log_levels"
+
+// logLevelsKey is the database key under which the log level overrides are stored.
+var logLevelsKey = []byte("overrides")
+
+// setLogLevel parses the given log level and applies it to the given module.
+// An empty level removes the log level override of the module.
+func setLogLevel(module, level string) error {
+	if module == "" {
+		return fmt.Errorf("missing module")
+	}
+
+	if level == "" {
+		if module == logging.DefaultModule {
+			return fmt.Errorf("the default log level cannot be reset")
+		}
+		logging.ResetLevel(module)
+		return nil
+	}
+
+	var lvl logging.Level
+	if err := lvl.Set(level); err != nil {
+		return err
+	}
+	return logging.SetLevel(module, lvl)
+}
+
+// getLogLevels returns the current log levels keyed by module.
+func getLogLevels() map[string]string {
+	defaultLvl, moduleLvls := logging.GetLevels()
+
+	levels := make(map[string]string, len(moduleLvls)+1)
+	levels[logging.DefaultModule] = strings.ToLower(defaultLvl.String())
+	for module, lvl := range moduleLvls {
+		levels[module] = strings.ToLower(lvl.String())
+	}
+	return levels
+}
+
+// persistLogLevel stores the log level override in the common store, if
+// persistence of log levels is enabled. An empty level removes the stored
+// override of the module.
+func persistLogLevel(store *persistent.CommonStore, module, level string) error {
+	if store == nil || !config.GlobalConfig.Common.Log.PersistLevels {
+		return nil
+	}
+
+	ss := store.GetServiceStore(logLevelsBucketName)
+	defer ss.Close()
+
+	overrides := make(map[string]string)
+	switch err := ss.GetCBOR(logLevelsKey, &overrides); err {
+	case nil, persistent.ErrNotFound:
+	default:
+		return fmt.Errorf("failed to load log level overrides: %w", err)
+	}
+	switch level {
+	case "":
+		delete(overrides, module)
+	default:
+		overrides[module] = strings.ToLower(level)
+	}
+
+	if len(overrides) == 0 {
+		switch err := ss.Delete(logLevelsKey); err {
+		case nil, persistent.ErrNotFound:
+			return nil
+		default:
+			return err
+		}
+	}
+	return ss.PutCBOR(logLevelsKey, overrides)
+}
+
+// restoreLogLevels applies the log level overrides previously persisted in
+// the common store, if persistence of log levels is enabled.
+func restoreLogLevels(store *persistent.CommonStore) error {
+	if !config.GlobalConfig.Common.Log.PersistLevels {
+		return nil
+	}
+
+	ss := store.GetServiceStore(logLevelsBucketName)
+	defer ss.Close()
+
+	var overrides map[string]string
+	switch err := ss.GetCBOR(logLevelsKey, &overrides); err {
+	case nil:
+	case persistent.ErrNotFound:
+		return nil
+	default:
+		return fmt.Errorf("failed to load log level overrides: %w", err)
+	}
+
+	for module, level := range overrides {
+		if err := setLogLevel(module, level); err != nil {
+			return fmt.Errorf("failed to restore log level of module '%s': %w", module, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/config"
+)
+
+func TestResetLogLevel(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	config.GlobalConfig.Common.Log.PersistLevels = true
+	defer func() {
+		config.GlobalConfig.Common.Log.PersistLevels = false
+	}()
+
+	store, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	n := &Node{
+		commonStore: store,
+		logger:      logging.GetLogger("node/test"),
+	}
+
+	loadOverrides := func() map[string]string {
+		ss := store.GetServiceStore(logLevelsBucketName)
+		defer ss.Close()
+
+		var overrides map[string]string
+		switch err := ss.GetCBOR(logLevelsKey, &overrides); err {
+		case nil, persistent.ErrNotFound:
+		default:
+			require.NoError(err, "GetCBOR")
+		}
+		return overrides
+	}
+
+	require.NoError(n.SetLogLevel(ctx, "test/module/a", "debug"), "SetLogLevel")
+	require.NoError(n.SetLogLevel(ctx, "test/module/b", "warn"), "SetLogLevel")
+	levels, err := n.GetLogLevels(ctx)
+	require.NoError(err, "GetLogLevels")
+	require.Equal("debug", levels["test/module/a"])
+	require.Equal(map[string]string{"test/module/a": "debug", "test/module/b": "warn"}, loadOverrides())
+
+	// An empty level should reset the override and remove the persisted entry.
+	require.NoError(n.SetLogLevel(ctx, "test/module/a", ""), "SetLogLevel reset")
+	levels, err = n.GetLogLevels(ctx)
+	require.NoError(err, "GetLogLevels")
+	require.NotContains(levels, "test/module/a", "override should be removed")
+	require.Equal(map[string]string{"test/module/b": "warn"}, loadOverrides())
+
+	// Removing the last override should remove the persisted overrides.
+	require.NoError(n.SetLogLevel(ctx, "test/module/b", ""), "SetLogLevel reset")
+	require.Nil(loadOverrides(), "persisted overrides should be removed")
+
+	// Resetting a module without an override should be a no-op.
+	require.NoError(n.SetLogLevel(ctx, "test/module/b", ""), "SetLogLevel reset")
+
+	// The default log level cannot be reset.
+	require.Error(n.SetLogLevel(ctx, logging.DefaultModule, ""), "default log level reset should fail")
+}
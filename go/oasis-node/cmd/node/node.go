@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
@@ -18,6 +19,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/consensus/cometbft"
 	cometbftAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
 	consensusLightP2P "github.com/oasisprotocol/oasis-core/go/consensus/p2p/light"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	controlAPI "github.com/oasisprotocol/oasis-core/go/control/api"
 	genesisAPI "github.com/oasisprotocol/oasis-core/go/genesis/api"
 	genesisFile "github.com/oasisprotocol/oasis-core/go/genesis/file"
@@ -31,7 +33,6 @@ import (
 	p2pAPI "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
 	roothashAPI "github.com/oasisprotocol/oasis-core/go/roothash/api"
-	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/provisioner"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
@@ -59,6 +60,9 @@ import (
 type Node struct {
 	svcMgr       *background.ServiceManager
 	grpcInternal *grpc.Server
+	grpcExternal *grpc.Server
+
+	grpcAccessPolicy *grpc.AccessPolicy
 
 	stopOnce sync.Once
 
@@ -75,7 +79,7 @@ type Node struct {
 	Sentry   sentryAPI.Backend
 
 	RuntimeRegistry runtimeRegistry.Registry
-	Provisioner     host.Provisioner
+	Provisioner     *provisioner.Provisioner
 
 	CommonWorker       *workerCommon.Worker
 	ExecutorWorker     *executor.Worker
@@ -88,6 +92,9 @@ type Node struct {
 	BeaconWorker       *workerBeacon.Worker
 	readyCh            chan struct{}
 
+	roleTransitionLock sync.Mutex
+	roleTransition     *control.RoleTransitionStatus
+
 	logger *logging.Logger
 }
 
@@ -98,6 +105,7 @@ func (n *Node) Cleanup() {
 		n.Upgrader.Close()
 	}
 	if n.commonStore != nil {
+		journal.Close()
 		n.commonStore.Close()
 	}
 }
@@ -146,14 +154,26 @@ func (n *Node) waitReady() {
 		<-n.CommonWorker.Initialized()
 	}
 
+	// Wait for the initial registration, unless the node never registers.
+	if n.RegistrationWorker != nil && !n.RegistrationWorker.WillNeverRegister() {
+		<-n.RegistrationWorker.InitialRegistrationCh()
+	}
+
 	close(n.readyCh)
 }
 
 // startRuntimeServices initializes and starts all the services that are required for runtime
 // support to work.
 func (n *Node) startRuntimeServices(genesisDoc *genesisAPI.Document) error {
-	var err error
-	if n.Sentry, err = sentry.New(n.Consensus, n.Identity); err != nil {
+	upstreams, err := workerSentry.UpstreamPubKeys()
+	if err != nil {
+		return err
+	}
+	consensusUpstreams, err := workerSentry.ConsensusUpstreamAddresses()
+	if err != nil {
+		return err
+	}
+	if n.Sentry, err = sentry.New(n.Consensus, n.Identity, upstreams, consensusUpstreams); err != nil {
 		return err
 	}
 
@@ -197,6 +217,7 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 	if err != nil {
 		return err
 	}
+	n.svcMgr.RegisterCleanupOnly(n.Provisioner, "runtime provisioner")
 
 	// Initialize the node's runtime registry.
 	n.RuntimeRegistry, err = runtimeRegistry.New(n.dataDir, n.Consensus)
@@ -209,6 +230,7 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 	n.CommonWorker, err = workerCommon.New(
 		n,
 		n.dataDir,
+		n.commonStore,
 		n.chainContext,
 		n.Identity,
 		n.Consensus,
@@ -251,6 +273,13 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 	}
 	n.svcMgr.Register(n.RegistrationWorker)
 
+	// Omit runtimes that were paused via the control API from node registration.
+	for id, rt := range n.CommonWorker.GetRuntimes() {
+		if rt.IsPaused() {
+			n.RegistrationWorker.SetRuntimePaused(id, true)
+		}
+	}
+
 	// Initialize the beacon worker.
 	n.BeaconWorker, err = workerBeacon.New(
 		n.Identity,
@@ -275,6 +304,7 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 
 	// Initialize the key manager worker.
 	n.KeymanagerWorker, err = workerKeymanager.New(
+		n.grpcInternal,
 		n.CommonWorker,
 		n.RegistrationWorker,
 		n.Consensus.KeyManager(),
@@ -289,6 +319,7 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 	n.ExecutorWorker, err = executor.New(
 		n.CommonWorker,
 		n.RegistrationWorker,
+		n.StorageWorker,
 	)
 	if err != nil {
 		return err
@@ -298,6 +329,7 @@ func (n *Node) initRuntimeWorkers(genesisDoc *genesisAPI.Document) error {
 	// Initialize the client worker.
 	n.ClientWorker, err = workerClient.New(
 		n.grpcInternal,
+		n.grpcExternal,
 		n.CommonWorker,
 		n.RegistrationWorker,
 	)
@@ -480,6 +512,30 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Record the identity of the node owning the common node store.
+	if err = node.commonStore.SetNodeID(node.Identity.NodeSigner.Public()); err != nil {
+		logger.Error("failed to record node identity in common node store",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	// Initialize the event journal.
+	if err = journal.Init(node.commonStore, config.GlobalConfig.Common.EventJournal.MaxEvents); err != nil {
+		logger.Error("failed to initialize event journal",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	// Restore any log levels changed via the control API.
+	if err = restoreLogLevels(node.commonStore); err != nil {
+		logger.Error("failed to restore log levels",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Initialize upgrader backend.
 	isArchive := config.GlobalConfig.Mode == config.ModeArchive
 	node.Upgrader, err = upgrade.New(node.commonStore, node.dataDir, !isArchive)
@@ -515,6 +571,22 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 	node.svcMgr.Register(node.Consensus)
 	consensusAPI.RegisterService(node.grpcInternal.Server(), node.Consensus)
 
+	// Initialize the external gRPC server, if enabled.
+	if err = node.initExternalGrpc(); err != nil {
+		logger.Error("failed to initialize external gRPC server",
+			"err", err,
+		)
+		return nil, err
+	}
+
+	// Initialize liveness and readiness probes.
+	if err = node.initHealth(); err != nil {
+		logger.Error("failed to initialize health probes",
+			"err", err,
+		)
+		return nil, err
+	}
+
 	// Initialize P2P network. Since libp2p host starts listening immediately when created, make
 	// sure that we don't start it if it is not needed.
 	if !isArchive {
@@ -579,6 +651,16 @@ func NewNode() (node *Node, err error) { // nolint: gocyclo
 		return nil, err
 	}
 
+	// Start the external gRPC server.
+	if node.grpcExternal != nil {
+		if err = node.grpcExternal.Start(); err != nil {
+			logger.Error("failed to start external gRPC server",
+				"err", err,
+			)
+			return nil, err
+		}
+	}
+
 	// Start the consensus backend service.
 	if err = node.Consensus.Start(); err != nil {
 		logger.Error("failed to start consensus backend service",
@@ -1,23 +1,44 @@
 package node
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmtCrypto "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/crypto"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	sentryAPI "github.com/oasisprotocol/oasis-core/go/sentry/api"
+	sentryClient "github.com/oasisprotocol/oasis-core/go/sentry/client"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	keymanagerWorker "github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/indexer"
 )
 
+// sentryStatusTimeout is the timeout for querying the status of a sentry node.
+const sentryStatusTimeout = 5 * time.Second
+
+// removableRoles are the roles that can be removed at runtime via the control API.
+const removableRoles = node.RoleComputeWorker | node.RoleStorageRPC
+
 // Assert that the node implements NodeController interface.
 var _ control.NodeController = (*Node)(nil)
 
@@ -81,6 +102,11 @@ func (n *Node) IsReady(ctx context.Context) (bool, error) {
 	}
 }
 
+// IsLive implements control.NodeController.
+func (n *Node) IsLive(context.Context) (bool, error) {
+	return heartbeat.DefaultMonitor().IsLive(), nil
+}
+
 // WaitSync implements control.NodeController.
 func (n *Node) WaitSync(ctx context.Context) error {
 	select {
@@ -105,7 +131,7 @@ func (n *Node) IsSynced(ctx context.Context) (bool, error) {
 
 // UpgradeBinary implements control.NodeController.
 func (n *Node) UpgradeBinary(_ context.Context, descriptor *upgrade.Descriptor) error {
-	return n.Upgrader.SubmitDescriptor(descriptor)
+	return n.Upgrader.SubmitDescriptor(upgrade.UpgradeSourceLocal, descriptor)
 }
 
 // CancelUpgrade implements control.NodeController.
@@ -113,6 +139,16 @@ func (n *Node) CancelUpgrade(_ context.Context, descriptor *upgrade.Descriptor)
 	return n.Upgrader.CancelUpgrade(descriptor)
 }
 
+// ListPendingUpgrades implements control.NodeController.
+func (n *Node) ListPendingUpgrades(context.Context) ([]*upgrade.PendingUpgrade, error) {
+	return n.Upgrader.PendingUpgrades()
+}
+
+// CancelLocalUpgrade implements control.NodeController.
+func (n *Node) CancelLocalUpgrade(_ context.Context, name upgrade.HandlerName) error {
+	return n.Upgrader.CancelLocalUpgrade(name)
+}
+
 // GetStatus implements control.NodeController.
 func (n *Node) GetStatus(ctx context.Context) (*control.Status, error) {
 	cs, err := n.getConsensusStatus(ctx)
@@ -120,11 +156,15 @@ func (n *Node) GetStatus(ctx context.Context) (*control.Status, error) {
 		return nil, fmt.Errorf("failed to get consensus status: %w", err)
 	}
 
+	ve := n.getValidatorEvidence(ctx, cs)
+
 	lcs, err := n.getLightClientStatus()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get light client status: %w", err)
 	}
 
+	tcb := n.getTCBStatus()
+
 	rs, err := n.getRegistrationStatus(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get registration status: %w", err)
@@ -135,6 +175,8 @@ func (n *Node) GetStatus(ctx context.Context) (*control.Status, error) {
 		return nil, fmt.Errorf("failed to get runtime status: %w", err)
 	}
 
+	bs := n.getBeaconStatus()
+
 	kms, err := n.getKeymanagerStatus()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get key manager worker status: %w", err)
@@ -145,30 +187,50 @@ func (n *Node) GetStatus(ctx context.Context) (*control.Status, error) {
 		return nil, fmt.Errorf("failed to get pending upgrades: %w", err)
 	}
 
+	upgradeReadiness, err := n.Upgrader.PendingUpgradeReadiness()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending upgrade readiness: %w", err)
+	}
+
 	ident := n.getIdentityStatus()
 
 	p2p := n.getP2PStatus()
 
+	sentry, err := n.getSentryStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sentry status: %w", err)
+	}
+
+	sentries := n.getSentriesStatus(ctx)
+
 	var ds *control.DebugStatus
 	if debugEnabled := cmdFlags.DebugDontBlameOasis(); debugEnabled {
 		ds = &control.DebugStatus{
 			Enabled:   debugEnabled,
 			AllowRoot: cmdFlags.DebugAllowRoot(),
+			Faults:    fault.Active(),
 		}
 	}
 
 	return &control.Status{
-		SoftwareVersion: version.SoftwareVersion,
-		Mode:            config.GlobalConfig.Mode,
-		Debug:           ds,
-		Identity:        ident,
-		Consensus:       cs,
-		LightClient:     lcs,
-		Runtimes:        runtimes,
-		Keymanager:      kms,
-		Registration:    rs,
-		PendingUpgrades: pendingUpgrades,
-		P2P:             p2p,
+		SoftwareVersion:   version.SoftwareVersion,
+		Mode:              config.GlobalConfig.Mode,
+		Debug:             ds,
+		Identity:          ident,
+		Consensus:         cs,
+		ValidatorEvidence: ve,
+		LightClient:       lcs,
+		TCBStatus:         tcb,
+		Runtimes:          runtimes,
+		Beacon:            bs,
+		RoleTransition:    n.getRoleTransitionStatus(),
+		Keymanager:        kms,
+		Registration:      rs,
+		PendingUpgrades:   pendingUpgrades,
+		UpgradeReadiness:  upgradeReadiness,
+		P2P:               p2p,
+		Sentry:            sentry,
+		Sentries:          sentries,
 	}, nil
 }
 
@@ -177,18 +239,272 @@ func (n *Node) AddBundle(_ context.Context, path string) error {
 	return n.RuntimeRegistry.GetBundleManager().Add(path)
 }
 
+// SetLogLevel implements control.NodeController.
+func (n *Node) SetLogLevel(_ context.Context, module string, level string) error {
+	if err := setLogLevel(module, level); err != nil {
+		return err
+	}
+
+	n.logger.Info("log level changed",
+		"module", module,
+		"level", level,
+	)
+
+	return persistLogLevel(n.commonStore, module, level)
+}
+
+// GetLogLevels implements control.NodeController.
+func (n *Node) GetLogLevels(context.Context) (map[string]string, error) {
+	return getLogLevels(), nil
+}
+
+// SetTxPoolLanes implements control.NodeController.
+func (n *Node) SetTxPoolLanes(_ context.Context, req *control.SetTxPoolLanesRequest) error {
+	if n.CommonWorker == nil {
+		return control.ErrNotImplemented
+	}
+	rtNode := n.CommonWorker.GetRuntime(req.RuntimeID)
+	if rtNode == nil {
+		return fmt.Errorf("runtime %s not found", req.RuntimeID)
+	}
+	return rtNode.TxPool.SetLanes(req.Lanes)
+}
+
+// PauseRuntime implements control.NodeController.
+func (n *Node) PauseRuntime(_ context.Context, runtimeID common.Namespace) error {
+	if n.CommonWorker == nil || !n.CommonWorker.Enabled() {
+		return control.ErrRuntimeNotHosted
+	}
+	if err := n.CommonWorker.PauseRuntime(runtimeID); err != nil {
+		return err
+	}
+	n.RegistrationWorker.SetRuntimePaused(runtimeID, true)
+	return nil
+}
+
+// ResumeRuntime implements control.NodeController.
+func (n *Node) ResumeRuntime(_ context.Context, runtimeID common.Namespace) error {
+	if n.CommonWorker == nil || !n.CommonWorker.Enabled() {
+		return control.ErrRuntimeNotHosted
+	}
+	if err := n.CommonWorker.ResumeRuntime(runtimeID); err != nil {
+		return err
+	}
+	n.RegistrationWorker.SetRuntimePaused(runtimeID, false)
+	return nil
+}
+
+// SetGrpcAccessAllowlist implements control.NodeController.
+func (n *Node) SetGrpcAccessAllowlist(_ context.Context, keys []signature.PublicKey) error {
+	if n.grpcAccessPolicy == nil {
+		return control.ErrNotImplemented
+	}
+	n.grpcAccessPolicy.SetAllowlist(keys)
+
+	n.logger.Info("gRPC access allowlist updated",
+		"num_keys", len(keys),
+	)
+	return nil
+}
+
+// AddIndexerToken implements control.NodeController.
+func (n *Node) AddIndexerToken(_ context.Context, token *storageWorker.IndexerToken) error {
+	idx, err := n.getIndexer()
+	if err != nil {
+		return err
+	}
+	return idx.Authenticator().Add(token)
+}
+
+// RevokeIndexerToken implements control.NodeController.
+func (n *Node) RevokeIndexerToken(_ context.Context, name string) error {
+	idx, err := n.getIndexer()
+	if err != nil {
+		return err
+	}
+	return idx.Authenticator().Revoke(name)
+}
+
+// ListIndexerTokens implements control.NodeController.
+func (n *Node) ListIndexerTokens(context.Context) ([]*storageWorker.IndexerTokenInfo, error) {
+	idx, err := n.getIndexer()
+	if err != nil {
+		return nil, err
+	}
+	return idx.Authenticator().Tokens(), nil
+}
+
+func (n *Node) getIndexer() (*indexer.Server, error) {
+	if n.StorageWorker == nil || n.StorageWorker.Indexer() == nil {
+		return nil, storageWorker.ErrIndexerDisabled
+	}
+	return n.StorageWorker.Indexer(), nil
+}
+
+// GetWorkerPoolLimits implements control.NodeController.
+func (n *Node) GetWorkerPoolLimits(context.Context) (*workerpool.Limits, error) {
+	limits := workerpool.Shared().Limits()
+	return &limits, nil
+}
+
+// SetWorkerPoolLimits implements control.NodeController.
+func (n *Node) SetWorkerPoolLimits(_ context.Context, limits *workerpool.Limits) error {
+	if err := workerpool.Shared().SetLimits(*limits); err != nil {
+		return err
+	}
+
+	n.logger.Info("worker pool limits changed",
+		"global", limits.Global,
+		"classes", limits.Classes,
+	)
+	return nil
+}
+
+// UpdateRoles implements control.NodeController.
+func (n *Node) UpdateRoles(_ context.Context, roles node.RolesMask) error {
+	if n.RegistrationWorker == nil || n.RegistrationWorker.WillNeverRegister() {
+		return control.ErrNotImplemented
+	}
+
+	provided := n.RegistrationWorker.ProvidedRoles()
+	if roles&^provided != 0 {
+		return errors.WithContext(control.ErrUnsupportedRoleChange, "adding roles is not supported")
+	}
+	removed := provided &^ roles
+	if removed&^removableRoles != 0 {
+		return errors.WithContext(control.ErrUnsupportedRoleChange,
+			fmt.Sprintf("only the following roles can be removed: %s", removableRoles),
+		)
+	}
+	if removed.IsEmptyRole() {
+		return nil
+	}
+
+	n.roleTransitionLock.Lock()
+	defer n.roleTransitionLock.Unlock()
+
+	if n.roleTransition != nil && n.roleTransition.State != control.RoleTransitionStateCompleted {
+		return errors.WithContext(control.ErrUnsupportedRoleChange, "role downgrade already in progress")
+	}
+
+	// Persist the removed roles and omit them from subsequent registrations. The registration
+	// only affects future committee elections, so this is safe to do before the workers stop.
+	registeredCh, err := n.RegistrationWorker.RemoveRoles(removed)
+	if err != nil {
+		return err
+	}
+
+	n.logger.Info("downgrading roles",
+		"roles", roles,
+		"removed_roles", removed,
+	)
+
+	n.roleTransition = &control.RoleTransitionStatus{
+		State:        control.RoleTransitionStateStoppingWorkers,
+		Roles:        roles,
+		RemovedRoles: removed,
+	}
+
+	// Stop participating in executor committees once the current round completes.
+	var retiredCh <-chan struct{}
+	if removed&node.RoleComputeWorker != 0 {
+		retiredCh = n.ExecutorWorker.Retire()
+	}
+
+	go n.waitRoleDowngrade(retiredCh, registeredCh)
+
+	return nil
+}
+
+func (n *Node) waitRoleDowngrade(retiredCh, registeredCh <-chan struct{}) {
+	if retiredCh != nil {
+		select {
+		case <-retiredCh:
+		case <-n.RegistrationWorker.Quit():
+			return
+		}
+	}
+	n.setRoleTransitionState(control.RoleTransitionStateUpdatingRegistration)
+
+	select {
+	case <-registeredCh:
+	case <-n.RegistrationWorker.Quit():
+		return
+	}
+	n.setRoleTransitionState(control.RoleTransitionStateCompleted)
+
+	n.logger.Info("roles downgraded")
+}
+
+func (n *Node) setRoleTransitionState(state control.RoleTransitionState) {
+	n.roleTransitionLock.Lock()
+	defer n.roleTransitionLock.Unlock()
+
+	n.roleTransition.State = state
+}
+
+func (n *Node) getRoleTransitionStatus() *control.RoleTransitionStatus {
+	n.roleTransitionLock.Lock()
+	defer n.roleTransitionLock.Unlock()
+
+	if n.roleTransition == nil {
+		return nil
+	}
+	status := *n.roleTransition
+	return &status
+}
+
 func (n *Node) getIdentityStatus() control.IdentityStatus {
-	return control.IdentityStatus{
+	status := control.IdentityStatus{
 		Node:      n.Identity.NodeSigner.Public(),
 		Consensus: n.Identity.ConsensusSigner.Public(),
-		TLS:       n.Identity.TLSSigner.Public(),
+		TLS:       n.Identity.GetTLSSigner().Public(),
+	}
+	if signer := n.Identity.GetNextTLSSigner(); signer != nil {
+		pk := signer.Public()
+		status.NextTLS = &pk
 	}
+	return status
 }
 
 func (n *Node) getConsensusStatus(ctx context.Context) (*consensus.Status, error) {
 	return n.Consensus.Core().GetStatus(ctx)
 }
 
+func (n *Node) getValidatorEvidence(ctx context.Context, cs *consensus.Status) []*consensus.EvidenceRecord {
+	if cs == nil || cs.Status != consensus.StatusStateReady {
+		return nil
+	}
+
+	records, err := n.Consensus.Core().GetEvidence(ctx, &consensus.GetEvidenceRequest{
+		Height: consensus.HeightLatest,
+	})
+	if err != nil {
+		n.logger.Warn("failed to fetch evidence records",
+			"err", err,
+		)
+		return nil
+	}
+
+	// Match records by consensus address as well, as the node can only be resolved while it is
+	// registered.
+	nodeID := n.Identity.NodeSigner.Public()
+	consensusPk := n.Identity.ConsensusSigner.Public()
+	consensusAddr := []byte(cmtCrypto.PublicKeyToCometBFT(&consensusPk).Address())
+
+	var evidence []*consensus.EvidenceRecord
+	for _, record := range records {
+		switch {
+		case record.NodeID != nil && record.NodeID.Equal(nodeID):
+		case bytes.Equal(record.ValidatorAddress, consensusAddr):
+		default:
+			continue
+		}
+		evidence = append(evidence, record)
+	}
+	return evidence
+}
+
 func (n *Node) getLightClientStatus() (*consensus.LightClientStatus, error) {
 	return n.LightService.GetStatus()
 }
@@ -200,6 +516,13 @@ func (n *Node) getRegistrationStatus(ctx context.Context) (*control.Registration
 	return n.RegistrationWorker.GetRegistrationStatus(ctx)
 }
 
+func (n *Node) getBeaconStatus() *control.BeaconStatus {
+	if n.BeaconWorker == nil {
+		return nil
+	}
+	return n.BeaconWorker.GetStatus()
+}
+
 func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]control.RuntimeStatus, error) {
 	runtimes := make(map[common.Namespace]control.RuntimeStatus)
 
@@ -301,6 +624,8 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 			if err != nil {
 				logger.Error("failed to fetch common committee worker status", "err", err)
 			}
+			status.Paused = rtNode.IsPaused()
+			status.Replicas = rtNode.GetHostedRuntimeReplicas()
 		}
 
 		// Fetch executor worker status.
@@ -319,11 +644,21 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 			}
 		}
 
+		// Report state sync progress for read-only runtimes.
+		if config.GlobalConfig.Runtime.IsReadOnly(rt.ID()) && status.Storage != nil {
+			status.ReadOnly = control.NewReadOnlyRuntimeStatus(status.LatestRound, status.Storage.LastFinalizedRound)
+		}
+
 		// Fetch history indexer status.
 		if indexer, ok := n.RuntimeRegistry.Indexer(rt.ID()); ok {
 			status.Indexer = indexer.Status()
 		}
 
+		// Fetch transaction tag index status.
+		if n.ClientWorker.Enabled() {
+			status.TagIndex = n.ClientWorker.TagIndexStatus(rt.ID())
+		}
+
 		// Fetch provisioner type.
 		status.Provisioner = n.Provisioner.Name()
 
@@ -338,12 +673,26 @@ func (n *Node) getRuntimeStatus(ctx context.Context) (map[common.Namespace]contr
 			})
 		}
 
+		// Fetch the status of bundles pending download.
+		status.PendingBundles = n.RuntimeRegistry.GetBundleManager().DownloadStatus(rt.ID())
+
 		// Store the runtime status.
 		runtimes[rt.ID()] = status
 	}
 	return runtimes, nil
 }
 
+func (n *Node) getTCBStatus() *pcs.PlatformTCBStatus {
+	if n.Provisioner == nil {
+		return nil
+	}
+	status := n.Provisioner.TCBStatus()
+	if status == nil || status.IsUpToDate() {
+		return nil
+	}
+	return status
+}
+
 func (n *Node) getKeymanagerStatus() (*keymanagerWorker.Status, error) {
 	if n.KeymanagerWorker == nil || !n.KeymanagerWorker.Enabled() {
 		return nil, nil
@@ -358,3 +707,64 @@ func (n *Node) getPendingUpgrades() ([]*upgrade.PendingUpgrade, error) {
 func (n *Node) getP2PStatus() *p2p.Status {
 	return n.P2P.GetStatus()
 }
+
+func (n *Node) getSentryStatus(ctx context.Context) (*sentryAPI.Status, error) {
+	if n.SentryWorker == nil || !n.SentryWorker.Enabled() {
+		return nil, nil
+	}
+	return n.Sentry.GetStatus(ctx)
+}
+
+func (n *Node) getSentriesStatus(ctx context.Context) []control.SentryStatus {
+	if n.CommonWorker == nil {
+		return nil
+	}
+	addrs := n.CommonWorker.GetConfig().SentryAddresses
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	statuses := make([]control.SentryStatus, 0, len(addrs))
+	for _, addr := range addrs {
+		status := control.SentryStatus{
+			Address: addr,
+		}
+		ss, err := n.getSentryNodeStatus(ctx, addr)
+		switch err {
+		case nil:
+			status.Status = ss
+		default:
+			status.Error = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (n *Node) getSentryNodeStatus(ctx context.Context, addr node.TLSAddress) (*sentryAPI.Status, error) {
+	client, err := sentryClient.New(addr, n.Identity)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, sentryStatusTimeout)
+	defer cancel()
+
+	return client.GetStatus(ctx)
+}
+
+// ListCrashDumps implements control.NodeController.
+func (n *Node) ListCrashDumps(context.Context) ([]*crashdump.Info, error) {
+	return crashdump.List()
+}
+
+// GetCrashDump implements control.NodeController.
+func (n *Node) GetCrashDump(_ context.Context, name string) (*crashdump.Dump, error) {
+	return crashdump.Get(name)
+}
+
+// GetEventJournal implements control.NodeController.
+func (n *Node) GetEventJournal(_ context.Context, request *control.GetEventJournalRequest) ([]*journal.Event, error) {
+	return journal.Events(request.FromIndex, request.Limit)
+}
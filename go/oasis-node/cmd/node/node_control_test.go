@@ -0,0 +1,78 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmtCrypto "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/crypto"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+type testConsensusService struct {
+	consensus.Service
+
+	records []*consensus.EvidenceRecord
+}
+
+func (s *testConsensusService) Core() consensus.Backend {
+	return &testConsensusBackend{records: s.records}
+}
+
+type testConsensusBackend struct {
+	consensus.Backend
+
+	records []*consensus.EvidenceRecord
+}
+
+func (b *testConsensusBackend) GetEvidence(context.Context, *consensus.GetEvidenceRequest) ([]*consensus.EvidenceRecord, error) {
+	return b.records, nil
+}
+
+func TestGetValidatorEvidence(t *testing.T) {
+	require := require.New(t)
+
+	nodeSigner := memorySigner.NewTestSigner("node control test: node signer")
+	consensusSigner := memorySigner.NewTestSigner("node control test: consensus signer")
+	otherSigner := memorySigner.NewTestSigner("node control test: other signer")
+
+	nodeID := nodeSigner.Public()
+	otherID := otherSigner.Public()
+	consensusPk := consensusSigner.Public()
+	consensusAddr := []byte(cmtCrypto.PublicKeyToCometBFT(&consensusPk).Address())
+	otherAddr := []byte(cmtCrypto.PublicKeyToCometBFT(&otherID).Address())
+
+	records := []*consensus.EvidenceRecord{
+		// Evidence against another validator.
+		{Height: 10, Reason: staking.SlashConsensusEquivocation, ValidatorAddress: otherAddr, NodeID: &otherID},
+		// Evidence against the local validator.
+		{Height: 11, Reason: staking.SlashConsensusEquivocation, ValidatorAddress: consensusAddr, NodeID: &nodeID},
+		// Evidence against the local validator which could not be resolved to a node.
+		{Height: 12, Reason: staking.SlashConsensusLightClientAttack, ValidatorAddress: consensusAddr},
+	}
+
+	n := &Node{
+		Consensus: &testConsensusService{records: records},
+		Identity: &identity.Identity{
+			NodeSigner:      nodeSigner,
+			ConsensusSigner: consensusSigner,
+		},
+		logger: logging.GetLogger("node/test"),
+	}
+	ctx := context.Background()
+
+	evidence := n.getValidatorEvidence(ctx, &consensus.Status{Status: consensus.StatusStateReady})
+	require.Equal(records[1:], evidence, "only the local validator's evidence should be reported")
+
+	evidence = n.getValidatorEvidence(ctx, &consensus.Status{Status: consensus.StatusStateSyncing})
+	require.Empty(evidence, "evidence should not be reported before consensus is ready")
+
+	n.Consensus = &testConsensusService{records: records[:1]}
+	evidence = n.getValidatorEvidence(ctx, &consensus.Status{Status: consensus.StatusStateReady})
+	require.Empty(evidence, "evidence against other validators should not be reported")
+}
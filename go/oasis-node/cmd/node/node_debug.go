@@ -2,10 +2,17 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/beacon/tests"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	storageWorkerAPI "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
 // Assert that the node implements DebugController interface.
@@ -16,6 +23,31 @@ func (n *Node) SetEpoch(ctx context.Context, epoch beacon.EpochTime) error {
 	return tests.SetEpoch(ctx, epoch, n.Consensus)
 }
 
+// SetEpochSchedule implements control.DebugController.
+func (n *Node) SetEpochSchedule(ctx context.Context, schedule *beacon.EpochSchedule) error {
+	return tests.SetEpochSchedule(ctx, schedule, n.Consensus)
+}
+
+// SetBlockTime implements control.DebugController.
+func (n *Node) SetBlockTime(ctx context.Context, base time.Time, step time.Duration) error {
+	params, err := n.Consensus.Core().GetParameters(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("failed to query consensus parameters: %w", err)
+	}
+	if !params.Parameters.DebugBlockTimeControl {
+		return fmt.Errorf("debug block time control is disabled via consensus")
+	}
+
+	tx := consensus.NewSetBlockTimeTx(0, nil, &consensus.BlockTimeSchedule{
+		Base: base,
+		Step: step,
+	})
+	if err = consensus.SignAndSubmitTx(ctx, n.Consensus, tests.TestSigner, tx); err != nil {
+		return fmt.Errorf("set block time failed: %w", err)
+	}
+	return nil
+}
+
 // WaitNodesRegistered implements control.DebugController.
 func (n *Node) WaitNodesRegistered(ctx context.Context, count int) error {
 	registry := n.Consensus.Registry()
@@ -57,3 +89,55 @@ Loop:
 
 	return nil
 }
+
+// DryRunUpgrade implements control.DebugController.
+func (n *Node) DryRunUpgrade(ctx context.Context, descriptor *upgrade.Descriptor) (*upgrade.DryRunResult, error) {
+	return n.Upgrader.DryRun(ctx, descriptor)
+}
+
+// RepairStorage implements control.DebugController.
+func (n *Node) RepairStorage(ctx context.Context, request *storageWorkerAPI.RepairRequest) (*storageWorkerAPI.RepairResult, error) {
+	storageNode := n.StorageWorker.GetRuntime(request.RuntimeID)
+	if storageNode == nil {
+		return nil, storageWorkerAPI.ErrRuntimeNotFound
+	}
+	return storageNode.RepairRound(ctx, request.Round)
+}
+
+// ExportRuntimeState implements control.DebugController.
+func (n *Node) ExportRuntimeState(ctx context.Context, request *storageWorkerAPI.ExportStateRequest) (*storageWorkerAPI.ExportStateResult, error) {
+	storageNode := n.StorageWorker.GetRuntime(request.RuntimeID)
+	if storageNode == nil {
+		return nil, storageWorkerAPI.ErrRuntimeNotFound
+	}
+	return storageNode.ExportState(ctx, request.Round, request.Path)
+}
+
+// GetRuntimeStateStats implements control.DebugController.
+func (n *Node) GetRuntimeStateStats(ctx context.Context, request *storageWorkerAPI.StateStatsRequest) (*mkvs.TreeStats, error) {
+	storageNode := n.StorageWorker.GetRuntime(request.RuntimeID)
+	if storageNode == nil {
+		return nil, storageWorkerAPI.ErrRuntimeNotFound
+	}
+	return storageNode.StateStats(ctx, request.Round, request.MaxDepth)
+}
+
+// SetRegistrationFault implements control.DebugController.
+func (n *Node) SetRegistrationFault(_ context.Context, stopped bool) error {
+	return fault.SetRegistrationStopped(stopped)
+}
+
+// SetExecutorCommitDelayFault implements control.DebugController.
+func (n *Node) SetExecutorCommitDelayFault(_ context.Context, delay time.Duration) error {
+	return fault.SetExecutorCommitDelay(delay)
+}
+
+// SetCommitteeMessageDropFault implements control.DebugController.
+func (n *Node) SetCommitteeMessageDropFault(_ context.Context, percent uint8) error {
+	return fault.SetCommitteeMessageDropPercent(percent)
+}
+
+// SetCheckpointServingFault implements control.DebugController.
+func (n *Node) SetCheckpointServingFault(_ context.Context, paused bool) error {
+	return fault.SetCheckpointServingPaused(paused)
+}
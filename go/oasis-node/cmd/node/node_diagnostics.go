@@ -0,0 +1,114 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/diagnostics"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
+)
+
+// diagnosticsVersion is the version information included in diagnostics bundles.
+type diagnosticsVersion struct {
+	SoftwareVersion string                   `json:"software_version"`
+	GitBranch       string                   `json:"git_branch,omitempty"`
+	Toolchain       version.Version          `json:"toolchain"`
+	OS              string                   `json:"os"`
+	Arch            string                   `json:"arch"`
+	Protocols       version.ProtocolVersions `json:"protocols"`
+}
+
+// CreateDiagnosticsBundle implements control.NodeController.
+func (n *Node) CreateDiagnosticsBundle(ctx context.Context) (*diagnostics.Info, error) {
+	entries := []diagnostics.Entry{
+		diagnosticsJSON("status.json", func() (any, error) {
+			return n.GetStatus(ctx)
+		}),
+		diagnosticsJSON("runtimes.json", func() (any, error) {
+			return n.getRuntimeStatus(ctx)
+		}),
+		diagnosticsJSON("consensus_parameters.json", func() (any, error) {
+			return n.Consensus.Core().GetParameters(ctx, consensus.HeightLatest)
+		}),
+		diagnosticsJSON("journal.json", func() (any, error) {
+			return journal.Events(0, 0)
+		}),
+		diagnosticsJSON("version.json", func() (any, error) {
+			return &diagnosticsVersion{
+				SoftwareVersion: version.SoftwareVersion,
+				GitBranch:       version.GitBranch,
+				Toolchain:       version.Toolchain,
+				OS:              runtime.GOOS,
+				Arch:            runtime.GOARCH,
+				Protocols:       version.Versions,
+			}, nil
+		}),
+		diagnosticsLogs(),
+		diagnosticsMetrics(),
+	}
+
+	info, err := diagnostics.Create(filepath.Join(n.dataDir, diagnostics.DirName), diagnostics.DefaultConfig(), entries)
+	if err != nil {
+		return nil, err
+	}
+
+	n.logger.Info("diagnostics bundle created",
+		"path", info.Path,
+		"size", info.Size,
+		"checksum", info.Checksum,
+	)
+
+	return info, nil
+}
+
+// GetDiagnosticsBundle implements control.NodeController.
+func (n *Node) GetDiagnosticsBundle(_ context.Context, name string) ([]byte, error) {
+	return diagnostics.Get(filepath.Join(n.dataDir, diagnostics.DirName), name)
+}
+
+func diagnosticsJSON(name string, fn func() (any, error)) diagnostics.Entry {
+	v, err := fn()
+	if err != nil {
+		return diagnostics.Entry{Name: name, Err: err}
+	}
+	data, err := cmdCommon.PrettyJSONMarshal(v)
+	return diagnostics.Entry{Name: name, Data: data, Err: err}
+}
+
+func diagnosticsLogs() diagnostics.Entry {
+	const name = "logs.txt"
+
+	if !crashdump.Enabled() {
+		return diagnostics.Entry{Name: name, Err: fmt.Errorf("recent logs are only recorded when crash dumps are enabled")}
+	}
+	lines := crashdump.RecentLogs()
+	return diagnostics.Entry{Name: name, Data: []byte(strings.Join(lines, "\n") + "\n")}
+}
+
+func diagnosticsMetrics() diagnostics.Entry {
+	const name = "metrics.txt"
+
+	// Gather from the same registry that is exposed by the metrics endpoint.
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return diagnostics.Entry{Name: name, Err: err}
+	}
+	var buf bytes.Buffer
+	for _, mf := range mfs {
+		if _, err = expfmt.MetricFamilyToText(&buf, mf); err != nil {
+			return diagnostics.Entry{Name: name, Err: err}
+		}
+	}
+	return diagnostics.Entry{Name: name, Data: buf.Bytes()}
+}
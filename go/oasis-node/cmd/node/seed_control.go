@@ -3,10 +3,19 @@ package node
 import (
 	"context"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/diagnostics"
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	upgrade "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	storageWorker "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 )
 
 // Assert that the seed node implements NodeController interface.
@@ -28,6 +37,11 @@ func (n *SeedNode) IsReady(context.Context) (bool, error) {
 	return false, control.ErrNotImplemented
 }
 
+// IsLive implements control.NodeController.
+func (n *SeedNode) IsLive(context.Context) (bool, error) {
+	return heartbeat.DefaultMonitor().IsLive(), nil
+}
+
 // WaitSync implements control.NodeController.
 func (n *SeedNode) WaitSync(context.Context) error {
 	return control.ErrNotImplemented
@@ -48,6 +62,16 @@ func (n *SeedNode) CancelUpgrade(context.Context, *upgrade.Descriptor) error {
 	return control.ErrNotImplemented
 }
 
+// ListPendingUpgrades implements control.NodeController.
+func (n *SeedNode) ListPendingUpgrades(context.Context) ([]*upgrade.PendingUpgrade, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// CancelLocalUpgrade implements control.NodeController.
+func (n *SeedNode) CancelLocalUpgrade(context.Context, upgrade.HandlerName) error {
+	return control.ErrNotImplemented
+}
+
 // GetStatus implements control.NodeController.
 func (n *SeedNode) GetStatus(context.Context) (*control.Status, error) {
 	tmAddresses, err := n.cometbftSeed.GetAddresses()
@@ -71,7 +95,7 @@ func (n *SeedNode) GetStatus(context.Context) (*control.Status, error) {
 	identity := control.IdentityStatus{
 		Node:      n.identity.NodeSigner.Public(),
 		Consensus: n.identity.ConsensusSigner.Public(),
-		TLS:       n.identity.TLSSigner.Public(),
+		TLS:       n.identity.GetTLSSigner().Public(),
 	}
 
 	return &control.Status{
@@ -86,3 +110,88 @@ func (n *SeedNode) GetStatus(context.Context) (*control.Status, error) {
 func (n *SeedNode) AddBundle(context.Context, string) error {
 	return control.ErrNotImplemented
 }
+
+// SetLogLevel implements control.NodeController.
+func (n *SeedNode) SetLogLevel(_ context.Context, module string, level string) error {
+	return setLogLevel(module, level)
+}
+
+// GetLogLevels implements control.NodeController.
+func (n *SeedNode) GetLogLevels(context.Context) (map[string]string, error) {
+	return getLogLevels(), nil
+}
+
+// SetTxPoolLanes implements control.NodeController.
+func (n *SeedNode) SetTxPoolLanes(context.Context, *control.SetTxPoolLanesRequest) error {
+	return control.ErrNotImplemented
+}
+
+// PauseRuntime implements control.NodeController.
+func (n *SeedNode) PauseRuntime(context.Context, common.Namespace) error {
+	return control.ErrRuntimeNotHosted
+}
+
+// ResumeRuntime implements control.NodeController.
+func (n *SeedNode) ResumeRuntime(context.Context, common.Namespace) error {
+	return control.ErrRuntimeNotHosted
+}
+
+// SetGrpcAccessAllowlist implements control.NodeController.
+func (n *SeedNode) SetGrpcAccessAllowlist(context.Context, []signature.PublicKey) error {
+	return control.ErrNotImplemented
+}
+
+// AddIndexerToken implements control.NodeController.
+func (n *SeedNode) AddIndexerToken(context.Context, *storageWorker.IndexerToken) error {
+	return control.ErrNotImplemented
+}
+
+// RevokeIndexerToken implements control.NodeController.
+func (n *SeedNode) RevokeIndexerToken(context.Context, string) error {
+	return control.ErrNotImplemented
+}
+
+// ListIndexerTokens implements control.NodeController.
+func (n *SeedNode) ListIndexerTokens(context.Context) ([]*storageWorker.IndexerTokenInfo, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// GetWorkerPoolLimits implements control.NodeController.
+func (n *SeedNode) GetWorkerPoolLimits(context.Context) (*workerpool.Limits, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// SetWorkerPoolLimits implements control.NodeController.
+func (n *SeedNode) SetWorkerPoolLimits(context.Context, *workerpool.Limits) error {
+	return control.ErrNotImplemented
+}
+
+// UpdateRoles implements control.NodeController.
+func (n *SeedNode) UpdateRoles(context.Context, node.RolesMask) error {
+	return control.ErrNotImplemented
+}
+
+// ListCrashDumps implements control.NodeController.
+func (n *SeedNode) ListCrashDumps(context.Context) ([]*crashdump.Info, error) {
+	return crashdump.List()
+}
+
+// GetCrashDump implements control.NodeController.
+func (n *SeedNode) GetCrashDump(_ context.Context, name string) (*crashdump.Dump, error) {
+	return crashdump.Get(name)
+}
+
+// GetEventJournal implements control.NodeController.
+func (n *SeedNode) GetEventJournal(_ context.Context, request *control.GetEventJournalRequest) ([]*journal.Event, error) {
+	return journal.Events(request.FromIndex, request.Limit)
+}
+
+// CreateDiagnosticsBundle implements control.NodeController.
+func (n *SeedNode) CreateDiagnosticsBundle(context.Context) (*diagnostics.Info, error) {
+	return nil, control.ErrNotImplemented
+}
+
+// GetDiagnosticsBundle implements control.NodeController.
+func (n *SeedNode) GetDiagnosticsBundle(context.Context, string) ([]byte, error) {
+	return nil, control.ErrNotImplemented
+}
@@ -192,6 +192,12 @@ func doInit(*cobra.Command, []string) { // nolint: gocyclo
 		}
 		n.P2P.Addresses = append(n.P2P.Addresses, addr)
 	}
+	if len(n.P2P.Addresses) > 1 {
+		// Addresses are prioritized in the order in which they are given.
+		for i := range n.P2P.Addresses {
+			n.P2P.AddressPriorities = append(n.P2P.AddressPriorities, uint8(i))
+		}
+	}
 	if len(n.P2P.Addresses) == 0 {
 		logger.Error("all nodes require at least 1 P2P address")
 		os.Exit(1)
@@ -209,8 +215,11 @@ func doInit(*cobra.Command, []string) { // nolint: gocyclo
 			os.Exit(1)
 		}
 
-		for _, v := range consensusAddrs {
-			var consensusAddr node.ConsensusAddress
+		for i, v := range consensusAddrs {
+			consensusAddr := node.ConsensusAddress{
+				// Addresses are prioritized in the order in which they are given.
+				Priority: uint8(i),
+			}
 			if consensusErr := consensusAddr.UnmarshalText([]byte(v)); consensusErr != nil {
 				if addrErr := consensusAddr.Address.UnmarshalText([]byte(v)); addrErr != nil {
 					logger.Error("failed to parse node's consensus address",
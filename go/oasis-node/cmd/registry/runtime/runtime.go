@@ -29,6 +29,10 @@ const (
 
 	// CfgIncludeSuspended is the flag to include suspended runtimes.
 	CfgIncludeSuspended = "include_suspended"
+
+	// CfgValidate is the flag to validate the runtime descriptor against the registry state of
+	// a node before generating the transaction.
+	CfgValidate = "runtime.validate"
 )
 
 var (
@@ -71,7 +75,7 @@ func doConnect(cmd *cobra.Command) (*grpc.ClientConn, registry.Backend) {
 	return conn, client
 }
 
-func doGenRegister(*cobra.Command, []string) {
+func doGenRegister(cmd *cobra.Command, _ []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
 	}
@@ -108,6 +112,18 @@ func doGenRegister(*cobra.Command, []string) {
 		os.Exit(1)
 	}
 
+	if viper.GetBool(CfgValidate) {
+		conn, client := doConnect(cmd)
+		defer conn.Close()
+
+		if err = client.ValidateRuntimeUpdate(context.Background(), &rt); err != nil {
+			logger.Error("runtime descriptor would be rejected by the registry",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
 	nonce, fee := cmdConsensus.GetTxNonceAndFee()
 	tx := registry.NewRegisterRuntimeTx(nonce, fee, &rt)
 
@@ -176,12 +192,14 @@ func Register(parentCmd *cobra.Command) {
 	listCmd.Flags().AddFlagSet(runtimeListFlags)
 
 	registerCmd.Flags().AddFlagSet(registerFlags)
+	registerCmd.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 
 	parentCmd.AddCommand(runtimeCmd)
 }
 
 func init() {
 	registerFlags.String(CfgRuntimeDescriptor, "", "Path to the runtime descriptor")
+	registerFlags.Bool(CfgValidate, false, "validate the runtime descriptor against the registry state of the node at the given address")
 	_ = viper.BindPFlags(registerFlags)
 	registerFlags.AddFlagSet(cmdSigner.Flags)
 	registerFlags.AddFlagSet(cmdSigner.CLIFlags)
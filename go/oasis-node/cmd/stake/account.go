@@ -10,7 +10,6 @@ import (
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
-	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	cmdConsensus "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
@@ -182,10 +181,7 @@ func doAccountInfo(cmd *cobra.Command, _ []string) {
 	incomingDelegations := getDelegationsTo(ctx, addr, height, client)
 	outgoingDebondingDelegationInfos := getDebondingDelegationInfosFor(ctx, addr, height, client)
 	incomingDebondingDelegations := getDebondingDelegationsTo(ctx, addr, height, client)
-	symbol := getTokenSymbol(ctx, client)
-	exp := getTokenValueExponent(ctx, client)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, exp)
+	ctx = withTokenInfo(ctx, getTokenInfo(ctx, client))
 
 	fmt.Printf("Account State for Height: %d\n", height)
 	fmt.Println("Balance:")
@@ -540,6 +536,7 @@ func registerAccountCmd() {
 
 	accountInfoCmd.Flags().AddFlagSet(commonAccountFlags)
 	accountInfoCmd.Flags().AddFlagSet(accountInfoFlags)
+	accountInfoCmd.Flags().AddFlagSet(baseUnitsFlags)
 	accountNonceCmd.Flags().AddFlagSet(commonAccountFlags)
 	accountValidateAddressCmd.Flags().AddFlagSet(commonAccountFlags)
 	accountValidateAddressCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
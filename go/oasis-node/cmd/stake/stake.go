@@ -23,8 +23,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
 )
 
-// CfgPublicKey configures the public key.
-const CfgPublicKey = "public_key"
+const (
+	// CfgPublicKey configures the public key.
+	CfgPublicKey = "public_key"
+
+	// CfgBaseUnits configures printing amounts in base units.
+	CfgBaseUnits = "base-units"
+)
 
 var (
 	stakeCmd = &cobra.Command{
@@ -56,6 +61,7 @@ var (
 
 	logger = logging.GetLogger("cmd/stake")
 
+	baseUnitsFlags      = flag.NewFlagSet("", flag.ContinueOnError)
 	infoFlags           = flag.NewFlagSet("", flag.ContinueOnError)
 	listFlags           = flag.NewFlagSet("", flag.ContinueOnError)
 	pubkey2AddressFlags = flag.NewFlagSet("", flag.ContinueOnError)
@@ -74,26 +80,27 @@ func doConnect(cmd *cobra.Command) (*grpc.ClientConn, api.Backend) {
 	return conn, client
 }
 
-func getTokenSymbol(ctx context.Context, client api.Backend) string {
-	symbol, err := client.TokenSymbol(ctx, consensus.HeightLatest)
+func getTokenInfo(ctx context.Context, client api.Backend) *api.TokenInfo {
+	info, err := client.GetTokenInfo(ctx, consensus.HeightLatest)
 	if err != nil {
-		logger.Error("failed to query token's symbol",
+		logger.Error("failed to query token's metadata",
 			"err", err,
 		)
 		os.Exit(1)
 	}
-	return symbol
+	return info
 }
 
-func getTokenValueExponent(ctx context.Context, client api.Backend) uint8 {
-	exp, err := client.TokenValueExponent(ctx, consensus.HeightLatest)
-	if err != nil {
-		logger.Error("failed to query token's value exponent",
-			"err", err,
-		)
-		os.Exit(1)
+// withTokenInfo returns a context configured for pretty-printing amounts
+// according to the given token's metadata, unless amounts should be printed
+// in base units.
+func withTokenInfo(ctx context.Context, info *api.TokenInfo) context.Context {
+	if viper.GetBool(CfgBaseUnits) {
+		return ctx
 	}
-	return exp
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, info.Symbol)
+	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, info.ValueExponent)
+	return ctx
 }
 
 func getAccount(ctx context.Context, addr api.Address, height int64, client api.Backend) *api.Account {
@@ -187,12 +194,10 @@ func doInfo(cmd *cobra.Command, _ []string) {
 	height := consensus.HeightLatest
 
 	ctx := context.Background()
-	symbol := getTokenSymbol(ctx, client)
-	fmt.Printf("Token's ticker symbol: %s\n", symbol)
-	exp := getTokenValueExponent(ctx, client)
-	fmt.Printf("Token's value base-10 exponent: %d\n", exp)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenSymbol, symbol)
-	ctx = context.WithValue(ctx, prettyprint.ContextKeyTokenValueExponent, exp)
+	tokenInfo := getTokenInfo(ctx, client)
+	fmt.Printf("Token's ticker symbol: %s\n", tokenInfo.Symbol)
+	fmt.Printf("Token's value base-10 exponent: %d\n", tokenInfo.ValueExponent)
+	ctx = withTokenInfo(ctx, tokenInfo)
 
 	totalSupply, err := client.TotalSupply(ctx, height)
 	if err != nil {
@@ -346,6 +351,7 @@ func Register(parentCmd *cobra.Command) {
 	}
 
 	infoCmd.Flags().AddFlagSet(infoFlags)
+	infoCmd.Flags().AddFlagSet(baseUnitsFlags)
 	listCmd.Flags().AddFlagSet(listFlags)
 	pubkey2AddressCmd.Flags().AddFlagSet(pubkey2AddressFlags)
 
@@ -353,6 +359,9 @@ func Register(parentCmd *cobra.Command) {
 }
 
 func init() {
+	baseUnitsFlags.Bool(CfgBaseUnits, false, "print amounts in base units")
+	_ = viper.BindPFlags(baseUnitsFlags)
+
 	infoFlags.AddFlagSet(cmdGrpc.ClientFlags)
 
 	listFlags.AddFlagSet(cmdFlags.VerboseFlags)
@@ -43,6 +43,8 @@ const (
 	cfgMetricsInterval  = "metrics.interval"
 	cfgTimeout          = "timeout"
 	cfgScenarioTimeout  = "scenario_timeout"
+	cfgFromSnapshot     = "from-snapshot"
+	cfgToSnapshot       = "to-snapshot"
 )
 
 var (
@@ -374,6 +376,13 @@ func runRoot(cmd *cobra.Command, _ []string) error { // nolint: gocyclo
 		return fmt.Errorf("root: failed to parse scenario parameters: %w", err)
 	}
 
+	// Snapshots can only be created from a single scenario instance.
+	if viper.GetString(cfgToSnapshot) != "" {
+		if numRuns != 1 || len(toRun) != 1 || len(toRunExploded[toRun[0].Name()]) != 1 {
+			return fmt.Errorf("root: %s requires exactly one scenario instance", cfgToSnapshot)
+		}
+	}
+
 	// Allow scenarios to run for a limited amount of time.
 	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration(cfgTimeout))
 	defer cancel()
@@ -502,6 +511,7 @@ func doScenario(ctx context.Context, childEnv *env.Env, sc scenario.Scenario) (e
 	// something on its own.
 	var net *oasis.Network
 	if fixture != nil {
+		fixture.Network.Snapshot = viper.GetString(cfgFromSnapshot)
 		if net, err = fixture.Create(childEnv); err != nil {
 			err = fmt.Errorf("root: failed to instantiate fixture: %w", err)
 			return
@@ -537,9 +547,17 @@ func doScenario(ctx context.Context, childEnv *env.Env, sc scenario.Scenario) (e
 	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration(cfgScenarioTimeout))
 	defer cancel()
 
-	if err = sc.Run(ctx, childEnv); err != nil {
-		err = fmt.Errorf("root: failed to run scenario: %w", err)
-		return
+	switch snapshotPath := viper.GetString(cfgToSnapshot); snapshotPath {
+	case "":
+		if err = sc.Run(ctx, childEnv); err != nil {
+			err = fmt.Errorf("root: failed to run scenario: %w", err)
+			return
+		}
+	default:
+		if err = createSnapshot(ctx, childEnv, sc, snapshotPath); err != nil {
+			err = fmt.Errorf("root: failed to create snapshot: %w", err)
+			return
+		}
 	}
 
 	if pusher != nil {
@@ -553,6 +571,26 @@ func doScenario(ctx context.Context, childEnv *env.Env, sc scenario.Scenario) (e
 	return
 }
 
+// createSnapshot runs the scenario preamble and saves a snapshot of the stopped network.
+func createSnapshot(ctx context.Context, childEnv *env.Env, sc scenario.Scenario, path string) error {
+	p, ok := sc.(scenario.Preambler)
+	if !ok {
+		return fmt.Errorf("scenario %s does not support snapshots", sc.Name())
+	}
+	net := sc.Network()
+	if net == nil {
+		return fmt.Errorf("scenario %s does not use a network", sc.Name())
+	}
+
+	if err := p.Preamble(ctx, childEnv); err != nil {
+		return fmt.Errorf("failed to run scenario preamble: %w", err)
+	}
+	if err := net.StopGracefully(); err != nil {
+		return err
+	}
+	return net.SaveSnapshot(path)
+}
+
 func doCleanup(childEnv *env.Env) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -635,6 +673,8 @@ func init() {
 	rootFlags.Int(cfgParallelJobIndex, 0, "(for CI) index of this parallel job")
 	rootFlags.Duration(cfgTimeout, 24*time.Hour, "the maximum allowable total duration for all scenarios")
 	rootFlags.Duration(cfgScenarioTimeout, 20*time.Minute, "the maximum allowable duration for an individual scenario")
+	rootFlags.String(cfgFromSnapshot, "", "path to a network snapshot used as the starting point of scenarios")
+	rootFlags.String(cfgToSnapshot, "", "run the scenario preamble and save a snapshot of the stopped network to the given path")
 	_ = viper.BindPFlags(rootFlags)
 	rootCmd.Flags().AddFlagSet(rootFlags)
 	rootCmd.Flags().AddFlagSet(env.Flags)
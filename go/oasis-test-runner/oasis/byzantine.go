@@ -63,7 +63,7 @@ func (worker *Byzantine) AddArgs(args *argBuilder) error {
 
 func (worker *Byzantine) ModifyConfig() error {
 	worker.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(worker.consensusPort))
-	worker.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(worker.consensusPort))
+	worker.setAdvertisedAddresses(nodePortP2P)
 
 	worker.Config.Consensus.Debug.P2PAllowDuplicateIP = true
 	worker.Config.Consensus.Debug.P2PAddrBookLenient = true
@@ -11,6 +11,7 @@ import (
 	cmdCommon "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/consensus"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/grpc"
 	cmdSigner "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/signer"
 	cmdRegRt "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/registry/runtime"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
@@ -40,6 +41,8 @@ func (r *RegistryHelpers) GenerateRegisterRuntimeTx(
 	args := []string{
 		"registry", "runtime", "gen_register",
 		"--" + cmdRegRt.CfgRuntimeDescriptor, rtDescPath,
+		"--" + cmdRegRt.CfgValidate,
+		"--" + grpc.CfgAddress, "unix:" + r.cfg.NodeSocketPath,
 		"--" + consensus.CfgTxNonce, strconv.FormatUint(nonce, 10),
 		"--" + consensus.CfgTxFile, txPath,
 		"--" + consensus.CfgTxFeeAmount, strconv.Itoa(0), // TODO: Make fee configurable.
@@ -57,7 +57,7 @@ func (client *Client) AddArgs(args *argBuilder) error {
 
 func (client *Client) ModifyConfig() error {
 	client.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(client.consensusPort))
-	client.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(client.consensusPort))
+	client.setAdvertisedAddresses(nodePortP2P)
 
 	if client.supplementarySanityInterval > 0 {
 		client.Config.Consensus.SupplementarySanity.Enabled = true
@@ -41,6 +41,8 @@ type Compute struct { // nolint: maligned
 	disablePublicRPC        bool
 	checkpointSyncDisabled  bool
 	checkpointCheckInterval time.Duration
+	initialDiffSyncDisabled bool
+	diffServingDisabled     bool
 
 	sentryPubKey  signature.PublicKey
 	consensusPort uint16
@@ -66,6 +68,8 @@ type ComputeCfg struct {
 	DisablePublicRPC        bool
 	CheckpointSyncDisabled  bool
 	CheckpointCheckInterval time.Duration
+	InitialDiffSyncDisabled bool
+	DiffServingDisabled     bool
 }
 
 // UpdateRuntimes updates the worker node runtimes.
@@ -148,7 +152,7 @@ func (worker *Compute) ModifyConfig() error {
 	defer worker.RUnlock()
 
 	worker.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(worker.consensusPort))
-	worker.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(worker.consensusPort))
+	worker.setAdvertisedAddresses(nodePortP2P)
 
 	if worker.supplementarySanityInterval > 0 {
 		worker.Config.Consensus.SupplementarySanity.Enabled = true
@@ -170,6 +174,8 @@ func (worker *Compute) ModifyConfig() error {
 	worker.Config.Storage.Backend = worker.storageBackend
 	worker.Config.Storage.PublicRPCEnabled = !worker.disablePublicRPC
 	worker.Config.Storage.CheckpointSyncDisabled = worker.checkpointSyncDisabled
+	worker.Config.Storage.InitialDiffSyncDisabled = worker.initialDiffSyncDisabled
+	worker.Config.Storage.DiffServingDisabled = worker.diffServingDisabled
 	worker.Config.Storage.Checkpointer.Enabled = true
 	worker.Config.Storage.Checkpointer.CheckInterval = worker.checkpointCheckInterval
 
@@ -236,6 +242,8 @@ func (net *Network) NewCompute(cfg *ComputeCfg) (*Compute, error) {
 		disablePublicRPC:        cfg.DisablePublicRPC,
 		checkpointSyncDisabled:  cfg.CheckpointSyncDisabled,
 		checkpointCheckInterval: cfg.CheckpointCheckInterval,
+		initialDiffSyncDisabled: cfg.InitialDiffSyncDisabled,
+		diffServingDisabled:     cfg.DiffServingDisabled,
 		sentryPubKey:            sentryPubKey,
 		runtimeProvisioner:      cfg.RuntimeProvisioner,
 		consensusPort:           host.getProvisionedPort(nodePortConsensus),
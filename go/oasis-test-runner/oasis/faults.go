@@ -0,0 +1,519 @@
+package oasis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+// proxyPortSuffix is the suffix of the names of ports provisioned for fault proxies.
+const proxyPortSuffix = "-proxy"
+
+// ErrNetworkFaultsDisabled is the error returned when trying to inject network faults into
+// a network that has not been configured with network fault injection enabled.
+var ErrNetworkFaultsDisabled = errors.New("oasis: network fault injection is disabled")
+
+// faultLink is an unordered pair of node names.
+type faultLink struct {
+	a, b string
+}
+
+func newFaultLink(a, b string) faultLink {
+	if a > b {
+		a, b = b, a
+	}
+	return faultLink{a, b}
+}
+
+// networkFaults is the network fault injection state of a test network.
+//
+// Faults are injected by userspace TCP proxies placed in front of the consensus and P2P ports of
+// every node, with nodes advertising the proxy ports instead of the ports they listen on. Since
+// all nodes connect from the loopback interface, the source node of each proxied connection is
+// determined by looking up which node process owns the connecting socket.
+type networkFaults struct {
+	sync.Mutex
+
+	logger *logging.Logger
+
+	partitions map[faultLink]bool
+	dropped    map[string]bool
+	latency    map[string]time.Duration
+
+	proxies map[uint16]*faultProxy
+	conns   map[*proxyConn]struct{}
+
+	// resolveSource returns the name of the node that made the given proxied connection or an
+	// empty string if the connection was not made by a known node.
+	resolveSource func(conn net.Conn) string
+}
+
+func newNetworkFaults(network *Network) *networkFaults {
+	f := &networkFaults{
+		logger:     logging.GetLogger("oasis/faults"),
+		partitions: make(map[faultLink]bool),
+		dropped:    make(map[string]bool),
+		latency:    make(map[string]time.Duration),
+		proxies:    make(map[uint16]*faultProxy),
+		conns:      make(map[*proxyConn]struct{}),
+	}
+	f.resolveSource = func(conn net.Conn) string {
+		return resolveSourceNode(network.nodes, conn)
+	}
+	return f
+}
+
+// isBlocked returns true iff traffic between the given nodes should be dropped.
+func (f *networkFaults) isBlocked(src, dst string) bool {
+	if f.dropped[src] || f.dropped[dst] {
+		return true
+	}
+	if src == "" {
+		// Traffic from unknown sources can only be dropped by the destination.
+		return false
+	}
+	return f.partitions[newFaultLink(src, dst)]
+}
+
+// delay returns the latency that should be added to traffic between the given nodes.
+func (f *networkFaults) delay(src, dst string) time.Duration {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.latency[src] + f.latency[dst]
+}
+
+// update applies the given modification of the fault state and closes all proxied connections
+// that should no longer be allowed.
+func (f *networkFaults) update(fn func()) {
+	f.Lock()
+	fn()
+	var blocked []*proxyConn
+	for pc := range f.conns {
+		if f.isBlocked(pc.src, pc.dst) {
+			blocked = append(blocked, pc)
+		}
+	}
+	f.Unlock()
+
+	for _, pc := range blocked {
+		f.logger.Debug("closing blocked connection",
+			"src", pc.src,
+			"dst", pc.dst,
+		)
+		pc.close()
+	}
+}
+
+// heal removes all injected faults.
+func (f *networkFaults) heal() {
+	f.update(func() {
+		f.partitions = make(map[faultLink]bool)
+		f.dropped = make(map[string]bool)
+		f.latency = make(map[string]time.Duration)
+	})
+}
+
+// startProxy starts a fault proxy forwarding connections from the given proxy port to the given
+// port of the given node, unless one is already running.
+func (f *networkFaults) startProxy(node *Node, proxyPort, port uint16) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.proxies[proxyPort]; ok {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", proxyPort))
+	if err != nil {
+		return fmt.Errorf("oasis/faults: failed to start proxy for node %s: %w", node.Name, err)
+	}
+	p := &faultProxy{
+		faults: f,
+		name:   node.Name,
+		target: fmt.Sprintf("127.0.0.1:%d", port),
+		ln:     ln,
+	}
+	f.proxies[proxyPort] = p
+	go p.serve()
+
+	return nil
+}
+
+// close stops all fault proxies and closes all proxied connections.
+func (f *networkFaults) close() {
+	f.Lock()
+	proxies := f.proxies
+	conns := f.conns
+	f.proxies = make(map[uint16]*faultProxy)
+	f.conns = make(map[*proxyConn]struct{})
+	f.Unlock()
+
+	for _, p := range proxies {
+		_ = p.ln.Close()
+	}
+	for pc := range conns {
+		pc.close()
+	}
+}
+
+// faultProxy is a TCP proxy in front of a port of a single node.
+type faultProxy struct {
+	faults *networkFaults
+
+	name   string
+	target string
+	ln     net.Listener
+}
+
+func (p *faultProxy) serve() {
+	for {
+		conn, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *faultProxy) handle(conn net.Conn) {
+	pc := &proxyConn{
+		src:  p.faults.resolveSource(conn),
+		dst:  p.name,
+		in:   conn,
+		done: make(chan struct{}),
+	}
+
+	p.faults.Lock()
+	if p.faults.isBlocked(pc.src, pc.dst) {
+		p.faults.Unlock()
+		_ = conn.Close()
+		return
+	}
+	p.faults.conns[pc] = struct{}{}
+	p.faults.Unlock()
+
+	defer func() {
+		p.faults.Lock()
+		delete(p.faults.conns, pc)
+		p.faults.Unlock()
+		pc.close()
+	}()
+
+	out, err := net.Dial("tcp", p.target)
+	if err != nil {
+		return
+	}
+	pc.setOut(out)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer pc.close()
+		p.forward(out, conn, pc)
+	}()
+	go func() {
+		defer wg.Done()
+		defer pc.close()
+		p.forward(conn, out, pc)
+	}()
+	wg.Wait()
+}
+
+// forward copies data from src to dst, delaying it by the currently configured latency.
+func (p *faultProxy) forward(dst io.Writer, src io.Reader, pc *proxyConn) {
+	type chunk struct {
+		data []byte
+		at   time.Time
+	}
+	ch := make(chan chunk, 64)
+
+	go func() {
+		defer close(ch)
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := src.Read(buf)
+			if n > 0 {
+				select {
+				case ch <- chunk{buf[:n], time.Now().Add(p.faults.delay(pc.src, pc.dst))}:
+				case <-pc.done:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for c := range ch {
+		if d := time.Until(c.at); d > 0 {
+			select {
+			case <-time.After(d):
+			case <-pc.done:
+				return
+			}
+		}
+		if _, err := dst.Write(c.data); err != nil {
+			return
+		}
+	}
+}
+
+// proxyConn is a proxied connection.
+type proxyConn struct {
+	sync.Mutex
+
+	src, dst string
+
+	in  net.Conn
+	out net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (pc *proxyConn) setOut(out net.Conn) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	select {
+	case <-pc.done:
+		_ = out.Close()
+	default:
+		pc.out = out
+	}
+}
+
+func (pc *proxyConn) close() {
+	pc.closeOnce.Do(func() {
+		pc.Lock()
+		defer pc.Unlock()
+
+		close(pc.done)
+		_ = pc.in.Close()
+		if pc.out != nil {
+			_ = pc.out.Close()
+		}
+	})
+}
+
+// resolveSourceNode returns the name of the node whose process owns the local end of the given
+// loopback connection accepted by a fault proxy, or an empty string if there is no such node.
+func resolveSourceNode(nodes []*Node, conn net.Conn) string {
+	remote, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return ""
+	}
+	local, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return ""
+	}
+	inode, ok := findSocketInode(uint16(remote.Port), uint16(local.Port))
+	if !ok {
+		return ""
+	}
+
+	for _, n := range nodes {
+		n.Lock()
+		cmd := n.cmd
+		n.Unlock()
+		if cmd == nil || cmd.Process == nil {
+			continue
+		}
+		if processOwnsSocket(cmd.Process.Pid, inode) {
+			return n.Name
+		}
+	}
+	return ""
+}
+
+// findSocketInode returns the inode of the TCP socket with the given local and remote ports.
+func findSocketInode(localPort, remotePort uint16) (string, bool) {
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		if inode, ok := findSocketInodeIn(path, localPort, remotePort); ok {
+			return inode, true
+		}
+	}
+	return "", false
+}
+
+func findSocketInodeIn(path string, localPort, remotePort uint16) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	parsePort := func(addr string) (uint16, bool) {
+		idx := strings.LastIndexByte(addr, ':')
+		if idx < 0 {
+			return 0, false
+		}
+		port, err := strconv.ParseUint(addr[idx+1:], 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		return uint16(port), true
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Skip the header.
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if lp, ok := parsePort(fields[1]); !ok || lp != localPort {
+			continue
+		}
+		if rp, ok := parsePort(fields[2]); !ok || rp != remotePort {
+			continue
+		}
+		return fields[9], true
+	}
+	return "", false
+}
+
+// processOwnsSocket returns true iff the process with the given PID has an open file descriptor
+// referring to the socket with the given inode.
+func processOwnsSocket(pid int, inode string) bool {
+	fdDir := filepath.Join("/proc", strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return false
+	}
+
+	socket := "socket:[" + inode + "]"
+	for _, entry := range entries {
+		link, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if link == socket {
+			return true
+		}
+	}
+	return false
+}
+
+// advertisedPort returns the port that other nodes should use to connect to the given port of
+// the node. When network fault injection is enabled, this is the port of the node's fault proxy.
+func (n *Node) advertisedPort(portName string) uint16 {
+	port := n.getProvisionedPort(portName)
+	if !n.net.cfg.NetworkFaults {
+		return port
+	}
+	return n.getProvisionedPort(portName + proxyPortSuffix)
+}
+
+// setAdvertisedAddresses configures the consensus and P2P addresses that the node advertises to
+// other nodes.
+func (n *Node) setAdvertisedAddresses(p2pPortName string) {
+	n.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(n.advertisedPort(nodePortConsensus)))
+	if n.net.cfg.NetworkFaults {
+		n.Config.P2P.Registration.Addresses = []string{fmt.Sprintf("127.0.0.1:%d", n.advertisedPort(p2pPortName))}
+	}
+}
+
+// startFaultProxies starts the fault proxies of all of the node's advertised ports.
+func (n *Node) startFaultProxies() error {
+	if !n.net.cfg.NetworkFaults {
+		return nil
+	}
+	for name, proxyPort := range n.ports {
+		portName, ok := strings.CutSuffix(name, proxyPortSuffix)
+		if !ok {
+			continue
+		}
+		if err := n.net.faults.startProxy(n, proxyPort, n.ports[portName]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Partition drops all traffic between any node in the first set and any node in the second set
+// until the network is healed.
+func (net *Network) Partition(a, b []*Node) error {
+	if !net.cfg.NetworkFaults {
+		return ErrNetworkFaultsDisabled
+	}
+
+	net.logger.Info("partitioning network",
+		"a", nodeNames(a),
+		"b", nodeNames(b),
+	)
+	net.faults.update(func() {
+		for _, na := range a {
+			for _, nb := range b {
+				net.faults.partitions[newFaultLink(na.Name, nb.Name)] = true
+			}
+		}
+	})
+	return nil
+}
+
+// DropTraffic drops all traffic to and from the given node until the network is healed.
+func (net *Network) DropTraffic(node *Node) error {
+	if !net.cfg.NetworkFaults {
+		return ErrNetworkFaultsDisabled
+	}
+
+	net.logger.Info("dropping node traffic",
+		"node", node.Name,
+	)
+	net.faults.update(func() {
+		net.faults.dropped[node.Name] = true
+	})
+	return nil
+}
+
+// SetLatency delays all traffic to and from the given node by the given duration. Zero latency
+// removes the delay.
+func (net *Network) SetLatency(node *Node, d time.Duration) error {
+	if !net.cfg.NetworkFaults {
+		return ErrNetworkFaultsDisabled
+	}
+
+	net.logger.Info("setting node latency",
+		"node", node.Name,
+		"latency", d,
+	)
+	net.faults.update(func() {
+		net.faults.latency[node.Name] = d
+	})
+	return nil
+}
+
+// HealNetwork removes all injected network faults.
+//
+// Faults are also removed automatically when the network is torn down.
+func (net *Network) HealNetwork() {
+	if !net.cfg.NetworkFaults {
+		return
+	}
+
+	net.logger.Info("healing network")
+	net.faults.heal()
+}
+
+func nodeNames(nodes []*Node) []string {
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
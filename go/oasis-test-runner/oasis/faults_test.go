@@ -0,0 +1,152 @@
+package oasis
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+)
+
+func TestNetworkFaults(t *testing.T) {
+	require := require.New(t)
+
+	a := &Node{Name: "a"}
+	b := &Node{Name: "b"}
+	c := &Node{Name: "c"}
+	network := &Network{
+		logger: logging.GetLogger("oasis/test"),
+		cfg:    &NetworkCfg{NetworkFaults: true},
+		nodes:  []*Node{a, b, c},
+	}
+	network.faults = newNetworkFaults(network)
+	defer network.faults.close()
+
+	source := "a"
+	network.faults.resolveSource = func(net.Conn) string {
+		return source
+	}
+
+	// The proxied node is an echo server.
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	proxyPort := uint16(ln.Addr().(*net.TCPAddr).Port)
+	require.NoError(ln.Close(), "Close")
+
+	err = network.faults.startProxy(b, proxyPort, uint16(echo.Addr().(*net.TCPAddr).Port))
+	require.NoError(err, "startProxy")
+
+	dial := func() net.Conn {
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(proxyPort))))
+		require.NoError(err, "Dial")
+		t.Cleanup(func() { _ = conn.Close() })
+		return conn
+	}
+	ping := func(conn net.Conn) (time.Duration, error) {
+		start := time.Now()
+		_ = conn.SetDeadline(start.Add(5 * time.Second))
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return 0, err
+		}
+		var buf [4]byte
+		if _, err := io.ReadFull(conn, buf[:]); err != nil {
+			return 0, err
+		}
+		return time.Since(start), nil
+	}
+
+	conn := dial()
+	_, err = ping(conn)
+	require.NoError(err, "traffic should be forwarded without faults")
+
+	// Latency.
+	err = network.SetLatency(b, 200*time.Millisecond)
+	require.NoError(err, "SetLatency")
+	rtt, err := ping(conn)
+	require.NoError(err, "traffic should be forwarded with latency")
+	require.GreaterOrEqual(rtt, 400*time.Millisecond, "latency should be added in both directions")
+	err = network.SetLatency(b, 0)
+	require.NoError(err, "SetLatency")
+
+	// Partitions not involving the nodes should not affect traffic.
+	err = network.Partition([]*Node{a}, []*Node{c})
+	require.NoError(err, "Partition")
+	_, err = ping(conn)
+	require.NoError(err, "traffic should not be affected by unrelated partitions")
+
+	// Partitions should close existing connections and reject new ones.
+	err = network.Partition([]*Node{a, c}, []*Node{b})
+	require.NoError(err, "Partition")
+	_, err = ping(conn)
+	require.Error(err, "existing connections should be closed by partitions")
+	_, err = ping(dial())
+	require.Error(err, "new connections should be rejected by partitions")
+
+	network.HealNetwork()
+	_, err = ping(dial())
+	require.NoError(err, "traffic should be forwarded after healing")
+
+	// Dropped traffic should also affect connections from unknown sources.
+	source = ""
+	err = network.DropTraffic(b)
+	require.NoError(err, "DropTraffic")
+	_, err = ping(dial())
+	require.Error(err, "traffic of dropped nodes should be dropped")
+
+	network.HealNetwork()
+	_, err = ping(dial())
+	require.NoError(err, "traffic should be forwarded after healing")
+
+	// Fault injection should only be possible when enabled.
+	network.cfg.NetworkFaults = false
+	err = network.Partition([]*Node{a}, []*Node{b})
+	require.ErrorIs(err, ErrNetworkFaultsDisabled)
+	err = network.SetLatency(a, time.Second)
+	require.ErrorIs(err, ErrNetworkFaultsDisabled)
+}
+
+func TestResolveSourceNode(t *testing.T) {
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer ln.Close()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(err, "Dial")
+	defer client.Close()
+	conn, err := ln.Accept()
+	require.NoError(err, "Accept")
+	defer conn.Close()
+
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(err, "FindProcess")
+	nodes := []*Node{
+		{Name: "stopped"},
+		{Name: "self", cmd: &exec.Cmd{Process: self}},
+	}
+	require.Equal("self", resolveSourceNode(nodes, conn))
+	require.Equal("", resolveSourceNode(nodes[:1], conn))
+}
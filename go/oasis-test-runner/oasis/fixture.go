@@ -47,6 +47,14 @@ func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 	if net, err = New(env, &f.Network); err != nil {
 		return nil, err
 	}
+	net.fixture = f
+
+	// Restore the network state from a snapshot, if configured.
+	if f.Network.Snapshot != "" {
+		if err = net.restoreSnapshot(f); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
 
 	// Ensure the creation order here is good enough for proper startup in
 	// net.Start, since that'll just iterate through node objects.
@@ -120,6 +128,12 @@ func (f *NetworkFixture) Create(env *env.Env) (*Network, error) {
 		}
 	}
 
+	if net.snapshot != nil {
+		if err = net.checkSnapshotNodes(); err != nil {
+			return nil, err
+		}
+	}
+
 	return net, nil
 }
 
@@ -404,6 +418,12 @@ type ComputeWorkerFixture struct {
 	CheckpointCheckInterval time.Duration `json:"checkpoint_check_interval,omitempty"`
 	CheckpointSyncEnabled   bool          `json:"checkpoint_sync_enabled,omitempty"`
 
+	// InitialDiffSyncDisabled disables initial storage sync by applying all diffs from genesis so
+	// the node can only sync from checkpoints.
+	InitialDiffSyncDisabled bool `json:"initial_diff_sync_disabled,omitempty"`
+	// DiffServingDisabled disables serving storage diffs to other nodes.
+	DiffServingDisabled bool `json:"diff_serving_disabled,omitempty"`
+
 	// Runtimes contains the indexes of the runtimes to enable.
 	Runtimes []int `json:"runtimes,omitempty"`
 	// RuntimeStatePaths are the paths to runtime state that will be copied to the compute worker node.
@@ -440,11 +460,13 @@ func (f *ComputeWorkerFixture) Create(net *Network) (*Compute, error) {
 		CheckpointCheckInterval: f.CheckpointCheckInterval,
 		// The checkpoint syncing flag is intentionally flipped here.
 		// Syncing should normally be enabled, but normally disabled in tests.
-		CheckpointSyncDisabled: !f.CheckpointSyncEnabled,
-		DisablePublicRPC:       f.DisablePublicRPC,
-		Runtimes:               f.Runtimes,
-		RuntimeConfig:          f.RuntimeConfig,
-		RuntimeStatePaths:      f.RuntimeStatePaths,
+		CheckpointSyncDisabled:  !f.CheckpointSyncEnabled,
+		InitialDiffSyncDisabled: f.InitialDiffSyncDisabled,
+		DiffServingDisabled:     f.DiffServingDisabled,
+		DisablePublicRPC:        f.DisablePublicRPC,
+		Runtimes:                f.Runtimes,
+		RuntimeConfig:           f.RuntimeConfig,
+		RuntimeStatePaths:       f.RuntimeStatePaths,
 	})
 }
 
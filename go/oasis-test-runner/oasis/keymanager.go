@@ -278,7 +278,7 @@ func (km *Keymanager) AddArgs(args *argBuilder) error {
 
 func (km *Keymanager) ModifyConfig() error {
 	km.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(km.consensusPort))
-	km.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(km.consensusPort))
+	km.setAdvertisedAddresses(nodePortP2P)
 
 	if km.supplementarySanityInterval > 0 {
 		km.Config.Consensus.SupplementarySanity.Enabled = true
@@ -68,6 +68,11 @@ type Network struct { // nolint: maligned
 
 	iasProxy *iasProxy
 
+	faults *networkFaults
+
+	fixture  *NetworkFixture
+	snapshot *snapshotManifest
+
 	cfg      *NetworkCfg
 	nextPort uint16
 	ports    map[string]uint16
@@ -169,6 +174,14 @@ type NetworkCfg struct { // nolint: maligned
 	// NodeLogFormat is the log format to use for created nodes.
 	NodeLogFormat string `json:"node_log_format,omitempty"`
 
+	// NetworkFaults enables network fault injection by placing proxies in front of the consensus
+	// and P2P ports of all nodes.
+	NetworkFaults bool `json:"network_faults,omitempty"`
+
+	// Snapshot is an optional path to a network snapshot archive which is used as the starting
+	// point of the network instead of a fresh genesis state.
+	Snapshot string `json:"snapshot,omitempty"`
+
 	// Nodes lists the names of nodes to be created, enabling an N:M mapping between physical node
 	// processes and the features they host. If a feature is specified as attached to a node that
 	// isn't listed here, a new node will be created automatically, so this list can normally be
@@ -717,6 +730,10 @@ func (net *Network) startOasisNode(
 	cmd.Stdout = w
 	cmd.Stderr = w
 
+	if err = node.startFaultProxies(); err != nil {
+		return err
+	}
+
 	// Write config to file.
 	cfgString, err := yaml.Marshal(&cfg)
 	if err != nil {
@@ -768,6 +785,7 @@ func (net *Network) startOasisNode(
 
 	node.cmd = cmd
 	node.exitCh = exitCh
+	node.stoppedCleanly = false
 
 	return nil
 }
@@ -815,6 +833,9 @@ func (net *Network) MakeGenesis() error {
 	if net.cfg.Beacon.DebugMockBackend {
 		args = append(args, "--"+genesis.CfgBeaconDebugMockBackend)
 	}
+	if net.cfg.Consensus.Parameters.DebugBlockTimeControl {
+		args = append(args, "--"+genesis.CfgConsensusDebugBlockTimeControl)
+	}
 	if net.cfg.RuntimeDefaultMaxAttestationAge != 0 {
 		args = append(args, "--"+genesis.CfgRegistryTEEFeaturesSGXDefaultMaxAttestationAge, strconv.FormatUint(net.cfg.RuntimeDefaultMaxAttestationAge, 10))
 	}
@@ -1044,6 +1065,8 @@ func New(env *env.Env, cfg *NetworkCfg) (*Network, error) {
 		ports:    make(map[string]uint16),
 		errCh:    make(chan error, maxNodes),
 	}
+	net.faults = newNetworkFaults(net)
+	env.AddOnCleanup(net.faults.close)
 
 	// Pre-provision node objects if they were listed in the top-level network fixture.
 	for _, nodeName := range cfg.Nodes {
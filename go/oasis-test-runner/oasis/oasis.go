@@ -99,10 +99,11 @@ type Node struct { // nolint: maligned
 
 	exitCh chan error
 
-	termEarlyOk bool
-	termErrorOk bool
-	isStopping  bool
-	noAutoStart bool
+	termEarlyOk    bool
+	termErrorOk    bool
+	isStopping     bool
+	stoppedCleanly bool
+	noAutoStart    bool
 
 	crashPointsProbability      float64
 	supplementarySanityInterval uint64
@@ -207,14 +208,14 @@ func (n *Node) AddSeedNodesToConfigExcept(excludeSeedNodeName string) {
 			ID: seed.p2pSigner,
 			Address: commonNode.Address{
 				IP:   net.ParseIP("127.0.0.1"),
-				Port: int64(seed.consensusPort),
+				Port: int64(seed.advertisedPort(nodePortConsensus)),
 			},
 		}
 		libp2pSeed := commonNode.ConsensusAddress{
 			ID: seed.p2pSigner,
 			Address: commonNode.Address{
 				IP:   net.ParseIP("127.0.0.1"),
-				Port: int64(seed.libp2pSeedPort),
+				Port: int64(seed.advertisedPort(nodePortP2PSeed)),
 			},
 		}
 
@@ -334,9 +335,13 @@ func (n *Node) stopNode(graceful bool) error {
 		_ = n.cmd.Process.Signal(os.Interrupt)
 	}
 	_ = n.cmd.Wait()
-	<-n.Exit()
+	exitErr := <-n.Exit()
 	n.cmd = nil
 
+	n.Lock()
+	n.stoppedCleanly = graceful && exitErr == nil
+	n.Unlock()
+
 	return nil
 }
 
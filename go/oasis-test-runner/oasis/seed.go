@@ -34,7 +34,7 @@ func (seed *Seed) ModifyConfig() error {
 	seed.Config.Mode = config.ModeSeed
 
 	seed.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(seed.consensusPort))
-	seed.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(seed.consensusPort))
+	seed.setAdvertisedAddresses(nodePortP2PSeed)
 
 	if seed.disableAddrBookFromGenesis {
 		seed.Config.Consensus.Debug.DisableAddrBookFromGenesis = true
@@ -0,0 +1,345 @@
+package oasis
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	snapshotVersion = 1
+
+	snapshotManifestFile = "manifest.json"
+	snapshotFixtureFile  = "fixture.json"
+	snapshotNetworkDir   = "network"
+	snapshotGenesisFile  = "genesis.json"
+
+	// cometbftAddrBookFile is the name of the CometBFT address book which refers to the ports of
+	// the snapshotted network and is therefore never included in snapshots.
+	cometbftAddrBookFile = "addrbook.json"
+)
+
+// ErrSnapshotMismatch is the error returned when the network does not match the restored snapshot.
+var ErrSnapshotMismatch = errors.New("oasis: network does not match snapshot")
+
+// snapshotManifest is the manifest of a network snapshot.
+type snapshotManifest struct {
+	// Version is the snapshot format version.
+	Version uint16 `json:"version"`
+	// Nodes are the names of all snapshotted nodes.
+	Nodes []string `json:"nodes"`
+}
+
+// RestoredFromSnapshot returns true iff the network state was restored from a snapshot.
+func (net *Network) RestoredFromSnapshot() bool {
+	return net.snapshot != nil
+}
+
+// StopGracefully gracefully stops all network nodes in reverse start order.
+func (net *Network) StopGracefully() error {
+	net.logger.Info("gracefully stopping network")
+
+	for i := len(net.nodes) - 1; i >= 0; i-- {
+		n := net.nodes[i]
+		if err := n.StopGracefully(); err != nil {
+			return fmt.Errorf("oasis: failed to stop node %s: %w", n.Name, err)
+		}
+	}
+	net.running = false
+
+	return nil
+}
+
+// SaveSnapshot saves the state of a stopped network into a snapshot archive at the given path.
+//
+// The snapshot contains the node data directories (including identities), the entities, the
+// genesis document and the network fixture. All nodes must have been stopped gracefully.
+func (net *Network) SaveSnapshot(archivePath string) error {
+	var nodes []string
+	for _, n := range net.snapshotNodes() {
+		if err := n.checkStoppedCleanly(); err != nil {
+			return err
+		}
+		nodes = append(nodes, n.Name)
+	}
+	slices.Sort(nodes)
+
+	net.logger.Info("saving network snapshot",
+		"path", archivePath,
+		"nodes", nodes,
+	)
+
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("oasis: failed to create snapshot: %w", err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	manifest, err := json.MarshalIndent(&snapshotManifest{
+		Version: snapshotVersion,
+		Nodes:   nodes,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("oasis: failed to marshal snapshot manifest: %w", err)
+	}
+	if err = writeTarFile(tw, snapshotManifestFile, manifest); err != nil {
+		return err
+	}
+	if net.fixture != nil {
+		var fixture []byte
+		if fixture, err = json.MarshalIndent(net.fixture, "", "  "); err != nil {
+			return fmt.Errorf("oasis: failed to marshal network fixture: %w", err)
+		}
+		if err = writeTarFile(tw, snapshotFixtureFile, fixture); err != nil {
+			return err
+		}
+	}
+
+	// The genesis document may live outside of the network directory (e.g., after a dump/restore)
+	// so always store it at a well-known location.
+	genesis, err := os.ReadFile(net.GenesisPath())
+	if err != nil {
+		return fmt.Errorf("oasis: failed to read genesis document: %w", err)
+	}
+	if err = writeTarFile(tw, path.Join(snapshotNetworkDir, snapshotGenesisFile), genesis); err != nil {
+		return err
+	}
+
+	baseDir := net.baseDir.String()
+	err = filepath.WalkDir(baseDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(baseDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." || rel == snapshotGenesisFile || !includeInSnapshot(d) {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(snapshotNetworkDir, filepath.ToSlash(rel))
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("oasis: failed to archive network directory: %w", err)
+	}
+
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("oasis: failed to finalize snapshot: %w", err)
+	}
+	if err = gw.Close(); err != nil {
+		return fmt.Errorf("oasis: failed to finalize snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+// restoreSnapshot extracts the configured snapshot into the network directory and configures the
+// network and the given fixture to reuse the restored identities and genesis document.
+func (net *Network) restoreSnapshot(f *NetworkFixture) error {
+	net.logger.Info("restoring network snapshot",
+		"path", net.cfg.Snapshot,
+	)
+
+	file, err := os.Open(net.cfg.Snapshot)
+	if err != nil {
+		return fmt.Errorf("oasis: failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("oasis: failed to open snapshot: %w", err)
+	}
+	defer gr.Close()
+
+	baseDir := net.baseDir.String()
+	var manifest *snapshotManifest
+	tr := tar.NewReader(gr)
+	for {
+		var hdr *tar.Header
+		hdr, err = tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("oasis: malformed snapshot: %w", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		switch {
+		case name == snapshotManifestFile:
+			manifest = new(snapshotManifest)
+			if err = json.NewDecoder(tr).Decode(manifest); err != nil {
+				return fmt.Errorf("oasis: malformed snapshot manifest: %w", err)
+			}
+			continue
+		case name == snapshotFixtureFile:
+			continue
+		}
+
+		rel, ok := strings.CutPrefix(name, snapshotNetworkDir+"/")
+		if !ok || !filepath.IsLocal(rel) {
+			return fmt.Errorf("oasis: malformed snapshot: invalid entry '%s'", hdr.Name)
+		}
+		dst := filepath.Join(baseDir, filepath.FromSlash(rel))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(dst, 0o700); err != nil {
+				return fmt.Errorf("oasis: failed to restore snapshot: %w", err)
+			}
+		case tar.TypeReg:
+			if err = extractTarFile(tr, dst, hdr.FileInfo().Mode().Perm()); err != nil {
+				return fmt.Errorf("oasis: failed to restore snapshot: %w", err)
+			}
+		default:
+			return fmt.Errorf("oasis: malformed snapshot: unsupported entry type for '%s'", hdr.Name)
+		}
+	}
+
+	switch {
+	case manifest == nil:
+		return fmt.Errorf("oasis: malformed snapshot: missing manifest")
+	case manifest.Version != snapshotVersion:
+		return fmt.Errorf("oasis: unsupported snapshot version: %d", manifest.Version)
+	}
+	net.snapshot = manifest
+
+	// Reuse the snapshotted genesis document and identities.
+	net.cfg.GenesisFile = filepath.Join(baseDir, snapshotGenesisFile)
+	net.cfg.RestoreIdentities = true
+	for i := range f.Entities {
+		if !f.Entities[i].IsDebugTestEntity {
+			f.Entities[i].Restore = true
+		}
+	}
+
+	return nil
+}
+
+// checkSnapshotNodes ensures that the nodes provisioned by the fixture match the restored snapshot.
+func (net *Network) checkSnapshotNodes() error {
+	var nodes []string
+	for _, n := range net.snapshotNodes() {
+		nodes = append(nodes, n.Name)
+	}
+	slices.Sort(nodes)
+
+	if !slices.Equal(nodes, net.snapshot.Nodes) {
+		return fmt.Errorf("%w: expected nodes %v, got %v", ErrSnapshotMismatch, net.snapshot.Nodes, nodes)
+	}
+	return nil
+}
+
+// snapshotNodes returns the nodes whose state is part of a snapshot.
+func (net *Network) snapshotNodes() []*Node {
+	var nodes []*Node
+	for _, n := range net.nodes {
+		// The IAS proxy is stateless and provisioned on start.
+		if net.iasProxy != nil && n.Name == net.iasProxy.Name {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// checkStoppedCleanly returns an error unless the node was never started or was stopped gracefully.
+func (n *Node) checkStoppedCleanly() error {
+	n.Lock()
+	defer n.Unlock()
+
+	switch {
+	case n.cmd != nil:
+		return fmt.Errorf("oasis: node %s was not stopped gracefully", n.Name)
+	case n.exitCh != nil && !n.stoppedCleanly:
+		return fmt.Errorf("oasis: node %s did not stop cleanly", n.Name)
+	default:
+		return nil
+	}
+}
+
+// includeInSnapshot returns true iff the given network directory entry should be snapshotted.
+func includeInSnapshot(d fs.DirEntry) bool {
+	switch {
+	case d.IsDir():
+		return true
+	case !d.Type().IsRegular():
+		// Skip sockets and other special files.
+		return false
+	case d.Name() == cometbftAddrBookFile:
+		return false
+	case strings.HasSuffix(d.Name(), ".log"):
+		// Skip logs so that log watchers of the restored network only see new entries.
+		return false
+	default:
+		return true
+	}
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0o600,
+		Size: int64(len(data)),
+	}); err != nil {
+		return fmt.Errorf("oasis: failed to write snapshot: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("oasis: failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+func extractTarFile(r io.Reader, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,136 @@
+package oasis
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+)
+
+func TestSnapshot(t *testing.T) {
+	newNetwork := func(t *testing.T, nodes ...string) *Network {
+		baseDir, err := new(env.Dir).NewSubDir(filepath.Join(t.TempDir(), "network"))
+		require.NoError(t, err, "NewSubDir")
+
+		net := &Network{
+			logger:  logging.GetLogger("oasis/test"),
+			baseDir: baseDir,
+			cfg:     &NetworkCfg{},
+		}
+		for _, name := range nodes {
+			dir, err := baseDir.NewSubDir(name)
+			require.NoError(t, err, "NewSubDir")
+			net.nodes = append(net.nodes, &Node{Name: name, dir: dir})
+		}
+		return net
+	}
+	writeFile := func(t *testing.T, path string, data string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700), "MkdirAll")
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600), "WriteFile")
+	}
+
+	t.Run("SaveRestore", func(t *testing.T) {
+		require := require.New(t)
+
+		src := newNetwork(t, "validator-0", "client-0")
+		src.fixture = &NetworkFixture{}
+		srcDir := src.BasePath()
+		writeFile(t, filepath.Join(srcDir, "genesis.json"), "genesis")
+		writeFile(t, filepath.Join(srcDir, "entity-1", "entity.json"), "entity")
+		writeFile(t, filepath.Join(srcDir, "validator-0", "identity.pem"), "identity")
+		writeFile(t, filepath.Join(srcDir, "validator-0", "node.log"), "log")
+		writeFile(t, filepath.Join(srcDir, "validator-0", "consensus", "config", "addrbook.json"), "peers")
+
+		archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+		// Nodes must be stopped gracefully.
+		validator := src.nodes[0]
+		validator.cmd = &exec.Cmd{}
+		require.Error(src.SaveSnapshot(archive), "snapshot of a running network should fail")
+		validator.cmd = nil
+		validator.exitCh = make(chan error)
+		require.Error(src.SaveSnapshot(archive), "snapshot of a killed node should fail")
+		validator.stoppedCleanly = true
+		require.NoError(src.SaveSnapshot(archive), "SaveSnapshot")
+
+		// Restore the snapshot.
+		dst := newNetwork(t, "client-0", "validator-0")
+		dst.cfg.Snapshot = archive
+		f := &NetworkFixture{
+			Entities: []EntityCfg{
+				{IsDebugTestEntity: true},
+				{},
+			},
+		}
+		require.False(dst.RestoredFromSnapshot())
+		require.NoError(dst.restoreSnapshot(f), "restoreSnapshot")
+		require.True(dst.RestoredFromSnapshot())
+		require.NoError(dst.checkSnapshotNodes(), "nodes should match the snapshot")
+
+		dstDir := dst.BasePath()
+		for path, data := range map[string]string{
+			"genesis.json":             "genesis",
+			"entity-1/entity.json":     "entity",
+			"validator-0/identity.pem": "identity",
+		} {
+			restored, err := os.ReadFile(filepath.Join(dstDir, path))
+			require.NoError(err, "ReadFile %s", path)
+			require.Equal(data, string(restored), "restored %s", path)
+		}
+		require.NoFileExists(filepath.Join(dstDir, "validator-0", "node.log"), "logs should not be restored")
+		require.NoFileExists(filepath.Join(dstDir, "validator-0", "consensus", "config", "addrbook.json"), "address books should not be restored")
+
+		require.Equal(filepath.Join(dstDir, "genesis.json"), dst.GenesisPath())
+		require.True(dst.cfg.RestoreIdentities)
+		require.False(f.Entities[0].Restore, "debug test entities should not be restored")
+		require.True(f.Entities[1].Restore, "entities should be restored")
+
+		// The fixture must provision the same nodes.
+		dir, err := dst.baseDir.NewSubDir("compute-0")
+		require.NoError(err, "NewSubDir")
+		dst.nodes = append(dst.nodes, &Node{Name: "compute-0", dir: dir})
+		require.ErrorIs(dst.checkSnapshotNodes(), ErrSnapshotMismatch)
+	})
+
+	for _, tc := range []struct {
+		name    string
+		entries map[string]string
+	}{
+		{"MissingManifest", map[string]string{"network/genesis.json": "genesis"}},
+		{"PathTraversal", map[string]string{
+			snapshotManifestFile:        `{"version":1}`,
+			"network/../../escape.json": "escape",
+		}},
+		{"UnknownEntry", map[string]string{
+			snapshotManifestFile: `{"version":1}`,
+			"other/file.json":    "other",
+		}},
+		{"UnsupportedVersion", map[string]string{snapshotManifestFile: `{"version":42}`}},
+	} {
+		t.Run("Malformed/"+tc.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+			f, err := os.Create(archive)
+			require.NoError(t, err, "Create")
+			gw := gzip.NewWriter(f)
+			tw := tar.NewWriter(gw)
+			for name, data := range tc.entries {
+				require.NoError(t, writeTarFile(tw, name, []byte(data)), "writeTarFile")
+			}
+			require.NoError(t, tw.Close(), "Close")
+			require.NoError(t, gw.Close(), "Close")
+			require.NoError(t, f.Close(), "Close")
+
+			net := newNetwork(t)
+			net.cfg.Snapshot = archive
+			require.Error(t, net.restoreSnapshot(&NetworkFixture{}), "malformed snapshots should be rejected")
+			require.False(t, net.RestoredFromSnapshot())
+		})
+	}
+}
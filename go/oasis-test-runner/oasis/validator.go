@@ -84,7 +84,7 @@ func (val *Validator) ModifyConfig() error {
 	val.Config.Consensus.Validator = true
 
 	val.Config.Consensus.ListenAddress = allInterfacesAddr + ":" + strconv.Itoa(int(val.consensusPort))
-	val.Config.Consensus.ExternalAddress = localhostAddr + ":" + strconv.Itoa(int(val.consensusPort))
+	val.setAdvertisedAddresses(nodePortP2P)
 
 	if val.supplementarySanityInterval > 0 {
 		val.Config.Consensus.SupplementarySanity.Enabled = true
@@ -136,14 +136,14 @@ func (net *Network) NewValidator(cfg *ValidatorCfg) (*Validator, error) {
 		}
 	} else {
 		var consensusAddr node.Address
-		if err = consensusAddr.FromIP(localhost, val.consensusPort); err != nil {
+		if err = consensusAddr.FromIP(localhost, val.advertisedPort(nodePortConsensus)); err != nil {
 			return nil, fmt.Errorf("oasis/validator: failed to parse consensus IP address: %w", err)
 		}
 		consensusAddrs = append(consensusAddrs, &consensusAddr)
 	}
 
 	var p2pAddr node.Address
-	if err = p2pAddr.FromIP(localhost, val.p2pPort); err != nil {
+	if err = p2pAddr.FromIP(localhost, val.advertisedPort(nodePortP2P)); err != nil {
 		return nil, fmt.Errorf("oasis/validator: failed to parse P2P IP address: %w", err)
 	}
 
@@ -69,10 +69,12 @@ func (sc *gasFeesImpl) Fixture() (*oasis.NetworkFixture, error) {
 				Parameters: staking.ConsensusParameters{
 					DebondingInterval: 1,
 					GasCosts: transaction.Costs{
-						staking.GasOpTransfer:      10,
-						staking.GasOpBurn:          10,
-						staking.GasOpAddEscrow:     10,
-						staking.GasOpReclaimEscrow: 10,
+						staking.GasOpTransfer:                   10,
+						staking.GasOpBurn:                       10,
+						staking.GasOpAddEscrow:                  10,
+						staking.GasOpReclaimEscrow:              10,
+						staking.GasOpDelegateFromAllowance:      10,
+						staking.GasOpReclaimEscrowFromAllowance: 10,
 					},
 					FeeSplitWeightPropose:     *quantity.NewFromUint64(1),
 					FeeSplitWeightVote:        *quantity.NewFromUint64(2),
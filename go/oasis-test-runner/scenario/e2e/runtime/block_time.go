@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+const (
+	// blockTimeStep is the simulated time step between consecutive consensus blocks.
+	blockTimeStep = 12 * time.Hour
+	// blockTimeSimulated is the amount of simulated time the scenario drives.
+	blockTimeSimulated = 7 * 24 * time.Hour
+)
+
+// BlockTime is the scenario where block timestamps observed by consensus applications are frozen
+// and stepped via the debug controller.
+var BlockTime scenario.Scenario = newBlockTimeImpl()
+
+type blockTimeImpl struct {
+	Scenario
+}
+
+func newBlockTimeImpl() scenario.Scenario {
+	return &blockTimeImpl{
+		Scenario: *NewScenario("block-time", nil),
+	}
+}
+
+func (sc *blockTimeImpl) Clone() scenario.Scenario {
+	return &blockTimeImpl{
+		Scenario: *sc.Scenario.Clone().(*Scenario),
+	}
+}
+
+func (sc *blockTimeImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.Scenario.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use mock epoch so that simulated time does not interfere with epoch transitions.
+	f.Network.SetMockEpoch()
+	f.Network.Consensus.Parameters.DebugBlockTimeControl = true
+
+	return f, nil
+}
+
+func (sc *blockTimeImpl) Run(ctx context.Context, _ *env.Env) error {
+	if err := sc.Net.Start(); err != nil {
+		return err
+	}
+
+	fixture, err := sc.Fixture()
+	if err != nil {
+		return err
+	}
+	if _, err = sc.initialEpochTransitions(ctx, fixture); err != nil {
+		return err
+	}
+
+	blkCh, sub, err := sc.Net.ClientController().RuntimeClient.WatchBlocks(ctx, KeyValueRuntimeID)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	// Step block time so that a week of simulated time passes in a handful of blocks. Note that
+	// block timestamps have a resolution of one second.
+	base := time.Now().Add(time.Minute).Truncate(time.Second)
+	sc.Logger.Info("stepping block time",
+		"base", base,
+		"step", blockTimeStep,
+	)
+	if err = sc.Net.Controller().SetBlockTime(ctx, base, blockTimeStep); err != nil {
+		return fmt.Errorf("failed to set block time: %w", err)
+	}
+	if _, err = sc.WaitBlocks(ctx, int(blockTimeSimulated/blockTimeStep)+1); err != nil {
+		return err
+	}
+
+	blk, err := sc.insertAndWaitBlock(ctx, blkCh, 0)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Unix(int64(blk.Block.Header.Timestamp), 0).Sub(base)
+	if elapsed < blockTimeSimulated {
+		return fmt.Errorf("expected at least %s of simulated time, got: %s", blockTimeSimulated, elapsed)
+	}
+	if elapsed%blockTimeStep != 0 {
+		return fmt.Errorf("simulated time %s is not a multiple of the step %s", elapsed, blockTimeStep)
+	}
+
+	// Freeze block time.
+	frozen := base.Add(2 * blockTimeSimulated)
+	sc.Logger.Info("freezing block time",
+		"time", frozen,
+	)
+	if err = sc.Net.Controller().SetBlockTime(ctx, frozen, 0); err != nil {
+		return fmt.Errorf("failed to freeze block time: %w", err)
+	}
+	for i := uint64(1); i <= 2; i++ {
+		if blk, err = sc.insertAndWaitBlock(ctx, blkCh, i); err != nil {
+			return err
+		}
+		if ts := time.Unix(int64(blk.Block.Header.Timestamp), 0); !ts.Equal(frozen) {
+			return fmt.Errorf("expected frozen block time %s, got: %s", frozen, ts)
+		}
+	}
+
+	// Going back in time should be rejected.
+	if err = sc.Net.Controller().SetBlockTime(ctx, base, blockTimeStep); err == nil {
+		return fmt.Errorf("setting block time in the past should fail")
+	}
+
+	// Restore block time proposed by the consensus backend.
+	if err = sc.Net.Controller().SetBlockTime(ctx, time.Time{}, 0); err != nil {
+		return fmt.Errorf("failed to restore block time: %w", err)
+	}
+
+	return nil
+}
+
+// insertAndWaitBlock submits a key/value insert transaction and returns the runtime block in which
+// it was included.
+func (sc *blockTimeImpl) insertAndWaitBlock(ctx context.Context, blkCh <-chan *roothash.AnnotatedBlock, nonce uint64) (*roothash.AnnotatedBlock, error) {
+	resp, err := sc.submitRuntimeTxMeta(ctx, KeyValueRuntimeID, nonce, "insert", InsertCall{
+		Key:   "time",
+		Value: fmt.Sprintf("tick %d", nonce),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = unpackRawTxResp(resp.Output); err != nil {
+		return nil, err
+	}
+	return sc.WaitRuntimeBlock(ctx, blkCh, resp.Round)
+}
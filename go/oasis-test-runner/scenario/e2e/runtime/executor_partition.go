@@ -0,0 +1,149 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// executorPartitionCatchUpTimeout is the time a partitioned compute worker has to catch up after
+// the network is healed.
+const executorPartitionCatchUpTimeout = 2 * time.Minute
+
+// ExecutorPartition is the scenario where a compute worker is partitioned from the rest of the
+// executor committee and the clients while rounds are being processed.
+var ExecutorPartition scenario.Scenario = newExecutorPartitionImpl()
+
+type executorPartitionImpl struct {
+	Scenario
+}
+
+func newExecutorPartitionImpl() scenario.Scenario {
+	return &executorPartitionImpl{
+		Scenario: *NewScenario("executor-partition", nil),
+	}
+}
+
+func (sc *executorPartitionImpl) Clone() scenario.Scenario {
+	return &executorPartitionImpl{
+		Scenario: *sc.Scenario.Clone().(*Scenario),
+	}
+}
+
+func (sc *executorPartitionImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.Scenario.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	f.Network.NetworkFaults = true
+
+	return f, nil
+}
+
+func (sc *executorPartitionImpl) Run(ctx context.Context, _ *env.Env) error {
+	if err := sc.StartNetworkAndWaitForClientSync(ctx); err != nil {
+		return err
+	}
+
+	blkCh, sub, err := sc.Net.ClientController().RuntimeClient.WatchBlocks(ctx, KeyValueRuntimeID)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	if _, err = sc.insertAndWaitRound(ctx, blkCh, 0); err != nil {
+		return err
+	}
+
+	// Partition the first compute worker from the rest of the executor committee and the clients.
+	// Make sure the network is healed even if the scenario fails.
+	computeWorkers := sc.Net.ComputeWorkers()
+	partitioned := computeWorkers[0]
+	var others []*oasis.Node
+	for _, cw := range computeWorkers[1:] {
+		others = append(others, cw.Node)
+	}
+	for _, client := range sc.Net.Clients() {
+		others = append(others, client.Node)
+	}
+	if err = sc.Net.Partition([]*oasis.Node{partitioned.Node}, others); err != nil {
+		return fmt.Errorf("failed to partition network: %w", err)
+	}
+	defer sc.Net.HealNetwork()
+
+	// The remaining compute workers should keep processing rounds.
+	sc.Logger.Info("submitting transaction during partition")
+	if _, err = sc.insertAndWaitRound(ctx, blkCh, 1); err != nil {
+		return fmt.Errorf("transaction not processed during partition: %w", err)
+	}
+
+	sc.Net.HealNetwork()
+
+	// The partitioned compute worker should catch up after the network is healed.
+	sc.Logger.Info("submitting transaction after healing the network")
+	round, err := sc.insertAndWaitRound(ctx, blkCh, 2)
+	if err != nil {
+		return fmt.Errorf("transaction not processed after healing the network: %w", err)
+	}
+
+	return sc.waitComputeWorkerRound(ctx, partitioned, round)
+}
+
+// insertAndWaitRound submits a key/value insert transaction and returns the runtime round in which
+// it was included.
+func (sc *executorPartitionImpl) insertAndWaitRound(ctx context.Context, blkCh <-chan *roothash.AnnotatedBlock, nonce uint64) (uint64, error) {
+	resp, err := sc.submitRuntimeTxMeta(ctx, KeyValueRuntimeID, nonce, "insert", InsertCall{
+		Key:   "partition",
+		Value: fmt.Sprintf("value %d", nonce),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err = unpackRawTxResp(resp.Output); err != nil {
+		return 0, err
+	}
+	if _, err = sc.WaitRuntimeBlock(ctx, blkCh, resp.Round); err != nil {
+		return 0, err
+	}
+	return resp.Round, nil
+}
+
+// waitComputeWorkerRound waits until the given compute worker has seen the given runtime round.
+func (sc *executorPartitionImpl) waitComputeWorkerRound(ctx context.Context, cw *oasis.Compute, round uint64) error {
+	sc.Logger.Info("waiting for compute worker to catch up",
+		"node", cw.Name,
+		"round", round,
+	)
+
+	ctrl, err := oasis.NewController(cw.SocketPath())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, executorPartitionCatchUpTimeout)
+	defer cancel()
+
+	for {
+		var status *control.Status
+		status, err = ctrl.GetStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get status for node %s: %w", cw.Name, err)
+		}
+		if rt, ok := status.Runtimes[KeyValueRuntimeID]; ok && rt.LatestRound >= round {
+			return nil
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("compute worker %s did not catch up to round %d: %w", cw.Name, round, ctx.Err())
+		}
+	}
+}
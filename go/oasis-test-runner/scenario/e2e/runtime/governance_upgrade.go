@@ -2,7 +2,9 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
@@ -363,6 +365,25 @@ func (sc *governanceConsensusUpgradeImpl) Run(ctx context.Context, childEnv *env
 			},
 		},
 	}
+	// Validate the upgrade against the current state before voting for it.
+	sc.Logger.Info("dry running the upgrade")
+	result, err := sc.Net.Controller().DryRunUpgrade(ctx, &content.Upgrade.Descriptor)
+	if err != nil {
+		return fmt.Errorf("failed to dry run upgrade: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("upgrade dry run failed: %s", result.Error)
+	}
+	if !slices.ContainsFunc(result.ParameterChanges, func(pc upgrade.ParameterChange) bool {
+		return pc.Module == "consensus" && pc.Parameter == "max_tx_size"
+	}) {
+		return fmt.Errorf("upgrade dry run should report the changed consensus parameters, got: %v", result.ParameterChanges)
+	}
+	sc.Logger.Info("upgrade dry run succeeded",
+		"duration", result.Duration,
+		"parameter_changes", result.ParameterChanges,
+	)
+
 	// Submit upgrade proposal.
 	proposal, err := sc.ensureProposalFinalized(ctx, content)
 	if err != nil {
@@ -378,6 +399,20 @@ func (sc *governanceConsensusUpgradeImpl) Run(ctx context.Context, childEnv *env
 		return fmt.Errorf("expected one pending upgrade, got: %v", l)
 	}
 
+	// Ensure the upgrade is scheduled locally and can not be cancelled as a local upgrade.
+	localUpgrades, err := sc.Net.Controller().ListPendingUpgrades(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list locally scheduled upgrades: %w", err)
+	}
+	if !slices.ContainsFunc(localUpgrades, func(pu *upgrade.PendingUpgrade) bool {
+		return pu.Descriptor.Equals(&content.Upgrade.Descriptor) && pu.Source == upgrade.UpgradeSourceGovernance
+	}) {
+		return fmt.Errorf("expected governance upgrade to be scheduled locally, got: %v", localUpgrades)
+	}
+	if err = sc.Net.Controller().CancelLocalUpgrade(ctx, content.Upgrade.Descriptor.Handler); !errors.Is(err, upgrade.ErrNotLocalUpgrade) {
+		return fmt.Errorf("cancelling governance upgrade locally should fail with not local upgrade error, got: %w", err)
+	}
+
 	// Cancel upgrade if configured so.
 	if sc.shouldCancelUpgrade {
 		if err = sc.cancelUpgrade(ctx, proposal.ID); err != nil {
@@ -6,11 +6,22 @@ import (
 	"fmt"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
 )
 
 func (sc *Scenario) initialEpochTransitions(ctx context.Context, fixture *oasis.NetworkFixture) (beacon.EpochTime, error) {
-	return sc.initialEpochTransitionsWith(ctx, fixture, 0)
+	var baseEpoch beacon.EpochTime
+	if sc.Net.RestoredFromSnapshot() {
+		// The restored network has already progressed past the initial epochs.
+		epoch, err := sc.Net.Controller().Beacon.GetEpoch(ctx, consensus.HeightLatest)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get current epoch: %w", err)
+		}
+		baseEpoch = epoch
+	}
+
+	return sc.initialEpochTransitionsWith(ctx, fixture, baseEpoch)
 }
 
 func (sc *Scenario) initialEpochTransitionsWith(ctx context.Context, fixture *oasis.NetworkFixture, baseEpoch beacon.EpochTime) (beacon.EpochTime, error) {
@@ -18,6 +18,17 @@ func (sc *Scenario) StartNetworkAndWaitForClientSync(ctx context.Context) error
 	return sc.WaitForClientSync(ctx)
 }
 
+// Preamble implements scenario.Preambler.
+//
+// The preamble starts the network and waits for all nodes to sync.
+func (sc *Scenario) Preamble(ctx context.Context, _ *env.Env) error {
+	if err := sc.StartNetworkAndWaitForClientSync(ctx); err != nil {
+		return err
+	}
+
+	return sc.WaitNodesSynced(ctx)
+}
+
 // StartNetworkAndTestClient starts the network and the runtime test client.
 func (sc *Scenario) StartNetworkAndTestClient(ctx context.Context, childEnv *env.Env) error {
 	if err := sc.StartNetworkAndWaitForClientSync(ctx); err != nil {
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// nodeFaultsCommitDelay is the executor commitment delay injected into the faulty compute worker.
+const nodeFaultsCommitDelay = 10 * time.Second
+
+// NodeFaults is the scenario where one compute worker is made to misbehave via the debug
+// controller, one injected fault at a time, and the network must keep processing rounds.
+var NodeFaults scenario.Scenario = newNodeFaultsImpl()
+
+type nodeFaultsImpl struct {
+	Scenario
+}
+
+func newNodeFaultsImpl() scenario.Scenario {
+	return &nodeFaultsImpl{
+		Scenario: *NewScenario("node-faults", nil),
+	}
+}
+
+func (sc *nodeFaultsImpl) Clone() scenario.Scenario {
+	return &nodeFaultsImpl{
+		Scenario: *sc.Scenario.Clone().(*Scenario),
+	}
+}
+
+func (sc *nodeFaultsImpl) Run(ctx context.Context, _ *env.Env) error {
+	if err := sc.StartNetworkAndWaitForClientSync(ctx); err != nil {
+		return err
+	}
+
+	blkCh, sub, err := sc.Net.ClientController().RuntimeClient.WatchBlocks(ctx, KeyValueRuntimeID)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	faulty := sc.Net.ComputeWorkers()[0]
+	ctrl, err := oasis.NewController(faulty.SocketPath())
+	if err != nil {
+		return err
+	}
+	defer ctrl.Close()
+
+	faults := []struct {
+		name    string
+		expect  fault.Faults
+		enable  func() error
+		disable func() error
+	}{
+		{
+			"stop registrations",
+			fault.Faults{RegistrationStopped: true},
+			func() error { return ctrl.SetRegistrationFault(ctx, true) },
+			func() error { return ctrl.SetRegistrationFault(ctx, false) },
+		},
+		{
+			"delay executor commitments",
+			fault.Faults{ExecutorCommitDelay: nodeFaultsCommitDelay},
+			func() error { return ctrl.SetExecutorCommitDelayFault(ctx, nodeFaultsCommitDelay) },
+			func() error { return ctrl.SetExecutorCommitDelayFault(ctx, 0) },
+		},
+		{
+			"drop committee messages",
+			fault.Faults{CommitteeMessageDropPercent: 50},
+			func() error { return ctrl.SetCommitteeMessageDropFault(ctx, 50) },
+			func() error { return ctrl.SetCommitteeMessageDropFault(ctx, 0) },
+		},
+		{
+			"pause checkpoint serving",
+			fault.Faults{CheckpointServingPaused: true},
+			func() error { return ctrl.SetCheckpointServingFault(ctx, true) },
+			func() error { return ctrl.SetCheckpointServingFault(ctx, false) },
+		},
+	}
+
+	var nonce uint64
+	for _, f := range faults {
+		sc.Logger.Info("injecting fault",
+			"node", faulty.Name,
+			"fault", f.name,
+		)
+
+		if err = f.enable(); err != nil {
+			return fmt.Errorf("failed to inject fault '%s': %w", f.name, err)
+		}
+		if err = sc.checkReportedFaults(ctx, ctrl, &f.expect); err != nil {
+			return fmt.Errorf("fault '%s': %w", f.name, err)
+		}
+
+		// The network should tolerate a single faulty compute worker. Process a few rounds so that
+		// the faulty node gets to act as a committee member.
+		for range 3 {
+			if err = sc.insertAndWaitRound(ctx, blkCh, nonce); err != nil {
+				return fmt.Errorf("transaction not processed with fault '%s': %w", f.name, err)
+			}
+			nonce++
+		}
+
+		if err = f.disable(); err != nil {
+			return fmt.Errorf("failed to clear fault '%s': %w", f.name, err)
+		}
+		if err = sc.checkReportedFaults(ctx, ctrl, nil); err != nil {
+			return fmt.Errorf("fault '%s': %w", f.name, err)
+		}
+	}
+
+	// Make sure everything still works once all faults are cleared.
+	return sc.insertAndWaitRound(ctx, blkCh, nonce)
+}
+
+// checkReportedFaults verifies that the node status reports the expected injected faults.
+func (sc *nodeFaultsImpl) checkReportedFaults(ctx context.Context, ctrl *oasis.Controller, expected *fault.Faults) error {
+	status, err := ctrl.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node status: %w", err)
+	}
+	if status.Debug == nil {
+		return fmt.Errorf("node status is missing debug status")
+	}
+
+	switch {
+	case expected == nil && status.Debug.Faults != nil:
+		return fmt.Errorf("unexpected faults reported: %+v", status.Debug.Faults)
+	case expected != nil && (status.Debug.Faults == nil || *status.Debug.Faults != *expected):
+		return fmt.Errorf("unexpected faults reported: %+v (expected: %+v)", status.Debug.Faults, expected)
+	}
+	return nil
+}
+
+// insertAndWaitRound submits a key/value insert transaction and waits for the round in which it
+// was included.
+func (sc *nodeFaultsImpl) insertAndWaitRound(ctx context.Context, blkCh <-chan *roothash.AnnotatedBlock, nonce uint64) error {
+	resp, err := sc.submitRuntimeTxMeta(ctx, KeyValueRuntimeID, nonce, "insert", InsertCall{
+		Key:   "faults",
+		Value: fmt.Sprintf("value %d", nonce),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = unpackRawTxResp(resp.Output); err != nil {
+		return err
+	}
+	_, err = sc.WaitRuntimeBlock(ctx, blkCh, resp.Round)
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,7 +30,10 @@ import (
 // RuntimeUpgrade is the runtime upgrade scenario.
 var RuntimeUpgrade scenario.Scenario = newRuntimeUpgradeImpl()
 
-const versionActivationTimeout = 15 * time.Second
+const (
+	versionActivationTimeout     = 15 * time.Second
+	bundleDownloadFailureTimeout = 2 * time.Minute
+)
 
 type runtimeUpgradeImpl struct {
 	Scenario
@@ -90,21 +94,39 @@ func (sc *runtimeUpgradeImpl) Run(ctx context.Context, childEnv *env.Env) error
 	}
 	port := parsedURL.Port()
 
-	// Start serving bundles.
+	// Start the bundle server, which is unavailable until every node reports
+	// a failed download, to ensure that failed downloads are retried.
 	server := newBundleServer(port, bundles, sc.Logger)
 	server.Start()
 	defer server.Stop()
 
+	errCh := make(chan error, 1)
+	go func() {
+		defer server.setAvailable()
+		errCh <- sc.waitFailedBundleDownloads(ctx)
+	}()
+
+	// Make sure invalid runtime updates are caught before submission.
+	if err := sc.ensureInvalidUpdateRejected(ctx); err != nil {
+		return err
+	}
+
 	// Upgrade the compute runtime.
 	if err := sc.UpgradeComputeRuntime(ctx, childEnv, cli, sc.upgradedRuntimeIndex, 0); err != nil {
 		return err
 	}
+	if err := <-errCh; err != nil {
+		return err
+	}
 
 	// Verify that all client and compute nodes requested bundle from the server.
 	n := 2 * (len(sc.Net.Clients()) + len(sc.Net.ComputeWorkers()))
 	if m := server.getRequestCount(); m != n {
 		return fmt.Errorf("invalid number of bundle requests (got: %d, expected: %d)", m, n)
 	}
+	if m := server.getFailedRequestCount(); m < n/2 {
+		return fmt.Errorf("invalid number of failed bundle requests (got: %d, expected at least: %d)", m, n/2)
+	}
 
 	// Run client again.
 	sc.Logger.Info("starting a second client to check if runtime works")
@@ -130,6 +152,84 @@ func (sc *runtimeUpgradeImpl) Run(ctx context.Context, childEnv *env.Env) error
 	return nil
 }
 
+func (sc *runtimeUpgradeImpl) ensureInvalidUpdateRejected(ctx context.Context) error {
+	sc.Logger.Info("ensuring invalid runtime update is rejected by dry-run validation")
+
+	rt := sc.Net.Runtimes()[sc.upgradedRuntimeIndex]
+
+	epoch, err := sc.Net.Controller().Beacon.GetEpoch(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("failed to get current epoch: %w", err)
+	}
+	oldRtDsc, err := sc.Net.Controller().Registry.GetRuntime(ctx, &registry.GetRuntimeQuery{
+		Height: consensus.HeightLatest,
+		ID:     rt.ID(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get runtime descriptor: %w", err)
+	}
+
+	// New deployments must not become valid retroactively.
+	newRtDsc := rt.ToRuntimeDescriptor()
+	newRtDpl := newRtDsc.Deployments[1]
+	newRtDpl.ValidFrom = epoch
+	newRtDsc.Deployments = append(oldRtDsc.Deployments, newRtDpl)
+
+	err = sc.Net.Controller().Registry.ValidateRuntimeUpdate(ctx, &newRtDsc)
+	if err == nil {
+		return fmt.Errorf("retroactive runtime deployment should be rejected")
+	}
+	sc.Logger.Info("invalid runtime update rejected",
+		"err", err,
+	)
+
+	return nil
+}
+
+func (sc *runtimeUpgradeImpl) waitFailedBundleDownloads(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, bundleDownloadFailureTimeout)
+	defer cancel()
+
+	var nodes []*oasis.Node
+	for _, n := range sc.Net.Clients() {
+		nodes = append(nodes, n.Node)
+	}
+	for _, n := range sc.Net.ComputeWorkers() {
+		nodes = append(nodes, n.Node)
+	}
+
+	for _, node := range nodes {
+		sc.Logger.Info("waiting for node to report a failed bundle download",
+			"node", node.Name,
+		)
+
+		nodeCtrl, err := oasis.NewController(node.SocketPath())
+		if err != nil {
+			return fmt.Errorf("%s: failed to create controller: %w", node.Name, err)
+		}
+
+		for {
+			status, err := nodeCtrl.GetStatus(ctx)
+			if err != nil {
+				return fmt.Errorf("%s: failed to query status: %w", node.Name, err)
+			}
+			if slices.ContainsFunc(status.Runtimes[KeyValueRuntimeID].PendingBundles, func(s bundle.DownloadStatus) bool {
+				return s.Attempts > 0 && s.LastError != ""
+			}) {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%s: no failed bundle download reported: %w", node.Name, ctx.Err())
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	return nil
+}
+
 func (sc *runtimeUpgradeImpl) verifyBundleDir(worker *oasis.Compute, manifestHash hash.Hash) error {
 	sc.Logger.Info("ensuring cached exploded bundle for old version was removed",
 		"worker", worker.Name,
@@ -184,7 +284,10 @@ type bundleServer struct {
 
 	bundles map[string]string
 
-	requestCount uint64
+	available atomic.Bool
+
+	requestCount       uint64
+	failedRequestCount uint64
 
 	logger *logging.Logger
 }
@@ -242,7 +345,17 @@ func (s *bundleServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *bundleServer) setAvailable() {
+	s.available.Store(true)
+}
+
 func (s *bundleServer) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
+	if !s.available.Load() {
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		atomic.AddUint64(&s.failedRequestCount, 1)
+		return
+	}
+
 	manifestHash := path.Base(r.URL.Path)
 	content := []byte(fmt.Sprintf("http://127.0.0.1:%s/%s%s\n", s.port, manifestHash, bundle.FileExtension))
 
@@ -281,6 +394,10 @@ func (s *bundleServer) getRequestCount() int {
 	return int(atomic.LoadUint64(&s.requestCount))
 }
 
+func (s *bundleServer) getFailedRequestCount() int {
+	return int(atomic.LoadUint64(&s.failedRequestCount))
+}
+
 func findBundles(dir string) (map[string]string, error) {
 	bundles := make(map[string]string)
 
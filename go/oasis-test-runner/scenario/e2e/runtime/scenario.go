@@ -306,6 +306,8 @@ func RegisterScenarios() error {
 		RuntimeEncryption,
 		RuntimeGovernance,
 		RuntimeMessage,
+		// Block time control test.
+		BlockTime,
 		// Byzantine executor node.
 		ByzantineExecutorHonest,
 		ByzantineExecutorSchedulerHonest,
@@ -324,6 +326,7 @@ func RegisterScenarios() error {
 		// Storage sync test.
 		StorageSync,
 		StorageSyncFromRegistered,
+		StorageSyncCheckpoints,
 		StorageSyncInconsistent,
 		StorageEarlyStateSync,
 		// Sentry test.
@@ -355,6 +358,10 @@ func RegisterScenarios() error {
 		MultipleRuntimes,
 		// Node shutdown test.
 		NodeShutdown,
+		// Network fault injection test.
+		ExecutorPartition,
+		// Node fault injection test.
+		NodeFaults,
 		OffsetRestart,
 		// Gas fees tests.
 		GasFeesRuntimes,
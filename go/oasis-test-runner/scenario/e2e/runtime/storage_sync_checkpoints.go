@@ -0,0 +1,263 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/env"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/log"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/oasis"
+	"github.com/oasisprotocol/oasis-core/go/oasis-test-runner/scenario"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	runtimeClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+const (
+	// cfgNumRounds is the number of runtime rounds to generate before starting the late node.
+	cfgNumRounds = "num_rounds"
+	// cfgCheckpointInterval is the runtime storage checkpoint interval.
+	cfgCheckpointInterval = "checkpoint_interval"
+	// cfgSyncTimeout is the maximum time the late node may take to become committee-eligible.
+	cfgSyncTimeout = "sync_timeout"
+)
+
+// StorageSyncCheckpoints is the storage sync scenario which tests that a fresh compute node can
+// join a network with a long runtime history using only checkpoint sync.
+var StorageSyncCheckpoints = func() scenario.Scenario {
+	sc := &storageSyncCheckpointsImpl{
+		Scenario: *NewScenario(
+			"storage-sync-checkpoints",
+			NewTestClient().WithScenario(SimpleScenario),
+		),
+	}
+	sc.Flags.Uint64(cfgNumRounds, 100, "number of runtime rounds to generate before starting the late node")
+	sc.Flags.Uint64(cfgCheckpointInterval, 10, "runtime storage checkpoint interval")
+	sc.Flags.Duration(cfgSyncTimeout, 5*time.Minute, "maximum time for the late node to become committee-eligible")
+
+	return sc
+}()
+
+type storageSyncCheckpointsImpl struct {
+	Scenario
+}
+
+func (sc *storageSyncCheckpointsImpl) Clone() scenario.Scenario {
+	return &storageSyncCheckpointsImpl{
+		Scenario: *sc.Scenario.Clone().(*Scenario),
+	}
+}
+
+func (sc *storageSyncCheckpointsImpl) Fixture() (*oasis.NetworkFixture, error) {
+	f, err := sc.Scenario.Fixture()
+	if err != nil {
+		return nil, err
+	}
+
+	// Configure runtime for storage checkpointing. Use small chunks so that restoring takes long
+	// enough to stop one of the providers in the middle of it.
+	checkpointInterval, _ := sc.Flags.GetUint64(cfgCheckpointInterval)
+	f.Runtimes[1].Storage.CheckpointInterval = checkpointInterval
+	f.Runtimes[1].Storage.CheckpointNumKept = 2
+	f.Runtimes[1].Storage.CheckpointChunkSize = 1 * 1024
+
+	// Make all existing compute workers check for checkpoints often so they all act as
+	// checkpoint providers. The second one only serves checkpoints and no diffs.
+	for i := range f.ComputeWorkers {
+		f.ComputeWorkers[i].CheckpointCheckInterval = 1 * time.Second
+	}
+	f.ComputeWorkers[1].DiffServingDisabled = true
+	// The second compute worker will be stopped during the restore.
+	f.ComputeWorkers[1].AllowErrorTermination = true
+
+	// One more compute worker for later, which may only sync from checkpoints.
+	f.ComputeWorkers = append(f.ComputeWorkers, oasis.ComputeWorkerFixture{
+		NodeFixture: oasis.NodeFixture{
+			NoAutoStart: true,
+		},
+		Entity:                  1,
+		Runtimes:                []int{1},
+		CheckpointSyncEnabled:   true,
+		InitialDiffSyncDisabled: true,
+		LogWatcherHandlerFactories: []log.WatcherHandlerFactory{
+			oasis.LogAssertCheckpointSync(),
+		},
+	})
+
+	return f, nil
+}
+
+func (sc *storageSyncCheckpointsImpl) Run(ctx context.Context, childEnv *env.Env) error {
+	if err := sc.StartNetworkAndTestClient(ctx, childEnv); err != nil {
+		return err
+	}
+	if err := sc.WaitTestClient(); err != nil {
+		return err
+	}
+
+	numRounds, _ := sc.Flags.GetUint64(cfgNumRounds)
+	syncTimeout, _ := sc.Flags.GetDuration(cfgSyncTimeout)
+
+	ctrl, err := oasis.NewController(sc.Net.ComputeWorkers()[0].SocketPath())
+	if err != nil {
+		return fmt.Errorf("failed to connect with the first compute node: %w", err)
+	}
+	defer ctrl.Close()
+
+	// Generate runtime history. Use large values so that there is enough state to make the
+	// checkpoints span many chunks.
+	drbg, err := drbgFromSeed([]byte("storage-sync-checkpoints/seq"), []byte("plant_your_seeds"))
+	if err != nil {
+		return err
+	}
+	largeVal := strings.Repeat("has he his auto ", 64) // 1 KiB value.
+	for i := 0; ; i++ {
+		var blk *block.Block
+		blk, err = ctrl.RuntimeClient.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+			RuntimeID: KeyValueRuntimeID,
+			Round:     runtimeClient.RoundLatest,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		if blk.Header.Round >= numRounds {
+			break
+		}
+
+		if _, err = sc.submitKeyValueRuntimeInsertTx(ctx, KeyValueRuntimeID, drbg.Uint64(), fmt.Sprintf("cp key %d", i), largeVal, 0, 0, plaintextTxKind); err != nil {
+			return err
+		}
+	}
+
+	lateWorker := sc.Net.ComputeWorkers()[len(sc.Net.ComputeWorkers())-1]
+	provider := sc.Net.ComputeWorkers()[1]
+
+	sc.Logger.Info("starting late compute worker")
+
+	if err = lateWorker.Start(); err != nil {
+		return fmt.Errorf("can't start late compute worker: %w", err)
+	}
+	lateCtrl, err := oasis.NewController(lateWorker.SocketPath())
+	if err != nil {
+		return fmt.Errorf("failed to connect with the late compute node: %w", err)
+	}
+	defer lateCtrl.Close()
+
+	syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+
+	// Stop one of the checkpoint providers while the late worker is restoring so that it needs
+	// to fail over to the remaining providers.
+	if err = sc.waitStorageStatus(syncCtx, lateCtrl, workerStorage.StatusSyncingCheckpoints); err != nil {
+		return err
+	}
+	sc.Logger.Info("stopping checkpoint provider during restore")
+	if err = provider.Stop(); err != nil {
+		return fmt.Errorf("failed to stop checkpoint provider: %w", err)
+	}
+
+	// The late worker must become eligible for the runtime committee within the timeout.
+	if err = sc.waitComputeRegistration(syncCtx, lateCtrl, lateWorker.NodeID); err != nil {
+		return err
+	}
+
+	sc.Logger.Info("late compute worker registered, restarting checkpoint provider")
+
+	if err = provider.Start(); err != nil {
+		return fmt.Errorf("failed to restart checkpoint provider: %w", err)
+	}
+
+	// Verify that the late worker has the same storage roots as the rest of the network.
+	status, err := lateCtrl.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get late compute worker status: %w", err)
+	}
+	rtStatus, ok := status.Runtimes[KeyValueRuntimeID]
+	if !ok || rtStatus.Storage == nil {
+		return fmt.Errorf("late compute worker is missing runtime storage status")
+	}
+	round := rtStatus.Storage.LastFinalizedRound
+
+	blk, err := ctrl.RuntimeClient.GetBlock(ctx, &runtimeClient.GetBlockRequest{
+		RuntimeID: KeyValueRuntimeID,
+		Round:     round,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %w", round, err)
+	}
+	for _, root := range blk.Header.StorageRoots() {
+		if root.Hash.IsEmpty() {
+			continue
+		}
+		if _, err = lateCtrl.Storage.SyncGet(ctx, &storage.GetRequest{
+			Tree: storage.TreeID{
+				Root:     root,
+				Position: root.Hash,
+			},
+		}); err != nil {
+			return fmt.Errorf("late compute worker is missing root %s at round %d: %w", root, round, err)
+		}
+		sc.Logger.Info("verified restored root",
+			"round", round,
+			"root", root,
+		)
+	}
+
+	// Wait a bit to give the logger in the node time to sync; the message has already been
+	// logged by this point, it just might not be on disk yet.
+	<-time.After(1 * time.Second)
+
+	return sc.Net.CheckLogWatchers()
+}
+
+func (sc *storageSyncCheckpointsImpl) waitStorageStatus(ctx context.Context, ctrl *oasis.Controller, want workerStorage.StorageWorkerStatus) error {
+	sc.Logger.Info("waiting for storage worker status",
+		"status", want,
+	)
+
+	for {
+		status, err := ctrl.GetStatus(ctx)
+		if err == nil {
+			if rtStatus, ok := status.Runtimes[KeyValueRuntimeID]; ok && rtStatus.Storage != nil {
+				if rtStatus.Storage.Status == want {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for storage worker status %s: %w", want, ctx.Err())
+		}
+	}
+}
+
+func (sc *storageSyncCheckpointsImpl) waitComputeRegistration(ctx context.Context, ctrl *oasis.Controller, nodeID signature.PublicKey) error {
+	sc.Logger.Info("waiting for compute worker registration",
+		"node_id", nodeID,
+	)
+
+	for {
+		nd, err := ctrl.Registry.GetNode(ctx, &registry.IDQuery{
+			Height: consensus.HeightLatest,
+			ID:     nodeID,
+		})
+		if err == nil && nd.HasRoles(node.RoleComputeWorker) && nd.HasRuntime(KeyValueRuntimeID) {
+			return nil
+		}
+
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for compute worker %s to register: %w", nodeID, ctx.Err())
+		}
+	}
+}
@@ -244,12 +244,14 @@ func (sc *txSourceImpl) Fixture() (*oasis.NetworkFixture, error) {
 			},
 			DebondingInterval: 2,
 			GasCosts: transaction.Costs{
-				staking.GasOpTransfer:      10,
-				staking.GasOpBurn:          10,
-				staking.GasOpAddEscrow:     10,
-				staking.GasOpReclaimEscrow: 10,
-				staking.GasOpAllow:         10,
-				staking.GasOpWithdraw:      10,
+				staking.GasOpTransfer:                   10,
+				staking.GasOpBurn:                       10,
+				staking.GasOpAddEscrow:                  10,
+				staking.GasOpReclaimEscrow:              10,
+				staking.GasOpAllow:                      10,
+				staking.GasOpWithdraw:                   10,
+				staking.GasOpDelegateFromAllowance:      10,
+				staking.GasOpReclaimEscrowFromAllowance: 10,
 			},
 			MaxAllowances:             32,
 			FeeSplitWeightPropose:     *quantity.NewFromUint64(2),
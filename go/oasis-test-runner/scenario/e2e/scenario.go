@@ -85,6 +85,18 @@ func (sc *Scenario) Run(context.Context, *env.Env) error {
 	return nil
 }
 
+// Preamble implements scenario.Preambler.
+//
+// The preamble starts the network and waits for all nodes to register.
+func (sc *Scenario) Preamble(ctx context.Context, _ *env.Env) error {
+	if err := sc.Net.Start(); err != nil {
+		return err
+	}
+
+	sc.Logger.Info("waiting for nodes to register")
+	return sc.Net.Controller().WaitNodesRegistered(ctx, sc.Net.NumRegisterNodes())
+}
+
 // Fixture implements scenario.Scenario.
 func (sc *Scenario) Fixture() (*oasis.NetworkFixture, error) {
 	nodeBinary, _ := sc.Flags.GetString(cfgNodeBinary)
@@ -139,6 +151,7 @@ func RegisterScenarios() error {
 		NodeUpgradeCancel,
 		NodeUpgradeConsensus240,
 		NodeUpgradeConsensus242,
+		NodeUpgradeConsensus243,
 		// Debonding entries from genesis test.
 		Debond,
 		// Consensus state sync.
@@ -88,6 +88,16 @@ func (sc *seedAPI) Run(ctx context.Context, _ *env.Env) error { // nolint: gocyc
 		return fmt.Errorf("seed node CancelUpgrade should fail with not implemented error")
 	}
 
+	sc.Logger.Info("testing ListPendingUpgrades")
+	if _, err = seedCtrl.ListPendingUpgrades(ctx); !errors.Is(err, control.ErrNotImplemented) {
+		return fmt.Errorf("seed node ListPendingUpgrades should fail with not implemented error")
+	}
+
+	sc.Logger.Info("testing CancelLocalUpgrade")
+	if err = seedCtrl.CancelLocalUpgrade(ctx, "upgrade"); !errors.Is(err, control.ErrNotImplemented) {
+		return fmt.Errorf("seed node CancelLocalUpgrade should fail with not implemented error")
+	}
+
 	// Implemented node controller methods.
 	sc.Logger.Info("testing GetStatus")
 	status, err := seedCtrl.GetStatus(ctx)
@@ -271,6 +271,28 @@ func (c *upgrade242Checker) PostUpgradeFn(ctx context.Context, ctrl *oasis.Contr
 	return nil
 }
 
+type upgrade243Checker struct{}
+
+func (c *upgrade243Checker) PreUpgradeFn(ctx context.Context, ctrl *oasis.Controller) error {
+	return (&upgrade242Checker{}).PreUpgradeFn(ctx, ctrl)
+}
+
+func (c *upgrade243Checker) PostUpgradeFn(ctx context.Context, ctrl *oasis.Controller) error {
+	// Check updated consensus parameters.
+	consParams, err := ctrl.Consensus.GetParameters(ctx, consensus.HeightLatest)
+	if err != nil {
+		return fmt.Errorf("can't get consensus parameters: %w", err)
+	}
+	if consParams.Parameters.FeatureVersion == nil || *consParams.Parameters.FeatureVersion != migrations.Version243 {
+		return fmt.Errorf("consensus parameter FeatureVersion not updated correctly (expected: %s actual: %s)",
+			migrations.Version243,
+			consParams.Parameters.FeatureVersion,
+		)
+	}
+
+	return nil
+}
+
 var (
 	// NodeUpgradeDummy is the node upgrade dummy scenario.
 	NodeUpgradeDummy scenario.Scenario = newNodeUpgradeImpl(migrations.DummyUpgradeHandler, &dummyUpgradeChecker{}, true)
@@ -280,6 +302,8 @@ var (
 	NodeUpgradeConsensus240 scenario.Scenario = newNodeUpgradeImpl(migrations.Consensus240, &upgrade240Checker{}, false)
 	// NodeUpgradeConsensus242 is the node upgrade scenario for migrating to consensus 24.2.
 	NodeUpgradeConsensus242 scenario.Scenario = newNodeUpgradeImpl(migrations.Consensus242, &upgrade242Checker{}, false)
+	// NodeUpgradeConsensus243 is the node upgrade scenario for migrating to consensus 24.3.
+	NodeUpgradeConsensus243 scenario.Scenario = newNodeUpgradeImpl(migrations.Consensus243, &upgrade243Checker{}, false)
 
 	malformedDescriptor = []byte(`{
 		"v": 1,
@@ -547,6 +571,20 @@ func (sc *nodeUpgradeImpl) Run(ctx context.Context, childEnv *env.Env) error { /
 		return err
 	}
 
+	// Validate the upgrade against the current state before submitting it.
+	sc.Logger.Info("dry running the upgrade")
+	result, err := sc.Net.Controller().DryRunUpgrade(ctx, &validDescriptor)
+	if err != nil {
+		return fmt.Errorf("failed to dry run upgrade: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("upgrade dry run failed: %s", result.Error)
+	}
+	sc.Logger.Info("upgrade dry run succeeded",
+		"duration", result.Duration,
+		"parameter_changes", result.ParameterChanges,
+	)
+
 	// Now submit the valid descriptor to all of the validators.
 	sc.Logger.Info("submitting valid upgrade descriptor to all validators")
 	for i, val := range sc.Net.Validators() {
@@ -555,6 +593,19 @@ func (sc *nodeUpgradeImpl) Run(ctx context.Context, childEnv *env.Env) error { /
 			return fmt.Errorf("failed to submit upgrade descriptor to validator %d: %w", i, err)
 		}
 	}
+
+	// Make sure the node reports being ready for the upgrade.
+	status, err := sc.controller.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node status: %w", err)
+	}
+	if n := len(status.UpgradeReadiness); n != 1 {
+		return fmt.Errorf("unexpected number of pending upgrades (expected: 1 got: %d)", n)
+	}
+	if !status.UpgradeReadiness[0].HandlerPresent {
+		return fmt.Errorf("node is not ready for the upgrade")
+	}
+
 	if err = sc.nextEpoch(ctx); err != nil {
 		return err
 	}
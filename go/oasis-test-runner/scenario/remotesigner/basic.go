@@ -3,6 +3,7 @@ package remotesigner
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"time"
 
@@ -127,6 +128,7 @@ func (sc *basicImpl) Run(ctx context.Context, childEnv *env.Env) error {
 	if err != nil {
 		return err
 	}
+	defer sf.(io.Closer).Close()
 
 	// Run basic common signer tests.
 	if err = signerTests.BasicTests(sf, sc.logger, signature.SignerRoles); err != nil {
@@ -150,6 +152,19 @@ func (sc *basicImpl) Run(ctx context.Context, childEnv *env.Env) error {
 		if !pk.Equal(fsi.Public()) {
 			return fmt.Errorf("public key mismatch: %v (expected: %v)", pk, fsi.Public())
 		}
+
+		// Ensure that VRF proofs are only supported for the VRF role.
+		_, err = signature.Prove(si, []byte("remote signer scenario"))
+		switch v {
+		case signature.SignerVRF:
+			if err != nil {
+				return fmt.Errorf("failed to Prove(%v): %w", v, err)
+			}
+		default:
+			if err == nil {
+				return fmt.Errorf("unexpected VRF proof for non-VRF role %v", v)
+			}
+		}
 	}
 
 	return nil
@@ -44,3 +44,14 @@ type Scenario interface {
 	// Run runs the scenario.
 	Run(ctx context.Context, childEnv *env.Env) error
 }
+
+// Preambler is a scenario with a preamble that brings the network into a state which is shared by
+// all scenarios of the same family.
+//
+// Such a state can be snapshotted and used as the starting point of other scenarios.
+type Preambler interface {
+	Scenario
+
+	// Preamble runs the scenario preamble.
+	Preamble(ctx context.Context, childEnv *env.Env) error
+}
@@ -48,6 +48,19 @@ type Status struct {
 
 	// Topics is a set of registered topics together with the number of connected peers.
 	Topics map[string]int `json:"topics"`
+
+	// PeerProtocols is a set of connected peers together with their support for the registered
+	// protocols. Only protocols for which the peer advertises at least one version are included.
+	PeerProtocols map[core.PeerID]map[core.ProtocolID]*ProtocolSupport `json:"peer_protocols,omitempty"`
+}
+
+// ProtocolSupport describes whether a peer supports a registered protocol.
+type ProtocolSupport struct {
+	// Supported is true iff the peer supports the registered protocol version.
+	Supported bool `json:"supported"`
+
+	// Versions is a list of other versions of the protocol advertised by the peer.
+	Versions []core.ProtocolID `json:"versions,omitempty"`
 }
 
 // Service is a P2P node service interface.
@@ -57,7 +57,7 @@ func AddrInfosFromConsensusAddrs(addrs []string) ([]peer.AddrInfo, error) {
 
 		ma, err := addr.Address.MultiAddress()
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert address to multi address (%s): %w", addr, err)
+			return nil, fmt.Errorf("failed to convert address to multi address (%s): %w", &addr, err)
 		}
 
 		// If we already have this peer ID, append to its addresses.
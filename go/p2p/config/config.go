@@ -38,7 +38,8 @@ type BootstrapConfig struct {
 // RegistrationConfig is the P2P registration configuration structure.
 type RegistrationConfig struct {
 	// Address/port(s) to use for P2P connections when registering this node
-	// (if not set, all non-loopback local interfaces will be used).
+	// (if not set, all non-loopback local interfaces will be used). When set,
+	// these are also the only addresses announced to peers.
 	Addresses []string `yaml:"addresses"`
 }
 
@@ -12,6 +12,7 @@ import (
 
 	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/p2p/api"
@@ -43,6 +44,9 @@ type topicHandler struct {
 	cancelRelay pubsub.RelayCancelFunc
 	handler     api.Handler
 
+	// isCommittee is true iff the topic is a runtime committee topic.
+	isCommittee bool
+
 	numWorkers uint64
 
 	pendingQueue chan *rawMessage
@@ -84,6 +88,14 @@ func (h *topicHandler) topicMessageValidator(_ context.Context, _ core.PeerID, e
 		return false
 	}
 
+	// Drop committee messages from other peers when instructed to do so by an injected fault.
+	if h.isCommittee && peerID != h.host.ID() && fault.DropCommitteeMessage() {
+		h.logger.Debug("dropping message from peer due to an injected fault",
+			"peer_id", peerID,
+		)
+		return false
+	}
+
 	// Dispatch the message.  Yes, from the topic validator.  The
 	// default topic validator configuration is asynchronous so
 	// this won't actually block anything, and it saves having to
@@ -263,6 +275,7 @@ func newTopicHandler(p *p2p, topicID string, handler api.Handler) (*topicHandler
 		topic:        topic,
 		host:         p.host,
 		handler:      handler,
+		isCommittee:  protocol.IsTopicKind(topicID, api.TopicKindCommittee),
 		pendingQueue: make(chan *rawMessage, rawMsgQueueSize),
 		logger:       logging.GetLogger("p2p/" + topicID),
 	}
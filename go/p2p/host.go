@@ -13,13 +13,16 @@ import (
 	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
 	"github.com/libp2p/go-libp2p/p2p/net/conngater"
 	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
 	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	"github.com/oasisprotocol/oasis-core/go/p2p/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
 )
 
 // HostConfig describes a set of settings for a host.
@@ -30,6 +33,12 @@ type HostConfig struct {
 	ListenAddr multiaddr.Multiaddr
 	Port       uint16
 
+	// AnnounceAddrs are the addresses announced to peers instead of the listen addresses, if set.
+	AnnounceAddrs []multiaddr.Multiaddr
+
+	// DialRanker is the dial ranker used instead of the default one, if set.
+	DialRanker network.DialRanker
+
 	ConnManagerConfig
 	ConnGaterConfig
 }
@@ -56,14 +65,27 @@ func NewHost(cfg *HostConfig) (host.Host, *conngater.BasicConnectionGater, error
 		return nil, nil, err
 	}
 
-	host, err := libp2p.New(
+	opts := []libp2p.Option{
 		libp2p.UserAgent(cfg.UserAgent),
 		libp2p.ListenAddrs(cfg.ListenAddr),
 		libp2p.Identity(id),
 		libp2p.ResourceManager(rm),
 		libp2p.ConnectionManager(cm),
 		libp2p.ConnectionGater(cg),
-	)
+	}
+	if len(cfg.AnnounceAddrs) > 0 {
+		// Make sure peers only learn about the addresses that are also registered so that they
+		// don't bypass e.g. port forwarding by connecting to the listen addresses directly.
+		opts = append(opts, libp2p.AddrsFactory(func([]multiaddr.Multiaddr) []multiaddr.Multiaddr {
+			return cfg.AnnounceAddrs
+		}))
+	}
+
+	if cfg.DialRanker != nil {
+		opts = append(opts, libp2p.SwarmOpts(swarm.WithDialRanker(cfg.DialRanker)))
+	}
+
+	host, err := libp2p.New(opts...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,6 +112,21 @@ func (cfg *HostConfig) Load() error {
 		return fmt.Errorf("failed to create multiaddress: %w", err)
 	}
 
+	// Announce the registration addresses, if configured.
+	rawAddresses, err := configparser.ParseAddressList(config.GlobalConfig.P2P.Registration.Addresses)
+	if err != nil {
+		return fmt.Errorf("failed to parse address list: %w", err)
+	}
+	var announceAddrs []multiaddr.Multiaddr
+	for _, addr := range rawAddresses {
+		var mAddr multiaddr.Multiaddr
+		mAddr, err = manet.FromNetAddr(addr.ToTCPAddr())
+		if err != nil {
+			return fmt.Errorf("failed to convert address to multiaddress: %w", err)
+		}
+		announceAddrs = append(announceAddrs, mAddr)
+	}
+
 	var cmCfg ConnManagerConfig
 	if err = cmCfg.Load(); err != nil {
 		return fmt.Errorf("failed to load connection manager config: %w", err)
@@ -103,6 +140,7 @@ func (cfg *HostConfig) Load() error {
 	cfg.UserAgent = userAgent
 	cfg.Port = port
 	cfg.ListenAddr = listenAddr
+	cfg.AnnounceAddrs = announceAddrs
 	cfg.ConnManagerConfig = cmCfg
 	cfg.ConnGaterConfig = cgCfg
 
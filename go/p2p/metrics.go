@@ -32,6 +32,13 @@ var (
 		Name: "oasis_p2p_protocols",
 		Help: "Number of supported P2P protocols.",
 	})
+	protocolMismatchesMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_p2p_protocol_mismatches",
+			Help: "Number of identified P2P peers that only support other versions of a registered protocol.",
+		},
+		[]string{"protocol"},
+	)
 
 	p2pCollectors = []prometheus.Collector{
 		peersMetric,
@@ -39,6 +46,7 @@ var (
 		connectionsMetric,
 		topicsMetric,
 		protocolsMetric,
+		protocolMismatchesMetric,
 	}
 
 	metricsOnce sync.Once
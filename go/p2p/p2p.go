@@ -33,7 +33,6 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
 	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
 	registryAPI "github.com/oasisprotocol/oasis-core/go/registry/api"
-	"github.com/oasisprotocol/oasis-core/go/worker/common/configparser"
 )
 
 const (
@@ -71,6 +70,7 @@ type p2p struct {
 	ctxCancel       context.CancelFunc
 	quitCh          chan struct{}
 	metricsClosedCh chan struct{}
+	probeClosedCh   chan struct{}
 
 	chainContext string
 	signer       signature.Signer
@@ -102,6 +102,7 @@ func (p *p2p) Start() error {
 	// However, we can start everything else.
 	p.peerMgr.Start()
 	go p.metricsWorker()
+	go p.protocolProbeWorker()
 
 	return nil
 }
@@ -114,7 +115,7 @@ func (p *p2p) Stop() {
 
 	var wg sync.WaitGroup
 	defer wg.Wait()
-	wg.Add(3)
+	wg.Add(4)
 
 	go func() {
 		defer wg.Done()
@@ -130,6 +131,11 @@ func (p *p2p) Stop() {
 		defer wg.Done()
 		<-p.metricsClosedCh
 	}()
+
+	go func() {
+		defer wg.Done()
+		<-p.probeClosedCh
+	}()
 }
 
 // Implements api.Service.
@@ -157,6 +163,7 @@ func (p *p2p) GetStatus() *api.Status {
 		NumConnections: len(p.host.Network().Conns()),
 		Protocols:      protocols,
 		Topics:         topics,
+		PeerProtocols:  p.peerProtocols(),
 	}
 }
 
@@ -366,8 +373,10 @@ func New(identity *identity.Identity, consensus consensus.Service, store *persis
 		return nil, fmt.Errorf("p2p: failed to load peer config: %w", err)
 	}
 
-	// Create the P2P host.
+	// Create the P2P host, preferring the peer addresses with the highest priority when dialing.
+	priorities := peermgmt.NewAddressPriorities()
 	cfg.HostConfig.Signer = identity.P2PSigner
+	cfg.HostConfig.DialRanker = priorities.DialRanker
 	host, cg, err := NewHost(&cfg.HostConfig)
 	if err != nil {
 		return nil, fmt.Errorf("p2p: failed to initialize libp2p host: %w", err)
@@ -403,7 +412,9 @@ func New(identity *identity.Identity, consensus consensus.Service, store *persis
 	}
 
 	// Initialize the peer manager.
-	opts := make([]peermgmt.PeerManagerOption, 0, 1)
+	opts := []peermgmt.PeerManagerOption{
+		peermgmt.WithAddressPriorities(priorities),
+	}
 
 	if cfg.BootstrapDiscoveryConfig.Enable {
 		seeds := make([]discovery.Discovery, 0, len(cfg.Seeds))
@@ -423,6 +434,7 @@ func New(identity *identity.Identity, consensus consensus.Service, store *persis
 		ctxCancel:         ctxCancel,
 		quitCh:            make(chan struct{}),
 		metricsClosedCh:   make(chan struct{}),
+		probeClosedCh:     make(chan struct{}),
 		chainContext:      chainContext,
 		signer:            identity.P2PSigner,
 		host:              host,
@@ -458,20 +470,6 @@ type Config struct {
 
 // Load loads P2P configuration.
 func (cfg *Config) Load() error {
-	rawAddresses, err := configparser.ParseAddressList(config.GlobalConfig.P2P.Registration.Addresses)
-	if err != nil {
-		return fmt.Errorf("failed to parse address list: %w", err)
-	}
-	var addresses []multiaddr.Multiaddr
-	for _, addr := range rawAddresses {
-		var mAddr multiaddr.Multiaddr
-		mAddr, err = manet.FromNetAddr(addr.ToTCPAddr())
-		if err != nil {
-			return fmt.Errorf("failed to convert address to multiaddress: %w", err)
-		}
-		addresses = append(addresses, mAddr)
-	}
-
 	var hostCfg HostConfig
 	if err := hostCfg.Load(); err != nil {
 		return fmt.Errorf("failed to load host config: %w", err)
@@ -487,7 +485,7 @@ func (cfg *Config) Load() error {
 		return fmt.Errorf("failed to load bootstrap config: %w", err)
 	}
 
-	cfg.Addresses = addresses
+	cfg.Addresses = hostCfg.AnnounceAddrs
 	cfg.HostConfig = hostCfg
 	cfg.GossipSubConfig = gossipSubCfg
 	cfg.BootstrapDiscoveryConfig = bootstrapCfg
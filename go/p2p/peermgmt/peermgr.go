@@ -32,7 +32,8 @@ const (
 
 // PeerManagerOptions are peer manager options.
 type PeerManagerOptions struct {
-	seeds []discovery.Discovery
+	seeds      []discovery.Discovery
+	priorities *AddressPriorities
 }
 
 // PeerManagerOption is a peer manager option setter.
@@ -45,6 +46,14 @@ func WithBootstrapDiscovery(seeds []discovery.Discovery) PeerManagerOption {
 	}
 }
 
+// WithAddressPriorities configures the tracker of the address priorities advertised by peers,
+// which should also be used to rank dials.
+func WithAddressPriorities(priorities *AddressPriorities) PeerManagerOption {
+	return func(opts *PeerManagerOptions) {
+		opts.priorities = priorities
+	}
+}
+
 type watermark struct {
 	// min is the minimum number of peers from the registry we want to have connected.
 	min int
@@ -91,6 +100,9 @@ func NewPeerManager(
 	for _, opt := range opts {
 		opt(&pmo)
 	}
+	if pmo.priorities == nil {
+		pmo.priorities = NewAddressPriorities()
+	}
 
 	l := logging.GetLogger("p2p/peer-manager")
 	cm := h.ConnManager()
@@ -100,7 +112,7 @@ func NewPeerManager(
 		logger:    l,
 		host:      h,
 		pubsub:    ps,
-		registry:  newPeerRegistry(consensus, chainContext),
+		registry:  newPeerRegistry(consensus, chainContext, pmo.priorities),
 		connector: newPeerConnector(h, g),
 		tagger:    newPeerTagger(cm),
 		backup:    newPeerstoreBackup(h.Peerstore(), cstore),
@@ -0,0 +1,121 @@
+package peermgmt
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+)
+
+// addressFallbackDelay is the delay after which the addresses with the next lower priority are
+// dialed in case the more preferred addresses of a peer neither succeeded nor failed.
+const addressFallbackDelay = time.Second
+
+// unknownAddressPriority is the priority of addresses that were not advertised with a priority,
+// e.g. addresses learned via discovery. These are the least preferred.
+const unknownAddressPriority = math.MaxUint8 + 1
+
+// AddressPriorities tracks the priorities of the addresses advertised by peers in their node
+// descriptors so that dials prefer the addresses with the highest priority and only fall back to
+// the remaining addresses when the preferred ones can't be reached.
+type AddressPriorities struct {
+	mu sync.RWMutex
+
+	priorities map[string]uint8
+	peerAddrs  map[core.PeerID][]string
+}
+
+// NewAddressPriorities creates a new address priority tracker.
+func NewAddressPriorities() *AddressPriorities {
+	return &AddressPriorities{
+		priorities: make(map[string]uint8),
+		peerAddrs:  make(map[core.PeerID][]string),
+	}
+}
+
+// DialRanker ranks the given addresses by priority. Addresses with the same priority are ranked
+// by the default dial ranker and are dialed after all more preferred addresses, delayed by the
+// fallback delay for each less preferred priority level.
+//
+// Note that the swarm dials the next addresses immediately once all dials in flight have failed,
+// so the delay only applies when the preferred addresses are unresponsive.
+func (ap *AddressPriorities) DialRanker(addrs []multiaddr.Multiaddr) []network.AddrDelay {
+	groups := make(map[int][]multiaddr.Multiaddr)
+
+	ap.mu.RLock()
+	for _, addr := range addrs {
+		priority := unknownAddressPriority
+		if p, ok := ap.priorities[string(addr.Bytes())]; ok {
+			priority = int(p)
+		}
+		groups[priority] = append(groups[priority], addr)
+	}
+	ap.mu.RUnlock()
+
+	if len(groups) <= 1 {
+		return swarm.DefaultDialRanker(addrs)
+	}
+
+	levels := make([]int, 0, len(groups))
+	for level := range groups {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+
+	ranked := make([]network.AddrDelay, 0, len(addrs))
+	for i, level := range levels {
+		offset := time.Duration(i) * addressFallbackDelay
+		for _, ad := range swarm.DefaultDialRanker(groups[level]) {
+			ad.Delay += offset
+			ranked = append(ranked, ad)
+		}
+	}
+	return ranked
+}
+
+// set replaces the address priorities of the given peer with the ones advertised in its P2P info.
+func (ap *AddressPriorities) set(p core.PeerID, pi *node.P2PInfo) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	ap.removeLocked(p)
+
+	if len(pi.AddressPriorities) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(pi.Addresses))
+	for i, nodeAddr := range pi.Addresses {
+		addr, err := manet.FromNetAddr(nodeAddr.ToTCPAddr())
+		if err != nil {
+			continue
+		}
+		key := string(addr.Bytes())
+		ap.priorities[key] = pi.AddressPriority(i)
+		keys = append(keys, key)
+	}
+	ap.peerAddrs[p] = keys
+}
+
+// clear removes the address priorities of all peers.
+func (ap *AddressPriorities) clear() {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	ap.priorities = make(map[string]uint8)
+	ap.peerAddrs = make(map[core.PeerID][]string)
+}
+
+func (ap *AddressPriorities) removeLocked(p core.PeerID) {
+	for _, key := range ap.peerAddrs[p] {
+		delete(ap.priorities, key)
+	}
+	delete(ap.peerAddrs, p)
+}
@@ -0,0 +1,167 @@
+package peermgmt
+
+import (
+	"context"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
+	"github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/p2p/api"
+)
+
+type AddressPrioritiesTestSuite struct {
+	suite.Suite
+
+	signer signature.Signer
+	pid    peer.ID
+
+	// The peer listens on two addresses.
+	host        host.Host
+	listenAddrs []node.Address
+}
+
+func TestAddressPrioritiesTestSuite(t *testing.T) {
+	suite.Run(t, new(AddressPrioritiesTestSuite))
+}
+
+func (s *AddressPrioritiesTestSuite) SetupSuite() {
+	require := require.New(s.T())
+
+	var err error
+	s.signer, err = memory.NewFactory().Generate(signature.SignerP2P, rand.Reader)
+	require.NoError(err, "Generate failed")
+	s.pid, err = api.PublicKeyToPeerID(s.signer.Public())
+	require.NoError(err, "PublicKeyToPeerID failed")
+
+	s.host, err = libp2p.New(
+		libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0", "/ip4/127.0.0.1/tcp/0"),
+		libp2p.Identity(api.SignerToPrivKey(s.signer)),
+	)
+	require.NoError(err, "libp2p.New failed")
+
+	for _, addr := range s.host.Addrs() {
+		netAddr, err := manet.ToNetAddr(addr)
+		require.NoError(err, "ToNetAddr failed")
+
+		var listenAddr node.Address
+		err = listenAddr.FromIP(netAddr.(*net.TCPAddr).IP, uint16(netAddr.(*net.TCPAddr).Port))
+		require.NoError(err, "FromIP failed")
+		s.listenAddrs = append(s.listenAddrs, listenAddr)
+	}
+	require.Len(s.listenAddrs, 2)
+}
+
+func (s *AddressPrioritiesTestSuite) TearDownSuite() {
+	require.NoError(s.T(), s.host.Close(), "Close failed")
+}
+
+func (s *AddressPrioritiesTestSuite) TestDialRanker() {
+	require := require.New(s.T())
+
+	info := node.P2PInfo{
+		ID: s.signer.Public(),
+		Addresses: []node.Address{
+			{IP: net.ParseIP("127.0.0.1"), Port: 1000},
+			{IP: net.ParseIP("127.0.0.1"), Port: 1001},
+		},
+		AddressPriorities: []uint8{1, 0},
+	}
+	other := node.Address{IP: net.ParseIP("127.0.0.1"), Port: 1002}
+
+	var addrs []multiaddr.Multiaddr
+	for _, addr := range append(info.Addresses, other) {
+		ma, err := addr.MultiAddress()
+		require.NoError(err, "MultiAddress failed")
+		addrs = append(addrs, ma)
+	}
+
+	ap := NewAddressPriorities()
+	require.Equal(swarm.DefaultDialRanker(addrs), ap.DialRanker(addrs), "addresses without priorities should use the default ranker")
+
+	ap.set(s.pid, &info)
+	delays := make(map[string]time.Duration)
+	for _, ad := range ap.DialRanker(addrs) {
+		delays[ad.Addr.String()] = ad.Delay
+	}
+	require.Len(delays, len(addrs))
+	require.Less(delays[addrs[1].String()], delays[addrs[0].String()], "preferred address should be dialed first")
+	require.GreaterOrEqual(delays[addrs[0].String()]-delays[addrs[1].String()], addressFallbackDelay)
+	require.Less(delays[addrs[0].String()], delays[addrs[2].String()], "addresses without priorities should be dialed last")
+
+	// Updating the node without priorities should remove them.
+	info.AddressPriorities = nil
+	ap.set(s.pid, &info)
+	require.Equal(swarm.DefaultDialRanker(addrs), ap.DialRanker(addrs))
+
+	info.AddressPriorities = []uint8{1, 0}
+	ap.set(s.pid, &info)
+	ap.clear()
+	require.Equal(swarm.DefaultDialRanker(addrs), ap.DialRanker(addrs))
+}
+
+func (s *AddressPrioritiesTestSuite) TestDialFallback() {
+	require := require.New(s.T())
+
+	// An address at which the peer can't be reached.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen failed")
+	closedAddr := node.Address{IP: net.ParseIP("127.0.0.1"), Port: int64(l.Addr().(*net.TCPAddr).Port)}
+	require.NoError(l.Close(), "Close failed")
+
+	connect := func(info *node.P2PInfo) (host.Host, string, time.Duration) {
+		ap := NewAddressPriorities()
+		h, err := libp2p.New(
+			libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"),
+			libp2p.SwarmOpts(swarm.WithDialRanker(ap.DialRanker)),
+		)
+		require.NoError(err, "libp2p.New failed")
+
+		ai, err := p2pInfoToAddrInfo(info)
+		require.NoError(err, "p2pInfoToAddrInfo failed")
+		ap.set(ai.ID, info)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		err = h.Connect(ctx, *ai)
+		require.NoError(err, "Connect failed")
+		elapsed := time.Since(start)
+
+		conns := h.Network().ConnsToPeer(ai.ID)
+		require.Len(conns, 1)
+		return h, conns[0].RemoteMultiaddr().String(), elapsed
+	}
+
+	// The preferred address should be used when the peer is reachable at all addresses.
+	h, remote, _ := connect(&node.P2PInfo{
+		ID:                s.signer.Public(),
+		Addresses:         s.listenAddrs,
+		AddressPriorities: []uint8{1, 0},
+	})
+	defer h.Close()
+	require.Equal(s.listenAddrs[1].MultiAddressStr(), remote, "preferred address should be used")
+
+	// Less preferred addresses should be dialed immediately when the preferred one fails.
+	h, remote, elapsed := connect(&node.P2PInfo{
+		ID:                s.signer.Public(),
+		Addresses:         []node.Address{closedAddr, s.listenAddrs[0]},
+		AddressPriorities: []uint8{0, 1},
+	})
+	defer h.Close()
+	require.Equal(s.listenAddrs[0].MultiAddressStr(), remote, "fallback address should be used")
+	require.Less(elapsed, addressFallbackDelay, "fallback should not wait for the fallback delay")
+}
@@ -21,6 +21,7 @@ type peerRegistry struct {
 
 	consensus    consensus.Service
 	chainContext string
+	priorities   *AddressPriorities
 
 	mu            sync.Mutex
 	peers         map[core.PeerID]peer.AddrInfo
@@ -33,13 +34,14 @@ type peerRegistry struct {
 	startOne cmSync.One
 }
 
-func newPeerRegistry(consensus consensus.Service, chainContext string) *peerRegistry {
+func newPeerRegistry(consensus consensus.Service, chainContext string, priorities *AddressPriorities) *peerRegistry {
 	l := logging.GetLogger("p2p/peer-manager/registry")
 
 	return &peerRegistry{
 		logger:        l,
 		consensus:     consensus,
 		chainContext:  chainContext,
+		priorities:    priorities,
 		peers:         make(map[core.PeerID]peer.AddrInfo),
 		protocolPeers: make(map[core.ProtocolID]map[core.PeerID]struct{}),
 		topicPeers:    make(map[string]map[core.PeerID]struct{}),
@@ -181,6 +183,7 @@ func (r *peerRegistry) clearNodes() {
 	r.peers = make(map[core.PeerID]peer.AddrInfo)
 	r.protocolPeers = make(map[core.ProtocolID]map[core.PeerID]struct{})
 	r.topicPeers = make(map[string]map[core.PeerID]struct{})
+	r.priorities.clear()
 }
 
 // handleNodes updates protocols and topics supported by the given nodes and resets them if needed.
@@ -206,6 +209,7 @@ func (r *peerRegistry) handleNodes(nodes []*node.Node) {
 			continue
 		}
 
+		r.priorities.set(info.ID, &n.P2P) //nolint:gosec
 		protocols, topics := r.inspectNode(n)
 
 		peers[info.ID] = &peerData{*info, protocols, topics}
@@ -274,7 +278,8 @@ func p2pInfoToAddrInfo(pi *node.P2PInfo) (*peer.AddrInfo, error) {
 	if ai.ID, err = api.PublicKeyToPeerID(pi.ID); err != nil {
 		return nil, fmt.Errorf("failed to extract public key from node P2P ID: %w", err)
 	}
-	for _, nodeAddr := range pi.Addresses {
+	// Order the addresses by priority so that the most preferred ones are tried first.
+	for _, nodeAddr := range pi.SortedAddresses() {
 		addr, err := manet.FromNetAddr(nodeAddr.ToTCPAddr())
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert address to libp2p format: %w", err)
@@ -0,0 +1,116 @@
+package p2p
+
+import (
+	"slices"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/event"
+
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
+	"github.com/oasisprotocol/oasis-core/go/p2p/api"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+)
+
+// protocolProbeWorker checks which of the registered protocols are supported by peers once they
+// complete the identification handshake and records any protocol version mismatches.
+func (p *p2p) protocolProbeWorker() {
+	defer close(p.probeClosedCh)
+
+	sub, err := p.host.EventBus().Subscribe([]any{
+		new(event.EvtPeerIdentificationCompleted),
+		new(event.EvtPeerProtocolsUpdated),
+	})
+	if err != nil {
+		p.logger.Error("failed to subscribe to peer protocol updates",
+			"err", err,
+		)
+		return
+	}
+	defer sub.Close()
+
+	hb := heartbeat.Register("p2p/probe")
+	defer hb.Stop()
+	hbTicker := time.NewTicker(heartbeat.Interval)
+	defer hbTicker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-hbTicker.C:
+			hb.Beat()
+		case ev, ok := <-sub.Out():
+			if !ok {
+				return
+			}
+
+			switch evt := ev.(type) {
+			case event.EvtPeerIdentificationCompleted:
+				p.probePeer(evt.Peer)
+			case event.EvtPeerProtocolsUpdated:
+				p.probePeer(evt.Peer)
+			}
+		}
+	}
+}
+
+// probePeer checks whether the given peer supports the registered protocols.
+func (p *p2p) probePeer(peerID core.PeerID) {
+	protocols, err := p.host.Peerstore().GetProtocols(peerID)
+	if err != nil {
+		p.logger.Debug("failed to get peer's protocols",
+			"err", err,
+			"peer_id", peerID,
+		)
+		return
+	}
+
+	for _, pid := range p.peerMgr.Protocols() {
+		if slices.Contains(protocols, pid) {
+			continue
+		}
+		versions := rpc.ProtocolVersions(pid, protocols)
+		if len(versions) == 0 {
+			continue
+		}
+
+		protocolMismatchesMetric.WithLabelValues(string(pid)).Inc()
+
+		p.logger.Debug("peer does not support the registered protocol version",
+			"peer_id", peerID,
+			"protocol", pid,
+			"peer_versions", versions,
+		)
+	}
+}
+
+// peerProtocols returns the support for the registered protocols of all connected peers.
+func (p *p2p) peerProtocols() map[core.PeerID]map[core.ProtocolID]*api.ProtocolSupport {
+	registered := p.peerMgr.Protocols()
+
+	peerProtocols := make(map[core.PeerID]map[core.ProtocolID]*api.ProtocolSupport)
+	for _, peerID := range p.host.Network().Peers() {
+		protocols, err := p.host.Peerstore().GetProtocols(peerID)
+		if err != nil {
+			continue
+		}
+
+		support := make(map[core.ProtocolID]*api.ProtocolSupport)
+		for _, pid := range registered {
+			supported := slices.Contains(protocols, pid)
+			versions := rpc.ProtocolVersions(pid, protocols)
+			if !supported && len(versions) == 0 {
+				continue
+			}
+			support[pid] = &api.ProtocolSupport{
+				Supported: supported,
+				Versions:  versions,
+			}
+		}
+		if len(support) > 0 {
+			peerProtocols[peerID] = support
+		}
+	}
+	return peerProtocols
+}
@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/libp2p/go-libp2p/core"
@@ -58,6 +59,12 @@ func NewTopicIDForRuntime(chainContext string, runtimeID common.Namespace, kind
 	return fmt.Sprintf("oasis/%s/%s/%s/%s", chainContext, kind, runtimeID.String(), version.MaskNonMajor())
 }
 
+// IsTopicKind returns true iff the given topic identifier is a runtime topic of the given kind.
+func IsTopicKind(topic string, kind api.TopicKind) bool {
+	parts := strings.Split(topic, "/")
+	return len(parts) == 5 && parts[0] == "oasis" && parts[2] == string(kind)
+}
+
 // NewTopicKindTxID constructs topic id from the given parameters.
 func NewTopicKindTxID(chainContext string, runtimeID common.Namespace) string {
 	return NewTopicIDForRuntime(chainContext, runtimeID, api.TopicKindTx, version.RuntimeCommitteeProtocol)
@@ -56,6 +56,22 @@ func TestProtocolID(t *testing.T) {
 		require.Equal(expected, NewTopicIDForRuntime(chainContext, runtimeID, kind, version))
 	})
 
+	t.Run("IsTopicKind", func(t *testing.T) {
+		require := require.New(t)
+
+		var runtimeID common.Namespace
+		err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+		require.NoError(err, "failed to unmarshal runtime id")
+
+		committeeTopic := NewTopicKindCommitteeID(chainContext, runtimeID)
+		txTopic := NewTopicKindTxID(chainContext, runtimeID)
+
+		require.True(IsTopicKind(committeeTopic, api.TopicKindCommittee))
+		require.False(IsTopicKind(committeeTopic, api.TopicKindTx))
+		require.True(IsTopicKind(txTopic, api.TopicKindTx))
+		require.False(IsTopicKind("committee", api.TopicKindCommittee))
+	})
+
 	registry = newProtocolRegistry()
 
 	t.Run("ValidateProtocolID", func(_ *testing.T) {
@@ -316,6 +316,10 @@ func (c *client) CallOne(
 	var pf PeerFeedback
 	tryPeers := func() error {
 		// Iterate through the list of peers and attempt to execute the request.
+		var (
+			notSupported     int
+			notSupportedErrs error
+		)
 		for _, peer := range peers {
 			c.logger.Debug("trying peer",
 				"method", method,
@@ -325,6 +329,10 @@ func (c *client) CallOne(
 			var err error
 			pf, err = c.timeCall(ctx, peer, &request, rsp, co.maxPeerResponseTime)
 			if err != nil {
+				if errors.Is(err, ErrProtocolNotSupported) {
+					notSupported++
+					notSupportedErrs = errors.Join(notSupportedErrs, fmt.Errorf("peer %s: %w", peer, err))
+				}
 				continue
 			}
 			if co.validationFn != nil {
@@ -346,6 +354,11 @@ func (c *client) CallOne(
 			"method", method,
 		)
 
+		// Fail fast in case none of the peers support the protocol as retrying cannot help.
+		if notSupported == len(peers) {
+			return backoff.Permanent(fmt.Errorf("call failed on all peers: %w", notSupportedErrs))
+		}
+
 		return fmt.Errorf("call failed on all peers")
 	}
 
@@ -482,6 +495,12 @@ func (c *client) call(
 	rsp any,
 	maxPeerResponseTime time.Duration,
 ) error {
+	// Fail fast in case the peer is known not to support the protocol instead of waiting for the
+	// stream negotiation to fail.
+	if err := CheckProtocolSupport(c.host.Peerstore(), peerID, c.protocolID); err != nil {
+		return err
+	}
+
 	// Attempt to open stream to the given peer.
 	stream, err := c.host.NewStream(
 		ctx,
@@ -604,7 +623,12 @@ func (c *client) recordBadPeer(peerID core.PeerID) {
 
 func retryFn(ctx context.Context, fn func() error, maxRetries uint64, retryInterval time.Duration) error {
 	if maxRetries == 0 {
-		return fn()
+		err := fn()
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) {
+			return permanent.Err
+		}
+		return err
 	}
 
 	retry := backoff.WithMaxRetries(backoff.NewConstantBackOff(retryInterval), maxRetries)
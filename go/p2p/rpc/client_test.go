@@ -276,6 +276,34 @@ func (s *RPCTestSuite) TestCallMulti() {
 	})
 }
 
+func (s *RPCTestSuite) TestProtocolNotSupported() {
+	require := require.New(s.T())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Wait for the servers to be identified.
+	peers := make([]peer.ID, 0, len(s.serverHosts))
+	for _, h := range s.serverHosts {
+		peers = append(peers, h.ID())
+		require.Eventually(func() bool {
+			protocols, err := s.clientHost.Peerstore().GetProtocols(h.ID())
+			return err == nil && len(protocols) > 0
+		}, time.Second, 10*time.Millisecond, "server should be identified")
+	}
+
+	client := NewClient(s.clientHost, core.ProtocolID("p2p/rpc/test/2.0.0"))
+
+	var rsp testResponse
+	_, err := client.Call(ctx, peers[2], testMethod, &testRequest{}, &rsp)
+	require.ErrorIs(err, ErrProtocolNotSupported, "Call should fail fast")
+	require.ErrorContains(err, string(testProtocol), "error should include the advertised versions")
+
+	_, err = client.CallOne(ctx, peers, testMethod, &testRequest{}, &rsp, WithMaxRetries(10))
+	require.ErrorIs(err, ErrProtocolNotSupported, "CallOne should fail fast")
+	require.NoError(ctx.Err(), "CallOne should not retry")
+}
+
 func (s *RPCTestSuite) TestListener() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+)
+
+// ProtocolVersions returns all versions of the given protocol found in the given list of
+// protocols, excluding the given protocol itself.
+//
+// Protocol identifiers are expected to end with the protocol version, so two protocols are
+// considered versions of the same protocol if they only differ in the last path component.
+func ProtocolVersions(p protocol.ID, protocols []protocol.ID) []protocol.ID {
+	idx := strings.LastIndex(string(p), "/")
+	if idx < 0 {
+		return nil
+	}
+	prefix := string(p[:idx+1])
+
+	var versions []protocol.ID
+	for _, other := range protocols {
+		if other == p || !strings.HasPrefix(string(other), prefix) {
+			continue
+		}
+		if strings.Contains(string(other[len(prefix):]), "/") {
+			continue
+		}
+		versions = append(versions, other)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// CheckProtocolSupport checks whether the given peer supports the given protocol based on the
+// protocols advertised by the peer during identification.
+//
+// Peers which have not yet been identified are assumed to support the protocol. In case the peer
+// does not support the protocol, the returned error includes the versions of the protocol that
+// the peer advertises.
+func CheckProtocolSupport(ps peerstore.Peerstore, peerID core.PeerID, p protocol.ID) error {
+	protocols, err := ps.GetProtocols(peerID)
+	if err != nil || len(protocols) == 0 {
+		return nil
+	}
+	if slices.Contains(protocols, p) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (peer supports: %v)", ErrProtocolNotSupported, p, ProtocolVersions(p, protocols))
+}
@@ -0,0 +1,29 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolVersions(t *testing.T) {
+	require := require.New(t)
+
+	p := protocol.ID("/oasis/chain/committee/runtime/2.0.0")
+	protocols := []protocol.ID{
+		"/oasis/chain/committee/runtime/3.0.0",
+		"/oasis/chain/committee/runtime/2.0.0",
+		"/oasis/chain/committee/runtime/1.0.0",
+		"/oasis/chain/committee/other/1.0.0",
+		"/oasis/chain/committee/runtime/1.0.0/extra",
+		"/ipfs/id/1.0.0",
+	}
+
+	require.Equal([]protocol.ID{
+		"/oasis/chain/committee/runtime/1.0.0",
+		"/oasis/chain/committee/runtime/3.0.0",
+	}, ProtocolVersions(p, protocols))
+	require.Empty(ProtocolVersions(p, nil))
+	require.Empty(ProtocolVersions("unversioned", protocols))
+}
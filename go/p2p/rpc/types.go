@@ -16,6 +16,9 @@ var (
 
 	// ErrBadRequest is an error raised when a given request is malformed.
 	ErrBadRequest = errors.New(ModuleName, 2, "rpc: bad request")
+
+	// ErrProtocolNotSupported is an error raised when a peer does not support the protocol.
+	ErrProtocolNotSupported = errors.New(ModuleName, 3, "rpc: protocol not supported")
 )
 
 // Request is a request sent by the client.
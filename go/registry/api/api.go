@@ -21,6 +21,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
 )
 
@@ -120,6 +121,17 @@ var (
 	// has runtimes.
 	ErrEntityHasRuntimes = errors.New(ModuleName, 19, "registry: entity still has runtimes")
 
+	// ErrNodeSoftwareVersionTooOld is the error returned when a node's software version is below
+	// the minimum node software version required by a runtime.
+	ErrNodeSoftwareVersionTooOld = errors.New(ModuleName, 20, "registry: node software version too old")
+
+	// ErrNoSuchNodeAuthorization is the error returned when a node authorization does not exist.
+	ErrNoSuchNodeAuthorization = errors.New(ModuleName, 21, "registry: no such node authorization")
+
+	// ErrNodeAuthorizationExpired is the error returned when a node registers using an expired
+	// authorization of its entity.
+	ErrNodeAuthorizationExpired = errors.New(ModuleName, 22, "registry: node authorization expired")
+
 	// MethodRegisterEntity is the method name for entity registrations.
 	MethodRegisterEntity = transaction.NewMethodName(ModuleName, "RegisterEntity", entity.SignedEntity{})
 	// MethodDeregisterEntity is the method name for entity deregistrations.
@@ -132,6 +144,10 @@ var (
 	MethodRegisterRuntime = transaction.NewMethodName(ModuleName, "RegisterRuntime", Runtime{})
 	// MethodProveFreshness is the method name for freshness proofs.
 	MethodProveFreshness = transaction.NewMethodName(ModuleName, "ProveFreshness", [32]byte{})
+	// MethodAuthorizeNode is the method name for node authorizations.
+	MethodAuthorizeNode = transaction.NewMethodName(ModuleName, "AuthorizeNode", AuthorizeNode{})
+	// MethodRevokeNodeAuthorization is the method name for node authorization revocations.
+	MethodRevokeNodeAuthorization = transaction.NewMethodName(ModuleName, "RevokeNodeAuthorization", RevokeNodeAuthorization{})
 
 	// Methods is the list of all methods supported by the registry backend.
 	Methods = []transaction.MethodName{
@@ -141,6 +157,8 @@ var (
 		MethodUnfreezeNode,
 		MethodRegisterRuntime,
 		MethodProveFreshness,
+		MethodAuthorizeNode,
+		MethodRevokeNodeAuthorization,
 	}
 
 	// RuntimesRequiredRoles are the Node roles that require runtimes.
@@ -164,6 +182,9 @@ var (
 		node.RoleStorageRPC
 )
 
+// MaxNodeAddresses is the maximum number of consensus or P2P addresses a node can advertise.
+const MaxNodeAddresses = 16
+
 // Backend is a registry implementation.
 type Backend interface {
 	// GetEntity gets an entity by ID.
@@ -185,6 +206,9 @@ type Backend interface {
 	// GetNodes gets a list of all registered nodes.
 	GetNodes(context.Context, int64) ([]*node.Node, error)
 
+	// GetNodeAuthorizations returns the node authorizations of the given entity.
+	GetNodeAuthorizations(context.Context, *IDQuery) ([]*NodeAuthorization, error)
+
 	// GetNodeByConsensusAddress looks up a node by its consensus address at the
 	// specified block height. The nature and format of the consensus address depends
 	// on the specific consensus backend implementation used.
@@ -224,6 +248,12 @@ type Backend interface {
 
 	// ConsensusParameters returns the registry consensus parameters.
 	ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error)
+
+	// ValidateRuntimeUpdate verifies that the given runtime descriptor would be accepted when
+	// registering it at the latest height, without submitting any transaction.
+	//
+	// Checks that depend on the transaction signer are not performed.
+	ValidateRuntimeUpdate(ctx context.Context, rt *Runtime) error
 }
 
 // IDQuery is a registry query by ID.
@@ -292,6 +322,16 @@ func NewProveFreshnessTx(nonce uint64, fee *transaction.Fee, blob [32]byte) *tra
 	return transaction.NewTransaction(nonce, fee, MethodProveFreshness, blob)
 }
 
+// NewAuthorizeNodeTx creates a new authorize node transaction.
+func NewAuthorizeNodeTx(nonce uint64, fee *transaction.Fee, authorize *AuthorizeNode) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodAuthorizeNode, authorize)
+}
+
+// NewRevokeNodeAuthorizationTx creates a new revoke node authorization transaction.
+func NewRevokeNodeAuthorizationTx(nonce uint64, fee *transaction.Fee, revoke *RevokeNodeAuthorization) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodRevokeNodeAuthorization, revoke)
+}
+
 // EntityEvent is the event that is returned via WatchEntities to signify
 // entity registration changes and updates.
 type EntityEvent struct {
@@ -379,6 +419,8 @@ type Event struct {
 	EntityEvent           *EntityEvent           `json:"entity,omitempty"`
 	NodeEvent             *NodeEvent             `json:"node,omitempty"`
 	NodeUnfrozenEvent     *NodeUnfrozenEvent     `json:"node_unfrozen,omitempty"`
+
+	NodeAuthorizationEvent *NodeAuthorizationEvent `json:"node_authorization,omitempty"`
 }
 
 // NodeList is a per-epoch immutable node list.
@@ -398,6 +440,9 @@ type NodeLookup interface {
 	// GetEntityNodes returns nodes registered by given entity.
 	// Note that this returns both active and expired nodes.
 	GetEntityNodes(ctx context.Context, id signature.PublicKey) ([]*node.Node, error)
+
+	// NodeAuthorization looks up the given entity's authorization for the given node.
+	NodeAuthorization(ctx context.Context, entityID, nodeID signature.PublicKey) (*NodeAuthorization, error)
 }
 
 // RuntimeLookup interface implements various ways for the verification
@@ -481,6 +526,48 @@ func VerifyRegisterEntityArgs(logger *logging.Logger, sigEnt *entity.SignedEntit
 	return &ent, nil
 }
 
+// verifyNodeAuthorized verifies that the node is authorized to register on behalf of the given
+// entity, either by being included in the entity descriptor's node list or by an unexpired node
+// authorization.
+func verifyNodeAuthorized(
+	ctx context.Context,
+	logger *logging.Logger,
+	entity *entity.Entity,
+	n *node.Node,
+	epoch beacon.EpochTime,
+	nodeLookup NodeLookup,
+) error {
+	if entity.HasNode(n.ID) {
+		return nil
+	}
+
+	auth, err := nodeLookup.NodeAuthorization(ctx, entity.ID, n.ID)
+	switch err {
+	case nil:
+	case ErrNoSuchNodeAuthorization:
+		logger.Debug("RegisterNode: node public key not found in entity's node list",
+			"node", n,
+		)
+		return fmt.Errorf("%w: node public key not found in entity's node list", ErrInvalidArgument)
+	default:
+		logger.Error("RegisterNode: failed to query node authorization",
+			"err", err,
+			"node", n,
+		)
+		return fmt.Errorf("failed to lookup node authorization: %w", err)
+	}
+
+	if auth.IsExpired(epoch) {
+		logger.Debug("RegisterNode: node authorization expired",
+			"node", n,
+			"expiration", auth.Expiration,
+			"epoch", epoch,
+		)
+		return fmt.Errorf("%w: authorization expired in epoch %d", ErrNodeAuthorizationExpired, auth.Expiration)
+	}
+	return nil
+}
+
 // VerifyRegisterNodeArgs verifies arguments for RegisterNode.
 //
 // Returns the node descriptor and a list of runtime descriptors the node is registering for.
@@ -544,12 +631,10 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 		return nil, nil, fmt.Errorf("%w: registration not signed by node identity", ErrInvalidArgument)
 	}
 	expectedSigners = append(expectedSigners, n.ID)
-	if !entity.HasNode(n.ID) && (!isSanityCheck || isGenesis) {
-		logger.Debug("RegisterNode: node public key not found in entity's node list",
-			"signed_node", sigNode,
-			"node", n,
-		)
-		return nil, nil, fmt.Errorf("%w: node public key not found in entity's node list", ErrInvalidArgument)
+	if !isSanityCheck || isGenesis {
+		if err := verifyNodeAuthorized(ctx, logger, entity, &n, epoch, nodeLookup); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Expired registrations are allowed here because this routine is abused
@@ -622,6 +707,19 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 				return nil, nil, err
 			}
 
+			// Enforce the runtime's minimum node software version. Similar to the attestation
+			// checks above, this is skipped at genesis and during sanity checks as the constraint
+			// may have been raised after the node registered. Such nodes are not elected anyway.
+			if err := regRt.VerifyNodeSoftwareVersion(n.SoftwareVersion); err != nil && !isSanityCheck && !isGenesis {
+				logger.Error("RegisterNode: node software version too old",
+					"err", err,
+					"node_id", n.ID,
+					"runtime_id", rt.ID,
+					"software_version", n.SoftwareVersion,
+				)
+				return nil, nil, err
+			}
+
 			// Enforce what kinds of runtimes are allowed.
 			if regRt.Kind == KindKeyManager && !n.HasRoles(KeyManagerRuntimeAllowedRoles) {
 				return nil, nil, fmt.Errorf("%w: key manager runtime not allowed", ErrInvalidArgument)
@@ -695,6 +793,22 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 		return nil, nil, fmt.Errorf("%w: registration not signed by TLS certificate key", ErrInvalidArgument)
 	}
 	expectedSigners = append(expectedSigners, n.TLS.PubKey)
+	if n.TLS.NextPubKey != nil {
+		if !n.TLS.NextPubKey.IsValid() || n.TLS.NextPubKey.Equal(n.TLS.PubKey) {
+			logger.Error("RegisterNode: invalid next TLS public key",
+				"node", n,
+			)
+			return nil, nil, fmt.Errorf("%w: invalid next TLS public key", ErrInvalidArgument)
+		}
+		if !sigNode.MultiSigned.IsSignedBy(*n.TLS.NextPubKey) {
+			logger.Error("RegisterNode: not signed by next TLS certificate key",
+				"signed_node", sigNode,
+				"node", n,
+			)
+			return nil, nil, fmt.Errorf("%w: registration not signed by next TLS certificate key", ErrInvalidArgument)
+		}
+		expectedSigners = append(expectedSigners, *n.TLS.NextPubKey)
+	}
 
 	// Validate P2PInfo.
 	if !n.P2P.ID.IsValid() {
@@ -751,6 +865,9 @@ func VerifyRegisterNodeArgs( // nolint: gocyclo
 		{"TLS public key", n.TLS.PubKey},
 		{"VRF ID", n.VRF.ID},
 	}
+	if n.TLS.NextPubKey != nil {
+		subKeys = append(subKeys, nodeSubKey{"next TLS public key", *n.TLS.NextPubKey})
+	}
 
 	for _, subKey := range subKeys {
 		subKeyDedup[subKey.id] = true
@@ -894,6 +1011,37 @@ func verifyAddresses(params *ConsensusParameters, addressRequired bool, addresse
 	return nil
 }
 
+// VerifyNodeAddressLimits verifies that the node does not advertise too many or duplicate
+// consensus and P2P addresses and that the P2P address priorities match the P2P addresses.
+func VerifyNodeAddressLimits(n *node.Node) error {
+	if len(n.Consensus.Addresses) > MaxNodeAddresses {
+		return fmt.Errorf("%w: too many consensus addresses", ErrInvalidArgument)
+	}
+	for i, v := range n.Consensus.Addresses {
+		for _, other := range n.Consensus.Addresses[:i] {
+			if v.Equal(&other) {
+				return fmt.Errorf("%w: duplicate consensus address", ErrInvalidArgument)
+			}
+		}
+	}
+
+	if len(n.P2P.Addresses) > MaxNodeAddresses {
+		return fmt.Errorf("%w: too many P2P addresses", ErrInvalidArgument)
+	}
+	for i, v := range n.P2P.Addresses {
+		for _, other := range n.P2P.Addresses[:i] {
+			if v.Equal(&other) {
+				return fmt.Errorf("%w: duplicate P2P address", ErrInvalidArgument)
+			}
+		}
+	}
+	if len(n.P2P.AddressPriorities) > 0 && len(n.P2P.AddressPriorities) != len(n.P2P.Addresses) {
+		return fmt.Errorf("%w: P2P address priorities do not match addresses", ErrInvalidArgument)
+	}
+
+	return nil
+}
+
 // verifyNodeRuntimeChanges verifies node runtime changes.
 func verifyNodeRuntimeChanges(
 	ctx context.Context,
@@ -1131,6 +1279,11 @@ func VerifyRuntime( // nolint: gocyclo
 		return fmt.Errorf("%w: runtime governance model is not enabled: %s", ErrForbidden, rt.GovernanceModel.String())
 	}
 
+	// Make sure the specified committee election policy is allowed.
+	if policy := rt.Executor.ElectionPolicy; policy != scheduler.ElectionPolicyUniform && !params.EnableElectionPolicies[policy] {
+		return fmt.Errorf("%w: election policy is not enabled: %s", ErrForbidden, policy)
+	}
+
 	// Ensure a valid TEE hardware is specified.
 	if rt.TEEHardware >= node.TEEHardwareReserved {
 		logger.Error("RegisterRuntime: invalid TEE hardware specified",
@@ -1372,6 +1525,9 @@ type Genesis struct {
 
 	// NodeStatuses is a set of node statuses.
 	NodeStatuses map[signature.PublicKey]*NodeStatus `json:"node_statuses,omitempty"`
+
+	// NodeAuthorizations is the list of node authorizations.
+	NodeAuthorizations []*NodeAuthorization `json:"node_authorizations,omitempty"`
 }
 
 // ConsensusParameters are the registry consensus parameters.
@@ -1411,6 +1567,10 @@ type ConsensusParameters struct {
 	// EnableRuntimeGovernanceModels is a set of enabled runtime governance models.
 	EnableRuntimeGovernanceModels map[RuntimeGovernanceModel]bool `json:"enable_runtime_governance_models,omitempty"`
 
+	// EnableElectionPolicies is a set of enabled committee election policies in addition to the
+	// uniform election policy which is always enabled.
+	EnableElectionPolicies map[scheduler.ElectionPolicy]bool `json:"enable_election_policies,omitempty"`
+
 	// TEEFeatures contains the configuration of supported TEE features.
 	TEEFeatures *node.TEEFeatures `json:"tee_features,omitempty"`
 
@@ -1435,6 +1595,9 @@ type ConsensusParameterChanges struct {
 	// EnableRuntimeGovernanceModels are the new enabled runtime governance models.
 	EnableRuntimeGovernanceModels map[RuntimeGovernanceModel]bool `json:"enable_runtime_governance_models,omitempty"`
 
+	// EnableElectionPolicies are the new enabled committee election policies.
+	EnableElectionPolicies map[scheduler.ElectionPolicy]bool `json:"enable_election_policies,omitempty"`
+
 	// TEEFeatures are the new TEE features.
 	TEEFeatures **node.TEEFeatures `json:"tee_features,omitempty"`
 
@@ -1459,6 +1622,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.EnableRuntimeGovernanceModels != nil {
 		params.EnableRuntimeGovernanceModels = c.EnableRuntimeGovernanceModels
 	}
+	if c.EnableElectionPolicies != nil {
+		params.EnableElectionPolicies = c.EnableElectionPolicies
+	}
 	if c.TEEFeatures != nil {
 		params.TEEFeatures = *c.TEEFeatures
 	}
@@ -1484,6 +1650,11 @@ const (
 	GasOpRuntimeEpochMaintenance transaction.Op = "runtime_epoch_maintenance"
 	// GasOpProveFreshness is the gas operation identifier for freshness proofs.
 	GasOpProveFreshness transaction.Op = "prove_freshness"
+	// GasOpAuthorizeNode is the gas operation identifier for node authorizations.
+	GasOpAuthorizeNode transaction.Op = "authorize_node"
+	// GasOpRevokeNodeAuthorization is the gas operation identifier for node authorization
+	// revocations.
+	GasOpRevokeNodeAuthorization transaction.Op = "revoke_node_authorization"
 )
 
 // XXX: Define reasonable default gas costs.
@@ -1497,6 +1668,8 @@ var DefaultGasCosts = transaction.Costs{
 	GasOpRegisterRuntime:         1000,
 	GasOpRuntimeEpochMaintenance: 1000,
 	GasOpProveFreshness:          1000,
+	GasOpAuthorizeNode:           1000,
+	GasOpRevokeNodeAuthorization: 1000,
 }
 
 const (
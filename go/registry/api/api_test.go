@@ -50,6 +50,10 @@ func (n *mockNodeLookup) GetEntityNodes(context.Context, signature.PublicKey) ([
 	panic("not implemented")
 }
 
+func (n *mockNodeLookup) NodeAuthorization(context.Context, signature.PublicKey, signature.PublicKey) (*NodeAuthorization, error) {
+	return nil, ErrNoSuchNodeAuthorization
+}
+
 type mockRuntimeLookup struct {
 	runtimes map[common.Namespace]*Runtime
 }
@@ -221,6 +225,54 @@ func TestVerifyRegisterNodeArgs(t *testing.T) {
 	}
 }
 
+func TestVerifyNodeAddressLimits(t *testing.T) {
+	require := require.New(t)
+
+	id := memorySigner.NewTestSigner("verify addresses tests signer").Public()
+
+	newNode := func(n int) *node.Node {
+		var nd node.Node
+		for i := 0; i < n; i++ {
+			addr := node.Address{IP: net.IPv4(127, 0, 0, 1), Port: int64(9000 + i)}
+			nd.P2P.Addresses = append(nd.P2P.Addresses, addr)
+			nd.P2P.AddressPriorities = append(nd.P2P.AddressPriorities, uint8(i))
+			nd.Consensus.Addresses = append(nd.Consensus.Addresses, node.ConsensusAddress{ID: id, Address: addr, Priority: uint8(i)})
+		}
+		return &nd
+	}
+
+	n := newNode(MaxNodeAddresses)
+	require.NoError(VerifyNodeAddressLimits(n), "maximum number of addresses should be allowed")
+
+	n = newNode(MaxNodeAddresses + 1)
+	n.Consensus.Addresses = n.Consensus.Addresses[:1]
+	require.ErrorIs(VerifyNodeAddressLimits(n), ErrInvalidArgument, "too many P2P addresses should be rejected")
+	n = newNode(MaxNodeAddresses + 1)
+	n.P2P.Addresses, n.P2P.AddressPriorities = n.P2P.Addresses[:1], nil
+	require.ErrorIs(VerifyNodeAddressLimits(n), ErrInvalidArgument, "too many consensus addresses should be rejected")
+
+	n = newNode(3)
+	n.P2P.Addresses = append(n.P2P.Addresses, n.P2P.Addresses[1])
+	n.P2P.AddressPriorities = append(n.P2P.AddressPriorities, 3)
+	require.ErrorIs(VerifyNodeAddressLimits(n), ErrInvalidArgument, "duplicate P2P addresses should be rejected")
+
+	n = newNode(3)
+	n.P2P.AddressPriorities = n.P2P.AddressPriorities[:2]
+	require.ErrorIs(VerifyNodeAddressLimits(n), ErrInvalidArgument, "mismatched P2P address priorities should be rejected")
+	n.P2P.AddressPriorities = nil
+	require.NoError(VerifyNodeAddressLimits(n), "P2P address priorities should be optional")
+
+	// Duplicates should be rejected even when they have a different priority.
+	dup := n.Consensus.Addresses[1]
+	dup.Priority = 10
+	n.Consensus.Addresses = append(n.Consensus.Addresses, dup)
+	require.ErrorIs(VerifyNodeAddressLimits(n), ErrInvalidArgument, "duplicate consensus addresses should be rejected")
+
+	// The same address with a different ID is not a duplicate.
+	n.Consensus.Addresses[3].ID = memorySigner.NewTestSigner("verify addresses tests signer 2").Public()
+	require.NoError(VerifyNodeAddressLimits(n), "same address with different IDs should be allowed")
+}
+
 func TestVerifyNodeUpdate(t *testing.T) {
 	logger := logging.GetLogger("registry/api/tests")
 
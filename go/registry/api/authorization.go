@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// NodeAuthorization is an entity's authorization for a node to register on its behalf.
+//
+// Authorizations are checked during node registration in addition to the node list
+// in the entity descriptor.
+type NodeAuthorization struct {
+	// EntityID is the identifier of the authorizing entity.
+	EntityID signature.PublicKey `json:"entity_id"`
+	// NodeID is the identifier of the authorized node.
+	NodeID signature.PublicKey `json:"node_id"`
+	// Expiration is the epoch in which the authorization expires. Zero means that the
+	// authorization never expires.
+	Expiration beacon.EpochTime `json:"expiration,omitempty"`
+}
+
+// IsExpired returns true if the authorization is expired in the given epoch.
+func (a *NodeAuthorization) IsExpired(epoch beacon.EpochTime) bool {
+	return a.Expiration != 0 && a.Expiration <= epoch
+}
+
+// ValidateBasic performs basic node authorization validity checks.
+func (a *NodeAuthorization) ValidateBasic() error {
+	if !a.EntityID.IsValid() {
+		return fmt.Errorf("%w: invalid entity ID", ErrInvalidArgument)
+	}
+	if !a.NodeID.IsValid() {
+		return fmt.Errorf("%w: invalid node ID", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// AuthorizeNode is a request to authorize a node to register on behalf of the entity signing
+// the transaction.
+//
+// An existing authorization for the same node is replaced.
+type AuthorizeNode struct {
+	// NodeID is the identifier of the authorized node.
+	NodeID signature.PublicKey `json:"node_id"`
+	// Expiration is the epoch in which the authorization expires. Zero means that the
+	// authorization never expires.
+	Expiration beacon.EpochTime `json:"expiration,omitempty"`
+}
+
+// RevokeNodeAuthorization is a request to revoke a node authorization of the entity signing
+// the transaction.
+//
+// Revocation prevents subsequent registrations of the node, but an already registered node
+// remains registered until its descriptor expires.
+type RevokeNodeAuthorization struct {
+	// NodeID is the identifier of the node.
+	NodeID signature.PublicKey `json:"node_id"`
+}
+
+// NodeAuthorizationEvent is the event emitted when a node authorization is granted, revoked
+// or expires.
+type NodeAuthorizationEvent struct {
+	Authorization *NodeAuthorization `json:"authorization"`
+	// IsGrant is true iff the authorization has been granted or updated.
+	IsGrant bool `json:"is_grant,omitempty"`
+	// IsExpired is true iff the authorization has been removed due to expiration.
+	IsExpired bool `json:"is_expired,omitempty"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *NodeAuthorizationEvent) EventKind() string {
+	return "node_authorization"
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// FeatureStorageBackpressureSoftLagThreshold is the deployment feature flag that sets the
+	// storage round lag beyond which executors stop proposing batches.
+	FeatureStorageBackpressureSoftLagThreshold = "executor.storage_backpressure.soft_lag_threshold"
+	// FeatureStorageBackpressureHardLagThreshold is the deployment feature flag that sets the
+	// storage round lag beyond which executors report themselves as unavailable.
+	FeatureStorageBackpressureHardLagThreshold = "executor.storage_backpressure.hard_lag_threshold"
+	// FeatureCheckTxRateLimit is the deployment feature flag that sets the maximum sustained rate
+	// of transaction checks served by client nodes (per second, zero disables the limit).
+	FeatureCheckTxRateLimit = "client.check_tx.rate_limit"
+	// FeatureCheckTxBurst is the deployment feature flag that sets the maximum burst of
+	// transaction checks served by client nodes.
+	FeatureCheckTxBurst = "client.check_tx.burst"
+	// FeatureStorageFetcherCount is the deployment feature flag that sets the number of storage
+	// diff fetchers used by storage workers.
+	FeatureStorageFetcherCount = "storage.fetcher_count"
+
+	// MaxDeploymentFeatures is the maximum number of feature flags in a single deployment.
+	MaxDeploymentFeatures = 16
+	// maxDeploymentFeatureValueSize is the maximum size of a feature flag value.
+	maxDeploymentFeatureValueSize = 32
+	// maxStorageFetcherCount is the maximum number of storage diff fetchers.
+	maxStorageFetcherCount = 64
+)
+
+// deploymentFeatures is the allowlist of known deployment feature flags together with their
+// value validators.
+var deploymentFeatures = map[string]func(string) error{
+	FeatureStorageBackpressureSoftLagThreshold: validateUintFeature(0, math.MaxUint64),
+	FeatureStorageBackpressureHardLagThreshold: validateUintFeature(0, math.MaxUint64),
+	FeatureCheckTxRateLimit:                    validateRateFeature,
+	FeatureCheckTxBurst:                        validateUintFeature(1, math.MaxInt32),
+	FeatureStorageFetcherCount:                 validateUintFeature(1, maxStorageFetcherCount),
+}
+
+func validateUintFeature(minValue, maxValue uint64) func(string) error {
+	return func(value string) error {
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("malformed unsigned integer: %w", err)
+		}
+		if v < minValue || v > maxValue {
+			return fmt.Errorf("value out of range [%d, %d]", minValue, maxValue)
+		}
+		return nil
+	}
+}
+
+func validateRateFeature(value string) error {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("malformed rate: %w", err)
+	}
+	if v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return fmt.Errorf("rate must be a non-negative finite number")
+	}
+	return nil
+}
+
+// ValidateFeatures validates the deployment feature flags against the allowlist of known flags.
+func (vi *VersionInfo) ValidateFeatures() error {
+	if len(vi.Features) > MaxDeploymentFeatures {
+		return fmt.Errorf("too many feature flags")
+	}
+	for name, value := range vi.Features {
+		validate, ok := deploymentFeatures[name]
+		if !ok {
+			return fmt.Errorf("unknown feature flag: %s", name)
+		}
+		if len(value) > maxDeploymentFeatureValueSize {
+			return fmt.Errorf("feature flag %s: value too long", name)
+		}
+		if err := validate(value); err != nil {
+			return fmt.Errorf("feature flag %s: %w", name, err)
+		}
+	}
+
+	// Backpressure thresholds must be consistent when both are set.
+	soft, hasSoft := vi.FeatureUint64(FeatureStorageBackpressureSoftLagThreshold)
+	hard, hasHard := vi.FeatureUint64(FeatureStorageBackpressureHardLagThreshold)
+	if hasSoft && hasHard && soft > 0 && hard > 0 && hard < soft {
+		return fmt.Errorf("feature flag %s: must not be lower than %s",
+			FeatureStorageBackpressureHardLagThreshold,
+			FeatureStorageBackpressureSoftLagThreshold,
+		)
+	}
+	return nil
+}
+
+// FeatureUint64 returns the value of the given unsigned integer feature flag and a boolean
+// indicating whether the flag is set.
+func (vi *VersionInfo) FeatureUint64(name string) (uint64, bool) {
+	if vi == nil {
+		return 0, false
+	}
+	value, ok := vi.Features[name]
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// FeatureFloat64 returns the value of the given floating point feature flag and a boolean
+// indicating whether the flag is set.
+func (vi *VersionInfo) FeatureFloat64(name string) (float64, bool) {
+	if vi == nil {
+		return 0, false
+	}
+	value, ok := vi.Features[name]
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
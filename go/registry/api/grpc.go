@@ -16,38 +16,42 @@ var (
 	serviceName = cmnGrpc.NewServiceName("Registry")
 
 	// methodGetEntity is the GetEntity method.
-	methodGetEntity = serviceName.NewMethod("GetEntity", IDQuery{})
+	methodGetEntity = serviceName.NewMethod("GetEntity", IDQuery{}).WithIdempotent()
 	// methodGetEntities is the GetEntities method.
-	methodGetEntities = serviceName.NewMethod("GetEntities", int64(0))
+	methodGetEntities = serviceName.NewMethod("GetEntities", int64(0)).WithIdempotent()
 	// methodGetNode is the GetNode method.
-	methodGetNode = serviceName.NewMethod("GetNode", IDQuery{})
+	methodGetNode = serviceName.NewMethod("GetNode", IDQuery{}).WithIdempotent()
 	// methodGetNodeByConsensusAddress is the GetNodeByConsensusAddress method.
-	methodGetNodeByConsensusAddress = serviceName.NewMethod("GetNodeByConsensusAddress", ConsensusAddressQuery{})
+	methodGetNodeByConsensusAddress = serviceName.NewMethod("GetNodeByConsensusAddress", ConsensusAddressQuery{}).WithIdempotent()
 	// methodGetNodeStatus is the GetNodeStatus method.
-	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{})
+	methodGetNodeStatus = serviceName.NewMethod("GetNodeStatus", IDQuery{}).WithIdempotent()
+	// methodGetNodeAuthorizations is the GetNodeAuthorizations method.
+	methodGetNodeAuthorizations = serviceName.NewMethod("GetNodeAuthorizations", IDQuery{}).WithIdempotent()
 	// methodGetNodes is the GetNodes method.
-	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0))
+	methodGetNodes = serviceName.NewMethod("GetNodes", int64(0)).WithIdempotent()
 	// methodGetRuntime is the GetRuntime method.
-	methodGetRuntime = serviceName.NewMethod("GetRuntime", GetRuntimeQuery{})
+	methodGetRuntime = serviceName.NewMethod("GetRuntime", GetRuntimeQuery{}).WithIdempotent()
 	// methodGetRuntimes is the GetRuntimes method.
-	methodGetRuntimes = serviceName.NewMethod("GetRuntimes", GetRuntimesQuery{})
+	methodGetRuntimes = serviceName.NewMethod("GetRuntimes", GetRuntimesQuery{}).WithIdempotent()
 	// methodStateToGenesis is the StateToGenesis method.
-	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
+	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0)).WithIdempotent()
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0)).WithIdempotent()
 	// methodConsensusParameters is the ConsensusParameters method.
-	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
+	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0)).WithIdempotent()
+	// methodValidateRuntimeUpdate is the ValidateRuntimeUpdate method.
+	methodValidateRuntimeUpdate = serviceName.NewMethod("ValidateRuntimeUpdate", Runtime{}).WithIdempotent()
 
 	// methodWatchEntities is the WatchEntities method.
-	methodWatchEntities = serviceName.NewMethod("WatchEntities", nil)
+	methodWatchEntities = serviceName.NewMethod("WatchEntities", nil).WithIdempotent()
 	// methodWatchNodes is the WatchNodes method.
-	methodWatchNodes = serviceName.NewMethod("WatchNodes", nil)
+	methodWatchNodes = serviceName.NewMethod("WatchNodes", nil).WithIdempotent()
 	// methodWatchNodeList is the WatchNodeList method.
-	methodWatchNodeList = serviceName.NewMethod("WatchNodeList", nil)
+	methodWatchNodeList = serviceName.NewMethod("WatchNodeList", nil).WithIdempotent()
 	// methodWatchRuntimes is the WatchRuntimes method.
-	methodWatchRuntimes = serviceName.NewMethod("WatchRuntimes", nil)
+	methodWatchRuntimes = serviceName.NewMethod("WatchRuntimes", nil).WithIdempotent()
 	// methodWatchEvents is the WatchEvents method.
-	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil)
+	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil).WithIdempotent()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -74,6 +78,10 @@ var (
 				MethodName: methodGetNodeStatus.ShortName(),
 				Handler:    handlerGetNodeStatus,
 			},
+			{
+				MethodName: methodGetNodeAuthorizations.ShortName(),
+				Handler:    handlerGetNodeAuthorizations,
+			},
 			{
 				MethodName: methodGetNodes.ShortName(),
 				Handler:    handlerGetNodes,
@@ -98,6 +106,10 @@ var (
 				MethodName: methodConsensusParameters.ShortName(),
 				Handler:    handlerConsensusParameters,
 			},
+			{
+				MethodName: methodValidateRuntimeUpdate.ShortName(),
+				Handler:    handlerValidateRuntimeUpdate,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -244,6 +256,29 @@ func handlerGetNodeStatus(
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerGetNodeAuthorizations(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query IDQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodeAuthorizations(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodeAuthorizations.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetNodeAuthorizations(ctx, req.(*IDQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetNodes(
 	srv any,
 	ctx context.Context,
@@ -382,6 +417,29 @@ func handlerConsensusParameters(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerValidateRuntimeUpdate(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rt Runtime
+	if err := dec(&rt); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(Backend).ValidateRuntimeUpdate(ctx, &rt)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodValidateRuntimeUpdate.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, srv.(Backend).ValidateRuntimeUpdate(ctx, req.(*Runtime))
+	}
+	return interceptor(ctx, &rt, info, handler)
+}
+
 func handlerWatchEntities(srv any, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -613,6 +671,14 @@ func (c *Client) GetNodeStatus(ctx context.Context, query *IDQuery) (*NodeStatus
 	return &rsp, nil
 }
 
+func (c *Client) GetNodeAuthorizations(ctx context.Context, query *IDQuery) ([]*NodeAuthorization, error) {
+	var rsp []*NodeAuthorization
+	if err := c.conn.Invoke(ctx, methodGetNodeAuthorizations.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *Client) GetNodes(ctx context.Context, height int64) ([]*node.Node, error) {
 	var rsp []*node.Node
 	if err := c.conn.Invoke(ctx, methodGetNodes.FullName(), height, &rsp); err != nil {
@@ -801,5 +867,9 @@ func (c *Client) ConsensusParameters(ctx context.Context, height int64) (*Consen
 	return &rsp, nil
 }
 
+func (c *Client) ValidateRuntimeUpdate(ctx context.Context, rt *Runtime) error {
+	return c.conn.Invoke(ctx, methodValidateRuntimeUpdate.FullName(), rt, nil)
+}
+
 func (c *Client) Cleanup() {
 }
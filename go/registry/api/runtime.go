@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"sort"
 	"strings"
 	"time"
@@ -78,6 +79,10 @@ func (k *RuntimeKind) FromString(str string) error {
 	return nil
 }
 
+// MaxInFlightRounds is the maximum supported number of in-flight rounds. Currently at most one
+// round can be processed speculatively while the previous round is being finalized.
+const MaxInFlightRounds = 2
+
 // ExecutorParameters are parameters for the executor committee.
 type ExecutorParameters struct {
 	// GroupSize is the size of the committee.
@@ -113,6 +118,35 @@ type ExecutorParameters struct {
 	// MaxLivenessFailures is the maximum number of liveness failures that are tolerated before
 	// suspending and/or slashing the node. Zero means unlimited.
 	MaxLivenessFailures uint8 `json:"max_liveness_fails,omitempty"`
+
+	// MaxInFlightRounds is the maximum number of rounds that can be in flight at the same time.
+	// Values greater than one enable round pipelining where the executor may speculatively
+	// process the next round while the current round is being finalized. Zero and one both
+	// mean that rounds are fully serialized.
+	//
+	// Runtimes enabling pipelining must not depend on the timestamp of the parent block as
+	// speculative execution can only use an estimate.
+	MaxInFlightRounds uint8 `json:"max_in_flight_rounds,omitempty"`
+
+	// FinalizationQuorumPercent is the percentage of primary workers that must submit commitments
+	// matching the scheduler's commitment for the round to be finalized immediately, without
+	// waiting for the remaining workers. Commitments submitted by the remaining workers after
+	// the round has been finalized are still taken into account for liveness and slashing.
+	// Zero means that all primary workers, except for the allowed stragglers, must commit.
+	FinalizationQuorumPercent uint8 `json:"finalization_quorum_percent,omitempty"`
+
+	// ElectionPolicy is the policy used to weigh candidates when electing the executor committee.
+	// The policy must be enabled in the registry consensus parameters.
+	ElectionPolicy scheduler.ElectionPolicy `json:"election_policy,omitempty"`
+
+	// ElectionMaxWeightPercent is the maximum weight of a single candidate as a percentage of the
+	// total capped candidate weight. It must be set iff the capped-weight election policy is used.
+	ElectionMaxWeightPercent uint8 `json:"election_max_weight_percent,omitempty"`
+}
+
+// IsPipeliningEnabled returns true iff round pipelining is enabled.
+func (e *ExecutorParameters) IsPipeliningEnabled() bool {
+	return e.MaxInFlightRounds > 1
 }
 
 // ValidateBasic performs basic executor parameter validity checks.
@@ -132,6 +166,28 @@ func (e *ExecutorParameters) ValidateBasic() error {
 		return fmt.Errorf("minimum live rounds percentage cannot be greater than 100")
 	}
 
+	if e.MaxInFlightRounds > MaxInFlightRounds {
+		return fmt.Errorf("maximum in-flight rounds cannot be greater than %d", MaxInFlightRounds)
+	}
+
+	if e.FinalizationQuorumPercent != 0 && (e.FinalizationQuorumPercent <= 50 || e.FinalizationQuorumPercent > 100) {
+		return fmt.Errorf("finalization quorum percentage must be greater than 50 and at most 100")
+	}
+
+	if e.ElectionPolicy > scheduler.MaxElectionPolicy {
+		return fmt.Errorf("unsupported election policy: %d", e.ElectionPolicy)
+	}
+	switch e.ElectionPolicy {
+	case scheduler.ElectionPolicyCappedWeight:
+		if e.ElectionMaxWeightPercent == 0 || e.ElectionMaxWeightPercent > 100 {
+			return fmt.Errorf("election maximum weight percentage must be greater than 0 and at most 100")
+		}
+	default:
+		if e.ElectionMaxWeightPercent != 0 {
+			return fmt.Errorf("election maximum weight percentage is only supported by the %s election policy", scheduler.ElectionPolicyCappedWeight)
+		}
+	}
+
 	return nil
 }
 
@@ -342,6 +398,10 @@ type Runtime struct { // nolint: maligned
 
 	// Deployments specifies the runtime deployments (versions).
 	Deployments []*VersionInfo `json:"deployments,omitempty"`
+
+	// MinNodeSoftwareVersion is the minimum oasis-node software version that nodes must be
+	// running in order to register for the runtime. If not set, there is no constraint.
+	MinNodeSoftwareVersion *version.Version `json:"min_node_software_version,omitempty"`
 }
 
 // RuntimeGovernanceModel specifies the runtime governance model.
@@ -435,6 +495,9 @@ func (r *Runtime) ValidateBasic(strictVersion bool) error {
 		if err := r.Executor.ValidateBasic(); err != nil {
 			return fmt.Errorf("bad executor parameters: %w", err)
 		}
+		if r.Executor.IsPipeliningEnabled() && r.TEEHardware != node.TEEHardwareInvalid {
+			return fmt.Errorf("bad executor parameters: round pipelining not supported for TEE runtimes")
+		}
 		if err := r.TxnScheduler.ValidateBasic(); err != nil {
 			return fmt.Errorf("bad txn scheduler parameters: %w", err)
 		}
@@ -471,6 +534,12 @@ func (r *Runtime) ValidateBasic(strictVersion bool) error {
 		return fmt.Errorf("%w: out of range", ErrUnsupportedRuntimeGovernanceModel)
 	}
 
+	if r.MinNodeSoftwareVersion != nil {
+		if err := r.MinNodeSoftwareVersion.ValidateBasic(); err != nil {
+			return fmt.Errorf("bad min node software version: %w", err)
+		}
+	}
+
 	if len(r.Deployments) == 0 {
 		return fmt.Errorf("no deployment information specified")
 	}
@@ -524,13 +593,35 @@ func (r *Runtime) DeploymentForVersion(v version.Version) *VersionInfo {
 	return nil
 }
 
+// VerifyNodeSoftwareVersion verifies that the given node software version satisfies the
+// runtime's minimum node software version constraint, if any.
+func (r *Runtime) VerifyNodeSoftwareVersion(sw node.SoftwareVersion) error {
+	if r.MinNodeSoftwareVersion == nil {
+		return nil
+	}
+
+	v, err := sw.Version()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNodeSoftwareVersionTooOld, err)
+	}
+	if v.Less(*r.MinNodeSoftwareVersion) {
+		return fmt.Errorf("%w: runtime %s requires at least %s, node is running %s",
+			ErrNodeSoftwareVersionTooOld,
+			r.ID,
+			r.MinNodeSoftwareVersion,
+			v,
+		)
+	}
+	return nil
+}
+
 // ValidateDeployments validates a runtime descriptor's Deployments field
 // at the specified epoch.
 func (r *Runtime) ValidateDeployments(now beacon.EpochTime, params *ConsensusParameters) error {
 	// The runtime descriptor's deployments field is considered valid
 	// if:
 	//  * There is at least one entry present.
-	//  * All of the entries are well-formed.
+	//  * All of the entries are well-formed (including their feature flags).
 	//  * There is at most max(2, params.MaxRuntimeDeployments) entries:
 	//  * The versions field increases as versions are deployed.
 
@@ -608,6 +699,10 @@ func (r *Runtime) ValidateDeployments(now beacon.EpochTime, params *ConsensusPar
 		if len(deployment.BundleChecksum) > 0 && len(deployment.BundleChecksum) != 32 {
 			return fmt.Errorf("%w: invalid bundle checksum", ErrInvalidArgument)
 		}
+
+		if err := deployment.ValidateFeatures(); err != nil {
+			return fmt.Errorf("%w: invalid deployment features: %w", ErrInvalidArgument, err)
+		}
 	}
 	if numFuture > 1 {
 		return fmt.Errorf("%w: more than one future deployment", ErrInvalidArgument)
@@ -656,6 +751,12 @@ type VersionInfo struct {
 
 	// BundleChecksum is the SHA256 hash of the runtime bundle manifest (optional).
 	BundleChecksum []byte `json:"bundle_checksum,omitempty"`
+
+	// Features are the node-side feature flags of the deployment (optional).
+	//
+	// Only flags from the allowlist of known flags are accepted. The flags take effect when the
+	// deployment becomes active.
+	Features map[string]string `json:"features,omitempty"`
 }
 
 // Equal compares vs another VersionInfo for equality.
@@ -678,6 +779,9 @@ func (vi *VersionInfo) Equal(cmp *VersionInfo) bool {
 	if !bytes.Equal(vi.BundleChecksum, cmp.BundleChecksum) {
 		return false
 	}
+	if !maps.Equal(vi.Features, cmp.Features) {
+		return false
+	}
 	return true
 }
 
@@ -666,6 +666,75 @@ func TestVerifyRuntime(t *testing.T) {
 	}
 }
 
+func TestExecutorParametersPipelining(t *testing.T) {
+	require := require.New(t)
+
+	params := ExecutorParameters{
+		GroupSize:    1,
+		RoundTimeout: 5,
+	}
+	require.NoError(params.ValidateBasic(), "pipelining disabled")
+	require.False(params.IsPipeliningEnabled())
+
+	params.MaxInFlightRounds = 1
+	require.NoError(params.ValidateBasic(), "single in-flight round")
+	require.False(params.IsPipeliningEnabled())
+
+	params.MaxInFlightRounds = MaxInFlightRounds
+	require.NoError(params.ValidateBasic(), "pipelining enabled")
+	require.True(params.IsPipeliningEnabled())
+
+	params.MaxInFlightRounds = MaxInFlightRounds + 1
+	require.Error(params.ValidateBasic(), "too many in-flight rounds")
+}
+
+func TestExecutorParametersFinalizationQuorum(t *testing.T) {
+	require := require.New(t)
+
+	params := ExecutorParameters{
+		GroupSize:    3,
+		RoundTimeout: 5,
+	}
+	for _, tc := range []struct {
+		percent uint8
+		valid   bool
+	}{
+		{0, true},
+		{1, false},
+		{50, false},
+		{51, true},
+		{67, true},
+		{100, true},
+		{101, false},
+	} {
+		params.FinalizationQuorumPercent = tc.percent
+		switch tc.valid {
+		case true:
+			require.NoError(params.ValidateBasic(), "finalization quorum %d%%", tc.percent)
+		case false:
+			require.Error(params.ValidateBasic(), "finalization quorum %d%%", tc.percent)
+		}
+	}
+}
+
+func TestMinNodeSoftwareVersion(t *testing.T) {
+	require := require.New(t)
+
+	var rt Runtime
+	require.NoError(rt.VerifyNodeSoftwareVersion(""), "no constraint should allow any version")
+
+	rt.MinNodeSoftwareVersion = &version.Version{Major: 24, Minor: 3}
+	require.NoError(rt.VerifyNodeSoftwareVersion("24.3"))
+	require.NoError(rt.VerifyNodeSoftwareVersion("24.3.1 (upgrade handlers: consensus243)"))
+	require.NoError(rt.VerifyNodeSoftwareVersion("25.0-git1234"))
+
+	err := rt.VerifyNodeSoftwareVersion("24.2.5")
+	require.ErrorIs(err, ErrNodeSoftwareVersionTooOld)
+	require.ErrorContains(err, "requires at least 24.3.0, node is running 24.2.5")
+	require.ErrorIs(rt.VerifyNodeSoftwareVersion(""), ErrNodeSoftwareVersionTooOld, "missing version")
+	require.ErrorIs(rt.VerifyNodeSoftwareVersion("invalid"), ErrNodeSoftwareVersionTooOld, "malformed version")
+}
+
 func TestDeployments(t *testing.T) {
 	require := require.New(t)
 
@@ -763,3 +832,93 @@ func TestDeployments(t *testing.T) {
 	})
 	require.Nil(ad)
 }
+
+func TestDeploymentFeatures(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		features map[string]string
+		valid    bool
+		msg      string
+	}{
+		{nil, true, "no features should be valid"},
+		{map[string]string{FeatureStorageFetcherCount: "8"}, true, "known feature should be valid"},
+		{map[string]string{FeatureCheckTxRateLimit: "12.5", FeatureCheckTxBurst: "20"}, true, "rate limit should be valid"},
+		{map[string]string{FeatureCheckTxRateLimit: "0"}, true, "disabled rate limit should be valid"},
+		{map[string]string{
+			FeatureStorageBackpressureSoftLagThreshold: "10",
+			FeatureStorageBackpressureHardLagThreshold: "20",
+		}, true, "consistent backpressure thresholds should be valid"},
+		{map[string]string{"executor.unknown": "1"}, false, "unknown feature should be invalid"},
+		{map[string]string{FeatureStorageFetcherCount: "0"}, false, "zero fetcher count should be invalid"},
+		{map[string]string{FeatureStorageFetcherCount: "1000"}, false, "too many fetchers should be invalid"},
+		{map[string]string{FeatureStorageFetcherCount: "-1"}, false, "negative fetcher count should be invalid"},
+		{map[string]string{FeatureCheckTxRateLimit: "-1"}, false, "negative rate limit should be invalid"},
+		{map[string]string{FeatureCheckTxRateLimit: "NaN"}, false, "NaN rate limit should be invalid"},
+		{map[string]string{FeatureCheckTxBurst: "0"}, false, "zero burst should be invalid"},
+		{map[string]string{
+			FeatureStorageBackpressureSoftLagThreshold: "20",
+			FeatureStorageBackpressureHardLagThreshold: "10",
+		}, false, "inconsistent backpressure thresholds should be invalid"},
+	} {
+		vi := VersionInfo{Features: tc.features}
+		switch tc.valid {
+		case true:
+			require.NoError(vi.ValidateFeatures(), tc.msg)
+		case false:
+			require.Error(vi.ValidateFeatures(), tc.msg)
+		}
+	}
+
+	vi := VersionInfo{Features: map[string]string{
+		FeatureStorageFetcherCount: "8",
+		FeatureCheckTxRateLimit:    "12.5",
+	}}
+	v, ok := vi.FeatureUint64(FeatureStorageFetcherCount)
+	require.True(ok)
+	require.EqualValues(8, v)
+	_, ok = vi.FeatureUint64(FeatureCheckTxBurst)
+	require.False(ok, "unset feature should not be reported")
+	f, ok := vi.FeatureFloat64(FeatureCheckTxRateLimit)
+	require.True(ok)
+	require.EqualValues(12.5, f)
+
+	// Features are part of the deployment identity.
+	other := VersionInfo{Features: map[string]string{FeatureStorageFetcherCount: "8"}}
+	require.False(vi.Equal(&other), "deployments with different features should not be equal")
+	other.Features[FeatureCheckTxRateLimit] = "12.5"
+	require.True(vi.Equal(&other), "deployments with same features should be equal")
+}
+
+func TestExecutorParametersElectionPolicy(t *testing.T) {
+	require := require.New(t)
+
+	params := ExecutorParameters{
+		GroupSize:    3,
+		RoundTimeout: 5,
+	}
+	for _, tc := range []struct {
+		policy  api.ElectionPolicy
+		percent uint8
+		valid   bool
+	}{
+		{api.ElectionPolicyUniform, 0, true},
+		{api.ElectionPolicyUniform, 10, false},
+		{api.ElectionPolicyStakeWeighted, 0, true},
+		{api.ElectionPolicyStakeWeighted, 10, false},
+		{api.ElectionPolicyCappedWeight, 0, false},
+		{api.ElectionPolicyCappedWeight, 10, true},
+		{api.ElectionPolicyCappedWeight, 100, true},
+		{api.ElectionPolicyCappedWeight, 101, false},
+		{api.MaxElectionPolicy + 1, 0, false},
+	} {
+		params.ElectionPolicy = tc.policy
+		params.ElectionMaxWeightPercent = tc.percent
+		switch tc.valid {
+		case true:
+			require.NoError(params.ValidateBasic(), "election policy %d (%d%%)", tc.policy, tc.percent)
+		case false:
+			require.Error(params.ValidateBasic(), "election policy %d (%d%%)", tc.policy, tc.percent)
+		}
+	}
+}
@@ -35,6 +35,7 @@ func (c *ConsensusParameterChanges) SanityCheck() error {
 		c.GasCosts == nil &&
 		c.MaxNodeExpiration == nil &&
 		c.EnableRuntimeGovernanceModels == nil &&
+		c.EnableElectionPolicies == nil &&
 		c.TEEFeatures == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
@@ -68,7 +69,7 @@ func (g *Genesis) SanityCheck(
 	}
 
 	// Check nodes.
-	nodeLookup, err := SanityCheckNodes(logger, &g.Parameters, g.Nodes, seenEntities, runtimesLookup, true, baseEpoch, now, height)
+	nodeLookup, err := SanityCheckNodes(logger, &g.Parameters, g.Nodes, seenEntities, g.NodeAuthorizations, runtimesLookup, true, baseEpoch, now, height)
 	if err != nil {
 		return err
 	}
@@ -171,6 +172,31 @@ func SanityCheckRuntimes(
 	return lookup, nil
 }
 
+// SanityCheckNodeAuthorizations examines the node authorizations table.
+// Pass lookup of entities from SanityCheckEntities for cross referencing purposes.
+func SanityCheckNodeAuthorizations(
+	authorizations []*NodeAuthorization,
+	seenEntities map[signature.PublicKey]*entity.Entity,
+) (map[signature.PublicKey]map[signature.PublicKey]*NodeAuthorization, error) {
+	seenAuthorizations := make(map[signature.PublicKey]map[signature.PublicKey]*NodeAuthorization)
+	for _, auth := range authorizations {
+		if err := auth.ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("registry: node authorization sanity check failed: %w", err)
+		}
+		if _, ok := seenEntities[auth.EntityID]; !ok {
+			return nil, fmt.Errorf("registry: node authorization sanity check failed: authorization for node %s references a missing entity", auth.NodeID)
+		}
+		if seenAuthorizations[auth.EntityID] == nil {
+			seenAuthorizations[auth.EntityID] = make(map[signature.PublicKey]*NodeAuthorization)
+		}
+		if _, ok := seenAuthorizations[auth.EntityID][auth.NodeID]; ok {
+			return nil, fmt.Errorf("registry: node authorization sanity check failed: duplicate authorization for node %s", auth.NodeID)
+		}
+		seenAuthorizations[auth.EntityID][auth.NodeID] = auth
+	}
+	return seenAuthorizations, nil
+}
+
 // SanityCheckNodes examines the nodes table.
 // Pass lookups of entities and runtimes from SanityCheckEntities
 // and SanityCheckRuntimes for cross referencing purposes.
@@ -179,15 +205,21 @@ func SanityCheckNodes(
 	params *ConsensusParameters,
 	nodes []*node.MultiSignedNode,
 	seenEntities map[signature.PublicKey]*entity.Entity,
+	authorizations []*NodeAuthorization,
 	runtimesLookup RuntimeLookup,
 	isGenesis bool,
 	epoch beacon.EpochTime,
 	now time.Time,
 	height uint64,
 ) (NodeLookup, error) { // nolint: gocyclo
+	seenAuthorizations, err := SanityCheckNodeAuthorizations(authorizations, seenEntities)
+	if err != nil {
+		return nil, err
+	}
 
 	nodeLookup := &sanityCheckNodeLookup{
-		nodes: make(map[signature.PublicKey]*node.Node),
+		nodes:          make(map[signature.PublicKey]*node.Node),
+		authorizations: seenAuthorizations,
 	}
 
 	for _, signedNode := range nodes {
@@ -379,6 +411,8 @@ type sanityCheckNodeLookup struct {
 	nodes map[signature.PublicKey]*node.Node
 
 	nodesList []*node.Node
+
+	authorizations map[signature.PublicKey]map[signature.PublicKey]*NodeAuthorization
 }
 
 func (n *sanityCheckNodeLookup) NodeBySubKey(_ context.Context, key signature.PublicKey) (*node.Node, error) {
@@ -396,3 +430,11 @@ func (n *sanityCheckNodeLookup) Nodes(context.Context) ([]*node.Node, error) {
 func (n *sanityCheckNodeLookup) GetEntityNodes(context.Context, signature.PublicKey) ([]*node.Node, error) {
 	return nil, fmt.Errorf("entity node lookup not supported")
 }
+
+func (n *sanityCheckNodeLookup) NodeAuthorization(_ context.Context, entityID, nodeID signature.PublicKey) (*NodeAuthorization, error) {
+	auth, ok := n.authorizations[entityID][nodeID]
+	if !ok {
+		return nil, ErrNoSuchNodeAuthorization
+	}
+	return auth, nil
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"os"
 
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -75,6 +76,19 @@ func main() {
 				NodeID: nodeSigner.Public(),
 			})
 			vectors = append(vectors, testvectors.MakeTestVector("UnfreezeNode", tx, true))
+
+			// Generate node authorization transactions.
+			for _, expiration := range []beacon.EpochTime{0, 100} {
+				tx = registry.NewAuthorizeNodeTx(nonce, fee, &registry.AuthorizeNode{
+					NodeID:     nodeSigner.Public(),
+					Expiration: expiration,
+				})
+				vectors = append(vectors, testvectors.MakeTestVector("AuthorizeNode", tx, true))
+			}
+			tx = registry.NewRevokeNodeAuthorizationTx(nonce, fee, &registry.RevokeNodeAuthorization{
+				NodeID: nodeSigner.Public(),
+			})
+			vectors = append(vectors, testvectors.MakeTestVector("RevokeNodeAuthorization", tx, true))
 		}
 	}
 
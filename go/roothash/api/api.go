@@ -10,6 +10,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -385,6 +386,8 @@ type RuntimeState struct {
 	Runtime *registry.Runtime `json:"runtime"`
 	// Suspended is a flag indicating whether the runtime is currently suspended.
 	Suspended bool `json:"suspended,omitempty"`
+	// SuspensionReason is the reason why the runtime is currently suspended.
+	SuspensionReason SuspensionReason `json:"suspension_reason,omitempty"`
 
 	// GenesisBlock is the runtime's first block.
 	GenesisBlock *block.Block `json:"genesis_block"`
@@ -409,6 +412,64 @@ type RuntimeState struct {
 
 	// LivenessStatistics contains the liveness statistics for the current epoch.
 	LivenessStatistics *LivenessStatistics `json:"liveness_stats,omitempty"`
+
+	// LateCommitments tracks the workers that may still submit commitments for the last round
+	// after it has been finalized early due to the finalization quorum being reached.
+	LateCommitments *LateCommitments `json:"late_commitments,omitempty"`
+}
+
+// LateCommitments contains the information needed to process executor commitments which arrive
+// after a round has been finalized early.
+//
+// Late commitments are only used for liveness accounting and slashing and never affect the
+// finalized block.
+type LateCommitments struct {
+	// Block is the block the finalized round was based on.
+	Block *block.Block `json:"block"`
+	// SchedulerID is the public key of the scheduler whose commitment was finalized.
+	SchedulerID signature.PublicKey `json:"scheduler_id"`
+	// Vote is the vote of the finalized scheduler commitment.
+	Vote hash.Hash `json:"vote"`
+	// Pending are the public keys of workers which have not yet submitted a commitment.
+	Pending []signature.PublicKey `json:"pending"`
+	// Deadline is the last consensus height at which late commitments are accepted in case the
+	// round has been finalized without early finalization and late commitments are accepted due
+	// to the late commitment window. Such commitments are only recorded as late rounds in the
+	// liveness statistics.
+	//
+	// Zero means that the round has been finalized early and late commitments are accepted until
+	// the next round is finalized.
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+// IsWindow returns true iff late commitments are tracked due to the late commitment window.
+func (lc *LateCommitments) IsWindow() bool {
+	return lc.Deadline > 0
+}
+
+// Accepts returns true iff late commitments are still accepted at the given consensus height.
+func (lc *LateCommitments) Accepts(height int64) bool {
+	return !lc.IsWindow() || height <= lc.Deadline
+}
+
+// IsPending returns true iff the given worker has not yet submitted a commitment.
+func (lc *LateCommitments) IsPending(id signature.PublicKey) bool {
+	for _, pk := range lc.Pending {
+		if pk.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove removes the given worker from the list of pending workers.
+func (lc *LateCommitments) Remove(id signature.PublicKey) {
+	for i, pk := range lc.Pending {
+		if pk.Equal(id) {
+			lc.Pending = append(lc.Pending[:i], lc.Pending[i+1:]...)
+			return
+		}
+	}
 }
 
 // AnnotatedBlock is an annotated roothash block.
@@ -419,6 +480,10 @@ type AnnotatedBlock struct {
 
 	// Block is the roothash block.
 	Block *block.Block `json:"block"`
+
+	// BatchSummary is the summary of resources consumed by the block's batch, if it was provided
+	// by the runtime.
+	BatchSummary *commitment.BatchSummary `json:"batch_summary,omitempty"`
 }
 
 // ExecutorCommittedEvent is an event emitted each time an executor node commits.
@@ -475,6 +540,23 @@ func (e *RuntimeIDAttribute) DecodeValue(value string) error {
 	return nil
 }
 
+// LateExecutorCommittedEvent is emitted when a correct executor commitment for an already
+// finalized round is accepted within the late commitment window.
+//
+// The commitment did not affect the finalized block and is only recorded as a late round in the
+// liveness statistics of the node.
+type LateExecutorCommittedEvent struct {
+	// Round is the finalized round the commitment was for.
+	Round uint64 `json:"round"`
+	// NodeID is the public key of the node that submitted the commitment.
+	NodeID signature.PublicKey `json:"node_id"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *LateExecutorCommittedEvent) EventKind() string {
+	return "late_executor_committed"
+}
+
 // FinalizedEvent is a finalized event.
 type FinalizedEvent struct {
 	// Round is the round that was finalized.
@@ -531,6 +613,9 @@ type Event struct {
 	ExecutionDiscrepancyDetected *ExecutionDiscrepancyDetectedEvent `json:"execution_discrepancy,omitempty"`
 	Finalized                    *FinalizedEvent                    `json:"finalized,omitempty"`
 	InMsgProcessed               *InMsgProcessedEvent               `json:"in_msg_processed,omitempty"`
+	RuntimeSuspended             *RuntimeSuspendedEvent             `json:"runtime_suspended,omitempty"`
+	RuntimeResumed               *RuntimeResumedEvent               `json:"runtime_resumed,omitempty"`
+	LateExecutorCommitted        *LateExecutorCommittedEvent        `json:"late_executor_committed,omitempty"`
 }
 
 // MetricsMonitorable is the interface exposed by backends capable of
@@ -586,6 +671,12 @@ type ConsensusParameters struct {
 	// MaxPastRootsStored is the maximum number of past runtime state and I/O
 	// roots that are stored in the consensus state.
 	MaxPastRootsStored uint64 `json:"max_past_roots_stored,omitempty"`
+
+	// MaxLateCommitmentBlocks is the number of consensus blocks after a round has been finalized
+	// during which matching executor commitments for that round are still accepted and recorded
+	// as late rounds in the liveness statistics. Late commitments never affect the finalized
+	// block. Zero disables accepting late commitments.
+	MaxLateCommitmentBlocks uint64 `json:"max_late_commitment_blocks,omitempty"`
 }
 
 // ConsensusParameterChanges are allowed roothash consensus parameter changes.
@@ -605,6 +696,9 @@ type ConsensusParameterChanges struct {
 	// MaxPastRootsStored is the new maximum number of past runtime state and I/O
 	// roots that are stored in the consensus state.
 	MaxPastRootsStored *uint64 `json:"max_past_roots_stored,omitempty"`
+
+	// MaxLateCommitmentBlocks is the new late commitment window.
+	MaxLateCommitmentBlocks *uint64 `json:"max_late_commitment_blocks,omitempty"`
 }
 
 // Apply applies changes to the given consensus parameters.
@@ -624,6 +718,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.MaxPastRootsStored != nil {
 		params.MaxPastRootsStored = *c.MaxPastRootsStored
 	}
+	if c.MaxLateCommitmentBlocks != nil {
+		params.MaxLateCommitmentBlocks = *c.MaxLateCommitmentBlocks
+	}
 	return nil
 }
 
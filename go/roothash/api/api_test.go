@@ -3,6 +3,7 @@ package api
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -725,3 +726,86 @@ func TestRoundRootsSerialization(t *testing.T) {
 		require.EqualValues(tc.rr, dec, "Runtime serialization should round-trip")
 	}
 }
+
+func TestSuspensionReason(t *testing.T) {
+	require := require.New(t)
+
+	for _, reason := range []SuspensionReason{
+		SuspensionReasonNone,
+		SuspensionReasonInsufficientCommittee,
+		SuspensionReasonMissingKeyManager,
+		SuspensionReasonInsufficientStake,
+	} {
+		text, err := reason.MarshalText()
+		require.NoError(err, "MarshalText")
+
+		var decoded SuspensionReason
+		err = decoded.UnmarshalText(text)
+		require.NoError(err, "UnmarshalText")
+		require.Equal(reason, decoded, "suspension reason should round-trip")
+	}
+
+	_, err := SuspensionReason(42).MarshalText()
+	require.Error(err, "MarshalText should fail for an invalid reason")
+	var decoded SuspensionReason
+	err = decoded.UnmarshalText([]byte("invalid"))
+	require.Error(err, "UnmarshalText should fail for an invalid reason")
+
+	// Suspension events should be decodable from event values.
+	ev := RuntimeSuspendedEvent{Reason: SuspensionReasonMissingKeyManager}
+	var decodedEv RuntimeSuspendedEvent
+	err = events.DecodeValue(events.EncodeValue(&ev), &decodedEv)
+	require.NoError(err, "DecodeValue")
+	require.Equal(ev, decodedEv, "suspended event should round-trip")
+}
+
+func TestLivenessStatisticsLateRounds(t *testing.T) {
+	require := require.New(t)
+
+	stats := NewLivenessStatistics(3)
+	stats.TotalRounds = 4
+	stats.LiveRounds[0] = 4
+	stats.LiveRounds[1] = 2
+
+	// Late rounds should not be serialized until the first one is recorded.
+	var raw map[string]any
+	err := cbor.Unmarshal(cbor.Marshal(stats), &raw)
+	require.NoError(err, "Unmarshal")
+	require.NotContains(raw, "late_rounds", "late rounds should be omitted")
+	require.EqualValues(0, stats.LateRoundsAt(1))
+	require.EqualValues(2, stats.MissedRoundsAt(1))
+
+	stats.RecordLateRound(1)
+	require.Len(stats.LateRounds, 3)
+	require.EqualValues(1, stats.LateRoundsAt(1))
+	require.EqualValues(0, stats.MissedRoundsAt(0))
+	require.EqualValues(1, stats.MissedRoundsAt(1))
+	require.EqualValues(4, stats.MissedRoundsAt(2))
+
+	var dec LivenessStatistics
+	err = cbor.Unmarshal(cbor.Marshal(stats), &dec)
+	require.NoError(err, "Unmarshal")
+	require.EqualValues(*stats, dec, "LivenessStatistics serialization should round-trip")
+}
+
+func TestLateCommitmentsAccepts(t *testing.T) {
+	require := require.New(t)
+
+	// Rounds finalized early accept late commitments until the next round is finalized.
+	lc := LateCommitments{}
+	require.False(lc.IsWindow())
+	require.True(lc.Accepts(math.MaxInt64))
+
+	// Otherwise late commitments are only accepted until the deadline.
+	lc.Deadline = 10
+	require.True(lc.IsWindow())
+	require.True(lc.Accepts(10))
+	require.False(lc.Accepts(11))
+
+	// The consensus parameters should not change when the window is not configured.
+	params := ConsensusParameters{MaxRuntimeMessages: 10}
+	var raw map[string]any
+	err := cbor.Unmarshal(cbor.Marshal(params), &raw)
+	require.NoError(err, "Unmarshal")
+	require.NotContains(raw, "max_late_commitment_blocks", "late commitment window should be omitted")
+}
@@ -54,6 +54,21 @@ type ComputeResultsHeader struct {
 	InMessagesHash *hash.Hash `json:"in_msgs_hash,omitempty"`
 	// InMessagesCount is the number of processed incoming messages.
 	InMessagesCount uint32 `json:"in_msgs_count,omitempty"`
+
+	// BatchSummary is the optional summary of resources consumed by the batch.
+	//
+	// It is only present when produced by runtimes supporting the batch summary feature.
+	BatchSummary *BatchSummary `json:"batch_summary,omitempty"`
+}
+
+// BatchSummary is a summary of resources consumed by processing a batch.
+type BatchSummary struct {
+	// GasUsed is the total amount of gas used by all transactions in the batch.
+	GasUsed uint64 `json:"gas_used,omitempty"`
+	// BatchSize is the total size of all transactions in the batch (in bytes).
+	BatchSize uint64 `json:"batch_size,omitempty"`
+	// TxCount is the number of transactions in the batch.
+	TxCount uint32 `json:"tx_count,omitempty"`
 }
 
 // IsParentOf returns true iff the header is the parent of a child header.
@@ -109,6 +124,7 @@ func (eh *ExecutorCommitmentHeader) SetFailure(failure ExecutorCommitmentFailure
 	eh.Header.MessagesHash = nil
 	eh.Header.InMessagesHash = nil
 	eh.Header.InMessagesCount = 0
+	eh.Header.BatchSummary = nil
 	eh.RAKSignature = nil
 	eh.Failure = failure
 }
@@ -230,6 +246,9 @@ func (c *ExecutorCommitment) ValidateBasic() error {
 		if header.InMessagesHash != nil || header.InMessagesCount != 0 {
 			return fmt.Errorf("failure indicating commitment includes InMessagesHash/Count")
 		}
+		if header.BatchSummary != nil {
+			return fmt.Errorf("failure indicating commitment includes BatchSummary")
+		}
 		// In case of failure indicating commitment make sure RAK signature is empty.
 		if c.Header.RAKSignature != nil {
 			return fmt.Errorf("failure indicating body includes RAK signature")
@@ -33,6 +33,16 @@ func TestConsistentHash(t *testing.T) {
 		InMessagesHash: &emptyRoot,
 	}
 	require.EqualValues(t, populatedHeaderHash.String(), populated.EncodedHash().String())
+
+	var summaryHeaderHash hash.Hash
+	_ = summaryHeaderHash.UnmarshalHex("0d26af3eab5d94755b150fd90aba95029f3aee873dc9c6db514b3c450fb93458")
+
+	populated.BatchSummary = &BatchSummary{
+		GasUsed:   1000,
+		BatchSize: 256,
+		TxCount:   2,
+	}
+	require.EqualValues(t, summaryHeaderHash.String(), populated.EncodedHash().String())
 }
 
 func TestValidateBasic(t *testing.T) {
@@ -158,6 +168,27 @@ func TestValidateBasic(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"Bad Failure (existing BatchSummary)",
+			func(ec ExecutorCommitment) ExecutorCommitment {
+				ec.Header.SetFailure(FailureUnknown)
+				ec.Header.Header.BatchSummary = &BatchSummary{}
+				return ec
+			},
+			true,
+		},
+		{
+			"Ok BatchSummary",
+			func(ec ExecutorCommitment) ExecutorCommitment {
+				ec.Header.Header.BatchSummary = &BatchSummary{
+					GasUsed:   1000,
+					BatchSize: 256,
+					TxCount:   2,
+				}
+				return ec
+			},
+			false,
+		},
 		{
 			"Ok Failure",
 			func(ec ExecutorCommitment) ExecutorCommitment {
@@ -2,9 +2,12 @@ package commitment
 
 import (
 	"context"
+	"fmt"
 	"math"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
@@ -81,7 +84,7 @@ func NewPool() *Pool {
 }
 
 // VerifyExecutorCommitment verifies the given executor commitment.
-func VerifyExecutorCommitment( // nolint: gocyclo
+func VerifyExecutorCommitment(
 	ctx context.Context,
 	blk *block.Block,
 	rt *registry.Runtime,
@@ -95,6 +98,53 @@ func VerifyExecutorCommitment( // nolint: gocyclo
 		return p2pError.Permanent(err)
 	}
 
+	return VerifyExecutorCommitmentContent(ctx, blk, rt, epoch, commit, msgValidator, nl)
+}
+
+// VerifyExecutorCommitmentSignatures batch verifies the signatures of the given executor
+// commitments, returning the signature verification error for each of the commitments, or
+// nil in case the corresponding signature is valid.
+func VerifyExecutorCommitmentSignatures(runtimeID common.Namespace, commits []ExecutorCommitment) []error {
+	errs := make([]error, len(commits))
+
+	sigCtx, err := ExecutorSignatureContext.WithSuffix(runtimeID.String())
+	if err != nil {
+		for i := range errs {
+			errs[i] = fmt.Errorf("roothash/commitment: signature context error: %w", err)
+		}
+		return errs
+	}
+
+	entries := make([]signature.BatchEntry, 0, len(commits))
+	for _, c := range commits {
+		entries = append(entries, signature.BatchEntry{
+			PublicKey: c.NodeID,
+			Context:   sigCtx,
+			Message:   cbor.Marshal(c.Header),
+			Signature: c.Signature[:],
+		})
+	}
+
+	if bvErr, ok := signature.BatchVerify(entries).(*signature.BatchVerifyError); ok {
+		for _, i := range bvErr.Invalid() {
+			errs[i] = fmt.Errorf("roothash/commitment: signature verification failed")
+		}
+	}
+	return errs
+}
+
+// VerifyExecutorCommitmentContent verifies the given executor commitment, except for its
+// signature which must have already been verified by the caller (e.g. using
+// VerifyExecutorCommitmentSignatures).
+func VerifyExecutorCommitmentContent( // nolint: gocyclo
+	ctx context.Context,
+	blk *block.Block,
+	rt *registry.Runtime,
+	epoch beacon.EpochTime,
+	commit *ExecutorCommitment,
+	msgValidator MessageValidator,
+	nl NodeLookup,
+) error {
 	// Validate executor commitment.
 	if err := commit.ValidateBasic(); err != nil {
 		logger.Debug("executor commitment validate basic error",
@@ -232,9 +282,10 @@ func (p *Pool) AddVerifiedExecutorCommitment(c *scheduler.Committee, ec *Executo
 			"node_id", ec.NodeID,
 		)
 		return ErrNotInCommittee
-	case p.Discrepancy && !c.IsBackupWorker(ec.NodeID):
+	case p.Discrepancy && !c.IsBackupWorker(ec.NodeID) && !p.isMissingSchedulerCommitment(ec):
 		// Discrepancy resolution accepts commitments only from backup workers to prevent workers
-		// from improving their liveness statistics.
+		// from improving their liveness statistics. The only exception is the scheduler's own
+		// commitment in case the discrepancy was detected early, before the scheduler committed.
 		logger.Debug("node is not a backup worker",
 			"round", ec.Header.Header.Round,
 			"node_id", ec.NodeID,
@@ -306,9 +357,71 @@ func (p *Pool) AddVerifiedExecutorCommitment(c *scheduler.Committee, ec *Executo
 	return sc.Add(ec)
 }
 
+// isMissingSchedulerCommitment returns true iff the given commitment was submitted by
+// the scheduler whose commitment is still missing for the highest rank.
+//
+// The highest rank can only lack the scheduler's commitment after early discrepancy detection.
+func (p *Pool) isMissingSchedulerCommitment(ec *ExecutorCommitment) bool {
+	if !ec.NodeID.Equal(ec.Header.SchedulerID) {
+		return false
+	}
+	sc, ok := p.SchedulerCommitments[p.HighestRank]
+	return ok && sc.Commitment == nil
+}
+
+// hasPrimaryDiscrepancy returns true iff at least two workers submitted valid but differing
+// commitments for the primary scheduler's proposal.
+func (p *Pool) hasPrimaryDiscrepancy(c *scheduler.Committee) bool {
+	sc, ok := p.SchedulerCommitments[0]
+	if !ok {
+		return false
+	}
+
+	var first *hash.Hash
+	for _, n := range c.Members {
+		if n.Role != scheduler.RoleWorker {
+			continue
+		}
+		vote := sc.Votes[n.PublicKey]
+		switch {
+		case vote == nil:
+			// Failures alone are not a proof of a discrepancy.
+			continue
+		case first == nil:
+			first = vote
+		case !first.Equal(vote):
+			return true
+		}
+	}
+	return false
+}
+
 // ProcessCommitments performs discrepancy detection or resolution.
+//
+// Discrepancies are detected as soon as two workers submit differing commitments for the primary
+// scheduler's proposal.
 func (p *Pool) ProcessCommitments(c *scheduler.Committee, allowedStragglers uint16, timeout bool) (*SchedulerCommitment, error) {
-	sc, err := p.processCommitments(c, allowedStragglers, timeout)
+	return p.ProcessCommitmentsWithQuorum(c, allowedStragglers, 0, true, timeout)
+}
+
+// ProcessCommitmentsWithQuorum performs discrepancy detection or resolution.
+//
+// During discrepancy detection, the round can be finalized as soon as the given percentage of
+// workers submitted commitments matching the scheduler's commitment, as long as no discrepancy
+// has been observed so far. Zero percentage requires all workers, except for the allowed
+// stragglers, to commit.
+//
+// In case early discrepancy detection is enabled, a discrepancy is detected as soon as two workers
+// submit differing commitments for the primary scheduler's proposal, even if the scheduler hasn't
+// committed yet.
+func (p *Pool) ProcessCommitmentsWithQuorum(
+	c *scheduler.Committee,
+	allowedStragglers uint16,
+	quorumPercent uint8,
+	earlyDiscrepancy bool,
+	timeout bool,
+) (*SchedulerCommitment, error) {
+	sc, err := p.processCommitments(c, allowedStragglers, quorumPercent, earlyDiscrepancy, timeout)
 	switch err {
 	case ErrDiscrepancyDetected:
 		// Switch to discrepancy resolution.
@@ -325,17 +438,21 @@ func (p *Pool) ProcessCommitments(c *scheduler.Committee, allowedStragglers uint
 	return sc, err
 }
 
-func (p *Pool) processCommitments(c *scheduler.Committee, allowedStragglers uint16, timeout bool) (*SchedulerCommitment, error) { // nolint: gocyclo
+func (p *Pool) processCommitments(c *scheduler.Committee, allowedStragglers uint16, quorumPercent uint8, earlyDiscrepancy bool, timeout bool) (*SchedulerCommitment, error) { // nolint: gocyclo
 	// Ensure we have at least scheduler's vote.
 	sc, ok := p.SchedulerCommitments[p.HighestRank]
 	switch {
 	case !ok && timeout:
 		// The round timer expired, but the schedulers haven't submitted any commitments.
 		return nil, ErrNoSchedulerCommitment
+	case !ok && earlyDiscrepancy && !p.Discrepancy && p.hasPrimaryDiscrepancy(c):
+		// Differing commitments for the primary scheduler's proposal are a proof of
+		// a discrepancy, so there is no need to wait for the scheduler to commit.
+		p.HighestRank = 0
+		return nil, ErrDiscrepancyDetected
 	case !ok:
 		// Wait for additional commitments or until the round timer expires.
 		return nil, ErrStillWaiting
-
 	}
 
 	// Gather votes.
@@ -400,6 +517,10 @@ func (p *Pool) processCommitments(c *scheduler.Committee, allowedStragglers uint
 
 		// Check if the majority has been reached.
 		required := total - int(allowedStragglers)
+		if quorum := finalizationQuorum(total, quorumPercent); quorum > 0 && quorum < required {
+			// Finalize early once the quorum of matching votes has been reached.
+			required = quorum
+		}
 		for _, v := range votes {
 			// The map should have exactly one key/value pair, which indicates how many votes
 			// the scheduler's commitment has received.
@@ -442,6 +563,12 @@ func (p *Pool) processCommitments(c *scheduler.Committee, allowedStragglers uint
 		case best < required:
 			// Wait for additional commitments or until the round timer expires.
 			return nil, ErrStillWaiting
+		case sc.Commitment == nil && timeout:
+			// The majority has been reached, but the scheduler never committed.
+			return nil, ErrNoSchedulerCommitment
+		case sc.Commitment == nil:
+			// Wait for the scheduler's commitment or until the round timer expires.
+			return nil, ErrStillWaiting
 		case hash != sc.Commitment.ToVote():
 			// The scheduler's commitment hasn't received the majority of the votes.
 			return nil, ErrBadSchedulerCommitment
@@ -450,3 +577,12 @@ func (p *Pool) processCommitments(c *scheduler.Committee, allowedStragglers uint
 
 	return sc, nil
 }
+
+// finalizationQuorum returns the number of matching votes out of the given total that satisfy
+// the given quorum percentage, rounded up. Zero is returned if the quorum is disabled.
+func finalizationQuorum(total int, quorumPercent uint8) int {
+	if quorumPercent == 0 {
+		return 0
+	}
+	return (total*int(quorumPercent) + 99) / 100
+}
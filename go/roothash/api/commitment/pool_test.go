@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -242,6 +243,68 @@ func TestProcess(t *testing.T) {
 		require.Len(t, sc.Votes, 2)
 	})
 
+	t.Run("Happy path, no discrepancy, finalization quorum", func(t *testing.T) {
+		for _, tc := range []struct {
+			quorumPercent     uint8
+			allowedStragglers uint16
+			required          int
+		}{
+			{0, 0, 4},
+			{100, 0, 4},
+			{75, 0, 3},
+			{67, 0, 3},
+			{51, 0, 3},
+			{75, 2, 2}, // Allowed stragglers take precedence when less votes are required.
+		} {
+			pool := NewPool()
+
+			for i := 0; i < tc.required; i++ {
+				// Not enough votes.
+				sc, err = pool.ProcessCommitmentsWithQuorum(committee, tc.allowedStragglers, tc.quorumPercent, true, false)
+				require.ErrorIs(t, err, ErrStillWaiting, "quorum: %d%%", tc.quorumPercent)
+				require.Nil(t, sc)
+
+				// Add new commitment.
+				ec := generateMemberCommitment(committee, lastBlock, i, 0)
+				err = pool.AddVerifiedExecutorCommitment(committee, ec)
+				require.NoError(t, err)
+			}
+
+			// Enough votes.
+			sc, err = pool.ProcessCommitmentsWithQuorum(committee, tc.allowedStragglers, tc.quorumPercent, true, false)
+			require.NoError(t, err, "quorum: %d%%", tc.quorumPercent)
+			require.NotNil(t, sc)
+			require.Len(t, sc.Votes, tc.required)
+		}
+	})
+
+	t.Run("Discrepancy detection, finalization quorum", func(t *testing.T) {
+		pool := NewPool()
+
+		for i := 0; i < 2; i++ {
+			ec := generateMemberCommitment(committee, lastBlock, i+1, 1)
+			err = pool.AddVerifiedExecutorCommitment(committee, ec)
+			require.NoError(t, err)
+		}
+
+		// A differing commitment arriving before the quorum has been reached must still trigger
+		// discrepancy resolution, even though the quorum would be reached with the remaining
+		// worker's commitment.
+		ec := generateMemberCommitment(committee, lastBlock, 3, 1)
+		ec.Header.Header.InMessagesCount = 10
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		ec = generateMemberCommitment(committee, lastBlock, 0, 1)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		sc, err = pool.ProcessCommitmentsWithQuorum(committee, 0, 75, true, false)
+		require.ErrorIs(t, err, ErrDiscrepancyDetected)
+		require.Nil(t, sc)
+		require.True(t, pool.Discrepancy)
+	})
+
 	t.Run("Happy path, discrepancy", func(t *testing.T) {
 		pool := NewPool()
 
@@ -313,6 +376,144 @@ func TestProcess(t *testing.T) {
 		require.Equal(t, uint64(math.MaxUint64), pool.HighestRank)
 	})
 
+	t.Run("Early discrepancy detection, no primary scheduler commitment", func(t *testing.T) {
+		pool := NewPool()
+
+		// One commit from a worker.
+		ec := generateMemberCommitment(committee, lastBlock, 0, 1)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		// Not enough votes, no timeout.
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.ErrorIs(t, err, ErrStillWaiting)
+		require.Nil(t, sc)
+
+		// Failures are not a proof of a discrepancy.
+		ec = generateMemberFailure(committee, lastBlock, 3, 1)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.ErrorIs(t, err, ErrStillWaiting)
+		require.Nil(t, sc)
+
+		// Another worker disagrees.
+		ec = generateMemberCommitment(committee, lastBlock, 2, 1)
+		ec.Header.Header.InMessagesCount = 10
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		// Without early discrepancy detection, the pool waits for the scheduler to commit.
+		sc, err = pool.ProcessCommitmentsWithQuorum(committee, 0, 0, false, false)
+		require.ErrorIs(t, err, ErrStillWaiting)
+		require.Nil(t, sc)
+		sc, err = pool.ProcessCommitmentsWithQuorum(committee, 0, 0, false, true)
+		require.ErrorIs(t, err, ErrNoSchedulerCommitment)
+		require.Nil(t, sc)
+		require.False(t, pool.Discrepancy)
+		require.Equal(t, uint64(math.MaxUint64), pool.HighestRank)
+
+		// Discrepancy detected without waiting for the scheduler or the round timeout.
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.ErrorIs(t, err, ErrDiscrepancyDetected)
+		require.Nil(t, sc)
+		require.True(t, pool.Discrepancy)
+		require.Equal(t, uint64(0), pool.HighestRank)
+
+		// The majority of backup workers (3/4) agrees with the first worker.
+		for i := 5; i < 8; i++ {
+			ec = generateMemberCommitment(committee, lastBlock, i, 1)
+			err = pool.AddVerifiedExecutorCommitment(committee, ec)
+			require.NoError(t, err)
+		}
+
+		// Other workers cannot commit during discrepancy resolution.
+		ec = generateMemberCommitment(committee, lastBlock, 2, 0)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.ErrorIs(t, err, ErrBadExecutorCommitment)
+
+		// Majority reached, but no scheduler commitment, no timeout.
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.ErrorIs(t, err, ErrStillWaiting)
+		require.Nil(t, sc)
+
+		// Majority reached, but no scheduler commitment, timeout.
+		sc, err = pool.ProcessCommitments(committee, 0, true)
+		require.ErrorIs(t, err, ErrNoSchedulerCommitment)
+		require.Nil(t, sc)
+
+		// The scheduler can still commit.
+		ec = generateMemberCommitment(committee, lastBlock, 1, 1)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.NoError(t, err)
+		require.NotNil(t, sc)
+		require.Equal(t, ec, sc.Commitment)
+	})
+
+	t.Run("Early discrepancy detection, commitment order", func(t *testing.T) {
+		// Discrepancy must be detected as soon as two differing commitments are in the pool,
+		// regardless of the order in which commitments arrive.
+		for _, order := range [][]int{
+			{0, 2, 3},
+			{0, 3, 2},
+			{2, 0, 3},
+			{2, 3, 0},
+			{3, 0, 2},
+			{3, 2, 0},
+		} {
+			pool := NewPool()
+
+			var detected int
+			for n, i := range order {
+				ec := generateMemberCommitment(committee, lastBlock, i, 1)
+				if i == 3 {
+					ec.Header.Header.InMessagesCount = 10
+				}
+				err = pool.AddVerifiedExecutorCommitment(committee, ec)
+				require.NoError(t, err)
+
+				sc, err = pool.ProcessCommitments(committee, 0, false)
+				require.Nil(t, sc)
+				if err == ErrDiscrepancyDetected {
+					detected = n + 1
+					break
+				}
+				require.ErrorIs(t, err, ErrStillWaiting)
+			}
+
+			// Worker 3 disagrees with the others, so the discrepancy is detected once both
+			// worker 3 and any other worker committed.
+			expected := max(slices.Index(order, 3), 1) + 1
+			require.Equal(t, expected, detected, "order: %v", order)
+			require.Equal(t, uint64(0), pool.HighestRank)
+		}
+	})
+
+	t.Run("Early discrepancy detection, equivocating worker", func(t *testing.T) {
+		pool := NewPool()
+
+		ec := generateMemberCommitment(committee, lastBlock, 2, 1)
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.NoError(t, err)
+
+		// The same worker submits a differing commitment.
+		ec = generateMemberCommitment(committee, lastBlock, 2, 1)
+		ec.Header.Header.InMessagesCount = 10
+		err = pool.AddVerifiedExecutorCommitment(committee, ec)
+		require.ErrorIs(t, err, ErrAlreadyCommitted)
+
+		// A single node cannot trigger discrepancy resolution.
+		sc, err = pool.ProcessCommitments(committee, 0, false)
+		require.ErrorIs(t, err, ErrStillWaiting)
+		require.Nil(t, sc)
+		require.False(t, pool.Discrepancy)
+		require.Equal(t, uint64(math.MaxUint64), pool.HighestRank)
+	})
+
 	t.Run("Discrepancy detection, not enough votes, primary scheduler", func(t *testing.T) {
 		pool := NewPool()
 
@@ -775,6 +976,28 @@ func TestVerify(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("Verify signatures", func(t *testing.T) {
+		ecs := make([]ExecutorCommitment, 0, 3)
+		for _, signer := range []signature.Signer{worker, backup, worker} {
+			ec := generateCommitment(signer.Public(), worker.Public(), lastBlock, nil, nil)
+			err = ec.Sign(signer, id)
+			require.NoError(t, err)
+			ecs = append(ecs, *ec)
+		}
+
+		// Verify valid signatures.
+		errs := VerifyExecutorCommitmentSignatures(id, ecs)
+		require.Equal(t, []error{nil, nil, nil}, errs)
+
+		// Verify invalid signature.
+		ecs[1].Signature[0]++ // Corrupt.
+
+		errs = VerifyExecutorCommitmentSignatures(id, ecs)
+		require.NoError(t, errs[0])
+		require.Error(t, errs[1])
+		require.NoError(t, errs[2])
+	})
+
 	t.Run("Validate basic", func(t *testing.T) {
 		// Valid commitment.
 		ec := generateCommitment(worker.Public(), worker.Public(), lastBlock, nil, nil)
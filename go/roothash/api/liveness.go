@@ -23,6 +23,16 @@ type LivenessStatistics struct {
 	// The list is ordered according to the committee arrangement (i.e., the counter at index i
 	// holds the value for the node at index i in the committee).
 	MissedProposals []uint64 `json:"missed_proposals"`
+
+	// LateRounds is a list that records the number of rounds in which a node submitted a correct
+	// commitment only after the round has already been finalized, within the late commitment
+	// window (see ConsensusParameters.MaxLateCommitmentBlocks). Such rounds are not included in
+	// LiveRounds, but still count towards the liveness of the node.
+	//
+	// The list is ordered according to the committee arrangement (i.e., the counter at index i
+	// holds the value for the node at index i in the committee). It is only allocated once the
+	// first late round is recorded.
+	LateRounds []uint64 `json:"late_rounds,omitempty"`
 }
 
 // NewLivenessStatistics creates a new instance of per-epoch liveness statistics.
@@ -34,3 +44,30 @@ func NewLivenessStatistics(numNodes int) *LivenessStatistics {
 		MissedProposals:    make([]uint64, numNodes),
 	}
 }
+
+// RecordLateRound records a late but correct round for the node at the given committee index.
+func (s *LivenessStatistics) RecordLateRound(idx int) {
+	if s.LateRounds == nil {
+		s.LateRounds = make([]uint64, len(s.LiveRounds))
+	}
+	s.LateRounds[idx]++
+}
+
+// LateRoundsAt returns the number of late but correct rounds for the node at the given committee
+// index.
+func (s *LivenessStatistics) LateRoundsAt(idx int) uint64 {
+	if idx >= len(s.LateRounds) {
+		return 0
+	}
+	return s.LateRounds[idx]
+}
+
+// MissedRoundsAt returns the number of rounds for which the node at the given committee index
+// did not submit a correct commitment at all, neither in time nor late.
+func (s *LivenessStatistics) MissedRoundsAt(idx int) uint64 {
+	credited := s.LiveRounds[idx] + s.LateRoundsAt(idx)
+	if credited >= s.TotalRounds {
+		return 0
+	}
+	return s.TotalRounds - credited
+}
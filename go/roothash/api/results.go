@@ -1,6 +1,9 @@
 package api
 
-import "github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
 
 // RoundResults contains information about how a particular round was executed by the consensus
 // layer.
@@ -14,4 +17,8 @@ type RoundResults struct {
 	// BadComputeEntities are the public keys of compute nodes' controlling entities that
 	// negatively contributed to the round by causing discrepancies.
 	BadComputeEntities []signature.PublicKey `json:"bad_compute_entities,omitempty"`
+
+	// BatchSummary is the summary of resources consumed by the round's batch, if it was provided
+	// by the runtime.
+	BatchSummary *commitment.BatchSummary `json:"batch_summary,omitempty"`
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
 )
 
 func TestRoundResultsSerialization(t *testing.T) {
@@ -38,6 +39,13 @@ func TestRoundResultsSerialization(t *testing.T) {
 				signature.NewPublicKey("0000000000000000000000000000000000000000000000000000000000000001"),
 			},
 		}, "o2htZXNzYWdlc4GkZGNvZGUYKmVpbmRleAFmbW9kdWxlZHRlc3RmcmVzdWx0a3Rlc3QtcmVzdWx0dGJhZF9jb21wdXRlX2VudGl0aWVzgVggAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAF1Z29vZF9jb21wdXRlX2VudGl0aWVzglggAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABYIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC"},
+		{RoundResults{
+			BatchSummary: &commitment.BatchSummary{
+				GasUsed:   1000,
+				BatchSize: 256,
+				TxCount:   2,
+			},
+		}, "oW1iYXRjaF9zdW1tYXJ5o2hnYXNfdXNlZBkD6Gh0eF9jb3VudAJqYmF0Y2hfc2l6ZRkBAA=="},
 	} {
 		enc := cbor.Marshal(tc.rr)
 		require.Equal(tc.expectedBase64, base64.StdEncoding.EncodeToString(enc), "serialization should match")
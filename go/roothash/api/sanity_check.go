@@ -51,7 +51,8 @@ func (c *ConsensusParameterChanges) SanityCheck() error {
 		c.MaxRuntimeMessages == nil &&
 		c.MaxInRuntimeMessages == nil &&
 		c.MaxEvidenceAge == nil &&
-		c.MaxPastRootsStored == nil {
+		c.MaxPastRootsStored == nil &&
+		c.MaxLateCommitmentBlocks == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
 	return nil
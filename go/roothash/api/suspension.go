@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+)
+
+// SuspensionReason is the reason why a runtime has been suspended.
+type SuspensionReason uint8
+
+const (
+	// SuspensionReasonNone is the reason used when the runtime is not suspended.
+	SuspensionReasonNone SuspensionReason = 0
+	// SuspensionReasonInsufficientCommittee is the reason used when no executor committee could
+	// be elected for the runtime (e.g., because no nodes have registered for the runtime and
+	// paid the maintenance fees).
+	SuspensionReasonInsufficientCommittee SuspensionReason = 1
+	// SuspensionReasonMissingKeyManager is the reason used when no executor committee could be
+	// elected for the runtime and the key manager runtime it depends on is not available.
+	SuspensionReasonMissingKeyManager SuspensionReason = 2
+	// SuspensionReasonInsufficientStake is the reason used when the runtime owner no longer has
+	// enough stake to cover the entity and runtime deposits (e.g., because it has debonded).
+	SuspensionReasonInsufficientStake SuspensionReason = 3
+)
+
+// String returns a string representation of a suspension reason.
+func (r SuspensionReason) String() string {
+	switch r {
+	case SuspensionReasonNone:
+		return "none"
+	case SuspensionReasonInsufficientCommittee:
+		return "insufficient committee"
+	case SuspensionReasonMissingKeyManager:
+		return "missing key manager"
+	case SuspensionReasonInsufficientStake:
+		return "insufficient stake"
+	default:
+		return "[unknown suspension reason]"
+	}
+}
+
+// MarshalText encodes a SuspensionReason into text form.
+func (r SuspensionReason) MarshalText() ([]byte, error) {
+	switch r {
+	case SuspensionReasonNone,
+		SuspensionReasonInsufficientCommittee,
+		SuspensionReasonMissingKeyManager,
+		SuspensionReasonInsufficientStake:
+		return []byte(r.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid suspension reason: %d", r)
+	}
+}
+
+// UnmarshalText decodes a text slice into a SuspensionReason.
+func (r *SuspensionReason) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case SuspensionReasonNone.String():
+		*r = SuspensionReasonNone
+	case SuspensionReasonInsufficientCommittee.String():
+		*r = SuspensionReasonInsufficientCommittee
+	case SuspensionReasonMissingKeyManager.String():
+		*r = SuspensionReasonMissingKeyManager
+	case SuspensionReasonInsufficientStake.String():
+		*r = SuspensionReasonInsufficientStake
+	default:
+		return fmt.Errorf("invalid suspension reason: %s", string(text))
+	}
+	return nil
+}
+
+// RuntimeSuspendedEvent is an event emitted when a runtime is suspended.
+type RuntimeSuspendedEvent struct {
+	// Reason is the reason why the runtime has been suspended.
+	Reason SuspensionReason `json:"reason"`
+}
+
+// EventKind returns a string representation of this event's kind.
+func (e *RuntimeSuspendedEvent) EventKind() string {
+	return "runtime_suspended"
+}
+
+// RuntimeResumedEvent is an event emitted when a previously suspended runtime is resumed.
+type RuntimeResumedEvent struct{}
+
+// EventKind returns a string representation of this event's kind.
+func (e *RuntimeResumedEvent) EventKind() string {
+	return "runtime_resumed"
+}
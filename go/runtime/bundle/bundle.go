@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/sigstruct"
 	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
@@ -569,6 +570,13 @@ func Open(fn string, opts ...OpenOption) (_ *Bundle, err error) {
 		return nil, fmt.Errorf("runtime/bundle: invalid manifest (got: %s, expected: %s)", manifestHash.Hex(), h.Hex())
 	}
 
+	// Verify the detached manifest signature, if requested.
+	if sig := options.manifestSignature; sig != nil {
+		if err = VerifyManifestSignature(&manifest, sig, options.trustedSigners); err != nil {
+			return nil, err
+		}
+	}
+
 	// Ensure the bundle is well-formed.
 	bnd := &Bundle{
 		Manifest:     &manifest,
@@ -639,6 +647,9 @@ type ManifestRewriterFunc func(*Manifest)
 // OpenOptions are options for opening bundle files.
 type OpenOptions struct {
 	manifestHash *hash.Hash
+
+	manifestSignature *signature.Signature
+	trustedSigners    []signature.PublicKey
 }
 
 // NewOpenOptions creates options using default and given values.
@@ -659,3 +670,13 @@ func WithManifestHash(manifestHash hash.Hash) OpenOption {
 		o.manifestHash = &manifestHash
 	}
 }
+
+// WithManifestSignature sets the detached manifest signature for verification.
+//
+// If any trusted signers are given, the signature must have been produced by one of them.
+func WithManifestSignature(sig *signature.Signature, trustedSigners ...signature.PublicKey) OpenOption {
+	return func(o *OpenOptions) {
+		o.manifestSignature = sig
+		o.trustedSigners = trustedSigners
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 )
@@ -97,6 +98,42 @@ func TestBundle(t *testing.T) {
 		require.ErrorContains(t, err, "invalid manifest (got: eaf1a8c7362dfd3381b2a0e6c63c1ec25732563a4ab180751acb9717435c5eef, expected: 0000000000000000000000000000000000000000000000000000000000000000)")
 	})
 
+	t.Run("Open_WithManifestSignature", func(t *testing.T) {
+		signer := memorySigner.NewTestSigner("runtime/bundle: manifest signature test")
+		otherSigner := memorySigner.NewTestSigner("runtime/bundle: manifest signature test (other)")
+
+		bnd, err := Open(bundleFn)
+		require.NoError(t, err, "Open")
+		sig, err := SignManifest(signer, bnd.Manifest)
+		require.NoError(t, err, "SignManifest")
+
+		// Round-trip through the signature file.
+		sigFn := bundleFn + ".sig"
+		err = WriteManifestSignature(sigFn, sig)
+		require.NoError(t, err, "WriteManifestSignature")
+		sig, err = ReadManifestSignature(sigFn)
+		require.NoError(t, err, "ReadManifestSignature")
+
+		_, err = Open(bundleFn, WithManifestSignature(sig))
+		require.NoError(t, err, "Open_WithManifestSignature")
+
+		_, err = Open(bundleFn, WithManifestSignature(sig, signer.Public()))
+		require.NoError(t, err, "Open_WithManifestSignature(trusted)")
+
+		_, err = Open(bundleFn, WithManifestSignature(sig, otherSigner.Public()))
+		require.ErrorContains(t, err, "untrusted signer")
+
+		// Signature over a different manifest.
+		bnd.Rewrite(func(m *Manifest) {
+			m.Name = "other-runtime"
+		})
+		otherSig, err := SignManifest(signer, bnd.Manifest)
+		require.NoError(t, err, "SignManifest")
+
+		_, err = Open(bundleFn, WithManifestSignature(otherSig))
+		require.ErrorContains(t, err, "invalid manifest signature")
+	})
+
 	t.Run("Rewrite", func(t *testing.T) {
 		bnd, err := Open(bundleFn)
 		require.NoError(t, err, "Open")
@@ -11,24 +11,32 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
 const (
-	// retryInterval is the time interval between failed bundle downloads.
+	// retryInterval is the maximum time interval between failed bundle downloads.
 	retryInterval = 15 * time.Minute
 
+	// minRetryInterval is the initial time interval between failed bundle downloads.
+	minRetryInterval = 15 * time.Second
+
 	// requestTimeout is the time limit for http client requests.
 	requestTimeout = time.Minute
 
@@ -131,6 +139,40 @@ func WithBundleVolumes(volumes map[string]*volume.Volume) AddOption {
 	}
 }
 
+// DownloadStatus is the status of a bundle pending download.
+type DownloadStatus struct {
+	// ManifestHash is the hash of the bundle manifest.
+	ManifestHash hash.Hash `json:"manifest_hash"`
+
+	// Attempts is the number of failed download attempts.
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastError is the error of the last failed download attempt.
+	LastError string `json:"last_error,omitempty"`
+
+	// NextAttempt is the time of the next download attempt.
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// downloadState is the internal state of a bundle pending download.
+type downloadState struct {
+	attempts    int
+	lastErr     error
+	nextAttempt time.Time
+	backoff     *backoff.ExponentialBackOff
+}
+
+func newDownloadState() *downloadState {
+	boff := cmnBackoff.NewExponentialBackOff()
+	boff.InitialInterval = minRetryInterval
+	boff.MaxInterval = retryInterval
+	boff.Reset()
+
+	return &downloadState{
+		backoff: boff,
+	}
+}
+
 // Manager is responsible for managing bundles.
 type Manager struct {
 	mu       sync.RWMutex
@@ -146,15 +188,17 @@ type Manager struct {
 	runtimeBaseURLs map[common.Namespace][]string
 	globalBaseURLs  []string
 
-	triggerCh     chan struct{}
-	downloadQueue map[common.Namespace][]hash.Hash
-	cleanupQueue  map[common.Namespace]version.Version
+	triggerCh           chan struct{}
+	downloadQueue       map[common.Namespace][]hash.Hash
+	downloadStates      map[hash.Hash]*downloadState
+	downloadDescriptors map[common.Namespace]*registry.Runtime
+	cleanupQueue        map[common.Namespace]version.Version
 
 	client        *http.Client
 	store         ManifestStore
 	volumeManager VolumeManager
 
-	logger logging.Logger
+	logger *logging.Logger
 }
 
 // NewManager creates a new bundle manager.
@@ -198,21 +242,23 @@ func NewManager(dataDir string, runtimeIDs []common.Namespace, store ManifestSto
 	}
 
 	return &Manager{
-		startOne:           cmSync.NewOne(),
-		dataDir:            dataDir,
-		bundleDir:          ExplodedPath(dataDir),
-		tmpBundleDir:       TmpBundlePath(dataDir),
-		maxBundleSizeBytes: bundleSize,
-		runtimeIDs:         runtimes,
-		globalBaseURLs:     globalBaseURLs,
-		runtimeBaseURLs:    runtimeBaseURLs,
-		triggerCh:          make(chan struct{}, 1),
-		downloadQueue:      make(map[common.Namespace][]hash.Hash),
-		cleanupQueue:       make(map[common.Namespace]version.Version),
-		client:             &client,
-		store:              store,
-		volumeManager:      volumeManager,
-		logger:             *logger,
+		startOne:            cmSync.NewOne(),
+		dataDir:             dataDir,
+		bundleDir:           ExplodedPath(dataDir),
+		tmpBundleDir:        TmpBundlePath(dataDir),
+		maxBundleSizeBytes:  bundleSize,
+		runtimeIDs:          runtimes,
+		globalBaseURLs:      globalBaseURLs,
+		runtimeBaseURLs:     runtimeBaseURLs,
+		triggerCh:           make(chan struct{}, 1),
+		downloadQueue:       make(map[common.Namespace][]hash.Hash),
+		downloadStates:      make(map[hash.Hash]*downloadState),
+		downloadDescriptors: make(map[common.Namespace]*registry.Runtime),
+		cleanupQueue:        make(map[common.Namespace]version.Version),
+		client:              &client,
+		store:               store,
+		volumeManager:       volumeManager,
+		logger:              logger,
 	}, nil
 }
 
@@ -289,12 +335,12 @@ func (m *Manager) run(ctx context.Context) {
 	}
 
 	// Start the main task responsible for managing bundles.
-	ticker := time.NewTicker(retryInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(retryInterval)
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 		case <-m.triggerCh:
 		case <-ctx.Done():
 			m.logger.Info("stopping")
@@ -303,6 +349,8 @@ func (m *Manager) run(ctx context.Context) {
 
 		m.download()
 		m.clean()
+
+		timer.Reset(m.nextDownloadDelay())
 	}
 }
 
@@ -408,8 +456,11 @@ func (m *Manager) RemoveTemporary(tmpPath string) error {
 // Download updates the checksums of bundles pending download for the given runtime.
 //
 // Any existing checksums in the download queue for the given runtime are removed
-// and replaced with the given ones.
-func (m *Manager) Download(runtimeID common.Namespace, manifestHashes []hash.Hash) {
+// and replaced with the given ones. Downloaded bundles are verified against the
+// given runtime descriptor.
+func (m *Manager) Download(rt *registry.Runtime, manifestHashes []hash.Hash) {
+	runtimeID := rt.ID
+
 	// Download bundles only for the configured runtimes.
 	if _, ok := m.runtimeIDs[runtimeID]; !ok {
 		return
@@ -430,11 +481,24 @@ func (m *Manager) Download(runtimeID common.Namespace, manifestHashes []hash.Has
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// Keep the retry state of bundles that remain queued.
+	for _, h := range m.downloadQueue[runtimeID] {
+		if !slices.Contains(manifestHashes, h) {
+			delete(m.downloadStates, h)
+		}
+	}
 	if len(manifestHashes) == 0 {
 		delete(m.downloadQueue, runtimeID)
+		delete(m.downloadDescriptors, runtimeID)
 		return
 	}
 	m.downloadQueue[runtimeID] = manifestHashes
+	m.downloadDescriptors[runtimeID] = rt
+	for _, h := range manifestHashes {
+		if _, ok := m.downloadStates[h]; !ok {
+			m.downloadStates[h] = newDownloadState()
+		}
+	}
 
 	// Trigger immediate download and clean-up of bundles.
 	select {
@@ -470,60 +534,121 @@ func (m *Manager) Cleanup(runtimeID common.Namespace, version version.Version) {
 	}
 }
 
+// DownloadStatus returns the status of the bundles pending download for the given runtime.
+func (m *Manager) DownloadStatus(runtimeID common.Namespace) []DownloadStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var statuses []DownloadStatus
+	for _, h := range m.downloadQueue[runtimeID] {
+		status := DownloadStatus{
+			ManifestHash: h,
+		}
+		if state, ok := m.downloadStates[h]; ok {
+			status.Attempts = state.attempts
+			status.NextAttempt = state.nextAttempt
+			if state.lastErr != nil {
+				status.LastError = state.lastErr.Error()
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// nextDownloadDelay returns the time until the next pending bundle download attempt.
+func (m *Manager) nextDownloadDelay() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	delay := retryInterval
+	for _, state := range m.downloadStates {
+		delay = min(delay, max(time.Until(state.nextAttempt), 0))
+	}
+	return delay
+}
+
 func (m *Manager) download() {
-	m.logger.Info("downloading bundles")
 	for runtimeID := range m.runtimeIDs {
 		m.downloadBundles(runtimeID)
 	}
 }
 
 func (m *Manager) downloadBundles(runtimeID common.Namespace) {
-	// Try to download queued bundles.
+	// Determine which queued bundles are due for a download attempt.
+	now := time.Now()
 	m.mu.RLock()
-	hashes := m.downloadQueue[runtimeID]
+	var hashes []hash.Hash
+	for _, h := range m.downloadQueue[runtimeID] {
+		if state, ok := m.downloadStates[h]; ok && state.nextAttempt.After(now) {
+			continue
+		}
+		hashes = append(hashes, h)
+	}
+	rt := m.downloadDescriptors[runtimeID]
 	m.mu.RUnlock()
 
+	if len(hashes) == 0 {
+		return
+	}
+
+	m.logger.Info("downloading bundles",
+		"runtime_id", runtimeID,
+	)
+
+	// Try to download queued bundles.
 	downloaded := make(map[hash.Hash]struct{})
+	failed := make(map[hash.Hash]error)
 	for _, hash := range hashes {
-		if err := m.downloadBundle(runtimeID, hash); err != nil {
+		if err := m.downloadBundle(rt, hash); err != nil {
 			m.logger.Error("failed to download bundle",
 				"err", err,
 				"runtime_id", runtimeID,
 				"manifest_hash", hash.Hex(),
 			)
+			failed[hash] = err
 			continue
 		}
 		downloaded[hash] = struct{}{}
 	}
 
-	// Remove downloaded bundles from the queue.
+	// Remove downloaded bundles from the queue and schedule retries for failed ones.
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	var pending []hash.Hash
 	for _, hash := range m.downloadQueue[runtimeID] {
 		if _, ok := downloaded[hash]; ok {
+			delete(m.downloadStates, hash)
 			continue
 		}
+		if err, ok := failed[hash]; ok {
+			if state, ok := m.downloadStates[hash]; ok {
+				state.attempts++
+				state.lastErr = err
+				state.nextAttempt = time.Now().Add(state.backoff.NextBackOff())
+			}
+		}
 		pending = append(pending, hash)
 	}
 	if len(pending) == 0 {
 		delete(m.downloadQueue, runtimeID)
+		delete(m.downloadDescriptors, runtimeID)
 		return
 	}
 	m.downloadQueue[runtimeID] = pending
 }
 
-func (m *Manager) downloadBundle(runtimeID common.Namespace, manifestHash hash.Hash) error {
+func (m *Manager) downloadBundle(rt *registry.Runtime, manifestHash hash.Hash) error {
 	var errs error
 
 	if m.store.HasManifest(manifestHash) {
 		return nil
 	}
 
-	for _, baseURLs := range [][]string{m.runtimeBaseURLs[runtimeID], m.globalBaseURLs} {
+	for _, baseURLs := range [][]string{m.runtimeBaseURLs[rt.ID], m.globalBaseURLs} {
 		for _, baseURL := range baseURLs {
-			if err := m.tryDownloadBundle(manifestHash, baseURL); err != nil {
+			if err := m.tryDownloadBundle(rt, manifestHash, baseURL); err != nil {
 				errs = errors.Join(errs, err)
 				continue
 			}
@@ -535,7 +660,7 @@ func (m *Manager) downloadBundle(runtimeID common.Namespace, manifestHash hash.H
 	return errs
 }
 
-func (m *Manager) tryDownloadBundle(manifestHash hash.Hash, baseURL string) error {
+func (m *Manager) tryDownloadBundle(rt *registry.Runtime, manifestHash hash.Hash, baseURL string) error {
 	metaURL, err := url.JoinPath(baseURL, manifestHash.Hex())
 	if err != nil {
 		m.logger.Error("failed to construct metadata URL",
@@ -568,7 +693,12 @@ func (m *Manager) tryDownloadBundle(manifestHash hash.Hash, baseURL string) erro
 	}
 	defer os.Remove(src)
 
-	manifest, err := m.explodeBundle(src, WithBundleManifestHash(manifestHash))
+	// Make sure the bundle matches the deployments of the runtime descriptor.
+	validator := func(bnd *Bundle) error {
+		return Verify(bnd, rt)
+	}
+
+	manifest, err := m.explodeBundle(src, WithBundleManifestHash(manifestHash), WithBundleValidator(validator))
 	if err != nil {
 		m.logger.Error("failed to explode bundle",
 			"err", err,
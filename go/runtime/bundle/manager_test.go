@@ -1,11 +1,22 @@
 package bundle
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/volume"
 )
 
@@ -76,3 +87,99 @@ func TestRegisterManifest(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, len(manifests), len(store.manifestHashes))
 }
+
+func TestDownload(t *testing.T) {
+	require := require.New(t)
+
+	var id common.Namespace
+	require.NoError(id.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"))
+
+	// Prepare a bundle to serve.
+	tmpDir := t.TempDir()
+	bnd := &Bundle{
+		Manifest: &Manifest{
+			Name: "test-runtime",
+			ID:   id,
+			Components: []*Component{
+				{
+					Kind:    component.RONL,
+					Version: version.Version{Major: 1, Minor: 2},
+					ELF: &ELFMetadata{
+						Executable: "runtime.bin",
+					},
+				},
+			},
+		},
+	}
+	require.NoError(bnd.Add("runtime.bin", NewBytesData(randBuffer(1))), "Add(elf)")
+	bundleFn := filepath.Join(tmpDir, "bundle.orc")
+	require.NoError(bnd.Write(bundleFn), "Write")
+	manifestHash := bnd.Manifest.Hash()
+
+	// Serve the bundle, failing the first request.
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, FileExtension):
+			http.ServeFile(w, r, bundleFn)
+		default:
+			fmt.Fprintf(w, "http://%s/%s%s\n", r.Host, manifestHash.Hex(), FileExtension)
+		}
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	store := newMockStore()
+	manager, err := NewManager(dataDir, []common.Namespace{id}, store, newMockVolumeManager())
+	require.NoError(err, "NewManager")
+	require.NoError(common.Mkdir(manager.tmpBundleDir))
+	require.NoError(common.Mkdir(manager.bundleDir))
+	manager.globalBaseURLs = []string{server.URL}
+
+	rt := &registry.Runtime{
+		ID: id,
+		Deployments: []*registry.VersionInfo{
+			{Version: version.Version{Major: 2}},
+		},
+	}
+
+	// The first attempt fails and is retried with backoff.
+	manager.Download(rt, []hash.Hash{manifestHash})
+	manager.download()
+	statuses := manager.DownloadStatus(id)
+	require.Len(statuses, 1)
+	require.Equal(manifestHash, statuses[0].ManifestHash)
+	require.Equal(1, statuses[0].Attempts)
+	require.Contains(statuses[0].LastError, "invalid status code 503")
+	require.True(statuses[0].NextAttempt.After(time.Now()), "next attempt should be delayed")
+	require.LessOrEqual(manager.nextDownloadDelay(), 2*minRetryInterval)
+
+	// Attempts are not made before the backoff expires.
+	manager.download()
+	require.EqualValues(1, requests.Load(), "no download should be attempted during backoff")
+
+	// Bundles not matching the descriptor are retried as well.
+	manager.downloadStates[manifestHash].nextAttempt = time.Time{}
+	manager.download()
+	statuses = manager.DownloadStatus(id)
+	require.Len(statuses, 1)
+	require.Equal(2, statuses[0].Attempts)
+	require.Contains(statuses[0].LastError, "does not match runtime descriptor")
+	require.False(store.HasManifest(manifestHash))
+
+	// Updating the descriptor keeps the retry state.
+	rt.Deployments[0].Version = version.Version{Major: 1, Minor: 2}
+	manager.Download(rt, []hash.Hash{manifestHash})
+	require.Equal(2, manager.DownloadStatus(id)[0].Attempts)
+
+	// Matching bundles are downloaded and removed from the queue.
+	manager.downloadStates[manifestHash].nextAttempt = time.Time{}
+	manager.download()
+	require.Empty(manager.DownloadStatus(id))
+	require.True(store.HasManifest(manifestHash))
+	require.Equal(retryInterval, manager.nextDownloadDelay())
+}
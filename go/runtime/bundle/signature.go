@@ -0,0 +1,67 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
+// SignatureFileExtension is the file extension used for storing detached bundle signatures.
+const SignatureFileExtension = FileExtension + ".sig"
+
+// ManifestSignatureContext is the context used for detached signatures over bundle manifests.
+var ManifestSignatureContext = signature.NewContext("oasis-core/runtime/bundle: manifest")
+
+// SignManifest produces a detached signature over the hash of the given manifest.
+//
+// As the manifest includes digests of all of the other files in the bundle, the signature
+// covers the entire bundle.
+func SignManifest(signer signature.Signer, manifest *Manifest) (*signature.Signature, error) {
+	h := manifest.Hash()
+	sig, err := signature.Sign(signer, ManifestSignatureContext, h[:])
+	if err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to sign manifest: %w", err)
+	}
+	return sig, nil
+}
+
+// VerifyManifestSignature verifies a detached signature over the hash of the given manifest.
+//
+// If any trusted signers are given, the signature must have been produced by one of them.
+func VerifyManifestSignature(manifest *Manifest, sig *signature.Signature, trustedSigners []signature.PublicKey) error {
+	if len(trustedSigners) > 0 && !slices.ContainsFunc(trustedSigners, sig.PublicKey.Equal) {
+		return fmt.Errorf("runtime/bundle: manifest signed by untrusted signer: %s", sig.PublicKey)
+	}
+	h := manifest.Hash()
+	if !sig.Verify(ManifestSignatureContext, h[:]) {
+		return fmt.Errorf("runtime/bundle: invalid manifest signature by %s", sig.PublicKey)
+	}
+	return nil
+}
+
+// WriteManifestSignature writes a PEM-encoded detached manifest signature to the given file.
+func WriteManifestSignature(fn string, sig *signature.Signature) error {
+	data, err := sig.MarshalPEM()
+	if err != nil {
+		return fmt.Errorf("runtime/bundle: failed to encode manifest signature: %w", err)
+	}
+	if err = os.WriteFile(fn, data, 0o600); err != nil {
+		return fmt.Errorf("runtime/bundle: failed to write manifest signature: %w", err)
+	}
+	return nil
+}
+
+// ReadManifestSignature reads a PEM-encoded detached manifest signature from the given file.
+func ReadManifestSignature(fn string) (*signature.Signature, error) {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("runtime/bundle: failed to read manifest signature: %w", err)
+	}
+	var sig signature.Signature
+	if err = sig.UnmarshalPEM(data); err != nil {
+		return nil, fmt.Errorf("runtime/bundle: malformed manifest signature: %w", err)
+	}
+	return &sig, nil
+}
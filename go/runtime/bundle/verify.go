@@ -0,0 +1,206 @@
+package bundle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+// Mismatch is a single difference between a runtime bundle and a runtime descriptor.
+type Mismatch struct {
+	// Component is the component the mismatch applies to (if any).
+	Component *component.ID `json:"component,omitempty"`
+
+	// Field is the name of the mismatched field.
+	Field string `json:"field"`
+
+	// Bundle is the value in the bundle.
+	Bundle string `json:"bundle"`
+
+	// Descriptor is the value (or the set of allowed values) in the runtime descriptor.
+	Descriptor string `json:"descriptor"`
+}
+
+// String returns a human readable representation of the mismatch.
+func (m Mismatch) String() string {
+	field := m.Field
+	if m.Component != nil {
+		field = fmt.Sprintf("%s: %s", m.Component, m.Field)
+	}
+	return fmt.Sprintf("%s (bundle: %s, descriptor: %s)", field, m.Bundle, m.Descriptor)
+}
+
+// VerificationError is the error returned when a runtime bundle does not match a runtime
+// descriptor.
+type VerificationError struct {
+	// Mismatches are all of the differences found between the bundle and the descriptor.
+	Mismatches []Mismatch
+}
+
+// Error implements error.
+func (e *VerificationError) Error() string {
+	msgs := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		msgs = append(msgs, m.String())
+	}
+	return fmt.Sprintf("runtime/bundle: bundle does not match runtime descriptor: %s", strings.Join(msgs, "; "))
+}
+
+// Verify verifies that the runtime bundle matches the given runtime descriptor.
+//
+// The runtime identifier, the RONL component versions and (for SGX runtimes) the enclave
+// identities are checked against the descriptor's deployments. In case any of them do not match,
+// a *VerificationError listing all of the mismatches is returned.
+func Verify(bnd *Bundle, rt *registry.Runtime) error {
+	if !bnd.Manifest.ID.Equal(&rt.ID) {
+		return &VerificationError{Mismatches: []Mismatch{{
+			Field:      "id",
+			Bundle:     bnd.Manifest.ID.String(),
+			Descriptor: rt.ID.String(),
+		}}}
+	}
+
+	comp, ok := bnd.Manifest.GetComponentByID(component.ID_RONL)
+	if !ok {
+		// Detached bundles only contain components which are not subject to deployments.
+		return nil
+	}
+
+	enclaves := func() ([]sgx.EnclaveIdentity, error) {
+		return bnd.EnclaveIdentities(component.ID_RONL)
+	}
+	mismatches, err := verifyComponent(comp, enclaves, rt)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) > 0 {
+		return &VerificationError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// VerifyComponent verifies that the exploded RONL component matches the given runtime descriptor.
+//
+// Only the enclave identities provided in the manifest are checked as the component's executables
+// are not inspected.
+func VerifyComponent(comp *ExplodedComponent, rt *registry.Runtime) error {
+	if !comp.ID().IsRONL() {
+		return nil
+	}
+
+	enclaves := func() ([]sgx.EnclaveIdentity, error) {
+		ids := make([]sgx.EnclaveIdentity, 0, len(comp.Identities))
+		for _, id := range comp.Identities {
+			ids = append(ids, id.Enclave)
+		}
+		return ids, nil
+	}
+	mismatches, err := verifyComponent(comp.Component, enclaves, rt)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) > 0 {
+		return &VerificationError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+func verifyComponent(comp *Component, enclaves func() ([]sgx.EnclaveIdentity, error), rt *registry.Runtime) ([]Mismatch, error) {
+	id := comp.ID()
+
+	deployment := rt.DeploymentForVersion(comp.Version)
+	if deployment == nil {
+		versions := make([]string, 0, len(rt.Deployments))
+		for _, d := range rt.Deployments {
+			versions = append(versions, d.Version.String())
+		}
+		return []Mismatch{{
+			Component:  &id,
+			Field:      "version",
+			Bundle:     comp.Version.String(),
+			Descriptor: fmt.Sprintf("[%s]", strings.Join(versions, ", ")),
+		}}, nil
+	}
+
+	switch rt.TEEHardware {
+	case node.TEEHardwareInvalid:
+		// Bundles may also include TEE metadata for runtimes that do not require a TEE.
+		return nil, nil
+	case node.TEEHardwareIntelSGX:
+		if comp.SGX == nil {
+			return []Mismatch{{
+				Component:  &id,
+				Field:      "tee",
+				Bundle:     comp.TEEKind().String(),
+				Descriptor: node.TEEHardwareIntelSGX.String(),
+			}}, nil
+		}
+	default:
+		return nil, fmt.Errorf("runtime/bundle: unsupported TEE hardware: %s", rt.TEEHardware)
+	}
+
+	var sc node.SGXConstraints
+	if err := cbor.Unmarshal(deployment.TEE, &sc); err != nil {
+		return nil, fmt.Errorf("runtime/bundle: malformed SGX constraints for version %s: %w", deployment.Version, err)
+	}
+	ids, err := enclaves()
+	if err != nil {
+		return nil, err
+	}
+
+	// Identities may differ between deployment systems, so one allowed identity is enough.
+	if len(ids) == 0 || slices.ContainsFunc(ids, func(eid sgx.EnclaveIdentity) bool {
+		return slices.Contains(sc.Enclaves, eid)
+	}) {
+		return nil, nil
+	}
+
+	// Report MRENCLAVE and MRSIGNER separately to make it easier to see which one is wrong.
+	var mismatches []Mismatch
+	for _, eid := range ids {
+		var (
+			mrEnclaves = make([]string, 0, len(sc.Enclaves))
+			mrSigners  = make([]string, 0, len(sc.Enclaves))
+			enclaveOk  bool
+			signerOk   bool
+		)
+		for _, allowed := range sc.Enclaves {
+			mrEnclaves = append(mrEnclaves, allowed.MrEnclave.String())
+			mrSigners = append(mrSigners, allowed.MrSigner.String())
+			enclaveOk = enclaveOk || allowed.MrEnclave == eid.MrEnclave
+			signerOk = signerOk || allowed.MrSigner == eid.MrSigner
+		}
+		if !enclaveOk {
+			mismatches = append(mismatches, Mismatch{
+				Component:  &id,
+				Field:      "mr_enclave",
+				Bundle:     eid.MrEnclave.String(),
+				Descriptor: fmt.Sprintf("[%s]", strings.Join(mrEnclaves, ", ")),
+			})
+		}
+		if !signerOk {
+			mismatches = append(mismatches, Mismatch{
+				Component:  &id,
+				Field:      "mr_signer",
+				Bundle:     eid.MrSigner.String(),
+				Descriptor: fmt.Sprintf("[%s]", strings.Join(mrSigners, ", ")),
+			})
+		}
+		if enclaveOk && signerOk {
+			// Both are allowed individually, but not as a pair.
+			mismatches = append(mismatches, Mismatch{
+				Component:  &id,
+				Field:      "enclave_identity",
+				Bundle:     eid.String(),
+				Descriptor: fmt.Sprintf("%v", sc.Enclaves),
+			})
+		}
+	}
+	return mismatches, nil
+}
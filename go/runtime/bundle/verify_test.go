@@ -0,0 +1,133 @@
+package bundle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+)
+
+func TestVerify(t *testing.T) {
+	require := require.New(t)
+
+	var id, otherID common.Namespace
+	require.NoError(id.UnmarshalHex("c000000000000000ffffffffffffffffffffffffffffffffffffffffffffffff"))
+	require.NoError(otherID.UnmarshalHex("c000000000000000fffffffffffffffffffffffffffffffffffffffffffffffe"))
+
+	identity := sgx.EnclaveIdentity{
+		MrSigner:  sgx.MrSigner{0x01},
+		MrEnclave: sgx.MrEnclave{0x02},
+	}
+	newRuntime := func(enclaves ...sgx.EnclaveIdentity) *registry.Runtime {
+		return &registry.Runtime{
+			ID:          id,
+			TEEHardware: node.TEEHardwareIntelSGX,
+			Deployments: []*registry.VersionInfo{
+				{
+					Version: version.Version{Major: 1, Minor: 2},
+					TEE: cbor.Marshal(node.SGXConstraints{
+						Versioned: cbor.NewVersioned(node.LatestSGXConstraintsVersion),
+						Enclaves:  enclaves,
+					}),
+				},
+			},
+		}
+	}
+	mismatches := func(err error) []Mismatch {
+		var verr *VerificationError
+		require.True(errors.As(err, &verr), "error should be a verification error")
+		return verr.Mismatches
+	}
+
+	bnd := &Bundle{
+		Manifest: &Manifest{
+			Name: "test-runtime",
+			ID:   id,
+			Components: []*Component{
+				{
+					Kind:    component.RONL,
+					Version: version.Version{Major: 1, Minor: 2},
+					ELF: &ELFMetadata{
+						Executable: "runtime.bin",
+					},
+					SGX: &SGXMetadata{
+						Executable: "runtime.sgx",
+					},
+					Identities: []Identity{{Enclave: identity}},
+				},
+			},
+		},
+	}
+
+	// Matching bundle.
+	require.NoError(Verify(bnd, newRuntime(identity)))
+
+	// Runtime identifier mismatch.
+	rt := newRuntime(identity)
+	rt.ID = otherID
+	ms := mismatches(Verify(bnd, rt))
+	require.Len(ms, 1)
+	require.Equal("id", ms[0].Field)
+
+	// Version mismatch.
+	rt = newRuntime(identity)
+	rt.Deployments[0].Version = version.Version{Major: 2}
+	ms = mismatches(Verify(bnd, rt))
+	require.Len(ms, 1)
+	require.Equal("version", ms[0].Field)
+	require.Equal("1.2.0", ms[0].Bundle)
+	require.Equal("[2.0.0]", ms[0].Descriptor)
+
+	// MRENCLAVE mismatch.
+	ms = mismatches(Verify(bnd, newRuntime(sgx.EnclaveIdentity{
+		MrSigner:  identity.MrSigner,
+		MrEnclave: sgx.MrEnclave{0x03},
+	})))
+	require.Len(ms, 1)
+	require.Equal("mr_enclave", ms[0].Field)
+	require.Equal(identity.MrEnclave.String(), ms[0].Bundle)
+
+	// MRENCLAVE and MRSIGNER mismatch.
+	ms = mismatches(Verify(bnd, newRuntime(sgx.EnclaveIdentity{
+		MrSigner:  sgx.MrSigner{0x04},
+		MrEnclave: sgx.MrEnclave{0x03},
+	})))
+	require.Len(ms, 2)
+	require.Equal("mr_enclave", ms[0].Field)
+	require.Equal("mr_signer", ms[1].Field)
+
+	// Both allowed individually, but not as a pair.
+	ms = mismatches(Verify(bnd, newRuntime(
+		sgx.EnclaveIdentity{MrSigner: identity.MrSigner, MrEnclave: sgx.MrEnclave{0x03}},
+		sgx.EnclaveIdentity{MrSigner: sgx.MrSigner{0x04}, MrEnclave: identity.MrEnclave},
+	)))
+	require.Len(ms, 1)
+	require.Equal("enclave_identity", ms[0].Field)
+
+	// Non-TEE runtime.
+	rt = newRuntime()
+	rt.TEEHardware = node.TEEHardwareInvalid
+	require.NoError(Verify(bnd, rt))
+
+	// Exploded component.
+	comp := &ExplodedComponent{
+		Component: bnd.Manifest.Components[0],
+		TEEKind:   component.TEEKindNone,
+	}
+	require.NoError(VerifyComponent(comp, newRuntime(identity)))
+	require.Error(VerifyComponent(comp, newRuntime()))
+
+	// Bundle without SGX metadata for an SGX runtime.
+	bnd.Manifest.Components[0].SGX = nil
+	ms = mismatches(Verify(bnd, newRuntime(identity)))
+	require.Len(ms, 1)
+	require.Equal("tee", ms[0].Field)
+}
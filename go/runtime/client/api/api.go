@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -20,6 +22,19 @@ const (
 
 	// RoundLatest is a special round number always referring to the latest round.
 	RoundLatest = roothash.RoundLatest
+
+	// MaxSubmitTxBatchSize is the maximum number of transactions in a SubmitTxBatch request.
+	MaxSubmitTxBatchSize = 1024
+	// MaxSubmitTxBatchBytes is the maximum total size (in bytes) of transactions in
+	// a SubmitTxBatch request.
+	MaxSubmitTxBatchBytes = 8 * 1024 * 1024
+
+	// DefaultQueryTxsByTagLimit is the default maximum number of transactions returned by
+	// a QueryTxsByTag request.
+	DefaultQueryTxsByTagLimit = 100
+	// MaxQueryTxsByTagLimit is the maximum number of transactions that can be returned by
+	// a QueryTxsByTag request.
+	MaxQueryTxsByTagLimit = 1000
 )
 
 var (
@@ -36,6 +51,15 @@ var (
 	ErrCheckTxFailed = errors.New(ModuleName, 5, "client: transaction check failed")
 	// ErrNoHostedRuntime is returned when the hosted runtime is not available locally.
 	ErrNoHostedRuntime = errors.New(ModuleName, 6, "client: no hosted runtime is available")
+	// ErrTxBatchTooLarge is returned when a submitted transaction batch exceeds the size limits.
+	ErrTxBatchTooLarge = errors.New(ModuleName, 7, "client: transaction batch too large")
+	// ErrEventSubscriptionLagging is returned when an event subscription is dropped because the
+	// subscriber is not consuming events fast enough.
+	ErrEventSubscriptionLagging = errors.New(ModuleName, 8, "client: event subscription lagging")
+	// ErrInvalidArgument is returned when a request contains invalid arguments.
+	ErrInvalidArgument = errors.New(ModuleName, 9, "client: invalid argument")
+	// ErrRateLimited is returned when a request is rejected due to rate limiting.
+	ErrRateLimited = errors.New(ModuleName, 10, "client: rate limited")
 )
 
 // RuntimeClient is the runtime client interface.
@@ -55,9 +79,23 @@ type RuntimeClient interface {
 	// not wait for transaction execution.
 	SubmitTxNoWait(ctx context.Context, request *SubmitTxRequest) error
 
+	// SubmitTxBatch submits a batch of transactions to the runtime transaction scheduler in
+	// a single operation and waits for their checks to complete, but does not wait for
+	// transaction execution.
+	//
+	// Response includes the CheckTx result of each transaction, in request order.
+	SubmitTxBatch(ctx context.Context, request *SubmitTxBatchRequest) (*SubmitTxBatchResponse, error)
+
 	// CheckTx asks the local runtime to check the specified transaction.
 	CheckTx(ctx context.Context, request *CheckTxRequest) error
 
+	// CheckTxMeta asks the local runtime to check the specified transaction against the state
+	// of the latest round, without submitting it to the transaction scheduler.
+	//
+	// Response includes the full CheckTx result together with the round and consensus height
+	// against which the transaction was checked.
+	CheckTxMeta(ctx context.Context, request *CheckTxRequest) (*CheckTxMetaResponse, error)
+
 	// GetGenesisBlock returns the genesis block.
 	GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error)
 
@@ -81,6 +119,20 @@ type RuntimeClient interface {
 	// GetEvents returns all events emitted in a given block.
 	GetEvents(ctx context.Context, request *GetEventsRequest) ([]*Event, error)
 
+	// WatchRuntimeEvents subscribes to events emitted by the given runtime in finalized rounds
+	// that match the given filter. A nil filter matches all events.
+	//
+	// In case the subscriber does not consume events fast enough, the subscription is dropped
+	// and its Err method returns ErrEventSubscriptionLagging.
+	WatchRuntimeEvents(ctx context.Context, runtimeID common.Namespace, filter *EventFilter) (<-chan *RuntimeEvent, EventSubscription, error)
+
+	// QueryTxsByTag returns transactions that emitted the given tag within the given round
+	// range, together with their results, in execution order.
+	//
+	// Rounds covered by the node's transaction tag index (if enabled for the given tag key) are
+	// served from the index while the remaining rounds are scanned.
+	QueryTxsByTag(ctx context.Context, request *QueryTxsByTagRequest) (*QueryTxsByTagResponse, error)
+
 	// Query makes a runtime-specific query.
 	Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error)
 
@@ -102,6 +154,10 @@ type SubmitTxResult struct {
 type SubmitTxRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Data      []byte           `json:"data"`
+
+	// Prove requests a proof of inclusion of the transaction to be returned in the SubmitTxMeta
+	// response.
+	Prove bool `json:"prove,omitempty"`
 }
 
 // SubmitTxMetaResponse is the SubmitTxMeta response.
@@ -113,16 +169,70 @@ type SubmitTxMetaResponse struct {
 	// BatchOrder is the order of the transaction in the execution batch.
 	BatchOrder uint32 `json:"batch_order,omitempty"`
 
+	// Header is the header of the runtime block in which the transaction was executed.
+	//
+	// This is only set when a proof of inclusion has been requested.
+	Header *block.Header `json:"header,omitempty"`
+	// Proof is the Merkle proof of the transaction artifacts against the IO root of the header.
+	// It can be verified using transaction.VerifyInclusionProof.
+	//
+	// This is only set when a proof of inclusion has been requested.
+	Proof *syncer.Proof `json:"proof,omitempty"`
+
 	// CheckTxError is the CheckTx error in case transaction failed the transaction check.
 	CheckTxError *protocol.Error `json:"check_tx_error,omitempty"`
 }
 
+// SubmitTxBatchRequest is a SubmitTxBatch request.
+type SubmitTxBatchRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Txs       [][]byte         `json:"txs"`
+}
+
+// ValidateBasic performs basic validation of the request.
+func (r *SubmitTxBatchRequest) ValidateBasic() error {
+	if len(r.Txs) > MaxSubmitTxBatchSize {
+		return errors.WithContext(ErrTxBatchTooLarge,
+			fmt.Sprintf("too many transactions (max: %d got: %d)", MaxSubmitTxBatchSize, len(r.Txs)),
+		)
+	}
+
+	var size int
+	for _, tx := range r.Txs {
+		size += len(tx)
+	}
+	if size > MaxSubmitTxBatchBytes {
+		return errors.WithContext(ErrTxBatchTooLarge,
+			fmt.Sprintf("too many bytes (max: %d got: %d)", MaxSubmitTxBatchBytes, size),
+		)
+	}
+
+	return nil
+}
+
+// SubmitTxBatchResponse is the SubmitTxBatch response.
+type SubmitTxBatchResponse struct {
+	// Results are the CheckTx results of the submitted transactions, in request order.
+	Results []*protocol.CheckTxResult `json:"results"`
+}
+
 // CheckTxRequest is a CheckTx request.
 type CheckTxRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Data      []byte           `json:"data"`
 }
 
+// CheckTxMetaResponse is the CheckTxMeta response.
+type CheckTxMetaResponse struct {
+	// Round is the runtime round against whose state the transaction was checked.
+	Round uint64 `json:"round"`
+	// Height is the consensus height against whose state the transaction was checked.
+	Height int64 `json:"height"`
+	// Result is the CheckTx result, including the transaction metadata in case the check
+	// succeeded.
+	Result *protocol.CheckTxResult `json:"result"`
+}
+
 // GetBlockRequest is a GetBlock request.
 type GetBlockRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
@@ -166,6 +276,150 @@ type PlainEvent struct {
 	Value []byte `json:"value"`
 }
 
+// EventFilter is a runtime event filter.
+type EventFilter struct {
+	// Key is the event key to match.
+	Key []byte `json:"key,omitempty"`
+	// KeyPrefix specifies that Key should be matched as a key prefix instead of exactly.
+	KeyPrefix bool `json:"key_prefix,omitempty"`
+	// Value is the optional event value to match exactly.
+	Value []byte `json:"value,omitempty"`
+}
+
+// Matches returns true iff the event with the given key and value matches the filter.
+func (f *EventFilter) Matches(key, value []byte) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.KeyPrefix && !bytes.HasPrefix(key, f.Key) {
+		return false
+	}
+	if !f.KeyPrefix && !bytes.Equal(key, f.Key) {
+		return false
+	}
+	if f.Value != nil && !bytes.Equal(value, f.Value) {
+		return false
+	}
+	return true
+}
+
+// WatchRuntimeEventsRequest is a WatchRuntimeEvents request.
+type WatchRuntimeEventsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Filter    *EventFilter     `json:"filter,omitempty"`
+}
+
+// RuntimeEvent is an event emitted by a runtime in a finalized round.
+//
+// Key and value semantics are runtime-dependent.
+type RuntimeEvent struct {
+	Round  uint64    `json:"round"`
+	Key    []byte    `json:"key"`
+	Value  []byte    `json:"value"`
+	TxHash hash.Hash `json:"tx_hash"`
+}
+
+// EventSubscription is a runtime event subscription.
+type EventSubscription interface {
+	pubsub.ClosableSubscription
+
+	// Err returns the error that caused the subscription to be dropped, if any. It should only be
+	// called after the event channel has been closed.
+	Err() error
+}
+
+// QueryTxsByTagRequest is a QueryTxsByTag request.
+type QueryTxsByTagRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+
+	// Key is the tag key to match exactly.
+	Key []byte `json:"key"`
+	// Value is the tag value to match exactly.
+	Value []byte `json:"value"`
+
+	// StartRound is the first round of the searched round range.
+	StartRound uint64 `json:"start_round"`
+	// EndRound is the last round (inclusive) of the searched round range. The special value
+	// RoundLatest refers to the latest round.
+	EndRound uint64 `json:"end_round"`
+
+	// After is the position of the last transaction returned in the previous page, if any.
+	After *TxPosition `json:"after,omitempty"`
+	// Limit is the maximum number of returned transactions. Zero uses the default limit.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// ValidateBasic performs basic validation of the request.
+func (r *QueryTxsByTagRequest) ValidateBasic() error {
+	if r.StartRound > r.EndRound {
+		return errors.WithContext(ErrInvalidArgument, "start round is after end round")
+	}
+	if r.Limit > MaxQueryTxsByTagLimit {
+		return errors.WithContext(ErrInvalidArgument,
+			fmt.Sprintf("limit too large (max: %d got: %d)", MaxQueryTxsByTagLimit, r.Limit),
+		)
+	}
+	return nil
+}
+
+// TxPosition is the position of a transaction in the runtime history.
+type TxPosition struct {
+	// Round is the round in which the transaction was executed.
+	Round uint64 `json:"round"`
+	// BatchOrder is the order of the transaction in the execution batch.
+	BatchOrder uint32 `json:"batch_order"`
+}
+
+// Less returns true iff the position is before the other position.
+func (p *TxPosition) Less(other *TxPosition) bool {
+	if p.Round != other.Round {
+		return p.Round < other.Round
+	}
+	return p.BatchOrder < other.BatchOrder
+}
+
+// TaggedTransaction is a transaction that emitted a queried tag, together with its raw result.
+type TaggedTransaction struct {
+	TxPosition
+
+	TxHash hash.Hash `json:"tx_hash"`
+	Tx     []byte    `json:"tx"`
+	Result []byte    `json:"result"`
+}
+
+// QueryTxsByTagResponse is a QueryTxsByTag response.
+type QueryTxsByTagResponse struct {
+	// Txs are the matching transactions in execution order.
+	Txs []*TaggedTransaction `json:"txs"`
+	// Next is the position to pass as After to fetch the next page. It is only set in case
+	// the limit has been reached and more transactions may be available.
+	Next *TxPosition `json:"next,omitempty"`
+}
+
+// TagIndexStatus is the status of the runtime transaction tag index.
+type TagIndexStatus struct {
+	// Status is the concise status of the tag index.
+	Status string `json:"status"`
+	// FirstRound is the first indexed round.
+	FirstRound uint64 `json:"first_round"`
+	// LastRound is the last indexed round.
+	LastRound uint64 `json:"last_round"`
+
+	// Rebuild is the status of the index rebuild after a gap (e.g., node downtime).
+	//
+	// It is nil unless the index is being rebuilt.
+	Rebuild *TagIndexRebuildStatus `json:"rebuild,omitempty"`
+}
+
+// TagIndexRebuildStatus is the status of the runtime transaction tag index rebuild.
+type TagIndexRebuildStatus struct {
+	// StartRound is the first round of the rebuilt interval.
+	StartRound uint64 `json:"start_round"`
+	// EndRound is the last round of the rebuilt interval.
+	EndRound uint64 `json:"end_round"`
+}
+
 // QueryRequest is a Query request.
 type QueryRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFilter(t *testing.T) {
+	require := require.New(t)
+
+	var filter *EventFilter
+	require.True(filter.Matches([]byte("foo"), []byte("bar")), "nil filter should match all events")
+
+	filter = &EventFilter{Key: []byte("foo")}
+	require.True(filter.Matches([]byte("foo"), []byte("bar")), "exact key should match")
+	require.False(filter.Matches([]byte("foobar"), []byte("bar")), "longer key should not match exactly")
+	require.False(filter.Matches([]byte("fo"), []byte("bar")), "shorter key should not match exactly")
+
+	filter = &EventFilter{Key: []byte("foo"), KeyPrefix: true}
+	require.True(filter.Matches([]byte("foo"), []byte("bar")), "exact key should match prefix")
+	require.True(filter.Matches([]byte("foobar"), []byte("bar")), "longer key should match prefix")
+	require.False(filter.Matches([]byte("fo"), []byte("bar")), "shorter key should not match prefix")
+
+	filter = &EventFilter{KeyPrefix: true}
+	require.True(filter.Matches([]byte("foo"), []byte("bar")), "empty prefix should match all keys")
+
+	filter = &EventFilter{Key: []byte("foo"), KeyPrefix: true, Value: []byte("bar")}
+	require.True(filter.Matches([]byte("foobar"), []byte("bar")), "matching value should match")
+	require.False(filter.Matches([]byte("foobar"), []byte("baz")), "different value should not match")
+}
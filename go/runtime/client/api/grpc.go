@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"io"
+	"sync"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -26,33 +28,41 @@ var (
 	methodSubmitTxMeta = serviceName.NewMethod("SubmitTxMeta", SubmitTxRequest{})
 	// methodSubmitTxNoWait is the SubmitTxNoWait method.
 	methodSubmitTxNoWait = serviceName.NewMethod("SubmitTxNoWait", SubmitTxRequest{})
+	// methodSubmitTxBatch is the SubmitTxBatch method.
+	methodSubmitTxBatch = serviceName.NewMethod("SubmitTxBatch", SubmitTxBatchRequest{})
 	// methodCheckTx is the CheckTx method.
-	methodCheckTx = serviceName.NewMethod("CheckTx", CheckTxRequest{})
+	methodCheckTx = serviceName.NewMethod("CheckTx", CheckTxRequest{}).WithIdempotent()
+	// methodCheckTxMeta is the CheckTxMeta method.
+	methodCheckTxMeta = serviceName.NewMethod("CheckTxMeta", CheckTxRequest{}).WithIdempotent()
 	// methodGetGenesisBlock is the GetGenesisBlock method.
-	methodGetGenesisBlock = serviceName.NewMethod("GetGenesisBlock", common.Namespace{})
+	methodGetGenesisBlock = serviceName.NewMethod("GetGenesisBlock", common.Namespace{}).WithIdempotent()
 	// methodGetBlock is the GetBlock method.
-	methodGetBlock = serviceName.NewMethod("GetBlock", GetBlockRequest{})
+	methodGetBlock = serviceName.NewMethod("GetBlock", GetBlockRequest{}).WithIdempotent()
 	// methodGetLastRetainedBlock is the GetLastRetainedBlock method.
-	methodGetLastRetainedBlock = serviceName.NewMethod("GetLastRetainedBlock", common.Namespace{})
+	methodGetLastRetainedBlock = serviceName.NewMethod("GetLastRetainedBlock", common.Namespace{}).WithIdempotent()
 	// methodGetTransactions is the GetTransactions method.
-	methodGetTransactions = serviceName.NewMethod("GetTransactions", GetTransactionsRequest{})
+	methodGetTransactions = serviceName.NewMethod("GetTransactions", GetTransactionsRequest{}).WithIdempotent()
 	// methodGetTransactionsWithResults is the GetTransactionsWithResults method.
-	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{})
+	methodGetTransactionsWithResults = serviceName.NewMethod("GetTransactionsWithResults", GetTransactionsRequest{}).WithIdempotent()
 	// methodGetUnconfirmedTransactions is the GetUnconfirmedTransactions method.
-	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", common.Namespace{})
+	methodGetUnconfirmedTransactions = serviceName.NewMethod("GetUnconfirmedTransactions", common.Namespace{}).WithIdempotent()
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{})
+	methodGetEvents = serviceName.NewMethod("GetEvents", GetEventsRequest{}).WithIdempotent()
+	// methodQueryTxsByTag is the QueryTxsByTag method.
+	methodQueryTxsByTag = serviceName.NewMethod("QueryTxsByTag", QueryTxsByTagRequest{}).WithIdempotent()
 	// methodQuery is the Query method.
-	methodQuery = serviceName.NewMethod("Query", QueryRequest{})
+	methodQuery = serviceName.NewMethod("Query", QueryRequest{}).WithIdempotent()
 	// methodStateSyncGet is the StateSyncGet method.
-	methodStateSyncGet = serviceName.NewMethod("StateSyncGet", syncer.GetRequest{})
+	methodStateSyncGet = serviceName.NewMethod("StateSyncGet", syncer.GetRequest{}).WithIdempotent()
 	// methodStateSyncGetPrefixes is the StateSyncGetPrefixes method.
-	methodStateSyncGetPrefixes = serviceName.NewMethod("StateSyncGetPrefixes", syncer.GetPrefixesRequest{})
+	methodStateSyncGetPrefixes = serviceName.NewMethod("StateSyncGetPrefixes", syncer.GetPrefixesRequest{}).WithIdempotent()
 	// methodStateSyncIterate is the StateSyncIterate method.
-	methodStateSyncIterate = serviceName.NewMethod("StateSyncIterate", syncer.IterateRequest{})
+	methodStateSyncIterate = serviceName.NewMethod("StateSyncIterate", syncer.IterateRequest{}).WithIdempotent()
 
 	// methodWatchBlocks is the WatchBlocks method.
-	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{})
+	methodWatchBlocks = serviceName.NewMethod("WatchBlocks", common.Namespace{}).WithIdempotent()
+	// methodWatchRuntimeEvents is the WatchRuntimeEvents method.
+	methodWatchRuntimeEvents = serviceName.NewMethod("WatchRuntimeEvents", WatchRuntimeEventsRequest{}).WithIdempotent()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -71,10 +81,18 @@ var (
 				MethodName: methodSubmitTxNoWait.ShortName(),
 				Handler:    handlerSubmitTxNoWait,
 			},
+			{
+				MethodName: methodSubmitTxBatch.ShortName(),
+				Handler:    handlerSubmitTxBatch,
+			},
 			{
 				MethodName: methodCheckTx.ShortName(),
 				Handler:    handlerCheckTx,
 			},
+			{
+				MethodName: methodCheckTxMeta.ShortName(),
+				Handler:    handlerCheckTxMeta,
+			},
 			{
 				MethodName: methodGetGenesisBlock.ShortName(),
 				Handler:    handlerGetGenesisBlock,
@@ -103,6 +121,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodQueryTxsByTag.ShortName(),
+				Handler:    handlerQueryTxsByTag,
+			},
 			{
 				MethodName: methodQuery.ShortName(),
 				Handler:    handlerQuery,
@@ -126,6 +148,11 @@ var (
 				Handler:       handlerWatchBlocks,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchRuntimeEvents.ShortName(),
+				Handler:       handlerWatchRuntimeEvents,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -199,6 +226,29 @@ func handlerSubmitTxNoWait(
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerSubmitTxBatch(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq SubmitTxBatchRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).SubmitTxBatch(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSubmitTxBatch.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).SubmitTxBatch(ctx, req.(*SubmitTxBatchRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerCheckTx(
 	srv any,
 	ctx context.Context,
@@ -222,6 +272,29 @@ func handlerCheckTx(
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerCheckTxMeta(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq CheckTxRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).CheckTxMeta(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCheckTxMeta.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).CheckTxMeta(ctx, req.(*CheckTxRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 // wrappedErrNotFound is a wrapped ErrNotFound error so that it corresponds
 // to the gRPC NotFound error code. It is required because Rust's gRPC bindings
 // do not support fetching error details.
@@ -416,6 +489,29 @@ func handlerGetEvents(
 	return interceptor(ctx, &rq, info, handler)
 }
 
+func handlerQueryTxsByTag(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var rq QueryTxsByTagRequest
+	if err := dec(&rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeClient).QueryTxsByTag(ctx, &rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodQueryTxsByTag.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(RuntimeClient).QueryTxsByTag(ctx, req.(*QueryTxsByTagRequest))
+	}
+	return interceptor(ctx, &rq, info, handler)
+}
+
 func handlerQuery( // nolint: revive
 	srv any,
 	ctx context.Context,
@@ -539,6 +635,35 @@ func handlerWatchBlocks(srv any, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchRuntimeEvents(srv any, stream grpc.ServerStream) error {
+	var rq WatchRuntimeEventsRequest
+	if err := stream.RecvMsg(&rq); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(RuntimeClient).WatchRuntimeEvents(ctx, rq.RuntimeID, rq.Filter)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return sub.Err()
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // RegisterService registers a new runtime client service with the given gRPC server.
 func RegisterService(server *grpc.Server, service RuntimeClient) {
 	server.RegisterService(&serviceDesc, service)
@@ -576,10 +701,26 @@ func (c *Client) SubmitTxNoWait(ctx context.Context, request *SubmitTxRequest) e
 	return c.conn.Invoke(ctx, methodSubmitTxNoWait.FullName(), request, nil)
 }
 
+func (c *Client) SubmitTxBatch(ctx context.Context, request *SubmitTxBatchRequest) (*SubmitTxBatchResponse, error) {
+	var rsp SubmitTxBatchResponse
+	if err := c.conn.Invoke(ctx, methodSubmitTxBatch.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) CheckTx(ctx context.Context, request *CheckTxRequest) error {
 	return c.conn.Invoke(ctx, methodCheckTx.FullName(), request, nil)
 }
 
+func (c *Client) CheckTxMeta(ctx context.Context, request *CheckTxRequest) (*CheckTxMetaResponse, error) {
+	var rsp CheckTxMetaResponse
+	if err := c.conn.Invoke(ctx, methodCheckTxMeta.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) GetGenesisBlock(ctx context.Context, runtimeID common.Namespace) (*block.Block, error) {
 	var rsp block.Block
 	if err := c.conn.Invoke(ctx, methodGetGenesisBlock.FullName(), runtimeID, &rsp); err != nil {
@@ -636,6 +777,14 @@ func (c *Client) GetEvents(ctx context.Context, request *GetEventsRequest) ([]*E
 	return rsp, nil
 }
 
+func (c *Client) QueryTxsByTag(ctx context.Context, request *QueryTxsByTagRequest) (*QueryTxsByTagResponse, error) {
+	var rsp QueryTxsByTagResponse
+	if err := c.conn.Invoke(ctx, methodQueryTxsByTag.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) Query(ctx context.Context, request *QueryRequest) (*QueryResponse, error) {
 	var rsp QueryResponse
 	if err := c.conn.Invoke(ctx, methodQuery.FullName(), request, &rsp); err != nil {
@@ -713,3 +862,60 @@ func (c *Client) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<
 
 	return ch, sub, nil
 }
+
+type eventSubscription struct {
+	sync.Mutex
+
+	pubsub.ClosableSubscription
+
+	err error
+}
+
+// Implements EventSubscription.
+func (s *eventSubscription) Err() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.err
+}
+
+func (c *Client) WatchRuntimeEvents(ctx context.Context, runtimeID common.Namespace, filter *EventFilter) (<-chan *RuntimeEvent, EventSubscription, error) {
+	ctx, csub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[1], methodWatchRuntimeEvents.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(&WatchRuntimeEventsRequest{RuntimeID: runtimeID, Filter: filter}); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	sub := &eventSubscription{ClosableSubscription: csub}
+	ch := make(chan *RuntimeEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev RuntimeEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				if !errors.Is(serr, io.EOF) && ctx.Err() == nil {
+					sub.Lock()
+					sub.err = serr
+					sub.Unlock()
+				}
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
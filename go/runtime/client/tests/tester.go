@@ -11,9 +11,11 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/mock"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 )
 
 // Keep this above the test network's max batch timeout.
@@ -46,6 +48,18 @@ func ClientImplementationTests(
 		testSubmitTransactionNoWait(ctx, t, runtimeID, client, noWaitInput)
 	})
 
+	t.Run("SubmitTxWithProof", func(t *testing.T) {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+		defer cancelFunc()
+		testSubmitTransactionWithProof(ctx, t, runtimeID, client, "nautilus at: "+time.Now().String())
+	})
+
+	t.Run("WatchRuntimeEvents", func(t *testing.T) {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+		defer cancelFunc()
+		testWatchRuntimeEvents(ctx, t, runtimeID, client, "cuttlefish at: "+time.Now().String())
+	})
+
 	t.Run("FailSubmitTx", func(t *testing.T) {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
 		defer cancelFunc()
@@ -71,6 +85,85 @@ func testSubmitTransaction(
 	require.True(t, resp.Round > 0, "SubmitTxMeta round should be non zero")
 }
 
+func testSubmitTransactionWithProof(
+	ctx context.Context,
+	t *testing.T,
+	runtimeID common.Namespace,
+	c api.RuntimeClient,
+	input string,
+) {
+	testInput := []byte(input)
+	resp, err := c.SubmitTxMeta(ctx, &api.SubmitTxRequest{Data: testInput, RuntimeID: runtimeID, Prove: true})
+	require.NoError(t, err, "SubmitTxMeta")
+	require.Nil(t, resp.CheckTxError, "SubmitTxMeta check tx error")
+	require.NotNil(t, resp.Header, "SubmitTxMeta should return the block header")
+	require.NotNil(t, resp.Proof, "SubmitTxMeta should return the inclusion proof")
+	require.EqualValues(t, resp.Round, resp.Header.Round, "header should be for the execution round")
+
+	tx := transaction.Transaction{
+		Input:      testInput,
+		Output:     resp.Output,
+		BatchOrder: resp.BatchOrder,
+	}
+	err = transaction.VerifyInclusionProof(ctx, resp.Header, &tx, resp.Proof)
+	require.NoError(t, err, "VerifyInclusionProof")
+
+	// Tampered outputs should be rejected.
+	tx.Output = []byte("tampered output")
+	err = transaction.VerifyInclusionProof(ctx, resp.Header, &tx, resp.Proof)
+	require.ErrorIs(t, err, transaction.ErrInvalidInclusionProof, "VerifyInclusionProof should fail with tampered output")
+}
+
+func testWatchRuntimeEvents(
+	ctx context.Context,
+	t *testing.T,
+	runtimeID common.Namespace,
+	c api.RuntimeClient,
+	input string,
+) {
+	// Subscribe to events emitted by the mock runtime (see mock worker for emitted events).
+	ch, sub, err := c.WatchRuntimeEvents(ctx, runtimeID, &api.EventFilter{
+		Key:       []byte("txn_"),
+		KeyPrefix: true,
+	})
+	require.NoError(t, err, "WatchRuntimeEvents")
+	defer sub.Close()
+
+	// Events not matching the filter should not be emitted.
+	chMiss, subMiss, err := c.WatchRuntimeEvents(ctx, runtimeID, &api.EventFilter{
+		Key: []byte("txn_"),
+	})
+	require.NoError(t, err, "WatchRuntimeEvents")
+	defer subMiss.Close()
+
+	testInput := []byte(input)
+	resp, err := c.SubmitTxMeta(ctx, &api.SubmitTxRequest{Data: testInput, RuntimeID: runtimeID})
+	require.NoError(t, err, "SubmitTxMeta")
+
+	txHash := hash.NewFromBytes(testInput)
+	for {
+		select {
+		case ev, ok := <-ch:
+			require.True(t, ok, "event channel should not be closed (err: %v)", sub.Err())
+			if ev.TxHash != txHash {
+				continue
+			}
+			require.EqualValues(t, resp.Round, ev.Round, "event should include the round")
+			require.EqualValues(t, []byte("txn_foo"), ev.Key)
+			require.EqualValues(t, []byte("txn_bar"), ev.Value)
+
+			select {
+			case ev = <-chMiss:
+				t.Fatalf("unexpected event not matching the filter: %+v", ev)
+			default:
+			}
+			return
+		case <-ctx.Done():
+			t.Fatalf("failed to receive runtime event: %s", ctx.Err())
+		}
+	}
+}
+
 func testFailSubmitTransaction(
 	ctx context.Context,
 	t *testing.T,
@@ -91,6 +184,17 @@ func testFailSubmitTransaction(
 	err = c.SubmitTxNoWait(ctx, &api.SubmitTxRequest{Data: mock.CheckTxFailInput, RuntimeID: runtimeID})
 	require.Error(t, err, "SubmitTxNoWait should fail check tx")
 
+	batchResp, err := c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{Txs: [][]byte{mock.CheckTxFailInput}, RuntimeID: runtimeID})
+	require.NoError(t, err, "SubmitTxBatch")
+	require.Len(t, batchResp.Results, 1, "SubmitTxBatch should return a result for each transaction")
+	require.EqualValues(t, protocol.Error{
+		Module: "mock",
+		Code:   1,
+	}, batchResp.Results[0].Error, "SubmitTxBatch should fail check tx")
+
+	_, err = c.SubmitTxBatch(ctx, &api.SubmitTxBatchRequest{Txs: make([][]byte, api.MaxSubmitTxBatchSize+1), RuntimeID: runtimeID})
+	require.ErrorIs(t, err, api.ErrTxBatchTooLarge, "SubmitTxBatch should fail for too large batches")
+
 	// Failures for unsupported runtimes.
 	var unsupportedRuntimeID common.Namespace
 	err = unsupportedRuntimeID.UnmarshalHex("0000000000000000BADF00BADF00BADF00BADF00BADF00BADF00BADF00BADF00")
@@ -230,6 +334,29 @@ func testQuery(
 	})
 	require.NoError(t, err, "CheckTx")
 
+	// Execute CheckTxMeta using the mock runtime host.
+	blkLatest, err = c.GetBlock(ctx, &api.GetBlockRequest{RuntimeID: runtimeID, Round: api.RoundLatest})
+	require.NoError(t, err, "GetBlock(RoundLatest)")
+	checkRsp, err := c.CheckTxMeta(ctx, &api.CheckTxRequest{
+		RuntimeID: runtimeID,
+		Data:      []byte("test checktx meta request"),
+	})
+	require.NoError(t, err, "CheckTxMeta")
+	require.True(t, checkRsp.Result.IsSuccess(), "CheckTxMeta should succeed")
+	require.GreaterOrEqual(t, checkRsp.Round, blkLatest.Header.Round, "CheckTxMeta should be evaluated against the latest round")
+	require.NotZero(t, checkRsp.Height, "CheckTxMeta should return the consensus height")
+
+	checkRsp, err = c.CheckTxMeta(ctx, &api.CheckTxRequest{
+		RuntimeID: runtimeID,
+		Data:      mock.CheckTxFailInput,
+	})
+	require.NoError(t, err, "CheckTxMeta")
+	require.False(t, checkRsp.Result.IsSuccess(), "CheckTxMeta should return the failed check result")
+	require.EqualValues(t, protocol.Error{
+		Module: "mock",
+		Code:   1,
+	}, checkRsp.Result.Error, "CheckTxMeta should fail check tx")
+
 	// Get the number of unconfirmed transactions.
 	utxs, err := c.GetUnconfirmedTransactions(ctx, runtimeID)
 	require.NoError(t, err, "GetUnconfirmedTransactions")
@@ -4,6 +4,8 @@ package config
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"path/filepath"
 	"slices"
 	"time"
 
@@ -87,6 +89,28 @@ const (
 	TEESelectModeTDX TEESelectMode = "tdx"
 )
 
+// RuntimeMode is the mode in which a configured runtime is operated.
+type RuntimeMode string
+
+const (
+	// RuntimeModeDefault specifies that the runtime should be operated according to the node mode.
+	RuntimeModeDefault RuntimeMode = ""
+
+	// RuntimeModeReadOnly specifies that the runtime should only follow the runtime state and
+	// serve queries, without ever participating in any runtime committees.
+	RuntimeModeReadOnly RuntimeMode = "read-only"
+)
+
+// Validate validates the runtime mode.
+func (m RuntimeMode) Validate() error {
+	switch m {
+	case RuntimeModeDefault, RuntimeModeReadOnly:
+		return nil
+	default:
+		return fmt.Errorf("unknown runtime mode: %s", m)
+	}
+}
+
 // Config is the runtime registry configuration structure.
 type Config struct {
 	// Runtimes is the list of runtimes to configure.
@@ -115,6 +139,12 @@ type Config struct {
 	// Indexer is history indexer configuration.
 	Indexer IndexerConfig `yaml:"indexer,omitempty"`
 
+	// TagIndex is the transaction tag index configuration.
+	TagIndex TagIndexConfig `yaml:"tag_index,omitempty"`
+
+	// CheckTx is the runtime client transaction check configuration.
+	CheckTx CheckTxConfig `yaml:"check_tx,omitempty"`
+
 	// RuntimeConfig maps runtime IDs to their respective local configurations.
 	// NOTE: This may go away in the future, use `RuntimeConfig.Config` instead.
 	RuntimeConfig map[string]map[string]any `yaml:"config,omitempty"`
@@ -149,6 +179,19 @@ type Config struct {
 	// If not specified, a default value is used.
 	MaxBundleSize string `yaml:"max_bundle_size,omitempty"`
 
+	// MaxConcurrentRequests is the maximum number of concurrent non-critical requests (e.g.,
+	// queries) that are sent to a runtime. Consensus-critical requests like batch execution
+	// are never delayed by this limit.
+	//
+	// If not specified, a default value is used.
+	MaxConcurrentRequests uint16 `yaml:"max_concurrent_requests,omitempty"`
+
+	// ShutdownDrainTimeout is the maximum amount of time in-flight requests are serviced when the
+	// connection to a runtime is being shut down, before the runtime is terminated.
+	//
+	// If not specified, a default value is used.
+	ShutdownDrainTimeout time.Duration `yaml:"shutdown_drain_timeout,omitempty"`
+
 	// DebugMockTEE enables mocking of the Trusted Execution Environment (TEE).
 	//
 	// This flag can only be used if the DebugDontBlameOasis flag is set.
@@ -178,6 +221,57 @@ func (c *Config) GetComponent(runtimeID common.Namespace, compID component.ID) (
 	return ComponentConfig{}, false
 }
 
+// GetRuntimeMode returns the mode in which the given runtime should be operated.
+func (c *Config) GetRuntimeMode(runtimeID common.Namespace) RuntimeMode {
+	for _, rt := range c.Runtimes {
+		if rt.ID == runtimeID {
+			return rt.Mode
+		}
+	}
+	return RuntimeModeDefault
+}
+
+// IsReadOnly returns true iff the given runtime should be operated in read-only mode.
+func (c *Config) IsReadOnly(runtimeID common.Namespace) bool {
+	return c.GetRuntimeMode(runtimeID) == RuntimeModeReadOnly
+}
+
+// GetTxPoolConfig returns the transaction pool configuration for the given runtime.
+func (c *Config) GetTxPoolConfig(runtimeID common.Namespace) tpConfig.Config {
+	cfg := c.TxPool
+	for _, rt := range c.Runtimes {
+		if rt.ID == runtimeID && rt.TxExecutionFailures != nil {
+			cfg.ExecutionFailures = *rt.TxExecutionFailures
+		}
+		if rt.ID == runtimeID && rt.TxLanes != nil {
+			cfg.Lanes = rt.TxLanes
+		}
+	}
+	return cfg
+}
+
+// GetStorageBackpressureConfig returns the storage backpressure configuration for the given
+// runtime.
+func (c *Config) GetStorageBackpressureConfig(runtimeID common.Namespace) StorageBackpressureConfig {
+	for _, rt := range c.Runtimes {
+		if rt.ID == runtimeID {
+			return rt.StorageBackpressure
+		}
+	}
+	return StorageBackpressureConfig{}
+}
+
+// QueryReplicas returns the number of query replicas for all runtimes that have them configured.
+func (c *Config) QueryReplicas() map[common.Namespace]int {
+	replicas := make(map[common.Namespace]int)
+	for _, rt := range c.Runtimes {
+		if rt.QueryReplicas > 0 {
+			replicas[rt.ID] = int(rt.QueryReplicas)
+		}
+	}
+	return replicas
+}
+
 // GetLocalConfig returns the local configuration for the given runtime,
 // if it exists.
 func (c *Config) GetLocalConfig(runtimeID common.Namespace) map[string]any {
@@ -196,6 +290,10 @@ func (c *Config) GetLocalConfig(runtimeID common.Namespace) map[string]any {
 type SgxConfig struct {
 	// Loader is the path to the SGX runtime loader binary.
 	Loader string `yaml:"loader,omitempty"`
+
+	// PCSURL is the base URL of the Intel PCS API used to fetch quote collateral. It can point
+	// to a caching PCS proxy (see `oasis-node ias proxy`). If not set, Intel PCS is used directly.
+	PCSURL string `yaml:"pcs_url,omitempty"`
 }
 
 // TdxConfig is configuration specific to Intel TDX.
@@ -211,6 +309,11 @@ type RuntimeConfig struct {
 	// ID is the runtime identifier.
 	ID common.Namespace `yaml:"id"`
 
+	// Mode is the mode in which the runtime is operated (read-only).
+	//
+	// If not provided, the runtime is operated according to the node mode.
+	Mode RuntimeMode `yaml:"mode,omitempty"`
+
 	// Components is the list of components to configure.
 	Components []ComponentConfig `yaml:"components,omitempty"`
 
@@ -224,10 +327,43 @@ type RuntimeConfig struct {
 	// to the base URL. Therefore, the provided URLs don't need to be valid
 	// endpoints themselves, only the constructed URLs need to be valid.
 	Registries []string `yaml:"registries,omitempty"`
+
+	// TxExecutionFailures overrides the transaction pool handling of transactions that
+	// repeatedly fail execution for this runtime.
+	TxExecutionFailures *tpConfig.ExecutionFailureConfig `yaml:"tx_execution_failures,omitempty"`
+
+	// TxLanes overrides the transaction pool priority lanes for this runtime.
+	TxLanes []tpConfig.LaneConfig `yaml:"tx_lanes,omitempty"`
+
+	// QueryReplicas is the number of additional runtime instances dedicated to serving queries.
+	//
+	// When set, it takes precedence over the global load balancer configuration for this runtime.
+	QueryReplicas uint64 `yaml:"query_replicas,omitempty"`
+
+	// StorageBackpressure configures how the executor reacts to the storage worker falling behind
+	// applying rounds of this runtime.
+	StorageBackpressure StorageBackpressureConfig `yaml:"storage_backpressure,omitempty"`
 }
 
 // Validate validates the runtime configuration.
 func (c *RuntimeConfig) Validate() error {
+	if err := c.Mode.Validate(); err != nil {
+		return fmt.Errorf("runtime %s: %w", c.ID, err)
+	}
+	if c.TxExecutionFailures != nil {
+		if err := c.TxExecutionFailures.Validate(); err != nil {
+			return fmt.Errorf("runtime %s: tx_execution_failures: %w", c.ID, err)
+		}
+	}
+	if err := tpConfig.ValidateLanes(c.TxLanes); err != nil {
+		return fmt.Errorf("runtime %s: tx_lanes: %w", c.ID, err)
+	}
+	if c.QueryReplicas > maxLoadBalancerInstances-1 {
+		return fmt.Errorf("runtime %s: cannot specify more than %d query replicas", c.ID, maxLoadBalancerInstances-1)
+	}
+	if err := c.StorageBackpressure.Validate(); err != nil {
+		return fmt.Errorf("runtime %s: storage_backpressure: %w", c.ID, err)
+	}
 	for _, comp := range c.Components {
 		if err := comp.Validate(); err != nil {
 			return err
@@ -236,6 +372,28 @@ func (c *RuntimeConfig) Validate() error {
 	return nil
 }
 
+// StorageBackpressureConfig is the storage backpressure configuration.
+//
+// The storage round lag is the number of runtime rounds that have been finalized by the consensus
+// layer but not yet applied to local storage.
+type StorageBackpressureConfig struct {
+	// SoftLagThreshold is the storage round lag beyond which the executor stops proposing batches
+	// but still commits to proposals of other executors. Zero disables the threshold.
+	SoftLagThreshold uint64 `yaml:"soft_lag_threshold,omitempty"`
+
+	// HardLagThreshold is the storage round lag beyond which the executor reports itself as
+	// unavailable so that it is not elected into the next committee. Zero disables the threshold.
+	HardLagThreshold uint64 `yaml:"hard_lag_threshold,omitempty"`
+}
+
+// Validate validates the storage backpressure configuration.
+func (c *StorageBackpressureConfig) Validate() error {
+	if c.SoftLagThreshold > 0 && c.HardLagThreshold > 0 && c.HardLagThreshold < c.SoftLagThreshold {
+		return fmt.Errorf("hard lag threshold must not be lower than soft lag threshold")
+	}
+	return nil
+}
+
 // ComponentConfig is the component configuration.
 type ComponentConfig struct {
 	// ID is the component identifier.
@@ -259,6 +417,14 @@ type ComponentConfig struct {
 
 	// Config contains component local configuration.
 	Config map[string]any `yaml:"config,omitempty"`
+
+	// DebugExecutable is the path to a local (non-bundled) runtime binary that should be executed
+	// instead of the bundled executable. The binary is watched for changes and the component is
+	// automatically restarted with the new binary whenever it changes.
+	//
+	// This is only supported for components running without a TEE and can only be used if the
+	// DebugDontBlameOasis flag is set.
+	DebugExecutable string `yaml:"debug_executable,omitempty"`
 }
 
 // Validate validates the component configuration.
@@ -272,6 +438,15 @@ func (c *ComponentConfig) Validate() error {
 		return fmt.Errorf("unknown TEE select mode: %s", c.TEE)
 	}
 
+	if c.DebugExecutable != "" {
+		if c.TEE != TEESelectModeNone {
+			return fmt.Errorf("component %s: debug_executable requires tee to be set to %s", c.ID, TEESelectModeNone)
+		}
+		if !filepath.IsAbs(c.DebugExecutable) {
+			return fmt.Errorf("component %s: debug_executable must be an absolute path", c.ID)
+		}
+	}
+
 	return nil
 }
 
@@ -355,6 +530,9 @@ type PruneConfig struct {
 	Interval time.Duration `yaml:"interval"`
 	// Number of last rounds to keep.
 	NumKept uint64 `yaml:"num_kept"`
+	// Maximum age of rounds to keep in addition to the last rounds (zero disables age-based
+	// retention). A round is only pruned once it is outside of both retention limits.
+	MaxAge time.Duration `yaml:"max_age,omitempty"`
 }
 
 // IndexerConfig is history indexer configuration.
@@ -363,8 +541,40 @@ type IndexerConfig struct {
 	//
 	// Setting it to zero uses the default batch size.
 	BatchSize uint16 `yaml:"batch_size,omitempty"`
+
+	// NumWorkers is the number of batches fetched in parallel during history reindex.
+	//
+	// Setting it to zero uses the default number of workers.
+	NumWorkers uint16 `yaml:"num_workers,omitempty"`
 }
 
+// TagIndexConfig is the transaction tag index configuration structure.
+type TagIndexConfig struct {
+	// Keys is the list of transaction tag keys to index. An empty list disables the index.
+	Keys []string `yaml:"keys,omitempty"`
+
+	// Retention is the number of most recent rounds kept in the index.
+	Retention uint64 `yaml:"retention,omitempty"`
+}
+
+// Enabled returns true iff the transaction tag index is enabled.
+func (c *TagIndexConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
+
+// CheckTxConfig is the runtime client transaction check configuration structure.
+type CheckTxConfig struct {
+	// RateLimit is the maximum number of transaction checks per second served by the runtime
+	// client for each runtime. Zero disables rate limiting.
+	RateLimit float64 `yaml:"rate_limit,omitempty"`
+
+	// Burst is the maximum number of transaction checks served at once.
+	Burst uint64 `yaml:"burst,omitempty"`
+}
+
+// maxLoadBalancerInstances is the maximum number of runtime instances used for load balancing.
+const maxLoadBalancerInstances = 128
+
 // LoadBalancerConfig is the load balancer configuration.
 type LoadBalancerConfig struct {
 	// NumInstances is the number of runtime instances to provision for load-balancing.
@@ -400,18 +610,49 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unknown runtime environment: %s", c.Environment)
 	}
 
+	if c.SGX.PCSURL != "" {
+		u, err := url.Parse(c.SGX.PCSURL)
+		if err != nil {
+			return fmt.Errorf("sgx.pcs_url is malformed: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("sgx.pcs_url must be an http or https URL")
+		}
+	}
+
 	switch c.Prune.Strategy {
 	case "none":
 	case "keep_last":
 		if c.Prune.Interval < 1*time.Second {
 			return fmt.Errorf("prune.interval must be >= 1 second")
 		}
+		if c.Prune.MaxAge < 0 {
+			return fmt.Errorf("prune.max_age must not be negative")
+		}
 	default:
 		return fmt.Errorf("unknown runtime history pruner strategy: %s", c.Prune.Strategy)
 	}
 
-	if c.LoadBalancer.NumInstances > 128 {
-		return fmt.Errorf("cannot specify more than 128 instances for load balancing")
+	if c.TagIndex.Enabled() && c.TagIndex.Retention == 0 {
+		return fmt.Errorf("tag_index.retention must be greater than zero")
+	}
+
+	if c.CheckTx.RateLimit < 0 {
+		return fmt.Errorf("check_tx.rate_limit must not be negative")
+	}
+	if c.CheckTx.RateLimit > 0 && c.CheckTx.Burst == 0 {
+		return fmt.Errorf("check_tx.burst must be greater than zero when rate limiting is enabled")
+	}
+
+	if err := c.TxPool.ExecutionFailures.Validate(); err != nil {
+		return fmt.Errorf("tx_pool.execution_failures: %w", err)
+	}
+	if err := tpConfig.ValidateLanes(c.TxPool.Lanes); err != nil {
+		return fmt.Errorf("tx_pool.lanes: %w", err)
+	}
+
+	if c.LoadBalancer.NumInstances > maxLoadBalancerInstances {
+		return fmt.Errorf("cannot specify more than %d instances for load balancing", maxLoadBalancerInstances)
 	}
 
 	for _, rt := range c.Runtimes {
@@ -502,13 +743,30 @@ func DefaultConfig() Config {
 		Indexer: IndexerConfig{
 			BatchSize: 1000,
 		},
+		TagIndex: TagIndexConfig{
+			Retention: 100_000,
+		},
+		CheckTx: CheckTxConfig{
+			RateLimit: 50,
+			Burst:     100,
+		},
 		SentryAddresses: []string{},
 		TxPool: tpConfig.Config{
 			MaxPoolSize:          50_000,
 			MaxLastSeenCacheSize: 100_000,
 			MaxCheckTxBatchSize:  128,
 			RecheckInterval:      5,
+			RecheckSweepRate:     1000,
 			RepublishInterval:    60 * time.Second,
+			ExecutionFailures: tpConfig.ExecutionFailureConfig{
+				MaxFailures:      3,
+				DenylistDuration: time.Hour,
+				SlowThreshold:    time.Second,
+			},
+			Persistence: tpConfig.PersistenceConfig{
+				MaxTxs:   10_000,
+				MaxBytes: 64 * 1024 * 1024,
+			},
 		},
 		PreWarmEpochs: 3,
 		LoadBalancer: LoadBalancerConfig{
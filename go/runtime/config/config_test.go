@@ -137,3 +137,148 @@ func TestNetworkingConfig(t *testing.T) {
 	err = cfg.Validate()
 	require.ErrorContains(err, "component rofl (foo-test): overlapping incoming IP/protocol/port")
 }
+
+func TestRuntimeMode(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID, otherID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	err = otherID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(err)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      mode: read-only
+    - id: 8000000000000000000000000000000000000000000000000000000000000001
+`
+	var cfg Config
+	err = yaml.Unmarshal([]byte(yamlCfg), &cfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	require.Equal(RuntimeModeReadOnly, cfg.GetRuntimeMode(runtimeID))
+	require.True(cfg.IsReadOnly(runtimeID))
+	require.Equal(RuntimeModeDefault, cfg.GetRuntimeMode(otherID))
+	require.False(cfg.IsReadOnly(otherID))
+	require.False(cfg.IsReadOnly(common.Namespace{}))
+
+	for _, rt := range cfg.Runtimes {
+		require.NoError(rt.Validate())
+	}
+
+	invalid := RuntimeConfig{ID: runtimeID, Mode: "unknown"}
+	require.Error(invalid.Validate())
+}
+
+func TestQueryReplicas(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      query_replicas: 3
+    - id: 8000000000000000000000000000000000000000000000000000000000000001
+`
+	var cfg Config
+	err = yaml.Unmarshal([]byte(yamlCfg), &cfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	require.Equal(map[common.Namespace]int{runtimeID: 3}, cfg.QueryReplicas())
+	for _, rt := range cfg.Runtimes {
+		require.NoError(rt.Validate())
+	}
+
+	invalid := RuntimeConfig{ID: runtimeID, QueryReplicas: 128}
+	require.ErrorContains(invalid.Validate(), "cannot specify more than 127 query replicas")
+}
+
+func TestStorageBackpressure(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID, otherID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+	err = otherID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(err)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      storage_backpressure:
+        soft_lag_threshold: 5
+        hard_lag_threshold: 20
+    - id: 8000000000000000000000000000000000000000000000000000000000000001
+`
+	var cfg Config
+	err = yaml.Unmarshal([]byte(yamlCfg), &cfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	require.Equal(StorageBackpressureConfig{SoftLagThreshold: 5, HardLagThreshold: 20}, cfg.GetStorageBackpressureConfig(runtimeID))
+	require.Equal(StorageBackpressureConfig{}, cfg.GetStorageBackpressureConfig(otherID))
+	for _, rt := range cfg.Runtimes {
+		require.NoError(rt.Validate())
+	}
+
+	invalid := RuntimeConfig{
+		ID: runtimeID,
+		StorageBackpressure: StorageBackpressureConfig{
+			SoftLagThreshold: 20,
+			HardLagThreshold: 5,
+		},
+	}
+	require.ErrorContains(invalid.Validate(), "hard lag threshold must not be lower than soft lag threshold")
+}
+
+func TestDebugExecutable(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	err := runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(err)
+
+	yamlCfg := `
+runtimes:
+    - id: 8000000000000000000000000000000000000000000000000000000000000000
+      components:
+        - id: ronl
+          tee: none
+          debug_executable: /path/to/runtime
+`
+	var cfg Config
+	err = yaml.Unmarshal([]byte(yamlCfg), &cfg)
+	require.NoError(err, "yaml.Unmarshal")
+
+	compCfg, ok := cfg.GetComponent(runtimeID, component.ID_RONL)
+	require.True(ok)
+	require.Equal("/path/to/runtime", compCfg.DebugExecutable)
+	require.NoError(cfg.Runtimes[0].Validate())
+
+	for _, invalid := range []ComponentConfig{
+		{ID: component.ID_RONL, DebugExecutable: "/path/to/runtime"},
+		{ID: component.ID_RONL, TEE: TEESelectModeSGX, DebugExecutable: "/path/to/runtime"},
+		{ID: component.ID_RONL, TEE: TEESelectModeNone, DebugExecutable: "relative/path"},
+	} {
+		require.Error(invalid.Validate())
+	}
+}
+
+func TestCheckTxConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	require.NoError(cfg.Validate())
+
+	cfg.CheckTx = CheckTxConfig{}
+	require.NoError(cfg.Validate(), "rate limiting should be disableable")
+
+	cfg.CheckTx = CheckTxConfig{RateLimit: 10}
+	require.ErrorContains(cfg.Validate(), "check_tx.burst must be greater than zero")
+
+	cfg.CheckTx = CheckTxConfig{RateLimit: -1, Burst: 1}
+	require.ErrorContains(cfg.Validate(), "check_tx.rate_limit must not be negative")
+}
@@ -0,0 +1,274 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+const (
+	// archiveVersion is the runtime history archive format version.
+	archiveVersion = 1
+
+	// importBatchSize is the maximum number of blocks imported in one transaction.
+	importBatchSize = 1000
+)
+
+// ErrInvalidArchive is the error returned when a runtime history archive fails verification.
+var ErrInvalidArchive = errors.New("runtime/history: invalid archive")
+
+// ArchiveHeader is the header of a runtime history archive.
+//
+// The header is followed by CBOR-serialized roothash.AnnotatedBlock for each of the rounds in
+// the archive, in ascending order.
+type ArchiveHeader struct {
+	cbor.Versioned
+
+	// RuntimeID is the identifier of the runtime the archive is for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// FromRound is the first round in the archive.
+	FromRound uint64 `json:"from_round"`
+	// ToRound is the last round in the archive.
+	ToRound uint64 `json:"to_round"`
+}
+
+// Export writes an archive of all blocks in the given (inclusive) round range to the writer.
+//
+// The archive can be imported by another node and is verified on import by checking that the
+// blocks form a hash chain that connects to the importing node's history.
+func (h *runtimeHistory) Export(ctx context.Context, fromRound, toRound uint64, w io.Writer) error {
+	if fromRound > toRound {
+		return fmt.Errorf("runtime/history: invalid round range [%d, %d]", fromRound, toRound)
+	}
+
+	enc := cbor.NewEncoder(w)
+	if err := enc.Encode(&ArchiveHeader{
+		Versioned: cbor.NewVersioned(archiveVersion),
+		RuntimeID: h.runtimeID,
+		FromRound: fromRound,
+		ToRound:   toRound,
+	}); err != nil {
+		return fmt.Errorf("runtime/history: failed to write archive header: %w", err)
+	}
+
+	// Rounds must be contiguous for the hash chain to be verifiable.
+	nextRound := fromRound
+	err := h.db.iterateBlocks(fromRound, toRound, func(blk *roothash.AnnotatedBlock) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if round := blk.Block.Header.Round; round != nextRound {
+			return fmt.Errorf("runtime/history: missing round %d in history", nextRound)
+		}
+		if err := enc.Encode(blk); err != nil {
+			return fmt.Errorf("runtime/history: failed to write block: %w", err)
+		}
+		nextRound++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if nextRound != toRound+1 {
+		return fmt.Errorf("runtime/history: missing round %d in history", nextRound)
+	}
+
+	h.logger.Info("exported history archive",
+		"from_round", fromRound,
+		"to_round", toRound,
+	)
+
+	return nil
+}
+
+// Import imports an archive produced by Export into the history.
+//
+// Blocks in the archive must form a hash chain. In case the history is not empty, the chain must
+// also connect to (or overlap with) existing blocks and any overlapping blocks must be identical.
+// If verification fails, no blocks from the archive remain in the history.
+func (h *runtimeHistory) Import(ctx context.Context, r io.Reader) error {
+	dec := cbor.NewDecoder(r)
+
+	var hdr ArchiveHeader
+	if err := dec.Decode(&hdr); err != nil {
+		return fmt.Errorf("%w: malformed header: %w", ErrInvalidArchive, err)
+	}
+	if v := hdr.V; v != archiveVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, v)
+	}
+	if !hdr.RuntimeID.Equal(&h.runtimeID) {
+		return fmt.Errorf("%w: archive for different runtime (expected: %s got: %s)",
+			ErrInvalidArchive,
+			h.runtimeID,
+			hdr.RuntimeID,
+		)
+	}
+	if hdr.FromRound > hdr.ToRound {
+		return fmt.Errorf("%w: invalid round range [%d, %d]", ErrInvalidArchive, hdr.FromRound, hdr.ToRound)
+	}
+
+	var empty bool
+	switch _, err := h.db.getEarliestBlock(); err {
+	case nil:
+	case roothash.ErrNotFound:
+		empty = true
+	default:
+		return err
+	}
+	meta, err := h.db.metadata()
+	if err != nil {
+		return err
+	}
+
+	imp := &archiveImport{
+		db:        h.db,
+		runtimeID: h.runtimeID,
+	}
+	if err = imp.run(ctx, dec.Decode, &hdr, empty); err != nil {
+		if rerr := h.db.rollbackImport(imp.written, meta); rerr != nil {
+			h.logger.Error("failed to remove partially imported blocks",
+				"err", rerr,
+			)
+		}
+		return err
+	}
+
+	h.logger.Info("imported history archive",
+		"from_round", hdr.FromRound,
+		"to_round", hdr.ToRound,
+		"num_imported", len(imp.written),
+	)
+
+	return nil
+}
+
+type archiveImport struct {
+	db        *DB
+	runtimeID common.Namespace
+
+	prevHash  hash.Hash
+	connected bool
+	batch     []*roothash.AnnotatedBlock
+	written   []uint64
+}
+
+func (imp *archiveImport) run(ctx context.Context, decode func(any) error, hdr *ArchiveHeader, empty bool) error {
+	// Anchor the first block to the preceding block, if present locally.
+	var anchored bool
+	if hdr.FromRound > 0 {
+		switch prev, err := imp.db.getBlock(hdr.FromRound - 1); err {
+		case nil:
+			imp.prevHash = prev.Block.Header.EncodedHash()
+			anchored = true
+		case roothash.ErrNotFound:
+		default:
+			return err
+		}
+	}
+
+	for round := hdr.FromRound; ; round++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var blk roothash.AnnotatedBlock
+		if err := decode(&blk); err != nil {
+			return fmt.Errorf("%w: malformed block for round %d: %w", ErrInvalidArchive, round, err)
+		}
+		if err := imp.verifyBlock(&blk, round, anchored || round > hdr.FromRound); err != nil {
+			return err
+		}
+		if err := imp.addBlock(&blk); err != nil {
+			return err
+		}
+
+		if round == hdr.ToRound {
+			break
+		}
+	}
+	imp.connected = imp.connected || anchored
+
+	if err := imp.flush(); err != nil {
+		return err
+	}
+
+	// Anchor the last block to the following block, if present locally.
+	if hdr.ToRound < math.MaxUint64 {
+		switch next, err := imp.db.getBlock(hdr.ToRound + 1); err {
+		case nil:
+			if !next.Block.Header.PreviousHash.Equal(&imp.prevHash) {
+				return fmt.Errorf("%w: block for round %d does not connect to history", ErrInvalidArchive, hdr.ToRound)
+			}
+			imp.connected = true
+		case roothash.ErrNotFound:
+		default:
+			return err
+		}
+	}
+
+	if !empty && !imp.connected {
+		return fmt.Errorf("%w: archive does not connect to history", ErrInvalidArchive)
+	}
+	return nil
+}
+
+func (imp *archiveImport) verifyBlock(blk *roothash.AnnotatedBlock, round uint64, checkPrev bool) error {
+	if blk.Block == nil {
+		return fmt.Errorf("%w: missing block for round %d", ErrInvalidArchive, round)
+	}
+	if !blk.Block.Header.Namespace.Equal(&imp.runtimeID) {
+		return fmt.Errorf("%w: block for round %d is for a different runtime", ErrInvalidArchive, round)
+	}
+	if blk.Block.Header.Round != round {
+		return fmt.Errorf("%w: unexpected round (expected: %d got: %d)", ErrInvalidArchive, round, blk.Block.Header.Round)
+	}
+	if checkPrev && !blk.Block.Header.PreviousHash.Equal(&imp.prevHash) {
+		return fmt.Errorf("%w: block for round %d does not connect to the previous block", ErrInvalidArchive, round)
+	}
+	imp.prevHash = blk.Block.Header.EncodedHash()
+	return nil
+}
+
+func (imp *archiveImport) addBlock(blk *roothash.AnnotatedBlock) error {
+	// Blocks that already exist must be identical.
+	switch existing, err := imp.db.getBlock(blk.Block.Header.Round); err {
+	case nil:
+		existingHash := existing.Block.Header.EncodedHash()
+		blkHash := blk.Block.Header.EncodedHash()
+		if !existingHash.Equal(&blkHash) || existing.Height != blk.Height {
+			return fmt.Errorf("%w: block for round %d differs from history", ErrInvalidArchive, blk.Block.Header.Round)
+		}
+		imp.connected = true
+		return nil
+	case roothash.ErrNotFound:
+	default:
+		return err
+	}
+
+	imp.batch = append(imp.batch, blk)
+	if len(imp.batch) >= importBatchSize {
+		return imp.flush()
+	}
+	return nil
+}
+
+func (imp *archiveImport) flush() error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+	if err := imp.db.importBlocks(imp.batch); err != nil {
+		return err
+	}
+	for _, blk := range imp.batch {
+		imp.written = append(imp.written, blk.Block.Header.Round)
+	}
+	imp.batch = imp.batch[:0]
+	return nil
+}
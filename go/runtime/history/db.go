@@ -2,6 +2,7 @@ package history
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/dgraph-io/badger/v4/options"
@@ -153,8 +154,8 @@ func (d *DB) metadata() (*dbMetadata, error) {
 	return meta, nil
 }
 
-func (d *DB) commit(blks []*roothash.AnnotatedBlock) error {
-	if len(blks) == 0 {
+func (d *DB) commit(blks []*roothash.AnnotatedBlock, height int64) error {
+	if len(blks) == 0 && height == 0 {
 		return nil
 	}
 
@@ -196,6 +197,11 @@ func (d *DB) commit(blks []*roothash.AnnotatedBlock) error {
 				meta.LastConsensusHeight = blk.Height
 			}
 		}
+
+		// Record heights without any blocks as seen.
+		if height > meta.LastConsensusHeight {
+			meta.LastConsensusHeight = height
+		}
 		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
 	})
 }
@@ -270,6 +276,79 @@ func (d *DB) getLastBlock() (*roothash.AnnotatedBlock, error) {
 	return &blk, nil
 }
 
+func (d *DB) iterateBlocks(fromRound, toRound uint64, fn func(*roothash.AnnotatedBlock) error) error {
+	return d.db.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix:         blockKeyFmt.Encode(),
+			PrefetchValues: true,
+		})
+		defer it.Close()
+
+		for it.Seek(blockKeyFmt.Encode(fromRound)); it.Valid(); it.Next() {
+			item := it.Item()
+
+			var round uint64
+			if !blockKeyFmt.Decode(item.Key(), &round) {
+				// This should not happen as the Badger iterator should take care of it.
+				panic("runtime/history: bad iterator")
+			}
+			if round > toRound {
+				break
+			}
+
+			var blk roothash.AnnotatedBlock
+			if err := item.Value(func(val []byte) error {
+				return cbor.UnmarshalTrusted(val, &blk)
+			}); err != nil {
+				return err
+			}
+			if err := fn(&blk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *DB) importBlocks(blks []*roothash.AnnotatedBlock) error {
+	return d.db.Update(func(tx *badger.Txn) error {
+		meta, err := d.queryGetMetadata(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, blk := range blks {
+			if err := tx.Set(blockKeyFmt.Encode(blk.Block.Header.Round), cbor.Marshal(blk)); err != nil {
+				return err
+			}
+
+			// Imported blocks may also extend the history.
+			meta.LastRound = max(meta.LastRound, blk.Block.Header.Round)
+			meta.LastConsensusHeight = max(meta.LastConsensusHeight, blk.Height)
+		}
+		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+	})
+}
+
+func (d *DB) rollbackImport(rounds []uint64, meta *dbMetadata) error {
+	for batch := range slices.Chunk(rounds, importBatchSize) {
+		if err := d.db.Update(func(tx *badger.Txn) error {
+			for _, round := range batch {
+				if err := tx.Delete(blockKeyFmt.Encode(round)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return d.db.Update(func(tx *badger.Txn) error {
+		return tx.Set(metadataKeyFmt.Encode(), cbor.Marshal(meta))
+	})
+}
+
 func (d *DB) close() {
 	d.gc.Stop()
 	d.db.Close()
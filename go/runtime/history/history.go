@@ -4,6 +4,7 @@ package history
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"sync"
 	"time"
@@ -32,9 +33,19 @@ type History interface {
 	roothash.BlockHistory
 	consensus.StatePruneHandler
 
+	// CommitToHeight commits the given blocks and records all consensus heights up to and
+	// including the given height as seen, even if there are no blocks at those heights.
+	CommitToHeight(blks []*roothash.AnnotatedBlock, height int64) error
+
 	// Pruner returns the history pruner.
 	Pruner() Pruner
 
+	// Export writes an archive of all blocks in the given (inclusive) round range to the writer.
+	Export(ctx context.Context, fromRound, toRound uint64, w io.Writer) error
+
+	// Import imports an archive produced by Export into the history.
+	Import(ctx context.Context, r io.Reader) error
+
 	// Close closes the history keeper.
 	Close()
 }
@@ -94,13 +105,16 @@ func (h *runtimeHistory) SetInitialized() error {
 }
 
 func (h *runtimeHistory) Commit(blks []*roothash.AnnotatedBlock) error {
-	if len(blks) == 0 {
-		return nil
-	}
+	return h.CommitToHeight(blks, 0)
+}
 
-	if err := h.db.commit(blks); err != nil {
+func (h *runtimeHistory) CommitToHeight(blks []*roothash.AnnotatedBlock, height int64) error {
+	if err := h.db.commit(blks, height); err != nil {
 		return err
 	}
+	if len(blks) == 0 {
+		return nil
+	}
 
 	// Notify the pruner what the new round is.
 	lastBlk := blks[len(blks)-1]
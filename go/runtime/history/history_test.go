@@ -1,9 +1,12 @@
 package history
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
@@ -400,7 +404,7 @@ func TestHistoryPrune(t *testing.T) {
 
 	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune test ns"), 0)
 
-	pruneFactory := NewKeepLastPrunerFactory(10, 100*time.Millisecond)
+	pruneFactory := NewKeepLastPrunerFactory(10, 0, 100*time.Millisecond)
 	history, err := New(runtimeID, dataDir, pruneFactory, true)
 	require.NoError(err, "New")
 	defer history.Close()
@@ -501,7 +505,7 @@ func TestHistoryPruneError(t *testing.T) {
 
 	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune error test ns"), 0)
 
-	pruneFactory := NewKeepLastPrunerFactory(10, 100*time.Millisecond)
+	pruneFactory := NewKeepLastPrunerFactory(10, 0, 100*time.Millisecond)
 	history, err := New(runtimeID, dataDir, pruneFactory, true)
 	require.NoError(err, "New")
 	defer history.Close()
@@ -558,3 +562,227 @@ func createBlocks(runtimeID common.Namespace) []*roothash.AnnotatedBlock {
 	}
 	return blks
 }
+
+func createChainedBlocks(runtimeID common.Namespace, n int, timestamp func(round uint64) uint64) []*roothash.AnnotatedBlock {
+	blks := make([]*roothash.AnnotatedBlock, 0, n)
+	blk := block.NewGenesisBlock(runtimeID, timestamp(0))
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			blk = block.NewEmptyBlock(blk, timestamp(uint64(i)), block.Normal)
+		}
+		blks = append(blks, &roothash.AnnotatedBlock{
+			Height: int64(i + 1),
+			Block:  blk,
+		})
+	}
+	return blks
+}
+
+func waitPruned(t *testing.T, history History, round uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
+	defer cancel()
+	for {
+		_, err := history.GetCommittedBlock(ctx, round)
+		if err == roothash.ErrNotFound {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("failed to wait for round %d to be pruned", round)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestHistoryPruneMaxAge(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune max age test ns"), 0)
+
+	pruneFactory := NewKeepLastPrunerFactory(5, time.Hour, 100*time.Millisecond)
+	history, err := New(runtimeID, t.TempDir(), pruneFactory, false)
+	require.NoError(err, "New")
+	defer history.Close()
+
+	// The first 10 rounds are older than the maximum age.
+	now := uint64(time.Now().Unix())
+	blks := createChainedBlocks(runtimeID, 20, func(round uint64) uint64 {
+		if round < 10 {
+			return now - 2*3600 + round
+		}
+		return now - 60 + round
+	})
+	err = history.Commit(blks)
+	require.NoError(err, "Commit")
+
+	waitPruned(t, history, 9)
+	time.Sleep(200 * time.Millisecond)
+
+	// Rounds younger than the maximum age should be kept even though they are
+	// not among the last 5 rounds.
+	for i := range blks {
+		_, err = history.GetCommittedBlock(ctx, uint64(i))
+		if i < 10 {
+			require.Equal(roothash.ErrNotFound, err, "GetCommittedBlock(%d) should fail for pruned block", i)
+			continue
+		}
+		require.NoError(err, "GetCommittedBlock(%d)", i)
+	}
+}
+
+type testPruneRetainingHandler struct {
+	mu        sync.Mutex
+	retainFor uint64
+}
+
+func (h *testPruneRetainingHandler) Prune(rounds []uint64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, round := range rounds {
+		if round >= h.retainFor {
+			return fmt.Errorf("%w: round %d", ErrRoundsRetained, round)
+		}
+	}
+	return nil
+}
+
+func (h *testPruneRetainingHandler) setRetainFor(round uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.retainFor = round
+}
+
+func TestHistoryPruneRetained(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history prune retained test ns"), 0)
+
+	pruneFactory := NewKeepLastPrunerFactory(10, 0, 100*time.Millisecond)
+	history, err := New(runtimeID, t.TempDir(), pruneFactory, false)
+	require.NoError(err, "New")
+	defer history.Close()
+
+	var ph testPruneRetainingHandler
+	history.Pruner().RegisterHandler(&ph)
+
+	blks := createChainedBlocks(runtimeID, 51, func(uint64) uint64 { return 0 })
+	err = history.Commit(blks)
+	require.NoError(err, "Commit")
+
+	// Nothing should be pruned while the rounds are retained.
+	time.Sleep(200 * time.Millisecond)
+	for i := range blks {
+		_, err = history.GetCommittedBlock(ctx, uint64(i))
+		require.NoError(err, "GetCommittedBlock(%d)", i)
+	}
+
+	// Release the rounds and make sure they get pruned.
+	ph.setRetainFor(100)
+	err = history.Commit(createChainedBlocks(runtimeID, 52, func(uint64) uint64 { return 0 })[51:])
+	require.NoError(err, "Commit")
+	waitPruned(t, history, 41)
+}
+
+func TestHistoryArchive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history archive test ns"), 0)
+	otherRuntimeID := common.NewTestNamespaceFromSeed([]byte("history archive test ns 2"), 0)
+	blks := createChainedBlocks(runtimeID, 30, func(round uint64) uint64 { return round })
+
+	newHistory := func(id common.Namespace) History {
+		history, err := New(id, t.TempDir(), NewNonePrunerFactory(), false)
+		require.NoError(err, "New")
+		t.Cleanup(history.Close)
+		return history
+	}
+	requireRounds := func(history History, from, to uint64) {
+		for round := from; round <= to; round++ {
+			blk, err := history.GetCommittedBlock(ctx, round)
+			require.NoError(err, "GetCommittedBlock(%d)", round)
+			require.Equal(blks[round].Block, blk)
+		}
+	}
+	export := func(history History, from, to uint64) []byte {
+		var buf bytes.Buffer
+		err := history.Export(ctx, from, to, &buf)
+		require.NoError(err, "Export")
+		return buf.Bytes()
+	}
+
+	source := newHistory(runtimeID)
+	err := source.Commit(blks)
+	require.NoError(err, "Commit")
+
+	// Export should fail for rounds not in history.
+	err = source.Export(ctx, 25, 35, io.Discard)
+	require.Error(err, "Export should fail for missing rounds")
+
+	// Import into an empty history.
+	archive := export(source, 10, 19)
+	target := newHistory(runtimeID)
+	err = target.Import(ctx, bytes.NewReader(archive))
+	require.NoError(err, "Import")
+	requireRounds(target, 10, 19)
+	lastHeight, err := target.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(blks[19].Height, lastHeight)
+
+	// Import older rounds connecting to existing history.
+	err = target.Import(ctx, bytes.NewReader(export(source, 0, 9)))
+	require.NoError(err, "Import")
+	requireRounds(target, 0, 19)
+	earliest, err := target.GetEarliestBlock(ctx)
+	require.NoError(err, "GetEarliestBlock")
+	require.EqualValues(0, earliest.Header.Round)
+
+	// Import of overlapping and newer rounds.
+	err = target.Import(ctx, bytes.NewReader(export(source, 15, 29)))
+	require.NoError(err, "Import")
+	requireRounds(target, 0, 29)
+
+	// Archives that do not connect to existing history should be rejected.
+	disconnected := newHistory(runtimeID)
+	err = disconnected.Commit(blks[:5])
+	require.NoError(err, "Commit")
+	err = disconnected.Import(ctx, bytes.NewReader(export(source, 10, 19)))
+	require.ErrorIs(err, ErrInvalidArchive)
+	_, err = disconnected.GetCommittedBlock(ctx, 10)
+	require.Equal(roothash.ErrNotFound, err, "rejected archive should not be imported")
+	lastHeight, err = disconnected.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(blks[4].Height, lastHeight)
+
+	// Archives for other runtimes should be rejected.
+	err = newHistory(otherRuntimeID).Import(ctx, bytes.NewReader(archive))
+	require.ErrorIs(err, ErrInvalidArchive)
+
+	// Tampered archives should be rejected.
+	var buf bytes.Buffer
+	enc := cbor.NewEncoder(&buf)
+	require.NoError(enc.Encode(&ArchiveHeader{
+		Versioned: cbor.NewVersioned(archiveVersion),
+		RuntimeID: runtimeID,
+		FromRound: 0,
+		ToRound:   2,
+	}))
+	tampered := *blks[1].Block
+	tampered.Header.StateRoot.FromBytes([]byte("tampered"))
+	for _, blk := range []*roothash.AnnotatedBlock{blks[0], {Height: blks[1].Height, Block: &tampered}, blks[2]} {
+		require.NoError(enc.Encode(blk))
+	}
+	tamperedTarget := newHistory(runtimeID)
+	err = tamperedTarget.Import(ctx, &buf)
+	require.ErrorIs(err, ErrInvalidArchive)
+	_, err = tamperedTarget.GetCommittedBlock(ctx, 0)
+	require.Equal(roothash.ErrNotFound, err, "rejected archive should not be imported")
+
+	// Truncated archives should be rejected.
+	err = newHistory(runtimeID).Import(ctx, bytes.NewReader(archive[:len(archive)-10]))
+	require.ErrorIs(err, ErrInvalidArchive)
+}
@@ -13,6 +13,7 @@ import (
 	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
 
 const (
@@ -39,6 +40,8 @@ type IndexerStatus struct {
 type ReindexStatus struct {
 	// BatchSize is the number of blocks to reindex in a single batch.
 	BatchSize uint16 `json:"batch_size"`
+	// NumWorkers is the number of batches that are reindexed in parallel.
+	NumWorkers uint16 `json:"num_workers"`
 	// LastHeight is the last consensus height that was indexed.
 	LastHeight int64 `json:"last_height"`
 	// StartHeight is the first height of history reindex interval.
@@ -51,6 +54,9 @@ type ReindexStatus struct {
 
 const (
 	maxPendingBlocks = 10
+
+	// defaultNumWorkers is the default number of batches reindexed in parallel.
+	defaultNumWorkers = 4
 )
 
 // BlockIndexer is responsible for indexing and committing finalized
@@ -59,9 +65,10 @@ type BlockIndexer struct {
 	mu       sync.RWMutex
 	startOne cmSync.One
 
-	consensus consensus.Service
-	history   History
-	batchSize uint16
+	consensus  consensus.Service
+	history    History
+	batchSize  uint16
+	numWorkers uint16
 
 	status      string
 	lastHeight  int64
@@ -74,16 +81,29 @@ type BlockIndexer struct {
 }
 
 // NewBlockIndexer creates a new block indexer.
-func NewBlockIndexer(consensus consensus.Service, history History, batchSize uint16) *BlockIndexer {
+//
+// During history reindex, up to the given number of batches of consensus heights are fetched
+// in parallel (zero uses the default) and applied in order.
+func NewBlockIndexer(consensus consensus.Service, history History, batchSize uint16, numWorkers uint16) *BlockIndexer {
 	logger := logging.GetLogger("runtime/history/indexer").With("runtime_id", history.RuntimeID())
 
-	return &BlockIndexer{
-		startOne:  cmSync.NewOne(),
-		consensus: consensus,
-		history:   history,
-		batchSize: batchSize,
-		logger:    logger,
+	if numWorkers == 0 {
+		numWorkers = defaultNumWorkers
+	}
+
+	bi := &BlockIndexer{
+		startOne:   cmSync.NewOne(),
+		consensus:  consensus,
+		history:    history,
+		batchSize:  batchSize,
+		numWorkers: numWorkers,
+		logger:     logger,
 	}
+
+	// Prevent pruning of rounds while the history is being reindexed.
+	history.Pruner().RegisterHandler(bi)
+
+	return bi
 }
 
 // Start starts the indexer.
@@ -110,20 +130,42 @@ func (bi *BlockIndexer) Status() *IndexerStatus {
 		return status
 	}
 
-	elapsed := time.Since(bi.started).Milliseconds()
-	remaining := elapsed * (bi.endHeight - bi.lastHeight) / max((bi.lastHeight-bi.startHeight+1), 1)
-	eta := time.Now().Add(time.Duration(remaining) * time.Millisecond)
 	status.ReindexStatus = &ReindexStatus{
 		BatchSize:   bi.batchSize,
+		NumWorkers:  bi.numWorkers,
 		LastHeight:  bi.lastHeight,
 		StartHeight: bi.startHeight,
 		EndHeight:   bi.endHeight,
-		ETA:         eta,
+		ETA:         bi.reindexETA(),
 	}
 
 	return status
 }
 
+// reindexETA returns the expected time of history reindex completion.
+//
+// Must be called with the lock held.
+func (bi *BlockIndexer) reindexETA() time.Time {
+	elapsed := time.Since(bi.started).Milliseconds()
+	remaining := elapsed * (bi.endHeight - bi.lastHeight) / max((bi.lastHeight-bi.startHeight+1), 1)
+	return time.Now().Add(time.Duration(remaining) * time.Millisecond)
+}
+
+// Prune implements PruneHandler.
+func (bi *BlockIndexer) Prune([]uint64) error {
+	bi.mu.RLock()
+	defer bi.mu.RUnlock()
+
+	switch bi.status {
+	case statusStarted, statusReindexing:
+		// Reindexed rounds may not yet have been processed by other history
+		// consumers (e.g., the storage worker), so keep them until done.
+		return fmt.Errorf("%w: history reindex in progress", ErrRoundsRetained)
+	default:
+		return nil
+	}
+}
+
 func (bi *BlockIndexer) run(ctx context.Context) {
 	bi.logger.Info("starting")
 
@@ -181,6 +223,14 @@ func (bi *BlockIndexer) index(ctx context.Context, blkCh <-chan *roothash.Annota
 	for {
 		select {
 		case blk := <-blkCh:
+			// Skip blocks that were already indexed during reindex.
+			if height, err := bi.history.LastConsensusHeight(); err == nil && blk.Height <= height {
+				bi.logger.Debug("skipping already indexed block",
+					"round", blk.Block.Header.Round,
+					"height", blk.Height,
+				)
+				continue
+			}
 			blks = append(blks, blk)
 		case <-time.After(retry):
 		case <-ctx.Done():
@@ -318,17 +368,66 @@ func (bi *BlockIndexer) reindexTo(ctx context.Context, height int64) error {
 	bi.mu.Unlock()
 
 	batchSize := int64(bi.batchSize)
-	for start := startHeight; start <= height; start += batchSize {
-		end := min(start+batchSize-1, height)
-		if err = bi.reindexRange(ctx, start, end); err != nil {
-			return fmt.Errorf("failed to reindex batch: %w", err)
+	for start := startHeight; start <= height; {
+		// Fetch multiple batches in parallel.
+		var batches []*reindexBatch
+		for i := 0; i < int(bi.numWorkers) && start <= height; i++ {
+			end := min(start+batchSize-1, height)
+			batches = append(batches, &reindexBatch{
+				start: start,
+				end:   end,
+			})
+			start = end + 1
 		}
+
+		var wg sync.WaitGroup
+		for _, batch := range batches {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				batch.blocks, batch.err = bi.fetchRange(ctx, batch.start, batch.end)
+			}()
+		}
+		wg.Wait()
+
+		// Apply batches in order, checkpointing progress after each batch so that
+		// reindex can resume after a restart.
+		for _, batch := range batches {
+			if batch.err != nil {
+				return fmt.Errorf("failed to reindex batch: %w", batch.err)
+			}
+			if err = bi.commitBlocksToHeight(batch.blocks, batch.end); err != nil {
+				return fmt.Errorf("failed to commit blocks: %w", err)
+			}
+
+			bi.mu.Lock()
+			bi.lastHeight = batch.end
+			bi.mu.Unlock()
+		}
+
+		bi.mu.RLock()
+		eta := bi.reindexETA()
+		bi.mu.RUnlock()
+
+		bi.logger.Info("reindex progress",
+			"last_height", batches[len(batches)-1].end,
+			"end_height", height,
+			"eta", eta,
+		)
 	}
 
 	return nil
 }
 
-func (bi *BlockIndexer) reindexRange(ctx context.Context, start int64, end int64) error {
+// reindexBatch is a range of consensus heights that is reindexed together.
+type reindexBatch struct {
+	start  int64
+	end    int64
+	blocks []*roothash.AnnotatedBlock
+	err    error
+}
+
+func (bi *BlockIndexer) fetchRange(ctx context.Context, start int64, end int64) ([]*roothash.AnnotatedBlock, error) {
 	bi.logger.Debug("reindexing blocks",
 		"start_height", start,
 		"end_height", end,
@@ -355,7 +454,7 @@ func (bi *BlockIndexer) reindexRange(ctx context.Context, start int64, end int64
 			)
 			continue
 		default:
-			return fmt.Errorf("failed to get runtime state: %w", err)
+			return nil, fmt.Errorf("failed to get runtime state: %w", err)
 		}
 		if state.LastBlockHeight != height {
 			// No new block at this height, skipping.
@@ -366,34 +465,45 @@ func (bi *BlockIndexer) reindexRange(ctx context.Context, start int64, end int64
 			Height: state.LastBlockHeight,
 			Block:  state.LastBlock,
 		}
+		if blk.Block.Header.HeaderType == block.Normal {
+			var results *roothash.RoundResults
+			results, err = bi.consensus.RootHash().GetLastRoundResults(ctx, &roothash.RuntimeRequest{
+				RuntimeID: bi.history.RuntimeID(),
+				Height:    height,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get round results: %w", err)
+			}
+			blk.BatchSummary = results.BatchSummary
+		}
 		blocks = append(blocks, blk)
 	}
 
-	if err := bi.commitBlocks(blocks); err != nil {
-		return fmt.Errorf("failed to commit blocks: %w", err)
-	}
-
-	bi.mu.Lock()
-	bi.lastHeight = end
-	bi.mu.Unlock()
-
-	bi.logger.Debug("block reindex completed")
-	return nil
+	return blocks, nil
 }
 
 func (bi *BlockIndexer) commitBlocks(blocks []*roothash.AnnotatedBlock) error {
 	if len(blocks) == 0 {
 		return nil
 	}
+	return bi.commitBlocksToHeight(blocks, blocks[len(blocks)-1].Height)
+}
 
-	bi.logger.Debug("committing blocks",
-		"start_round", blocks[0].Block.Header.Round,
-		"end_round", blocks[len(blocks)-1].Block.Header.Round,
-	)
+func (bi *BlockIndexer) commitBlocksToHeight(blocks []*roothash.AnnotatedBlock, height int64) error {
+	if len(blocks) > 0 {
+		bi.logger.Debug("committing blocks",
+			"start_round", blocks[0].Block.Header.Round,
+			"end_round", blocks[len(blocks)-1].Block.Header.Round,
+			"height", height,
+		)
+	}
 
-	if err := bi.history.Commit(blocks); err != nil {
+	if err := bi.history.CommitToHeight(blocks, height); err != nil {
 		return err
 	}
+	if len(blocks) == 0 {
+		return nil
+	}
 
 	bi.mu.Lock()
 	defer bi.mu.Unlock()
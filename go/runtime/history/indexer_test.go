@@ -0,0 +1,228 @@
+package history
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+const (
+	testBlockInterval = 5
+	reindexTimeout    = 10 * time.Second
+)
+
+type testConsensus struct {
+	consensus.Service
+
+	core     *testConsensusCore
+	roothash *testRootHash
+}
+
+func (c *testConsensus) Core() consensus.Backend {
+	return c.core
+}
+
+func (c *testConsensus) RootHash() roothash.Backend {
+	return c.roothash
+}
+
+type testConsensusCore struct {
+	consensus.Backend
+}
+
+func (c *testConsensusCore) GetLastRetainedHeight(context.Context) (int64, error) {
+	return 1, nil
+}
+
+type testRootHash struct {
+	roothash.Backend
+
+	mu          sync.Mutex
+	blocks      []*roothash.AnnotatedBlock
+	requested   []int64
+	stallHeight int64
+
+	notifier *pubsub.Broker
+}
+
+func (r *testRootHash) GetRuntimeState(ctx context.Context, request *roothash.RuntimeRequest) (*roothash.RuntimeState, error) {
+	r.mu.Lock()
+	r.requested = append(r.requested, request.Height)
+	stall := r.stallHeight > 0 && request.Height >= r.stallHeight
+	r.mu.Unlock()
+
+	if stall {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// Find the latest block at the given height.
+	var state *roothash.RuntimeState
+	for _, blk := range r.blocks {
+		if blk.Height > request.Height {
+			break
+		}
+		state = &roothash.RuntimeState{
+			LastBlock:       blk.Block,
+			LastBlockHeight: blk.Height,
+		}
+	}
+	if state == nil {
+		return nil, roothash.ErrInvalidRuntime
+	}
+	return state, nil
+}
+
+func (r *testRootHash) GetLastRoundResults(_ context.Context, request *roothash.RuntimeRequest) (*roothash.RoundResults, error) {
+	for _, blk := range r.blocks {
+		if blk.Height == request.Height {
+			return &roothash.RoundResults{BatchSummary: blk.BatchSummary}, nil
+		}
+	}
+	return &roothash.RoundResults{}, nil
+}
+
+func (r *testRootHash) WatchBlocks(context.Context, common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	sub := r.notifier.Subscribe()
+	ch := make(chan *roothash.AnnotatedBlock)
+	sub.Unwrap(ch)
+	return ch, sub, nil
+}
+
+func (r *testRootHash) publish(blk *roothash.AnnotatedBlock) {
+	r.notifier.Broadcast(blk)
+}
+
+func (r *testRootHash) setStallHeight(height int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stallHeight = height
+}
+
+func (r *testRootHash) requestedHeights() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	heights := slices.Clone(r.requested)
+	r.requested = nil
+	return heights
+}
+
+func waitLastConsensusHeight(t *testing.T, history History, height int64) {
+	deadline := time.After(reindexTimeout)
+	for {
+		lastHeight, err := history.LastConsensusHeight()
+		require.NoError(t, err, "LastConsensusHeight")
+		if lastHeight >= height {
+			return
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("failed to wait for height %d (last height: %d)", height, lastHeight)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestBlockIndexerReindexResume(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("history indexer test ns"), 0)
+	history, err := New(runtimeID, t.TempDir(), NewNonePrunerFactory(), false)
+	require.NoError(err, "New")
+	defer history.Close()
+
+	// Produce a block every few heights.
+	blks := createChainedBlocks(runtimeID, 101, func(round uint64) uint64 { return round })
+	for i, blk := range blks {
+		blk.Height = int64(i+1) * testBlockInterval
+	}
+	blks[60].BatchSummary = &commitment.BatchSummary{
+		GasUsed:   1000,
+		BatchSize: 256,
+		TxCount:   2,
+	}
+	rh := &testRootHash{
+		blocks:   blks[:100],
+		notifier: pubsub.NewBroker(true),
+	}
+	cons := &testConsensus{
+		core:     &testConsensusCore{},
+		roothash: rh,
+	}
+	rh.publish(blks[99])
+
+	// Start reindexing and kill the indexer midway.
+	rh.setStallHeight(251)
+	indexer := NewBlockIndexer(cons, history, 10, 4)
+	indexer.Start()
+	waitLastConsensusHeight(t, history, 240)
+
+	status := indexer.Status()
+	require.Equal(statusReindexing, status.Status)
+	require.NotNil(status.ReindexStatus)
+	require.EqualValues(4, status.ReindexStatus.NumWorkers)
+	require.EqualValues(500, status.ReindexStatus.EndHeight)
+
+	indexer.Stop()
+
+	// Batches fetched before the stalled one should be applied and retained.
+	lastHeight, err := history.LastConsensusHeight()
+	require.NoError(err, "LastConsensusHeight")
+	require.EqualValues(250, lastHeight)
+	_, err = history.GetCommittedBlock(ctx, 49)
+	require.NoError(err, "GetCommittedBlock(49)")
+	_, err = history.GetCommittedBlock(ctx, 50)
+	require.Equal(roothash.ErrNotFound, err, "blocks from unfinished batches should not be committed")
+
+	// Restart the indexer, which should resume from the last checkpointed height.
+	rh.setStallHeight(0)
+	rh.requestedHeights()
+	indexer = NewBlockIndexer(cons, history, 10, 4)
+	indexer.Start()
+	defer indexer.Stop()
+
+	select {
+	case <-history.Initialized():
+	case <-time.After(reindexTimeout):
+		t.Fatalf("failed to wait for reindex to complete")
+	}
+
+	requested := rh.requestedHeights()
+	require.NotEmpty(requested)
+	require.EqualValues(251, slices.Min(requested), "reindex should resume from the checkpoint")
+	require.EqualValues(500, slices.Max(requested))
+
+	for round := range uint64(100) {
+		blk, err := history.GetCommittedBlock(ctx, round)
+		require.NoError(err, "GetCommittedBlock(%d)", round)
+		require.Equal(blks[round].Block, blk)
+	}
+
+	// Reindexed blocks should be annotated with round results.
+	annBlk, err := history.GetAnnotatedBlock(ctx, 60)
+	require.NoError(err, "GetAnnotatedBlock(60)")
+	require.Equal(blks[60].BatchSummary, annBlk.BatchSummary)
+
+	// Blocks already indexed during reindex should be skipped and new blocks indexed.
+	rh.publish(blks[99])
+	rh.publish(blks[100])
+	waitLastConsensusHeight(t, history, blks[100].Height)
+	blk, err := history.GetCommittedBlock(ctx, 100)
+	require.NoError(err, "GetCommittedBlock(100)")
+	require.Equal(blks[100].Block, blk)
+	require.Equal(statusIndexing, indexer.Status().Status)
+}
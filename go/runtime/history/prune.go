@@ -1,6 +1,7 @@
 package history
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -8,7 +9,10 @@ import (
 	"github.com/dgraph-io/badger/v4"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 )
 
 const (
@@ -21,6 +25,10 @@ const (
 	maxBatchSize = 64
 )
 
+// ErrRoundsRetained is the error a prune handler can return to indicate that the rounds are still
+// needed and must not be pruned yet. Pruning is then deferred without being treated as a failure.
+var ErrRoundsRetained = errors.New("runtime/history: rounds must be retained")
+
 // PrunerFactory is the runtime history pruner factory interface.
 type PrunerFactory func(runtimeID common.Namespace, db *DB) (Pruner, error)
 
@@ -30,7 +38,9 @@ type PruneHandler interface {
 	// Prune is called before the specified rounds are pruned.
 	//
 	// If an error is returned, pruning is aborted and the rounds are
-	// not pruned from history.
+	// not pruned from history. Handlers should return ErrRoundsRetained
+	// in case the rounds are still needed and pruning should be retried
+	// later.
 	//
 	// Note that this can be called for the same round multiple
 	// times (e.g., if one of the handlers fails but others succeed
@@ -84,6 +94,7 @@ type keepLastPruner struct {
 	db     *DB
 
 	numKept       uint64
+	maxAge        time.Duration
 	pruneInterval time.Duration
 
 	mu       sync.RWMutex
@@ -98,10 +109,18 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 
 	lastPrunedRound := latestRound - p.numKept
 
-	return p.db.db.Update(func(tx *badger.Txn) error {
-		// NOTE: Do not prefetch values as we are only looking at keys.
+	// Rounds newer than the maximum age are kept as well.
+	var minTimestamp uint64
+	if p.maxAge > 0 {
+		minTimestamp = uint64(max(time.Now().Add(-p.maxAge).Unix(), 0))
+	}
+
+	err := p.db.db.Update(func(tx *badger.Txn) error {
+		// NOTE: Do not prefetch values as we are only looking at keys
+		//       unless we need to check block timestamps.
 		it := tx.NewIterator(badger.IteratorOptions{
-			Prefix: blockKeyFmt.Encode(),
+			Prefix:         blockKeyFmt.Encode(),
+			PrefetchValues: p.maxAge > 0,
 		})
 		defer it.Close()
 
@@ -120,6 +139,18 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 				break
 			}
 
+			if p.maxAge > 0 {
+				var blk roothash.AnnotatedBlock
+				if err := item.Value(func(val []byte) error {
+					return cbor.UnmarshalTrusted(val, &blk)
+				}); err != nil {
+					return err
+				}
+				if blk.Block.Header.Timestamp >= block.Timestamp(minTimestamp) {
+					break
+				}
+			}
+
 			if err := tx.Delete(item.KeyCopy(nil)); err != nil {
 				if err == badger.ErrTxnTooBig {
 					// We can't prune any more rounds in this transaction.
@@ -142,7 +173,19 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 		defer p.mu.RUnlock()
 
 		for _, ph := range p.handlers {
-			if err := ph.Prune(pruned); err != nil {
+			err := ph.Prune(pruned)
+			switch {
+			case err == nil:
+				continue
+			case errors.Is(err, ErrRoundsRetained):
+				p.logger.Debug("rounds retained by prune handler, deferring prune",
+					"err", err,
+					"round_count", len(pruned),
+					"round_min", pruned[0],
+					"round_max", pruned[len(pruned)-1],
+				)
+				return err
+			default:
 				p.logger.Error("prune handler failed, aborting prune",
 					"err", err,
 					"round_count", len(pruned),
@@ -155,6 +198,11 @@ func (p *keepLastPruner) Prune(latestRound uint64) error {
 
 		return nil
 	})
+	if errors.Is(err, ErrRoundsRetained) {
+		// Rounds will be pruned once they are no longer needed.
+		return nil
+	}
+	return err
 }
 
 // PruneInterval implements Pruner.
@@ -171,20 +219,25 @@ func (p *keepLastPruner) RegisterHandler(handler PruneHandler) {
 
 // NewKeepLastPruner creates a pruner that keeps the last configured
 // number of rounds.
-func NewKeepLastPruner(runtimeID common.Namespace, numKept uint64, pruneInterval time.Duration, db *DB) (Pruner, error) {
+//
+// If the maximum age is non-zero, rounds with blocks younger than the
+// maximum age are kept as well.
+func NewKeepLastPruner(runtimeID common.Namespace, numKept uint64, maxAge time.Duration, pruneInterval time.Duration, db *DB) (Pruner, error) {
 	return &keepLastPruner{
 		logger:        logging.GetLogger("runtime/prune/keep_last").With("runtime_id", runtimeID),
 		db:            db,
 		numKept:       numKept,
+		maxAge:        maxAge,
 		pruneInterval: pruneInterval,
 		handlers:      make([]PruneHandler, 0),
 	}, nil
 }
 
 // NewKeepLastPrunerFactory creates a new pruner factory for pruners that keep
-// the last configured number of rounds.
-func NewKeepLastPrunerFactory(numKept uint64, pruneInterval time.Duration) PrunerFactory {
+// the last configured number of rounds and, optionally, rounds younger than
+// the maximum age.
+func NewKeepLastPrunerFactory(numKept uint64, maxAge time.Duration, pruneInterval time.Duration) PrunerFactory {
 	return func(runtimeID common.Namespace, db *DB) (Pruner, error) {
-		return NewKeepLastPruner(runtimeID, numKept, pruneInterval, db)
+		return NewKeepLastPruner(runtimeID, numKept, maxAge, pruneInterval, db)
 	}
 }
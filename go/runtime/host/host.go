@@ -32,6 +32,13 @@ type Config struct {
 
 	// LocalConfig is the node-local runtime configuration.
 	LocalConfig map[string]any
+
+	// DebugExecutable is an optional path to a local runtime binary that should be executed
+	// instead of the component's bundled executable. Provisioners that support it watch the
+	// binary for changes and restart the runtime whenever it changes.
+	//
+	// Provisioners must reject this option unless unsafe debug flags are set.
+	DebugExecutable string
 }
 
 // Provisioner is the runtime provisioner interface.
@@ -91,6 +98,31 @@ type CompositeRuntime interface {
 	Component(id component.ID) (Runtime, bool)
 }
 
+// ReplicatedRuntime is a runtime that is backed by multiple runtime instances.
+type ReplicatedRuntime interface {
+	// Replicas returns the status of all runtime instances.
+	Replicas() []ReplicaStatus
+}
+
+// ReplicaStatus is the status of a single runtime instance of a replicated runtime.
+type ReplicaStatus struct {
+	// Index is the index of the instance.
+	Index int `json:"index"`
+
+	// Primary is true iff the instance handles consensus-critical requests.
+	Primary bool `json:"primary,omitempty"`
+
+	// QueriesOnly is true iff the instance is dedicated to serving queries.
+	QueriesOnly bool `json:"queries_only,omitempty"`
+
+	// Healthy is true iff the instance is running and available to serve requests.
+	Healthy bool `json:"healthy"`
+
+	// ConsensusHeight is the consensus height up to which the instance's consensus view has
+	// been synced.
+	ConsensusHeight uint64 `json:"consensus_height"`
+}
+
 // RuntimeHandler is the message handler for the host side of the runtime host protocol.
 type RuntimeHandler interface {
 	protocol.Handler
@@ -1,5 +1,9 @@
 // Package loadbalance implements a runtime provisioner that internally load-balances requests among
 // multiple runtime instances. This is especially useful on client nodes handling queries.
+//
+// The first instance is the primary instance which handles all consensus-critical requests. The
+// remaining instances can either be interchangeable with the primary or they can be replicas that
+// are dedicated to serving queries.
 package loadbalance
 
 import (
@@ -19,13 +23,21 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 )
 
+// primaryInstance is the index of the primary instance.
+const primaryInstance = 0
+
 type lbHost struct {
 	id        common.Namespace
 	instances []host.Runtime
 
+	// dedicatedReplicas specifies whether the non-primary instances are replicas dedicated to
+	// serving queries.
+	dedicatedReplicas bool
+
 	l                sync.Mutex
 	nextIdx          int
 	healthyInstances map[int]struct{}
+	syncedHeights    map[int]uint64
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -35,13 +47,31 @@ type lbHost struct {
 }
 
 // NewHost creates a new load balancer runtime host.
+//
+// Queries and transaction checks are load-balanced among all of the given instances, while all
+// other requests are only sent to the first (primary) instance.
 func NewHost(id common.Namespace, instances []host.Runtime) host.Runtime {
+	return newHost(id, instances, false)
+}
+
+// NewReplicatedHost creates a new load balancer runtime host with replicas dedicated to queries.
+//
+// All requests other than queries are only sent to the primary instance. Queries are load-balanced
+// among the replicas that have synced their consensus view at least up to the height that the
+// query is based on. When no such replica is available, queries fall back to the primary.
+func NewReplicatedHost(id common.Namespace, primary host.Runtime, replicas []host.Runtime) host.Runtime {
+	return newHost(id, append([]host.Runtime{primary}, replicas...), true)
+}
+
+func newHost(id common.Namespace, instances []host.Runtime, dedicatedReplicas bool) *lbHost {
 	return &lbHost{
-		id:               id,
-		instances:        instances,
-		healthyInstances: make(map[int]struct{}),
-		stopCh:           make(chan struct{}),
-		logger:           logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
+		id:                id,
+		instances:         instances,
+		dedicatedReplicas: dedicatedReplicas,
+		healthyInstances:  make(map[int]struct{}),
+		syncedHeights:     make(map[int]uint64),
+		stopCh:            make(chan struct{}),
+		logger:            logging.GetLogger("runtime/host/loadbalance").With("runtime_id", id),
 	}
 }
 
@@ -52,18 +82,18 @@ func (h *lbHost) ID() common.Namespace {
 
 // Implements host.Runtime.
 func (h *lbHost) GetInfo(ctx context.Context) (*protocol.RuntimeInfoResponse, error) {
-	return h.instances[0].GetInfo(ctx)
+	return h.instances[primaryInstance].GetInfo(ctx)
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetActiveVersion() (*version.Version, error) {
-	return h.instances[0].GetActiveVersion()
+	return h.instances[primaryInstance].GetActiveVersion()
 }
 
 // Implements host.Runtime.
 func (h *lbHost) GetCapabilityTEE() (*node.CapabilityTEE, error) {
 	// TODO: This won't work when registration of all client runtimes is required.
-	return h.instances[0].GetCapabilityTEE()
+	return h.instances[primaryInstance].GetCapabilityTEE()
 }
 
 // shouldPropagateToAll checks whether the given runtime request should be propagated to all
@@ -87,54 +117,107 @@ func shouldPropagateToAll(body *protocol.Body) bool {
 func (h *lbHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
 	switch {
 	case shouldPropagateToAll(body):
-		// Propagate call to all instances.
-		type result struct {
-			rsp *protocol.Body
-			err error
+		return h.propagateToAll(ctx, body)
+	case body.RuntimeQueryRequest != nil:
+		// Load-balance queries.
+		if h.dedicatedReplicas {
+			idx := h.selectReplica(uint64(max(body.RuntimeQueryRequest.ConsensusBlock.Height, 0)))
+			return h.callInstance(ctx, idx, body)
 		}
-		resCh := make(chan *result)
-		for _, rt := range h.instances {
-			go func() {
-				rsp, err := rt.Call(ctx, body)
-				resCh <- &result{
-					rsp: rsp,
-					err: err,
-				}
-			}()
+
+		idx, err := h.selectInstance()
+		if err != nil {
+			return nil, err
 		}
 
-		var (
-			anyErr error
-			rsp    *protocol.Body
-		)
-		for range h.instances {
-			res := <-resCh
+		return h.callInstance(ctx, idx, body)
+	case body.RuntimeCheckTxBatchRequest != nil && !h.dedicatedReplicas:
+		// Load-balance transaction checks among interchangeable instances.
+		idx, err := h.selectInstance()
+		if err != nil {
+			return nil, err
+		}
+
+		return h.callInstance(ctx, idx, body)
+	default:
+		// Propagate only to the primary instance.
+		return h.instances[primaryInstance].Call(ctx, body)
+	}
+}
+
+func (h *lbHost) callInstance(ctx context.Context, idx int, body *protocol.Body) (*protocol.Body, error) {
+	lbRequestCount.With(prometheus.Labels{
+		"runtime":     h.id.String(),
+		"lb_instance": fmt.Sprintf("%d", idx),
+	}).Inc()
+
+	return h.instances[idx].Call(ctx, body)
+}
+
+func (h *lbHost) propagateToAll(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	type result struct {
+		idx int
+		rsp *protocol.Body
+		err error
+	}
+	resCh := make(chan *result)
+	for idx, rt := range h.instances {
+		go func() {
+			rsp, err := rt.Call(ctx, body)
+			resCh <- &result{
+				idx: idx,
+				rsp: rsp,
+				err: err,
+			}
+		}()
+	}
+
+	var (
+		anyErr error
+		rsp    *protocol.Body
+	)
+	for range h.instances {
+		res := <-resCh
+		if res.err == nil {
+			if rq := body.RuntimeConsensusSyncRequest; rq != nil {
+				h.recordSyncedHeight(res.idx, rq.Height)
+			}
+		}
+
+		switch {
+		case h.dedicatedReplicas && res.idx != primaryInstance:
+			// Failures of replicas must not affect consensus-critical operation of the primary.
+			// A replica that failed to sync simply remains pinned to its last synced height.
+			if res.err != nil {
+				h.logger.Warn("failed to propagate request to replica",
+					"err", res.err,
+					"instance", res.idx,
+				)
+			}
+		case h.dedicatedReplicas:
+			rsp = res.rsp
+			anyErr = res.err
+		default:
 			// Return the response of the instance that finished last. Note that currently all of
 			// the propagated methods return a `protocol.Empty` response so this does not matter.
 			rsp = res.rsp
 			anyErr = errors.Join(anyErr, res.err)
 		}
-		if anyErr != nil {
-			return nil, anyErr
-		}
-		return rsp, nil
-	case body.RuntimeQueryRequest != nil, body.RuntimeCheckTxBatchRequest != nil:
-		// Load-balance queries.
-		idx, err := h.selectInstance()
-		if err != nil {
-			return nil, err
-		}
+	}
+	if anyErr != nil {
+		return nil, anyErr
+	}
+	return rsp, nil
+}
 
-		lbRequestCount.With(prometheus.Labels{
-			"runtime":     h.id.String(),
-			"lb_instance": fmt.Sprintf("%d", idx),
-		}).Inc()
+func (h *lbHost) recordSyncedHeight(idx int, height uint64) {
+	h.l.Lock()
+	defer h.l.Unlock()
 
-		return h.instances[idx].Call(ctx, body)
-	default:
-		// Propagate only to the first instance.
-		return h.instances[0].Call(ctx, body)
+	if _, healthy := h.healthyInstances[idx]; !healthy {
+		return
 	}
+	h.syncedHeights[idx] = max(h.syncedHeights[idx], height)
 }
 
 func (h *lbHost) selectInstance() (int, error) {
@@ -153,6 +236,52 @@ func (h *lbHost) selectInstance() (int, error) {
 	return 0, fmt.Errorf("host/loadbalance: no healthy instances available")
 }
 
+// selectReplica selects a healthy replica whose consensus view has been synced at least up to the
+// given height. In case there is no such replica, the primary instance is selected.
+func (h *lbHost) selectReplica(height uint64) int {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	numReplicas := len(h.instances) - 1
+	for attempt := 0; attempt < numReplicas; attempt++ {
+		idx := primaryInstance + 1 + h.nextIdx
+		h.nextIdx = (h.nextIdx + 1) % numReplicas
+
+		if _, healthy := h.healthyInstances[idx]; !healthy {
+			continue
+		}
+		if h.syncedHeights[idx] < height {
+			continue
+		}
+		return idx
+	}
+
+	lbFallbackCount.With(prometheus.Labels{
+		"runtime": h.id.String(),
+	}).Inc()
+
+	return primaryInstance
+}
+
+// Implements host.ReplicatedRuntime.
+func (h *lbHost) Replicas() []host.ReplicaStatus {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	replicas := make([]host.ReplicaStatus, 0, len(h.instances))
+	for idx := range h.instances {
+		_, healthy := h.healthyInstances[idx]
+		replicas = append(replicas, host.ReplicaStatus{
+			Index:           idx,
+			Primary:         idx == primaryInstance,
+			QueriesOnly:     h.dedicatedReplicas && idx != primaryInstance,
+			Healthy:         healthy,
+			ConsensusHeight: h.syncedHeights[idx],
+		})
+	}
+	return replicas
+}
+
 // Implements host.Runtime.
 func (h *lbHost) UpdateCapabilityTEE() {
 	for _, rt := range h.instances {
@@ -162,7 +291,7 @@ func (h *lbHost) UpdateCapabilityTEE() {
 
 // Implements host.Runtime.
 func (h *lbHost) WatchEvents() (<-chan *host.Event, pubsub.ClosableSubscription) {
-	return h.instances[0].WatchEvents()
+	return h.instances[primaryInstance].WatchEvents()
 }
 
 // Implements host.Runtime.
@@ -198,6 +327,7 @@ func (h *lbHost) Start() {
 
 							h.l.Lock()
 							delete(h.healthyInstances, idx)
+							delete(h.syncedHeights, idx)
 							h.l.Unlock()
 						default:
 						}
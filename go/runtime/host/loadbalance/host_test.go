@@ -0,0 +1,259 @@
+package loadbalance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle"
+	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/mock"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+var testRuntimeID = common.NewTestNamespaceFromSeed([]byte("loadbalance test ns"), 0)
+
+// countingRuntime is a runtime host that counts calls and can be made to fail consensus syncs.
+type countingRuntime struct {
+	host.Runtime
+
+	mu        sync.Mutex
+	calls     map[string]int
+	failSyncs bool
+}
+
+func methodName(body *protocol.Body) string {
+	switch {
+	case body.RuntimeQueryRequest != nil:
+		return "RuntimeQueryRequest"
+	case body.RuntimeCheckTxBatchRequest != nil:
+		return "RuntimeCheckTxBatchRequest"
+	case body.RuntimeConsensusSyncRequest != nil:
+		return "RuntimeConsensusSyncRequest"
+	default:
+		return "other"
+	}
+}
+
+func (r *countingRuntime) Call(ctx context.Context, body *protocol.Body) (*protocol.Body, error) {
+	r.mu.Lock()
+	r.calls[methodName(body)]++
+	failSyncs := r.failSyncs
+	r.mu.Unlock()
+
+	if body.RuntimeConsensusSyncRequest != nil && failSyncs {
+		return nil, fmt.Errorf("consensus sync failed")
+	}
+	return r.Runtime.Call(ctx, body)
+}
+
+func (r *countingRuntime) numCalls(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+func (r *countingRuntime) setFailSyncs(fail bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failSyncs = fail
+}
+
+type countingProvisioner struct {
+	inner     host.Provisioner
+	instances []*countingRuntime
+}
+
+func (p *countingProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
+	rt, err := p.inner.NewRuntime(cfg)
+	if err != nil {
+		return nil, err
+	}
+	crt := &countingRuntime{Runtime: rt, calls: make(map[string]int)}
+	p.instances = append(p.instances, crt)
+	return crt, nil
+}
+
+func (p *countingProvisioner) Name() string {
+	return "counting"
+}
+
+func query(height int64) *protocol.Body {
+	return &protocol.Body{RuntimeQueryRequest: &protocol.RuntimeQueryRequest{
+		ConsensusBlock: api.LightBlock{Height: height},
+		Method:         "hello",
+	}}
+}
+
+func consensusSync(height uint64) *protocol.Body {
+	return &protocol.Body{RuntimeConsensusSyncRequest: &protocol.RuntimeConsensusSyncRequest{
+		Height: height,
+	}}
+}
+
+func TestReplicatedHost(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	const numReplicas = 2
+	cp := &countingProvisioner{inner: mock.NewProvisioner()}
+	p := NewProvisioner(cp, 0, map[common.Namespace]int{testRuntimeID: numReplicas})
+
+	// Non-RONL components should not be load-balanced.
+	cfg := host.Config{
+		ID: testRuntimeID,
+		Component: &bundle.ExplodedComponent{
+			Component: &bundle.Component{
+				Kind: component.ROFL,
+				Name: "test",
+			},
+		},
+	}
+	rofl, err := p.NewRuntime(cfg)
+	require.NoError(err, "NewRuntime(ROFL)")
+	require.NotImplements((*host.ReplicatedRuntime)(nil), rofl)
+	require.Len(cp.instances, 1)
+	cp.instances = nil
+
+	cfg.Component = &bundle.ExplodedComponent{
+		Component: &bundle.Component{
+			Kind: component.RONL,
+		},
+	}
+	rt, err := p.NewRuntime(cfg)
+	require.NoError(err, "NewRuntime(RONL)")
+	require.Len(cp.instances, 1+numReplicas)
+	primary, replicas := cp.instances[0], cp.instances[1:]
+
+	rr, ok := rt.(host.ReplicatedRuntime)
+	require.True(ok, "runtime should be replicated")
+
+	rt.Start()
+	defer rt.Stop()
+
+	allHealthy := func() bool {
+		for _, st := range rr.Replicas() {
+			if !st.Healthy {
+				return false
+			}
+		}
+		return true
+	}
+	require.Eventually(allHealthy, time.Second, 10*time.Millisecond, "all instances should become healthy")
+
+	// Before any consensus sync, queries should fall back to the primary.
+	_, err = rt.Call(ctx, query(10))
+	require.NoError(err, "Call(query)")
+	require.Equal(1, primary.numCalls("RuntimeQueryRequest"))
+
+	// Once synced, queries should only be served by replicas.
+	_, err = rt.Call(ctx, consensusSync(10))
+	require.NoError(err, "Call(consensusSync)")
+	for i := 0; i < 2*numReplicas; i++ {
+		var rsp *protocol.Body
+		rsp, err = rt.Call(ctx, query(10))
+		require.NoError(err, "Call(query)")
+		require.NotNil(rsp.RuntimeQueryResponse)
+	}
+	require.Equal(1, primary.numCalls("RuntimeQueryRequest"))
+	for _, r := range replicas {
+		require.Equal(2, r.numCalls("RuntimeQueryRequest"), "queries should be balanced among replicas")
+	}
+
+	// Queries for heights that replicas have not yet synced should go to the primary.
+	_, err = rt.Call(ctx, query(11))
+	require.NoError(err, "Call(query)")
+	require.Equal(2, primary.numCalls("RuntimeQueryRequest"))
+
+	// Consensus-critical calls should only be sent to the primary.
+	_, err = rt.Call(ctx, &protocol.Body{RuntimeCheckTxBatchRequest: &protocol.RuntimeCheckTxBatchRequest{
+		ConsensusBlock: api.LightBlock{Height: 10},
+		Inputs:         [][]byte{[]byte("tx")},
+		Block:          *block.NewGenesisBlock(testRuntimeID, 0),
+	}})
+	require.NoError(err, "Call(checkTx)")
+	require.Equal(1, primary.numCalls("RuntimeCheckTxBatchRequest"))
+	for _, r := range replicas {
+		require.Zero(r.numCalls("RuntimeCheckTxBatchRequest"))
+	}
+
+	// A replica failing to sync should not fail the primary and should stay pinned.
+	replicas[0].setFailSyncs(true)
+	_, err = rt.Call(ctx, consensusSync(12))
+	require.NoError(err, "Call(consensusSync) should succeed when a replica fails")
+
+	status := rr.Replicas()
+	require.Len(status, 1+numReplicas)
+	require.True(status[0].Primary)
+	require.False(status[0].QueriesOnly)
+	require.EqualValues(12, status[0].ConsensusHeight)
+	require.True(status[1].QueriesOnly)
+	require.EqualValues(10, status[1].ConsensusHeight, "failed replica should remain pinned")
+	require.EqualValues(12, status[2].ConsensusHeight)
+
+	for i := 0; i < 3; i++ {
+		_, err = rt.Call(ctx, query(12))
+		require.NoError(err, "Call(query)")
+	}
+	require.Equal(2, replicas[0].numCalls("RuntimeQueryRequest"), "stale replica should not serve queries")
+	require.Equal(5, replicas[1].numCalls("RuntimeQueryRequest"))
+
+	// A failing primary should fail the consensus sync.
+	primary.setFailSyncs(true)
+	_, err = rt.Call(ctx, consensusSync(13))
+	require.Error(err, "Call(consensusSync) should fail when the primary fails")
+
+	// Stopped instances should be reported as unhealthy.
+	replicas[1].Stop()
+	require.Eventually(func() bool {
+		return !rr.Replicas()[2].Healthy
+	}, time.Second, 10*time.Millisecond, "stopped replica should become unhealthy")
+	require.Zero(rr.Replicas()[2].ConsensusHeight)
+}
+
+func TestLegacyHost(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	cp := &countingProvisioner{inner: mock.NewProvisioner()}
+	require.Equal(cp, NewProvisioner(cp, 1, nil), "single instance should not be load-balanced")
+
+	p := NewProvisioner(cp, 2, nil)
+	rt, err := p.NewRuntime(host.Config{
+		ID: testRuntimeID,
+		Component: &bundle.ExplodedComponent{
+			Component: &bundle.Component{
+				Kind: component.RONL,
+			},
+		},
+	})
+	require.NoError(err, "NewRuntime")
+	require.Len(cp.instances, 2)
+
+	rt.Start()
+	defer rt.Stop()
+
+	rr := rt.(host.ReplicatedRuntime)
+	require.Eventually(func() bool {
+		st := rr.Replicas()
+		return st[0].Healthy && st[1].Healthy
+	}, time.Second, 10*time.Millisecond, "all instances should become healthy")
+	require.False(rr.Replicas()[1].QueriesOnly)
+
+	// Queries are balanced among all instances regardless of consensus sync.
+	for i := 0; i < 4; i++ {
+		_, err = rt.Call(ctx, query(10))
+		require.NoError(err, "Call(query)")
+	}
+	for _, r := range cp.instances {
+		require.Equal(2, r.numCalls("RuntimeQueryRequest"))
+	}
+}
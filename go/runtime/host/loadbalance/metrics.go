@@ -23,9 +23,17 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	lbFallbackCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_client_lb_fallback_requests",
+			Help: "Number of queries sent to the primary instance as no replica was available.",
+		},
+		[]string{"runtime"},
+	)
 	nodeCollectors = []prometheus.Collector{
 		lbRequestCount,
 		lbHealthyInstanceCount,
+		lbFallbackCount,
 	}
 
 	metricsOnce sync.Once
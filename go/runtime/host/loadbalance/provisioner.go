@@ -3,18 +3,24 @@ package loadbalance
 import (
 	"fmt"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 )
 
 type lbProvisioner struct {
-	inner        host.Provisioner
-	numInstances int
+	inner         host.Provisioner
+	numInstances  int
+	queryReplicas map[common.Namespace]int
 }
 
 // NewProvisioner creates a load-balancing runtime provisioner.
-func NewProvisioner(inner host.Provisioner, numInstances int) host.Provisioner {
-	if numInstances < 2 {
+//
+// The numInstances argument is the number of interchangeable instances provisioned for each
+// runtime, while queryReplicas maps runtime identifiers to the number of additional instances
+// dedicated to serving queries for that runtime. Query replicas take precedence when configured.
+func NewProvisioner(inner host.Provisioner, numInstances int, queryReplicas map[common.Namespace]int) host.Provisioner {
+	if numInstances < 2 && len(queryReplicas) == 0 {
 		// If there is only a single instance configured just return the inner provisioner.
 		return inner
 	}
@@ -22,26 +28,31 @@ func NewProvisioner(inner host.Provisioner, numInstances int) host.Provisioner {
 	initMetrics()
 
 	return &lbProvisioner{
-		inner:        inner,
-		numInstances: numInstances,
+		inner:         inner,
+		numInstances:  numInstances,
+		queryReplicas: queryReplicas,
 	}
 }
 
 // Implements host.Provisioner.
 func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
-	if p.numInstances < 2 {
-		// This should never happen as the provisioner constructor made sure, but just to be safe.
-		return nil, fmt.Errorf("host/loadbalance: number of instances must be at least two")
-	}
-
 	// The load-balancer can only be used for the RONL component. For others, do pass-through.
 	if cfg.Component.ID() != component.ID_RONL {
 		return p.inner.NewRuntime(cfg)
 	}
 
+	numReplicas := p.queryReplicas[cfg.ID]
+	numInstances := p.numInstances
+	if numReplicas > 0 {
+		numInstances = 1 + numReplicas
+	}
+	if numInstances < 2 {
+		return p.inner.NewRuntime(cfg)
+	}
+
 	// Use the inner provisioner to provision multiple runtimes.
 	var instances []host.Runtime
-	for i := 0; i < p.numInstances; i++ {
+	for i := 0; i < numInstances; i++ {
 		rt, err := p.inner.NewRuntime(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("host/loadbalance: failed to provision instance %d: %w", i, err)
@@ -50,9 +61,10 @@ func (p *lbProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 		instances = append(instances, rt)
 	}
 
-	host := NewHost(cfg.ID, instances)
-
-	return host, nil
+	if numReplicas > 0 {
+		return NewReplicatedHost(cfg.ID, instances[0], instances[1:]), nil
+	}
+	return NewHost(cfg.ID, instances), nil
 }
 
 // Implements host.Provisioner.
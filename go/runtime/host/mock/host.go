@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -23,6 +25,36 @@ import (
 // CheckTxFailInput is the input that will cause a CheckTx failure in the mock runtime.
 var CheckTxFailInput = []byte("checktx-mock-fail")
 
+// SlowTxInput is the input that the mock runtime reports as taking SlowTxExecutionTime to execute.
+var SlowTxInput = []byte("exectx-mock-slow")
+
+// SlowTxExecutionTime is the execution time reported by the mock runtime for SlowTxInput.
+const SlowTxExecutionTime = 5 * time.Second
+
+// ExpiringTxInputPrefix is the prefix of inputs that the mock runtime reports as expiring. See
+// NewExpiringTxInput.
+var ExpiringTxInputPrefix = []byte("checktx-mock-expiry:")
+
+// NewExpiringTxInput returns an input that the mock runtime reports as expiring at the given round.
+// The tag can be used to make inputs unique.
+func NewExpiringTxInput(expiryRound uint64, tag string) []byte {
+	return fmt.Appendf(bytes.Clone(ExpiringTxInputPrefix), "%d:%s", expiryRound, tag)
+}
+
+// parseExpiringTxInput returns the expiry round of an input created by NewExpiringTxInput.
+func parseExpiringTxInput(input []byte) (uint64, bool) {
+	rest, ok := bytes.CutPrefix(input, ExpiringTxInputPrefix)
+	if !ok {
+		return 0, false
+	}
+	round, _, _ := bytes.Cut(rest, []byte(":"))
+	expiryRound, err := strconv.ParseUint(string(round), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return expiryRound, true
+}
+
 type mockHost struct {
 	runtimeID common.Namespace
 
@@ -43,6 +75,9 @@ func (h *mockHost) GetInfo(context.Context) (*protocol.RuntimeInfoResponse, erro
 			ScheduleControl: &protocol.FeatureScheduleControl{
 				InitialBatchSize: 100,
 			},
+			BatchSummary:     true,
+			TxExecutionTimes: true,
+			LocalStorage:     true,
 		},
 	}, nil
 }
@@ -114,6 +149,29 @@ func (h *mockHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Bod
 		msgsHash.Empty()
 		inMsgsHash.Empty()
 
+		// Summarize the batch, charging one unit of gas per transaction byte.
+		var batchSummary *commitment.BatchSummary
+		if rq.BatchSummary {
+			batchSummary = &commitment.BatchSummary{
+				TxCount: uint32(len(rq.Inputs)),
+			}
+			for _, tx := range rq.Inputs {
+				batchSummary.BatchSize += uint64(len(tx))
+			}
+			batchSummary.GasUsed = batchSummary.BatchSize
+		}
+
+		// Report execution times, charging one microsecond per transaction byte.
+		txExecutionTimes := make([]time.Duration, 0, len(rq.Inputs))
+		for _, tx := range rq.Inputs {
+			switch {
+			case bytes.Equal(tx, SlowTxInput):
+				txExecutionTimes = append(txExecutionTimes, SlowTxExecutionTime)
+			default:
+				txExecutionTimes = append(txExecutionTimes, time.Duration(len(tx))*time.Microsecond)
+			}
+		}
+
 		return &protocol.Body{RuntimeExecuteTxBatchResponse: &protocol.RuntimeExecuteTxBatchResponse{
 			Batch: protocol.ComputedBatch{
 				Header: commitment.ComputeResultsHeader{
@@ -123,12 +181,14 @@ func (h *mockHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Bod
 					StateRoot:      &stateRoot,
 					MessagesHash:   &msgsHash,
 					InMessagesHash: &inMsgsHash,
+					BatchSummary:   batchSummary,
 				},
 				IOWriteLog: ioWriteLog,
 			},
-			TxHashes:        txHashes,
-			TxInputRoot:     txInputRoot,
-			TxInputWriteLog: txInputWriteLog,
+			TxHashes:         txHashes,
+			TxInputRoot:      txInputRoot,
+			TxInputWriteLog:  txInputWriteLog,
+			TxExecutionTimes: txExecutionTimes,
 			// No RakSig in mock response.
 		}}, nil
 	case body.RuntimeCheckTxBatchRequest != nil:
@@ -136,7 +196,18 @@ func (h *mockHost) Call(ctx context.Context, body *protocol.Body) (*protocol.Bod
 
 		var results []protocol.CheckTxResult
 		for _, input := range rq.Inputs {
+			expiryRound, expiring := parseExpiringTxInput(input)
+
 			switch {
+			case expiring:
+				results = append(results, protocol.CheckTxResult{
+					Error: protocol.Error{
+						Code: errors.CodeNoError,
+					},
+					Meta: &protocol.CheckTxMetadata{
+						ExpiryRound: expiryRound,
+					},
+				})
 			case bytes.Equal(input, CheckTxFailInput):
 				results = append(results, protocol.CheckTxResult{
 					Error: protocol.Error{
@@ -0,0 +1,66 @@
+package mock
+
+import (
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/localstorage"
+)
+
+type mockLocalStorage struct {
+	sync.Mutex
+
+	quota  uint64
+	size   uint64
+	values map[string][]byte
+}
+
+// Implements localstorage.LocalStorage.
+func (s *mockLocalStorage) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, localstorage.ErrInvalidKey
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	value, ok := s.values[string(key)]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte{}, value...), nil
+}
+
+// Implements localstorage.LocalStorage.
+func (s *mockLocalStorage) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return localstorage.ErrInvalidKey
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	size := s.size
+	if old, ok := s.values[string(key)]; ok {
+		size -= uint64(len(key)) + uint64(len(old))
+	}
+	size += uint64(len(key)) + uint64(len(value))
+	if size > s.quota {
+		return localstorage.ErrQuotaExceeded
+	}
+
+	s.values[string(key)] = append([]byte{}, value...)
+	s.size = size
+	return nil
+}
+
+// Implements localstorage.LocalStorage.
+func (s *mockLocalStorage) Stop() {
+}
+
+// NewLocalStorage creates a new in-memory local storage with the given quota, useful for tests.
+func NewLocalStorage(quota uint64) localstorage.LocalStorage {
+	return &mockLocalStorage{
+		quota:  quota,
+		values: make(map[string][]byte),
+	}
+}
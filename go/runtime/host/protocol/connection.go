@@ -26,11 +26,18 @@ const (
 	// connReadyTimeout is the timeout while waiting for the connection to be ready while attempting
 	// to handle a new request from the runtime.
 	connReadyTimeout = 5 * time.Second
+
+	// DefaultShutdownDrainTimeout is the default maximum amount of time the connection keeps
+	// servicing in-flight requests after a shutdown has been initiated.
+	DefaultShutdownDrainTimeout = 5 * time.Second
 )
 
 var (
 	// ErrNotReady is the error reported when the Runtime Host Protocol is not initialized.
 	ErrNotReady = errors.New(moduleName, 1, "rhp: not ready")
+	// ErrFeatureNotDeclared is the error reported when the runtime makes a request that requires
+	// a feature which it didn't declare.
+	ErrFeatureNotDeclared = errors.New(moduleName, 2, "rhp: feature not declared")
 
 	rhpLatency = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
@@ -99,9 +106,17 @@ func (n *NoOpNotifier) Stop() {
 
 // Connection is a Runtime Host Protocol connection interface.
 type Connection interface {
-	// Close closes the connection.
+	// Close initiates a graceful shutdown of the connection.
+	//
+	// New calls are rejected immediately while calls and requests from the other side that are
+	// already in progress are serviced for at most the shutdown drain timeout before the
+	// connection is closed. Use WaitClosed to wait for the shutdown to complete.
 	Close()
 
+	// WaitClosed waits for the connection to be closed and for all connection-handling goroutines
+	// to terminate.
+	WaitClosed()
+
 	// GetInfo retrieves the runtime information.
 	GetInfo() (*RuntimeInfoResponse, error)
 
@@ -140,6 +155,18 @@ type HostInfo struct {
 	// This configuration must not be used in any context which requires determinism across
 	// replicated runtime instances.
 	LocalConfig map[string]any
+
+	// MaxConcurrentRequests is the maximum number of concurrent non-critical requests sent to
+	// the runtime. If zero, DefaultMaxConcurrentRequests is used.
+	//
+	// This value is not sent to the runtime.
+	MaxConcurrentRequests uint16
+
+	// ShutdownDrainTimeout is the maximum amount of time in-flight requests are serviced after
+	// the connection shutdown has been initiated. If zero, DefaultShutdownDrainTimeout is used.
+	//
+	// This value is not sent to the runtime.
+	ShutdownDrainTimeout time.Duration
 }
 
 // Clone returns a copy of the HostInfo structure.
@@ -157,6 +184,8 @@ func (hi *HostInfo) Clone() *HostInfo {
 		ConsensusProtocolVersion: hi.ConsensusProtocolVersion,
 		ConsensusChainContext:    hi.ConsensusChainContext,
 		LocalConfig:              localConfig,
+		MaxConcurrentRequests:    hi.MaxConcurrentRequests,
+		ShutdownDrainTimeout:     hi.ShutdownDrainTimeout,
 	}
 }
 
@@ -167,6 +196,7 @@ const (
 	stateUninitialized state = iota
 	stateInitializing
 	stateReady
+	stateClosing
 	stateClosed
 )
 
@@ -178,6 +208,8 @@ func (s state) String() string {
 		return "initializing"
 	case stateReady:
 		return "ready"
+	case stateClosing:
+		return "closing"
 	case stateClosed:
 		return "closed"
 	default:
@@ -189,12 +221,16 @@ func (s state) String() string {
 var validStateTransitions = map[state][]state{
 	stateUninitialized: {
 		stateInitializing,
+		stateClosed,
 	},
 	stateInitializing: {
 		stateReady,
 		stateClosed,
 	},
 	stateReady: {
+		stateClosing,
+	},
+	stateClosing: {
 		stateClosed,
 	},
 	// No transitions from Closed state.
@@ -216,19 +252,24 @@ type connection struct { // nolint: maligned
 
 	info *RuntimeInfoResponse
 
-	readyCh chan struct{}
-	outCh   chan *Message
-	closeCh chan struct{}
-	quitWg  sync.WaitGroup
+	// limiter bounds the number of concurrent non-critical requests. Only set for host
+	// connections.
+	limiter *requestLimiter
 
-	logger *logging.Logger
-}
+	// drainTimeout is the maximum amount of time in-flight requests are serviced during shutdown.
+	drainTimeout time.Duration
+	// outgoing tracks calls to the other side that are in progress.
+	outgoing activityTracker
+	// incoming tracks requests from the other side that are being handled.
+	incoming activityTracker
 
-func (c *connection) getState() state {
-	c.RLock()
-	s := c.state
-	c.RUnlock()
-	return s
+	readyCh  chan struct{}
+	outCh    [numPriorities]chan *Message
+	closeCh  chan struct{}
+	closedCh chan struct{}
+	quitWg   sync.WaitGroup
+
+	logger *logging.Logger
 }
 
 // waitReady waits for the connection to become ready for at most connReadyTimeout.
@@ -266,14 +307,70 @@ func (c *connection) setStateLocked(s state) {
 // Implements Connection.
 func (c *connection) Close() {
 	c.Lock()
-	if c.state != stateReady && c.state != stateInitializing {
-		c.Unlock()
-		return
+	defer c.Unlock()
+
+	switch c.state {
+	case stateUninitialized:
+		// No connection-handling goroutines have been started.
+		c.setStateLocked(stateClosed)
+		close(c.closedCh)
+	case stateInitializing:
+		// Nothing to drain as no calls are allowed yet.
+		c.setStateLocked(stateClosed)
+		go c.close()
+	case stateReady:
+		c.setStateLocked(stateClosing)
+		go c.shutdown()
+	default:
+	}
+}
+
+// Implements Connection.
+func (c *connection) WaitClosed() {
+	<-c.closedCh
+}
+
+// shutdown performs a graceful shutdown of the connection.
+func (c *connection) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.drainTimeout)
+	defer cancel()
+
+	// Wait for our in-flight calls to complete.
+	select {
+	case <-c.outgoing.idle():
+	case <-c.closeCh:
+	case <-ctx.Done():
+		c.logger.Warn("timed out while waiting for in-flight calls to complete")
+	}
+
+	// Notify the other side that the connection is being shut down so that it stops issuing new
+	// requests. Older runtimes do not support the notification, so any errors are ignored.
+	if _, err := c.call(ctx, &Body{RuntimeShutdownRequest: &Empty{}}); err != nil {
+		c.logger.Debug("shutdown notification failed",
+			"err", err,
+		)
 	}
 
+	// Keep servicing requests from the other side until they have been handled.
+	select {
+	case <-c.incoming.idle():
+	case <-c.closeCh:
+	case <-ctx.Done():
+		c.logger.Warn("timed out while waiting for in-flight requests to be handled")
+	}
+
+	c.Lock()
 	c.setStateLocked(stateClosed)
 	c.Unlock()
 
+	c.close()
+}
+
+// close closes the underlying connection and waits for all the connection-handling goroutines to
+// terminate.
+func (c *connection) close() {
+	defer close(c.closedCh)
+
 	if err := c.conn.Close(); err != nil {
 		c.logger.Error("error while closing connection",
 			"err", err,
@@ -297,9 +394,14 @@ func (c *connection) GetInfo() (*RuntimeInfoResponse, error) {
 
 // Implements Connection.
 func (c *connection) Call(ctx context.Context, body *Body) (*Body, error) {
-	if c.getState() != stateReady {
+	c.RLock()
+	if c.state != stateReady {
+		c.RUnlock()
 		return nil, ErrNotReady
 	}
+	c.outgoing.start()
+	c.RUnlock()
+	defer c.outgoing.done()
 
 	b, err := c.call(ctx, body)
 	return b, err
@@ -344,8 +446,18 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 		Body:        *body,
 	}
 
+	// Bound the number of concurrent non-critical requests as the runtime processes all requests
+	// received over the connection in order.
+	priority := body.Priority()
+	if c.limiter != nil && priority != PriorityCritical {
+		if err = c.limiter.acquire(ctx, priority); err != nil {
+			return nil, err
+		}
+		defer c.limiter.release()
+	}
+
 	// Queue the message.
-	if err = c.sendMessage(ctx, &msg); err != nil {
+	if err = c.sendMessage(ctx, &msg, priority); err != nil {
 		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
@@ -358,9 +470,9 @@ func (c *connection) call(ctx context.Context, body *Body) (result *Body, err er
 	return resp, nil
 }
 
-func (c *connection) sendMessage(ctx context.Context, msg *Message) error {
+func (c *connection) sendMessage(ctx context.Context, msg *Message, priority Priority) error {
 	select {
-	case c.outCh <- msg:
+	case c.outCh[priority] <- msg:
 		return nil
 	case <-c.closeCh:
 		return fmt.Errorf("connection closed")
@@ -385,30 +497,53 @@ func (c *connection) readResponse(ctx context.Context, respCh <-chan *Body) (*Bo
 	}
 }
 
+// nextOutgoing returns the next queued outgoing message with the highest priority, waiting for
+// one if none is queued. It returns nil in case the connection has terminated.
+func (c *connection) nextOutgoing() *Message {
+	for p := numPriorities - 1; p >= 0; p-- {
+		select {
+		case msg := <-c.outCh[p]:
+			return msg
+		default:
+		}
+	}
+
+	select {
+	case msg := <-c.outCh[PriorityCritical]:
+		return msg
+	case msg := <-c.outCh[PriorityNormal]:
+		return msg
+	case msg := <-c.outCh[PriorityBackground]:
+		return msg
+	case <-c.closeCh:
+		return nil
+	}
+}
+
 func (c *connection) workerOutgoing() {
 	for {
-		select {
-		case msg := <-c.outCh:
-			if err := c.conn.SetWriteDeadline(time.Now().Add(connWriteTimeout)); err != nil {
-				c.logger.Error("error setting connection deadline",
-					"err", err,
-				)
-			}
-			// Outgoing message, send it.
-			if err := c.codec.Write(msg); err != nil {
-				c.logger.Error("error while sending message",
-					"err", err,
-				)
-			}
-			if err := c.conn.SetWriteDeadline(time.Time{}); err != nil {
-				c.logger.Error("error setting connection deadline",
-					"err", err,
-				)
-			}
-		case <-c.closeCh:
+		msg := c.nextOutgoing()
+		if msg == nil {
 			// Connection has terminated.
 			return
 		}
+
+		if err := c.conn.SetWriteDeadline(time.Now().Add(connWriteTimeout)); err != nil {
+			c.logger.Error("error setting connection deadline",
+				"err", err,
+			)
+		}
+		// Outgoing message, send it.
+		if err := c.codec.Write(msg); err != nil {
+			c.logger.Error("error while sending message",
+				"err", err,
+			)
+		}
+		if err := c.conn.SetWriteDeadline(time.Time{}); err != nil {
+			c.logger.Error("error setting connection deadline",
+				"err", err,
+			)
+		}
 	}
 }
 
@@ -435,19 +570,27 @@ func (c *connection) handleMessage(ctx context.Context, message *Message) {
 	switch message.MessageType {
 	case MessageRequest:
 		// Incoming request.
+		c.incoming.start()
+		defer c.incoming.done()
+
 		if err := c.waitReady(ctx); err != nil {
-			_ = c.sendMessage(ctx, newResponseMessage(message, errorToBody(ErrNotReady)))
+			_ = c.sendMessage(ctx, newResponseMessage(message, errorToBody(ErrNotReady)), PriorityCritical)
 			return
 		}
 
 		// Call actual handler.
-		body, err := c.handler.Handle(ctx, &message.Body)
+		var body *Body
+		err := c.checkFeatures(&message.Body)
+		if err == nil {
+			body, err = c.handler.Handle(ctx, &message.Body)
+		}
 		if err != nil {
 			body = errorToBody(err)
 		}
 
 		// Prepare and send response.
-		if err := c.sendMessage(ctx, newResponseMessage(message, body)); err != nil {
+		// Responses are always critical as the other side may be blocked on them.
+		if err := c.sendMessage(ctx, newResponseMessage(message, body), PriorityCritical); err != nil {
 			c.logger.Warn("failed to send response message",
 				"err", err,
 			)
@@ -474,6 +617,26 @@ func (c *connection) handleMessage(ctx context.Context, message *Message) {
 	}
 }
 
+// checkFeatures checks whether the runtime declared the features required by the given request.
+func (c *connection) checkFeatures(body *Body) error {
+	c.RLock()
+	info := c.info
+	c.RUnlock()
+
+	if info == nil {
+		// Guest connections don't know the runtime's features.
+		return nil
+	}
+
+	switch {
+	case body.HostLocalStorageGetRequest != nil, body.HostLocalStorageSetRequest != nil:
+		if !info.Features.LocalStorage {
+			return ErrFeatureNotDeclared
+		}
+	}
+	return nil
+}
+
 func (c *connection) workerIncoming() {
 	// Wait for request handlers to finish.
 	var wg sync.WaitGroup
@@ -555,6 +718,15 @@ func (c *connection) InitGuest(conn net.Conn) error {
 
 // Implements Connection.
 func (c *connection) InitHost(ctx context.Context, conn net.Conn, hi *HostInfo) (*version.Version, error) {
+	maxConcurrentRequests := int(hi.MaxConcurrentRequests)
+	if maxConcurrentRequests == 0 {
+		maxConcurrentRequests = DefaultMaxConcurrentRequests
+	}
+	c.limiter = newRequestLimiter(maxConcurrentRequests)
+	if hi.ShutdownDrainTimeout > 0 {
+		c.drainTimeout = hi.ShutdownDrainTimeout
+	}
+
 	c.initConn(conn)
 
 	// Check Runtime Host Protocol version.
@@ -613,11 +785,56 @@ func NewConnection(logger *logging.Logger, runtimeID common.Namespace, handler H
 		handler:         handler,
 		state:           stateUninitialized,
 		pendingRequests: make(map[uint64]chan<- *Body),
+		drainTimeout:    DefaultShutdownDrainTimeout,
 		readyCh:         make(chan struct{}),
-		outCh:           make(chan *Message),
 		closeCh:         make(chan struct{}),
+		closedCh:        make(chan struct{}),
 		logger:          logger,
 	}
+	for p := range c.outCh {
+		c.outCh[p] = make(chan *Message)
+	}
 
 	return c, nil
 }
+
+// activityTracker tracks the number of operations in progress.
+type activityTracker struct {
+	sync.Mutex
+
+	active int
+	idleCh chan struct{}
+}
+
+func (a *activityTracker) start() {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.active == 0 {
+		a.idleCh = make(chan struct{})
+	}
+	a.active++
+}
+
+func (a *activityTracker) done() {
+	a.Lock()
+	defer a.Unlock()
+
+	a.active--
+	if a.active == 0 {
+		close(a.idleCh)
+	}
+}
+
+// idle returns a channel that is closed once there are no operations in progress.
+func (a *activityTracker) idle() <-chan struct{} {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.active == 0 {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	return a.idleCh
+}
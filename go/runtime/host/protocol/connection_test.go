@@ -3,7 +3,11 @@ package protocol
 import (
 	"context"
 	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -128,3 +132,332 @@ func TestBigMessage(t *testing.T) {
 	require.EqualValues(0, handlerA.calls, "Handler A must not be called")
 	require.EqualValues(1, handlerB.calls, "Handler B must be called")
 }
+
+// serialHandler processes requests one at a time in order of arrival, similar to how the runtime
+// processes requests received over the connection.
+type serialHandler struct {
+	queryDuration time.Duration
+	queue         chan func()
+}
+
+// Implements Handler.
+func (h *serialHandler) Handle(_ context.Context, body *Body) (*Body, error) {
+	if body.RuntimeInfoRequest != nil {
+		return &Body{
+			RuntimeInfoResponse: &RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeHostProtocol,
+			},
+		}, nil
+	}
+
+	done := make(chan struct{})
+	h.queue <- func() {
+		defer close(done)
+		if body.RuntimeQueryRequest != nil {
+			time.Sleep(h.queryDuration)
+		}
+	}
+	<-done
+	return body, nil
+}
+
+func TestRequestPriority(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	const (
+		maxConcurrentRequests = 2
+		numQueries            = 30
+		queryDuration         = 50 * time.Millisecond
+	)
+
+	connA, connB := net.Pipe()
+	// The guest processes requests one at a time.
+	handlerA := &serialHandler{
+		queryDuration: queryDuration,
+		queue:         make(chan func(), 128),
+	}
+	go func() {
+		for fn := range handlerA.queue {
+			fn()
+		}
+	}()
+	protoA, err := NewConnection(logger, runtimeID, handlerA)
+	require.NoError(err, "A.New()")
+	protoB, err := NewConnection(logger, runtimeID, &testHandler{})
+	require.NoError(err, "B.New()")
+	defer protoA.Close()
+	defer protoB.Close()
+
+	err = protoA.InitGuest(connA)
+	require.NoError(err, "A.InitGuest()")
+	_, err = protoB.InitHost(context.Background(), connB, &HostInfo{MaxConcurrentRequests: maxConcurrentRequests})
+	require.NoError(err, "B.InitHost()")
+
+	// Flood the runtime with queries.
+	var wg sync.WaitGroup
+	for range numQueries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, qerr := protoB.Call(context.Background(), &Body{RuntimeQueryRequest: &RuntimeQueryRequest{}})
+			require.NoError(qerr, "query")
+		}()
+	}
+	defer wg.Wait()
+
+	// Wait for the flood to saturate the connection.
+	time.Sleep(2 * queryDuration)
+
+	// Batch execution should only need to wait for the queries that are already being processed.
+	start := time.Now()
+	_, err = protoB.Call(context.Background(), &Body{RuntimeExecuteTxBatchRequest: &RuntimeExecuteTxBatchRequest{}})
+	require.NoError(err, "execute")
+	require.Less(time.Since(start), (maxConcurrentRequests+2)*queryDuration, "execute should not be delayed by queries")
+}
+
+func TestRequestLimiter(t *testing.T) {
+	require := require.New(t)
+
+	l := newRequestLimiter(1)
+	require.NoError(l.acquire(context.Background(), PriorityNormal))
+
+	// Waiters are admitted in order of priority.
+	admitted := make(chan Priority, 2)
+	for _, p := range []Priority{PriorityBackground, PriorityNormal} {
+		go func() {
+			_ = l.acquire(context.Background(), p)
+			admitted <- p
+		}()
+	}
+
+	// A canceled waiter must not consume a slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(l.acquire(ctx, PriorityNormal), context.DeadlineExceeded)
+
+	l.release()
+	require.Equal(PriorityNormal, <-admitted)
+	l.release()
+	require.Equal(PriorityBackground, <-admitted)
+	l.release()
+
+	require.NoError(l.acquire(context.Background(), PriorityBackground))
+}
+
+// shutdownHandler handles requests after a delay, or until canceled if the delay is negative.
+type shutdownHandler struct {
+	delay time.Duration
+
+	started  chan struct{}
+	shutdown atomic.Bool
+}
+
+// Implements Handler.
+func (h *shutdownHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	switch {
+	case body.RuntimeInfoRequest != nil:
+		return &Body{
+			RuntimeInfoResponse: &RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeHostProtocol,
+			},
+		}, nil
+	case body.RuntimeShutdownRequest != nil:
+		h.shutdown.Store(true)
+		return &Body{Empty: &Empty{}}, nil
+	}
+
+	h.started <- struct{}{}
+	if h.delay < 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	time.Sleep(h.delay)
+	return body, nil
+}
+
+func requireNoGoroutineLeak(t *testing.T, baseline int) {
+	// Note that require.Eventually cannot be used as it spawns goroutines itself.
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), baseline, "all connection goroutines should terminate")
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	const (
+		numRequests  = 10
+		requestDelay = 100 * time.Millisecond
+	)
+
+	baseline := runtime.NumGoroutine()
+
+	connGuest, connHost := net.Pipe()
+	guestHandler := &shutdownHandler{delay: requestDelay, started: make(chan struct{}, 128)}
+	guest, err := NewConnection(logger, runtimeID, guestHandler)
+	require.NoError(err, "guest.New()")
+	hostHandler := &shutdownHandler{delay: requestDelay, started: make(chan struct{}, 128)}
+	host, err := NewConnection(logger, runtimeID, hostHandler)
+	require.NoError(err, "host.New()")
+
+	err = guest.InitGuest(connGuest)
+	require.NoError(err, "guest.InitGuest()")
+	_, err = host.InitHost(context.Background(), connHost, &HostInfo{})
+	require.NoError(err, "host.InitHost()")
+
+	// Issue requests in both directions.
+	var wg sync.WaitGroup
+	errCh := make(chan error, 2*numRequests)
+	for range numRequests {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, cerr := guest.Call(context.Background(), &Body{HostStorageSyncRequest: &HostStorageSyncRequest{}})
+			errCh <- cerr
+		}()
+		go func() {
+			defer wg.Done()
+			_, cerr := host.Call(context.Background(), &Body{RuntimeQueryRequest: &RuntimeQueryRequest{}})
+			errCh <- cerr
+		}()
+	}
+	for range numRequests {
+		<-guestHandler.started
+		<-hostHandler.started
+	}
+
+	// Shut down the host side while requests are in flight.
+	host.Close()
+	_, err = host.Call(context.Background(), &Body{RuntimeQueryRequest: &RuntimeQueryRequest{}})
+	require.ErrorIs(err, ErrNotReady, "new calls should be rejected during shutdown")
+
+	host.WaitClosed()
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		require.NoError(err, "requests issued before shutdown should succeed")
+	}
+	require.True(guestHandler.shutdown.Load(), "runtime should be notified about the shutdown")
+
+	guest.Close()
+	guest.WaitClosed()
+
+	requireNoGoroutineLeak(t, baseline)
+}
+
+func TestShutdownDrainTimeout(t *testing.T) {
+	require := require.New(t)
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	const drainTimeout = 100 * time.Millisecond
+
+	baseline := runtime.NumGoroutine()
+
+	connGuest, connHost := net.Pipe()
+	guest, err := NewConnection(logger, runtimeID, &shutdownHandler{delay: 0, started: make(chan struct{}, 128)})
+	require.NoError(err, "guest.New()")
+	hostHandler := &shutdownHandler{delay: -1, started: make(chan struct{}, 128)}
+	host, err := NewConnection(logger, runtimeID, hostHandler)
+	require.NoError(err, "host.New()")
+
+	err = guest.InitGuest(connGuest)
+	require.NoError(err, "guest.InitGuest()")
+	_, err = host.InitHost(context.Background(), connHost, &HostInfo{ShutdownDrainTimeout: drainTimeout})
+	require.NoError(err, "host.InitHost()")
+
+	// Issue a request that is never handled.
+	errCh := make(chan error, 1)
+	go func() {
+		_, cerr := guest.Call(context.Background(), &Body{HostStorageSyncRequest: &HostStorageSyncRequest{}})
+		errCh <- cerr
+	}()
+	<-hostHandler.started
+
+	// Shutdown should complete once the drain timeout expires.
+	start := time.Now()
+	host.Close()
+	host.WaitClosed()
+	require.Less(time.Since(start), 10*drainTimeout, "shutdown should not wait for stuck requests")
+	require.Error(<-errCh, "stuck request should fail")
+
+	guest.Close()
+	guest.WaitClosed()
+
+	requireNoGoroutineLeak(t, baseline)
+}
+
+type featuresHandler struct {
+	testHandler
+
+	features Features
+}
+
+// Implements Handler.
+func (h *featuresHandler) Handle(ctx context.Context, body *Body) (*Body, error) {
+	if body.RuntimeInfoRequest != nil {
+		return &Body{
+			RuntimeInfoResponse: &RuntimeInfoResponse{
+				ProtocolVersion: version.RuntimeHostProtocol,
+				Features:        h.features,
+			},
+		}, nil
+	}
+	return h.testHandler.Handle(ctx, body)
+}
+
+func TestFeatureGating(t *testing.T) {
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("test conn"), 0)
+	logger := logging.GetLogger("test")
+
+	for _, tc := range []struct {
+		name     string
+		features Features
+		allowed  bool
+	}{
+		{"Declared", Features{LocalStorage: true}, true},
+		{"NotDeclared", Features{}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			connA, connB := net.Pipe()
+			guestHandler := &featuresHandler{features: tc.features}
+			guest, err := NewConnection(logger, runtimeID, guestHandler)
+			require.NoError(err, "NewConnection")
+			defer guest.Close()
+			hostHandler := &testHandler{}
+			host, err := NewConnection(logger, runtimeID, hostHandler)
+			require.NoError(err, "NewConnection")
+			defer host.Close()
+
+			require.NoError(guest.InitGuest(connA), "InitGuest")
+			_, err = host.InitHost(context.Background(), connB, &HostInfo{})
+			require.NoError(err, "InitHost")
+
+			for _, body := range []*Body{
+				{HostLocalStorageGetRequest: &HostLocalStorageGetRequest{Key: []byte("key")}},
+				{HostLocalStorageSetRequest: &HostLocalStorageSetRequest{Key: []byte("key"), Value: []byte("value")}},
+			} {
+				_, err = guest.Call(context.Background(), body)
+				switch tc.allowed {
+				case true:
+					require.NoError(err, "requests for declared features should be allowed")
+				case false:
+					require.ErrorIs(err, ErrFeatureNotDeclared, "requests for undeclared features should be rejected")
+				}
+			}
+
+			// Requests not requiring any features should always be allowed.
+			_, err = guest.Call(context.Background(), &Body{Empty: &Empty{}})
+			require.NoError(err, "Call")
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// DefaultMaxConcurrentRequests is the default maximum number of concurrent non-critical requests
+// sent to the runtime.
+const DefaultMaxConcurrentRequests = 16
+
+// Priority is the priority class of an outgoing Runtime Host Protocol message.
+type Priority uint8
+
+const (
+	// PriorityBackground is the priority class of periodic maintenance requests.
+	PriorityBackground Priority = iota
+	// PriorityNormal is the priority class of client queries and other requests.
+	PriorityNormal
+	// PriorityCritical is the priority class of consensus-critical requests and of responses.
+	//
+	// Critical requests are never subject to concurrency limits.
+	PriorityCritical
+
+	numPriorities = int(PriorityCritical) + 1
+)
+
+// String returns a string representation of the priority class.
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityNormal:
+		return "normal"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "[unknown]"
+	}
+}
+
+// Priority returns the priority class of the request with the given body.
+func (b *Body) Priority() Priority {
+	switch {
+	case b.RuntimeInfoRequest != nil,
+		b.RuntimeExecuteTxBatchRequest != nil,
+		b.RuntimeConsensusSyncRequest != nil,
+		b.RuntimeAbortRequest != nil,
+		b.RuntimeShutdownRequest != nil,
+		b.RuntimePingRequest != nil:
+		// Batch execution and consensus sync are required for the node to keep up with rounds.
+		// Pings must also not be delayed as the watchdog would mistake a busy runtime for
+		// a dead one.
+		return PriorityCritical
+	case b.RuntimeCapabilityTEERakInitRequest != nil,
+		b.RuntimeCapabilityTEERakReportRequest != nil,
+		b.RuntimeCapabilityTEERakAvrRequest != nil,
+		b.RuntimeCapabilityTEERakQuoteRequest != nil,
+		b.RuntimeCapabilityTEEUpdateEndorsementRequest != nil:
+		// Periodic re-attestation.
+		return PriorityBackground
+	default:
+		return PriorityNormal
+	}
+}
+
+// requestLimiter bounds the number of concurrent non-critical requests. Waiting requests are
+// admitted in order of their priority.
+type requestLimiter struct {
+	sync.Mutex
+
+	limit    int
+	inFlight int
+	waiters  [numPriorities][]chan struct{}
+}
+
+func newRequestLimiter(limit int) *requestLimiter {
+	return &requestLimiter{
+		limit: limit,
+	}
+}
+
+// acquire waits until a request with the given priority may be sent.
+func (l *requestLimiter) acquire(ctx context.Context, p Priority) error {
+	l.Lock()
+	if l.inFlight < l.limit {
+		l.inFlight++
+		l.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	l.waiters[p] = append(l.waiters[p], ch)
+	l.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		l.Lock()
+		defer l.Unlock()
+
+		select {
+		case <-ch:
+			// The slot has been handed over concurrently, pass it on.
+			l.releaseLocked()
+		default:
+			l.waiters[p] = slices.DeleteFunc(l.waiters[p], func(w chan struct{}) bool {
+				return w == ch
+			})
+		}
+		return ctx.Err()
+	}
+}
+
+// release releases a previously acquired slot.
+func (l *requestLimiter) release() {
+	l.Lock()
+	defer l.Unlock()
+
+	l.releaseLocked()
+}
+
+func (l *requestLimiter) releaseLocked() {
+	// Hand the slot over to the oldest waiter with the highest priority.
+	for p := numPriorities - 1; p >= 0; p-- {
+		if len(l.waiters[p]) == 0 {
+			continue
+		}
+		ch := l.waiters[p][0]
+		l.waiters[p] = l.waiters[p][1:]
+		close(ch)
+		return
+	}
+	l.inFlight--
+}
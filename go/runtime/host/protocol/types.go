@@ -3,6 +3,7 @@ package protocol
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/oasisprotocol/curve25519-voi/primitives/x25519"
 
@@ -196,6 +197,15 @@ type Features struct {
 	// EndorsedCapabilityTEE is a feature specifying that the runtime supports endorsed TEE
 	// capabilities.
 	EndorsedCapabilityTEE bool `json:"endorsed_capability_tee,omitempty"`
+	// BatchSummary is a feature specifying that the runtime supports including a summary of
+	// consumed resources in compute results headers.
+	BatchSummary bool `json:"batch_summary,omitempty"`
+	// TxExecutionTimes is a feature specifying that the runtime supports respecting a batch
+	// execution deadline and reporting per-transaction execution times.
+	TxExecutionTimes bool `json:"tx_execution_times,omitempty"`
+	// LocalStorage is a feature specifying that the runtime uses the host-local storage. Runtimes
+	// that don't declare it can't access the host-local storage.
+	LocalStorage bool `json:"local_storage,omitempty"`
 }
 
 // HasScheduleControl returns true when the runtime supports the schedule control feature.
@@ -332,6 +342,11 @@ type CheckTxMetadata struct {
 	// sequence number must be lower than or equal to SenderSeq.
 	SenderStateSeq uint64 `json:"sender_state_seq,omitempty"`
 
+	// ExpiryRound is the runtime round at which the transaction expires. An expired transaction
+	// must not be included in a batch for the expiry round or any later round. Zero means that the
+	// transaction does not expire.
+	ExpiryRound uint64 `json:"expiry_round,omitempty"`
+
 	// Fields below are deprecated to avoid breaking protocol changes. They may be removed once
 	// all runtimes stop sending those fields.
 
@@ -412,6 +427,22 @@ type RuntimeExecuteTxBatchRequest struct {
 	// MaxMessages is the maximum number of messages that can be emitted in this
 	// round. Any more messages will be rejected by the consensus layer.
 	MaxMessages uint32 `json:"max_messages"`
+
+	// Speculative is true iff the batch is executed on top of a block that has not yet been
+	// finalized. The results of speculative execution may be discarded by the host in case the
+	// finalized block differs, so the runtime must not have any side effects outside of the
+	// returned write logs.
+	Speculative bool `json:"speculative,omitempty"`
+
+	// BatchSummary is true iff the runtime should include a batch summary in the compute results
+	// header. It is only set for runtimes supporting the batch summary feature.
+	BatchSummary bool `json:"batch_summary,omitempty"`
+
+	// ExecutionDeadline is the time budget for executing the batch, measured from the time the
+	// runtime receives the request. When scheduling, the runtime should stop including further
+	// transactions once the deadline has passed. It is only set for runtimes supporting the
+	// transaction execution times feature.
+	ExecutionDeadline time.Duration `json:"execution_deadline,omitempty"`
 }
 
 // RuntimeExecuteTxBatchResponse is a worker execute tx batch response message body.
@@ -427,6 +458,10 @@ type RuntimeExecuteTxBatchResponse struct {
 	TxInputRoot hash.Hash `json:"tx_input_root,omitempty"`
 	// TxInputWriteLog is the write log for generating transaction inputs.
 	TxInputWriteLog storage.WriteLog `json:"tx_input_write_log,omitempty"`
+	// TxExecutionTimes are the execution times of the transactions of the included batch, in the
+	// same order as TxHashes. They are only reported by runtimes supporting the transaction
+	// execution times feature.
+	TxExecutionTimes []time.Duration `json:"tx_execution_times,omitempty"`
 
 	// Fields below are deprecated to avoid breaking protocol changes. They may be removed once
 	// all runtimes stop sending those fields.
@@ -543,6 +578,10 @@ type HostStorageSyncResponse struct {
 }
 
 // HostLocalStorageGetRequest is a host local storage get request message body.
+//
+// The host-local storage is an untrusted per-runtime cache of limited size which persists across
+// runtime restarts. The host can arbitrarily modify, drop or replay stored values, so runtimes
+// must verify anything that they read.
 type HostLocalStorageGetRequest struct {
 	Key []byte `json:"key"`
 }
@@ -553,6 +592,9 @@ type HostLocalStorageGetResponse struct {
 }
 
 // HostLocalStorageSetRequest is a host local storage set request message body.
+//
+// The request fails when the total size of stored keys and values would exceed the host-local
+// storage quota.
 type HostLocalStorageSetRequest struct {
 	Key   []byte `json:"key"`
 	Value []byte `json:"value"`
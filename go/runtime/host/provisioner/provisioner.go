@@ -6,6 +6,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -21,9 +22,40 @@ import (
 	hostProtocol "github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
 	hostSandbox "github.com/oasisprotocol/oasis-core/go/runtime/host/sandbox"
 	hostSgx "github.com/oasisprotocol/oasis-core/go/runtime/host/sgx"
+	sgxCommon "github.com/oasisprotocol/oasis-core/go/runtime/host/sgx/common"
 	hostTdx "github.com/oasisprotocol/oasis-core/go/runtime/host/tdx"
 )
 
+// Provisioner is a runtime provisioner which also owns the quote service shared by all
+// TEE provisioners.
+type Provisioner struct {
+	runtimeHost.Provisioner
+
+	qs           pcs.QuoteService
+	tcbStatusSub pubsub.ClosableSubscription
+}
+
+// TCBStatus returns the last known TCB status of the local platform.
+//
+// In case the status is not yet known, nil is returned.
+func (p *Provisioner) TCBStatus() *pcs.PlatformTCBStatus {
+	w, ok := p.qs.(pcs.TCBStatusWatcher)
+	if !ok {
+		return nil
+	}
+	return w.TCBStatus()
+}
+
+// Cleanup stops the platform TCB status watcher.
+func (p *Provisioner) Cleanup() {
+	if p.tcbStatusSub != nil {
+		p.tcbStatusSub.Close()
+	}
+	if w, ok := p.qs.(pcs.TCBStatusWatcher); ok {
+		w.Stop()
+	}
+}
+
 // New creates a new runtime provisioner.
 //
 // This helper function creates a provisioner capable of provisioning runtimes
@@ -34,7 +66,7 @@ func New(
 	commonStore *persistent.CommonStore,
 	identity *identity.Identity,
 	consensus consensus.Service,
-) (runtimeHost.Provisioner, error) {
+) (*Provisioner, error) {
 	// Initialize the IAS proxy client.
 	ias, err := ias.New(identity)
 	if err != nil {
@@ -52,9 +84,30 @@ func New(
 	if err != nil {
 		return nil, err
 	}
+	p := &Provisioner{
+		qs: qs,
+	}
+
+	// Export platform TCB status changes as metrics.
+	if w, ok := qs.(pcs.TCBStatusWatcher); ok {
+		sgxCommon.InitMetrics()
+
+		var ch <-chan *pcs.PlatformTCBStatus
+		ch, p.tcbStatusSub = w.WatchTCBStatus()
+		go func() {
+			for status := range ch {
+				sgxCommon.UpdateTCBStatusMetrics(status)
+			}
+		}()
+	}
 
 	// Create runtime provisioner.
-	return createProvisioner(dataDir, commonStore, identity, consensus, hostInfo, ias, qs)
+	p.Provisioner, err = createProvisioner(dataDir, commonStore, identity, consensus, hostInfo, ias, qs)
+	if err != nil {
+		p.Cleanup()
+		return nil, err
+	}
+	return p, nil
 }
 
 func createHostInfo(consensus consensus.Service) (*hostProtocol.HostInfo, error) {
@@ -72,20 +125,21 @@ func createHostInfo(consensus consensus.Service) (*hostProtocol.HostInfo, error)
 		ConsensusBackend:         cs.Backend,
 		ConsensusProtocolVersion: cs.Version,
 		ConsensusChainContext:    chainCtx,
+		MaxConcurrentRequests:    config.GlobalConfig.Runtime.MaxConcurrentRequests,
+		ShutdownDrainTimeout:     config.GlobalConfig.Runtime.ShutdownDrainTimeout,
 	}, nil
 }
 
 func createCachingQuoteService(commonStore *persistent.CommonStore) (pcs.QuoteService, error) {
 	pc, err := pcs.NewHTTPClient(&pcs.HTTPClientConfig{
 		// TODO: Support configuring the API key.
+		BaseURL: config.GlobalConfig.Runtime.SGX.PCSURL,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PCS HTTP client: %w", err)
 	}
 
-	qs := pcs.NewCachingQuoteService(pc, commonStore)
-
-	return qs, nil
+	return pcs.NewCachingQuoteService(pc, commonStore), nil
 }
 
 func createProvisioner(
@@ -138,9 +192,10 @@ func createProvisioner(
 
 		// Configure the non-TEE provisioner.
 		provisioners[component.TEEKindNone], err = hostSandbox.NewProvisioner(hostSandbox.Config{
-			HostInfo:          hostInfo,
-			InsecureNoSandbox: insecureNoSandbox,
-			SandboxBinaryPath: sandboxBinary,
+			HostInfo:                     hostInfo,
+			InsecureNoSandbox:            insecureNoSandbox,
+			InsecureAllowDebugExecutable: cmdFlags.DebugDontBlameOasis(),
+			SandboxBinaryPath:            sandboxBinary,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create runtime provisioner: %w", err)
@@ -201,9 +256,10 @@ func createProvisioner(
 	}
 
 	// Configure optional load balancing.
+	numInstances := int(config.GlobalConfig.Runtime.LoadBalancer.NumInstances)
+	queryReplicas := config.GlobalConfig.Runtime.QueryReplicas()
 	for tee, rp := range provisioners {
-		numInstances := int(config.GlobalConfig.Runtime.LoadBalancer.NumInstances)
-		provisioners[tee] = hostLoadBalance.NewProvisioner(rp, numInstances)
+		provisioners[tee] = hostLoadBalance.NewProvisioner(rp, numInstances, queryReplicas)
 	}
 
 	// Create a composite provisioner to provision the individual components.
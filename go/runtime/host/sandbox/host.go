@@ -26,12 +26,13 @@ import (
 var errRuntimeNotReady = errors.New("runtime is not yet ready")
 
 const (
-	runtimeInitTimeout         = 1 * time.Second
-	runtimeExtendedInitTimeout = 120 * time.Second
-	runtimeInterruptTimeout    = 1 * time.Second
-	stopTickerTimeout          = 15 * time.Minute
-	watchdogInterval           = 15 * time.Second
-	watchdogPingTimeout        = 5 * time.Second
+	runtimeInitTimeout          = 1 * time.Second
+	runtimeExtendedInitTimeout  = 120 * time.Second
+	runtimeInterruptTimeout     = 1 * time.Second
+	stopTickerTimeout           = 15 * time.Minute
+	watchdogInterval            = 15 * time.Second
+	watchdogPingTimeout         = 5 * time.Second
+	debugExecutablePollInterval = 1 * time.Second
 
 	ctrlChannelBufferSize = 16
 )
@@ -73,6 +74,9 @@ type sandboxHost struct {
 
 	rtVersion *version.Version
 
+	// watcher is the local runtime binary watcher used for hot reload (if configured).
+	watcher *executableWatcher
+
 	logger *logging.Logger
 }
 
@@ -243,6 +247,9 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 	if err = connector.Configure(&h.rtCfg, &cfg); err != nil {
 		return err
 	}
+	if h.watcher != nil {
+		h.watcher.reset()
+	}
 
 	switch h.cfg.InsecureNoSandbox {
 	case true:
@@ -284,6 +291,7 @@ func (h *sandboxHost) startProcess(ctx context.Context) (err error) {
 		// Make sure the connection gets cleaned up in case of errors.
 		if !ok {
 			pc.Close()
+			pc.WaitClosed()
 		}
 	}()
 
@@ -390,6 +398,7 @@ func (h *sandboxHost) handleAbortRequest(ctx context.Context, rq *abortRequest)
 	// Remove the process so it will be respanwed (it would be respawned either way, but with an
 	// additional "unexpected termination" message).
 	h.conn.Close()
+	h.conn.WaitClosed()
 	h.process = nil
 	h.Lock()
 	h.conn = nil
@@ -403,6 +412,29 @@ func (h *sandboxHost) handleAbortRequest(ctx context.Context, rq *abortRequest)
 	return nil
 }
 
+// handleReload stops the runtime so that it is restarted using the changed local runtime binary.
+func (h *sandboxHost) handleReload() {
+	h.logger.Warn("local runtime binary has changed, reloading runtime",
+		"path", h.rtCfg.DebugExecutable,
+	)
+
+	// Give the runtime a chance to complete any in-flight requests before killing it.
+	h.conn.Close()
+	h.conn.WaitClosed()
+	h.process.Kill()
+	<-h.process.Wait()
+	h.process = nil
+	h.Lock()
+	h.conn = nil
+	h.capabilityTEE = nil
+	h.rtVersion = nil
+	h.Unlock()
+
+	// Notify subscribers that the runtime has stopped. Once the new binary is started, subscribers
+	// will be notified via the usual started event and can re-initialize the runtime.
+	h.notifier.Broadcast(&host.Event{Stopped: &host.StoppedEvent{}})
+}
+
 // watchdogPing pings the runtime for liveness and terminates the process in case response is not
 // received in time.
 func (h *sandboxHost) watchdogPing(ctx context.Context) {
@@ -432,7 +464,9 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			ticker = nil
 		}
 		if h.process != nil {
+			// Give the runtime a chance to complete any in-flight requests before killing it.
 			h.conn.Close()
+			h.conn.WaitClosed()
 			h.process.Kill()
 			<-h.process.Wait()
 			h.process = nil
@@ -457,7 +491,11 @@ func (h *sandboxHost) manager(ctx context.Context) {
 		attempt      int
 		stopTickerCh <-chan time.Time
 		watchdogCh   <-chan time.Time
+		reloadCh     <-chan time.Time
 	)
+	if h.watcher != nil {
+		reloadCh = time.Tick(debugExecutablePollInterval)
+	}
 	for {
 		// Terminate immediately when requested.
 		select {
@@ -534,6 +572,7 @@ func (h *sandboxHost) manager(ctx context.Context) {
 			)
 
 			h.conn.Close()
+			h.conn.WaitClosed()
 			h.process = nil
 			h.Lock()
 			h.conn = nil
@@ -560,6 +599,18 @@ func (h *sandboxHost) manager(ctx context.Context) {
 		case <-watchdogCh:
 			// Check for runtime liveness.
 			h.watchdogPing(ctx)
+		case <-reloadCh:
+			// Check whether the local runtime binary has changed.
+			if !h.watcher.changed() {
+				continue
+			}
+			h.handleReload()
+
+			// Make sure the new binary is started immediately.
+			if ticker != nil {
+				ticker.Stop()
+				ticker = nil
+			}
 		}
 	}
 }
@@ -48,6 +48,10 @@ type Config struct {
 
 	// InsecureNoSandbox disables the sandbox and runs the runtime binary directly.
 	InsecureNoSandbox bool
+
+	// InsecureAllowDebugExecutable allows running local runtime binaries with hot reload instead
+	// of the bundled executables (see host.Config.DebugExecutable).
+	InsecureAllowDebugExecutable bool
 }
 
 type sandboxProvisioner struct {
@@ -89,6 +93,15 @@ func NewProvisioner(cfg Config) (host.Provisioner, error) {
 
 // Implements host.Provisioner.
 func (p *sandboxProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
+	if cfg.DebugExecutable != "" && !p.cfg.InsecureAllowDebugExecutable {
+		return nil, fmt.Errorf("local runtime binaries require use of unsafe debug flags")
+	}
+
+	var watcher *executableWatcher
+	if cfg.DebugExecutable != "" {
+		watcher = newExecutableWatcher(cfg.DebugExecutable)
+	}
+
 	return &sandboxHost{
 		cfg:                         p.cfg,
 		rtCfg:                       cfg,
@@ -97,6 +110,7 @@ func (p *sandboxProvisioner) NewRuntime(cfg host.Config) (host.Runtime, error) {
 		ctrlCh:                      make(chan any, ctrlChannelBufferSize),
 		notifier:                    pubsub.NewBroker(false),
 		notifyUpdateCapabilityTEECh: make(chan struct{}, 1),
+		watcher:                     watcher,
 		logger:                      p.cfg.Logger.With("runtime_id", cfg.ID),
 	}, nil
 }
@@ -121,9 +135,13 @@ func DefaultGetSandboxConfig(logger *logging.Logger, sandboxBinaryPath string) G
 		if cfg.Component.ELF != nil {
 			executable = cfg.Component.ELF.Executable
 		}
+		path := cfg.Component.ExplodedPath(executable)
+		if cfg.DebugExecutable != "" {
+			path = cfg.DebugExecutable
+		}
 
 		return process.Config{
-			Path:              cfg.Component.ExplodedPath(executable),
+			Path:              path,
 			SandboxBinaryPath: sandboxBinaryPath,
 			Stdout:            logWrapper,
 			Stderr:            logWrapper,
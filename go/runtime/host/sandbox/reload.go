@@ -0,0 +1,68 @@
+package sandbox
+
+import (
+	"os"
+	"time"
+)
+
+// executableState is the observed state of an executable used to detect changes.
+type executableState struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s *executableState) equal(other *executableState) bool {
+	return s.modTime.Equal(other.modTime) && s.size == other.size
+}
+
+func statExecutable(path string) (*executableState, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &executableState{
+		modTime: fi.ModTime(),
+		size:    fi.Size(),
+	}, nil
+}
+
+// executableWatcher detects changes of a local runtime binary.
+//
+// A change is only reported once the binary has remained unchanged for two consecutive checks, so
+// that a binary that is still being written by the build is not executed.
+type executableWatcher struct {
+	path string
+
+	current *executableState
+	pending *executableState
+}
+
+func newExecutableWatcher(path string) *executableWatcher {
+	return &executableWatcher{path: path}
+}
+
+// reset records the current state of the binary as the one being executed.
+func (w *executableWatcher) reset() {
+	w.current, _ = statExecutable(w.path)
+	w.pending = nil
+}
+
+// changed returns true iff the binary has changed since the last reset and is ready to be
+// executed.
+func (w *executableWatcher) changed() bool {
+	st, err := statExecutable(w.path)
+	if err != nil {
+		// The binary may be temporarily missing while it is being rebuilt.
+		w.pending = nil
+		return false
+	}
+	if w.current != nil && st.equal(w.current) {
+		w.pending = nil
+		return false
+	}
+	if w.pending == nil || !st.equal(w.pending) {
+		w.pending = st
+		return false
+	}
+	return true
+}
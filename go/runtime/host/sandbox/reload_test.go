@@ -0,0 +1,64 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+func TestExecutableWatcher(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "runtime")
+	require.NoError(os.WriteFile(path, []byte("v1"), 0o700))
+
+	w := newExecutableWatcher(path)
+	w.reset()
+	require.False(w.changed(), "unchanged binary should not be reported")
+
+	// Simulate a rebuild, the change should only be reported once the binary settles.
+	require.NoError(os.WriteFile(path, []byte("v2-partial"), 0o700))
+	require.False(w.changed(), "binary being written should not be reported")
+	require.NoError(os.WriteFile(path, []byte("v2-complete"), 0o700))
+	require.False(w.changed(), "binary being written should not be reported")
+	require.True(w.changed(), "settled binary should be reported")
+
+	// Once the new binary has been started, it should no longer be reported.
+	w.reset()
+	require.False(w.changed())
+	require.False(w.changed())
+
+	// A missing binary should not be reported.
+	require.NoError(os.Remove(path))
+	require.False(w.changed())
+	require.False(w.changed())
+
+	// Recreating it should be reported.
+	require.NoError(os.WriteFile(path, []byte("v3"), 0o700))
+	require.NoError(os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	require.False(w.changed())
+	require.True(w.changed())
+}
+
+func TestDebugExecutableRequiresDebugFlags(t *testing.T) {
+	require := require.New(t)
+
+	cfg := host.Config{DebugExecutable: "/path/to/runtime"}
+
+	p, err := NewProvisioner(Config{HostInfo: &protocol.HostInfo{}})
+	require.NoError(err, "NewProvisioner")
+	_, err = p.NewRuntime(cfg)
+	require.Error(err, "local runtime binaries should be rejected without unsafe debug flags")
+
+	p, err = NewProvisioner(Config{HostInfo: &protocol.HostInfo{}, InsecureAllowDebugExecutable: true})
+	require.NoError(err, "NewProvisioner")
+	rt, err := p.NewRuntime(cfg)
+	require.NoError(err, "NewRuntime")
+	require.NotNil(rt.(*sandboxHost).watcher)
+}
@@ -1,11 +1,13 @@
 package common
 
 import (
+	"encoding/hex"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/pcs"
 	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 )
@@ -38,10 +40,20 @@ var (
 		[]string{"runtime", "kind"},
 	)
 
+	// TCB status of the local platform.
+	teeTCBStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_tee_tcb_status",
+			Help: "TCB status of the local platform (1 = UpToDate, 2 = SWHardeningNeeded, 3 = ConfigurationNeeded, 4 = ConfigurationAndSWHardeningNeeded, 5 = OutOfDate, 6 = OutOfDateConfigurationNeeded, 7 = Revoked).",
+		},
+		[]string{"kind", "fmspc"},
+	)
+
 	teeCollectors = []prometheus.Collector{
 		teeAttestationsPerformed,
 		teeAttestationsSuccessful,
 		teeAttestationsFailed,
+		teeTCBStatus,
 	}
 
 	metricsOnce sync.Once
@@ -64,6 +76,18 @@ func UpdateAttestationMetrics(runtimeID common.Namespace, kind component.TEEKind
 	}
 }
 
+// UpdateTCBStatusMetrics updates the platform TCB status metrics if metrics are enabled.
+func UpdateTCBStatusMetrics(status *pcs.PlatformTCBStatus) {
+	if !metrics.Enabled() {
+		return
+	}
+
+	kind := status.TeeType.String()
+	fmspc := hex.EncodeToString(status.FMSPC)
+
+	teeTCBStatus.With(prometheus.Labels{"kind": kind, "fmspc": fmspc}).Set(float64(status.Status))
+}
+
 // InitMetrics registers the metrics collectors if metrics are enabled.
 func InitMetrics() {
 	if !metrics.Enabled() {
@@ -1,23 +1,39 @@
 // Package localstorage implements untrusted local storage that is used
 // by runtimes to store per-node key/value pairs.
+//
+// The contents of local storage are an untrusted local cache. The host can
+// arbitrarily modify, drop or replay stored values, so runtimes must verify
+// anything that they read back.
 package localstorage
 
 import (
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/dgraph-io/badger/v4"
 	"github.com/dgraph-io/badger/v4/options"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	cmnBadger "github.com/oasisprotocol/oasis-core/go/common/badger"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 )
 
+const (
+	moduleName = "localstorage"
+
+	// DefaultQuota is the default maximum total size of all keys and values stored in the
+	// local storage of a single runtime.
+	DefaultQuota = 16 * 1024 * 1024
+)
+
 var (
-	errInvalidKey = errors.New("invalid local storage key")
+	// ErrInvalidKey is the error returned when the local storage key is invalid.
+	ErrInvalidKey = errors.New(moduleName, 1, "localstorage: invalid key")
+	// ErrQuotaExceeded is the error returned when a write would exceed the local storage quota.
+	ErrQuotaExceeded = errors.New(moduleName, 2, "localstorage: quota exceeded")
 
 	_ LocalStorage = (*localStorage)(nil)
 )
@@ -35,15 +51,20 @@ type LocalStorage interface {
 }
 
 type localStorage struct {
+	sync.Mutex
+
 	logger *logging.Logger
 
 	db *badger.DB
 	gc *cmnBadger.GCWorker
+
+	quota uint64
+	size  uint64
 }
 
 func (s *localStorage) Get(key []byte) ([]byte, error) {
 	if len(key) == 0 {
-		return nil, errInvalidKey
+		return nil, ErrInvalidKey
 	}
 
 	var value []byte
@@ -74,10 +95,30 @@ func (s *localStorage) Get(key []byte) ([]byte, error) {
 
 func (s *localStorage) Set(key, value []byte) error {
 	if len(key) == 0 {
-		return errInvalidKey
+		return ErrInvalidKey
 	}
 
+	s.Lock()
+	defer s.Unlock()
+
+	var size uint64
 	if err := s.db.Update(func(tx *badger.Txn) error {
+		// Account for the entry being replaced, if any.
+		size = s.size
+		item, txErr := tx.Get(key)
+		switch txErr {
+		case nil:
+			size -= uint64(len(key)) + uint64(item.ValueSize())
+		case badger.ErrKeyNotFound:
+		default:
+			return txErr
+		}
+
+		size += uint64(len(key)) + uint64(len(value))
+		if size > s.quota {
+			return ErrQuotaExceeded
+		}
+
 		return tx.Set(key, value)
 	}); err != nil {
 		s.logger.Error("failed put",
@@ -87,6 +128,7 @@ func (s *localStorage) Set(key, value []byte) error {
 		)
 		return err
 	}
+	s.size = size
 
 	return nil
 }
@@ -101,10 +143,12 @@ func (s *localStorage) Stop() {
 	s.db = nil
 }
 
-// New creates new untrusted local storage.
-func New(dataDir, fn string, runtimeID common.Namespace) (LocalStorage, error) {
+// New creates new untrusted local storage, limiting the total size of all stored keys and values
+// to the given quota.
+func New(dataDir, fn string, runtimeID common.Namespace, quota uint64) (LocalStorage, error) {
 	s := &localStorage{
 		logger: logging.GetLogger("runtime/localstorage").With("runtime_id", runtimeID),
+		quota:  quota,
 	}
 
 	opts := badger.DefaultOptions(filepath.Join(dataDir, fn))
@@ -117,6 +161,21 @@ func New(dataDir, fn string, runtimeID common.Namespace) (LocalStorage, error) {
 		return nil, fmt.Errorf("failed to open local storage database: %w", err)
 	}
 
+	// Compute the size of existing entries, which may exceed the quota if it was lowered.
+	if err = s.db.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			s.size += uint64(len(item.Key())) + uint64(item.ValueSize())
+		}
+		return nil
+	}); err != nil {
+		_ = s.db.Close()
+		return nil, fmt.Errorf("failed to compute local storage size: %w", err)
+	}
+
 	s.gc = cmnBadger.NewGCWorker(s.logger, s.db)
 	s.gc.Start()
 
@@ -0,0 +1,75 @@
+package localstorage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/mock"
+	"github.com/oasisprotocol/oasis-core/go/runtime/localstorage"
+)
+
+const testQuota = 64
+
+var testNs = common.NewTestNamespaceFromSeed([]byte("local storage test ns"), 0)
+
+func TestLocalStorage(t *testing.T) {
+	t.Run("Badger", func(t *testing.T) {
+		dataDir := t.TempDir()
+		s, err := localstorage.New(dataDir, "local-storage.badger.db", testNs, testQuota)
+		require.NoError(t, err, "New")
+		testLocalStorage(t, s)
+		s.Stop()
+
+		// Reopening should account for the existing entries.
+		s, err = localstorage.New(dataDir, "local-storage.badger.db", testNs, testQuota)
+		require.NoError(t, err, "New")
+		defer s.Stop()
+
+		value, err := s.Get([]byte("key"))
+		require.NoError(t, err, "Get")
+		require.Equal(t, make([]byte, 29), value, "values should persist across restarts")
+		require.ErrorIs(t, s.Set([]byte("other"), make([]byte, testQuota)), localstorage.ErrQuotaExceeded)
+	})
+	t.Run("Mock", func(t *testing.T) {
+		testLocalStorage(t, mock.NewLocalStorage(testQuota))
+	})
+}
+
+func testLocalStorage(t *testing.T, s localstorage.LocalStorage) {
+	require := require.New(t)
+
+	_, err := s.Get(nil)
+	require.ErrorIs(err, localstorage.ErrInvalidKey)
+	require.ErrorIs(s.Set(nil, []byte("value")), localstorage.ErrInvalidKey)
+
+	value, err := s.Get([]byte("key"))
+	require.NoError(err, "Get")
+	require.Nil(value, "missing keys should have no value")
+
+	require.NoError(s.Set([]byte("key"), []byte("value")), "Set")
+	value, err = s.Get([]byte("key"))
+	require.NoError(err, "Get")
+	require.Equal([]byte("value"), value)
+
+	// Writes up to the quota should succeed.
+	require.NoError(s.Set([]byte("key2"), make([]byte, testQuota-len("key")-len("value")-len("key2"))), "Set")
+
+	// Writes over the quota should fail and leave the previous value in place.
+	err = s.Set([]byte("key3"), []byte("x"))
+	require.ErrorIs(err, localstorage.ErrQuotaExceeded)
+	value, err = s.Get([]byte("key3"))
+	require.NoError(err, "Get")
+	require.Nil(value, "failed writes should not be stored")
+
+	err = s.Set([]byte("key"), []byte("value2"))
+	require.ErrorIs(err, localstorage.ErrQuotaExceeded)
+	value, err = s.Get([]byte("key"))
+	require.NoError(err, "Get")
+	require.Equal([]byte("value"), value, "failed writes should not overwrite values")
+
+	// Shrinking existing values should free up space.
+	require.NoError(s.Set([]byte("key2"), nil), "Set")
+	require.NoError(s.Set([]byte("key"), make([]byte, 29)), "Set")
+}
@@ -26,6 +26,11 @@ func getLocalConfig(runtimeID common.Namespace, compID component.ID) map[string]
 	return compCfg.Config
 }
 
+func getDebugExecutable(runtimeID common.Namespace, compID component.ID) string {
+	compCfg, _ := config.GlobalConfig.Runtime.GetComponent(runtimeID, compID)
+	return compCfg.DebugExecutable
+}
+
 func getConfiguredRuntimeIDs() ([]common.Namespace, error) {
 	// Check if any runtimes are configured to be hosted.
 	runtimes := make(map[common.Namespace]struct{})
@@ -92,8 +97,9 @@ func createHistoryFactory() (history.Factory, error) {
 		pruneFactory = history.NewNonePrunerFactory()
 	case history.PrunerStrategyKeepLast:
 		numKept := config.GlobalConfig.Runtime.Prune.NumKept
+		maxAge := config.GlobalConfig.Runtime.Prune.MaxAge
 		pruneInterval := max(config.GlobalConfig.Runtime.Prune.Interval, time.Second)
-		pruneFactory = history.NewKeepLastPrunerFactory(numKept, pruneInterval)
+		pruneFactory = history.NewKeepLastPrunerFactory(numKept, maxAge, pruneInterval)
 	default:
 		return nil, fmt.Errorf("runtime/registry: unknown history pruner strategy: %s", strategy)
 	}
@@ -0,0 +1,194 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+// consensusCacheCapacity is the maximum number of responses kept in the consensus cache.
+const consensusCacheCapacity = 1024
+
+const (
+	consensusMethodBlock       = "HostFetchConsensusBlock"
+	consensusMethodEvents      = "HostFetchConsensusEvents"
+	consensusMethodBlockMetaTx = "HostFetchBlockMetadataTx"
+)
+
+type consensusCacheKey struct {
+	method    string
+	height    uint64
+	eventKind protocol.EventKind
+}
+
+type consensusCacheEntry struct {
+	value    any
+	duration time.Duration
+}
+
+// ConsensusCache is a cache of consensus data requested by hosted runtimes via the runtime host
+// protocol (e.g., light blocks and events used for light client verification).
+//
+// The cache is shared across all runtimes hosted by the node. Only data for specific heights is
+// cached, as such data does not change once the block is finalized. To be safe, the cache is
+// still invalidated in case the consensus layer ever reports a block at a height that has
+// already been seen.
+type ConsensusCache struct {
+	mu sync.Mutex
+
+	cache *lru.Cache
+
+	lastHeight int64
+
+	logger *logging.Logger
+}
+
+// NewConsensusCache creates a new consensus cache holding at most the given number of responses.
+func NewConsensusCache(capacity uint64) *ConsensusCache {
+	initMetrics()
+
+	return &ConsensusCache{
+		cache:  lru.New(lru.Capacity(capacity, false)),
+		logger: logging.GetLogger("runtime/registry/consensus-cache"),
+	}
+}
+
+// fetch returns the cached response for the given key or fetches it using the given function
+// and caches it on success.
+func (c *ConsensusCache) fetch(key consensusCacheKey, fetchFn func() (any, error)) (any, error) {
+	start := time.Now()
+
+	// Requests for the latest height are never cached.
+	if key.height == 0 {
+		value, err := fetchFn()
+		consensusRequestDuration.WithLabelValues(key.method, "uncached").Observe(time.Since(start).Seconds())
+		return value, err
+	}
+
+	if raw, ok := c.cache.Get(key); ok {
+		entry := raw.(*consensusCacheEntry)
+		consensusCacheRequests.WithLabelValues(key.method, "hit").Inc()
+		consensusRequestDuration.WithLabelValues(key.method, "hit").Observe(time.Since(start).Seconds())
+		if saved := entry.duration - time.Since(start); saved > 0 {
+			consensusCacheSavedTime.WithLabelValues(key.method).Add(saved.Seconds())
+		}
+		return entry.value, nil
+	}
+
+	value, err := fetchFn()
+	duration := time.Since(start)
+	consensusCacheRequests.WithLabelValues(key.method, "miss").Inc()
+	consensusRequestDuration.WithLabelValues(key.method, "miss").Observe(duration.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Do not cache data for blocks that are ahead of what the consensus layer has reported as
+	// this could race with invalidation.
+	if c.lastHeight == 0 || int64(key.height) <= c.lastHeight {
+		_ = c.cache.Put(key, &consensusCacheEntry{value: value, duration: duration})
+	}
+
+	return value, nil
+}
+
+// LightBlock returns the consensus light block response for the given height.
+func (c *ConsensusCache) LightBlock(
+	height uint64,
+	fetchFn func() (*protocol.HostFetchConsensusBlockResponse, error),
+) (*protocol.HostFetchConsensusBlockResponse, error) {
+	key := consensusCacheKey{method: consensusMethodBlock, height: height}
+	value, err := c.fetch(key, func() (any, error) { return fetchFn() })
+	if err != nil {
+		return nil, err
+	}
+	return value.(*protocol.HostFetchConsensusBlockResponse), nil
+}
+
+// Events returns the consensus events response of the given kind for the given height.
+func (c *ConsensusCache) Events(
+	height uint64,
+	kind protocol.EventKind,
+	fetchFn func() (*protocol.HostFetchConsensusEventsResponse, error),
+) (*protocol.HostFetchConsensusEventsResponse, error) {
+	key := consensusCacheKey{method: consensusMethodEvents, height: height, eventKind: kind}
+	value, err := c.fetch(key, func() (any, error) { return fetchFn() })
+	if err != nil {
+		return nil, err
+	}
+	return value.(*protocol.HostFetchConsensusEventsResponse), nil
+}
+
+// BlockMetadataTx returns the block metadata transaction response for the given height.
+func (c *ConsensusCache) BlockMetadataTx(
+	height uint64,
+	fetchFn func() (*protocol.HostFetchBlockMetadataTxResponse, error),
+) (*protocol.HostFetchBlockMetadataTxResponse, error) {
+	key := consensusCacheKey{method: consensusMethodBlockMetaTx, height: height}
+	value, err := c.fetch(key, func() (any, error) { return fetchFn() })
+	if err != nil {
+		return nil, err
+	}
+	return value.(*protocol.HostFetchBlockMetadataTxResponse), nil
+}
+
+// processBlock updates the latest consensus height and invalidates any cached data for heights
+// that are reported again.
+func (c *ConsensusCache) processBlock(blk *consensus.Block) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lastHeight != 0 && blk.Height <= c.lastHeight {
+		var removed int
+		for _, raw := range c.cache.Keys() {
+			key := raw.(consensusCacheKey)
+			if int64(key.height) < blk.Height {
+				continue
+			}
+			if c.cache.Remove(key) {
+				removed++
+			}
+		}
+
+		c.logger.Warn("consensus block height did not advance, invalidated cached data",
+			"height", blk.Height,
+			"last_height", c.lastHeight,
+			"hash", blk.Hash,
+			"removed", removed,
+		)
+		consensusCacheInvalidations.Inc()
+	}
+	c.lastHeight = blk.Height
+}
+
+// watch invalidates cached data based on consensus block updates until the context is canceled.
+func (c *ConsensusCache) watch(ctx context.Context, consensus consensus.Service) {
+	blkCh, blkSub, err := consensus.Core().WatchBlocks(ctx)
+	if err != nil {
+		c.logger.Error("failed to subscribe to consensus block updates",
+			"err", err,
+		)
+		return
+	}
+	defer blkSub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blk, ok := <-blkCh:
+			if !ok {
+				return
+			}
+			c.processBlock(blk)
+		}
+	}
+}
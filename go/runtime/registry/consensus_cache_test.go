@@ -0,0 +1,169 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+type testConsensusBackend struct {
+	consensus.Backend
+
+	mu          sync.Mutex
+	calls       map[int64]int
+	latest      int64
+	eventsCalls map[int64]int
+}
+
+func (b *testConsensusBackend) GetLightBlock(_ context.Context, height int64) (*consensus.LightBlock, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls[height]++
+	if height > b.latest {
+		return nil, fmt.Errorf("height %d not available", height)
+	}
+	return &consensus.LightBlock{Height: height, Meta: []byte(fmt.Sprintf("block %d", height))}, nil
+}
+
+func (b *testConsensusBackend) GetEvents(_ context.Context, height int64) ([]*staking.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.eventsCalls[height]++
+	return []*staking.Event{{Height: height}}, nil
+}
+
+func (b *testConsensusBackend) numCalls(height int64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls[height]
+}
+
+type testStakingBackend struct {
+	staking.Backend
+
+	backend *testConsensusBackend
+}
+
+func (b *testStakingBackend) GetEvents(ctx context.Context, height int64) ([]*staking.Event, error) {
+	return b.backend.GetEvents(ctx, height)
+}
+
+type testConsensusService struct {
+	consensus.Service
+
+	backend *testConsensusBackend
+}
+
+func (s *testConsensusService) Core() consensus.Backend {
+	return s.backend
+}
+
+func (s *testConsensusService) Staking() staking.Backend {
+	return &testStakingBackend{backend: s.backend}
+}
+
+type testHandlerEnvironment struct {
+	RuntimeHostHandlerEnvironment
+}
+
+func (env *testHandlerEnvironment) GetLightProvider() (consensus.LightProvider, error) {
+	return nil, fmt.Errorf("light provider not available")
+}
+
+func fetchBlock(h *runtimeHostHandler, height uint64) (*protocol.Body, error) {
+	return h.Handle(context.Background(), &protocol.Body{
+		HostFetchConsensusBlockRequest: &protocol.HostFetchConsensusBlockRequest{Height: height},
+	})
+}
+
+func TestConsensusCache(t *testing.T) {
+	require := require.New(t)
+
+	backend := &testConsensusBackend{
+		calls:       make(map[int64]int),
+		eventsCalls: make(map[int64]int),
+		latest:      30,
+	}
+	cache := NewConsensusCache(4)
+
+	// Simulate multiple hosted runtimes sharing the same cache.
+	var handlers []*runtimeHostHandler
+	for range 2 {
+		handlers = append(handlers, &runtimeHostHandler{
+			env:       &testHandlerEnvironment{},
+			consensus: &testConsensusService{backend: backend},
+			cache:     cache,
+		})
+	}
+
+	// Repeated requests for the same height should only hit the backend once.
+	for i := range 4 {
+		rsp, err := fetchBlock(handlers[i%2], 10)
+		require.NoError(err, "HostFetchConsensusBlockRequest")
+		require.EqualValues(10, rsp.HostFetchConsensusBlockResponse.Block.Height)
+		require.Equal([]byte("block 10"), rsp.HostFetchConsensusBlockResponse.Block.Meta)
+	}
+	require.Equal(1, backend.numCalls(10), "light block should be cached")
+
+	for range 2 {
+		rsp, err := handlers[0].Handle(context.Background(), &protocol.Body{
+			HostFetchConsensusEventsRequest: &protocol.HostFetchConsensusEventsRequest{
+				Height: 10,
+				Kind:   protocol.EventKindStaking,
+			},
+		})
+		require.NoError(err, "HostFetchConsensusEventsRequest")
+		require.Len(rsp.HostFetchConsensusEventsResponse.Events, 1)
+	}
+	require.Equal(1, backend.eventsCalls[10], "events should be cached")
+
+	// Requests for the latest height and failed requests should not be cached.
+	for range 2 {
+		_, err := fetchBlock(handlers[0], 0)
+		require.NoError(err, "HostFetchConsensusBlockRequest")
+		_, err = fetchBlock(handlers[0], 40)
+		require.Error(err, "HostFetchConsensusBlockRequest should fail for unavailable heights")
+	}
+	require.Equal(2, backend.numCalls(0), "latest light block should not be cached")
+	require.Equal(2, backend.numCalls(40), "failures should not be cached")
+
+	// Once the consensus height is known, data for later heights should not be cached.
+	cache.processBlock(&consensus.Block{Height: 20})
+	for range 2 {
+		_, err := fetchBlock(handlers[1], 25)
+		require.NoError(err, "HostFetchConsensusBlockRequest")
+		_, err = fetchBlock(handlers[1], 15)
+		require.NoError(err, "HostFetchConsensusBlockRequest")
+	}
+	require.Equal(2, backend.numCalls(25), "light block ahead of consensus should not be cached")
+	require.Equal(1, backend.numCalls(15))
+
+	// Reporting an already seen height should invalidate data for that and later heights.
+	cache.processBlock(&consensus.Block{Height: 21})
+	cache.processBlock(&consensus.Block{Height: 15})
+	_, err := fetchBlock(handlers[0], 15)
+	require.NoError(err, "HostFetchConsensusBlockRequest")
+	_, err = fetchBlock(handlers[0], 10)
+	require.NoError(err, "HostFetchConsensusBlockRequest")
+	require.Equal(2, backend.numCalls(15), "invalidated light block should be fetched again")
+	require.Equal(1, backend.numCalls(10), "earlier light block should remain cached")
+
+	// The cache should be bounded.
+	for height := uint64(1); height <= 4; height++ {
+		_, err = fetchBlock(handlers[0], height)
+		require.NoError(err, "HostFetchConsensusBlockRequest")
+	}
+	require.EqualValues(4, cache.cache.Size())
+	_, err = fetchBlock(handlers[0], 10)
+	require.NoError(err, "HostFetchConsensusBlockRequest")
+	require.Equal(2, backend.numCalls(10), "evicted light block should be fetched again")
+}
@@ -0,0 +1,83 @@
+package registry
+
+import (
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	cmdFlags "github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/flags"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+// DeploymentFeatures resolves node-side runtime settings from the feature flags of the active
+// runtime deployment and the local node configuration.
+//
+// Feature flags set by the runtime owner take precedence over the local configuration, which is
+// only used for settings that the active deployment does not specify. In debug mode the local
+// configuration always takes precedence.
+type DeploymentFeatures struct {
+	runtimeID  common.Namespace
+	deployment *registry.VersionInfo
+
+	localOverride bool
+}
+
+// NewDeploymentFeatures creates deployment features for the deployment of the given runtime
+// descriptor that is active at the given epoch.
+func NewDeploymentFeatures(rt *registry.Runtime, epoch beacon.EpochTime) *DeploymentFeatures {
+	return &DeploymentFeatures{
+		runtimeID:     rt.ID,
+		deployment:    rt.ActiveDeployment(epoch),
+		localOverride: cmdFlags.DebugDontBlameOasis(),
+	}
+}
+
+func (f *DeploymentFeatures) uint64Feature(name string) (uint64, bool) {
+	if f.localOverride {
+		return 0, false
+	}
+	return f.deployment.FeatureUint64(name)
+}
+
+func (f *DeploymentFeatures) float64Feature(name string) (float64, bool) {
+	if f.localOverride {
+		return 0, false
+	}
+	return f.deployment.FeatureFloat64(name)
+}
+
+// StorageBackpressureConfig returns the executor storage backpressure configuration.
+func (f *DeploymentFeatures) StorageBackpressureConfig() runtimeConfig.StorageBackpressureConfig {
+	cfg := config.GlobalConfig.Runtime.GetStorageBackpressureConfig(f.runtimeID)
+	if v, ok := f.uint64Feature(registry.FeatureStorageBackpressureSoftLagThreshold); ok {
+		cfg.SoftLagThreshold = v
+	}
+	if v, ok := f.uint64Feature(registry.FeatureStorageBackpressureHardLagThreshold); ok {
+		cfg.HardLagThreshold = v
+	}
+	return cfg
+}
+
+// CheckTxConfig returns the runtime client transaction check configuration.
+func (f *DeploymentFeatures) CheckTxConfig() runtimeConfig.CheckTxConfig {
+	cfg := config.GlobalConfig.Runtime.CheckTx
+	if v, ok := f.float64Feature(registry.FeatureCheckTxRateLimit); ok {
+		cfg.RateLimit = v
+	}
+	if v, ok := f.uint64Feature(registry.FeatureCheckTxBurst); ok {
+		cfg.Burst = v
+	}
+	if cfg.RateLimit > 0 {
+		cfg.Burst = max(cfg.Burst, 1)
+	}
+	return cfg
+}
+
+// StorageFetcherCount returns the number of storage diff fetchers.
+func (f *DeploymentFeatures) StorageFetcherCount() uint {
+	count := config.GlobalConfig.Storage.FetcherCount
+	if v, ok := f.uint64Feature(registry.FeatureStorageFetcherCount); ok {
+		count = uint(v)
+	}
+	return max(count, 1)
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	runtimeConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+)
+
+func TestDeploymentFeatures(t *testing.T) {
+	require := require.New(t)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("deployment features test"), 0)
+
+	prevCfg := config.GlobalConfig
+	defer func() { config.GlobalConfig = prevCfg }()
+	config.GlobalConfig = config.DefaultConfig()
+	config.GlobalConfig.Storage.FetcherCount = 4
+	config.GlobalConfig.Runtime.CheckTx = runtimeConfig.CheckTxConfig{RateLimit: 50, Burst: 100}
+	config.GlobalConfig.Runtime.Runtimes = []runtimeConfig.RuntimeConfig{
+		{
+			ID: runtimeID,
+			StorageBackpressure: runtimeConfig.StorageBackpressureConfig{
+				SoftLagThreshold: 5,
+				HardLagThreshold: 50,
+			},
+		},
+	}
+
+	rt := &registry.Runtime{
+		ID: runtimeID,
+		Deployments: []*registry.VersionInfo{
+			{
+				Version:   version.Version{Major: 1},
+				ValidFrom: 0,
+			},
+			{
+				Version:   version.Version{Major: 2},
+				ValidFrom: 10,
+				Features: map[string]string{
+					registry.FeatureStorageFetcherCount:                 "8",
+					registry.FeatureCheckTxRateLimit:                    "0",
+					registry.FeatureStorageBackpressureHardLagThreshold: "20",
+				},
+			},
+		},
+	}
+
+	// Before the activation epoch, the local configuration is used.
+	f := NewDeploymentFeatures(rt, 9)
+	require.EqualValues(4, f.StorageFetcherCount())
+	require.Equal(runtimeConfig.CheckTxConfig{RateLimit: 50, Burst: 100}, f.CheckTxConfig())
+	require.Equal(runtimeConfig.StorageBackpressureConfig{SoftLagThreshold: 5, HardLagThreshold: 50}, f.StorageBackpressureConfig())
+
+	// Starting with the activation epoch, the deployment features take precedence.
+	for _, epoch := range []beacon.EpochTime{10, 11, 100} {
+		f = NewDeploymentFeatures(rt, epoch)
+		require.EqualValues(8, f.StorageFetcherCount())
+		require.Zero(f.CheckTxConfig().RateLimit, "rate limit should be disabled by the deployment")
+		require.Equal(runtimeConfig.StorageBackpressureConfig{SoftLagThreshold: 5, HardLagThreshold: 20}, f.StorageBackpressureConfig())
+	}
+
+	// In debug mode, the local configuration takes precedence.
+	f = NewDeploymentFeatures(rt, 10)
+	f.localOverride = true
+	require.EqualValues(4, f.StorageFetcherCount())
+	require.Equal(runtimeConfig.CheckTxConfig{RateLimit: 50, Burst: 100}, f.CheckTxConfig())
+	require.Equal(runtimeConfig.StorageBackpressureConfig{SoftLagThreshold: 5, HardLagThreshold: 50}, f.StorageBackpressureConfig())
+}
@@ -47,6 +47,7 @@ type runtimeHostHandler struct {
 	env       RuntimeHostHandlerEnvironment
 	runtime   Runtime
 	consensus consensus.Service
+	cache     *ConsensusCache
 }
 
 // NewRuntimeHostHandler returns a protocol handler that provides the required host methods for the
@@ -62,6 +63,7 @@ func NewRuntimeHostHandler(
 		env:       env,
 		runtime:   runtime,
 		consensus: consensus,
+		cache:     env.GetRuntimeRegistry().GetConsensusCache(),
 	}
 }
 
@@ -256,6 +258,15 @@ func (h *runtimeHostHandler) handleHostLocalStorageSet(
 func (h *runtimeHostHandler) handleHostFetchConsensusBlock(
 	ctx context.Context,
 	rq *protocol.HostFetchConsensusBlockRequest,
+) (*protocol.HostFetchConsensusBlockResponse, error) {
+	return h.cache.LightBlock(rq.Height, func() (*protocol.HostFetchConsensusBlockResponse, error) {
+		return h.fetchConsensusBlock(ctx, rq)
+	})
+}
+
+func (h *runtimeHostHandler) fetchConsensusBlock(
+	ctx context.Context,
+	rq *protocol.HostFetchConsensusBlockRequest,
 ) (*protocol.HostFetchConsensusBlockResponse, error) {
 	blk, err := h.consensus.Core().GetLightBlock(ctx, int64(rq.Height))
 	if err != nil {
@@ -274,6 +285,15 @@ func (h *runtimeHostHandler) handleHostFetchConsensusBlock(
 func (h *runtimeHostHandler) handleHostFetchConsensusEvents(
 	ctx context.Context,
 	rq *protocol.HostFetchConsensusEventsRequest,
+) (*protocol.HostFetchConsensusEventsResponse, error) {
+	return h.cache.Events(rq.Height, rq.Kind, func() (*protocol.HostFetchConsensusEventsResponse, error) {
+		return h.fetchConsensusEvents(ctx, rq)
+	})
+}
+
+func (h *runtimeHostHandler) fetchConsensusEvents(
+	ctx context.Context,
+	rq *protocol.HostFetchConsensusEventsRequest,
 ) (*protocol.HostFetchConsensusEventsResponse, error) {
 	var evs []*consensusResults.Event
 	switch rq.Kind {
@@ -349,6 +369,15 @@ func (h *runtimeHostHandler) handleHostFetchTxBatch(
 func (h *runtimeHostHandler) handleHostFetchBlockMetadataTx(
 	ctx context.Context,
 	rq *protocol.HostFetchBlockMetadataTxRequest,
+) (*protocol.HostFetchBlockMetadataTxResponse, error) {
+	return h.cache.BlockMetadataTx(rq.Height, func() (*protocol.HostFetchBlockMetadataTxResponse, error) {
+		return h.fetchBlockMetadataTx(ctx, rq)
+	})
+}
+
+func (h *runtimeHostHandler) fetchBlockMetadataTx(
+	ctx context.Context,
+	rq *protocol.HostFetchBlockMetadataTxRequest,
 ) (*protocol.HostFetchBlockMetadataTxResponse, error) {
 	tps, err := h.consensus.Core().GetTransactionsWithProofs(ctx, int64(rq.Height))
 	if err != nil {
@@ -1,9 +1,13 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
@@ -14,6 +18,10 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/multi"
 )
 
+// descriptorWaitTimeout is the maximum time to wait for the registry descriptor when verifying
+// runtime components before provisioning them.
+const descriptorWaitTimeout = time.Second
+
 // RuntimeHostNode provides methods for nodes that need to host runtimes.
 type RuntimeHostNode struct {
 	mu sync.Mutex
@@ -25,6 +33,8 @@ type RuntimeHostNode struct {
 	handler     host.RuntimeHandler
 
 	rofls map[component.ID]version.Version
+
+	logger *logging.Logger
 }
 
 // NewRuntimeHostNode creates a new runtime host node.
@@ -37,11 +47,16 @@ func NewRuntimeHostNode(runtime Runtime, provisioner host.Provisioner, handler h
 		handler:     handler,
 		provisioner: provisioner,
 		rofls:       make(map[component.ID]version.Version),
+		logger:      logging.GetLogger("runtime/registry/host").With("runtime_id", runtime.ID()),
 	}, nil
 }
 
 // ProvisionHostedRuntimeComponent provisions the given runtime component.
 func (n *RuntimeHostNode) ProvisionHostedRuntimeComponent(comp *bundle.ExplodedComponent) error {
+	if !n.verifyComponent(comp) {
+		return nil
+	}
+
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
@@ -63,6 +78,13 @@ func (n *RuntimeHostNode) ProvisionHostedRuntimeComponent(comp *bundle.ExplodedC
 		MessageHandler: handler,
 		LocalConfig:    getLocalConfig(n.runtime.ID(), comp.ID()),
 	}
+	if path := getDebugExecutable(n.runtime.ID(), comp.ID()); path != "" {
+		n.logger.Warn("using an UNSAFE local runtime binary with hot reload",
+			"component", comp.ID(),
+			"path", path,
+		)
+		cfg.DebugExecutable = path
+	}
 
 	rt, err := n.provisioner.NewRuntime(cfg)
 	if err != nil {
@@ -136,6 +158,24 @@ func (n *RuntimeHostNode) GetHostedRuntimeCapabilityTEEForVersion(version versio
 	return rt.GetCapabilityTEE()
 }
 
+// GetHostedRuntimeReplicas returns the status of all instances of the active RONL component in
+// case it is backed by multiple runtime instances.
+func (n *RuntimeHostNode) GetHostedRuntimeReplicas() []host.ReplicaStatus {
+	comp, ok := n.host.Component(component.ID_RONL)
+	if !ok {
+		return nil
+	}
+	rt, ok := comp.Component(component.ID_RONL)
+	if !ok {
+		return nil
+	}
+	rr, ok := rt.(host.ReplicatedRuntime)
+	if !ok {
+		return nil
+	}
+	return rr.Replicas()
+}
+
 // SetHostedRuntimeVersion sets the currently active and next versions for the hosted runtime.
 func (n *RuntimeHostNode) SetHostedRuntimeVersion(active *version.Version, next *version.Version) {
 	n.mu.Lock()
@@ -198,6 +238,59 @@ func (n *RuntimeHostNode) createMessageHandler(comp *bundle.ExplodedComponent) (
 	}
 }
 
+// verifyComponent verifies the given component against the runtime's registry descriptor and
+// returns false in case the component should not be provisioned.
+//
+// Components with versions that are not (yet) deployed are still provisioned as the deployment
+// may be added later, but components with enclave identities that can never be attested are not.
+func (n *RuntimeHostNode) verifyComponent(comp *bundle.ExplodedComponent) bool {
+	if !comp.ID().IsRONL() {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), descriptorWaitTimeout)
+	defer cancel()
+	rt, err := n.runtime.RegistryDescriptor(ctx)
+	if err != nil {
+		// Registry descriptor not yet available, nothing to verify against.
+		return true
+	}
+
+	err = bundle.VerifyComponent(comp, rt)
+	var verr *bundle.VerificationError
+	switch {
+	case err == nil:
+		return true
+	case !errors.As(err, &verr):
+		n.logger.Warn("failed to verify runtime component against registry descriptor",
+			"err", err,
+			"id", comp.ID(),
+			"version", comp.Version,
+		)
+		return true
+	}
+
+	for _, m := range verr.Mismatches {
+		if m.Field == "version" {
+			continue
+		}
+		n.logger.Error("refusing to provision runtime component not matching registry descriptor",
+			"err", err,
+			"id", comp.ID(),
+			"version", comp.Version,
+			"mismatches", verr.Mismatches,
+		)
+		return false
+	}
+
+	n.logger.Warn("runtime component version is not deployed",
+		"id", comp.ID(),
+		"version", comp.Version,
+		"mismatches", verr.Mismatches,
+	)
+	return true
+}
+
 func (n *RuntimeHostNode) isComponentWanted(comp *bundle.ExplodedComponent) bool {
 	// Always allow RONL component.
 	if comp.ID().IsRONL() {
@@ -214,8 +307,9 @@ func (n *RuntimeHostNode) isComponentWanted(comp *bundle.ExplodedComponent) bool
 		return true
 	}
 
-	// On non-compute nodes, assume all components are disabled by default.
-	if config.GlobalConfig.Mode != config.ModeCompute {
+	// On non-compute nodes and for read-only runtimes, assume all components are disabled
+	// by default.
+	if config.GlobalConfig.Mode != config.ModeCompute || config.GlobalConfig.Runtime.IsReadOnly(n.runtime.ID()) {
 		return false
 	}
 
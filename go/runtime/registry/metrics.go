@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+var (
+	consensusCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_runtime_host_consensus_cache_requests",
+			Help: "Number of cacheable consensus host requests by cache result.",
+		},
+		[]string{"method", "result"},
+	)
+	consensusCacheSavedTime = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_runtime_host_consensus_cache_saved_time",
+			Help: "Time saved by serving consensus host requests from the cache (seconds).",
+		},
+		[]string{"method"},
+	)
+	consensusCacheInvalidations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_runtime_host_consensus_cache_invalidations",
+			Help: "Number of consensus cache invalidations.",
+		},
+	)
+	consensusRequestDuration = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name: "oasis_runtime_host_consensus_request_duration",
+			Help: "Time to serve consensus host requests (seconds).",
+		},
+		[]string{"method", "result"},
+	)
+	registryCollectors = []prometheus.Collector{
+		consensusCacheRequests,
+		consensusCacheSavedTime,
+		consensusCacheInvalidations,
+		consensusRequestDuration,
+	}
+
+	metricsOnce sync.Once
+)
+
+// initMetrics registers the metrics collectors if metrics are enabled.
+func initMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(registryCollectors...)
+	})
+}
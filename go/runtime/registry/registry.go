@@ -74,6 +74,9 @@ type Registry interface {
 
 	// GetVolumeManager returns the volume manager.
 	GetVolumeManager() *volume.Manager
+
+	// GetConsensusCache returns the consensus cache shared by all hosted runtimes.
+	GetConsensusCache() *ConsensusCache
 }
 
 // Runtime is the running node's supported runtime interface.
@@ -159,7 +162,7 @@ func newRuntime(
 	}
 
 	// Create runtime-specific local storage backend.
-	localStorage, err := localstorage.New(rtDataDir, LocalStorageFile, runtimeID)
+	localStorage, err := localstorage.New(rtDataDir, LocalStorageFile, runtimeID, localstorage.DefaultQuota)
 	if err != nil {
 		return nil, fmt.Errorf("runtime/registry: cannot create local storage for runtime %s: %w", runtimeID, err)
 	}
@@ -412,7 +415,7 @@ func (r *runtime) run(ctx context.Context) {
 				}
 			}
 
-			r.bundleManager.Download(r.id, manifestHashes)
+			r.bundleManager.Download(rt, manifestHashes)
 		}
 	}
 }
@@ -469,6 +472,7 @@ type runtimeRegistry struct {
 	sync.RWMutex
 
 	quitCh chan struct{}
+	cancel context.CancelFunc
 
 	logger *logging.Logger
 
@@ -485,6 +489,7 @@ type runtimeRegistry struct {
 	bundleRegistry *bundle.Registry
 	bundleManager  *bundle.Manager
 	volumeManager  *volume.Manager
+	consensusCache *ConsensusCache
 }
 
 // GetRuntime implements Registry.
@@ -556,7 +561,12 @@ func (r *runtimeRegistry) createRuntime(runtimeID common.Namespace, managed bool
 		r.consensus.Pruner().RegisterHandler(rt.history)
 
 		// Start indexing blocks.
-		indexer := history.NewBlockIndexer(r.consensus, rt.history, config.GlobalConfig.Runtime.Indexer.BatchSize)
+		indexer := history.NewBlockIndexer(
+			r.consensus,
+			rt.history,
+			config.GlobalConfig.Runtime.Indexer.BatchSize,
+			config.GlobalConfig.Runtime.Indexer.NumWorkers,
+		)
 		r.indexers[runtimeID] = indexer
 	}
 
@@ -621,6 +631,11 @@ func (r *runtimeRegistry) GetVolumeManager() *volume.Manager {
 	return r.volumeManager
 }
 
+// GetConsensusCache implements Registry.
+func (r *runtimeRegistry) GetConsensusCache() *ConsensusCache {
+	return r.consensusCache
+}
+
 // Name implements BackgroundService.
 func (r *runtimeRegistry) Name() string {
 	return "runtime registry"
@@ -631,6 +646,10 @@ func (r *runtimeRegistry) Start() error {
 	r.bundleManager.Start()
 	r.volumeManager.Start()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.consensusCache.watch(ctx, r.consensus)
+
 	r.RLock()
 	defer r.RUnlock()
 	for _, rt := range r.runtimes {
@@ -647,6 +666,9 @@ func (r *runtimeRegistry) Start() error {
 func (r *runtimeRegistry) Stop() {
 	r.bundleManager.Stop()
 	r.volumeManager.Stop()
+	if r.cancel != nil {
+		r.cancel()
+	}
 
 	r.RLock()
 	defer r.RUnlock()
@@ -734,6 +756,7 @@ func New(
 		bundleRegistry: bundleRegistry,
 		bundleManager:  bundleManager,
 		volumeManager:  volumeManager,
+		consensusCache: NewConsensusCache(consensusCacheCapacity),
 	}
 
 	// Initialize the runtime registry.
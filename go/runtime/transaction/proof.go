@@ -0,0 +1,109 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// ErrInvalidInclusionProof is the error returned when a transaction inclusion proof is invalid.
+var ErrInvalidInclusionProof = errors.New("transaction: invalid inclusion proof")
+
+// inclusionProofArtifactCount is the maximum number of artifacts covered by an inclusion proof.
+const inclusionProofArtifactCount uint16 = 2
+
+// inclusionProofVersion is the proof version of inclusion proofs. It is pinned so that proofs
+// remain verifiable by all verifiers, including the runtime one, independent of the latest
+// supported proof version.
+const inclusionProofVersion uint16 = 1
+
+// GetInclusionProof generates a Merkle proof of the artifacts of the transaction with the given
+// hash against the IO root of this tree.
+//
+// The proof can be verified by anyone holding the corresponding block header by using
+// VerifyInclusionProof.
+func (t *Tree) GetInclusionProof(ctx context.Context, txHash hash.Hash) (*syncer.Proof, error) {
+	rsp, err := t.tree.SyncGetPrefixes(ctx, &syncer.GetPrefixesRequest{
+		Tree: syncer.TreeID{
+			Root:     t.ioRoot,
+			Position: t.ioRoot.Hash,
+		},
+		Prefixes:     [][]byte{txnKeyFmt.Encode(&txHash)},
+		Limit:        inclusionProofArtifactCount,
+		ProofVersion: inclusionProofVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transaction: failed to generate inclusion proof: %w", err)
+	}
+	return &rsp.Proof, nil
+}
+
+// VerifyInclusionProof verifies that the given transaction, including its output (if any), has
+// been included in the IO root of the given block header.
+//
+// The header must be obtained from a trusted source (e.g., a verified consensus light client)
+// as the proof only binds the transaction artifacts to the header's IO root.
+func VerifyInclusionProof(ctx context.Context, header *block.Header, tx *Transaction, proof *syncer.Proof) error {
+	if header == nil || proof == nil {
+		return fmt.Errorf("%w: missing header or proof", ErrInvalidInclusionProof)
+	}
+	if len(tx.Input) == 0 {
+		return fmt.Errorf("%w: no input artifact given", ErrInvalidInclusionProof)
+	}
+	if header.IORoot.IsEmpty() {
+		return fmt.Errorf("%w: empty IO root", ErrInvalidInclusionProof)
+	}
+
+	var pv syncer.ProofVerifier
+	wl, err := pv.VerifyProofToWriteLog(ctx, header.IORoot, proof)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInclusionProof, err)
+	}
+
+	txHash := tx.Hash()
+	var hasInput, hasOutput bool
+	for _, wle := range wl {
+		var (
+			decHash hash.Hash
+			decKind artifactKind
+		)
+		if !txnKeyFmt.Decode(wle.Key, &decHash, &decKind) || !decHash.Equal(&txHash) {
+			continue
+		}
+
+		switch decKind {
+		case kindInput:
+			var ia inputArtifacts
+			if err = cbor.Unmarshal(wle.Value, &ia); err != nil {
+				return fmt.Errorf("%w: malformed input artifacts: %w", ErrInvalidInclusionProof, err)
+			}
+			if !bytes.Equal(ia.Input, tx.Input) || ia.BatchOrder != tx.BatchOrder {
+				return fmt.Errorf("%w: input artifacts mismatch", ErrInvalidInclusionProof)
+			}
+			hasInput = true
+		case kindOutput:
+			var oa outputArtifacts
+			if err = cbor.Unmarshal(wle.Value, &oa); err != nil {
+				return fmt.Errorf("%w: malformed output artifacts: %w", ErrInvalidInclusionProof, err)
+			}
+			if !bytes.Equal(oa.Output, tx.Output) {
+				return fmt.Errorf("%w: output artifacts mismatch", ErrInvalidInclusionProof)
+			}
+			hasOutput = true
+		}
+	}
+	if !hasInput {
+		return fmt.Errorf("%w: input artifacts not included", ErrInvalidInclusionProof)
+	}
+	if !hasOutput && len(tx.Output) > 0 {
+		return fmt.Errorf("%w: output artifacts not included", ErrInvalidInclusionProof)
+	}
+
+	return nil
+}
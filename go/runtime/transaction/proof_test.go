@@ -0,0 +1,115 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+func TestInclusionProof(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	store := mkvs.New(nil, nil, node.RootTypeIO)
+
+	var root node.Root
+	root.Type = node.RootTypeIO
+	root.Empty()
+
+	// Prepare transaction tree.
+	tree := NewTree(store, root)
+	testTxns := populateTransactions(t, tree)
+	noOutputTx := Transaction{
+		Input:      []byte("this goes in but nothing comes out"),
+		BatchOrder: 100,
+	}
+	err := tree.AddTransaction(ctx, noOutputTx, nil)
+	require.NoError(err, "AddTransaction")
+
+	writeLog, rootHash, err := tree.Commit(ctx)
+	require.NoError(err, "Commit")
+	err = store.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog))
+	require.NoError(err, "ApplyWriteLog")
+	_, _, err = store.Commit(ctx, root.Namespace, root.Version)
+	require.NoError(err, "Commit")
+
+	root.Hash = rootHash
+	tree = NewTree(store, root)
+	defer tree.Close()
+
+	header := &block.Header{IORoot: rootHash}
+
+	tx := testTxns[5]
+	proof, err := tree.GetInclusionProof(ctx, tx.Hash())
+	require.NoError(err, "GetInclusionProof")
+	require.EqualValues(inclusionProofVersion, proof.V, "inclusion proofs should use the pinned proof version")
+
+	err = VerifyInclusionProof(ctx, header, &tx, proof)
+	require.NoError(err, "VerifyInclusionProof should succeed for a valid proof")
+
+	// Tampered output.
+	tampered := tx
+	tampered.Output = []byte("and this does not come out")
+	err = VerifyInclusionProof(ctx, header, &tampered, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with tampered output")
+
+	// Missing output.
+	tampered = tx
+	tampered.Output = nil
+	err = VerifyInclusionProof(ctx, header, &tampered, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with missing output")
+
+	// Tampered batch order.
+	tampered = tx
+	tampered.BatchOrder++
+	err = VerifyInclusionProof(ctx, header, &tampered, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with tampered batch order")
+
+	// Different transaction.
+	other := testTxns[6]
+	err = VerifyInclusionProof(ctx, header, &other, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail for a different transaction")
+
+	// Wrong IO root.
+	err = VerifyInclusionProof(ctx, &block.Header{IORoot: hash.NewFromBytes([]byte("wrong"))}, &tx, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with wrong IO root")
+
+	// Tampered proof.
+	badProof := *proof
+	badProof.Entries = append([][]byte{}, proof.Entries...)
+	for i, entry := range badProof.Entries {
+		if len(entry) == 0 {
+			continue
+		}
+		tamperedEntry := append([]byte{}, entry...)
+		tamperedEntry[len(tamperedEntry)-1] ^= 0xff
+		badProof.Entries[i] = tamperedEntry
+	}
+	err = VerifyInclusionProof(ctx, header, &tx, &badProof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with tampered proof")
+
+	// Transactions without an output.
+	proof, err = tree.GetInclusionProof(ctx, noOutputTx.Hash())
+	require.NoError(err, "GetInclusionProof")
+	err = VerifyInclusionProof(ctx, header, &noOutputTx, proof)
+	require.NoError(err, "VerifyInclusionProof should succeed for a transaction without output")
+
+	tampered = noOutputTx
+	tampered.Output = []byte("forged output")
+	err = VerifyInclusionProof(ctx, header, &tampered, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail with forged output")
+
+	// Missing transaction.
+	missing := Transaction{Input: []byte("this never went in")}
+	proof, err = tree.GetInclusionProof(ctx, missing.Hash())
+	require.NoError(err, "GetInclusionProof")
+	err = VerifyInclusionProof(ctx, header, &missing, proof)
+	require.ErrorIs(err, ErrInvalidInclusionProof, "VerifyInclusionProof should fail for missing transaction")
+}
@@ -30,6 +30,27 @@ func (cq *checkTxQueue) add(pct *PendingCheckTransaction) error {
 	return nil
 }
 
+// addBatch adds as many of the given transactions as there is room for in the queue and returns
+// the number of transactions that were added.
+func (cq *checkTxQueue) addBatch(pcts []*PendingCheckTransaction) int {
+	cq.l.Lock()
+	defer cq.l.Unlock()
+
+	room := cq.maxSize - cq.txs.Len()
+	if room <= 0 {
+		return 0
+	}
+	if room > len(pcts) {
+		room = len(pcts)
+	}
+
+	for _, pct := range pcts[:room] {
+		cq.txs.PushBack(pct)
+	}
+
+	return room
+}
+
 func (cq *checkTxQueue) retryBatch(pcts []*PendingCheckTransaction) {
 	cq.l.Lock()
 	defer cq.l.Unlock()
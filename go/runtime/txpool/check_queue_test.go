@@ -62,3 +62,29 @@ func TestCheckTxQueuePop(t *testing.T) {
 	require.EqualValues(t, 1, len(batch), "Batch size")
 	require.EqualValues(t, 0, queue.size(), "Size")
 }
+
+func TestCheckTxQueueAddBatch(t *testing.T) {
+	queue := newCheckTxQueue(10, 4)
+
+	err := queue.add(newPendingTx([]byte("hello world")))
+	require.NoError(t, err, "Add")
+
+	var batch []*PendingCheckTransaction
+	for i := 0; i < 12; i++ {
+		batch = append(batch, newPendingTx([]byte(fmt.Sprintf("call %d", i))))
+	}
+
+	added := queue.addBatch(batch)
+	require.EqualValues(t, 9, added, "Added batch size")
+	require.EqualValues(t, 10, queue.size(), "Size")
+
+	added = queue.addBatch(batch[added:])
+	require.EqualValues(t, 0, added, "Added batch size on queue full")
+
+	popped := queue.pop()
+	require.EqualValues(t, 4, len(popped), "Batch size")
+	require.EqualValues(t, popped[0].Raw(), []byte("hello world"))
+	for i := 0; i < 3; i++ {
+		require.EqualValues(t, popped[i+1].Raw(), []byte(fmt.Sprintf("call %d", i)))
+	}
+}
@@ -1,7 +1,10 @@
 // Package config implements the txpool configuration options.
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Config is the runtime transaction pool configuration structure.
 type Config struct {
@@ -11,8 +14,107 @@ type Config struct {
 	MaxLastSeenCacheSize uint64 `yaml:"schedule_tx_cache_size"`
 	// Maximum check tx batch size.
 	MaxCheckTxBatchSize uint64 `yaml:"check_tx_max_batch_size"`
-	// Transaction recheck interval (in rounds).
+	// Transaction recheck interval (in rounds). Only used when the recheck sweep is disabled.
 	RecheckInterval uint64 `yaml:"recheck_interval"`
+	// Number of transactions rechecked per round by the background recheck sweep. Transactions
+	// whose senders had transactions executed are always rechecked after each round. Zero
+	// disables incremental rechecking and all transactions are rechecked every RecheckInterval
+	// rounds instead.
+	RecheckSweepRate uint64 `yaml:"recheck_sweep_rate"`
 	// Republish interval.
 	RepublishInterval time.Duration
+	// Handling of transactions that repeatedly fail execution.
+	ExecutionFailures ExecutionFailureConfig `yaml:"execution_failures"`
+	// Priority lanes with reserved batch capacity.
+	Lanes []LaneConfig `yaml:"lanes,omitempty"`
+	// Persistence of pending transactions across node restarts.
+	Persistence PersistenceConfig `yaml:"persistence"`
+}
+
+// PersistenceConfig is the configuration of persisting schedulable transactions so that they
+// survive node restarts.
+type PersistenceConfig struct {
+	// Disabled disables persisting schedulable transactions.
+	Disabled bool `yaml:"disabled"`
+	// MaxTxs is the maximum number of persisted transactions. Zero disables persistence.
+	MaxTxs uint64 `yaml:"max_txs"`
+	// MaxBytes is the maximum total size of persisted transactions (in bytes). Zero disables
+	// persistence.
+	MaxBytes uint64 `yaml:"max_bytes"`
+}
+
+// Enabled returns true iff schedulable transactions should be persisted.
+func (c *PersistenceConfig) Enabled() bool {
+	return !c.Disabled && c.MaxTxs > 0 && c.MaxBytes > 0
+}
+
+// ExecutionFailureConfig is the configuration of handling transactions that repeatedly fail
+// execution (e.g., make the runtime panic) even though they pass transaction checks.
+type ExecutionFailureConfig struct {
+	// MaxFailures is the number of failed execution attempts of a transaction in isolation after
+	// which the transaction is removed from the pool and denylisted. Zero disables denylisting.
+	MaxFailures uint64 `yaml:"max_failures"`
+	// DenylistDuration is the duration for which a denylisted transaction is rejected.
+	DenylistDuration time.Duration `yaml:"denylist_duration"`
+	// SlowThreshold is the execution time reported by the runtime above which a transaction is
+	// considered slow. Zero disables tracking of slow transactions. Only runtimes supporting
+	// reporting of transaction execution times are affected.
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+	// MaxSlowExecutions is the number of slow executions of a transaction after which the
+	// transaction is removed from the pool and denylisted. Zero disables denylisting of slow
+	// transactions.
+	MaxSlowExecutions uint64 `yaml:"max_slow_executions"`
+}
+
+// Validate validates the configuration settings.
+func (c *ExecutionFailureConfig) Validate() error {
+	if c.MaxFailures > 0 && c.DenylistDuration <= 0 {
+		return fmt.Errorf("denylist_duration must be positive when max_failures is set")
+	}
+	if c.SlowThreshold < 0 {
+		return fmt.Errorf("slow_threshold must not be negative")
+	}
+	if c.MaxSlowExecutions > 0 {
+		if c.SlowThreshold == 0 {
+			return fmt.Errorf("slow_threshold must be set when max_slow_executions is set")
+		}
+		if c.DenylistDuration <= 0 {
+			return fmt.Errorf("denylist_duration must be positive when max_slow_executions is set")
+		}
+	}
+	return nil
+}
+
+// LaneConfig is the configuration of a priority lane of the schedulable transaction queue.
+//
+// Each transaction belongs to the lane with the highest minimum priority not exceeding the
+// priority returned by the runtime in CheckTx. Transactions with a priority below the minimum
+// priority of all lanes do not belong to any lane.
+type LaneConfig struct {
+	// MinPriority is the minimum priority of transactions in the lane.
+	MinPriority uint64 `yaml:"min_priority" json:"min_priority"`
+	// ReservedCapacity is the percentage of each proposed batch reserved for transactions in the
+	// lane. Capacity not used by the lane is available to other transactions.
+	ReservedCapacity uint8 `yaml:"reserved_capacity" json:"reserved_capacity"`
+}
+
+// ValidateLanes validates the given priority lane configuration.
+func ValidateLanes(lanes []LaneConfig) error {
+	var total uint64
+	seen := make(map[uint64]struct{}, len(lanes))
+	for _, lane := range lanes {
+		if _, ok := seen[lane.MinPriority]; ok {
+			return fmt.Errorf("duplicate lane with min_priority %d", lane.MinPriority)
+		}
+		seen[lane.MinPriority] = struct{}{}
+
+		if lane.ReservedCapacity == 0 || lane.ReservedCapacity > 100 {
+			return fmt.Errorf("lane with min_priority %d: reserved_capacity must be between 1 and 100", lane.MinPriority)
+		}
+		total += uint64(lane.ReservedCapacity)
+	}
+	if total > 100 {
+		return fmt.Errorf("total reserved_capacity of all lanes must not exceed 100")
+	}
+	return nil
 }
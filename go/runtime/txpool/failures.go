@@ -0,0 +1,261 @@
+package txpool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+const moduleName = "txpool"
+
+// ErrTxDenylisted is the error returned when a transaction is rejected as it has repeatedly failed
+// execution.
+var ErrTxDenylisted = errors.New(moduleName, 2, "txpool: transaction denylisted after repeated execution failures")
+
+// failureTracker keeps track of transactions that were part of batches which failed execution.
+//
+// As the runtime does not report which transaction caused a batch to fail, all transactions in
+// a failed batch become suspects. Suspects are then scheduled separately from other transactions,
+// halving the scheduled set on each failure, until a failing transaction is executed on its own.
+// Only failures in isolation count towards denylisting, so transactions that merely shared a batch
+// with a failing transaction are never denylisted.
+type failureTracker struct {
+	sync.Mutex
+
+	cfg config.ExecutionFailureConfig
+
+	// suspects maps hashes of transactions included in failed batches to the number of times
+	// they failed in isolation.
+	suspects map[hash.Hash]uint64
+	// suspectsSince maps hashes of suspects to the time they were last part of a failed batch.
+	suspectsSince map[hash.Hash]time.Time
+	// denylist maps hashes of denylisted transactions to the time their denylisting expires.
+	denylist map[hash.Hash]time.Time
+
+	// slow maps hashes of transactions that were reported as slow to the number of times they
+	// were reported as such.
+	slow map[hash.Hash]uint64
+	// slowSince maps hashes of slow transactions to the time they were last reported as slow.
+	slowSince map[hash.Hash]time.Time
+
+	// isolating is true while the current scheduling session only schedules suspects.
+	isolating bool
+	// scheduled are the transactions handed out during the current scheduling session.
+	scheduled []hash.Hash
+}
+
+func newFailureTracker(cfg config.ExecutionFailureConfig) *failureTracker {
+	return &failureTracker{
+		cfg:           cfg,
+		suspects:      make(map[hash.Hash]uint64),
+		suspectsSince: make(map[hash.Hash]time.Time),
+		denylist:      make(map[hash.Hash]time.Time),
+		slow:          make(map[hash.Hash]uint64),
+		slowSince:     make(map[hash.Hash]time.Time),
+	}
+}
+
+func (ft *failureTracker) enabled() bool {
+	return ft.cfg.MaxFailures > 0
+}
+
+// isDenylisted returns true iff the given transaction is currently denylisted.
+func (ft *failureTracker) isDenylisted(h hash.Hash) bool {
+	ft.Lock()
+	defer ft.Unlock()
+
+	expires, ok := ft.denylist[h]
+	return ok && time.Now().Before(expires)
+}
+
+// startScheduling starts a scheduling session with the given suggested transactions and returns
+// the transactions that should actually be scheduled.
+func (ft *failureTracker) startScheduling(txs []*TxQueueMeta) []*TxQueueMeta {
+	ft.Lock()
+	defer ft.Unlock()
+
+	ft.isolating = false
+	ft.scheduled = ft.scheduled[:0]
+
+	var suspects []*TxQueueMeta
+	for _, tx := range txs {
+		if _, ok := ft.suspects[tx.Hash()]; ok {
+			suspects = append(suspects, tx)
+		}
+	}
+	if len(suspects) > 0 {
+		ft.isolating = true
+		txs = suspects[:(len(suspects)+1)/2]
+	}
+
+	for _, tx := range txs {
+		ft.scheduled = append(ft.scheduled, tx.Hash())
+	}
+	return txs
+}
+
+// addScheduled records extra transactions handed out during the current scheduling session and
+// returns the transactions that should actually be scheduled.
+func (ft *failureTracker) addScheduled(txs []*TxQueueMeta) []*TxQueueMeta {
+	ft.Lock()
+	defer ft.Unlock()
+
+	if ft.isolating {
+		// Do not mix suspects with other transactions.
+		return nil
+	}
+	for _, tx := range txs {
+		ft.scheduled = append(ft.scheduled, tx.Hash())
+	}
+	return txs
+}
+
+// scheduledTxs returns the transactions handed out during the current scheduling session.
+func (ft *failureTracker) scheduledTxs() []hash.Hash {
+	ft.Lock()
+	defer ft.Unlock()
+
+	return append([]hash.Hash{}, ft.scheduled...)
+}
+
+// failed records that a batch consisting of the given transactions failed execution and returns
+// the transactions that have been denylisted as a result.
+func (ft *failureTracker) failed(txs []hash.Hash) []hash.Hash {
+	ft.Lock()
+	defer ft.Unlock()
+
+	now := time.Now()
+	ft.pruneLocked(now)
+
+	if len(txs) == 0 {
+		return nil
+	}
+
+	if len(txs) == 1 {
+		h := txs[0]
+		ft.suspects[h]++
+		ft.suspectsSince[h] = now
+		if ft.suspects[h] < ft.cfg.MaxFailures {
+			return nil
+		}
+
+		ft.removeLocked(h)
+		ft.denylist[h] = now.Add(ft.cfg.DenylistDuration)
+		return txs
+	}
+
+	// If only suspects failed, the failing transaction must be among them so the remaining
+	// suspects can be cleared.
+	allSuspects := true
+	for _, h := range txs {
+		if _, ok := ft.suspects[h]; !ok {
+			allSuspects = false
+			break
+		}
+	}
+	if allSuspects {
+		failed := make(map[hash.Hash]struct{}, len(txs))
+		for _, h := range txs {
+			failed[h] = struct{}{}
+		}
+		for h := range ft.suspects {
+			if _, ok := failed[h]; !ok {
+				ft.removeSuspectLocked(h)
+			}
+		}
+	}
+
+	for _, h := range txs {
+		if _, ok := ft.suspects[h]; !ok {
+			ft.suspects[h] = 0
+		}
+		ft.suspectsSince[h] = now
+	}
+	return nil
+}
+
+// slowEnabled returns true iff repeatedly slow transactions should be denylisted.
+func (ft *failureTracker) slowEnabled() bool {
+	return ft.cfg.MaxSlowExecutions > 0
+}
+
+// slowExecuted records that the given transactions were reported as slow and returns the
+// transactions that have been denylisted as a result.
+func (ft *failureTracker) slowExecuted(txs []hash.Hash) []hash.Hash {
+	ft.Lock()
+	defer ft.Unlock()
+
+	now := time.Now()
+	ft.pruneLocked(now)
+
+	var denylisted []hash.Hash
+	for _, h := range txs {
+		ft.slow[h]++
+		ft.slowSince[h] = now
+		if ft.slow[h] < ft.cfg.MaxSlowExecutions {
+			continue
+		}
+
+		ft.removeLocked(h)
+		ft.denylist[h] = now.Add(ft.cfg.DenylistDuration)
+		denylisted = append(denylisted, h)
+	}
+	return denylisted
+}
+
+// remove clears any suspicion of the given transactions.
+func (ft *failureTracker) remove(txs []hash.Hash) {
+	ft.Lock()
+	defer ft.Unlock()
+
+	for _, h := range txs {
+		ft.removeLocked(h)
+	}
+}
+
+func (ft *failureTracker) removeLocked(h hash.Hash) {
+	ft.removeSuspectLocked(h)
+	ft.removeSlowLocked(h)
+}
+
+func (ft *failureTracker) removeSuspectLocked(h hash.Hash) {
+	delete(ft.suspects, h)
+	delete(ft.suspectsSince, h)
+}
+
+func (ft *failureTracker) removeSlowLocked(h hash.Hash) {
+	delete(ft.slow, h)
+	delete(ft.slowSince, h)
+}
+
+// pruneLocked removes expired denylist entries and suspects that have not failed or been slow for
+// a while (e.g., because they have been evicted from the pool).
+func (ft *failureTracker) pruneLocked(now time.Time) {
+	for h, expires := range ft.denylist {
+		if !now.Before(expires) {
+			delete(ft.denylist, h)
+		}
+	}
+	for h, since := range ft.suspectsSince {
+		if now.Sub(since) >= ft.cfg.DenylistDuration {
+			ft.removeSuspectLocked(h)
+		}
+	}
+	for h, since := range ft.slowSince {
+		if now.Sub(since) >= ft.cfg.DenylistDuration {
+			ft.removeSlowLocked(h)
+		}
+	}
+}
+
+// counts returns the number of suspects and the number of denylisted transactions.
+func (ft *failureTracker) counts() (int, int) {
+	ft.Lock()
+	defer ft.Unlock()
+
+	ft.pruneLocked(time.Now())
+	return len(ft.suspects), len(ft.denylist)
+}
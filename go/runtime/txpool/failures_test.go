@@ -0,0 +1,111 @@
+package txpool
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestFailureTracker(t *testing.T) {
+	require := require.New(t)
+
+	ft := newFailureTracker(config.ExecutionFailureConfig{
+		MaxFailures:      2,
+		DenylistDuration: time.Hour,
+	})
+
+	var txs []*TxQueueMeta
+	for i := range 8 {
+		raw := []byte(fmt.Sprintf("tx %d", i))
+		txs = append(txs, &TxQueueMeta{raw: raw, hash: hash.NewFromBytes(raw)})
+	}
+	extra := &TxQueueMeta{raw: []byte("extra"), hash: hash.NewFromBytes([]byte("extra"))}
+	culprit := txs[5].Hash()
+
+	// Simulate the executor repeatedly scheduling all transactions, where the batch fails
+	// whenever it includes the culprit.
+	var denylisted []hash.Hash
+	for round := 0; len(denylisted) == 0; round++ {
+		require.Less(round, 20, "culprit should be denylisted")
+
+		scheduled := ft.startScheduling(txs)
+		_ = ft.addScheduled([]*TxQueueMeta{extra})
+
+		var included bool
+		for _, tx := range scheduled {
+			included = included || tx.Hash() == culprit
+		}
+		if !included {
+			// Successfully executed transactions are removed from the pool.
+			ft.remove(ft.scheduledTxs())
+			txs = txs[len(scheduled):]
+			continue
+		}
+		denylisted = ft.failed(ft.scheduledTxs())
+	}
+	require.Equal([]hash.Hash{culprit}, denylisted)
+	require.True(ft.isDenylisted(culprit))
+
+	// No other transaction should have been denylisted.
+	numSuspects, numDenylisted := ft.counts()
+	require.Equal(1, numDenylisted)
+	for _, tx := range txs {
+		if tx.Hash() != culprit {
+			require.False(ft.isDenylisted(tx.Hash()))
+		}
+	}
+	require.False(ft.isDenylisted(extra.Hash()))
+	require.LessOrEqual(numSuspects, len(txs))
+
+	// Extra transactions must not be mixed with suspects.
+	ft.failed([]hash.Hash{txs[len(txs)-1].Hash(), extra.Hash()})
+	scheduled := ft.startScheduling(append(txs, extra))
+	require.NotEmpty(scheduled)
+	require.Nil(ft.addScheduled([]*TxQueueMeta{extra}))
+
+	// Denylisting expires.
+	ft.denylist[culprit] = time.Now().Add(-time.Second)
+	require.False(ft.isDenylisted(culprit))
+	_, numDenylisted = ft.counts()
+	require.Equal(0, numDenylisted)
+}
+
+func TestFailureTrackerSlow(t *testing.T) {
+	require := require.New(t)
+
+	ft := newFailureTracker(config.ExecutionFailureConfig{
+		MaxFailures:       3,
+		DenylistDuration:  time.Hour,
+		SlowThreshold:     time.Second,
+		MaxSlowExecutions: 2,
+	})
+	require.True(ft.slowEnabled())
+
+	slow := hash.NewFromBytes([]byte("slow"))
+	used := hash.NewFromBytes([]byte("used"))
+
+	// The first slow execution is tolerated.
+	require.Empty(ft.slowExecuted([]hash.Hash{slow, used}))
+	require.False(ft.isDenylisted(slow))
+
+	// Transactions included in a block are forgotten.
+	ft.remove([]hash.Hash{used})
+	require.Empty(ft.slowExecuted([]hash.Hash{used}))
+
+	// Clearing suspects must not affect slow transactions.
+	other := hash.NewFromBytes([]byte("other"))
+	ft.failed([]hash.Hash{slow, used, other})
+	ft.failed([]hash.Hash{used, other})
+	numSuspects, _ := ft.counts()
+	require.Equal(2, numSuspects)
+
+	// Repeatedly slow transactions are denylisted.
+	require.Equal([]hash.Hash{slow}, ft.slowExecuted([]hash.Hash{slow}))
+	require.True(ft.isDenylisted(slow))
+	require.False(ft.isDenylisted(used))
+}
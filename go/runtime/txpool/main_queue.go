@@ -5,6 +5,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 var (
@@ -27,6 +28,10 @@ type MainQueueTransaction struct {
 	// senderStateSeq is the current (as of when the check was performed) sequence number of the
 	// sender stored in runtime state.
 	senderStateSeq uint64
+
+	// expiryRound is the round at which the transaction expires as specified by the runtime. Zero
+	// means that the transaction does not expire.
+	expiryRound uint64
 }
 
 func newTransaction(tx TxQueueMeta) *MainQueueTransaction {
@@ -55,6 +60,16 @@ func (tx *MainQueueTransaction) SenderSeq() uint64 {
 	return tx.senderSeq
 }
 
+// ExpiryRound returns the round at which the transaction expires or zero if it does not expire.
+func (tx *MainQueueTransaction) ExpiryRound() uint64 {
+	return tx.expiryRound
+}
+
+// isExpired returns true iff the transaction cannot be included in a batch for the given round.
+func (tx *MainQueueTransaction) isExpired(round uint64) bool {
+	return tx.expiryRound != 0 && tx.expiryRound <= round
+}
+
 // setChecked populates transaction data retrieved from checks.
 func (tx *MainQueueTransaction) setChecked(meta *protocol.CheckTxMetadata) {
 	if meta != nil {
@@ -62,6 +77,7 @@ func (tx *MainQueueTransaction) setChecked(meta *protocol.CheckTxMetadata) {
 		tx.sender = string(meta.Sender)
 		tx.senderSeq = meta.SenderSeq
 		tx.senderStateSeq = meta.SenderStateSeq
+		tx.expiryRound = meta.ExpiryRound
 	}
 
 	// If the sender is empty (e.g. because the runtime does not support specifying a sender), we
@@ -84,6 +100,11 @@ func newMainQueue(capacity int) *mainQueue {
 	}
 }
 
+// setLanes configures the priority lanes used when suggesting transactions for scheduling.
+func (mq *mainQueue) setLanes(lanes []config.LaneConfig) {
+	mq.inner.setLanes(lanes)
+}
+
 func (mq *mainQueue) GetSchedulingSuggestion(countHint uint32) []*TxQueueMeta {
 	txMetas := mq.inner.getPrioritizedBatch(nil, countHint)
 	txs := make([]*TxQueueMeta, 0, len(txMetas))
@@ -133,6 +154,23 @@ func (mq *mainQueue) TakeAll() []*TxQueueMeta {
 	return txs
 }
 
+// takeBySender removes and returns all transactions from the given senders.
+func (mq *mainQueue) takeBySender(senders []string) []*TxQueueMeta {
+	return toTxQueueMetas(mq.inner.takeBySender(senders))
+}
+
+// takeLeastRecentlyChecked removes and returns up to limit transactions that passed checks the
+// longest time ago.
+func (mq *mainQueue) takeLeastRecentlyChecked(limit int) []*TxQueueMeta {
+	return toTxQueueMetas(mq.inner.takeLeastRecentlyChecked(limit))
+}
+
+// takeExpired sets the round the next batch is scheduled for and removes and returns all
+// transactions that expire before they could be included in it.
+func (mq *mainQueue) takeExpired(round uint64) []*TxQueueMeta {
+	return toTxQueueMetas(mq.inner.takeExpired(round))
+}
+
 func (mq *mainQueue) OfferChecked(tx *TxQueueMeta, meta *protocol.CheckTxMetadata) error {
 	txMeta := newTransaction(*tx)
 	txMeta.setChecked(meta)
@@ -143,3 +181,11 @@ func (mq *mainQueue) OfferChecked(tx *TxQueueMeta, meta *protocol.CheckTxMetadat
 func (mq *mainQueue) GetTxsToPublish() []*TxQueueMeta {
 	return mq.PeekAll()
 }
+
+func toTxQueueMetas(txMetas []*MainQueueTransaction) []*TxQueueMeta {
+	txs := make([]*TxQueueMeta, 0, len(txMetas))
+	for _, txMeta := range txMetas {
+		txs = append(txs, &txMeta.TxQueueMeta) //nolint:gosec
+	}
+	return txs
+}
@@ -49,6 +49,77 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	recheckedTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_rechecked_transactions",
+			Help: "Number of transactions submitted for rechecking.",
+		},
+		[]string{"runtime", "kind"},
+	)
+	schedulingLatency = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "oasis_txpool_scheduling_latency",
+			Help:       "Time from a transaction passing checks to its inclusion in a proposed batch (seconds).",
+			Objectives: map[float64]float64{0.5: 0.05, 0.95: 0.01},
+		},
+		[]string{"runtime"},
+	)
+	suspectTransactions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_txpool_suspect_transactions",
+			Help: "Number of transactions suspected of failing execution.",
+		},
+		[]string{"runtime"},
+	)
+	denylistedTransactions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_txpool_denylisted_transactions",
+			Help: "Number of transactions denylisted after repeatedly failing or being slow to execute.",
+		},
+		[]string{"runtime"},
+	)
+	denylistedRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_denylisted_rejections",
+			Help: "Number of submitted transactions rejected as denylisted.",
+		},
+		[]string{"runtime"},
+	)
+	slowTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_slow_transactions",
+			Help: "Number of transaction executions reported by the runtime as exceeding the slow threshold.",
+		},
+		[]string{"runtime"},
+	)
+	persistedTransactions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_txpool_persisted_transactions",
+			Help: "Number of schedulable transactions persisted across node restarts.",
+		},
+		[]string{"runtime"},
+	)
+	restoredTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_restored_transactions",
+			Help: "Number of persisted transactions re-admitted after a node restart.",
+		},
+		[]string{"runtime"},
+	)
+	discardedRestoredTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_restore_discarded_transactions",
+			Help: "Number of persisted transactions discarded after a node restart (failing check tx).",
+		},
+		[]string{"runtime"},
+	)
+	expiredTransactions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_txpool_expired_transactions",
+			Help: "Number of transactions evicted after reaching their runtime-declared expiry round.",
+		},
+		[]string{"runtime"},
+	)
 	txpoolCollectors = []prometheus.Collector{
 		pendingCheckSize,
 		mainQueueSize,
@@ -56,6 +127,16 @@ var (
 		rimQueueSize,
 		rejectedTransactions,
 		acceptedTransactions,
+		recheckedTransactions,
+		schedulingLatency,
+		suspectTransactions,
+		denylistedTransactions,
+		denylistedRejections,
+		slowTransactions,
+		persistedTransactions,
+		restoredTransactions,
+		discardedRestoredTransactions,
+		expiredTransactions,
 	}
 
 	metricsOnce sync.Once
@@ -0,0 +1,194 @@
+package txpool
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+// persistentStoreName is the name of the persistent store namespace prefix used for storing
+// schedulable transactions.
+const persistentStoreName = "runtime/txpool"
+
+// persistedTx is a schedulable transaction persisted across node restarts.
+type persistedTx struct {
+	// Tx is the raw transaction.
+	Tx []byte `json:"tx"`
+	// Local is a flag indicating that the transaction was obtained from a local client.
+	Local bool `json:"local,omitempty"`
+}
+
+type persistedTxEntry struct {
+	size uint64
+	seq  uint64
+}
+
+// txStore persists schedulable transactions so they can be restored after a node restart.
+//
+// Transactions are written before they are admitted into the schedulable queues. Removals are
+// done lazily by periodically pruning transactions that are no longer queued, as any stale
+// transactions are discarded during restore when they are checked again.
+type txStore struct {
+	sync.Mutex
+
+	store *persistent.TypedStore[persistedTx]
+	cfg   config.PersistenceConfig
+
+	entries map[hash.Hash]persistedTxEntry
+	size    uint64
+	seq     uint64
+}
+
+// load loads all persisted transactions and initializes the in-memory index.
+func (s *txStore) load() ([]*persistedTx, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var txs []*persistedTx
+	err := s.store.Iterate(func(key []byte, value *persistedTx) error {
+		var h hash.Hash
+		if err := h.UnmarshalBinary(key); err != nil {
+			return fmt.Errorf("malformed transaction hash: %w", err)
+		}
+		s.track(h, uint64(len(value.Tx)))
+		txs = append(txs, value)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load persisted transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// add persists the given transactions. Transactions that do not fit into the configured limits
+// are skipped and their count is returned.
+func (s *txStore) add(txs []*PendingCheckTransaction, isLocal func(*PendingCheckTransaction) bool) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var (
+		skipped int
+		keys    [][]byte
+		values  []*persistedTx
+	)
+	for _, pct := range txs {
+		h := pct.Hash()
+		if _, ok := s.entries[h]; ok {
+			continue
+		}
+		size := uint64(len(pct.Raw()))
+		if uint64(len(s.entries)) >= s.cfg.MaxTxs || s.size+size > s.cfg.MaxBytes {
+			skipped++
+			continue
+		}
+		s.track(h, size)
+
+		keys = append(keys, h[:])
+		values = append(values, &persistedTx{
+			Tx:    pct.Raw(),
+			Local: isLocal(pct),
+		})
+	}
+	if len(keys) == 0 {
+		return skipped, nil
+	}
+	if err := s.store.PutMany(keys, values); err != nil {
+		for _, key := range keys {
+			s.untrack(hash.Hash(key))
+		}
+		return skipped, fmt.Errorf("failed to persist transactions: %w", err)
+	}
+	return skipped, nil
+}
+
+// remove removes the given transactions from the store.
+func (s *txStore) remove(hashes []hash.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.removeLocked(hashes)
+}
+
+func (s *txStore) removeLocked(hashes []hash.Hash) error {
+	keys := make([][]byte, 0, len(hashes))
+	for _, h := range hashes {
+		if _, ok := s.entries[h]; !ok {
+			continue
+		}
+		s.untrack(h)
+		keys = append(keys, h[:])
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := s.store.DeleteMany(keys); err != nil {
+		return fmt.Errorf("failed to remove persisted transactions: %w", err)
+	}
+	return nil
+}
+
+// checkpoint returns a sequence number that can later be passed to prune.
+func (s *txStore) checkpoint() uint64 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.seq
+}
+
+// prune removes all transactions persisted before the given checkpoint that are not in the
+// given set of queued transactions. It returns the number of removed transactions.
+func (s *txStore) prune(queued map[hash.Hash]struct{}, checkpoint uint64) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var stale []hash.Hash
+	for h, entry := range s.entries {
+		if entry.seq >= checkpoint {
+			continue
+		}
+		if _, ok := queued[h]; ok {
+			continue
+		}
+		stale = append(stale, h)
+	}
+	return len(stale), s.removeLocked(stale)
+}
+
+// count returns the number of persisted transactions.
+func (s *txStore) count() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return len(s.entries)
+}
+
+func (s *txStore) track(h hash.Hash, size uint64) {
+	s.entries[h] = persistedTxEntry{
+		size: size,
+		seq:  s.seq,
+	}
+	s.size += size
+	s.seq++
+}
+
+func (s *txStore) untrack(h hash.Hash) {
+	entry, ok := s.entries[h]
+	if !ok {
+		return
+	}
+	delete(s.entries, h)
+	s.size -= entry.size
+}
+
+// newTxStore creates a new store for persisting schedulable transactions of the given runtime.
+func newTxStore(cs *persistent.CommonStore, runtimeID common.Namespace, cfg config.PersistenceConfig) *txStore {
+	return &txStore{
+		store:   persistent.NewTypedStore[persistedTx](cs, persistentStoreName+"/"+runtimeID.String()),
+		cfg:     cfg,
+		entries: make(map[hash.Hash]persistedTxEntry),
+	}
+}
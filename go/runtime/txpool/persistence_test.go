@@ -0,0 +1,123 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+func TestTxStore(t *testing.T) {
+	require := require.New(t)
+
+	cs, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer cs.Close()
+
+	cfg := config.PersistenceConfig{
+		MaxTxs:   3,
+		MaxBytes: 10,
+	}
+	isLocal := func(pct *PendingCheckTransaction) bool {
+		return string(pct.Raw()) == "tx 0"
+	}
+
+	store := newTxStore(cs, common.Namespace{}, cfg)
+	txs, err := store.load()
+	require.NoError(err, "load")
+	require.Empty(txs, "store should initially be empty")
+
+	skipped, err := store.add([]*PendingCheckTransaction{
+		newPendingTx([]byte("tx 0")),
+		newPendingTx([]byte("tx 1")),
+		newPendingTx([]byte("tx 1")),
+		newPendingTx([]byte("tx 2")),
+	}, isLocal)
+	require.NoError(err, "add")
+	require.Equal(1, skipped, "transactions over the byte limit should be skipped")
+	require.Equal(2, store.count())
+
+	skipped, err = store.add([]*PendingCheckTransaction{
+		newPendingTx([]byte("tx")),
+		newPendingTx([]byte("tx 3")),
+	}, isLocal)
+	require.NoError(err, "add")
+	require.Equal(1, skipped, "transactions over the count limit should be skipped")
+	require.Equal(3, store.count())
+
+	err = store.remove([]hash.Hash{hash.NewFromBytes([]byte("tx 1"))})
+	require.NoError(err, "remove")
+	require.Equal(2, store.count())
+
+	// Pruning should only remove transactions persisted before the checkpoint.
+	checkpoint := store.checkpoint()
+	skipped, err = store.add([]*PendingCheckTransaction{newPendingTx([]byte("tx 4"))}, isLocal)
+	require.NoError(err, "add")
+	require.Zero(skipped)
+	pruned, err := store.prune(map[hash.Hash]struct{}{
+		hash.NewFromBytes([]byte("tx 0")): {},
+	}, checkpoint)
+	require.NoError(err, "prune")
+	require.Equal(1, pruned)
+	require.Equal(2, store.count())
+
+	// Reopening the store should restore the remaining transactions.
+	store = newTxStore(cs, common.Namespace{}, cfg)
+	txs, err = store.load()
+	require.NoError(err, "load")
+	require.Len(txs, 2)
+	for _, tx := range txs {
+		require.Equal(string(tx.Tx) == "tx 0", tx.Local)
+	}
+
+	err = store.remove([]hash.Hash{hash.NewFromBytes([]byte("tx 0")), hash.NewFromBytes([]byte("tx 4"))})
+	require.NoError(err, "remove")
+	require.Zero(store.count())
+}
+
+func TestRestore(t *testing.T) {
+	require := require.New(t)
+
+	cs, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer cs.Close()
+
+	cfg := config.PersistenceConfig{
+		MaxTxs:   100,
+		MaxBytes: 1024,
+	}
+
+	store := newTxStore(cs, common.Namespace{}, cfg)
+	_, err = store.add([]*PendingCheckTransaction{
+		newPendingTx([]byte("tx 0")),
+		newPendingTx([]byte("bad")),
+		newPendingTx([]byte("tx 1")),
+	}, func(*PendingCheckTransaction) bool { return false })
+	require.NoError(err, "add")
+
+	tp := newTestTxPool(t, config.Config{
+		MaxPoolSize:          10,
+		MaxLastSeenCacheSize: 10,
+		MaxCheckTxBatchSize:  128,
+	}, rejectTx([]byte("bad")))
+	processTestRound(tp, 1)
+	tp.store = newTxStore(cs, common.Namespace{}, cfg)
+	tp.restoreTxs, err = tp.store.load()
+	require.NoError(err, "load")
+	require.Len(tp.restoreTxs, 3)
+
+	tp.restore()
+	require.Nil(tp.restoreTxs, "restored transactions should be released")
+	require.Equal(2, tp.store.count(), "transactions failing checks should be discarded")
+
+	txs, err := newTxStore(cs, common.Namespace{}, cfg).load()
+	require.NoError(err, "load")
+	require.Len(txs, 2)
+	for _, tx := range txs {
+		require.NotEqual([]byte("bad"), tx.Tx)
+	}
+}
@@ -16,6 +16,11 @@ type TxQueueMeta struct {
 	// receiving from txSync) leave this in its default value. Transactions from those sources, however, only move
 	// through a limited area in the tx pool.
 	firstSeen time.Time
+	// accepted is the timestamp when the transaction first passed checks and was queued for
+	// scheduling. It is left in its default value for transactions that did not go through checks.
+	accepted time.Time
+	// checkedRound is the runtime round against which the transaction last passed checks.
+	checkedRound uint64
 }
 
 // Raw returns the raw transaction data.
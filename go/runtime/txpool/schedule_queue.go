@@ -1,17 +1,23 @@
 package txpool
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/google/btree"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 var (
 	ErrReplacementTxPriorityTooLow = errors.New("txpool: replacement tx priority too low")
 	ErrQueueFull                   = errors.New("txpool: schedule queue is full")
+	ErrTxExpired                   = errors.New("txpool: transaction expired")
 )
 
 // priorityLessFunc is a comparison function for ordering transactions by priority.
@@ -42,13 +48,46 @@ type scheduleQueue struct {
 	bySender   map[string]*MainQueueTransaction
 	byPriority *btree.BTreeG[*MainQueueTransaction]
 
+	// lanes are the priority lanes, ordered by descending minimum priority.
+	lanes []config.LaneConfig
+
+	// round is the round the next batch is scheduled for. Transactions expiring at or before this
+	// round are never scheduled.
+	round uint64
+
 	capacity int
 }
 
+// setLanes configures the priority lanes.
+func (sq *scheduleQueue) setLanes(lanes []config.LaneConfig) {
+	sq.l.Lock()
+	defer sq.l.Unlock()
+
+	sq.lanes = slices.Clone(lanes)
+	sort.Slice(sq.lanes, func(i, j int) bool {
+		return sq.lanes[i].MinPriority > sq.lanes[j].MinPriority
+	})
+}
+
+// laneLocked returns the index of the lane the given transaction belongs to or -1 in case it does
+// not belong to any lane.
+func (sq *scheduleQueue) laneLocked(tx *MainQueueTransaction) int {
+	for i, lane := range sq.lanes {
+		if tx.priority >= lane.MinPriority {
+			return i
+		}
+	}
+	return -1
+}
+
 func (sq *scheduleQueue) add(tx *MainQueueTransaction) error {
 	sq.l.Lock()
 	defer sq.l.Unlock()
 
+	if tx.isExpired(sq.round) {
+		return ErrTxExpired
+	}
+
 	// If a transaction from the same sender already exists, we accept a new transaction only if it
 	// has a higher priority or if the old transaction is no longer valid based on sequence numbers.
 	if etx, exists := sq.bySender[tx.sender]; exists {
@@ -97,6 +136,73 @@ func (sq *scheduleQueue) remove(txHashes []hash.Hash) {
 	}
 }
 
+// takeBySender removes and returns all transactions from the given senders.
+func (sq *scheduleQueue) takeBySender(senders []string) []*MainQueueTransaction {
+	sq.l.Lock()
+	defer sq.l.Unlock()
+
+	var result []*MainQueueTransaction
+	for _, sender := range senders {
+		tx, exists := sq.bySender[sender]
+		if !exists {
+			continue
+		}
+
+		sq.removeLocked(tx)
+		result = append(result, tx)
+	}
+	return result
+}
+
+// takeLeastRecentlyChecked removes and returns up to limit transactions that passed checks the
+// longest time ago.
+func (sq *scheduleQueue) takeLeastRecentlyChecked(limit int) []*MainQueueTransaction {
+	sq.l.Lock()
+	defer sq.l.Unlock()
+
+	if limit <= 0 {
+		return nil
+	}
+
+	result := make([]*MainQueueTransaction, 0, len(sq.all))
+	for _, tx := range sq.all {
+		result = append(result, tx)
+	}
+	slices.SortFunc(result, func(a, b *MainQueueTransaction) int {
+		if c := cmp.Compare(a.checkedRound, b.checkedRound); c != 0 {
+			return c
+		}
+		ha, hb := a.Hash(), b.Hash()
+		return bytes.Compare(ha[:], hb[:])
+	})
+	result = result[:min(limit, len(result))]
+
+	for _, tx := range result {
+		sq.removeLocked(tx)
+	}
+	return result
+}
+
+// takeExpired sets the round the next batch is scheduled for and removes and returns all
+// transactions that cannot be included in a batch for that or any later round.
+func (sq *scheduleQueue) takeExpired(round uint64) []*MainQueueTransaction {
+	sq.l.Lock()
+	defer sq.l.Unlock()
+
+	sq.round = round
+
+	var result []*MainQueueTransaction
+	for _, tx := range sq.all {
+		if tx.isExpired(round) {
+			result = append(result, tx)
+		}
+	}
+	for _, tx := range result {
+		sq.removeLocked(tx)
+	}
+	return result
+}
+
 func (sq *scheduleQueue) getPrioritizedBatch(offset *hash.Hash, limit uint32) []*MainQueueTransaction {
 	sq.l.Lock()
 	defer sq.l.Unlock()
@@ -112,6 +218,8 @@ func (sq *scheduleQueue) getPrioritizedBatch(offset *hash.Hash, limit uint32) []
 			return nil
 		}
 		offsetItem = offsetTx
+	} else if len(sq.lanes) > 0 {
+		return sq.getLanedBatchLocked(limit)
 	}
 
 	sq.byPriority.DescendLessOrEqual(offsetItem, func(tx *MainQueueTransaction) bool {
@@ -120,6 +228,10 @@ func (sq *scheduleQueue) getPrioritizedBatch(offset *hash.Hash, limit uint32) []
 		if h.Equal(offset) {
 			return true
 		}
+		// Skip transactions that would expire before being included.
+		if tx.isExpired(sq.round) {
+			return true
+		}
 
 		// Add the transaction to the batch.
 		batch = append(batch, tx)
@@ -132,6 +244,50 @@ func (sq *scheduleQueue) getPrioritizedBatch(offset *hash.Hash, limit uint32) []
 	return batch
 }
 
+// getLanedBatchLocked returns a batch of at most limit transactions where each lane gets up to its
+// reserved share of the batch and any remaining capacity is filled in priority order. The batch
+// is ordered by descending priority.
+func (sq *scheduleQueue) getLanedBatchLocked(limit uint32) []*MainQueueTransaction {
+	reserved := make([]int, len(sq.lanes))
+	var totalReserved int
+	for i, lane := range sq.lanes {
+		reserved[i] = int(uint64(limit) * uint64(lane.ReservedCapacity) / 100)
+		if reserved[i] == 0 && limit > 0 {
+			// Always reserve at least one slot so small batches are not starved.
+			reserved[i] = 1
+		}
+		reserved[i] = min(reserved[i], int(limit)-totalReserved)
+		totalReserved += reserved[i]
+	}
+
+	var (
+		batch []*MainQueueTransaction
+		rest  []*MainQueueTransaction
+	)
+	taken := make([]int, len(sq.lanes))
+	sq.byPriority.Descend(func(tx *MainQueueTransaction) bool {
+		if tx.isExpired(sq.round) {
+			return true
+		}
+		if lane := sq.laneLocked(tx); lane >= 0 && taken[lane] < reserved[lane] {
+			batch = append(batch, tx)
+			taken[lane]++
+		} else if len(rest) < int(limit) {
+			rest = append(rest, tx)
+		}
+
+		// Stop once all reservations are satisfied and enough other transactions are available.
+		return !(slices.Equal(taken, reserved) && len(rest) >= int(limit))
+	})
+
+	// Fill the remaining capacity in priority order.
+	batch = append(batch, rest[:min(len(rest), int(limit)-len(batch))]...)
+	sort.Slice(batch, func(i, j int) bool {
+		return priorityLessFunc(batch[j], batch[i])
+	})
+	return batch
+}
+
 func (sq *scheduleQueue) getKnownBatch(batch []hash.Hash) ([]*MainQueueTransaction, map[hash.Hash]int) {
 	sq.l.Lock()
 	defer sq.l.Unlock()
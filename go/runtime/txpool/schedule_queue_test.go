@@ -9,6 +9,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
 )
 
 func newTestTransaction(data []byte, priority uint64) *MainQueueTransaction {
@@ -213,3 +214,108 @@ func TestScheduleQueueSender(t *testing.T) {
 	queue.remove([]hash.Hash{tx.Hash()})
 	require.Equal(0, queue.size())
 }
+
+func TestScheduleQueueLanes(t *testing.T) {
+	require := require.New(t)
+
+	queue := newScheduleQueue(100)
+	queue.setLanes([]config.LaneConfig{
+		{MinPriority: 0, ReservedCapacity: 20},
+		{MinPriority: 1000, ReservedCapacity: 50},
+	})
+
+	// Fill the pool with low-priority transactions.
+	for i := 0; i < 100; i++ {
+		err := queue.add(newTestTransaction([]byte(fmt.Sprintf("low %d", i)), 1))
+		require.NoError(err, "Add")
+	}
+
+	// A newly arrived high-priority transaction must be included in the next batch.
+	highTx := newTestTransaction([]byte("high"), 1000)
+	require.NoError(queue.add(highTx), "Add")
+	require.EqualValues(100, queue.size(), "Size")
+
+	batch := queue.getPrioritizedBatch(nil, 10)
+	require.Len(batch, 10, "Batch size")
+	require.Equal(highTx.Hash(), batch[0].Hash(), "high-priority transaction should be first")
+
+	// Transactions in the lowest lane must not be fully excluded by higher-priority transactions
+	// that do not belong to a higher lane.
+	for i := 0; i < 50; i++ {
+		err := queue.add(newTestTransaction([]byte(fmt.Sprintf("medium %d", i)), 100))
+		require.NoError(err, "Add")
+	}
+	queue.setLanes([]config.LaneConfig{
+		{MinPriority: 1000, ReservedCapacity: 50},
+		{MinPriority: 10, ReservedCapacity: 10},
+		{MinPriority: 0, ReservedCapacity: 20},
+	})
+
+	batch = queue.getPrioritizedBatch(nil, 10)
+	require.Len(batch, 10, "Batch size")
+	require.Equal(highTx.Hash(), batch[0].Hash(), "high-priority transaction should be first")
+	var numLow int
+	for i, tx := range batch {
+		if tx.priority == 1 {
+			numLow++
+		}
+		if i > 0 {
+			require.GreaterOrEqual(batch[i-1].priority, tx.priority, "batch should be ordered by priority")
+		}
+	}
+	require.Equal(2, numLow, "lowest lane should get its reserved capacity")
+
+	// Without lanes, batches are ordered strictly by priority.
+	queue.setLanes(nil)
+	batch = queue.getPrioritizedBatch(nil, 10)
+	require.Len(batch, 10, "Batch size")
+	for _, tx := range batch {
+		require.NotEqualValues(1, tx.priority, "low-priority transactions should be excluded")
+	}
+}
+
+func TestScheduleQueueExpiry(t *testing.T) {
+	require := require.New(t)
+
+	queue := newScheduleQueue(10)
+
+	newExpiringTx := func(data string, priority, expiryRound uint64) *MainQueueTransaction {
+		tx := newTestTransaction([]byte(data), priority)
+		tx.expiryRound = expiryRound
+		return tx
+	}
+
+	expiring := newExpiringTx("expiring", 10, 5)
+	later := newExpiringTx("later", 5, 7)
+	forever := newExpiringTx("forever", 0, 0)
+	for _, tx := range []*MainQueueTransaction{expiring, later, forever} {
+		require.NoError(queue.add(tx), "Add")
+	}
+
+	// All transactions can be included in a batch for round 4.
+	require.Empty(queue.takeExpired(4), "no transactions should expire before round 4")
+	batch := queue.getPrioritizedBatch(nil, 10)
+	require.Len(batch, 3, "Batch size")
+
+	// Transactions that expire at the scheduled round must not be proposed, even before eviction.
+	queue.round = 5
+	batch = queue.getPrioritizedBatch(nil, 10)
+	require.Len(batch, 2, "Batch size")
+	for _, tx := range batch {
+		require.NotEqual(expiring.Hash(), tx.Hash(), "expired transaction should not be proposed")
+	}
+
+	expired := queue.takeExpired(5)
+	require.Len(expired, 1, "one transaction should expire at round 5")
+	require.Equal(expiring.Hash(), expired[0].Hash())
+	require.Equal(2, queue.size(), "Size")
+
+	// Transactions that already expired are not admitted.
+	err := queue.add(newExpiringTx("too late", 100, 5))
+	require.ErrorIs(err, ErrTxExpired, "Add")
+
+	expired = queue.takeExpired(100)
+	require.Len(expired, 1, "one transaction should expire at round 100")
+	require.Equal(later.Hash(), expired[0].Hash())
+	require.Equal(1, queue.size(), "transactions without expiry should be kept")
+}
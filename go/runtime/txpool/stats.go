@@ -0,0 +1,134 @@
+package txpool
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// schedulingLatencyWindow is the number of most recent scheduling latency samples used to
+	// compute the scheduling status.
+	schedulingLatencyWindow = 1000
+	// batchSizeWindow is the number of most recent proposed batch sizes used to compute the
+	// scheduling status.
+	batchSizeWindow = 100
+)
+
+// SchedulingStatus is a rolling summary of transaction scheduling on this node.
+type SchedulingStatus struct {
+	// NumSamples is the number of scheduled transactions the latencies are computed from.
+	NumSamples int `json:"num_samples"`
+
+	// LatencyP50 is the median time from a transaction passing checks to its inclusion in
+	// a proposed batch.
+	LatencyP50 time.Duration `json:"latency_p50"`
+
+	// LatencyP95 is the 95th percentile time from a transaction passing checks to its inclusion
+	// in a proposed batch.
+	LatencyP95 time.Duration `json:"latency_p95"`
+
+	// AvgBatchSize is the average number of transactions in recently proposed batches.
+	AvgBatchSize float64 `json:"avg_batch_size"`
+
+	// NumSuspectTxs is the number of transactions that were part of batches which failed
+	// execution and are being scheduled in isolation.
+	NumSuspectTxs int `json:"num_suspect_txs"`
+
+	// NumDenylistedTxs is the number of transactions that are rejected as they have repeatedly
+	// failed execution.
+	NumDenylistedTxs int `json:"num_denylisted_txs"`
+}
+
+// sampleWindow is a fixed-size window of the most recent samples.
+type sampleWindow[T int | time.Duration] struct {
+	samples []T
+	next    int
+}
+
+func newSampleWindow[T int | time.Duration](size int) *sampleWindow[T] {
+	return &sampleWindow[T]{
+		samples: make([]T, 0, size),
+	}
+}
+
+// add adds a sample to the window, replacing the oldest sample if the window is full.
+func (w *sampleWindow[T]) add(v T) {
+	if len(w.samples) < cap(w.samples) {
+		w.samples = append(w.samples, v)
+		return
+	}
+	w.samples[w.next] = v
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+// percentiles returns the given percentiles (in range [0, 1]) of the samples in the window.
+func (w *sampleWindow[T]) percentiles(ps ...float64) []T {
+	result := make([]T, len(ps))
+	if len(w.samples) == 0 {
+		return result
+	}
+
+	sorted := slices.Clone(w.samples)
+	slices.Sort(sorted)
+	for i, p := range ps {
+		idx := int(p * float64(len(sorted)-1))
+		result[i] = sorted[idx]
+	}
+	return result
+}
+
+// average returns the average of the samples in the window.
+func (w *sampleWindow[T]) average() float64 {
+	if len(w.samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range w.samples {
+		sum += float64(v)
+	}
+	return sum / float64(len(w.samples))
+}
+
+// schedulingStats keeps track of recent transaction scheduling latencies and batch sizes.
+type schedulingStats struct {
+	sync.Mutex
+
+	latencies  *sampleWindow[time.Duration]
+	batchSizes *sampleWindow[int]
+}
+
+func newSchedulingStats() *schedulingStats {
+	return &schedulingStats{
+		latencies:  newSampleWindow[time.Duration](schedulingLatencyWindow),
+		batchSizes: newSampleWindow[int](batchSizeWindow),
+	}
+}
+
+func (s *schedulingStats) addLatency(latency time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.latencies.add(latency)
+}
+
+func (s *schedulingStats) addBatchSize(size int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.batchSizes.add(size)
+}
+
+func (s *schedulingStats) status() *SchedulingStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	ps := s.latencies.percentiles(0.5, 0.95)
+	return &SchedulingStatus{
+		NumSamples:   len(s.latencies.samples),
+		LatencyP50:   ps[0],
+		LatencyP95:   ps[1],
+		AvgBatchSize: s.batchSizes.average(),
+	}
+}
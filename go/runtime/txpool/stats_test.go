@@ -0,0 +1,51 @@
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleWindow(t *testing.T) {
+	require := require.New(t)
+
+	w := newSampleWindow[int](10)
+	require.Equal([]int{0, 0}, w.percentiles(0.5, 0.95), "empty window")
+	require.EqualValues(0, w.average(), "empty window")
+
+	for i := range 10 {
+		w.add(10 - i)
+	}
+	require.Equal([]int{5, 9, 10}, w.percentiles(0.5, 0.95, 1), "full window")
+	require.EqualValues(5.5, w.average())
+
+	// Oldest samples should be replaced.
+	for i := range 5 {
+		w.add(100 + i)
+	}
+	require.Len(w.samples, 10)
+	require.Equal([]int{1, 5, 104}, w.percentiles(0, 0.5, 1), "after wrap around")
+	require.EqualValues((15+510)/10.0, w.average())
+}
+
+func TestSchedulingStats(t *testing.T) {
+	require := require.New(t)
+
+	s := newSchedulingStats()
+	status := s.status()
+	require.Equal(0, status.NumSamples)
+	require.Zero(status.LatencyP50)
+
+	for i := range 100 {
+		s.addLatency(time.Duration(i+1) * time.Millisecond)
+	}
+	s.addBatchSize(10)
+	s.addBatchSize(20)
+
+	status = s.status()
+	require.Equal(100, status.NumSamples)
+	require.Equal(50*time.Millisecond, status.LatencyP50)
+	require.Equal(95*time.Millisecond, status.LatencyP95)
+	require.EqualValues(15, status.AvgBatchSize)
+}
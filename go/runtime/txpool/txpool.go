@@ -2,7 +2,6 @@ package txpool
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -12,7 +11,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
@@ -43,6 +44,10 @@ const (
 	// case when the maxRepublishTxs limit is reached. This should be much shorter than the
 	// RepublishInterval.
 	republishLimitReinvokeTimeout = 1 * time.Second
+
+	// persistPruneInterval is the interval at which persisted transactions that are no longer
+	// queued are removed from the persistent store.
+	persistPruneInterval = 10 * time.Second
 )
 
 // TransactionMeta contains the per-transaction metadata.
@@ -72,6 +77,14 @@ type TransactionPool interface {
 	// SubmitTxNoWait adds the transaction into the transaction pool and returns immediately.
 	SubmitTxNoWait(tx []byte, meta *TransactionMeta) error
 
+	// SubmitTxBatch adds the given transactions into the transaction pool in a single operation,
+	// first performing checks on them by invoking the runtime. This method waits for the checks
+	// of all transactions to complete and returns a result for each transaction, in order.
+	//
+	// Transactions that are rejected before being checked (e.g., because they are denylisted,
+	// duplicates or the check queue is full) have the rejection reported in their result.
+	SubmitTxBatch(ctx context.Context, txs [][]byte, meta *TransactionMeta) ([]*protocol.CheckTxResult, error)
+
 	// SubmitProposedBatch adds the given (possibly new) transaction batch into the current
 	// proposal queue.
 	SubmitProposedBatch(batch [][]byte)
@@ -95,6 +108,23 @@ type TransactionPool interface {
 	// can remove those transactions will do so.
 	HandleTxsUsed(txs []hash.Hash)
 
+	// HandleTxsFailed indicates that the runtime failed while executing a batch consisting of the
+	// given transactions. Transactions that repeatedly fail execution on their own are removed from
+	// the pool and denylisted for a while.
+	//
+	// This must not be called when batch execution has been aborted.
+	HandleTxsFailed(txs []hash.Hash)
+
+	// HandleSchedulingFailed is like HandleTxsFailed, but for all transactions handed out in
+	// the current scheduling session. It must be called before FinishScheduling.
+	HandleSchedulingFailed()
+
+	// HandleTxsExecutionTimes indicates that the runtime executed the given transactions, taking
+	// the given amount of time for each of them. Slow transactions are logged and transactions that
+	// are repeatedly slow without being included in a block are removed from the pool and
+	// denylisted for a while.
+	HandleTxsExecutionTimes(txs []hash.Hash, times []time.Duration)
+
 	// GetSchedulingSuggestion returns a list of transactions to schedule. This begins a
 	// scheduling session, which suppresses transaction rechecking and republishing. Subsequently
 	// call GetSchedulingExtra for more transactions, followed by FinishScheduling.
@@ -132,6 +162,13 @@ type TransactionPool interface {
 
 	// GetTxs returns all transactions currently queued in the transaction pool.
 	GetTxs() []*TxQueueMeta
+
+	// GetSchedulingStatus returns a rolling summary of recent transaction scheduling.
+	GetSchedulingStatus() *SchedulingStatus
+
+	// SetLanes reconfigures the priority lanes of the schedulable transaction queue. The new
+	// configuration applies to subsequent scheduling sessions.
+	SetLanes(lanes []config.LaneConfig) error
 }
 
 // TransactionPublisher is an interface representing a mechanism for publishing transactions.
@@ -161,6 +198,8 @@ type txPool struct {
 	// seenCache maps from transaction hashes to time.Time that specifies when the transaction was
 	// last published.
 	seenCache *lru.Cache
+	// senderCache maps from transaction hashes to the senders of recently checked transactions.
+	senderCache *lru.Cache
 
 	checkTxCh       *channels.RingChannel
 	checkTxQueue    *checkTxQueue
@@ -169,6 +208,12 @@ type txPool struct {
 
 	drainLock sync.Mutex
 
+	// recheckingLock protects rechecking.
+	recheckingLock sync.Mutex
+	// rechecking is the set of transactions that have been taken out of the queues for rechecking
+	// outside the drain lock.
+	rechecking map[hash.Hash]struct{}
+
 	usableSources        []UsableTransactionSource
 	recheckableStores    []RecheckableTransactionStore
 	republishableSources []RepublishableTransactionSource
@@ -179,18 +224,40 @@ type txPool struct {
 	proposedTxsLock sync.Mutex
 	proposedTxs     map[hash.Hash]*TxQueueMeta
 
+	schedulingStats *schedulingStats
+	failures        *failureTracker
+
 	blockInfoLock      sync.Mutex
 	blockInfo          *runtime.BlockInfo
 	lastBlockProcessed time.Time
 	lastRecheckRound   uint64
+	fullRecheck        bool
 
 	republishCh *channels.RingChannel
+
+	store      *txStore
+	restoreTxs []*persistedTx
 }
 
 func (t *txPool) Start() error {
+	if t.store != nil {
+		// Load persisted transactions before any new transactions are admitted.
+		txs, err := t.store.load()
+		if err != nil {
+			t.logger.Error("failed to load persisted transactions, persistence disabled",
+				"err", err,
+			)
+			t.store = nil
+		}
+		t.restoreTxs = txs
+	}
+
 	go t.checkWorker()
 	go t.republishWorker()
 	go t.recheckWorker()
+	if t.store != nil {
+		go t.persistWorker()
+	}
 	return nil
 }
 
@@ -225,16 +292,83 @@ func (t *txPool) SubmitTxNoWait(tx []byte, meta *TransactionMeta) error {
 	return t.submitTx(tx, meta, nil)
 }
 
+func (t *txPool) SubmitTxBatch(ctx context.Context, rawTxs [][]byte, meta *TransactionMeta) ([]*protocol.CheckTxResult, error) {
+	results := make([]*protocol.CheckTxResult, len(rawTxs))
+	notifyChs := make([]chan *protocol.CheckTxResult, len(rawTxs))
+	pcts := make([]*PendingCheckTransaction, 0, len(rawTxs))
+	indices := make([]int, 0, len(rawTxs))
+	batchTxs := make(map[hash.Hash]struct{}, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		notifyCh := make(chan *protocol.CheckTxResult, 1)
+		pct, err := t.newPendingCheckTransaction(rawTx, meta, notifyCh)
+		if err == nil {
+			// Reject duplicates within the batch itself, as the seen cache is only updated
+			// after the checks complete.
+			if _, dup := batchTxs[pct.Hash()]; dup {
+				err = fmt.Errorf("duplicate transaction")
+			}
+		}
+		if err != nil {
+			results[i] = newRejectedCheckTxResult(err)
+			continue
+		}
+		batchTxs[pct.Hash()] = struct{}{}
+
+		notifyChs[i] = notifyCh
+		pcts = append(pcts, pct)
+		indices = append(indices, i)
+	}
+
+	// Queue all transactions for checks at once.
+	added := t.addBatchToCheckQueue(pcts)
+	for j := added; j < len(pcts); j++ {
+		results[indices[j]] = newRejectedCheckTxResult(fmt.Errorf("check queue is full"))
+		notifyChs[indices[j]] = nil
+	}
+
+	// Wait for responses from transaction checks.
+	for i, notifyCh := range notifyChs {
+		if notifyCh == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.stopCh:
+			return nil, fmt.Errorf("shutting down")
+		case result := <-notifyCh:
+			results[i] = result
+		}
+	}
+
+	return results, nil
+}
+
 func (t *txPool) submitTx(rawTx []byte, meta *TransactionMeta, notifyCh chan *protocol.CheckTxResult) error {
+	pct, err := t.newPendingCheckTransaction(rawTx, meta, notifyCh)
+	if err != nil {
+		return err
+	}
+	return t.addToCheckQueue(pct)
+}
+
+func (t *txPool) newPendingCheckTransaction(rawTx []byte, meta *TransactionMeta, notifyCh chan *protocol.CheckTxResult) (*PendingCheckTransaction, error) {
 	tx := &TxQueueMeta{
 		raw:       rawTx,
 		hash:      hash.NewFromBytes(rawTx),
 		firstSeen: time.Now(),
 	}
+	// Reject transactions that repeatedly failed execution.
+	if t.failures.isDenylisted(tx.Hash()) {
+		t.logger.Debug("rejecting denylisted transaction", "tx_hash", tx.Hash())
+		denylistedRejections.With(t.getMetricLabels()).Inc()
+		return nil, ErrTxDenylisted
+	}
 	// Skip recently seen transactions.
 	if _, seen := t.seenCache.Peek(tx.Hash()); seen {
 		t.logger.Debug("ignoring already seen transaction", "tx_hash", tx.Hash())
-		return fmt.Errorf("duplicate transaction")
+		return nil, fmt.Errorf("duplicate transaction")
 	}
 
 	// Queue transaction for checks.
@@ -250,7 +384,7 @@ func (t *txPool) submitTx(rawTx []byte, meta *TransactionMeta, notifyCh chan *pr
 		pct.dstQueue = t.mainQueue
 	}
 
-	return t.addToCheckQueue(pct)
+	return pct, nil
 }
 
 func (t *txPool) addToCheckQueue(pct *PendingCheckTransaction) error {
@@ -275,6 +409,51 @@ func (t *txPool) addToCheckQueue(pct *PendingCheckTransaction) error {
 	return nil
 }
 
+// addBatchToCheckQueue queues the given transactions for checks as a single operation and
+// returns the number of transactions (from the start of the batch) that were queued.
+func (t *txPool) addBatchToCheckQueue(pcts []*PendingCheckTransaction) int {
+	if len(pcts) == 0 {
+		return 0
+	}
+
+	t.logger.Debug("queuing transaction batch for check",
+		"num_txs", len(pcts),
+	)
+	added := t.checkTxQueue.addBatch(pcts)
+	if added < len(pcts) {
+		t.logger.Warn("unable to queue all transactions, check queue is full",
+			"num_txs", len(pcts),
+			"queued_txs", added,
+		)
+	}
+	if added == 0 {
+		return 0
+	}
+
+	// Wake up the check batcher.
+	t.checkTxCh.In() <- struct{}{}
+
+	pendingCheckSize.With(t.getMetricLabels()).Set(float64(t.PendingCheckSize()))
+
+	return added
+}
+
+// newRejectedCheckTxResult converts an error encountered while submitting a transaction into a
+// failed check result.
+func newRejectedCheckTxResult(err error) *protocol.CheckTxResult {
+	module, code := errors.Code(err)
+	if module == errors.UnknownModule {
+		module, code = moduleName, 1
+	}
+	return &protocol.CheckTxResult{
+		Error: protocol.Error{
+			Module:  module,
+			Code:    code,
+			Message: err.Error(),
+		},
+	}
+}
+
 func (t *txPool) SubmitProposedBatch(batch [][]byte) {
 	// Also ingest into the regular pool (may fail).
 	for _, rawTx := range batch {
@@ -304,12 +483,20 @@ func (t *txPool) PromoteProposedBatch(batch []hash.Hash) ([]*TxQueueMeta, map[ha
 	t.proposedTxsLock.Lock()
 	defer t.proposedTxsLock.Unlock()
 
+	now := time.Now()
 	for _, tx := range txs {
 		if tx == nil {
 			continue
 		}
+		// Only account for the first time a checked transaction is proposed.
+		if _, ok := t.proposedTxs[tx.Hash()]; !ok && !tx.accepted.IsZero() {
+			latency := now.Sub(tx.accepted)
+			schedulingLatency.With(t.getMetricLabels()).Observe(latency.Seconds())
+			t.schedulingStats.addLatency(latency)
+		}
 		t.proposedTxs[tx.Hash()] = tx
 	}
+	t.schedulingStats.addBatchSize(len(batch))
 
 	return txs, missingTxs
 }
@@ -327,11 +514,11 @@ func (t *txPool) GetSchedulingSuggestion(countHint uint32) []*TxQueueMeta {
 	for _, q := range t.usableSources {
 		txs = append(txs, q.GetSchedulingSuggestion(countHint)...)
 	}
-	return txs
+	return t.failures.startScheduling(txs)
 }
 
 func (t *txPool) GetSchedulingExtra(offset *hash.Hash, limit uint32) []*TxQueueMeta {
-	return t.mainQueue.GetSchedulingExtra(offset, limit)
+	return t.failures.addScheduled(t.mainQueue.GetSchedulingExtra(offset, limit))
 }
 
 func (t *txPool) FinishScheduling() {
@@ -345,18 +532,99 @@ func (t *txPool) RejectTxs(hashes []hash.Hash) {
 		t.seenCache.Remove(h)
 	}
 
-	t.HandleTxsUsed(hashes)
+	t.removeTxs(hashes)
 }
 
 func (t *txPool) HandleTxsUsed(hashes []hash.Hash) {
+	// Senders of executed transactions may have had their state changed, so their other
+	// transactions need to be rechecked. Look them up before the transactions are removed.
+	var senders []string
+	for _, h := range hashes {
+		if sender, ok := t.senderCache.Peek(h); ok {
+			senders = append(senders, sender.(string))
+		}
+	}
+
+	t.removeTxs(hashes)
+	t.recheckSenders(senders)
+}
+
+// removeTxs removes the given transactions from all queues.
+func (t *txPool) removeTxs(hashes []hash.Hash) {
 	for _, q := range t.usableSources {
 		q.HandleTxsUsed(hashes)
 	}
+	t.failures.remove(hashes)
+	t.updateFailureMetrics()
 
 	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
 	localQueueSize.With(t.getMetricLabels()).Set(float64(t.localQueue.size()))
 }
 
+func (t *txPool) HandleTxsFailed(hashes []hash.Hash) {
+	if !t.failures.enabled() {
+		return
+	}
+
+	denylisted := t.failures.failed(hashes)
+	for _, h := range denylisted {
+		t.logger.Warn("denylisting transaction after repeated execution failures",
+			"tx_hash", h,
+		)
+	}
+	if len(denylisted) > 0 {
+		t.removeTxs(denylisted)
+	}
+	t.updateFailureMetrics()
+}
+
+func (t *txPool) HandleSchedulingFailed() {
+	t.HandleTxsFailed(t.failures.scheduledTxs())
+}
+
+func (t *txPool) HandleTxsExecutionTimes(hashes []hash.Hash, times []time.Duration) {
+	threshold := t.failures.cfg.SlowThreshold
+	if threshold == 0 || len(hashes) != len(times) {
+		return
+	}
+
+	var slow []hash.Hash
+	for i, h := range hashes {
+		if times[i] < threshold {
+			continue
+		}
+		t.logger.Warn("slow transaction execution",
+			"tx_hash", h,
+			"execution_time", times[i],
+		)
+		slow = append(slow, h)
+	}
+	if len(slow) == 0 {
+		return
+	}
+	slowTransactions.With(t.getMetricLabels()).Add(float64(len(slow)))
+
+	if !t.failures.slowEnabled() {
+		return
+	}
+	denylisted := t.failures.slowExecuted(slow)
+	for _, h := range denylisted {
+		t.logger.Warn("denylisting transaction after repeated slow executions",
+			"tx_hash", h,
+		)
+	}
+	if len(denylisted) > 0 {
+		t.removeTxs(denylisted)
+	}
+	t.updateFailureMetrics()
+}
+
+func (t *txPool) updateFailureMetrics() {
+	numSuspects, numDenylisted := t.failures.counts()
+	suspectTransactions.With(t.getMetricLabels()).Set(float64(numSuspects))
+	denylistedTransactions.With(t.getMetricLabels()).Set(float64(numDenylisted))
+}
+
 func (t *txPool) GetKnownBatch(batch []hash.Hash) ([]*TxQueueMeta, map[hash.Hash]int) {
 	var txs []*TxQueueMeta
 	missingTxs := make(map[hash.Hash]int)
@@ -400,15 +668,47 @@ func (t *txPool) ProcessBlock(bi *runtime.BlockInfo) {
 	t.blockInfo = bi
 	t.lastBlockProcessed = time.Now()
 
-	// Force transaction rechecks on epoch transitions and if needed.
+	// Evict transactions that can no longer be included before expiring.
+	t.evictExpired(bi.RuntimeBlock.Header.Round + 1)
+
+	// Force full transaction rechecks on epoch transitions and if needed, otherwise continue the
+	// background recheck sweep.
 	isEpochTransition := bi.RuntimeBlock.Header.HeaderType == block.EpochTransition
 	roundDifference := bi.RuntimeBlock.Header.Round - t.lastRecheckRound
-	if isEpochTransition || roundDifference > t.cfg.RecheckInterval {
+	switch {
+	case isEpochTransition, t.cfg.RecheckSweepRate == 0 && roundDifference > t.cfg.RecheckInterval:
+		t.fullRecheck = true
 		t.recheckTxCh.In() <- struct{}{}
 		t.lastRecheckRound = bi.RuntimeBlock.Header.Round
+	case t.cfg.RecheckSweepRate > 0:
+		t.recheckTxCh.In() <- struct{}{}
 	}
 }
 
+// evictExpired removes all transactions that expire at or before the given round from the pool.
+//
+// Expired transactions are kept in the seen cache as they can never become valid again.
+func (t *txPool) evictExpired(round uint64) {
+	expired := t.mainQueue.takeExpired(round)
+	if len(expired) == 0 {
+		return
+	}
+
+	hashes := make([]hash.Hash, 0, len(expired))
+	for _, tx := range expired {
+		hashes = append(hashes, tx.Hash())
+	}
+	t.failures.remove(hashes)
+	t.updateFailureMetrics()
+
+	t.logger.Debug("evicted expired transactions",
+		"round", round,
+		"num_txs", len(expired),
+	)
+	expiredTransactions.With(t.getMetricLabels()).Add(float64(len(expired)))
+	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
+}
+
 func (t *txPool) ProcessIncomingMessages(inMsgs []*message.IncomingMessage) {
 	t.rimQueue.Load(inMsgs)
 	rimQueueSize.With(t.getMetricLabels()).Set(float64(t.rimQueue.size()))
@@ -436,6 +736,24 @@ func (t *txPool) GetTxs() []*TxQueueMeta {
 	return txs
 }
 
+func (t *txPool) GetSchedulingStatus() *SchedulingStatus {
+	status := t.schedulingStats.status()
+	status.NumSuspectTxs, status.NumDenylistedTxs = t.failures.counts()
+	return status
+}
+
+func (t *txPool) SetLanes(lanes []config.LaneConfig) error {
+	if err := config.ValidateLanes(lanes); err != nil {
+		return err
+	}
+	t.mainQueue.setLanes(lanes)
+
+	t.logger.Info("transaction pool priority lanes reconfigured",
+		"lanes", lanes,
+	)
+	return nil
+}
+
 func (t *txPool) getCurrentBlockInfo() (*runtime.BlockInfo, time.Time, error) {
 	t.blockInfoLock.Lock()
 	defer t.blockInfoLock.Unlock()
@@ -519,6 +837,16 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 
 	pendingCheckSize.With(t.getMetricLabels()).Set(float64(t.PendingCheckSize()))
 
+	t.handleCheckedBatch(batch, results, bi.RuntimeBlock.Header.Round, lastBlockProcessed)
+
+	return nil
+}
+
+// handleCheckedBatch notifies submitters of the results of checking a transaction batch against
+// the given round and queues the transactions that passed checks for scheduling.
+func (t *txPool) handleCheckedBatch(batch []*PendingCheckTransaction, results []protocol.CheckTxResult, round uint64, lastBlockProcessed time.Time) {
+	defer t.doneRechecking(batch)
+
 	notifySubmitter := func(i int) {
 		// Send back the result of running the checks.
 		if batch[i].notifyCh != nil {
@@ -550,6 +878,13 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 			continue
 		}
 
+		// Remember the sender so that the transaction's sender can be rechecked when the
+		// transaction is executed.
+		if res.Meta != nil && len(res.Meta.Sender) > 0 {
+			// Put cannot fail as senderCache's LRU capacity is not in bytes.
+			_ = t.senderCache.Put(batch[i].Hash(), string(res.Meta.Sender))
+		}
+
 		if batch[i].dstQueue == nil {
 			notifySubmitter(i)
 			continue
@@ -571,7 +906,7 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 	}
 
 	if len(goodPcts) == 0 {
-		return nil
+		return
 	}
 
 	t.logger.Debug("checked new transactions",
@@ -579,9 +914,17 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 		"accepted_txs", len(goodPcts),
 	)
 
+	// Persist new transactions before admitting them, so they survive node restarts.
+	t.persistTxs(newTxs)
+
 	// Queue checked transactions for scheduling.
+	now := time.Now()
 	for i, pct := range goodPcts {
-		if err = pct.dstQueue.OfferChecked(pct.TxQueueMeta, results[batchIndices[i]].Meta); err != nil {
+		if !pct.flags.isRecheck() {
+			pct.accepted = now
+		}
+		pct.checkedRound = round
+		if err := pct.dstQueue.OfferChecked(pct.TxQueueMeta, results[batchIndices[i]].Meta); err != nil {
 			t.logger.Error("unable to queue transaction for scheduling",
 				"err", err,
 				"tx_hash", pct.Hash(),
@@ -630,8 +973,6 @@ func (t *txPool) checkTxBatch(ctx context.Context) error {
 
 	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
 	localQueueSize.With(t.getMetricLabels()).Set(float64(t.localQueue.size()))
-
-	return nil
 }
 
 func (t *txPool) ensureInitialized() error {
@@ -809,6 +1150,155 @@ func (t *txPool) republishWorker() {
 	}
 }
 
+// persistTxs persists the given newly accepted transactions.
+func (t *txPool) persistTxs(pcts []*PendingCheckTransaction) {
+	if t.store == nil || len(pcts) == 0 {
+		return
+	}
+
+	isLocal := func(pct *PendingCheckTransaction) bool {
+		return pct.dstQueue == t.localQueue
+	}
+	skipped, err := t.store.add(pcts, isLocal)
+	if err != nil {
+		t.logger.Warn("failed to persist transactions",
+			"err", err,
+			"num_txs", len(pcts),
+		)
+	}
+	if skipped > 0 {
+		t.logger.Debug("persistent transaction store is full, not persisting transactions",
+			"num_txs", skipped,
+		)
+	}
+	persistedTransactions.With(t.getMetricLabels()).Set(float64(t.store.count()))
+}
+
+func (t *txPool) persistWorker() {
+	// Wait for initialization.
+	if err := t.ensureInitialized(); err != nil {
+		return
+	}
+
+	t.restore()
+
+	ticker := time.NewTicker(persistPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		t.prunePersisted()
+	}
+}
+
+// restore checks the transactions that were persisted before the node restarted against the
+// current round and re-admits the ones that are still valid.
+func (t *txPool) restore() {
+	txs := t.restoreTxs
+	t.restoreTxs = nil
+	if len(txs) == 0 {
+		return
+	}
+
+	t.logger.Info("restoring persisted transactions",
+		"num_txs", len(txs),
+	)
+
+	var (
+		pcts      []*PendingCheckTransaction
+		notifyChs []chan *protocol.CheckTxResult
+		discarded []hash.Hash
+	)
+	for _, ptx := range txs {
+		notifyCh := make(chan *protocol.CheckTxResult, 1)
+		pct, err := t.newPendingCheckTransaction(ptx.Tx, &TransactionMeta{Local: ptx.Local}, notifyCh)
+		if err != nil {
+			discarded = append(discarded, hash.NewFromBytes(ptx.Tx))
+			continue
+		}
+		pcts = append(pcts, pct)
+		notifyChs = append(notifyChs, notifyCh)
+	}
+
+	added := t.addBatchToCheckQueue(pcts)
+	for _, pct := range pcts[added:] {
+		discarded = append(discarded, pct.Hash())
+	}
+
+	var restored int
+	for i, notifyCh := range notifyChs[:added] {
+		select {
+		case <-t.stopCh:
+			return
+		case result := <-notifyCh:
+			if !result.IsSuccess() {
+				discarded = append(discarded, pcts[i].Hash())
+				continue
+			}
+			restored++
+		}
+	}
+
+	if err := t.store.remove(discarded); err != nil {
+		t.logger.Warn("failed to remove discarded transactions",
+			"err", err,
+		)
+	}
+	restoredTransactions.With(t.getMetricLabels()).Add(float64(restored))
+	discardedRestoredTransactions.With(t.getMetricLabels()).Add(float64(len(discarded)))
+	persistedTransactions.With(t.getMetricLabels()).Set(float64(t.store.count()))
+
+	t.logger.Info("restored persisted transactions",
+		"restored_txs", restored,
+		"discarded_txs", len(discarded),
+	)
+}
+
+// prunePersisted removes persisted transactions that are no longer queued.
+func (t *txPool) prunePersisted() {
+	queued := make(map[hash.Hash]struct{})
+	checkpoint := func() uint64 {
+		// Prevent rechecks from temporarily taking transactions out of the queues.
+		t.drainLock.Lock()
+		defer t.drainLock.Unlock()
+
+		// Also prevent transactions from being taken out of the queues for rechecking their
+		// senders, but treat the ones that are already being rechecked as queued.
+		t.recheckingLock.Lock()
+		defer t.recheckingLock.Unlock()
+
+		checkpoint := t.store.checkpoint()
+		for _, tx := range t.localQueue.PeekAll() {
+			queued[tx.Hash()] = struct{}{}
+		}
+		for _, tx := range t.mainQueue.PeekAll() {
+			queued[tx.Hash()] = struct{}{}
+		}
+		for h := range t.rechecking {
+			queued[h] = struct{}{}
+		}
+		return checkpoint
+	}()
+
+	pruned, err := t.store.prune(queued, checkpoint)
+	if err != nil {
+		t.logger.Warn("failed to prune persisted transactions",
+			"err", err,
+		)
+	}
+	if pruned > 0 {
+		t.logger.Debug("pruned persisted transactions",
+			"num_txs", pruned,
+		)
+	}
+	persistedTransactions.With(t.getMetricLabels()).Set(float64(t.store.count()))
+}
+
 func (t *txPool) recheckWorker() {
 	// Wait for initialization.
 	if err := t.ensureInitialized(); err != nil {
@@ -822,29 +1312,48 @@ func (t *txPool) recheckWorker() {
 		case <-t.recheckTxCh.Out():
 		}
 
-		t.recheck()
+		t.blockInfoLock.Lock()
+		fullRecheck := t.fullRecheck
+		t.fullRecheck = false
+		t.blockInfoLock.Unlock()
+
+		if fullRecheck {
+			t.recheck()
+		} else {
+			t.recheckSweep()
+		}
 	}
 }
 
+// recheck rechecks all queued transactions.
 func (t *txPool) recheck() {
 	t.drainLock.Lock()
 	defer t.drainLock.Unlock()
 
-	// Get a batch of scheduled transactions.
 	var pcts []*PendingCheckTransaction
-	var results []chan *protocol.CheckTxResult
 	for _, q := range t.recheckableStores {
-		for _, tx := range q.TakeAll() {
-			notifyCh := make(chan *protocol.CheckTxResult, 1)
-			pcts = append(pcts, &PendingCheckTransaction{
-				TxQueueMeta: tx,
-				flags:       txCheckRecheck,
-				dstQueue:    q,
-				notifyCh:    notifyCh,
-			})
-			results = append(results, notifyCh)
-		}
+		pcts = append(pcts, newRecheckTransactions(q, q.TakeAll())...)
 	}
+	t.recheckTxs(pcts, "full")
+}
+
+// recheckSweep rechecks all local transactions and the configured number of main queue
+// transactions that passed checks the longest time ago.
+//
+// Local transactions are always rechecked as the local queue is small and does not track
+// transaction senders.
+func (t *txPool) recheckSweep() {
+	t.drainLock.Lock()
+	defer t.drainLock.Unlock()
+
+	pcts := newRecheckTransactions(t.localQueue, t.localQueue.TakeAll())
+	txs := t.mainQueue.takeLeastRecentlyChecked(int(t.cfg.RecheckSweepRate))
+	pcts = append(pcts, newRecheckTransactions(t.mainQueue, txs)...)
+	t.recheckTxs(pcts, "sweep")
+}
+
+// recheckTxs submits the given transactions for rechecking and blocks until checking is done.
+func (t *txPool) recheckTxs(pcts []*PendingCheckTransaction, kind string) {
 	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
 	localQueueSize.With(t.getMetricLabels()).Set(float64(t.localQueue.size()))
 
@@ -852,19 +1361,19 @@ func (t *txPool) recheck() {
 		return
 	}
 
-	// Recheck all transactions in batch.
+	results := make([]chan *protocol.CheckTxResult, 0, len(pcts))
 	for _, pct := range pcts {
-		err := t.addToCheckQueue(pct)
-		if err != nil {
-			t.logger.Warn("failed to submit transaction for recheck",
-				"err", err,
-				"tx_hash", pct.Hash(),
-			)
-		}
+		notifyCh := make(chan *protocol.CheckTxResult, 1)
+		pct.notifyCh = notifyCh
+		results = append(results, notifyCh)
 	}
 
+	// Recheck all transactions in batch.
+	added := t.addBatchToCheckQueue(pcts)
+	t.observeRechecked(kind, added)
+
 	// Block until checking is done.
-	for _, notifyCh := range results {
+	for _, notifyCh := range results[:added] {
 		select {
 		case <-t.stopCh:
 			return
@@ -874,13 +1383,88 @@ func (t *txPool) recheck() {
 	}
 }
 
+// recheckSenders takes the main queue transactions of the given senders out of the queue and
+// submits them for rechecking without waiting for the results.
+//
+// As the transactions are taken out of the queue immediately, a transaction invalidated by the
+// execution of another transaction from the same sender cannot be scheduled before it passes
+// checks again.
+func (t *txPool) recheckSenders(senders []string) {
+	if t.cfg.RecheckSweepRate == 0 || len(senders) == 0 {
+		return
+	}
+
+	// Track the transactions while they are out of the queue, so they are not pruned from the
+	// persistent store in the meantime.
+	t.recheckingLock.Lock()
+	defer t.recheckingLock.Unlock()
+
+	pcts := newRecheckTransactions(t.mainQueue, t.mainQueue.takeBySender(senders))
+	if len(pcts) == 0 {
+		return
+	}
+	for _, pct := range pcts {
+		t.rechecking[pct.Hash()] = struct{}{}
+	}
+
+	added := t.addBatchToCheckQueue(pcts)
+	for _, pct := range pcts[added:] {
+		delete(t.rechecking, pct.Hash())
+	}
+	t.observeRechecked("sender", added)
+
+	mainQueueSize.With(t.getMetricLabels()).Set(float64(t.mainQueue.inner.size()))
+}
+
+// doneRechecking clears the given transactions from the set of transactions being rechecked.
+func (t *txPool) doneRechecking(pcts []*PendingCheckTransaction) {
+	t.recheckingLock.Lock()
+	defer t.recheckingLock.Unlock()
+
+	if len(t.rechecking) == 0 {
+		return
+	}
+	for _, pct := range pcts {
+		delete(t.rechecking, pct.Hash())
+	}
+}
+
+func (t *txPool) observeRechecked(kind string, n int) {
+	t.logger.Debug("rechecking transactions",
+		"kind", kind,
+		"num_txs", n,
+	)
+
+	labels := t.getMetricLabels()
+	labels["kind"] = kind
+	recheckedTransactions.With(labels).Add(float64(n))
+}
+
+// newRecheckTransactions prepares the given transactions taken from the given store for
+// rechecking.
+func newRecheckTransactions(q RecheckableTransactionStore, txs []*TxQueueMeta) []*PendingCheckTransaction {
+	pcts := make([]*PendingCheckTransaction, 0, len(txs))
+	for _, tx := range txs {
+		pcts = append(pcts, &PendingCheckTransaction{
+			TxQueueMeta: tx,
+			flags:       txCheckRecheck,
+			dstQueue:    q,
+		})
+	}
+	return pcts
+}
+
 // New creates a new transaction pool instance.
+//
+// If a common store is given and persistence is enabled in the configuration, schedulable
+// transactions are persisted in the store and restored when the transaction pool is started.
 func New(
 	runtimeID common.Namespace,
 	cfg config.Config,
 	runtime host.Runtime,
 	history history.History,
 	txPublisher TransactionPublisher,
+	commonStore *persistent.CommonStore,
 ) TransactionPool {
 	initMetrics()
 
@@ -893,6 +1477,12 @@ func New(
 	rq := newRimQueue()
 	lq := newLocalQueue()
 	mq := newMainQueue(int(cfg.MaxPoolSize))
+	mq.setLanes(cfg.Lanes)
+
+	var store *txStore
+	if commonStore != nil && cfg.Persistence.Enabled() {
+		store = newTxStore(commonStore, runtimeID, cfg.Persistence)
+	}
 
 	return &txPool{
 		logger:               logging.GetLogger("runtime/txpool"),
@@ -905,10 +1495,12 @@ func New(
 		history:              history,
 		txPublisher:          txPublisher,
 		seenCache:            seenCache,
+		senderCache:          lru.New(lru.Capacity(cfg.MaxLastSeenCacheSize, false)),
 		checkTxQueue:         newCheckTxQueue(maxCheckTxQueueSize, int(cfg.MaxCheckTxBatchSize)),
 		checkTxCh:            channels.NewRingChannel(1),
 		checkTxNotifier:      pubsub.NewBroker(false),
 		recheckTxCh:          channels.NewRingChannel(1),
+		rechecking:           make(map[hash.Hash]struct{}),
 		usableSources:        []UsableTransactionSource{rq, lq, mq},
 		recheckableStores:    []RecheckableTransactionStore{lq, mq},
 		republishableSources: []RepublishableTransactionSource{lq, mq},
@@ -916,6 +1508,9 @@ func New(
 		localQueue:           lq,
 		mainQueue:            mq,
 		proposedTxs:          make(map[hash.Hash]*TxQueueMeta),
+		schedulingStats:      newSchedulingStats(),
+		failures:             newFailureTracker(cfg.ExecutionFailures),
 		republishCh:          channels.NewRingChannel(1),
+		store:                store,
 	}
 }
@@ -0,0 +1,499 @@
+package txpool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool/config"
+)
+
+// newTestTxPool creates a transaction pool whose transaction checks are performed by a fake
+// check worker using the given check function against the current round.
+func newTestTxPool(tb testing.TB, cfg config.Config, checkFn func(*PendingCheckTransaction) protocol.CheckTxResult) *txPool {
+	tp := New(common.Namespace{}, cfg, nil, nil, nil, nil).(*txPool)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-tp.stopCh:
+				return
+			case <-tp.checkTxCh.Out():
+			}
+
+			bi, lastBlockProcessed, err := tp.getCurrentBlockInfo()
+			if err != nil {
+				panic(err)
+			}
+			for batch := tp.checkTxQueue.pop(); len(batch) > 0; batch = tp.checkTxQueue.pop() {
+				results := make([]protocol.CheckTxResult, 0, len(batch))
+				for _, pct := range batch {
+					results = append(results, checkFn(pct))
+				}
+				tp.handleCheckedBatch(batch, results, bi.RuntimeBlock.Header.Round, lastBlockProcessed)
+			}
+		}
+	}()
+	tb.Cleanup(func() {
+		tp.Stop()
+		wg.Wait()
+	})
+
+	return tp
+}
+
+func processTestRound(tp *txPool, round uint64) {
+	tp.ProcessBlock(&runtime.BlockInfo{
+		RuntimeBlock: &block.Block{
+			Header: block.Header{
+				HeaderType: block.Normal,
+				Round:      round,
+			},
+		},
+	})
+}
+
+// waitRechecked waits for the transactions taken out of the queues for rechecking to be checked.
+func waitRechecked(tb testing.TB, tp *txPool) {
+	require.Eventually(tb, func() bool {
+		tp.recheckingLock.Lock()
+		defer tp.recheckingLock.Unlock()
+		return len(tp.rechecking) == 0
+	}, 10*time.Second, time.Millisecond)
+}
+
+// rejectTx returns a check function that accepts all transactions except the given one.
+func rejectTx(tx []byte) func(*PendingCheckTransaction) protocol.CheckTxResult {
+	return func(pct *PendingCheckTransaction) protocol.CheckTxResult {
+		var result protocol.CheckTxResult
+		if string(pct.Raw()) == string(tx) {
+			result.Error = protocol.Error{Module: "test", Code: 1, Message: "rejected"}
+		}
+		return result
+	}
+}
+
+func TestSubmitTxBatch(t *testing.T) {
+	require := require.New(t)
+
+	tp := newTestTxPool(t, config.Config{
+		MaxPoolSize:          10,
+		MaxLastSeenCacheSize: 10,
+		MaxCheckTxBatchSize:  128,
+	}, rejectTx([]byte("bad")))
+	processTestRound(tp, 1)
+
+	// Mark a transaction as already seen.
+	_ = tp.seenCache.Put(hash.NewFromBytes([]byte("seen")), time.Time{})
+
+	txs := [][]byte{
+		[]byte("tx 0"),
+		[]byte("bad"),
+		[]byte("seen"),
+		[]byte("tx 0"),
+		[]byte("tx 1"),
+	}
+	results, err := tp.SubmitTxBatch(context.Background(), txs, &TransactionMeta{Local: true})
+	require.NoError(err, "SubmitTxBatch")
+	require.Len(results, len(txs), "there should be a result for each transaction")
+
+	require.True(results[0].IsSuccess(), "valid transaction should be accepted")
+	require.False(results[1].IsSuccess(), "invalid transaction should be rejected")
+	require.Equal("test", results[1].Error.Module)
+	require.False(results[2].IsSuccess(), "seen transaction should be rejected")
+	require.Equal(moduleName, results[2].Error.Module)
+	require.Contains(results[2].Error.Message, "duplicate transaction")
+	require.False(results[3].IsSuccess(), "duplicate transaction within batch should be rejected")
+	require.Contains(results[3].Error.Message, "duplicate transaction")
+	require.True(results[4].IsSuccess(), "valid transaction should be accepted")
+}
+
+func TestAddBatchToCheckQueue(t *testing.T) {
+	require := require.New(t)
+
+	// The check queue holds 110% of the pool size, i.e. 11 transactions.
+	tp := New(common.Namespace{}, config.Config{
+		MaxPoolSize:          10,
+		MaxLastSeenCacheSize: 10,
+		MaxCheckTxBatchSize:  128,
+	}, nil, nil, nil, nil).(*txPool)
+
+	added := tp.addBatchToCheckQueue(nil)
+	require.Zero(added, "empty batch should not be queued")
+
+	var pcts []*PendingCheckTransaction
+	for i := range 15 {
+		pcts = append(pcts, newPendingTx([]byte(fmt.Sprintf("tx %d", i))))
+	}
+	added = tp.addBatchToCheckQueue(pcts)
+	require.Equal(11, added, "only transactions that fit should be queued")
+	require.Equal(11, tp.PendingCheckSize())
+
+	added = tp.addBatchToCheckQueue(pcts[11:])
+	require.Zero(added, "nothing should be queued when the queue is full")
+}
+
+func BenchmarkSubmitTx(b *testing.B) {
+	const batchSize = 256
+
+	newTxs := func(n int) [][]byte {
+		txs := make([][]byte, 0, batchSize)
+		for i := range batchSize {
+			txs = append(txs, []byte(fmt.Sprintf("tx %d %d", n, i)))
+		}
+		return txs
+	}
+
+	b.Run("PerTx", func(b *testing.B) {
+		tp := newTestTxPool(b, config.Config{
+			MaxPoolSize:          uint64(b.N) * batchSize,
+			MaxLastSeenCacheSize: uint64(b.N) * batchSize,
+			MaxCheckTxBatchSize:  128,
+		}, rejectTx(nil))
+		ctx := context.Background()
+		processTestRound(tp, 1)
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			txs := newTxs(n)
+
+			var wg sync.WaitGroup
+			for _, tx := range txs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := tp.SubmitTx(ctx, tx, &TransactionMeta{Local: true}); err != nil {
+						b.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+		}
+	})
+
+	b.Run("Batch", func(b *testing.B) {
+		tp := newTestTxPool(b, config.Config{
+			MaxPoolSize:          uint64(b.N) * batchSize,
+			MaxLastSeenCacheSize: uint64(b.N) * batchSize,
+			MaxCheckTxBatchSize:  128,
+		}, rejectTx(nil))
+		ctx := context.Background()
+		processTestRound(tp, 1)
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if _, err := tp.SubmitTxBatch(ctx, newTxs(n), &TransactionMeta{Local: true}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// testSenderState is a fake runtime state that tracks sender sequence numbers and checks
+// transactions of the form "<sender>/<seq>/<tag>" against it.
+type testSenderState struct {
+	sync.Mutex
+
+	seqs      map[string]uint64
+	rechecked int
+}
+
+func senderTx(sender string, seq uint64, tag string) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%s", sender, seq, tag))
+}
+
+func (s *testSenderState) setSeq(sender string, seq uint64) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.seqs == nil {
+		s.seqs = make(map[string]uint64)
+	}
+	s.seqs[sender] = seq
+}
+
+func (s *testSenderState) numRechecked() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.rechecked
+}
+
+func (s *testSenderState) check(pct *PendingCheckTransaction) protocol.CheckTxResult {
+	s.Lock()
+	defer s.Unlock()
+
+	if pct.flags.isRecheck() {
+		s.rechecked++
+	}
+
+	parts := strings.SplitN(string(pct.Raw()), "/", 3)
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	stateSeq := s.seqs[parts[0]]
+	if seq < stateSeq {
+		return protocol.CheckTxResult{
+			Error: protocol.Error{Module: "test", Code: 1, Message: "invalid nonce"},
+		}
+	}
+	return protocol.CheckTxResult{
+		Meta: &protocol.CheckTxMetadata{
+			Sender:         []byte(parts[0]),
+			SenderSeq:      seq,
+			SenderStateSeq: stateSeq,
+		},
+	}
+}
+
+func TestRecheckExecutedSenders(t *testing.T) {
+	require := require.New(t)
+
+	state := &testSenderState{}
+	tp := newTestTxPool(t, config.Config{
+		MaxPoolSize:          100,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  128,
+		RecheckSweepRate:     1,
+	}, state.check)
+	ctx := context.Background()
+
+	processTestRound(tp, 1)
+
+	pending := [][]byte{
+		senderTx("alice", 0, "pending"),
+		senderTx("bob", 0, "pending"),
+		senderTx("carol", 1, "pending"),
+	}
+	results, err := tp.SubmitTxBatch(ctx, pending, &TransactionMeta{Local: false})
+	require.NoError(err, "SubmitTxBatch")
+	for _, result := range results {
+		require.True(result.IsSuccess(), "pending transactions should be accepted")
+	}
+
+	// Transactions from the same senders are included in a batch proposed by another node. They
+	// pass checks, but do not replace the pending transactions.
+	executed := [][]byte{
+		senderTx("alice", 0, "executed"),
+		senderTx("carol", 0, "executed"),
+	}
+	var executedHashes []hash.Hash
+	for _, tx := range executed {
+		_, err = tp.SubmitTx(ctx, tx, &TransactionMeta{Local: false})
+		require.NoError(err, "SubmitTx")
+		executedHashes = append(executedHashes, hash.NewFromBytes(tx))
+	}
+
+	// Execute the batch, invalidating the pending transaction from alice.
+	state.setSeq("alice", 1)
+	state.setSeq("carol", 1)
+	tp.HandleTxsUsed(executedHashes)
+	processTestRound(tp, 2)
+
+	// The invalidated transaction must not be proposed.
+	batch := tp.GetSchedulingSuggestion(10)
+	tp.FinishScheduling()
+	for _, tx := range batch {
+		require.NotEqual(pending[0], tx.Raw(), "invalidated transaction should not be proposed")
+	}
+
+	waitRechecked(t, tp)
+	require.Nil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(pending[0])), "invalidated transaction should be removed")
+	require.NotNil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(pending[1])), "unrelated transaction should be kept")
+	require.NotNil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(pending[2])), "still valid transaction should be kept")
+	require.Equal(2, state.numRechecked(), "only transactions from executed senders should be rechecked")
+}
+
+func TestRecheckSweep(t *testing.T) {
+	require := require.New(t)
+
+	state := &testSenderState{}
+	tp := newTestTxPool(t, config.Config{
+		MaxPoolSize:          100,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  128,
+		RecheckSweepRate:     3,
+	}, state.check)
+	ctx := context.Background()
+
+	processTestRound(tp, 1)
+
+	var txs [][]byte
+	for i := range 10 {
+		txs = append(txs, senderTx(fmt.Sprintf("sender %d", i), 0, "pending"))
+	}
+	_, err := tp.SubmitTxBatch(ctx, txs, &TransactionMeta{Local: false})
+	require.NoError(err, "SubmitTxBatch")
+
+	processTestRound(tp, 2)
+
+	countChecked := func(round uint64) int {
+		var n int
+		for _, tx := range tp.mainQueue.inner.getAll() {
+			if tx.checkedRound == round {
+				n++
+			}
+		}
+		return n
+	}
+	for i := 1; i <= 3; i++ {
+		tp.recheckSweep()
+		require.Equal(3*i, state.numRechecked(), "sweep should recheck the configured number of transactions")
+		require.Equal(3*i, countChecked(2), "sweep should recheck the least recently checked transactions")
+	}
+	require.Equal(10, tp.mainQueue.inner.size(), "valid transactions should be kept")
+
+	// Invalidated transactions should eventually be removed by the sweep.
+	state.setSeq("sender 0", 1)
+	processTestRound(tp, 3)
+	for range 4 {
+		tp.recheckSweep()
+	}
+	require.Nil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(txs[0])), "invalidated transaction should be removed")
+}
+
+func BenchmarkRecheck(b *testing.B) {
+	const (
+		poolSize     = 10_000
+		executedSize = 100
+		sweepRate    = 500
+	)
+
+	run := func(b *testing.B, cfg config.Config, recheck func(*txPool)) {
+		state := &testSenderState{}
+		cfg.MaxPoolSize = poolSize
+		cfg.MaxLastSeenCacheSize = 2 * poolSize
+		cfg.MaxCheckTxBatchSize = 128
+		tp := newTestTxPool(b, cfg, state.check)
+		ctx := context.Background()
+
+		processTestRound(tp, 1)
+
+		sender := func(i int) string {
+			return fmt.Sprintf("sender %d", i)
+		}
+		var txs [][]byte
+		for i := range poolSize {
+			txs = append(txs, senderTx(sender(i), 0, "pending"))
+		}
+		if _, err := tp.SubmitTxBatch(ctx, txs, &TransactionMeta{Local: false}); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			round := uint64(n + 2)
+			seq := uint64(n + 1)
+
+			// Execute a batch proposed by another node with transactions from some senders.
+			var executed, pending [][]byte
+			var executedHashes []hash.Hash
+			for i := range executedSize {
+				s := sender((n*executedSize + i) % poolSize)
+				executed = append(executed, senderTx(s, seq-1, "executed"))
+				pending = append(pending, senderTx(s, seq, "pending"))
+				executedHashes = append(executedHashes, hash.NewFromBytes(executed[i]))
+			}
+			if _, err := tp.SubmitTxBatch(ctx, executed, &TransactionMeta{Local: false}); err != nil {
+				b.Fatal(err)
+			}
+			for i := range executedSize {
+				state.setSeq(sender((n*executedSize+i)%poolSize), seq)
+			}
+
+			tp.HandleTxsUsed(executedHashes)
+			processTestRound(tp, round)
+			recheck(tp)
+			waitRechecked(b, tp)
+
+			// Senders submit their next transactions.
+			if _, err := tp.SubmitTxBatch(ctx, pending, &TransactionMeta{Local: false}); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+
+		b.ReportMetric(float64(state.numRechecked())/float64(b.N), "rechecks/round")
+	}
+
+	b.Run("Full", func(b *testing.B) {
+		run(b, config.Config{RecheckInterval: 0}, (*txPool).recheck)
+	})
+
+	b.Run("Incremental", func(b *testing.B) {
+		run(b, config.Config{RecheckSweepRate: sweepRate}, (*txPool).recheckSweep)
+	})
+}
+
+func TestExpiredTransactions(t *testing.T) {
+	require := require.New(t)
+
+	const expiryRound = 10
+
+	tp := newTestTxPool(t, config.Config{
+		MaxPoolSize:          100,
+		MaxLastSeenCacheSize: 100,
+		MaxCheckTxBatchSize:  128,
+	}, func(pct *PendingCheckTransaction) protocol.CheckTxResult {
+		var meta protocol.CheckTxMetadata
+		if strings.HasPrefix(string(pct.Raw()), "expiring") {
+			meta.ExpiryRound = expiryRound
+		}
+		return protocol.CheckTxResult{Meta: &meta}
+	})
+	ctx := context.Background()
+
+	isProposed := func(tx []byte) bool {
+		batch := tp.GetSchedulingSuggestion(10)
+		tp.FinishScheduling()
+		for _, btx := range batch {
+			if string(btx.Raw()) == string(tx) {
+				return true
+			}
+		}
+		return false
+	}
+
+	expiring := []byte("expiring")
+	other := []byte("other")
+
+	processTestRound(tp, expiryRound-2)
+	results, err := tp.SubmitTxBatch(ctx, [][]byte{expiring, other}, &TransactionMeta{Local: false})
+	require.NoError(err, "SubmitTxBatch")
+	for _, result := range results {
+		require.True(result.IsSuccess(), "transactions should be accepted")
+	}
+
+	// The next batch is for the round before expiry, so the transaction can still be proposed.
+	require.True(isProposed(expiring), "transaction should be proposed before expiry")
+
+	// Once the next batch is for the expiry round, the transaction must never be proposed again.
+	for round := uint64(expiryRound - 1); round < expiryRound+2; round++ {
+		processTestRound(tp, round)
+		require.False(isProposed(expiring), "transaction should not be proposed at round %d", round+1)
+		require.True(isProposed(other), "transaction without expiry should be proposed")
+	}
+	require.Nil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(expiring)), "expired transaction should be evicted")
+	require.Equal(1, tp.mainQueue.inner.size(), "transaction without expiry should be kept")
+
+	// Resubmitting an expired transaction is rejected as it is still in the seen cache.
+	_, err = tp.SubmitTx(ctx, expiring, &TransactionMeta{Local: false})
+	require.ErrorContains(err, "duplicate transaction", "SubmitTx")
+	require.Nil(tp.mainQueue.GetTxByHash(hash.NewFromBytes(expiring)), "expired transaction should not be readmitted")
+}
@@ -363,6 +363,12 @@ type Backend interface {
 	// be sent immediately.
 	WatchCommittees(ctx context.Context) (<-chan *Committee, pubsub.ClosableSubscription, error)
 
+	// GetElectionInputs returns the inputs that were used to elect the committee of the given
+	// kind for the given runtime, valid at the specified block height.
+	//
+	// Election inputs are only available if they are recorded (see ElectionInputsRetention).
+	GetElectionInputs(ctx context.Context, request *GetElectionInputsRequest) (*ElectionInputs, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
@@ -415,6 +421,10 @@ type ConsensusParameters struct {
 
 	// VotingPowerDistribution is the voting power distribution.
 	VotingPowerDistribution VotingPowerDistribution `json:"voting_power_distribution,omitempty"`
+
+	// ElectionInputsRetention is the number of epochs for which committee election inputs are
+	// kept in state. Zero disables recording of election inputs.
+	ElectionInputsRetention uint64 `json:"election_inputs_retention,omitempty"`
 }
 
 // ConsensusParameterChanges are allowed scheduler consensus parameter changes.
@@ -427,6 +437,9 @@ type ConsensusParameterChanges struct {
 
 	// VotingPowerDistribution is the new voting power distribution.
 	VotingPowerDistribution *VotingPowerDistribution `json:"voting_power_distribution,omitempty"`
+
+	// ElectionInputsRetention is the new number of epochs for which election inputs are kept.
+	ElectionInputsRetention *uint64 `json:"election_inputs_retention,omitempty"`
 }
 
 // Apply applies changes to the given consensus parameters.
@@ -440,6 +453,9 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.VotingPowerDistribution != nil {
 		params.VotingPowerDistribution = *c.VotingPowerDistribution
 	}
+	if c.ElectionInputsRetention != nil {
+		params.ElectionInputsRetention = *c.ElectionInputsRetention
+	}
 	return nil
 }
 
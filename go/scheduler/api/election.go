@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+// ErrNoElectionInputs is the error returned when no election inputs are available for the given
+// committee.
+var ErrNoElectionInputs = errors.New(ModuleName, 1, "scheduler: no election inputs available")
+
+// ElectionPolicy is the policy used to weigh candidates during committee elections.
+type ElectionPolicy uint8
+
+const (
+	// ElectionPolicyUniform elects committee members uniformly at random among all eligible
+	// candidates, regardless of their stake.
+	ElectionPolicyUniform ElectionPolicy = 0
+	// ElectionPolicyStakeWeighted elects committee members with probability proportional to the
+	// escrow balance of the entity controlling the candidate.
+	ElectionPolicyStakeWeighted ElectionPolicy = 1
+	// ElectionPolicyCappedWeight elects committee members with probability proportional to the
+	// escrow balance of the entity controlling the candidate, with the weight of each candidate
+	// capped at a maximum fraction of the total weight.
+	ElectionPolicyCappedWeight ElectionPolicy = 2
+
+	// MaxElectionPolicy is the highest supported election policy.
+	MaxElectionPolicy = ElectionPolicyCappedWeight
+
+	ElectionPolicyUniformName       = "uniform"
+	ElectionPolicyStakeWeightedName = "stake-weighted"
+	ElectionPolicyCappedWeightName  = "capped-weight"
+)
+
+// String returns a string representation of an ElectionPolicy.
+func (p ElectionPolicy) String() string {
+	name, err := p.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("[unknown election policy: %d]", p)
+	}
+	return string(name)
+}
+
+// MarshalText encodes an ElectionPolicy into text form.
+func (p ElectionPolicy) MarshalText() ([]byte, error) {
+	switch p {
+	case ElectionPolicyUniform:
+		return []byte(ElectionPolicyUniformName), nil
+	case ElectionPolicyStakeWeighted:
+		return []byte(ElectionPolicyStakeWeightedName), nil
+	case ElectionPolicyCappedWeight:
+		return []byte(ElectionPolicyCappedWeightName), nil
+	default:
+		return nil, fmt.Errorf("invalid election policy: %d", p)
+	}
+}
+
+// UnmarshalText decodes a text slice into an ElectionPolicy.
+func (p *ElectionPolicy) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case ElectionPolicyUniformName:
+		*p = ElectionPolicyUniform
+	case ElectionPolicyStakeWeightedName:
+		*p = ElectionPolicyStakeWeighted
+	case ElectionPolicyCappedWeightName:
+		*p = ElectionPolicyCappedWeight
+	default:
+		return fmt.Errorf("invalid election policy: %s", string(text))
+	}
+	return nil
+}
+
+// GetElectionInputsRequest is a GetElectionInputs request.
+type GetElectionInputsRequest struct {
+	// Height is the consensus block height at which the committee is queried.
+	Height int64 `json:"height"`
+	// RuntimeID is the identifier of the runtime the committee is elected for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Kind is the committee kind.
+	Kind CommitteeKind `json:"kind"`
+}
+
+// ElectionInputs are the inputs that were used to elect a committee, sufficient to independently
+// reproduce the election.
+type ElectionInputs struct {
+	// Epoch is the epoch for which the committee was elected.
+	Epoch beacon.EpochTime `json:"epoch"`
+	// RuntimeID is the identifier of the runtime the committee was elected for.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Kind is the committee kind.
+	Kind CommitteeKind `json:"kind"`
+
+	// VRF is true iff the election used VRF proofs submitted by the candidates. Otherwise the
+	// election used the per-epoch entropy.
+	VRF bool `json:"vrf,omitempty"`
+	// Entropy is the per-epoch entropy used in case the election did not use VRF proofs.
+	Entropy []byte `json:"entropy,omitempty"`
+
+	// Policy is the election policy used to weigh the candidates.
+	Policy ElectionPolicy `json:"policy,omitempty"`
+	// MaxWeightPercent is the maximum weight of a single candidate as a percentage of the total capped
+	// weight in case the capped-weight election policy was used.
+	MaxWeightPercent uint8 `json:"max_weight_percent,omitempty"`
+
+	// SuspendedNodes are the nodes that were not eligible for election because they were
+	// suspended for the runtime due to faults.
+	SuspendedNodes []signature.PublicKey `json:"suspended_nodes,omitempty"`
+
+	// Roles are the per-role election inputs, in election order.
+	Roles []*RoleElectionInputs `json:"roles"`
+}
+
+// RoleElectionInputs are the inputs that were used to elect the members of a committee with
+// a given role.
+type RoleElectionInputs struct {
+	// Role is the committee role.
+	Role Role `json:"role"`
+	// GroupSize is the number of nodes elected to the role.
+	GroupSize uint16 `json:"group_size"`
+	// MaxNodesPerEntity is the maximum number of nodes per entity that can be elected to the
+	// role. Zero means that there is no limit.
+	MaxNodesPerEntity uint16 `json:"max_nodes_per_entity,omitempty"`
+	// MinPoolSize is the minimum number of eligible candidates required for the election.
+	MinPoolSize uint16 `json:"min_pool_size,omitempty"`
+	// Candidates are the eligible candidates, in the order considered by the election.
+	Candidates []*ElectionCandidate `json:"candidates"`
+}
+
+// ElectionCandidate is a node eligible for election.
+type ElectionCandidate struct {
+	// ID is the node identifier.
+	ID signature.PublicKey `json:"id"`
+	// EntityID is the identifier of the entity controlling the node.
+	EntityID signature.PublicKey `json:"entity_id"`
+	// Stake is the escrow balance of the entity at election time.
+	Stake quantity.Quantity `json:"stake"`
+	// Beta is the VRF output of the node's proof in case the election used VRF proofs.
+	Beta []byte `json:"beta,omitempty"`
+}
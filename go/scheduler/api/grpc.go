@@ -17,6 +17,8 @@ var (
 	methodGetValidators = serviceName.NewMethod("GetValidators", int64(0))
 	// methodGetCommittees is the GetCommittees method.
 	methodGetCommittees = serviceName.NewMethod("GetCommittees", GetCommitteesRequest{})
+	// methodGetElectionInputs is the GetElectionInputs method.
+	methodGetElectionInputs = serviceName.NewMethod("GetElectionInputs", GetElectionInputsRequest{})
 	// methodStateToGenesis is the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
 	// methodConsensusParameters is the ConsensusParameters method.
@@ -38,6 +40,10 @@ var (
 				MethodName: methodGetCommittees.ShortName(),
 				Handler:    handlerGetCommittees,
 			},
+			{
+				MethodName: methodGetElectionInputs.ShortName(),
+				Handler:    handlerGetElectionInputs,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -103,6 +109,29 @@ func handlerGetCommittees(
 	return interceptor(ctx, &req, info, handler)
 }
 
+func handlerGetElectionInputs(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req GetElectionInputsRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetElectionInputs(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetElectionInputs.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetElectionInputs(ctx, req.(*GetElectionInputsRequest))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func handlerStateToGenesis(
 	srv any,
 	ctx context.Context,
@@ -210,6 +239,14 @@ func (c *Client) GetCommittees(ctx context.Context, request *GetCommitteesReques
 	return rsp, nil
 }
 
+func (c *Client) GetElectionInputs(ctx context.Context, request *GetElectionInputsRequest) (*ElectionInputs, error) {
+	var rsp ElectionInputs
+	if err := c.conn.Invoke(ctx, methodGetElectionInputs.FullName(), request, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -43,7 +43,8 @@ func (p *ConsensusParameters) SanityCheck() error {
 func (c *ConsensusParameterChanges) SanityCheck() error {
 	if c.MinValidators == nil &&
 		c.MaxValidators == nil &&
-		c.VotingPowerDistribution == nil {
+		c.VotingPowerDistribution == nil &&
+		c.ElectionInputsRetention == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
 	return nil
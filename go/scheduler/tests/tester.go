@@ -91,6 +91,13 @@ func SchedulerImplementationTests(t *testing.T, name string, identity *identity.
 		}
 
 		require.Nil(executor, "fetched an executor committee")
+
+		_, err = scheduler.GetElectionInputs(context.Background(), &api.GetElectionInputsRequest{
+			Height:    consensusAPI.HeightLatest,
+			RuntimeID: rt.Runtime.ID,
+			Kind:      api.KindComputeExecutor,
+		})
+		require.ErrorIs(err, api.ErrNoElectionInputs, "election inputs should not be recorded by default")
 	}
 
 	var nExecutor int
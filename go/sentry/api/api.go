@@ -3,9 +3,11 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/accessctl"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 )
@@ -21,8 +23,64 @@ type ServicePolicies struct {
 	AccessPolicies map[common.Namespace]accessctl.Policy `json:"access_policies"`
 }
 
+// UpstreamState is the state of an upstream node as seen by the sentry node.
+type UpstreamState string
+
+const (
+	// UpstreamStateUnknown is the state of an upstream node that has not yet contacted the
+	// sentry node.
+	UpstreamStateUnknown UpstreamState = "unknown"
+	// UpstreamStateConnected is the state of an upstream node that has successfully fetched
+	// the sentry node's addresses.
+	UpstreamStateConnected UpstreamState = "connected"
+)
+
+// UpstreamStatus is the status of an upstream node authorized to use the sentry node.
+type UpstreamStatus struct {
+	// PubKey is the TLS public key of the upstream node.
+	PubKey signature.PublicKey `json:"pub_key"`
+
+	// State is the state of the upstream node.
+	State UpstreamState `json:"state"`
+
+	// CertificateFingerprint is the hex-encoded SHA-256 fingerprint of the last TLS certificate
+	// presented by the upstream node.
+	CertificateFingerprint string `json:"certificate_fingerprint,omitempty"`
+
+	// CertificateExpiration is the expiration time of the last TLS certificate presented by the
+	// upstream node.
+	CertificateExpiration time.Time `json:"certificate_expiration,omitempty"`
+
+	// LastFetch is the time of the last successful address fetch by the upstream node.
+	LastFetch time.Time `json:"last_fetch,omitempty"`
+
+	// NumFetches is the number of successful address fetches by the upstream node.
+	NumFetches uint64 `json:"num_fetches"`
+}
+
+// ConsensusUpstreamStatus is the status of a consensus upstream node.
+type ConsensusUpstreamStatus struct {
+	// Address is the configured consensus address of the upstream node.
+	Address string `json:"address"`
+
+	// Connected is true iff the sentry node is connected to the upstream consensus peer.
+	Connected bool `json:"connected"`
+}
+
+// Status is the sentry node status.
+type Status struct {
+	// Upstreams are the statuses of the upstream nodes authorized to use the sentry node.
+	Upstreams []UpstreamStatus `json:"upstreams"`
+
+	// ConsensusUpstreams are the statuses of the configured consensus upstream nodes.
+	ConsensusUpstreams []ConsensusUpstreamStatus `json:"consensus_upstreams"`
+}
+
 // Backend is a sentry backend implementation.
 type Backend interface {
 	// Get addresses returns the list of consensus and TLS addresses of the sentry node.
 	GetAddresses(context.Context) (*SentryAddresses, error)
+
+	// GetStatus returns the sentry node status.
+	GetStatus(context.Context) (*Status, error)
 }
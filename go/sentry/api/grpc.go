@@ -14,6 +14,8 @@ var (
 
 	// methodGetAddresses is the GetAddresses method.
 	methodGetAddresses = serviceName.NewMethod("GetAddresses", nil)
+	// methodGetStatus is the GetStatus method.
+	methodGetStatus = serviceName.NewMethod("GetStatus", nil)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -24,6 +26,10 @@ var (
 				MethodName: methodGetAddresses.ShortName(),
 				Handler:    handlerGetAddresses,
 			},
+			{
+				MethodName: methodGetStatus.ShortName(),
+				Handler:    handlerGetStatus,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -48,6 +54,25 @@ func handlerGetAddresses(
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetStatus(
+	srv any,
+	ctx context.Context,
+	_ func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	if interceptor == nil {
+		return srv.(Backend).GetStatus(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetStatus.FullName(),
+	}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return srv.(Backend).GetStatus(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 // RegisterService registers a new sentry service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
@@ -72,3 +97,11 @@ func (c *Client) GetAddresses(ctx context.Context) (*SentryAddresses, error) {
 	}
 	return &rsp, nil
 }
+
+func (c *Client) GetStatus(ctx context.Context) (*Status, error) {
+	var rsp Status
+	if err := c.conn.Invoke(ctx, methodGetStatus.FullName(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
@@ -0,0 +1,51 @@
+package sentry
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-core/go/oasis-node/cmd/common/metrics"
+)
+
+var (
+	upstreamFetches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_sentry_upstream_fetches",
+			Help: "Number of successful address fetches by the upstream node.",
+		},
+		[]string{"upstream"},
+	)
+	upstreamLastFetch = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_sentry_upstream_last_fetch",
+			Help: "UNIX timestamp of the last successful address fetch by the upstream node.",
+		},
+		[]string{"upstream"},
+	)
+	consensusUpstreamConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_sentry_consensus_upstream_connected",
+			Help: "Whether the sentry node is connected to the consensus upstream node.",
+		},
+		[]string{"upstream"},
+	)
+	sentryCollectors = []prometheus.Collector{
+		upstreamFetches,
+		upstreamLastFetch,
+		consensusUpstreamConnected,
+	}
+
+	metricsOnce sync.Once
+)
+
+// initMetrics registers the metrics collectors if metrics are enabled.
+func initMetrics() {
+	if !metrics.Enabled() {
+		return
+	}
+
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(sentryCollectors...)
+	})
+}
@@ -3,12 +3,23 @@ package sentry
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmtCrypto "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/crypto"
 	"github.com/oasisprotocol/oasis-core/go/sentry/api"
 )
 
@@ -21,9 +32,13 @@ type backend struct {
 
 	consensus consensus.Service
 	identity  *identity.Identity
+
+	upstreams          map[signature.PublicKey]*api.UpstreamStatus
+	upstreamOrder      []signature.PublicKey
+	consensusUpstreams []node.ConsensusAddress
 }
 
-func (b *backend) GetAddresses(context.Context) (*api.SentryAddresses, error) {
+func (b *backend) GetAddresses(ctx context.Context) (*api.SentryAddresses, error) {
 	// Consensus addresses.
 	consensusAddrs, err := b.consensus.GetAddresses()
 	if err != nil {
@@ -33,24 +48,141 @@ func (b *backend) GetAddresses(context.Context) (*api.SentryAddresses, error) {
 		"addresses", consensusAddrs,
 	)
 
+	b.recordFetch(ctx)
+
 	return &api.SentryAddresses{
 		Consensus: consensusAddrs,
 	}, nil
 }
 
+func (b *backend) GetStatus(ctx context.Context) (*api.Status, error) {
+	status := &api.Status{
+		Upstreams:          make([]api.UpstreamStatus, 0, len(b.upstreamOrder)),
+		ConsensusUpstreams: make([]api.ConsensusUpstreamStatus, 0, len(b.consensusUpstreams)),
+	}
+
+	b.RLock()
+	for _, pk := range b.upstreamOrder {
+		status.Upstreams = append(status.Upstreams, *b.upstreams[pk])
+	}
+	b.RUnlock()
+
+	if len(b.consensusUpstreams) == 0 {
+		return status, nil
+	}
+
+	cs, err := b.consensus.Core().GetStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sentry: error obtaining consensus status: %w", err)
+	}
+	peers := make(map[string]bool)
+	if cs.P2P != nil {
+		for _, p := range cs.P2P.Peers {
+			id, _, _ := strings.Cut(p, "@")
+			peers[strings.ToLower(id)] = true
+		}
+	}
+	for _, addr := range b.consensusUpstreams {
+		id := strings.ToLower(cmtCrypto.PublicKeyToCometBFT(&addr.ID).Address().String())
+		connected := peers[id]
+		status.ConsensusUpstreams = append(status.ConsensusUpstreams, api.ConsensusUpstreamStatus{
+			Address:   addr.String(),
+			Connected: connected,
+		})
+
+		var v float64
+		if connected {
+			v = 1
+		}
+		consensusUpstreamConnected.WithLabelValues(addr.ID.String()).Set(v)
+	}
+
+	return status, nil
+}
+
+// recordFetch records a successful address fetch by the upstream node identified by the
+// TLS certificate of the caller.
+func (b *backend) recordFetch(ctx context.Context) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return
+	}
+	cert := tlsInfo.State.PeerCertificates[0]
+	rawPk, ok := cert.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return
+	}
+	var pk signature.PublicKey
+	if err := pk.UnmarshalBinary(rawPk); err != nil {
+		return
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	b.Lock()
+	defer b.Unlock()
+
+	us, ok := b.upstreams[pk]
+	if !ok {
+		b.logger.Warn("address fetch by unknown upstream",
+			"pub_key", pk,
+		)
+		return
+	}
+	if fp := hex.EncodeToString(fingerprint[:]); fp != us.CertificateFingerprint {
+		if us.CertificateFingerprint != "" {
+			b.logger.Info("upstream TLS certificate rotated",
+				"pub_key", pk,
+				"fingerprint", fp,
+				"expiration", cert.NotAfter,
+			)
+		}
+		us.CertificateFingerprint = fp
+		us.CertificateExpiration = cert.NotAfter
+	}
+	us.State = api.UpstreamStateConnected
+	us.LastFetch = time.Now()
+	us.NumFetches++
+
+	upstreamFetches.WithLabelValues(pk.String()).Inc()
+	upstreamLastFetch.WithLabelValues(pk.String()).SetToCurrentTime()
+}
+
 // New constructs a new sentry backend instance.
+//
+// The upstreams are the TLS public keys of the upstream nodes authorized to use the sentry node
+// and the consensus upstreams are the configured consensus addresses of the upstream nodes.
 func New(
 	consensus consensus.Service,
 	identity *identity.Identity,
+	upstreams []signature.PublicKey,
+	consensusUpstreams []node.ConsensusAddress,
 ) (api.Backend, error) {
 	if consensus == nil {
 		return nil, fmt.Errorf("sentry: consensus backend is nil")
 	}
 
+	initMetrics()
+
 	b := &backend{
-		logger:    logging.GetLogger("sentry"),
-		consensus: consensus,
-		identity:  identity,
+		logger:             logging.GetLogger("sentry"),
+		consensus:          consensus,
+		identity:           identity,
+		upstreams:          make(map[signature.PublicKey]*api.UpstreamStatus),
+		consensusUpstreams: consensusUpstreams,
+	}
+	for _, pk := range upstreams {
+		if _, ok := b.upstreams[pk]; ok {
+			continue
+		}
+		b.upstreams[pk] = &api.UpstreamStatus{
+			PubKey: pk,
+			State:  api.UpstreamStateUnknown,
+		}
+		b.upstreamOrder = append(b.upstreamOrder, pk)
 	}
 
 	return b, nil
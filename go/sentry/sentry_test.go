@@ -0,0 +1,152 @@
+package sentry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	tlsCert "github.com/oasisprotocol/oasis-core/go/common/crypto/tls"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	cmtCrypto "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/crypto"
+	"github.com/oasisprotocol/oasis-core/go/sentry/api"
+)
+
+type testConsensus struct {
+	consensus.Service
+
+	core *testConsensusCore
+}
+
+func (c *testConsensus) GetAddresses() ([]node.ConsensusAddress, error) {
+	return nil, nil
+}
+
+func (c *testConsensus) Core() consensus.Backend {
+	return c.core
+}
+
+type testConsensusCore struct {
+	consensus.Backend
+
+	peers []string
+}
+
+func (c *testConsensusCore) GetStatus(context.Context) (*consensus.Status, error) {
+	return &consensus.Status{
+		P2P: &consensus.P2PStatus{
+			Peers: c.peers,
+		},
+	}, nil
+}
+
+func withPeerCertificate(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+			},
+		},
+	})
+}
+
+func TestUpstreamStatus(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	upstreamCert, err := tlsCert.Generate(identity.CommonName)
+	require.NoError(err, "Generate")
+	cert, err := x509.ParseCertificate(upstreamCert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	var upstream signature.PublicKey
+	require.NoError(upstream.UnmarshalBinary(cert.PublicKey.(ed25519.PublicKey)))
+
+	otherCert, err := tlsCert.Generate(identity.CommonName)
+	require.NoError(err, "Generate")
+	unknownCert, err := x509.ParseCertificate(otherCert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+
+	var consensusUpstream, otherConsensusUpstream node.ConsensusAddress
+	require.NoError(consensusUpstream.UnmarshalText([]byte("4yEh7tEB2XVCQqwdxRQaHvP7b3BJZV1j/EMRLmH8hnE=@127.0.0.1:26656")))
+	require.NoError(otherConsensusUpstream.UnmarshalText([]byte("2kDTUxa6Lr/VYZxhLUwsTB1BD8fh8sIUThNwgVFFWxA=@127.0.0.1:26657")))
+	peerID := strings.ToLower(cmtCrypto.PublicKeyToCometBFT(&consensusUpstream.ID).Address().String())
+
+	cons := &testConsensus{
+		core: &testConsensusCore{
+			peers: []string{peerID + "@127.0.0.1:26656"},
+		},
+	}
+	b, err := New(cons, nil, []signature.PublicKey{upstream}, []node.ConsensusAddress{consensusUpstream, otherConsensusUpstream})
+	require.NoError(err, "New")
+
+	// Upstream has not contacted the sentry yet.
+	status, err := b.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.Len(status.Upstreams, 1)
+	require.Equal(upstream, status.Upstreams[0].PubKey)
+	require.Equal(api.UpstreamStateUnknown, status.Upstreams[0].State)
+	require.Empty(status.Upstreams[0].CertificateFingerprint)
+	require.Equal([]api.ConsensusUpstreamStatus{
+		{Address: consensusUpstream.String(), Connected: true},
+		{Address: otherConsensusUpstream.String(), Connected: false},
+	}, status.ConsensusUpstreams)
+
+	// Fetches from unknown upstreams should be ignored.
+	_, err = b.GetAddresses(withPeerCertificate(unknownCert))
+	require.NoError(err, "GetAddresses")
+	status, err = b.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.Len(status.Upstreams, 1)
+	require.Equal(api.UpstreamStateUnknown, status.Upstreams[0].State)
+
+	// Fetch by the upstream.
+	_, err = b.GetAddresses(withPeerCertificate(cert))
+	require.NoError(err, "GetAddresses")
+
+	status, err = b.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	us := status.Upstreams[0]
+	fp := sha256.Sum256(cert.Raw)
+	require.Equal(api.UpstreamStateConnected, us.State)
+	require.Equal(hex.EncodeToString(fp[:]), us.CertificateFingerprint)
+	require.True(cert.NotAfter.Equal(us.CertificateExpiration))
+	require.EqualValues(1, us.NumFetches)
+	require.False(us.LastFetch.IsZero())
+	lastFetch := us.LastFetch
+
+	// Upstream rotates its TLS certificate, the sentry should pick up the new one.
+	template := *cert
+	template.SerialNumber = big.NewInt(2)
+	template.NotAfter = cert.NotAfter.Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, cert.PublicKey, upstreamCert.PrivateKey)
+	require.NoError(err, "CreateCertificate")
+	rotated, err := x509.ParseCertificate(der)
+	require.NoError(err, "ParseCertificate")
+	_, err = b.GetAddresses(withPeerCertificate(rotated))
+	require.NoError(err, "GetAddresses")
+
+	status, err = b.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	us = status.Upstreams[0]
+	fp = sha256.Sum256(rotated.Raw)
+	require.Equal(upstream, us.PubKey)
+	require.Equal(api.UpstreamStateConnected, us.State)
+	require.Equal(hex.EncodeToString(fp[:]), us.CertificateFingerprint)
+	require.True(rotated.NotAfter.Equal(us.CertificateExpiration))
+	require.EqualValues(2, us.NumFetches)
+	require.False(us.LastFetch.Before(lastFetch))
+}
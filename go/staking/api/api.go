@@ -84,6 +84,11 @@ var (
 	MethodAllow = transaction.NewMethodName(ModuleName, "Allow", Allow{})
 	// MethodWithdraw is the method name for
 	MethodWithdraw = transaction.NewMethodName(ModuleName, "Withdraw", Withdraw{})
+	// MethodDelegateFromAllowance is the method name for escrowing stake from an allowance.
+	MethodDelegateFromAllowance = transaction.NewMethodName(ModuleName, "DelegateFromAllowance", DelegateFromAllowance{})
+	// MethodReclaimEscrowFromAllowance is the method name for reclaiming stake escrowed from an
+	// allowance.
+	MethodReclaimEscrowFromAllowance = transaction.NewMethodName(ModuleName, "ReclaimEscrowFromAllowance", ReclaimEscrowFromAllowance{})
 
 	// Methods is the list of all methods supported by the staking backend.
 	Methods = []transaction.MethodName{
@@ -94,6 +99,8 @@ var (
 		MethodAmendCommissionSchedule,
 		MethodAllow,
 		MethodWithdraw,
+		MethodDelegateFromAllowance,
+		MethodReclaimEscrowFromAllowance,
 	}
 
 	_ prettyprint.PrettyPrinter = (*Transfer)(nil)
@@ -103,6 +110,8 @@ var (
 	_ prettyprint.PrettyPrinter = (*AmendCommissionSchedule)(nil)
 	_ prettyprint.PrettyPrinter = (*Allow)(nil)
 	_ prettyprint.PrettyPrinter = (*Withdraw)(nil)
+	_ prettyprint.PrettyPrinter = (*DelegateFromAllowance)(nil)
+	_ prettyprint.PrettyPrinter = (*ReclaimEscrowFromAllowance)(nil)
 	_ prettyprint.PrettyPrinter = (*SharePool)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeThreshold)(nil)
 	_ prettyprint.PrettyPrinter = (*StakeAccumulator)(nil)
@@ -120,6 +129,10 @@ type Backend interface {
 	// 1 token = 10**TokenValueExponent base units.
 	TokenValueExponent(ctx context.Context, height int64) (uint8, error)
 
+	// GetTokenInfo returns the token's metadata (ticker symbol and value
+	// base-10 exponent).
+	GetTokenInfo(ctx context.Context, height int64) (*TokenInfo, error)
+
 	// TotalSupply returns the total number of base units.
 	TotalSupply(ctx context.Context, height int64) (*quantity.Quantity, error)
 
@@ -173,6 +186,24 @@ type Backend interface {
 	// Allowance looks up the allowance for the given owner/beneficiary combination.
 	Allowance(ctx context.Context, query *AllowanceQuery) (*quantity.Quantity, error)
 
+	// GetAllowanceHistory returns the recorded allowance changes for the given owner/beneficiary
+	// combination, ordered from oldest to newest.
+	//
+	// Allowance changes are only available if they are recorded (see the staking consensus
+	// parameter MaxAllowanceHistory).
+	GetAllowanceHistory(ctx context.Context, query *AllowanceHistoryQuery) ([]*AllowanceHistoryEntry, error)
+
+	// CommissionAt returns the commission rate of the given account at the given (possibly
+	// future) epoch according to its current commission schedule or nil if no rate step has
+	// started by then.
+	CommissionAt(ctx context.Context, query *CommissionAtQuery) (*quantity.Quantity, error)
+
+	// ValidateScheduleUpdate checks whether the given commission schedule amendment of the given
+	// account would be accepted at the current epoch and returns the resulting schedule.
+	//
+	// Only the commission schedule rules are checked, not the stake requirements.
+	ValidateScheduleUpdate(ctx context.Context, query *ScheduleUpdateQuery) (*CommissionSchedule, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
@@ -182,6 +213,10 @@ type Backend interface {
 	// GetEvents returns the events at specified block height.
 	GetEvents(ctx context.Context, height int64) ([]*Event, error)
 
+	// GetEventProof returns the given event at the specified block height together with a proof
+	// that it has been emitted in that block.
+	GetEventProof(ctx context.Context, query *EventProofQuery) (*EventProof, error)
+
 	// WatchEvents returns a channel that produces a stream of Events.
 	WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error)
 }
@@ -205,6 +240,51 @@ type AllowanceQuery struct {
 	Beneficiary Address `json:"beneficiary"`
 }
 
+// AllowanceHistoryQuery is an allowance history query.
+type AllowanceHistoryQuery struct {
+	Height      int64   `json:"height"`
+	Owner       Address `json:"owner"`
+	Beneficiary Address `json:"beneficiary"`
+
+	// Offset is the number of oldest history entries to skip.
+	Offset uint64 `json:"offset,omitempty"`
+	// Limit is the maximum number of history entries to return. Zero means no limit.
+	Limit uint32 `json:"limit,omitempty"`
+}
+
+// AllowanceHistoryEntry is a recorded change of the allowance for a beneficiary.
+type AllowanceHistoryEntry struct {
+	// Height is the height of the block in which the allowance has been changed.
+	Height int64 `json:"height"`
+	// OldAllowance is the allowance before the change.
+	OldAllowance quantity.Quantity `json:"old_allowance"`
+	// Allowance is the allowance after the change.
+	Allowance quantity.Quantity `json:"allowance"`
+}
+
+// CommissionAtQuery is a commission rate projection query.
+type CommissionAtQuery struct {
+	Height int64            `json:"height"`
+	Owner  Address          `json:"owner"`
+	Epoch  beacon.EpochTime `json:"epoch"`
+}
+
+// ScheduleUpdateQuery is a commission schedule amendment validation query.
+type ScheduleUpdateQuery struct {
+	Height    int64              `json:"height"`
+	Owner     Address            `json:"owner"`
+	Amendment CommissionSchedule `json:"amendment"`
+}
+
+// EventProofQuery is an event proof query.
+type EventProofQuery struct {
+	// Height is the consensus block height at which the event has been emitted.
+	Height int64 `json:"height"`
+	// Index is the index of the event in the list of events returned by GetEvents at the given
+	// height.
+	Index uint64 `json:"index"`
+}
+
 // TransferEvent is the event emitted when stake is transferred, either by a
 // call to Transfer or Withdraw.
 type TransferEvent struct {
@@ -281,6 +361,10 @@ type AddEscrowEvent struct {
 	Escrow    Address           `json:"escrow"`
 	Amount    quantity.Quantity `json:"amount"`
 	NewShares quantity.Quantity `json:"new_shares"`
+
+	// Beneficiary is the beneficiary that escrowed the stake from the owner's allowance. It is
+	// only set for stake escrowed via DelegateFromAllowance.
+	Beneficiary *Address `json:"beneficiary,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
@@ -343,6 +427,10 @@ type DebondingStartEscrowEvent struct {
 	ActiveShares    quantity.Quantity `json:"active_shares"`
 	DebondingShares quantity.Quantity `json:"debonding_shares"`
 	DebondEndTime   beacon.EpochTime  `json:"debond_end_time"`
+
+	// Beneficiary is the beneficiary that started debonding stake it previously escrowed from
+	// the owner's allowance. It is only set for stake reclaimed via ReclaimEscrowFromAllowance.
+	Beneficiary *Address `json:"beneficiary,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
@@ -397,6 +485,10 @@ type AllowanceChangeEvent struct { // nolint: maligned
 	Allowance    quantity.Quantity `json:"allowance"`
 	Negative     bool              `json:"negative,omitempty"`
 	AmountChange quantity.Quantity `json:"amount_change"`
+
+	// OldAllowance is the allowance before the change. It is only set for events emitted after
+	// the consensus243 upgrade.
+	OldAllowance *quantity.Quantity `json:"old_allowance,omitempty"`
 }
 
 // EventKind returns a string representation of this event's kind.
@@ -599,6 +691,65 @@ func NewWithdrawTx(nonce uint64, fee *transaction.Fee, withdraw *Withdraw) *tran
 	return transaction.NewTransaction(nonce, fee, MethodWithdraw, withdraw)
 }
 
+// DelegateFromAllowance is an escrow of stake from an owner's account, debited from the
+// allowance of the beneficiary submitting the transaction. The resulting delegation is owned by
+// the original owner.
+type DelegateFromAllowance struct {
+	From    Address           `json:"from"`
+	Account Address           `json:"account"`
+	Amount  quantity.Quantity `json:"amount"`
+}
+
+// PrettyPrint writes a pretty-printed representation of DelegateFromAllowance to the given writer.
+func (d DelegateFromAllowance) PrettyPrint(ctx context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sFrom:   %s\n", prefix, d.From)
+	fmt.Fprintf(w, "%sTo:     %s\n", prefix, d.Account)
+
+	fmt.Fprintf(w, "%sAmount: ", prefix)
+	token.PrettyPrintAmount(ctx, d.Amount, w)
+	fmt.Fprintln(w)
+}
+
+// PrettyType returns a representation of DelegateFromAllowance that can be used for pretty
+// printing.
+func (d DelegateFromAllowance) PrettyType() (any, error) {
+	return d, nil
+}
+
+// NewDelegateFromAllowanceTx creates a new delegate from allowance transaction.
+func NewDelegateFromAllowanceTx(nonce uint64, fee *transaction.Fee, delegate *DelegateFromAllowance) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodDelegateFromAllowance, delegate)
+}
+
+// ReclaimEscrowFromAllowance is a reclamation of stake that the beneficiary submitting the
+// transaction previously escrowed from the owner's allowance. The reclaimed stake is returned
+// to the owner's general account once debonded and the allowance is not restored.
+type ReclaimEscrowFromAllowance struct {
+	From    Address           `json:"from"`
+	Account Address           `json:"account"`
+	Shares  quantity.Quantity `json:"shares"`
+}
+
+// PrettyPrint writes a pretty-printed representation of ReclaimEscrowFromAllowance to the given
+// writer.
+func (re ReclaimEscrowFromAllowance) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sOwner:  %s\n", prefix, re.From)
+	fmt.Fprintf(w, "%sFrom:   %s\n", prefix, re.Account)
+
+	fmt.Fprintf(w, "%sShares: %s\n", prefix, re.Shares)
+}
+
+// PrettyType returns a representation of ReclaimEscrowFromAllowance that can be used for pretty
+// printing.
+func (re ReclaimEscrowFromAllowance) PrettyType() (any, error) {
+	return re, nil
+}
+
+// NewReclaimEscrowFromAllowanceTx creates a new reclaim escrow from allowance transaction.
+func NewReclaimEscrowFromAllowanceTx(nonce uint64, fee *transaction.Fee, reclaim *ReclaimEscrowFromAllowance) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodReclaimEscrowFromAllowance, reclaim)
+}
+
 // SharePool is a combined balance of several entries, the relative sizes
 // of which are tracked through shares.
 type SharePool struct {
@@ -1271,6 +1422,14 @@ type ConsensusParameters struct { // nolint: maligned
 	// to the entity that proposed the block.
 	RewardFactorBlockProposed quantity.Quantity `json:"reward_factor_block_proposed"`
 
+	// EvidenceRecordsRetention is the number of blocks for which records of processed validator
+	// misbehavior evidence are retained. Zero means that evidence records are not recorded.
+	EvidenceRecordsRetention uint64 `json:"evidence_records_retention,omitempty"`
+
+	// MaxAllowanceHistory is the maximum number of allowance changes recorded for each
+	// owner/beneficiary combination. Zero means that allowance changes are not recorded.
+	MaxAllowanceHistory uint32 `json:"max_allowance_history,omitempty"`
+
 	// DebugBypassStake is true iff all of the staking-related checks and
 	// operations should be bypassed.
 	DebugBypassStake bool `json:"debug_bypass_stake,omitempty"`
@@ -1318,6 +1477,12 @@ type ConsensusParameterChanges struct {
 	RewardFactorEpochSigned *quantity.Quantity `json:"reward_factor_epoch_signed"`
 	// RewardFactorBlockProposed is the new block proposed reward factor.
 	RewardFactorBlockProposed *quantity.Quantity `json:"reward_factor_block_proposed"`
+
+	// EvidenceRecordsRetention is the new evidence records retention.
+	EvidenceRecordsRetention *uint64 `json:"evidence_records_retention,omitempty"`
+
+	// MaxAllowanceHistory is the new maximum number of recorded allowance changes.
+	MaxAllowanceHistory *uint32 `json:"max_allowance_history,omitempty"`
 }
 
 // Apply applies changes to the given consensus parameters.
@@ -1370,6 +1535,12 @@ func (c *ConsensusParameterChanges) Apply(params *ConsensusParameters) error {
 	if c.RewardFactorBlockProposed != nil {
 		params.RewardFactorBlockProposed = *c.RewardFactorBlockProposed
 	}
+	if c.EvidenceRecordsRetention != nil {
+		params.EvidenceRecordsRetention = *c.EvidenceRecordsRetention
+	}
+	if c.MaxAllowanceHistory != nil {
+		params.MaxAllowanceHistory = *c.MaxAllowanceHistory
+	}
 	return nil
 }
 
@@ -1388,6 +1559,11 @@ const (
 	GasOpAllow transaction.Op = "allow"
 	// GasOpWithdraw is the gas operation identifier for withdraw.
 	GasOpWithdraw transaction.Op = "withdraw"
+	// GasOpDelegateFromAllowance is the gas operation identifier for delegate from allowance.
+	GasOpDelegateFromAllowance transaction.Op = "delegate_from_allowance"
+	// GasOpReclaimEscrowFromAllowance is the gas operation identifier for reclaim escrow from
+	// allowance.
+	GasOpReclaimEscrowFromAllowance transaction.Op = "reclaim_escrow_from_allowance"
 )
 
 // TransferResult is the result of staking transfer.
@@ -409,6 +409,57 @@ func (cs *CommissionSchedule) CurrentRate(now beacon.EpochTime) *quantity.Quanti
 	return &latestStartedStep.Rate
 }
 
+// clone returns a deep copy of the commission schedule.
+func (cs *CommissionSchedule) clone() *CommissionSchedule {
+	var c CommissionSchedule
+	if cs.Rates != nil {
+		c.Rates = make([]CommissionRateStep, 0, len(cs.Rates))
+		for _, step := range cs.Rates {
+			c.Rates = append(c.Rates, CommissionRateStep{
+				Start: step.Start,
+				Rate:  *step.Rate.Clone(),
+			})
+		}
+	}
+	if cs.Bounds != nil {
+		c.Bounds = make([]CommissionRateBoundStep, 0, len(cs.Bounds))
+		for _, step := range cs.Bounds {
+			c.Bounds = append(c.Bounds, CommissionRateBoundStep{
+				Start:   step.Start,
+				RateMin: *step.RateMin.Clone(),
+				RateMax: *step.RateMax.Clone(),
+			})
+		}
+	}
+	return &c
+}
+
+// CommissionAt returns the commission rate that the given schedule defines for the given
+// (possibly future) epoch or nil if no rate step has started by then.
+//
+// The projection assumes that the schedule is not amended in the meantime.
+func CommissionAt(schedule *CommissionSchedule, epoch beacon.EpochTime) *quantity.Quantity {
+	return schedule.CurrentRate(epoch)
+}
+
+// ValidateScheduleUpdate checks whether the proposed amendment of the current commission schedule
+// would be accepted by an AmendCommissionSchedule transaction executed at the given epoch and
+// returns the resulting (amended and pruned) schedule.
+//
+// The current schedule is not modified.
+func ValidateScheduleUpdate(
+	current *CommissionSchedule,
+	proposed *CommissionSchedule,
+	rules *CommissionScheduleRules,
+	now beacon.EpochTime,
+) (*CommissionSchedule, error) {
+	cs := current.clone()
+	if err := cs.AmendAndPruneAndValidate(proposed.clone(), rules, now); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
 func init() {
 	// Compute CommissionRateDenominator from its base-10 exponent.
 	CommissionRateDenominator = quantity.NewQuantity()
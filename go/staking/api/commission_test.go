@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 )
@@ -875,6 +876,85 @@ func TestCommissionSchedule(t *testing.T) {
 	require.Equal(t, beacon.EpochTime(10), cs.Bounds[0].Start, "prune 10 bounds start")
 }
 
+func TestCommissionAt(t *testing.T) {
+	require := require.New(t)
+
+	cs := CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 10, Rate: mustInitQuantity(t, 50_000)},
+			{Start: 20, Rate: mustInitQuantity(t, 40_000)},
+		},
+	}
+
+	require.Nil(CommissionAt(&CommissionSchedule{}, 10), "empty schedule")
+	require.Nil(CommissionAt(&cs, 9), "before first step")
+	require.Equal(mustInitQuantityP(t, 50_000), CommissionAt(&cs, 10), "first step")
+	require.Equal(mustInitQuantityP(t, 50_000), CommissionAt(&cs, 19), "first step")
+	require.Equal(mustInitQuantityP(t, 40_000), CommissionAt(&cs, 20), "second step")
+	require.Equal(mustInitQuantityP(t, 40_000), CommissionAt(&cs, 1000), "far future")
+}
+
+func TestValidateScheduleUpdate(t *testing.T) {
+	require := require.New(t)
+
+	rules := CommissionScheduleRules{
+		RateChangeInterval: 10,
+		RateBoundLead:      30,
+		MaxRateSteps:       4,
+		MaxBoundSteps:      4,
+	}
+
+	current := CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 0, Rate: mustInitQuantity(t, 50_000)},
+			{Start: 10, Rate: mustInitQuantity(t, 40_000)},
+		},
+		Bounds: []CommissionRateBoundStep{
+			{Start: 0, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 60_000)},
+		},
+	}
+	orig := cbor.Marshal(current)
+
+	// Valid amendment.
+	cs, err := ValidateScheduleUpdate(&current, &CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 20, Rate: mustInitQuantity(t, 30_000)},
+		},
+	}, &rules, 15)
+	require.NoError(err, "valid amendment")
+	require.Len(cs.Rates, 2, "pruned and amended rates")
+	require.Equal(beacon.EpochTime(10), cs.Rates[0].Start)
+	require.Equal(beacon.EpochTime(20), cs.Rates[1].Start)
+	require.Len(cs.Bounds, 1, "unchanged bounds")
+	require.Equal(orig, cbor.Marshal(current), "current schedule must not be modified")
+
+	// Changing a rate that is already in effect.
+	_, err = ValidateScheduleUpdate(&current, &CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 10, Rate: mustInitQuantity(t, 30_000)},
+		},
+	}, &rules, 15)
+	require.Error(err, "rate change in the past")
+	require.Equal(orig, cbor.Marshal(current), "current schedule must not be modified")
+
+	// Changing bounds without enough lead time.
+	_, err = ValidateScheduleUpdate(&current, &CommissionSchedule{
+		Bounds: []CommissionRateBoundStep{
+			{Start: 20, RateMin: mustInitQuantity(t, 0), RateMax: mustInitQuantity(t, 100_000)},
+		},
+	}, &rules, 15)
+	require.Error(err, "bound change without lead time")
+
+	// Rate out of bounds.
+	_, err = ValidateScheduleUpdate(&current, &CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{Start: 20, Rate: mustInitQuantity(t, 70_000)},
+		},
+	}, &rules, 15)
+	require.Error(err, "rate out of bounds")
+	require.Equal(orig, cbor.Marshal(current), "current schedule must not be modified")
+}
+
 func TestPrettyPrintCommissionRateStep(t *testing.T) {
 	require := require.New(t)
 
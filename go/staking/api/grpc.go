@@ -15,48 +15,58 @@ var (
 	serviceName = cmnGrpc.NewServiceName("Staking")
 
 	// methodTokenSymbol is the TokenSymbol method.
-	methodTokenSymbol = serviceName.NewMethod("TokenSymbol", int64(0))
+	methodTokenSymbol = serviceName.NewMethod("TokenSymbol", int64(0)).WithIdempotent()
 	// methodTokenValueExponent is the TokenValueExponent method.
-	methodTokenValueExponent = serviceName.NewMethod("TokenValueExponent", int64(0))
+	methodTokenValueExponent = serviceName.NewMethod("TokenValueExponent", int64(0)).WithIdempotent()
+	// methodGetTokenInfo is the GetTokenInfo method.
+	methodGetTokenInfo = serviceName.NewMethod("GetTokenInfo", int64(0)).WithIdempotent()
 	// methodTotalSupply is the TotalSupply method.
-	methodTotalSupply = serviceName.NewMethod("TotalSupply", int64(0))
+	methodTotalSupply = serviceName.NewMethod("TotalSupply", int64(0)).WithIdempotent()
 	// methodCommonPool is the CommonPool method.
-	methodCommonPool = serviceName.NewMethod("CommonPool", int64(0))
+	methodCommonPool = serviceName.NewMethod("CommonPool", int64(0)).WithIdempotent()
 	// methodLastBlockFees is the LastBlockFees method.
-	methodLastBlockFees = serviceName.NewMethod("LastBlockFees", int64(0))
+	methodLastBlockFees = serviceName.NewMethod("LastBlockFees", int64(0)).WithIdempotent()
 	// methodGovernanceDeposits is the GovernanceDeposits method.
-	methodGovernanceDeposits = serviceName.NewMethod("GovernanceDeposits", int64(0))
+	methodGovernanceDeposits = serviceName.NewMethod("GovernanceDeposits", int64(0)).WithIdempotent()
 	// methodThreshold is the Threshold method.
-	methodThreshold = serviceName.NewMethod("Threshold", ThresholdQuery{})
+	methodThreshold = serviceName.NewMethod("Threshold", ThresholdQuery{}).WithIdempotent()
 	// methodAddresses is the Addresses method.
-	methodAddresses = serviceName.NewMethod("Addresses", int64(0))
+	methodAddresses = serviceName.NewMethod("Addresses", int64(0)).WithIdempotent()
 	// methodCommissionScheduleAddresses is the CommissionScheduleAddresses method.
-	methodCommissionScheduleAddresses = serviceName.NewMethod("CommissionScheduleAddresses", int64(0))
+	methodCommissionScheduleAddresses = serviceName.NewMethod("CommissionScheduleAddresses", int64(0)).WithIdempotent()
 	// methodAccount is the Account method.
-	methodAccount = serviceName.NewMethod("Account", OwnerQuery{})
+	methodAccount = serviceName.NewMethod("Account", OwnerQuery{}).WithIdempotent()
 	// methodDelegationsFor is the DelegationsFor method.
-	methodDelegationsFor = serviceName.NewMethod("DelegationsFor", OwnerQuery{})
+	methodDelegationsFor = serviceName.NewMethod("DelegationsFor", OwnerQuery{}).WithIdempotent()
 	// methodDelegationInfosFor is the DelegationInfosFor method.
-	methodDelegationInfosFor = serviceName.NewMethod("DelegationInfosFor", OwnerQuery{})
+	methodDelegationInfosFor = serviceName.NewMethod("DelegationInfosFor", OwnerQuery{}).WithIdempotent()
 	// methodDelegationsTo is the DelegationsTo method.
-	methodDelegationsTo = serviceName.NewMethod("DelegationsTo", OwnerQuery{})
+	methodDelegationsTo = serviceName.NewMethod("DelegationsTo", OwnerQuery{}).WithIdempotent()
 	// methodDebondingDelegationsFor is the DebondingDelegationsFor method.
-	methodDebondingDelegationsFor = serviceName.NewMethod("DebondingDelegationsFor", OwnerQuery{})
+	methodDebondingDelegationsFor = serviceName.NewMethod("DebondingDelegationsFor", OwnerQuery{}).WithIdempotent()
 	// methodDebondingDelegationInfosFor is the DebondingDelegationInfosFor method.
-	methodDebondingDelegationInfosFor = serviceName.NewMethod("DebondingDelegationInfosFor", OwnerQuery{})
+	methodDebondingDelegationInfosFor = serviceName.NewMethod("DebondingDelegationInfosFor", OwnerQuery{}).WithIdempotent()
 	// methodDebondingDelegationsTo is the DebondingDelegationsTo method.
-	methodDebondingDelegationsTo = serviceName.NewMethod("DebondingDelegationsTo", OwnerQuery{})
+	methodDebondingDelegationsTo = serviceName.NewMethod("DebondingDelegationsTo", OwnerQuery{}).WithIdempotent()
 	// methodAllowance is the Allowance method.
-	methodAllowance = serviceName.NewMethod("Allowance", AllowanceQuery{})
+	methodAllowance = serviceName.NewMethod("Allowance", AllowanceQuery{}).WithIdempotent()
+	// methodGetAllowanceHistory is the GetAllowanceHistory method.
+	methodGetAllowanceHistory = serviceName.NewMethod("GetAllowanceHistory", AllowanceHistoryQuery{}).WithIdempotent()
+	// methodCommissionAt is the CommissionAt method.
+	methodCommissionAt = serviceName.NewMethod("CommissionAt", CommissionAtQuery{}).WithIdempotent()
+	// methodValidateScheduleUpdate is the ValidateScheduleUpdate method.
+	methodValidateScheduleUpdate = serviceName.NewMethod("ValidateScheduleUpdate", ScheduleUpdateQuery{}).WithIdempotent()
 	// methodStateToGenesis is the StateToGenesis method.
-	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0))
+	methodStateToGenesis = serviceName.NewMethod("StateToGenesis", int64(0)).WithIdempotent()
 	// methodConsensusParameters is the ConsensusParameters method.
-	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0))
+	methodConsensusParameters = serviceName.NewMethod("ConsensusParameters", int64(0)).WithIdempotent()
 	// methodGetEvents is the GetEvents method.
-	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0))
+	methodGetEvents = serviceName.NewMethod("GetEvents", int64(0)).WithIdempotent()
+	// methodGetEventProof is the GetEventProof method.
+	methodGetEventProof = serviceName.NewMethod("GetEventProof", EventProofQuery{}).WithIdempotent()
 
 	// methodWatchEvents is the WatchEvents method.
-	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil)
+	methodWatchEvents = serviceName.NewMethod("WatchEvents", nil).WithIdempotent()
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -71,6 +81,10 @@ var (
 				MethodName: methodTokenValueExponent.ShortName(),
 				Handler:    handlerTokenValueExponent,
 			},
+			{
+				MethodName: methodGetTokenInfo.ShortName(),
+				Handler:    handlerGetTokenInfo,
+			},
 			{
 				MethodName: methodTotalSupply.ShortName(),
 				Handler:    handlerTotalSupply,
@@ -131,6 +145,18 @@ var (
 				MethodName: methodAllowance.ShortName(),
 				Handler:    handlerAllowance,
 			},
+			{
+				MethodName: methodGetAllowanceHistory.ShortName(),
+				Handler:    handlerGetAllowanceHistory,
+			},
+			{
+				MethodName: methodCommissionAt.ShortName(),
+				Handler:    handlerCommissionAt,
+			},
+			{
+				MethodName: methodValidateScheduleUpdate.ShortName(),
+				Handler:    handlerValidateScheduleUpdate,
+			},
 			{
 				MethodName: methodStateToGenesis.ShortName(),
 				Handler:    handlerStateToGenesis,
@@ -143,6 +169,10 @@ var (
 				MethodName: methodGetEvents.ShortName(),
 				Handler:    handlerGetEvents,
 			},
+			{
+				MethodName: methodGetEventProof.ShortName(),
+				Handler:    handlerGetEventProof,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -200,6 +230,29 @@ func handlerTokenValueExponent(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetTokenInfo(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetTokenInfo(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTokenInfo.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetTokenInfo(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerTotalSupply(
 	srv any,
 	ctx context.Context,
@@ -545,6 +598,75 @@ func handlerAllowance(
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerGetAllowanceHistory(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query AllowanceHistoryQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetAllowanceHistory(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetAllowanceHistory.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetAllowanceHistory(ctx, req.(*AllowanceHistoryQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerCommissionAt(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query CommissionAtQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).CommissionAt(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodCommissionAt.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).CommissionAt(ctx, req.(*CommissionAtQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerValidateScheduleUpdate(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query ScheduleUpdateQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).ValidateScheduleUpdate(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodValidateScheduleUpdate.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).ValidateScheduleUpdate(ctx, req.(*ScheduleUpdateQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerStateToGenesis(
 	srv any,
 	ctx context.Context,
@@ -614,6 +736,29 @@ func handlerGetEvents(
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetEventProof(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var query EventProofQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEventProof(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEventProof.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetEventProof(ctx, req.(*EventProofQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerWatchEvents(srv any, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -675,6 +820,14 @@ func (c *Client) TokenValueExponent(ctx context.Context, height int64) (uint8, e
 	return rsp, nil
 }
 
+func (c *Client) GetTokenInfo(ctx context.Context, height int64) (*TokenInfo, error) {
+	var rsp TokenInfo
+	if err := c.conn.Invoke(ctx, methodGetTokenInfo.FullName(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) TotalSupply(ctx context.Context, height int64) (*quantity.Quantity, error) {
 	var rsp quantity.Quantity
 	if err := c.conn.Invoke(ctx, methodTotalSupply.FullName(), height, &rsp); err != nil {
@@ -795,6 +948,30 @@ func (c *Client) Allowance(ctx context.Context, query *AllowanceQuery) (*quantit
 	return &rsp, nil
 }
 
+func (c *Client) GetAllowanceHistory(ctx context.Context, query *AllowanceHistoryQuery) ([]*AllowanceHistoryEntry, error) {
+	var rsp []*AllowanceHistoryEntry
+	if err := c.conn.Invoke(ctx, methodGetAllowanceHistory.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) CommissionAt(ctx context.Context, query *CommissionAtQuery) (*quantity.Quantity, error) {
+	var rsp *quantity.Quantity
+	if err := c.conn.Invoke(ctx, methodCommissionAt.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) ValidateScheduleUpdate(ctx context.Context, query *ScheduleUpdateQuery) (*CommissionSchedule, error) {
+	var rsp CommissionSchedule
+	if err := c.conn.Invoke(ctx, methodValidateScheduleUpdate.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.FullName(), height, &rsp); err != nil {
@@ -819,6 +996,14 @@ func (c *Client) GetEvents(ctx context.Context, height int64) ([]*Event, error)
 	return rsp, nil
 }
 
+func (c *Client) GetEventProof(ctx context.Context, query *EventProofQuery) (*EventProof, error) {
+	var rsp EventProof
+	if err := c.conn.Invoke(ctx, methodGetEventProof.FullName(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *Client) WatchEvents(ctx context.Context) (<-chan *Event, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	cmtmerkle "github.com/cometbft/cometbft/crypto/merkle"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/events"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+// methodBlockMeta is the method name of the block metadata system transaction.
+//
+// This must be kept in sync with consensus.MethodMeta which cannot be imported here.
+var methodBlockMeta = transaction.MethodName("consensus.Meta")
+
+// EventProof is a proof that a staking event has been emitted in a given consensus block.
+//
+// The event is proven against the provable events root committed to by the block metadata
+// system transaction, which is itself proven against the transactions root of the block header.
+type EventProof struct {
+	// Event is the proven event.
+	Event *Event `json:"event"`
+	// Header is the CBOR-encoded backend-specific header of the consensus block in which the
+	// event has been emitted.
+	Header []byte `json:"header"`
+
+	// EventProof is the Merkle proof of inclusion of the event in the provable events root.
+	EventProof *cmtmerkle.Proof `json:"event_proof"`
+	// MetaTx is the signed block metadata system transaction committing to the provable events
+	// root.
+	MetaTx []byte `json:"meta_tx"`
+	// MetaTxProof is the Merkle proof of inclusion of the block metadata transaction in the
+	// transactions root of the block header.
+	MetaTxProof *cmtmerkle.Proof `json:"meta_tx_proof"`
+}
+
+// blockMetadata is the consensus block metadata.
+//
+// This must be kept in sync with consensus.BlockMetadata which cannot be imported here.
+type blockMetadata struct {
+	StateRoot  hash.Hash `json:"state_root"`
+	EventsRoot []byte    `json:"events_root"`
+}
+
+// ProvableRepresentation returns the provable representation of the event.
+func (e *Event) ProvableRepresentation() (any, error) {
+	var pv events.Provable
+	switch {
+	case e.Transfer != nil:
+		pv = e.Transfer
+	case e.Burn != nil:
+		pv = e.Burn
+	case e.AllowanceChange != nil:
+		pv = e.AllowanceChange
+	case e.Escrow != nil && e.Escrow.Add != nil:
+		pv = e.Escrow.Add
+	case e.Escrow != nil && e.Escrow.Take != nil:
+		pv = e.Escrow.Take
+	case e.Escrow != nil && e.Escrow.DebondingStart != nil:
+		pv = e.Escrow.DebondingStart
+	case e.Escrow != nil && e.Escrow.Reclaim != nil:
+		pv = e.Escrow.Reclaim
+	default:
+		return nil, fmt.Errorf("staking: malformed event")
+	}
+	return pv.ProvableRepresentation(), nil
+}
+
+// VerifyEventProof verifies that the event in the given proof has been emitted in the consensus
+// block with the given transactions root.
+//
+// The transactions root must be taken from a block header that has been verified by a light
+// client. Note that only the provable representation of the event is verified, the height and
+// the transaction hash are not covered by the proof.
+func VerifyEventProof(txsRoot []byte, proof *EventProof) error {
+	if proof.Event == nil || proof.EventProof == nil || proof.MetaTxProof == nil {
+		return fmt.Errorf("%w: malformed event proof", ErrInvalidArgument)
+	}
+
+	// Verify that the block metadata transaction has been included in the block.
+	metaTxHash := sha256.Sum256(proof.MetaTx)
+	if err := proof.MetaTxProof.Verify(txsRoot, metaTxHash[:]); err != nil {
+		return fmt.Errorf("%w: invalid block metadata transaction proof: %w", ErrInvalidArgument, err)
+	}
+
+	// The block metadata transaction is included by the block proposer and has already been
+	// validated by the consensus layer, so there is no need to verify the signature.
+	var sigTx transaction.SignedTransaction
+	if err := cbor.Unmarshal(proof.MetaTx, &sigTx); err != nil {
+		return fmt.Errorf("%w: malformed block metadata transaction: %w", ErrInvalidArgument, err)
+	}
+	var tx transaction.Transaction
+	if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+		return fmt.Errorf("%w: malformed block metadata transaction: %w", ErrInvalidArgument, err)
+	}
+	if tx.Method != methodBlockMeta {
+		return fmt.Errorf("%w: not a block metadata transaction", ErrInvalidArgument)
+	}
+	var meta blockMetadata
+	if err := cbor.Unmarshal(tx.Body, &meta); err != nil {
+		return fmt.Errorf("%w: malformed block metadata: %w", ErrInvalidArgument, err)
+	}
+
+	// Verify that the event has been included in the provable events root.
+	pv, err := proof.Event.ProvableRepresentation()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgument, err)
+	}
+	if err = proof.EventProof.Verify(meta.EventsRoot, cbor.Marshal(pv)); err != nil {
+		return fmt.Errorf("%w: invalid event proof: %w", ErrInvalidArgument, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	cmtmerkle "github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+)
+
+func TestVerifyEventProof(t *testing.T) {
+	require := require.New(t)
+
+	addr1 := NewModuleAddress("test", "event proof 1")
+	addr2 := NewModuleAddress("test", "event proof 2")
+	events := []*Event{
+		{Transfer: &TransferEvent{From: addr1, To: addr2, Amount: *quantity.NewFromUint64(100)}},
+		{Burn: &BurnEvent{Owner: addr1, Amount: *quantity.NewFromUint64(10)}},
+		{Escrow: &EscrowEvent{Add: &AddEscrowEvent{
+			Owner:     addr1,
+			Escrow:    addr2,
+			Amount:    *quantity.NewFromUint64(1000),
+			NewShares: *quantity.NewFromUint64(1000),
+		}}},
+	}
+
+	leaves := make([][]byte, 0, len(events))
+	for _, ev := range events {
+		pv, err := ev.ProvableRepresentation()
+		require.NoError(err, "ProvableRepresentation")
+		leaves = append(leaves, cbor.Marshal(pv))
+	}
+	eventsRoot, eventProofs := cmtmerkle.ProofsFromByteSlices(leaves)
+
+	newSignedTx := func(method transaction.MethodName, body any) []byte {
+		tx := transaction.NewTransaction(0, nil, method, body)
+		return cbor.Marshal(&transaction.SignedTransaction{
+			Signed: signature.Signed{Blob: cbor.Marshal(tx)},
+		})
+	}
+	txs := [][]byte{
+		newSignedTx(MethodTransfer, &Transfer{To: addr2, Amount: *quantity.NewFromUint64(100)}),
+		newSignedTx(methodBlockMeta, &blockMetadata{EventsRoot: eventsRoot}),
+	}
+	hashes := make([][]byte, 0, len(txs))
+	for _, tx := range txs {
+		h := sha256.Sum256(tx)
+		hashes = append(hashes, h[:])
+	}
+	txsRoot, txProofs := cmtmerkle.ProofsFromByteSlices(hashes)
+
+	for i, ev := range events {
+		err := VerifyEventProof(txsRoot, &EventProof{
+			Event:       ev,
+			EventProof:  eventProofs[i],
+			MetaTx:      txs[1],
+			MetaTxProof: txProofs[1],
+		})
+		require.NoError(err, "VerifyEventProof should succeed for event %d", i)
+	}
+
+	proof := &EventProof{
+		Event:       events[0],
+		EventProof:  eventProofs[0],
+		MetaTx:      txs[1],
+		MetaTxProof: txProofs[1],
+	}
+
+	// Invalid transactions root.
+	err := VerifyEventProof(eventsRoot, proof)
+	require.ErrorIs(err, ErrInvalidArgument, "VerifyEventProof should fail with invalid transactions root")
+
+	// Modified event.
+	modified := *proof
+	modified.Event = &Event{Transfer: &TransferEvent{From: addr1, To: addr2, Amount: *quantity.NewFromUint64(1000)}}
+	err = VerifyEventProof(txsRoot, &modified)
+	require.ErrorIs(err, ErrInvalidArgument, "VerifyEventProof should fail with modified event")
+
+	// Proof for a different event.
+	modified = *proof
+	modified.EventProof = eventProofs[1]
+	err = VerifyEventProof(txsRoot, &modified)
+	require.ErrorIs(err, ErrInvalidArgument, "VerifyEventProof should fail with proof for a different event")
+
+	// Transaction that is not a block metadata transaction.
+	modified = *proof
+	modified.MetaTx = txs[0]
+	modified.MetaTxProof = txProofs[0]
+	err = VerifyEventProof(txsRoot, &modified)
+	require.ErrorIs(err, ErrInvalidArgument, "VerifyEventProof should fail with non-metadata transaction")
+
+	// Missing proof.
+	modified = *proof
+	modified.EventProof = nil
+	err = VerifyEventProof(txsRoot, &modified)
+	require.ErrorIs(err, ErrInvalidArgument, "VerifyEventProof should fail with missing proof")
+}
@@ -84,7 +84,9 @@ func (c *ConsensusParameterChanges) SanityCheck() error {
 		c.FeeSplitWeightVote == nil &&
 		c.FeeSplitWeightNextPropose == nil &&
 		c.RewardFactorEpochSigned == nil &&
-		c.RewardFactorBlockProposed == nil {
+		c.RewardFactorBlockProposed == nil &&
+		c.EvidenceRecordsRetention == nil &&
+		c.MaxAllowanceHistory == nil {
 		return fmt.Errorf("consensus parameter changes should not be empty")
 	}
 	return nil
@@ -287,8 +289,9 @@ func SanityCheckAccountShares(
 	return nil
 }
 
-// SanityCheck does basic sanity checking on the genesis state.
-func (g *Genesis) SanityCheck(now beacon.EpochTime) error { // nolint: gocyclo
+// SanityCheckHeader does basic sanity checking on the genesis state, excluding the ledger and
+// the delegations.
+func (g *Genesis) SanityCheckHeader() error {
 	if err := g.Parameters.SanityCheck(); err != nil {
 		return fmt.Errorf("staking: sanity check failed: %w", err)
 	}
@@ -321,22 +324,27 @@ func (g *Genesis) SanityCheck(now beacon.EpochTime) error { // nolint: gocyclo
 		return fmt.Errorf("staking: sanity check failed: last block fees is invalid")
 	}
 
-	// Check if the total supply adds up:
-	// common pool + last block fees + all balances in the ledger.
-	// Check all commission schedules.
-	var total quantity.Quantity
-	for addr, acct := range g.Ledger {
-		err := SanityCheckAccount(&total, &g.Parameters, now, addr, acct, &g.TotalSupply)
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		// Make sure that the stake accumulator is empty as otherwise it could be inconsistent with
-		// what is registered in the genesis block.
-		if len(acct.Escrow.StakeAccumulator.Claims) > 0 {
-			return fmt.Errorf("staking: non-empty stake accumulator in genesis")
-		}
+// SanityCheckGenesisAccount examines a genesis account's balances, commission schedule and
+// stake accumulator. Adds the balances to a running total `total`.
+func (g *Genesis) SanityCheckGenesisAccount(total *quantity.Quantity, now beacon.EpochTime, addr Address, acct *Account) error {
+	if err := SanityCheckAccount(total, &g.Parameters, now, addr, acct, &g.TotalSupply); err != nil {
+		return err
+	}
+
+	// Make sure that the stake accumulator is empty as otherwise it could be inconsistent with
+	// what is registered in the genesis block.
+	if len(acct.Escrow.StakeAccumulator.Claims) > 0 {
+		return fmt.Errorf("staking: non-empty stake accumulator in genesis")
 	}
+	return nil
+}
+
+// SanityCheckTotalSupply checks that the total of all balances in the ledger, plus governance
+// deposits, plus common pool, plus last block fees, adds up to the total supply.
+func (g *Genesis) SanityCheckTotalSupply(total quantity.Quantity) error {
 	_ = total.Add(&g.GovernanceDeposits)
 	_ = total.Add(&g.CommonPool)
 	_ = total.Add(&g.LastBlockFees)
@@ -346,6 +354,48 @@ func (g *Genesis) SanityCheck(now beacon.EpochTime) error { // nolint: gocyclo
 			total.String(), g.TotalSupply.String(),
 		)
 	}
+	return nil
+}
+
+// SanityCheckBurnAccount checks that the burn address is actually "unused" for reasonable
+// definitions of "unused".
+func SanityCheckBurnAccount(ba *Account) error {
+	if !ba.General.Balance.IsZero() {
+		return fmt.Errorf(
+			"staking: sanity check failed: burn address has non-zero balance: %v", ba.General.Balance,
+		)
+	}
+	if ba.General.Nonce != 0 {
+		return fmt.Errorf(
+			"staking: sanity check failed: burn address has non-zero nonce: %v", ba.General.Nonce,
+		)
+	}
+	if len(ba.General.Allowances) != 0 {
+		return fmt.Errorf(
+			"staking: sanity check failed: burn address has non-empty allowances",
+		)
+	}
+	return nil
+}
+
+// SanityCheck does basic sanity checking on the genesis state.
+func (g *Genesis) SanityCheck(now beacon.EpochTime) error {
+	if err := g.SanityCheckHeader(); err != nil {
+		return err
+	}
+
+	// Check if the total supply adds up:
+	// common pool + last block fees + all balances in the ledger.
+	// Check all commission schedules.
+	var total quantity.Quantity
+	for addr, acct := range g.Ledger {
+		if err := g.SanityCheckGenesisAccount(&total, now, addr, acct); err != nil {
+			return err
+		}
+	}
+	if err := g.SanityCheckTotalSupply(total); err != nil {
+		return err
+	}
 
 	// All shares of all delegations for a given account must add up to account's Escrow.Active.TotalShares.
 	for addr, delegations := range g.Delegations {
@@ -376,20 +426,8 @@ func (g *Genesis) SanityCheck(now beacon.EpochTime) error { // nolint: gocyclo
 
 	// The burn address is actually "unused" for reasonable definitions of "unused".
 	if ba := g.Ledger[BurnAddress]; ba != nil {
-		if !ba.General.Balance.IsZero() {
-			return fmt.Errorf(
-				"staking: sanity check failed: burn address has non-zero balance: %v", ba.General.Balance,
-			)
-		}
-		if ba.General.Nonce != 0 {
-			return fmt.Errorf(
-				"staking: sanity check failed: burn address has non-zero nonce: %v", ba.General.Nonce,
-			)
-		}
-		if len(ba.General.Allowances) != 0 {
-			return fmt.Errorf(
-				"staking: sanity check failed: burn address has non-empty allowances",
-			)
+		if err := SanityCheckBurnAccount(ba); err != nil {
+			return err
 		}
 	}
 
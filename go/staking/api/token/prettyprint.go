@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/big"
+	"strings"
 
 	"github.com/oasisprotocol/oasis-core/go/common/prettyprint"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -23,6 +25,55 @@ func ConvertToTokenAmount(amount quantity.Quantity, tokenValueExponent uint8) (s
 	return prettyprint.QuantityFrac(amount, tokenValueExponent), nil
 }
 
+// ConvertToBaseUnits returns the amount in base units corresponding to the
+// given token amount according to the given token's value base-10 exponent.
+//
+// The token amount must be a non-negative decimal number (e.g., "10.5"). No
+// rounding is performed: if the token amount has more non-zero fractional
+// digits than the token's value base-10 exponent allows, an error is returned.
+func ConvertToBaseUnits(tokenAmount string, tokenValueExponent uint8) (*quantity.Quantity, error) {
+	if tokenValueExponent > TokenValueExponentMaxValue {
+		return nil, ErrInvalidTokenValueExponent
+	}
+
+	integral, fractional, _ := strings.Cut(tokenAmount, ".")
+	if !isDigits(integral) || (strings.Contains(tokenAmount, ".") && !isDigits(fractional)) {
+		return nil, fmt.Errorf("%w: malformed amount '%s'", ErrInvalidTokenAmount, tokenAmount)
+	}
+
+	// Trailing zeros do not change the amount, any other excess digits would be lost.
+	fractional = strings.TrimRight(fractional, "0")
+	if len(fractional) > int(tokenValueExponent) {
+		return nil, fmt.Errorf("%w: amount '%s' has more than %d decimal places",
+			ErrInvalidTokenAmount, tokenAmount, tokenValueExponent,
+		)
+	}
+	fractional += strings.Repeat("0", int(tokenValueExponent)-len(fractional))
+
+	amount, ok := new(big.Int).SetString(integral+fractional, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed amount '%s'", ErrInvalidTokenAmount, tokenAmount)
+	}
+
+	var q quantity.Quantity
+	if err := q.FromBigInt(amount); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTokenAmount, err)
+	}
+	return &q, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // PrettyPrintAmount writes a pretty-printed representation of the given amount
 // to the given writer.
 //
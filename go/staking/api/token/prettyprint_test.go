@@ -49,6 +49,67 @@ func TestConvertToTokenAmount(t *testing.T) {
 	}
 }
 
+func TestConvertToBaseUnits(t *testing.T) {
+	require := require.New(t)
+
+	for _, t := range []struct {
+		expectedAmount *quantity.Quantity
+		tokenAmount    string
+		exp            uint8
+		valid          bool
+	}{
+		// General checks where 1 tokens equals 10^9 base units.
+		{quantity.NewFromUint64(10000000000000000000), "10000000000", 9, true},
+		{quantity.NewFromUint64(100000000000), "100.0", 9, true},
+		{quantity.NewFromUint64(7999217230119682890), "7999217230.11968289", 9, true},
+		{quantity.NewFromUint64(7999217230100000000), "7999217230.1", 9, true},
+		{quantity.NewFromUint64(1), "0.000000001", 9, true},
+		{quantity.NewFromUint64(0), "0", 9, true},
+		{quantity.NewFromUint64(0), "0.0", 9, true},
+		// Excess trailing zeros don't change the amount.
+		{quantity.NewFromUint64(1500000000), "1.500000000000", 9, true},
+		// Amounts that would need rounding must be rejected.
+		{nil, "0.0000000001", 9, false},
+		{nil, "1.0000000005", 9, false},
+		// Malformed amounts.
+		{nil, "", 9, false},
+		{nil, ".5", 9, false},
+		{nil, "1.", 9, false},
+		{nil, "-1", 9, false},
+		{nil, "+1", 9, false},
+		{nil, "1e9", 9, false},
+		{nil, "1,5", 9, false},
+		{nil, "1.5.0", 9, false},
+		{nil, " 1", 9, false},
+		// Check for a too large token's value base-10 exponent.
+		{nil, "1", 21, false},
+		// Special checks for large and small token's value base-10 exponents.
+		{quantity.NewFromUint64(10000000000000000000), "10", 18, true},
+		{quantity.NewFromUint64(10000000000000000001), "10.000000000000000001", 18, true},
+		{nil, "10.0000000000000000001", 18, false},
+		{quantity.NewFromUint64(10000000000000000001), "10000000000000000001", 0, true},
+		{quantity.NewFromUint64(10000000000000000001), "10000000000000000001.0", 0, true},
+		{nil, "1.1", 0, false},
+		{quantity.NewFromUint64(100000000000000000), "0.001", 20, true},
+	} {
+		amount, err := ConvertToBaseUnits(t.tokenAmount, t.exp)
+		if !t.valid {
+			require.Error(err, "converting token amount '%s' to base units should fail", t.tokenAmount)
+			continue
+		}
+		require.NoError(err, "converting token amount '%s' to base units shouldn't fail", t.tokenAmount)
+		require.Equal(t.expectedAmount.String(), amount.String(),
+			"converting token amount to base units didn't return the expected amount")
+
+		// Converting back should result in the same amount.
+		tokenAmount, err := ConvertToTokenAmount(*amount, t.exp)
+		require.NoError(err, "converting base unit amount to tokens shouldn't fail")
+		roundTrip, err := ConvertToBaseUnits(tokenAmount, t.exp)
+		require.NoError(err, "converting token amount to base units shouldn't fail")
+		require.Equal(amount.String(), roundTrip.String(), "round trip should preserve the amount")
+	}
+}
+
 func TestPrettyPrintAmount(t *testing.T) {
 	require := require.New(t)
 
@@ -15,6 +15,12 @@ const (
 	TokenValueExponentMaxValue = 20
 )
 
-// ErrInvalidTokenValueExponent is the error returned when an invalid token's
-// value base-10 exponent is specified.
-var ErrInvalidTokenValueExponent = errors.New(ModuleName, 1, "staking/token: invalid token's value exponent")
+var (
+	// ErrInvalidTokenValueExponent is the error returned when an invalid token's
+	// value base-10 exponent is specified.
+	ErrInvalidTokenValueExponent = errors.New(ModuleName, 1, "staking/token: invalid token's value exponent")
+
+	// ErrInvalidTokenAmount is the error returned when a malformed token amount
+	// is specified or when it cannot be represented in base units exactly.
+	ErrInvalidTokenAmount = errors.New(ModuleName, 2, "staking/token: invalid token amount")
+)
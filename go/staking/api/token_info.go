@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+// TokenInfo is the staking token's metadata.
+type TokenInfo struct {
+	// Symbol is the token's ticker symbol.
+	Symbol string `json:"symbol"`
+	// ValueExponent is the token's value base-10 exponent, i.e.
+	// 1 token = 10**ValueExponent base units.
+	ValueExponent uint8 `json:"value_exponent"`
+}
+
+// FormatAmount returns the given amount of base units formatted as a token
+// amount followed by the token's ticker symbol (e.g., "10.5 ROSE").
+//
+// In case the token's metadata is invalid, the amount is formatted in base
+// units instead.
+func (ti *TokenInfo) FormatAmount(amount quantity.Quantity) string {
+	if ti.Symbol == "" || len(ti.Symbol) > token.TokenSymbolMaxLength {
+		return fmt.Sprintf("%s base units", amount)
+	}
+	tokenAmount, err := token.ConvertToTokenAmount(amount, ti.ValueExponent)
+	if err != nil {
+		return fmt.Sprintf("%s base units", amount)
+	}
+	return fmt.Sprintf("%s %s", tokenAmount, ti.Symbol)
+}
+
+// ParseAmount parses the given token amount, optionally followed by the
+// token's ticker symbol (e.g., "10.5" or "10.5 ROSE"), and returns the
+// corresponding amount of base units.
+//
+// Amounts that cannot be represented in base units exactly are rejected
+// instead of being rounded.
+func (ti *TokenInfo) ParseAmount(s string) (*quantity.Quantity, error) {
+	amount, symbol, hasSymbol := strings.Cut(strings.TrimSpace(s), " ")
+	if hasSymbol && strings.TrimSpace(symbol) != ti.Symbol {
+		return nil, fmt.Errorf("%w: unexpected token symbol '%s'", token.ErrInvalidTokenAmount, strings.TrimSpace(symbol))
+	}
+	return token.ConvertToBaseUnits(amount, ti.ValueExponent)
+}
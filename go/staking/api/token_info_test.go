@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/staking/api/token"
+)
+
+func TestTokenInfoFormatAmount(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		expected string
+		info     TokenInfo
+		amount   *quantity.Quantity
+	}{
+		{"100.0 ROSE", TokenInfo{Symbol: "ROSE", ValueExponent: 9}, quantity.NewFromUint64(100000000000)},
+		{"0.000000001 ROSE", TokenInfo{Symbol: "ROSE", ValueExponent: 9}, quantity.NewFromUint64(1)},
+		{"10.000000000000000001 BIG", TokenInfo{Symbol: "BIG", ValueExponent: 18}, quantity.NewFromUint64(10000000000000000001)},
+		{"42.0 SMALL", TokenInfo{Symbol: "SMALL", ValueExponent: 0}, quantity.NewFromUint64(42)},
+		// Invalid token metadata should fall back to base units.
+		{"100 base units", TokenInfo{Symbol: "", ValueExponent: 9}, quantity.NewFromUint64(100)},
+		{"100 base units", TokenInfo{Symbol: "SOMETHINGLONG", ValueExponent: 9}, quantity.NewFromUint64(100)},
+		{"100 base units", TokenInfo{Symbol: "TOOBIG", ValueExponent: 21}, quantity.NewFromUint64(100)},
+	} {
+		require.Equal(tc.expected, tc.info.FormatAmount(*tc.amount))
+	}
+}
+
+func TestTokenInfoParseAmount(t *testing.T) {
+	require := require.New(t)
+
+	info := TokenInfo{Symbol: "ROSE", ValueExponent: 9}
+	for _, tc := range []struct {
+		amount   string
+		expected *quantity.Quantity
+		valid    bool
+	}{
+		{"100", quantity.NewFromUint64(100000000000), true},
+		{"100.5 ROSE", quantity.NewFromUint64(100500000000), true},
+		{"  0.000000001 ROSE ", quantity.NewFromUint64(1), true},
+		{"0.0000000001 ROSE", nil, false},
+		{"100 TEST", nil, false},
+		{"100 ROSE ROSE", nil, false},
+		{"ROSE", nil, false},
+	} {
+		amount, err := info.ParseAmount(tc.amount)
+		if !tc.valid {
+			require.ErrorIs(err, token.ErrInvalidTokenAmount, "ParseAmount(%s)", tc.amount)
+			continue
+		}
+		require.NoError(err, "ParseAmount(%s)", tc.amount)
+		require.Equal(tc.expected.String(), amount.String(), "ParseAmount(%s)", tc.amount)
+
+		// Formatted amounts should be parsed back to the same amount.
+		roundTrip, err := info.ParseAmount(info.FormatAmount(*amount))
+		require.NoError(err, "ParseAmount(FormatAmount(%s))", tc.amount)
+		require.Equal(amount.String(), roundTrip.String())
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"testing"
 	"time"
 
+	cmttypes "github.com/cometbft/cometbft/types"
 	"github.com/stretchr/testify/require"
 
 	beaconTests "github.com/oasisprotocol/oasis-core/go/beacon/tests"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
@@ -146,6 +148,7 @@ func StakingImplementationTests(
 		n  string
 		fn func(*testing.T, *stakingTestsState, api.Backend, consensusAPI.Service)
 	}{
+		{"TokenInfo", testTokenInfo},
 		{"Thresholds", testThresholds},
 		{"CommonPool", testCommonPool},
 		{"LastBlockFees", testLastBlockFees},
@@ -209,6 +212,20 @@ func testThresholds(t *testing.T, _ *stakingTestsState, staking api.Backend, _ c
 	}
 }
 
+func testTokenInfo(t *testing.T, _ *stakingTestsState, staking api.Backend, _ consensusAPI.Service) {
+	require := require.New(t)
+
+	symbol, err := staking.TokenSymbol(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "TokenSymbol")
+	exp, err := staking.TokenValueExponent(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "TokenValueExponent")
+
+	info, err := staking.GetTokenInfo(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetTokenInfo")
+	require.Equal(symbol, info.Symbol, "GetTokenInfo - symbol should match")
+	require.Equal(exp, info.ValueExponent, "GetTokenInfo - value exponent should match")
+}
+
 func testCommonPool(t *testing.T, _ *stakingTestsState, staking api.Backend, _ consensusAPI.Service) {
 	require := require.New(t)
 
@@ -525,6 +542,8 @@ TransferWaitLoop:
 					}
 				}
 				require.True(gotTransfer, "GetEvents should return transfer event")
+
+				testEventProof(t, staking, ev)
 			}
 
 			if gotTransfer {
@@ -1260,3 +1279,42 @@ WaitLoop:
 	}
 	// XXX: no freezing is configured for this.
 }
+
+func testEventProof(t *testing.T, staking api.Backend, ev *api.Event) {
+	require := require.New(t)
+
+	evts, err := staking.GetEvents(context.Background(), ev.Height)
+	require.NoError(err, "GetEvents")
+	index := -1
+	for i, evt := range evts {
+		if evt.Transfer == nil {
+			continue
+		}
+		if evt.Transfer.From.Equal(ev.Transfer.From) && evt.Transfer.To.Equal(ev.Transfer.To) && evt.Transfer.Amount.Cmp(&ev.Transfer.Amount) == 0 {
+			index = i
+			break
+		}
+	}
+	require.NotEqual(-1, index, "GetEvents should return the event")
+
+	proof, err := staking.GetEventProof(context.Background(), &api.EventProofQuery{
+		Height: ev.Height,
+		Index:  uint64(index),
+	})
+	require.NoError(err, "GetEventProof")
+	require.EqualValues(evts[index], proof.Event, "GetEventProof should return the event")
+
+	var header cmttypes.Header
+	err = cbor.Unmarshal(proof.Header, &header)
+	require.NoError(err, "header should be decodable")
+	require.EqualValues(ev.Height, header.Height, "header height should match")
+
+	err = api.VerifyEventProof(header.DataHash, proof)
+	require.NoError(err, "VerifyEventProof")
+
+	_, err = staking.GetEventProof(context.Background(), &api.EventProofQuery{
+		Height: ev.Height,
+		Index:  uint64(len(evts)),
+	})
+	require.ErrorIs(err, api.ErrInvalidArgument, "GetEventProof should fail for out of range index")
+}
@@ -83,6 +83,10 @@ type Config struct { // nolint: maligned
 	// MaxCacheSize is the maximum in-memory cache size for the database.
 	MaxCacheSize int64
 
+	// ApplyMemoryBudget is the maximum estimated size of pending changes kept in memory while
+	// applying write logs. Zero means no limit.
+	ApplyMemoryBudget uint64
+
 	// DiscardWriteLogs will cause all write logs to be discarded.
 	DiscardWriteLogs bool
 
@@ -186,6 +190,10 @@ type ApplyRequest struct {
 	DstRound  uint64           `json:"dst_round"`
 	DstRoot   hash.Hash        `json:"dst_root"`
 	WriteLog  WriteLog         `json:"writelog"`
+
+	// WriteLogIterator is an optional iterator over write log entries sorted by key. If set, it
+	// is used instead of WriteLog, which allows streaming the write log from the network.
+	WriteLogIterator WriteLogIterator `json:"-"`
 }
 
 // SyncOptions are the sync options.
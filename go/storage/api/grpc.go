@@ -60,6 +60,9 @@ var (
 	// MethodGetCheckpointChunk is the GetCheckpointChunk method.
 	MethodGetCheckpointChunk = ServiceName.NewMethod("GetCheckpointChunk", checkpoint.ChunkMetadata{})
 
+	// MethodGetCheckpointChunkInfo is the GetCheckpointChunkInfo method.
+	MethodGetCheckpointChunkInfo = ServiceName.NewMethod("GetCheckpointChunkInfo", checkpoint.ChunkMetadata{})
+
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
 		ServiceName: string(ServiceName),
@@ -81,6 +84,10 @@ var (
 				MethodName: MethodGetCheckpoints.ShortName(),
 				Handler:    handlerGetCheckpoints,
 			},
+			{
+				MethodName: MethodGetCheckpointChunkInfo.ShortName(),
+				Handler:    handlerGetCheckpointChunkInfo,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -189,6 +196,29 @@ func handlerGetCheckpoints(
 	return interceptor(ctx, &req, info, handler)
 }
 
+func handlerGetCheckpointChunkInfo(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	var req checkpoint.ChunkMetadata
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetCheckpointChunkInfo(ctx, &req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MethodGetCheckpointChunkInfo.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(Backend).GetCheckpointChunkInfo(ctx, req.(*checkpoint.ChunkMetadata))
+	}
+	return interceptor(ctx, &req, info, handler)
+}
+
 func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.ServerStream) error {
 	var totalSent uint64
 	skipping := true
@@ -324,6 +354,14 @@ func (c *Client) GetCheckpoints(ctx context.Context, request *checkpoint.GetChec
 	return rsp, nil
 }
 
+func (c *Client) GetCheckpointChunkInfo(ctx context.Context, chunk *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	var rsp checkpoint.ChunkInfo
+	if err := c.conn.Invoke(ctx, MethodGetCheckpointChunkInfo.FullName(), chunk, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream) WriteLogIterator {
 	pipe := writelog.NewPipeIterator(ctx)
 
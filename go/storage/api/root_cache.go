@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"slices"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
@@ -12,6 +14,8 @@ import (
 // RootCache is a LRU based tree cache.
 type RootCache struct {
 	localDB nodedb.NodeDB
+
+	applyMemoryBudget uint64
 }
 
 // GetTree gets a tree entry from the cache by the root iff present, or creates
@@ -27,6 +31,30 @@ func (rc *RootCache) Apply(
 	root Root,
 	expectedNewRoot Root,
 	writeLog WriteLog,
+) (*hash.Hash, error) {
+	// Write logs are applied in a streaming fashion which requires them to be sorted.
+	compareEntries := func(a, b writelog.LogEntry) int {
+		return bytes.Compare(a.Key, b.Key)
+	}
+	if !slices.IsSortedFunc(writeLog, compareEntries) {
+		writeLog = slices.Clone(writeLog)
+		slices.SortFunc(writeLog, compareEntries)
+	}
+
+	return rc.ApplyIterator(ctx, root, expectedNewRoot, writelog.NewStaticIterator(writeLog))
+}
+
+// ApplyIterator applies the write log entries produced by the given iterator,
+// bypassing the apply operation iff the new root already is in the node
+// database.
+//
+// The write log entries must be sorted by key. This allows the write log to be
+// consumed directly from the network without materializing it in memory.
+func (rc *RootCache) ApplyIterator(
+	ctx context.Context,
+	root Root,
+	expectedNewRoot Root,
+	it WriteLogIterator,
 ) (*hash.Hash, error) {
 	// Sanity check the expected new root.
 	if !expectedNewRoot.Follows(&root) {
@@ -38,14 +66,7 @@ func (rc *RootCache) Apply(
 	// Check if we already have the expected new root in our local DB.
 	if !rc.localDB.HasRoot(expectedNewRoot) {
 		// We don't, apply operations.
-		tree := mkvs.NewWithRoot(nil, rc.localDB, root)
-		defer tree.Close()
-
-		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
-			return nil, err
-		}
-
-		_, err := tree.CommitKnown(ctx, expectedNewRoot)
+		err := mkvs.StreamApplyWriteLog(ctx, rc.localDB, root, expectedNewRoot, it, rc.applyMemoryBudget)
 		switch err {
 		case nil:
 		case mkvs.ErrKnownRootMismatch:
@@ -62,8 +83,13 @@ func (rc *RootCache) HasRoot(root Root) bool {
 	return rc.localDB.HasRoot(root)
 }
 
-func NewRootCache(localDB nodedb.NodeDB) (*RootCache, error) {
+// NewRootCache creates a new root cache.
+//
+// The apply memory budget bounds the estimated size of pending changes kept in
+// memory while applying write logs. Zero means no limit.
+func NewRootCache(localDB nodedb.NodeDB, applyMemoryBudget uint64) (*RootCache, error) {
 	return &RootCache{
-		localDB: localDB,
+		localDB:           localDB,
+		applyMemoryBudget: applyMemoryBudget,
 	}, nil
 }
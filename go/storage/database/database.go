@@ -57,7 +57,7 @@ func New(cfg *api.Config) (api.LocalBackend, error) {
 		return nil, fmt.Errorf("storage/database: failed to create node database: %w", err)
 	}
 
-	rootCache, err := api.NewRootCache(ndb)
+	rootCache, err := api.NewRootCache(ndb, cfg.ApplyMemoryBudget)
 	if err != nil {
 		ndb.Close()
 		return nil, fmt.Errorf("storage/database: failed to create root cache: %w", err)
@@ -138,6 +138,10 @@ func (ba *databaseBackend) GetCheckpointChunk(ctx context.Context, chunk *checkp
 	return ba.checkpointer.GetCheckpointChunk(ctx, chunk, w)
 }
 
+func (ba *databaseBackend) GetCheckpointChunkInfo(ctx context.Context, chunk *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	return ba.checkpointer.GetCheckpointChunkInfo(ctx, chunk)
+}
+
 // Implements api.LocalBackend.
 func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest) error {
 	if ba.readOnly {
@@ -156,12 +160,13 @@ func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest)
 		Type:      request.RootType,
 		Hash:      request.DstRoot,
 	}
-	_, err := ba.rootCache.Apply(
-		ctx,
-		oldRoot,
-		expectedNewRoot,
-		request.WriteLog,
-	)
+	var err error
+	switch request.WriteLogIterator {
+	case nil:
+		_, err = ba.rootCache.Apply(ctx, oldRoot, expectedNewRoot, request.WriteLog)
+	default:
+		_, err = ba.rootCache.ApplyIterator(ctx, oldRoot, expectedNewRoot, request.WriteLogIterator)
+	}
 	if err != nil {
 		return fmt.Errorf("storage/database: failed to Apply: %w", err)
 	}
@@ -0,0 +1,365 @@
+package mkvs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+// applyNodeOverhead is the estimated in-memory overhead of a single inserted
+// entry (excluding its key and value) used for memory budget accounting.
+//
+// Each insertion creates a new leaf node and at most one new internal node,
+// together with their pointers.
+const applyNodeOverhead = 512
+
+// StreamApplyWriteLog applies the write log entries produced by the given
+// iterator on top of root and persists the result in the node database as
+// expectedNewRoot.
+//
+// In contrast to applying the write log via a tree and committing it, dirty
+// subtrees are flushed to the node database batch as soon as the estimated
+// size of the pending changes exceeds the given memory budget. This requires
+// the write log entries to be strictly sorted by key as flushed subtrees are
+// never visited again. In case they are not, ErrWriteLogNotSorted is returned.
+// A memory budget of zero disables flushing.
+//
+// Note that the write log keys are still retained in memory as the node
+// database stores the write log as a whole when the batch is committed.
+func StreamApplyWriteLog(
+	ctx context.Context,
+	ndb db.NodeDB,
+	root node.Root,
+	expectedNewRoot node.Root,
+	wl writelog.Iterator,
+	memoryBudget uint64,
+) error {
+	if !expectedNewRoot.Follows(&root) {
+		return db.ErrRootMustFollowOld
+	}
+
+	t := NewWithRoot(nil, ndb, root, WithoutWriteLog()).(*tree)
+	defer t.Close()
+
+	t.cache.Lock()
+	defer t.cache.Unlock()
+
+	oldRoot := root
+	if oldRoot.IsEmpty() {
+		oldRoot.Namespace = expectedNewRoot.Namespace
+		oldRoot.Version = expectedNewRoot.Version
+		oldRoot.Type = expectedNewRoot.Type
+	}
+
+	batch, err := ndb.NewBatch(oldRoot, expectedNewRoot.Version, false)
+	if err != nil {
+		return err
+	}
+	defer batch.Reset()
+
+	a := &streamApplier{
+		tree:         t,
+		batch:        batch,
+		memoryBudget: memoryBudget,
+	}
+	for {
+		more, err := wl.Next()
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+		entry, err := wl.Value()
+		if err != nil {
+			return err
+		}
+
+		if err = a.apply(ctx, entry); err != nil {
+			return err
+		}
+	}
+
+	return a.commit(ctx, expectedNewRoot)
+}
+
+// streamApplier applies sorted write log entries to a tree while flushing
+// subtrees that will no longer be modified.
+type streamApplier struct {
+	tree  *tree
+	batch db.Batch
+
+	memoryBudget uint64
+	pendingSize  uint64
+
+	lastKey node.Key
+
+	log     writelog.WriteLog
+	logAnns writelog.Annotations
+}
+
+func (a *streamApplier) apply(ctx context.Context, entry writelog.LogEntry) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	t := a.tree
+	key := node.Key(entry.Key)
+	if a.lastKey != nil && bytes.Compare(a.lastKey, key) >= 0 {
+		return ErrWriteLogNotSorted
+	}
+	a.lastKey = key
+
+	// Remember where the path from root to target node ends (will end).
+	t.cache.markPosition()
+
+	switch entry.Value {
+	case nil:
+		// Make sure that the key exists before removing it as removals do not check the labels
+		// of internal nodes and could otherwise descend into subtrees that were already flushed.
+		exists, err := a.contains(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Skip entries that do not exist before and after the update.
+			return nil
+		}
+
+		newRoot, _, _, err := t.doRemove(ctx, t.cache.pendingRoot, 0, key)
+		if err != nil {
+			return err
+		}
+		t.cache.setPendingRoot(newRoot)
+
+		a.log = append(a.log, writelog.LogEntry{Key: key})
+		a.logAnns = append(a.logAnns, writelog.LogEntryAnnotation{InsertedNode: nil})
+	default:
+		result, err := t.doInsert(ctx, t.cache.pendingRoot, 0, key, entry.Value)
+		if err != nil {
+			return err
+		}
+		t.cache.setPendingRoot(result.newRoot)
+
+		// Values are not retained in the write log as the node database only
+		// needs the inserted leaf nodes which already contain them.
+		a.log = append(a.log, writelog.LogEntry{Key: key})
+		a.logAnns = append(a.logAnns, writelog.LogEntryAnnotation{InsertedNode: result.insertedLeaf})
+		a.pendingSize += uint64(len(key) + len(entry.Value) + applyNodeOverhead)
+	}
+
+	if a.memoryBudget > 0 && a.pendingSize > a.memoryBudget {
+		if err := a.flush(key); err != nil {
+			return err
+		}
+		a.pendingSize = 0
+	}
+	return nil
+}
+
+// contains checks whether the given key exists in the tree, making sure that
+// the labels of all internal nodes on the path match the key.
+func (a *streamApplier) contains(ctx context.Context, key node.Key) (bool, error) {
+	t := a.tree
+	ptr := t.cache.pendingRoot
+	var bitDepth node.Depth
+	for {
+		nd, err := t.cache.derefNodePtr(ctx, ptr, t.newFetcherSyncGet(key, true))
+		if err != nil {
+			return false, err
+		}
+
+		switch n := nd.(type) {
+		case nil:
+			return false, nil
+		case *node.InternalNode:
+			_, keyRemainder := key.Split(bitDepth, key.BitLength())
+			if n.Label.CommonPrefixLen(n.LabelBitLength, keyRemainder, key.BitLength()-bitDepth) != n.LabelBitLength {
+				return false, nil
+			}
+
+			bitLength := bitDepth + n.LabelBitLength
+			switch {
+			case key.BitLength() == bitLength:
+				ptr = n.LeafNode
+			case key.GetBit(bitLength):
+				ptr = n.Right
+			default:
+				ptr = n.Left
+			}
+			bitDepth = bitLength
+		case *node.LeafNode:
+			return n.Key.Equal(key), nil
+		default:
+			panic(fmt.Sprintf("mkvs: unknown node type: %+v", n))
+		}
+	}
+}
+
+// flush persists all dirty subtrees that only contain keys smaller than the
+// given key and drops them from memory.
+//
+// Since keys are applied in sorted order, the only nodes that can still be
+// modified by subsequent updates are the ones on the path to the given key
+// and their immediate children (e.g., when a path node is collapsed, its
+// remaining child's label is updated). Therefore, for each left sibling of
+// the path, both of its subtrees are flushed.
+func (a *streamApplier) flush(key node.Key) error {
+	ptr := a.tree.cache.pendingRoot
+	var bitDepth node.Depth
+	for ptr != nil {
+		n, ok := ptr.Node.(*node.InternalNode)
+		if !ok {
+			break
+		}
+
+		_, keyRemainder := key.Split(bitDepth, key.BitLength())
+		if n.Label.CommonPrefixLen(n.LabelBitLength, keyRemainder, key.BitLength()-bitDepth) != n.LabelBitLength {
+			break
+		}
+
+		bitLength := bitDepth + n.LabelBitLength
+		if key.BitLength() == bitLength {
+			// All keys in both subtrees are greater than the given key.
+			break
+		}
+		if !key.GetBit(bitLength) {
+			ptr = n.Left
+			bitDepth = bitLength
+			continue
+		}
+
+		if err := a.flushChildren(n.Left); err != nil {
+			return err
+		}
+
+		ptr = n.Right
+		bitDepth = bitLength
+	}
+	return nil
+}
+
+// flushChildren flushes and detaches both subtrees of the given node in case
+// it is a dirty internal node.
+func (a *streamApplier) flushChildren(ptr *node.Pointer) error {
+	if ptr.IsClean() {
+		return nil
+	}
+	n, ok := ptr.Node.(*node.InternalNode)
+	if !ok {
+		return nil
+	}
+
+	for _, subNode := range []*node.Pointer{n.Left, n.Right} {
+		if err := a.flushNode(subNode, ptr); err != nil {
+			return err
+		}
+		detachNode(subNode)
+	}
+	return nil
+}
+
+// flushNode persists the given subtree into the batch and marks it as clean.
+//
+// In contrast to doCommit, nodes are marked clean immediately and are not made
+// eligible for eviction as they are dropped from memory afterwards.
+func (a *streamApplier) flushNode(ptr *node.Pointer, parent *node.Pointer) error {
+	if ptr == nil {
+		return nil
+	} else if ptr.Clean {
+		return a.batch.VisitCleanNode(ptr, parent)
+	}
+
+	switch n := ptr.Node.(type) {
+	case nil:
+		// Dead node.
+		ptr.Hash.Empty()
+	case *node.InternalNode:
+		if err := a.batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+
+		// Flush internal leaf (considered to be on the same depth as the internal node).
+		if err := a.flushNode(n.LeafNode, ptr); err != nil {
+			return err
+		}
+		for _, subNode := range []*node.Pointer{n.Left, n.Right} {
+			if err := a.flushNode(subNode, ptr); err != nil {
+				return err
+			}
+		}
+
+		n.UpdateHash()
+		if err := a.batch.PutNode(ptr); err != nil {
+			return err
+		}
+		n.Clean = true
+		ptr.Hash = n.Hash
+	case *node.LeafNode:
+		if err := a.batch.VisitDirtyNode(ptr, parent); err != nil {
+			return err
+		}
+
+		n.UpdateHash()
+		if err := a.batch.PutNode(ptr); err != nil {
+			return err
+		}
+		n.Clean = true
+		ptr.Hash = n.Hash
+	}
+
+	ptr.Clean = true
+	return nil
+}
+
+// commit persists the remaining dirty nodes and the write log, verifying that
+// the resulting root matches the given root.
+func (a *streamApplier) commit(ctx context.Context, root node.Root) error {
+	t := a.tree
+	rootHash, err := doCommit(ctx, t.cache, a.batch, t.cache.pendingRoot, nil)
+	if err != nil {
+		return err
+	}
+	if !rootHash.Equal(&root.Hash) {
+		return ErrKnownRootMismatch
+	}
+
+	if err = a.batch.PutWriteLog(a.log, a.logAnns); err != nil {
+		return err
+	}
+	if err = a.batch.RemoveNodes(t.pendingRemovedNodes); err != nil {
+		return err
+	}
+	if err = a.batch.Commit(root); err != nil {
+		return err
+	}
+
+	t.pendingRemovedNodes = nil
+	t.cache.setSyncRoot(root)
+	return nil
+}
+
+// detachNode drops the given flushed subtree from memory, leaving only the
+// hash and database references in the pointers.
+//
+// Nodes that are managed by the cache are left alone as they are subject to
+// eviction anyway.
+func detachNode(ptr *node.Pointer) {
+	if ptr == nil || ptr.LRU != nil {
+		return
+	}
+	if !ptr.Clean {
+		panic("mkvs: detach called on dirty pointer")
+	}
+
+	if n, ok := ptr.Node.(*node.InternalNode); ok {
+		detachNode(n.LeafNode)
+		detachNode(n.Left)
+		detachNode(n.Right)
+	}
+	ptr.Node = nil
+}
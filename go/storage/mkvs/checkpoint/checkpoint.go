@@ -13,6 +13,12 @@ import (
 
 const moduleName = "storage/mkvs/checkpoint"
 
+// IncrementalVersion is the version of incremental checkpoints.
+//
+// Incremental checkpoints split the tree into chunks along subtree boundaries, so that chunks of
+// subtrees that did not change can be reused by later checkpoints instead of being re-created.
+const IncrementalVersion uint16 = 2
+
 var (
 	// ErrCheckpointNotFound is the error when a checkpoint is not found.
 	ErrCheckpointNotFound = errors.New(moduleName, 1, "checkpoint: not found")
@@ -44,6 +50,10 @@ type ChunkProvider interface {
 
 	// GetCheckpointChunk fetches a specific chunk from an existing chekpoint.
 	GetCheckpointChunk(ctx context.Context, chunk *ChunkMetadata, w io.Writer) error
+
+	// GetCheckpointChunkInfo returns information about a specific chunk from an existing
+	// checkpoint without fetching the chunk itself.
+	GetCheckpointChunkInfo(ctx context.Context, chunk *ChunkMetadata) (*ChunkInfo, error)
 }
 
 // GetCheckpointsRequest is a GetCheckpoints request.
@@ -63,6 +73,12 @@ type Creator interface {
 	// CreateCheckpoint creates a new checkpoint at the given root.
 	CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error)
 
+	// CreateIncrementalCheckpoint creates a new incremental checkpoint at the given root.
+	//
+	// Chunks of subtrees that are unchanged since the most recent earlier incremental checkpoint
+	// of the same root type are referenced instead of being re-created.
+	CreateIncrementalCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error)
+
 	// GetCheckpoint retrieves checkpoint metadata for a specific checkpoint.
 	GetCheckpoint(ctx context.Context, version uint16, root node.Root) (*Metadata, error)
 
@@ -126,16 +142,75 @@ type ChunkMetadata struct {
 	Digest  hash.Hash `json:"digest"`
 }
 
+// ChunkInfo is information about a checkpoint chunk available at a chunk provider.
+type ChunkInfo struct {
+	// Index is the index of the chunk.
+	Index uint64 `json:"index"`
+	// Size is the size of the chunk in bytes.
+	Size uint64 `json:"size"`
+	// Digest is the digest of the chunk.
+	Digest hash.Hash `json:"digest"`
+}
+
 // Metadata is checkpoint metadata.
 type Metadata struct {
 	Version uint16      `json:"version"`
 	Root    node.Root   `json:"root"`
 	Chunks  []hash.Hash `json:"chunks"`
+
+	// Reused is the chunk-reuse manifest of an incremental checkpoint. It lists chunks that are
+	// identical to chunks of an earlier checkpoint, which may be fetched from that checkpoint.
+	Reused []ChunkReference `json:"reused,omitempty"`
+
+	// Availability is optional information about the availability of checkpoint chunks at the
+	// chunk provider that returned the metadata. It is not part of the checkpoint identity.
+	Availability *Availability `json:"availability,omitempty"`
+}
+
+// ChunkReference is a reference to an identical chunk of an earlier checkpoint.
+type ChunkReference struct {
+	// Index is the index of the chunk in the referencing checkpoint.
+	Index uint64 `json:"index"`
+	// Root is the root of the earlier checkpoint.
+	Root node.Root `json:"root"`
+	// SourceIndex is the index of the chunk in the earlier checkpoint.
+	SourceIndex uint64 `json:"source_index"`
+}
+
+// Availability is information about the availability of checkpoint chunks at a chunk provider.
+type Availability struct {
+	// ChunkCount is the number of chunks that are present.
+	ChunkCount uint64 `json:"chunk_count"`
+	// Size is the total size of all present chunks in bytes.
+	Size uint64 `json:"size"`
+	// Complete is true iff all of the checkpoint chunks are present.
+	Complete bool `json:"complete"`
 }
 
 // EncodedHash returns the encoded cryptographic hash of the checkpoint metadata.
+//
+// Availability information is not included in the hash.
 func (m *Metadata) EncodedHash() hash.Hash {
-	return hash.NewFrom(m)
+	return hash.NewFrom(m.WithoutAvailability())
+}
+
+// WithoutAvailability returns a copy of the checkpoint metadata without availability information.
+//
+// This should be used before sending the metadata to peers which may not support it.
+func (m *Metadata) WithoutAvailability() *Metadata {
+	if m.Availability == nil {
+		return m
+	}
+
+	mc := *m
+	mc.Availability = nil
+	return &mc
+}
+
+// IsComplete returns true unless the chunk provider reported that some of the checkpoint chunks
+// are missing.
+func (m *Metadata) IsComplete() bool {
+	return m.Availability == nil || m.Availability.Complete
 }
 
 // GetChunkMetadata returns the chunk metadata for the corresponding chunk.
@@ -151,3 +226,24 @@ func (m Metadata) GetChunkMetadata(idx uint64) (*ChunkMetadata, error) {
 		Digest:  m.Chunks[int(idx)],
 	}, nil
 }
+
+// GetChunkSource returns the chunk metadata of the earlier checkpoint chunk that is identical to
+// the corresponding chunk or nil in case the chunk is not reused.
+func (m Metadata) GetChunkSource(idx uint64) *ChunkMetadata {
+	if idx >= uint64(len(m.Chunks)) {
+		return nil
+	}
+
+	for _, ref := range m.Reused {
+		if ref.Index != idx {
+			continue
+		}
+		return &ChunkMetadata{
+			Version: m.Version,
+			Root:    ref.Root,
+			Index:   ref.SourceIndex,
+			Digest:  m.Chunks[int(idx)],
+		}
+	}
+	return nil
+}
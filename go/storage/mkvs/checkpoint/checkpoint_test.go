@@ -94,7 +94,11 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1})
 	require.NoError(err, "GetCheckpoints")
 	require.Len(cps, 1, "there should be one checkpoint")
-	require.Equal(cp, cps[0], "checkpoint returned by GetCheckpoint should be correct")
+	require.Equal(cp, cps[0].WithoutAvailability(), "checkpoint returned by GetCheckpoint should be correct")
+	require.Equal(cp.EncodedHash(), cps[0].EncodedHash(), "availability should not affect the checkpoint hash")
+	require.True(cps[0].IsComplete(), "checkpoint should be complete")
+	require.NotNil(cps[0].Availability, "checkpoint availability should be reported")
+	require.EqualValues(2, cps[0].Availability.ChunkCount, "all chunks should be present")
 
 	gcp, err := fc.GetCheckpoint(ctx, 1, root)
 	require.NoError(err, "GetCheckpoint")
@@ -121,6 +125,26 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 	err = fc.GetCheckpointChunk(ctx, &invalidChunk, &buf)
 	require.Error(err, "GetChunk on a non-existent chunk should fail")
 
+	// Chunk information should match the chunk.
+	ci, err := fc.GetCheckpointChunkInfo(ctx, chunk0)
+	require.NoError(err, "GetCheckpointChunkInfo")
+	require.EqualValues(0, ci.Index, "chunk index should be correct")
+	require.EqualValues(buf.Len(), ci.Size, "chunk size should be correct")
+	require.Equal(chunk0.Digest, ci.Digest, "chunk digest should be correct")
+	_, err = fc.GetCheckpointChunkInfo(ctx, &invalidChunk)
+	require.ErrorIs(err, ErrChunkNotFound, "GetCheckpointChunkInfo on a non-existent chunk should fail")
+
+	var totalSize uint64
+	for idx := range cp.Chunks {
+		var cm *ChunkMetadata
+		cm, err = cp.GetChunkMetadata(uint64(idx))
+		require.NoError(err, "GetChunkMetadata")
+		ci, err = fc.GetCheckpointChunkInfo(ctx, cm)
+		require.NoError(err, "GetCheckpointChunkInfo")
+		totalSize += ci.Size
+	}
+	require.EqualValues(totalSize, cps[0].Availability.Size, "total checkpoint size should be correct")
+
 	// Create a fresh node database to restore into.
 	ndb2, err := factory.New(&dbApi.Config{
 		DB:           filepath.Join(dir, "db2"),
@@ -233,6 +257,25 @@ func testFileCheckpointCreator(t *testing.T, factory dbApi.Factory) {
 		require.Equal([]byte(strconv.Itoa(i)), value)
 	}
 
+	// Checkpoints with missing chunks should be reported as incomplete.
+	chunk1, err := cp.GetChunkMetadata(1)
+	require.NoError(err, "GetChunkMetadata")
+	err = os.Remove(filepath.Join(dir, "checkpoints", strconv.FormatUint(root.Version, 10), root.Hash.String(), "chunks", "1"))
+	require.NoError(err, "Remove")
+
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: 1})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 1, "there should be one checkpoint")
+	require.False(cps[0].IsComplete(), "checkpoint with a missing chunk should be incomplete")
+	require.EqualValues(1, cps[0].Availability.ChunkCount, "only present chunks should be counted")
+	require.Less(cps[0].Availability.Size, totalSize, "only present chunks should be included in size")
+	require.Equal(cp.EncodedHash(), cps[0].EncodedHash(), "availability should not affect the checkpoint hash")
+
+	_, err = fc.GetCheckpointChunkInfo(ctx, chunk1)
+	require.ErrorIs(err, ErrChunkNotFound, "GetCheckpointChunkInfo on a missing chunk should fail")
+	_, err = fc.GetCheckpointChunkInfo(ctx, chunk0)
+	require.NoError(err, "GetCheckpointChunkInfo on a present chunk should work")
+
 	// Deleting a checkpoint should work.
 	err = fc.DeleteCheckpoint(ctx, 1, root)
 	require.NoError(err, "DeleteCheckpoint")
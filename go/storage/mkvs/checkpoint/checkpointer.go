@@ -13,6 +13,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/common/random"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
@@ -39,6 +40,10 @@ type CheckpointerConfig struct {
 	// RootsPerVersion is the number of roots per version.
 	RootsPerVersion int
 
+	// Incremental specifies whether incremental checkpoints should be created instead of regular
+	// ones.
+	Incremental bool
+
 	// Parameters are the checkpoint creation parameters.
 	Parameters *CreationParameters
 	// GetParameters can be used instead of specifying Parameters to dynamically fetch the current
@@ -50,6 +55,10 @@ type CheckpointerConfig struct {
 	//
 	// This must return exactly RootsPerVersion roots.
 	GetRoots func(context.Context, uint64) ([]node.Root, error)
+
+	// Pool is an optional priority pool in which checkpoints are created as background work. If
+	// not specified, checkpoints are created directly by the checkpointer worker.
+	Pool *workerpool.PriorityPool
 }
 
 // CreationParameters are the checkpoint creation parameters used by the checkpointer.
@@ -162,7 +171,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 
 		// If there is an error, make sure to remove any created checkpoints.
 		for _, root := range roots {
-			_ = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root)
+			_ = c.creator.DeleteCheckpoint(ctx, c.version(), root)
 		}
 	}()
 
@@ -177,7 +186,7 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 			"chunk_size", params.ChunkSize,
 		)
 
-		_, err = c.creator.CreateCheckpoint(ctx, root, params.ChunkSize)
+		err = c.createCheckpoint(ctx, root, params.ChunkSize)
 		if err != nil {
 			c.logger.Error("failed to create checkpoint",
 				"root", root,
@@ -189,10 +198,38 @@ func (c *checkpointer) checkpoint(ctx context.Context, version uint64, params *C
 	return nil
 }
 
+// version returns the version of the checkpoints created by the checkpointer.
+func (c *checkpointer) version() uint16 {
+	if c.cfg.Incremental {
+		return IncrementalVersion
+	}
+	return checkpointVersion
+}
+
+func (c *checkpointer) createCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) error {
+	create := c.creator.CreateCheckpoint
+	if c.cfg.Incremental {
+		create = c.creator.CreateIncrementalCheckpoint
+	}
+
+	if c.cfg.Pool == nil {
+		_, err := create(ctx, root, chunkSize)
+		return err
+	}
+
+	var err error
+	if perr := c.cfg.Pool.Run(ctx, workerpool.ClassBackground, func() {
+		_, err = create(ctx, root, chunkSize)
+	}); perr != nil {
+		return perr
+	}
+	return err
+}
+
 func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, params *CreationParameters) error {
 	// Get a list of all current checkpoints.
 	cps, err := c.creator.GetCheckpoints(ctx, &GetCheckpointsRequest{
-		Version:   checkpointVersion,
+		Version:   c.version(),
 		Namespace: c.cfg.Namespace,
 	})
 	if err != nil {
@@ -266,7 +303,7 @@ func (c *checkpointer) maybeCheckpoint(ctx context.Context, version uint64, para
 
 		for _, version := range cpVersions[:len(cpVersions)-int(params.NumKept)] {
 			for _, root := range cpsByVersion[version] {
-				if err = c.creator.DeleteCheckpoint(ctx, checkpointVersion, root); err != nil {
+				if err = c.creator.DeleteCheckpoint(ctx, c.version(), root); err != nil {
 					c.logger.Warn("failed to garbage collect checkpoint",
 						"root", root,
 						"err", err,
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
 	dbApi "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
@@ -23,7 +24,7 @@ const (
 	testNumKept       = 2
 )
 
-func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, interval uint64, preExistingData bool) {
+func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, interval uint64, preExistingData, incremental bool, pool *workerpool.PriorityPool) {
 	require := require.New(t)
 	ctx := context.Background()
 
@@ -75,6 +76,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		Namespace:       testNs,
 		CheckInterval:   testCheckInterval,
 		RootsPerVersion: 1,
+		Incremental:     incremental,
 		Parameters: &CreationParameters{
 			Interval:       interval,
 			NumKept:        testNumKept,
@@ -88,6 +90,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 			}
 			return ndb.GetRootsForVersion(version)
 		},
+		Pool: pool,
 	})
 	require.NoError(err, "NewCheckpointer")
 
@@ -122,7 +125,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 		// Make sure that there are always the correct number of checkpoints.
 		if round > earliestVersion+(testNumKept+1)*interval {
 			cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-				Version:   checkpointVersion,
+				Version:   cp.(*checkpointer).version(),
 				Namespace: testNs,
 			})
 			require.NoError(err, "GetCheckpoints")
@@ -151,7 +154,7 @@ func testCheckpointer(t *testing.T, factory dbApi.Factory, earliestVersion, inte
 
 		// Make sure that the correct checkpoint was created.
 		cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
-			Version:   checkpointVersion,
+			Version:   cp.(*checkpointer).version(),
 			Namespace: testNs,
 		})
 		require.NoError(err, "GetCheckpoints")
@@ -173,18 +176,28 @@ func TestCheckpointer(t *testing.T) {
 
 func testCheckpointerWithBackend(t *testing.T, factory dbApi.Factory) {
 	t.Run("Basic", func(t *testing.T) {
-		testCheckpointer(t, factory, 0, 1, false)
+		testCheckpointer(t, factory, 0, 1, false, false, nil)
 	})
 	t.Run("NonZeroEarliestVersion", func(t *testing.T) {
-		testCheckpointer(t, factory, 1000, 1, false)
+		testCheckpointer(t, factory, 1000, 1, false, false, nil)
 	})
 	t.Run("NonZeroEarliestInitialVersion", func(t *testing.T) {
-		testCheckpointer(t, factory, 100, 1, true)
+		testCheckpointer(t, factory, 100, 1, true, false, nil)
 	})
 	t.Run("MaybeUnderflow", func(t *testing.T) {
-		testCheckpointer(t, factory, 5, 10, true)
+		testCheckpointer(t, factory, 5, 10, true, false, nil)
 	})
 	t.Run("ForceCheckpoint", func(t *testing.T) {
-		testCheckpointer(t, factory, 0, 10, false)
+		testCheckpointer(t, factory, 0, 10, false, false, nil)
+	})
+	t.Run("Incremental", func(t *testing.T) {
+		testCheckpointer(t, factory, 0, 10, false, true, nil)
+	})
+	t.Run("PriorityPool", func(t *testing.T) {
+		pool, err := workerpool.NewPriorityPool("checkpointer", workerpool.Limits{Global: 1})
+		require.NoError(t, err, "NewPriorityPool")
+		defer pool.Stop()
+
+		testCheckpointer(t, factory, 0, 1, false, false, pool)
 	})
 }
@@ -59,25 +59,48 @@ func createChunk(
 	it.Next()
 	nextOffset = it.Key()
 
+	chunkHash, err = writeChunk(proof, w)
+	return
+}
+
+// writeChunk writes the entries of the given proof as a chunk and returns the chunk hash.
+func writeChunk(proof *syncer.Proof, w io.Writer) (hash.Hash, error) {
 	hb := hash.NewBuilder()
 	sw := snappy.NewBufferedWriter(io.MultiWriter(w, hb))
 	enc := cbor.NewEncoder(sw)
 	for _, entry := range proof.Entries {
-		if err = enc.Encode(entry); err != nil {
-			err = fmt.Errorf("chunk: failed to encode chunk part: %w", err)
-			return
+		if err := enc.Encode(entry); err != nil {
+			return hash.Hash{}, fmt.Errorf("chunk: failed to encode chunk part: %w", err)
 		}
 	}
-	if err = sw.Close(); err != nil {
-		err = fmt.Errorf("chunk: failed to close chunk: %w", err)
-		return
+	if err := sw.Close(); err != nil {
+		return hash.Hash{}, fmt.Errorf("chunk: failed to close chunk: %w", err)
 	}
 
-	chunkHash = hb.Build()
-	return
+	return hb.Build(), nil
 }
 
 func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io.Reader) error {
+	p, err := decodeChunk(ctx, chunk, r)
+	if err != nil {
+		return err
+	}
+	p.UntrustedRoot = chunk.Root.Hash
+
+	// Verify the proof.
+	var pv syncer.ProofVerifier
+	ptr, err := pv.VerifyProof(ctx, chunk.Root.Hash, p)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
+	}
+
+	return importChunk(ctx, ndb, chunk.Root, ptr)
+}
+
+// decodeChunk decodes the proof entries of a chunk and verifies the chunk digest.
+//
+// The untrusted root of the returned proof is not set.
+func decodeChunk(ctx context.Context, chunk *ChunkMetadata, r io.Reader) (*syncer.Proof, error) {
 	hb := hash.NewBuilder()
 	tr := io.TeeReader(r, hb)
 	sr := snappy.NewReader(tr)
@@ -89,7 +112,7 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	p.V = checkpointProofsVersion
 	for {
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return nil, ctx.Err()
 		}
 
 		var entry []byte
@@ -107,12 +130,11 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 
 		p.Entries = append(p.Entries, entry)
 	}
-	p.UntrustedRoot = chunk.Root.Hash
 
 	// Verify overall chunk integrity.
 	chunkHash := hb.Build()
 	if !chunk.Digest.Equal(&chunkHash) {
-		return fmt.Errorf("%w: digest incorrect (expected: %s got: %s)",
+		return nil, fmt.Errorf("%w: digest incorrect (expected: %s got: %s)",
 			ErrChunkCorrupted,
 			chunk.Digest,
 			chunkHash,
@@ -121,25 +143,22 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 
 	// Treat decode errors after integrity verification as proof verification failures.
 	if decodeErr != nil {
-		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, decodeErr.Error())
+		return nil, fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, decodeErr.Error())
 	}
 
-	// Verify the proof.
-	var pv syncer.ProofVerifier
-	ptr, err := pv.VerifyProof(ctx, chunk.Root.Hash, &p)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
-	}
+	return &p, nil
+}
 
-	// Import chunk into the node database.
+// importChunk imports the nodes of a verified chunk into the node database.
+func importChunk(ctx context.Context, ndb db.NodeDB, root node.Root, ptr *node.Pointer) error {
 	emptyRoot := node.Root{
-		Namespace: chunk.Root.Namespace,
-		Version:   chunk.Root.Version,
-		Type:      chunk.Root.Type,
+		Namespace: root.Namespace,
+		Version:   root.Version,
+		Type:      root.Type,
 	}
 	emptyRoot.Hash.Empty()
 
-	batch, err := ndb.NewBatch(emptyRoot, chunk.Root.Version, true)
+	batch, err := ndb.NewBatch(emptyRoot, root.Version, true)
 	if err != nil {
 		return fmt.Errorf("chunk: failed to create batch: %w", err)
 	}
@@ -148,7 +167,7 @@ func restoreChunk(ctx context.Context, ndb db.NodeDB, chunk *ChunkMetadata, r io
 	if err = doRestoreChunk(ctx, batch, ptr, nil); err != nil {
 		return fmt.Errorf("chunk: node import failed: %w", err)
 	}
-	if err = batch.Commit(chunk.Root); err != nil {
+	if err = batch.Commit(root); err != nil {
 		return fmt.Errorf("chunk: node import failed: %w", err)
 	}
 
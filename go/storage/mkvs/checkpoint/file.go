@@ -31,16 +31,60 @@ type fileCreator struct {
 	ndb     db.NodeDB
 }
 
-func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (meta *Metadata, err error) {
-	tree := mkvs.NewWithRoot(nil, fc.ndb, root)
-	defer tree.Close()
+func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error) {
+	return fc.createCheckpoint(ctx, checkpointVersion, root, func(chunksDir string) (*Metadata, error) {
+		tree := mkvs.NewWithRoot(nil, fc.ndb, root)
+		defer tree.Close()
+
+		// Create chunks until we are done.
+		var chunks []hash.Hash
+		var nextOffset node.Key
+		for chunkIndex := 0; ; chunkIndex++ {
+			dataFilename := filepath.Join(chunksDir, strconv.Itoa(chunkIndex))
+
+			// Generate chunk.
+			f, err := os.Create(dataFilename)
+			if err != nil {
+				return nil, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", chunkIndex, err)
+			}
+
+			var chunkHash hash.Hash
+			chunkHash, nextOffset, err = createChunk(ctx, tree, root, nextOffset, chunkSize, f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("checkpoint: failed to create chunk %d: %w", chunkIndex, err)
+			}
+
+			chunks = append(chunks, chunkHash)
+
+			// Check if we are finished.
+			if nextOffset == nil {
+				break
+			}
+		}
+
+		return &Metadata{
+			Chunks: chunks,
+		}, nil
+	})
+}
 
+func (fc *fileCreator) CreateIncrementalCheckpoint(ctx context.Context, root node.Root, chunkSize uint64) (*Metadata, error) {
+	return fc.createCheckpoint(ctx, IncrementalVersion, root, func(chunksDir string) (*Metadata, error) {
+		return createIncrementalChunks(ctx, fc, root, chunkSize, chunksDir)
+	})
+}
+
+// createCheckpoint creates a checkpoint of the given version, using the given function to create
+// the chunks in the chunks directory.
+func (fc *fileCreator) createCheckpoint(
+	ctx context.Context,
+	version uint16,
+	root node.Root,
+	createChunks func(chunksDir string) (*Metadata, error),
+) (meta *Metadata, err error) {
 	// Create checkpoint directory.
-	checkpointDir := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-	)
+	checkpointDir := fc.checkpointDir(version, root)
 	if err = common.Mkdir(checkpointDir); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint directory: %w", err)
 	}
@@ -67,39 +111,13 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 		return nil, fmt.Errorf("checkpoint: failed to create chunk directory: %w", err)
 	}
 
-	// Create chunks until we are done.
-	var chunks []hash.Hash
-	var nextOffset node.Key
-	for chunkIndex := 0; ; chunkIndex++ {
-		dataFilename := filepath.Join(chunksDir, strconv.Itoa(chunkIndex))
-
-		// Generate chunk.
-		var f *os.File
-		if f, err = os.Create(dataFilename); err != nil {
-			return nil, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", chunkIndex, err)
-		}
-
-		var chunkHash hash.Hash
-		chunkHash, nextOffset, err = createChunk(ctx, tree, root, nextOffset, chunkSize, f)
-		f.Close()
-		if err != nil {
-			return nil, fmt.Errorf("checkpoint: failed to create chunk %d: %w", chunkIndex, err)
-		}
-
-		chunks = append(chunks, chunkHash)
-
-		// Check if we are finished.
-		if nextOffset == nil {
-			break
-		}
+	if meta, err = createChunks(chunksDir); err != nil {
+		return nil, err
 	}
 
 	// Generate and write checkpoint metadata.
-	meta = &Metadata{
-		Version: checkpointVersion,
-		Root:    root,
-		Chunks:  chunks,
-	}
+	meta.Version = version
+	meta.Root = root
 
 	if err = os.WriteFile(filepath.Join(checkpointDir, checkpointMetadataFile), cbor.Marshal(meta), 0o600); err != nil {
 		return nil, fmt.Errorf("checkpoint: failed to create checkpoint metadata: %w", err)
@@ -108,8 +126,8 @@ func (fc *fileCreator) CreateCheckpoint(ctx context.Context, root node.Root, chu
 }
 
 func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpointsRequest) ([]*Metadata, error) {
-	// Currently we only support a single version so we report no checkpoints for other versions.
-	if request.Version != checkpointVersion {
+	// Report no checkpoints for unsupported versions.
+	if !isSupportedVersion(request.Version) {
 		return []*Metadata{}, nil
 	}
 
@@ -135,24 +153,57 @@ func (fc *fileCreator) GetCheckpoints(_ context.Context, request *GetCheckpoints
 		if err = cbor.Unmarshal(data, &cp); err != nil {
 			return nil, fmt.Errorf("checkpoint: corrupted checkpoint metadata at %s: %w", m, err)
 		}
+		if cp.Version != request.Version {
+			continue
+		}
+		cp.Availability = fc.checkpointAvailability(&cp)
 
 		cps = append(cps, &cp)
 	}
 	return cps, nil
 }
 
-func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root node.Root) (*Metadata, error) {
-	// Currently we only support a single version.
+// checkpointAvailability determines which of the checkpoint chunks are present.
+func (fc *fileCreator) checkpointAvailability(cp *Metadata) *Availability {
+	av := Availability{
+		Complete: true,
+	}
+	for idx := range cp.Chunks {
+		fi, err := os.Stat(fc.chunkFilename(cp.Version, cp.Root, uint64(idx)))
+		if err != nil || !fi.Mode().IsRegular() {
+			av.Complete = false
+			continue
+		}
+
+		av.ChunkCount++
+		av.Size += uint64(fi.Size())
+	}
+	return &av
+}
+
+// checkpointDir returns the directory of the checkpoint of the given version at the given root.
+func (fc *fileCreator) checkpointDir(version uint16, root node.Root) string {
+	name := root.Hash.String()
 	if version != checkpointVersion {
-		return nil, ErrCheckpointNotFound
+		name += ".v" + strconv.FormatUint(uint64(version), 10)
 	}
+	return filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10), name)
+}
 
-	checkpointFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(root.Version, 10),
-		root.Hash.String(),
-		checkpointMetadataFile,
+func (fc *fileCreator) chunkFilename(version uint16, root node.Root, index uint64) string {
+	return filepath.Join(
+		fc.checkpointDir(version, root),
+		chunksDir,
+		strconv.FormatUint(index, 10),
 	)
+}
+
+func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root node.Root) (*Metadata, error) {
+	if !isSupportedVersion(version) {
+		return nil, ErrCheckpointNotFound
+	}
+
+	checkpointFilename := filepath.Join(fc.checkpointDir(version, root), checkpointMetadataFile)
 	data, err := os.ReadFile(checkpointFilename)
 	if err != nil {
 		return nil, ErrCheckpointNotFound
@@ -166,13 +217,12 @@ func (fc *fileCreator) GetCheckpoint(_ context.Context, version uint16, root nod
 }
 
 func (fc *fileCreator) DeleteCheckpoint(_ context.Context, version uint16, root node.Root) error {
-	// Currently we only support a single version.
-	if version != checkpointVersion {
+	if !isSupportedVersion(version) {
 		return ErrCheckpointNotFound
 	}
 
 	versionDir := filepath.Join(fc.dataDir, strconv.FormatUint(root.Version, 10))
-	checkpointDir := filepath.Join(versionDir, root.Hash.String())
+	checkpointDir := fc.checkpointDir(version, root)
 	checkpointFilename := filepath.Join(checkpointDir, checkpointMetadataFile)
 	if err := os.Remove(checkpointFilename); err != nil {
 		return ErrCheckpointNotFound
@@ -205,20 +255,11 @@ func (fc *fileCreator) DeleteCheckpoint(_ context.Context, version uint16, root
 }
 
 func (fc *fileCreator) GetCheckpointChunk(_ context.Context, chunk *ChunkMetadata, w io.Writer) error {
-	// Currently we only support a single version.
-	if chunk.Version != checkpointVersion {
+	if !isSupportedVersion(chunk.Version) {
 		return ErrChunkNotFound
 	}
 
-	chunkFilename := filepath.Join(
-		fc.dataDir,
-		strconv.FormatUint(chunk.Root.Version, 10),
-		chunk.Root.Hash.String(),
-		chunksDir,
-		strconv.FormatUint(chunk.Index, 10),
-	)
-
-	f, err := os.Open(chunkFilename)
+	f, err := os.Open(fc.chunkFilename(chunk.Version, chunk.Root, chunk.Index))
 	if err != nil {
 		return ErrChunkNotFound
 	}
@@ -230,6 +271,33 @@ func (fc *fileCreator) GetCheckpointChunk(_ context.Context, chunk *ChunkMetadat
 	return nil
 }
 
+func (fc *fileCreator) GetCheckpointChunkInfo(ctx context.Context, chunk *ChunkMetadata) (*ChunkInfo, error) {
+	cp, err := fc.GetCheckpoint(ctx, chunk.Version, chunk.Root)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := cp.GetChunkMetadata(chunk.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(fc.chunkFilename(chunk.Version, chunk.Root, chunk.Index))
+	if err != nil || !fi.Mode().IsRegular() {
+		return nil, ErrChunkNotFound
+	}
+
+	return &ChunkInfo{
+		Index:  cm.Index,
+		Size:   uint64(fi.Size()),
+		Digest: cm.Digest,
+	}, nil
+}
+
+// isSupportedVersion returns true iff the given checkpoint version is supported.
+func isSupportedVersion(version uint16) bool {
+	return version == checkpointVersion || version == IncrementalVersion
+}
+
 // NewFileCreator creates a new checkpoint creator that writes created chunks into the filesystem.
 func NewFileCreator(dataDir string, ndb db.NodeDB) (Creator, error) {
 	return &fileCreator{
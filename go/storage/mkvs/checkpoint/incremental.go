@@ -0,0 +1,450 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+// Incremental checkpoints consist of a skeleton chunk (at index zero) and of subtree chunks.
+//
+// The skeleton chunk is a proof against the tree root which contains all internal nodes whose
+// subtrees do not fit into a single chunk. Each of the remaining chunks is a proof against the
+// root of a subtree that is referenced (by hash) from the skeleton, in the order in which the
+// subtrees are referenced. As subtree chunks only depend on the content of their subtree, the
+// chunks of subtrees that did not change are identical between checkpoints.
+
+// skeleton is the verified skeleton of an incremental checkpoint.
+type skeleton struct {
+	// root is the root of the skeleton.
+	root *node.Pointer
+	// subtrees are the subtrees referenced from the skeleton in chunk order.
+	subtrees []*skeletonSubtree
+}
+
+// skeletonSubtree is a subtree referenced from the skeleton.
+type skeletonSubtree struct {
+	// hash is the hash of the subtree root.
+	hash hash.Hash
+	// path are the skeleton nodes on the path from the root to the subtree.
+	path []*node.InternalNode
+	// left specifies for each node on the path whether the path continues to the left.
+	left []bool
+}
+
+func (sk *skeleton) collectSubtrees(ptr *node.Pointer, path []*node.InternalNode, left []bool) {
+	if ptr == nil {
+		return
+	}
+
+	switch n := ptr.Node.(type) {
+	case nil:
+		sk.subtrees = append(sk.subtrees, &skeletonSubtree{
+			hash: ptr.Hash,
+			path: slices.Clone(path),
+			left: slices.Clone(left),
+		})
+	case *node.InternalNode:
+		sk.collectSubtrees(n.Left, append(path, n), append(left, true))
+		sk.collectSubtrees(n.Right, append(path, n), append(left, false))
+	}
+}
+
+// graft attaches the given subtree to a fresh copy of the skeleton path leading to it, so that
+// it can be imported in the same way as a regular checkpoint chunk.
+func (sk *skeleton) graft(index int, subtree *node.Pointer) *node.Pointer {
+	st := sk.subtrees[index]
+
+	ptr := subtree
+	for i := len(st.path) - 1; i >= 0; i-- {
+		n := st.path[i]
+
+		nd := &node.InternalNode{
+			Clean:          true,
+			Hash:           n.Hash,
+			Label:          n.Label,
+			LabelBitLength: n.LabelBitLength,
+			LeafNode:       copyPointer(n.LeafNode, true),
+			Left:           copyPointer(n.Left, false),
+			Right:          copyPointer(n.Right, false),
+		}
+		switch st.left[i] {
+		case true:
+			nd.Left = ptr
+		case false:
+			nd.Right = ptr
+		}
+
+		ptr = &node.Pointer{
+			Clean: true,
+			Hash:  n.Hash,
+			Node:  nd,
+		}
+	}
+	return ptr
+}
+
+// copyPointer makes a copy of the pointer without any node database metadata, optionally also
+// copying the node pointed to.
+func copyPointer(ptr *node.Pointer, withNode bool) *node.Pointer {
+	if ptr == nil {
+		return nil
+	}
+
+	cp := &node.Pointer{
+		Clean: true,
+		Hash:  ptr.Hash,
+	}
+	if withNode && ptr.Node != nil {
+		cp.Node = ptr.Node.ExtractUnchecked()
+	}
+	return cp
+}
+
+// verifySkeleton verifies the skeleton chunk proof against the given root.
+func verifySkeleton(ctx context.Context, root hash.Hash, p *syncer.Proof) (*skeleton, error) {
+	p.UntrustedRoot = root
+
+	var pv syncer.ProofVerifier
+	ptr, err := pv.VerifyProof(ctx, root, p)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
+	}
+
+	sk := &skeleton{
+		root: ptr,
+	}
+	sk.collectSubtrees(ptr, nil, nil)
+	return sk, nil
+}
+
+// restoreSubtreeChunk verifies the given subtree chunk proof against the subtree referenced from
+// the skeleton and imports it into the node database.
+func restoreSubtreeChunk(
+	ctx context.Context,
+	ndb db.NodeDB,
+	root node.Root,
+	sk *skeleton,
+	index uint64,
+	p *syncer.Proof,
+) error {
+	if index == 0 || index > uint64(len(sk.subtrees)) {
+		return fmt.Errorf("%w: chunk %d not referenced from skeleton", ErrChunkProofVerificationFailed, index)
+	}
+	st := sk.subtrees[index-1]
+	p.UntrustedRoot = st.hash
+
+	var pv syncer.ProofVerifier
+	ptr, err := pv.VerifyProof(ctx, st.hash, p)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrChunkProofVerificationFailed, err.Error())
+	}
+
+	return importChunk(ctx, ndb, root, sk.graft(int(index-1), ptr))
+}
+
+// incrementalCreator creates the chunks of an incremental checkpoint.
+type incrementalCreator struct {
+	fc        *fileCreator
+	root      node.Root
+	chunkSize uint64
+	chunksDir string
+
+	// previous is the most recent earlier incremental checkpoint of the same root type.
+	previous *Metadata
+	// reusable maps subtree hashes to the chunks of the previous checkpoint.
+	reusable map[hash.Hash]uint64
+	// split contains the internal nodes whose subtrees do not fit into a single chunk.
+	split map[hash.Hash]*node.InternalNode
+
+	skeleton *syncer.ProofBuilder
+	meta     Metadata
+}
+
+func createIncrementalChunks(
+	ctx context.Context,
+	fc *fileCreator,
+	root node.Root,
+	chunkSize uint64,
+	chunksDir string,
+) (*Metadata, error) {
+	ic := &incrementalCreator{
+		fc:        fc,
+		root:      root,
+		chunkSize: chunkSize,
+		chunksDir: chunksDir,
+		reusable:  make(map[hash.Hash]uint64),
+		split:     make(map[hash.Hash]*node.InternalNode),
+		// Incremental checkpoints use V0 proofs, same as V1 checkpoints.
+		skeleton: syncer.NewProofBuilderV0(root.Hash, root.Hash),
+	}
+
+	// A missing or corrupted previous checkpoint only prevents chunks from being reused.
+	_ = ic.loadPrevious(ctx)
+
+	// Reserve the first chunk for the skeleton which is only known after all subtree chunks have
+	// been created.
+	ic.meta.Chunks = []hash.Hash{{}}
+
+	if !root.Hash.IsEmpty() {
+		rootPtr := &node.Pointer{
+			Clean: true,
+			Hash:  root.Hash,
+		}
+		if _, err := ic.measure(ctx, rootPtr); err != nil {
+			return nil, err
+		}
+		if err := ic.createChunks(ctx, rootPtr); err != nil {
+			return nil, err
+		}
+	}
+
+	proof, err := ic.skeleton.Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint: failed to build skeleton: %w", err)
+	}
+	if ic.meta.Chunks[0], err = ic.writeChunk(0, proof); err != nil {
+		return nil, err
+	}
+
+	return &ic.meta, nil
+}
+
+// loadPrevious loads the subtrees of the most recent earlier incremental checkpoint.
+func (ic *incrementalCreator) loadPrevious(ctx context.Context) error {
+	cps, err := ic.fc.GetCheckpoints(ctx, &GetCheckpointsRequest{
+		Version:   IncrementalVersion,
+		Namespace: ic.root.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	var previous *Metadata
+	for _, cp := range cps {
+		switch {
+		case !cp.Root.Namespace.Equal(&ic.root.Namespace) || cp.Root.Type != ic.root.Type:
+			continue
+		case cp.Root.Version >= ic.root.Version || !cp.IsComplete():
+			continue
+		case previous != nil && cp.Root.Version <= previous.Root.Version:
+			continue
+		default:
+			previous = cp
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+
+	cm, err := previous.GetChunkMetadata(0)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(ic.fc.chunkFilename(IncrementalVersion, previous.Root, 0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	p, err := decodeChunk(ctx, cm, f)
+	if err != nil {
+		return err
+	}
+	sk, err := verifySkeleton(ctx, previous.Root.Hash, p)
+	if err != nil {
+		return err
+	}
+	if len(sk.subtrees) != len(previous.Chunks)-1 {
+		return fmt.Errorf("checkpoint: skeleton does not match previous checkpoint")
+	}
+
+	ic.previous = previous
+	for i, st := range sk.subtrees {
+		ic.reusable[st.hash] = uint64(i + 1)
+	}
+	return nil
+}
+
+// getNode resolves the given pointer, including the leaf of internal nodes.
+func (ic *incrementalCreator) getNode(ptr *node.Pointer) (node.Node, error) {
+	nd := ptr.Node
+	if nd == nil {
+		var err error
+		if nd, err = ic.fc.ndb.GetNode(ic.root, ptr); err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to get node %s: %w", ptr.Hash, err)
+		}
+	}
+
+	if n, ok := nd.(*node.InternalNode); ok && n.LeafNode != nil && n.LeafNode.Node == nil {
+		leaf, err := ic.fc.ndb.GetNode(ic.root, n.LeafNode)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint: failed to get node %s: %w", n.LeafNode.Hash, err)
+		}
+		n.LeafNode.Node = leaf
+	}
+	return nd, nil
+}
+
+// measure returns the chunk size of the subtree rooted at the given pointer and records all
+// internal nodes whose subtrees need to be split between multiple chunks.
+//
+// Subtrees that can be reused from the previous checkpoint are not traversed.
+func (ic *incrementalCreator) measure(ctx context.Context, ptr *node.Pointer) (uint64, error) {
+	if ptr == nil {
+		return 0, nil
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if _, ok := ic.reusable[ptr.Hash]; ok {
+		// Make sure that reused subtrees remain separate chunks.
+		return ic.chunkSize + 1, nil
+	}
+
+	nd, err := ic.getNode(ptr)
+	if err != nil {
+		return 0, err
+	}
+	size, err := proofNodeSize(nd)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := nd.(*node.InternalNode)
+	if !ok {
+		// Leaf nodes are never split, even if they are larger than the chunk size.
+		return size, nil
+	}
+	for _, child := range []*node.Pointer{n.Left, n.Right} {
+		var childSize uint64
+		if childSize, err = ic.measure(ctx, child); err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+	if size > ic.chunkSize {
+		ic.split[n.Hash] = n
+	}
+	return size, nil
+}
+
+// createChunks adds the split internal nodes to the skeleton and creates a chunk for each of the
+// remaining subtrees, in the order in which the skeleton references them.
+func (ic *incrementalCreator) createChunks(ctx context.Context, ptr *node.Pointer) error {
+	if ptr == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	n, ok := ic.split[ptr.Hash]
+	if !ok {
+		return ic.createSubtreeChunk(ctx, ptr)
+	}
+
+	ic.skeleton.Include(n)
+	for _, child := range []*node.Pointer{n.Left, n.Right} {
+		if err := ic.createChunks(ctx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSubtreeChunk creates a chunk containing the subtree rooted at the given pointer, reusing
+// the chunk of the previous checkpoint when possible.
+func (ic *incrementalCreator) createSubtreeChunk(ctx context.Context, ptr *node.Pointer) error {
+	index := uint64(len(ic.meta.Chunks))
+
+	if srcIndex, ok := ic.reusable[ptr.Hash]; ok {
+		// Link the chunk of the previous checkpoint, so that it remains available even after the
+		// previous checkpoint is removed. In case this fails, the chunk is simply re-created.
+		err := os.Link(
+			ic.fc.chunkFilename(IncrementalVersion, ic.previous.Root, srcIndex),
+			filepath.Join(ic.chunksDir, strconv.FormatUint(index, 10)),
+		)
+		if err == nil {
+			ic.meta.Chunks = append(ic.meta.Chunks, ic.previous.Chunks[srcIndex])
+			ic.meta.Reused = append(ic.meta.Reused, ChunkReference{
+				Index:       index,
+				Root:        ic.previous.Root,
+				SourceIndex: srcIndex,
+			})
+			return nil
+		}
+	}
+
+	pb := syncer.NewProofBuilderV0(ptr.Hash, ptr.Hash)
+	if err := ic.includeSubtree(ctx, pb, ptr); err != nil {
+		return err
+	}
+	proof, err := pb.Build(ctx)
+	if err != nil {
+		return fmt.Errorf("checkpoint: failed to build chunk %d: %w", index, err)
+	}
+
+	chunkHash, err := ic.writeChunk(index, proof)
+	if err != nil {
+		return err
+	}
+	ic.meta.Chunks = append(ic.meta.Chunks, chunkHash)
+	return nil
+}
+
+// includeSubtree includes all nodes of the subtree rooted at the given pointer into the proof.
+func (ic *incrementalCreator) includeSubtree(ctx context.Context, pb *syncer.ProofBuilder, ptr *node.Pointer) error {
+	if ptr == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	nd, err := ic.getNode(ptr)
+	if err != nil {
+		return err
+	}
+	pb.Include(nd)
+
+	if n, ok := nd.(*node.InternalNode); ok {
+		for _, child := range []*node.Pointer{n.Left, n.Right} {
+			if err = ic.includeSubtree(ctx, pb, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ic *incrementalCreator) writeChunk(index uint64, proof *syncer.Proof) (hash.Hash, error) {
+	f, err := os.Create(filepath.Join(ic.chunksDir, strconv.FormatUint(index, 10)))
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("checkpoint: failed to create chunk file for chunk %d: %w", index, err)
+	}
+	defer f.Close()
+
+	chunkHash, err := writeChunk(proof, f)
+	if err != nil {
+		return hash.Hash{}, fmt.Errorf("checkpoint: failed to create chunk %d: %w", index, err)
+	}
+	return chunkHash, nil
+}
+
+// proofNodeSize returns the size of the given node when included in a checkpoint chunk.
+func proofNodeSize(nd node.Node) (uint64, error) {
+	data, err := nd.CompactMarshalBinaryV0()
+	if err != nil {
+		return 0, fmt.Errorf("checkpoint: failed to marshal node: %w", err)
+	}
+	return 1 + uint64(len(data)), nil
+}
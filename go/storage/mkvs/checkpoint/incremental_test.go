@@ -0,0 +1,360 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
+	dbApi "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	dbTesting "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/testing"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+const (
+	testIncrementalNumKeys   = 10_000
+	testIncrementalChunkSize = 1024
+)
+
+func testIncrementalKey(i int) []byte {
+	return []byte(fmt.Sprintf("key %05d", i))
+}
+
+func testIncrementalValue(i int, version uint64) []byte {
+	return []byte(fmt.Sprintf("value %05d at version %03d", i, version))
+}
+
+func TestIncrementalCheckpoint(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testIncrementalCheckpoint)
+}
+
+func testIncrementalCheckpoint(t *testing.T, factory dbApi.Factory) {
+	require := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	ndb, err := factory.New(&dbApi.Config{
+		DB:           filepath.Join(dir, "db"),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	// Create the first version of the state.
+	tree := mkvs.New(nil, ndb, node.RootTypeState)
+	for i := 0; i < testIncrementalNumKeys; i++ {
+		err = tree.Insert(ctx, testIncrementalKey(i), testIncrementalValue(i, 1))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash1, err := tree.Commit(ctx, testNs, 1)
+	require.NoError(err, "Commit")
+	tree.Close()
+	root1 := node.Root{
+		Namespace: testNs,
+		Version:   1,
+		Type:      node.RootTypeState,
+		Hash:      rootHash1,
+	}
+	require.NoError(ndb.Finalize([]node.Root{root1}), "Finalize")
+
+	cp1, err := fc.CreateIncrementalCheckpoint(ctx, root1, testIncrementalChunkSize)
+	require.NoError(err, "CreateIncrementalCheckpoint")
+	require.Equal(IncrementalVersion, cp1.Version, "version should be correct")
+	require.Equal(root1, cp1.Root, "checkpoint root should be correct")
+	require.Empty(cp1.Reused, "first checkpoint should not reuse any chunks")
+	require.Greater(len(cp1.Chunks), 100, "there should be many chunks")
+
+	// Chunking should be deterministic.
+	cp1Again, err := fc.CreateIncrementalCheckpoint(ctx, root1, testIncrementalChunkSize)
+	require.NoError(err, "CreateIncrementalCheckpoint")
+	require.Equal(cp1, cp1Again, "existing checkpoint should be returned")
+
+	// Regular and incremental checkpoints should be kept separately.
+	_, err = fc.GetCheckpoint(ctx, checkpointVersion, root1)
+	require.ErrorIs(err, ErrCheckpointNotFound, "regular checkpoint should not exist")
+	cps, err := fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: checkpointVersion})
+	require.NoError(err, "GetCheckpoints")
+	require.Empty(cps, "there should be no regular checkpoints")
+
+	// Change 1% of the keys, keeping the value sizes the same.
+	tree = mkvs.NewWithRoot(nil, ndb, root1)
+	var numChanged int
+	for i := 0; i < testIncrementalNumKeys; i += 100 {
+		err = tree.Insert(ctx, testIncrementalKey(i), testIncrementalValue(i, 2))
+		require.NoError(err, "Insert")
+		numChanged++
+	}
+	_, rootHash2, err := tree.Commit(ctx, testNs, 2)
+	require.NoError(err, "Commit")
+	tree.Close()
+	root2 := node.Root{
+		Namespace: testNs,
+		Version:   2,
+		Type:      node.RootTypeState,
+		Hash:      rootHash2,
+	}
+	require.NoError(ndb.Finalize([]node.Root{root2}), "Finalize")
+
+	cp2, err := fc.CreateIncrementalCheckpoint(ctx, root2, testIncrementalChunkSize)
+	require.NoError(err, "CreateIncrementalCheckpoint")
+	require.Len(cp2.Chunks, len(cp1.Chunks), "number of chunks should not change")
+
+	// Only chunks containing changed keys (and the skeleton) should be fresh.
+	numFresh := len(cp2.Chunks) - len(cp2.Reused)
+	require.LessOrEqual(numFresh, numChanged+1, "only chunks of changed subtrees should be fresh")
+	require.Less(numFresh*4, len(cp2.Chunks), "fresh chunks should be a small fraction of all chunks")
+
+	for _, ref := range cp2.Reused {
+		require.Equal(root1, ref.Root, "chunks should be reused from the previous checkpoint")
+		require.Equal(cp1.Chunks[ref.SourceIndex], cp2.Chunks[ref.Index], "reused chunk digest should match")
+
+		src := cp2.GetChunkSource(ref.Index)
+		require.NotNil(src, "GetChunkSource")
+		require.Equal(root1, src.Root, "chunk source root should be correct")
+		require.Equal(ref.SourceIndex, src.Index, "chunk source index should be correct")
+	}
+	require.Nil(cp2.GetChunkSource(0), "skeleton should never be reused")
+
+	// Reused chunks should remain available after the previous checkpoint is removed.
+	require.NoError(fc.DeleteCheckpoint(ctx, IncrementalVersion, root1), "DeleteCheckpoint")
+	cps, err = fc.GetCheckpoints(ctx, &GetCheckpointsRequest{Version: IncrementalVersion})
+	require.NoError(err, "GetCheckpoints")
+	require.Len(cps, 1, "there should be one checkpoint")
+	require.True(cps[0].IsComplete(), "checkpoint should be complete")
+	require.Equal(cp2.EncodedHash(), cps[0].EncodedHash(), "checkpoint should be correct")
+
+	fetchChunk := func(cp *Metadata, idx uint64) []byte {
+		cm, err := cp.GetChunkMetadata(idx)
+		require.NoError(err, "GetChunkMetadata")
+
+		var buf bytes.Buffer
+		err = fc.GetCheckpointChunk(ctx, cm, &buf)
+		require.NoError(err, "GetCheckpointChunk")
+		return buf.Bytes()
+	}
+
+	newRestoreDB := func(name string) (dbApi.NodeDB, Restorer) {
+		rdb, err := factory.New(&dbApi.Config{
+			DB:           filepath.Join(dir, name),
+			Namespace:    testNs,
+			MaxCacheSize: 16 * 1024 * 1024,
+		})
+		require.NoError(err, "New")
+		t.Cleanup(rdb.Close)
+
+		rs, err := NewRestorer(rdb)
+		require.NoError(err, "NewRestorer")
+
+		err = rdb.StartMultipartInsert(root2.Version)
+		require.NoError(err, "StartMultipartInsert")
+		return rdb, rs
+	}
+
+	// Chunks which do not match the subtrees referenced from the skeleton should be rejected.
+	_, rs := newRestoreDB("db-bogus")
+	bogusCp := *cp2
+	bogusCp.Chunks = append([]hash.Hash{}, cp2.Chunks...)
+	bogusCp.Chunks[1], bogusCp.Chunks[2] = cp2.Chunks[2], cp2.Chunks[1]
+	require.NoError(rs.StartRestore(ctx, &bogusCp), "StartRestore")
+	_, err = rs.RestoreChunk(ctx, 1, bytes.NewReader(fetchChunk(cp2, 2)))
+	require.NoError(err, "RestoreChunk should defer chunks received before the skeleton")
+	_, err = rs.RestoreChunk(ctx, 0, bytes.NewReader(fetchChunk(cp2, 0)))
+	require.ErrorIs(err, ErrChunkProofVerificationFailed, "RestoreChunk should fail with swapped chunks")
+	require.Nil(rs.GetCurrentCheckpoint(), "restore should be aborted")
+
+	// Restore the checkpoint in reverse order, so that subtree chunks are received before the
+	// skeleton.
+	rdb, rs := newRestoreDB("db-restore")
+	require.NoError(rs.StartRestore(ctx, cp2), "StartRestore")
+	for i := len(cp2.Chunks) - 1; i >= 0; i-- {
+		data := fetchChunk(cp2, uint64(i))
+
+		// Corrupted chunks should be rejected.
+		_, err = rs.RestoreChunk(ctx, uint64(i), bytes.NewReader([]byte("corrupted chunk")))
+		require.ErrorIs(err, ErrChunkCorrupted, "RestoreChunk should fail with corrupted chunk")
+
+		var done bool
+		done, err = rs.RestoreChunk(ctx, uint64(i), bytes.NewReader(data))
+		require.NoError(err, "RestoreChunk")
+		require.Equal(i == 0, done, "RestoreChunk should signal completed restoration when done")
+
+		if i > 0 {
+			_, err = rs.RestoreChunk(ctx, uint64(i), bytes.NewReader(data))
+			require.ErrorIs(err, ErrChunkAlreadyRestored, "RestoreChunk should fail for duplicate chunks")
+		}
+	}
+	require.NoError(rdb.Finalize([]node.Root{root2}), "Finalize")
+
+	// Verify that everything has been restored.
+	tree = mkvs.NewWithRoot(nil, rdb, root2)
+	defer tree.Close()
+	for i := 0; i < testIncrementalNumKeys; i++ {
+		version := uint64(1)
+		if i%100 == 0 {
+			version = 2
+		}
+
+		var value []byte
+		value, err = tree.Get(ctx, testIncrementalKey(i))
+		require.NoError(err, "Get(%d)", i)
+		require.Equal(testIncrementalValue(i, version), value)
+	}
+
+	// The restored tree should be complete.
+	var numLeaves int
+	err = dbApi.Visit(ctx, rdb, root2, func(_ context.Context, n node.Node) bool {
+		if _, ok := n.(*node.LeafNode); ok {
+			numLeaves++
+		}
+		return true
+	})
+	require.NoError(err, "Visit")
+	require.Equal(testIncrementalNumKeys, numLeaves, "all leaves should be restored")
+}
+
+func TestIncrementalCheckpointSmallTrees(t *testing.T) {
+	dbTesting.TestMultipleBackends(t, db.Backends, testIncrementalCheckpointSmallTrees)
+}
+
+func testIncrementalCheckpointSmallTrees(t *testing.T, factory dbApi.Factory) {
+	for _, numKeys := range []int{0, 1, 10} {
+		t.Run(fmt.Sprintf("Keys%d", numKeys), func(t *testing.T) {
+			require := require.New(t)
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			ndb, err := factory.New(&dbApi.Config{
+				DB:        filepath.Join(dir, "db"),
+				Namespace: testNs,
+			})
+			require.NoError(err, "New")
+			defer ndb.Close()
+
+			tree := mkvs.New(nil, ndb, node.RootTypeState)
+			for i := 0; i < numKeys; i++ {
+				err = tree.Insert(ctx, testIncrementalKey(i), testIncrementalValue(i, 1))
+				require.NoError(err, "Insert")
+			}
+			_, rootHash, err := tree.Commit(ctx, testNs, 1)
+			require.NoError(err, "Commit")
+			tree.Close()
+			root := node.Root{
+				Namespace: testNs,
+				Version:   1,
+				Type:      node.RootTypeState,
+				Hash:      rootHash,
+			}
+			require.NoError(ndb.Finalize([]node.Root{root}), "Finalize")
+
+			fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+			require.NoError(err, "NewFileCreator")
+			cp, err := fc.CreateIncrementalCheckpoint(ctx, root, testIncrementalChunkSize)
+			require.NoError(err, "CreateIncrementalCheckpoint")
+
+			expectedChunks := 2
+			if numKeys == 0 {
+				expectedChunks = 1
+			}
+			require.Len(cp.Chunks, expectedChunks, "there should be the correct number of chunks")
+
+			rdb, err := factory.New(&dbApi.Config{
+				DB:        filepath.Join(dir, "db-restore"),
+				Namespace: testNs,
+			})
+			require.NoError(err, "New")
+			defer rdb.Close()
+
+			rs, err := NewRestorer(rdb)
+			require.NoError(err, "NewRestorer")
+			require.NoError(rdb.StartMultipartInsert(root.Version), "StartMultipartInsert")
+			require.NoError(rs.StartRestore(ctx, cp), "StartRestore")
+			for i := range cp.Chunks {
+				cm, err := cp.GetChunkMetadata(uint64(i))
+				require.NoError(err, "GetChunkMetadata")
+
+				var buf bytes.Buffer
+				require.NoError(fc.GetCheckpointChunk(ctx, cm, &buf), "GetCheckpointChunk")
+				done, err := rs.RestoreChunk(ctx, uint64(i), &buf)
+				require.NoError(err, "RestoreChunk")
+				require.Equal(i == len(cp.Chunks)-1, done, "RestoreChunk should signal completed restoration when done")
+			}
+			require.NoError(rdb.Finalize([]node.Root{root}), "Finalize")
+
+			tree = mkvs.NewWithRoot(nil, rdb, root)
+			defer tree.Close()
+			for i := 0; i < numKeys; i++ {
+				value, err := tree.Get(ctx, testIncrementalKey(i))
+				require.NoError(err, "Get(%d)", i)
+				require.Equal(testIncrementalValue(i, 1), value)
+			}
+		})
+	}
+}
+
+func TestIncrementalCheckpointMissingPrevious(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	ndb, err := db.Backends[0].New(&dbApi.Config{
+		DB:        filepath.Join(dir, "db"),
+		Namespace: testNs,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	fc, err := NewFileCreator(filepath.Join(dir, "checkpoints"), ndb)
+	require.NoError(err, "NewFileCreator")
+
+	root := node.Root{
+		Namespace: testNs,
+		Type:      node.RootTypeState,
+	}
+	root.Hash.Empty()
+
+	var cps []*Metadata
+	for version := uint64(1); version <= 2; version++ {
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		for i := 0; i < 1000; i++ {
+			err = tree.Insert(ctx, testIncrementalKey(i), testIncrementalValue(i, 1))
+			require.NoError(err, "Insert")
+		}
+		_, rootHash, err := tree.Commit(ctx, testNs, version)
+		require.NoError(err, "Commit")
+		tree.Close()
+
+		root.Version = version
+		root.Hash = rootHash
+		require.NoError(ndb.Finalize([]node.Root{root}), "Finalize")
+
+		if version == 1 {
+			cp, err := fc.CreateIncrementalCheckpoint(ctx, root, testIncrementalChunkSize)
+			require.NoError(err, "CreateIncrementalCheckpoint")
+			cps = append(cps, cp)
+
+			// Remove one of the chunks, which should prevent it from being reused.
+			err = os.Remove(fc.(*fileCreator).chunkFilename(IncrementalVersion, root, 1))
+			require.NoError(err, "Remove")
+			continue
+		}
+
+		cp, err := fc.CreateIncrementalCheckpoint(ctx, root, testIncrementalChunkSize)
+		require.NoError(err, "CreateIncrementalCheckpoint")
+		cps = append(cps, cp)
+	}
+
+	// Incomplete checkpoints should not be reused.
+	require.Empty(cps[1].Reused, "incomplete checkpoint should not be reused")
+	require.Equal(cps[0].Chunks, cps[1].Chunks, "identical trees should have identical chunks")
+}
@@ -3,10 +3,12 @@ package checkpoint
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 
 	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 // restorer is a checkpoint restorer.
@@ -20,6 +22,12 @@ type restorer struct {
 	currentCheckpoint *Metadata
 	// pendingChunks is a set of pending chunks.
 	pendingChunks map[uint64]bool
+
+	// skeleton is the verified skeleton of the incremental checkpoint that is being restored.
+	skeleton *skeleton
+	// deferredChunks are the incremental checkpoint chunks that were received before the skeleton
+	// and can only be verified once the skeleton has been restored.
+	deferredChunks map[uint64]*syncer.Proof
 }
 
 // Implements Restorer.
@@ -36,6 +44,8 @@ func (rs *restorer) StartRestore(_ context.Context, checkpoint *Metadata) error
 	for idx := range checkpoint.Chunks {
 		rs.pendingChunks[uint64(idx)] = true
 	}
+	rs.skeleton = nil
+	rs.deferredChunks = make(map[uint64]*syncer.Proof)
 
 	return nil
 }
@@ -46,6 +56,8 @@ func (rs *restorer) AbortRestore(context.Context) error {
 
 	rs.pendingChunks = nil
 	rs.currentCheckpoint = nil
+	rs.skeleton = nil
+	rs.deferredChunks = nil
 
 	return nil
 }
@@ -73,7 +85,7 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 		}
 
 		// Check if the given chunk is still pending.
-		if !rs.pendingChunks[idx] {
+		if !rs.pendingChunks[idx] || rs.deferredChunks[idx] != nil {
 			return nil, ErrChunkAlreadyRestored
 		}
 
@@ -83,7 +95,14 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 		return false, err
 	}
 
-	err = restoreChunk(ctx, rs.ndb, chunk, r)
+	var restored []uint64
+	switch chunk.Version {
+	case IncrementalVersion:
+		restored, err = rs.restoreIncrementalChunk(ctx, chunk, r)
+	default:
+		err = restoreChunk(ctx, rs.ndb, chunk, r)
+		restored = []uint64{idx}
+	}
 	switch {
 	case err == nil:
 	case errors.Is(err, ErrChunkProofVerificationFailed):
@@ -98,19 +117,98 @@ func (rs *restorer) RestoreChunk(ctx context.Context, idx uint64, r io.Reader) (
 	rs.Lock()
 	defer rs.Unlock()
 
-	// Mark the given chunk as restored.
-	delete(rs.pendingChunks, idx)
+	// Mark the given chunks as restored.
+	for _, idx := range restored {
+		delete(rs.pendingChunks, idx)
+	}
 
 	// If there are no more pending chunks, restore is done.
 	if len(rs.pendingChunks) == 0 {
 		rs.pendingChunks = nil
 		rs.currentCheckpoint = nil
+		rs.skeleton = nil
+		rs.deferredChunks = nil
 		return true, nil
 	}
 
 	return false, nil
 }
 
+// restoreIncrementalChunk restores a chunk of an incremental checkpoint and returns the indices
+// of all chunks that have been restored as a result.
+func (rs *restorer) restoreIncrementalChunk(ctx context.Context, chunk *ChunkMetadata, r io.Reader) ([]uint64, error) {
+	p, err := decodeChunk(ctx, chunk, r)
+	if err != nil {
+		return nil, err
+	}
+	if chunk.Index == 0 {
+		return rs.restoreSkeleton(ctx, chunk, p)
+	}
+
+	rs.Lock()
+	sk := rs.skeleton
+	if sk == nil {
+		// Subtree chunks can only be verified against the skeleton, so defer restoring them until
+		// the skeleton has been restored.
+		if rs.deferredChunks != nil {
+			rs.deferredChunks[chunk.Index] = p
+		}
+		rs.Unlock()
+		return nil, nil
+	}
+	rs.Unlock()
+
+	if err = restoreSubtreeChunk(ctx, rs.ndb, chunk.Root, sk, chunk.Index, p); err != nil {
+		return nil, err
+	}
+	return []uint64{chunk.Index}, nil
+}
+
+// restoreSkeleton restores the skeleton chunk of an incremental checkpoint together with any
+// subtree chunks that have been deferred.
+func (rs *restorer) restoreSkeleton(ctx context.Context, chunk *ChunkMetadata, p *syncer.Proof) ([]uint64, error) {
+	sk, err := verifySkeleton(ctx, chunk.Root.Hash, p)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.Lock()
+	cp := rs.currentCheckpoint
+	rs.Unlock()
+	if cp == nil {
+		return nil, ErrNoRestoreInProgress
+	}
+	if len(sk.subtrees) != len(cp.Chunks)-1 {
+		return nil, fmt.Errorf("%w: skeleton references %d subtrees, manifest has %d chunks",
+			ErrChunkProofVerificationFailed,
+			len(sk.subtrees),
+			len(cp.Chunks),
+		)
+	}
+
+	// Import the skeleton unless the whole tree is contained in a single subtree chunk.
+	if sk.root == nil || sk.root.Node != nil {
+		if err = importChunk(ctx, rs.ndb, chunk.Root, sk.root); err != nil {
+			return nil, err
+		}
+	}
+
+	rs.Lock()
+	rs.skeleton = sk
+	deferred := rs.deferredChunks
+	rs.deferredChunks = make(map[uint64]*syncer.Proof)
+	rs.Unlock()
+
+	restored := []uint64{chunk.Index}
+	for idx, dp := range deferred {
+		if err = restoreSubtreeChunk(ctx, rs.ndb, chunk.Root, sk, idx, dp); err != nil {
+			return nil, err
+		}
+		restored = append(restored, idx)
+	}
+	return restored, nil
+}
+
 // NewRestorer creates a new checkpoint restorer.
 func NewRestorer(ndb db.NodeDB) (Restorer, error) {
 	return &restorer{ndb: ndb}, nil
@@ -189,12 +189,25 @@ type NodeDB interface {
 	Close()
 }
 
+// NodeRepairer is an optional interface implemented by node databases that support restoring
+// nodes which went missing from roots that have already been finalized.
+type NodeRepairer interface {
+	// RepairNode persists the given node as part of an existing root.
+	//
+	// The caller is responsible for ensuring that the node has been verified to be part of the
+	// given root.
+	RepairNode(root node.Root, n node.Node) error
+}
+
 // Batch is a NodeDB-specific batch implementation.
 type Batch interface {
 	// PutNode persists a node in the NodeDB.
 	PutNode(ptr *node.Pointer) error
 
 	// PutWriteLog stores the specified write log into the batch.
+	//
+	// Implementations must not rely on the values of inserted entries as those may be omitted
+	// by callers; the inserted leaf nodes referenced by the annotations should be used instead.
 	PutWriteLog(writeLog writelog.WriteLog, logAnnotations writelog.Annotations) error
 
 	// RemoveNodes marks nodes for eventual garbage collection.
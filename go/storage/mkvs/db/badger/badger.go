@@ -321,6 +321,52 @@ func (d *badgerNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, er
 	return n, nil
 }
 
+// Implements api.NodeRepairer.
+func (d *badgerNodeDB) RepairNode(root node.Root, n node.Node) error {
+	if d.readOnly {
+		return api.ErrReadOnly
+	}
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return err
+	}
+
+	data, err := n.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("mkvs/badger: failed to marshal node: %w", err)
+	}
+	h := n.GetHash()
+
+	d.metaUpdateLock.Lock()
+	defer d.metaUpdateLock.Unlock()
+
+	if d.multipartVersion != multipartVersionNone {
+		return api.ErrMultipartInProgress
+	}
+	if root.Version < d.meta.getEarliestVersion() {
+		return api.ErrVersionNotFound
+	}
+
+	tx := d.db.NewTransactionAt(versionToTs(root.Version), false)
+	defer tx.Discard()
+
+	if err = d.checkRoot(tx, root); err != nil {
+		return err
+	}
+
+	// Nodes are keyed by their hash, so the repaired node is stored under the root's version
+	// which makes it visible to this and all later versions.
+	batch := d.db.NewWriteBatchAt(versionToTs(root.Version))
+	defer batch.Cancel()
+
+	if err = batch.Set(nodeKeyFmt.Encode(&h), data); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to set node: %w", err)
+	}
+	if err = batch.Flush(); err != nil {
+		return fmt.Errorf("mkvs/badger: failed to flush batch: %w", err)
+	}
+	return nil
+}
+
 func (d *badgerNodeDB) GetWriteLog(ctx context.Context, startRoot, endRoot node.Root) (writelog.Iterator, error) {
 	if d.discardWriteLogs {
 		return nil, api.ErrWriteLogNotFound
@@ -379,3 +379,52 @@ func TestFinalizeBasic(t *testing.T) {
 	err = ndb.Finalize([]node.Root{root2})
 	require.Errorf(err, "mkvs: root not found", "Finalize({root2-broken})")
 }
+
+func TestRepairNode(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	ndb, err := New(dbCfg)
+	require.NoError(err, "New()")
+	defer ndb.Close()
+	badgerdb := ndb.(*badgerNodeDB)
+
+	root := fillDB(ctx, require, testValues, nil, 1, 2, ndb)
+	err = ndb.Finalize([]node.Root{root})
+	require.NoError(err, "Finalize({root})")
+
+	var nodes []node.Node
+	err = api.Visit(ctx, ndb, root, func(_ context.Context, n node.Node) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+	require.NoError(err, "Visit()")
+
+	// Corrupt the database by removing a node from the finalized root.
+	lost := nodes[len(nodes)-1]
+	h := lost.GetHash()
+	batch := badgerdb.db.NewWriteBatchAt(versionToTs(root.Version))
+	require.NoError(batch.Delete(nodeKeyFmt.Encode(&h)), "Delete()")
+	require.NoError(batch.Flush(), "Flush()")
+
+	ptr := &node.Pointer{Clean: true, Hash: h}
+	_, err = ndb.GetNode(root, ptr)
+	require.ErrorIs(err, api.ErrNodeNotFound, "GetNode() should fail for a removed node")
+	err = api.Visit(ctx, ndb, root, func(context.Context, node.Node) bool { return true })
+	require.ErrorIs(err, api.ErrNodeNotFound, "Visit() should fail for a corrupted root")
+
+	// Repairing nodes of unknown roots should fail.
+	bogusRoot := root
+	bogusRoot.Hash[3]++
+	err = badgerdb.RepairNode(bogusRoot, lost)
+	require.ErrorIs(err, api.ErrRootNotFound, "RepairNode() with an unknown root")
+
+	err = badgerdb.RepairNode(root, lost)
+	require.NoError(err, "RepairNode()")
+
+	n, err := ndb.GetNode(root, ptr)
+	require.NoError(err, "GetNode() should succeed for a repaired node")
+	require.True(lost.Equal(n), "repaired node should be equal to the removed node")
+	err = api.Visit(ctx, ndb, root, func(context.Context, node.Node) bool { return true })
+	require.NoError(err, "Visit() should succeed for a repaired root")
+}
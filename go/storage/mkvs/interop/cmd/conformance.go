@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/interop/conformance"
+)
+
+const (
+	cfgConformanceOutput = "output"
+	cfgConformanceScript = "script"
+)
+
+var (
+	conformanceGenerateFlags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	conformanceCmd = &cobra.Command{
+		Use:   "conformance",
+		Short: "MKVS conformance test vectors",
+	}
+
+	conformanceGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "generate conformance test vectors from scripts",
+		RunE:  doConformanceGenerate,
+	}
+
+	conformanceCheckCmd = &cobra.Command{
+		Use:   "check <vector>...",
+		Short: "check the MKVS implementation against conformance test vectors",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  doConformanceCheck,
+	}
+)
+
+func doConformanceGenerate(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	scripts := conformance.Scripts()
+	if path := viper.GetString(cfgConformanceScript); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read script: %w", err)
+		}
+		script := conformance.Script{
+			ProofVersion: conformance.DefaultProofVersion,
+		}
+		if err = json.Unmarshal(data, &script); err != nil {
+			return fmt.Errorf("failed to parse script: %w", err)
+		}
+		scripts = []*conformance.Script{&script}
+	}
+
+	outDir := viper.GetString(cfgConformanceOutput)
+	for _, script := range scripts {
+		vector, err := conformance.Generate(context.Background(), script)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(outDir, script.Name+conformance.FileExtension)
+		if err = conformance.SaveVector(path, vector); err != nil {
+			return err
+		}
+		fmt.Printf("Generated %s\n", path)
+	}
+	return nil
+}
+
+func doConformanceCheck(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	for _, path := range args {
+		vector, err := conformance.LoadVector(path)
+		if err != nil {
+			return err
+		}
+		if err = conformance.Check(context.Background(), vector); err != nil {
+			return err
+		}
+		fmt.Printf("Checked %s\n", path)
+	}
+	return nil
+}
+
+// RegisterConformance registers the conformance sub-command and all of it's children.
+func RegisterConformance(parentCmd *cobra.Command) {
+	conformanceGenerateCmd.Flags().AddFlagSet(conformanceGenerateFlags)
+
+	conformanceCmd.AddCommand(conformanceGenerateCmd)
+	conformanceCmd.AddCommand(conformanceCheckCmd)
+	parentCmd.AddCommand(conformanceCmd)
+}
+
+func init() {
+	conformanceGenerateFlags.String(cfgConformanceOutput, ".", "output directory for generated test vectors")
+	conformanceGenerateFlags.String(cfgConformanceScript, "", "JSON script to generate the test vector from (default: built-in scripts)")
+	_ = viper.BindPFlags(conformanceGenerateFlags)
+}
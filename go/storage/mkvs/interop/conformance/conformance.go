@@ -0,0 +1,281 @@
+// Package conformance implements MKVS conformance test vectors.
+//
+// A conformance test vector is generated from a script (a list of tree operations) and records
+// the expected root hash after each step together with the expected proofs for a set of keys.
+// The vectors are serialized using CBOR so that other MKVS implementations can verify that they
+// agree with the Go implementation.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+)
+
+const (
+	// OpInsert is the tree insert operation name.
+	OpInsert = "Insert"
+	// OpRemove is the tree remove operation name.
+	OpRemove = "Remove"
+
+	// DefaultProofVersion is the default proof version used for generated proofs. It is the
+	// latest proof version supported by all MKVS implementations.
+	DefaultProofVersion = 1
+
+	// FileExtension is the file extension of serialized conformance test vectors.
+	FileExtension = ".cbor"
+)
+
+// Op is a tree operation.
+type Op struct {
+	// Op is the operation name.
+	Op string `json:"op"`
+	// Key is the key that is inserted or removed.
+	Key []byte `json:"key"`
+	// Value is the value that is inserted. An absent value is the same as an empty value.
+	Value []byte `json:"value,omitempty"`
+}
+
+// Script is a conformance test script from which a test vector can be generated.
+type Script struct {
+	// Name is the name of the script.
+	Name string `json:"name"`
+	// ProofVersion is the proof version used for generated proofs.
+	ProofVersion uint16 `json:"proof_version"`
+	// Steps are the script steps.
+	Steps []*ScriptStep `json:"steps"`
+}
+
+// ScriptStep is a conformance test script step.
+type ScriptStep struct {
+	// Ops are the operations that are applied to the tree before it is committed.
+	Ops []*Op `json:"ops"`
+	// Prove are the keys for which proofs are generated after the tree is committed.
+	Prove [][]byte `json:"prove,omitempty"`
+}
+
+// Vector is a conformance test vector.
+type Vector struct {
+	// Name is the name of the script the vector was generated from.
+	Name string `json:"name"`
+	// ProofVersion is the proof version of all expected proofs.
+	ProofVersion uint16 `json:"proof_version"`
+	// Steps are the test vector steps.
+	Steps []*Step `json:"steps"`
+}
+
+// Step is a conformance test vector step.
+type Step struct {
+	// Ops are the operations that are applied to the tree before it is committed.
+	Ops []*Op `json:"ops"`
+	// Root is the expected root hash after the tree is committed.
+	Root hash.Hash `json:"root"`
+	// Proofs are the expected proofs against the committed root.
+	Proofs []*Proof `json:"proofs,omitempty"`
+}
+
+// Proof is an expected proof for a key.
+type Proof struct {
+	// Key is the looked up key.
+	Key []byte `json:"key"`
+	// Exists is true iff the key exists in the tree.
+	Exists bool `json:"exists"`
+	// Value is the expected value of the key.
+	Value []byte `json:"value,omitempty"`
+	// Proof is the expected proof for the lookup of the key.
+	Proof syncer.Proof `json:"proof"`
+}
+
+// Generate generates a conformance test vector from the given script.
+func Generate(ctx context.Context, script *Script) (*Vector, error) {
+	vector := &Vector{
+		Name:         script.Name,
+		ProofVersion: script.ProofVersion,
+	}
+	err := run(ctx, script.ProofVersion, script.Steps, func(_ int, ss *ScriptStep, tree mkvs.Tree, root node.Root) error {
+		step := &Step{
+			Ops:  ss.Ops,
+			Root: root.Hash,
+		}
+		for _, key := range ss.Prove {
+			proof, err := prove(ctx, tree, root, key, script.ProofVersion)
+			if err != nil {
+				return err
+			}
+			step.Proofs = append(step.Proofs, proof)
+		}
+		vector.Steps = append(vector.Steps, step)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("conformance: %s: %w", script.Name, err)
+	}
+	return vector, nil
+}
+
+// Check verifies the current MKVS implementation against the given conformance test vector.
+func Check(ctx context.Context, vector *Vector) error {
+	steps := make([]*ScriptStep, 0, len(vector.Steps))
+	for _, step := range vector.Steps {
+		ss := &ScriptStep{Ops: step.Ops}
+		for _, p := range step.Proofs {
+			ss.Prove = append(ss.Prove, p.Key)
+		}
+		steps = append(steps, ss)
+	}
+
+	err := run(ctx, vector.ProofVersion, steps, func(idx int, _ *ScriptStep, tree mkvs.Tree, root node.Root) error {
+		step := vector.Steps[idx]
+		if !root.Hash.Equal(&step.Root) {
+			return fmt.Errorf("step %d: root hash mismatch (expected: %s got: %s)", idx, step.Root, root.Hash)
+		}
+
+		for _, expected := range step.Proofs {
+			// Make sure that the expected proof is valid and proves the expected value.
+			if err := verifyProof(ctx, root, expected); err != nil {
+				return fmt.Errorf("step %d: key %X: %w", idx, expected.Key, err)
+			}
+
+			proof, err := prove(ctx, tree, root, expected.Key, vector.ProofVersion)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(cbor.Marshal(proof), cbor.Marshal(expected)) {
+				return fmt.Errorf("step %d: key %X: proof mismatch", idx, expected.Key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("conformance: %s: %w", vector.Name, err)
+	}
+	return nil
+}
+
+// LoadVector loads a serialized conformance test vector from the given file.
+func LoadVector(path string) (*Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("conformance: failed to read test vector: %w", err)
+	}
+
+	var vector Vector
+	if err = cbor.UnmarshalTrusted(data, &vector); err != nil {
+		return nil, fmt.Errorf("conformance: failed to decode test vector: %w", err)
+	}
+	return &vector, nil
+}
+
+// SaveVector serializes the conformance test vector into the given file.
+func SaveVector(path string, vector *Vector) error {
+	if err := os.WriteFile(path, cbor.Marshal(vector), 0o644); err != nil { // nolint: gosec
+		return fmt.Errorf("conformance: failed to write test vector: %w", err)
+	}
+	return nil
+}
+
+func run(
+	ctx context.Context,
+	proofVersion uint16,
+	steps []*ScriptStep,
+	fn func(int, *ScriptStep, mkvs.Tree, node.Root) error,
+) error {
+	if proofVersion > syncer.LatestProofVersion {
+		return fmt.Errorf("unsupported proof version: %d", proofVersion)
+	}
+
+	tree := mkvs.New(nil, nil, node.RootTypeState)
+	defer tree.Close()
+
+	for idx, step := range steps {
+		for _, op := range step.Ops {
+			var err error
+			switch op.Op {
+			case OpInsert:
+				err = tree.Insert(ctx, op.Key, op.Value)
+			case OpRemove:
+				err = tree.Remove(ctx, op.Key)
+			default:
+				err = fmt.Errorf("unsupported operation: %s", op.Op)
+			}
+			if err != nil {
+				return fmt.Errorf("step %d: %w", idx, err)
+			}
+		}
+
+		version := uint64(idx)
+		_, rootHash, err := tree.Commit(ctx, common.Namespace{}, version)
+		if err != nil {
+			return fmt.Errorf("step %d: failed to commit: %w", idx, err)
+		}
+		root := node.Root{
+			Version: version,
+			Type:    node.RootTypeState,
+			Hash:    rootHash,
+		}
+
+		if err = fn(idx, step, tree, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prove(ctx context.Context, tree mkvs.Tree, root node.Root, key []byte, proofVersion uint16) (*Proof, error) {
+	rsp, err := tree.SyncGet(ctx, &syncer.GetRequest{
+		Tree: syncer.TreeID{
+			Root:     root,
+			Position: root.Hash,
+		},
+		Key:          key,
+		ProofVersion: proofVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate proof for key %X: %w", key, err)
+	}
+	value, err := tree.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key %X: %w", key, err)
+	}
+
+	return &Proof{
+		Key:    key,
+		Exists: value != nil,
+		Value:  value,
+		Proof:  rsp.Proof,
+	}, nil
+}
+
+func verifyProof(ctx context.Context, root node.Root, expected *Proof) error {
+	var pv syncer.ProofVerifier
+	wl, err := pv.VerifyProofToWriteLog(ctx, root.Hash, &expected.Proof)
+	if err != nil {
+		return fmt.Errorf("invalid proof: %w", err)
+	}
+
+	var (
+		value  []byte
+		exists bool
+	)
+	for _, entry := range wl {
+		if bytes.Equal(entry.Key, expected.Key) {
+			value, exists = entry.Value, true
+			break
+		}
+	}
+	if exists != expected.Exists {
+		return fmt.Errorf("proof existence mismatch (expected: %t got: %t)", expected.Exists, exists)
+	}
+	if !bytes.Equal(value, expected.Value) {
+		return fmt.Errorf("proof value mismatch")
+	}
+	return nil
+}
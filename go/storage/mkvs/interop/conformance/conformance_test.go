@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// testVectorsDir is the directory containing the committed conformance test vectors.
+const testVectorsDir = "testdata"
+
+func TestConformance(t *testing.T) {
+	ctx := context.Background()
+
+	for _, script := range Scripts() {
+		t.Run(script.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			vector, err := LoadVector(filepath.Join(testVectorsDir, script.Name+FileExtension))
+			require.NoError(err, "LoadVector")
+
+			err = Check(ctx, vector)
+			require.NoError(err, "Check")
+
+			// The committed test vector should match the one generated from the script.
+			generated, err := Generate(ctx, script)
+			require.NoError(err, "Generate")
+			require.Equal(cbor.Marshal(vector), cbor.Marshal(generated), "committed test vector should be up to date")
+		})
+	}
+}
+
+func TestConformanceMismatch(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	vector, err := Generate(ctx, emptyValuesScript())
+	require.NoError(err, "Generate")
+	require.NoError(Check(ctx, vector), "Check")
+
+	// Root hash mismatch.
+	tampered := cbor.Marshal(vector)
+	var bad Vector
+	require.NoError(cbor.Unmarshal(tampered, &bad), "Unmarshal")
+	bad.Steps[0].Root[0] ^= 0xff
+	require.ErrorContains(Check(ctx, &bad), "root hash mismatch", "Check should fail on bad root")
+
+	// Empty value proven as absent.
+	require.NoError(cbor.Unmarshal(tampered, &bad), "Unmarshal")
+	bad.Steps[0].Proofs[0].Exists = false
+	require.ErrorContains(Check(ctx, &bad), "existence mismatch", "Check should fail on bad existence")
+
+	// Proof for a different key.
+	require.NoError(cbor.Unmarshal(tampered, &bad), "Unmarshal")
+	bad.Steps[0].Proofs[0].Proof = bad.Steps[0].Proofs[2].Proof
+	require.Error(Check(ctx, &bad), "Check should fail on bad proof")
+
+	// Unsupported operation.
+	require.NoError(cbor.Unmarshal(tampered, &bad), "Unmarshal")
+	bad.Steps[0].Ops[0].Op = "Get"
+	require.ErrorContains(Check(ctx, &bad), "unsupported operation", "Check should fail on bad operation")
+}
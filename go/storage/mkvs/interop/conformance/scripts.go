@@ -0,0 +1,161 @@
+package conformance
+
+import (
+	"bytes"
+
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// maxKeyLength is the length (in bytes) of the longest key whose bit length fits into the
+// maximum tree depth.
+const maxKeyLength = int(^node.Depth(0)) / 8
+
+// Scripts returns the built-in conformance test scripts.
+func Scripts() []*Script {
+	return []*Script{
+		emptyValuesScript(),
+		prefixBoundariesScript(),
+		maxDepthScript(),
+	}
+}
+
+// emptyValuesScript covers the difference between keys with empty values and absent keys.
+func emptyValuesScript() *Script {
+	return &Script{
+		Name:         "empty_values",
+		ProofVersion: DefaultProofVersion,
+		Steps: []*ScriptStep{
+			{
+				Ops: []*Op{
+					{Op: OpInsert, Key: []byte("empty")},
+					{Op: OpInsert, Key: []byte("empty value"), Value: []byte{}},
+					{Op: OpInsert, Key: []byte("non-empty"), Value: []byte("value")},
+				},
+				Prove: [][]byte{[]byte("empty"), []byte("empty value"), []byte("non-empty"), []byte("absent")},
+			},
+			{
+				Ops: []*Op{
+					{Op: OpInsert, Key: []byte("non-empty")},
+					{Op: OpRemove, Key: []byte("empty")},
+					{Op: OpRemove, Key: []byte("absent")},
+				},
+				Prove: [][]byte{[]byte("empty"), []byte("empty value"), []byte("non-empty"), []byte("absent")},
+			},
+			{
+				Ops: []*Op{
+					{Op: OpRemove, Key: []byte("empty value")},
+					{Op: OpRemove, Key: []byte("non-empty")},
+				},
+				Prove: [][]byte{[]byte("empty value"), []byte("non-empty")},
+			},
+		},
+	}
+}
+
+// prefixBoundariesScript covers keys that are prefixes of other keys, where the shorter key ends
+// exactly at an internal node boundary, both at byte and at bit granularity.
+func prefixBoundariesScript() *Script {
+	keys := [][]byte{
+		{},
+		{0x00},
+		{0x00, 0x00},
+		{0x00, 0x80},
+		{0x00, 0x00, 0x00},
+		{0x80},
+		{0xff},
+		{0xff, 0xff},
+		[]byte("a"),
+		[]byte("ab"),
+		[]byte("abc"),
+		[]byte("abd"),
+	}
+	absent := [][]byte{
+		{0x00, 0x00, 0x00, 0x00},
+		{0x00, 0x40},
+		{0x40},
+		[]byte("abcd"),
+		[]byte("b"),
+	}
+
+	var inserts []*Op
+	for i, key := range keys {
+		inserts = append(inserts, &Op{Op: OpInsert, Key: key, Value: []byte{byte(i)}})
+	}
+
+	return &Script{
+		Name:         "prefix_boundaries",
+		ProofVersion: DefaultProofVersion,
+		Steps: []*ScriptStep{
+			{
+				Ops:   inserts,
+				Prove: append(append([][]byte{}, keys...), absent...),
+			},
+			{
+				// Removing the prefix keys leaves internal nodes without leaves which must be
+				// collapsed.
+				Ops: []*Op{
+					{Op: OpRemove, Key: []byte{0x00}},
+					{Op: OpRemove, Key: []byte{0x00, 0x00}},
+					{Op: OpRemove, Key: []byte("ab")},
+					{Op: OpRemove, Key: []byte{}},
+				},
+				Prove: [][]byte{
+					{},
+					{0x00},
+					{0x00, 0x00},
+					{0x00, 0x00, 0x00},
+					{0x00, 0x80},
+					[]byte("a"),
+					[]byte("ab"),
+					[]byte("abc"),
+				},
+			},
+			{
+				// Removing the longer keys leaves the prefix key as the only leaf in the subtree.
+				Ops: []*Op{
+					{Op: OpRemove, Key: []byte("abc")},
+					{Op: OpRemove, Key: []byte("abd")},
+					{Op: OpRemove, Key: []byte{0xff, 0xff}},
+				},
+				Prove: [][]byte{
+					[]byte("a"),
+					[]byte("abc"),
+					{0xff},
+					{0xff, 0xff},
+				},
+			},
+		},
+	}
+}
+
+// maxDepthScript covers paths of maximum depth, where keys of maximum length only differ in
+// their last bit.
+func maxDepthScript() *Script {
+	left := bytes.Repeat([]byte{0xaa}, maxKeyLength)
+	right := bytes.Clone(left)
+	right[maxKeyLength-1] ^= 0x01
+	prefix := left[:maxKeyLength-1]
+	absent := bytes.Clone(left)
+	absent[maxKeyLength-1] ^= 0x80
+
+	return &Script{
+		Name:         "max_depth",
+		ProofVersion: DefaultProofVersion,
+		Steps: []*ScriptStep{
+			{
+				Ops: []*Op{
+					{Op: OpInsert, Key: left, Value: []byte("left")},
+					{Op: OpInsert, Key: right, Value: []byte("right")},
+					{Op: OpInsert, Key: prefix, Value: []byte("prefix")},
+				},
+				Prove: [][]byte{left, prefix, absent},
+			},
+			{
+				Ops: []*Op{
+					{Op: OpRemove, Key: right},
+				},
+				Prove: [][]byte{right},
+			},
+		},
+	}
+}
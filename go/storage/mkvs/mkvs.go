@@ -20,6 +20,10 @@ var (
 	// ErrKnownRootMismatch is the error returned by CommitKnown when the known
 	// root mismatches.
 	ErrKnownRootMismatch = errors.New("mkvs: known root mismatch")
+
+	// ErrWriteLogNotSorted is the error returned by StreamApplyWriteLog when the
+	// write log entries are not strictly sorted by key.
+	ErrWriteLogNotSorted = errors.New("mkvs: write log not sorted")
 )
 
 // ImmutableKeyValueTree is the immutable key-value store tree interface.
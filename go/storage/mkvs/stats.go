@@ -0,0 +1,203 @@
+package mkvs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"golang.org/x/time/rate"
+
+	db "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// SubtreeStats are aggregate statistics of a (part of a) tree.
+type SubtreeStats struct {
+	// Nodes is the number of nodes (internal and leaf).
+	Nodes uint64 `json:"nodes"`
+	// Keys is the number of keys (leaf nodes).
+	Keys uint64 `json:"keys"`
+	// Size is the total size of all nodes in bytes.
+	Size uint64 `json:"size"`
+	// ValueSize is the total size of all values in bytes.
+	ValueSize uint64 `json:"value_size"`
+}
+
+func (s *SubtreeStats) addInternal(n *node.InternalNode) {
+	s.Nodes++
+	s.Size += node.InternalNodeSize + uint64(len(n.Label))
+}
+
+func (s *SubtreeStats) addLeaf(n *node.LeafNode) {
+	s.Nodes++
+	s.Keys++
+	s.Size += n.Size()
+	s.ValueSize += uint64(len(n.Value))
+}
+
+// PrefixStats are aggregate statistics of all nodes sharing a key prefix.
+type PrefixStats struct {
+	SubtreeStats
+
+	// Prefix is the key prefix.
+	Prefix node.Key `json:"prefix"`
+	// PrefixBitLength is the length of the key prefix in bits.
+	PrefixBitLength node.Depth `json:"prefix_bit_length"`
+}
+
+// TreeStats are aggregate statistics of a tree.
+type TreeStats struct {
+	// Root is the root of the tree.
+	Root node.Root `json:"root"`
+	// MaxDepth is the bit depth at which nodes were grouped by prefix.
+	MaxDepth node.Depth `json:"max_depth"`
+	// Total are the statistics of the whole tree.
+	Total SubtreeStats `json:"total"`
+	// Prefixes are the statistics grouped by key prefixes of MaxDepth bits, sorted by prefix.
+	//
+	// Internal nodes with a path shorter than MaxDepth are only accounted for in Total. Keys
+	// shorter than MaxDepth are grouped under the key itself.
+	Prefixes []*PrefixStats `json:"prefixes"`
+}
+
+// StatsOption is a configuration option for Stats.
+type StatsOption func(w *statsWalker)
+
+// StatsRateLimit limits the rate at which nodes are fetched from the node database while
+// collecting statistics.
+func StatsRateLimit(nodesPerSecond float64, burst int) StatsOption {
+	return func(w *statsWalker) {
+		w.limiter = rate.NewLimiter(rate.Limit(nodesPerSecond), burst)
+	}
+}
+
+type prefixKey struct {
+	prefix    string
+	bitLength node.Depth
+}
+
+type statsWalker struct {
+	ndb      db.NodeDB
+	root     node.Root
+	maxDepth node.Depth
+	limiter  *rate.Limiter
+
+	stats    *TreeStats
+	prefixes map[prefixKey]*PrefixStats
+}
+
+// Stats walks the whole tree with the given root and returns aggregate node counts, key counts
+// and sizes, grouped by key prefixes of maxDepth bits.
+//
+// The walk may touch a large fraction of the node database. It can be cancelled via the passed
+// context and should be rate limited via StatsRateLimit when used on a live node.
+func Stats(ctx context.Context, ndb db.NodeDB, root node.Root, maxDepth node.Depth, options ...StatsOption) (*TreeStats, error) {
+	w := &statsWalker{
+		ndb:      ndb,
+		root:     root,
+		maxDepth: maxDepth,
+		stats: &TreeStats{
+			Root:     root,
+			MaxDepth: maxDepth,
+			Prefixes: []*PrefixStats{},
+		},
+		prefixes: make(map[prefixKey]*PrefixStats),
+	}
+	for _, o := range options {
+		o(w)
+	}
+
+	if !root.Hash.IsEmpty() {
+		if err := w.visit(ctx, &node.Pointer{Clean: true, Hash: root.Hash}, 0, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ps := range w.prefixes {
+		w.stats.Prefixes = append(w.stats.Prefixes, ps)
+	}
+	slices.SortFunc(w.stats.Prefixes, func(a, b *PrefixStats) int {
+		if c := bytes.Compare(a.Prefix, b.Prefix); c != 0 {
+			return c
+		}
+		return int(a.PrefixBitLength) - int(b.PrefixBitLength)
+	})
+	return w.stats, nil
+}
+
+func (w *statsWalker) visit(ctx context.Context, ptr *node.Pointer, bitDepth node.Depth, path node.Key) error {
+	if ptr == nil || (ptr.Node == nil && ptr.Hash.IsEmpty()) {
+		return nil
+	}
+
+	nd := ptr.Node
+	if nd == nil {
+		if err := w.wait(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		if nd, err = w.ndb.GetNode(w.root, ptr); err != nil {
+			return fmt.Errorf("mkvs: failed to get node %s: %w", ptr.Hash, err)
+		}
+	}
+
+	switch n := nd.(type) {
+	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+		path = path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+		w.stats.Total.addInternal(n)
+		if ps := w.prefix(path, bitLength, false); ps != nil {
+			ps.addInternal(n)
+		}
+
+		if err := w.visit(ctx, n.LeafNode, bitLength, path); err != nil {
+			return err
+		}
+		if err := w.visit(ctx, n.Left, bitLength, path.AppendBit(bitLength, false)); err != nil {
+			return err
+		}
+		return w.visit(ctx, n.Right, bitLength, path.AppendBit(bitLength, true))
+	case *node.LeafNode:
+		w.stats.Total.addLeaf(n)
+		w.prefix(n.Key, n.Key.BitLength(), true).addLeaf(n)
+	}
+	return nil
+}
+
+func (w *statsWalker) wait(ctx context.Context) error {
+	if w.limiter == nil {
+		return ctx.Err()
+	}
+	return w.limiter.Wait(ctx)
+}
+
+// prefix returns the prefix statistics for the given path or nil in case the path of an internal
+// node is shorter than the maximum depth.
+//
+// Keys of leaf nodes are always accounted for, even when shorter.
+func (w *statsWalker) prefix(path node.Key, bitLength node.Depth, leaf bool) *PrefixStats {
+	var prefix node.Key
+	switch {
+	case bitLength >= w.maxDepth:
+		prefix, _ = path.Split(w.maxDepth, bitLength)
+		bitLength = w.maxDepth
+	case leaf:
+		prefix = path
+	default:
+		return nil
+	}
+
+	key := prefixKey{prefix: string(prefix), bitLength: bitLength}
+	ps, ok := w.prefixes[key]
+	if !ok {
+		ps = &PrefixStats{
+			Prefix:          prefix,
+			PrefixBitLength: bitLength,
+		}
+		w.prefixes[key] = ps
+	}
+	return ps
+}
@@ -13,8 +13,12 @@ import (
 const (
 	// MinimumProofVersion is the minimum supported proof version.
 	MinimumProofVersion = 0
-	// LatestProofVersion is the latest supported proof version.
+	// LatestProofVersion is the latest proof version supported by all verifiers, including the
+	// runtime one, and is the version that should be used by default.
 	LatestProofVersion = 1
+	// MaximumProofVersion is the maximum proof version supported by the Go proof builder and
+	// verifier. Versions above LatestProofVersion must only be requested by Go clients.
+	MaximumProofVersion = 2
 )
 
 const (
@@ -40,13 +44,20 @@ type Proof struct {
 	// serialized within the internal node.  The rationale behind this change is to eliminate
 	// the need to serialize all leaf nodes on the path when proving the existence of a
 	// specific value.
+	//
+	// Version 2 change:
+	// Nodes are encoded in post-order traversal and the entries are replaced by a fixed set of
+	// entries: a bitmap of 2-bit codes describing the tree structure, the concatenated distinct
+	// subtree hashes, references to repeated subtree hashes and the concatenated full nodes. This
+	// removes the per-entry overhead which dominates proofs for deep trees.
 	V uint16 `json:"v,omitempty"`
 
 	// UntrustedRoot is the root hash this proof is for. This should only be
 	// used as a quick sanity check and proof verification MUST use an
 	// independently obtained root hash as the prover can provide any root.
 	UntrustedRoot hash.Hash `json:"untrusted_root"`
-	// Entries are the proof entries in pre-order traversal.
+	// Entries are the proof entries in pre-order traversal (or the fixed set of entries in
+	// version 2).
 	Entries [][]byte `json:"entries"`
 }
 
@@ -64,9 +75,9 @@ type ProofBuilder struct {
 	size         uint64
 }
 
-// NewProofBuilder creates a new Merkle proof builder for the given root.
+// NewProofBuilder creates a new version 1 Merkle proof builder for the given root.
 func NewProofBuilder(root, subtree hash.Hash) *ProofBuilder {
-	pb, err := NewProofBuilderForVersion(root, subtree, LatestProofVersion)
+	pb, err := NewProofBuilderForVersion(root, subtree, 1)
 	if err != nil {
 		panic(err)
 	}
@@ -85,7 +96,7 @@ func NewProofBuilderV0(root, subtree hash.Hash) *ProofBuilder {
 // NewProofBuilderForVersion creates a new Merkle proof builder for the given root
 // in a given proof version format.
 func NewProofBuilderForVersion(root, subtree hash.Hash, proofVersion uint16) (*ProofBuilder, error) {
-	if proofVersion < MinimumProofVersion || proofVersion > LatestProofVersion {
+	if proofVersion < MinimumProofVersion || proofVersion > MaximumProofVersion {
 		return nil, fmt.Errorf("%v: %d", ErrUnsupportedProofVersion, proofVersion)
 	}
 	return &ProofBuilder{
@@ -125,7 +136,7 @@ func (b *ProofBuilder) Include(n node.Node) {
 	case 0:
 		// In version 0, the leaf is included in the internal node.
 		pn.serialized, err = n.CompactMarshalBinaryV0()
-	case 1:
+	case 1, 2:
 		// In version 1, the leaf node is added separately, as a child.
 		pn.serialized, err = n.CompactMarshalBinaryV1()
 	default:
@@ -146,7 +157,7 @@ func (b *ProofBuilder) Include(n node.Node) {
 				nd.Left,
 				nd.Right,
 			}
-		case 1:
+		case 1, 2:
 			// In version 1, the leaf node is added separately, as a child.
 			children = []*node.Pointer{
 				nd.LeafNode,
@@ -203,7 +214,14 @@ func (b *ProofBuilder) Build(ctx context.Context) (*Proof, error) {
 		proof.UntrustedRoot = b.root
 	}
 
-	if err := b.build(ctx, &proof, proof.UntrustedRoot); err != nil {
+	var err error
+	switch b.proofVersion {
+	case 2:
+		err = b.buildV2(ctx, &proof, proof.UntrustedRoot)
+	default:
+		err = b.build(ctx, &proof, proof.UntrustedRoot)
+	}
+	if err != nil {
 		return nil, err
 	}
 	return &proof, nil
@@ -289,7 +307,7 @@ func (pv *ProofVerifier) VerifyProofToWriteLog(ctx context.Context, root hash.Ha
 }
 
 func (pv *ProofVerifier) verifyProofOpts(ctx context.Context, root hash.Hash, proof *Proof, opts *verifyOpts) (*verifyResult, error) {
-	if proof.V < MinimumProofVersion || proof.V > LatestProofVersion {
+	if proof.V < MinimumProofVersion || proof.V > MaximumProofVersion {
 		return nil, fmt.Errorf("verifier: unsupported proof version: %d", proof.V)
 	}
 
@@ -305,15 +323,27 @@ func (pv *ProofVerifier) verifyProofOpts(ctx context.Context, root hash.Hash, pr
 		return nil, errors.New("verifier: empty proof")
 	}
 
-	var res verifyResult
-	idx, rootPtr, err := pv.verifyProof(ctx, proof, 0, opts, &res)
-	if err != nil {
-		return nil, err
-	}
-	// Make sure that all of the entries in the proof have been used. The returned index should
-	// point to just beyond the last element.
-	if idx != len(proof.Entries) {
-		return nil, fmt.Errorf("verifier: unused entries in proof")
+	var (
+		res     verifyResult
+		rootPtr *node.Pointer
+	)
+	switch proof.V {
+	case 2:
+		var err error
+		if rootPtr, err = pv.verifyProofV2(ctx, proof, opts, &res); err != nil {
+			return nil, err
+		}
+	default:
+		idx, ptr, err := pv.verifyProof(ctx, proof, 0, opts, &res)
+		if err != nil {
+			return nil, err
+		}
+		// Make sure that all of the entries in the proof have been used. The returned index should
+		// point to just beyond the last element.
+		if idx != len(proof.Entries) {
+			return nil, fmt.Errorf("verifier: unused entries in proof")
+		}
+		rootPtr = ptr
 	}
 	rootNodeHash := rootPtr.GetHash()
 	if rootNodeHash.IsEmpty() {
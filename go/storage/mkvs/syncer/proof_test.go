@@ -9,6 +9,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
 func TestProofExtraNodes(t *testing.T) {
@@ -63,6 +64,8 @@ func FuzzProof(f *testing.F) {
 	f.Add(rawProofV0)
 	rawProofV1, _ := base64.StdEncoding.DecodeString("o2F2AWdlbnRyaWVzh0oBASQAa2V5IDAC9lghAibniky28BTAIiYrb3z9/rTq7r91woTo2EqR91Pf16P9RgEBAwCAAvZYIQIwwW7eyXCi2yXyFCzFD9U+Ssy1gwSwiskBQfk+9KCUA1QBAAUAa2V5IDkHAAAAdmFsdWUgOW51bnRydXN0ZWRfcm9vdFggWeZ8L9wIuOEN0Iu2uO/mFPzJZey4liX5fxf4fwcQRhM=")
 	f.Add(rawProofV1)
+	rawProofV2, _ := base64.StdEncoding.DecodeString("o2F2AmdlbnRyaWVzhEMHEfxYQBQ6RgqFtADx+B6VKE0CVRrfDHmwgZwU3ewsj4gswWv+MMFu3slwotsl8hQsxQ/VPkrMtYMEsIrJAUH5PvSglAP2WCEABQBrZXkgOQcAAAB2YWx1ZSA5AQMAgAIBJABrZXkgMAJudW50cnVzdGVkX3Jvb3RYIKlAud7XYhorEEl8hG9G3Hd4OXl5VR1xvuLAepMZ5qpF")
+	f.Add(rawProofV2)
 
 	// Fuzzing.
 	f.Fuzz(func(_ *testing.T, data []byte) {
@@ -76,3 +79,116 @@ func FuzzProof(f *testing.F) {
 		_, _ = verifier.VerifyProof(context.Background(), proof.UntrustedRoot, &proof)
 	})
 }
+
+// proofTreeGenerator generates random trees for proof tests from fuzzer input.
+type proofTreeGenerator struct {
+	data []byte
+}
+
+func (g *proofTreeGenerator) byte() byte {
+	if len(g.data) == 0 {
+		return 0
+	}
+	b := g.data[0]
+	g.data = g.data[1:]
+	return b
+}
+
+// leaf generates a random leaf node. A small alphabet is used so that repeated subtrees, which
+// are deduplicated in version 2 proofs, are common.
+func (g *proofTreeGenerator) leaf() *node.Pointer {
+	b := g.byte()
+	n := &node.LeafNode{
+		Clean: true,
+		Key:   node.Key{b & 0x03},
+		Value: []byte{(b >> 2) & 0x03},
+	}
+	n.UpdateHash()
+	return &node.Pointer{Clean: true, Node: n, Hash: n.Hash}
+}
+
+// generate generates a random subtree together with the set of nodes included in the proof.
+func (g *proofTreeGenerator) generate(depth int, included *[]node.Node) *node.Pointer {
+	b := g.byte()
+
+	var ptr *node.Pointer
+	switch {
+	case depth == 0 || b&0x01 == 0:
+		ptr = g.leaf()
+	default:
+		n := &node.InternalNode{
+			Clean:          true,
+			Label:          node.Key{b},
+			LabelBitLength: node.Depth((b>>5)&0x07) + 1,
+		}
+		if b&0x02 != 0 {
+			n.LeafNode = g.leaf()
+			if b&0x04 != 0 {
+				*included = append(*included, n.LeafNode.Node)
+			}
+		}
+		n.Left = g.generate(depth-1, included)
+		n.Right = g.generate(depth-1, included)
+		n.UpdateHash()
+		ptr = &node.Pointer{Clean: true, Node: n, Hash: n.Hash}
+	}
+	if b&0x08 == 0 {
+		*included = append(*included, ptr.Node)
+	}
+	return ptr
+}
+
+func FuzzProofVersionsRandomTrees(f *testing.F) {
+	// Seed corpus.
+	f.Add([]byte{0x01})
+	f.Add([]byte{0x03, 0x07, 0x01, 0x00, 0x01, 0x00, 0x00, 0x08, 0x00})
+	f.Add([]byte{0x0b, 0x07, 0x27, 0x41, 0x00, 0x00, 0x01, 0x05, 0x05, 0x0f, 0xff, 0x13, 0x37})
+	f.Add([]byte("key 0key 1key 10key 2"))
+
+	// Fuzzing.
+	f.Fuzz(func(t *testing.T, data []byte) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		gen := proofTreeGenerator{data: data}
+		var included []node.Node
+		root := gen.generate(8, &included)
+		// Always include the root so that the proof is not just a single hash.
+		included = append(included, root.Node)
+
+		var (
+			proofs   [2]*Proof
+			verifier ProofVerifier
+		)
+		for i, proofVersion := range []uint16{1, 2} {
+			pb, err := NewProofBuilderForVersion(root.Hash, root.Hash, proofVersion)
+			require.NoError(err, "NewProofBuilderForVersion")
+			for _, n := range included {
+				pb.Include(n)
+			}
+			proofs[i], err = pb.Build(ctx)
+			require.NoError(err, "Build")
+			require.EqualValues(proofVersion, proofs[i].V)
+		}
+
+		// Both proof versions must verify to the same tree and write log.
+		ptrV1, err := verifier.VerifyProof(ctx, root.Hash, proofs[0])
+		require.NoError(err, "VerifyProof should not fail with a valid version 1 proof")
+		ptrV2, err := verifier.VerifyProof(ctx, root.Hash, proofs[1])
+		require.NoError(err, "VerifyProof should not fail with a valid version 2 proof")
+		require.Equal(ptrV1.GetHash(), ptrV2.GetHash(), "root hashes should be equal for both proof versions")
+
+		wlV1, err := verifier.VerifyProofToWriteLog(ctx, root.Hash, proofs[0])
+		require.NoError(err, "VerifyProofToWriteLog should not fail with a valid version 1 proof")
+		wlV2, err := verifier.VerifyProofToWriteLog(ctx, root.Hash, proofs[1])
+		require.NoError(err, "VerifyProofToWriteLog should not fail with a valid version 2 proof")
+		require.Equal(wlV1, wlV2, "write logs should be equal for both proof versions")
+
+		// A version 2 proof must not verify against a different root.
+		var otherRoot hash.Hash
+		otherRoot.FromBytes(root.Hash[:])
+		proofs[1].UntrustedRoot = otherRoot
+		_, err = verifier.VerifyProof(ctx, otherRoot, proofs[1])
+		require.Error(err, "VerifyProof should fail for a different root")
+	})
+}
@@ -0,0 +1,246 @@
+package syncer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+// Version 2 proofs always consist of the following entries.
+const (
+	// proofV2EntryStructure is the entry containing the number of nodes in the proof (as an
+	// uvarint) followed by their 2-bit structure codes in post-order traversal.
+	proofV2EntryStructure = iota
+	// proofV2EntryHashes is the entry containing the concatenated distinct subtree hashes in
+	// the order of their first occurrence.
+	proofV2EntryHashes
+	// proofV2EntryHashRefs is the entry containing the concatenated uvarint indices of repeated
+	// subtree hashes.
+	proofV2EntryHashRefs
+	// proofV2EntryNodes is the entry containing the concatenated compact encodings of full nodes.
+	proofV2EntryNodes
+
+	proofV2NumEntries
+)
+
+// Structure codes of version 2 proofs.
+const (
+	// proofV2CodeNil is the structure code for empty nodes.
+	proofV2CodeNil byte = iota
+	// proofV2CodeHash is the structure code for subtree hashes not seen before.
+	proofV2CodeHash
+	// proofV2CodeHashRef is the structure code for repeated subtree hashes.
+	proofV2CodeHashRef
+	// proofV2CodeFull is the structure code for full nodes. Full internal nodes are preceded by
+	// their leaf, left and right children.
+	proofV2CodeFull
+)
+
+var errMalformedProofV2 = errors.New("verifier: malformed proof")
+
+// proofV2Encoder accumulates the entries of a version 2 proof.
+type proofV2Encoder struct {
+	codes     []byte
+	hashes    []byte
+	hashIndex map[hash.Hash]uint64
+	hashRefs  []byte
+	nodes     []byte
+}
+
+func (e *proofV2Encoder) entries() [][]byte {
+	structure := binary.AppendUvarint(nil, uint64(len(e.codes)))
+	packed := make([]byte, (len(e.codes)+3)/4)
+	for i, code := range e.codes {
+		packed[i/4] |= code << (6 - 2*(i%4))
+	}
+	structure = append(structure, packed...)
+
+	entries := make([][]byte, proofV2NumEntries)
+	entries[proofV2EntryStructure] = structure
+	entries[proofV2EntryHashes] = e.hashes
+	entries[proofV2EntryHashRefs] = e.hashRefs
+	entries[proofV2EntryNodes] = e.nodes
+	return entries
+}
+
+func (b *ProofBuilder) buildV2(ctx context.Context, proof *Proof, h hash.Hash) error {
+	enc := proofV2Encoder{
+		hashIndex: make(map[hash.Hash]uint64),
+	}
+	if err := b.encodeV2(ctx, &enc, h); err != nil {
+		return err
+	}
+	proof.Entries = enc.entries()
+	return nil
+}
+
+func (b *ProofBuilder) encodeV2(ctx context.Context, enc *proofV2Encoder, h hash.Hash) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if h.IsEmpty() {
+		enc.codes = append(enc.codes, proofV2CodeNil)
+		return nil
+	}
+	n := b.included[h]
+	if n == nil {
+		// Node is not included in this proof, just add hash of subtree (only once).
+		if idx, ok := enc.hashIndex[h]; ok {
+			enc.codes = append(enc.codes, proofV2CodeHashRef)
+			enc.hashRefs = binary.AppendUvarint(enc.hashRefs, idx)
+			return nil
+		}
+		enc.hashIndex[h] = uint64(len(enc.hashIndex))
+		enc.codes = append(enc.codes, proofV2CodeHash)
+		enc.hashes = append(enc.hashes, h[:]...)
+		return nil
+	}
+
+	// Post-order traversal, first add any children.
+	for _, childHash := range n.children {
+		if err := b.encodeV2(ctx, enc, childHash); err != nil {
+			return err
+		}
+	}
+
+	// And then add the visited node.
+	enc.codes = append(enc.codes, proofV2CodeFull)
+	enc.nodes = append(enc.nodes, n.serialized...)
+
+	return nil
+}
+
+// decodeProofV2Node decodes a compactly encoded node from the start of the given data and returns
+// the node and the size of its encoding.
+func decodeProofV2Node(data []byte) (node.Node, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errMalformedProofV2
+	}
+
+	switch data[0] {
+	case node.PrefixLeafNode:
+		var leaf node.LeafNode
+		size, err := leaf.SizedUnmarshalBinary(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &leaf, size, nil
+	case node.PrefixInternalNode:
+		// Bound the data to the compact encoding as otherwise the following nodes could be
+		// interpreted as child hashes.
+		var labelBitLength node.Depth
+		if _, err := labelBitLength.UnmarshalBinary(data[1:]); err != nil {
+			return nil, 0, err
+		}
+		size := 1 + node.DepthSize + labelBitLength.ToBytes() + 1
+		if size > len(data) {
+			return nil, 0, errMalformedProofV2
+		}
+
+		var inode node.InternalNode
+		if _, err := inode.SizedUnmarshalBinary(data[:size]); err != nil {
+			return nil, 0, err
+		}
+		return &inode, size, nil
+	default:
+		return nil, 0, errMalformedProofV2
+	}
+}
+
+func (pv *ProofVerifier) verifyProofV2(ctx context.Context, proof *Proof, opts *verifyOpts, res *verifyResult) (*node.Pointer, error) {
+	if len(proof.Entries) != proofV2NumEntries {
+		return nil, errMalformedProofV2
+	}
+
+	structure := proof.Entries[proofV2EntryStructure]
+	count, n := binary.Uvarint(structure)
+	if n <= 0 {
+		return nil, errMalformedProofV2
+	}
+	codes := structure[n:]
+	if count == 0 || uint64(len(codes)) != (count+3)/4 {
+		return nil, errMalformedProofV2
+	}
+	if pad := count % 4; pad != 0 && codes[len(codes)-1]<<(2*pad) != 0 {
+		// Padding must be zero so that the encoding is canonical.
+		return nil, errMalformedProofV2
+	}
+
+	hashes := proof.Entries[proofV2EntryHashes]
+	if len(hashes)%hash.Size != 0 {
+		return nil, errMalformedProofV2
+	}
+	numHashes := len(hashes) / hash.Size
+	hashRefs := proof.Entries[proofV2EntryHashRefs]
+	nodes := proof.Entries[proofV2EntryNodes]
+
+	var (
+		stack    []*node.Pointer
+		nextHash int
+	)
+	hashAt := func(idx int) *node.Pointer {
+		var h hash.Hash
+		copy(h[:], hashes[idx*hash.Size:(idx+1)*hash.Size])
+		return &node.Pointer{Clean: true, Hash: h}
+	}
+	for i := uint64(0); i < count; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		switch code := (codes[i/4] >> (6 - 2*(i%4))) & 0x03; code {
+		case proofV2CodeNil:
+			stack = append(stack, nil)
+		case proofV2CodeHash:
+			if nextHash >= numHashes {
+				return nil, errMalformedProofV2
+			}
+			stack = append(stack, hashAt(nextHash))
+			nextHash++
+		case proofV2CodeHashRef:
+			idx, n := binary.Uvarint(hashRefs)
+			if n <= 0 || idx >= uint64(nextHash) {
+				return nil, errMalformedProofV2
+			}
+			hashRefs = hashRefs[n:]
+			stack = append(stack, hashAt(int(idx)))
+		case proofV2CodeFull:
+			nd, size, err := decodeProofV2Node(nodes)
+			if err != nil {
+				return nil, err
+			}
+			nodes = nodes[size:]
+
+			// For internal nodes, also attach children.
+			if inode, ok := nd.(*node.InternalNode); ok {
+				if len(stack) < 3 {
+					return nil, errMalformedProofV2
+				}
+				children := stack[len(stack)-3:]
+				inode.LeafNode, inode.Left, inode.Right = children[0], children[1], children[2]
+				stack = stack[:len(stack)-3]
+
+				// Recompute hash as hashes were not recomputed for compact encoding.
+				inode.UpdateHash()
+			}
+
+			ptr := &node.Pointer{Clean: true, Hash: nd.GetHash(), Node: nd}
+
+			if opts.writeLog {
+				res.addLeafToWriteLog(ptr)
+			}
+
+			stack = append(stack, ptr)
+		}
+	}
+
+	// Make sure that all of the data in the proof has been used.
+	if len(stack) != 1 || nextHash != numHashes || len(hashRefs) != 0 || len(nodes) != 0 {
+		return nil, errors.New("verifier: unused entries in proof")
+	}
+	return stack[0], nil
+}
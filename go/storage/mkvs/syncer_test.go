@@ -540,3 +540,176 @@ func TestTreeProofs(t *testing.T) {
 		}
 	}
 }
+
+// syncProofVersions performs the given sync operation for proof versions 1 and 2 and makes sure
+// that both proofs verify to the same write log.
+func syncProofVersions(
+	t *testing.T,
+	roothash hash.Hash,
+	sync func(proofVersion uint16) (*syncer.ProofResponse, error),
+) (*syncer.Proof, *syncer.Proof) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	respV1, err := sync(1)
+	require.NoError(err, "sync with proof version 1")
+	respV2, err := sync(2)
+	require.NoError(err, "sync with proof version 2")
+	require.EqualValues(2, respV2.Proof.V, "proof version should be 2")
+
+	var pv syncer.ProofVerifier
+	wlV1, err := pv.VerifyProofToWriteLog(ctx, roothash, &respV1.Proof)
+	require.NoError(err, "VerifyProofToWriteLog should not fail with a valid version 1 proof")
+	wlV2, err := pv.VerifyProofToWriteLog(ctx, roothash, &respV2.Proof)
+	require.NoError(err, "VerifyProofToWriteLog should not fail with a valid version 2 proof")
+	require.Equal(wlV1, wlV2, "write logs should be equal for both proof versions")
+
+	ptrV1, err := pv.VerifyProof(ctx, roothash, &respV1.Proof)
+	require.NoError(err, "VerifyProof should not fail with a valid version 1 proof")
+	ptrV2, err := pv.VerifyProof(ctx, roothash, &respV2.Proof)
+	require.NoError(err, "VerifyProof should not fail with a valid version 2 proof")
+	require.Equal(ptrV1.GetHash(), ptrV2.GetHash(), "root hashes should be equal for both proof versions")
+
+	// Tampering with any of the version 2 entries should cause verification to fail.
+	for i := range respV2.Proof.Entries {
+		if len(respV2.Proof.Entries[i]) == 0 {
+			continue
+		}
+		proof := copyProof(&respV2.Proof)
+		proof.Entries[i] = proof.Entries[i][:len(proof.Entries[i])-1]
+		_, err = pv.VerifyProof(ctx, roothash, proof)
+		require.Error(err, "VerifyProof should fail with a truncated version 2 proof entry %d", i)
+	}
+
+	return &respV1.Proof, &respV2.Proof
+}
+
+func FuzzProofVersions(f *testing.F) {
+	// Seed corpus.
+	f.Add([]byte("key 0key 1key 10key 2"))
+	f.Add([]byte{0x00, 0x01, 0x02, 0x03, 0xff, 0xfe, 0x00, 0x00, 0x80, 0x81, 0x7f})
+
+	// Fuzzing.
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ctx := context.Background()
+		var ns common.Namespace
+
+		// Build a random tree where the data determines both key lengths and contents.
+		tree := New(nil, nil, node.RootTypeState).(*tree)
+		var keys [][]byte
+		for len(data) > 0 {
+			n := 1 + int(data[0])%8
+			data = data[1:]
+			if n > len(data) {
+				n = len(data)
+			}
+			key := append([]byte{}, data[:n]...)
+			data = data[n:]
+
+			value := hash.NewFromBytes(key)
+			if err := tree.Insert(ctx, key, value[:1+len(key)]); err != nil {
+				t.Fatalf("Insert: %v", err)
+			}
+			keys = append(keys, key)
+		}
+		_, roothash, err := tree.Commit(ctx, ns, 0)
+		require.NoError(t, err, "Commit")
+		if len(keys) == 0 {
+			return
+		}
+
+		treeID := syncer.TreeID{
+			Root:     node.Root{Namespace: ns, Version: 0, Hash: roothash, Type: node.RootTypeState},
+			Position: roothash,
+		}
+		for _, key := range keys {
+			for _, includeSiblings := range []bool{false, true} {
+				syncProofVersions(t, roothash, func(proofVersion uint16) (*syncer.ProofResponse, error) {
+					return tree.SyncGet(ctx, &syncer.GetRequest{
+						Tree:            treeID,
+						Key:             key,
+						IncludeSiblings: includeSiblings,
+						ProofVersion:    proofVersion,
+					})
+				})
+			}
+		}
+		syncProofVersions(t, roothash, func(proofVersion uint16) (*syncer.ProofResponse, error) {
+			return tree.SyncIterate(ctx, &syncer.IterateRequest{
+				Tree:         treeID,
+				Key:          keys[0],
+				Prefetch:     10,
+				ProofVersion: proofVersion,
+			})
+		})
+		syncProofVersions(t, roothash, func(proofVersion uint16) (*syncer.ProofResponse, error) {
+			return tree.SyncGetPrefixes(ctx, &syncer.GetPrefixesRequest{
+				Tree:         treeID,
+				Prefixes:     [][]byte{keys[0][:len(keys[0])/2], keys[len(keys)-1]},
+				Limit:        10,
+				ProofVersion: proofVersion,
+			})
+		})
+	})
+}
+
+func TestProofV2Size(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	keys, values := generateKeyValuePairsEx("", 10_000)
+	var ns common.Namespace
+
+	// Use an unbounded cache as there is no node database to fetch evicted nodes from.
+	tree := New(nil, nil, node.RootTypeState, Capacity(0, 0)).(*tree)
+	for i, key := range keys {
+		err := tree.Insert(ctx, key, values[i])
+		require.NoError(err, "Insert")
+	}
+	_, roothash, err := tree.Commit(ctx, ns, 0)
+	require.NoError(err, "Commit")
+
+	treeID := syncer.TreeID{
+		Root:     node.Root{Namespace: ns, Version: 0, Hash: roothash, Type: node.RootTypeState},
+		Position: roothash,
+	}
+	measure := func(name string, sync func(key []byte, proofVersion uint16) (*syncer.ProofResponse, error)) {
+		var sizeV1, sizeV2 int
+		for i := range 100 {
+			key := keys[(i*97)%len(keys)]
+			proofV1, proofV2 := syncProofVersions(t, roothash, func(proofVersion uint16) (*syncer.ProofResponse, error) {
+				return sync(key, proofVersion)
+			})
+			sizeV1 += len(cbor.Marshal(proofV1))
+			sizeV2 += len(cbor.Marshal(proofV2))
+		}
+		require.Less(sizeV2, sizeV1, "version 2 proofs should be smaller (%s)", name)
+		t.Logf("%s: v1 %d bytes, v2 %d bytes (%.1f%% smaller)",
+			name, sizeV1, sizeV2, 100*(1-float64(sizeV2)/float64(sizeV1)),
+		)
+	}
+
+	measure("get", func(key []byte, proofVersion uint16) (*syncer.ProofResponse, error) {
+		return tree.SyncGet(ctx, &syncer.GetRequest{
+			Tree:         treeID,
+			Key:          key,
+			ProofVersion: proofVersion,
+		})
+	})
+	measure("get with siblings", func(key []byte, proofVersion uint16) (*syncer.ProofResponse, error) {
+		return tree.SyncGet(ctx, &syncer.GetRequest{
+			Tree:            treeID,
+			Key:             key,
+			IncludeSiblings: true,
+			ProofVersion:    proofVersion,
+		})
+	})
+	measure("iterate", func(key []byte, proofVersion uint16) (*syncer.ProofResponse, error) {
+		return tree.SyncIterate(ctx, &syncer.IterateRequest{
+			Tree:         treeID,
+			Key:          key,
+			Prefetch:     100,
+			ProofVersion: proofVersion,
+		})
+	})
+}
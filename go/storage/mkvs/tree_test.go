@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -932,6 +935,63 @@ func testDebugDumpLocal(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
 	require.True(t, len(buffer.Bytes()) > 0)
 }
 
+func testStats(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
+	ctx := context.Background()
+	tree := New(nil, ndb, node.RootTypeState)
+
+	keyCounts := map[string]int{"a": 10, "b": 5}
+	for prefix, count := range keyCounts {
+		for i := range count {
+			err := tree.Insert(ctx, []byte(fmt.Sprintf("%s%d", prefix, i)), []byte("value"))
+			require.NoError(t, err, "Insert")
+		}
+	}
+	err := tree.Insert(ctx, []byte{}, []byte("empty key"))
+	require.NoError(t, err, "Insert")
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(t, err, "Commit")
+	root := node.Root{Namespace: testNs, Version: 0, Type: node.RootTypeState, Hash: rootHash}
+
+	stats, err := Stats(ctx, ndb, root, 8, StatsRateLimit(1_000_000, 1))
+	require.NoError(t, err, "Stats")
+	require.EqualValues(t, 16, stats.Total.Keys)
+	require.EqualValues(t, 15*len("value")+len("empty key"), stats.Total.ValueSize)
+	require.Len(t, stats.Prefixes, 3)
+
+	// Keys shorter than the maximum depth are grouped under the key itself.
+	require.Equal(t, node.Key{}, stats.Prefixes[0].Prefix)
+	require.EqualValues(t, 0, stats.Prefixes[0].PrefixBitLength)
+	require.EqualValues(t, 1, stats.Prefixes[0].Keys)
+	require.EqualValues(t, 1, stats.Prefixes[0].Nodes)
+	for i, prefix := range []string{"a", "b"} {
+		ps := stats.Prefixes[i+1]
+		require.Equal(t, node.Key(prefix), ps.Prefix)
+		require.EqualValues(t, 8, ps.PrefixBitLength)
+		require.EqualValues(t, keyCounts[prefix], ps.Keys)
+		require.EqualValues(t, 2*keyCounts[prefix]-1, ps.Nodes, "internal nodes below the prefix should be accounted for")
+	}
+
+	// Without grouping, the only prefix covers the whole tree.
+	stats, err = Stats(ctx, ndb, root, 0)
+	require.NoError(t, err, "Stats")
+	require.Len(t, stats.Prefixes, 1)
+	require.Equal(t, stats.Total, stats.Prefixes[0].SubtreeStats)
+
+	// Empty roots have no statistics.
+	emptyRoot := node.Root{Namespace: testNs, Type: node.RootTypeState}
+	emptyRoot.Hash.Empty()
+	stats, err = Stats(ctx, ndb, emptyRoot, 8)
+	require.NoError(t, err, "Stats")
+	require.Equal(t, SubtreeStats{}, stats.Total)
+	require.Empty(t, stats.Prefixes)
+
+	// Walks can be cancelled.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Stats(cancelledCtx, ndb, root, 8)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
 func testApplyWriteLog(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
 	keys, values := generateKeyValuePairsEx("", 100)
 
@@ -2343,6 +2403,92 @@ func testLargeUpdates(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
 	require.NoError(t, err, "Finalize")
 }
 
+func testStreamApplyWriteLog(t *testing.T, ndb db.NodeDB, _ NodeDBFactory) {
+	// Large write logs are covered by BenchmarkStreamApplyWriteLog.
+	const memoryBudget = 64 * 1024
+
+	keys, values := generateKeyValuePairsEx("", 10_000)
+	newKeys, newValues := generateKeyValuePairsEx("new ", len(keys)/10)
+
+	ctx := context.Background()
+	applyWriteLog := func(root node.Root, writeLog writelog.WriteLog) node.Root {
+		slices.SortFunc(writeLog, func(a, b writelog.LogEntry) int {
+			return bytes.Compare(a.Key, b.Key)
+		})
+
+		tree := NewWithRoot(nil, ndb, root)
+		defer tree.Close()
+		err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog))
+		require.NoError(t, err, "ApplyWriteLog")
+
+		newRoot := root
+		newRoot.Version++
+		_, newRoot.Hash, err = tree.Commit(ctx, root.Namespace, newRoot.Version, NoPersist())
+		require.NoError(t, err, "Commit")
+
+		err = StreamApplyWriteLog(ctx, ndb, root, newRoot, writelog.NewStaticIterator(writeLog), memoryBudget)
+		require.NoError(t, err, "StreamApplyWriteLog")
+		require.True(t, ndb.HasRoot(newRoot), "root should exist after apply")
+		return newRoot
+	}
+
+	// Insert some items first.
+	var writeLog writelog.WriteLog
+	for i := range keys {
+		writeLog = append(writeLog, writelog.LogEntry{Key: keys[i], Value: values[i]})
+	}
+	emptyRoot := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Type:      node.RootTypeState,
+	}
+	emptyRoot.Hash.Empty()
+	root := applyWriteLog(emptyRoot, writeLog)
+	err := ndb.Finalize([]node.Root{root})
+	require.NoError(t, err, "Finalize")
+
+	// Then apply updates, insertions and removals (including removals of missing keys).
+	writeLog = nil
+	for i := range newKeys {
+		switch i % 4 {
+		case 0:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i*10]})
+		case 1:
+			writeLog = append(writeLog, writelog.LogEntry{Key: newKeys[i]})
+		case 2:
+			writeLog = append(writeLog, writelog.LogEntry{Key: keys[i*10], Value: newValues[i]})
+		case 3:
+			writeLog = append(writeLog, writelog.LogEntry{Key: newKeys[i], Value: []byte{}})
+		}
+	}
+	newRoot := applyWriteLog(root, writeLog)
+
+	tree := NewWithRoot(nil, ndb, newRoot)
+	defer tree.Close()
+	for _, entry := range writeLog {
+		value, err := tree.Get(ctx, entry.Key)
+		require.NoError(t, err, "Get")
+		require.EqualValues(t, entry.Value, value, "value should be correct")
+	}
+
+	// The stored write log should not include removals of missing keys.
+	wli, err := ndb.GetWriteLog(ctx, root, newRoot)
+	require.NoError(t, err, "GetWriteLog")
+	require.Len(t, foldWriteLogIterator(t, wli), len(writeLog)-len(writeLog)/4)
+
+	// Unsorted and mismatching write logs should be rejected.
+	slices.Reverse(writeLog)
+	err = StreamApplyWriteLog(ctx, ndb, root, newRoot, writelog.NewStaticIterator(writeLog), memoryBudget)
+	require.ErrorIs(t, err, ErrWriteLogNotSorted)
+
+	sameRoot := newRoot
+	sameRoot.Hash = root.Hash
+	err = StreamApplyWriteLog(ctx, ndb, root, sameRoot, writelog.NewStaticIterator(nil), memoryBudget)
+	require.NoError(t, err, "StreamApplyWriteLog with no changes")
+	err = StreamApplyWriteLog(ctx, ndb, root, newRoot, writelog.NewStaticIterator(nil), memoryBudget)
+	require.ErrorIs(t, err, ErrKnownRootMismatch)
+}
+
 func testBackend(
 	t *testing.T,
 	initBackend func(t *testing.T) (NodeDBFactory, func()),
@@ -2359,6 +2505,7 @@ func testBackend(
 		{"InsertCommitEach", testInsertCommitEach},
 		{"Remove", testRemove},
 		{"ApplyWriteLog", testApplyWriteLog},
+		{"StreamApplyWriteLog", testStreamApplyWriteLog},
 		{"SyncerBasic", testSyncerBasic},
 		{"SyncerRootEmptyLabelNeedsDeref", testSyncerRootEmptyLabelNeedsDeref},
 		{"SyncerRemove", testSyncerRemove},
@@ -2369,6 +2516,7 @@ func testBackend(
 		{"NodeEviction", testNodeEviction},
 		{"DoubleInsertWithEviction", testDoubleInsertWithEviction},
 		{"DebugDump", testDebugDumpLocal},
+		{"Stats", testStats},
 		{"OnCommitHooks", testOnCommitHooks},
 		{"CommitNoPersist", testCommitNoPersist},
 		{"EmptyValueWriteLog", testEmptyValueWriteLog},
@@ -2527,6 +2675,76 @@ func benchmarkInsertBatch(b *testing.B, numValues int, commit bool) {
 	}
 }
 
+// sortedWriteLogIterator generates a write log with the given number of uniformly distributed and
+// strictly sorted keys without keeping it in memory.
+type sortedWriteLogIterator struct {
+	cursor     uint64
+	numEntries uint64
+}
+
+func (it *sortedWriteLogIterator) Next() (bool, error) {
+	it.cursor++
+	return it.cursor <= it.numEntries, nil
+}
+
+func (it *sortedWriteLogIterator) Value() (writelog.LogEntry, error) {
+	i := it.cursor - 1
+	h := hash.NewFromBytes([]byte(fmt.Sprintf("key %d", i)))
+	binary.BigEndian.PutUint64(h[:8], i*(math.MaxUint64/it.numEntries))
+	return writelog.LogEntry{
+		Key:   h[:],
+		Value: []byte(fmt.Sprintf("value %d", i)),
+	}, nil
+}
+
+func BenchmarkStreamApplyWriteLog(b *testing.B) {
+	const (
+		numEntries   = 1_000_000
+		memoryBudget = 16 * 1024 * 1024
+	)
+	ctx := context.Background()
+
+	emptyRoot := node.Root{
+		Namespace: testNs,
+		Version:   0,
+		Type:      node.RootTypeState,
+	}
+	emptyRoot.Hash.Empty()
+	root := emptyRoot
+	root.Version = 1
+
+	// Compute the expected root hash.
+	tree := New(nil, nil, node.RootTypeState)
+	err := tree.ApplyWriteLog(ctx, &sortedWriteLogIterator{numEntries: numEntries})
+	require.NoError(b, err, "ApplyWriteLog")
+	_, root.Hash, err = tree.Commit(ctx, testNs, root.Version, NoPersist())
+	require.NoError(b, err, "Commit")
+	tree.Close()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		dir, err := os.MkdirTemp("", "mkvs.bench.badgerdb")
+		require.NoError(b, err, "TempDir")
+		ndb, err := badgerDb.New(&db.Config{
+			DB:           dir,
+			NoFsync:      true,
+			Namespace:    testNs,
+			MaxCacheSize: 16 * 1024 * 1024,
+		})
+		require.NoError(b, err, "New")
+		b.StartTimer()
+
+		err = StreamApplyWriteLog(ctx, ndb, emptyRoot, root, &sortedWriteLogIterator{numEntries: numEntries}, memoryBudget)
+		require.NoError(b, err, "StreamApplyWriteLog")
+
+		b.StopTimer()
+		ndb.Close()
+		os.RemoveAll(dir)
+		b.StartTimer()
+	}
+}
+
 func generateKeyValuePairsEx(prefix string, count int) ([][]byte, [][]byte) {
 	keys := make([][]byte, count)
 	values := make([][]byte, count)
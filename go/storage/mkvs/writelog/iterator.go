@@ -7,6 +7,7 @@ import (
 
 var (
 	_ Iterator = (*staticIterator)(nil)
+	_ Iterator = (*drainingIterator)(nil)
 	_ Iterator = (*PipeIterator)(nil)
 
 	// ErrIteratorInvalid is raised when Value() is called on an iterator that finished already or hasn't started yet.
@@ -54,6 +55,39 @@ func NewStaticIterator(writeLog WriteLog) Iterator {
 	}
 }
 
+type drainingIterator struct {
+	current *LogEntry
+	entries WriteLog
+}
+
+func (i *drainingIterator) Next() (bool, error) {
+	if len(i.entries) == 0 {
+		i.current = nil
+		return false, nil
+	}
+	entry := i.entries[0]
+	i.current = &entry
+	i.entries[0] = LogEntry{}
+	i.entries = i.entries[1:]
+	return true, nil
+}
+
+func (i *drainingIterator) Value() (LogEntry, error) {
+	if i.current == nil {
+		return LogEntry{}, ErrIteratorInvalid
+	}
+	return *i.current, nil
+}
+
+// NewDrainingIterator returns a new writelog iterator that takes ownership of the given in-memory
+// array and releases each entry once the iterator advances past it, so that the memory used by
+// already consumed entries can be reclaimed while the rest are still being processed.
+func NewDrainingIterator(writeLog WriteLog) Iterator {
+	return &drainingIterator{
+		entries: writeLog,
+	}
+}
+
 // PipeIterator is a queue-backed writelog iterator which can be asynchronously
 // both pushed into and read from.
 type PipeIterator struct {
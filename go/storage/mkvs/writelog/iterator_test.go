@@ -55,6 +55,35 @@ func TestStaticIterator(t *testing.T) {
 	require.Equal(t, more, false)
 }
 
+func TestDrainingIterator(t *testing.T) {
+	wl := makeWriteLog()
+	expected := makeWriteLog()
+
+	it := NewDrainingIterator(wl)
+	_, err := it.Value()
+	require.Error(t, err, "it.Value() before start")
+
+	for i, ent := range expected {
+		more, err := it.Next()
+		require.NoError(t, err, "it.Next()")
+		require.True(t, more)
+		val, err := it.Value()
+		require.NoError(t, err, "it.Value()")
+		require.Equal(t, ent, val)
+		require.Equal(t, LogEntry{}, wl[i], "consumed entries should be released")
+	}
+	more, err := it.Next()
+	require.NoError(t, err, "last it.Next()")
+	require.False(t, more)
+	_, err = it.Value()
+	require.Error(t, err, "last it.Value()")
+
+	it = NewDrainingIterator(nil)
+	more, err = it.Next()
+	require.NoError(t, err, "empty it.Next()")
+	require.False(t, more)
+}
+
 func TestPipeIterator(t *testing.T) {
 	var err error
 	var more bool
@@ -189,7 +189,15 @@ func testBasic(t *testing.T, localBackend api.LocalBackend, storage api.Backend,
 
 		cps, err := storage.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{Version: 1, Namespace: namespace})
 		require.NoError(t, err, "GetCheckpoints")
-		require.Contains(t, cps, cp, "GetCheckpoints should return correct checkpoint metadata")
+		var found bool
+		for _, c := range cps {
+			if c.EncodedHash() == cp.EncodedHash() {
+				require.Equal(t, cp, c.WithoutAvailability(), "GetCheckpoints should return correct checkpoint metadata")
+				require.True(t, c.IsComplete(), "checkpoint should be complete")
+				found = true
+			}
+		}
+		require.True(t, found, "GetCheckpoints should return the created checkpoint")
 		require.Len(t, cp.Chunks, 1, "checkpoint should have a single chunk")
 
 		var buf bytes.Buffer
@@ -202,5 +210,9 @@ func testBasic(t *testing.T, localBackend api.LocalBackend, storage api.Backend,
 		_, err = io.Copy(hb, &buf)
 		require.NoError(t, err, "Copy")
 		require.Equal(t, cp.Chunks[0], hb.Build(), "GetCheckpointChunk must return correct chunk")
+
+		ci, err := storage.GetCheckpointChunkInfo(ctx, chunk)
+		require.NoError(t, err, "GetCheckpointChunkInfo")
+		require.Equal(t, cp.Chunks[0], ci.Digest, "GetCheckpointChunkInfo must return correct digest")
 	})
 }
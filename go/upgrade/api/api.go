@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
@@ -83,7 +84,20 @@ var (
 	// ErrBadDescriptor is the error returned when the provided descriptor is bad.
 	ErrBadDescriptor = errors.New(ModuleName, 8, "upgrade: bad descriptor")
 
+	// ErrSimulationUnavailable is the error returned from DryRun when the consensus state is not
+	// available for simulating upgrades.
+	ErrSimulationUnavailable = errors.New(ModuleName, 9, "upgrade: upgrade simulation not available")
+
+	// ErrSimulationNotSupported is the error returned from DryRun when the upgrade handler does not
+	// support simulation.
+	ErrSimulationNotSupported = errors.New(ModuleName, 10, "upgrade: handler does not support simulation")
+
+	// ErrNotLocalUpgrade is the error returned from CancelLocalUpgrade when the upgrade being
+	// cancelled was not submitted locally.
+	ErrNotLocalUpgrade = errors.New(ModuleName, 11, "upgrade: can not cancel upgrade not submitted locally")
+
 	_ prettyprint.PrettyPrinter = (*Descriptor)(nil)
+	_ prettyprint.PrettyPrinter = (*DryRunResult)(nil)
 )
 
 // HandlerName is the name of the upgrade descriptor handler.
@@ -190,6 +204,57 @@ func (d Descriptor) PrettyType() (any, error) {
 	return d, nil
 }
 
+// UpgradeSource is the source from which an upgrade was scheduled.
+type UpgradeSource uint8
+
+const (
+	// UpgradeSourceUnknown is the source of upgrades scheduled before sources were tracked.
+	UpgradeSourceUnknown UpgradeSource = 0
+	// UpgradeSourceGovernance is the source of upgrades scheduled by governance proposals.
+	UpgradeSourceGovernance UpgradeSource = 1
+	// UpgradeSourceLocal is the source of upgrades submitted locally by the node operator.
+	UpgradeSourceLocal UpgradeSource = 2
+)
+
+// String returns a string representation of the upgrade source.
+func (s UpgradeSource) String() string {
+	switch s {
+	case UpgradeSourceUnknown:
+		return "unknown"
+	case UpgradeSourceGovernance:
+		return "governance"
+	case UpgradeSourceLocal:
+		return "local"
+	default:
+		return fmt.Sprintf("[unknown source: %d]", uint8(s))
+	}
+}
+
+// MarshalText encodes an upgrade source into text form.
+func (s UpgradeSource) MarshalText() ([]byte, error) {
+	switch s {
+	case UpgradeSourceUnknown, UpgradeSourceGovernance, UpgradeSourceLocal:
+		return []byte(s.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid upgrade source: %d", uint8(s))
+	}
+}
+
+// UnmarshalText decodes a text slice into an upgrade source.
+func (s *UpgradeSource) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case UpgradeSourceUnknown.String():
+		*s = UpgradeSourceUnknown
+	case UpgradeSourceGovernance.String():
+		*s = UpgradeSourceGovernance
+	case UpgradeSourceLocal.String():
+		*s = UpgradeSourceLocal
+	default:
+		return fmt.Errorf("invalid upgrade source: %s", string(text))
+	}
+	return nil
+}
+
 // PendingUpgrade describes a currently pending upgrade and includes the
 // submitted upgrade descriptor.
 type PendingUpgrade struct {
@@ -198,6 +263,9 @@ type PendingUpgrade struct {
 	// Descriptor is the upgrade descriptor describing the upgrade.
 	Descriptor *Descriptor `json:"descriptor"`
 
+	// Source is the source from which the upgrade was scheduled.
+	Source UpgradeSource `json:"source,omitempty"`
+
 	// UpgradeHeight is the height at which the upgrade epoch was reached
 	// (or InvalidUpgradeHeight if it hasn't been reached yet).
 	UpgradeHeight int64 `json:"upgrade_height"`
@@ -229,21 +297,48 @@ func (pu *PendingUpgrade) PushStage(stage UpgradeStage) {
 	pu.LastCompletedStage = stage
 }
 
+// PendingUpgradeReadiness describes whether the running binary is ready for a pending upgrade.
+type PendingUpgradeReadiness struct {
+	// Descriptor is the upgrade descriptor describing the upgrade.
+	Descriptor *Descriptor `json:"descriptor"`
+
+	// HandlerPresent is true iff the running binary has the upgrade handler required by the
+	// upgrade.
+	HandlerPresent bool `json:"handler_present"`
+
+	// SoftwareVersion is the version of the running binary which provides the upgrade handler
+	// (empty if the handler is not present).
+	SoftwareVersion string `json:"software_version,omitempty"`
+}
+
 // Backend defines the interface for upgrade managers.
 type Backend interface {
 	// SubmitDescriptor submits the serialized descriptor to the upgrade manager
 	// which then schedules and manages the upgrade.
-	SubmitDescriptor(*Descriptor) error
+	//
+	// In case the same upgrade was already submitted locally, submitting it from governance
+	// changes its source to governance.
+	SubmitDescriptor(UpgradeSource, *Descriptor) error
 
 	// PendingUpgrades returns pending upgrades.
 	PendingUpgrades() ([]*PendingUpgrade, error)
 
+	// PendingUpgradeReadiness returns the readiness of this node for each of the pending upgrades
+	// that have not yet been completed.
+	PendingUpgradeReadiness() ([]*PendingUpgradeReadiness, error)
+
 	// HasPendingUpgradeAt returns whether there is a pending upgrade at a specified height.
 	HasPendingUpgradeAt(int64) (bool, error)
 
 	// CancelUpgrade cancels a specific pending upgrade, unless it is already in progress.
 	CancelUpgrade(*Descriptor) error
 
+	// CancelLocalUpgrade cancels all pending upgrades with the given handler name that were
+	// submitted locally, unless they are already in progress.
+	//
+	// In case the matching upgrades were not submitted locally, this returns ErrNotLocalUpgrade.
+	CancelLocalUpgrade(HandlerName) error
+
 	// GetUpgrade returns the pending upgrade (if any) that has the given descriptor.
 	//
 	// In case no such upgrade exists, this returns ErrUpgradeNotFound.
@@ -260,6 +355,82 @@ type Backend interface {
 	// It is idempotent with respect to the current upgrade descriptor.
 	ConsensusUpgrade(any, beacon.EpochTime, int64) error
 
+	// DryRun executes the consensus portion of the upgrade described by the given descriptor
+	// against a throwaway copy of the current consensus state, without committing anything.
+	//
+	// A failing upgrade handler does not result in an error, instead the failure is reported in
+	// the returned result.
+	DryRun(ctx context.Context, descriptor *Descriptor) (*DryRunResult, error)
+
+	// SetConsensusSimulator configures the simulator used to perform dry runs.
+	SetConsensusSimulator(simulator ConsensusSimulator)
+
 	// Close cleans up any upgrader state and database handles.
 	Close()
 }
+
+// ConsensusSimulator simulates the consensus portion of upgrades.
+type ConsensusSimulator interface {
+	// SimulateConsensusUpgrade calls the given function with the begin block and then with the
+	// end block private context of a block executed on top of a throwaway copy of the latest
+	// consensus state. Returns the height of the consensus state used.
+	SimulateConsensusUpgrade(ctx context.Context, fn func(privateCtx any) error) (int64, error)
+}
+
+// ParameterChange is a consensus parameter change.
+type ParameterChange struct {
+	// Module is the name of the module the parameter belongs to.
+	Module string `json:"module"`
+	// Parameter is the dot-separated path of the parameter.
+	Parameter string `json:"parameter"`
+	// Old is the JSON-encoded value before the change (empty if the parameter was not set).
+	Old string `json:"old,omitempty"`
+	// New is the JSON-encoded value after the change (empty if the parameter was removed).
+	New string `json:"new,omitempty"`
+}
+
+// DryRunResult is the result of an upgrade dry run.
+type DryRunResult struct {
+	// Handler is the name of the upgrade handler.
+	Handler HandlerName `json:"handler"`
+	// Height is the height of the consensus state the upgrade was executed against.
+	Height int64 `json:"height"`
+	// Success is true iff the upgrade handler completed without errors.
+	Success bool `json:"success"`
+	// Error is the upgrade handler error in case the dry run was not successful.
+	Error string `json:"error,omitempty"`
+	// Duration is the time it took to execute the upgrade handler.
+	Duration time.Duration `json:"duration"`
+	// ParameterChanges are the consensus parameters changed by the upgrade handler.
+	ParameterChanges []ParameterChange `json:"parameter_changes,omitempty"`
+}
+
+// PrettyPrint writes a pretty-printed representation of DryRunResult to the given writer.
+func (r DryRunResult) PrettyPrint(_ context.Context, prefix string, w io.Writer) {
+	fmt.Fprintf(w, "%sHandler: %s\n", prefix, r.Handler)
+	fmt.Fprintf(w, "%sHeight: %d\n", prefix, r.Height)
+	fmt.Fprintf(w, "%sSuccess: %t\n", prefix, r.Success)
+	if r.Error != "" {
+		fmt.Fprintf(w, "%sError: %s\n", prefix, r.Error)
+	}
+	fmt.Fprintf(w, "%sDuration: %s\n", prefix, r.Duration)
+	fmt.Fprintf(w, "%sParameter changes:\n", prefix)
+	if len(r.ParameterChanges) == 0 {
+		fmt.Fprintf(w, "%s  (none)\n", prefix)
+	}
+	for _, pc := range r.ParameterChanges {
+		fmt.Fprintf(w, "%s  %s.%s: %s -> %s\n", prefix, pc.Module, pc.Parameter, valueOrNone(pc.Old), valueOrNone(pc.New))
+	}
+}
+
+// PrettyType returns a representation of DryRunResult that can be used for pretty printing.
+func (r DryRunResult) PrettyType() (any, error) {
+	return r, nil
+}
+
+func valueOrNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
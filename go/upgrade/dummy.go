@@ -1,6 +1,8 @@
 package upgrade
 
 import (
+	"context"
+
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
 )
@@ -9,7 +11,7 @@ var _ api.Backend = (*dummyUpgradeManager)(nil)
 
 type dummyUpgradeManager struct{}
 
-func (u *dummyUpgradeManager) SubmitDescriptor(*api.Descriptor) error {
+func (u *dummyUpgradeManager) SubmitDescriptor(api.UpgradeSource, *api.Descriptor) error {
 	return nil
 }
 
@@ -17,6 +19,10 @@ func (u *dummyUpgradeManager) PendingUpgrades() ([]*api.PendingUpgrade, error) {
 	return nil, nil
 }
 
+func (u *dummyUpgradeManager) PendingUpgradeReadiness() ([]*api.PendingUpgradeReadiness, error) {
+	return nil, nil
+}
+
 func (u *dummyUpgradeManager) HasPendingUpgradeAt(int64) (bool, error) {
 	return false, nil
 }
@@ -25,6 +31,10 @@ func (u *dummyUpgradeManager) CancelUpgrade(*api.Descriptor) error {
 	return nil
 }
 
+func (u *dummyUpgradeManager) CancelLocalUpgrade(api.HandlerName) error {
+	return api.ErrUpgradeNotFound
+}
+
 func (u *dummyUpgradeManager) GetUpgrade(*api.Descriptor) (*api.PendingUpgrade, error) {
 	return nil, api.ErrUpgradeNotFound
 }
@@ -37,6 +47,13 @@ func (u *dummyUpgradeManager) ConsensusUpgrade(any, beacon.EpochTime, int64) err
 	return nil
 }
 
+func (u *dummyUpgradeManager) DryRun(context.Context, *api.Descriptor) (*api.DryRunResult, error) {
+	return nil, api.ErrSimulationUnavailable
+}
+
+func (u *dummyUpgradeManager) SetConsensusSimulator(api.ConsensusSimulator) {
+}
+
 func (u *dummyUpgradeManager) Close() {
 }
 
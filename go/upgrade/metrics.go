@@ -0,0 +1,42 @@
+package upgrade
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	upgradeHandlerReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_upgrade_handler_ready",
+			Help: "Whether the running binary has the upgrade handler required by a pending upgrade (1 = yes, 0 = no).",
+		},
+		[]string{"handler", "epoch"},
+	)
+	upgradeCollectors = []prometheus.Collector{
+		upgradeHandlerReady,
+	}
+
+	metricsOnce sync.Once
+)
+
+// NOTE: Assumes lock is held.
+func (u *upgradeManager) updateMetricsLocked() {
+	upgradeHandlerReady.Reset()
+	for _, pu := range u.pending {
+		if pu.IsCompleted() {
+			continue
+		}
+
+		var ready float64
+		if upgradeReadiness(pu.Descriptor).HandlerPresent {
+			ready = 1
+		}
+		upgradeHandlerReady.With(prometheus.Labels{
+			"handler": string(pu.Descriptor.Handler),
+			"epoch":   strconv.FormatUint(uint64(pu.Descriptor.Epoch), 10),
+		}).Set(ready)
+	}
+}
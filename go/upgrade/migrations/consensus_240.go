@@ -40,6 +40,11 @@ func (h *Handler240) StartupUpgrade() error {
 	return nil
 }
 
+// SupportsSimulation implements Handler.
+func (h *Handler240) SupportsSimulation() bool {
+	return true
+}
+
 // ConsensusUpgrade implements Handler.
 func (h *Handler240) ConsensusUpgrade(privateCtx any) error {
 	abciCtx := privateCtx.(*abciAPI.Context)
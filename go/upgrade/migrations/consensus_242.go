@@ -33,6 +33,11 @@ func (h *Handler242) StartupUpgrade() error {
 	return nil
 }
 
+// SupportsSimulation implements Handler.
+func (h *Handler242) SupportsSimulation() bool {
+	return true
+}
+
 // ConsensusUpgrade implements Handler.
 func (h *Handler242) ConsensusUpgrade(privateCtx any) error {
 	abciCtx := privateCtx.(*abciAPI.Context)
@@ -0,0 +1,265 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+)
+
+// Consensus243 is the name of the upgrade that enables features introduced in Oasis Core 24.3.
+//
+// This upgrade includes:
+//   - The `SuspensionReason` field in the roothash runtime state, which records the reason why
+//     the runtime has been suspended. Reasons are set for runtimes that are already suspended.
+//   - The optional batch summary in executor commitments, which is recorded in round results.
+//   - The optional minimum node software version in runtime descriptors. Existing descriptors
+//     default to no constraint.
+//   - The optional early finalization quorum in runtime descriptors. Existing descriptors default
+//     to finalization only after all non-straggler workers have committed.
+//   - The optional maximum number of in-flight rounds in runtime descriptors, which enables round
+//     pipelining. Existing descriptors default to fully serialized rounds.
+//   - Records of processed validator misbehavior evidence, which are enabled by setting the
+//     evidence records retention staking consensus parameter via governance. Recording is disabled
+//     by default.
+//   - The old allowance in allowance change events and the bounded per-beneficiary allowance
+//     history, which is enabled on networks where allowances are enabled.
+//   - Entity-controlled node authorizations with optional expiration. The node lists of existing
+//     entity descriptors are converted into authorizations that never expire.
+//   - The optional next TLS public key in node descriptors, which enables TLS key rotations with
+//     an overlap window.
+//   - Escrow operations on behalf of the owner via allowances. Gas costs of the new operations
+//     default to the costs of the corresponding escrow operations.
+//   - The late commitment window roothash consensus parameter, which allows correct executor
+//     commitments submitted shortly after a round has been finalized to be recorded in liveness
+//     statistics. The window defaults to zero (disabled) and can be changed via governance.
+//   - Recording of committee election inputs, which is enabled by setting the election inputs
+//     retention scheduler consensus parameter via governance. Recording is disabled by default.
+//   - Per-runtime committee election policies, which must be enabled via the registry consensus
+//     parameters. Existing descriptors default to the uniform election policy.
+//   - Epoch interval changes at a future epoch via governance change parameters proposals for the
+//     beacon module. Epoch boundaries are computed piecewise so that past epochs remain stable.
+//   - Early executor discrepancy detection, which starts discrepancy resolution as soon as two
+//     workers submit differing commitments for the primary scheduler's proposal, even before the
+//     scheduler has committed.
+//   - Optional priorities of consensus and P2P addresses in node descriptors, together with the
+//     limit on the number of advertised addresses and the rejection of duplicate addresses.
+//   - Optional per-deployment feature flags in runtime descriptors. Existing deployments default
+//     to no feature flags.
+const Consensus243 = "consensus243"
+
+// Version243 is the Oasis Core 24.3 version.
+var Version243 = version.MustFromString("24.3")
+
+// defaultMaxAllowanceHistory is the maximum number of recorded allowance changes per
+// owner/beneficiary combination set by the upgrade.
+const defaultMaxAllowanceHistory = 16
+
+var _ Handler = (*Handler243)(nil)
+
+// Handler243 is the upgrade handler that transitions Oasis Core from version 24.2 to 24.3.
+type Handler243 struct{}
+
+// HasStartupUpgrade implements Handler.
+func (h *Handler243) HasStartupUpgrade() bool {
+	return false
+}
+
+// StartupUpgrade implements Handler.
+func (h *Handler243) StartupUpgrade() error {
+	return nil
+}
+
+// SupportsSimulation implements Handler.
+func (h *Handler243) SupportsSimulation() bool {
+	return true
+}
+
+// ConsensusUpgrade implements Handler.
+func (h *Handler243) ConsensusUpgrade(privateCtx any) error {
+	abciCtx := privateCtx.(*abciAPI.Context)
+	switch abciCtx.Mode() {
+	case abciAPI.ContextBeginBlock:
+		// Nothing to do.
+	case abciAPI.ContextEndBlock:
+		// Consensus parameters.
+		consState := consensusState.NewMutableState(abciCtx.State())
+		consParams, err := consState.ConsensusParameters(abciCtx)
+		if err != nil {
+			return fmt.Errorf("failed to load consensus parameters: %w", err)
+		}
+
+		consParams.FeatureVersion = &Version243
+
+		if err = consState.SetConsensusParameters(abciCtx, consParams); err != nil {
+			return fmt.Errorf("failed to set consensus parameters: %w", err)
+		}
+
+		// Registry.
+		if err = h.migrateRuntimeDescriptors(abciCtx); err != nil {
+			return err
+		}
+		if err = h.migrateNodeAuthorizations(abciCtx); err != nil {
+			return err
+		}
+
+		// Roothash.
+		if err = h.migrateSuspensionReasons(abciCtx); err != nil {
+			return err
+		}
+
+		// Staking.
+		if err = h.migrateStakingParameters(abciCtx); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("upgrade handler called in unexpected context: %s", abciCtx.Mode())
+	}
+	return nil
+}
+
+// migrateSuspensionReasons sets the suspension reason for runtimes that are already suspended.
+//
+// Suspended runtimes are not scheduled, so the reason is derived from whether the key manager
+// runtime the runtime depends on is still available.
+func (h *Handler243) migrateSuspensionReasons(ctx *abciAPI.Context) error {
+	regState := registryState.NewMutableState(ctx.State())
+	rhState := roothashState.NewMutableState(ctx.State())
+
+	rtStates, err := rhState.RuntimeStates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load runtime states: %w", err)
+	}
+	for _, rtState := range rtStates {
+		if !rtState.Suspended || rtState.SuspensionReason != roothash.SuspensionReasonNone {
+			continue
+		}
+
+		rtState.SuspensionReason = roothash.SuspensionReasonInsufficientCommittee
+		if km := rtState.Runtime.KeyManager; km != nil {
+			_, err = regState.Runtime(ctx, *km)
+			switch err {
+			case nil:
+			case registry.ErrNoSuchRuntime:
+				rtState.SuspensionReason = roothash.SuspensionReasonMissingKeyManager
+			default:
+				return fmt.Errorf("failed to fetch key manager runtime: %w", err)
+			}
+		}
+
+		if err = rhState.SetRuntimeState(ctx, rtState); err != nil {
+			return fmt.Errorf("failed to set runtime state for %s: %w", rtState.Runtime.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateRuntimeDescriptors defaults the minimum node software version, the finalization quorum
+// and the maximum number of in-flight rounds of all existing runtime descriptors to their disabled
+// values, so that they can only be configured through a runtime update once the upgrade is
+// complete.
+func (h *Handler243) migrateRuntimeDescriptors(ctx *abciAPI.Context) error {
+	regState := registryState.NewMutableState(ctx.State())
+
+	for _, suspended := range []bool{false, true} {
+		var (
+			runtimes []*registry.Runtime
+			err      error
+		)
+		switch suspended {
+		case false:
+			runtimes, err = regState.Runtimes(ctx)
+		case true:
+			runtimes, err = regState.SuspendedRuntimes(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load runtimes: %w", err)
+		}
+
+		for _, rt := range runtimes {
+			if rt.MinNodeSoftwareVersion == nil && rt.Executor.FinalizationQuorumPercent == 0 && rt.Executor.MaxInFlightRounds == 0 {
+				continue
+			}
+
+			rt.MinNodeSoftwareVersion = nil
+			rt.Executor.FinalizationQuorumPercent = 0
+			rt.Executor.MaxInFlightRounds = 0
+			if err = regState.SetRuntime(ctx, rt, suspended); err != nil {
+				return fmt.Errorf("failed to set runtime descriptor for %s: %w", rt.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateNodeAuthorizations converts the node lists of all registered entity descriptors into
+// node authorizations that never expire.
+func (h *Handler243) migrateNodeAuthorizations(ctx *abciAPI.Context) error {
+	regState := registryState.NewMutableState(ctx.State())
+
+	entities, err := regState.Entities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load entities: %w", err)
+	}
+	for _, ent := range entities {
+		for _, nodeID := range ent.Nodes {
+			auth := &registry.NodeAuthorization{
+				EntityID: ent.ID,
+				NodeID:   nodeID,
+			}
+			if err = regState.SetNodeAuthorization(ctx, auth); err != nil {
+				return fmt.Errorf("failed to set node authorization for %s: %w", nodeID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateStakingParameters enables the allowance history in case allowances are enabled and
+// configures gas costs for escrow operations via allowances.
+func (h *Handler243) migrateStakingParameters(ctx *abciAPI.Context) error {
+	stakeState := stakingState.NewMutableState(ctx.State())
+
+	stakeParams, err := stakeState.ConsensusParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load staking consensus parameters: %w", err)
+	}
+
+	if stakeParams.MaxAllowances != 0 && stakeParams.MaxAllowanceHistory == 0 {
+		stakeParams.MaxAllowanceHistory = defaultMaxAllowanceHistory
+	}
+
+	for op, baseOp := range map[transaction.Op]transaction.Op{
+		staking.GasOpDelegateFromAllowance:      staking.GasOpAddEscrow,
+		staking.GasOpReclaimEscrowFromAllowance: staking.GasOpReclaimEscrow,
+	} {
+		if _, ok := stakeParams.GasCosts[op]; ok {
+			continue
+		}
+		cost, ok := stakeParams.GasCosts[baseOp]
+		if !ok {
+			continue
+		}
+		stakeParams.GasCosts[op] = cost
+	}
+
+	if err = stakeState.SetConsensusParameters(ctx, stakeParams); err != nil {
+		return fmt.Errorf("failed to update staking consensus parameters: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	Register(Consensus243, &Handler243{})
+}
@@ -46,6 +46,10 @@ func (th *dummyMigrationHandler) StartupUpgrade() error {
 	return nil
 }
 
+func (th *dummyMigrationHandler) SupportsSimulation() bool {
+	return true
+}
+
 func (th *dummyMigrationHandler) ConsensusUpgrade(privateCtx any) error {
 	abciCtx := privateCtx.(*abciAPI.Context)
 	switch abciCtx.Mode() {
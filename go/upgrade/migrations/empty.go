@@ -19,6 +19,10 @@ func (th *emptyHandler) StartupUpgrade() error {
 	return nil
 }
 
+func (th *emptyHandler) SupportsSimulation() bool {
+	return true
+}
+
 func (th *emptyHandler) ConsensusUpgrade(any) error {
 	// Nothing to do.
 	return nil
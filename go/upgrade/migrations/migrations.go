@@ -3,6 +3,7 @@ package migrations
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
@@ -38,6 +39,13 @@ type Handler interface {
 	// This method will be called twice, once in BeginBlock and once in
 	// EndBlock.
 	ConsensusUpgrade(any) error
+
+	// SupportsSimulation returns true iff the consensus portion of the upgrade can be executed
+	// against a throwaway copy of consensus state in order to validate the upgrade in advance.
+	//
+	// When simulated, ConsensusUpgrade is called with a context for which IsUpgradeSimulation
+	// returns true and the handler must not have any side effects outside of consensus state.
+	SupportsSimulation() bool
 }
 
 // Context defines the common context used by migration handlers.
@@ -79,3 +87,14 @@ func GetHandler(name upgradeApi.HandlerName) (Handler, error) {
 
 	return h.(Handler), nil
 }
+
+// SupportedHandlers returns the sorted names of all registered migration handlers.
+func SupportedHandlers() []upgradeApi.HandlerName {
+	var names []upgradeApi.HandlerName
+	registeredHandlers.Range(func(name, _ any) bool {
+		names = append(names, name.(upgradeApi.HandlerName))
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
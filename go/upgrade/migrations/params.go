@@ -0,0 +1,156 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	consensusState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/abci/state"
+	abciAPI "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/api"
+	beaconState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/beacon/state"
+	governanceState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/governance/state"
+	churpState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/keymanager/churp/state"
+	secretsState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/keymanager/secrets/state"
+	registryState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/registry/state"
+	roothashState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/roothash/state"
+	schedulerState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/scheduler/state"
+	stakingState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/staking/state"
+	vaultState "github.com/oasisprotocol/oasis-core/go/consensus/cometbft/apps/vault/state"
+	governance "github.com/oasisprotocol/oasis-core/go/governance/api"
+	keymanager "github.com/oasisprotocol/oasis-core/go/keymanager/api"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+	staking "github.com/oasisprotocol/oasis-core/go/staking/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	upgradeApi "github.com/oasisprotocol/oasis-core/go/upgrade/api"
+	vault "github.com/oasisprotocol/oasis-core/go/vault/api"
+)
+
+// parameterLoaders are the loaders of the consensus parameters of all modules.
+var parameterLoaders = map[string]func(context.Context, mkvs.ImmutableKeyValueTree) (any, error){
+	"consensus": func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return consensusState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	beacon.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return beaconState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	governance.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return governanceState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	churp.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return churpState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	keymanager.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return secretsState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	registry.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return registryState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	roothash.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return roothashState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	scheduler.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return schedulerState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	staking.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return stakingState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+	vault.ModuleName: func(ctx context.Context, tree mkvs.ImmutableKeyValueTree) (any, error) {
+		return vaultState.NewImmutableState(tree).ConsensusParameters(ctx)
+	},
+}
+
+// ParameterSnapshot is a flattened snapshot of the consensus parameters of all modules, mapping
+// module names to dot-separated parameter paths and their JSON-encoded values.
+type ParameterSnapshot map[string]map[string]string
+
+// SnapshotParameters takes a snapshot of the consensus parameters of all modules from the state
+// of the given private context.
+//
+// Modules whose parameters are not present in state (e.g., because the module has not yet been
+// enabled) are omitted from the snapshot.
+func SnapshotParameters(privateCtx any) (ParameterSnapshot, error) {
+	abciCtx := privateCtx.(*abciAPI.Context)
+
+	snapshot := make(ParameterSnapshot, len(parameterLoaders))
+	for module, load := range parameterLoaders {
+		params, err := load(abciCtx, abciCtx.State())
+		if err != nil {
+			continue
+		}
+
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s consensus parameters: %w", module, err)
+		}
+		var value any
+		if err = json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s consensus parameters: %w", module, err)
+		}
+
+		flat := make(map[string]string)
+		flattenParameters(flat, "", value)
+		snapshot[module] = flat
+	}
+	return snapshot, nil
+}
+
+// flattenParameters flattens the JSON object into the given map of dot-separated paths.
+func flattenParameters(flat map[string]string, prefix string, value any) {
+	obj, ok := value.(map[string]any)
+	if !ok || (len(obj) == 0 && prefix != "") {
+		raw, _ := json.Marshal(value)
+		flat[prefix] = string(raw)
+		return
+	}
+	for key, v := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flattenParameters(flat, path, v)
+	}
+}
+
+// Changes returns the parameters that differ between the snapshot and the given newer snapshot,
+// sorted by module and parameter path.
+func (s ParameterSnapshot) Changes(newer ParameterSnapshot) []upgradeApi.ParameterChange {
+	modules := make(map[string]struct{}, len(s)+len(newer))
+	for m := range s {
+		modules[m] = struct{}{}
+	}
+	for m := range newer {
+		modules[m] = struct{}{}
+	}
+
+	var changes []upgradeApi.ParameterChange
+	for _, module := range slices.Sorted(maps.Keys(modules)) {
+		oldParams, newParams := s[module], newer[module]
+
+		paths := make(map[string]struct{}, len(oldParams)+len(newParams))
+		for p := range oldParams {
+			paths[p] = struct{}{}
+		}
+		for p := range newParams {
+			paths[p] = struct{}{}
+		}
+		for _, path := range slices.Sorted(maps.Keys(paths)) {
+			oldValue, newValue := oldParams[path], newParams[path]
+			if oldValue == newValue {
+				continue
+			}
+			changes = append(changes, upgradeApi.ParameterChange{
+				Module:    module,
+				Parameter: path,
+				Old:       oldValue,
+				New:       newValue,
+			})
+		}
+	}
+	return changes
+}
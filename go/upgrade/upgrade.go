@@ -7,13 +7,19 @@
 package upgrade
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
 	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 )
@@ -31,13 +37,15 @@ type upgradeManager struct {
 	pending    []*api.PendingUpgrade
 	shouldStop bool
 
+	simulator api.ConsensusSimulator
+
 	dataDir string
 
 	logger *logging.Logger
 }
 
 // Implements api.Backend.
-func (u *upgradeManager) SubmitDescriptor(descriptor *api.Descriptor) error {
+func (u *upgradeManager) SubmitDescriptor(source api.UpgradeSource, descriptor *api.Descriptor) error {
 	if descriptor == nil {
 		return api.ErrBadDescriptor
 	}
@@ -46,20 +54,37 @@ func (u *upgradeManager) SubmitDescriptor(descriptor *api.Descriptor) error {
 	defer u.Unlock()
 
 	for _, pu := range u.pending {
-		if pu.Descriptor.Equals(descriptor) {
+		if !pu.Descriptor.Equals(descriptor) {
+			continue
+		}
+		if source != api.UpgradeSourceGovernance || pu.Source != api.UpgradeSourceLocal {
 			return api.ErrAlreadyPending
 		}
+
+		// Upgrades scheduled by governance must not be cancelled locally.
+		u.logger.Info("locally submitted upgrade scheduled by governance",
+			"handler", pu.Descriptor.Handler,
+			"epoch", pu.Descriptor.Epoch,
+		)
+		pu.Source = source
+		if err := u.flushDescriptorLocked(); err != nil {
+			pu.Source = api.UpgradeSourceLocal
+			return err
+		}
+		return nil
 	}
 
 	pending := &api.PendingUpgrade{
 		Versioned:  cbor.NewVersioned(api.LatestPendingUpgradeVersion),
 		Descriptor: descriptor,
+		Source:     source,
 	}
 	u.pending = append(u.pending, pending)
 
 	u.logger.Info("received upgrade descriptor, scheduling shutdown",
 		"handler", pending.Descriptor.Handler,
 		"epoch", pending.Descriptor.Epoch,
+		"source", pending.Source,
 	)
 
 	return u.flushDescriptorLocked()
@@ -73,6 +98,32 @@ func (u *upgradeManager) PendingUpgrades() ([]*api.PendingUpgrade, error) {
 	return append([]*api.PendingUpgrade{}, u.pending...), nil
 }
 
+// Implements api.Backend.
+func (u *upgradeManager) PendingUpgradeReadiness() ([]*api.PendingUpgradeReadiness, error) {
+	u.Lock()
+	defer u.Unlock()
+
+	var readiness []*api.PendingUpgradeReadiness
+	for _, pu := range u.pending {
+		if pu.IsCompleted() {
+			continue
+		}
+		readiness = append(readiness, upgradeReadiness(pu.Descriptor))
+	}
+	return readiness, nil
+}
+
+func upgradeReadiness(descriptor *api.Descriptor) *api.PendingUpgradeReadiness {
+	readiness := &api.PendingUpgradeReadiness{
+		Descriptor: descriptor,
+	}
+	if _, err := migrations.GetHandler(descriptor.Handler); err == nil {
+		readiness.HandlerPresent = true
+		readiness.SoftwareVersion = version.SoftwareVersion
+	}
+	return readiness
+}
+
 // Implements api.Backend.
 func (u *upgradeManager) HasPendingUpgradeAt(height int64) (bool, error) {
 	u.Lock()
@@ -124,6 +175,55 @@ func (u *upgradeManager) CancelUpgrade(descriptor *api.Descriptor) error {
 	return nil
 }
 
+// Implements api.Backend.
+func (u *upgradeManager) CancelLocalUpgrade(name api.HandlerName) error {
+	u.Lock()
+	defer u.Unlock()
+
+	var (
+		found     bool
+		cancelled []*api.PendingUpgrade
+		pending   []*api.PendingUpgrade
+	)
+	for _, pu := range u.pending {
+		if pu.Descriptor.Handler != name {
+			pending = append(pending, pu)
+			continue
+		}
+		found = true
+
+		if pu.Source != api.UpgradeSourceLocal {
+			pending = append(pending, pu)
+			continue
+		}
+		if pu.UpgradeHeight != api.InvalidUpgradeHeight || pu.HasAnyStages() {
+			return api.ErrUpgradeInProgress
+		}
+		cancelled = append(cancelled, pu)
+	}
+	switch {
+	case !found:
+		return api.ErrUpgradeNotFound
+	case len(cancelled) == 0:
+		return api.ErrNotLocalUpgrade
+	}
+
+	oldPending := u.pending
+	u.pending = pending
+	if err := u.flushDescriptorLocked(); err != nil {
+		u.pending = oldPending
+		return err
+	}
+
+	for _, pu := range cancelled {
+		u.logger.Info("cancelled locally submitted upgrade",
+			"handler", pu.Descriptor.Handler,
+			"epoch", pu.Descriptor.Epoch,
+		)
+	}
+	return nil
+}
+
 // Implements api.Backend.
 func (u *upgradeManager) GetUpgrade(descriptor *api.Descriptor) (*api.PendingUpgrade, error) {
 	if descriptor == nil {
@@ -211,6 +311,7 @@ func (u *upgradeManager) flushDescriptorLocked() error {
 		pending = append(pending, pu)
 	}
 	u.pending = pending
+	u.updateMetricsLocked()
 
 	// Delete the state if there's no pending upgrades.
 	if len(u.pending) == 0 {
@@ -252,6 +353,12 @@ func (u *upgradeManager) StartupUpgrade() error {
 			return err
 		}
 		pu.PushStage(api.UpgradeStageStartup)
+
+		journal.Record(&journal.Event{
+			Kind:    journal.KindUpgradeApplied,
+			Height:  pu.UpgradeHeight,
+			Message: fmt.Sprintf("startup stage of upgrade %s applied", pu.Descriptor.Handler),
+		})
 	}
 
 	return u.flushDescriptorLocked()
@@ -328,12 +435,108 @@ func (u *upgradeManager) ConsensusUpgrade(privateCtx any, currentEpoch beacon.Ep
 			if err := handler.ConsensusUpgrade(privateCtx); err != nil {
 				return err
 			}
+
+			journal.Record(&journal.Event{
+				Kind:    journal.KindUpgradeApplied,
+				Height:  currentHeight,
+				Message: fmt.Sprintf("consensus stage of upgrade %s applied", pu.Descriptor.Handler),
+			})
 		}
 	}
 
 	return u.flushDescriptorLocked()
 }
 
+// Implements api.Backend.
+func (u *upgradeManager) DryRun(ctx context.Context, descriptor *api.Descriptor) (*api.DryRunResult, error) {
+	if descriptor == nil {
+		return nil, api.ErrBadDescriptor
+	}
+	if err := descriptor.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %w", api.ErrBadDescriptor, err)
+	}
+
+	u.Lock()
+	simulator := u.simulator
+	u.Unlock()
+	if simulator == nil {
+		return nil, api.ErrSimulationUnavailable
+	}
+
+	handler, err := migrations.GetHandler(descriptor.Handler)
+	if err != nil {
+		return nil, err
+	}
+	if !handler.SupportsSimulation() {
+		return nil, api.ErrSimulationNotSupported
+	}
+
+	var (
+		before, after migrations.ParameterSnapshot
+		handlerErr    error
+		duration      time.Duration
+	)
+	runHandler := func(privateCtx any) {
+		defer func() {
+			if r := recover(); r != nil {
+				handlerErr = fmt.Errorf("upgrade handler panicked: %v", r)
+			}
+		}()
+
+		start := time.Now()
+		handlerErr = handler.ConsensusUpgrade(privateCtx)
+		duration += time.Since(start)
+	}
+
+	height, err := simulator.SimulateConsensusUpgrade(ctx, func(privateCtx any) error {
+		var err error
+		if before == nil {
+			if before, err = migrations.SnapshotParameters(privateCtx); err != nil {
+				return err
+			}
+		}
+		runHandler(privateCtx)
+		if handlerErr != nil {
+			return handlerErr
+		}
+		after, err = migrations.SnapshotParameters(privateCtx)
+		return err
+	})
+
+	result := &api.DryRunResult{
+		Handler:  descriptor.Handler,
+		Height:   height,
+		Duration: duration,
+	}
+	switch {
+	case handlerErr != nil:
+		result.Error = handlerErr.Error()
+	case err != nil:
+		return nil, fmt.Errorf("upgrade: failed to simulate upgrade: %w", err)
+	default:
+		result.Success = true
+		result.ParameterChanges = before.Changes(after)
+	}
+
+	u.logger.Info("upgrade dry run completed",
+		"handler", descriptor.Handler,
+		"height", result.Height,
+		"success", result.Success,
+		"err", result.Error,
+		"duration", result.Duration,
+	)
+
+	return result, nil
+}
+
+// Implements api.Backend.
+func (u *upgradeManager) SetConsensusSimulator(simulator api.ConsensusSimulator) {
+	u.Lock()
+	defer u.Unlock()
+
+	u.simulator = simulator
+}
+
 // Implements api.Backend.
 func (u *upgradeManager) Close() {
 	u.Lock()
@@ -346,6 +549,10 @@ func (u *upgradeManager) Close() {
 // pending upgrade descriptors; if this node is not the one intended to be run according
 // to the loaded descriptor, New will return an error.
 func New(store *persistent.CommonStore, dataDir string, checkStatus bool) (api.Backend, error) {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(upgradeCollectors...)
+	})
+
 	svcStore := store.GetServiceStore(api.ModuleName)
 
 	upgrader := &upgradeManager{
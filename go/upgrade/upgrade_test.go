@@ -0,0 +1,103 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/api"
+)
+
+func TestCancelLocalUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	dataDir := t.TempDir()
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	upgrader, err := New(store, dataDir, false)
+	require.NoError(err, "New")
+
+	governance := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   "governance-upgrade",
+		Target:    version.Versions,
+		Epoch:     10,
+	}
+	local := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   "local-upgrade",
+		Target:    version.Versions,
+		Epoch:     20,
+	}
+	promoted := &api.Descriptor{
+		Versioned: cbor.NewVersioned(api.LatestDescriptorVersion),
+		Handler:   "promoted-upgrade",
+		Target:    version.Versions,
+		Epoch:     30,
+	}
+
+	err = upgrader.SubmitDescriptor(api.UpgradeSourceGovernance, governance)
+	require.NoError(err, "SubmitDescriptor(governance)")
+	err = upgrader.SubmitDescriptor(api.UpgradeSourceLocal, local)
+	require.NoError(err, "SubmitDescriptor(local)")
+	err = upgrader.SubmitDescriptor(api.UpgradeSourceLocal, governance)
+	require.ErrorIs(err, api.ErrAlreadyPending, "local resubmission should not change the source")
+
+	// Upgrades submitted locally and later scheduled by governance become governance upgrades.
+	err = upgrader.SubmitDescriptor(api.UpgradeSourceLocal, promoted)
+	require.NoError(err, "SubmitDescriptor(local)")
+	err = upgrader.SubmitDescriptor(api.UpgradeSourceGovernance, promoted)
+	require.NoError(err, "SubmitDescriptor(governance)")
+
+	pending, err := upgrader.PendingUpgrades()
+	require.NoError(err, "PendingUpgrades")
+	require.Len(pending, 3)
+	sources := make(map[api.HandlerName]api.UpgradeSource)
+	for _, pu := range pending {
+		sources[pu.Descriptor.Handler] = pu.Source
+	}
+	require.Equal(map[api.HandlerName]api.UpgradeSource{
+		governance.Handler: api.UpgradeSourceGovernance,
+		local.Handler:      api.UpgradeSourceLocal,
+		promoted.Handler:   api.UpgradeSourceGovernance,
+	}, sources)
+
+	// Governance upgrades must not be cancelled locally.
+	err = upgrader.CancelLocalUpgrade(governance.Handler)
+	require.ErrorIs(err, api.ErrNotLocalUpgrade)
+	err = upgrader.CancelLocalUpgrade(promoted.Handler)
+	require.ErrorIs(err, api.ErrNotLocalUpgrade)
+	err = upgrader.CancelLocalUpgrade("unknown-upgrade")
+	require.ErrorIs(err, api.ErrUpgradeNotFound)
+
+	// Upgrades in progress must not be cancelled.
+	pu, err := upgrader.GetUpgrade(local)
+	require.NoError(err, "GetUpgrade")
+	pu.UpgradeHeight = 42
+	err = upgrader.CancelLocalUpgrade(local.Handler)
+	require.ErrorIs(err, api.ErrUpgradeInProgress)
+	pu.UpgradeHeight = api.InvalidUpgradeHeight
+
+	err = upgrader.CancelLocalUpgrade(local.Handler)
+	require.NoError(err, "CancelLocalUpgrade")
+	_, err = upgrader.GetUpgrade(local)
+	require.ErrorIs(err, api.ErrUpgradeNotFound)
+
+	// Sources should be persisted.
+	upgrader.Close()
+	upgrader, err = New(store, dataDir, true)
+	require.NoError(err, "New")
+	defer upgrader.Close()
+
+	pending, err = upgrader.PendingUpgrades()
+	require.NoError(err, "PendingUpgrades")
+	require.Len(pending, 2)
+	for _, pu := range pending {
+		require.Equal(api.UpgradeSourceGovernance, pu.Source)
+	}
+}
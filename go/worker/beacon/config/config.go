@@ -0,0 +1,26 @@
+// Package config implements global configuration options.
+package config
+
+import "fmt"
+
+// Config is the beacon worker configuration structure.
+type Config struct {
+	// VRFProofWarningFraction is the fraction of the VRF proof submission window after which a
+	// warning is raised in case the node has not yet submitted its VRF proof.
+	VRFProofWarningFraction float64 `yaml:"vrf_proof_warning_fraction"`
+}
+
+// Validate validates the configuration settings.
+func (c *Config) Validate() error {
+	if c.VRFProofWarningFraction <= 0 || c.VRFProofWarningFraction > 1 {
+		return fmt.Errorf("vrf_proof_warning_fraction must be in the range (0, 1]")
+	}
+	return nil
+}
+
+// DefaultConfig returns the default configuration settings.
+func DefaultConfig() Config {
+	return Config{
+		VRFProofWarningFraction: 0.5,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
 
@@ -56,6 +57,14 @@ func (w *Worker) Name() string {
 	return "beacon worker"
 }
 
+// GetStatus returns the beacon worker status.
+func (w *Worker) GetStatus() *control.BeaconStatus {
+	if w.vrf == nil || !w.vrf.enabled {
+		return nil
+	}
+	return w.vrf.GetStatus()
+}
+
 // New creates a new worker instance.
 func New(
 	identity *identity.Identity,
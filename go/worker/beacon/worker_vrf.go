@@ -4,17 +4,38 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"sync"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	"github.com/oasisprotocol/oasis-core/go/consensus/api/transaction"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+var (
+	vrfProofWarning = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_beacon_vrf_proof_warning",
+			Help: "Has the node not submitted its VRF proof by the configured fraction of the submission window (binary).",
+		},
+	)
+
+	vrfCollectors = []prometheus.Collector{
+		vrfProofWarning,
+	}
+
+	metricsOnce sync.Once
 )
 
 type vrfWorker struct {
+	sync.RWMutex
+
 	parent *Worker
 	logger *logging.Logger
 
@@ -25,6 +46,8 @@ type vrfWorker struct {
 	quitCh chan struct{}
 
 	enabled bool
+
+	status control.BeaconStatus
 }
 
 func (w *vrfWorker) Start() error {
@@ -75,6 +98,20 @@ func (w *vrfWorker) worker() {
 	}
 	defer eventSub.Close()
 
+	// Subscribe to VRF participation events of this node.
+	participationCh, participationSub, err := w.backend.WatchParticipation(
+		w.parent.ctx,
+		[]signature.PublicKey{w.parent.identity.NodeSigner.Public()},
+		config.GlobalConfig.Beacon.VRFProofWarningFraction,
+	)
+	if err != nil {
+		w.logger.Error("failed to subscribe to VRF participation events",
+			"err", err,
+		)
+		return
+	}
+	defer participationSub.Close()
+
 	// Subscribe to block height events.
 	blockCh, blockSub, err := w.parent.consensus.Core().WatchBlocks(w.parent.ctx)
 	if err != nil {
@@ -100,6 +137,9 @@ func (w *vrfWorker) worker() {
 				"submit_after", ev.SubmitAfter,
 			)
 			w.txRetry.Cancel()
+		case pev := <-participationCh:
+			w.updateParticipation(pev)
+			continue
 		case blk := <-blockCh:
 			height = blk.Height
 		}
@@ -166,6 +206,38 @@ func (w *vrfWorker) worker() {
 	}
 }
 
+func (w *vrfWorker) updateParticipation(ev *beacon.VRFParticipationEvent) {
+	if ev.Warning {
+		w.logger.Warn("VRF proof not submitted in time",
+			"epoch", ev.Epoch,
+			"height", ev.Height,
+		)
+		vrfProofWarning.Set(1)
+	} else {
+		w.logger.Info("VRF proof warning cleared",
+			"epoch", ev.Epoch,
+			"height", ev.Height,
+			"submitted", ev.Submitted,
+		)
+		vrfProofWarning.Set(0)
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	w.status.VRFProofWarning = ev.Warning
+	w.status.VRFProofWarningEpoch = ev.Epoch
+}
+
+// GetStatus returns the VRF worker status.
+func (w *vrfWorker) GetStatus() *control.BeaconStatus {
+	w.RLock()
+	defer w.RUnlock()
+
+	status := w.status
+	return &status
+}
+
 func (w *vrfWorker) retrySubmitTx(tx *transaction.Transaction, epoch beacon.EpochTime) {
 	checkFn := func(ctx context.Context) error {
 		// Query state to make sure submitting the tx is still sensible.
@@ -204,5 +276,9 @@ func newVRF(parent *Worker) (*vrfWorker, error) {
 	}
 	w.txRetry = newTxRetry(w.logger, parent.consensus, parent.identity)
 
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(vrfCollectors...)
+	})
+
 	return w, nil
 }
@@ -8,16 +8,20 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/eapache/channels"
+	"golang.org/x/time/rate"
 
 	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/config"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/bundle/component"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
@@ -46,6 +50,8 @@ type Node struct {
 
 	txCh *channels.InfiniteChannel
 
+	checkTxLimiter *rate.Limiter
+
 	logger *logging.Logger
 }
 
@@ -86,8 +92,38 @@ func (n *Node) HandleNewBlockEarlyLocked(*runtime.BlockInfo) {
 }
 
 // HandleNewBlockLocked is guarded by CrossNode.
-func (n *Node) HandleNewBlockLocked(*runtime.BlockInfo) {
-	// Nothing to do here.
+func (n *Node) HandleNewBlockLocked(bi *runtime.BlockInfo) {
+	// Apply settings of the active deployment.
+	n.updateCheckTxLimiterLocked(bi)
+}
+
+// updateCheckTxLimiterLocked applies the transaction check rate limit of the runtime deployment
+// that is active for the given block.
+//
+// Guarded by CrossNode.
+func (n *Node) updateCheckTxLimiterLocked(bi *runtime.BlockInfo) {
+	if bi.ActiveDescriptor == nil {
+		return
+	}
+
+	cfg := runtimeRegistry.NewDeploymentFeatures(bi.ActiveDescriptor, bi.Epoch).CheckTxConfig()
+	limit, burst := checkTxLimit(cfg)
+	if n.checkTxLimiter.Limit() == limit && n.checkTxLimiter.Burst() == burst {
+		return
+	}
+	n.checkTxLimiter.SetLimit(limit)
+	n.checkTxLimiter.SetBurst(burst)
+
+	n.logger.Info("transaction check rate limit updated",
+		"epoch", bi.Epoch,
+		"rate_limit", cfg.RateLimit,
+		"burst", cfg.Burst,
+	)
+}
+
+// AllowCheckTx returns true iff a transaction check is permitted by the rate limit.
+func (n *Node) AllowCheckTx() bool {
+	return n.checkTxLimiter.Allow()
 }
 
 // HandleRuntimeHostEventLocked is guarded by CrossNode.
@@ -168,10 +204,68 @@ func (n *Node) SubmitTx(ctx context.Context, tx []byte) (*SubmitTxSubscription,
 	return sub, nil, nil
 }
 
+// SubmitTxBatch submits the given transactions to the transaction pool in a single operation and
+// waits for them to get checked, returning the check result of each transaction.
+func (n *Node) SubmitTxBatch(ctx context.Context, txs [][]byte) ([]*protocol.CheckTxResult, error) {
+	// Make sure consensus is synced.
+	select {
+	case <-n.commonNode.Consensus.Synced():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		return nil, api.ErrNotSynced
+	}
+
+	return n.commonNode.TxPool.SubmitTxBatch(ctx, txs, &txpool.TransactionMeta{Local: true})
+}
+
 func (n *Node) CheckTx(ctx context.Context, tx []byte) (*protocol.CheckTxResult, error) {
 	return n.commonNode.TxPool.SubmitTx(ctx, tx, &txpool.TransactionMeta{Local: true, Discard: true})
 }
 
+// CheckTxMeta checks the given transaction against the state of the latest round without
+// submitting it to the transaction pool.
+func (n *Node) CheckTxMeta(ctx context.Context, tx []byte) (*api.CheckTxMetaResponse, error) {
+	hrt := n.commonNode.GetHostedRuntime()
+
+	// Fetch the active descriptor so we can get the current message limits.
+	n.commonNode.CrossNode.Lock()
+	dsc := n.commonNode.CurrentDescriptor
+	blk := n.commonNode.CurrentBlock
+	n.commonNode.CrossNode.Unlock()
+
+	if dsc == nil || blk == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+	maxMessages := dsc.Executor.MaxMessages
+
+	annBlk, err := n.commonNode.Runtime.History().GetAnnotatedBlock(ctx, api.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to fetch annotated block from history: %w", err)
+	}
+
+	lb, err := n.commonNode.Consensus.Core().GetLightBlock(ctx, annBlk.Height)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to get light block at height %d: %w", annBlk.Height, err)
+	}
+	epoch, err := n.commonNode.Consensus.Beacon().GetEpoch(ctx, annBlk.Height)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to get epoch at height %d: %w", annBlk.Height, err)
+	}
+
+	rt := host.NewRichRuntime(hrt)
+	results, err := rt.CheckTx(ctx, annBlk.Block, lb, epoch, maxMessages, transaction.RawBatch{tx})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.CheckTxMetaResponse{
+		Round:  annBlk.Block.Header.Round,
+		Height: annBlk.Height,
+		Result: &results[0],
+	}, nil
+}
+
 func (n *Node) Query(ctx context.Context, round uint64, method string, args []byte, comp *component.ID) ([]byte, error) {
 	hrt := n.commonNode.GetHostedRuntime()
 
@@ -378,5 +472,15 @@ func NewNode(commonNode *committee.Node, roleProvider registration.RoleProvider)
 		txCh:         channels.NewInfiniteChannel(),
 		logger:       logging.GetLogger("worker/client/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
+	n.checkTxLimiter = rate.NewLimiter(checkTxLimit(config.GlobalConfig.Runtime.CheckTx))
+
 	return n, nil
 }
+
+// checkTxLimit returns the rate limiter parameters for the given transaction check configuration.
+func checkTxLimit(cfg rtConfig.CheckTxConfig) (rate.Limit, int) {
+	if cfg.RateLimit <= 0 {
+		return rate.Inf, 0
+	}
+	return rate.Limit(cfg.RateLimit), int(cfg.Burst)
+}
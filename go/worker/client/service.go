@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
@@ -18,8 +19,13 @@ import (
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 	"github.com/oasisprotocol/oasis-core/go/worker/client/committee"
+	"github.com/oasisprotocol/oasis-core/go/worker/client/tagindex"
 )
 
+// eventSubscriptionBacklog is the maximum number of matched runtime events that are buffered
+// for a subscriber before the subscription is dropped.
+const eventSubscriptionBacklog = 1024
+
 type service struct {
 	w *Worker
 }
@@ -71,11 +77,43 @@ func (s *service) SubmitTxMeta(ctx context.Context, request *api.SubmitTxRequest
 			if !ok {
 				return nil, fmt.Errorf("client: channel closed unexpectedly")
 			}
-			return resp.Result, resp.Error
+			if resp.Error != nil || !request.Prove {
+				return resp.Result, resp.Error
+			}
+			if err = s.proveInclusion(ctx, request, resp.Result); err != nil {
+				return nil, err
+			}
+			return resp.Result, nil
 		}
 	}
 }
 
+// proveInclusion populates the given SubmitTxMeta response with the header of the block in which
+// the transaction was executed and with a proof of inclusion of its artifacts.
+func (s *service) proveInclusion(ctx context.Context, request *api.SubmitTxRequest, meta *api.SubmitTxMetaResponse) error {
+	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.RuntimeID)
+	if err != nil {
+		return err
+	}
+
+	blk, err := rt.History().GetBlock(ctx, meta.Round)
+	if err != nil {
+		return fmt.Errorf("client: failed to fetch block for round %d: %w", meta.Round, err)
+	}
+
+	tree := s.getTxnTree(rt.Storage(), blk)
+	defer tree.Close()
+
+	proof, err := tree.GetInclusionProof(ctx, hash.NewFromBytes(request.Data))
+	if err != nil {
+		return err
+	}
+
+	meta.Header = &blk.Header
+	meta.Proof = proof
+	return nil
+}
+
 // Implements api.RuntimeClient.
 func (s *service) SubmitTxNoWait(ctx context.Context, request *api.SubmitTxRequest) error {
 	sub, checkTxErr, err := s.submitTx(ctx, request)
@@ -89,12 +127,35 @@ func (s *service) SubmitTxNoWait(ctx context.Context, request *api.SubmitTxReque
 	return nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) SubmitTxBatch(ctx context.Context, request *api.SubmitTxBatchRequest) (*api.SubmitTxBatchResponse, error) {
+	if err := request.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+
+	results, err := rt.SubmitTxBatch(ctx, request.Txs)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SubmitTxBatchResponse{
+		Results: results,
+	}, nil
+}
+
 // Implements api.RuntimeClient.
 func (s *service) CheckTx(ctx context.Context, request *api.CheckTxRequest) error {
 	rt := s.w.runtimes[request.RuntimeID]
 	if rt == nil {
 		return api.ErrNoHostedRuntime
 	}
+	if !rt.AllowCheckTx() {
+		return api.ErrRateLimited
+	}
 
 	resp, err := rt.CheckTx(ctx, request.Data)
 	if err != nil {
@@ -107,6 +168,19 @@ func (s *service) CheckTx(ctx context.Context, request *api.CheckTxRequest) erro
 	return nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) CheckTxMeta(ctx context.Context, request *api.CheckTxRequest) (*api.CheckTxMetaResponse, error) {
+	rt := s.w.runtimes[request.RuntimeID]
+	if rt == nil {
+		return nil, api.ErrNoHostedRuntime
+	}
+	if !rt.AllowCheckTx() {
+		return nil, api.ErrRateLimited
+	}
+
+	return rt.CheckTxMeta(ctx, request.Data)
+}
+
 // Implements api.RuntimeClient.
 func (s *service) WatchBlocks(_ context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
 	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(runtimeID)
@@ -160,6 +234,16 @@ func (s *service) GetLastRetainedBlock(ctx context.Context, runtimeID common.Nam
 	return blk, nil
 }
 
+// Implements api.RuntimeClient.
+func (s *service) QueryTxsByTag(ctx context.Context, request *api.QueryTxsByTagRequest) (*api.QueryTxsByTagResponse, error) {
+	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(request.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tagindex.Query(ctx, rt.History(), rt.Storage(), s.w.tagIndexes[request.RuntimeID], request)
+}
+
 func (s *service) getTxnTree(backend storage.Backend, blk *block.Block) *transaction.Tree {
 	ioRoot := storage.Root{
 		Namespace: blk.Header.Namespace,
@@ -292,6 +376,116 @@ func (s *service) GetEvents(ctx context.Context, request *api.GetEventsRequest)
 	return events, nil
 }
 
+type eventSubscription struct {
+	sync.Mutex
+
+	pubsub.ClosableSubscription
+
+	err error
+}
+
+// Implements api.EventSubscription.
+func (s *eventSubscription) Err() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.err
+}
+
+func (s *eventSubscription) setErr(err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.err = err
+}
+
+// Implements api.RuntimeClient.
+func (s *service) WatchRuntimeEvents(ctx context.Context, runtimeID common.Namespace, filter *api.EventFilter) (<-chan *api.RuntimeEvent, api.EventSubscription, error) {
+	rt, err := s.w.commonWorker.RuntimeRegistry.GetRuntime(runtimeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blkCh, blkSub, err := rt.History().WatchBlocks()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, csub := pubsub.NewContextSubscription(ctx)
+	sub := &eventSubscription{ClosableSubscription: csub}
+	ch := make(chan *api.RuntimeEvent, eventSubscriptionBacklog)
+
+	go func() {
+		defer close(ch)
+		defer blkSub.Close()
+
+		for {
+			var (
+				annBlk *roothash.AnnotatedBlock
+				ok     bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case annBlk, ok = <-blkCh:
+				if !ok {
+					return
+				}
+			}
+
+			if err := s.sendRuntimeEvents(ctx, rt.Storage(), annBlk.Block, filter, ch); err != nil {
+				s.w.logger.Debug("dropping runtime event subscription",
+					"runtime_id", runtimeID,
+					"round", annBlk.Block.Header.Round,
+					"err", err,
+				)
+				sub.setErr(err)
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (s *service) sendRuntimeEvents(
+	ctx context.Context,
+	backend storage.Backend,
+	blk *block.Block,
+	filter *api.EventFilter,
+	ch chan<- *api.RuntimeEvent,
+) error {
+	if blk.Header.IORoot.IsEmpty() {
+		return nil
+	}
+
+	tree := s.getTxnTree(backend, blk)
+	defer tree.Close()
+
+	tags, err := tree.GetTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if !filter.Matches(tag.Key, tag.Value) {
+			continue
+		}
+
+		select {
+		case ch <- &api.RuntimeEvent{
+			Round:  blk.Header.Round,
+			Key:    tag.Key,
+			Value:  tag.Value,
+			TxHash: tag.TxHash,
+		}:
+		default:
+			// Drop the subscription instead of buffering events for a slow subscriber.
+			return api.ErrEventSubscriptionLagging
+		}
+	}
+	return nil
+}
+
 // Implements api.RuntimeClient.
 func (s *service) Query(ctx context.Context, request *api.QueryRequest) (*api.QueryResponse, error) {
 	rt := s.w.runtimes[request.RuntimeID]
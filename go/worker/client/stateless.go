@@ -42,6 +42,10 @@ func (s *statelessStorage) GetCheckpointChunk(context.Context, *checkpoint.Chunk
 	return storage.ErrUnsupported
 }
 
+func (s *statelessStorage) GetCheckpointChunkInfo(context.Context, *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	return nil, storage.ErrUnsupported
+}
+
 func (s *statelessStorage) Cleanup() {
 }
 
@@ -0,0 +1,240 @@
+package tagindex
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+// Query returns transactions that emitted the given tag in the requested round range.
+//
+// Rounds covered by the given indexer (which may be nil) are served from the index while all
+// other rounds are served by scanning the transaction trees.
+func Query(
+	ctx context.Context,
+	history roothash.BlockHistory,
+	backend storage.Backend,
+	ix *Indexer,
+	request *api.QueryTxsByTagRequest,
+) (*api.QueryTxsByTagResponse, error) {
+	if err := request.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	limit := int(request.Limit)
+	if limit == 0 {
+		limit = api.DefaultQueryTxsByTagLimit
+	}
+
+	start, end := request.StartRound, request.EndRound
+	if end == api.RoundLatest {
+		blk, err := history.GetBlock(ctx, roothash.RoundLatest)
+		if err != nil {
+			return nil, err
+		}
+		end = blk.Header.Round
+	}
+	earliest, err := earliestRound(ctx, history, backend)
+	if err != nil {
+		return nil, err
+	}
+	start = max(start, earliest)
+	if request.After != nil {
+		start = max(start, request.After.Round)
+	}
+
+	var txs []*api.TaggedTransaction
+	for round := start; round <= end && len(txs) < limit; {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if ix != nil {
+			found, last, ok, err := ix.lookup(request.Key, request.Value, round, end, request.After, limit-len(txs))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				fetched, err := fetchTransactions(ctx, history, backend, found)
+				if err != nil {
+					return nil, err
+				}
+				txs = append(txs, fetched...)
+				round = last + 1
+				continue
+			}
+		}
+
+		matched, err := scanRound(ctx, history, backend, round, request.Key, request.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range matched {
+			if request.After != nil && !request.After.Less(&tx.TxPosition) {
+				continue
+			}
+			txs = append(txs, tx)
+			if len(txs) >= limit {
+				break
+			}
+		}
+		round++
+	}
+
+	rsp := &api.QueryTxsByTagResponse{
+		Txs: txs,
+	}
+	if len(txs) >= limit {
+		next := txs[len(txs)-1].TxPosition
+		rsp.Next = &next
+	}
+	return rsp, nil
+}
+
+// scanRound returns all transactions in the given round that emitted the given tag, ordered by
+// their position in the batch.
+func scanRound(
+	ctx context.Context,
+	history roothash.BlockHistory,
+	backend storage.Backend,
+	round uint64,
+	key []byte,
+	value []byte,
+) ([]*api.TaggedTransaction, error) {
+	blk, err := history.GetBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("tagindex: failed to get block %d: %w", round, err)
+	}
+	if blk.Header.IORoot.IsEmpty() {
+		return nil, nil
+	}
+
+	tree := newTxTree(backend, blk)
+	defer tree.Close()
+
+	tags, err := tree.GetTag(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("tagindex: failed to get tags of round %d: %w", round, err)
+	}
+	var txHashes []hash.Hash
+	for _, tag := range tags {
+		if bytes.Equal(tag.Value, value) {
+			txHashes = append(txHashes, tag.TxHash)
+		}
+	}
+
+	txs, err := getTransactions(ctx, tree, round, txHashes)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].BatchOrder < txs[j].BatchOrder
+	})
+	return txs, nil
+}
+
+// fetchTransactions returns the given indexed transactions, preserving their order.
+func fetchTransactions(
+	ctx context.Context,
+	history roothash.BlockHistory,
+	backend storage.Backend,
+	found []*indexedTx,
+) ([]*api.TaggedTransaction, error) {
+	txs := make([]*api.TaggedTransaction, 0, len(found))
+	for len(found) > 0 {
+		// Transactions are ordered by round, so fetch them one round at a time.
+		round := found[0].Round
+		n := 1
+		for n < len(found) && found[n].Round == round {
+			n++
+		}
+
+		blk, err := history.GetBlock(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("tagindex: failed to get block %d: %w", round, err)
+		}
+		tree := newTxTree(backend, blk)
+		txHashes := make([]hash.Hash, 0, n)
+		for _, tx := range found[:n] {
+			txHashes = append(txHashes, tx.txHash)
+		}
+		fetched, err := getTransactions(ctx, tree, round, txHashes)
+		tree.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, fetched...)
+		found = found[n:]
+	}
+	return txs, nil
+}
+
+// getTransactions returns the transactions with the given hashes in the given order.
+func getTransactions(
+	ctx context.Context,
+	tree *transaction.Tree,
+	round uint64,
+	txHashes []hash.Hash,
+) ([]*api.TaggedTransaction, error) {
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+
+	txs, err := tree.GetTransactionMultiple(ctx, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("tagindex: failed to get transactions of round %d: %w", round, err)
+	}
+
+	result := make([]*api.TaggedTransaction, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		tx, ok := txs[txHash]
+		if !ok {
+			return nil, fmt.Errorf("tagindex: missing transaction %s in round %d", txHash, round)
+		}
+		result = append(result, &api.TaggedTransaction{
+			TxPosition: api.TxPosition{
+				Round:      round,
+				BatchOrder: tx.BatchOrder,
+			},
+			TxHash: txHash,
+			Tx:     tx.Input,
+			Result: tx.Output,
+		})
+	}
+	return result, nil
+}
+
+// earliestRound returns the earliest round for which both the block and the transactions are
+// available.
+func earliestRound(ctx context.Context, history roothash.BlockHistory, backend storage.Backend) (uint64, error) {
+	blk, err := history.GetEarliestBlock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("tagindex: failed to get earliest block: %w", err)
+	}
+	round := blk.Header.Round
+
+	// Stateful nodes may only have a later checkpoint available.
+	if lsb, ok := backend.(storage.LocalBackend); ok {
+		round = max(round, lsb.NodeDB().GetEarliestVersion())
+	}
+	return round, nil
+}
+
+func newTxTree(backend storage.Backend, blk *block.Block) *transaction.Tree {
+	ioRoot := storage.Root{
+		Namespace: blk.Header.Namespace,
+		Version:   blk.Header.Round,
+		Type:      storage.RootTypeIO,
+		Hash:      blk.Header.IORoot,
+	}
+
+	return transaction.NewTree(backend, ioRoot)
+}
@@ -0,0 +1,556 @@
+// Package tagindex implements a secondary index of runtime transaction tags over recent rounds
+// which makes tag lookups on client nodes fast.
+package tagindex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	cmnBackoff "github.com/oasisprotocol/oasis-core/go/common/backoff"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	cmSync "github.com/oasisprotocol/oasis-core/go/common/sync"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+)
+
+// persistentStoreName is the name of the persistent store namespace prefix used for storing
+// the tag index.
+const persistentStoreName = "runtime/client/tagindex"
+
+const (
+	statusStarted    = "started"
+	statusRebuilding = "rebuilding"
+	statusIndexing   = "indexing"
+	statusStopped    = "stopped"
+)
+
+var (
+	// entryKeyFmt is the key format used for index entries.
+	//
+	// Key format is: 0x01 <H(tag key, tag value) (hash.Hash)> <round (uint64)> <batch order (uint32)>
+	// Value is the CBOR-serialized hash of the transaction that emitted the tag.
+	entryKeyFmt = keyformat.New(0x01, &hash.Hash{}, uint64(0), uint32(0))
+	// roundKeyFmt is the key format used for the list of index entries added in a round, which
+	// is needed for pruning.
+	//
+	// Key format is: 0x02 <round (uint64)>
+	// Value is the CBOR-serialized list of index entry keys.
+	roundKeyFmt = keyformat.New(0x02, uint64(0))
+	// metaKeyFmt is the key format used for index metadata.
+	//
+	// Value is the CBOR-serialized index metadata.
+	metaKeyFmt = keyformat.New(0x03)
+
+	errStopIteration = errors.New("stop iteration")
+)
+
+// indexMeta is the persisted index metadata.
+type indexMeta struct {
+	// Keys are the indexed tag keys.
+	Keys [][]byte `json:"keys"`
+	// FirstRound is the first indexed round.
+	FirstRound uint64 `json:"first_round"`
+	// LastRound is the last indexed round.
+	LastRound uint64 `json:"last_round"`
+}
+
+// indexedTx is a transaction found in the index.
+type indexedTx struct {
+	api.TxPosition
+
+	txHash hash.Hash
+}
+
+// Indexer maintains an index of runtime transaction tags with the configured keys over
+// a contiguous range of the most recent rounds.
+//
+// Rounds are indexed as they are finalized. In case the index falls behind (e.g., due to node
+// downtime), the missing rounds are indexed in the background.
+type Indexer struct {
+	mu       sync.RWMutex
+	startOne cmSync.One
+
+	history   roothash.BlockHistory
+	storage   storage.Backend
+	store     *persistent.ServiceStore
+	keys      [][]byte
+	retention uint64
+
+	status  string
+	meta    *indexMeta
+	rebuild *api.TagIndexRebuildStatus
+
+	logger *logging.Logger
+}
+
+// New creates a new tag indexer for the runtime with the given block history and storage.
+//
+// Only tags with the given keys are indexed and only the given number of most recent rounds
+// are kept in the index.
+func New(
+	history roothash.BlockHistory,
+	backend storage.Backend,
+	commonStore *persistent.CommonStore,
+	keys [][]byte,
+	retention uint64,
+) (*Indexer, error) {
+	runtimeID := history.RuntimeID()
+
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, bytes.Compare)
+	keys = slices.CompactFunc(keys, bytes.Equal)
+
+	ix := &Indexer{
+		startOne:  cmSync.NewOne(),
+		history:   history,
+		storage:   backend,
+		store:     commonStore.GetServiceStore(persistentStoreName + "/" + runtimeID.String()),
+		keys:      keys,
+		retention: retention,
+		logger:    logging.GetLogger("worker/client/tagindex").With("runtime_id", runtimeID),
+	}
+
+	var meta indexMeta
+	switch err := ix.store.GetCBOR(metaKeyFmt.Encode(), &meta); err {
+	case nil:
+		ix.meta = &meta
+	case persistent.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("tagindex: failed to load index metadata: %w", err)
+	}
+
+	// Start from scratch in case the indexed keys have changed.
+	if ix.meta != nil && !slices.EqualFunc(ix.meta.Keys, ix.keys, bytes.Equal) {
+		ix.logger.Info("indexed tag keys changed, clearing index")
+
+		if err := ix.clearLocked(); err != nil {
+			return nil, fmt.Errorf("tagindex: failed to clear index: %w", err)
+		}
+	}
+
+	return ix, nil
+}
+
+// Start starts the indexer.
+func (ix *Indexer) Start() {
+	ix.startOne.TryStart(ix.run)
+}
+
+// Stop halts the indexer.
+func (ix *Indexer) Stop() {
+	ix.startOne.TryStop()
+}
+
+// Status returns the tag index status.
+func (ix *Indexer) Status() *api.TagIndexStatus {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	status := &api.TagIndexStatus{
+		Status: ix.status,
+	}
+	if ix.meta != nil {
+		status.FirstRound = ix.meta.FirstRound
+		status.LastRound = ix.meta.LastRound
+	}
+	if ix.rebuild != nil {
+		rebuild := *ix.rebuild
+		status.Rebuild = &rebuild
+	}
+	return status
+}
+
+func (ix *Indexer) setStatus(status string, rebuild *api.TagIndexRebuildStatus) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.status = status
+	ix.rebuild = rebuild
+}
+
+func (ix *Indexer) run(ctx context.Context) {
+	ix.logger.Info("starting")
+	ix.setStatus(statusStarted, nil)
+
+	defer func() {
+		ix.logger.Info("stopping")
+		ix.setStatus(statusStopped, nil)
+	}()
+
+	select {
+	case <-ix.history.Initialized():
+	case <-ctx.Done():
+		return
+	}
+
+	blkCh, blkSub, err := ix.history.WatchBlocks()
+	if err != nil {
+		ix.logger.Error("failed to watch blocks",
+			"err", err,
+		)
+		return
+	}
+	defer blkSub.Close()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Index rounds in a separate goroutine so that block notifications are always consumed,
+	// even while the index is being rebuilt.
+	roundCh := make(chan uint64, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ix.worker(ctx, roundCh)
+	}()
+
+	// Catch up with the latest round first, rebuilding the index in case of a gap.
+	if blk, err := ix.history.GetBlock(ctx, roothash.RoundLatest); err == nil {
+		sendRound(roundCh, blk.Header.Round)
+	}
+
+	for {
+		select {
+		case annBlk, ok := <-blkCh:
+			if !ok {
+				return
+			}
+			sendRound(roundCh, annBlk.Block.Header.Round)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ix *Indexer) worker(ctx context.Context, roundCh <-chan uint64) {
+	retry := time.Duration(math.MaxInt64)
+	boff := cmnBackoff.NewExponentialBackOff()
+	boff.Reset()
+
+	var (
+		round   uint64
+		pending bool
+	)
+	for {
+		select {
+		case round = <-roundCh:
+			pending = true
+		case <-time.After(retry):
+		case <-ctx.Done():
+			return
+		}
+		if !pending {
+			continue
+		}
+
+		if err := ix.indexTo(ctx, round); err != nil {
+			ix.logger.Warn("failed to index rounds",
+				"err", err,
+				"round", round,
+			)
+			retry = boff.NextBackOff()
+			continue
+		}
+
+		pending = false
+		retry = math.MaxInt64
+		boff.Reset()
+	}
+}
+
+// indexTo indexes all not yet indexed rounds up to and including the given round.
+func (ix *Indexer) indexTo(ctx context.Context, round uint64) error {
+	start, err := ix.nextRound(ctx, round)
+	if err != nil {
+		return err
+	}
+	if start > round {
+		return nil
+	}
+
+	// Indexing more than a single round means that the index needs to catch up.
+	status := statusIndexing
+	var rebuild *api.TagIndexRebuildStatus
+	if start < round {
+		ix.logger.Info("rebuilding index",
+			"start_round", start,
+			"end_round", round,
+		)
+
+		status = statusRebuilding
+		rebuild = &api.TagIndexRebuildStatus{
+			StartRound: start,
+			EndRound:   round,
+		}
+	}
+	ix.setStatus(status, rebuild)
+	defer ix.setStatus(statusIndexing, nil)
+
+	for r := start; r <= round; r++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = ix.indexRound(ctx, r); err != nil {
+			return fmt.Errorf("failed to index round %d: %w", r, err)
+		}
+	}
+	return nil
+}
+
+// nextRound returns the first round that needs to be indexed when indexing up to the given
+// round.
+func (ix *Indexer) nextRound(ctx context.Context, round uint64) (uint64, error) {
+	var next uint64
+
+	ix.mu.RLock()
+	if ix.meta != nil {
+		next = ix.meta.LastRound + 1
+	}
+	ix.mu.RUnlock()
+
+	// Only the most recent rounds are kept in the index.
+	if round+1 > ix.retention {
+		next = max(next, round+1-ix.retention)
+	}
+
+	// Rounds that are no longer available can not be indexed.
+	earliest, err := earliestRound(ctx, ix.history, ix.storage)
+	if err != nil {
+		return 0, err
+	}
+
+	return max(next, earliest), nil
+}
+
+// indexRound indexes the given round.
+func (ix *Indexer) indexRound(ctx context.Context, round uint64) error {
+	blk, err := ix.history.GetBlock(ctx, round)
+	if err != nil {
+		return fmt.Errorf("failed to get block: %w", err)
+	}
+
+	var (
+		keys   [][]byte
+		values []any
+	)
+	if !blk.Header.IORoot.IsEmpty() {
+		tree := newTxTree(ix.storage, blk)
+		defer tree.Close()
+
+		tags, err := tree.GetTagMultiple(ctx, ix.keys)
+		if err != nil {
+			return fmt.Errorf("failed to get tags: %w", err)
+		}
+
+		batchOrders, err := getBatchOrders(ctx, tree, tags)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			h := tagHash(tag.Key, tag.Value)
+			keys = append(keys, entryKeyFmt.Encode(&h, round, batchOrders[tag.TxHash]))
+			values = append(values, tag.TxHash)
+		}
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	// The index covers a contiguous range of rounds, so start from scratch after a gap.
+	if ix.meta != nil && round != ix.meta.LastRound+1 {
+		ix.logger.Info("gap in indexed rounds, clearing index",
+			"last_round", ix.meta.LastRound,
+			"round", round,
+		)
+
+		if err = ix.clearLocked(); err != nil {
+			return fmt.Errorf("failed to clear index: %w", err)
+		}
+	}
+
+	meta := indexMeta{
+		Keys:       ix.keys,
+		FirstRound: round,
+		LastRound:  round,
+	}
+	if ix.meta != nil {
+		meta.FirstRound = ix.meta.FirstRound
+	}
+
+	// Write the metadata last, so that a partially written round is simply indexed again.
+	entryKeys := keys
+	keys = append(keys, roundKeyFmt.Encode(round), metaKeyFmt.Encode())
+	values = append(values, entryKeys, &meta)
+	if err = ix.store.PutManyCBOR(keys, values); err != nil {
+		return fmt.Errorf("failed to store index entries: %w", err)
+	}
+	ix.meta = &meta
+
+	return ix.pruneLocked()
+}
+
+// pruneLocked removes the rounds outside of the retention window from the index.
+func (ix *Indexer) pruneLocked() error {
+	if ix.meta.LastRound+1 <= ix.retention {
+		return nil
+	}
+	first := ix.meta.LastRound + 1 - ix.retention
+	if first <= ix.meta.FirstRound {
+		return nil
+	}
+
+	var keys [][]byte
+	for round := ix.meta.FirstRound; round < first; round++ {
+		var entryKeys [][]byte
+		switch err := ix.store.GetCBOR(roundKeyFmt.Encode(round), &entryKeys); err {
+		case nil:
+		case persistent.ErrNotFound:
+			continue
+		default:
+			return fmt.Errorf("failed to get index entries of round %d: %w", round, err)
+		}
+		keys = append(keys, entryKeys...)
+		keys = append(keys, roundKeyFmt.Encode(round))
+	}
+	if err := ix.store.DeleteMany(keys); err != nil {
+		return fmt.Errorf("failed to prune index entries: %w", err)
+	}
+
+	meta := *ix.meta
+	meta.FirstRound = first
+	if err := ix.store.PutCBOR(metaKeyFmt.Encode(), &meta); err != nil {
+		return fmt.Errorf("failed to store index metadata: %w", err)
+	}
+	ix.meta = &meta
+
+	return nil
+}
+
+// clearLocked removes everything from the index.
+func (ix *Indexer) clearLocked() error {
+	var keys [][]byte
+	err := ix.store.Iterate(func(key, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err = ix.store.DeleteMany(keys); err != nil {
+		return err
+	}
+	ix.meta = nil
+
+	return nil
+}
+
+// lookup returns the positions and hashes of transactions that emitted the given tag in the
+// given round range, ordered by position, skipping the ones at or before the given position and
+// returning at most limit transactions.
+//
+// In case the first round of the range is covered by the index, it returns true and the last
+// round of the range which is covered. Otherwise it returns false.
+func (ix *Indexer) lookup(
+	key []byte,
+	value []byte,
+	from uint64,
+	to uint64,
+	after *api.TxPosition,
+	limit int,
+) ([]*indexedTx, uint64, bool, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	if ix.meta == nil || from < ix.meta.FirstRound || from > ix.meta.LastRound {
+		return nil, 0, false, nil
+	}
+	if _, ok := slices.BinarySearchFunc(ix.keys, key, bytes.Compare); !ok {
+		return nil, 0, false, nil
+	}
+	to = min(to, ix.meta.LastRound)
+
+	var txs []*indexedTx
+	h := tagHash(key, value)
+	err := ix.store.IteratePrefix(entryKeyFmt.Encode(&h), func(rawKey, rawValue []byte) error {
+		var (
+			decHash hash.Hash
+			tx      indexedTx
+		)
+		if !entryKeyFmt.Decode(rawKey, &decHash, &tx.Round, &tx.BatchOrder) {
+			return fmt.Errorf("malformed index entry key")
+		}
+		if tx.Round < from || (after != nil && !after.Less(&tx.TxPosition)) {
+			return nil
+		}
+		if tx.Round > to {
+			return errStopIteration
+		}
+		if err := cbor.Unmarshal(rawValue, &tx.txHash); err != nil {
+			return fmt.Errorf("malformed index entry: %w", err)
+		}
+
+		txs = append(txs, &tx)
+		if len(txs) >= limit {
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return nil, 0, false, fmt.Errorf("tagindex: failed to iterate index: %w", err)
+	}
+
+	return txs, to, true, nil
+}
+
+func tagHash(key, value []byte) hash.Hash {
+	return hash.NewFrom([][]byte{key, value})
+}
+
+func getBatchOrders(ctx context.Context, tree *transaction.Tree, tags transaction.Tags) (map[hash.Hash]uint32, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	txHashes := make([]hash.Hash, 0, len(tags))
+	for _, tag := range tags {
+		txHashes = append(txHashes, tag.TxHash)
+	}
+	txs, err := tree.GetTransactionMultiple(ctx, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	batchOrders := make(map[hash.Hash]uint32, len(txs))
+	for _, tag := range tags {
+		tx, ok := txs[tag.TxHash]
+		if !ok {
+			return nil, fmt.Errorf("missing transaction %s", tag.TxHash)
+		}
+		batchOrders[tag.TxHash] = tx.BatchOrder
+	}
+	return batchOrders, nil
+}
+
+func sendRound(ch chan uint64, round uint64) {
+	// Only the latest round matters, so replace any pending one.
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- round
+}
@@ -0,0 +1,328 @@
+package tagindex
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+)
+
+const (
+	testRetention  = 5
+	testWaitPeriod = 10 * time.Second
+)
+
+var testRuntimeID = common.NewTestNamespaceFromSeed([]byte("tagindex test ns"), 0)
+
+// blockingHistory is a block history that blocks fetching the given round until released.
+type blockingHistory struct {
+	history.History
+
+	round   uint64
+	release chan struct{}
+}
+
+func (h *blockingHistory) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	if round == h.round {
+		<-h.release
+	}
+	return h.History.GetBlock(ctx, round)
+}
+
+func TestTagIndex(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	h, err := history.New(testRuntimeID, dataDir, history.NewNonePrunerFactory(), false)
+	require.NoError(t, err, "history.New")
+	defer h.Close()
+
+	backend, err := database.New(&storage.Config{
+		Backend:      database.BackendNameBadgerDB,
+		DB:           filepath.Join(dataDir, "storage"),
+		Namespace:    testRuntimeID,
+		MaxCacheSize: 16 * 1024 * 1024,
+		NoFsync:      true,
+	})
+	require.NoError(t, err, "database.New")
+	defer backend.Cleanup()
+
+	store, err := persistent.NewCommonStore(filepath.Join(dataDir, "persistent"))
+	require.NoError(t, err, "NewCommonStore")
+	defer store.Close()
+
+	// The genesis block has no transactions.
+	err = h.Commit([]*roothash.AnnotatedBlock{{
+		Height: 1,
+		Block:  block.NewGenesisBlock(testRuntimeID, 0),
+	}})
+	require.NoError(t, err, "Commit")
+	require.NoError(t, h.SetInitialized(), "SetInitialized")
+
+	// Adds the given number of rounds with transactions emitting a mix of tags.
+	addRounds := func(t *testing.T, n int) {
+		require := require.New(t)
+
+		ctx := context.Background()
+
+		latest, err := h.GetBlock(ctx, roothash.RoundLatest)
+		require.NoError(err, "GetBlock")
+
+		for i := 0; i < n; i++ {
+			round := latest.Header.Round + 1 + uint64(i)
+
+			var emptyRoot hash.Hash
+			emptyRoot.Empty()
+			ioRoot := storage.Root{
+				Namespace: testRuntimeID,
+				Version:   round,
+				Type:      storage.RootTypeIO,
+				Hash:      emptyRoot,
+			}
+			tree := transaction.NewTree(nil, ioRoot)
+
+			// Every third round is empty, others contain a round-dependent number of transactions.
+			numTxs := 0
+			if round%3 != 0 {
+				numTxs = int(round%4) + 2
+			}
+			for j := 0; j < numTxs; j++ {
+				tx := transaction.Transaction{
+					Input:      []byte(fmt.Sprintf("tx %d/%d", round, j)),
+					Output:     []byte(fmt.Sprintf("result %d/%d", round, j)),
+					BatchOrder: uint32(numTxs - j - 1),
+				}
+				tags := transaction.Tags{
+					{Key: []byte("indexed"), Value: []byte(fmt.Sprintf("value%d", j%2))},
+					{Key: []byte("unindexed"), Value: []byte("value0")},
+				}
+				require.NoError(tree.AddTransaction(ctx, tx, tags), "AddTransaction")
+			}
+			writeLog, rootHash, err := tree.Commit(ctx)
+			require.NoError(err, "Commit")
+			tree.Close()
+
+			err = backend.Apply(ctx, &storage.ApplyRequest{
+				Namespace: testRuntimeID,
+				RootType:  storage.RootTypeIO,
+				SrcRound:  round,
+				SrcRoot:   emptyRoot,
+				DstRound:  round,
+				DstRoot:   rootHash,
+				WriteLog:  writeLog,
+			})
+			require.NoError(err, "Apply")
+
+			blk := block.NewGenesisBlock(testRuntimeID, 0)
+			blk.Header.Round = round
+			blk.Header.IORoot = rootHash
+			err = h.Commit([]*roothash.AnnotatedBlock{{
+				Height: int64(round) + 1,
+				Block:  blk,
+			}})
+			require.NoError(err, "Commit")
+		}
+	}
+
+	newIndexer := func(t *testing.T, store *persistent.CommonStore) *Indexer {
+		ix, err := New(h, backend, store, [][]byte{[]byte("indexed")}, testRetention)
+		require.NoError(t, err, "New")
+		return ix
+	}
+
+	// Fetches all pages of the given query.
+	queryAll := func(t *testing.T, ix *Indexer, req api.QueryTxsByTagRequest) []*api.TaggedTransaction {
+		ctx := context.Background()
+
+		var txs []*api.TaggedTransaction
+		for {
+			rsp, err := Query(ctx, h, backend, ix, &req)
+			require.NoError(t, err, "Query")
+			require.True(t, req.Limit == 0 || len(rsp.Txs) <= int(req.Limit), "page should not exceed limit")
+
+			txs = append(txs, rsp.Txs...)
+			if rsp.Next == nil {
+				return txs
+			}
+			req.After = rsp.Next
+		}
+	}
+
+	waitIndexed := func(t *testing.T, ix *Indexer, round uint64) *api.TagIndexStatus {
+		var status *api.TagIndexStatus
+		require.Eventually(t, func() bool {
+			status = ix.Status()
+			return status.Status == statusIndexing && status.LastRound == round
+		}, testWaitPeriod, 10*time.Millisecond, "indexer should index all rounds")
+		return status
+	}
+
+	requireEquivalent := func(t *testing.T, ix *Indexer) {
+		for _, tc := range []struct {
+			key, value string
+			start, end uint64
+		}{
+			{"indexed", "value0", 0, api.RoundLatest},
+			{"indexed", "value1", 0, api.RoundLatest},
+			{"indexed", "value0", 4, 9},
+			{"indexed", "value1", 9, 12},
+			{"indexed", "value0", 12, api.RoundLatest},
+			{"indexed", "missing", 0, api.RoundLatest},
+			{"unindexed", "value0", 0, api.RoundLatest},
+			{"missing", "value0", 0, api.RoundLatest},
+		} {
+			for _, limit := range []uint64{0, 1, 2, 3, 7} {
+				req := api.QueryTxsByTagRequest{
+					RuntimeID:  testRuntimeID,
+					Key:        []byte(tc.key),
+					Value:      []byte(tc.value),
+					StartRound: tc.start,
+					EndRound:   tc.end,
+					Limit:      limit,
+				}
+				desc := fmt.Sprintf("%s=%s rounds %d-%d limit %d", tc.key, tc.value, tc.start, tc.end, limit)
+
+				scanned := queryAll(t, nil, req)
+				indexed := queryAll(t, ix, req)
+				require.Equal(t, scanned, indexed, "index should be equivalent to scanning (%s)", desc)
+
+				for i := 1; i < len(scanned); i++ {
+					require.True(t, scanned[i-1].Less(&scanned[i].TxPosition), "results should be ordered (%s)", desc)
+				}
+			}
+		}
+	}
+
+	t.Run("Index", func(t *testing.T) {
+		require := require.New(t)
+
+		addRounds(t, 15)
+
+		ix := newIndexer(t, store)
+		ix.Start()
+
+		// Only the most recent rounds should be indexed.
+		status := waitIndexed(t, ix, 15)
+		require.EqualValues(15-testRetention+1, status.FirstRound)
+		require.Nil(status.Rebuild)
+
+		// Sanity check that the index is actually used and contains the expected data.
+		found, last, ok, err := ix.lookup([]byte("indexed"), []byte("value1"), 11, 15, nil, 100)
+		require.NoError(err, "lookup")
+		require.True(ok, "rounds should be covered by the index")
+		require.EqualValues(15, last)
+		require.NotEmpty(found)
+		_, _, ok, err = ix.lookup([]byte("indexed"), []byte("value1"), 10, 15, nil, 100)
+		require.NoError(err, "lookup")
+		require.False(ok, "pruned rounds should not be covered by the index")
+		_, _, ok, err = ix.lookup([]byte("unindexed"), []byte("value0"), 11, 15, nil, 100)
+		require.NoError(err, "lookup")
+		require.False(ok, "unindexed keys should not be covered by the index")
+
+		requireEquivalent(t, ix)
+
+		// New rounds should be indexed as they are finalized.
+		addRounds(t, 2)
+		status = waitIndexed(t, ix, 17)
+		require.EqualValues(17-testRetention+1, status.FirstRound)
+		requireEquivalent(t, ix)
+
+		// Pruned rounds should be removed from the index.
+		var rounds []uint64
+		err = ix.store.Iterate(func(key, _ []byte) error {
+			var round uint64
+			if roundKeyFmt.Decode(key, &round) {
+				rounds = append(rounds, round)
+			}
+			return nil
+		})
+		require.NoError(err, "Iterate")
+		require.Equal([]uint64{13, 14, 15, 16, 17}, rounds)
+
+		// Simulate node downtime which causes a gap that needs to be rebuilt in the background.
+		ix.Stop()
+		require.Equal(statusStopped, ix.Status().Status)
+		addRounds(t, 8)
+
+		ix = newIndexer(t, store)
+		status = ix.Status()
+		require.EqualValues(17, status.LastRound, "index should be loaded from the persistent store")
+		ix.Start()
+		defer ix.Stop()
+
+		status = waitIndexed(t, ix, 25)
+		require.EqualValues(25-testRetention+1, status.FirstRound)
+		requireEquivalent(t, ix)
+
+		// Invalid requests should be rejected.
+		_, err = Query(ctx, h, backend, ix, &api.QueryTxsByTagRequest{
+			RuntimeID:  testRuntimeID,
+			StartRound: 10,
+			EndRound:   5,
+		})
+		require.ErrorIs(err, api.ErrInvalidArgument)
+		_, err = Query(ctx, h, backend, ix, &api.QueryTxsByTagRequest{
+			RuntimeID: testRuntimeID,
+			EndRound:  api.RoundLatest,
+			Limit:     api.MaxQueryTxsByTagLimit + 1,
+		})
+		require.ErrorIs(err, api.ErrInvalidArgument)
+	})
+
+	t.Run("RebuildStatus", func(t *testing.T) {
+		require := require.New(t)
+
+		// Rebuild a fresh index over the existing rounds.
+		store, err := persistent.NewCommonStore(t.TempDir())
+		require.NoError(err, "NewCommonStore")
+		defer store.Close()
+
+		ix := newIndexer(t, store)
+		require.NoError(ix.indexTo(ctx, 1), "indexTo")
+		require.Equal(statusIndexing, ix.Status().Status)
+
+		// Pause catching up in the middle, so that progress can be observed.
+		bh := &blockingHistory{
+			History: h,
+			round:   3,
+			release: make(chan struct{}),
+		}
+		ix.history = bh
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- ix.indexTo(ctx, 4)
+		}()
+
+		var status *api.TagIndexStatus
+		require.Eventually(func() bool {
+			status = ix.Status()
+			return status.LastRound == 2
+		}, testWaitPeriod, 10*time.Millisecond, "indexer should make progress")
+		require.Equal(statusRebuilding, status.Status)
+		require.Equal(&api.TagIndexRebuildStatus{StartRound: 2, EndRound: 4}, status.Rebuild)
+
+		close(bh.release)
+		require.NoError(<-errCh, "indexTo")
+
+		status = ix.Status()
+		require.Equal(statusIndexing, status.Status)
+		require.EqualValues(0, status.FirstRound)
+		require.EqualValues(4, status.LastRound)
+		require.Nil(status.Rebuild)
+	})
+}
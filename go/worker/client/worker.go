@@ -11,6 +11,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/config"
 	"github.com/oasisprotocol/oasis-core/go/runtime/client/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/client/committee"
+	"github.com/oasisprotocol/oasis-core/go/worker/client/tagindex"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	committeeCommon "github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
@@ -23,7 +24,8 @@ type Worker struct {
 	commonWorker *workerCommon.Worker
 	registration *registration.Worker
 
-	runtimes map[common.Namespace]*committee.Node
+	runtimes   map[common.Namespace]*committee.Node
+	tagIndexes map[common.Namespace]*tagindex.Indexer
 
 	quitCh chan struct{}
 	initCh chan struct{}
@@ -76,6 +78,11 @@ func (w *Worker) Start() error {
 		}
 	}
 
+	// Start transaction tag indexers.
+	for _, ix := range w.tagIndexes {
+		ix.Start()
+	}
+
 	return nil
 }
 
@@ -86,6 +93,10 @@ func (w *Worker) Stop() {
 		return
 	}
 
+	for _, ix := range w.tagIndexes {
+		ix.Stop()
+	}
+
 	for id, rt := range w.runtimes {
 		w.logger.Info("stopping services for runtime",
 			"runtime_id", id,
@@ -116,6 +127,16 @@ func (w *Worker) Cleanup() {
 	}
 }
 
+// TagIndexStatus returns the status of the transaction tag index of the given runtime, if the
+// index is enabled.
+func (w *Worker) TagIndexStatus(runtimeID common.Namespace) *api.TagIndexStatus {
+	ix, ok := w.tagIndexes[runtimeID]
+	if !ok {
+		return nil
+	}
+	return ix.Status()
+}
+
 // Initialized returns a channel that will be closed when the client worker
 // is initialized and ready to service requests.
 func (w *Worker) Initialized() <-chan struct{} {
@@ -163,6 +184,20 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 		commonNode.Runtime.RegisterStorage(NewStatelessStorage(commonNode.P2P, w.commonWorker.ChainContext, id))
 	}
 
+	// Create transaction tag indexer, if enabled.
+	if cfg := config.GlobalConfig.Runtime.TagIndex; cfg.Enabled() && w.commonWorker.CommonStore != nil {
+		keys := make([][]byte, 0, len(cfg.Keys))
+		for _, key := range cfg.Keys {
+			keys = append(keys, []byte(key))
+		}
+
+		ix, err := tagindex.New(commonNode.Runtime.History(), commonNode.Runtime.Storage(), w.commonWorker.CommonStore, keys, cfg.Retention)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction tag indexer: %w", err)
+		}
+		w.tagIndexes[id] = ix
+	}
+
 	commonNode.AddHooks(node)
 	w.runtimes[id] = node
 
@@ -174,8 +209,11 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 }
 
 // New creates a new runtime client worker.
+//
+// The runtime client service is also exposed on the external gRPC server, if one is given.
 func New(
 	grpcInternal *grpc.Server,
+	grpcExternal *grpc.Server,
 	commonWorker *workerCommon.Worker,
 	registration *registration.Worker,
 ) (*Worker, error) {
@@ -199,6 +237,7 @@ func New(
 		commonWorker: commonWorker,
 		registration: registration,
 		runtimes:     make(map[common.Namespace]*committee.Node),
+		tagIndexes:   make(map[common.Namespace]*tagindex.Indexer),
 		quitCh:       make(chan struct{}),
 		initCh:       make(chan struct{}),
 		logger:       logging.GetLogger("worker/client"),
@@ -218,6 +257,9 @@ func New(
 	srv := &service{w: w}
 	// Attach the runtime client worker's internal GRPC interface.
 	api.RegisterService(grpcInternal.Server(), srv)
+	if grpcExternal != nil {
+		api.RegisterService(grpcExternal.Server(), srv)
+	}
 	// Register the client service with the registry.
 	err := commonWorker.RuntimeRegistry.RegisterClient(srv)
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/version"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 )
 
@@ -27,6 +29,8 @@ const (
 	StatusStateWaitingWorkersInit StatusState = 6
 	// StatusStateRuntimeSuspended is the runtime suspended status state.
 	StatusStateRuntimeSuspended StatusState = 7
+	// StatusStateRuntimePaused is the runtime paused status state.
+	StatusStateRuntimePaused StatusState = 8
 )
 
 // String returns a string representation of a status state.
@@ -48,6 +52,8 @@ func (s StatusState) String() string {
 		return "waiting for workers to initialize"
 	case StatusStateRuntimeSuspended:
 		return "runtime suspended"
+	case StatusStateRuntimePaused:
+		return "runtime paused"
 	default:
 		return "[invalid status state]"
 	}
@@ -72,6 +78,8 @@ func (s StatusState) MarshalText() ([]byte, error) {
 		return []byte(StatusStateWaitingWorkersInit.String()), nil
 	case StatusStateRuntimeSuspended:
 		return []byte(StatusStateRuntimeSuspended.String()), nil
+	case StatusStateRuntimePaused:
+		return []byte(StatusStateRuntimePaused.String()), nil
 	default:
 		return nil, fmt.Errorf("invalid StatusState: %d", s)
 	}
@@ -96,6 +104,8 @@ func (s *StatusState) UnmarshalText(text []byte) error {
 		*s = StatusStateWaitingWorkersInit
 	case StatusStateRuntimeSuspended.String():
 		*s = StatusStateRuntimeSuspended
+	case StatusStateRuntimePaused.String():
+		*s = StatusStateRuntimePaused
 	default:
 		return fmt.Errorf("invalid StatusState: %s", string(text))
 	}
@@ -106,6 +116,9 @@ func (s *StatusState) UnmarshalText(text []byte) error {
 type Status struct {
 	// Status is a concise status of the committee node.
 	Status StatusState `json:"status"`
+	// SuspensionReason is the reason why the runtime is suspended. It is only set when the
+	// runtime is suspended and the reason is known.
+	SuspensionReason *roothash.SuspensionReason `json:"suspension_reason,omitempty"`
 
 	// ActiveVersion is the currently active version.
 	ActiveVersion *version.Version `json:"active_version"`
@@ -128,6 +141,27 @@ type Status struct {
 
 	// Host is the runtime host status.
 	Host HostStatus `json:"host"`
+
+	// Processing is the runtime processing status.
+	Processing *ProcessingStatus `json:"processing,omitempty"`
+}
+
+// ProcessingStatus is a compact summary of runtime processing on this node.
+type ProcessingStatus struct {
+	// RoundsPerMinute is the number of runtime rounds processed in the last minute.
+	RoundsPerMinute uint64 `json:"rounds_per_minute"`
+
+	// ConsensusRound is the latest runtime round as observed by the consensus layer.
+	ConsensusRound uint64 `json:"consensus_round"`
+	// HeaderSyncLag is the number of rounds the latest processed block header is behind the
+	// latest consensus-observed round.
+	HeaderSyncLag uint64 `json:"header_sync_lag"`
+	// StateSyncLag is the number of rounds the latest synced runtime state is behind the latest
+	// consensus-observed round.
+	StateSyncLag uint64 `json:"state_sync_lag"`
+
+	// Scheduling is a rolling summary of recent transaction scheduling.
+	Scheduling *txpool.SchedulingStatus `json:"scheduling,omitempty"`
 }
 
 // HostStatus is the runtime host status.
@@ -15,10 +15,10 @@ type AccessPolicy struct {
 // the specified list of nodes.
 func (ap AccessPolicy) AddRulesForNodes(policy *accessctl.Policy, nodes []*node.Node) {
 	for _, node := range nodes {
-		// Allow the node to perform actions from the given access policy.
-		subject := accessctl.SubjectFromPublicKey(node.TLS.PubKey)
-		for _, action := range ap.Actions {
-			policy.Allow(subject, action)
+		// Allow the node to perform actions from the given access policy, using any of its TLS
+		// keys as both are valid during a TLS key rotation.
+		for _, pk := range node.TLS.PubKeys() {
+			ap.AddPublicKeyPolicy(policy, pk)
 		}
 	}
 }
@@ -41,9 +41,8 @@ func (ap AccessPolicy) AddRulesForNodeRoles(
 ) {
 	for _, n := range nodes {
 		if n.HasRoles(roles) {
-			subject := accessctl.SubjectFromPublicKey(n.TLS.PubKey)
-			for _, action := range ap.Actions {
-				policy.Allow(subject, action)
+			for _, pk := range n.TLS.PubKeys() {
+				ap.AddPublicKeyPolicy(policy, pk)
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,8 +12,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/heartbeat"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
@@ -34,7 +38,12 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/txsync"
 )
 
-const periodicMetricsInterval = 60 * time.Second
+const (
+	periodicMetricsInterval = 60 * time.Second
+
+	// processingRateWindow is the window over which the number of processed rounds is counted.
+	processingRateWindow = time.Minute
+)
 
 var (
 	processedBlockCount = prometheus.NewCounterVec(
@@ -107,12 +116,34 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	roundsPerMinute = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_rounds_per_minute",
+			Help: "Number of runtime rounds processed in the last minute.",
+		},
+		[]string{"runtime"},
+	)
+	headerSyncLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_header_sync_lag",
+			Help: "Number of rounds the latest processed block header is behind the latest consensus-observed round.",
+		},
+		[]string{"runtime"},
+	)
+	stateSyncLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_state_sync_lag",
+			Help: "Number of rounds the latest synced runtime state is behind the latest consensus-observed round.",
+		},
+		[]string{"runtime"},
+	)
 
 	nodeCollectors = []prometheus.Collector{
 		processedBlockCount,
 		failedRoundCount,
 		epochTransitionCount,
 		epochNumber,
+		roundsPerMinute,
 		// Periodically collected metrics.
 		workerIsExecutorWorker,
 		workerIsExecutorBackup,
@@ -120,6 +151,8 @@ var (
 		livenessTotalRounds,
 		livenessLiveRounds,
 		livenessRatio,
+		headerSyncLag,
+		stateSyncLag,
 	}
 
 	metricsOnce sync.Once
@@ -170,6 +203,10 @@ type Node struct {
 	quitCh    chan struct{}
 	initCh    chan struct{}
 	resumeCh  chan struct{}
+	pauseCh   chan struct{}
+
+	// Guarded by CrossNode.
+	suspensionReason roothash.SuspensionReason
 
 	hooks []NodeHooks
 
@@ -180,6 +217,7 @@ type Node struct {
 	hostedRuntimeProvisioned  uint32
 	historyReindexingDone     uint32
 	workersInitialized        uint32
+	paused                    uint32
 
 	// Mutable and shared between nodes' workers.
 	// Guarded by .CrossNode.
@@ -190,6 +228,10 @@ type Node struct {
 	CurrentDescriptor     *registry.Runtime
 	CurrentEpoch          beacon.EpochTime
 
+	// processedRoundTimes are the times at which recent rounds were processed.
+	// Guarded by .CrossNode.
+	processedRoundTimes []time.Time
+
 	logger *logging.Logger
 }
 
@@ -209,6 +251,9 @@ func (n *Node) getStatusStateLocked() api.StatusState {
 	if atomic.LoadUint32(&n.workersInitialized) == 0 {
 		return api.StatusStateWaitingWorkersInit
 	}
+	if n.IsPaused() {
+		return api.StatusStateRuntimePaused
+	}
 	if atomic.LoadUint32(&n.hostedRuntimeProvisioned) == 0 {
 		return api.StatusStateWaitingHostedRuntime
 	}
@@ -220,6 +265,37 @@ func (n *Node) getStatusStateLocked() api.StatusState {
 	return api.StatusStateReady
 }
 
+// SuspendedLocked returns true and the suspension reason iff the runtime is suspended.
+//
+// Guarded by n.CrossNode.
+func (n *Node) SuspendedLocked() (bool, roothash.SuspensionReason) {
+	return n.resumeCh != nil, n.suspensionReason
+}
+
+// SetPaused pauses or resumes the hosted runtime. While paused, the hosted runtime is stopped.
+//
+// It may be called before the node is started in which case the hosted runtime is not started
+// until the node is resumed.
+func (n *Node) SetPaused(paused bool) {
+	var val uint32
+	if paused {
+		val = 1
+	}
+	if atomic.SwapUint32(&n.paused, val) == val {
+		return
+	}
+
+	select {
+	case n.pauseCh <- struct{}{}:
+	default:
+	}
+}
+
+// IsPaused returns true iff the hosted runtime is paused.
+func (n *Node) IsPaused() bool {
+	return atomic.LoadUint32(&n.paused) == 1
+}
+
 // Name returns the service name.
 func (n *Node) Name() string {
 	return "committee node"
@@ -276,6 +352,10 @@ func (n *Node) GetStatus() (*api.Status, error) {
 
 	var status api.Status
 	status.Status = n.getStatusStateLocked()
+	if status.Status == api.StatusStateRuntimeSuspended && n.suspensionReason != roothash.SuspensionReasonNone {
+		reason := n.suspensionReason
+		status.SuspensionReason = &reason
+	}
 
 	if n.CurrentBlock != nil {
 		status.LatestRound = n.CurrentBlock.Header.Round
@@ -324,9 +404,65 @@ func (n *Node) GetStatus() (*api.Status, error) {
 
 	status.Host.Versions = n.RuntimeRegistry.GetBundleRegistry().GetVersions(n.Runtime.ID())
 
+	ps, err := n.getProcessingStatusLocked(n.ctx)
+	if err != nil {
+		n.logger.Debug("failed to get processing status",
+			"err", err,
+		)
+	}
+	status.Processing = ps
+
 	return &status, nil
 }
 
+// Guarded by n.CrossNode.
+func (n *Node) roundsPerMinuteLocked() uint64 {
+	cutoff := time.Now().Add(-processingRateWindow)
+	idx, _ := slices.BinarySearchFunc(n.processedRoundTimes, cutoff, func(t time.Time, cutoff time.Time) int {
+		return t.Compare(cutoff)
+	})
+	n.processedRoundTimes = n.processedRoundTimes[idx:]
+	return uint64(len(n.processedRoundTimes))
+}
+
+// Guarded by n.CrossNode.
+func (n *Node) getProcessingStatusLocked(ctx context.Context) (*api.ProcessingStatus, error) {
+	status := &api.ProcessingStatus{
+		RoundsPerMinute: n.roundsPerMinuteLocked(),
+		Scheduling:      n.TxPool.GetSchedulingStatus(),
+	}
+
+	blk, err := n.Consensus.RootHash().GetLatestBlock(ctx, &roothash.RuntimeRequest{
+		RuntimeID: n.Runtime.ID(),
+		Height:    consensus.HeightLatest,
+	})
+	if err != nil {
+		return status, fmt.Errorf("failed to query latest consensus-observed block: %w", err)
+	}
+	status.ConsensusRound = blk.Header.Round
+
+	lag := func(round uint64) uint64 {
+		if round >= status.ConsensusRound {
+			return 0
+		}
+		return status.ConsensusRound - round
+	}
+
+	var headerRound uint64
+	if n.CurrentBlock != nil {
+		headerRound = n.CurrentBlock.Header.Round
+	}
+	status.HeaderSyncLag = lag(headerRound)
+
+	stateRound, err := n.Runtime.History().LastStorageSyncedRound()
+	if err != nil {
+		return status, fmt.Errorf("failed to query last storage synced round: %w", err)
+	}
+	status.StateSyncLag = lag(stateRound)
+
+	return status, nil
+}
+
 func (n *Node) getMetricLabels() prometheus.Labels {
 	return prometheus.Labels{
 		"runtime": n.Runtime.ID().String(),
@@ -358,11 +494,35 @@ func (n *Node) handleEpochTransitionLocked(height int64) {
 	}
 
 	epochNumber.With(n.getMetricLabels()).Set(float64(epoch.epochNumber))
+
+	if epoch.IsExecutorMember() {
+		role := "worker"
+		if !epoch.IsExecutorWorker() {
+			role = "backup worker"
+		}
+		runtimeID := n.Runtime.ID()
+		journal.Record(&journal.Event{
+			Kind:      journal.KindCommitteeElected,
+			Height:    height,
+			RuntimeID: &runtimeID,
+			Message:   fmt.Sprintf("elected into the executor committee for epoch %d as %s", epoch.epochNumber, role),
+		})
+	}
 }
 
 // Guarded by n.CrossNode.
-func (n *Node) handleSuspendLocked(int64) {
-	n.logger.Warn("runtime has been suspended")
+func (n *Node) handleSuspendLocked(height int64) {
+	n.logger.Warn("runtime has been suspended",
+		"reason", n.suspensionReason,
+	)
+
+	runtimeID := n.Runtime.ID()
+	journal.Record(&journal.Event{
+		Kind:      journal.KindRuntimeSuspended,
+		Height:    height,
+		RuntimeID: &runtimeID,
+		Message:   fmt.Sprintf("runtime has been suspended (reason: %s)", n.suspensionReason),
+	})
 
 	// Suspend group.
 	n.Group.Suspend()
@@ -433,9 +593,10 @@ func (n *Node) updateHostedRuntimeVersionLocked() {
 		*activeVersion = activeDeploy.Version
 	}
 
-	// For compute nodes, determine if there is a next version and activate it early.
+	// For compute nodes, determine if there is a next version and activate it early. Read-only
+	// runtimes never participate in committees, so there is nothing to prepare for.
 	var nextVersion *version.Version
-	if config.GlobalConfig.Mode == config.ModeCompute {
+	if config.GlobalConfig.Mode == config.ModeCompute && !config.GlobalConfig.Runtime.IsReadOnly(n.Runtime.ID()) {
 		nextDeploy := n.CurrentDescriptor.NextDeployment(epoch)
 		preWarmEpochs := beacon.EpochTime(config.GlobalConfig.Runtime.PreWarmEpochs)
 
@@ -461,6 +622,9 @@ func (n *Node) updateHostedRuntimeVersionLocked() {
 func (n *Node) handleNewBlockLocked(blk *block.Block, height int64) {
 	processedBlockCount.With(n.getMetricLabels()).Inc()
 
+	n.processedRoundTimes = append(n.processedRoundTimes, time.Now())
+	roundsPerMinute.With(n.getMetricLabels()).Set(float64(n.roundsPerMinuteLocked()))
+
 	header := blk.Header
 
 	// The first received block will be treated an epoch transition (if valid).
@@ -499,6 +663,7 @@ func (n *Node) handleNewBlockLocked(blk *block.Block, height int64) {
 			return
 		}
 		n.CurrentDescriptor = rs.Runtime
+		n.suspensionReason = rs.SuspensionReason
 
 		n.CurrentEpoch, err = n.Consensus.Beacon().GetEpoch(n.ctx, height)
 		if err != nil {
@@ -589,6 +754,16 @@ func (n *Node) handleRuntimeHostEventLocked(ev *host.Event) {
 	switch {
 	case ev.Started != nil:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 1)
+	case ev.Updated != nil:
+		if ev.Updated.CapabilityTEE != nil {
+			runtimeID := n.Runtime.ID()
+			journal.Record(&journal.Event{
+				Kind:      journal.KindAttestationRenewed,
+				Height:    n.CurrentBlockHeight,
+				RuntimeID: &runtimeID,
+				Message:   fmt.Sprintf("runtime attestation renewed (version: %s)", ev.Updated.Version),
+			})
+		}
 	case ev.FailedToStart != nil, ev.Stopped != nil:
 		atomic.StoreUint32(&n.hostedRuntimeProvisioned, 0)
 	}
@@ -732,19 +907,57 @@ func (n *Node) worker() {
 	hrtEventCh, hrtSub := hrt.WatchEvents()
 	defer hrtSub.Close()
 
-	hrt.Start()
+	var running bool
+	provisionAndStart := func() error {
+		if n.IsPaused() || running {
+			return nil
+		}
+
+		// Stopping the hosted runtime tears down all running component instances, so they
+		// need to be provisioned again.
+		for _, comp := range bundleRegistry.Components(n.Runtime.ID()) {
+			if err := n.ProvisionHostedRuntimeComponent(comp); err != nil {
+				return fmt.Errorf("failed to provision runtime component %s version %s: %w", comp.ID(), comp.Version, err)
+			}
+		}
+
+		n.CrossNode.Lock()
+		n.updateHostedRuntimeVersionLocked()
+		n.CrossNode.Unlock()
+
+		hrt.Start()
+		running = true
+		return nil
+	}
+	if err = provisionAndStart(); err != nil {
+		n.logger.Error("failed to start hosted runtime",
+			"err", err,
+		)
+		return
+	}
 	defer hrt.Stop()
 
+	if n.IsPaused() {
+		n.logger.Info("hosted runtime is paused, not starting it")
+	}
+
 	// Start the runtime's notifier.
 	n.notifier.Start()
 	defer n.notifier.Stop()
 
+	hb := heartbeat.Register("worker/common/" + n.Runtime.ID().String())
+	defer hb.Stop()
+	hbTicker := time.NewTicker(heartbeat.Interval)
+	defer hbTicker.Stop()
+
 	// Enter the main processing loop.
 	for {
 		select {
 		case <-n.stopCh:
 			n.logger.Info("termination requested")
 			return
+		case <-hbTicker.C:
+			hb.Beat()
 		case blk := <-blkCh:
 			// Received a block (annotated).
 			func() {
@@ -759,6 +972,24 @@ func (n *Node) worker() {
 				defer n.CrossNode.Unlock()
 				n.handleRuntimeHostEventLocked(ev)
 			}()
+		case <-n.pauseCh:
+			// Received a request to pause or resume the hosted runtime.
+			switch paused := n.IsPaused(); {
+			case paused && running:
+				n.logger.Info("pausing hosted runtime")
+
+				hrt.Stop()
+				running = false
+			case !paused && !running:
+				n.logger.Info("resuming hosted runtime")
+
+				if err = provisionAndStart(); err != nil {
+					n.logger.Error("failed to resume hosted runtime",
+						"err", err,
+					)
+					return
+				}
+			}
 		case compNotify := <-compCh:
 			switch {
 			case compNotify.Added != nil:
@@ -806,6 +1037,12 @@ func (n *Node) updatePeriodicMetrics() {
 
 	n.logger.Debug("updating periodic worker node metrics")
 
+	roundsPerMinute.With(labels).Set(float64(n.roundsPerMinuteLocked()))
+	if ps, err := n.getProcessingStatusLocked(n.ctx); err == nil {
+		headerSyncLag.With(labels).Set(float64(ps.HeaderSyncLag))
+		stateSyncLag.With(labels).Set(float64(ps.StateSyncLag))
+	}
+
 	epoch := n.Group.GetEpochSnapshot()
 	cmte := epoch.GetExecutorCommittee()
 	if cmte == nil {
@@ -875,6 +1112,7 @@ func NewNode(
 	consensus consensus.Service,
 	lightProvider consensus.LightProvider,
 	p2pHost p2pAPI.Service,
+	commonStore *persistent.CommonStore,
 	txPoolCfg tpConfig.Config,
 ) (*Node, error) {
 	metricsOnce.Do(func() {
@@ -909,6 +1147,7 @@ func NewNode(
 		stopCh:          make(chan struct{}),
 		quitCh:          make(chan struct{}),
 		initCh:          make(chan struct{}),
+		pauseCh:         make(chan struct{}, 1),
 		logger:          logging.GetLogger("worker/common/committee").With("runtime_id", runtime.ID()),
 	}
 
@@ -929,7 +1168,7 @@ func NewNode(
 	n.notifier = runtimeRegistry.NewRuntimeHostNotifier(runtime, rhn.GetHostedRuntime(), consensus)
 
 	// Prepare transaction pool.
-	n.TxPool = txpool.New(runtime.ID(), txPoolCfg, rhn.GetHostedRuntime(), runtime.History(), n)
+	n.TxPool = txpool.New(runtime.ID(), txPoolCfg, rhn.GetHostedRuntime(), runtime.History(), n, commonStore)
 
 	// Register transaction message handler as that is something that all workers must handle.
 	p2pHost.RegisterHandler(txTopic, &txMsgHandler{n})
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/config"
@@ -13,8 +14,6 @@ import (
 type Config struct { // nolint: maligned
 	SentryAddresses []node.TLSAddress
 
-	TxPool tpConfig.Config
-
 	logger *logging.Logger
 }
 
@@ -32,9 +31,13 @@ func NewConfig() (*Config, error) {
 
 	cfg := Config{
 		SentryAddresses: sentryAddresses,
-		TxPool:          config.GlobalConfig.Runtime.TxPool,
 		logger:          logging.GetLogger("worker/config"),
 	}
 
 	return &cfg, nil
 }
+
+// TxPoolConfig returns the transaction pool configuration for the given runtime.
+func (c *Config) TxPoolConfig(runtimeID common.Namespace) tpConfig.Config {
+	return config.GlobalConfig.Runtime.GetTxPoolConfig(runtimeID)
+}
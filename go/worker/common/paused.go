@@ -0,0 +1,112 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+)
+
+// pausedRuntimesBucketName is the name of the database bucket for the runtimes paused via the
+// control API.
+const pausedRuntimesBucketName = "worker/common/paused_runtimes"
+
+// pausedRuntimesKey is the database key under which the set of paused runtimes is stored.
+var pausedRuntimesKey = []byte("paused")
+
+// PauseRuntime pauses the given hosted runtime and persists its paused state.
+func (w *Worker) PauseRuntime(id common.Namespace) error {
+	return w.setRuntimePaused(id, true)
+}
+
+// ResumeRuntime resumes the given paused hosted runtime and persists its state.
+func (w *Worker) ResumeRuntime(id common.Namespace) error {
+	return w.setRuntimePaused(id, false)
+}
+
+func (w *Worker) setRuntimePaused(id common.Namespace, paused bool) error {
+	rt := w.GetRuntime(id)
+	if rt == nil {
+		return control.ErrRuntimeNotHosted
+	}
+
+	w.pausedLock.Lock()
+	defer w.pausedLock.Unlock()
+
+	if rt.IsPaused() == paused {
+		return nil
+	}
+
+	var ids []common.Namespace
+	for rtID, rtNode := range w.runtimes {
+		if rtID == id {
+			if paused {
+				ids = append(ids, rtID)
+			}
+			continue
+		}
+		if rtNode.IsPaused() {
+			ids = append(ids, rtID)
+		}
+	}
+	if err := w.persistPausedRuntimes(ids); err != nil {
+		return err
+	}
+
+	rt.SetPaused(paused)
+
+	w.logger.Info("runtime paused state changed",
+		"runtime_id", id,
+		"paused", paused,
+	)
+
+	return nil
+}
+
+func (w *Worker) persistPausedRuntimes(ids []common.Namespace) error {
+	if w.CommonStore == nil {
+		return nil
+	}
+
+	ss := w.CommonStore.GetServiceStore(pausedRuntimesBucketName)
+	defer ss.Close()
+
+	if err := ss.PutCBOR(pausedRuntimesKey, ids); err != nil {
+		return fmt.Errorf("worker/common: failed to persist paused runtimes: %w", err)
+	}
+	return nil
+}
+
+// restorePausedRuntimes pauses the runtimes that were paused before the node was restarted.
+func (w *Worker) restorePausedRuntimes() error {
+	if w.CommonStore == nil {
+		return nil
+	}
+
+	ss := w.CommonStore.GetServiceStore(pausedRuntimesBucketName)
+	defer ss.Close()
+
+	var ids []common.Namespace
+	switch err := ss.GetCBOR(pausedRuntimesKey, &ids); err {
+	case nil:
+	case persistent.ErrNotFound:
+		return nil
+	default:
+		return fmt.Errorf("worker/common: failed to load paused runtimes: %w", err)
+	}
+
+	for _, id := range ids {
+		rt := w.GetRuntime(id)
+		if rt == nil {
+			// Runtime is no longer configured, it will be dropped on the next change.
+			continue
+		}
+		rt.SetPaused(true)
+
+		w.logger.Info("runtime is paused",
+			"runtime_id", id,
+		)
+	}
+	return nil
+}
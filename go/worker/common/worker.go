@@ -2,10 +2,12 @@ package common
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	control "github.com/oasisprotocol/oasis-core/go/control/api"
@@ -23,6 +25,7 @@ type Worker struct {
 
 	HostNode        control.NodeController
 	DataDir         string
+	CommonStore     *persistent.CommonStore
 	ChainContext    string
 	Identity        *identity.Identity
 	Consensus       consensus.Service
@@ -32,7 +35,8 @@ type Worker struct {
 	RuntimeRegistry runtimeRegistry.Registry
 	Provisioner     host.Provisioner
 
-	runtimes map[common.Namespace]*committee.Node
+	runtimes   map[common.Namespace]*committee.Node
+	pausedLock sync.Mutex
 
 	quitCh chan struct{}
 	initCh chan struct{}
@@ -165,7 +169,8 @@ func (w *Worker) registerRuntime(runtime runtimeRegistry.Runtime) error {
 		w.Consensus,
 		w.LightProvider,
 		w.P2P,
-		w.cfg.TxPool,
+		w.CommonStore,
+		w.cfg.TxPoolConfig(id),
 	)
 	if err != nil {
 		return err
@@ -183,6 +188,7 @@ func (w *Worker) registerRuntime(runtime runtimeRegistry.Runtime) error {
 func New(
 	hostNode control.NodeController,
 	dataDir string,
+	commonStore *persistent.CommonStore,
 	chainContext string,
 	identity *identity.Identity,
 	consensus consensus.Service,
@@ -213,6 +219,7 @@ func New(
 		cfg:             *cfg,
 		HostNode:        hostNode,
 		DataDir:         dataDir,
+		CommonStore:     commonStore,
 		ChainContext:    chainContext,
 		Identity:        identity,
 		Consensus:       consensus,
@@ -241,5 +248,9 @@ func New(
 		}
 	}
 
+	if err := w.restorePausedRuntimes(); err != nil {
+		return nil, err
+	}
+
 	return w, nil
 }
@@ -1,6 +1,10 @@
 package api
 
-import "fmt"
+import (
+	"fmt"
+
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
 
 // StatusState is the concise status state of the common runtime worker.
 type StatusState uint8
@@ -12,6 +16,14 @@ const (
 	StatusStateWaitingRuntime StatusState = 1
 	// StatusStateWaitingTrustSync is the waiting for runtime trust sync status state.
 	StatusStateWaitingTrustSync StatusState = 2
+	// StatusStateRuntimeSuspended is the runtime suspended status state.
+	StatusStateRuntimeSuspended StatusState = 3
+	// StatusStateRetiring is the status state in which the node is waiting for the current round
+	// to complete before it stops participating in committees.
+	StatusStateRetiring StatusState = 4
+	// StatusStateRetired is the status state in which the node no longer participates in
+	// committees.
+	StatusStateRetired StatusState = 5
 )
 
 // String returns a string representation of a status state.
@@ -23,6 +35,12 @@ func (s StatusState) String() string {
 		return "waiting for runtime readiness"
 	case StatusStateWaitingTrustSync:
 		return "waiting for trust sync"
+	case StatusStateRuntimeSuspended:
+		return "runtime suspended"
+	case StatusStateRetiring:
+		return "retiring"
+	case StatusStateRetired:
+		return "retired"
 	default:
 		return "[invalid status state]"
 	}
@@ -37,6 +55,12 @@ func (s StatusState) MarshalText() ([]byte, error) {
 		return []byte(StatusStateWaitingRuntime.String()), nil
 	case StatusStateWaitingTrustSync:
 		return []byte(StatusStateWaitingTrustSync.String()), nil
+	case StatusStateRuntimeSuspended:
+		return []byte(StatusStateRuntimeSuspended.String()), nil
+	case StatusStateRetiring:
+		return []byte(StatusStateRetiring.String()), nil
+	case StatusStateRetired:
+		return []byte(StatusStateRetired.String()), nil
 	default:
 		return nil, fmt.Errorf("invalid StatusState: %d", s)
 	}
@@ -51,14 +75,81 @@ func (s *StatusState) UnmarshalText(text []byte) error {
 		*s = StatusStateWaitingRuntime
 	case StatusStateWaitingTrustSync.String():
 		*s = StatusStateWaitingTrustSync
+	case StatusStateRuntimeSuspended.String():
+		*s = StatusStateRuntimeSuspended
+	case StatusStateRetiring.String():
+		*s = StatusStateRetiring
+	case StatusStateRetired.String():
+		*s = StatusStateRetired
 	default:
 		return fmt.Errorf("invalid StatusState: %s", string(text))
 	}
 	return nil
 }
 
+// BackpressureState is the storage backpressure state of the executor.
+type BackpressureState uint8
+
+const (
+	// BackpressureNone is the state in which the storage worker keeps up with the runtime.
+	BackpressureNone BackpressureState = 0
+	// BackpressureSoft is the state in which the storage round lag exceeds the soft threshold and
+	// the executor stops proposing batches.
+	BackpressureSoft BackpressureState = 1
+	// BackpressureHard is the state in which the storage round lag exceeds the hard threshold and
+	// the executor reports itself as unavailable.
+	BackpressureHard BackpressureState = 2
+)
+
+// String returns a string representation of a backpressure state.
+func (s BackpressureState) String() string {
+	switch s {
+	case BackpressureNone:
+		return "none"
+	case BackpressureSoft:
+		return "soft"
+	case BackpressureHard:
+		return "hard"
+	default:
+		return "[invalid backpressure state]"
+	}
+}
+
+// MarshalText encodes a BackpressureState into text form.
+func (s BackpressureState) MarshalText() ([]byte, error) {
+	switch s {
+	case BackpressureNone, BackpressureSoft, BackpressureHard:
+		return []byte(s.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid BackpressureState: %d", s)
+	}
+}
+
+// UnmarshalText decodes a text slice into a BackpressureState.
+func (s *BackpressureState) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case BackpressureNone.String():
+		*s = BackpressureNone
+	case BackpressureSoft.String():
+		*s = BackpressureSoft
+	case BackpressureHard.String():
+		*s = BackpressureHard
+	default:
+		return fmt.Errorf("invalid BackpressureState: %s", string(text))
+	}
+	return nil
+}
+
 // Status is the executor worker status.
 type Status struct {
 	// Status is a concise status of the committee node.
 	Status StatusState `json:"status"`
+	// SuspensionReason is the reason why the runtime is suspended. It is only set when the
+	// runtime is suspended and the reason is known.
+	SuspensionReason *roothash.SuspensionReason `json:"suspension_reason,omitempty"`
+
+	// StorageRoundLag is the number of finalized rounds not yet applied to local storage.
+	StorageRoundLag uint64 `json:"storage_round_lag"`
+	// Backpressure is the storage backpressure state.
+	Backpressure BackpressureState `json:"backpressure"`
 }
@@ -0,0 +1,136 @@
+package committee
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+// StorageLagSource is a source of storage round lag updates.
+type StorageLagSource interface {
+	// WatchRoundLag subscribes to updates of the storage round lag, which is the number of rounds
+	// that have been finalized by the consensus layer but not yet applied to local storage.
+	WatchRoundLag() (<-chan uint64, pubsub.ClosableSubscription)
+}
+
+// storageBackpressure tracks the storage round lag and derives the backpressure state from it.
+type storageBackpressure struct {
+	cfgLock sync.RWMutex
+	cfg     rtConfig.StorageBackpressureConfig
+
+	lag atomic.Uint64
+}
+
+// setConfig updates the backpressure configuration and returns true iff it has changed.
+func (b *storageBackpressure) setConfig(cfg rtConfig.StorageBackpressureConfig) bool {
+	b.cfgLock.Lock()
+	defer b.cfgLock.Unlock()
+
+	if b.cfg == cfg {
+		return false
+	}
+	b.cfg = cfg
+	return true
+}
+
+// update records the given storage round lag and returns the backpressure states before and
+// after the update.
+//
+// This is only called from a single goroutine.
+func (b *storageBackpressure) update(lag uint64) (api.BackpressureState, api.BackpressureState) {
+	prev := b.state()
+	b.lag.Store(lag)
+	return prev, b.state()
+}
+
+// roundLag returns the last recorded storage round lag.
+func (b *storageBackpressure) roundLag() uint64 {
+	return b.lag.Load()
+}
+
+// state returns the current backpressure state.
+func (b *storageBackpressure) state() api.BackpressureState {
+	b.cfgLock.RLock()
+	cfg := b.cfg
+	b.cfgLock.RUnlock()
+
+	lag := b.lag.Load()
+	switch {
+	case cfg.HardLagThreshold > 0 && lag > cfg.HardLagThreshold:
+		return api.BackpressureHard
+	case cfg.SoftLagThreshold > 0 && lag > cfg.SoftLagThreshold:
+		return api.BackpressureSoft
+	default:
+		return api.BackpressureNone
+	}
+}
+
+func (n *Node) backpressureWorker() {
+	lagCh, lagSub := n.lagSource.WatchRoundLag()
+	defer lagSub.Close()
+
+	for {
+		select {
+		case <-n.stopCh:
+			return
+		case lag := <-lagCh:
+			n.handleRoundLag(lag)
+		}
+	}
+}
+
+func (n *Node) handleRoundLag(lag uint64) {
+	prev, state := n.backpressure.update(lag)
+
+	storageRoundLag.With(n.getMetricLabels()).Set(float64(lag))
+	storageBackpressureState.With(n.getMetricLabels()).Set(float64(state))
+
+	if prev == state {
+		return
+	}
+
+	switch state {
+	case api.BackpressureNone:
+		n.logger.Info("storage caught up, backpressure released",
+			"round_lag", lag,
+		)
+	default:
+		n.logger.Warn("storage is lagging behind, applying backpressure",
+			"round_lag", lag,
+			"backpressure", state,
+		)
+	}
+
+	// Update availability when entering or leaving the hard backpressure state.
+	if prev == api.BackpressureHard || state == api.BackpressureHard {
+		n.commonNode.CrossNode.Lock()
+		n.nudgeAvailabilityLocked(false)
+		n.commonNode.CrossNode.Unlock()
+	}
+}
+
+// updateBackpressureConfigLocked applies the storage backpressure configuration of the runtime
+// deployment that is active for the given block.
+//
+// Guarded by n.commonNode.CrossNode.
+func (n *Node) updateBackpressureConfigLocked(bi *runtime.BlockInfo) {
+	if bi.ActiveDescriptor == nil {
+		return
+	}
+
+	cfg := runtimeRegistry.NewDeploymentFeatures(bi.ActiveDescriptor, bi.Epoch).StorageBackpressureConfig()
+	if !n.backpressure.setConfig(cfg) {
+		return
+	}
+
+	n.logger.Info("storage backpressure configuration updated",
+		"epoch", bi.Epoch,
+		"soft_lag_threshold", cfg.SoftLagThreshold,
+		"hard_lag_threshold", cfg.HardLagThreshold,
+	)
+}
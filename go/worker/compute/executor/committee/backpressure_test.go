@@ -0,0 +1,144 @@
+package committee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/version"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	rtConfig "github.com/oasisprotocol/oasis-core/go/runtime/config"
+	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
+)
+
+// slowStorage simulates a storage worker whose apply path is artificially slowed down so that it
+// only applies a single round for every applyEvery finalized rounds.
+type slowStorage struct {
+	notifier *pubsub.Broker
+
+	applyEvery uint64
+	finalized  uint64
+	latest     uint64
+	applied    uint64
+}
+
+// WatchRoundLag implements StorageLagSource.
+func (s *slowStorage) WatchRoundLag() (<-chan uint64, pubsub.ClosableSubscription) {
+	ch := make(chan uint64)
+	sub := s.notifier.Subscribe()
+	sub.Unwrap(ch)
+	return ch, sub
+}
+
+// finalizeRound simulates a new round being finalized by the consensus layer.
+func (s *slowStorage) finalizeRound() {
+	s.finalized++
+	s.latest++
+	if s.finalized%s.applyEvery == 0 {
+		s.applied++
+	}
+	s.notifier.Broadcast(s.latest - s.applied)
+}
+
+// catchUp simulates the storage apply path speeding up and applying all pending rounds.
+func (s *slowStorage) catchUp() {
+	for s.applied < s.latest {
+		s.applied++
+		s.notifier.Broadcast(s.latest - s.applied)
+	}
+}
+
+func TestStorageBackpressure(t *testing.T) {
+	require := require.New(t)
+
+	storage := &slowStorage{
+		notifier:   pubsub.NewBroker(true),
+		applyEvery: 2,
+	}
+	lagCh, lagSub := storage.WatchRoundLag()
+	defer lagSub.Close()
+
+	bp := storageBackpressure{
+		cfg: rtConfig.StorageBackpressureConfig{
+			SoftLagThreshold: 3,
+			HardLagThreshold: 6,
+		},
+	}
+	require.Equal(api.BackpressureNone, bp.state(), "there should be no backpressure initially")
+
+	// Finalize rounds faster than storage is able to apply them and record state transitions.
+	var transitions []api.BackpressureState
+	for range 16 {
+		storage.finalizeRound()
+
+		prev, state := bp.update(<-lagCh)
+		if prev != state {
+			transitions = append(transitions, state)
+		}
+	}
+	require.Equal([]api.BackpressureState{api.BackpressureSoft, api.BackpressureHard}, transitions,
+		"backpressure should be applied in stages as storage falls behind",
+	)
+	require.EqualValues(8, bp.roundLag())
+
+	// Once storage catches up, backpressure should be released in stages.
+	transitions = nil
+	storage.catchUp()
+	for bp.roundLag() > 0 {
+		prev, state := bp.update(<-lagCh)
+		if prev != state {
+			transitions = append(transitions, state)
+		}
+	}
+	require.Equal([]api.BackpressureState{api.BackpressureSoft, api.BackpressureNone}, transitions,
+		"backpressure should be released once storage catches up",
+	)
+
+	// Disabled thresholds should never apply backpressure.
+	disabled := storageBackpressure{}
+	_, state := disabled.update(1000)
+	require.Equal(api.BackpressureNone, state, "disabled thresholds should not apply backpressure")
+
+	// Only the hard threshold is configured.
+	hardOnly := storageBackpressure{cfg: rtConfig.StorageBackpressureConfig{HardLagThreshold: 10}}
+	_, state = hardOnly.update(5)
+	require.Equal(api.BackpressureNone, state)
+	_, state = hardOnly.update(11)
+	require.Equal(api.BackpressureHard, state)
+}
+
+func TestStorageBackpressureDeploymentSwitchover(t *testing.T) {
+	require := require.New(t)
+
+	rt := &registry.Runtime{
+		ID: common.NewTestNamespaceFromSeed([]byte("executor backpressure switchover test"), 0),
+		Deployments: []*registry.VersionInfo{
+			{
+				Version:   version.Version{Major: 1},
+				ValidFrom: 0,
+			},
+			{
+				Version:   version.Version{Major: 2},
+				ValidFrom: 10,
+				Features: map[string]string{
+					registry.FeatureStorageBackpressureSoftLagThreshold: "5",
+				},
+			},
+		},
+	}
+	n := &Node{logger: logging.GetLogger("worker/executor/committee/test")}
+	n.backpressure.update(8)
+
+	// The deployment features do not apply before the activation epoch.
+	n.updateBackpressureConfigLocked(&runtime.BlockInfo{ActiveDescriptor: rt, Epoch: 9})
+	require.Equal(api.BackpressureNone, n.backpressure.state())
+
+	// The deployment features apply starting with the activation epoch.
+	n.updateBackpressureConfigLocked(&runtime.BlockInfo{ActiveDescriptor: rt, Epoch: 10})
+	require.Equal(api.BackpressureSoft, n.backpressure.state())
+	require.False(n.backpressure.setConfig(rtConfig.StorageBackpressureConfig{SoftLagThreshold: 5}), "configuration should be unchanged")
+}
@@ -0,0 +1,39 @@
+package committee
+
+import (
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crashdump"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/transaction"
+)
+
+// handlePanic writes a crash dump for the given recovered panic value (if any) that occurred
+// while processing the round following the given block and re-panics. In case a batch is given,
+// it is included in the dump as the in-flight request.
+//
+// It must be called from a deferred function with the result of recover.
+func (n *Node) handlePanic(p any, bi *runtime.BlockInfo, batch transaction.RawBatch) {
+	if p == nil {
+		return
+	}
+
+	crashdump.Recover(p, "executor/"+n.commonNode.Runtime.ID().String(), func() *crashdump.State {
+		var state crashdump.State
+		if bi != nil {
+			state.Height = bi.ConsensusBlock.Height
+			state.Round = bi.RuntimeBlock.Header.Round + 1
+			state.StateRoots = map[string]hash.Hash{
+				"runtime_state": bi.RuntimeBlock.Header.StateRoot,
+				"runtime_io":    bi.RuntimeBlock.Header.IORoot,
+			}
+		}
+		if batch != nil {
+			state.Request = &crashdump.Request{
+				Method: "RuntimeExecuteTxBatch",
+				Body:   cbor.Marshal(batch),
+			}
+		}
+		return &state
+	})
+}
@@ -72,7 +72,7 @@ func (n *Node) predictDiscrepancy(ctx context.Context, ec *commitment.ExecutorCo
 
 	// Verify and add the commitment.
 	rt := n.epoch.GetRuntime()
-	if err := commitment.VerifyExecutorCommitment(ctx, n.blockInfo.RuntimeBlock, rt, n.committee.ValidFor, ec, nil, n.epoch); err != nil {
+	if err := n.verifyExecutorCommitment(ctx, ec); err != nil {
 		n.logger.Debug("ignoring bad observed executor commitment, verification failed",
 			"err", err,
 			"node_id", ec.NodeID,
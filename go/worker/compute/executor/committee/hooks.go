@@ -11,9 +11,12 @@ var _ committee.NodeHooks = (*Node)(nil)
 
 // HandleNewBlockEarlyLocked implements NodeHooks.
 // Guarded by n.commonNode.CrossNode.
-func (n *Node) HandleNewBlockEarlyLocked(*runtime.BlockInfo) {
+func (n *Node) HandleNewBlockEarlyLocked(bi *runtime.BlockInfo) {
 	crash.Here(crashPointRoothashReceiveAfter)
 
+	// Apply settings of the active deployment.
+	n.updateBackpressureConfigLocked(bi)
+
 	// Update our availability.
 	n.nudgeAvailabilityLocked(false)
 }
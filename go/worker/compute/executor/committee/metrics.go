@@ -51,6 +51,14 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	txExecutionTime = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "oasis_worker_tx_execution_time",
+			Help:    "Time it takes for a transaction to be executed as reported by the runtime (seconds).",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"runtime"},
+	)
 	batchSize = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name: "oasis_worker_batch_size",
@@ -58,6 +66,27 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	speculativeBatchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_execution_speculative_batch_count",
+			Help: "Number of speculatively executed batches by outcome.",
+		},
+		[]string{"runtime", "outcome"},
+	)
+	storageRoundLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_executor_storage_round_lag",
+			Help: "Number of finalized rounds not yet applied to local storage as seen by the executor.",
+		},
+		[]string{"runtime"},
+	)
+	storageBackpressureState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_executor_storage_backpressure",
+			Help: "Storage backpressure state of the executor (0 = none, 1 = soft, 2 = hard).",
+		},
+		[]string{"runtime"},
+	)
 	nodeCollectors = []prometheus.Collector{
 		processedEventCount,
 		discrepancyDetectedCount,
@@ -65,7 +94,11 @@ var (
 		storageCommitLatency,
 		batchProcessingTime,
 		batchRuntimeProcessingTime,
+		txExecutionTime,
 		batchSize,
+		speculativeBatchCount,
+		storageRoundLag,
+		storageBackpressureState,
 	}
 
 	metricsOnce sync.Once
@@ -77,6 +110,13 @@ func (n *Node) getMetricLabels() prometheus.Labels {
 	}
 }
 
+func (n *Node) getSpeculationMetricLabels(outcome string) prometheus.Labels {
+	return prometheus.Labels{
+		"runtime": n.commonNode.Runtime.ID().String(),
+		"outcome": outcome,
+	}
+}
+
 // initMetrics registers the metrics collectors if metrics are enabled.
 func initMetrics() {
 	if !metrics.Enabled() {
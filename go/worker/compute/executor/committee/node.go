@@ -11,16 +11,21 @@ import (
 	"golang.org/x/exp/maps"
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/crash"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/workerpool"
+	"github.com/oasisprotocol/oasis-core/go/config"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	p2pProtocol "github.com/oasisprotocol/oasis-core/go/p2p/protocol"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/message"
 	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
@@ -28,14 +33,19 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
 	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 	commonWorker "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/p2p/txsync"
+	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
 
 var (
 	errMsgFromNonTxnSched = fmt.Errorf("executor: received txn scheduler dispatch msg from non-txn scheduler")
+	// errExecutionFailed is the error returned when the runtime itself failed to execute a batch
+	// (e.g., it panicked), as opposed to batch execution being aborted.
+	errExecutionFailed = fmt.Errorf("executor: runtime failed to execute batch")
 
 	// abortTimeout is the duration to wait for the runtime to abort.
 	abortTimeout = 5 * time.Second
@@ -47,6 +57,10 @@ var (
 // the formula F * ProposerTimeout to ensure that a broken runtime doesn't block forever.
 const executeBatchTimeoutFactor = 3
 
+// maxObservedCommitmentBatchSize is the maximum number of observed executor commitments whose
+// signatures are verified as a single batch.
+const maxObservedCommitmentBatchSize = 128
+
 // Node is a committee node.
 type Node struct { // nolint: maligned
 	runtimeReady         bool
@@ -57,6 +71,9 @@ type Node struct { // nolint: maligned
 	commonCfg    commonWorker.Config
 	roleProvider registration.RoleProvider
 
+	lagSource    StorageLagSource
+	backpressure storageBackpressure
+
 	committeeTopic string
 
 	ctx       context.Context
@@ -66,6 +83,10 @@ type Node struct { // nolint: maligned
 	quitCh    chan struct{}
 	initCh    chan struct{}
 
+	retireOnce sync.Once
+	retireCh   chan struct{} // closed when retirement is requested
+	retiredCh  chan struct{} // closed after the node stopped participating in committees
+
 	storage storage.LocalBackend
 	txSync  txsync.Client
 
@@ -77,6 +98,10 @@ type Node struct { // nolint: maligned
 	committee        *scheduler.Committee
 	commitPool       *commitment.Pool
 
+	// Speculative execution of the next round, if any.
+	speculation        *speculativeBatch
+	adoptedSpeculation *speculativeBatch
+
 	blockInfoCh      chan *runtime.BlockInfo
 	processedBatchCh chan *processedBatch
 	reselectCh       chan struct{}
@@ -144,6 +169,36 @@ func (n *Node) Initialized() <-chan struct{} {
 	return n.initCh
 }
 
+// Retire requests the node to stop participating in committees once the current round
+// completes.
+//
+// The returned channel is closed after the node stopped participating in committees.
+func (n *Node) Retire() <-chan struct{} {
+	n.retireOnce.Do(func() {
+		n.logger.Info("retirement requested, waiting for the current round to complete")
+		close(n.retireCh)
+	})
+	return n.retiredCh
+}
+
+func (n *Node) isRetiring() bool {
+	select {
+	case <-n.retireCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (n *Node) isRetired() bool {
+	select {
+	case <-n.retiredCh:
+		return true
+	default:
+		return false
+	}
+}
+
 // WatchStateTransitions subscribes to the node's state transitions.
 func (n *Node) WatchStateTransitions() (<-chan NodeState, *pubsub.Subscription) {
 	sub := n.stateTransitions.Subscribe()
@@ -200,8 +255,10 @@ func (n *Node) transitionStateToProcessing(ctx context.Context, proposal *commit
 
 	// Request the worker host to process a batch. This is done in a separate
 	// goroutine so that the runtime worker can continue processing events.
+	bi := n.blockInfo
 	go func() {
 		defer close(done)
+		defer func() { n.handlePanic(recover(), bi, batch) }()
 		n.startProcessingBatch(ctx, proposal, rank, batch)
 	}()
 }
@@ -349,6 +406,23 @@ func (n *Node) scheduleBatch(ctx context.Context, round uint64, force bool) {
 		return
 	}
 
+	// Do not propose while local storage is lagging behind, but keep committing to proposals
+	// of other executors.
+	if state := n.backpressure.state(); state != api.BackpressureNone {
+		n.logger.Debug("not scheduling, storage is lagging behind",
+			"round_lag", n.backpressure.roundLag(),
+			"backpressure", state,
+		)
+		return
+	}
+
+	// Propose the speculatively executed batch if it has been adopted.
+	if sb := n.adoptedSpeculation; sb != nil {
+		n.adoptedSpeculation = nil
+		n.scheduleSpeculativeBatch(sb)
+		return
+	}
+
 	// If the next block will be an epoch transition block, do not propose anything as it will be
 	// reverted anyway (since the committee will change).
 	epochState, err := n.commonNode.Consensus.Beacon().GetFutureEpoch(ctx, n.blockInfo.ConsensusBlock.Height) // TODO: is this height ok?
@@ -479,11 +553,15 @@ func (n *Node) startSchedulingBatch(ctx context.Context, batch []*txpool.TxQueue
 		n.roundResults,
 		hash.Hash{}, // IORoot is ignored as it is yet to be determined.
 		initialBatch,
+		false,
 	)
 	if err != nil {
 		n.logger.Error("runtime batch execution failed",
 			"err", err,
 		)
+		if errors.Is(err, errExecutionFailed) {
+			n.commonNode.TxPool.HandleSchedulingFailed()
+		}
 		// Notify the round worker that the execution failed.
 		n.processedBatchCh <- nil
 		return
@@ -525,36 +603,84 @@ func (n *Node) runtimeExecuteTxBatch(
 	roundResults *roothash.RoundResults,
 	inputRoot hash.Hash,
 	inputs transaction.RawBatch,
+	speculative bool,
 ) (*protocol.RuntimeExecuteTxBatchResponse, error) {
-	// Ensure block round is synced to storage.
-	n.logger.Debug("ensuring block round is synced", "round", blk.Header.Round)
-	if _, err := n.commonNode.Runtime.History().WaitRoundSynced(ctx, blk.Header.Round); err != nil {
-		return nil, err
+	var inMsgs []*message.IncomingMessage
+	switch speculative {
+	case true:
+		// The block is not yet finalized, but its state has already been committed to local
+		// storage. Speculation is only performed when no incoming messages are queued.
+	case false:
+		// Ensure block round is synced to storage.
+		n.logger.Debug("ensuring block round is synced", "round", blk.Header.Round)
+		if _, err := n.commonNode.Runtime.History().WaitRoundSynced(ctx, blk.Header.Round); err != nil {
+			return nil, err
+		}
+
+		// Fetch any incoming messages.
+		var err error
+		inMsgs, err = n.commonNode.Consensus.RootHash().GetIncomingMessageQueue(ctx, &roothash.InMessageQueueRequest{
+			RuntimeID: n.commonNode.Runtime.ID(),
+			Height:    consensusBlk.Height,
+		})
+		if err != nil {
+			n.logger.Error("failed to fetch incoming runtime message queue metadata",
+				"err", err,
+			)
+			return nil, err
+		}
 	}
 
-	// Fetch any incoming messages.
-	inMsgs, err := n.commonNode.Consensus.RootHash().GetIncomingMessageQueue(ctx, &roothash.InMessageQueueRequest{
-		RuntimeID: n.commonNode.Runtime.ID(),
-		Height:    consensusBlk.Height,
-	})
+	// Only request a batch summary when both the runtime and the consensus layer support it. The
+	// feature version is checked at the consensus height of the round so that all executors agree.
+	rtInfo, err := rt.GetInfo(ctx)
 	if err != nil {
-		n.logger.Error("failed to fetch incoming runtime message queue metadata",
-			"err", err,
-		)
 		return nil, err
 	}
+	var batchSummary bool
+	switch rtInfo.Features.BatchSummary {
+	case true:
+		var params *consensus.Parameters
+		params, err = n.commonNode.Consensus.Core().GetParameters(ctx, consensusBlk.Height)
+		if err != nil {
+			n.logger.Error("failed to fetch consensus parameters",
+				"err", err,
+				"height", consensusBlk.Height,
+			)
+			return nil, err
+		}
+		batchSummary = params.Parameters.IsFeatureVersion(migrations.Version243)
+	case false:
+		// Runtimes not supporting batch summaries may be unable to decode them.
+		if roundResults != nil && roundResults.BatchSummary != nil {
+			rr := *roundResults
+			rr.BatchSummary = nil
+			roundResults = &rr
+		}
+	}
+
+	// Only pass the execution deadline to runtimes supporting it, so that batches are cut short
+	// before the round times out.
+	proposerTimeout := state.Runtime.TxnScheduler.ProposerTimeout
+	var executionDeadline time.Duration
+	if rtInfo.Features.TxExecutionTimes {
+		executionDeadline = proposerTimeout
+	}
 
 	rq := &protocol.Body{
 		RuntimeExecuteTxBatchRequest: &protocol.RuntimeExecuteTxBatchRequest{
-			Mode:           mode,
-			ConsensusBlock: *consensusBlk,
-			RoundResults:   roundResults,
-			IORoot:         inputRoot,
-			Inputs:         inputs,
-			InMessages:     inMsgs,
-			Block:          *blk,
-			Epoch:          epoch,
-			MaxMessages:    state.Runtime.Executor.MaxMessages,
+			Mode:              mode,
+			ConsensusBlock:    *consensusBlk,
+			RoundResults:      roundResults,
+			IORoot:            inputRoot,
+			Inputs:            inputs,
+			InMessages:        inMsgs,
+			Block:             *blk,
+			Epoch:             epoch,
+			MaxMessages:       state.Runtime.Executor.MaxMessages,
+			Speculative:       speculative,
+			BatchSummary:      batchSummary,
+			ExecutionDeadline: executionDeadline,
 		},
 	}
 	batchSize.With(n.getMetricLabels()).Observe(float64(len(inputs)))
@@ -571,7 +697,6 @@ func (n *Node) runtimeExecuteTxBatch(
 	// to prevent runtimes from restarting, as abort requests are currently not
 	// supported. Execution shouldn't take a significant amount of time anyway
 	// unless something is seriously wrong.
-	proposerTimeout := state.Runtime.TxnScheduler.ProposerTimeout
 	callCtx, cancelCallFn := context.WithTimeoutCause(
 		context.TODO(), // Replace with ctx once runtimes start supporting abort requests.
 		executeBatchTimeoutFactor*proposerTimeout,
@@ -599,11 +724,14 @@ func (n *Node) runtimeExecuteTxBatch(
 			)
 		}
 		return nil, fmt.Errorf("batch processing aborted by context")
+	case errors.Is(err, protocol.ErrNotReady):
+		// The runtime is restarting, which says nothing about the batch.
+		return nil, err
 	default:
 		n.logger.Error("error while sending batch processing request to runtime",
 			"err", err,
 		)
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", errExecutionFailed, err)
 	}
 	crash.Here(crashPointBatchProcessStartAfter)
 
@@ -614,9 +742,29 @@ func (n *Node) runtimeExecuteTxBatch(
 		return nil, fmt.Errorf("malformed response from runtime")
 	}
 
+	if rtInfo.Features.TxExecutionTimes {
+		n.handleTxExecutionTimes(rsp.RuntimeExecuteTxBatchResponse)
+	}
+
 	return rsp.RuntimeExecuteTxBatchResponse, nil
 }
 
+// handleTxExecutionTimes records the transaction execution times reported by the runtime.
+func (n *Node) handleTxExecutionTimes(rsp *protocol.RuntimeExecuteTxBatchResponse) {
+	if len(rsp.TxExecutionTimes) != len(rsp.TxHashes) {
+		n.logger.Warn("runtime reported execution times for an unexpected number of transactions",
+			"num_txs", len(rsp.TxHashes),
+			"num_times", len(rsp.TxExecutionTimes),
+		)
+		return
+	}
+
+	for _, d := range rsp.TxExecutionTimes {
+		txExecutionTime.With(n.getMetricLabels()).Observe(d.Seconds())
+	}
+	n.commonNode.TxPool.HandleTxsExecutionTimes(rsp.TxHashes, rsp.TxExecutionTimes)
+}
+
 func (n *Node) startProcessingBatch(ctx context.Context, proposal *commitment.Proposal, rank uint64, batch transaction.RawBatch) {
 	// This method runs within its own goroutine and is always stopped before the runtime
 	// worker finishes. Therefore, it is safe to read local round variables (block info, ...).
@@ -636,11 +784,15 @@ func (n *Node) startProcessingBatch(ctx context.Context, proposal *commitment.Pr
 		n.roundResults,
 		proposal.Header.BatchHash,
 		batch,
+		false,
 	)
 	if err != nil {
 		n.logger.Error("runtime batch execution failed",
 			"err", err,
 		)
+		if errors.Is(err, errExecutionFailed) {
+			n.commonNode.TxPool.HandleTxsFailed(proposal.Batch)
+		}
 		// Notify the round worker that the execution failed.
 		n.processedBatchCh <- nil
 		return
@@ -798,6 +950,9 @@ func (n *Node) proposeBatch(
 
 	n.transitionState(StateWaitingForBatch{})
 
+	// Start processing the next round while this round is being finalized.
+	n.maybeStartSpeculation(roundCtx, &ec.Header.Header, processed.proposal.Batch)
+
 	crash.Here(crashPointBatchProposeAfter)
 }
 
@@ -813,6 +968,17 @@ func (n *Node) signAndSubmitCommitment(roundCtx context.Context, ec *commitment.
 
 	tx := roothash.NewExecutorCommitTx(0, nil, n.commonNode.Runtime.ID(), []commitment.ExecutorCommitment{*ec})
 	go func() {
+		if delay := fault.ExecutorCommitDelay(); delay > 0 {
+			n.logger.Warn("delaying executor commit due to an injected fault",
+				"delay", delay,
+			)
+			select {
+			case <-time.After(delay):
+			case <-roundCtx.Done():
+				return
+			}
+		}
+
 		commitErr := consensus.SignAndSubmitTx(roundCtx, n.commonNode.Consensus, n.commonNode.Identity.NodeSigner, tx)
 		switch commitErr {
 		case nil:
@@ -965,8 +1131,11 @@ func (n *Node) nudgeAvailabilityLocked(force bool) {
 	default:
 	}
 
+	// Make sure local storage is not lagging too far behind.
+	storageAvailable := n.backpressure.state() != api.BackpressureHard
+
 	switch {
-	case n.runtimeReady && lastRoundAvailable && n.runtimeTrustSynced && keymanagerAvailable:
+	case n.runtimeReady && lastRoundAvailable && n.runtimeTrustSynced && keymanagerAvailable && storageAvailable:
 		// Executor is ready to process requests.
 		if n.roleProvider.IsAvailable() && !force {
 			break
@@ -1126,6 +1295,87 @@ func (n *Node) handleEvent(ctx context.Context, ev *roothash.Event) {
 	}
 }
 
+// verifyExecutorCommitment verifies the given executor commitment against the current block,
+// except for its signature which must have already been verified by the caller.
+//
+// Verification is performed as critical work in the shared priority pool so that it is not
+// delayed by other resource-intensive work (e.g., checkpoint creation).
+func (n *Node) verifyExecutorCommitment(ctx context.Context, ec *commitment.ExecutorCommitment) error {
+	blk := n.blockInfo.RuntimeBlock
+	rt := n.epoch.GetRuntime()
+	validFor := n.committee.ValidFor
+	epoch := n.epoch
+
+	var err error
+	if perr := workerpool.Shared().Run(ctx, workerpool.ClassCritical, func() {
+		err = commitment.VerifyExecutorCommitmentContent(ctx, blk, rt, validFor, ec, nil, epoch)
+	}); perr != nil {
+		return perr
+	}
+	return err
+}
+
+// verifyExecutorCommitmentSignatures batch verifies the signatures of the given executor
+// commitments, returning the signature verification error for each of the commitments, or nil
+// in case the corresponding signature is valid.
+//
+// Verification is performed as critical work in the shared priority pool so that it is not
+// delayed by other resource-intensive work (e.g., checkpoint creation).
+func (n *Node) verifyExecutorCommitmentSignatures(ctx context.Context, ecs []*commitment.ExecutorCommitment) ([]error, error) {
+	var errs []error
+	if perr := workerpool.Shared().Run(ctx, workerpool.ClassCritical, func() {
+		errs = verifyExecutorCommitmentSignatures(n.commonNode.Runtime.ID(), ecs)
+	}); perr != nil {
+		return nil, perr
+	}
+	return errs, nil
+}
+
+func verifyExecutorCommitmentSignatures(runtimeID common.Namespace, ecs []*commitment.ExecutorCommitment) []error {
+	commits := make([]commitment.ExecutorCommitment, 0, len(ecs))
+	for _, ec := range ecs {
+		commits = append(commits, *ec)
+	}
+	return commitment.VerifyExecutorCommitmentSignatures(runtimeID, commits)
+}
+
+// drainObservedExecutorCommitments returns the given observed executor commitment together with
+// any further observed executor commitments that are already queued, so that their signatures
+// can be verified as a batch.
+func (n *Node) drainObservedExecutorCommitments(ec *commitment.ExecutorCommitment) []*commitment.ExecutorCommitment {
+	ecs := []*commitment.ExecutorCommitment{ec}
+	for len(ecs) < maxObservedCommitmentBatchSize {
+		select {
+		case ec, ok := <-n.ecCh:
+			if !ok {
+				return ecs
+			}
+			ecs = append(ecs, ec)
+		default:
+			return ecs
+		}
+	}
+	return ecs
+}
+
+func (n *Node) handleObservedExecutorCommitments(ctx context.Context, ecs []*commitment.ExecutorCommitment) {
+	errs, err := n.verifyExecutorCommitmentSignatures(ctx, ecs)
+	if err != nil {
+		return
+	}
+
+	for i, ec := range ecs {
+		if errs[i] != nil {
+			n.logger.Debug("ignoring bad observed executor commitment, signature verification failed",
+				"err", errs[i],
+				"node_id", ec.NodeID,
+			)
+			continue
+		}
+		n.handleObservedExecutorCommitment(ctx, ec)
+	}
+}
+
 func (n *Node) handleExecutorCommitment(ctx context.Context, ec *commitment.ExecutorCommitment) {
 	n.logger.Debug("executor commitment",
 		"commitment", ec,
@@ -1176,8 +1426,7 @@ func (n *Node) estimatePoolRank(ctx context.Context, ec *commitment.ExecutorComm
 
 	if observed {
 		// Verify the commitment.
-		rt := n.epoch.GetRuntime()
-		if err := commitment.VerifyExecutorCommitment(ctx, n.blockInfo.RuntimeBlock, rt, n.committee.ValidFor, ec, nil, n.epoch); err != nil {
+		if err := n.verifyExecutorCommitment(ctx, ec); err != nil {
 			n.logger.Debug("ignoring bad executor commitment, verification failed",
 				"err", err,
 				"node_id", ec.NodeID,
@@ -1343,6 +1592,11 @@ func (n *Node) worker() {
 		}
 	}()
 
+	// Apply backpressure when local storage is lagging behind.
+	if n.lagSource != nil {
+		go n.backpressureWorker()
+	}
+
 	// Restart the round worker every time a runtime block is finalized.
 	for {
 		var bi *runtime.BlockInfo
@@ -1357,6 +1611,7 @@ func (n *Node) worker() {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
+				defer func() { n.handlePanic(recover(), n.blockInfo, nil) }()
 				n.roundWorker(ctx)
 				n.drainChannels(ctx)
 			}()
@@ -1370,6 +1625,12 @@ func (n *Node) worker() {
 		// Round worker stopped, so it is safe to update the last block info.
 		n.blockInfo = bi
 
+		// Stop participating in committees once the round in progress has completed.
+		if bi != nil && n.isRetiring() && !n.isRetired() {
+			n.logger.Info("retired, no longer participating in committees")
+			close(n.retiredCh)
+		}
+
 		select {
 		case <-n.stopCh:
 			n.logger.Info("termination requested")
@@ -1398,6 +1659,13 @@ func (n *Node) roundWorker(ctx context.Context) {
 	// Prune proposals.
 	n.proposals.Prune(round)
 
+	if n.isRetired() {
+		n.logger.Debug("skipping round, retired",
+			"round", round,
+		)
+		return
+	}
+
 	// Need to be an executor committee member.
 	n.epoch = n.commonNode.Group.GetEpochSnapshot()
 	if epoch := n.epoch.GetEpochNumber(); epoch != n.blockInfo.Epoch {
@@ -1453,6 +1721,9 @@ func (n *Node) roundWorker(ctx context.Context) {
 		"backup_worker", n.epoch.IsExecutorBackupWorker(),
 	)
 
+	// Adopt or discard the speculatively executed batch for this round, if any.
+	n.resolveSpeculation(ctx)
+
 	// Estimate the pool's highest rank to prevent committing to worse-ranked proposals
 	// that will be rejected by the pool.
 	n.poolRank = uint64(math.MaxUint64)
@@ -1518,8 +1789,9 @@ func (n *Node) roundWorker(ctx context.Context) {
 			// Missing transactions fetched.
 			n.handleMissingTransactions(txs)
 		case ec := <-n.ecCh:
-			// Process observed executor commitments.
-			n.handleObservedExecutorCommitment(ctx, ec)
+			// Process observed executor commitments, verifying signatures of any queued
+			// commitments as a batch.
+			n.handleObservedExecutorCommitments(ctx, n.drainObservedExecutorCommitments(ec))
 		case batch := <-n.processedBatchCh:
 			// Batch processing has either finished or failed.
 			n.handleProcessedBatch(ctx, batch)
@@ -1544,6 +1816,7 @@ func NewNode(
 	commonNode *committee.Node,
 	commonCfg commonWorker.Config,
 	roleProvider registration.RoleProvider,
+	lagSource StorageLagSource,
 ) (*Node, error) {
 	initMetrics()
 
@@ -1555,6 +1828,7 @@ func NewNode(
 		commonNode:       commonNode,
 		commonCfg:        commonCfg,
 		roleProvider:     roleProvider,
+		lagSource:        lagSource,
 		committeeTopic:   committeeTopic,
 		proposals:        newPendingProposals(),
 		ctx:              ctx,
@@ -1562,6 +1836,8 @@ func NewNode(
 		stopCh:           make(chan struct{}),
 		quitCh:           make(chan struct{}),
 		initCh:           make(chan struct{}),
+		retireCh:         make(chan struct{}),
+		retiredCh:        make(chan struct{}),
 		state:            StateWaitingForBatch{},
 		txSync:           txsync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID()),
 		stateTransitions: pubsub.NewBroker(false),
@@ -1572,6 +1848,8 @@ func NewNode(
 		logger:           logging.GetLogger("worker/executor/committee").With("runtime_id", commonNode.Runtime.ID()),
 	}
 
+	n.backpressure.cfg = config.GlobalConfig.Runtime.GetStorageBackpressureConfig(commonNode.Runtime.ID())
+
 	// Register prune handler.
 	commonNode.Runtime.History().Pruner().RegisterHandler(&pruneHandler{commonNode: commonNode})
 
@@ -0,0 +1,104 @@
+package committee
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasisprotocol/oasis-core/go/genesis/tests"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+)
+
+// generateSignedCommitments generates executor commitments for the given number of committee
+// members, each signed by its member.
+func generateSignedCommitments(t testing.TB, runtimeID common.Namespace, n int) []*commitment.ExecutorCommitment {
+	genesisTestHelpers.SetTestChainContext()
+
+	blk := block.NewEmptyBlock(block.NewGenesisBlock(runtimeID, 0), 1, block.Normal)
+
+	var ecs []*commitment.ExecutorCommitment
+	for range n {
+		signer, err := memorySigner.NewSigner(rand.Reader)
+		require.NoError(t, err, "NewSigner")
+
+		var msgsHash, inMsgsHash hash.Hash
+		msgsHash.Empty()
+		inMsgsHash.Empty()
+		ec := &commitment.ExecutorCommitment{
+			NodeID: signer.Public(),
+			Header: commitment.ExecutorCommitmentHeader{
+				SchedulerID: signer.Public(),
+				Header: commitment.ComputeResultsHeader{
+					Round:          blk.Header.Round,
+					PreviousHash:   blk.Header.PreviousHash,
+					IORoot:         &blk.Header.IORoot,
+					StateRoot:      &blk.Header.StateRoot,
+					MessagesHash:   &msgsHash,
+					InMessagesHash: &inMsgsHash,
+				},
+			},
+		}
+		err = ec.Sign(signer, runtimeID)
+		require.NoError(t, err, "Sign")
+		ecs = append(ecs, ec)
+	}
+	return ecs
+}
+
+func TestVerifyExecutorCommitmentSignatures(t *testing.T) {
+	require := require.New(t)
+
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("executor signature verification test"), 0)
+	ecs := generateSignedCommitments(t, runtimeID, 30)
+
+	errs := verifyExecutorCommitmentSignatures(runtimeID, ecs)
+	require.Len(errs, len(ecs))
+	for i, err := range errs {
+		require.NoError(err, "signature %d should be valid", i)
+	}
+
+	// Batch verification must identify the same offenders as individual verification.
+	ecs[3].Signature[0]++
+	ecs[17].Header.Header.Round++
+
+	errs = verifyExecutorCommitmentSignatures(runtimeID, ecs)
+	for i, err := range errs {
+		switch ecs[i].Verify(runtimeID) {
+		case nil:
+			require.NoError(err, "signature %d should be valid", i)
+		default:
+			require.Error(err, "signature %d should be invalid", i)
+		}
+	}
+	require.Error(errs[3])
+	require.Error(errs[17])
+}
+
+func BenchmarkVerifyExecutorCommitmentSignatures(b *testing.B) {
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("executor signature verification benchmark"), 0)
+	ecs := generateSignedCommitments(b, runtimeID, 30)
+
+	b.Run("Individual/30", func(b *testing.B) {
+		for b.Loop() {
+			for _, ec := range ecs {
+				if err := ec.Verify(runtimeID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("Batch/30", func(b *testing.B) {
+		for b.Loop() {
+			for _, err := range verifyExecutorCommitmentSignatures(runtimeID, ecs) {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
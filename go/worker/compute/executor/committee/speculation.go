@@ -0,0 +1,332 @@
+package committee
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+	"github.com/oasisprotocol/oasis-core/go/runtime/txpool"
+	scheduler "github.com/oasisprotocol/oasis-core/go/scheduler/api"
+)
+
+const (
+	speculationAdopted   = "adopted"
+	speculationDiscarded = "discarded"
+)
+
+// speculativeBatch is a batch for the next round that was scheduled and executed speculatively
+// on top of this node's results for the current round, before the current round was finalized.
+type speculativeBatch struct {
+	// parent is the predicted header of the block the batch was executed on top of.
+	parent block.Header
+	// epoch is the epoch in which the batch was executed.
+	epoch beacon.EpochTime
+	// roundResults are the predicted results of the parent round.
+	roundResults *roothash.RoundResults
+
+	startTime time.Time
+	done      chan struct{}
+
+	// Set once speculative execution completes.
+	rsp *protocol.RuntimeExecuteTxBatchResponse
+	err error
+}
+
+// predictBlockHeader predicts the header of the block that will be finalized in case the given
+// compute results are accepted.
+func predictBlockHeader(prev *block.Block, hdr *commitment.ComputeResultsHeader, timestamp uint64) block.Header {
+	blk := block.NewEmptyBlock(prev, timestamp, block.Normal)
+	blk.Header.IORoot = *hdr.IORoot
+	blk.Header.StateRoot = *hdr.StateRoot
+	blk.Header.MessagesHash = *hdr.MessagesHash
+	blk.Header.InMessagesHash = *hdr.InMessagesHash
+	return blk.Header
+}
+
+// validate checks whether the speculative batch can be adopted given the finalized parent block,
+// the current epoch and the results of the parent round.
+func (sb *speculativeBatch) validate(blk *block.Block, epoch beacon.EpochTime, roundResults *roothash.RoundResults) error {
+	if sb.err != nil {
+		return fmt.Errorf("speculative execution failed: %w", sb.err)
+	}
+	if blk.Header.HeaderType != block.Normal {
+		return fmt.Errorf("parent round not finalized normally (header type: %d)", blk.Header.HeaderType)
+	}
+
+	// The block timestamp cannot be predicted, so it is ignored.
+	hdr := blk.Header
+	hdr.Timestamp = sb.parent.Timestamp
+	if h1, h2 := hdr.EncodedHash(), sb.parent.EncodedHash(); !h1.Equal(&h2) {
+		return fmt.Errorf("finalized block differs from predicted block")
+	}
+
+	if epoch != sb.epoch {
+		return fmt.Errorf("epoch changed (expected: %d got: %d)", sb.epoch, epoch)
+	}
+	if !bytes.Equal(cbor.Marshal(roundResults), cbor.Marshal(sb.roundResults)) {
+		return fmt.Errorf("round results differ from predicted round results")
+	}
+
+	return nil
+}
+
+// toProcessedBatch rebases the speculative results on top of the finalized parent block.
+func (sb *speculativeBatch) toProcessedBatch(blk *block.Block, id signature.PublicKey, rank uint64) *processedBatch {
+	prevHash := blk.Header.EncodedHash()
+
+	computed := sb.rsp.Batch
+	computed.Header.PreviousHash = prevHash
+
+	return &processedBatch{
+		proposal: &commitment.Proposal{
+			NodeID: id,
+			Header: commitment.ProposalHeader{
+				Round:        blk.Header.Round + 1,
+				PreviousHash: prevHash,
+				BatchHash:    sb.rsp.TxInputRoot,
+			},
+			Batch: sb.rsp.TxHashes,
+		},
+		rank:            rank,
+		computed:        &computed,
+		txInputWriteLog: sb.rsp.TxInputWriteLog,
+	}
+}
+
+// maybeStartSpeculation starts speculative scheduling and execution of the next round on top of
+// the given (not yet finalized) results of the current round in case round pipelining is enabled
+// and this node is the primary transaction scheduler for the next round.
+func (n *Node) maybeStartSpeculation(ctx context.Context, hdr *commitment.ComputeResultsHeader, proposed []hash.Hash) {
+	if n.speculation != nil {
+		return
+	}
+	if !n.rtState.Runtime.Executor.IsPipeliningEnabled() {
+		return
+	}
+	// Speculative results are rebased on top of the finalized block which would invalidate
+	// any RAK signatures.
+	if n.rtState.Runtime.TEEHardware != node.TEEHardwareInvalid {
+		return
+	}
+
+	// Only the primary transaction scheduler of the next round speculates.
+	round := n.blockInfo.RuntimeBlock.Header.Round + 2
+	id := n.commonNode.Identity.NodeSigner.Public()
+	if rank, ok := n.committee.SchedulerRank(round, id); !ok || rank != 0 {
+		return
+	}
+
+	// Results of emitted and incoming runtime messages are only known after finalization.
+	if !hdr.MessagesHash.IsEmpty() || hdr.InMessagesCount > 0 {
+		n.logger.Debug("not speculating, round contains runtime messages")
+		return
+	}
+	inMsgMeta, err := n.commonNode.Consensus.RootHash().GetIncomingMessageQueueMeta(ctx, &roothash.RuntimeRequest{
+		RuntimeID: n.commonNode.Runtime.ID(),
+		Height:    consensus.HeightLatest,
+	})
+	if err != nil {
+		n.logger.Error("failed to fetch incoming runtime message queue metadata",
+			"err", err,
+		)
+		return
+	}
+	if inMsgMeta.Size > 0 {
+		n.logger.Debug("not speculating, incoming runtime messages are queued")
+		return
+	}
+
+	roundResults, err := n.predictRoundResults(ctx)
+	if err != nil {
+		n.logger.Error("failed to predict round results",
+			"err", err,
+		)
+		return
+	}
+
+	rtInfo, err := n.rt.GetInfo(ctx)
+	if err != nil {
+		n.logger.Warn("not speculating, the runtime is broken",
+			"err", err,
+		)
+		return
+	}
+	if !rtInfo.Features.HasScheduleControl() {
+		return
+	}
+
+	sb := &speculativeBatch{
+		parent:       predictBlockHeader(n.blockInfo.RuntimeBlock, hdr, uint64(time.Now().Unix())),
+		epoch:        n.blockInfo.Epoch,
+		roundResults: roundResults,
+		startTime:    time.Now(),
+		done:         make(chan struct{}),
+	}
+	n.speculation = sb
+
+	n.logger.Debug("speculatively scheduling next round",
+		"round", round,
+		"predicted_state_root", sb.parent.StateRoot,
+	)
+
+	// Transactions proposed in the current round are still in the queue until the round is
+	// finalized, so make sure to skip them.
+	batch := n.commonNode.TxPool.GetSchedulingSuggestion(rtInfo.Features.ScheduleControl.InitialBatchSize)
+	batch = slices.DeleteFunc(batch, func(tx *txpool.TxQueueMeta) bool {
+		return slices.Contains(proposed, tx.Hash())
+	})
+	initialBatch := make([][]byte, 0, len(batch))
+	for _, tx := range batch {
+		initialBatch = append(initialBatch, tx.Raw())
+	}
+
+	// Capture round-local state as the speculation outlives the current round.
+	var (
+		rt           = n.rt
+		rtState      = n.rtState
+		consensusBlk = n.blockInfo.ConsensusBlock
+	)
+	go func() {
+		defer close(sb.done)
+		defer n.commonNode.TxPool.FinishScheduling()
+
+		blk := &block.Block{Header: sb.parent}
+		sb.rsp, sb.err = n.runtimeExecuteTxBatch(
+			n.ctx,
+			rt,
+			protocol.ExecutionModeSchedule,
+			sb.epoch,
+			consensusBlk,
+			blk,
+			rtState,
+			roundResults,
+			hash.Hash{}, // IORoot is ignored as it is yet to be determined.
+			initialBatch,
+			true,
+		)
+		if errors.Is(sb.err, errExecutionFailed) {
+			n.commonNode.TxPool.HandleSchedulingFailed()
+		}
+	}()
+}
+
+// resolveSpeculation waits for any speculative execution of the current round to complete and
+// either adopts or discards its results based on the finalized parent block.
+func (n *Node) resolveSpeculation(ctx context.Context) {
+	n.adoptedSpeculation = nil
+
+	sb := n.speculation
+	if sb == nil {
+		return
+	}
+	n.speculation = nil
+
+	select {
+	case <-sb.done:
+	case <-ctx.Done():
+		speculativeBatchCount.With(n.getSpeculationMetricLabels(speculationDiscarded)).Inc()
+		return
+	}
+
+	err := func() error {
+		if n.rank != 0 {
+			return fmt.Errorf("not the primary transaction scheduler")
+		}
+		if err := sb.validate(n.blockInfo.RuntimeBlock, n.blockInfo.Epoch, n.roundResults); err != nil {
+			return err
+		}
+
+		// Incoming messages may have been queued since speculation started.
+		inMsgMeta, err := n.commonNode.Consensus.RootHash().GetIncomingMessageQueueMeta(ctx, &roothash.RuntimeRequest{
+			RuntimeID: n.commonNode.Runtime.ID(),
+			Height:    n.blockInfo.ConsensusBlock.Height,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch incoming runtime message queue metadata: %w", err)
+		}
+		if inMsgMeta.Size > 0 {
+			return fmt.Errorf("incoming runtime messages are queued")
+		}
+		return nil
+	}()
+	if err != nil {
+		n.logger.Info("discarding speculative batch",
+			"round", sb.parent.Round+1,
+			"err", err,
+		)
+		speculativeBatchCount.With(n.getSpeculationMetricLabels(speculationDiscarded)).Inc()
+		return
+	}
+
+	n.logger.Info("adopting speculative batch",
+		"round", sb.parent.Round+1,
+		"batch_size", len(sb.rsp.TxHashes),
+	)
+	speculativeBatchCount.With(n.getSpeculationMetricLabels(speculationAdopted)).Inc()
+	n.adoptedSpeculation = sb
+}
+
+// scheduleSpeculativeBatch proposes the adopted speculative batch instead of scheduling a new one.
+func (n *Node) scheduleSpeculativeBatch(sb *speculativeBatch) {
+	// Apply the transaction pool changes that were deferred until the batch has been adopted.
+	n.commonNode.TxPool.RejectTxs(sb.rsp.TxRejectHashes)
+	_, _ = n.commonNode.TxPool.PromoteProposedBatch(sb.rsp.TxHashes)
+
+	done := make(chan struct{})
+	close(done)
+
+	n.transitionState(StateProcessingBatch{
+		mode:           protocol.ExecutionModeSchedule,
+		rank:           n.rank,
+		batchStartTime: sb.startTime,
+		cancelFn:       func(error) {},
+		done:           done,
+	})
+
+	// Submit response to the round worker.
+	n.processedBatchCh <- sb.toProcessedBatch(n.blockInfo.RuntimeBlock, n.commonNode.Identity.NodeSigner.Public(), n.rank)
+}
+
+// predictRoundResults predicts the results of the current round assuming that all executor
+// workers submit matching commitments and the round emits no runtime messages.
+func (n *Node) predictRoundResults(ctx context.Context) (*roothash.RoundResults, error) {
+	var results roothash.RoundResults
+	seen := make(map[signature.PublicKey]struct{})
+	for _, member := range n.committee.Members {
+		if member.Role != scheduler.RoleWorker {
+			continue
+		}
+		if _, ok := seen[member.PublicKey]; ok {
+			continue
+		}
+		seen[member.PublicKey] = struct{}{}
+
+		nd, err := n.commonNode.Consensus.Registry().GetNode(ctx, &registry.IDQuery{
+			ID:     member.PublicKey,
+			Height: consensus.HeightLatest,
+		})
+		switch {
+		case err == nil:
+		case errors.Is(err, registry.ErrNoSuchNode):
+			continue
+		default:
+			return nil, fmt.Errorf("failed to get node %s: %w", member.PublicKey, err)
+		}
+		results.GoodComputeEntities = append(results.GoodComputeEntities, nd.EntityID)
+	}
+	return &results, nil
+}
@@ -0,0 +1,160 @@
+package committee
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/commitment"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+)
+
+func TestSpeculativeBatch(t *testing.T) {
+	var ns common.Namespace
+	require.NoError(t, ns.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"))
+
+	prev := block.NewGenesisBlock(ns, 1000)
+
+	var ioRoot, stateRoot, emptyRoot hash.Hash
+	ioRoot.FromBytes([]byte("io root"))
+	stateRoot.FromBytes([]byte("state root"))
+	emptyRoot.Empty()
+	hdr := &commitment.ComputeResultsHeader{
+		Round:          prev.Header.Round + 1,
+		PreviousHash:   prev.Header.EncodedHash(),
+		IORoot:         &ioRoot,
+		StateRoot:      &stateRoot,
+		MessagesHash:   &emptyRoot,
+		InMessagesHash: &emptyRoot,
+	}
+
+	goodEntity := signature.NewPublicKey("1234567890000000000000000000000000000000000000000000000000000000")
+
+	// The finalized block has a different timestamp than predicted.
+	newFinalized := func() *block.Block {
+		blk := block.NewEmptyBlock(prev, 1010, block.Normal)
+		blk.Header.IORoot = *hdr.IORoot
+		blk.Header.StateRoot = *hdr.StateRoot
+		return blk
+	}
+
+	// Speculatively execute the next round on top of the predicted block.
+	newBatch := func() *speculativeBatch {
+		parent := predictBlockHeader(prev, hdr, 1005)
+		var nextIORoot, nextStateRoot hash.Hash
+		nextIORoot.FromBytes([]byte("next io root"))
+		nextStateRoot.FromBytes([]byte("next state root"))
+		return &speculativeBatch{
+			parent: parent,
+			epoch:  3,
+			roundResults: &roothash.RoundResults{
+				GoodComputeEntities: []signature.PublicKey{goodEntity},
+			},
+			rsp: &protocol.RuntimeExecuteTxBatchResponse{
+				Batch: protocol.ComputedBatch{
+					Header: commitment.ComputeResultsHeader{
+						Round:          parent.Round + 1,
+						PreviousHash:   parent.EncodedHash(),
+						IORoot:         &nextIORoot,
+						StateRoot:      &nextStateRoot,
+						MessagesHash:   &emptyRoot,
+						InMessagesHash: &emptyRoot,
+					},
+				},
+				TxHashes:    []hash.Hash{hash.NewFromBytes([]byte("tx"))},
+				TxInputRoot: nextIORoot,
+			},
+		}
+	}
+
+	t.Run("Adopt", func(t *testing.T) {
+		require := require.New(t)
+
+		sb := newBatch()
+		finalized := newFinalized()
+		err := sb.validate(finalized, 3, &roothash.RoundResults{
+			GoodComputeEntities: []signature.PublicKey{goodEntity},
+		})
+		require.NoError(err, "speculative batch should be adopted")
+
+		id := signature.NewPublicKey("abcdef0000000000000000000000000000000000000000000000000000000000")
+		processed := sb.toProcessedBatch(finalized, id, 0)
+		finalizedHash := finalized.Header.EncodedHash()
+		require.Equal(id, processed.proposal.NodeID)
+		require.Equal(finalized.Header.Round+1, processed.proposal.Header.Round)
+		require.Equal(finalizedHash, processed.proposal.Header.PreviousHash)
+		require.Equal(sb.rsp.TxInputRoot, processed.proposal.Header.BatchHash)
+		require.Equal(sb.rsp.TxHashes, processed.proposal.Batch)
+		require.Equal(finalized.Header.Round+1, processed.computed.Header.Round)
+		require.Equal(finalizedHash, processed.computed.Header.PreviousHash)
+		require.Equal(*sb.rsp.Batch.Header.StateRoot, *processed.computed.Header.StateRoot)
+
+		// The speculative results themselves must not be modified.
+		require.Equal(sb.parent.EncodedHash(), sb.rsp.Batch.Header.PreviousHash)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*block.Block, *roothash.RoundResults, *speculativeBatch)
+		epoch  beacon.EpochTime
+	}{
+		{
+			name: "discrepancy changed the round outcome",
+			modify: func(blk *block.Block, _ *roothash.RoundResults, _ *speculativeBatch) {
+				// Backup workers resolved the discrepancy in favor of different results.
+				blk.Header.StateRoot.FromBytes([]byte("other state root"))
+			},
+		},
+		{
+			name: "discrepancy resolved with bad compute entities",
+			modify: func(_ *block.Block, results *roothash.RoundResults, _ *speculativeBatch) {
+				// Same results, but some workers submitted incorrect results.
+				results.BadComputeEntities = []signature.PublicKey{
+					signature.NewPublicKey("ffffff0000000000000000000000000000000000000000000000000000000000"),
+				}
+			},
+		},
+		{
+			name: "round failed",
+			modify: func(blk *block.Block, _ *roothash.RoundResults, _ *speculativeBatch) {
+				blk.Header.HeaderType = block.RoundFailed
+				blk.Header.IORoot.Empty()
+			},
+		},
+		{
+			name:  "epoch transition",
+			epoch: 4,
+		},
+		{
+			name: "speculative execution failed",
+			modify: func(_ *block.Block, _ *roothash.RoundResults, sb *speculativeBatch) {
+				sb.err = errors.New("runtime failure")
+			},
+		},
+	} {
+		t.Run("Discard/"+tc.name, func(t *testing.T) {
+			sb := newBatch()
+			finalized := newFinalized()
+			results := &roothash.RoundResults{
+				GoodComputeEntities: []signature.PublicKey{goodEntity},
+			}
+			if tc.modify != nil {
+				tc.modify(finalized, results, sb)
+			}
+			epoch := sb.epoch
+			if tc.epoch != 0 {
+				epoch = tc.epoch
+			}
+
+			err := sb.validate(finalized, epoch, results)
+			require.Error(t, err, "speculative batch should be discarded")
+		})
+	}
+}
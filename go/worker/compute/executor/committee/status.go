@@ -1,6 +1,7 @@
 package committee
 
 import (
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/api"
 )
 
@@ -10,7 +11,17 @@ func (n *Node) GetStatus() (*api.Status, error) {
 	defer n.commonNode.CrossNode.Unlock()
 
 	var status api.Status
+	suspended, reason := n.commonNode.SuspendedLocked()
 	switch {
+	case n.isRetired():
+		status.Status = api.StatusStateRetired
+	case n.isRetiring():
+		status.Status = api.StatusStateRetiring
+	case suspended:
+		status.Status = api.StatusStateRuntimeSuspended
+		if reason != roothash.SuspensionReasonNone {
+			status.SuspensionReason = &reason
+		}
 	case !n.runtimeReady:
 		status.Status = api.StatusStateWaitingRuntime
 	case !n.runtimeTrustSynced:
@@ -19,5 +30,8 @@ func (n *Node) GetStatus() (*api.Status, error) {
 		status.Status = api.StatusStateReady
 	}
 
+	status.StorageRoundLag = n.backpressure.roundLag()
+	status.Backpressure = n.backpressure.state()
+
 	return &status, nil
 }
@@ -12,6 +12,7 @@ import (
 	committeeCommon "github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/compute/executor/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
+	workerStorage "github.com/oasisprotocol/oasis-core/go/worker/storage"
 )
 
 // Worker is an executor worker handling many runtimes.
@@ -20,6 +21,7 @@ type Worker struct {
 
 	commonWorker *workerCommon.Worker
 	registration *registration.Worker
+	storage      *workerStorage.Worker
 
 	runtimes map[common.Namespace]*committee.Node
 
@@ -134,6 +136,33 @@ func (w *Worker) GetRuntime(id common.Namespace) *committee.Node {
 	return w.runtimes[id]
 }
 
+// Retire requests all runtimes to stop participating in executor committees once their current
+// rounds complete.
+//
+// The returned channel is closed after all runtimes stopped participating in committees.
+func (w *Worker) Retire() <-chan struct{} {
+	ch := make(chan struct{})
+	if !w.enabled {
+		close(ch)
+		return ch
+	}
+
+	w.logger.Info("retiring executor worker")
+
+	go func() {
+		defer close(ch)
+
+		for _, rt := range w.runtimes {
+			select {
+			case <-rt.Retire():
+			case <-rt.Quit():
+			}
+		}
+	}()
+
+	return ch
+}
+
 func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	id := commonNode.Runtime.ID()
 	w.logger.Info("registering new runtime",
@@ -145,11 +174,20 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 		return fmt.Errorf("failed to create role provider: %w", err)
 	}
 
+	// Use the storage worker as the source of storage round lag updates, if available.
+	var lagSource committee.StorageLagSource
+	if w.storage != nil {
+		if sn := w.storage.GetRuntime(id); sn != nil {
+			lagSource = sn
+		}
+	}
+
 	// Create committee node for the given runtime.
 	node, err := committee.NewNode(
 		commonNode,
 		w.commonWorker.GetConfig(),
 		rp,
+		lagSource,
 	)
 	if err != nil {
 		return err
@@ -169,6 +207,7 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 func New(
 	commonWorker *workerCommon.Worker,
 	registration *registration.Worker,
+	storage *workerStorage.Worker,
 ) (*Worker, error) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 
@@ -181,10 +220,17 @@ func New(
 		enabled = false
 	}
 
+	// Do not participate in executor committees in case the compute role has been removed via the
+	// control API.
+	if enabled && registration.RemovedRoles()&node.RoleComputeWorker != 0 {
+		enabled = false
+	}
+
 	w := &Worker{
 		enabled:      enabled,
 		commonWorker: commonWorker,
 		registration: registration,
+		storage:      storage,
 		runtimes:     make(map[common.Namespace]*committee.Node),
 		ctx:          ctx,
 		cancelCtx:    cancelCtx,
@@ -197,8 +243,14 @@ func New(
 		return w, nil
 	}
 
-	// Register all configured runtimes.
-	for _, rt := range commonWorker.GetRuntimes() {
+	// Register all configured runtimes, except the ones that only follow the runtime state.
+	for id, rt := range commonWorker.GetRuntimes() {
+		if config.GlobalConfig.Runtime.IsReadOnly(id) {
+			w.logger.Info("not registering read-only runtime",
+				"runtime_id", id,
+			)
+			continue
+		}
 		if err := w.registerRuntime(rt); err != nil {
 			return nil, err
 		}
@@ -9,6 +9,9 @@ import (
 
 	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/version"
 	"github.com/oasisprotocol/oasis-core/go/keymanager/churp"
 	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
@@ -141,6 +144,9 @@ type SecretsWorkerStatus struct {
 	// EphemeralSecrets are the ephemeral secret generation and replication stats.
 	EphemeralSecrets EphemeralSecretStats `json:"ephemeral_secrets"`
 
+	// Replication is the master and ephemeral secrets replication status.
+	Replication ReplicationStatus `json:"replication"`
+
 	// PrivatePeers is a list of peers that are always allowed to call protected methods.
 	PrivatePeers []core.PeerID `json:"private_peers"`
 }
@@ -175,6 +181,27 @@ type EphemeralSecretStats struct {
 	LastGenerated beacon.EpochTime `json:"last_generated_epoch"`
 }
 
+// ReplicationStatus is the master and ephemeral secrets replication status.
+type ReplicationStatus struct {
+	// Generation is the generation of the latest master secret available to the enclave.
+	Generation uint64 `json:"generation"`
+
+	// GenerationsBehind is the number of master secret generations that the enclave is missing
+	// compared to the key manager committee.
+	GenerationsBehind uint64 `json:"generations_behind"`
+
+	// Source is the ID of the node from which secrets were last replicated.
+	Source *signature.PublicKey `json:"source,omitempty"`
+
+	// LastReplication is the time of the last successful replication. In case no secrets were
+	// replicated yet, it will be the zero timestamp.
+	LastReplication time.Time `json:"last_replication"`
+
+	// Stalled is true iff the enclave is behind and replication has not progressed for longer
+	// than the configured stall timeout.
+	Stalled bool `json:"stalled"`
+}
+
 // ChurpStatus represents the status of the key manager CHURP extension.
 type ChurpStatus struct {
 	// Schemes is a list of CHURP scheme configurations.
@@ -187,6 +214,55 @@ type ChurpSchemeStatus struct {
 	Status *churp.Status `json:"status,omitempty"`
 }
 
+// AccessAuditEvent is a change of the key manager access policy or status observed by the key
+// manager worker.
+type AccessAuditEvent struct {
+	// Index is the monotonically increasing index of the event in the audit log.
+	Index uint64 `json:"index"`
+
+	// Height is the consensus height at which the change was observed.
+	Height int64 `json:"height"`
+
+	// Time is the local time at which the change was observed.
+	Time time.Time `json:"time"`
+
+	// Generation is the generation of the latest master secret.
+	Generation uint64 `json:"generation"`
+
+	// PolicySerial is the serial number of the key manager policy, if any.
+	PolicySerial uint32 `json:"policy_serial,omitempty"`
+
+	// RuntimesAdded are the runtimes that were granted access to key derivation.
+	RuntimesAdded []common.Namespace `json:"runtimes_added,omitempty"`
+
+	// RuntimesRemoved are the runtimes that lost access to key derivation.
+	RuntimesRemoved []common.Namespace `json:"runtimes_removed,omitempty"`
+
+	// EnclavesAdded are the key manager enclaves that were added to the policy.
+	EnclavesAdded []sgx.EnclaveIdentity `json:"enclaves_added,omitempty"`
+
+	// EnclavesRemoved are the key manager enclaves that were removed from the policy.
+	EnclavesRemoved []sgx.EnclaveIdentity `json:"enclaves_removed,omitempty"`
+}
+
+// GetAccessAuditLogRequest is a GetAccessAuditLog request.
+type GetAccessAuditLogRequest struct {
+	// FromIndex is the index of the first returned event.
+	FromIndex uint64 `json:"from_index,omitempty"`
+
+	// Limit is the maximum number of returned events. Zero means no limit.
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// KeyManagerWorker is the key manager worker control API interface.
+type KeyManagerWorker interface {
+	// GetAccessAuditLog returns the retained access audit events, starting at the given index.
+	GetAccessAuditLog(ctx context.Context, request *GetAccessAuditLogRequest) ([]*AccessAuditEvent, error)
+
+	// WatchAccessAuditLog returns a channel that produces a stream of new access audit events.
+	WatchAccessAuditLog(ctx context.Context) (<-chan *AccessAuditEvent, pubsub.ClosableSubscription, error)
+}
+
 // RPCAccessController handles the authorization of enclave RPC calls.
 type RPCAccessController interface {
 	// Methods returns a list of allowed methods.
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+)
+
+var (
+	// serviceName is the gRPC service name.
+	serviceName = cmnGrpc.NewServiceName("KeyManagerWorker")
+
+	// methodGetAccessAuditLog is the GetAccessAuditLog method.
+	methodGetAccessAuditLog = serviceName.NewMethod("GetAccessAuditLog", &GetAccessAuditLogRequest{})
+	// methodWatchAccessAuditLog is the WatchAccessAuditLog method.
+	methodWatchAccessAuditLog = serviceName.NewMethod("WatchAccessAuditLog", nil)
+
+	// serviceDesc is the gRPC service descriptor.
+	serviceDesc = grpc.ServiceDesc{
+		ServiceName: string(serviceName),
+		HandlerType: (*KeyManagerWorker)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: methodGetAccessAuditLog.ShortName(),
+				Handler:    handlerGetAccessAuditLog,
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    methodWatchAccessAuditLog.ShortName(),
+				Handler:       handlerWatchAccessAuditLog,
+				ServerStreams: true,
+			},
+		},
+	}
+)
+
+func handlerGetAccessAuditLog(
+	srv any,
+	ctx context.Context,
+	dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	rq := new(GetAccessAuditLogRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyManagerWorker).GetAccessAuditLog(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetAccessAuditLog.FullName(),
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(KeyManagerWorker).GetAccessAuditLog(ctx, req.(*GetAccessAuditLogRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerWatchAccessAuditLog(srv any, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(KeyManagerWorker).WatchAccessAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RegisterService registers a new key manager worker service with the given gRPC server.
+func RegisterService(server *grpc.Server, service KeyManagerWorker) {
+	server.RegisterService(&serviceDesc, service)
+}
+
+// Client is a gRPC key manager worker client.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a new gRPC key manager worker client.
+func NewClient(c *grpc.ClientConn) *Client {
+	return &Client{
+		conn: c,
+	}
+}
+
+func (c *Client) GetAccessAuditLog(ctx context.Context, req *GetAccessAuditLogRequest) ([]*AccessAuditEvent, error) {
+	var rsp []*AccessAuditEvent
+	if err := c.conn.Invoke(ctx, methodGetAccessAuditLog.FullName(), req, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *Client) WatchAccessAuditLog(ctx context.Context) (<-chan *AccessAuditEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[0], methodWatchAccessAuditLog.FullName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *AccessAuditEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev AccessAuditEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
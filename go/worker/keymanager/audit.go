@@ -0,0 +1,248 @@
+package keymanager
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/keyformat"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
+	"github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
+)
+
+// auditStoreName is the name of the persistent store namespace prefix used for storing the access
+// audit log.
+const auditStoreName = "worker/keymanager/audit"
+
+var (
+	// auditEventKeyFmt is the key format used for audit events.
+	//
+	// Key format is: 0x01 <index (uint64)>
+	// Value is the CBOR-serialized audit event.
+	auditEventKeyFmt = keyformat.New(0x01, uint64(0))
+	// auditStateKeyFmt is the key format used for the audit log state.
+	//
+	// Value is the CBOR-serialized audit log state.
+	auditStateKeyFmt = keyformat.New(0x02)
+)
+
+// auditState is the persisted audit log state.
+type auditState struct {
+	// FirstIndex is the index of the oldest retained event.
+	FirstIndex uint64 `json:"first_index"`
+	// NextIndex is the index of the next event.
+	NextIndex uint64 `json:"next_index"`
+
+	// Generation is the last observed master secret generation.
+	Generation uint64 `json:"generation"`
+	// PolicySerial is the last observed policy serial number.
+	PolicySerial uint32 `json:"policy_serial"`
+	// Runtimes are the last observed runtimes allowed to query the key manager.
+	Runtimes []common.Namespace `json:"runtimes"`
+	// Enclaves are the last observed key manager enclaves.
+	Enclaves []sgx.EnclaveIdentity `json:"enclaves"`
+}
+
+// auditLog is a bounded persistent log of key manager access policy and status changes.
+type auditLog struct {
+	mu sync.RWMutex
+
+	store     *persistent.ServiceStore
+	maxEvents uint64
+	state     *auditState
+	notifier  *pubsub.Broker
+
+	logger *logging.Logger
+}
+
+// newAuditLog creates a new access audit log for the given key manager runtime, retaining at most
+// the given number of events.
+func newAuditLog(commonStore *persistent.CommonStore, runtimeID common.Namespace, maxEvents uint64) (*auditLog, error) {
+	l := &auditLog{
+		store:     commonStore.GetServiceStore(auditStoreName + "/" + runtimeID.String()),
+		maxEvents: maxEvents,
+		notifier:  pubsub.NewBroker(false),
+		logger:    logging.GetLogger("worker/keymanager/audit"),
+	}
+
+	var state auditState
+	switch err := l.store.GetCBOR(auditStateKeyFmt.Encode(), &state); err {
+	case nil:
+		l.state = &state
+	case persistent.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("failed to load audit log state: %w", err)
+	}
+
+	return l, nil
+}
+
+// Observe records the changes between the given key manager status and the last observed one.
+func (l *auditLog) Observe(height int64, status *secrets.Status) error {
+	if l.maxEvents == 0 {
+		return nil
+	}
+
+	state := auditState{
+		Generation: status.Generation,
+		Runtimes:   make([]common.Namespace, 0),
+		Enclaves:   make([]sgx.EnclaveIdentity, 0),
+	}
+	if status.Policy != nil {
+		state.PolicySerial = status.Policy.Policy.Serial
+		for id, enc := range status.Policy.Policy.Enclaves {
+			state.Enclaves = append(state.Enclaves, id)
+			for rtID := range enc.MayQuery {
+				state.Runtimes = append(state.Runtimes, rtID)
+			}
+		}
+	}
+	sortNamespaces(state.Runtimes)
+	state.Runtimes = slices.Compact(state.Runtimes)
+	sortEnclaves(state.Enclaves)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prev := l.state
+	if prev == nil {
+		prev = &auditState{}
+	}
+	state.FirstIndex, state.NextIndex = prev.FirstIndex, prev.NextIndex
+
+	ev := api.AccessAuditEvent{
+		Index:           state.NextIndex,
+		Height:          height,
+		Time:            time.Now(),
+		Generation:      state.Generation,
+		PolicySerial:    state.PolicySerial,
+		RuntimesAdded:   difference(state.Runtimes, prev.Runtimes),
+		RuntimesRemoved: difference(prev.Runtimes, state.Runtimes),
+		EnclavesAdded:   difference(state.Enclaves, prev.Enclaves),
+		EnclavesRemoved: difference(prev.Enclaves, state.Enclaves),
+	}
+	if l.state != nil &&
+		ev.Generation == prev.Generation &&
+		ev.PolicySerial == prev.PolicySerial &&
+		len(ev.RuntimesAdded)+len(ev.RuntimesRemoved)+len(ev.EnclavesAdded)+len(ev.EnclavesRemoved) == 0 {
+		return nil
+	}
+
+	state.NextIndex++
+	if err := l.store.PutManyCBOR(
+		[][]byte{auditEventKeyFmt.Encode(ev.Index), auditStateKeyFmt.Encode()},
+		[]any{&ev, &state},
+	); err != nil {
+		return fmt.Errorf("failed to persist audit event: %w", err)
+	}
+	l.state = &state
+
+	l.logger.Info("key manager access changed",
+		"index", ev.Index,
+		"height", ev.Height,
+		"generation", ev.Generation,
+		"policy_serial", ev.PolicySerial,
+		"runtimes_added", ev.RuntimesAdded,
+		"runtimes_removed", ev.RuntimesRemoved,
+		"enclaves_added", ev.EnclavesAdded,
+		"enclaves_removed", ev.EnclavesRemoved,
+	)
+
+	l.notifier.Broadcast(&ev)
+
+	if err := l.pruneLocked(); err != nil {
+		l.logger.Warn("failed to prune audit log",
+			"err", err,
+		)
+	}
+
+	return nil
+}
+
+func (l *auditLog) pruneLocked() error {
+	if l.state.NextIndex-l.state.FirstIndex <= l.maxEvents {
+		return nil
+	}
+
+	state := *l.state
+	state.FirstIndex = state.NextIndex - l.maxEvents
+
+	keys := make([][]byte, 0, state.FirstIndex-l.state.FirstIndex)
+	for idx := l.state.FirstIndex; idx < state.FirstIndex; idx++ {
+		keys = append(keys, auditEventKeyFmt.Encode(idx))
+	}
+	// Update the state first so that a failure to delete events can only leave unreachable
+	// events behind.
+	if err := l.store.PutCBOR(auditStateKeyFmt.Encode(), &state); err != nil {
+		return err
+	}
+	l.state = &state
+
+	return l.store.DeleteMany(keys)
+}
+
+// Events returns the retained audit events, starting at the given index.
+func (l *auditLog) Events(fromIndex uint64, limit uint64) ([]*api.AccessAuditEvent, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.state == nil {
+		return nil, nil
+	}
+
+	fromIndex = max(fromIndex, l.state.FirstIndex)
+	toIndex := l.state.NextIndex
+	if limit > 0 && fromIndex < toIndex && toIndex-fromIndex > limit {
+		toIndex = fromIndex + limit
+	}
+
+	var events []*api.AccessAuditEvent
+	for idx := fromIndex; idx < toIndex; idx++ {
+		var ev api.AccessAuditEvent
+		if err := l.store.GetCBOR(auditEventKeyFmt.Encode(idx), &ev); err != nil {
+			return nil, fmt.Errorf("failed to load audit event %d: %w", idx, err)
+		}
+		events = append(events, &ev)
+	}
+	return events, nil
+}
+
+// Watch returns a channel that produces a stream of new audit events.
+func (l *auditLog) Watch() (<-chan *api.AccessAuditEvent, pubsub.ClosableSubscription) {
+	sub := l.notifier.Subscribe()
+	ch := make(chan *api.AccessAuditEvent)
+	sub.Unwrap(ch)
+
+	return ch, sub
+}
+
+func sortNamespaces(ns []common.Namespace) {
+	slices.SortFunc(ns, func(a, b common.Namespace) int {
+		return bytes.Compare(a[:], b[:])
+	})
+}
+
+func sortEnclaves(ids []sgx.EnclaveIdentity) {
+	slices.SortFunc(ids, func(a, b sgx.EnclaveIdentity) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
+
+// difference returns the elements of a that are not in b.
+func difference[T comparable](a, b []T) []T {
+	var diff []T
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,105 @@
+package keymanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/keymanager/secrets"
+)
+
+func TestAuditLog(t *testing.T) {
+	require := require.New(t)
+
+	store, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	kmID := common.NewTestNamespaceFromSeed([]byte("audit log test km"), 0)
+	rt1 := common.NewTestNamespaceFromSeed([]byte("audit log test runtime 1"), 0)
+	rt2 := common.NewTestNamespaceFromSeed([]byte("audit log test runtime 2"), 0)
+	var enc1, enc2 sgx.EnclaveIdentity
+	enc1.MrEnclave[0], enc2.MrEnclave[0] = 1, 2
+
+	status := func(generation uint64, serial uint32, enclaves map[sgx.EnclaveIdentity][]common.Namespace) *secrets.Status {
+		policy := secrets.PolicySGX{
+			Serial:   serial,
+			ID:       kmID,
+			Enclaves: make(map[sgx.EnclaveIdentity]*secrets.EnclavePolicySGX),
+		}
+		for id, rts := range enclaves {
+			enc := &secrets.EnclavePolicySGX{
+				MayQuery: make(map[common.Namespace][]sgx.EnclaveIdentity),
+			}
+			for _, rt := range rts {
+				enc.MayQuery[rt] = nil
+			}
+			policy.Enclaves[id] = enc
+		}
+		return &secrets.Status{
+			ID:         kmID,
+			Generation: generation,
+			Policy:     &secrets.SignedPolicySGX{Policy: policy},
+		}
+	}
+
+	log, err := newAuditLog(store, kmID, 2)
+	require.NoError(err, "newAuditLog")
+	ch, sub := log.Watch()
+	defer sub.Close()
+
+	// The initial status grants access to everything in the policy.
+	err = log.Observe(10, status(0, 1, map[sgx.EnclaveIdentity][]common.Namespace{enc1: {rt1}}))
+	require.NoError(err, "Observe")
+	ev := <-ch
+	require.EqualValues(0, ev.Index)
+	require.EqualValues(10, ev.Height)
+	require.Equal([]common.Namespace{rt1}, ev.RuntimesAdded)
+	require.Equal([]sgx.EnclaveIdentity{enc1}, ev.EnclavesAdded)
+
+	// Unchanged statuses are not recorded.
+	err = log.Observe(11, status(0, 1, map[sgx.EnclaveIdentity][]common.Namespace{enc1: {rt1}}))
+	require.NoError(err, "Observe")
+
+	err = log.Observe(12, status(0, 2, map[sgx.EnclaveIdentity][]common.Namespace{enc2: {rt1, rt2}}))
+	require.NoError(err, "Observe")
+	ev = <-ch
+	require.EqualValues(1, ev.Index)
+	require.EqualValues(12, ev.Height)
+	require.EqualValues(2, ev.PolicySerial)
+	require.Equal([]common.Namespace{rt2}, ev.RuntimesAdded)
+	require.Empty(ev.RuntimesRemoved)
+	require.Equal([]sgx.EnclaveIdentity{enc2}, ev.EnclavesAdded)
+	require.Equal([]sgx.EnclaveIdentity{enc1}, ev.EnclavesRemoved)
+
+	err = log.Observe(13, status(1, 2, map[sgx.EnclaveIdentity][]common.Namespace{enc2: {rt2}}))
+	require.NoError(err, "Observe")
+	ev = <-ch
+	require.EqualValues(1, ev.Generation)
+	require.Equal([]common.Namespace{rt1}, ev.RuntimesRemoved)
+
+	// Only the most recent events are retained.
+	events, err := log.Events(0, 0)
+	require.NoError(err, "Events")
+	require.Len(events, 2)
+	require.EqualValues(1, events[0].Index)
+	require.EqualValues(2, events[1].Index)
+
+	events, err = log.Events(0, 1)
+	require.NoError(err, "Events")
+	require.Len(events, 1)
+	require.EqualValues(1, events[0].Index)
+
+	// The audit log should survive restarts.
+	log, err = newAuditLog(store, kmID, 2)
+	require.NoError(err, "newAuditLog")
+	err = log.Observe(14, status(1, 2, map[sgx.EnclaveIdentity][]common.Namespace{enc2: {rt2}}))
+	require.NoError(err, "Observe")
+	events, err = log.Events(2, 0)
+	require.NoError(err, "Events")
+	require.Len(events, 1)
+	require.EqualValues(13, events[0].Height)
+}
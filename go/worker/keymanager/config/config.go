@@ -1,6 +1,14 @@
 // Package config implements global configuration options.
 package config
 
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+)
+
 // ChurpConfig holds configuration details for the CHURP extension.
 type ChurpConfig struct {
 	// Schemes is a list of CHURP scheme configurations.
@@ -13,6 +21,48 @@ type ChurpSchemeConfig struct {
 	ID uint8 `yaml:"id,omitempty"`
 }
 
+// ReplicationConfig holds configuration details for master and ephemeral secret replication.
+type ReplicationConfig struct {
+	// Base64-encoded node IDs of key manager nodes that should be used as replication sources
+	// while they are available.
+	PreferredSources []string `yaml:"preferred_sources,omitempty"`
+	// Base64-encoded node IDs of key manager nodes that should never be used as replication
+	// sources.
+	ExcludedSources []string `yaml:"excluded_sources,omitempty"`
+	// StallTimeout is the duration after which a warning is emitted in case the enclave is behind
+	// the latest master secret generation and replication has not progressed. Zero disables
+	// the warning.
+	StallTimeout time.Duration `yaml:"stall_timeout,omitempty"`
+}
+
+// Validate validates the replication configuration.
+func (c *ReplicationConfig) Validate() error {
+	preferred, err := ParseNodeIDs(c.PreferredSources)
+	if err != nil {
+		return fmt.Errorf("preferred sources: %w", err)
+	}
+	excluded, err := ParseNodeIDs(c.ExcludedSources)
+	if err != nil {
+		return fmt.Errorf("excluded sources: %w", err)
+	}
+	for _, pk := range preferred {
+		if slices.Contains(excluded, pk) {
+			return fmt.Errorf("replication source %s is both preferred and excluded", pk)
+		}
+	}
+	if c.StallTimeout < 0 {
+		return fmt.Errorf("replication stall timeout must not be negative")
+	}
+	return nil
+}
+
+// AuditLogConfig holds configuration details for the access audit log.
+type AuditLogConfig struct {
+	// MaxEvents is the maximum number of access audit events to keep, with the oldest events
+	// being pruned first. Zero disables the audit log.
+	MaxEvents uint64 `yaml:"max_events"`
+}
+
 // Config is the keymanager worker configuration structure.
 type Config struct {
 	// Key manager runtime ID.
@@ -22,10 +72,19 @@ type Config struct {
 
 	// Churp holds configuration details for the CHURP extension.
 	Churp ChurpConfig `yaml:"churp,omitempty"`
+
+	// Replication holds configuration details for master and ephemeral secret replication.
+	Replication ReplicationConfig `yaml:"replication,omitempty"`
+
+	// AuditLog holds configuration details for the access audit log.
+	AuditLog AuditLogConfig `yaml:"audit_log,omitempty"`
 }
 
 // Validate validates the configuration settings.
 func (c *Config) Validate() error {
+	if err := c.Replication.Validate(); err != nil {
+		return fmt.Errorf("replication: %w", err)
+	}
 	return nil
 }
 
@@ -37,5 +96,26 @@ func DefaultConfig() Config {
 		Churp: ChurpConfig{
 			Schemes: []ChurpSchemeConfig{},
 		},
+		Replication: ReplicationConfig{
+			PreferredSources: []string{},
+			ExcludedSources:  []string{},
+			StallTimeout:     10 * time.Minute,
+		},
+		AuditLog: AuditLogConfig{
+			MaxEvents: 10_000,
+		},
+	}
+}
+
+// ParseNodeIDs parses a list of base64-encoded node IDs.
+func ParseNodeIDs(ids []string) ([]signature.PublicKey, error) {
+	pks := make([]signature.PublicKey, 0, len(ids))
+	for _, id := range ids {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(id)); err != nil {
+			return nil, fmt.Errorf("`%s` is not a valid node ID: %w", id, err)
+		}
+		pks = append(pks, pk)
 	}
+	return pks, nil
 }
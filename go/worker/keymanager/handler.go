@@ -16,7 +16,7 @@ type workerEnvironment struct {
 
 // GetKeyManagerClient implements RuntimeHostHandlerEnvironment.
 func (env *workerEnvironment) GetKeyManagerClient() (runtimeKeymanager.Client, error) {
-	return env.w.keyManagerClient, nil
+	return env.w.replicationClient, nil
 }
 
 // GetTxPool implements RuntimeHostHandlerEnvironment.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/oasisprotocol/oasis-core/go/common/grpc"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/config"
@@ -14,12 +15,14 @@ import (
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	committeeCommon "github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	workerKeymanager "github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
+	kmConfig "github.com/oasisprotocol/oasis-core/go/worker/keymanager/config"
 	"github.com/oasisprotocol/oasis-core/go/worker/keymanager/p2p"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 )
 
 // New constructs a new key manager worker.
 func New(
+	grpcInternal *grpc.Server,
 	commonWorker *workerCommon.Worker,
 	r *registration.Worker,
 	keymanager api.Backend,
@@ -77,6 +80,23 @@ func New(
 	w.keyManagerClient = committeeCommon.NewKeyManagerClientWrapper(w.commonWorker.P2P, w.commonWorker.Consensus, w.commonWorker.ChainContext, w.logger)
 	w.keyManagerClient.SetKeyManagerID(&w.runtimeID)
 
+	// Prepare the client used by the enclave for replicating secrets.
+	preferred, err := kmConfig.ParseNodeIDs(config.GlobalConfig.Keymanager.Replication.PreferredSources)
+	if err != nil {
+		return nil, fmt.Errorf("worker/keymanager: failed to parse preferred replication sources: %w", err)
+	}
+	excluded, err := kmConfig.ParseNodeIDs(config.GlobalConfig.Keymanager.Replication.ExcludedSources)
+	if err != nil {
+		return nil, fmt.Errorf("worker/keymanager: failed to parse excluded replication sources: %w", err)
+	}
+	w.replicationClient = newReplicationClient(w.keyManagerClient, preferred, excluded)
+
+	// Prepare the access audit log.
+	w.auditLog, err = newAuditLog(commonWorker.CommonStore, w.runtimeID, config.GlobalConfig.Keymanager.AuditLog.MaxEvents)
+	if err != nil {
+		return nil, fmt.Errorf("worker/keymanager: failed to create access audit log: %w", err)
+	}
+
 	// Prepare the runtime host handler.
 	handler := runtimeRegistry.NewRuntimeHostHandler(&workerEnvironment{w}, w.runtime, w.commonWorker.Consensus)
 
@@ -121,5 +141,8 @@ func New(
 	// Register keymanager service.
 	commonWorker.P2P.RegisterProtocolServer(p2p.NewServer(commonWorker.ChainContext, w.runtimeID, w))
 
+	// Attach the key manager worker's internal GRPC interface.
+	workerKeymanager.RegisterService(grpcInternal.Server(), w)
+
 	return w, nil
 }
@@ -118,6 +118,13 @@ var (
 		},
 		[]string{"runtime"},
 	)
+	replicationGenerationsBehind = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_keymanager_replication_generations_behind",
+			Help: "Number of master secret generations the enclave is missing.",
+		},
+		[]string{"runtime"},
+	)
 	churpThresholdNumber = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "oasis_worker_keymanager_churp_threshold_number",
@@ -204,6 +211,7 @@ var (
 		enclaveGeneratedMasterSecretEpochNumber,
 		enclaveGeneratedMasterSecretGenerationNumber,
 		enclaveGeneratedEphemeralSecretEpochNumber,
+		replicationGenerationsBehind,
 		churpThresholdNumber,
 		churpExtraSharesNumber,
 		churpHandoffNumber,
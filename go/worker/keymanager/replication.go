@@ -0,0 +1,176 @@
+package keymanager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	enclaverpc "github.com/oasisprotocol/oasis-core/go/runtime/enclaverpc/api"
+	runtimeKeymanager "github.com/oasisprotocol/oasis-core/go/runtime/keymanager/api"
+)
+
+const (
+	// replicationCallTimeout is the maximum duration of a single call to a replication source
+	// after which the source is considered stalled.
+	replicationCallTimeout = 30 * time.Second
+	// replicationSourceBackoff is the duration for which a failed replication source is not
+	// preferred over other sources.
+	replicationSourceBackoff = time.Minute
+)
+
+var errReplicationSourceNotAllowed = fmt.Errorf("worker/keymanager: replication source not allowed")
+
+// replicationClient is the client used by the key manager enclave to replicate secrets from other
+// key manager enclaves.
+//
+// The enclave tries replication sources one by one, so the client rejects calls to excluded
+// sources, and to other sources as long as a preferred source is available, which makes
+// the enclave move on to the next source.
+type replicationClient struct {
+	mu sync.Mutex
+
+	inner runtimeKeymanager.Client
+
+	preferred []signature.PublicKey
+	excluded  []signature.PublicKey
+
+	callTimeout time.Duration
+	backoff     time.Duration
+
+	// nodes are the current key manager committee members.
+	nodes []signature.PublicKey
+	// failures are times of the last failed calls to replication sources.
+	failures map[signature.PublicKey]time.Time
+
+	lastSource      *signature.PublicKey
+	lastReplication time.Time
+}
+
+func newReplicationClient(inner runtimeKeymanager.Client, preferred, excluded []signature.PublicKey) *replicationClient {
+	return &replicationClient{
+		inner:       inner,
+		preferred:   preferred,
+		excluded:    excluded,
+		callTimeout: replicationCallTimeout,
+		backoff:     replicationSourceBackoff,
+		failures:    make(map[signature.PublicKey]time.Time),
+	}
+}
+
+// SetNodes sets the current key manager committee members.
+func (c *replicationClient) SetNodes(nodes []signature.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nodes = slices.Clone(nodes)
+}
+
+// LastReplication returns the source and time of the last successful replication.
+func (c *replicationClient) LastReplication() (*signature.PublicKey, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lastSource, c.lastReplication
+}
+
+// isAvailableLocked returns true iff the given source did not fail recently.
+func (c *replicationClient) isAvailableLocked(node signature.PublicKey) bool {
+	failed, ok := c.failures[node]
+	return !ok || time.Since(failed) >= c.backoff
+}
+
+// sources returns the sources the call restricted to the given nodes may be routed to.
+func (c *replicationClient) sources(nodes []signature.PublicKey) ([]signature.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	candidates := nodes
+	if len(candidates) == 0 {
+		if len(c.nodes) == 0 || len(c.excluded) == 0 && len(c.preferred) == 0 {
+			// Let the host choose any source.
+			return nil, nil
+		}
+		candidates = c.nodes
+	}
+	candidates = slices.DeleteFunc(slices.Clone(candidates), func(node signature.PublicKey) bool {
+		return slices.Contains(c.excluded, node)
+	})
+
+	var preferred []signature.PublicKey
+	for _, node := range c.preferred {
+		if !slices.Contains(c.nodes, node) || !c.isAvailableLocked(node) {
+			continue
+		}
+		preferred = append(preferred, node)
+	}
+	if len(preferred) > 0 {
+		// Only use the preferred sources while they are available.
+		candidates = slices.DeleteFunc(candidates, func(node signature.PublicKey) bool {
+			return !slices.Contains(preferred, node)
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil, errReplicationSourceNotAllowed
+	}
+	return candidates, nil
+}
+
+func (c *replicationClient) recordResult(sources []signature.PublicKey, node signature.PublicKey, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		// Only blame the source if it was chosen explicitly.
+		if len(sources) == 1 {
+			c.failures[sources[0]] = time.Now()
+		}
+		return
+	}
+
+	delete(c.failures, node)
+	c.lastSource = &node
+	c.lastReplication = time.Now()
+}
+
+// Implements runtimeKeymanager.Client.
+func (c *replicationClient) CallEnclaveDeprecated(ctx context.Context, data []byte, nodes []signature.PublicKey, kind enclaverpc.Kind, pf *enclaverpc.PeerFeedback) ([]byte, signature.PublicKey, error) {
+	sources, err := c.sources(nodes)
+	if err != nil {
+		return nil, signature.PublicKey{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	rsp, node, err := c.inner.CallEnclaveDeprecated(ctx, data, sources, kind, pf) //nolint:staticcheck // Suppress SA1019 deprecation warning
+	c.recordResult(sources, node, err)
+	return rsp, node, err
+}
+
+// Implements runtimeKeymanager.Client.
+func (c *replicationClient) CallEnclave(ctx context.Context, requestID uint64, data []byte, nodes []signature.PublicKey, kind enclaverpc.Kind) (*runtimeKeymanager.EnclaveResponse, error) {
+	sources, err := c.sources(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.callTimeout)
+	defer cancel()
+
+	rsp, err := c.inner.CallEnclave(ctx, requestID, data, sources, kind)
+	var node signature.PublicKey
+	if rsp != nil {
+		node = rsp.Node
+	}
+	c.recordResult(sources, node, err)
+	return rsp, err
+}
+
+// Implements runtimeKeymanager.Client.
+func (c *replicationClient) SubmitPeerFeedback(requestID uint64, feedback enclaverpc.PeerFeedback) {
+	c.inner.SubmitPeerFeedback(requestID, feedback)
+}
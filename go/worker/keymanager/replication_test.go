@@ -0,0 +1,112 @@
+package keymanager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	enclaverpc "github.com/oasisprotocol/oasis-core/go/runtime/enclaverpc/api"
+	runtimeKeymanager "github.com/oasisprotocol/oasis-core/go/runtime/keymanager/api"
+)
+
+type testKeyManagerClient struct {
+	sync.Mutex
+
+	stalled map[signature.PublicKey]struct{}
+	calls   []signature.PublicKey
+}
+
+func (c *testKeyManagerClient) route(ctx context.Context, nodes []signature.PublicKey) (signature.PublicKey, error) {
+	c.Lock()
+	node := nodes[0]
+	c.calls = append(c.calls, node)
+	_, stalled := c.stalled[node]
+	c.Unlock()
+
+	if stalled {
+		<-ctx.Done()
+		return signature.PublicKey{}, ctx.Err()
+	}
+	return node, nil
+}
+
+func (c *testKeyManagerClient) CallEnclaveDeprecated(ctx context.Context, _ []byte, nodes []signature.PublicKey, _ enclaverpc.Kind, _ *enclaverpc.PeerFeedback) ([]byte, signature.PublicKey, error) {
+	node, err := c.route(ctx, nodes)
+	return nil, node, err
+}
+
+func (c *testKeyManagerClient) CallEnclave(ctx context.Context, _ uint64, _ []byte, nodes []signature.PublicKey, _ enclaverpc.Kind) (*runtimeKeymanager.EnclaveResponse, error) {
+	node, err := c.route(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+	return &runtimeKeymanager.EnclaveResponse{Node: node}, nil
+}
+
+func (c *testKeyManagerClient) SubmitPeerFeedback(uint64, enclaverpc.PeerFeedback) {
+}
+
+func TestReplicationClient(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		nodeA = signature.NewPublicKey("a000000000000000000000000000000000000000000000000000000000000000")
+		nodeB = signature.NewPublicKey("b000000000000000000000000000000000000000000000000000000000000000")
+		nodeC = signature.NewPublicKey("c000000000000000000000000000000000000000000000000000000000000000")
+	)
+
+	inner := &testKeyManagerClient{
+		stalled: map[signature.PublicKey]struct{}{nodeA: {}},
+	}
+	c := newReplicationClient(inner, []signature.PublicKey{nodeA}, []signature.PublicKey{nodeC})
+	c.callTimeout = 50 * time.Millisecond
+	c.SetNodes([]signature.PublicKey{nodeA, nodeB, nodeC})
+
+	// The enclave tries all sources one by one, until one of them succeeds.
+	replicate := func() (*signature.PublicKey, error) {
+		var err error
+		for _, node := range []signature.PublicKey{nodeC, nodeB, nodeA} {
+			var rsp *runtimeKeymanager.EnclaveResponse
+			if rsp, err = c.CallEnclave(context.Background(), 1, nil, []signature.PublicKey{node}, enclaverpc.KindNoiseSession); err == nil {
+				return &rsp.Node, nil
+			}
+		}
+		return nil, err
+	}
+
+	// Excluded sources are never used and other sources are rejected while the preferred source
+	// is available.
+	_, err := replicate()
+	require.Error(err, "replication from a stalled preferred source should fail")
+	require.Equal([]signature.PublicKey{nodeA}, inner.calls, "only the preferred source should be used")
+	source, _ := c.LastReplication()
+	require.Nil(source)
+
+	// Once the preferred source stalls, replication should fail over to another source.
+	node, err := replicate()
+	require.NoError(err, "replication should fail over to another source")
+	require.Equal(nodeB, *node)
+	require.Equal([]signature.PublicKey{nodeA, nodeB}, inner.calls)
+	source, lastReplication := c.LastReplication()
+	require.Equal(nodeB, *source)
+	require.False(lastReplication.IsZero())
+
+	// Unrestricted calls are restricted to allowed sources.
+	sources, err := c.sources(nil)
+	require.NoError(err)
+	require.ElementsMatch([]signature.PublicKey{nodeA, nodeB}, sources, "should not restrict to stalled preferred source")
+
+	// The preferred source is used again after it recovers.
+	c.backoff = 0
+	delete(inner.stalled, nodeA)
+	node, err = replicate()
+	require.NoError(err)
+	require.Equal(nodeA, *node)
+	sources, err = c.sources(nil)
+	require.NoError(err)
+	require.Equal([]signature.PublicKey{nodeA}, sources)
+}
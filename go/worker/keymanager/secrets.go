@@ -78,6 +78,10 @@ type secretsWorker struct {
 	status   workerKm.SecretsStatus // Guarded by mutex.
 	kmStatus *secrets.Status
 
+	stallTimeout          time.Duration
+	enclaveNextGeneration uint64    // Guarded by mutex.
+	replicationProgress   time.Time // Guarded by mutex.
+
 	initEnclaveInProgress  bool
 	initEnclaveRequired    bool
 	initEnclaveDoneCh      chan *secrets.SignedInitResponse
@@ -154,6 +158,7 @@ func newSecretsWorker(
 		genEphSecDoneCh:   make(chan bool, 1),
 		genSecHeight:      int64(math.MaxInt64),
 		status:            status,
+		stallTimeout:      config.GlobalConfig.Keymanager.Replication.StallTimeout,
 	}, nil
 }
 
@@ -452,6 +457,8 @@ func (w *secretsWorker) handleNewBlock(ctx context.Context, blk *consensus.Block
 	// (Re)Load master/ephemeral secrets.
 	w.handleLoadMasterSecret(ctx)
 	w.handleLoadEphemeralSecret(ctx)
+
+	w.updateReplicationStatus()
 }
 
 func (w *secretsWorker) handleRuntimeHostEvent(ctx context.Context, ev *host.Event) {
@@ -462,6 +469,10 @@ func (w *secretsWorker) handleRuntimeHostEvent(ctx context.Context, ev *host.Eve
 		//
 		// Missing the first event is not an issue, as we always initialize the enclave
 		// when the first status update is received.
+		w.mu.Lock()
+		w.enclaveNextGeneration = 0
+		w.mu.Unlock()
+
 		w.handleInitEnclave(ctx)
 	}
 }
@@ -488,6 +499,11 @@ func (w *secretsWorker) handleStatusUpdate(ctx context.Context, kmStatus *secret
 	w.status.Status = kmStatus
 	w.mu.Unlock()
 
+	w.kmWorker.replicationClient.SetNodes(kmStatus.Nodes)
+	w.updateReplicationStatus()
+
+	w.auditStatusUpdate(ctx, kmStatus)
+
 	// (Re)Initialize the enclave.
 	// A new master secret generation or policy might have been published.
 	w.handleInitEnclave(ctx)
@@ -496,6 +512,20 @@ func (w *secretsWorker) handleStatusUpdate(ctx context.Context, kmStatus *secret
 	w.updateGenerateMasterSecretEpoch()
 }
 
+func (w *secretsWorker) auditStatusUpdate(ctx context.Context, kmStatus *secrets.Status) {
+	height, err := w.commonWorker.Consensus.Core().GetLatestHeight(ctx)
+	if err != nil {
+		w.logger.Warn("failed to fetch latest consensus height",
+			"err", err,
+		)
+	}
+	if err = w.kmWorker.auditLog.Observe(height, kmStatus); err != nil {
+		w.logger.Error("failed to record access audit event",
+			"err", err,
+		)
+	}
+}
+
 func (w *secretsWorker) handleInitEnclave(ctx context.Context) {
 	if w.kmStatus == nil {
 		// There's no need to retry as another call will be made
@@ -577,6 +607,12 @@ func (w *secretsWorker) initEnclave(ctx context.Context, kmStatus *secrets.Statu
 	w.status.Worker.Policy = kmStatus.Policy
 	w.status.Worker.PolicyChecksum = rsp.InitResponse.PolicyChecksum
 
+	// The enclave replicates all missing master secrets during initialization.
+	if next := kmStatus.NextGeneration(); next > w.enclaveNextGeneration {
+		w.enclaveNextGeneration = next
+		w.replicationProgress = time.Now()
+	}
+
 	return &rsp, nil
 }
 
@@ -613,6 +649,49 @@ func (w *secretsWorker) handleInitEnclaveDone(ctx context.Context, rsp *secrets.
 	if rsp != nil {
 		w.registerNode(rsp, *version)
 	}
+
+	w.updateReplicationStatus()
+}
+
+// updateReplicationStatus updates the replication status and warns in case the enclave is behind
+// and replication has not progressed for longer than the stall timeout.
+func (w *secretsWorker) updateReplicationStatus() {
+	if w.kmStatus == nil {
+		return
+	}
+	source, lastReplication := w.kmWorker.replicationClient.LastReplication()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var behind uint64
+	if next := w.kmStatus.NextGeneration(); next > w.enclaveNextGeneration {
+		behind = next - w.enclaveNextGeneration
+	}
+	if behind == 0 || w.replicationProgress.IsZero() {
+		w.replicationProgress = time.Now()
+	}
+	stalled := behind > 0 && w.stallTimeout > 0 && time.Since(w.replicationProgress) > w.stallTimeout
+
+	status := &w.status.Worker.Replication
+	if stalled && !status.Stalled {
+		w.logger.Warn("master secret replication has stalled",
+			"generations_behind", behind,
+			"last_progress", w.replicationProgress,
+			"last_source", source,
+		)
+	}
+
+	status.Generation = 0
+	if w.enclaveNextGeneration > 0 {
+		status.Generation = w.enclaveNextGeneration - 1
+	}
+	status.GenerationsBehind = behind
+	status.Source = source
+	status.LastReplication = lastReplication
+	status.Stalled = stalled
+
+	replicationGenerationsBehind.WithLabelValues(w.runtimeLabel).Set(float64(behind))
 }
 
 func (w *secretsWorker) registerNode(rsp *secrets.SignedInitResponse, version version.Version) {
@@ -1,7 +1,10 @@
 package keymanager
 
 import (
+	"context"
+
 	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	"github.com/oasisprotocol/oasis-core/go/worker/keymanager/api"
 )
 
@@ -62,3 +65,14 @@ func (w *Worker) GetStatus() (*api.Status, error) {
 		Churp:          churp,
 	}, nil
 }
+
+// GetAccessAuditLog implements api.KeyManagerWorker.
+func (w *Worker) GetAccessAuditLog(_ context.Context, request *api.GetAccessAuditLogRequest) ([]*api.AccessAuditEvent, error) {
+	return w.auditLog.Events(request.FromIndex, request.Limit)
+}
+
+// WatchAccessAuditLog implements api.KeyManagerWorker.
+func (w *Worker) WatchAccessAuditLog(context.Context) (<-chan *api.AccessAuditEvent, pubsub.ClosableSubscription, error) {
+	ch, sub := w.auditLog.Watch()
+	return ch, sub, nil
+}
@@ -80,6 +80,10 @@ type Worker struct { // nolint: maligned
 	notifier         protocol.Notifier
 	keyManagerClient *commonCommittee.KeyManagerClientWrapper
 
+	replicationClient *replicationClient
+
+	auditLog *auditLog
+
 	enabled bool
 }
 
@@ -301,7 +305,7 @@ func (w *Worker) runtimeEncryptionKeys(nodes []signature.PublicKey) (map[x25519.
 // selectBlockHeight returns the height of a random block within the specified
 // percentiles of the given epoch.
 //
-// Calculation is based on the current epoch and its block interval.
+// Calculation is based on the current epoch and the block intervals of the epochs in between.
 func (w *Worker) selectBlockHeight(epoch beacon.EpochTime, from uint8, to uint8) (int64, error) {
 	// Fetch the height of the first block in the current epoch.
 	now, err := w.commonWorker.Consensus.Beacon().GetEpoch(w.ctx, consensus.HeightLatest)
@@ -313,18 +317,21 @@ func (w *Worker) selectBlockHeight(epoch beacon.EpochTime, from uint8, to uint8)
 		return 0, fmt.Errorf("failed to fetch epoch block height: %w", err)
 	}
 
-	// Fetch the epoch interval for the current epoch.
+	// Fetch the epoch intervals.
 	params, err := w.commonWorker.Consensus.Beacon().ConsensusParameters(w.ctx, consensus.HeightLatest)
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch consensus parameters: %w", err)
 	}
-	interval := params.Interval()
 
+	// Estimate the height of the first block in the given epoch and its interval.
+	//
 	// Use a zero interval when mocking epoch time, as the interval is untrusted.
 	// It is set in genesis to a fixed value, but the real interval can vary
 	// between epochs.
-	if cmdFlags.DebugDontBlameOasis() && params.DebugMockBackend {
-		interval = 0
+	var interval int64
+	if !cmdFlags.DebugDontBlameOasis() || !params.DebugMockBackend {
+		first += params.EpochBlocks(now, epoch)
+		interval = params.IntervalAt(epoch)
 	}
 
 	// Pick a random block from the given percentile.
@@ -333,10 +340,6 @@ func (w *Worker) selectBlockHeight(epoch beacon.EpochTime, from uint8, to uint8)
 	span = max(1, span)
 	height := first + offset + rand.Int63n(span)
 
-	// Estimate the block height for the given epoch.
-	diff := int64(epoch) - int64(now)
-	height = height + diff*interval
-
 	return height, nil
 }
 
@@ -358,6 +361,10 @@ func (w *Worker) handleRuntimeHostEvent(ev *host.Event) {
 			return
 		}
 
+		if capabilityTEE != nil {
+			go w.explainAttestationFailure(version, capabilityTEE)
+		}
+
 		w.roleProvider.SetAvailableWithCallback(func(n *node.Node) error {
 			rt := n.AddOrUpdateRuntime(w.runtime.ID(), version)
 			rt.Version = version
@@ -381,6 +388,38 @@ func (w *Worker) handleRuntimeHostEvent(ev *host.Event) {
 	}
 }
 
+// explainAttestationFailure logs an explanation in case the key manager enclave attestation
+// does not satisfy the quote policy of the key manager runtime, so that registration rejections
+// can be diagnosed without decoding the quote.
+func (w *Worker) explainAttestationFailure(version version.Version, capabilityTEE *node.CapabilityTEE) {
+	rt, err := w.runtime.RegistryDescriptor(w.ctx)
+	if err != nil {
+		return
+	}
+	deployment := rt.DeploymentForVersion(version)
+	if deployment == nil {
+		return
+	}
+	params, err := w.commonWorker.Consensus.Registry().ConsensusParameters(w.ctx, consensus.HeightLatest)
+	if err != nil {
+		return
+	}
+
+	failure := capabilityTEE.ExplainVerificationFailure(params.TEEFeatures, time.Now(), deployment.TEE)
+	if failure == nil {
+		return
+	}
+	w.logger.Error("key manager attestation rejected by quote policy",
+		"version", version,
+		"rule", failure.Rule,
+		"reason", failure.Reason,
+		"identity", failure.Identity,
+		"tcb_status", failure.TCBStatus,
+		"advisory_ids", failure.AdvisoryIDs,
+		"err", failure.Err,
+	)
+}
+
 func (w *Worker) worker() {
 	w.logger.Info("starting key manager worker")
 
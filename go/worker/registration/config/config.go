@@ -15,6 +15,35 @@ type Config struct {
 
 	// EntityID to use as the node owner in registrations (public key).
 	EntityID string `yaml:"entity_id"`
+
+	// AdvertiseUpgradeHandlers specifies whether the node descriptor's software version should
+	// include the names of the upgrade handlers supported by the running binary.
+	AdvertiseUpgradeHandlers bool `yaml:"advertise_upgrade_handlers"`
+
+	// TLSRotation is the automatic TLS key rotation configuration.
+	TLSRotation TLSRotationConfig `yaml:"tls_rotation,omitempty"`
+}
+
+// TLSRotationConfig is the automatic TLS key rotation configuration structure.
+type TLSRotationConfig struct {
+	// Interval is the number of epochs between automatic TLS key rotations. Zero disables
+	// automatic rotation.
+	Interval uint64 `yaml:"interval"`
+
+	// Overlap is the number of epochs during which both the current and the next TLS public keys
+	// are included in the node descriptor before the node switches to the next key.
+	Overlap uint64 `yaml:"overlap"`
+}
+
+// Validate validates the configuration settings.
+func (c *TLSRotationConfig) Validate() error {
+	if c.Overlap == 0 {
+		return fmt.Errorf("overlap must be at least one epoch")
+	}
+	if c.Interval != 0 && c.Interval <= c.Overlap {
+		return fmt.Errorf("interval must be greater than overlap")
+	}
+	return nil
 }
 
 // Validate validates the configuration settings.
@@ -34,13 +63,22 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("malformed entity ID: %w", err)
 		}
 	}
+
+	if err := c.TLSRotation.Validate(); err != nil {
+		return fmt.Errorf("tls_rotation: %w", err)
+	}
 	return nil
 }
 
 // DefaultConfig returns the default configuration settings.
 func DefaultConfig() Config {
 	return Config{
-		Entity:   "",
-		EntityID: "",
+		Entity:                   "",
+		EntityID:                 "",
+		AdvertiseUpgradeHandlers: false,
+		TLSRotation: TLSRotationConfig{
+			Interval: 0,
+			Overlap:  2,
+		},
 	}
 }
@@ -0,0 +1,169 @@
+package registration
+
+import (
+	"sync"
+	"time"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	control "github.com/oasisprotocol/oasis-core/go/control/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/registration/config"
+)
+
+var tlsRotationStoreKey = []byte("tls rotation")
+
+// tlsRotationState is the persisted TLS key rotation state.
+type tlsRotationState struct {
+	// StartedEpoch is the epoch at which the pending rotation started.
+	StartedEpoch beacon.EpochTime `json:"started_epoch"`
+	// LastRotationEpoch is the epoch at which the last rotation completed (or at which the
+	// rotation schedule started in case the node never rotated its TLS key).
+	LastRotationEpoch beacon.EpochTime `json:"last_rotation_epoch"`
+	// LastRotation is the time at which the last rotation completed.
+	LastRotation time.Time `json:"last_rotation"`
+}
+
+// tlsRotator performs automatic TLS key rotations based on epoch transitions.
+//
+// A rotation starts by generating the next TLS key, which is then included in the node descriptor
+// next to the current one for the configured number of overlap epochs. Once the overlap window
+// passes, the node switches to the next key and only includes it in the node descriptor.
+type tlsRotator struct {
+	sync.Mutex
+
+	cfg      config.TLSRotationConfig
+	identity *identity.Identity
+	store    *persistent.ServiceStore
+	logger   *logging.Logger
+
+	state tlsRotationState
+}
+
+func newTLSRotator(
+	cfg config.TLSRotationConfig,
+	identity *identity.Identity,
+	store *persistent.ServiceStore,
+	logger *logging.Logger,
+) (*tlsRotator, error) {
+	r := &tlsRotator{
+		cfg:      cfg,
+		identity: identity,
+		store:    store,
+		logger:   logger,
+		state: tlsRotationState{
+			StartedEpoch:      beacon.EpochInvalid,
+			LastRotationEpoch: beacon.EpochInvalid,
+		},
+	}
+
+	err := store.GetCBOR(tlsRotationStoreKey, &r.state)
+	if err != nil && err != persistent.ErrNotFound {
+		return nil, err
+	}
+	return r, nil
+}
+
+// enabled returns true iff automatic rotation is enabled or there is a pending rotation that
+// still needs to be completed.
+func (r *tlsRotator) enabled() bool {
+	return r.cfg.Interval > 0 || r.identity.GetNextTLSSigner() != nil
+}
+
+// update advances the rotation state for the given epoch.
+func (r *tlsRotator) update(epoch beacon.EpochTime) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.enabled() {
+		return nil
+	}
+
+	state := r.state
+	switch signer := r.identity.GetNextTLSSigner(); signer {
+	case nil:
+		if state.LastRotationEpoch == beacon.EpochInvalid {
+			// Start the rotation schedule.
+			state.LastRotationEpoch = epoch
+			break
+		}
+		if epoch < state.LastRotationEpoch+beacon.EpochTime(r.cfg.Interval) {
+			return nil
+		}
+
+		nextPubKey, err := r.identity.BeginTLSRotation()
+		if err != nil {
+			r.logger.Error("failed to start TLS key rotation",
+				"err", err,
+				"epoch", epoch,
+			)
+			return err
+		}
+		state.StartedEpoch = epoch
+
+		r.logger.Info("TLS key rotation started",
+			"epoch", epoch,
+			"tls_pk", r.identity.GetTLSSigner().Public(),
+			"next_tls_pk", nextPubKey,
+			"complete_epoch", epoch+beacon.EpochTime(r.cfg.Overlap),
+		)
+	default:
+		if state.StartedEpoch == beacon.EpochInvalid {
+			// The rotation was started but its state was not persisted, restart the window.
+			state.StartedEpoch = epoch
+			break
+		}
+		if epoch < state.StartedEpoch+beacon.EpochTime(r.cfg.Overlap) {
+			return nil
+		}
+
+		previousPubKey := r.identity.GetTLSSigner().Public()
+		if err := r.identity.CompleteTLSRotation(); err != nil {
+			r.logger.Error("failed to complete TLS key rotation",
+				"err", err,
+				"epoch", epoch,
+			)
+			return err
+		}
+		state.StartedEpoch = beacon.EpochInvalid
+		state.LastRotationEpoch = epoch
+		state.LastRotation = time.Now()
+
+		r.logger.Info("TLS key rotation completed",
+			"epoch", epoch,
+			"previous_tls_pk", previousPubKey,
+			"tls_pk", signer.Public(),
+		)
+	}
+
+	if err := r.store.PutCBOR(tlsRotationStoreKey, &state); err != nil {
+		return err
+	}
+	r.state = state
+	return nil
+}
+
+// status returns the rotation status or nil if automatic rotation is disabled.
+func (r *tlsRotator) status() *control.TLSRotationStatus {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.enabled() {
+		return nil
+	}
+
+	status := control.TLSRotationStatus{
+		LastRotation: r.state.LastRotation,
+	}
+	if r.state.LastRotationEpoch != beacon.EpochInvalid && !r.state.LastRotation.IsZero() {
+		status.LastRotationEpoch = r.state.LastRotationEpoch
+	}
+	if signer := r.identity.GetNextTLSSigner(); signer != nil {
+		pk := signer.Public()
+		status.Pending = true
+		status.NextPubKey = &pk
+		status.StartedEpoch = r.state.StartedEpoch
+	}
+	return &status
+}
@@ -0,0 +1,151 @@
+package registration
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	beacon "github.com/oasisprotocol/oasis-core/go/beacon/api"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
+	cmnTesting "github.com/oasisprotocol/oasis-core/go/common/grpc/testing"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	"github.com/oasisprotocol/oasis-core/go/worker/registration/config"
+)
+
+func TestTLSRotation(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dataDir := t.TempDir()
+	ident, err := identity.LoadOrGenerate(dataDir, memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate")
+	store, err := persistent.NewCommonStore(dataDir)
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	cfg := config.TLSRotationConfig{
+		Interval: 10,
+		Overlap:  2,
+	}
+	logger := logging.GetLogger("worker/registration/test")
+	rotator, err := newTLSRotator(cfg, ident, store.GetServiceStore(DBBucketName), logger)
+	require.NoError(err, "newTLSRotator")
+
+	// Start a server that only accepts peers authenticated with the TLS keys from their node
+	// descriptors.
+	serverIdentity, err := identity.LoadOrGenerate(t.TempDir(), memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate (server)")
+	authenticator := auth.NewPeerPubkeyAuthenticator()
+	serverCfg := &cmnGrpc.ServerConfig{
+		Name:     "localhost",
+		Port:     52173,
+		Identity: serverIdentity,
+		AuthFunc: authenticator.AuthFunc,
+	}
+	grpcServer, err := cmnGrpc.NewServer(serverCfg)
+	require.NoError(err, "NewServer")
+	cmnTesting.RegisterService(grpcServer.Server(), cmnTesting.NewPingServer(authenticator.AuthFunc))
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer func() {
+		grpcServer.Stop()
+		grpcServer.Cleanup()
+	}()
+
+	ping := func(cert *tls.Certificate) error {
+		creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+			CommonName: identity.CommonName,
+			ServerPubKeys: map[signature.PublicKey]bool{
+				serverIdentity.GetTLSSigner().Public(): true,
+			},
+			Certificates: []tls.Certificate{*cert},
+		})
+		require.NoError(err, "NewClientCreds")
+		conn, err := cmnGrpc.Dial(
+			fmt.Sprintf("%s:%d", serverCfg.Name, serverCfg.Port),
+			grpc.WithTransportCredentials(creds),
+		)
+		require.NoError(err, "Dial")
+		defer conn.Close()
+
+		_, err = cmnTesting.NewPingClient(conn).Ping(ctx, &cmnTesting.PingQuery{})
+		return err
+	}
+	// advance advances the rotation to the given epoch and makes the server accept the TLS keys
+	// that the node would include in its descriptor.
+	advance := func(epoch beacon.EpochTime) node.TLSInfo {
+		err := rotator.update(epoch)
+		require.NoError(err, "update")
+
+		tlsInfo := node.TLSInfo{
+			PubKey: ident.GetTLSSigner().Public(),
+		}
+		if signer := ident.GetNextTLSSigner(); signer != nil {
+			pk := signer.Public()
+			tlsInfo.NextPubKey = &pk
+		}
+		authenticator.SetAllowedPeerPublicKeys(tlsInfo.PubKeys())
+		return tlsInfo
+	}
+
+	staleCert := ident.GetTLSCertificate()
+	stalePubKey := ident.GetTLSSigner().Public()
+
+	// Before the rotation.
+	for _, epoch := range []beacon.EpochTime{5, 14} {
+		tlsInfo := advance(epoch)
+		require.Equal(stalePubKey, tlsInfo.PubKey)
+		require.Nil(tlsInfo.NextPubKey, "there should be no next TLS key before the rotation")
+		require.NoError(ping(staleCert), "current key should be accepted")
+		require.False(rotator.status().Pending)
+	}
+
+	// During the overlap window.
+	var nextCert *tls.Certificate
+	for _, epoch := range []beacon.EpochTime{15, 16} {
+		tlsInfo := advance(epoch)
+		require.Equal(stalePubKey, tlsInfo.PubKey)
+		require.NotNil(tlsInfo.NextPubKey, "next TLS key should be included during the overlap window")
+		nextCert = ident.GetNextTLSCertificate()
+		require.NoError(ping(staleCert), "stale key should be accepted during the overlap window")
+		require.NoError(ping(nextCert), "next key should be accepted during the overlap window")
+
+		rs := rotator.status()
+		require.True(rs.Pending)
+		require.Equal(tlsInfo.NextPubKey, rs.NextPubKey)
+		require.EqualValues(15, rs.StartedEpoch)
+	}
+
+	// After the overlap window.
+	tlsInfo := advance(17)
+	require.NotEqual(stalePubKey, tlsInfo.PubKey)
+	require.Nil(tlsInfo.NextPubKey, "there should be no next TLS key after the overlap window")
+	require.Equal(nextCert, ident.GetTLSCertificate(), "node should switch to the next key")
+	err = ping(staleCert)
+	require.Error(err, "stale key should be rejected after the overlap window")
+	require.Equal(codes.PermissionDenied, status.Code(err))
+	require.NoError(ping(nextCert), "new key should be accepted after the overlap window")
+
+	rs := rotator.status()
+	require.False(rs.Pending)
+	require.EqualValues(17, rs.LastRotationEpoch)
+	require.False(rs.LastRotation.IsZero())
+
+	// The schedule should survive a restart.
+	rotator, err = newTLSRotator(cfg, ident, store.GetServiceStore(DBBucketName), logger)
+	require.NoError(err, "newTLSRotator (2)")
+	require.Nil(advance(26).NextPubKey, "rotation should not start before the interval passes")
+	require.NotNil(advance(27).NextPubKey, "rotation should start once the interval passes")
+}
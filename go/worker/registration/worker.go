@@ -2,9 +2,12 @@ package registration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,7 +21,9 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
 	"github.com/oasisprotocol/oasis-core/go/common/entity"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	"github.com/oasisprotocol/oasis-core/go/common/journal"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/persistent"
@@ -32,7 +37,9 @@ import (
 	p2p "github.com/oasisprotocol/oasis-core/go/p2p/api"
 	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
 	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
+	sentryAPI "github.com/oasisprotocol/oasis-core/go/sentry/api"
 	sentryClient "github.com/oasisprotocol/oasis-core/go/sentry/client"
+	"github.com/oasisprotocol/oasis-core/go/upgrade/migrations"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 )
 
@@ -42,10 +49,15 @@ const (
 	DBBucketName = "worker/registration"
 
 	periodicMetricsInterval = 60 * time.Second
+
+	// sentryQueryTimeout is the timeout for querying a sentry node at a single address before
+	// falling back to the next one.
+	sentryQueryTimeout = 10 * time.Second
 )
 
 var (
 	deregistrationRequestStoreKey = []byte("deregistration requested")
+	removedRolesStoreKey          = []byte("removed roles")
 
 	allowUnroutableAddresses bool
 
@@ -206,8 +218,13 @@ type Worker struct { // nolint: maligned
 	logger    *logging.Logger
 	consensus consensus.Service
 
-	roleProviders []*roleProvider
-	registerCh    chan struct{}
+	roleProviders  []*roleProvider
+	pausedRuntimes map[common.Namespace]bool
+	removedRoles   node.RolesMask
+	roleRemovalCh  chan struct{} // closed after registering without the removed roles
+	registerCh     chan struct{}
+
+	tlsRotator *tlsRotator
 
 	status control.RegistrationStatus
 }
@@ -217,14 +234,27 @@ func DebugForceAllowUnroutableAddresses() {
 	allowUnroutableAddresses = true
 }
 
+// reregistrationDelay returns the maximum re-registration delay and the height of the first block
+// of the given epoch.
+func (w *Worker) reregistrationDelay(epoch beacon.EpochTime) (int64, int64, error) {
+	params, err := w.beacon.ConsensusParameters(w.ctx, consensus.HeightLatest)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query beacon parameters: %w", err)
+	}
+	epochHeight, err := w.beacon.GetEpochBlock(w.ctx, epoch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query block height for epoch: %w", err)
+	}
+	return params.IntervalAt(epoch) / 100 * 5, epochHeight, nil // 5%
+}
+
 func (w *Worker) registrationLoop() { // nolint: gocyclo
 	// Delay node registration till after the consensus service has
 	// finished initial synchronization if applicable.
 	var (
 		blockCh <-chan *consensus.Block
 
-		delayReregistration    bool
-		maxReregistrationDelay int64
+		delayReregistration bool
 	)
 	if w.consensus != nil {
 		w.logger.Debug("waiting for consensus sync")
@@ -241,16 +271,6 @@ func (w *Worker) registrationLoop() { // nolint: gocyclo
 		switch err {
 		case nil:
 			delayReregistration = beaconParameters.Backend == beacon.BackendVRF
-			if delayReregistration {
-				epochInterval := beaconParameters.VRFParameters.Interval
-				maxReregistrationDelay = epochInterval / 100 * 5 // 5%
-				if maxReregistrationDelay == 0 {
-					w.logger.Warn("epoch interval too short to provide meaningful re-registration delay",
-						"epoch_interval", epochInterval,
-					)
-					delayReregistration = false
-				}
-			}
 		default:
 			w.logger.Error("failed to query beacon parameters",
 				"err", err,
@@ -365,9 +385,13 @@ Loop:
 			// Epoch updated, check if we can submit a registration.
 			if delayReregistration {
 				// Derive the re-registration delay.
-				epochHeight, err := w.beacon.GetEpochBlock(w.ctx, epoch)
-				switch err {
-				case nil:
+				maxReregistrationDelay, epochHeight, err := w.reregistrationDelay(epoch)
+				switch {
+				case err == nil && maxReregistrationDelay == 0:
+					w.logger.Warn("epoch interval too short to provide meaningful re-registration delay",
+						"epoch", epoch,
+					)
+				case err == nil:
 					// Schedule the re-registration, and wait till the target height.
 					reregisterHeight = epochHeight + rand.Int63n(maxReregistrationDelay)
 					w.logger.Info("per-epoch re-registration scheduled",
@@ -376,7 +400,7 @@ Loop:
 					)
 					continue
 				default:
-					w.logger.Error("failed to query block height for epoch",
+					w.logger.Error("failed to derive re-registration delay for epoch",
 						"err", err,
 						"epoch", epoch,
 					)
@@ -401,44 +425,7 @@ Loop:
 
 		// If there are any role providers which are still not ready, we must wait for more
 		// notifications.
-		hooks, cbs, vers := func() (h []RegisterNodeHook, cbs []RegisterNodeCallback, vers []uint64) {
-			w.RLock()
-			defer w.RUnlock()
-
-			w.logger.Debug("enumerating role provider hooks")
-
-			for _, rp := range w.roleProviders {
-				rp.Lock()
-				role := rp.role
-				hook := rp.hook
-				cb := rp.cb
-				ver := rp.version
-				rp.Unlock()
-
-				w.logger.Debug("role provider hook",
-					"ver", ver,
-					"role", role,
-					"hook", hook,
-					"cb", cb,
-				)
-
-				if hook == nil {
-					w.logger.Debug("nil hook for role",
-						"role", role,
-						"ver", ver,
-					)
-					return nil, nil, nil
-				}
-
-				h = append(h, func(n *node.Node) error {
-					n.AddRoles(role)
-					return hook(n)
-				})
-				cbs = append(cbs, cb)
-				vers = append(vers, ver)
-			}
-			return
-		}()
+		rps, hooks, cbs, vers := w.roleProviderHooks()
 		if hooks == nil {
 			w.logger.Debug("not registering, no role provider hooks")
 			continue Loop
@@ -520,7 +507,7 @@ Loop:
 			w.RLock()
 			defer w.RUnlock()
 
-			for i, rp := range w.roleProviders {
+			for i, rp := range rps {
 				// Only clear the pending callback in case the hook/call have not been modified.
 				rp.Lock()
 				if rp.version == vers[i] {
@@ -540,6 +527,77 @@ Loop:
 	}
 }
 
+// roleProviderHooks returns the role providers included in the node registration together with
+// their registration hooks, callbacks and versions. Role providers of paused runtimes and of
+// removed roles are omitted.
+//
+// In case any of the included role providers is not available, nil hooks are returned.
+func (w *Worker) roleProviderHooks() (rps []*roleProvider, h []RegisterNodeHook, cbs []RegisterNodeCallback, vers []uint64) {
+	w.RLock()
+	defer w.RUnlock()
+
+	w.logger.Debug("enumerating role provider hooks")
+
+	var roles node.RolesMask
+	for _, rp := range w.roleProviders {
+		rp.Lock()
+		role := rp.role
+		runtimeID := rp.runtimeID
+		hook := rp.hook
+		cb := rp.cb
+		ver := rp.version
+		rp.Unlock()
+
+		// Roles of paused runtimes are omitted from the registration.
+		if runtimeID != nil && w.pausedRuntimes[*runtimeID] {
+			w.logger.Debug("skipping role of paused runtime",
+				"role", role,
+				"runtime_id", *runtimeID,
+			)
+			continue
+		}
+
+		// Roles removed via the control API are omitted from the registration.
+		if role&w.removedRoles != 0 {
+			w.logger.Debug("skipping removed role",
+				"role", role,
+			)
+			continue
+		}
+
+		w.logger.Debug("role provider hook",
+			"ver", ver,
+			"role", role,
+			"hook", hook,
+			"cb", cb,
+		)
+
+		if hook == nil {
+			w.logger.Debug("nil hook for role",
+				"role", role,
+				"ver", ver,
+			)
+			return nil, nil, nil, nil
+		}
+
+		rps = append(rps, rp)
+		h = append(h, func(n *node.Node) error {
+			n.AddRoles(role)
+			return hook(n)
+		})
+		cbs = append(cbs, cb)
+		vers = append(vers, ver)
+		roles |= role
+	}
+
+	// In case all roles have been removed, there is nothing to register for.
+	if roles.IsEmptyRole() && !w.removedRoles.IsEmptyRole() {
+		w.logger.Debug("all roles have been removed")
+		return nil, nil, nil, nil
+	}
+	return
+}
+
 func (w *Worker) metricsWorker() {
 	w.logger.Info("delaying metrics worker start until initial registration")
 	select {
@@ -726,6 +784,8 @@ func (w *Worker) GetRegistrationStatus(ctx context.Context) (*control.Registrati
 	*status = w.status
 	w.RUnlock()
 
+	status.TLSRotation = w.tlsRotator.status()
+
 	if status.Descriptor == nil {
 		return status, nil
 	}
@@ -768,6 +828,90 @@ func (w *Worker) NewRuntimeRoleProvider(role node.RolesMask, runtimeID common.Na
 	return w.newRoleProvider(role, &runtimeID)
 }
 
+// SetRuntimePaused omits (or restores) all roles provided for the given runtime from subsequent
+// node registrations.
+func (w *Worker) SetRuntimePaused(runtimeID common.Namespace, paused bool) {
+	w.Lock()
+	switch paused {
+	case true:
+		w.pausedRuntimes[runtimeID] = true
+	case false:
+		delete(w.pausedRuntimes, runtimeID)
+	}
+	w.Unlock()
+
+	// Notify worker that the set of role providers has been updated.
+	select {
+	case w.registerCh <- struct{}{}:
+	default:
+	}
+}
+
+// RemoveRoles omits the given roles from subsequent node registrations and persists the change
+// so that the roles remain removed across node restarts.
+//
+// The returned channel is closed once the node has registered without the removed roles or
+// immediately in case no roles remain, as the existing registration will then simply expire.
+func (w *Worker) RemoveRoles(roles node.RolesMask) (<-chan struct{}, error) {
+	w.Lock()
+	removedRoles := w.removedRoles | roles
+	if err := w.store.PutCBOR(removedRolesStoreKey, removedRoles); err != nil {
+		w.Unlock()
+		return nil, fmt.Errorf("registration: failed to persist removed roles: %w", err)
+	}
+	w.removedRoles = removedRoles
+
+	if w.roleRemovalCh == nil {
+		w.roleRemovalCh = make(chan struct{})
+	}
+	ch := w.roleRemovalCh
+	if w.providedRolesLocked().IsEmptyRole() {
+		close(w.roleRemovalCh)
+		w.roleRemovalCh = nil
+	}
+	w.Unlock()
+
+	w.logger.Info("roles removed from registration",
+		"roles", roles,
+		"removed_roles", removedRoles,
+	)
+
+	// Notify worker that the set of role providers has been updated.
+	select {
+	case w.registerCh <- struct{}{}:
+	default:
+	}
+
+	return ch, nil
+}
+
+// RemovedRoles returns the roles that have been removed from node registrations.
+func (w *Worker) RemovedRoles() node.RolesMask {
+	w.RLock()
+	defer w.RUnlock()
+
+	return w.removedRoles
+}
+
+// ProvidedRoles returns the roles that are included in node registrations, not taking the
+// availability of role providers into account.
+func (w *Worker) ProvidedRoles() node.RolesMask {
+	w.RLock()
+	defer w.RUnlock()
+
+	return w.providedRolesLocked()
+}
+
+func (w *Worker) providedRolesLocked() node.RolesMask {
+	var roles node.RolesMask
+	for _, rp := range w.roleProviders {
+		rp.Lock()
+		roles |= rp.role
+		rp.Unlock()
+	}
+	return roles &^ w.removedRoles
+}
+
 func (w *Worker) newRoleProvider(role node.RolesMask, runtimeID *common.Namespace) (RoleProvider, error) {
 	w.logger.Debug("new role provider",
 		"id", runtimeID,
@@ -827,7 +971,90 @@ func (w *Worker) gatherConsensusAddresses(sentryConsensusAddrs []node.ConsensusA
 		return nil, fmt.Errorf("worker/registration: node has no valid consensus addresses")
 	}
 
-	return validatedAddrs, nil
+	// Advertise the addresses in priority order, omitting duplicates and the least preferred
+	// addresses over the limit.
+	var addrs []node.ConsensusAddress
+	for _, addr := range node.SortConsensusAddresses(validatedAddrs) {
+		if slices.ContainsFunc(addrs, func(other node.ConsensusAddress) bool { return addr.Equal(&other) }) {
+			continue
+		}
+		if len(addrs) == registry.MaxNodeAddresses {
+			w.logger.Warn("worker/registration: too many consensus addresses, omitting the least preferred ones",
+				"max_addresses", registry.MaxNodeAddresses,
+			)
+			break
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+// gatherP2PAddresses returns the P2P addresses to advertise, omitting duplicates and the addresses
+// over the limit. In case the addresses are prioritized, their priorities follow the given order.
+func (w *Worker) gatherP2PAddresses(p2pAddrs []node.Address, prioritized bool) ([]node.Address, []uint8) {
+	var (
+		addrs      []node.Address
+		priorities []uint8
+	)
+	for _, addr := range p2pAddrs {
+		if slices.ContainsFunc(addrs, func(other node.Address) bool { return addr.Equal(&other) }) {
+			continue
+		}
+		if len(addrs) == registry.MaxNodeAddresses {
+			w.logger.Warn("worker/registration: too many P2P addresses, omitting the least preferred ones",
+				"max_addresses", registry.MaxNodeAddresses,
+			)
+			break
+		}
+		if prioritized {
+			priorities = append(priorities, uint8(len(addrs)))
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, priorities
+}
+
+// softwareVersion returns the software version advertised in the node descriptor, optionally
+// including the upgrade handlers supported by the running binary.
+//
+// Handlers that would make the software version exceed the maximum allowed length are omitted.
+func (w *Worker) softwareVersion() node.SoftwareVersion {
+	sw := node.SoftwareVersion(version.SoftwareVersion)
+	if !config.GlobalConfig.Registration.AdvertiseUpgradeHandlers {
+		return sw
+	}
+
+	var handlers []string
+	for _, name := range migrations.SupportedHandlers() {
+		candidate := formatSoftwareVersion(append(handlers, string(name)))
+		if err := candidate.ValidateBasic(); err != nil {
+			w.logger.Debug("not advertising upgrade handler, software version too long",
+				"handler", name,
+			)
+			continue
+		}
+		handlers = append(handlers, string(name))
+	}
+	if len(handlers) == 0 {
+		return sw
+	}
+	return formatSoftwareVersion(handlers)
+}
+
+func formatSoftwareVersion(handlers []string) node.SoftwareVersion {
+	return node.SoftwareVersion(fmt.Sprintf("%s (upgrade handlers: %s)", version.SoftwareVersion, strings.Join(handlers, ",")))
+}
+
+// isFeatureVersion returns true iff the consensus feature version at the latest height is high
+// enough to enable features introduced in the given version.
+func (w *Worker) isFeatureVersion(minVersion version.Version) (bool, error) {
+	params, err := w.consensus.Core().GetParameters(w.ctx, consensus.HeightLatest)
+	if err != nil {
+		return false, err
+	}
+	return params.Parameters.IsFeatureVersion(minVersion), nil
 }
 
 func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (err error) {
@@ -837,13 +1064,31 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		"node_id", identityPublic.String(),
 	)
 
+	// TLS key rotations are only performed once node descriptors may include the next TLS public
+	// key, as otherwise the node would switch its TLS key without an overlap window. Address
+	// priorities are only advertised once node descriptors may include them.
+	features243, err := w.isFeatureVersion(migrations.Version243)
+	if err != nil {
+		return fmt.Errorf("failed to query consensus feature version: %w", err)
+	}
+
+	// Advance the TLS key rotation (if any) before building the descriptor so that both TLS keys
+	// are included during the rotation overlap window.
+	if features243 {
+		if err = w.tlsRotator.update(epoch); err != nil {
+			return fmt.Errorf("failed to update TLS key rotation: %w", err)
+		}
+	}
+	tlsSigner := w.identity.GetTLSSigner()
+	nextTLSSigner := w.identity.GetNextTLSSigner()
+
 	nodeDesc := node.Node{
 		Versioned:  cbor.NewVersioned(node.LatestNodeDescriptorVersion),
 		ID:         identityPublic,
 		EntityID:   w.entityID,
 		Expiration: uint64(epoch) + 2,
 		TLS: node.TLSInfo{
-			PubKey: w.identity.TLSSigner.Public(),
+			PubKey: tlsSigner.Public(),
 		},
 		P2P: node.P2PInfo{
 			ID: w.identity.P2PSigner.Public(),
@@ -854,7 +1099,11 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		VRF: node.VRFInfo{
 			ID: w.identity.VRFSigner.Public(),
 		},
-		SoftwareVersion: node.SoftwareVersion(version.SoftwareVersion),
+		SoftwareVersion: w.softwareVersion(),
+	}
+	if nextTLSSigner != nil && features243 {
+		nextPubKey := nextTLSSigner.Public()
+		nodeDesc.TLS.NextPubKey = &nextPubKey
 	}
 
 	// Update the registration status on successful or failed registration.
@@ -869,6 +1118,11 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 			w.status.LastAttempt = time.Now()
 			w.status.LastRegistration = w.status.LastAttempt
 			w.status.Descriptor = &nodeDesc
+
+			if w.roleRemovalCh != nil && nodeDesc.Roles&w.removedRoles == 0 {
+				close(w.roleRemovalCh)
+				w.roleRemovalCh = nil
+			}
 		default:
 			w.status.LastAttemptSuccessful = false
 			w.status.LastAttemptErrorMessage = err.Error()
@@ -902,6 +1156,12 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		return fmt.Errorf("registration: no runtimes provided while runtimes are required")
 	}
 
+	// Make sure that the local software version satisfies the constraints of all runtimes as
+	// otherwise the registration would be rejected.
+	if err = w.verifySoftwareVersion(&nodeDesc); err != nil {
+		return err
+	}
+
 	sentryConsensusAddrs := w.querySentries()
 
 	// Add Consensus Addresses if required.
@@ -910,12 +1170,19 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		if grr != nil {
 			return fmt.Errorf("error gathering consensus addresses: %w", grr)
 		}
+		if !features243 {
+			for i := range addrs {
+				addrs[i].Priority = 0
+			}
+		}
 		nodeDesc.Consensus.Addresses = addrs
 	}
 
 	// Add P2P Addresses if required.
 	if nodeDesc.HasRoles(registry.P2PAddressRequiredRoles) {
-		nodeDesc.P2P.Addresses = w.p2p.Addresses()
+		// Explicitly configured registration addresses are prioritized in the configured order.
+		prioritized := features243 && len(config.GlobalConfig.P2P.Registration.Addresses) > 0
+		nodeDesc.P2P.Addresses, nodeDesc.P2P.AddressPriorities = w.gatherP2PAddresses(w.p2p.Addresses(), prioritized)
 	}
 
 	nodeSigners := []signature.Signer{
@@ -923,7 +1190,10 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		w.identity.P2PSigner,
 		w.identity.ConsensusSigner,
 		w.identity.VRFSigner,
-		w.identity.TLSSigner,
+		tlsSigner,
+	}
+	if nextTLSSigner != nil {
+		nodeSigners = append(nodeSigners, nextTLSSigner)
 	}
 	if !w.identity.NodeSigner.Public().Equal(w.registrationSigner.Public()) {
 		// In the case where the registration signer is the entity signer
@@ -940,41 +1210,119 @@ func (w *Worker) registerNode(epoch beacon.EpochTime, hook RegisterNodeHook) (er
 		return fmt.Errorf("unable to sign node descriptor: %w", grr)
 	}
 
+	if fault.RegistrationStopped() {
+		w.logger.Warn("not submitting node registration due to an injected fault")
+		return fault.ErrInjected
+	}
+
 	tx := registry.NewRegisterNodeTx(0, nil, sigNode)
+	w.recordJournalEvent(journal.KindRegistrationSubmitted,
+		fmt.Sprintf("node registration submitted for epoch %d (roles: %s)", epoch, nodeDesc.Roles),
+	)
 	if err = consensus.SignAndSubmitTx(w.ctx, w.consensus, w.registrationSigner, tx); err != nil {
 		w.logger.Error("failed to register node",
 			"err", err,
 		)
+		w.explainAttestationFailures(&nodeDesc)
 		return err
 	}
 
 	w.logger.Info("node registered with the registry")
+	w.recordJournalEvent(journal.KindRegistrationAccepted,
+		fmt.Sprintf("node registered for epoch %d, expires in epoch %d", epoch, nodeDesc.Expiration),
+	)
 	return nil
 }
 
-func (w *Worker) querySentries() []node.ConsensusAddress {
-	var consensusAddrs []node.ConsensusAddress
-	var err error
+// recordJournalEvent records a registration event in the node event journal.
+func (w *Worker) recordJournalEvent(kind journal.Kind, message string) {
+	// The height is best-effort as it only serves as a reference point.
+	height, _ := w.consensus.Core().GetLatestHeight(w.ctx)
+	journal.Record(&journal.Event{
+		Kind:    kind,
+		Height:  height,
+		Message: message,
+	})
+}
 
-	for _, sentryAddr := range w.sentryAddresses {
-		var client *sentryClient.Client
-		client, err = sentryClient.New(sentryAddr, w.identity)
+// verifySoftwareVersion verifies that the node software version in the node descriptor satisfies
+// the minimum node software version of each runtime in the node descriptor.
+func (w *Worker) verifySoftwareVersion(nodeDesc *node.Node) error {
+	for _, rt := range nodeDesc.Runtimes {
+		regRt, err := w.consensus.Registry().GetRuntime(w.ctx, &registry.GetRuntimeQuery{
+			Height:           consensus.HeightLatest,
+			ID:               rt.ID,
+			IncludeSuspended: true,
+		})
 		if err != nil {
-			w.logger.Warn("failed to create client to a sentry node",
+			// Leave it to the registry to reject the registration in case this is a problem.
+			continue
+		}
+		if err = regRt.VerifyNodeSoftwareVersion(nodeDesc.SoftwareVersion); err != nil {
+			w.logger.Error("not registering: node software version too old, upgrade required",
 				"err", err,
-				"sentry_address", sentryAddr,
+				"runtime_id", rt.ID,
+				"software_version", nodeDesc.SoftwareVersion,
+				"min_software_version", regRt.MinNodeSoftwareVersion,
 			)
+			return fmt.Errorf("registration: %w", err)
+		}
+	}
+	return nil
+}
+
+// explainAttestationFailures logs an explanation for each runtime TEE attestation in the node
+// descriptor that does not satisfy the quote policy of the runtime.
+func (w *Worker) explainAttestationFailures(nodeDesc *node.Node) {
+	params, err := w.consensus.Registry().ConsensusParameters(w.ctx, consensus.HeightLatest)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, rt := range nodeDesc.Runtimes {
+		if rt.Capabilities.TEE == nil {
 			continue
 		}
-		defer client.Close()
 
-		// Query sentry node for addresses.
-		sentryAddresses, err := client.GetAddresses(w.ctx)
+		regRt, err := w.consensus.Registry().GetRuntime(w.ctx, &registry.GetRuntimeQuery{
+			Height:           consensus.HeightLatest,
+			ID:               rt.ID,
+			IncludeSuspended: true,
+		})
+		if err != nil {
+			continue
+		}
+		deployment := regRt.DeploymentForVersion(rt.Version)
+		if deployment == nil {
+			continue
+		}
+
+		failure := rt.Capabilities.TEE.ExplainVerificationFailure(params.TEEFeatures, now, deployment.TEE)
+		if failure == nil {
+			continue
+		}
+		w.logger.Error("runtime attestation rejected by quote policy",
+			"runtime_id", rt.ID,
+			"version", rt.Version,
+			"rule", failure.Rule,
+			"reason", failure.Reason,
+			"identity", failure.Identity,
+			"tcb_status", failure.TCBStatus,
+			"advisory_ids", failure.AdvisoryIDs,
+			"err", failure.Err,
+		)
+	}
+}
+
+func (w *Worker) querySentries() []node.ConsensusAddress {
+	var consensusAddrs []node.ConsensusAddress
+
+	// Sentry nodes can be configured with multiple addresses, which are tried in the configured
+	// order until the sentry node is reached.
+	for _, sentryAddrs := range groupSentryAddresses(w.sentryAddresses) {
+		sentryAddresses, err := queryWithFallback(w.ctx, sentryAddrs, sentryQueryTimeout, w.querySentry)
 		if err != nil {
-			w.logger.Warn("failed to obtain addresses from sentry node",
-				"err", err,
-				"sentry_address", sentryAddr,
-			)
 			continue
 		}
 		consensusAddrs = append(consensusAddrs, sentryAddresses.Consensus...)
@@ -989,6 +1337,75 @@ func (w *Worker) querySentries() []node.ConsensusAddress {
 	return consensusAddrs
 }
 
+// querySentry queries the sentry node at the given address for its addresses.
+func (w *Worker) querySentry(ctx context.Context, sentryAddr node.TLSAddress) (*sentryAPI.SentryAddresses, error) {
+	client, err := sentryClient.New(sentryAddr, w.identity)
+	if err != nil {
+		w.logger.Warn("failed to create client to a sentry node",
+			"err", err,
+			"sentry_address", sentryAddr,
+		)
+		return nil, err
+	}
+	defer client.Close()
+
+	sentryAddresses, err := client.GetAddresses(ctx)
+	if err != nil {
+		w.logger.Warn("failed to obtain addresses from sentry node",
+			"err", err,
+			"sentry_address", sentryAddr,
+		)
+		return nil, err
+	}
+	return sentryAddresses, nil
+}
+
+// groupSentryAddresses groups the configured sentry addresses by the sentry node's public key,
+// preserving the configured order of sentry nodes and of addresses of each sentry node.
+func groupSentryAddresses(addrs []node.TLSAddress) [][]node.TLSAddress {
+	var groups [][]node.TLSAddress
+	indices := make(map[signature.PublicKey]int)
+	for _, addr := range addrs {
+		idx, ok := indices[addr.PubKey]
+		if !ok {
+			idx = len(groups)
+			indices[addr.PubKey] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], addr)
+	}
+	return groups
+}
+
+// queryWithFallback performs the given query against each of the given addresses in order until
+// one succeeds, limiting each attempt to the given timeout so that unreachable addresses don't
+// block the fallback.
+func queryWithFallback[A, T any](
+	ctx context.Context,
+	addrs []A,
+	timeout time.Duration,
+	query func(context.Context, A) (T, error),
+) (T, error) {
+	var (
+		result T
+		errs   []error
+	)
+	for _, addr := range addrs {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		rsp, err := query(attemptCtx, addr)
+		cancel()
+		if err == nil {
+			return rsp, nil
+		}
+		errs = append(errs, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
 // RequestDeregistration requests that the node not register itself in the next epoch.
 func (w *Worker) RequestDeregistration() error {
 	if !atomic.CompareAndSwapUint32(&w.deregRequested, 0, 1) {
@@ -1080,6 +1497,12 @@ func New(
 		return nil, err
 	}
 
+	var removedRoles node.RolesMask
+	err = serviceStore.GetCBOR(removedRolesStoreKey, &removedRoles)
+	if err != nil && err != persistent.ErrNotFound {
+		return nil, err
+	}
+
 	w := &Worker{
 		workerCommonCfg:    workerCommonCfg,
 		store:              serviceStore,
@@ -1099,11 +1522,17 @@ func New(
 		logger:             logger,
 		consensus:          consensus,
 		p2p:                p2p,
+		pausedRuntimes:     make(map[common.Namespace]bool),
+		removedRoles:       removedRoles,
 		registerCh:         make(chan struct{}, 1),
 	}
 
 	w.storedDeregister = storedDeregister
 
+	if w.tlsRotator, err = newTLSRotator(config.GlobalConfig.Registration.TLSRotation, identity, serviceStore, logger); err != nil {
+		return nil, err
+	}
+
 	if config.GlobalConfig.Consensus.Validator || config.GlobalConfig.Mode == config.ModeValidator {
 		rp, err := w.NewRoleProvider(node.RoleValidator)
 		if err != nil {
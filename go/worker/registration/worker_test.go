@@ -0,0 +1,238 @@
+package registration
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
+	"github.com/oasisprotocol/oasis-core/go/common/persistent"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+)
+
+func TestPausedRuntimeRoles(t *testing.T) {
+	require := require.New(t)
+
+	w := &Worker{
+		pausedRuntimes: make(map[common.Namespace]bool),
+		registerCh:     make(chan struct{}, 1),
+		logger:         logging.GetLogger("worker/registration/test"),
+	}
+
+	rtID1 := common.NewTestNamespaceFromSeed([]byte("runtime 1"), 0)
+	rtID2 := common.NewTestNamespaceFromSeed([]byte("runtime 2"), 0)
+
+	rp1, err := w.NewRuntimeRoleProvider(node.RoleComputeWorker, rtID1)
+	require.NoError(err, "NewRuntimeRoleProvider")
+	rp2, err := w.NewRuntimeRoleProvider(node.RoleComputeWorker, rtID2)
+	require.NoError(err, "NewRuntimeRoleProvider")
+
+	rp1.SetAvailable(func(*node.Node) error { return nil })
+
+	_, hooks, _, _ := w.roleProviderHooks()
+	require.Nil(hooks, "unavailable role providers should block registration")
+
+	// Pausing a runtime should omit its roles instead of blocking registration.
+	w.SetRuntimePaused(rtID2, true)
+	rps, hooks, cbs, vers := w.roleProviderHooks()
+	require.Len(hooks, 1)
+	require.Len(cbs, 1)
+	require.Len(vers, 1)
+	require.Equal([]*roleProvider{rp1.(*roleProvider)}, rps)
+
+	var n node.Node
+	require.NoError(hooks[0](&n))
+	require.True(n.HasRoles(node.RoleComputeWorker))
+
+	// Roles of paused runtimes should be omitted even when available.
+	rp2.SetAvailable(func(*node.Node) error { return nil })
+	rps, _, _, _ = w.roleProviderHooks()
+	require.Len(rps, 1)
+
+	// Resuming the runtime should include its roles again.
+	w.SetRuntimePaused(rtID2, false)
+	rps, hooks, _, _ = w.roleProviderHooks()
+	require.Len(hooks, 2)
+	require.Len(rps, 2)
+}
+
+func TestRemovedRoles(t *testing.T) {
+	require := require.New(t)
+
+	store, err := persistent.NewCommonStore(t.TempDir())
+	require.NoError(err, "NewCommonStore")
+	defer store.Close()
+
+	w := &Worker{
+		store:          store.GetServiceStore(DBBucketName),
+		pausedRuntimes: make(map[common.Namespace]bool),
+		registerCh:     make(chan struct{}, 1),
+		logger:         logging.GetLogger("worker/registration/test"),
+	}
+
+	rtID := common.NewTestNamespaceFromSeed([]byte("runtime"), 0)
+
+	rpCompute, err := w.NewRuntimeRoleProvider(node.RoleComputeWorker, rtID)
+	require.NoError(err, "NewRuntimeRoleProvider")
+	rpStorage, err := w.NewRuntimeRoleProvider(node.RoleEmpty, rtID)
+	require.NoError(err, "NewRuntimeRoleProvider")
+	rpRPC, err := w.NewRuntimeRoleProvider(node.RoleStorageRPC, rtID)
+	require.NoError(err, "NewRuntimeRoleProvider")
+
+	rpStorage.SetAvailable(func(*node.Node) error { return nil })
+	rpRPC.SetAvailable(func(*node.Node) error { return nil })
+	require.Equal(node.RoleComputeWorker|node.RoleStorageRPC, w.ProvidedRoles())
+
+	// Removing a role should omit its role provider instead of blocking registration.
+	registeredCh, err := w.RemoveRoles(node.RoleComputeWorker)
+	require.NoError(err, "RemoveRoles")
+	require.Equal(node.RoleComputeWorker, w.RemovedRoles())
+	require.Equal(node.RoleStorageRPC, w.ProvidedRoles())
+	require.NotNil(registeredCh)
+	select {
+	case <-registeredCh:
+		require.Fail("role removal should only complete after registration")
+	default:
+	}
+
+	rps, hooks, _, _ := w.roleProviderHooks()
+	require.Len(hooks, 2)
+	require.Equal([]*roleProvider{rpStorage.(*roleProvider), rpRPC.(*roleProvider)}, rps)
+
+	// Roles of removed role providers should be omitted even when available.
+	rpCompute.SetAvailable(func(*node.Node) error { return nil })
+	rps, _, _, _ = w.roleProviderHooks()
+	require.Len(rps, 2)
+
+	// Removing all roles should leave nothing to register for.
+	registeredCh, err = w.RemoveRoles(node.RoleStorageRPC)
+	require.NoError(err, "RemoveRoles")
+	require.True(w.ProvidedRoles().IsEmptyRole())
+	<-registeredCh
+
+	_, hooks, _, _ = w.roleProviderHooks()
+	require.Nil(hooks, "there should be nothing to register for")
+
+	// Removed roles should be persisted.
+	var removedRoles node.RolesMask
+	err = w.store.GetCBOR(removedRolesStoreKey, &removedRoles)
+	require.NoError(err, "GetCBOR")
+	require.Equal(node.RoleComputeWorker|node.RoleStorageRPC, removedRoles)
+}
+
+func TestQueryWithFallback(t *testing.T) {
+	require := require.New(t)
+
+	// Blackholed address that accepts connections but never responds.
+	blackholed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer blackholed.Close()
+
+	// Address that responds to queries.
+	working, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	defer working.Close()
+	go func() {
+		for {
+			conn, err := working.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("addresses\n"))
+			conn.Close()
+		}
+	}()
+
+	query := func(ctx context.Context, addr string) (string, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+		return bufio.NewReader(conn).ReadString('\n')
+	}
+
+	// The top-priority address is blackholed, so the query should fall back to the next one.
+	addrs := []string{blackholed.Addr().String(), working.Addr().String()}
+	start := time.Now()
+	rsp, err := queryWithFallback(context.Background(), addrs, 100*time.Millisecond, query)
+	require.NoError(err, "queryWithFallback")
+	require.Equal("addresses\n", rsp)
+	require.GreaterOrEqual(time.Since(start), 100*time.Millisecond, "blackholed address should be tried first")
+
+	// When the top-priority address works, it should be used without waiting.
+	addrs = []string{working.Addr().String(), blackholed.Addr().String()}
+	start = time.Now()
+	rsp, err = queryWithFallback(context.Background(), addrs, time.Minute, query)
+	require.NoError(err, "queryWithFallback")
+	require.Equal("addresses\n", rsp)
+	require.Less(time.Since(start), time.Minute)
+
+	// When all addresses fail, all errors should be returned.
+	addrs = []string{blackholed.Addr().String(), blackholed.Addr().String()}
+	_, err = queryWithFallback(context.Background(), addrs, 50*time.Millisecond, query)
+	require.Error(err, "queryWithFallback should fail when all addresses are unreachable")
+
+	// Cancelling the parent context should stop the fallback.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var attempts int
+	_, err = queryWithFallback(ctx, addrs, time.Minute, func(ctx context.Context, _ string) (string, error) {
+		attempts++
+		return "", ctx.Err()
+	})
+	require.ErrorIs(err, context.Canceled)
+	require.Equal(1, attempts, "fallback should stop once the parent context is cancelled")
+}
+
+func TestGroupSentryAddresses(t *testing.T) {
+	require := require.New(t)
+
+	pk1 := signature.NewPublicKey("1000000000000000000000000000000000000000000000000000000000000001")
+	pk2 := signature.NewPublicKey("1000000000000000000000000000000000000000000000000000000000000002")
+	addrs := []node.TLSAddress{
+		{PubKey: pk1, Address: node.Address{IP: net.IPv4(127, 0, 0, 1), Port: 1}},
+		{PubKey: pk2, Address: node.Address{IP: net.IPv4(127, 0, 0, 1), Port: 2}},
+		{PubKey: pk1, Address: node.Address{IP: net.ParseIP("::1"), Port: 1}},
+	}
+
+	groups := groupSentryAddresses(addrs)
+	require.Equal([][]node.TLSAddress{{addrs[0], addrs[2]}, {addrs[1]}}, groups)
+	require.Empty(groupSentryAddresses(nil))
+}
+
+func TestGatherP2PAddresses(t *testing.T) {
+	require := require.New(t)
+
+	w := &Worker{
+		logger: logging.GetLogger("worker/registration/test"),
+	}
+
+	var addrs []node.Address
+	for i := 0; i < registry.MaxNodeAddresses+2; i++ {
+		addrs = append(addrs, node.Address{IP: net.IPv4(127, 0, 0, 1), Port: int64(9000 + i)})
+	}
+
+	gathered, priorities := w.gatherP2PAddresses(addrs[:3], false)
+	require.Equal(addrs[:3], gathered)
+	require.Nil(priorities, "addresses should not be prioritized")
+
+	gathered, priorities = w.gatherP2PAddresses([]node.Address{addrs[2], addrs[0], addrs[2], addrs[1]}, true)
+	require.Equal([]node.Address{addrs[2], addrs[0], addrs[1]}, gathered, "duplicates should be omitted")
+	require.Equal([]uint8{0, 1, 2}, priorities, "addresses should be prioritized in the given order")
+
+	gathered, priorities = w.gatherP2PAddresses(addrs, true)
+	require.Equal(addrs[:registry.MaxNodeAddresses], gathered, "addresses over the limit should be omitted")
+	require.Len(priorities, registry.MaxNodeAddresses)
+}
@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
@@ -8,6 +9,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/grpc/auth"
 	"github.com/oasisprotocol/oasis-core/go/common/identity"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/config"
 	"github.com/oasisprotocol/oasis-core/go/sentry/api"
 )
@@ -17,6 +19,41 @@ func Enabled() bool {
 	return config.GlobalConfig.Sentry.Enabled
 }
 
+// UpstreamPubKeys returns the TLS public keys of the upstream nodes authorized to use the sentry
+// node.
+func UpstreamPubKeys() ([]signature.PublicKey, error) {
+	if !Enabled() {
+		return nil, nil
+	}
+
+	pks := make([]signature.PublicKey, 0, len(config.GlobalConfig.Sentry.Control.AuthorizedPubkeys))
+	for _, pubkey := range config.GlobalConfig.Sentry.Control.AuthorizedPubkeys {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(pubkey)); err != nil {
+			return nil, fmt.Errorf("worker/sentry: failed unmarshalling upstream public key: %s: %w", pubkey, err)
+		}
+		pks = append(pks, pk)
+	}
+	return pks, nil
+}
+
+// ConsensusUpstreamAddresses returns the configured consensus addresses of the upstream nodes.
+func ConsensusUpstreamAddresses() ([]node.ConsensusAddress, error) {
+	if !Enabled() {
+		return nil, nil
+	}
+
+	addrs := make([]node.ConsensusAddress, 0, len(config.GlobalConfig.Consensus.SentryUpstreamAddresses))
+	for _, a := range config.GlobalConfig.Consensus.SentryUpstreamAddresses {
+		var addr node.ConsensusAddress
+		if err := addr.UnmarshalText([]byte(a)); err != nil {
+			return nil, fmt.Errorf("worker/sentry: failed unmarshalling consensus upstream address: %s: %w", a, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 // Worker is a sentry node worker providing its address(es) to other nodes and
 // enabling them to hide their real address(es).
 type Worker struct {
@@ -102,19 +139,29 @@ func New(sentry api.Backend, identity *identity.Identity) (*Worker, error) {
 	}
 
 	if w.enabled {
+		pks, err := UpstreamPubKeys()
+		if err != nil {
+			return nil, err
+		}
 		peerPubkeyAuth := auth.NewPeerPubkeyAuthenticator()
-		for _, pubkey := range config.GlobalConfig.Sentry.Control.AuthorizedPubkeys {
-			var pk signature.PublicKey
-			if err := pk.UnmarshalText([]byte(pubkey)); err != nil {
-				return nil, fmt.Errorf("worker/sentry: failed unmarshalling upstream public key: %s: %w", pubkey, err)
-			}
+		for _, pk := range pks {
 			peerPubkeyAuth.AllowPeerPublicKey(pk)
 		}
+		// Log rejected requests so that misconfigured upstreams are not dropped silently.
+		authFunc := func(ctx context.Context, req any) error {
+			err := peerPubkeyAuth.AuthFunc(ctx, req)
+			if err != nil {
+				w.logger.Warn("rejected request from unauthorized upstream",
+					"err", err,
+				)
+			}
+			return err
+		}
 		grpcServer, err := grpc.NewServer(&grpc.ServerConfig{
 			Name:     "sentry",
 			Port:     config.GlobalConfig.Sentry.Control.Port,
 			Identity: identity,
-			AuthFunc: peerPubkeyAuth.AuthFunc,
+			AuthFunc: authFunc,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("worker/sentry: failed to create a new gRPC server: %w", err)
@@ -6,6 +6,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/errors"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
 )
 
 // ModuleName is the storage worker module name.
@@ -30,6 +31,21 @@ var (
 	// ErrCantPauseCheckpointer is the error returned when trying to pause the checkpointer without
 	// setting the debug flag.
 	ErrCantPauseCheckpointer = errors.New(ModuleName, 2, "worker/storage: pausing checkpointer only available in debug mode")
+	// ErrRoundNotAvailable is the error returned when the requested round is not available in
+	// local storage.
+	ErrRoundNotAvailable = errors.New(ModuleName, 3, "worker/storage: round not available")
+	// ErrRoundPruned is the error returned when the requested round has already been pruned from
+	// local storage. The error context contains the nearest available round.
+	ErrRoundPruned = errors.New(ModuleName, 4, "worker/storage: round pruned")
+	// ErrIndexerDisabled is the error returned when indexer access is not enabled.
+	ErrIndexerDisabled = errors.New(ModuleName, 5, "worker/storage: indexer access not enabled")
+	// ErrInvalidIndexerToken is the error returned when an indexer access token is malformed.
+	ErrInvalidIndexerToken = errors.New(ModuleName, 6, "worker/storage: invalid indexer access token")
+	// ErrIndexerTokenExists is the error returned when an indexer access token with the same name
+	// or secret already exists.
+	ErrIndexerTokenExists = errors.New(ModuleName, 7, "worker/storage: indexer access token already exists")
+	// ErrIndexerTokenNotFound is the error returned when an indexer access token does not exist.
+	ErrIndexerTokenNotFound = errors.New(ModuleName, 8, "worker/storage: indexer access token not found")
 )
 
 // StorageWorker is the storage worker control API interface.
@@ -59,6 +75,56 @@ type PauseCheckpointerRequest struct {
 	Pause     bool             `json:"pause"`
 }
 
+// RepairRequest is a request to repair local storage state of a runtime round.
+type RepairRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+}
+
+// RepairResult is the result of a storage repair.
+type RepairResult struct {
+	// Round is the round whose roots were repaired.
+	Round uint64 `json:"round"`
+	// VisitedNodes is the number of nodes that were checked.
+	VisitedNodes uint64 `json:"visited_nodes"`
+	// MissingNodes is the number of nodes that were missing or corrupted locally.
+	MissingNodes uint64 `json:"missing_nodes"`
+	// RepairedNodes is the number of missing nodes that were restored from peers.
+	RepairedNodes uint64 `json:"repaired_nodes"`
+}
+
+// ExportStateRequest is a request to export the runtime state at a given round.
+type ExportStateRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+	// Path is the path of the archive file on the node's filesystem. The file must not exist.
+	Path string `json:"path"`
+}
+
+// ExportStateResult is the result of a runtime state export.
+type ExportStateResult struct {
+	// Round is the exported round.
+	Round uint64 `json:"round"`
+	// Path is the path of the written archive file.
+	Path string `json:"path"`
+	// StateRoot is the exported state root.
+	StateRoot storage.Root `json:"state_root"`
+	// IORoot is the exported IO root.
+	IORoot storage.Root `json:"io_root"`
+}
+
+// MaxStateStatsDepth is the maximum key prefix depth (in bits) at which runtime state statistics
+// can be grouped.
+const MaxStateStatsDepth = node.Depth(32 * 8)
+
+// StateStatsRequest is a request for runtime state statistics at a given round.
+type StateStatsRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	Round     uint64           `json:"round"`
+	// MaxDepth is the key prefix depth (in bits) at which statistics are grouped.
+	MaxDepth node.Depth `json:"max_depth"`
+}
+
 // Status is the storage worker status.
 type Status struct {
 	// Status is the current status of the storage worker.
@@ -66,4 +132,41 @@ type Status struct {
 
 	// LastFinalizedRound is the last synced and finalized round.
 	LastFinalizedRound uint64 `json:"last_finalized_round"`
+
+	// RoundLag is the number of rounds that have been finalized by the consensus layer but not
+	// yet applied to local storage.
+	RoundLag uint64 `json:"round_lag"`
+}
+
+// IndexerToken is an access token that authorizes an indexer to fetch historical storage diffs
+// and checkpoints from the indexer gRPC server.
+type IndexerToken struct {
+	// Name is the unique name of the token used in logs and metrics.
+	Name string `json:"name"`
+	// Secret is the bearer token presented by the indexer.
+	Secret string `json:"secret"`
+	// RateLimit is the maximum sustained number of requests per second (0 means no limit).
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+	// RateBurst is the maximum number of requests in a burst (defaults to the rate limit).
+	RateBurst uint64 `json:"rate_burst,omitempty"`
+	// Expiration is the UNIX timestamp after which the token is no longer accepted (0 means
+	// never).
+	Expiration int64 `json:"expiration,omitempty"`
+}
+
+// IndexerTokenInfo is the information about a configured indexer access token.
+//
+// The secret is never included.
+type IndexerTokenInfo struct {
+	// Name is the unique name of the token used in logs and metrics.
+	Name string `json:"name"`
+	// RateLimit is the maximum sustained number of requests per second (0 means no limit).
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+	// RateBurst is the maximum number of requests in a burst.
+	RateBurst uint64 `json:"rate_burst,omitempty"`
+	// Expiration is the UNIX timestamp after which the token is no longer accepted (0 means
+	// never).
+	Expiration int64 `json:"expiration,omitempty"`
+	// Expired is true iff the token has expired.
+	Expired bool `json:"expired,omitempty"`
 }
@@ -0,0 +1,292 @@
+// Package archive implements self-contained runtime state archives.
+//
+// An archive contains the full contents of the storage roots of a single runtime round together
+// with the corresponding runtime block header and the runtime descriptor that was active at that
+// round. Archives are meant for reproducing runtime bugs by booting a local node at exactly the
+// archived state.
+//
+// The archive is a gzip-compressed stream of CBOR-encoded items. The first item is the archive
+// header, followed by a root record for each storage root and any number of entry records
+// containing the root's key/value pairs in key order.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+)
+
+const (
+	// archiveVersion is the runtime state archive format version.
+	archiveVersion = 1
+
+	// maxEntriesPerRecord is the maximum number of key/value pairs stored in a single entry
+	// record.
+	maxEntriesPerRecord = 1024
+)
+
+var (
+	// ErrInvalidArchive is the error returned when a runtime state archive is malformed.
+	ErrInvalidArchive = errors.New("archive: invalid archive")
+	// ErrRootMismatch is the error returned when the archived contents of a storage root do not
+	// match its hash.
+	ErrRootMismatch = errors.New("archive: root hash mismatch")
+)
+
+// Header is the runtime state archive header.
+type Header struct {
+	cbor.Versioned
+
+	// RuntimeID is the identifier of the archived runtime.
+	RuntimeID common.Namespace `json:"runtime_id"`
+	// Round is the archived runtime round.
+	Round uint64 `json:"round"`
+	// ConsensusHeight is the consensus height at which the archived round was finalized.
+	ConsensusHeight int64 `json:"consensus_height"`
+
+	// BlockHeader is the roothash header of the archived round.
+	BlockHeader block.Header `json:"block_header"`
+	// Runtime is the runtime descriptor at the consensus height of the archived round.
+	Runtime *registry.Runtime `json:"runtime"`
+	// Deployment is the runtime deployment that was active at the archived round.
+	Deployment *registry.VersionInfo `json:"deployment,omitempty"`
+}
+
+// ValidateBasic performs basic archive header validity checks.
+func (h *Header) ValidateBasic() error {
+	if h.V != archiveVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, h.V)
+	}
+	if h.BlockHeader.Namespace != h.RuntimeID || h.BlockHeader.Round != h.Round {
+		return fmt.Errorf("%w: block header does not match the archived round", ErrInvalidArchive)
+	}
+	if h.Runtime == nil || h.Runtime.ID != h.RuntimeID {
+		return fmt.Errorf("%w: runtime descriptor does not match the archived runtime", ErrInvalidArchive)
+	}
+	return nil
+}
+
+// hasRoot checks whether the given root is one of the storage roots of the archived round.
+func (h *Header) hasRoot(root node.Root) bool {
+	for _, r := range h.BlockHeader.StorageRoots() {
+		if r.Equal(&root) {
+			return true
+		}
+	}
+	return false
+}
+
+// record is a single archive item following the header.
+type record struct {
+	// Root is set for records that start a new storage root.
+	Root *node.Root `json:"root,omitempty"`
+	// Entries are the key/value pairs of the current storage root.
+	Entries writelog.WriteLog `json:"entries,omitempty"`
+}
+
+// Writer is a runtime state archive writer.
+type Writer struct {
+	gz     *gzip.Writer
+	encode func(any) error
+
+	hdr *Header
+}
+
+// NewWriter creates a new archive writer and writes the given header.
+func NewWriter(w io.Writer, hdr *Header) (*Writer, error) {
+	hdr.Versioned = cbor.NewVersioned(archiveVersion)
+	if err := hdr.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	enc := cbor.NewEncoder(gz)
+	if err := enc.Encode(hdr); err != nil {
+		return nil, fmt.Errorf("archive: failed to write header: %w", err)
+	}
+
+	return &Writer{
+		gz:     gz,
+		encode: enc.Encode,
+		hdr:    hdr,
+	}, nil
+}
+
+// WriteRoot writes the full contents of the given storage root, read from the given iterator.
+//
+// The contents are verified against the root hash while being written.
+func (w *Writer) WriteRoot(ctx context.Context, root node.Root, it mkvs.Iterator) error {
+	if !w.hdr.hasRoot(root) {
+		return fmt.Errorf("archive: root does not belong to the archived round")
+	}
+	if err := w.encode(&record{Root: &root}); err != nil {
+		return fmt.Errorf("archive: failed to write root: %w", err)
+	}
+
+	verifier := mkvs.New(nil, nil, root.Type, mkvs.WithoutWriteLog())
+	defer verifier.Close()
+
+	var entries writelog.WriteLog
+	flush := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+		if err := w.encode(&record{Entries: entries}); err != nil {
+			return fmt.Errorf("archive: failed to write entries: %w", err)
+		}
+		entries = nil
+		return nil
+	}
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		key, value := bytes.Clone(it.Key()), bytes.Clone(it.Value())
+		if err := verifier.Insert(ctx, key, value); err != nil {
+			return fmt.Errorf("archive: failed to verify entry: %w", err)
+		}
+
+		entries = append(entries, writelog.LogEntry{Key: key, Value: value})
+		if len(entries) >= maxEntriesPerRecord {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("archive: failed to iterate root: %w", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return commitAndVerify(ctx, verifier, root)
+}
+
+// Close flushes any buffered data and finishes the archive. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	return w.gz.Close()
+}
+
+// Reader is a runtime state archive reader.
+type Reader struct {
+	gz     *gzip.Reader
+	decode func(any) error
+
+	hdr     Header
+	pending *record
+}
+
+// NewReader creates a new archive reader and reads the archive header.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	rd := &Reader{
+		gz:     gz,
+		decode: cbor.NewDecoder(gz).Decode,
+	}
+	if err = rd.decode(&rd.hdr); err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %w", ErrInvalidArchive, err)
+	}
+	if err = rd.hdr.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// Header returns the archive header.
+func (r *Reader) Header() *Header {
+	return &r.hdr
+}
+
+// ReadRoot reads the next storage root from the archive, inserts its contents into a tree
+// created by newTree and commits the tree, verifying that the resulting hash matches the archived
+// root.
+//
+// The tree returned by newTree must be empty and is closed before ReadRoot returns. In case there
+// are no more roots in the archive, io.EOF is returned.
+func (r *Reader) ReadRoot(ctx context.Context, newTree func(root node.Root) mkvs.Tree) (*node.Root, error) {
+	rec, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	if rec.Root == nil {
+		return nil, fmt.Errorf("%w: expected root record", ErrInvalidArchive)
+	}
+	root := *rec.Root
+	if !r.hdr.hasRoot(root) {
+		return nil, fmt.Errorf("%w: root does not belong to the archived round", ErrInvalidArchive)
+	}
+
+	tree := newTree(root)
+	defer tree.Close()
+
+	for {
+		rec, err = r.next()
+		switch {
+		case errors.Is(err, io.EOF):
+		case err != nil:
+			return nil, err
+		case rec.Root != nil:
+			r.pending = rec
+		default:
+			for _, entry := range rec.Entries {
+				if err = tree.Insert(ctx, entry.Key, entry.Value); err != nil {
+					return nil, fmt.Errorf("archive: failed to insert entry: %w", err)
+				}
+			}
+			continue
+		}
+		break
+	}
+
+	if err = commitAndVerify(ctx, tree, root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+func (r *Reader) next() (*record, error) {
+	if rec := r.pending; rec != nil {
+		r.pending = nil
+		return rec, nil
+	}
+
+	var rec record
+	switch err := r.decode(&rec); {
+	case errors.Is(err, io.EOF):
+		return nil, io.EOF
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	return &rec, nil
+}
+
+// Close releases resources associated with the reader. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
+
+func commitAndVerify(ctx context.Context, tree mkvs.Tree, root node.Root) error {
+	_, rootHash, err := tree.Commit(ctx, root.Namespace, root.Version)
+	if err != nil {
+		return fmt.Errorf("archive: failed to commit root: %w", err)
+	}
+	if !rootHash.Equal(&root.Hash) {
+		return fmt.Errorf("%w: expected %s got %s (type: %s)", ErrRootMismatch, root.Hash, rootHash, root.Type)
+	}
+	return nil
+}
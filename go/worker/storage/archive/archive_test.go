@@ -0,0 +1,145 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+)
+
+func populateTree(t *testing.T, tree mkvs.Tree, prefix string, count int) {
+	for i := range count {
+		err := tree.Insert(context.Background(), []byte(fmt.Sprintf("%s key %d", prefix, i)), []byte(fmt.Sprintf("value %d", i)))
+		require.NoError(t, err, "Insert")
+	}
+}
+
+func compress(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(data)
+	require.NoError(t, err, "Write")
+	require.NoError(t, gz.Close(), "Close")
+	return buf.Bytes()
+}
+
+func decompress(t *testing.T, data []byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err, "gzip.NewReader")
+	defer gz.Close()
+	raw, err := io.ReadAll(gz)
+	require.NoError(t, err, "ReadAll")
+	return raw
+}
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var runtimeID common.Namespace
+	require.NoError(runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000"))
+	const round = 42
+
+	// Prepare state and IO trees.
+	stateTree := mkvs.New(nil, nil, node.RootTypeState)
+	defer stateTree.Close()
+	populateTree(t, stateTree, "state", 2*maxEntriesPerRecord+10)
+	_, stateRoot, err := stateTree.Commit(ctx, runtimeID, round)
+	require.NoError(err, "Commit")
+
+	ioTree := mkvs.New(nil, nil, node.RootTypeIO)
+	defer ioTree.Close()
+	populateTree(t, ioTree, "io", 10)
+	_, ioRoot, err := ioTree.Commit(ctx, runtimeID, round)
+	require.NoError(err, "Commit")
+
+	hdr := &Header{
+		RuntimeID:       runtimeID,
+		Round:           round,
+		ConsensusHeight: 100,
+		BlockHeader: block.Header{
+			Namespace: runtimeID,
+			Round:     round,
+			StateRoot: stateRoot,
+			IORoot:    ioRoot,
+		},
+		Runtime: &registry.Runtime{ID: runtimeID},
+	}
+	trees := map[node.RootType]mkvs.Tree{
+		node.RootTypeState: stateTree,
+		node.RootTypeIO:    ioTree,
+	}
+
+	writeArchive := func(hdr *Header) ([]byte, error) {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, hdr)
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range hdr.BlockHeader.StorageRoots() {
+			it := trees[root.Type].NewIterator(ctx)
+			err = w.WriteRoot(ctx, root, it)
+			it.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err = w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := writeArchive(hdr)
+	require.NoError(err, "writing the archive should succeed")
+
+	// Read the archive back.
+	rd, err := NewReader(bytes.NewReader(data))
+	require.NoError(err, "NewReader")
+	defer rd.Close()
+	require.EqualValues(round, rd.Header().Round)
+	require.EqualValues(100, rd.Header().ConsensusHeight)
+	require.Equal(hdr.BlockHeader, rd.Header().BlockHeader)
+
+	newTree := func(root node.Root) mkvs.Tree {
+		return mkvs.New(nil, nil, root.Type)
+	}
+	for _, expected := range hdr.BlockHeader.StorageRoots() {
+		root, err := rd.ReadRoot(ctx, newTree)
+		require.NoError(err, "ReadRoot")
+		require.Equal(expected, *root)
+	}
+	_, err = rd.ReadRoot(ctx, newTree)
+	require.ErrorIs(err, io.EOF, "ReadRoot should return EOF after the last root")
+
+	// Writing roots that do not match their contents should fail.
+	badHdr := *hdr
+	badHdr.BlockHeader.StateRoot = hash.NewFromBytes([]byte("corrupted"))
+	_, err = writeArchive(&badHdr)
+	require.ErrorIs(err, ErrRootMismatch, "writing mismatched roots should fail")
+
+	// Headers not matching the archived runtime should be rejected.
+	badHdr = *hdr
+	badHdr.BlockHeader.Round++
+	_, err = writeArchive(&badHdr)
+	require.ErrorIs(err, ErrInvalidArchive, "writing an invalid header should fail")
+
+	// Tampered contents should be detected on import.
+	tampered := bytes.Replace(decompress(t, data), []byte("value 7"), []byte("value 8"), 1)
+	rd, err = NewReader(bytes.NewReader(compress(t, tampered)))
+	require.NoError(err, "NewReader")
+	defer rd.Close()
+	_, err = rd.ReadRoot(ctx, newTree)
+	require.ErrorIs(err, ErrRootMismatch, "tampered contents should be detected")
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
@@ -38,12 +39,20 @@ type CheckpointSyncConfig struct {
 	// only sync by applying all diffs from genesis.
 	Disabled bool
 
+	// Required specifies whether checkpoint sync is required for initial sync. In this case the
+	// node will keep retrying checkpoint sync and never fall back to applying all diffs from
+	// genesis.
+	Required bool
+
 	// ChunkFetcherCount specifies the number of parallel checkpoint chunk fetchers.
 	ChunkFetcherCount uint
 }
 
 // Validate performs configuration checks.
 func (cfg *CheckpointSyncConfig) Validate() error {
+	if cfg.Disabled && cfg.Required {
+		return fmt.Errorf("checkpoint sync cannot be both disabled and required")
+	}
 	if !cfg.Disabled && cfg.ChunkFetcherCount == 0 {
 		return fmt.Errorf("number of checkpoint chunk fetchers must be greater than zero")
 	}
@@ -55,6 +64,25 @@ type chunk struct {
 
 	// checkpoint points to the checkpoint this chunk originated from.
 	checkpoint *storageSync.Checkpoint
+
+	// source is an optional alternative location of the same chunk in an
+	// earlier incremental checkpoint that this chunk was reused from.
+	source *chunk
+}
+
+// fetch fetches the chunk from peers advertising its checkpoint, falling back to the
+// peers advertising the checkpoint the chunk was reused from, if any.
+func (c *chunk) fetch(ctx context.Context, client storageSync.Client) (*storageSync.GetCheckpointChunkResponse, rpc.PeerFeedback, error) {
+	rsp, pf, err := client.GetCheckpointChunk(ctx, &storageSync.GetCheckpointChunkRequest{
+		Version: c.Version,
+		Root:    c.Root,
+		Index:   c.Index,
+		Digest:  c.Digest,
+	}, c.checkpoint)
+	if err == nil || c.source == nil {
+		return rsp, pf, err
+	}
+	return c.source.fetch(ctx, client)
 }
 
 type chunkHeap struct {
@@ -100,12 +128,7 @@ func (n *Node) checkpointChunkFetcher(
 		defer cancel()
 
 		// Fetch chunk from peers.
-		rsp, pf, err := n.storageSync.GetCheckpointChunk(chunkCtx, &storageSync.GetCheckpointChunkRequest{
-			Version: chunk.Version,
-			Root:    chunk.Root,
-			Index:   chunk.Index,
-			Digest:  chunk.Digest,
-		}, chunk.checkpoint)
+		rsp, pf, err := chunk.fetch(chunkCtx, n.storageSync)
 		if err != nil {
 			n.logger.Error("failed to fetch chunk from peers",
 				"err", err,
@@ -156,7 +179,7 @@ func (n *Node) checkpointChunkFetcher(
 	}
 }
 
-func (n *Node) handleCheckpoint(check *storageSync.Checkpoint, maxParallelRequests uint) (cpStatus int, rerr error) {
+func (n *Node) handleCheckpoint(check *storageSync.Checkpoint, cps []*storageSync.Checkpoint, maxParallelRequests uint) (cpStatus int, rerr error) {
 	if err := n.localStorage.Checkpointer().StartRestore(n.ctx, check.Metadata); err != nil {
 		// Any previous restores were already aborted by the driver up the call stack, so
 		// things should have been going smoothly here; bail.
@@ -224,6 +247,7 @@ func (n *Node) handleCheckpoint(check *storageSync.Checkpoint, maxParallelReques
 				Root:    check.Root,
 			},
 			checkpoint: check,
+			source:     chunkSource(check, cps, uint64(i)),
 		})
 	}
 	n.logger.Debug("checkpoint chunks prepared for dispatch",
@@ -275,14 +299,47 @@ func (n *Node) handleCheckpoint(check *storageSync.Checkpoint, maxParallelReques
 	}
 }
 
+// chunkSource returns the chunk that the given chunk of an incremental checkpoint was reused
+// from, provided that its checkpoint is among the known checkpoints.
+func chunkSource(check *storageSync.Checkpoint, cps []*storageSync.Checkpoint, idx uint64) *chunk {
+	src := check.GetChunkSource(idx)
+	if src == nil {
+		return nil
+	}
+	for _, cp := range cps {
+		if cp.Version != src.Version || !cp.Root.Equal(&src.Root) {
+			continue
+		}
+		if src.Index >= uint64(len(cp.Chunks)) || !cp.Chunks[src.Index].Equal(&src.Digest) {
+			continue
+		}
+		return &chunk{
+			ChunkMetadata: src,
+			checkpoint:    cp,
+		}
+	}
+	return nil
+}
+
 func (n *Node) getCheckpointList() ([]*storageSync.Checkpoint, error) {
 	ctx, cancel := context.WithTimeout(n.ctx, cpListsTimeout)
 	defer cancel()
 
-	list, err := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
-		Version: 1,
-	})
-	if err != nil {
+	var (
+		list []*storageSync.Checkpoint
+		err  error
+	)
+	for _, version := range []uint16{1, checkpoint.IncrementalVersion} {
+		cps, verr := n.storageSync.GetCheckpoints(ctx, &storageSync.GetCheckpointsRequest{
+			Version: version,
+		})
+		if verr != nil {
+			err = verr
+			continue
+		}
+		list = append(list, cps...)
+	}
+	if len(list) == 0 && err != nil {
 		n.logger.Error("failed to retrieve any checkpoints",
 			"err", err,
 		)
@@ -417,7 +474,7 @@ func (n *Node) syncCheckpoints(genesisRound uint64, wantOnlyGenesis bool) (*bloc
 			}
 		}
 
-		status, err := n.handleCheckpoint(check, n.checkpointSyncCfg.ChunkFetcherCount)
+		status, err := n.handleCheckpoint(check, cps, n.checkpointSyncCfg.ChunkFetcherCount)
 		switch status {
 		case checkpointStatusDone:
 			n.logger.Info("successfully restored from checkpoint", "root", check.Root, "mask", mask)
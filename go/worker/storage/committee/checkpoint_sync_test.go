@@ -0,0 +1,73 @@
+package committee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
+)
+
+func TestChunkSource(t *testing.T) {
+	require := require.New(t)
+
+	ns := common.NewTestNamespaceFromSeed([]byte("checkpoint sync test ns"), 0)
+	var h1, h2, h3 hash.Hash
+	h1.FromBytes([]byte("chunk 1"))
+	h2.FromBytes([]byte("chunk 2"))
+	h3.FromBytes([]byte("chunk 3"))
+
+	prevRoot := node.Root{Namespace: ns, Version: 10, Type: node.RootTypeState}
+	prevRoot.Hash.FromBytes([]byte("previous root"))
+	root := node.Root{Namespace: ns, Version: 20, Type: node.RootTypeState}
+	root.Hash.FromBytes([]byte("root"))
+
+	prev := &storageSync.Checkpoint{
+		Metadata: &checkpoint.Metadata{
+			Version: checkpoint.IncrementalVersion,
+			Root:    prevRoot,
+			Chunks:  []hash.Hash{h1, h2},
+		},
+	}
+	check := &storageSync.Checkpoint{
+		Metadata: &checkpoint.Metadata{
+			Version: checkpoint.IncrementalVersion,
+			Root:    root,
+			Chunks:  []hash.Hash{h3, h2, h1},
+			Reused: []checkpoint.ChunkReference{
+				{Index: 1, Root: prevRoot, SourceIndex: 1},
+				{Index: 2, Root: prevRoot, SourceIndex: 0},
+			},
+		},
+	}
+	cps := []*storageSync.Checkpoint{check, prev}
+
+	require.Nil(chunkSource(check, cps, 0), "fresh chunks should have no source")
+
+	src := chunkSource(check, cps, 1)
+	require.NotNil(src, "reused chunks should have a source")
+	require.Equal(prev, src.checkpoint)
+	require.Equal(prevRoot, src.Root)
+	require.EqualValues(1, src.Index)
+	require.Equal(h2, src.Digest)
+
+	src = chunkSource(check, cps, 2)
+	require.NotNil(src, "reused chunks should have a source")
+	require.EqualValues(0, src.Index)
+
+	require.Nil(chunkSource(check, []*storageSync.Checkpoint{check}, 1), "unknown source checkpoint should be ignored")
+
+	// A source checkpoint with a different chunk should be ignored.
+	bogus := &storageSync.Checkpoint{
+		Metadata: &checkpoint.Metadata{
+			Version: checkpoint.IncrementalVersion,
+			Root:    prevRoot,
+			Chunks:  []hash.Hash{h3, h3},
+		},
+	}
+	require.Nil(chunkSource(check, []*storageSync.Checkpoint{check, bogus}, 1), "mismatched source chunk should be ignored")
+}
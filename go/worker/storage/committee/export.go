@@ -0,0 +1,115 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	registry "github.com/oasisprotocol/oasis-core/go/registry/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/archive"
+)
+
+// ExportState writes a self-contained archive of the runtime state at the given round to the
+// given path on the local filesystem.
+//
+// The archive contains the full contents of the round's storage roots, the round's block header
+// and the runtime descriptor active at that round. All root hashes are verified while exporting.
+func (n *Node) ExportState(ctx context.Context, round uint64, path string) (*api.ExportStateResult, error) {
+	select {
+	case <-n.initCh:
+	default:
+		return nil, api.ErrRoundNotAvailable
+	}
+
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("export path must be absolute")
+	}
+
+	earliest, latest, ok := n.repairWindow()
+	switch {
+	case !ok || round > latest:
+		return nil, api.ErrRoundNotAvailable
+	case round < earliest:
+		return nil, errors.WithContext(api.ErrRoundPruned, fmt.Sprintf("nearest available round: %d", earliest))
+	}
+
+	annBlk, err := n.commonNode.Runtime.History().GetAnnotatedBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block for round %d: %w", round, err)
+	}
+	rt, err := n.commonNode.Consensus.Registry().GetRuntime(ctx, &registry.GetRuntimeQuery{
+		Height:           annBlk.Height,
+		ID:               n.commonNode.Runtime.ID(),
+		IncludeSuspended: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime descriptor at height %d: %w", annBlk.Height, err)
+	}
+	epoch, err := n.commonNode.Consensus.Beacon().GetEpoch(ctx, annBlk.Height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch at height %d: %w", annBlk.Height, err)
+	}
+
+	blk := annBlk.Block
+	hdr := &archive.Header{
+		RuntimeID:       blk.Header.Namespace,
+		Round:           round,
+		ConsensusHeight: annBlk.Height,
+		BlockHeader:     blk.Header,
+		Runtime:         rt,
+		Deployment:      rt.ActiveDeployment(epoch),
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	if err = n.writeStateArchive(ctx, f, hdr); err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	n.logger.Info("exported runtime state",
+		"round", round,
+		"path", path,
+	)
+
+	return &api.ExportStateResult{
+		Round:     round,
+		Path:      path,
+		StateRoot: blk.Header.StorageRootState(),
+		IORoot:    blk.Header.StorageRootIO(),
+	}, nil
+}
+
+func (n *Node) writeStateArchive(ctx context.Context, f *os.File, hdr *archive.Header) error {
+	w, err := archive.NewWriter(f, hdr)
+	if err != nil {
+		return err
+	}
+
+	for _, root := range hdr.BlockHeader.StorageRoots() {
+		tree := mkvs.NewWithRoot(nil, n.localStorage.NodeDB(), root)
+		it := tree.NewIterator(ctx)
+		err = w.WriteRoot(ctx, root, it)
+		it.Close()
+		tree.Close()
+		if err != nil {
+			return fmt.Errorf("failed to export %s root: %w", root.Type, err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return f.Sync()
+}
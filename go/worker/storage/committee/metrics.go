@@ -31,6 +31,14 @@ var (
 		[]string{"runtime"},
 	)
 
+	storageWorkerRoundLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_storage_round_lag",
+			Help: "Number of finalized rounds not yet applied to local storage.",
+		},
+		[]string{"runtime"},
+	)
+
 	storageWorkerRoundSyncLatency = prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Name: "oasis_worker_storage_round_sync_latency",
@@ -39,11 +47,30 @@ var (
 		[]string{"runtime"},
 	)
 
+	storageWorkerRepairMissingNodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_repair_missing_nodes",
+			Help: "Number of locally missing or corrupted nodes detected by the storage repairer.",
+		},
+		[]string{"runtime"},
+	)
+
+	storageWorkerRepairRepairedNodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_repair_repaired_nodes",
+			Help: "Number of nodes restored from peers by the storage repairer.",
+		},
+		[]string{"runtime"},
+	)
+
 	storageWorkerCollectors = []prometheus.Collector{
 		storageWorkerLastFullRound,
 		storageWorkerLastSyncedRound,
 		storageWorkerLastPendingRound,
+		storageWorkerRoundLag,
 		storageWorkerRoundSyncLatency,
+		storageWorkerRepairMissingNodes,
+		storageWorkerRepairRepairedNodes,
 	}
 
 	prometheusOnce sync.Once
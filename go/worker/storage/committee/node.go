@@ -1,16 +1,19 @@
 package committee
 
 import (
+	"bytes"
 	"container/heap"
 	"context"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/eapache/channels"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cache/lru"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
@@ -23,10 +26,13 @@ import (
 	roothashApi "github.com/oasisprotocol/oasis-core/go/roothash/api"
 	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
 	runtime "github.com/oasisprotocol/oasis-core/go/runtime/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/history"
 	"github.com/oasisprotocol/oasis-core/go/runtime/host"
+	runtimeRegistry "github.com/oasisprotocol/oasis-core/go/runtime/registry"
 	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
 	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	"github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
@@ -125,7 +131,8 @@ type Node struct { // nolint: maligned
 
 	undefinedRound uint64
 
-	fetchPool *workerpool.Pool
+	fetchPool    *workerpool.Pool
+	fetcherCount uint
 
 	workerCommonCfg workerCommon.Config
 
@@ -138,6 +145,12 @@ type Node struct { // nolint: maligned
 
 	statusLock sync.RWMutex
 	status     api.StorageWorkerStatus
+	roundLag   uint64
+
+	roundLagNotifier *pubsub.Broker
+
+	stateStatsLock  sync.Mutex
+	stateStatsCache *lru.Cache
 
 	blockCh    *channels.InfiniteChannel
 	diffCh     chan *fetchedDiff
@@ -177,12 +190,17 @@ func NewNode(
 
 		localStorage: localStorage,
 
-		fetchPool: fetchPool,
+		fetchPool:    fetchPool,
+		fetcherCount: config.GlobalConfig.Storage.FetcherCount,
 
 		checkpointSyncCfg: checkpointSyncCfg,
 
 		status: api.StatusInitializing,
 
+		roundLagNotifier: pubsub.NewBroker(true),
+
+		stateStatsCache: lru.New(lru.Capacity(stateStatsCacheSize, false)),
+
 		blockCh:    channels.NewInfiniteChannel(),
 		diffCh:     make(chan *fetchedDiff),
 		finalizeCh: make(chan finalizeResult),
@@ -212,27 +230,7 @@ func NewNode(
 		Namespace:       commonNode.Runtime.ID(),
 		CheckInterval:   checkInterval,
 		RootsPerVersion: 2, // State root and I/O root.
-		GetParameters: func(ctx context.Context) (*checkpoint.CreationParameters, error) {
-			rt, rerr := commonNode.Runtime.ActiveDescriptor(ctx)
-			if rerr != nil {
-				return nil, fmt.Errorf("failed to retrieve runtime descriptor: %w", rerr)
-			}
-
-			blk, rerr := commonNode.Consensus.RootHash().GetGenesisBlock(ctx, &roothashApi.RuntimeRequest{
-				RuntimeID: rt.ID,
-				Height:    consensus.HeightLatest,
-			})
-			if rerr != nil {
-				return nil, fmt.Errorf("failed to retrieve genesis block: %w", rerr)
-			}
-
-			return &checkpoint.CreationParameters{
-				Interval:       rt.Storage.CheckpointInterval,
-				NumKept:        rt.Storage.CheckpointNumKept,
-				ChunkSize:      rt.Storage.CheckpointChunkSize,
-				InitialVersion: blk.Header.Round,
-			}, nil
-		},
+		GetParameters:   n.checkpointParameters,
 		GetRoots: func(ctx context.Context, version uint64) ([]storageApi.Root, error) {
 			blk, berr := commonNode.Runtime.History().GetCommittedBlock(ctx, version)
 			if berr != nil {
@@ -241,6 +239,8 @@ func NewNode(
 
 			return blk.Header.StorageRoots(), nil
 		},
+		Pool:        workerpool.Shared(),
+		Incremental: config.GlobalConfig.Storage.Checkpointer.Incremental,
 	}
 	var err error
 	n.checkpointer, err = checkpoint.NewCheckpointer(
@@ -260,7 +260,7 @@ func NewNode(
 	})
 
 	// Register storage sync service.
-	commonNode.P2P.RegisterProtocolServer(storageSync.NewServer(commonNode.ChainContext, commonNode.Runtime.ID(), localStorage))
+	commonNode.P2P.RegisterProtocolServer(storageSync.NewServer(commonNode.ChainContext, commonNode.Runtime.ID(), localStorage, config.GlobalConfig.Storage.DiffServingDisabled))
 	n.storageSync = storageSync.NewClient(commonNode.P2P, commonNode.ChainContext, commonNode.Runtime.ID())
 
 	// Register storage pub service if configured.
@@ -271,6 +271,71 @@ func NewNode(
 	return n, nil
 }
 
+func (n *Node) checkpointParameters(ctx context.Context) (*checkpoint.CreationParameters, error) {
+	rt, err := n.commonNode.Runtime.ActiveDescriptor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve runtime descriptor: %w", err)
+	}
+
+	blk, err := n.commonNode.Consensus.RootHash().GetGenesisBlock(ctx, &roothashApi.RuntimeRequest{
+		RuntimeID: rt.ID,
+		Height:    consensus.HeightLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve genesis block: %w", err)
+	}
+
+	return &checkpoint.CreationParameters{
+		Interval:       rt.Storage.CheckpointInterval,
+		NumKept:        rt.Storage.CheckpointNumKept,
+		ChunkSize:      rt.Storage.CheckpointChunkSize,
+		InitialVersion: blk.Header.Round,
+	}, nil
+}
+
+// ensureCheckpointed makes sure that none of the given rounds is scheduled
+// for a checkpoint that has not yet been created.
+func (n *Node) ensureCheckpointed(rounds []uint64) error {
+	if !config.GlobalConfig.Storage.Checkpointer.Enabled {
+		return nil
+	}
+
+	params, err := n.checkpointParameters(n.ctx)
+	if err != nil {
+		return err
+	}
+	if params.Interval == 0 {
+		return nil
+	}
+
+	version := uint16(1)
+	if config.GlobalConfig.Storage.Checkpointer.Incremental {
+		version = checkpoint.IncrementalVersion
+	}
+	cps, err := n.localStorage.Checkpointer().GetCheckpoints(n.ctx, &checkpoint.GetCheckpointsRequest{
+		Version:   version,
+		Namespace: n.commonNode.Runtime.ID(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get checkpoints: %w", err)
+	}
+	var lastCheckpointed uint64
+	for _, cp := range cps {
+		lastCheckpointed = max(lastCheckpointed, cp.Root.Version)
+	}
+
+	for _, round := range rounds {
+		if round <= lastCheckpointed || round < params.InitialVersion {
+			continue
+		}
+		if (round-params.InitialVersion)%params.Interval != 0 {
+			continue
+		}
+		return fmt.Errorf("%w: round %d not yet checkpointed", history.ErrRoundsRetained, round)
+	}
+	return nil
+}
+
 // Service interface.
 
 // Name returns the service name.
@@ -284,6 +349,9 @@ func (n *Node) Start() error {
 	if config.GlobalConfig.Storage.Checkpointer.Enabled {
 		go n.consensusCheckpointSyncer()
 	}
+	if config.GlobalConfig.Storage.Repair.Enabled {
+		go n.repairWorker()
+	}
 	return nil
 }
 
@@ -323,10 +391,44 @@ func (n *Node) GetStatus(context.Context) (*api.Status, error) {
 
 	return &api.Status{
 		LastFinalizedRound: n.syncedState.Round,
+		RoundLag:           n.roundLag,
 		Status:             n.status,
 	}, nil
 }
 
+// WatchRoundLag subscribes to updates of the storage round lag, which is the number of rounds
+// that have been finalized by the consensus layer but not yet applied to local storage.
+//
+// Upon subscription the current round lag is sent immediately if known.
+func (n *Node) WatchRoundLag() (<-chan uint64, pubsub.ClosableSubscription) {
+	ch := make(chan uint64)
+	sub := n.roundLagNotifier.Subscribe()
+	sub.Unwrap(ch)
+	return ch, sub
+}
+
+// This is only called from the main worker goroutine, so no locking should be necessary.
+func (n *Node) updateRoundLag(lastSynced, latest uint64) {
+	if lastSynced == n.undefinedRound || latest == n.undefinedRound {
+		return
+	}
+
+	var lag uint64
+	if latest > lastSynced {
+		lag = latest - lastSynced
+	}
+
+	n.statusLock.Lock()
+	changed := n.roundLag != lag
+	n.roundLag = lag
+	n.statusLock.Unlock()
+
+	storageWorkerRoundLag.With(n.getMetricLabels()).Set(float64(lag))
+	if changed {
+		n.roundLagNotifier.Broadcast(lag)
+	}
+}
+
 func (n *Node) PauseCheckpointer(pause bool) error {
 	if !commonFlags.DebugDontBlameOasis() {
 		return api.ErrCantPauseCheckpointer
@@ -349,10 +451,37 @@ func (n *Node) HandleNewBlockEarlyLocked(*runtime.BlockInfo) {
 
 // HandleNewBlockLocked is guarded by CrossNode.
 func (n *Node) HandleNewBlockLocked(bi *runtime.BlockInfo) {
+	// Apply settings of the active deployment.
+	n.updateFetcherCount(bi)
+
 	// Notify the state syncer that there is a new block.
 	n.blockCh.In() <- bi.RuntimeBlock
 }
 
+// updateFetcherCount resizes the fetcher pool according to the runtime deployment that is active
+// for the given block.
+func (n *Node) updateFetcherCount(bi *runtime.BlockInfo) {
+	if bi.ActiveDescriptor == nil {
+		return
+	}
+	count := runtimeRegistry.NewDeploymentFeatures(bi.ActiveDescriptor, bi.Epoch).StorageFetcherCount()
+
+	n.statusLock.Lock()
+	defer n.statusLock.Unlock()
+
+	// The pool cannot be resized once it has been stopped.
+	if count == n.fetcherCount || n.status == api.StatusStopping {
+		return
+	}
+	n.fetchPool.Resize(count)
+	n.fetcherCount = count
+
+	n.logger.Info("storage fetcher count updated",
+		"epoch", bi.Epoch,
+		"fetcher_count", count,
+	)
+}
+
 // HandleRuntimeHostEventLocked is guarded by CrossNode.
 func (n *Node) HandleRuntimeHostEventLocked(*host.Event) {
 	// Nothing to do here.
@@ -419,12 +548,24 @@ func (n *Node) fetchDiff(round uint64, prevRoot, thisRoot storageApi.Root) {
 			}
 			result.pf = pf
 			result.writeLog = rsp.WriteLog
+
+			// Write logs are applied in a streaming fashion which requires them to be sorted. Sort
+			// the fetched write log in place here so that it doesn't need to be copied when applied.
+			slices.SortFunc(result.writeLog, func(a, b storageApi.LogEntry) int {
+				return bytes.Compare(a.Key, b.Key)
+			})
 		}
 	}
 }
 
 func (n *Node) finalize(summary *blockSummary) {
-	err := n.localStorage.NodeDB().Finalize(summary.Roots)
+	var err error
+	if perr := workerpool.Shared().Run(n.ctx, workerpool.ClassNormal, func() {
+		err = n.localStorage.NodeDB().Finalize(summary.Roots)
+	}); perr != nil {
+		// The worker is stopping.
+		return
+	}
 	switch err {
 	case nil:
 		n.logger.Debug("storage round finalized",
@@ -965,6 +1106,9 @@ func (n *Node) worker() { // nolint: gocyclo
 	//
 	// - We haven't synced anything yet and checkpoint sync is not disabled.
 	//
+	// If checkpoint sync is required, failed attempts are retried until one succeeds instead of
+	// falling back to applying all diffs from genesis.
+	//
 	// If checkpoint sync is disabled but sync has been forced (e.g. because the state at genesis
 	// is non-empty), we must request to sync the checkpoint at genesis as otherwise we will jump
 	// to a later state which may not be desired given that checkpoint sync has been explicitly
@@ -987,10 +1131,11 @@ func (n *Node) worker() { // nolint: gocyclo
 			}
 
 			attempt++
-			switch n.checkpointSyncForced {
+			switch n.checkpointSyncForced || n.checkpointSyncCfg.Required {
 			case true:
 				// We have no other options but to perform a checkpoint sync as we are missing
-				// either state or authoritative blocks.
+				// either state or authoritative blocks, or syncing from genesis has been
+				// explicitly disabled via config.
 				n.logger.Info("checkpoint sync required, retrying",
 					"err", err,
 					"attempt", attempt,
@@ -1122,15 +1267,17 @@ mainLoop:
 			// Apply the write log if one exists.
 			err = nil
 			if lastDiff.fetched {
+				// Consumed write log entries are released while the rest are still being applied.
 				err = n.localStorage.Apply(n.ctx, &storageApi.ApplyRequest{
-					Namespace: lastDiff.thisRoot.Namespace,
-					RootType:  lastDiff.thisRoot.Type,
-					SrcRound:  lastDiff.prevRoot.Version,
-					SrcRoot:   lastDiff.prevRoot.Hash,
-					DstRound:  lastDiff.thisRoot.Version,
-					DstRoot:   lastDiff.thisRoot.Hash,
-					WriteLog:  lastDiff.writeLog,
+					Namespace:        lastDiff.thisRoot.Namespace,
+					RootType:         lastDiff.thisRoot.Type,
+					SrcRound:         lastDiff.prevRoot.Version,
+					SrcRoot:          lastDiff.prevRoot.Hash,
+					DstRound:         lastDiff.thisRoot.Version,
+					DstRoot:          lastDiff.thisRoot.Hash,
+					WriteLogIterator: writelog.NewDrainingIterator(lastDiff.writeLog),
 				})
+				lastDiff.writeLog = nil
 				switch {
 				case err == nil:
 					lastDiff.pf.RecordSuccess()
@@ -1199,6 +1346,7 @@ mainLoop:
 			// Check if we're far enough to reasonably register as available.
 			latestBlockRound = blk.Header.Round
 			n.nudgeAvailability(cachedLastRound, latestBlockRound)
+			n.updateRoundLag(cachedLastRound, latestBlockRound)
 
 			if _, ok := hashCache[lastFullyAppliedRound]; !ok && lastFullyAppliedRound == n.undefinedRound {
 				dummy := blockSummary{
@@ -1291,6 +1439,7 @@ mainLoop:
 
 				// Check if we're far enough to reasonably register as available.
 				n.nudgeAvailability(cachedLastRound, latestBlockRound)
+				n.updateRoundLag(cachedLastRound, latestBlockRound)
 
 				// Notify the checkpointer that there is a new finalized round.
 				if config.GlobalConfig.Storage.Checkpointer.Enabled {
@@ -1323,6 +1472,11 @@ func (p *pruneHandler) Prune(rounds []uint64) error {
 	// Make sure we never prune past what was synced.
 	lastSycnedRound, _, _ := p.node.GetLastSynced()
 
+	// Make sure we don't prune rounds that need to be checkpointed but haven't been yet.
+	if err := p.node.ensureCheckpointed(rounds); err != nil {
+		return err
+	}
+
 	for _, round := range rounds {
 		if round >= lastSycnedRound {
 			return fmt.Errorf("worker/storage: tried to prune past last synced round (last synced: %d)",
@@ -1330,8 +1484,6 @@ func (p *pruneHandler) Prune(rounds []uint64) error {
 			)
 		}
 
-		// TODO: Make sure we don't prune rounds that need to be checkpointed but haven't been yet.
-
 		p.logger.Debug("pruning storage for round", "round", round)
 
 		// Prune given block.
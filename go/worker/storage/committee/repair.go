@@ -0,0 +1,298 @@
+package committee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/config"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	storageApi "github.com/oasisprotocol/oasis-core/go/storage/api"
+	mkvsDB "github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
+)
+
+// repairProofVersion is the proof version used when fetching missing nodes. Version 0 proofs
+// always include the leaf node together with its internal node, which is how internal nodes
+// are persisted in the node database.
+const repairProofVersion uint16 = 0
+
+// errRepairNotSupported is the error returned when the node database cannot restore nodes.
+var errRepairNotSupported = errors.New("node database does not support repairs")
+
+// proofFetcher fetches subtree proofs from remote peers.
+type proofFetcher interface {
+	// Get requests a proof for the subtree rooted at the given position along the path of the
+	// given key.
+	Get(ctx context.Context, request *storageSync.GetRequest) (*storageSync.GetResponse, rpc.PeerFeedback, error)
+}
+
+// nodeRepairer walks storage roots, detects nodes that are missing from (or corrupted in) the
+// local node database and restores them from proofs fetched from peers.
+type nodeRepairer struct {
+	logger *logging.Logger
+
+	ndb      mkvsDB.NodeDB
+	fetcher  proofFetcher
+	verifier syncer.ProofVerifier
+}
+
+func newNodeRepairer(logger *logging.Logger, ndb mkvsDB.NodeDB, fetcher proofFetcher) *nodeRepairer {
+	return &nodeRepairer{
+		logger:  logger,
+		ndb:     ndb,
+		fetcher: fetcher,
+	}
+}
+
+// repairRoot walks the given root and repairs any missing nodes.
+//
+// When full is set, the whole tree is walked. Otherwise a single random path from the root
+// to a leaf is walked.
+func (r *nodeRepairer) repairRoot(ctx context.Context, root storageApi.Root, full bool, result *api.RepairResult) error {
+	if root.Hash.IsEmpty() {
+		return nil
+	}
+	return r.visit(ctx, root, &node.Pointer{Clean: true, Hash: root.Hash}, 0, nil, full, result)
+}
+
+func (r *nodeRepairer) visit(
+	ctx context.Context,
+	root storageApi.Root,
+	ptr *node.Pointer,
+	bitDepth node.Depth,
+	path node.Key,
+	full bool,
+	result *api.RepairResult,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	result.VisitedNodes++
+
+	nd, err := r.ndb.GetNode(root, ptr)
+	var ndHash hash.Hash
+	if err == nil {
+		ndHash = nd.GetHash()
+	}
+	switch {
+	case err == nil && ndHash.Equal(&ptr.Hash):
+	case err == nil || errors.Is(err, mkvsDB.ErrNodeNotFound):
+		result.MissingNodes++
+		r.logger.Warn("detected missing node in local storage",
+			"root", root,
+			"node", ptr.Hash,
+		)
+
+		nd, err = r.repairNode(ctx, root, ptr, path)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			r.logger.Error("failed to repair node",
+				"err", err,
+				"root", root,
+				"node", ptr.Hash,
+			)
+			// The subtree below a missing node cannot be walked, continue elsewhere.
+			return nil
+		}
+		result.RepairedNodes++
+		r.logger.Info("repaired missing node",
+			"root", root,
+			"node", ptr.Hash,
+		)
+	default:
+		return fmt.Errorf("failed to get node %s: %w", ptr.Hash, err)
+	}
+
+	n, ok := nd.(*node.InternalNode)
+	if !ok {
+		return nil
+	}
+
+	// The leaf node is stored together with the internal node, so only descend into children.
+	bitLength := bitDepth + n.LabelBitLength
+	path = path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+	type child struct {
+		ptr *node.Pointer
+		bit bool
+	}
+	var children []child
+	for _, c := range []child{{n.Left, false}, {n.Right, true}} {
+		if c.ptr == nil || c.ptr.Hash.IsEmpty() {
+			continue
+		}
+		children = append(children, c)
+	}
+	if !full && len(children) > 1 {
+		i := rand.IntN(len(children))
+		children = children[i : i+1]
+	}
+
+	for _, c := range children {
+		childPtr := &node.Pointer{
+			Clean:      true,
+			Hash:       c.ptr.Hash,
+			DBInternal: c.ptr.DBInternal,
+		}
+		if err = r.visit(ctx, root, childPtr, bitLength, path.AppendBit(bitLength, c.bit), full, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repairNode fetches the node identified by the given pointer from peers, verifies it and
+// persists it in the local node database.
+func (r *nodeRepairer) repairNode(ctx context.Context, root storageApi.Root, ptr *node.Pointer, path node.Key) (node.Node, error) {
+	repairer, ok := r.ndb.(mkvsDB.NodeRepairer)
+	if !ok {
+		return nil, errRepairNotSupported
+	}
+
+	rsp, pf, err := r.fetcher.Get(ctx, &storageSync.GetRequest{
+		Tree: syncer.TreeID{
+			Root:     root,
+			Position: ptr.Hash,
+		},
+		Key:          path,
+		ProofVersion: repairProofVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch node: %w", err)
+	}
+
+	subtree, err := r.verifier.VerifyProof(ctx, ptr.Hash, &rsp.Proof)
+	if err != nil {
+		pf.RecordBadPeer()
+		return nil, fmt.Errorf("failed to verify proof: %w", err)
+	}
+	if subtree == nil || subtree.Node == nil {
+		pf.RecordFailure()
+		return nil, fmt.Errorf("proof does not contain node %s", ptr.Hash)
+	}
+	pf.RecordSuccess()
+
+	if err = repairer.RepairNode(root, subtree.Node); err != nil {
+		return nil, fmt.Errorf("failed to store node: %w", err)
+	}
+	return subtree.Node, nil
+}
+
+// RepairRound walks all storage roots of the given round and restores any nodes that are
+// missing from local storage by fetching them from peers.
+func (n *Node) RepairRound(ctx context.Context, round uint64) (*api.RepairResult, error) {
+	select {
+	case <-n.initCh:
+	default:
+		return nil, api.ErrRoundNotAvailable
+	}
+
+	earliest, latest, ok := n.repairWindow()
+	if !ok || round < earliest || round > latest {
+		return nil, api.ErrRoundNotAvailable
+	}
+
+	return n.repairRound(ctx, round, true)
+}
+
+// repairWindow returns the range of rounds that are available in local storage.
+func (n *Node) repairWindow() (uint64, uint64, bool) {
+	n.syncedLock.RLock()
+	lastSynced := n.syncedState.Round
+	n.syncedLock.RUnlock()
+
+	earliest := n.localStorage.NodeDB().GetEarliestVersion()
+	if lastSynced == defaultUndefinedRound || lastSynced == n.undefinedRound || lastSynced < earliest {
+		return 0, 0, false
+	}
+	return earliest, lastSynced, true
+}
+
+func (n *Node) repairRound(ctx context.Context, round uint64, full bool) (*api.RepairResult, error) {
+	blk, err := n.commonNode.Runtime.History().GetCommittedBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block for round %d: %w", round, err)
+	}
+
+	walks := uint(1)
+	if !full {
+		walks = config.GlobalConfig.Storage.Repair.Walks
+	}
+
+	result := &api.RepairResult{Round: round}
+	defer func() {
+		labels := n.getMetricLabels()
+		storageWorkerRepairMissingNodes.With(labels).Add(float64(result.MissingNodes))
+		storageWorkerRepairRepairedNodes.With(labels).Add(float64(result.RepairedNodes))
+	}()
+
+	repairer := newNodeRepairer(n.logger, n.localStorage.NodeDB(), n.storageSync)
+	for _, root := range blk.Header.StorageRoots() {
+		for range walks {
+			if err = repairer.repairRoot(ctx, root, full, result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if result.MissingNodes > 0 {
+		n.logger.Warn("storage repair found missing nodes",
+			"round", round,
+			"full", full,
+			"visited", result.VisitedNodes,
+			"missing", result.MissingNodes,
+			"repaired", result.RepairedNodes,
+		)
+	}
+	return result, nil
+}
+
+// repairWorker periodically samples a random round within the retention window and walks
+// random paths of its storage roots, repairing any missing nodes.
+func (n *Node) repairWorker() {
+	select {
+	case <-n.initCh:
+	case <-n.ctx.Done():
+		return
+	}
+
+	cfg := config.GlobalConfig.Storage.Repair
+	n.logger.Info("starting storage repairer",
+		"interval", cfg.Interval,
+		"walks", cfg.Walks,
+	)
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		earliest, latest, ok := n.repairWindow()
+		if !ok {
+			continue
+		}
+		round := earliest + rand.Uint64N(latest-earliest+1)
+
+		if _, err := n.repairRound(n.ctx, round, false); err != nil {
+			n.logger.Debug("storage repair sample failed",
+				"err", err,
+				"round", round,
+			)
+		}
+	}
+}
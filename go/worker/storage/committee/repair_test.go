@@ -0,0 +1,259 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db/badger"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/writelog"
+	workerApi "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	storageSync "github.com/oasisprotocol/oasis-core/go/worker/storage/p2p/sync"
+)
+
+var repairTestNs = common.NewTestNamespaceFromSeed([]byte("storage repair test ns"), 0)
+
+// damagedNodeDB is a node database that is missing some nodes and returns corrupted versions of
+// others until they are repaired.
+type damagedNodeDB struct {
+	api.NodeDB
+
+	lost      map[hash.Hash]struct{}
+	corrupted map[hash.Hash]node.Node
+}
+
+func (d *damagedNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	if _, ok := d.lost[ptr.Hash]; ok {
+		return nil, api.ErrNodeNotFound
+	}
+	if n, ok := d.corrupted[ptr.Hash]; ok {
+		return n, nil
+	}
+	return d.NodeDB.GetNode(root, ptr)
+}
+
+func (d *damagedNodeDB) RepairNode(root node.Root, n node.Node) error {
+	h := n.GetHash()
+	delete(d.lost, h)
+	delete(d.corrupted, h)
+	return d.NodeDB.(api.NodeRepairer).RepairNode(root, n)
+}
+
+// unrepairableNodeDB is a damaged node database that does not support repairs.
+type unrepairableNodeDB struct {
+	api.NodeDB
+}
+
+type testProofFetcher struct {
+	ndb      api.NodeDB
+	requests int
+}
+
+func (f *testProofFetcher) Get(ctx context.Context, request *storageSync.GetRequest) (*storageSync.GetResponse, rpc.PeerFeedback, error) {
+	f.requests++
+
+	tree := mkvs.NewWithRoot(nil, f.ndb, request.Tree.Root)
+	defer tree.Close()
+
+	rsp, err := tree.SyncGet(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp, rpc.NewNopPeerFeedback(), nil
+}
+
+// badRootFetcher serves proofs from a different root.
+type badRootFetcher struct {
+	testProofFetcher
+
+	root node.Root
+}
+
+func (f *badRootFetcher) Get(ctx context.Context, request *storageSync.GetRequest) (*storageSync.GetResponse, rpc.PeerFeedback, error) {
+	rq := *request
+	rq.Tree.Root = f.root
+	rq.Tree.Position = f.root.Hash
+	return f.testProofFetcher.Get(ctx, &rq)
+}
+
+func TestRepairRoot(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.GetLogger("worker/storage/committee/test")
+
+	// Source and local databases contain the same tree, local nodes are damaged by wrapping the
+	// local database.
+	newDB := func(values int) (api.NodeDB, node.Root) {
+		ndb, err := badger.New(&api.Config{
+			Namespace:    repairTestNs,
+			MaxCacheSize: 16 * 1024 * 1024,
+			NoFsync:      true,
+			MemoryOnly:   true,
+		})
+		require.NoError(t, err, "badger.New()")
+		t.Cleanup(ndb.Close)
+
+		var wl writelog.WriteLog
+		for i := range values {
+			wl = append(wl, writelog.LogEntry{
+				Key:   []byte(fmt.Sprintf("key %04d", i)),
+				Value: []byte(fmt.Sprintf("value %d", i)),
+			})
+		}
+
+		tree := mkvs.New(nil, ndb, node.RootTypeState)
+		defer tree.Close()
+		err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(wl))
+		require.NoError(t, err, "ApplyWriteLog()")
+		_, rootHash, err := tree.Commit(ctx, repairTestNs, 1)
+		require.NoError(t, err, "Commit()")
+
+		root := node.Root{
+			Namespace: repairTestNs,
+			Version:   1,
+			Type:      node.RootTypeState,
+			Hash:      rootHash,
+		}
+		err = ndb.Finalize([]node.Root{root})
+		require.NoError(t, err, "Finalize()")
+
+		return ndb, root
+	}
+
+	const numValues = 200
+	srcDB, root := newDB(numValues)
+	localDB, localRoot := newDB(numValues)
+	require.Equal(t, root, localRoot, "local and source roots should be equal")
+
+	var (
+		nodes    []node.Node
+		internal []node.Node
+		leaves   []node.Node
+	)
+	err := api.Visit(ctx, localDB, root, func(_ context.Context, n node.Node) bool {
+		nodes = append(nodes, n)
+		switch n.(type) {
+		case *node.InternalNode:
+			internal = append(internal, n)
+		case *node.LeafNode:
+			leaves = append(leaves, n)
+		}
+		return true
+	})
+	require.NoError(t, err, "Visit()")
+
+	t.Run("Full", func(t *testing.T) {
+		require := require.New(t)
+
+		ndb := &damagedNodeDB{
+			NodeDB: localDB,
+			lost: map[hash.Hash]struct{}{
+				internal[len(internal)/2].GetHash(): {},
+				leaves[0].GetHash():                 {},
+				leaves[len(leaves)-1].GetHash():     {},
+			},
+			corrupted: map[hash.Hash]node.Node{
+				leaves[len(leaves)/2].GetHash(): &node.LeafNode{Key: []byte("corrupted"), Value: []byte("corrupted")},
+			},
+		}
+		fetcher := &testProofFetcher{ndb: srcDB}
+		r := newNodeRepairer(logger, ndb, fetcher)
+
+		var result workerApi.RepairResult
+		err := r.repairRoot(ctx, root, true, &result)
+		require.NoError(err, "repairRoot()")
+		require.EqualValues(len(nodes), result.VisitedNodes, "all nodes should be visited")
+		require.EqualValues(4, result.MissingNodes, "all damaged nodes should be detected")
+		require.EqualValues(4, result.RepairedNodes, "all damaged nodes should be repaired")
+		require.Equal(4, fetcher.requests)
+		require.Empty(ndb.lost, "lost nodes should be restored")
+		require.Empty(ndb.corrupted, "corrupted nodes should be restored")
+
+		// The repaired tree must be readable.
+		tree := mkvs.NewWithRoot(nil, ndb, root)
+		defer tree.Close()
+		for i := range numValues {
+			value, err := tree.Get(ctx, []byte(fmt.Sprintf("key %04d", i)))
+			require.NoError(err, "Get()")
+			require.Equal([]byte(fmt.Sprintf("value %d", i)), value)
+		}
+
+		// Walking again should not find anything.
+		result = workerApi.RepairResult{}
+		err = r.repairRoot(ctx, root, true, &result)
+		require.NoError(err, "repairRoot()")
+		require.EqualValues(0, result.MissingNodes)
+		require.Equal(4, fetcher.requests)
+	})
+
+	t.Run("Sample", func(t *testing.T) {
+		require := require.New(t)
+
+		// Every random walk passes through the root.
+		ndb := &damagedNodeDB{
+			NodeDB: localDB,
+			lost: map[hash.Hash]struct{}{
+				root.Hash: {},
+			},
+		}
+		r := newNodeRepairer(logger, ndb, &testProofFetcher{ndb: srcDB})
+
+		var result workerApi.RepairResult
+		err := r.repairRoot(ctx, root, false, &result)
+		require.NoError(err, "repairRoot()")
+		require.Less(result.VisitedNodes, uint64(len(nodes)), "only a single path should be walked")
+		require.EqualValues(1, result.MissingNodes)
+		require.EqualValues(1, result.RepairedNodes)
+		require.Empty(ndb.lost, "lost root node should be restored")
+	})
+
+	t.Run("BadProof", func(t *testing.T) {
+		require := require.New(t)
+
+		// A peer serving a different tree must not be able to inject nodes.
+		otherDB, otherRoot := newDB(numValues / 2)
+		ndb := &damagedNodeDB{
+			NodeDB: localDB,
+			lost: map[hash.Hash]struct{}{
+				leaves[0].GetHash(): {},
+			},
+		}
+		r := newNodeRepairer(logger, ndb, &badRootFetcher{testProofFetcher{ndb: otherDB}, otherRoot})
+
+		var result workerApi.RepairResult
+		err := r.repairRoot(ctx, root, true, &result)
+		require.NoError(err, "repairRoot()")
+		require.EqualValues(1, result.MissingNodes)
+		require.EqualValues(0, result.RepairedNodes)
+		require.Len(ndb.lost, 1, "lost node should not be restored from a bad proof")
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		require := require.New(t)
+
+		ndb := &unrepairableNodeDB{&damagedNodeDB{
+			NodeDB: localDB,
+			lost: map[hash.Hash]struct{}{
+				internal[len(internal)/2].GetHash(): {},
+			},
+		}}
+		fetcher := &testProofFetcher{ndb: srcDB}
+		r := newNodeRepairer(logger, ndb, fetcher)
+
+		var result workerApi.RepairResult
+		err := r.repairRoot(ctx, root, true, &result)
+		require.NoError(err, "repairRoot()")
+		require.Less(result.VisitedNodes, uint64(len(nodes)), "subtree of a missing node cannot be walked")
+		require.EqualValues(1, result.MissingNodes)
+		require.EqualValues(0, result.RepairedNodes)
+		require.Zero(fetcher.requests, "nothing should be fetched when repairs are not supported")
+	})
+}
@@ -0,0 +1,84 @@
+package committee
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/node"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+const (
+	// stateStatsCacheSize is the number of state statistics results that are cached.
+	stateStatsCacheSize = 16
+	// stateStatsNodesPerSecond is the maximum rate at which nodes are fetched from the node
+	// database while collecting state statistics.
+	stateStatsNodesPerSecond = 50_000
+	// stateStatsBurst is the maximum burst of nodes fetched while collecting state statistics.
+	stateStatsBurst = 1_000
+)
+
+type stateStatsKey struct {
+	root     node.Root
+	maxDepth node.Depth
+}
+
+// StateStats walks the runtime state at the given round and returns per-prefix aggregate node
+// counts, key counts and sizes.
+//
+// Results are cached per finalized root. Only a single walk runs at a time and it is rate
+// limited to avoid starving other users of the node database.
+func (n *Node) StateStats(ctx context.Context, round uint64, maxDepth node.Depth) (*mkvs.TreeStats, error) {
+	select {
+	case <-n.initCh:
+	default:
+		return nil, api.ErrRoundNotAvailable
+	}
+
+	if maxDepth > api.MaxStateStatsDepth {
+		return nil, fmt.Errorf("maximum depth must not exceed %d bits", api.MaxStateStatsDepth)
+	}
+
+	earliest, latest, ok := n.repairWindow()
+	switch {
+	case !ok || round > latest:
+		return nil, api.ErrRoundNotAvailable
+	case round < earliest:
+		return nil, errors.WithContext(api.ErrRoundPruned, fmt.Sprintf("nearest available round: %d", earliest))
+	}
+
+	blk, err := n.commonNode.Runtime.History().GetCommittedBlock(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block for round %d: %w", round, err)
+	}
+	key := stateStatsKey{
+		root:     blk.Header.StorageRootState(),
+		maxDepth: maxDepth,
+	}
+
+	n.stateStatsLock.Lock()
+	defer n.stateStatsLock.Unlock()
+
+	if cached, ok := n.stateStatsCache.Get(key); ok {
+		return cached.(*mkvs.TreeStats), nil
+	}
+
+	stats, err := mkvs.Stats(ctx, n.localStorage.NodeDB(), key.root, maxDepth,
+		mkvs.StatsRateLimit(stateStatsNodesPerSecond, stateStatsBurst),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect state statistics: %w", err)
+	}
+	_ = n.stateStatsCache.Put(key, stats)
+
+	n.logger.Info("collected runtime state statistics",
+		"round", round,
+		"max_depth", maxDepth,
+		"nodes", stats.Total.Nodes,
+		"keys", stats.Total.Keys,
+	)
+
+	return stats, nil
+}
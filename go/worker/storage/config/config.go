@@ -2,6 +2,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/db"
@@ -13,6 +14,9 @@ type Config struct {
 	Backend string `yaml:"backend"`
 	// Maximum in-memory cache size.
 	MaxCacheSize string `yaml:"max_cache_size"`
+	// Maximum estimated size of pending changes kept in memory while applying
+	// write logs (0 means no limit).
+	ApplyMemoryBudget string `yaml:"apply_memory_budget"`
 	// Number of concurrent storage diff fetchers.
 	FetcherCount uint `yaml:"fetcher_count"`
 
@@ -20,9 +24,20 @@ type Config struct {
 	PublicRPCEnabled bool `yaml:"public_rpc_enabled,omitempty"`
 	// Disable initial storage sync from checkpoints.
 	CheckpointSyncDisabled bool `yaml:"checkpoint_sync_disabled,omitempty"`
+	// Disable initial storage sync by applying all diffs from genesis. Initial sync from
+	// checkpoints is retried until it succeeds.
+	InitialDiffSyncDisabled bool `yaml:"initial_diff_sync_disabled,omitempty"`
+	// Disable serving storage diffs to other nodes.
+	DiffServingDisabled bool `yaml:"diff_serving_disabled,omitempty"`
 
 	// Storage checkpointer configuration.
 	Checkpointer CheckpointerConfig `yaml:"checkpointer,omitempty"`
+
+	// Storage repair configuration.
+	Repair RepairConfig `yaml:"repair,omitempty"`
+
+	// Indexer access configuration.
+	Indexer IndexerConfig `yaml:"indexer,omitempty"`
 }
 
 // CheckpointerConfig is the storage worker checkpointer configuration structure.
@@ -31,13 +46,74 @@ type CheckpointerConfig struct {
 	Enabled bool `yaml:"enabled"`
 	// Storage checkpointer check interval.
 	CheckInterval time.Duration `yaml:"check_interval"`
+	// Create incremental checkpoints that reuse unchanged chunks of the
+	// previous checkpoint.
+	Incremental bool `yaml:"incremental"`
+}
+
+// RepairConfig is the storage worker repair configuration structure.
+type RepairConfig struct {
+	// Enable periodic sampling of local state for missing nodes.
+	Enabled bool `yaml:"enabled"`
+	// Interval between consecutive samples.
+	Interval time.Duration `yaml:"interval"`
+	// Number of random subtree walks performed for each sampled root.
+	Walks uint `yaml:"walks"`
+}
+
+// IndexerConfig is the indexer access configuration structure.
+type IndexerConfig struct {
+	// TCP port of the indexer gRPC server serving historical storage diffs and checkpoints to
+	// token-authenticated indexers (0 disables indexer access).
+	Port uint16 `yaml:"port,omitempty"`
+	// Static indexer access tokens.
+	Tokens []IndexerTokenConfig `yaml:"tokens,omitempty"`
+	// Path to a YAML file with a list of additional indexer access tokens.
+	TokensFile string `yaml:"tokens_file,omitempty"`
+}
+
+// IndexerTokenConfig is the indexer access token configuration structure.
+type IndexerTokenConfig struct {
+	// Unique name of the token used in logs and metrics.
+	Name string `yaml:"name"`
+	// Bearer token presented by the indexer.
+	Secret string `yaml:"secret"`
+	// Maximum sustained number of requests per second (0 means no limit).
+	RateLimit uint64 `yaml:"rate_limit,omitempty"`
+	// Maximum number of requests in a burst (defaults to the rate limit).
+	RateBurst uint64 `yaml:"rate_burst,omitempty"`
+	// Time after which the token is no longer accepted (zero means never).
+	Expiration time.Time `yaml:"expiration,omitempty"`
 }
 
 // Validate validates the configuration settings.
 func (c *Config) Validate() error {
 	if c.Backend != "auto" {
-		_, err := db.GetBackendByName(c.Backend)
-		return err
+		if _, err := db.GetBackendByName(c.Backend); err != nil {
+			return err
+		}
+	}
+	if c.CheckpointSyncDisabled && c.InitialDiffSyncDisabled {
+		return fmt.Errorf("checkpoint_sync_disabled and initial_diff_sync_disabled are mutually exclusive")
+	}
+	if c.Repair.Enabled {
+		if c.Repair.Interval <= 0 {
+			return fmt.Errorf("repair.interval must be greater than zero")
+		}
+		if c.Repair.Walks == 0 {
+			return fmt.Errorf("repair.walks must be greater than zero")
+		}
+	}
+	if c.Indexer.Port == 0 && (len(c.Indexer.Tokens) > 0 || c.Indexer.TokensFile != "") {
+		return fmt.Errorf("indexer.port must be set when indexer access tokens are configured")
+	}
+	for _, token := range c.Indexer.Tokens {
+		if token.Name == "" {
+			return fmt.Errorf("indexer.tokens: name must not be empty")
+		}
+		if token.Secret == "" {
+			return fmt.Errorf("indexer.tokens: secret of token '%s' must not be empty", token.Name)
+		}
 	}
 	return nil
 }
@@ -45,14 +121,22 @@ func (c *Config) Validate() error {
 // DefaultConfig returns the default configuration settings.
 func DefaultConfig() Config {
 	return Config{
-		Backend:                "auto",
-		MaxCacheSize:           "64mb",
-		FetcherCount:           4,
-		PublicRPCEnabled:       false,
-		CheckpointSyncDisabled: false,
+		Backend:                 "auto",
+		MaxCacheSize:            "64mb",
+		ApplyMemoryBudget:       "256mb",
+		FetcherCount:            4,
+		PublicRPCEnabled:        false,
+		CheckpointSyncDisabled:  false,
+		InitialDiffSyncDisabled: false,
+		DiffServingDisabled:     false,
 		Checkpointer: CheckpointerConfig{
 			Enabled:       false,
 			CheckInterval: 1 * time.Minute,
 		},
+		Repair: RepairConfig{
+			Enabled:  false,
+			Interval: 10 * time.Minute,
+			Walks:    16,
+		},
 	}
 }
@@ -0,0 +1,275 @@
+// Package indexer implements token-authenticated access to historical storage diffs and
+// checkpoints for external indexers.
+//
+// Indexers that are neither committee members nor run full storage nodes can fetch write logs
+// and checkpoints over a dedicated gRPC server by presenting a bearer token. Each token has its
+// own rate limit and optional expiration and can be added or revoked at runtime. Storage serving
+// policies of the P2P protocols are not affected.
+package indexer
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/logging"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+)
+
+const (
+	// authorizationHeader is the metadata key carrying the bearer token.
+	authorizationHeader = "authorization"
+	// bearerPrefix is the authorization scheme prefix of bearer tokens.
+	bearerPrefix = "Bearer "
+)
+
+var (
+	// errUnauthenticated is the error returned when token validation fails. It intentionally
+	// does not include any details about the reason for the rejection.
+	errUnauthenticated = status.Error(codes.Unauthenticated, "unauthenticated")
+	// errPermissionDenied is the error returned when a token is used to call a method that is not
+	// available to indexers.
+	errPermissionDenied = status.Error(codes.PermissionDenied, "permission denied")
+	// errRateLimited is the error returned when a token exceeds its rate limit.
+	errRateLimited = status.Error(codes.ResourceExhausted, "rate limit exceeded")
+)
+
+// allowedMethods are the read-only storage methods available to indexers.
+var allowedMethods = map[string]bool{
+	storage.MethodGetDiff.FullName():                true,
+	storage.MethodGetCheckpoints.FullName():         true,
+	storage.MethodGetCheckpointChunk.FullName():     true,
+	storage.MethodGetCheckpointChunkInfo.FullName(): true,
+}
+
+type token struct {
+	api.IndexerTokenInfo
+
+	secret  hash.Hash
+	limiter *rate.Limiter
+}
+
+func (t *token) isExpired(now time.Time) bool {
+	return t.Expiration != 0 && now.Unix() >= t.Expiration
+}
+
+// Authenticator authenticates indexer requests using bearer tokens.
+type Authenticator struct {
+	sync.RWMutex
+
+	tokens map[string]*token
+
+	now    func() time.Time
+	logger *logging.Logger
+}
+
+// Add adds a new indexer access token.
+func (a *Authenticator) Add(t *api.IndexerToken) error {
+	switch {
+	case t.Name == "":
+		return fmt.Errorf("%w: name must not be empty", api.ErrInvalidIndexerToken)
+	case t.Secret == "":
+		return fmt.Errorf("%w: secret must not be empty", api.ErrInvalidIndexerToken)
+	}
+
+	tok := &token{
+		IndexerTokenInfo: api.IndexerTokenInfo{
+			Name:       t.Name,
+			RateLimit:  t.RateLimit,
+			RateBurst:  t.RateBurst,
+			Expiration: t.Expiration,
+		},
+		secret: hash.NewFromBytes([]byte(t.Secret)),
+	}
+	if t.RateLimit > 0 {
+		if tok.RateBurst == 0 {
+			tok.RateBurst = t.RateLimit
+		}
+		tok.limiter = rate.NewLimiter(rate.Limit(t.RateLimit), int(tok.RateBurst))
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if _, ok := a.tokens[t.Name]; ok {
+		return fmt.Errorf("%w: %s", api.ErrIndexerTokenExists, t.Name)
+	}
+	for _, other := range a.tokens {
+		if other.secret.Equal(&tok.secret) {
+			return fmt.Errorf("%w: %s", api.ErrIndexerTokenExists, t.Name)
+		}
+	}
+	a.tokens[t.Name] = tok
+
+	a.logger.Info("indexer access token added",
+		"name", t.Name,
+		"rate_limit", t.RateLimit,
+		"expiration", t.Expiration,
+	)
+
+	return nil
+}
+
+// Revoke revokes the indexer access token with the given name.
+func (a *Authenticator) Revoke(name string) error {
+	a.Lock()
+	defer a.Unlock()
+
+	if _, ok := a.tokens[name]; !ok {
+		return fmt.Errorf("%w: %s", api.ErrIndexerTokenNotFound, name)
+	}
+	delete(a.tokens, name)
+
+	a.logger.Info("indexer access token revoked",
+		"name", name,
+	)
+
+	return nil
+}
+
+// Tokens returns information about all indexer access tokens, sorted by name.
+func (a *Authenticator) Tokens() []*api.IndexerTokenInfo {
+	now := a.now()
+
+	a.RLock()
+	defer a.RUnlock()
+
+	tokens := make([]*api.IndexerTokenInfo, 0, len(a.tokens))
+	for _, tok := range a.tokens {
+		info := tok.IndexerTokenInfo
+		info.Expired = tok.isExpired(now)
+		tokens = append(tokens, &info)
+	}
+	slices.SortFunc(tokens, func(x, y *api.IndexerTokenInfo) int {
+		return strings.Compare(x.Name, y.Name)
+	})
+	return tokens
+}
+
+// lookup returns the token with the given secret.
+func (a *Authenticator) lookup(secret string) *token {
+	h := hash.NewFromBytes([]byte(secret))
+
+	a.RLock()
+	defer a.RUnlock()
+
+	var found *token
+	for _, tok := range a.tokens {
+		// Compare all tokens in constant time to avoid leaking which tokens exist.
+		if subtle.ConstantTimeCompare(tok.secret[:], h[:]) == 1 {
+			found = tok
+		}
+	}
+	return found
+}
+
+// authorize checks whether the caller is allowed to call the given method.
+func (a *Authenticator) authorize(ctx context.Context, method string) error {
+	secret, ok := bearerToken(ctx)
+	if !ok {
+		indexerRejectedRequests.Inc()
+		return a.reject(method, "missing token")
+	}
+	tok := a.lookup(secret)
+	if tok == nil {
+		indexerRejectedRequests.Inc()
+		return a.reject(method, "unknown token")
+	}
+
+	labels := prometheus.Labels{"token": tok.Name, "method": method}
+	switch {
+	case tok.isExpired(a.now()):
+		labels["result"] = resultExpired
+		indexerRequests.With(labels).Inc()
+		return a.reject(method, "expired token", "token", tok.Name)
+	case !allowedMethods[method]:
+		labels["result"] = resultDenied
+		indexerRequests.With(labels).Inc()
+		a.logger.Debug("request denied",
+			"method", method,
+			"token", tok.Name,
+		)
+		return errPermissionDenied
+	case tok.limiter != nil && !tok.limiter.Allow():
+		labels["result"] = resultRateLimited
+		indexerRequests.With(labels).Inc()
+		a.logger.Debug("request rate limited",
+			"method", method,
+			"token", tok.Name,
+		)
+		return errRateLimited
+	}
+
+	labels["result"] = resultAllowed
+	indexerRequests.With(labels).Inc()
+	return nil
+}
+
+func (a *Authenticator) reject(method string, reason string, keyvals ...any) error {
+	a.logger.Debug("request rejected",
+		append([]any{"method", method, "reason", reason}, keyvals...)...,
+	)
+
+	return errUnauthenticated
+}
+
+// UnaryServerInterceptor returns a unary server interceptor enforcing indexer authentication.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor enforcing indexer authentication.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// bearerToken returns the bearer token from the incoming request metadata.
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(authorizationHeader)
+	if len(values) != 1 {
+		return "", false
+	}
+	secret, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok || secret == "" {
+		return "", false
+	}
+	return secret, true
+}
+
+// NewAuthenticator creates a new indexer authenticator without any tokens.
+func NewAuthenticator() *Authenticator {
+	initMetrics()
+
+	return &Authenticator{
+		tokens: make(map[string]*token),
+		now:    time.Now,
+		logger: logging.GetLogger("worker/storage/indexer"),
+	}
+}
@@ -0,0 +1,122 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/config"
+)
+
+func withToken(secret string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, bearerPrefix+secret))
+}
+
+func TestAuthenticatorTokens(t *testing.T) {
+	require := require.New(t)
+
+	a := NewAuthenticator()
+	err := a.Add(&api.IndexerToken{Name: "", Secret: "secret"})
+	require.ErrorIs(err, api.ErrInvalidIndexerToken, "Add should fail for an empty name")
+	err = a.Add(&api.IndexerToken{Name: "empty"})
+	require.ErrorIs(err, api.ErrInvalidIndexerToken, "Add should fail for an empty secret")
+
+	require.NoError(a.Add(&api.IndexerToken{Name: "b", Secret: "secret-b", RateLimit: 10}), "Add")
+	require.NoError(a.Add(&api.IndexerToken{Name: "a", Secret: "secret-a", Expiration: 1}), "Add")
+	err = a.Add(&api.IndexerToken{Name: "a", Secret: "other"})
+	require.ErrorIs(err, api.ErrIndexerTokenExists, "Add should fail for a duplicate name")
+	err = a.Add(&api.IndexerToken{Name: "c", Secret: "secret-a"})
+	require.ErrorIs(err, api.ErrIndexerTokenExists, "Add should fail for a duplicate secret")
+
+	require.Equal([]*api.IndexerTokenInfo{
+		{Name: "a", Expiration: 1, Expired: true},
+		{Name: "b", RateLimit: 10, RateBurst: 10},
+	}, a.Tokens(), "Tokens should be sorted by name and never include secrets")
+
+	require.NoError(a.Revoke("a"), "Revoke")
+	err = a.Revoke("a")
+	require.ErrorIs(err, api.ErrIndexerTokenNotFound, "Revoke should fail for an unknown token")
+	require.Len(a.Tokens(), 1)
+}
+
+func TestAuthenticatorAuthorize(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1000, 0)
+	a := NewAuthenticator()
+	a.now = func() time.Time { return now }
+
+	require.NoError(a.Add(&api.IndexerToken{Name: "valid", Secret: "valid", Expiration: 2000}), "Add")
+	require.NoError(a.Add(&api.IndexerToken{Name: "limited", Secret: "limited", RateLimit: 1, RateBurst: 2}), "Add")
+	method := storage.MethodGetDiff.FullName()
+
+	err := a.authorize(context.Background(), method)
+	require.Equal(codes.Unauthenticated, status.Code(err), "missing token should be rejected")
+	err = a.authorize(withToken("unknown"), method)
+	require.Equal(codes.Unauthenticated, status.Code(err), "unknown token should be rejected")
+
+	require.NoError(a.authorize(withToken("valid"), method), "valid token should be accepted")
+	err = a.authorize(withToken("valid"), storage.MethodSyncGet.FullName())
+	require.Equal(codes.PermissionDenied, status.Code(err), "methods not available to indexers should be denied")
+
+	// Tokens are rate limited individually.
+	require.NoError(a.authorize(withToken("limited"), method), "request within burst should be accepted")
+	require.NoError(a.authorize(withToken("limited"), method), "request within burst should be accepted")
+	err = a.authorize(withToken("limited"), method)
+	require.Equal(codes.ResourceExhausted, status.Code(err), "request over burst should be rate limited")
+	require.NoError(a.authorize(withToken("valid"), method), "other tokens should not be rate limited")
+
+	// Expired tokens are rejected.
+	now = time.Unix(2000, 0)
+	err = a.authorize(withToken("valid"), method)
+	require.Equal(codes.Unauthenticated, status.Code(err), "expired token should be rejected")
+
+	// Revoked tokens are rejected.
+	require.NoError(a.Revoke("limited"), "Revoke")
+	err = a.authorize(withToken("limited"), method)
+	require.Equal(codes.Unauthenticated, status.Code(err), "revoked token should be rejected")
+}
+
+func TestLoadTokensFile(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	err := os.WriteFile(path, []byte(`
+- name: explorer
+  secret: explorer-secret
+  rate_limit: 50
+  expiration: 2030-01-01T00:00:00Z
+- name: archive
+  secret: archive-secret
+`), 0o600)
+	require.NoError(err, "WriteFile")
+
+	tokens, err := LoadTokensFile(path)
+	require.NoError(err, "LoadTokensFile")
+	require.Len(tokens, 2)
+	require.Equal(&api.IndexerToken{
+		Name:       "explorer",
+		Secret:     "explorer-secret",
+		RateLimit:  50,
+		Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}, TokenFromConfig(&tokens[0]))
+	require.Equal(&api.IndexerToken{
+		Name:   "archive",
+		Secret: "archive-secret",
+	}, TokenFromConfig(&tokens[1]))
+
+	_, err = LoadTokensFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(err, "LoadTokensFile should fail for a missing file")
+
+	var cfg config.IndexerTokenConfig
+	require.Equal(&api.IndexerToken{}, TokenFromConfig(&cfg))
+}
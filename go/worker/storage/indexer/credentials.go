@@ -0,0 +1,32 @@
+package indexer
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+type tokenCredentials struct {
+	secret string
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (c *tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{
+		authorizationHeader: bearerPrefix + c.secret,
+	}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// NewTokenCredentials creates per-RPC credentials that present the given indexer access token.
+//
+// The credentials can only be used over TLS connections.
+func NewTokenCredentials(secret string) credentials.PerRPCCredentials {
+	return &tokenCredentials{
+		secret: secret,
+	}
+}
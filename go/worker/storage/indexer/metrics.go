@@ -0,0 +1,44 @@
+package indexer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultAllowed     = "allowed"
+	resultDenied      = "denied"
+	resultExpired     = "expired"
+	resultRateLimited = "rate_limited"
+)
+
+var (
+	indexerRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_indexer_requests",
+			Help: "Number of indexer storage requests by token, method and result.",
+		},
+		[]string{"token", "method", "result"},
+	)
+
+	indexerRejectedRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_indexer_rejected_requests",
+			Help: "Number of indexer storage requests rejected due to a missing or unknown token.",
+		},
+	)
+
+	indexerCollectors = []prometheus.Collector{
+		indexerRequests,
+		indexerRejectedRequests,
+	}
+
+	prometheusOnce sync.Once
+)
+
+func initMetrics() {
+	prometheusOnce.Do(func() {
+		prometheus.MustRegister(indexerCollectors...)
+	})
+}
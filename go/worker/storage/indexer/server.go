@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/config"
+)
+
+// StorageLookup returns the local storage backend of the given runtime.
+type StorageLookup func(runtimeID common.Namespace) (storage.Backend, error)
+
+// Server is the indexer gRPC server.
+type Server struct {
+	*cmnGrpc.Server
+
+	auth *Authenticator
+}
+
+// Authenticator returns the authenticator of the indexer gRPC server.
+func (s *Server) Authenticator() *Authenticator {
+	return s.auth
+}
+
+// New creates a new indexer gRPC server serving the storage of the runtimes available via the
+// given lookup function.
+func New(cfg *config.IndexerConfig, identity *identity.Identity, lookup StorageLookup) (*Server, error) {
+	auth := NewAuthenticator()
+
+	tokens := cfg.Tokens
+	if cfg.TokensFile != "" {
+		fileTokens, err := LoadTokensFile(cfg.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, fileTokens...)
+	}
+	for _, t := range tokens {
+		if err := auth.Add(TokenFromConfig(&t)); err != nil {
+			return nil, fmt.Errorf("worker/storage/indexer: failed to add token: %w", err)
+		}
+	}
+
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name:     "indexer",
+		Port:     cfg.Port,
+		Identity: identity,
+		CustomOptions: []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(auth.StreamServerInterceptor()),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("worker/storage/indexer: failed to create gRPC server: %w", err)
+	}
+	storage.RegisterService(grpcServer.Server(), &backend{lookup: lookup})
+
+	return &Server{
+		Server: grpcServer,
+		auth:   auth,
+	}, nil
+}
+
+// TokenFromConfig converts an indexer access token configuration into a token.
+func TokenFromConfig(cfg *config.IndexerTokenConfig) *api.IndexerToken {
+	t := &api.IndexerToken{
+		Name:      cfg.Name,
+		Secret:    cfg.Secret,
+		RateLimit: cfg.RateLimit,
+		RateBurst: cfg.RateBurst,
+	}
+	if !cfg.Expiration.IsZero() {
+		t.Expiration = cfg.Expiration.Unix()
+	}
+	return t
+}
+
+// LoadTokensFile loads a list of indexer access tokens from the given YAML file.
+func LoadTokensFile(path string) ([]config.IndexerTokenConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("worker/storage/indexer: failed to read tokens file: %w", err)
+	}
+	var tokens []config.IndexerTokenConfig
+	if err = yaml.Unmarshal(raw, &tokens); err != nil {
+		return nil, fmt.Errorf("worker/storage/indexer: malformed tokens file: %w", err)
+	}
+	return tokens, nil
+}
+
+// backend is the storage backend exposed to indexers.
+//
+// Only the read-only methods that are allowed by the authenticator are supported.
+type backend struct {
+	lookup StorageLookup
+}
+
+func (b *backend) SyncGet(context.Context, *storage.GetRequest) (*storage.ProofResponse, error) {
+	return nil, errPermissionDenied
+}
+
+func (b *backend) SyncGetPrefixes(context.Context, *storage.GetPrefixesRequest) (*storage.ProofResponse, error) {
+	return nil, errPermissionDenied
+}
+
+func (b *backend) SyncIterate(context.Context, *storage.IterateRequest) (*storage.ProofResponse, error) {
+	return nil, errPermissionDenied
+}
+
+func (b *backend) GetDiff(ctx context.Context, request *storage.GetDiffRequest) (storage.WriteLogIterator, error) {
+	s, err := b.lookup(request.StartRoot.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDiff(ctx, request)
+}
+
+func (b *backend) GetCheckpoints(ctx context.Context, request *checkpoint.GetCheckpointsRequest) ([]*checkpoint.Metadata, error) {
+	s, err := b.lookup(request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCheckpoints(ctx, request)
+}
+
+func (b *backend) GetCheckpointChunk(ctx context.Context, chunk *checkpoint.ChunkMetadata, w io.Writer) error {
+	s, err := b.lookup(chunk.Root.Namespace)
+	if err != nil {
+		return err
+	}
+	return s.GetCheckpointChunk(ctx, chunk, w)
+}
+
+func (b *backend) GetCheckpointChunkInfo(ctx context.Context, chunk *checkpoint.ChunkMetadata) (*checkpoint.ChunkInfo, error) {
+	s, err := b.lookup(chunk.Root.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCheckpointChunkInfo(ctx, chunk)
+}
+
+func (b *backend) Cleanup() {
+}
+
+func (b *backend) Initialized() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
@@ -0,0 +1,112 @@
+package indexer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	cmnGrpc "github.com/oasisprotocol/oasis-core/go/common/grpc"
+	"github.com/oasisprotocol/oasis-core/go/common/identity"
+	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/storage/database"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/api"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/config"
+)
+
+func TestServer(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("indexer server test ns"), 0)
+	localBackend, err := database.New(&storage.Config{
+		Backend:      database.BackendNameBadgerDB,
+		DB:           t.TempDir(),
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "database.New")
+	defer localBackend.Cleanup()
+
+	lookup := func(id common.Namespace) (storage.Backend, error) {
+		if id != testNs {
+			return nil, api.ErrRuntimeNotFound
+		}
+		return localBackend, nil
+	}
+
+	serverIdentity, err := identity.LoadOrGenerate(t.TempDir(), memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate (server)")
+	cfg := &config.IndexerConfig{
+		Port: 52176,
+		Tokens: []config.IndexerTokenConfig{
+			{Name: "static", Secret: "static-secret"},
+		},
+	}
+	srv, err := New(cfg, serverIdentity, lookup)
+	require.NoError(err, "New")
+	require.NoError(srv.Start(), "Start")
+	defer func() {
+		srv.Stop()
+		srv.Cleanup()
+	}()
+
+	clientIdentity, err := identity.LoadOrGenerate(t.TempDir(), memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate (client)")
+	dial := func(opts ...grpc.DialOption) *storage.Client {
+		creds, err := cmnGrpc.NewClientCreds(&cmnGrpc.ClientOptions{
+			CommonName: identity.CommonName,
+			ServerPubKeys: map[signature.PublicKey]bool{
+				serverIdentity.GetTLSSigner().Public(): true,
+			},
+			Certificates: []tls.Certificate{*clientIdentity.GetTLSCertificate()},
+		})
+		require.NoError(err, "NewClientCreds")
+		conn, err := cmnGrpc.Dial(
+			fmt.Sprintf("localhost:%d", cfg.Port),
+			append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, opts...)...,
+		)
+		require.NoError(err, "Dial")
+		t.Cleanup(func() { conn.Close() })
+		return storage.NewClient(conn)
+	}
+	request := &checkpoint.GetCheckpointsRequest{Version: 1, Namespace: testNs}
+
+	// Requests without a token are rejected.
+	_, err = dial().GetCheckpoints(ctx, request)
+	require.Equal(codes.Unauthenticated, status.Code(err), "request without a token should be rejected")
+
+	// Statically configured tokens are accepted.
+	client := dial(grpc.WithPerRPCCredentials(NewTokenCredentials("static-secret")))
+	cps, err := client.GetCheckpoints(ctx, request)
+	require.NoError(err, "GetCheckpoints")
+	require.Empty(cps)
+
+	_, err = client.GetCheckpoints(ctx, &checkpoint.GetCheckpointsRequest{Version: 1})
+	require.Error(err, "GetCheckpoints should fail for an unknown runtime")
+
+	_, err = client.SyncGet(ctx, &storage.GetRequest{})
+	require.Equal(codes.PermissionDenied, status.Code(err), "methods not available to indexers should be denied")
+
+	// Tokens can be added and revoked at runtime.
+	client = dial(grpc.WithPerRPCCredentials(NewTokenCredentials("runtime-secret")))
+	_, err = client.GetCheckpoints(ctx, request)
+	require.Equal(codes.Unauthenticated, status.Code(err), "unknown token should be rejected")
+
+	require.NoError(srv.Authenticator().Add(&api.IndexerToken{Name: "runtime", Secret: "runtime-secret"}), "Add")
+	_, err = client.GetCheckpoints(ctx, request)
+	require.NoError(err, "GetCheckpoints with added token")
+
+	require.NoError(srv.Authenticator().Revoke("runtime"), "Revoke")
+	_, err = client.GetCheckpoints(ctx, request)
+	require.Equal(codes.Unauthenticated, status.Code(err), "revoked token should be rejected")
+}
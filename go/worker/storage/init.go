@@ -32,11 +32,12 @@ func NewLocalBackend(
 	namespace common.Namespace,
 ) (api.LocalBackend, error) {
 	cfg := &api.Config{
-		Backend:      strings.ToLower(config.GlobalConfig.Storage.Backend),
-		DB:           dataDir,
-		Namespace:    namespace,
-		MaxCacheSize: int64(config.ParseSizeInBytes(config.GlobalConfig.Storage.MaxCacheSize)),
-		NoFsync:      true, // Should be safe, storage will be re-applied on crashes.
+		Backend:           strings.ToLower(config.GlobalConfig.Storage.Backend),
+		DB:                dataDir,
+		Namespace:         namespace,
+		MaxCacheSize:      int64(config.ParseSizeInBytes(config.GlobalConfig.Storage.MaxCacheSize)),
+		ApplyMemoryBudget: uint64(config.ParseSizeInBytes(config.GlobalConfig.Storage.ApplyMemoryBudget)),
+		NoFsync:           true, // Should be safe, storage will be re-applied on crashes.
 	}
 
 	cfg.DB = GetLocalBackendDBDir(dataDir, cfg.Backend)
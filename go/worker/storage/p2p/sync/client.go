@@ -36,6 +36,12 @@ type Client interface {
 		request *GetCheckpointChunkRequest,
 		cp *Checkpoint,
 	) (*GetCheckpointChunkResponse, rpc.PeerFeedback, error)
+
+	// Get requests a proof for the subtree rooted at the given position along the path of the
+	// given key.
+	//
+	// The caller is responsible for verifying the returned proof.
+	Get(ctx context.Context, request *GetRequest) (*GetResponse, rpc.PeerFeedback, error)
 }
 
 // Checkpoint contains checkpoint metadata together with peer information.
@@ -78,11 +84,16 @@ func (c *client) GetCheckpoints(ctx context.Context, request *GetCheckpointsRequ
 		peerCps := peerRsp.(*GetCheckpointsResponse).Checkpoints
 
 		for _, cpMeta := range peerCps {
+			// Skip peers that report that they are missing some of the checkpoint chunks.
+			if !cpMeta.IsComplete() {
+				continue
+			}
+
 			h := cpMeta.EncodedHash()
 			cp := cps[h]
 			if cp == nil {
 				cp = &Checkpoint{
-					Metadata: cpMeta,
+					Metadata: cpMeta.WithoutAvailability(),
 				}
 				cps[h] = cp
 				checkpoints = append(checkpoints, cp)
@@ -124,6 +135,17 @@ func (c *client) GetCheckpointChunk(
 	return &rsp, pf, nil
 }
 
+func (c *client) Get(ctx context.Context, request *GetRequest) (*GetResponse, rpc.PeerFeedback, error) {
+	var rsp GetResponse
+	pf, err := c.rcD.CallOne(ctx, c.mgrD.GetBestPeers(), MethodGet, request, &rsp,
+		rpc.WithMaxPeerResponseTime(MaxGetResponseTime),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rsp, pf, nil
+}
+
 // NewClient creates a new storage sync protocol client.
 func NewClient(p2p rpc.P2P, chainContext string, runtimeID common.Namespace) Client {
 	// Use two separate clients and managers for the same protocol. This is to make sure that peers
@@ -12,13 +12,14 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/checkpoint"
+	"github.com/oasisprotocol/oasis-core/go/storage/mkvs/syncer"
 )
 
 // StorageSyncProtocolID is a unique protocol identifier for the storage sync protocol.
 const StorageSyncProtocolID = "storagesync"
 
 // StorageSyncProtocolVersion is the supported version of the storage sync protocol.
-var StorageSyncProtocolVersion = version.Version{Major: 2, Minor: 0, Patch: 0}
+var StorageSyncProtocolVersion = version.Version{Major: 2, Minor: 1, Patch: 0}
 
 // Constants related to the GetDiff method.
 const (
@@ -71,6 +72,21 @@ type GetCheckpointChunkResponse struct {
 	Chunk []byte `json:"chunk,omitempty"`
 }
 
+// Constants related to the Get method.
+const (
+	MethodGet          = "Get"
+	MaxGetResponseTime = 5 * time.Second
+)
+
+// GetRequest is a Get request.
+//
+// The response is a proof of the subtree rooted at the node identified by the request position
+// along the path of the request key.
+type GetRequest = syncer.GetRequest
+
+// GetResponse is a response to a Get request.
+type GetResponse = syncer.ProofResponse
+
 func init() {
 	peermgmt.RegisterNodeHandler(&peermgmt.NodeHandlerBundle{
 		ProtocolsFn: func(n *node.Node, chainContext string) []core.ProtocolID {
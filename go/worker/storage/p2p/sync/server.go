@@ -6,6 +6,7 @@ import (
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/fault"
 	"github.com/oasisprotocol/oasis-core/go/p2p/protocol"
 	"github.com/oasisprotocol/oasis-core/go/p2p/rpc"
 	storage "github.com/oasisprotocol/oasis-core/go/storage/api"
@@ -14,11 +15,17 @@ import (
 
 type service struct {
 	backend storage.Backend
+
+	diffsDisabled bool
 }
 
 func (s *service) HandleRequest(ctx context.Context, method string, body cbor.RawMessage) (any, error) {
 	switch method {
 	case MethodGetDiff:
+		if s.diffsDisabled {
+			return nil, rpc.ErrMethodNotSupported
+		}
+
 		var rq GetDiffRequest
 		if err := cbor.Unmarshal(body, &rq); err != nil {
 			return nil, rpc.ErrBadRequest
@@ -26,6 +33,10 @@ func (s *service) HandleRequest(ctx context.Context, method string, body cbor.Ra
 
 		return s.handleGetDiff(ctx, &rq)
 	case MethodGetCheckpoints:
+		if fault.CheckpointServingPaused() {
+			return nil, fault.ErrInjected
+		}
+
 		var rq GetCheckpointsRequest
 		if err := cbor.Unmarshal(body, &rq); err != nil {
 			return nil, rpc.ErrBadRequest
@@ -33,12 +44,23 @@ func (s *service) HandleRequest(ctx context.Context, method string, body cbor.Ra
 
 		return s.handleGetCheckpoints(ctx, &rq)
 	case MethodGetCheckpointChunk:
+		if fault.CheckpointServingPaused() {
+			return nil, fault.ErrInjected
+		}
+
 		var rq GetCheckpointChunkRequest
 		if err := cbor.Unmarshal(body, &rq); err != nil {
 			return nil, rpc.ErrBadRequest
 		}
 
 		return s.handleGetCheckpointChunk(ctx, &rq)
+	case MethodGet:
+		var rq GetRequest
+		if err := cbor.Unmarshal(body, &rq); err != nil {
+			return nil, rpc.ErrBadRequest
+		}
+
+		return s.handleGet(ctx, &rq)
 	default:
 		return nil, rpc.ErrMethodNotSupported
 	}
@@ -80,9 +102,18 @@ func (s *service) handleGetCheckpoints(ctx context.Context, request *GetCheckpoi
 		return nil, err
 	}
 
-	return &GetCheckpointsResponse{
-		Checkpoints: cps,
-	}, nil
+	// Do not advertise checkpoints that are missing chunks as peers would fail to restore them.
+	// Availability information is stripped as it is not part of the protocol.
+	rsp := GetCheckpointsResponse{
+		Checkpoints: make([]*checkpoint.Metadata, 0, len(cps)),
+	}
+	for _, cp := range cps {
+		if !cp.IsComplete() {
+			continue
+		}
+		rsp.Checkpoints = append(rsp.Checkpoints, cp.WithoutAvailability())
+	}
+	return &rsp, nil
 }
 
 func (s *service) handleGetCheckpointChunk(ctx context.Context, request *GetCheckpointChunkRequest) (*GetCheckpointChunkResponse, error) {
@@ -103,7 +134,14 @@ func (s *service) handleGetCheckpointChunk(ctx context.Context, request *GetChec
 	}, nil
 }
 
+func (s *service) handleGet(ctx context.Context, request *GetRequest) (*GetResponse, error) {
+	return s.backend.SyncGet(ctx, request)
+}
+
 // NewServer creates a new storage sync protocol server.
-func NewServer(chainContext string, runtimeID common.Namespace, backend storage.Backend) rpc.Server {
-	return rpc.NewServer(protocol.NewRuntimeProtocolID(chainContext, runtimeID, StorageSyncProtocolID, StorageSyncProtocolVersion), &service{backend})
+//
+// When diffsDisabled is set, the server will not serve storage diffs and will only serve
+// checkpoints and state queries.
+func NewServer(chainContext string, runtimeID common.Namespace, backend storage.Backend, diffsDisabled bool) rpc.Server {
+	return rpc.NewServer(protocol.NewRuntimeProtocolID(chainContext, runtimeID, StorageSyncProtocolID, StorageSyncProtocolVersion), &service{backend, diffsDisabled})
 }
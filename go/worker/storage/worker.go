@@ -8,11 +8,13 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/node"
 	"github.com/oasisprotocol/oasis-core/go/config"
+	storageAPI "github.com/oasisprotocol/oasis-core/go/storage/api"
 	workerCommon "github.com/oasisprotocol/oasis-core/go/worker/common"
 	committeeCommon "github.com/oasisprotocol/oasis-core/go/worker/common/committee"
 	"github.com/oasisprotocol/oasis-core/go/worker/registration"
 	storageWorkerAPI "github.com/oasisprotocol/oasis-core/go/worker/storage/api"
 	"github.com/oasisprotocol/oasis-core/go/worker/storage/committee"
+	"github.com/oasisprotocol/oasis-core/go/worker/storage/indexer"
 )
 
 // Worker is a worker handling storage operations.
@@ -27,6 +29,8 @@ type Worker struct {
 	quitCh chan struct{}
 
 	runtimes map[common.Namespace]*committee.Node
+
+	indexer *indexer.Server
 }
 
 // New constructs a new storage worker.
@@ -61,9 +65,25 @@ func New(
 	// Attach the storage worker's internal GRPC interface.
 	storageWorkerAPI.RegisterService(grpcInternal.Server(), s)
 
+	// Serve historical storage to indexers if configured.
+	if cfg := config.GlobalConfig.Storage.Indexer; cfg.Port != 0 {
+		var err error
+		if s.indexer, err = indexer.New(&cfg, commonWorker.Identity, s.lookupStorage); err != nil {
+			return nil, fmt.Errorf("failed to create indexer gRPC server: %w", err)
+		}
+	}
+
 	return s, nil
 }
 
+func (w *Worker) lookupStorage(runtimeID common.Namespace) (storageAPI.Backend, error) {
+	node, ok := w.runtimes[runtimeID]
+	if !ok {
+		return nil, storageWorkerAPI.ErrRuntimeNotFound
+	}
+	return node.GetLocalStorage(), nil
+}
+
 func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	id := commonNode.Runtime.ID()
 	w.logger.Info("registering new runtime",
@@ -98,6 +118,7 @@ func (w *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 		localStorage,
 		&committee.CheckpointSyncConfig{
 			Disabled:          config.GlobalConfig.Storage.CheckpointSyncDisabled,
+			Required:          config.GlobalConfig.Storage.InitialDiffSyncDisabled,
 			ChunkFetcherCount: config.GlobalConfig.Storage.FetcherCount,
 		},
 	)
@@ -142,13 +163,23 @@ func (w *Worker) Start() error {
 		return nil
 	}
 
-	// Wait for all runtimes to terminate.
+	// Start serving indexers.
+	if w.indexer != nil {
+		if err := w.indexer.Start(); err != nil {
+			return fmt.Errorf("failed to start indexer gRPC server: %w", err)
+		}
+	}
+
+	// Wait for all runtimes and the indexer gRPC server to terminate.
 	go func() {
 		defer close(w.quitCh)
 
 		for _, r := range w.runtimes {
 			<-r.Quit()
 		}
+		if w.indexer != nil {
+			<-w.indexer.Quit()
+		}
 	}()
 
 	// Start all runtimes and wait for initialization.
@@ -182,6 +213,9 @@ func (w *Worker) Stop() {
 	for _, r := range w.runtimes {
 		r.Stop()
 	}
+	if w.indexer != nil {
+		w.indexer.Stop()
+	}
 }
 
 // Quit returns a channel that will be closed when the service terminates.
@@ -191,6 +225,9 @@ func (w *Worker) Quit() <-chan struct{} {
 
 // Cleanup performs the service specific post-termination cleanup.
 func (w *Worker) Cleanup() {
+	if w.indexer != nil {
+		w.indexer.Cleanup()
+	}
 }
 
 // GetRuntime returns a storage committee node for the given runtime (if available).
@@ -199,3 +236,8 @@ func (w *Worker) Cleanup() {
 func (w *Worker) GetRuntime(id common.Namespace) *committee.Node {
 	return w.runtimes[id]
 }
+
+// Indexer returns the indexer gRPC server or nil in case indexer access is not enabled.
+func (w *Worker) Indexer() *indexer.Server {
+	return w.indexer
+}
@@ -68,8 +68,10 @@ pub struct TLSInfo {
     /// Public key used for establishing TLS connections.
     pub pub_key: signature::PublicKey,
 
-    #[cbor(rename = "next_pub_key", optional)]
-    pub _deprecated_next_pub_key: Option<signature::PublicKey>,
+    /// Public key that will be used for establishing TLS connections once the node's pending TLS
+    /// key rotation completes. Only set during the rotation overlap window.
+    #[cbor(optional)]
+    pub next_pub_key: Option<signature::PublicKey>,
 
     #[cbor(rename = "addresses", optional)]
     pub _deprecated_addresses: Option<Vec<TLSAddress>>,
@@ -83,6 +85,11 @@ pub struct P2PInfo {
 
     /// List of addresses at which the node can be reached.
     pub addresses: Option<Vec<TCPAddress>>,
+
+    /// Priorities of the addresses at the same index, where addresses with lower values are
+    /// preferred. If empty, all addresses have the same priority.
+    #[cbor(optional)]
+    pub address_priorities: Option<Vec<u8>>,
 }
 
 /// Represents a consensus address that includes an ID and a TCP address.
@@ -96,6 +103,10 @@ pub struct ConsensusAddress {
 
     /// Address at which the node can be reached.
     pub address: TCPAddress,
+
+    /// Priority of the address, where addresses with lower values are preferred.
+    #[cbor(optional)]
+    pub priority: u8,
 }
 
 /// Node's consensus member information.
@@ -478,6 +489,36 @@ pub struct ExecutorParameters {
     /// node. Zero means unlimited.
     #[cbor(optional)]
     pub max_liveness_fails: u8,
+    /// Maximum number of rounds that can be in flight at the same time. Values greater than one
+    /// enable speculative execution of the next round while the current round is finalizing.
+    #[cbor(optional)]
+    pub max_in_flight_rounds: u8,
+    /// Percentage of primary workers that must submit commitments matching the scheduler's
+    /// commitment for the round to be finalized without waiting for the remaining workers.
+    /// Zero means that all primary workers, except for the allowed stragglers, must commit.
+    #[cbor(optional)]
+    pub finalization_quorum_percent: u8,
+    /// Policy used to weigh candidates when electing the executor committee.
+    #[cbor(optional)]
+    pub election_policy: ElectionPolicy,
+    /// Maximum weight of a single candidate as a percentage of the total capped candidate weight
+    /// in case the capped-weight election policy is used.
+    #[cbor(optional)]
+    pub election_max_weight_percent: u8,
+}
+
+/// Committee election policy.
+#[derive(Clone, Debug, Default, PartialEq, Eq, Hash, cbor::Encode, cbor::Decode)]
+#[repr(u8)]
+pub enum ElectionPolicy {
+    /// Candidates are elected uniformly at random regardless of their stake.
+    #[default]
+    Uniform = 0,
+    /// Candidates are elected with probability proportional to their entity's stake.
+    StakeWeighted = 1,
+    /// Candidates are elected with probability proportional to their entity's stake, with the
+    /// weight of each candidate capped at a maximum fraction of the total weight.
+    CappedWeight = 2,
 }
 
 /// Parameters for the runtime transaction scheduler.
@@ -664,6 +705,9 @@ pub struct VersionInfo {
     /// The SHA256 hash of the runtime bundle (optional).
     #[cbor(optional)]
     pub bundle_checksum: Vec<u8>,
+    /// Node-side feature flags of the deployment (optional).
+    #[cbor(optional)]
+    pub features: BTreeMap<String, String>,
 }
 
 impl VersionInfo {
@@ -895,6 +939,10 @@ pub struct Runtime {
     pub staking: RuntimeStakingParameters,
     /// Runtime governance model.
     pub governance_model: RuntimeGovernanceModel,
+    /// Minimum oasis-node software version that nodes must be running in order to register for
+    /// the runtime. If not set, there is no constraint.
+    #[cbor(optional)]
+    pub min_node_software_version: Option<Version>,
 }
 
 fn staking_params_are_empty(p: &RuntimeStakingParameters) -> bool {
@@ -1035,6 +1083,7 @@ mod tests {
                         valid_from: 0,
                         tee: b"version tee".to_vec(),
                         bundle_checksum: vec![0x1; 32],
+                        ..Default::default()
                     }],
                     key_manager: Some(Namespace::from(
                         "8000000000000000000000000000000000000000000000000000000000000001",
@@ -1049,6 +1098,10 @@ mod tests {
                         max_missed_proposals_percent: 3,
                         min_live_rounds_eval: 2,
                         max_liveness_fails: 1,
+                        max_in_flight_rounds: 0,
+                        finalization_quorum_percent: 0,
+                        election_policy: ElectionPolicy::Uniform,
+                        election_max_weight_percent: 0,
                     },
                     txn_scheduler: TxnSchedulerParameters {
                         batch_flush_timeout: 1_000_000_000, // 1 second.
@@ -1100,6 +1153,7 @@ mod tests {
                         min_in_message_fee: Quantity::from(0u32),
                     },
                     governance_model: RuntimeGovernanceModel::GovernanceConsensus,
+                    min_node_software_version: None,
                 },
             ),
         ];
@@ -1210,7 +1264,7 @@ mod tests {
                     v: 2,
                     tls: TLSInfo{
                         pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff2"),
-                        _deprecated_next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
+                        next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
                         _deprecated_addresses: Some(vec![]),
                     },
                     ..Default::default()
@@ -1222,7 +1276,7 @@ mod tests {
                     v: 2,
                     tls: TLSInfo{
                         pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff2"),
-                        _deprecated_next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
+                        next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
                         _deprecated_addresses: Some(vec![
                             TLSAddress{
                                 pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff4"),
@@ -1251,7 +1305,7 @@ mod tests {
                     expiration: 32,
                     tls: TLSInfo{
                         pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff2"),
-                        _deprecated_next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
+                        next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
                         _deprecated_addresses: Some(vec![TLSAddress{
                                 pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff4"),
                                 address: TCPAddress { ip: Ipv4Addr::new(127, 0, 0, 1).to_ipv6_mapped().octets().to_vec(), port: 123, ..Default::default() }
@@ -1300,7 +1354,7 @@ mod tests {
                     expiration: 32,
                     tls: TLSInfo{
                         pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff2"),
-                        _deprecated_next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
+                        next_pub_key: Some(signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3")),
                         _deprecated_addresses: Some(vec![TLSAddress{
                                 pub_key: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff4"),
                                 address: TCPAddress { ip: Ipv4Addr::new(127, 0, 0, 1).to_ipv6_mapped().octets().to_vec(), port: 123, ..Default::default() }
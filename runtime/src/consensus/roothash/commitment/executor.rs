@@ -65,6 +65,28 @@ pub struct ComputeResultsHeader {
     /// The number of processed incoming messages.
     #[cbor(optional)]
     pub in_msgs_count: u32,
+
+    /// Optional summary of resources consumed by the batch.
+    #[cbor(optional)]
+    pub batch_summary: Option<BatchSummary>,
+}
+
+/// Summary of resources consumed by processing a batch.
+///
+/// # Note
+///
+/// This should be kept in sync with go/roothash/api/commitment/executor.go.
+#[derive(Clone, Debug, Default, PartialEq, Eq, Hash, cbor::Encode, cbor::Decode)]
+pub struct BatchSummary {
+    /// Total amount of gas used by all transactions in the batch.
+    #[cbor(optional)]
+    pub gas_used: u64,
+    /// Total size of all transactions in the batch (in bytes).
+    #[cbor(optional)]
+    pub batch_size: u64,
+    /// Number of transactions in the batch.
+    #[cbor(optional)]
+    pub tx_count: u32,
 }
 
 impl ComputeResultsHeader {
@@ -234,6 +256,9 @@ impl ExecutorCommitment {
                         "failure indicating commitment includes InMessagesHash/Count"
                     ));
                 }
+                if self.header.header.batch_summary.is_some() {
+                    return Err(anyhow!("failure indicating commitment includes BatchSummary"));
+                }
                 // In case of failure indicating commitment make sure RAK signature is empty.
                 if self.header.rak_signature.is_some() {
                     return Err(anyhow!("failure indicating body includes RAK signature"));
@@ -288,11 +313,25 @@ mod tests {
             messages_hash: Some(Hash::empty_hash()),
             in_msgs_hash: Some(Hash::empty_hash()),
             in_msgs_count: 0,
+            batch_summary: None,
         };
         assert_eq!(
             populated.encoded_hash(),
             Hash::from("8459a9e6e3341cd2df5ada5737469a505baf92397aaa88b7100915324506d843")
         );
+
+        let summary = ComputeResultsHeader {
+            batch_summary: Some(BatchSummary {
+                gas_used: 1000,
+                batch_size: 256,
+                tx_count: 2,
+            }),
+            ..populated
+        };
+        assert_eq!(
+            summary.encoded_hash(),
+            Hash::from("0d26af3eab5d94755b150fd90aba95029f3aee873dc9c6db514b3c450fb93458")
+        );
     }
 
     #[test]
@@ -314,6 +353,7 @@ mod tests {
                     messages_hash: Some(Hash::empty_hash()),
                     in_msgs_hash: Some(Hash::empty_hash()),
                     in_msgs_count: 0,
+                    batch_summary: None,
                 },
                 failure: ExecutorCommitmentFailure::FailureNone,
                 rak_signature: None,
@@ -390,6 +430,29 @@ mod tests {
                 },
                 true,
             ),
+            (
+                "Bad Failure (existing batch_summary)",
+                |ec: &mut ExecutorCommitment| {
+                    ec.header.failure = ExecutorCommitmentFailure::FailureUnknown;
+                    ec.header.header.io_root = None;
+                    ec.header.header.state_root = None;
+                    ec.header.header.messages_hash = None;
+                    ec.header.header.in_msgs_hash = None;
+                    ec.header.header.batch_summary = Some(BatchSummary::default());
+                },
+                true,
+            ),
+            (
+                "Ok batch_summary",
+                |ec: &mut ExecutorCommitment| {
+                    ec.header.header.batch_summary = Some(BatchSummary {
+                        gas_used: 1000,
+                        batch_size: 256,
+                        tx_count: 2,
+                    });
+                },
+                false,
+            ),
             (
                 "Ok Failure",
                 |ec: &mut ExecutorCommitment| {
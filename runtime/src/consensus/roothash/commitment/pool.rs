@@ -633,6 +633,7 @@ mod tests {
                     messages_hash: Some(msgs_hash),
                     in_msgs_hash: Some(in_msgs_hash),
                     in_msgs_count: 0,
+                    batch_summary: None,
                 },
                 failure: ExecutorCommitmentFailure::FailureNone,
                 rak_signature: None,
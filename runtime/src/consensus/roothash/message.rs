@@ -263,6 +263,7 @@ mod tests {
                 ..Default::default()
             },
             governance_model: registry::RuntimeGovernanceModel::GovernanceEntity,
+            min_node_software_version: None,
         };
 
         // NOTE: These hashes MUST be synced with go/roothash/api/message/message_test.go.
@@ -171,6 +171,15 @@ pub struct LivenessStatistics {
     /// The list is ordered according to the committee arrangement (i.e., the counter at index i
     /// holds the value for the node at index i in the committee).
     pub missed_proposals: Vec<u64>,
+
+    /// A list that records the number of rounds in which a node submitted a correct commitment
+    /// only after the round has already been finalized, within the late commitment window.
+    ///
+    /// The list is ordered according to the committee arrangement (i.e., the counter at index i
+    /// holds the value for the node at index i in the committee). It is empty in case no late
+    /// rounds have been recorded.
+    #[cbor(optional)]
+    pub late_rounds: Vec<u64>,
 }
 
 /// Information about how a particular round was executed by the consensus layer.
@@ -188,6 +197,10 @@ pub struct RoundResults {
     /// by causing discrepancies.
     #[cbor(optional)]
     pub bad_compute_entities: Vec<PublicKey>,
+
+    /// Summary of resources consumed by the round's batch, if it was provided by the runtime.
+    #[cbor(optional)]
+    pub batch_summary: Option<BatchSummary>,
 }
 
 /// Per-round state and I/O roots that are stored in consensus state.
@@ -232,6 +245,16 @@ mod tests {
                     bad_compute_entities: vec![
                         "0000000000000000000000000000000000000000000000000000000000000001".into(),
                     ],
+                    ..Default::default()
+                }),
+            ("oW1iYXRjaF9zdW1tYXJ5o2hnYXNfdXNlZBkD6Gh0eF9jb3VudAJqYmF0Y2hfc2l6ZRkBAA==",
+                RoundResults {
+                    batch_summary: Some(BatchSummary {
+                        gas_used: 1000,
+                        batch_size: 256,
+                        tx_count: 2,
+                    }),
+                    ..Default::default()
                 }),
         ];
         for (encoded_base64, rr) in tcs {
@@ -272,6 +272,9 @@ pub enum EscrowEvent {
         escrow: Address,
         amount: Quantity,
         new_shares: Quantity,
+        /// Beneficiary that escrowed the stake from the owner's allowance.
+        #[cbor(optional)]
+        beneficiary: Option<Address>,
     },
 
     /// Event emitted when stake is taken from an escrow account (i.e. stake is slashed).
@@ -297,6 +300,9 @@ pub enum EscrowEvent {
         active_shares: Quantity,
         debonding_shares: Quantity,
         debond_end_time: EpochTime,
+        /// Beneficiary that reclaimed the stake it escrowed from the owner's allowance.
+        #[cbor(optional)]
+        beneficiary: Option<Address>,
     },
 
     /// Event emitted when stake is reclaimed from an escrow account back into owner's general
@@ -319,6 +325,10 @@ pub struct AllowanceChangeEvent {
     #[cbor(optional)]
     pub negative: bool,
     pub amount_change: Quantity,
+    /// Allowance before the change. Only present for events emitted after the allowance history
+    /// feature has been enabled.
+    #[cbor(optional)]
+    pub old_allowance: Option<Quantity>,
 }
 
 #[cfg(test)]
@@ -637,6 +647,7 @@ mod tests {
                         escrow: addr2.clone(),
                         amount: 100u32.into(),
                         new_shares: 50u32.into(),
+                        beneficiary: None,
                     }),
                     ..Default::default()
                 },
@@ -666,6 +677,7 @@ mod tests {
                         active_shares: 50u32.into(),
                         debonding_shares: 25u32.into(),
                         debond_end_time: 42,
+                        beneficiary: None,
                     }),
                     ..Default::default()
                 },
@@ -697,6 +709,7 @@ mod tests {
                         allowance: 100u32.into(),
                         negative: false,
                         amount_change: 50u32.into(),
+                        old_allowance: None,
                     }),
                     ..Default::default()
                 },
@@ -712,6 +725,23 @@ mod tests {
                         allowance: 100u32.into(),
                         negative: true,
                         amount_change: 50u32.into(),
+                        old_allowance: None,
+                    }),
+                    ..Default::default()
+                },
+            ),
+            (
+                "o2ZoZWlnaHQYKmd0eF9oYXNoWCDGcrjR71btKKuHw2IsURQGm90617j5c3SY0MAezvCWenBhbGxvd2FuY2VfY2hhbmdlpWVvd25lclUAIHIUNIk/YWwJgUjiz5+Z4+KCbhNpYWxsb3dhbmNlQWRrYmVuZWZpY2lhcnlVALkSOXiV5kcMUfq+zJ3cg/cK7YahbWFtb3VudF9jaGFuZ2VBMm1vbGRfYWxsb3dhbmNlQTI=",
+                Event {
+                    height: 42,
+                    tx_hash,
+                    allowance_change: Some(AllowanceChangeEvent {
+                        owner: addr1.clone(),
+                        beneficiary: addr2.clone(),
+                        allowance: 100u32.into(),
+                        negative: false,
+                        amount_change: 50u32.into(),
+                        old_allowance: Some(50u32.into()),
                     }),
                     ..Default::default()
                 },
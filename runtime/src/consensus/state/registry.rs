@@ -169,6 +169,7 @@ mod test {
                     p2p: P2PInfo{
                         id: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff3"),
                         addresses: Some(Vec::new()),
+                        ..Default::default()
                     },
                     consensus: ConsensusInfo{
                         id: signature::PublicKey::from("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff4"),
@@ -263,6 +264,7 @@ mod test {
                         valid_from: 42,
                         tee: vec![1, 2, 3, 4, 5],
                         bundle_checksum: vec![0x5; 32],
+                        ..Default::default()
                     },
                     VersionInfo {
                         version: Version::from(120),
@@ -299,6 +301,7 @@ mod test {
                     valid_from: 42,
                     tee: vec![1, 2, 3, 4, 5],
                     bundle_checksum: vec![0x5; 32],
+                    ..Default::default()
                 }],
                 ..Default::default()
             },
@@ -3,6 +3,7 @@ use std::{
     convert::TryInto,
     sync::{Arc, Condvar, Mutex},
     thread,
+    time::{Duration, Instant},
 };
 
 use anyhow::Result as AnyResult;
@@ -118,6 +119,9 @@ struct TxDispatchState {
     round_results: roothash::RoundResults,
     max_messages: u32,
     check_only: bool,
+    speculative: bool,
+    batch_summary: bool,
+    execution_deadline: Option<Instant>,
 }
 
 /// State provided by the protocol upon successful initialization.
@@ -349,7 +353,13 @@ impl Dispatcher {
                 block,
                 epoch,
                 max_messages,
+                speculative,
+                batch_summary,
+                execution_deadline,
             } => {
+                let execution_deadline = (execution_deadline > 0)
+                    .then(|| Instant::now() + Duration::from_nanos(execution_deadline));
+
                 // Transaction execution.
                 self.dispatch_txn(
                     state.cache_set,
@@ -367,6 +377,9 @@ impl Dispatcher {
                         round_results,
                         max_messages,
                         check_only: false,
+                        speculative,
+                        batch_summary,
+                        execution_deadline,
                     },
                 )
                 .await
@@ -395,6 +408,9 @@ impl Dispatcher {
                         round_results: Default::default(),
                         max_messages,
                         check_only: true,
+                        speculative: false,
+                        batch_summary: false,
+                        execution_deadline: None,
                     },
                 )
                 .await
@@ -423,6 +439,9 @@ impl Dispatcher {
                         round_results: Default::default(),
                         max_messages,
                         check_only: true,
+                        speculative: false,
+                        batch_summary: false,
+                        execution_deadline: None,
                     },
                 )
                 .await
@@ -613,11 +632,29 @@ impl Dispatcher {
     ) -> Result<Body, Error> {
         // Verify consensus state and runtime state root integrity before execution.
         // TODO: Make this async.
-        let consensus_state = block_on(state.consensus_verifier.verify(
-            state.consensus_block.clone(),
-            state.header.clone(),
-            state.epoch,
-        ))?;
+        let consensus_state = if state.speculative {
+            // Speculative execution is based on a runtime header that has not yet been finalized,
+            // so it cannot be verified against the consensus layer. This is only acceptable when
+            // the host is trusted anyway.
+            if cfg!(target_env = "sgx") {
+                return Err(Error::new(
+                    "rhp/dispatcher",
+                    1,
+                    "speculative execution not supported",
+                ));
+            }
+            block_on(
+                state
+                    .consensus_verifier
+                    .unverified_state(state.consensus_block.clone()),
+            )?
+        } else {
+            block_on(state.consensus_verifier.verify(
+                state.consensus_block.clone(),
+                state.header.clone(),
+                state.epoch,
+            ))?
+        };
         // Ensure the runtime is still ready to process requests.
         protocol.ensure_initialized()?;
 
@@ -631,7 +668,7 @@ impl Dispatcher {
         });
         let mut overlay = OverlayTree::new(cache.tree_mut());
 
-        let txn_ctx = TxnContext::new(
+        let mut txn_ctx = TxnContext::new(
             protocol,
             &state.consensus_block,
             consensus_state,
@@ -642,6 +679,7 @@ impl Dispatcher {
             state.max_messages,
             state.check_only,
         );
+        txn_ctx.deadline = state.execution_deadline;
 
         // Perform execution based on the passed mode.
         let mut results = match state.mode {
@@ -676,8 +714,10 @@ impl Dispatcher {
             },
         );
         let mut hashes = Vec::new();
+        let mut batch_size: u64 = 0;
         for (batch_order, input) in inputs.drain(..).enumerate() {
             hashes.push(Hash::digest_bytes(&input));
+            batch_size += input.len() as u64;
             txn_tree
                 .add_input(input, batch_order.try_into().unwrap())
                 .expect("add transaction must succeed");
@@ -704,6 +744,17 @@ impl Dispatcher {
 
         let (io_write_log, io_root) = txn_tree.commit().expect("io commit must succeed");
 
+        // Only report execution times when the runtime reported them for all transactions.
+        let tx_execution_times = if results.tx_execution_times.len() == hashes.len() {
+            results
+                .tx_execution_times
+                .iter()
+                .map(|d| d.as_nanos().try_into().unwrap_or(u64::MAX))
+                .collect()
+        } else {
+            Vec::new()
+        };
+
         let header = ComputeResultsHeader {
             round: header.round + 1,
             previous_hash: header.encoded_hash(),
@@ -714,6 +765,11 @@ impl Dispatcher {
                 &in_msgs[..results.in_msgs_count],
             )),
             in_msgs_count: results.in_msgs_count.try_into().unwrap(),
+            batch_summary: state.batch_summary.then(|| roothash::BatchSummary {
+                gas_used: results.gas_used,
+                batch_size,
+                tx_count: hashes.len().try_into().unwrap(),
+            }),
         };
 
         debug!(self.logger, "Transaction batch execution complete";
@@ -722,6 +778,7 @@ impl Dispatcher {
             "state_root" => ?header.state_root,
             "messages_hash" => ?header.messages_hash,
             "in_msgs_hash" => ?header.in_msgs_hash,
+            "batch_summary" => ?header.batch_summary,
         );
 
         let rak_sig = self
@@ -744,6 +801,7 @@ impl Dispatcher {
             tx_reject_hashes: results.tx_reject_hashes,
             tx_input_root: input_io_root,
             tx_input_write_log: input_write_log,
+            tx_execution_times,
         })
     }
 
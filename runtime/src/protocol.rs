@@ -406,8 +406,10 @@ impl Protocol {
             ))),
             Body::RuntimePingRequest {} => Ok(Some(Body::Empty {})),
             Body::RuntimeShutdownRequest {} => {
+                // The host is shutting down the connection, but it keeps servicing in-flight
+                // requests until they complete, so there is nothing to do but acknowledge.
                 info!(self.logger, "Received worker shutdown request");
-                Err(ProtocolError::MethodNotSupported.into())
+                Ok(Some(Body::Empty {}))
             }
             Body::RuntimeAbortRequest {} => {
                 info!(self.logger, "Received worker abort request");
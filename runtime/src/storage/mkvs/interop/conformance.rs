@@ -0,0 +1,186 @@
+//! MKVS conformance test vectors.
+//!
+//! The test vectors are generated by the Go implementation, see
+//! go/storage/mkvs/interop/conformance.
+use std::{any::Any, fs, path::Path};
+
+use anyhow::{anyhow, Result};
+
+use crate::{
+    common::{crypto::hash::Hash, namespace::Namespace},
+    storage::mkvs::{sync::*, tree::*},
+};
+
+/// Location of the conformance test vectors directory (from Go).
+const TEST_VECTORS_DIR: &str = "../go/storage/mkvs/interop/conformance/testdata";
+
+/// Tree insert operation name.
+const OP_INSERT: &str = "Insert";
+/// Tree remove operation name.
+const OP_REMOVE: &str = "Remove";
+
+/// Tree operation.
+#[derive(Clone, Debug, Default, cbor::Decode)]
+pub struct Op {
+    /// Operation name.
+    pub op: String,
+    /// Key that is inserted or removed.
+    pub key: Vec<u8>,
+    /// Value that is inserted. An absent value is the same as an empty value.
+    #[cbor(optional)]
+    pub value: Vec<u8>,
+}
+
+/// Conformance test vector.
+#[derive(Clone, Debug, Default, cbor::Decode)]
+pub struct Vector {
+    /// Name of the script the vector was generated from.
+    pub name: String,
+    /// Proof version of all expected proofs.
+    pub proof_version: u16,
+    /// Test vector steps.
+    pub steps: Vec<Step>,
+}
+
+/// Conformance test vector step.
+#[derive(Clone, Debug, Default, cbor::Decode)]
+pub struct Step {
+    /// Operations that are applied to the tree before it is committed.
+    pub ops: Vec<Op>,
+    /// Expected root hash after the tree is committed.
+    pub root: Hash,
+    /// Expected proofs against the committed root.
+    #[cbor(optional)]
+    pub proofs: Vec<ExpectedProof>,
+}
+
+/// Expected proof for a key.
+#[derive(Clone, Debug, Default, cbor::Decode)]
+pub struct ExpectedProof {
+    /// Looked up key.
+    pub key: Vec<u8>,
+    /// Whether the key exists in the tree.
+    pub exists: bool,
+    /// Expected value of the key.
+    #[cbor(optional)]
+    pub value: Vec<u8>,
+    /// Expected proof for the lookup of the key.
+    pub proof: Proof,
+}
+
+/// A read syncer that always returns the given proof.
+struct FixedProofReadSyncer {
+    proof: Proof,
+}
+
+impl ReadSync for FixedProofReadSyncer {
+    fn as_any(&self) -> &dyn Any {
+        self
+    }
+
+    fn sync_get(&mut self, _request: GetRequest) -> Result<ProofResponse> {
+        Ok(ProofResponse {
+            proof: self.proof.clone(),
+        })
+    }
+
+    fn sync_get_prefixes(&mut self, _request: GetPrefixesRequest) -> Result<ProofResponse> {
+        Err(SyncerError::Unsupported.into())
+    }
+
+    fn sync_iterate(&mut self, _request: IterateRequest) -> Result<ProofResponse> {
+        Err(SyncerError::Unsupported.into())
+    }
+}
+
+/// Verify the MKVS implementation against the given conformance test vector.
+///
+/// The vector's proofs must use a proof version supported by the proof builder.
+pub fn check(vector: &Vector) -> Result<()> {
+    let mut tree = Tree::builder()
+        .with_root_type(RootType::State)
+        .build(Box::new(NoopReadSyncer));
+
+    for (idx, step) in vector.steps.iter().enumerate() {
+        for op in &step.ops {
+            match op.op.as_str() {
+                OP_INSERT => {
+                    tree.insert(&op.key, &op.value)?;
+                }
+                OP_REMOVE => {
+                    tree.remove(&op.key)?;
+                }
+                _ => return Err(anyhow!("step {}: unsupported operation: {}", idx, op.op)),
+            }
+        }
+
+        let version = idx as u64;
+        let root_hash = tree.commit(Namespace::default(), version)?;
+        if root_hash != step.root {
+            return Err(anyhow!(
+                "step {}: root hash mismatch (expected: {:?} got: {:?})",
+                idx,
+                step.root,
+                root_hash,
+            ));
+        }
+
+        for expected in &step.proofs {
+            // Make sure that the expected proof is valid and proves the expected value.
+            let remote_tree = Tree::builder()
+                .with_capacity(0, 0)
+                .with_root(Root {
+                    root_type: RootType::State,
+                    version,
+                    hash: root_hash,
+                    ..Default::default()
+                })
+                .build(Box::new(FixedProofReadSyncer {
+                    proof: expected.proof.clone(),
+                }));
+            let value = remote_tree.get(&expected.key)?;
+            let expected_value = expected.exists.then(|| expected.value.clone());
+            if value != expected_value {
+                return Err(anyhow!(
+                    "step {}: key {:?}: proof value mismatch",
+                    idx,
+                    expected.key,
+                ));
+            }
+
+            // Proofs are only generated for existing keys.
+            if !expected.exists {
+                continue;
+            }
+            let proof = tree
+                .get_proof(&expected.key)?
+                .ok_or_else(|| anyhow!("step {}: key {:?}: missing proof", idx, expected.key))?;
+            if proof != expected.proof {
+                return Err(anyhow!(
+                    "step {}: key {:?}: proof mismatch",
+                    idx,
+                    expected.key,
+                ));
+            }
+        }
+    }
+
+    Ok(())
+}
+
+#[test]
+fn test_conformance_vectors() {
+    let mut checked = 0;
+    for entry in fs::read_dir(Path::new(TEST_VECTORS_DIR)).expect("failed to read test vectors") {
+        let path = entry.expect("failed to read test vector").path();
+        if path.extension().map_or(true, |ext| ext != "cbor") {
+            continue;
+        }
+
+        let data = fs::read(&path).expect("failed to read test vector");
+        let vector: Vector = cbor::from_slice(&data).expect("failed to decode test vector");
+        check(&vector).unwrap_or_else(|err| panic!("{}: {}", vector.name, err));
+        checked += 1;
+    }
+    assert!(checked > 0, "there should be at least one test vector");
+}
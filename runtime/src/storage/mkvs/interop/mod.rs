@@ -4,6 +4,7 @@ use crate::{
     storage::mkvs::WriteLog,
 };
 
+pub mod conformance;
 mod protocol_server;
 mod rpc;
 
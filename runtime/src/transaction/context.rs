@@ -1,5 +1,5 @@
 //! Runtime call context.
-use std::sync::Arc;
+use std::{sync::Arc, time::Instant};
 
 use crate::{
     consensus::{
@@ -33,6 +33,9 @@ pub struct Context<'a> {
     /// Flag indicating whether to only perform transaction check rather than
     /// running the transaction.
     pub check_only: bool,
+    /// Deadline for executing the batch, if any. When scheduling, runtimes should stop including
+    /// further transactions once the deadline has passed.
+    pub deadline: Option<Instant>,
 }
 
 impl<'a> Context<'a> {
@@ -59,6 +62,7 @@ impl<'a> Context<'a> {
             round_results,
             max_messages,
             check_only,
+            deadline: None,
         }
     }
 }
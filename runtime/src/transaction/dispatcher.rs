@@ -1,5 +1,8 @@
 //! Runtime transaction batch dispatcher.
-use std::sync::{atomic::AtomicBool, Arc};
+use std::{
+    sync::{atomic::AtomicBool, Arc},
+    time::Duration,
+};
 
 use super::{context::Context, tags::Tags, types::TxnBatch};
 use crate::{
@@ -195,12 +198,20 @@ pub struct ExecuteBatchResult {
     pub messages: Vec<roothash::Message>,
     /// Number of processed incoming messages.
     pub in_msgs_count: usize,
+    /// Total amount of gas used by all transactions in the batch.
+    ///
+    /// Runtimes that do not account for gas should leave this at zero.
+    pub gas_used: u64,
     /// Block emitted tags (not emitted by a specific transaction).
     pub block_tags: Tags,
     /// Hashes of transactions to reject.
     ///
     /// Note that these are only taken into account in schedule execution mode.
     pub tx_reject_hashes: Vec<Hash>,
+    /// Per-transaction execution times, in the same order as results.
+    ///
+    /// Runtimes that do not measure execution times should leave this empty.
+    pub tx_execution_times: Vec<Duration>,
 }
 
 /// No-op dispatcher.
@@ -226,7 +237,9 @@ impl Dispatcher for NoopDispatcher {
             messages: Vec::new(),
             block_tags: Tags::new(),
             in_msgs_count: in_msgs.len(),
+            gas_used: 0,
             tx_reject_hashes: Vec::new(),
+            tx_execution_times: Vec::new(),
         })
     }
 
@@ -241,7 +254,9 @@ impl Dispatcher for NoopDispatcher {
             messages: Vec::new(),
             block_tags: Tags::new(),
             in_msgs_count: in_msgs.len(),
+            gas_used: 0,
             tx_reject_hashes: Vec::new(),
+            tx_execution_times: Vec::new(),
         })
     }
 
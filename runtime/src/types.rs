@@ -184,6 +184,14 @@ pub enum Body {
         block: Block,
         epoch: EpochTime,
         max_messages: u32,
+        #[cbor(optional)]
+        speculative: bool,
+        #[cbor(optional)]
+        batch_summary: bool,
+        /// Time budget for executing the batch in nanoseconds, measured from the time the request
+        /// is received. Zero means that there is no deadline.
+        #[cbor(optional)]
+        execution_deadline: u64,
     },
     RuntimeExecuteTxBatchResponse {
         batch: ComputedBatch,
@@ -192,6 +200,10 @@ pub enum Body {
         tx_reject_hashes: Vec<Hash>,
         tx_input_root: Hash,
         tx_input_write_log: WriteLog,
+        /// Execution times of the included transactions in nanoseconds, in the same order as
+        /// `tx_hashes`.
+        #[cbor(optional)]
+        tx_execution_times: Vec<u64>,
     },
     RuntimeKeyManagerStatusUpdateRequest {
         status: KeyManagerStatus,
@@ -385,6 +397,17 @@ pub struct Features {
     /// A feature specifying that the runtime supports endorsed TEE capabilities.
     #[cbor(optional)]
     pub endorsed_capability_tee: bool,
+    /// A feature specifying that the runtime supports including a summary of consumed resources
+    /// in compute results headers.
+    #[cbor(optional)]
+    pub batch_summary: bool,
+    /// A feature specifying that the runtime supports respecting a batch execution deadline and
+    /// reporting per-transaction execution times.
+    #[cbor(optional)]
+    pub tx_execution_times: bool,
+    /// A feature specifying that the runtime uses the host-local storage.
+    #[cbor(optional)]
+    pub local_storage: bool,
 }
 
 impl Default for Features {
@@ -394,6 +417,9 @@ impl Default for Features {
             key_manager_quote_policy_updates: true,
             key_manager_status_updates: true,
             endorsed_capability_tee: true,
+            batch_summary: true,
+            tx_execution_times: false,
+            local_storage: true,
         }
     }
 }
@@ -461,6 +487,11 @@ pub struct CheckTxMetadata {
     pub sender_seq: u64,
     #[cbor(optional)]
     pub sender_state_seq: u64,
+
+    /// Runtime round at which the transaction expires. Zero means that the transaction does not
+    /// expire.
+    #[cbor(optional)]
+    pub expiry_round: u64,
 }
 
 /// Consensus event kind.
@@ -7,6 +7,7 @@ pub mod types;
 use std::{
     convert::TryInto,
     sync::{atomic::AtomicBool, Arc},
+    time::Instant,
 };
 
 use oasis_core_keymanager::client::KeyManagerClient;
@@ -305,16 +306,21 @@ impl TxnDispatcher for Dispatcher {
 
         // Execute transactions.
         let mut results = vec![];
+        let mut tx_execution_times = vec![];
         for tx in batch.iter() {
+            let start = Instant::now();
             results.push(Self::execute_tx(&mut ctx, tx)?);
+            tx_execution_times.push(start.elapsed());
         }
 
         Ok(ExecuteBatchResult {
             results,
             messages: ctx.messages,
             in_msgs_count: in_msgs.len(),
+            gas_used: 0,
             block_tags: vec![],
             tx_reject_hashes: vec![],
+            tx_execution_times,
         })
     }
 
@@ -345,10 +351,15 @@ impl TxnDispatcher for Dispatcher {
         let mut new_batch = vec![];
         let mut results = vec![];
         let mut tx_reject_hashes = vec![];
+        let mut tx_execution_times = vec![];
         for tx in batch.drain(..) {
             if new_batch.len() >= MAX_BATCH_SIZE {
                 break;
             }
+            // Stop including transactions once the execution deadline has passed.
+            if matches!(ctx.core.deadline, Some(deadline) if Instant::now() >= deadline) {
+                break;
+            }
 
             // Reject any transactions that don't pass check tx.
             if Self::check_tx(&mut ctx, &tx)?.error.code != 0 {
@@ -356,7 +367,9 @@ impl TxnDispatcher for Dispatcher {
                 continue;
             }
 
+            let start = Instant::now();
             results.push(Self::execute_tx(&mut ctx, &tx)?);
+            tx_execution_times.push(start.elapsed());
             new_batch.push(tx);
         }
 
@@ -367,8 +380,10 @@ impl TxnDispatcher for Dispatcher {
             results,
             messages: ctx.messages,
             in_msgs_count: in_msgs.len(),
+            gas_used: 0,
             block_tags: vec![],
             tx_reject_hashes,
+            tx_execution_times,
         })
     }
 
@@ -439,6 +454,8 @@ pub fn main_with_version(version: Version) {
                 schedule_control: Some(FeatureScheduleControl {
                     initial_batch_size: MAX_BATCH_SIZE.try_into().unwrap(),
                 }),
+                // Enable reporting of transaction execution times.
+                tx_execution_times: true,
                 ..Default::default()
             },
             ..Default::default()